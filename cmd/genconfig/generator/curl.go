@@ -0,0 +1,310 @@
+package generator
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// curlNoArgFlags 不带参数、对生成配置无影响的 curl 选项
+var curlNoArgFlags = map[string]bool{
+	"-s": true, "--silent": true, "-S": true, "--show-error": true, "-v": true, "--verbose": true,
+	"-i": true, "--include": true, "-L": true, "--location": true, "-k": true, "--insecure": true,
+	"--compressed": true, "-N": true, "--no-buffer": true, "-f": true, "--fail": true,
+	"-g": true, "--globoff": true, "--http1.1": true, "--http2": true,
+}
+
+// curlIgnoredArgFlags 带一个参数、对生成配置无影响的 curl 选项
+var curlIgnoredArgFlags = map[string]bool{
+	"-o": true, "--output": true, "-m": true, "--max-time": true, "--connect-timeout": true,
+	"-w": true, "--write-out": true, "--retry": true, "-x": true, "--proxy": true,
+}
+
+// ParseCurl 解析 curl 命令（支持从浏览器 / API 文档复制的多行命令）
+//
+// 识别 -X、-H、-d/--data*、--json、-u、-A、-e、-b、--url 与 -G；
+// 未指定 -X 时有请求体为 POST，否则为 GET。凭证与模型名已替换为占位符
+func ParseCurl(command string) (*RequestSpec, error) {
+	args, err := splitShellWords(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) > 0 && (args[0] == "curl" || strings.HasSuffix(args[0], "/curl")) {
+		args = args[1:]
+	}
+
+	req := &RequestSpec{}
+	var data []string
+	getMode := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		// --opt=value 形式
+		name, inline, hasInline := arg, "", false
+		if strings.HasPrefix(arg, "--") {
+			if n, v, ok := strings.Cut(arg, "="); ok {
+				name, inline, hasInline = n, v, true
+			}
+		}
+		value := func() (string, error) {
+			if hasInline {
+				return inline, nil
+			}
+			// -XPOST / -HName:value 等短选项紧跟参数的形式
+			if len(name) > 2 && !strings.HasPrefix(name, "--") {
+				return name[2:], nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("curl 选项 %s 缺少参数", name)
+			}
+			i++
+			return args[i], nil
+		}
+		short := name
+		if len(name) > 2 && !strings.HasPrefix(name, "--") && strings.HasPrefix(name, "-") {
+			short = name[:2]
+		}
+
+		switch {
+		case !strings.HasPrefix(arg, "-"):
+			if req.URL != "" {
+				return nil, fmt.Errorf("curl 命令包含多个 URL: %s, %s", req.URL, arg)
+			}
+			req.URL = arg
+		case curlNoArgFlags[name]:
+		case curlIgnoredArgFlags[short] || curlIgnoredArgFlags[name]:
+			if _, err := value(); err != nil {
+				return nil, err
+			}
+		case short == "-X" || name == "--request":
+			v, err := value()
+			if err != nil {
+				return nil, err
+			}
+			req.Method = strings.ToUpper(v)
+		case short == "-H" || name == "--header":
+			v, err := value()
+			if err != nil {
+				return nil, err
+			}
+			k, hv, ok := strings.Cut(v, ":")
+			if !ok || strings.TrimSpace(k) == "" {
+				return nil, fmt.Errorf("无效的请求头: %s", v)
+			}
+			req.SetHeader(strings.TrimSpace(k), strings.TrimSpace(hv))
+		case short == "-d" || name == "--data" || name == "--data-raw" || name == "--data-binary" || name == "--data-ascii" || name == "--data-urlencode":
+			v, err := value()
+			if err != nil {
+				return nil, err
+			}
+			if strings.HasPrefix(v, "@") && name != "--data-raw" {
+				return nil, fmt.Errorf("不支持从文件读取请求体 (%s)，请粘贴请求体内容", v)
+			}
+			data = append(data, v)
+		case name == "--json":
+			v, err := value()
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, v)
+			if _, ok := req.Header("Content-Type"); !ok {
+				req.SetHeader("Content-Type", "application/json")
+			}
+			if _, ok := req.Header("Accept"); !ok {
+				req.SetHeader("Accept", "application/json")
+			}
+		case short == "-u" || name == "--user":
+			v, err := value()
+			if err != nil {
+				return nil, err
+			}
+			req.SetHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(v)))
+		case short == "-A" || name == "--user-agent":
+			v, err := value()
+			if err != nil {
+				return nil, err
+			}
+			req.SetHeader("User-Agent", v)
+		case short == "-e" || name == "--referer":
+			v, err := value()
+			if err != nil {
+				return nil, err
+			}
+			req.SetHeader("Referer", v)
+		case short == "-b" || name == "--cookie":
+			v, err := value()
+			if err != nil {
+				return nil, err
+			}
+			req.SetHeader("Cookie", v)
+		case name == "--url":
+			v, err := value()
+			if err != nil {
+				return nil, err
+			}
+			req.URL = v
+		case short == "-G" || name == "--get":
+			getMode = true
+		case combinedNoArgFlags(name):
+		default:
+			return nil, fmt.Errorf("不支持的 curl 选项: %s", name)
+		}
+	}
+
+	if req.URL == "" {
+		return nil, fmt.Errorf("curl 命令中未找到 URL")
+	}
+	if !strings.Contains(req.URL, "://") {
+		req.URL = "https://" + req.URL
+	}
+	if _, err := url.ParseRequestURI(req.URL); err != nil {
+		return nil, fmt.Errorf("无效的 URL: %s", req.URL)
+	}
+
+	body := strings.Join(data, "&")
+	if getMode && body != "" {
+		// -G：请求体参数拼接到查询字符串
+		sep := "?"
+		if strings.Contains(req.URL, "?") {
+			sep = "&"
+		}
+		req.URL += sep + body
+		body = ""
+	}
+	req.Body = body
+	if req.Method == "" {
+		req.Method = "GET"
+		if body != "" {
+			req.Method = "POST"
+		}
+	}
+	if body != "" {
+		if _, ok := req.Header("Content-Type"); !ok {
+			// curl -d 默认按表单发送；JSON 请求体按 API 实际期望标注为 application/json
+			contentType := "application/x-www-form-urlencoded"
+			if json.Valid([]byte(body)) {
+				contentType = "application/json"
+			}
+			req.SetHeader("Content-Type", contentType)
+		}
+	}
+
+	req.Templatize()
+	return req, nil
+}
+
+// combinedNoArgFlags 判断是否为合并书写的无参数短选项（如 -sSL）
+func combinedNoArgFlags(arg string) bool {
+	if len(arg) < 3 || strings.HasPrefix(arg, "--") {
+		return false
+	}
+	for _, c := range arg[1:] {
+		if !curlNoArgFlags["-"+string(c)] {
+			return false
+		}
+	}
+	return true
+}
+
+// splitShellWords 按 POSIX shell 规则拆分命令行（单引号、双引号、反斜杠转义与续行；支持 $'...'）
+func splitShellWords(s string) ([]string, error) {
+	var (
+		words   []string
+		cur     strings.Builder
+		inWord  bool
+		quote   byte // 当前引号类型：' " 或 $（$'...'）
+		escaped bool
+	)
+	flush := func() {
+		if inWord {
+			words = append(words, cur.String())
+			cur.Reset()
+			inWord = false
+		}
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if escaped {
+			escaped = false
+			if c == '\n' {
+				// 反斜杠续行
+				continue
+			}
+			inWord = true
+			if quote == '"' && c != '"' && c != '\\' && c != '$' && c != '`' {
+				cur.WriteByte('\\')
+			}
+			cur.WriteByte(c)
+			continue
+		}
+
+		switch quote {
+		case '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				cur.WriteByte(c)
+			}
+			continue
+		case '$':
+			switch {
+			case c == '\'':
+				quote = 0
+			case c == '\\' && i+1 < len(s):
+				i++
+				switch s[i] {
+				case 'n':
+					cur.WriteByte('\n')
+				case 't':
+					cur.WriteByte('\t')
+				case 'r':
+					cur.WriteByte('\r')
+				default:
+					cur.WriteByte(s[i])
+				}
+			default:
+				cur.WriteByte(c)
+			}
+			continue
+		case '"':
+			switch c {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				cur.WriteByte(c)
+			}
+			continue
+		}
+
+		switch c {
+		case ' ', '\t', '\n', '\r':
+			flush()
+		case '\\':
+			escaped = true
+		case '\'', '"':
+			inWord = true
+			quote = c
+		case '$':
+			inWord = true
+			if i+1 < len(s) && s[i+1] == '\'' {
+				quote = '$'
+				i++
+			} else {
+				cur.WriteByte(c)
+			}
+		default:
+			inWord = true
+			cur.WriteByte(c)
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("curl 命令引号未闭合")
+	}
+	flush()
+	return words, nil
+}
@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCurlAnthropic(t *testing.T) {
+	cmd := `curl https://api.88code.com/v1/messages \
+     --header "x-api-key: sk-ant-1234567890" \
+     --header "anthropic-version: 2023-06-01" \
+     --header "content-type: application/json" \
+     --data '{"model": "claude-sonnet-4-20250514", "max_tokens": 1, "messages": [{"role": "user", "content": "hi"}]}'`
+
+	req, err := ParseCurl(cmd)
+	if err != nil {
+		t.Fatalf("ParseCurl 失败: %v", err)
+	}
+	if req.Method != "POST" || req.URL != "https://api.88code.com/v1/messages" {
+		t.Fatalf("method/url 错误: %s %s", req.Method, req.URL)
+	}
+	if v, _ := req.Header("x-api-key"); v != apiKeyPlaceholder {
+		t.Fatalf("凭证未替换: %q", v)
+	}
+	if v, _ := req.Header("anthropic-version"); v != "2023-06-01" {
+		t.Fatalf("普通请求头丢失: %q", v)
+	}
+	if req.Model != "claude-sonnet-4-20250514" || !strings.Contains(req.Body, `"model": "{{MODEL}}"`) {
+		t.Fatalf("模型未替换: model=%q body=%s", req.Model, req.Body)
+	}
+	if InferService(req) != "cc" || InferProvider(req) != "88code" {
+		t.Fatalf("推断错误: service=%q provider=%q", InferService(req), InferProvider(req))
+	}
+}
+
+func TestParseCurlOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		cmd        string
+		wantMethod string
+		wantURL    string
+		wantHeader [2]string
+		wantBody   string
+	}{
+		{"GET 默认方法", `curl -sSL 'https://api.example.com/v1/models'`, "GET", "https://api.example.com/v1/models", [2]string{}, ""},
+		{"显式方法", `curl -XPUT https://api.example.com/x -d 'a=1'`, "PUT", "https://api.example.com/x", [2]string{"Content-Type", "application/x-www-form-urlencoded"}, "a=1"},
+		{"Bearer 保留方案", `curl https://api.example.com/v1/chat/completions -H 'Authorization: Bearer sk-abc' --json '{"x":1}'`, "POST", "https://api.example.com/v1/chat/completions", [2]string{"Authorization", "Bearer {{API_KEY}}"}, "{\n  \"x\": 1\n}"},
+		{"查询参数凭证", `curl "https://generativelanguage.googleapis.com/v1beta/models/gemini-pro:generateContent?key=AIza123"`, "GET", "https://generativelanguage.googleapis.com/v1beta/models/gemini-pro:generateContent?key={{API_KEY}}", [2]string{}, ""},
+		{"-G 拼接查询", `curl -G https://api.example.com/search --data-urlencode 'q=hi'`, "GET", "https://api.example.com/search?q=hi", [2]string{}, ""},
+		{"--opt=value", `curl --request=DELETE --url=https://api.example.com/x --user-agent=probe`, "DELETE", "https://api.example.com/x", [2]string{"User-Agent", "probe"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := ParseCurl(tt.cmd)
+			if err != nil {
+				t.Fatalf("ParseCurl 失败: %v", err)
+			}
+			if req.Method != tt.wantMethod || req.URL != tt.wantURL {
+				t.Fatalf("got %s %s, want %s %s", req.Method, req.URL, tt.wantMethod, tt.wantURL)
+			}
+			if tt.wantHeader[0] != "" {
+				if v, _ := req.Header(tt.wantHeader[0]); v != tt.wantHeader[1] {
+					t.Fatalf("header %s = %q, want %q", tt.wantHeader[0], v, tt.wantHeader[1])
+				}
+			}
+			if req.Body != tt.wantBody {
+				t.Fatalf("body = %q, want %q", req.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestParseCurlErrors(t *testing.T) {
+	for _, cmd := range []string{
+		`curl -H 'x-api-key: 1'`,
+		`curl https://a.example.com https://b.example.com`,
+		`curl https://a.example.com -d @body.json`,
+		`curl https://a.example.com --unknown-flag`,
+		`curl 'https://a.example.com`,
+	} {
+		if _, err := ParseCurl(cmd); err == nil {
+			t.Errorf("期望报错: %s", cmd)
+		}
+	}
+}
+
+func TestGenerateFromRequest(t *testing.T) {
+	req, err := ParseCurl(`curl https://api.example.com/v1/chat/completions -H 'Authorization: Bearer sk-1' -d '{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}],"max_tokens":1}'`)
+	if err != nil {
+		t.Fatalf("ParseCurl 失败: %v", err)
+	}
+	config, err := GenerateFromRequest(map[string]string{"sponsor": "团队", "category": "commercial"}, req)
+	if err != nil {
+		t.Fatalf("GenerateFromRequest 失败: %v", err)
+	}
+	for _, want := range []string{
+		`provider: "example"`,
+		`service: "cx"`,
+		`channel: "cx"`,
+		`model: "gpt-4o"`,
+		`success_contains: "choices"`,
+		`"Authorization": "Bearer {{API_KEY}}"`,
+		`      "model": "{{MODEL}}",`,
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("生成的配置缺少 %s:\n%s", want, config)
+		}
+	}
+
+	if _, err := GenerateFromRequest(map[string]string{"sponsor": "团队", "category": "commercial"}, &RequestSpec{URL: "https://api.example.com/health", Method: "GET"}); err == nil {
+		t.Error("无法推断 service 时应报错")
+	}
+}
@@ -92,10 +92,26 @@ func quoteYAML(s string) string {
 }
 
 // GenerateConfig 生成 YAML 配置（带枚举校验 + YAML 转义）
+// 监测项包含 models（逗号分隔的模型列表）时生成一个父通道加 N 个子模型监测项
 func GenerateConfig(interval, slowLatency, timeout string, monitors []map[string]string) (string, error) {
 	var sb strings.Builder
+	writeGlobalConfig(&sb, interval, slowLatency, timeout)
+	for i, monitor := range monitors {
+		var err error
+		if models := ParseModels(monitor["models"]); len(models) > 0 {
+			err = writeModelSet(&sb, i, monitor, models)
+		} else {
+			err = writeMonitor(&sb, i, monitor, nil)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return sb.String(), nil
+}
 
-	// 全局配置
+// writeGlobalConfig 写入全局配置、板块、存储与 monitors 段头
+func writeGlobalConfig(sb *strings.Builder, interval, slowLatency, timeout string) {
 	sb.WriteString("# RelayPulse 配置文件\n")
 	sb.WriteString("# 由 genconfig 工具生成\n\n")
 	sb.WriteString("# 全局配置\n")
@@ -114,77 +130,101 @@ func GenerateConfig(interval, slowLatency, timeout string, monitors []map[string
 	// 监测项
 	sb.WriteString("\n# 监测项列表\n")
 	sb.WriteString("monitors:\n")
+}
 
-	for i, monitor := range monitors {
-		provider := strings.TrimSpace(monitor["provider"])
-		service := strings.ToLower(strings.TrimSpace(monitor["service"]))
-		category := strings.ToLower(strings.TrimSpace(monitor["category"]))
-		sponsor := strings.TrimSpace(monitor["sponsor"])
-		channel := strings.TrimSpace(monitor["channel"])
-		board := strings.ToLower(strings.TrimSpace(monitor["board"]))
-		url := strings.TrimSpace(monitor["url"])
-		method := strings.ToUpper(strings.TrimSpace(monitor["method"]))
-		successContains := strings.TrimSpace(monitor["success_contains"])
-
-		if provider == "" {
-			return "", fmt.Errorf("monitor[%d]: provider 不能为空", i)
-		}
-		if service == "" {
-			return "", fmt.Errorf("monitor[%d]: service 不能为空", i)
-		}
-		if !isValidEnum(service, validServices) {
-			return "", fmt.Errorf("monitor[%d]: service '%s' 无效，必须是 cc/cx/gm", i, service)
-		}
-		if category == "" {
-			return "", fmt.Errorf("monitor[%d]: category 不能为空", i)
-		}
-		if !isValidEnum(category, validCategories) {
-			return "", fmt.Errorf("monitor[%d]: category '%s' 无效，必须是 commercial/public", i, category)
-		}
-		if sponsor == "" {
-			return "", fmt.Errorf("monitor[%d]: sponsor 不能为空", i)
-		}
-		if channel == "" {
-			return "", fmt.Errorf("monitor[%d]: channel 不能为空", i)
-		}
-		if board == "" {
-			board = "hot"
-		}
-		if !isValidEnum(board, validBoards) {
-			return "", fmt.Errorf("monitor[%d]: board '%s' 无效，必须是 hot/secondary/cold", i, board)
-		}
-		if url == "" {
-			return "", fmt.Errorf("monitor[%d]: url 不能为空", i)
-		}
-		if method == "" {
-			method = "POST"
-		}
-		if !isValidEnum(method, validMethods) {
-			return "", fmt.Errorf("monitor[%d]: method '%s' 无效，必须是 GET/POST/PUT/DELETE/PATCH", i, method)
-		}
+// writeMonitor 校验并写入单个监测项
+// req 为 nil 时写入默认的请求头与请求体占位，否则使用解析出的请求头、请求体与模型
+func writeMonitor(sb *strings.Builder, i int, monitor map[string]string, req *RequestSpec) error {
+	provider := strings.TrimSpace(monitor["provider"])
+	service := strings.ToLower(strings.TrimSpace(monitor["service"]))
+	category := strings.ToLower(strings.TrimSpace(monitor["category"]))
+	sponsor := strings.TrimSpace(monitor["sponsor"])
+	channel := strings.TrimSpace(monitor["channel"])
+	board := strings.ToLower(strings.TrimSpace(monitor["board"]))
+	url := strings.TrimSpace(monitor["url"])
+	method := strings.ToUpper(strings.TrimSpace(monitor["method"]))
+	successContains := strings.TrimSpace(monitor["success_contains"])
+
+	if provider == "" {
+		return fmt.Errorf("monitor[%d]: provider 不能为空", i)
+	}
+	if service == "" {
+		return fmt.Errorf("monitor[%d]: service 不能为空", i)
+	}
+	if !isValidEnum(service, validServices) {
+		return fmt.Errorf("monitor[%d]: service '%s' 无效，必须是 cc/cx/gm", i, service)
+	}
+	if category == "" {
+		return fmt.Errorf("monitor[%d]: category 不能为空", i)
+	}
+	if !isValidEnum(category, validCategories) {
+		return fmt.Errorf("monitor[%d]: category '%s' 无效，必须是 commercial/public", i, category)
+	}
+	if sponsor == "" {
+		return fmt.Errorf("monitor[%d]: sponsor 不能为空", i)
+	}
+	if channel == "" {
+		return fmt.Errorf("monitor[%d]: channel 不能为空", i)
+	}
+	if board == "" {
+		board = "hot"
+	}
+	if !isValidEnum(board, validBoards) {
+		return fmt.Errorf("monitor[%d]: board '%s' 无效，必须是 hot/secondary/cold", i, board)
+	}
+	if url == "" {
+		return fmt.Errorf("monitor[%d]: url 不能为空", i)
+	}
+	if method == "" {
+		method = "POST"
+	}
+	if !isValidEnum(method, validMethods) {
+		return fmt.Errorf("monitor[%d]: method '%s' 无效，必须是 GET/POST/PUT/DELETE/PATCH", i, method)
+	}
 
-		sb.WriteString("  - provider: " + quoteYAML(provider) + "\n")
-		sb.WriteString("    service: " + quoteYAML(service) + "\n")
-		sb.WriteString("    category: " + quoteYAML(category) + "\n")
-		sb.WriteString("    sponsor: " + quoteYAML(sponsor) + "\n")
-		sb.WriteString("    channel: " + quoteYAML(channel) + "\n")
-		sb.WriteString("    board: " + quoteYAML(board) + "\n")
-		sb.WriteString("    url: " + quoteYAML(url) + "\n")
-		sb.WriteString("    method: " + quoteYAML(method) + "\n")
-
-		if successContains != "" {
-			sb.WriteString("    success_contains: " + quoteYAML(successContains) + "\n")
-		}
+	if req != nil && req.Comment != "" {
+		sb.WriteString("  # " + strings.ReplaceAll(req.Comment, "\n", " ") + "\n")
+	}
+	sb.WriteString("  - provider: " + quoteYAML(provider) + "\n")
+	sb.WriteString("    service: " + quoteYAML(service) + "\n")
+	sb.WriteString("    category: " + quoteYAML(category) + "\n")
+	sb.WriteString("    sponsor: " + quoteYAML(sponsor) + "\n")
+	sb.WriteString("    channel: " + quoteYAML(channel) + "\n")
+	sb.WriteString("    board: " + quoteYAML(board) + "\n")
+	if req != nil && req.Model != "" {
+		sb.WriteString("    model: " + quoteYAML(req.Model) + "\n")
+	}
+	sb.WriteString("    url: " + quoteYAML(url) + "\n")
+	sb.WriteString("    method: " + quoteYAML(method) + "\n")
+
+	if successContains != "" {
+		sb.WriteString("    success_contains: " + quoteYAML(successContains) + "\n")
+	}
 
+	if req == nil {
 		sb.WriteString("    headers:\n")
 		sb.WriteString("      Authorization: \"Bearer {{API_KEY}}\"\n")
 		sb.WriteString("      Content-Type: \"application/json\"\n")
 		sb.WriteString("    body: |\n")
 		sb.WriteString("      {\"test\": true}\n")
 		sb.WriteString("\n")
+		return nil
 	}
 
-	return sb.String(), nil
+	if len(req.Headers) > 0 {
+		sb.WriteString("    headers:\n")
+		for _, h := range req.Headers {
+			sb.WriteString("      " + quoteYAML(h.Name) + ": " + quoteYAML(h.Value) + "\n")
+		}
+	}
+	if body := strings.TrimSpace(req.Body); body != "" {
+		sb.WriteString("    body: |\n")
+		for _, line := range strings.Split(body, "\n") {
+			sb.WriteString("      " + strings.TrimRight(line, "\r") + "\n")
+		}
+	}
+	sb.WriteString("\n")
+	return nil
 }
 
 // GenerateFromTemplate 从模板生成配置
@@ -528,6 +568,7 @@ monitors:
 `
 
 const multiModelTemplate = `# 多模型监测配置（父子关系示例）
+# 自定义服务商与模型列表：go run ./cmd/genconfig -mode template -template multi-model -provider <name> -models a,b,c
 interval: "1m"
 slow_latency: "5s"
 timeout: "10s"
@@ -541,7 +582,7 @@ storage:
     path: "monitor.db"
 
 monitors:
-  # 父通道：定义公共配置
+  # 父通道：定义公共配置，body 中的 {{MODEL}} 由各监测项的 model 替换
   - provider: "88code"
     service: "cc"
     channel: "vip"
@@ -550,11 +591,12 @@ monitors:
     sponsor: "团队"
     sponsor_level: "advanced"
     board: "hot"
-    url: "https://api.88code.com/v1/chat/completions"
+    url: "https://api.88code.com/v1/messages"
     method: "POST"
     success_contains: "content"
     headers:
-      Authorization: "Bearer {{API_KEY}}"
+      x-api-key: "{{API_KEY}}"
+      anthropic-version: "2023-06-01"
       Content-Type: "application/json"
     body: |
       {
@@ -563,22 +605,10 @@ monitors:
         "max_tokens": 1
       }
 
-  # 子通道：继承父配置，只需指定 model 和 parent
+  # 子通道：继承父配置（含 body），只需指定 model 和 parent
   - model: "claude-opus-4-20250514"
     parent: "88code/cc/vip"
-    body: |
-      {
-        "model": "claude-opus-4-20250514",
-        "messages": [{"role": "user", "content": "hi"}],
-        "max_tokens": 1
-      }
 
   - model: "claude-haiku-4-20250514"
     parent: "88code/cc/vip"
-    body: |
-      {
-        "model": "claude-haiku-4-20250514",
-        "messages": [{"role": "user", "content": "hi"}],
-        "max_tokens": 1
-      }
 `
@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// modelPlaceholder 请求体 / URL 中的模型占位符（子通道继承父通道 body 后按各自 model 替换）
+const modelPlaceholder = "{{MODEL}}"
+
+// multiModelDefaults multi-model 模板变量的默认值（与内置 multi-model 模板一致）
+var multiModelDefaults = map[string]string{
+	"provider": "88code",
+	"service":  "cc",
+	"channel":  "vip",
+	"category": "commercial",
+	"sponsor":  "团队",
+	"board":    "hot",
+}
+
+// defaultModelURLs 未指定 url 时各服务类型的示例端点
+var defaultModelURLs = map[string]string{
+	"cc": "https://api.example.com/v1/messages",
+	"cx": "https://api.example.com/v1/chat/completions",
+	"gm": "https://api.example.com/v1beta/models/" + modelPlaceholder + ":generateContent",
+}
+
+// ParseModels 解析模型列表（逗号或空白分隔，去重并保持输入顺序）
+func ParseModels(s string) []string {
+	var models []string
+	seen := make(map[string]bool)
+	for _, m := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	}) {
+		if !seen[m] {
+			seen[m] = true
+			models = append(models, m)
+		}
+	}
+	return models
+}
+
+// modelRequest 按服务类型生成带 {{MODEL}} 占位的请求头与请求体
+// gm 的模型位于 URL 中，请求体不含 model 字段
+func modelRequest(service string) *RequestSpec {
+	const messages = `  "messages": [{"role": "user", "content": "hi"}],` + "\n" + `  "max_tokens": 1`
+	switch service {
+	case "cc":
+		return &RequestSpec{
+			Headers: []Header{{"x-api-key", apiKeyPlaceholder}, {"anthropic-version", "2023-06-01"}, {"Content-Type", "application/json"}},
+			Body:    "{\n  \"model\": \"" + modelPlaceholder + "\",\n" + messages + "\n}",
+		}
+	case "gm":
+		return &RequestSpec{
+			Headers: []Header{{"x-goog-api-key", apiKeyPlaceholder}, {"Content-Type", "application/json"}},
+			Body:    "{\n  \"contents\": [{\"parts\": [{\"text\": \"hi\"}]}]\n}",
+		}
+	default:
+		return &RequestSpec{
+			Headers: []Header{{"Authorization", "Bearer " + apiKeyPlaceholder}, {"Content-Type", "application/json"}},
+			Body:    "{\n  \"model\": \"" + modelPlaceholder + "\",\n" + messages + "\n}",
+		}
+	}
+}
+
+// writeModelSet 写入父子模型监测组：父通道使用第一个模型并定义公共配置，其余模型各生成一个仅含 model / parent 的子监测项
+// URL 不做占位符替换，因此 URL 含 {{MODEL}} 时（如 gm）每个监测项写入替换后的 url
+func writeModelSet(sb *strings.Builder, i int, monitor map[string]string, models []string) error {
+	if len(models) == 0 {
+		return fmt.Errorf("monitor[%d]: 模型列表不能为空", i)
+	}
+	service := strings.ToLower(strings.TrimSpace(monitor["service"]))
+	url := strings.TrimSpace(monitor["url"])
+	if service == "gm" && !strings.Contains(url, modelPlaceholder) {
+		return fmt.Errorf("monitor[%d]: gm 的模型位于 URL 中，多模型监测需在 url 中使用 %s", i, modelPlaceholder)
+	}
+
+	parent := make(map[string]string, len(monitor))
+	for k, v := range monitor {
+		parent[k] = v
+	}
+	parent["url"] = strings.ReplaceAll(url, modelPlaceholder, models[0])
+	if strings.TrimSpace(parent["success_contains"]) == "" {
+		parent["success_contains"] = InferSuccessContains(service)
+	}
+	req := modelRequest(service)
+	req.Model = models[0]
+	req.Comment = "父通道：定义公共配置，子通道继承后按各自 model 替换 {{MODEL}}"
+	if err := writeMonitor(sb, i, parent, req); err != nil {
+		return err
+	}
+
+	parentPath := strings.TrimSpace(monitor["provider"]) + "/" + service + "/" + strings.TrimSpace(monitor["channel"])
+	for j, model := range models[1:] {
+		if j == 0 {
+			sb.WriteString("  # 子通道：继承父通道配置，只需指定 model 和 parent\n")
+		}
+		sb.WriteString("  - model: " + quoteYAML(model) + "\n")
+		sb.WriteString("    parent: " + quoteYAML(parentPath) + "\n")
+		if strings.Contains(url, modelPlaceholder) {
+			sb.WriteString("    url: " + quoteYAML(strings.ReplaceAll(url, modelPlaceholder, model)) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	return nil
+}
+
+// GenerateMultiModel 按模板变量生成父子模型监测配置（-template multi-model -models ...）
+// vars 支持 provider/service/channel/category/sponsor/board/url/method/success_contains，为空的字段使用 multi-model 模板默认值
+func GenerateMultiModel(vars map[string]string, models []string) (string, error) {
+	monitor := make(map[string]string, len(multiModelDefaults)+3)
+	for k, v := range multiModelDefaults {
+		monitor[k] = v
+	}
+	for k, v := range vars {
+		if v = strings.TrimSpace(v); v != "" {
+			monitor[k] = v
+		}
+	}
+	service := strings.ToLower(monitor["service"])
+	if monitor["url"] == "" {
+		monitor["url"] = defaultModelURLs[service]
+	}
+
+	var sb strings.Builder
+	writeGlobalConfig(&sb, "1m", "5s", "10s")
+	if err := writeModelSet(&sb, 0, monitor, models); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
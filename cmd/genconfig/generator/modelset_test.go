@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"monitor/internal/config"
+)
+
+// validateGenerated 按服务端加载流程解析并校验生成的配置（含父子继承）
+func validateGenerated(t *testing.T, text string) *config.AppConfig {
+	t.Helper()
+	var cfg config.AppConfig
+	if err := yaml.Unmarshal([]byte(text), &cfg); err != nil {
+		t.Fatalf("生成的配置不是合法 YAML: %v\n%s", err, text)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("生成的配置校验失败: %v\n%s", err, text)
+	}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("生成的配置规范化失败: %v\n%s", err, text)
+	}
+	for i := range cfg.Monitors {
+		cfg.Monitors[i].ProcessPlaceholders()
+	}
+	return &cfg
+}
+
+func TestParseModels(t *testing.T) {
+	got := ParseModels(" gpt-4o, gpt-4o-mini\tgpt-4o ,,o3 ")
+	want := []string{"gpt-4o", "gpt-4o-mini", "o3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseModels = %v, want %v", got, want)
+	}
+	if ParseModels("  ") != nil {
+		t.Fatal("空输入应返回 nil")
+	}
+}
+
+func TestGenerateConfigModelSet(t *testing.T) {
+	monitors := []map[string]string{{
+		"provider": "relay",
+		"service":  "cx",
+		"category": "commercial",
+		"sponsor":  "team",
+		"channel":  "vip",
+		"url":      "https://api.relay.example.com/v1/chat/completions",
+		"models":   "gpt-4o, gpt-4o-mini, o3",
+	}}
+	text, err := GenerateConfig("1m", "5s", "10s", monitors)
+	if err != nil {
+		t.Fatalf("GenerateConfig 失败: %v", err)
+	}
+	if got := strings.Count(text, `parent: "relay/cx/vip"`); got != 2 {
+		t.Fatalf("期望 2 个子监测项，实际 %d:\n%s", got, text)
+	}
+
+	cfg := validateGenerated(t, text)
+	if len(cfg.Monitors) != 3 {
+		t.Fatalf("监测项数量 = %d", len(cfg.Monitors))
+	}
+	for i, model := range []string{"gpt-4o", "gpt-4o-mini", "o3"} {
+		m := cfg.Monitors[i]
+		if m.Model != model || m.Channel != "vip" || m.URL != monitors[0]["url"] {
+			t.Errorf("monitor[%d] = %s/%s %s", i, m.Channel, m.Model, m.URL)
+		}
+		if !strings.Contains(m.Body, `"model": "`+model+`"`) {
+			t.Errorf("monitor[%d] body 未替换模型: %s", i, m.Body)
+		}
+		if m.SuccessContains != "choices" {
+			t.Errorf("monitor[%d] success_contains = %q", i, m.SuccessContains)
+		}
+	}
+}
+
+func TestGenerateMultiModel(t *testing.T) {
+	text, err := GenerateMultiModel(map[string]string{"provider": "acme", "service": "gm"}, []string{"gemini-2.5-pro", "gemini-2.5-flash"})
+	if err != nil {
+		t.Fatalf("GenerateMultiModel 失败: %v", err)
+	}
+	cfg := validateGenerated(t, text)
+	if len(cfg.Monitors) != 2 {
+		t.Fatalf("监测项数量 = %d", len(cfg.Monitors))
+	}
+	// gm 的模型位于 URL 中，子监测项写入各自的 url
+	child := cfg.Monitors[1]
+	if child.Parent != "acme/gm/vip" || !strings.Contains(child.URL, "/models/gemini-2.5-flash:generateContent") {
+		t.Fatalf("子监测项 parent=%q url=%q", child.Parent, child.URL)
+	}
+
+	if _, err := GenerateMultiModel(map[string]string{"service": "gm", "url": "https://gm.example.com/v1beta/models/x:generateContent"}, []string{"a", "b"}); err == nil {
+		t.Fatal("gm URL 不含 {{MODEL}} 时应报错")
+	}
+	if _, err := GenerateMultiModel(nil, nil); err == nil {
+		t.Fatal("模型列表为空时应报错")
+	}
+}
+
+func TestMultiModelTemplateValid(t *testing.T) {
+	text, err := GenerateFromTemplate("multi-model")
+	if err != nil {
+		t.Fatalf("GenerateFromTemplate 失败: %v", err)
+	}
+	cfg := validateGenerated(t, text)
+	for _, m := range cfg.Monitors {
+		if !strings.Contains(m.Body, `"model": "`+m.Model+`"`) {
+			t.Errorf("%s body 未按模型替换: %s", m.Model, m.Body)
+		}
+	}
+}
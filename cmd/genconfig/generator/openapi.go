@@ -0,0 +1,437 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// openAPIMethods 支持生成监测项的 HTTP 方法（与 validMethods 一致，按此顺序列出操作）
+var openAPIMethods = []string{"get", "post", "put", "patch", "delete"}
+
+// maxSchemaDepth 由 schema 生成示例请求体时的最大嵌套深度（避免循环引用）
+const maxSchemaDepth = 8
+
+// openAPIOperation 定义中的单个操作
+type openAPIOperation struct {
+	method string
+	path   string
+	op     map[string]any
+	item   map[string]any
+}
+
+// label 操作的可读标识：METHOD /path (operationId)
+func (o openAPIOperation) label() string {
+	s := strings.ToUpper(o.method) + " " + o.path
+	if id, _ := o.op["operationId"].(string); id != "" {
+		s += " (" + id + ")"
+	}
+	return s
+}
+
+// openAPIDoc OpenAPI 文档（解析为通用 map，按需解析 $ref）
+type openAPIDoc struct {
+	root map[string]any
+}
+
+// ParseOpenAPI 从 OpenAPI 3.x 定义（YAML 或 JSON）生成指定操作的探测请求
+//
+// operation 为 operationId 或 "METHOD /path"，定义中只有一个操作时可为空；
+// baseURL 非空时覆盖 servers 中的地址（servers 缺失或为相对路径时必填）。
+// 请求头取必填的 header 参数与安全方案（凭证写为 {{API_KEY}}），请求体优先使用 example，否则按 schema 生成
+func ParseOpenAPI(data []byte, operation, baseURL string) (*RequestSpec, error) {
+	var root map[string]any
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("解析 OpenAPI 定义失败: %w", err)
+	}
+	if root == nil {
+		return nil, fmt.Errorf("OpenAPI 定义为空")
+	}
+	version := fmt.Sprint(root["openapi"])
+	if !strings.HasPrefix(version, "3.") {
+		if root["swagger"] != nil {
+			return nil, fmt.Errorf("仅支持 OpenAPI 3.x，Swagger 2.0 定义请先转换")
+		}
+		return nil, fmt.Errorf("缺少 openapi 版本声明（仅支持 OpenAPI 3.x）")
+	}
+	doc := &openAPIDoc{root: root}
+
+	ops := doc.operations()
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("OpenAPI 定义中没有可用的操作（支持 %s）", strings.ToUpper(strings.Join(openAPIMethods, "/")))
+	}
+	selected, err := selectOperation(ops, operation)
+	if err != nil {
+		return nil, err
+	}
+
+	server, err := doc.serverURL(selected, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &RequestSpec{Method: strings.ToUpper(selected.method)}
+	path := selected.path
+	query := url.Values{}
+	for _, p := range doc.parameters(selected) {
+		name, _ := p["name"].(string)
+		in, _ := p["in"].(string)
+		required, _ := p["required"].(bool)
+		value, hasValue := doc.parameterValue(p)
+		switch in {
+		case "path":
+			if strings.Contains(strings.ToLower(name), "model") {
+				req.Model = value
+				value, hasValue = "{{MODEL}}", true
+			}
+			if !hasValue {
+				return nil, fmt.Errorf("路径参数 %s 缺少 example/default，请先在定义中补充或生成后手动替换", name)
+			}
+			path = strings.ReplaceAll(path, "{"+name+"}", value)
+		case "query":
+			if required && hasValue {
+				query.Set(name, value)
+			}
+		case "header":
+			if required && hasValue {
+				req.SetHeader(name, value)
+			}
+		}
+	}
+	doc.applySecurity(selected, req, query)
+
+	req.URL = strings.TrimRight(server, "/") + path
+	if len(query) > 0 {
+		req.URL += "?" + strings.NewReplacer("%7B", "{", "%7D", "}").Replace(query.Encode())
+	}
+
+	if err := doc.applyRequestBody(selected, req); err != nil {
+		return nil, err
+	}
+
+	req.Comment = selected.label()
+	if summary, _ := selected.op["summary"].(string); summary != "" {
+		req.Comment = summary + " - " + req.Comment
+	}
+
+	req.Templatize()
+	return req, nil
+}
+
+// selectOperation 按 operationId 或 "METHOD /path" 选择操作
+func selectOperation(ops []openAPIOperation, operation string) (openAPIOperation, error) {
+	operation = strings.TrimSpace(operation)
+	if operation == "" {
+		if len(ops) == 1 {
+			return ops[0], nil
+		}
+		labels := make([]string, 0, len(ops))
+		for _, o := range ops {
+			labels = append(labels, "  - "+o.label())
+		}
+		return openAPIOperation{}, fmt.Errorf("定义包含 %d 个操作，请通过 -operation 指定（operationId 或 \"POST /v1/messages\"）:\n%s",
+			len(ops), strings.Join(labels, "\n"))
+	}
+
+	method, path, isPath := strings.Cut(operation, " ")
+	for _, o := range ops {
+		if id, _ := o.op["operationId"].(string); id != "" && strings.EqualFold(id, operation) {
+			return o, nil
+		}
+		if isPath && strings.EqualFold(method, o.method) && strings.TrimSpace(path) == o.path {
+			return o, nil
+		}
+	}
+	return openAPIOperation{}, fmt.Errorf("未找到操作: %s", operation)
+}
+
+// operations 列出全部支持的操作（按路径、方法排序）
+func (d *openAPIDoc) operations() []openAPIOperation {
+	paths, _ := d.root["paths"].(map[string]any)
+	keys := make([]string, 0, len(paths))
+	for p := range paths {
+		keys = append(keys, p)
+	}
+	sort.Strings(keys)
+
+	var ops []openAPIOperation
+	for _, p := range keys {
+		item := d.resolve(paths[p])
+		for _, m := range openAPIMethods {
+			if op, ok := item[m].(map[string]any); ok {
+				ops = append(ops, openAPIOperation{method: m, path: p, op: op, item: item})
+			}
+		}
+	}
+	return ops
+}
+
+// resolve 解析本文档内的 $ref（#/components/...），非对象或无法解析时返回 nil
+func (d *openAPIDoc) resolve(v any) map[string]any {
+	for range maxSchemaDepth {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		ref, _ := m["$ref"].(string)
+		if ref == "" {
+			return m
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			return nil
+		}
+		var cur any = d.root
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+			obj, ok := cur.(map[string]any)
+			if !ok {
+				return nil
+			}
+			cur = obj[part]
+		}
+		v = cur
+	}
+	return nil
+}
+
+// serverURL 按 operation > path > 根级别的顺序选取 servers[0]，展开服务器变量默认值
+func (d *openAPIDoc) serverURL(o openAPIOperation, baseURL string) (string, error) {
+	if baseURL = strings.TrimSpace(baseURL); baseURL != "" {
+		return baseURL, nil
+	}
+	var server map[string]any
+	for _, src := range []map[string]any{o.op, o.item, d.root} {
+		if list, ok := src["servers"].([]any); ok && len(list) > 0 {
+			server, _ = list[0].(map[string]any)
+			break
+		}
+	}
+	raw, _ := server["url"].(string)
+	if vars, ok := server["variables"].(map[string]any); ok {
+		for name, v := range vars {
+			if vm, ok := v.(map[string]any); ok && vm["default"] != nil {
+				raw = strings.ReplaceAll(raw, "{"+name+"}", fmt.Sprint(vm["default"]))
+			}
+		}
+	}
+	if u, err := url.Parse(raw); err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("OpenAPI 定义未提供完整的服务器地址 (%q)，请通过 -base-url 指定", raw)
+	}
+	return raw, nil
+}
+
+// parameters 合并 path 级与 operation 级参数（operation 级同名同位置参数覆盖 path 级）
+func (d *openAPIDoc) parameters(o openAPIOperation) []map[string]any {
+	var out []map[string]any
+	index := make(map[string]int)
+	for _, src := range []map[string]any{o.item, o.op} {
+		list, _ := src["parameters"].([]any)
+		for _, raw := range list {
+			p := d.resolve(raw)
+			if p == nil {
+				continue
+			}
+			key := fmt.Sprint(p["in"]) + ":" + fmt.Sprint(p["name"])
+			if i, ok := index[key]; ok {
+				out[i] = p
+				continue
+			}
+			index[key] = len(out)
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// parameterValue 参数示例值：example > examples > schema.example > schema.default > schema.enum[0]
+func (d *openAPIDoc) parameterValue(p map[string]any) (string, bool) {
+	if v, ok := p["example"]; ok && v != nil {
+		return fmt.Sprint(v), true
+	}
+	if examples, ok := p["examples"].(map[string]any); ok {
+		if v, ok := d.firstExample(examples); ok {
+			return fmt.Sprint(v), true
+		}
+	}
+	schema := d.resolve(p["schema"])
+	for _, key := range []string{"example", "default"} {
+		if v, ok := schema[key]; ok && v != nil {
+			return fmt.Sprint(v), true
+		}
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return fmt.Sprint(enum[0]), true
+	}
+	return "", false
+}
+
+// firstExample 按名称排序取第一个 examples 条目的 value
+func (d *openAPIDoc) firstExample(examples map[string]any) (any, bool) {
+	names := make([]string, 0, len(examples))
+	for name := range examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ex := d.resolve(examples[name]); ex != nil {
+			if v, ok := ex["value"]; ok && v != nil {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// applySecurity 按操作（未声明时按全局）的第一个安全要求添加凭证占位
+func (d *openAPIDoc) applySecurity(o openAPIOperation, req *RequestSpec, query url.Values) {
+	security, ok := o.op["security"].([]any)
+	if !ok {
+		security, _ = d.root["security"].([]any)
+	}
+	if len(security) == 0 {
+		return
+	}
+	requirement, _ := security[0].(map[string]any)
+	components, _ := d.root["components"].(map[string]any)
+	schemes, _ := components["securitySchemes"].(map[string]any)
+
+	names := make([]string, 0, len(requirement))
+	for name := range requirement {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		scheme := d.resolve(schemes[name])
+		if scheme == nil {
+			continue
+		}
+		switch scheme["type"] {
+		case "apiKey":
+			keyName, _ := scheme["name"].(string)
+			switch scheme["in"] {
+			case "header":
+				req.SetHeader(keyName, apiKeyPlaceholder)
+			case "query":
+				query.Set(keyName, apiKeyPlaceholder)
+			}
+		case "http":
+			if s, _ := scheme["scheme"].(string); strings.EqualFold(s, "basic") {
+				req.SetHeader("Authorization", "Basic "+apiKeyPlaceholder)
+			} else {
+				req.SetHeader("Authorization", "Bearer "+apiKeyPlaceholder)
+			}
+		case "oauth2", "openIdConnect":
+			req.SetHeader("Authorization", "Bearer "+apiKeyPlaceholder)
+		}
+	}
+}
+
+// applyRequestBody 生成 JSON 请求体并设置 Content-Type（非 JSON 请求体只设置 Content-Type）
+func (d *openAPIDoc) applyRequestBody(o openAPIOperation, req *RequestSpec) error {
+	body := d.resolve(o.op["requestBody"])
+	if body == nil {
+		return nil
+	}
+	content, _ := body["content"].(map[string]any)
+	mediaType := ""
+	for mt := range content {
+		if mt == "application/json" || (mediaType == "" && strings.Contains(mt, "json")) {
+			mediaType = mt
+		}
+	}
+	if mediaType == "" {
+		for mt := range content {
+			req.SetHeader("Content-Type", mt)
+			break
+		}
+		return nil
+	}
+	req.SetHeader("Content-Type", mediaType)
+
+	media := d.resolve(content[mediaType])
+	var sample any
+	if v, ok := media["example"]; ok && v != nil {
+		sample = v
+	} else if examples, ok := media["examples"].(map[string]any); ok {
+		sample, _ = d.firstExample(examples)
+	}
+	if sample == nil {
+		sample = d.sampleFromSchema(media["schema"], 0)
+	}
+	if sample == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(sample, "", "  ")
+	if err != nil {
+		return fmt.Errorf("生成请求体失败: %w", err)
+	}
+	req.Body = string(data)
+	return nil
+}
+
+// sampleFromSchema 按 schema 生成示例值：优先 example / default / enum，对象只包含必填属性（未声明必填时包含全部属性）
+func (d *openAPIDoc) sampleFromSchema(v any, depth int) any {
+	schema := d.resolve(v)
+	if schema == nil || depth > maxSchemaDepth {
+		return nil
+	}
+	for _, key := range []string{"example", "default"} {
+		if ex, ok := schema[key]; ok && ex != nil {
+			return ex
+		}
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	if all, ok := schema["allOf"].([]any); ok {
+		merged := map[string]any{}
+		for _, s := range all {
+			if obj, ok := d.sampleFromSchema(s, depth+1).(map[string]any); ok {
+				for k, val := range obj {
+					merged[k] = val
+				}
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if list, ok := schema[key].([]any); ok && len(list) > 0 {
+			return d.sampleFromSchema(list[0], depth+1)
+		}
+	}
+
+	typ, _ := schema["type"].(string)
+	props, hasProps := schema["properties"].(map[string]any)
+	switch {
+	case typ == "object" || hasProps:
+		required, _ := schema["required"].([]any)
+		out := map[string]any{}
+		if len(required) > 0 {
+			for _, name := range required {
+				key := fmt.Sprint(name)
+				out[key] = d.sampleFromSchema(props[key], depth+1)
+			}
+		} else {
+			for key, prop := range props {
+				out[key] = d.sampleFromSchema(prop, depth+1)
+			}
+		}
+		return out
+	case typ == "array":
+		if item := d.sampleFromSchema(schema["items"], depth+1); item != nil {
+			return []any{item}
+		}
+		return []any{}
+	case typ == "string":
+		return "string"
+	case typ == "integer" || typ == "number":
+		return 1
+	case typ == "boolean":
+		return false
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+const testOpenAPISpec = `
+openapi: 3.0.3
+info:
+  title: Relay API
+  version: "1"
+servers:
+  - url: https://{region}.relay.example.com
+    variables:
+      region:
+        default: hk
+security:
+  - apiKeyAuth: []
+components:
+  securitySchemes:
+    apiKeyAuth:
+      type: apiKey
+      in: header
+      name: x-api-key
+  parameters:
+    Version:
+      name: anthropic-version
+      in: header
+      required: true
+      schema:
+        type: string
+        default: "2023-06-01"
+  schemas:
+    Message:
+      type: object
+      required: [role, content]
+      properties:
+        role:
+          type: string
+          enum: [user, assistant]
+        content:
+          type: string
+          example: hi
+    MessagesRequest:
+      type: object
+      required: [model, max_tokens, messages]
+      properties:
+        model:
+          type: string
+          example: claude-haiku-4
+        max_tokens:
+          type: integer
+        messages:
+          type: array
+          items:
+            $ref: '#/components/schemas/Message'
+        metadata:
+          type: object
+paths:
+  /v1/messages:
+    post:
+      operationId: createMessage
+      summary: Create a message
+      parameters:
+        - $ref: '#/components/parameters/Version'
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/MessagesRequest'
+  /v1/models:
+    get:
+      operationId: listModels
+      security: []
+`
+
+func TestParseOpenAPISchemaBody(t *testing.T) {
+	req, err := ParseOpenAPI([]byte(testOpenAPISpec), "createMessage", "")
+	if err != nil {
+		t.Fatalf("ParseOpenAPI 失败: %v", err)
+	}
+	if req.Method != "POST" || req.URL != "https://hk.relay.example.com/v1/messages" {
+		t.Fatalf("method/url 错误: %s %s", req.Method, req.URL)
+	}
+	if v, _ := req.Header("x-api-key"); v != apiKeyPlaceholder {
+		t.Fatalf("安全方案未生成凭证占位: %q", v)
+	}
+	if v, _ := req.Header("anthropic-version"); v != "2023-06-01" {
+		t.Fatalf("必填请求头参数缺失: %q", v)
+	}
+	if v, _ := req.Header("Content-Type"); v != "application/json" {
+		t.Fatalf("Content-Type = %q", v)
+	}
+	if req.Model != "claude-haiku-4" {
+		t.Fatalf("model = %q", req.Model)
+	}
+	for _, want := range []string{`"model": "{{MODEL}}"`, `"max_tokens": 1`, `"role": "user"`, `"content": "hi"`} {
+		if !strings.Contains(req.Body, want) {
+			t.Errorf("请求体缺少 %s:\n%s", want, req.Body)
+		}
+	}
+	if strings.Contains(req.Body, "metadata") {
+		t.Errorf("非必填属性不应生成:\n%s", req.Body)
+	}
+	if !strings.Contains(req.Comment, "Create a message") {
+		t.Errorf("comment = %q", req.Comment)
+	}
+}
+
+func TestParseOpenAPISelectOperation(t *testing.T) {
+	_, err := ParseOpenAPI([]byte(testOpenAPISpec), "", "")
+	if err == nil || !strings.Contains(err.Error(), "GET /v1/models (listModels)") {
+		t.Fatalf("多个操作时应列出可选操作, got %v", err)
+	}
+
+	req, err := ParseOpenAPI([]byte(testOpenAPISpec), "get /v1/models", "https://staging.example.com/")
+	if err != nil {
+		t.Fatalf("ParseOpenAPI 失败: %v", err)
+	}
+	if req.URL != "https://staging.example.com/v1/models" {
+		t.Fatalf("url = %q", req.URL)
+	}
+	if _, ok := req.Header("x-api-key"); ok {
+		t.Fatal("security: [] 的操作不应添加凭证")
+	}
+
+	if _, err := ParseOpenAPI([]byte(testOpenAPISpec), "deleteEverything", ""); err == nil {
+		t.Fatal("未知操作应报错")
+	}
+	if _, err := ParseOpenAPI([]byte(`{"swagger": "2.0", "paths": {}}`), "", ""); err == nil {
+		t.Fatal("Swagger 2.0 应报错")
+	}
+}
+
+func TestParseOpenAPIJSONExample(t *testing.T) {
+	spec := `{
+  "openapi": "3.1.0",
+  "servers": [{"url": "/api"}],
+  "components": {"securitySchemes": {"bearer": {"type": "http", "scheme": "bearer"}}},
+  "security": [{"bearer": []}],
+  "paths": {
+    "/v1beta/models/{model}:generateContent": {
+      "post": {
+        "parameters": [{"name": "model", "in": "path", "required": true, "example": "gemini-2.5-flash"}],
+        "requestBody": {"content": {"application/json": {"example": {"contents": [{"parts": [{"text": "hi"}]}]}}}}
+      }
+    }
+  }
+}`
+	if _, err := ParseOpenAPI([]byte(spec), "", ""); err == nil || !strings.Contains(err.Error(), "-base-url") {
+		t.Fatalf("相对服务器地址应提示 -base-url, got %v", err)
+	}
+
+	req, err := ParseOpenAPI([]byte(spec), "", "https://gm.example.com")
+	if err != nil {
+		t.Fatalf("ParseOpenAPI 失败: %v", err)
+	}
+	if req.URL != "https://gm.example.com/v1beta/models/{{MODEL}}:generateContent" || req.Model != "gemini-2.5-flash" {
+		t.Fatalf("url/model 错误: %s %s", req.URL, req.Model)
+	}
+	if v, _ := req.Header("Authorization"); v != "Bearer {{API_KEY}}" {
+		t.Fatalf("Authorization = %q", v)
+	}
+	if !strings.Contains(req.Body, `"text": "hi"`) || InferService(req) != "gm" {
+		t.Fatalf("body/service 错误: %s", req.Body)
+	}
+}
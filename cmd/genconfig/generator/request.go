@@ -0,0 +1,207 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// apiKeyPlaceholder 生成配置中代替真实凭证的占位符（运行时由 api_key / 环境变量替换）
+const apiKeyPlaceholder = "{{API_KEY}}"
+
+// Header 请求头（保持输入顺序，生成的 YAML 可稳定比对）
+type Header struct {
+	Name  string
+	Value string
+}
+
+// RequestSpec 从 curl 命令或 OpenAPI 定义解析出的探测请求
+type RequestSpec struct {
+	URL     string
+	Method  string
+	Headers []Header
+	Body    string
+
+	// Model 请求体中的模型名（已在 Body 中替换为 {{MODEL}}）
+	Model string
+	// Comment 生成配置时写在监测项上方的注释（如 OpenAPI 操作描述）
+	Comment string
+}
+
+// Header 返回指定请求头的值（忽略大小写）
+func (r *RequestSpec) Header(name string) (string, bool) {
+	for _, h := range r.Headers {
+		if strings.EqualFold(h.Name, name) {
+			return h.Value, true
+		}
+	}
+	return "", false
+}
+
+// SetHeader 设置请求头（已存在时覆盖，保留原有位置）
+func (r *RequestSpec) SetHeader(name, value string) {
+	for i := range r.Headers {
+		if strings.EqualFold(r.Headers[i].Name, name) {
+			r.Headers[i].Value = value
+			return
+		}
+	}
+	r.Headers = append(r.Headers, Header{Name: name, Value: value})
+}
+
+// credentialHeader 判断请求头是否携带凭证（值替换为 {{API_KEY}}）
+func credentialHeader(name string) bool {
+	lower := strings.ToLower(name)
+	return lower == "authorization" || lower == "proxy-authorization" ||
+		strings.Contains(lower, "key") || strings.Contains(lower, "token")
+}
+
+// credentialQuery 判断查询参数是否携带凭证
+func credentialQuery(name string) bool {
+	lower := strings.ToLower(name)
+	return lower == "key" || strings.Contains(lower, "api_key") || strings.Contains(lower, "apikey") || strings.Contains(lower, "token")
+}
+
+// modelFieldPattern 匹配 JSON 请求体中的顶层 model 字段
+var modelFieldPattern = regexp.MustCompile(`"model"\s*:\s*"((?:[^"\\]|\\.)*)"`)
+
+// Templatize 将请求中的凭证替换为 {{API_KEY}}，模型名替换为 {{MODEL}}，并格式化 JSON 请求体
+// 避免真实密钥写入配置文件；保留认证方案（如 "Bearer "）
+func (r *RequestSpec) Templatize() {
+	for i := range r.Headers {
+		h := &r.Headers[i]
+		if !credentialHeader(h.Name) || strings.Contains(h.Value, "{{") {
+			continue
+		}
+		if scheme, _, ok := strings.Cut(h.Value, " "); ok && strings.EqualFold(h.Name, "authorization") {
+			h.Value = scheme + " " + apiKeyPlaceholder
+		} else {
+			h.Value = apiKeyPlaceholder
+		}
+	}
+
+	if u, err := url.Parse(r.URL); err == nil && u.RawQuery != "" {
+		query := u.Query()
+		changed := false
+		for k := range query {
+			if credentialQuery(k) && !strings.Contains(query.Get(k), "{{") {
+				query.Set(k, apiKeyPlaceholder)
+				changed = true
+			}
+		}
+		if changed {
+			// 占位符不做 URL 编码，保持可读并由配置加载时替换
+			u.RawQuery = strings.NewReplacer("%7B", "{", "%7D", "}").Replace(query.Encode())
+			r.URL = u.String()
+		}
+	}
+
+	body := strings.TrimSpace(r.Body)
+	if body == "" {
+		return
+	}
+	var obj map[string]any
+	if json.Unmarshal([]byte(body), &obj) != nil {
+		r.Body = body
+		return
+	}
+	var pretty bytes.Buffer
+	if json.Indent(&pretty, []byte(body), "", "  ") == nil {
+		body = pretty.String()
+	}
+	if model, ok := obj["model"].(string); ok && model != "" && !strings.Contains(model, "{{") {
+		r.Model = model
+		loc := modelFieldPattern.FindStringSubmatchIndex(body)
+		if loc != nil {
+			body = body[:loc[2]] + "{{MODEL}}" + body[loc[3]:]
+		}
+	}
+	r.Body = body
+}
+
+// InferService 按请求路径推断服务类型（cc=Anthropic Messages，cx=OpenAI，gm=Gemini），无法推断时返回空
+func InferService(r *RequestSpec) string {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return ""
+	}
+	path := strings.ToLower(u.Path)
+	switch {
+	case strings.HasSuffix(path, "/messages"):
+		return "cc"
+	case strings.Contains(path, ":generatecontent") || strings.Contains(path, ":streamgeneratecontent"):
+		return "gm"
+	case strings.HasSuffix(path, "/chat/completions") || strings.HasSuffix(path, "/responses") || strings.HasSuffix(path, "/completions"):
+		return "cx"
+	}
+	if _, ok := r.Header("anthropic-version"); ok {
+		return "cc"
+	}
+	return ""
+}
+
+// InferSuccessContains 按服务类型推断响应体关键字（与内置模板一致）
+func InferSuccessContains(service string) string {
+	switch service {
+	case "cc":
+		return "content"
+	case "cx":
+		return "choices"
+	case "gm":
+		return "candidates"
+	}
+	return ""
+}
+
+// InferProvider 按 URL 主机名推断服务商标识（去掉 api./www. 前缀后的首段，如 api.88code.com → 88code）
+func InferProvider(r *RequestSpec) string {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, prefix := range []string{"api.", "www."} {
+		host = strings.TrimPrefix(host, prefix)
+	}
+	name, _, _ := strings.Cut(host, ".")
+	return name
+}
+
+// GenerateFromRequest 按解析出的请求生成单个监测项的完整配置
+// monitor 为 provider/service/category/sponsor/channel/board 等字段，为空的字段按请求推断
+func GenerateFromRequest(monitor map[string]string, req *RequestSpec) (string, error) {
+	if req == nil || strings.TrimSpace(req.URL) == "" {
+		return "", fmt.Errorf("未解析到请求 URL")
+	}
+	fields := make(map[string]string, len(monitor)+3)
+	for k, v := range monitor {
+		fields[k] = strings.TrimSpace(v)
+	}
+	if fields["provider"] == "" {
+		fields["provider"] = InferProvider(req)
+	}
+	if fields["service"] == "" {
+		fields["service"] = InferService(req)
+		if fields["service"] == "" {
+			return "", fmt.Errorf("无法从请求推断 service，请通过 -service 指定 (cc/cx/gm)")
+		}
+	}
+	if fields["channel"] == "" {
+		fields["channel"] = fields["service"]
+	}
+	if fields["success_contains"] == "" {
+		fields["success_contains"] = InferSuccessContains(strings.ToLower(fields["service"]))
+	}
+	fields["url"] = req.URL
+	fields["method"] = req.Method
+
+	var sb strings.Builder
+	writeGlobalConfig(&sb, "1m", "5s", "10s")
+	if err := writeMonitor(&sb, 0, fields, req); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -11,12 +12,25 @@ import (
 )
 
 func main() {
-	mode := flag.String("mode", "interactive", "生成模式: interactive(交互式) 或 template(模板快速生成)")
+	mode := flag.String("mode", "interactive", "生成模式: interactive(交互式)、template(模板快速生成)、curl(从 curl 命令导入) 或 openapi(从 OpenAPI 定义导入)")
 	template := flag.String("template", "", "模板名称 (仅在 mode=template 时使用)")
 	output := flag.String("output", "", "输出文件路径 (不指定则输出到 stdout)")
 	appendMode := flag.Bool("append", false, "追加到现有配置文件 (monitors-only，仅在指定 output 时生效)")
 	listTemplates := flag.Bool("list", false, "列出所有可用模板")
 
+	// curl / openapi 导入模式与 multi-model 模板变量
+	input := flag.String("input", "", "curl 命令或 OpenAPI 定义文件路径 (mode=curl 时不指定则从 stdin 读取)")
+	operation := flag.String("operation", "", "OpenAPI 操作：operationId 或 \"POST /v1/messages\" (定义只有一个操作时可省略)")
+	baseURL := flag.String("base-url", "", "覆盖 OpenAPI 定义中的服务器地址")
+	provider := flag.String("provider", "", "服务商标识 (默认按 URL 主机名推断)")
+	service := flag.String("service", "", "服务类型 cc/cx/gm (默认按请求路径推断)")
+	channel := flag.String("channel", "", "业务通道 (默认与 service 相同)")
+	sponsor := flag.String("sponsor", "团队", "赞助者名称")
+	category := flag.String("category", "commercial", "分类 commercial/public")
+	board := flag.String("board", "hot", "板块 hot/secondary/cold")
+	models := flag.String("models", "", "模型列表，逗号分隔 (template=multi-model 时生成一个父通道加 N 个子模型监测项)")
+	url := flag.String("url", "", "健康检查端点 URL (template=multi-model 时使用，可含 {{MODEL}})")
+
 	flag.Parse()
 
 	// 列出模板
@@ -42,7 +56,34 @@ func main() {
 			fmt.Println("使用 -list 查看所有可用模板")
 			os.Exit(1)
 		}
-		config, err = generator.GenerateFromTemplate(*template)
+		if *models == "" {
+			config, err = generator.GenerateFromTemplate(*template)
+			break
+		}
+		if *template != "multi-model" {
+			fmt.Println("❌ -models 仅适用于 multi-model 模板")
+			os.Exit(1)
+		}
+		vars := map[string]string{
+			"provider": *provider,
+			"service":  *service,
+			"channel":  *channel,
+			"sponsor":  *sponsor,
+			"category": *category,
+			"board":    *board,
+			"url":      *url,
+		}
+		config, err = generator.GenerateMultiModel(vars, generator.ParseModels(*models))
+	case "curl", "openapi":
+		monitor := map[string]string{
+			"provider": *provider,
+			"service":  *service,
+			"channel":  *channel,
+			"sponsor":  *sponsor,
+			"category": *category,
+			"board":    *board,
+		}
+		config, err = runImportMode(*mode, *input, *operation, *baseURL, monitor)
 	default:
 		fmt.Printf("❌ 未知的模式: %s\n", *mode)
 		os.Exit(1)
@@ -66,6 +107,37 @@ func main() {
 	}
 }
 
+// runImportMode 解析 curl 命令或 OpenAPI 定义并生成监测项配置
+func runImportMode(mode, input, operation, baseURL string, monitor map[string]string) (string, error) {
+	var data []byte
+	var err error
+	switch {
+	case input != "" && input != "-":
+		data, err = os.ReadFile(input)
+	case mode == "openapi" && input == "":
+		return "", fmt.Errorf("openapi 模式需要通过 -input 指定定义文件（- 表示 stdin）")
+	default:
+		if mode == "curl" {
+			fmt.Fprintln(os.Stderr, "📋 粘贴 curl 命令，以 Ctrl-D 结束:")
+		}
+		data, err = io.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		return "", fmt.Errorf("读取输入失败: %w", err)
+	}
+
+	var req *generator.RequestSpec
+	if mode == "curl" {
+		req, err = generator.ParseCurl(string(data))
+	} else {
+		req, err = generator.ParseOpenAPI(data, operation, baseURL)
+	}
+	if err != nil {
+		return "", err
+	}
+	return generator.GenerateFromRequest(monitor, req)
+}
+
 func runInteractiveMode() (string, error) {
 	reader := bufio.NewReader(os.Stdin)
 
@@ -94,6 +166,7 @@ func runInteractiveMode() (string, error) {
 		url := prompt(reader, "健康检查端点 URL")
 		method := promptEnumWithDefault(reader, "HTTP 方法", "POST", []string{"GET", "POST", "PUT", "DELETE", "PATCH"})
 		successContains := promptWithDefault(reader, "响应体关键字 (success_contains)", "")
+		models := promptWithDefault(reader, "模型列表，逗号分隔 (第一个为父通道，其余生成子通道；留空则不生成)", "")
 
 		monitor := map[string]string{
 			"provider":         provider,
@@ -105,6 +178,7 @@ func runInteractiveMode() (string, error) {
 			"url":              url,
 			"method":           method,
 			"success_contains": successContains,
+			"models":           models,
 		}
 		monitors = append(monitors, monitor)
 
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	_ "modernc.org/sqlite" // 纯Go实现的SQLite驱动
+)
+
+// Uptime-Kuma heartbeat.status 取值
+const (
+	kumaDown        = 0
+	kumaUp          = 1
+	kumaPending     = 2
+	kumaMaintenance = 3
+)
+
+// kumaSource 读取 Uptime-Kuma 的 SQLite 数据库（kuma.db）
+// monitor 表提供监测项，heartbeat 表提供逐次检测结果（时间为 UTC）
+type kumaSource struct {
+	db *sql.DB
+}
+
+func openKuma(path string) (*kumaSource, error) {
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?mode=ro", path))
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &kumaSource{db: db}, nil
+}
+
+func (k *kumaSource) Close() error {
+	return k.db.Close()
+}
+
+func (k *kumaSource) Monitors() ([]sourceMonitor, error) {
+	rows, err := k.db.Query(`SELECT id, name, COALESCE(url, '') FROM monitor ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("读取 monitor 表失败（是否为 Uptime-Kuma 数据库？）: %w", err)
+	}
+	defer rows.Close()
+
+	var monitors []sourceMonitor
+	for rows.Next() {
+		var id int64
+		var m sourceMonitor
+		if err := rows.Scan(&id, &m.Name, &m.URL); err != nil {
+			return nil, err
+		}
+		m.ID = strconv.FormatInt(id, 10)
+		monitors = append(monitors, m)
+	}
+	return monitors, rows.Err()
+}
+
+// Samples 遍历 heartbeat：UP 记为绿色，DOWN 记为红色（按 msg 推断细分状态），
+// PENDING（重试中）与 MAINTENANCE（维护中）不代表可用性，跳过
+func (k *kumaSource) Samples(m sourceMonitor, fn func(sample) error) error {
+	rows, err := k.db.Query(`SELECT status, COALESCE(msg, ''), time, COALESCE(ping, 0)
+		FROM heartbeat WHERE monitor_id = ? ORDER BY time`, m.ID)
+	if err != nil {
+		return fmt.Errorf("读取 heartbeat 表失败: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status, ping int
+		var msg, ts string
+		if err := rows.Scan(&status, &msg, &ts, &ping); err != nil {
+			return err
+		}
+		t, ok := parseTime(ts, time.UTC)
+		if !ok {
+			return fmt.Errorf("无法解析 heartbeat 时间: %q", ts)
+		}
+
+		s := sample{Time: t, Latency: ping}
+		switch status {
+		case kumaUp:
+			s.Status, s.HttpCode = 1, 200
+		case kumaDown:
+			s.Status = 0
+			s.HttpCode, s.SubStatus = classifyFailure(msg)
+		default:
+			// kumaPending / kumaMaintenance
+			continue
+		}
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// 确保 kumaSource 实现 source 接口
+var _ source = (*kumaSource)(nil)
@@ -0,0 +1,273 @@
+// import 将 Uptime-Kuma（SQLite 数据库）或 UptimeRobot（日志 CSV）的历史可用性数据
+// 导入 probe_history，来源监测项通过 YAML 映射文件或交互式问答对应到已配置的
+// provider/service/channel[/model]，便于迁移后保留历史可用率。
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+// plan 一个来源监测项的导入计划
+type plan struct {
+	src    sourceMonitor
+	target *config.ServiceConfig
+	path   string
+
+	// 导入统计
+	green, yellow, red int
+	first, last        time.Time
+}
+
+func (p *plan) key() storage.MonitorKey {
+	return storage.MonitorKey{Provider: p.target.Provider, Service: p.target.Service, Channel: p.target.Channel, Model: p.target.Model}
+}
+
+func (p *plan) total() int {
+	return p.green + p.yellow + p.red
+}
+
+func openSource(kind, input string, loc *time.Location, interval time.Duration) (source, error) {
+	switch kind {
+	case "kuma", "uptime-kuma":
+		return openKuma(input)
+	case "uptimerobot", "robot":
+		return openUptimeRobot(input, loc, interval)
+	default:
+		return nil, fmt.Errorf("不支持的来源 %q（可选 kuma、uptimerobot）", kind)
+	}
+}
+
+// buildPlans 按映射文件（及交互式问答）确定每个来源监测项的导入目标
+// 返回导入计划与最终映射（用于 -write-mapping）
+func buildPlans(cfg *config.AppConfig, monitors []sourceMonitor, mf *mappingFile, interactive bool) ([]*plan, *mappingFile, error) {
+	var plans []*plan
+	result := &mappingFile{}
+	var ask *prompter
+	if interactive {
+		ask = newPrompter(cfg, os.Stdin, os.Stdout)
+	}
+
+	for _, m := range monitors {
+		entry, found := mf.lookup(m)
+		for {
+			if !found {
+				if ask == nil {
+					fmt.Printf("⏭️  未映射，跳过: %s\n", m.Name)
+					entry = mappingEntry{Source: m.Name, Skip: true}
+					break
+				}
+				var err error
+				if entry, err = ask.ask(m); err != nil {
+					return nil, nil, err
+				}
+			}
+			if entry.Skip || strings.TrimSpace(entry.Target) == "" {
+				entry.Skip = true
+				break
+			}
+			target, err := resolveTarget(cfg, entry.Target)
+			if err == nil {
+				plans = append(plans, &plan{src: m, target: target, path: targetPath(target)})
+				break
+			}
+			if ask == nil {
+				return nil, nil, fmt.Errorf("监测项 %s: %w", m.Name, err)
+			}
+			fmt.Printf("❌ %v\n", err)
+			found = false
+		}
+		result.Monitors = append(result.Monitors, entry)
+	}
+	return plans, result, nil
+}
+
+// toRecord 将来源样本转换为探测记录；成功但延迟超过监测项 slow_latency 时记为黄色
+func toRecord(key storage.MonitorKey, s sample, slowLatency time.Duration) *storage.ProbeRecord {
+	record := &storage.ProbeRecord{
+		Provider:  key.Provider,
+		Service:   key.Service,
+		Channel:   key.Channel,
+		Model:     key.Model,
+		Status:    s.Status,
+		SubStatus: s.SubStatus,
+		HttpCode:  s.HttpCode,
+		Latency:   s.Latency,
+		Timestamp: s.Time.Unix(),
+	}
+	if record.Status == 1 && slowLatency > 0 && time.Duration(s.Latency)*time.Millisecond > slowLatency {
+		record.Status = 2
+		record.SubStatus = storage.SubStatusSlowLatency
+	}
+	return record
+}
+
+func main() {
+	kind := flag.String("source", "", "来源类型：kuma（Uptime-Kuma SQLite 数据库）或 uptimerobot（日志 CSV）")
+	input := flag.String("input", "", "来源文件路径（kuma.db 或 UptimeRobot 导出的 CSV）")
+	configFile := flag.String("config", "config.yaml", "配置文件路径（使用其 storage 与 monitors）")
+	mappingPath := flag.String("mapping", "", "映射文件路径（YAML）")
+	interactive := flag.Bool("interactive", false, "交互式确认未在映射文件中的监测项")
+	writeMapping := flag.String("write-mapping", "", "将最终映射写入该文件（可作为下次的 -mapping）")
+	interval := flag.Duration("interval", 5*time.Minute, "UptimeRobot 状态事件展开为样本的间隔")
+	tz := flag.String("tz", "UTC", "来源中未携带时区的时间所在时区（UptimeRobot CSV）")
+	since := flag.String("since", "", "仅导入该时间之后的样本（如 2024-01-01 或 2024-01-01 08:00:00）")
+	until := flag.String("until", "", "仅导入该时间之前的样本（建议设为 RelayPulse 开始探测的时间，避免与已有数据重叠）")
+	dryRun := flag.Bool("dry-run", false, "仅统计将导入的样本，不写入存储")
+	flag.Parse()
+
+	if err := run(*kind, *input, *configFile, *mappingPath, *interactive, *writeMapping, *interval, *tz, *since, *until, *dryRun); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(kind, input, configFile, mappingPath string, interactive bool, writeMapping string,
+	interval time.Duration, tz, since, until string, dryRun bool) error {
+	if kind == "" || input == "" {
+		return errors.New("必须指定 -source 与 -input")
+	}
+	if interval <= 0 {
+		return errors.New("interval 必须大于 0")
+	}
+	if mappingPath == "" && !interactive {
+		return errors.New("请通过 -mapping 指定映射文件，或使用 -interactive 交互式映射")
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return fmt.Errorf("无效的时区 %q: %w", tz, err)
+	}
+	var from, to time.Time
+	if since != "" {
+		var ok bool
+		if from, ok = parseTime(since, loc); !ok {
+			return fmt.Errorf("无法解析 since 时间 %q", since)
+		}
+	}
+	if until != "" {
+		var ok bool
+		if to, ok = parseTime(until, loc); !ok {
+			return fmt.Errorf("无法解析 until 时间 %q", until)
+		}
+	}
+
+	if err := config.LoadDotenvFromConfigDir(configFile, false); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	cfg, err := config.NewLoader().Load(configFile)
+	if err != nil {
+		return fmt.Errorf("加载配置失败: %w", err)
+	}
+
+	var mf *mappingFile
+	if mappingPath != "" {
+		if mf, err = loadMapping(mappingPath); err != nil {
+			return fmt.Errorf("读取映射文件失败: %w", err)
+		}
+	}
+
+	src, err := openSource(kind, input, loc, interval)
+	if err != nil {
+		return fmt.Errorf("打开来源失败: %w", err)
+	}
+	defer src.Close()
+
+	monitors, err := src.Monitors()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("📥 来源 %s：%d 个监测项\n", kind, len(monitors))
+
+	plans, final, err := buildPlans(cfg, monitors, mf, interactive)
+	if err != nil {
+		return err
+	}
+	if writeMapping != "" {
+		if err := saveMapping(writeMapping, final); err != nil {
+			return fmt.Errorf("写入映射文件失败: %w", err)
+		}
+		fmt.Printf("📝 映射已写入 %s\n", writeMapping)
+	}
+	if len(plans) == 0 {
+		return errors.New("没有需要导入的监测项")
+	}
+
+	var store storage.Storage
+	if !dryRun {
+		if store, err = storage.New(&cfg.Storage); err != nil {
+			return fmt.Errorf("初始化存储失败: %w", err)
+		}
+		defer store.Close()
+		if err := store.Init(); err != nil {
+			return fmt.Errorf("初始化数据库失败: %w", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for _, p := range plans {
+		key := p.key()
+		err := src.Samples(p.src, func(s sample) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if (!from.IsZero() && s.Time.Before(from)) || (!to.IsZero() && !s.Time.Before(to)) {
+				return nil
+			}
+			record := toRecord(key, s, p.target.SlowLatencyDuration)
+			if store != nil {
+				if err := store.SaveRecord(record); err != nil {
+					return fmt.Errorf("写入记录失败: %w", err)
+				}
+			}
+			switch record.Status {
+			case 1:
+				p.green++
+			case 2:
+				p.yellow++
+			default:
+				p.red++
+			}
+			if p.first.IsZero() {
+				p.first = s.Time
+			}
+			p.last = s.Time
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("导入 %s 失败: %w", p.src.Name, err)
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\n来源\t目标\t样本\t绿\t黄\t红\t时间范围")
+	total := 0
+	for _, p := range plans {
+		span := "-"
+		if p.total() > 0 {
+			span = p.first.In(loc).Format("2006-01-02 15:04") + " ~ " + p.last.In(loc).Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%s\n", p.src.Name, p.path, p.total(), p.green, p.yellow, p.red, span)
+		total += p.total()
+	}
+	w.Flush()
+
+	if dryRun {
+		fmt.Printf("\n🔍 dry-run：共 %d 条样本，未写入存储\n", total)
+		return nil
+	}
+	fmt.Printf("\n✅ 导入完成：共 %d 条记录（存储 %s）\n", total, cfg.Storage.Type)
+	return nil
+}
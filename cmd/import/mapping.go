@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"monitor/internal/config"
+)
+
+// mappingFile 来源监测项到 RelayPulse 监测项的映射文件（YAML）
+//
+//	monitors:
+//	  - source: "OpenAI API"          # 来源监测项名称（Uptime-Kuma 也可写 "id:<id>"）
+//	    target: "openai/cx/vip"       # provider/service/channel[/model]
+//	  - source: "Old Endpoint"
+//	    skip: true                    # 不导入
+type mappingFile struct {
+	Monitors []mappingEntry `yaml:"monitors"`
+}
+
+type mappingEntry struct {
+	Source string `yaml:"source"`
+	Target string `yaml:"target,omitempty"`
+	Skip   bool   `yaml:"skip,omitempty"`
+}
+
+func loadMapping(path string) (*mappingFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var mf mappingFile
+	if err := yaml.Unmarshal(data, &mf); err != nil {
+		return nil, fmt.Errorf("解析映射文件失败: %w", err)
+	}
+	return &mf, nil
+}
+
+func saveMapping(path string, mf *mappingFile) error {
+	data, err := yaml.Marshal(mf)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// lookup 查找来源监测项的映射（按 "id:<id>" 或名称匹配）
+func (mf *mappingFile) lookup(m sourceMonitor) (mappingEntry, bool) {
+	if mf == nil {
+		return mappingEntry{}, false
+	}
+	for _, e := range mf.Monitors {
+		source := strings.TrimSpace(e.Source)
+		if source == "id:"+m.ID || source == m.Name {
+			return e, true
+		}
+	}
+	return mappingEntry{}, false
+}
+
+// targetPath 监测项的映射路径：多模型通道的父层（及单模型监测项）省略 model
+func targetPath(m *config.ServiceConfig) string {
+	path := fmt.Sprintf("%s/%s/%s", m.Provider, m.Service, m.Channel)
+	if strings.TrimSpace(m.Parent) != "" && m.Model != "" {
+		path += "/" + m.Model
+	}
+	return path
+}
+
+// resolveTarget 将映射目标解析为配置中的监测项
+// provider/service/channel 匹配父层（或单模型）监测项，provider/service/channel/model 精确匹配
+func resolveTarget(cfg *config.AppConfig, target string) (*config.ServiceConfig, error) {
+	parts := strings.Split(strings.Trim(strings.TrimSpace(target), "/"), "/")
+	if len(parts) < 3 || len(parts) > 4 {
+		return nil, fmt.Errorf("目标 %q 格式无效（应为 provider/service/channel[/model]）", target)
+	}
+	for i := range cfg.Monitors {
+		m := &cfg.Monitors[i]
+		if !strings.EqualFold(m.Provider, parts[0]) || !strings.EqualFold(m.Service, parts[1]) || !strings.EqualFold(m.Channel, parts[2]) {
+			continue
+		}
+		if len(parts) == 4 && m.Model != parts[3] {
+			continue
+		}
+		if len(parts) == 3 && strings.TrimSpace(m.Parent) != "" {
+			continue
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("目标 %q 不在配置的监测项中", target)
+}
+
+// prompter 交互式映射：逐个询问未映射的来源监测项
+type prompter struct {
+	in      *bufio.Reader
+	out     io.Writer
+	targets []string
+	byURL   map[string]int // 监测 URL → targets 下标（用于推荐默认映射）
+	listed  bool
+}
+
+func newPrompter(cfg *config.AppConfig, in io.Reader, out io.Writer) *prompter {
+	p := &prompter{in: bufio.NewReader(in), out: out, byURL: make(map[string]int)}
+	for i := range cfg.Monitors {
+		m := &cfg.Monitors[i]
+		p.targets = append(p.targets, targetPath(m))
+		if u := strings.TrimRight(strings.TrimSpace(m.URL), "/"); u != "" {
+			if _, exists := p.byURL[u]; !exists {
+				p.byURL[u] = len(p.targets) - 1
+			}
+		}
+	}
+	return p
+}
+
+// ask 询问来源监测项的映射目标；输入编号或路径，回车接受推荐（无推荐时跳过），"-" 跳过
+func (p *prompter) ask(m sourceMonitor) (mappingEntry, error) {
+	if !p.listed {
+		fmt.Fprintln(p.out, "\n已配置的监测项：")
+		for i, t := range p.targets {
+			fmt.Fprintf(p.out, "  [%d] %s\n", i+1, t)
+		}
+		p.listed = true
+	}
+
+	suggested := -1
+	if idx, ok := p.byURL[strings.TrimRight(strings.TrimSpace(m.URL), "/")]; ok && m.URL != "" {
+		suggested = idx
+	}
+
+	for {
+		label := m.Name
+		if m.URL != "" {
+			label += " (" + m.URL + ")"
+		}
+		if suggested >= 0 {
+			fmt.Fprintf(p.out, "\n映射 %s 到 [编号/路径，回车=%s，-=跳过]: ", label, p.targets[suggested])
+		} else {
+			fmt.Fprintf(p.out, "\n映射 %s 到 [编号/路径，回车或-=跳过]: ", label)
+		}
+
+		line, err := p.in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return mappingEntry{}, err
+		}
+		answer := strings.TrimSpace(line)
+		entry := mappingEntry{Source: m.Name}
+
+		switch {
+		case answer == "" && suggested >= 0:
+			entry.Target = p.targets[suggested]
+		case answer == "" || answer == "-":
+			entry.Skip = true
+		default:
+			if n, convErr := strconv.Atoi(answer); convErr == nil {
+				if n < 1 || n > len(p.targets) {
+					fmt.Fprintf(p.out, "编号超出范围 1-%d\n", len(p.targets))
+					continue
+				}
+				entry.Target = p.targets[n-1]
+			} else {
+				entry.Target = answer
+			}
+		}
+		return entry, nil
+	}
+}
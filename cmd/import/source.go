@@ -0,0 +1,93 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"monitor/internal/storage"
+)
+
+// sourceMonitor 来源系统中的监测项
+type sourceMonitor struct {
+	ID   string
+	Name string
+	URL  string
+}
+
+// sample 来源系统中的一条可用性样本（已映射为 RelayPulse 状态）
+type sample struct {
+	Time      time.Time
+	Status    int // 1=绿, 0=红
+	SubStatus storage.SubStatus
+	HttpCode  int
+	Latency   int // ms
+}
+
+// source 历史数据来源
+type source interface {
+	// Monitors 列出来源中的全部监测项
+	Monitors() ([]sourceMonitor, error)
+	// Samples 按时间升序遍历监测项的样本
+	Samples(m sourceMonitor, fn func(sample) error) error
+	Close() error
+}
+
+var httpCodePattern = regexp.MustCompile(`\b([1-5]\d{2})\b`)
+
+// classifyFailure 根据来源系统的失败描述推断 HTTP 状态码与细分状态
+// 描述中包含 HTTP 状态码时按内置规则映射（401/403、400、429、5xx、其它 4xx），否则视为网络错误
+func classifyFailure(msg string) (int, storage.SubStatus) {
+	m := httpCodePattern.FindStringSubmatch(msg)
+	if m == nil || !looksLikeHTTPStatus(msg) {
+		return 0, storage.SubStatusNetworkError
+	}
+	code, _ := strconv.Atoi(m[1])
+	switch {
+	case code == 401 || code == 403:
+		return code, storage.SubStatusAuthError
+	case code == 400:
+		return code, storage.SubStatusInvalidRequest
+	case code == 429:
+		return code, storage.SubStatusRateLimit
+	case code >= 500:
+		return code, storage.SubStatusServerError
+	case code >= 400:
+		return code, storage.SubStatusClientError
+	}
+	return 0, storage.SubStatusNetworkError
+}
+
+// looksLikeHTTPStatus 避免把超时毫秒数等数字误判为状态码
+func looksLikeHTTPStatus(msg string) bool {
+	lower := strings.ToLower(msg)
+	if strings.Contains(lower, "timeout") || strings.Contains(lower, "timed out") {
+		return false
+	}
+	return true
+}
+
+// timeLayouts 来源系统常见的时间格式
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"01/02/2006 15:04:05",
+	"01/02/2006 15:04",
+	"02-01-2006 15:04:05",
+}
+
+// parseTime 按常见格式解析时间，未携带时区的时间按 loc 解释
+func parseTime(value string, loc *time.Location) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"monitor/internal/storage"
+)
+
+// robotEvent UptimeRobot 日志中的一条状态事件
+type robotEvent struct {
+	start     time.Time
+	duration  time.Duration // 0 表示未知（持续到下一条事件）
+	up        bool
+	paused    bool
+	httpCode  int
+	subStatus storage.SubStatus
+}
+
+// robotSource 读取 UptimeRobot 导出的日志 CSV（Event / Monitor / Date-Time / Reason / Duration 等列）
+// UptimeRobot 只记录状态变化事件，导入时按 interval 将每段状态展开为等间隔样本
+type robotSource struct {
+	monitors []sourceMonitor
+	events   map[string][]robotEvent // monitor ID → 按时间升序的事件
+	interval time.Duration
+	now      time.Time
+}
+
+// robotColumns CSV 表头列位置（-1 表示不存在）
+type robotColumns struct {
+	monitor, url, event, dateTime, reason, duration int
+	durationUnit                                    time.Duration // 纯数字时长的单位
+}
+
+func detectRobotColumns(header []string) (robotColumns, error) {
+	cols := robotColumns{monitor: -1, url: -1, event: -1, dateTime: -1, reason: -1, duration: -1, durationUnit: time.Second}
+	for i, h := range header {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		switch {
+		case strings.Contains(name, "url"):
+			if cols.url < 0 {
+				cols.url = i
+			}
+		case strings.Contains(name, "monitor") || strings.Contains(name, "friendly name"):
+			if cols.monitor < 0 {
+				cols.monitor = i
+			}
+		case name == "event" || name == "type" || name == "status":
+			cols.event = i
+		case strings.Contains(name, "date") || name == "time":
+			cols.dateTime = i
+		case strings.Contains(name, "reason"):
+			cols.reason = i
+		case strings.Contains(name, "duration"):
+			// 同时存在多种时长列时优先秒，其次分钟
+			unit := time.Second
+			if strings.Contains(name, "min") {
+				unit = time.Minute
+			}
+			if cols.duration < 0 || (unit == time.Second && cols.durationUnit != time.Second) {
+				cols.duration, cols.durationUnit = i, unit
+			}
+		}
+	}
+	if cols.monitor < 0 || cols.event < 0 || cols.dateTime < 0 {
+		return cols, fmt.Errorf("CSV 缺少必需的列（Monitor、Event、Date-Time），表头: %v", header)
+	}
+	return cols, nil
+}
+
+var clockDurationPattern = regexp.MustCompile(`^(\d+):(\d{1,2}):(\d{1,2})$`)
+
+// parseRobotDuration 解析时长：纯数字（按列单位）、HH:MM:SS 或 "1h 2m 3s" 形式
+func parseRobotDuration(value string, unit time.Duration) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if n, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(n * float64(unit))
+	}
+	if m := clockDurationPattern.FindStringSubmatch(value); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		mi, _ := strconv.Atoi(m[2])
+		s, _ := strconv.Atoi(m[3])
+		return time.Duration(h)*time.Hour + time.Duration(mi)*time.Minute + time.Duration(s)*time.Second
+	}
+	compact := strings.NewReplacer(" ", "", ",", "", "hours", "h", "minutes", "m", "seconds", "s", "hrs", "h", "hr", "h", "mins", "m", "min", "m", "secs", "s", "sec", "s").Replace(strings.ToLower(value))
+	if d, err := time.ParseDuration(compact); err == nil {
+		return d
+	}
+	return 0
+}
+
+func openUptimeRobot(path string, loc *time.Location, interval time.Duration) (*robotSource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("读取 CSV 表头失败: %w", err)
+	}
+	cols, err := detectRobotColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	src := &robotSource{events: make(map[string][]robotEvent), interval: interval, now: time.Now()}
+	field := func(row []string, idx int) string {
+		if idx < 0 || idx >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[idx])
+	}
+
+	line := 1
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行: %w", line, err)
+		}
+
+		name := field(row, cols.monitor)
+		if name == "" {
+			continue
+		}
+		start, ok := parseTime(field(row, cols.dateTime), loc)
+		if !ok {
+			return nil, fmt.Errorf("第 %d 行: 无法解析时间 %q", line, field(row, cols.dateTime))
+		}
+
+		ev := robotEvent{start: start, duration: parseRobotDuration(field(row, cols.duration), cols.durationUnit)}
+		switch event := strings.ToLower(field(row, cols.event)); {
+		case strings.Contains(event, "down"):
+			ev.httpCode, ev.subStatus = classifyFailure(field(row, cols.reason))
+		case strings.Contains(event, "up"), strings.Contains(event, "start"), strings.Contains(event, "resume"):
+			ev.up = true
+		case strings.Contains(event, "pause"):
+			ev.paused = true
+		default:
+			continue // SSL 到期提醒等非状态事件
+		}
+
+		if _, exists := src.events[name]; !exists {
+			src.monitors = append(src.monitors, sourceMonitor{ID: name, Name: name, URL: field(row, cols.url)})
+		}
+		src.events[name] = append(src.events[name], ev)
+	}
+
+	for id := range src.events {
+		events := src.events[id]
+		sort.SliceStable(events, func(i, j int) bool { return events[i].start.Before(events[j].start) })
+	}
+	sort.Slice(src.monitors, func(i, j int) bool { return src.monitors[i].Name < src.monitors[j].Name })
+	return src, nil
+}
+
+func (r *robotSource) Close() error { return nil }
+
+func (r *robotSource) Monitors() ([]sourceMonitor, error) {
+	return r.monitors, nil
+}
+
+// Samples 将状态事件展开为样本：每段持续到 duration 结束（未知时到下一条事件，最后一段到当前时间），
+// 期间每 interval 生成一条；暂停期间不生成样本
+func (r *robotSource) Samples(m sourceMonitor, fn func(sample) error) error {
+	events := r.events[m.ID]
+	for i, ev := range events {
+		if ev.paused {
+			continue
+		}
+		end := r.now
+		if i+1 < len(events) {
+			end = events[i+1].start
+		}
+		if ev.duration > 0 && ev.start.Add(ev.duration).Before(end) {
+			end = ev.start.Add(ev.duration)
+		}
+
+		for t := ev.start; t.Before(end); t = t.Add(r.interval) {
+			s := sample{Time: t}
+			if ev.up {
+				s.Status, s.HttpCode = 1, 200
+			} else {
+				s.Status, s.HttpCode, s.SubStatus = 0, ev.httpCode, ev.subStatus
+			}
+			if err := fn(s); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// 确保 robotSource 实现 source 接口
+var _ source = (*robotSource)(nil)
@@ -0,0 +1,432 @@
+// loadtest 对运行中的实例回放典型查询组合（/api/status 各时间范围与过滤条件、事件轮询等），
+// 统计各场景 p50/p95/p99 延迟与错误率；可选先向存储写入指定规模的合成 probe_history，
+// 用于发布前发现 API 层性能回退。
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+// scenario 一类查询请求
+type scenario struct {
+	name   string
+	weight int
+	// build 生成本次请求的路径（含查询参数）
+	build func(r *rand.Rand, t *targets) string
+	// auth 是否携带事件 API Token
+	auth bool
+}
+
+// targets 从 /api/status 发现的 provider/service，用于构造带过滤条件的请求
+type targets struct {
+	providers []string
+	services  []string
+
+	mu      sync.Mutex
+	sinceID int64 // 事件轮询游标（模拟订阅方增量拉取）
+}
+
+func (t *targets) pick(r *rand.Rand, values []string, fallback string) string {
+	if len(values) == 0 {
+		return fallback
+	}
+	return values[r.Intn(len(values))]
+}
+
+// defaultScenarios 默认查询组合：以首页 24h/7d 为主，辅以长周期、时区过滤、单服务商与事件轮询
+func defaultScenarios(eventsToken string) []scenario {
+	list := []scenario{
+		{name: "status_24h", weight: 30, build: func(*rand.Rand, *targets) string { return "/api/status?period=24h" }},
+		{name: "status_7d", weight: 15, build: func(*rand.Rand, *targets) string { return "/api/status?period=7d" }},
+		{name: "status_30d", weight: 8, build: func(*rand.Rand, *targets) string { return "/api/status?period=30d" }},
+		{name: "status_90m", weight: 5, build: func(*rand.Rand, *targets) string { return "/api/status?period=90m" }},
+		{name: "status_tz_filter", weight: 5, build: func(*rand.Rand, *targets) string {
+			return "/api/status?period=7d&tz=Asia/Shanghai&time_filter=09:00-18:00"
+		}},
+		{name: "status_provider", weight: 10, build: func(r *rand.Rand, t *targets) string {
+			return "/api/status?period=24h&provider=" + url.QueryEscape(t.pick(r, t.providers, "all"))
+		}},
+		{name: "status_service_7d", weight: 5, build: func(r *rand.Rand, t *targets) string {
+			return "/api/status?period=7d&service=" + url.QueryEscape(t.pick(r, t.services, "all"))
+		}},
+		{name: "rankings", weight: 5, build: func(*rand.Rand, *targets) string { return "/api/rankings?period=7d" }},
+		{name: "heatmap", weight: 2, build: func(*rand.Rand, *targets) string { return "/api/heatmap" }},
+	}
+	// 事件接口强制鉴权，仅在提供 Token 时加入
+	if eventsToken != "" {
+		list = append(list,
+			scenario{name: "events_latest", weight: 5, auth: true, build: func(*rand.Rand, *targets) string { return "/api/events/latest" }},
+			scenario{name: "events_poll", weight: 10, auth: true, build: func(_ *rand.Rand, t *targets) string {
+				t.mu.Lock()
+				sinceID := t.sinceID
+				t.mu.Unlock()
+				return fmt.Sprintf("/api/events?since_id=%d&limit=100", sinceID)
+			}},
+		)
+	}
+	return list
+}
+
+// filterScenarios 按 -scenarios 参数筛选场景（逗号分隔，为空表示全部）
+func filterScenarios(list []scenario, names string) ([]scenario, error) {
+	if strings.TrimSpace(names) == "" {
+		return list, nil
+	}
+	byName := make(map[string]scenario, len(list))
+	for _, s := range list {
+		byName[s.name] = s
+	}
+	var out []scenario
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		s, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("未知场景: %s", name)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// sample 单次请求结果
+type sample struct {
+	scenario string
+	latency  time.Duration
+	failed   bool
+	canceled bool // 压测结束时被取消的请求，不计入统计
+}
+
+// loadRunner 压测执行器
+type loadRunner struct {
+	baseURL     string
+	client      *http.Client
+	eventsToken string
+	scenarios   []scenario
+	totalWeight int
+	targets     *targets
+}
+
+// discover 拉取一次 /api/status，收集 provider/service 作为过滤条件候选
+func (lr *loadRunner) discover(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, lr.baseURL+"/api/status?period=24h", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := lr.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET /api/status 返回 HTTP %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data []struct {
+			Provider string `json:"provider"`
+			Service  string `json:"service"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("解析 /api/status 响应失败: %w", err)
+	}
+
+	providers, services := map[string]bool{}, map[string]bool{}
+	for _, m := range body.Data {
+		if m.Provider != "" && !providers[strings.ToLower(m.Provider)] {
+			providers[strings.ToLower(m.Provider)] = true
+			lr.targets.providers = append(lr.targets.providers, strings.ToLower(m.Provider))
+		}
+		if m.Service != "" && !services[m.Service] {
+			services[m.Service] = true
+			lr.targets.services = append(lr.targets.services, m.Service)
+		}
+	}
+	return nil
+}
+
+// next 按权重抽取场景
+func (lr *loadRunner) next(r *rand.Rand) scenario {
+	n := r.Intn(lr.totalWeight)
+	for _, s := range lr.scenarios {
+		if n < s.weight {
+			return s
+		}
+		n -= s.weight
+	}
+	return lr.scenarios[len(lr.scenarios)-1]
+}
+
+// do 执行一次请求并读完响应体（计入传输耗时）
+func (lr *loadRunner) do(ctx context.Context, r *rand.Rand) sample {
+	s := lr.next(r)
+	result := sample{scenario: s.name}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, lr.baseURL+s.build(r, lr.targets), nil)
+	if err != nil {
+		result.failed = true
+		return result
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+	if s.auth {
+		req.Header.Set("Authorization", "Bearer "+lr.eventsToken)
+	}
+
+	start := time.Now()
+	resp, err := lr.client.Do(req)
+	if err != nil {
+		result.latency = time.Since(start)
+		result.failed = true
+		result.canceled = ctx.Err() != nil
+		return result
+	}
+	body, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	result.latency = time.Since(start)
+	result.failed = readErr != nil || resp.StatusCode >= 400
+	result.canceled = readErr != nil && ctx.Err() != nil
+
+	if s.name == "events_poll" && !result.failed {
+		lr.advanceEventCursor(body)
+	}
+	return result
+}
+
+// advanceEventCursor 根据 /api/events 响应推进轮询游标
+func (lr *loadRunner) advanceEventCursor(body []byte) {
+	var page struct {
+		Meta struct {
+			NextSinceID int64 `json:"next_since_id"`
+		} `json:"meta"`
+	}
+	if json.Unmarshal(body, &page) != nil || page.Meta.NextSinceID == 0 {
+		return
+	}
+	lr.targets.mu.Lock()
+	if page.Meta.NextSinceID > lr.targets.sinceID {
+		lr.targets.sinceID = page.Meta.NextSinceID
+	}
+	lr.targets.mu.Unlock()
+}
+
+// run 以固定并发（可选总速率上限）持续发压直到 ctx 结束
+func (lr *loadRunner) run(ctx context.Context, concurrency int, rps float64) []sample {
+	var ticker <-chan time.Time
+	if rps > 0 {
+		t := time.NewTicker(time.Duration(float64(time.Second) / rps))
+		defer t.Stop()
+		ticker = t.C
+	}
+
+	var (
+		mu      sync.Mutex
+		samples []sample
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+			var local []sample
+			for {
+				if ticker != nil {
+					select {
+					case <-ticker:
+					case <-ctx.Done():
+					}
+				}
+				if ctx.Err() != nil {
+					break
+				}
+				if s := lr.do(ctx, r); !s.canceled {
+					local = append(local, s)
+				}
+			}
+			mu.Lock()
+			samples = append(samples, local...)
+			mu.Unlock()
+		}(time.Now().UnixNano() + int64(i))
+	}
+	wg.Wait()
+	return samples
+}
+
+// stats 单个场景（或汇总）的统计结果
+type stats struct {
+	name          string
+	count         int
+	errors        int
+	p50, p95, p99 time.Duration
+	max           time.Duration
+}
+
+func (s stats) errorRate() float64 {
+	if s.count == 0 {
+		return 0
+	}
+	return float64(s.errors) / float64(s.count)
+}
+
+// percentile 最近秩法计算分位数（latencies 需已升序排列）
+func percentile(latencies []time.Duration, p float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	idx := int(float64(len(latencies))*p+0.999999) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(latencies) {
+		idx = len(latencies) - 1
+	}
+	return latencies[idx]
+}
+
+func summarize(name string, samples []sample) stats {
+	st := stats{name: name, count: len(samples)}
+	latencies := make([]time.Duration, 0, len(samples))
+	for _, s := range samples {
+		if s.failed {
+			st.errors++
+		}
+		latencies = append(latencies, s.latency)
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	st.p50 = percentile(latencies, 0.50)
+	st.p95 = percentile(latencies, 0.95)
+	st.p99 = percentile(latencies, 0.99)
+	if len(latencies) > 0 {
+		st.max = latencies[len(latencies)-1]
+	}
+	return st
+}
+
+// report 按场景输出统计表，返回汇总统计
+func report(w io.Writer, samples []sample, elapsed time.Duration) stats {
+	byScenario := make(map[string][]sample)
+	for _, s := range samples {
+		byScenario[s.scenario] = append(byScenario[s.scenario], s)
+	}
+	names := make([]string, 0, len(byScenario))
+	for name := range byScenario {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ms := func(d time.Duration) string { return fmt.Sprintf("%.1f", float64(d.Microseconds())/1000) }
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "scenario\trequests\terrors\terror%\tp50(ms)\tp95(ms)\tp99(ms)\tmax(ms)\t")
+	row := func(st stats) {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\t%s\t%s\t%s\t%s\t\n",
+			st.name, st.count, st.errors, st.errorRate()*100, ms(st.p50), ms(st.p95), ms(st.p99), ms(st.max))
+	}
+	for _, name := range names {
+		row(summarize(name, byScenario[name]))
+	}
+	total := summarize("TOTAL", samples)
+	row(total)
+	tw.Flush()
+
+	if elapsed > 0 {
+		fmt.Fprintf(w, "\n耗时 %s，吞吐 %.1f req/s\n", elapsed.Round(time.Millisecond), float64(total.count)/elapsed.Seconds())
+	}
+	return total
+}
+
+func main() {
+	baseURL := flag.String("url", "http://localhost:8080", "被测实例地址")
+	duration := flag.Duration("duration", time.Minute, "压测时长（0 表示仅写入合成数据）")
+	concurrency := flag.Int("concurrency", 20, "并发请求数")
+	rps := flag.Float64("rps", 0, "总请求速率上限（0 表示不限速，按并发尽力发压）")
+	timeout := flag.Duration("timeout", 30*time.Second, "单次请求超时")
+	scenarioNames := flag.String("scenarios", "", "仅运行指定场景（逗号分隔，默认全部）")
+	eventsToken := flag.String("events-token", os.Getenv("EVENTS_API_TOKEN"), "事件 API Token（配置后加入 /api/events 轮询场景）")
+	maxP95 := flag.Duration("max-p95", 0, "汇总 p95 超过该值时以非零状态退出（0 表示不检查）")
+	maxErrorRate := flag.Float64("max-error-rate", -1, "汇总错误率（0-1）超过该值时以非零状态退出（负数表示不检查）")
+
+	configFile := flag.String("config", "config.yaml", "配置文件路径（写入合成数据时使用其 storage 与 monitors）")
+	seedRecords := flag.Int("seed-records", 0, "压测前写入的合成 probe_history 记录总数（0 表示不写入）")
+	seedDays := flag.Int("seed-days", 30, "合成数据覆盖的天数（截至当前时间）")
+	seedMonitors := flag.Int("seed-monitors", 0, "配置中无监测项时生成的合成监测项数量")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *seedRecords > 0 {
+		if err := seed(ctx, *configFile, *seedRecords, *seedDays, *seedMonitors); err != nil {
+			fmt.Printf("❌ 写入合成数据失败: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *duration <= 0 {
+		return
+	}
+
+	list, err := filterScenarios(defaultScenarios(*eventsToken), *scenarioNames)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if *concurrency < 1 {
+		*concurrency = 1
+	}
+
+	lr := &loadRunner{
+		baseURL:     strings.TrimRight(*baseURL, "/"),
+		client:      &http.Client{Timeout: *timeout, Transport: &http.Transport{MaxIdleConnsPerHost: *concurrency}},
+		eventsToken: *eventsToken,
+		scenarios:   list,
+		targets:     &targets{},
+	}
+	for _, s := range list {
+		lr.totalWeight += s.weight
+	}
+
+	if err := lr.discover(ctx); err != nil {
+		fmt.Printf("⚠️  发现监测项失败，过滤类场景将使用 all: %v\n", err)
+	}
+	fmt.Printf("🚀 压测 %s：并发 %d，时长 %s，场景 %d 个，发现 provider %d 个\n",
+		lr.baseURL, *concurrency, *duration, len(list), len(lr.targets.providers))
+
+	runCtx, cancel := context.WithTimeout(ctx, *duration)
+	defer cancel()
+	start := time.Now()
+	samples := lr.run(runCtx, *concurrency, *rps)
+	elapsed := time.Since(start)
+
+	fmt.Println()
+	total := report(os.Stdout, samples, elapsed)
+
+	var failures []string
+	if *maxP95 > 0 && total.p95 > *maxP95 {
+		failures = append(failures, fmt.Sprintf("p95 %s 超过阈值 %s", total.p95.Round(time.Millisecond), *maxP95))
+	}
+	if *maxErrorRate >= 0 && total.errorRate() > *maxErrorRate {
+		failures = append(failures, fmt.Sprintf("错误率 %.2f%% 超过阈值 %.2f%%", total.errorRate()*100, *maxErrorRate*100))
+	}
+	if total.count == 0 {
+		failures = append(failures, "未完成任何请求")
+	}
+	if len(failures) > 0 {
+		fmt.Printf("\n❌ %s\n", strings.Join(failures, "；"))
+		os.Exit(1)
+	}
+	fmt.Println("\n✅ 压测完成")
+}
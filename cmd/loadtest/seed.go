@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+// seed 向配置的存储写入合成 probe_history，记录均匀分布在最近 days 天内
+// 监测项取自配置（跳过已禁用项）；配置中没有监测项时生成 syntheticMonitors 个合成监测项
+// （合成监测项不在配置中，API 不会展示，仅用于放大存储规模）
+func seed(ctx context.Context, configFile string, records, days, syntheticMonitors int) error {
+	if days < 1 {
+		return fmt.Errorf("seed-days 必须大于 0")
+	}
+
+	if err := config.LoadDotenvFromConfigDir(configFile, false); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	cfg, err := config.NewLoader().Load(configFile)
+	if err != nil {
+		return fmt.Errorf("加载配置失败: %w", err)
+	}
+
+	var keys []storage.MonitorKey
+	for _, m := range cfg.Monitors {
+		if m.Disabled {
+			continue
+		}
+		keys = append(keys, storage.MonitorKey{Provider: m.Provider, Service: m.Service, Channel: m.Channel, Model: m.Model})
+	}
+	if len(keys) == 0 {
+		for i := 0; i < syntheticMonitors; i++ {
+			keys = append(keys, storage.MonitorKey{Provider: fmt.Sprintf("loadtest-%d", i), Service: "cc", Channel: "default"})
+		}
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("配置中没有可用监测项，请通过 -seed-monitors 指定合成监测项数量")
+	}
+
+	store, err := storage.New(&cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("初始化存储失败: %w", err)
+	}
+	defer store.Close()
+	if err := store.Init(); err != nil {
+		return fmt.Errorf("初始化数据库失败: %w", err)
+	}
+
+	perMonitor := max(records/len(keys), 1)
+	span := time.Duration(days) * 24 * time.Hour
+	step := span / time.Duration(perMonitor)
+	start := time.Now().Add(-span)
+	total := perMonitor * len(keys)
+
+	fmt.Printf("🌱 写入合成数据：%d 个监测项 × %d 条 = %d 条，覆盖 %d 天（间隔 %s，存储 %s）\n",
+		len(keys), perMonitor, total, days, step.Round(time.Second), cfg.Storage.Type)
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	began := time.Now()
+	written := 0
+	for i := 0; i < perMonitor; i++ {
+		ts := start.Add(time.Duration(i) * step)
+		for _, key := range keys {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			record := syntheticRecord(r, key, ts.Add(time.Duration(r.Int63n(int64(step/2)+1))))
+			if err := store.SaveRecord(record); err != nil {
+				return fmt.Errorf("写入记录失败: %w", err)
+			}
+			written++
+			if written%max(total/10, 1) == 0 {
+				fmt.Printf("   %d/%d（%.0f 条/秒）\n", written, total, float64(written)/time.Since(began).Seconds())
+			}
+		}
+	}
+
+	fmt.Printf("✅ 已写入 %d 条记录，耗时 %s\n", written, time.Since(began).Round(time.Millisecond))
+	return nil
+}
+
+// syntheticRecord 生成一条合成探测记录：约 94% 绿色、3% 慢速黄色、3% 红色（服务端/网络/限流错误）
+func syntheticRecord(r *rand.Rand, key storage.MonitorKey, ts time.Time) *storage.ProbeRecord {
+	record := &storage.ProbeRecord{
+		Provider:  key.Provider,
+		Service:   key.Service,
+		Channel:   key.Channel,
+		Model:     key.Model,
+		Status:    1,
+		HttpCode:  200,
+		Latency:   300 + r.Intn(2500),
+		Timestamp: ts.Unix(),
+	}
+	switch roll := r.Float64(); {
+	case roll < 0.01:
+		record.Status, record.SubStatus, record.HttpCode = 0, storage.SubStatusServerError, 502
+	case roll < 0.02:
+		record.Status, record.SubStatus, record.HttpCode, record.Latency = 0, storage.SubStatusNetworkError, 0, 10000
+	case roll < 0.03:
+		record.Status, record.SubStatus, record.HttpCode = 0, storage.SubStatusRateLimit, 429
+	case roll < 0.06:
+		record.Status, record.SubStatus, record.Latency = 2, storage.SubStatusSlowLatency, 5000+r.Intn(10000)
+	}
+	return record
+}
@@ -0,0 +1,122 @@
+// migrate-channel 将 provider/service 下某通道的历史数据改名或合并到另一通道，
+// 在单个事务内同步迁移探测记录、状态事件、状态机状态、每日汇总与用量等表，
+// 用于通道重命名或多个通道合并后保留历史可用率与事件。
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func main() {
+	configFile := flag.String("config", "config.yaml", "配置文件路径（使用其 storage 与 monitors）")
+	provider := flag.String("provider", "", "服务商（provider 字段，必填）")
+	service := flag.String("service", "", "服务类型（service 字段，必填）")
+	from := flag.String("from", "", "原通道（必填）")
+	to := flag.String("to", "", "目标通道（必填；已有数据时合并）")
+	dryRun := flag.Bool("dry-run", false, "仅统计各表将迁移的行数，不修改数据")
+	flag.Parse()
+
+	rename := storage.ChannelRename{
+		Provider: strings.TrimSpace(*provider),
+		Service:  strings.TrimSpace(*service),
+		From:     strings.TrimSpace(*from),
+		To:       strings.TrimSpace(*to),
+	}
+	if err := run(*configFile, rename, *dryRun); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(configFile string, rename storage.ChannelRename, dryRun bool) error {
+	if rename.Provider == "" || rename.Service == "" || rename.From == "" || rename.To == "" {
+		return errors.New("必须指定 -provider、-service、-from 与 -to")
+	}
+	if rename.From == rename.To {
+		return errors.New("-from 与 -to 不能相同")
+	}
+
+	if err := config.LoadDotenvFromConfigDir(configFile, false); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	cfg, err := config.NewLoader().Load(configFile)
+	if err != nil {
+		return fmt.Errorf("加载配置失败: %w", err)
+	}
+	checkConfig(cfg, rename)
+
+	store, err := storage.New(&cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("初始化存储失败: %w", err)
+	}
+	defer store.Close()
+	if err := store.Init(); err != nil {
+		return fmt.Errorf("初始化数据库失败: %w", err)
+	}
+	rs, ok := store.(storage.ChannelRenameStorage)
+	if !ok {
+		return errors.New("当前存储后端不支持通道迁移")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	counts, err := rs.RenameChannel(ctx, rename, dryRun)
+	if err != nil {
+		return err
+	}
+
+	path := rename.Provider + "/" + rename.Service + "/"
+	fmt.Printf("🔀 %s%s → %s%s\n", path, rename.From, path, rename.To)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\n表\t行数")
+	var total int64
+	for _, c := range counts {
+		fmt.Fprintf(w, "%s\t%d\n", c.Table, c.Rows)
+		total += c.Rows
+	}
+	w.Flush()
+
+	if dryRun {
+		fmt.Printf("\n🔍 dry-run：共 %d 行将被迁移，已回滚，未修改数据\n", total)
+		return nil
+	}
+	fmt.Printf("\n✅ 迁移完成：共 %d 行（存储 %s）\n", total, cfg.Storage.Type)
+	return nil
+}
+
+// checkConfig 提示配置与迁移方向不一致的情况（不阻止执行）
+func checkConfig(cfg *config.AppConfig, rename storage.ChannelRename) {
+	var hasFrom, hasTo bool
+	for _, m := range cfg.Monitors {
+		if m.Provider != rename.Provider || m.Service != rename.Service {
+			continue
+		}
+		switch m.Channel {
+		case rename.From:
+			hasFrom = true
+		case rename.To:
+			hasTo = true
+		}
+	}
+	if hasFrom {
+		fmt.Printf("⚠️  配置中仍存在通道 %s，迁移后其新探测记录会继续写入原通道\n", rename.From)
+	}
+	if !hasTo {
+		fmt.Printf("⚠️  配置中不存在通道 %s，迁移后的历史数据不会展示\n", rename.To)
+	}
+	if cfg.Transparency.Enabled {
+		fmt.Println("⚠️  已启用 transparency：包含被迁移记录的已封存小时将无法通过 Merkle 校验")
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"monitor/internal/storage"
+
+	_ "modernc.org/sqlite"
+)
+
+// newMigrateTestEnv 创建 SQLite 配置文件并写入 relay/cc 下 old、new 两个通道的数据
+func newMigrateTestEnv(t *testing.T) (configFile string, db *sql.DB) {
+	t.Helper()
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "monitor.db")
+	configFile = filepath.Join(dir, "config.yaml")
+	cfg := fmt.Sprintf(`
+interval: "1m"
+storage:
+  type: "sqlite"
+  sqlite:
+    path: %q
+monitors:
+  - provider: "relay"
+    service: "cc"
+    channel: "new"
+    category: "public"
+    sponsor: "relay"
+    url: "https://relay.example.com"
+    method: "POST"
+`, dbPath)
+	if err := os.WriteFile(configFile, []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := storage.NewSQLiteStorage(dbPath)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := store.Init(); err != nil {
+		t.Fatalf("init sqlite: %v", err)
+	}
+	now := time.Now().Unix()
+	for i, channel := range []string{"old", "old", "old", "new"} {
+		if err := store.SaveRecord(&storage.ProbeRecord{Provider: "relay", Service: "cc", Channel: channel, Status: 1, Timestamp: now - int64(i)}); err != nil {
+			t.Fatalf("save record: %v", err)
+		}
+	}
+	store.Close()
+
+	db, err = sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	for _, q := range []string{
+		`INSERT INTO probe_daily (provider, service, channel, model, day, total, green, yellow, red, latency_sum, latency_count)
+			VALUES ('relay', 'cc', 'old', '', '2024-03-01', 10, 9, 0, 1, 900, 9), ('relay', 'cc', 'new', '', '2024-03-01', 5, 5, 0, 0, 500, 5)`,
+		`INSERT INTO decommissioned_monitors (provider, service, channel, retired_at) VALUES ('relay', 'cc', 'old', 1)`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	return configFile, db
+}
+
+// snapshot 返回受迁移影响的各表按通道汇总的行数与计数
+func snapshot(t *testing.T, db *sql.DB) map[string]string {
+	t.Helper()
+	got := make(map[string]string)
+	for table, q := range map[string]string{
+		"probe_history":           `SELECT channel, COUNT(*) FROM probe_history GROUP BY channel ORDER BY channel`,
+		"probe_daily":             `SELECT channel, SUM(total) FROM probe_daily GROUP BY channel ORDER BY channel`,
+		"decommissioned_monitors": `SELECT channel, COUNT(*) FROM decommissioned_monitors GROUP BY channel ORDER BY channel`,
+	} {
+		rows, err := db.Query(q)
+		if err != nil {
+			t.Fatalf("query %s: %v", table, err)
+		}
+		var parts []string
+		for rows.Next() {
+			var channel string
+			var n int
+			if err := rows.Scan(&channel, &n); err != nil {
+				t.Fatalf("scan %s: %v", table, err)
+			}
+			parts = append(parts, fmt.Sprintf("%s=%d", channel, n))
+		}
+		rows.Close()
+		got[table] = strings.Join(parts, ",")
+	}
+	return got
+}
+
+func TestRunMigratesChannel(t *testing.T) {
+	configFile, db := newMigrateTestEnv(t)
+	if got := snapshot(t, db)["probe_daily"]; got != "new=6,old=13" { // 含 SaveRecord 累计的当日汇总
+		t.Fatalf("unexpected seeded probe_daily: %s", got)
+	}
+
+	if err := run(configFile, storage.ChannelRename{Provider: "relay", Service: "cc", From: "old", To: "new"}, false); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	want := map[string]string{
+		"probe_history":           "new=4",
+		"probe_daily":             "new=19",
+		"decommissioned_monitors": "",
+	}
+	if got := snapshot(t, db); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected data after migration:\n got=%v\nwant=%v", got, want)
+	}
+}
+
+func TestRunRollsBackOnError(t *testing.T) {
+	configFile, db := newMigrateTestEnv(t)
+	before := snapshot(t, db)
+
+	// 最后一步（清理下线登记）失败：此前已在事务内完成的各表迁移必须全部回滚
+	if _, err := db.Exec(`CREATE TRIGGER fail_decommission BEFORE DELETE ON decommissioned_monitors
+		BEGIN SELECT RAISE(ABORT, 'forced failure'); END`); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+
+	err := run(configFile, storage.ChannelRename{Provider: "relay", Service: "cc", From: "old", To: "new"}, false)
+	if err == nil || !strings.Contains(err.Error(), "forced failure") {
+		t.Fatalf("expected forced failure, got %v", err)
+	}
+	if after := snapshot(t, db); !reflect.DeepEqual(after, before) {
+		t.Fatalf("data changed after failed migration:\n got=%v\nwant=%v", after, before)
+	}
+}
+
+func TestRunDryRunLeavesData(t *testing.T) {
+	configFile, db := newMigrateTestEnv(t)
+	before := snapshot(t, db)
+
+	if err := run(configFile, storage.ChannelRename{Provider: "relay", Service: "cc", From: "old", To: "new"}, true); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if after := snapshot(t, db); !reflect.DeepEqual(after, before) {
+		t.Fatalf("dry-run changed data:\n got=%v\nwant=%v", after, before)
+	}
+}
@@ -2,21 +2,30 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"syscall"
 	"time"
 
+	"monitor/internal/adminauth"
 	"monitor/internal/announcements"
 	"monitor/internal/api"
+	"monitor/internal/audit"
+	"monitor/internal/boards"
 	"monitor/internal/buildinfo"
 	"monitor/internal/config"
+	"monitor/internal/eventbus"
 	"monitor/internal/events"
 	"monitor/internal/logger"
 	"monitor/internal/scheduler"
 	"monitor/internal/selftest"
+	"monitor/internal/sla"
 	"monitor/internal/storage"
+	"monitor/internal/tracing"
+	"monitor/internal/transparency"
 )
 
 // buildChannelMigrationMappings 从配置构建 channel 迁移映射（同一 provider+service 取第一个非空 channel）
@@ -51,6 +60,23 @@ func buildChannelMigrationMappings(monitors []config.ServiceConfig) []storage.Ch
 }
 
 func main() {
+	genSchema := flag.Bool("gen-schema", false, "输出配置文件的 JSON Schema 到 stdout 后退出（供编辑器校验与补全）")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "用法: %s [-gen-schema] [config.yaml]\n", filepath.Base(os.Args[0]))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *genSchema {
+		data, err := config.ConfigSchemaJSON()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "生成配置 Schema 失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
 	// 打印版本信息
 	logger.Info("main", "Relay Pulse Monitor 启动",
 		"version", buildinfo.GetVersion(),
@@ -59,8 +85,22 @@ func main() {
 
 	// 配置文件路径
 	configFile := "config.yaml"
-	if len(os.Args) > 1 {
-		configFile = os.Args[1]
+	if flag.NArg() > 0 {
+		configFile = flag.Arg(0)
+	}
+
+	// 远程配置来源（可选）：启动时先同步一次，失败则回退到本地文件
+	remoteSyncer, err := config.NewRemoteSyncerFromEnv(configFile)
+	if err != nil {
+		logger.Error("main", "远程配置来源无效", "error", err)
+		os.Exit(1)
+	}
+	if remoteSyncer != nil {
+		if _, err := remoteSyncer.Sync(context.Background()); err != nil {
+			logger.Warn("main", "远程配置初次同步失败，使用本地配置文件", "source", remoteSyncer.Source(), "error", err)
+		} else {
+			logger.Info("main", "远程配置已同步", "source", remoteSyncer.Source())
+		}
 	}
 
 	// 创建配置加载器
@@ -73,6 +113,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	// 应用日志配置（级别、格式、采样与日志文件）
+	if err := logger.Configure(cfg.Logging.LoggerOptions()); err != nil {
+		logger.Error("main", "日志配置无效", "error", err)
+		os.Exit(1)
+	}
+	defer logger.Close()
+
 	logger.Info("main", "配置加载完成",
 		"monitors", len(cfg.Monitors),
 		"interval", cfg.Interval,
@@ -82,6 +129,19 @@ func main() {
 		"degraded_weight", cfg.DegradedWeight,
 	)
 
+	// 初始化分布式追踪（修改 tracing 配置需重启生效）
+	tracer, err := tracing.Init(cfg.Tracing)
+	if err != nil {
+		logger.Error("main", "初始化分布式追踪失败", "error", err)
+		os.Exit(1)
+	}
+	if tracer != nil {
+		logger.Info("main", "分布式追踪已启用",
+			"endpoint", cfg.Tracing.Endpoint,
+			"service_name", cfg.Tracing.ServiceName,
+			"sample_ratio", *cfg.Tracing.SampleRatio)
+	}
+
 	// 初始化存储（支持 SQLite 和 PostgreSQL）
 	store, err := storage.New(&cfg.Storage)
 	if err != nil {
@@ -100,6 +160,25 @@ func main() {
 		logger.Warn("main", "channel 数据迁移失败", "error", err)
 	}
 
+	// 对账已下线监测项登记（重新加入配置的监测项恢复正常展示）
+	if _, restored, err := storage.SyncDecommissionedMonitors(store, nil, cfg.Monitors, time.Now()); err != nil {
+		logger.Warn("main", "已下线监测项对账失败", "error", err)
+	} else if restored > 0 {
+		logger.Info("main", "已下线监测项重新加入配置", "restored", restored)
+	}
+
+	// 记录监测项元数据版本（服务商改名、赞助/价格/徽标变更后，历史导出仍可关联当时的元数据）
+	if _, err := storage.SyncMonitorMetadata(store, cfg.Monitors, time.Now()); err != nil {
+		logger.Warn("main", "记录监测项元数据版本失败", "error", err)
+	}
+
+	// 首次启动时按 ADMIN_BOOTSTRAP_USERNAME / ADMIN_BOOTSTRAP_PASSWORD 创建管理员账号
+	if created, err := adminauth.Bootstrap(store, cfg.AdminAuth.BootstrapUsername, cfg.AdminAuth.BootstrapPassword, time.Now()); err != nil {
+		logger.Warn("main", "创建初始管理员失败", "error", err)
+	} else if created {
+		logger.Info("main", "已创建初始管理员账号", "username", cfg.AdminAuth.BootstrapUsername)
+	}
+
 	storageType := cfg.Storage.Type
 	if storageType == "" {
 		storageType = "sqlite"
@@ -138,6 +217,38 @@ func main() {
 		}
 	}
 
+	// 启动数据库维护任务（低峰时段 VACUUM / ANALYZE / REINDEX）
+	var maintainer *storage.Maintainer
+	if cfg.Storage.Maintenance.Enabled {
+		maintainer = storage.NewMaintainer(store, &cfg.Storage.Maintenance)
+		go maintainer.Start(ctx)
+	}
+
+	// 审计日志记录器（audit.enabled=false 时记录为空操作）
+	auditRecorder := audit.NewRecorder(store, cfg.Audit)
+	go auditRecorder.Start(ctx)
+	if cfg.Audit.Enabled {
+		logger.Info("main", "审计日志已启用", "retention_days", cfg.Audit.RetentionDays)
+	}
+
+	// 启动探测数据透明度封存任务（按小时签名 Merkle 根）
+	var sealer *transparency.Sealer
+	if cfg.Transparency.Enabled {
+		if _, ok := store.(storage.TransparencyStorage); !ok {
+			logger.Warn("main", "透明度功能已启用但当前存储不支持，封存任务将不会执行",
+				"storage_type", cfg.Storage.Type)
+		} else if sealer, err = transparency.NewSealer(store, cfg.Transparency); err != nil {
+			logger.Error("main", "创建透明度封存任务失败", "error", err)
+			os.Exit(1)
+		} else {
+			go sealer.Start(ctx)
+			logger.Info("main", "透明度封存任务已启动",
+				"key_id", sealer.KeyID(),
+				"seal_delay", cfg.Transparency.SealDelay,
+				"backfill_hours", cfg.Transparency.BackfillHours)
+		}
+	}
+
 	// 创建调度器（支持通过 config.yaml 配置 interval）
 	interval := cfg.IntervalDuration
 	if interval <= 0 {
@@ -145,98 +256,107 @@ func main() {
 	}
 	sched := scheduler.NewScheduler(store, interval)
 
-	// 创建事件服务（如果启用）
-	eventSvc, err := events.NewService(events.ServiceConfig{
-		DetectorConfig: events.DetectorConfig{
-			DownThreshold: cfg.Events.DownThreshold,
-			UpThreshold:   cfg.Events.UpThreshold,
-		},
-		ChannelDetectorConfig: events.ChannelDetectorConfig{
-			DownThreshold: cfg.Events.ChannelDownThreshold,
-		},
-		Mode:             cfg.Events.Mode,
-		ChannelCountMode: cfg.Events.ChannelCountMode,
-		Enabled:          cfg.Events.Enabled,
-	}, store)
+	// 创建事件服务（未启用时同样创建但不处理探测记录，便于热更新开启）
+	eventSvc, err := events.NewService(events.NewServiceConfig(&cfg.Events), store)
 	if err != nil {
 		logger.Error("main", "创建事件服务失败", "error", err)
 		os.Exit(1)
 	}
+
+	// 创建事件总线发布器（如果启用）
+	var bus *eventbus.Publisher
+	if cfg.EventBus.Enabled {
+		bus, err = eventbus.New(cfg.EventBus)
+		if err != nil {
+			logger.Error("main", "创建事件总线发布器失败", "error", err)
+			os.Exit(1)
+		}
+		eventSvc.SetEventBus(bus)
+		sched.SetEventBus(bus)
+	}
+
+	sched.SetEventService(eventSvc)
+	// 初始化活跃模型索引
+	eventSvc.UpdateActiveModels(cfg.Monitors, cfg.Boards.Enabled)
+	eventSvc.UpdateBaselines(cfg.Monitors)
 	if eventSvc.IsEnabled() {
-		sched.SetEventService(eventSvc)
-		// 初始化活跃模型索引
-		eventSvc.UpdateActiveModels(cfg.Monitors, cfg.Boards.Enabled)
 		logger.Info("main", "事件服务已启用",
 			"mode", eventSvc.GetMode(),
 			"down_threshold", cfg.Events.DownThreshold,
 			"up_threshold", cfg.Events.UpThreshold,
 			"channel_down_threshold", cfg.Events.ChannelDownThreshold,
-			"channel_count_mode", cfg.Events.ChannelCountMode)
+			"channel_count_mode", cfg.Events.ChannelCountMode,
+			"cert_expiry_days", *cfg.Events.CertExpiryDays,
+			"degraded_threshold", cfg.Events.DegradedThreshold,
+			"webhooks", len(cfg.Events.Webhooks),
+			"outbox", cfg.Events.Outbox.Enabled)
 	}
 
 	sched.Start(ctx, cfg)
 
-	// 创建API服务器
-	server := api.NewServer(store, cfg, "8080")
-
-	// 初始化自助测试管理器（如果启用）
-	var selfTestMgr *selftest.TestJobManager
-	if cfg.SelfTest.Enabled {
-		// 设置 selftest 数据目录（用于动态读取 cc_base.json、cx_base.json 等模板）
-		// 数据目录为配置文件所在目录下的 data/ 子目录
-		configDir := filepath.Dir(configFile)
-		if configDir == "" || configDir == "." {
-			// 如果配置文件在当前目录，使用当前工作目录
-			if cwd, err := os.Getwd(); err == nil {
-				configDir = cwd
-			}
-		}
-		dataDir := filepath.Join(configDir, "data")
-		selftest.SetDataDir(dataDir)
+	// 启动 SLA 评估任务（未配置 sla.targets 时空转，热更新后生效；事件服务未启用时不发出 SLA 事件）
+	slaEvaluator := sla.NewEvaluator(store, eventSvc, cfg)
+	go slaEvaluator.Start(ctx)
+	if cfg.SLA.Enabled() {
+		logger.Info("main", "SLA 评估任务已启动",
+			"targets", len(cfg.SLA.Targets),
+			"interval", cfg.SLA.Interval,
+			"warning_threshold", cfg.SLA.WarningThreshold)
+	}
 
-		// 解析时间间隔
-		jobTimeout, err := time.ParseDuration(cfg.SelfTest.JobTimeout)
-		if err != nil || jobTimeout <= 0 {
-			jobTimeout = 30 * time.Second
-		}
-		resultTTL, err := time.ParseDuration(cfg.SelfTest.ResultTTL)
-		if err != nil || resultTTL <= 0 {
-			resultTTL = 2 * time.Minute
-		}
+	// 启动板块自动调整任务（未开启 boards.auto 时空转，热更新后生效；事件服务未启用时调整结果不持久化）
+	boardEngine := boards.NewEngine(store, eventSvc, cfg)
+	if cfg.Boards.Auto.Enabled {
+		logger.Info("main", "板块自动调整任务已启动",
+			"interval", cfg.Boards.Auto.Interval,
+			"window_days", cfg.Boards.Auto.WindowDays,
+			"secondary_below", cfg.Boards.Auto.SecondaryBelow,
+			"cold_below", cfg.Boards.Auto.ColdBelow)
+	}
 
-		// 应用默认值
-		maxConcurrent := cfg.SelfTest.MaxConcurrent
-		if maxConcurrent <= 0 {
-			maxConcurrent = 10
-		}
-		maxQueueSize := cfg.SelfTest.MaxQueueSize
-		if maxQueueSize <= 0 {
-			maxQueueSize = 50
-		}
-		rateLimitPerMinute := cfg.SelfTest.RateLimitPerMinute
-		if rateLimitPerMinute <= 0 {
-			rateLimitPerMinute = 10
+	// 创建API服务器
+	server := api.NewServer(store, cfg)
+	server.GetHandler().SetAuditRecorder(auditRecorder)
+	server.GetHandler().SetScheduler(sched)
+	server.GetHandler().SetSLAEvaluator(slaEvaluator)
+	server.GetHandler().SetBoardEngine(boardEngine)
+	boardEngine.OnChange(server.GetHandler().RefreshBoards)
+	go boardEngine.Start(ctx)
+	if maintainer != nil {
+		server.GetHandler().SetMaintainer(maintainer)
+	}
+	server.GetHandler().SetOverrideStore(config.NewOverrideStore(config.OverridesPath(configFile)))
+
+	// 初始化自助测试管理器（selftest.enabled 为 false 时同样创建，便于热更新开启）
+	// 设置 selftest 数据目录（用于动态读取 cc_base.json、cx_base.json 等模板）
+	// 数据目录为配置文件所在目录下的 data/ 子目录
+	configDir := filepath.Dir(configFile)
+	if configDir == "" || configDir == "." {
+		// 如果配置文件在当前目录，使用当前工作目录
+		if cwd, err := os.Getwd(); err == nil {
+			configDir = cwd
 		}
+	}
+	selftest.SetDataDir(filepath.Join(configDir, "data"))
 
-		// 创建 TestJobManager（内部创建独立的安全 prober）
-		selfTestMgr = selftest.NewTestJobManager(
-			maxConcurrent,
-			maxQueueSize,
-			jobTimeout,
-			resultTTL,
-			rateLimitPerMinute,
-			selftest.WithSlowLatencyByService(cfg.SlowLatencyByServiceDuration),
-		)
+	// 创建 TestJobManager（内部创建独立的安全 prober；默认值与人机验证配置已在 Normalize 中处理）
+	selfTestMgr, err := selftest.NewTestJobManagerFromConfig(cfg.SelfTest, cfg.SlowLatencyByServiceDuration)
+	if err != nil {
+		logger.Error("main", "初始化自助测试人机验证失败", "error", err)
+		os.Exit(1)
+	}
 
-		// 注入到 handler
-		server.GetHandler().SetSelfTestManager(selfTestMgr)
+	// 注入到 handler
+	server.GetHandler().SetSelfTestManager(selfTestMgr)
 
+	if cfg.SelfTest.Enabled {
 		logger.Info("main", "自助测试功能已启用",
-			"max_concurrent", maxConcurrent,
-			"max_queue_size", maxQueueSize,
-			"job_timeout", jobTimeout,
-			"result_ttl", resultTTL,
-			"rate_limit", rateLimitPerMinute)
+			"max_concurrent", cfg.SelfTest.MaxConcurrent,
+			"max_queue_size", cfg.SelfTest.MaxQueueSize,
+			"job_timeout", cfg.SelfTest.JobTimeoutDuration,
+			"result_ttl", cfg.SelfTest.ResultTTLDuration,
+			"rate_limit", cfg.SelfTest.RateLimitPerMinute,
+			"challenge", cfg.SelfTest.Challenge.Type)
 	}
 
 	// 初始化公告服务（如果启用）
@@ -265,14 +385,56 @@ func main() {
 	}
 
 	// 启动配置监听器（热更新）
+	prevMonitors := cfg.Monitors
+	startupServerCfg := cfg.Server
+	startupDiagListen := cfg.Diagnostics.Listen
 	watcher, err := config.NewWatcher(loader, configFile, func(newCfg *config.AppConfig) {
 		// 配置热更新回调
+		if err := logger.Configure(newCfg.Logging.LoggerOptions()); err != nil {
+			logger.Warn("main", "热更新日志配置失败，沿用原日志配置", "error", err)
+		}
+		// 事件配置先于调度器更新，重建后的任务按新阈值检测
+		if err := eventSvc.UpdateConfig(events.NewServiceConfig(&newCfg.Events)); err != nil {
+			logger.Warn("main", "热更新事件配置失败，沿用原事件配置", "error", err)
+		}
+		if err := selfTestMgr.UpdateConfig(newCfg.SelfTest, newCfg.SlowLatencyByServiceDuration); err != nil {
+			logger.Warn("main", "热更新自助测试配置失败，沿用原自助测试配置", "error", err)
+		}
 		sched.UpdateConfig(newCfg)
 		server.UpdateConfig(newCfg)
+		auditRecorder.UpdateConfig(newCfg.Audit)
+		slaEvaluator.UpdateConfig(newCfg)
+		boardEngine.UpdateConfig(newCfg)
+		// 监听地址、端口与证书路径仅在启动时生效
+		if newCfg.Server != startupServerCfg {
+			logger.Warn("main", "server 监听配置已变更，需重启后生效")
+		}
+		if newCfg.Diagnostics.Listen != startupDiagListen {
+			logger.Warn("main", "diagnostics.listen 已变更，需重启后生效")
+		}
+		auditRecorder.Record(ctx, storage.AuditEntry{
+			Actor:  "system:watcher",
+			Action: "config.reload",
+			Target: configFile,
+			Detail: fmt.Sprintf("monitors=%d", len(newCfg.Monitors)),
+		})
 		// 重新运行 channel 迁移（支持运行时添加 channel）
 		if err := store.MigrateChannelData(buildChannelMigrationMappings(newCfg.Monitors)); err != nil {
 			logger.Warn("main", "热更新时 channel 迁移失败", "error", err)
 		}
+		// 登记从配置移除的监测项（宽限期内仍可通过 include_retired 查询历史，期满由清理任务删除）
+		retired, restored, err := storage.SyncDecommissionedMonitors(store, prevMonitors, newCfg.Monitors, time.Now())
+		if err != nil {
+			logger.Warn("main", "热更新时登记已下线监测项失败", "error", err)
+		} else if retired > 0 || restored > 0 {
+			logger.Info("main", "已下线监测项登记已更新", "retired", retired, "restored", restored)
+		}
+		if versions, err := storage.SyncMonitorMetadata(store, newCfg.Monitors, time.Now()); err != nil {
+			logger.Warn("main", "热更新时记录监测项元数据版本失败", "error", err)
+		} else if versions > 0 {
+			logger.Info("main", "监测项元数据版本已更新", "versions", versions)
+		}
+		prevMonitors = newCfg.Monitors
 		// 注意：不再调用 TriggerNow()，rebuildTasks 已安排错峰首次执行
 		// 避免与 rebuildTasks 的首轮调度产生竞态导致重复探测
 	})
@@ -280,10 +442,27 @@ func main() {
 	if err != nil {
 		logger.Warn("main", "配置监听器创建失败，热更新功能不可用", "error", err)
 	} else {
+		// badges.yaml 变更仅更新 API 展示的徽标，不重建调度任务
+		watcher.SetBadgesReloadHandler(func(newCfg *config.AppConfig) {
+			server.UpdateConfig(newCfg)
+			if _, err := storage.SyncMonitorMetadata(store, newCfg.Monitors, time.Now()); err != nil {
+				logger.Warn("main", "徽标重载时记录监测项元数据版本失败", "error", err)
+			}
+			auditRecorder.Record(ctx, storage.AuditEntry{
+				Actor:  "system:watcher",
+				Action: "config.badges_reload",
+				Target: config.BadgesPath(configFile),
+				Detail: fmt.Sprintf("definitions=%d providers=%d", len(newCfg.BadgeDefs), len(newCfg.BadgeProviders)),
+			})
+		})
 		if err := watcher.Start(ctx); err != nil {
 			logger.Warn("main", "配置监听器启动失败，热更新功能不可用", "error", err)
 		} else {
 			logger.Info("main", "配置热更新已启用")
+			// 远程配置写入本地文件后由 watcher 触发热更新
+			if remoteSyncer != nil {
+				go remoteSyncer.Start(ctx)
+			}
 		}
 	}
 
@@ -291,6 +470,20 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
+	// SIGHUP：重新加载 HTTPS 证书（证书续期后无需重启）
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	go func() {
+		for range hupChan {
+			if err := server.ReloadTLS(); err != nil {
+				logger.Warn("main", "重新加载 HTTPS 证书失败，继续使用原证书", "error", err)
+			}
+		}
+	}()
+
+	// 预热常用查询的响应缓存后再开始监听，避免滚动发布时新实例的第一波请求直接打到数据库
+	server.GetHandler().WarmCache(ctx)
+
 	// 启动HTTP服务器（阻塞）
 	go func() {
 		if err := server.Start(); err != nil {
@@ -308,14 +501,15 @@ func main() {
 	// 取消上下文
 	cancel()
 
-	// 停止调度器
+	// 停止调度器（等待在途探测完成结果写入与事件检测，受 shutdown_drain_timeout 约束）
 	sched.Stop()
 
-	// 停止自助测试管理器（如果启用）
-	if selfTestMgr != nil {
-		selfTestMgr.Stop()
-		logger.Info("main", "自助测试管理器已关闭")
-	}
+	// 停止事件服务（等待进行中的 Webhook 投递，未完成的重试写入死信）
+	eventSvc.Stop()
+
+	// 停止自助测试管理器
+	selfTestMgr.Stop()
+	logger.Info("main", "自助测试管理器已关闭")
 
 	// 停止公告服务（如果启用）
 	if announcementsSvc != nil {
@@ -323,7 +517,19 @@ func main() {
 		logger.Info("main", "公告服务已关闭")
 	}
 
-	// 停止清理和归档任务
+	// 停止审计日志清理任务
+	auditRecorder.Stop()
+
+	// 停止透明度封存任务
+	if sealer != nil {
+		sealer.Stop()
+	}
+
+	// 停止 SLA 评估任务
+	slaEvaluator.Stop()
+	boardEngine.Stop()
+
+	// 停止清理、归档和维护任务
 	if cleaner != nil {
 		cleaner.Stop()
 		logger.Info("main", "历史数据清理任务已关闭")
@@ -332,6 +538,10 @@ func main() {
 		archiver.Stop()
 		logger.Info("main", "历史数据归档任务已关闭")
 	}
+	if maintainer != nil {
+		maintainer.Stop()
+		logger.Info("main", "数据库维护任务已关闭")
+	}
 
 	// 停止HTTP服务器
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -341,5 +551,17 @@ func main() {
 		logger.Warn("main", "HTTP服务器关闭错误", "error", err)
 	}
 
+	// 发布事件总线缓冲中剩余的消息
+	if err := bus.Stop(shutdownCtx); err != nil {
+		logger.Warn("main", "事件总线消息发布未完成", "error", err)
+	}
+
+	// 刷新未上报的追踪数据
+	if tracer != nil {
+		if err := tracer.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("main", "追踪数据刷新失败", "error", err)
+		}
+	}
+
 	logger.Info("main", "服务已安全退出")
 }
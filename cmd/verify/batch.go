@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/monitor"
+)
+
+// 批量验证结果状态
+const (
+	resultPass     = "pass"     // 绿色
+	resultDegraded = "degraded" // 黄色（慢响应等，不计为失败）
+	resultFail     = "fail"     // 红色
+)
+
+// batchOptions 批量验证参数
+type batchOptions struct {
+	providers []string // 服务商过滤（忽略大小写，空表示全部）
+	services  []string // 服务过滤（忽略大小写，空表示全部）
+	channel   string   // 通道过滤（为空表示全部）
+	model     string   // 模型过滤（为空表示全部）
+	parallel  int      // 最大并发数
+	retries   int      // 额外重试次数（-1 表示沿用配置）
+	format    string   // 输出格式：table / json / junit
+	output    string   // 输出文件（为空时写到 stdout）
+}
+
+// verifyResult 单个监测项的验证结果
+type verifyResult struct {
+	Provider  string `json:"provider"`
+	Service   string `json:"service"`
+	Channel   string `json:"channel,omitempty"`
+	Model     string `json:"model,omitempty"`
+	Status    string `json:"status"` // pass / degraded / fail
+	SubStatus string `json:"sub_status,omitempty"`
+	HTTPCode  int    `json:"http_code"`
+	LatencyMs int    `json:"latency_ms"`
+	Attempts  int    `json:"attempts"` // 实际请求次数（含重试）
+	Error     string `json:"error,omitempty"`
+
+	duration time.Duration // 含重试的总耗时（JUnit time 属性）
+}
+
+// batchSummary 批量验证汇总
+type batchSummary struct {
+	Total      int   `json:"total"`
+	Passed     int   `json:"passed"`
+	Degraded   int   `json:"degraded"`
+	Failed     int   `json:"failed"`
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// splitList 解析逗号分隔的过滤列表
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// matchAny 值是否命中过滤列表（忽略大小写，空列表视为全部命中）
+func matchAny(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectMonitors 按过滤条件选出待验证的监测项（跳过已停用的监测项，保持配置顺序）
+func selectMonitors(cfg *config.AppConfig, opts batchOptions) []config.ServiceConfig {
+	var targets []config.ServiceConfig
+	for _, m := range cfg.Monitors {
+		if m.Disabled {
+			continue
+		}
+		if !matchAny(opts.providers, m.Provider) || !matchAny(opts.services, m.Service) {
+			continue
+		}
+		if opts.channel != "" && !strings.EqualFold(opts.channel, m.Channel) {
+			continue
+		}
+		if opts.model != "" && !strings.EqualFold(opts.model, m.Model) {
+			continue
+		}
+		if opts.retries >= 0 {
+			m.RetryCount = opts.retries
+		}
+		targets = append(targets, m)
+	}
+	return targets
+}
+
+// runBatch 并发验证全部选中的监测项，返回进程退出码（任一失败时为 1）
+func runBatch(ctx context.Context, cfg *config.AppConfig, opts batchOptions) int {
+	targets := selectMonitors(cfg, opts)
+	if len(targets) == 0 {
+		fmt.Fprintln(os.Stderr, "❌ 没有匹配的检测项")
+		return 1
+	}
+
+	parallel := opts.parallel
+	if parallel <= 0 {
+		parallel = 1
+	}
+	fmt.Fprintf(os.Stderr, "🔍 批量验证 %d 个检测项（并发 %d）...\n", len(targets), parallel)
+
+	prober := monitor.NewProber(nil)
+	defer prober.Close()
+
+	start := time.Now()
+	results := make([]verifyResult, len(targets))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = verifyMonitor(ctx, prober, &targets[i])
+		}(i)
+	}
+	wg.Wait()
+
+	summary := batchSummary{Total: len(results), DurationMs: time.Since(start).Milliseconds()}
+	for _, r := range results {
+		switch r.Status {
+		case resultPass:
+			summary.Passed++
+		case resultDegraded:
+			summary.Degraded++
+		default:
+			summary.Failed++
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if opts.output != "" {
+		f, err := os.Create(opts.output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ 创建输出文件失败: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := writeReport(w, opts.format, results, summary); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ 输出结果失败: %v\n", err)
+		return 1
+	}
+
+	if summary.Failed > 0 {
+		fmt.Fprintf(os.Stderr, "❌ %d/%d 个检测项验证失败\n", summary.Failed, summary.Total)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "✅ 全部 %d 个检测项验证通过\n", summary.Total)
+	return 0
+}
+
+// verifyMonitor 使用与调度器相同的探测逻辑验证单个监测项（含配置的重试、状态映射与内容匹配）
+func verifyMonitor(ctx context.Context, prober *monitor.Prober, m *config.ServiceConfig) verifyResult {
+	start := time.Now()
+	r := prober.Probe(ctx, m)
+
+	result := verifyResult{
+		Provider:  m.Provider,
+		Service:   m.Service,
+		Channel:   m.Channel,
+		Model:     m.Model,
+		SubStatus: string(r.SubStatus),
+		HTTPCode:  r.HttpCode,
+		LatencyMs: r.Latency,
+		Attempts:  r.Attempts,
+		duration:  time.Since(start),
+	}
+	switch r.Status {
+	case 1:
+		result.Status = resultPass
+	case 2:
+		result.Status = resultDegraded
+	default:
+		result.Status = resultFail
+	}
+	if r.Error != nil {
+		result.Error = r.Error.Error()
+	}
+	return result
+}
@@ -3,16 +3,21 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"monitor/internal/config"
+	"monitor/internal/logger"
 )
 
 func main() {
@@ -22,12 +27,50 @@ func main() {
 	model := flag.String("model", "", "Model name (optional, uses first matching channel if not specified)")
 	configFile := flag.String("config", "config.yaml", "Config file path")
 	verbose := flag.Bool("v", false, "Verbose output")
+	all := flag.Bool("all", false, "Verify all monitors in batch mode (-provider/-service accept comma-separated filters)")
+	parallel := flag.Int("parallel", 4, "Batch mode: max concurrent probes")
+	retries := flag.Int("retries", -1, "Batch mode: extra retries per monitor (-1 uses config retry)")
+	format := flag.String("format", formatTable, "Batch mode output format: table, json, junit")
+	output := flag.String("output", "", "Batch mode: write report to file instead of stdout")
 
 	flag.Parse()
 
+	if *all {
+		if *format != formatTable && *format != formatJSON && *format != formatJUnit {
+			fmt.Printf("❌ 无效的输出格式: %s（支持 table/json/junit）\n", *format)
+			os.Exit(1)
+		}
+		// 日志写到 stderr，避免污染 stdout 上的结构化结果
+		logger.SetOutput(os.Stderr)
+		if err := config.LoadDotenvFromConfigDir(*configFile, *verbose); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		}
+		cfg, err := config.NewLoader().Load(*configFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ 加载配置失败: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		code := runBatch(ctx, cfg, batchOptions{
+			providers: splitList(*provider),
+			services:  splitList(*service),
+			channel:   strings.TrimSpace(*channel),
+			model:     strings.TrimSpace(*model),
+			parallel:  *parallel,
+			retries:   *retries,
+			format:    *format,
+			output:    *output,
+		})
+		stop()
+		os.Exit(code)
+	}
+
 	if *provider == "" || *service == "" {
-		fmt.Println("用法: go run cmd/verify/main.go -provider <name> -service <name> [-channel <name>] [-model <name>] [-config <path>] [-v]")
-		fmt.Println("示例: go run cmd/verify/main.go -provider 88code -service cx -channel vip3 -model gpt-5.1-codex-mini -v")
+		fmt.Println("用法: go run ./cmd/verify -provider <name> -service <name> [-channel <name>] [-model <name>] [-config <path>] [-v]")
+		fmt.Println("      go run ./cmd/verify -all [-provider <a,b>] [-service <a,b>] [-parallel 4] [-retries -1] [-format table|json|junit] [-output <file>]")
+		fmt.Println("示例: go run ./cmd/verify -provider 88code -service cx -channel vip3 -model gpt-5.1-codex-mini -v")
+		fmt.Println("      go run ./cmd/verify -all -format junit -output verify.xml")
 		os.Exit(1)
 	}
 
@@ -90,16 +133,15 @@ func main() {
 		if target.Model != "" {
 			fmt.Printf("  Model: %s\n", target.Model)
 		}
-		fmt.Printf("  URL: %s\n", target.URL)
+		if u, err := url.Parse(target.URL); err == nil {
+			fmt.Printf("  URL: %s\n", cfg.Redaction.RedactURL(u))
+		}
 		fmt.Printf("  Method: %s\n", target.Method)
 		fmt.Printf("  Success Contains: %s\n", target.SuccessContains)
 		fmt.Printf("  Headers:\n")
 		for k, v := range target.Headers {
-			// 隐藏 API key
-			if strings.Contains(strings.ToLower(k), "key") || strings.Contains(strings.ToLower(k), "auth") {
-				v = v[:min(10, len(v))] + "..."
-			}
-			fmt.Printf("    %s: %s\n", k, v)
+			// 按 redaction 策略隐藏凭证
+			fmt.Printf("    %s: %s\n", k, cfg.Redaction.Redact(k, v))
 		}
 		fmt.Printf("  Body (%d bytes):\n", len(target.Body))
 		if len(target.Body) > 200 {
@@ -134,7 +176,7 @@ func main() {
 	if *verbose {
 		fmt.Println("📨 实际请求 Headers:")
 		for k, v := range req.Header {
-			fmt.Printf("    %s: %s\n", k, v)
+			fmt.Printf("    %s: %s\n", k, cfg.Redaction.Redact(k, strings.Join(v, ", ")))
 		}
 		fmt.Println()
 	}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// 支持的输出格式
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatJUnit = "junit"
+)
+
+// writeReport 按格式输出批量验证结果
+func writeReport(w io.Writer, format string, results []verifyResult, summary batchSummary) error {
+	switch format {
+	case formatJSON:
+		return writeJSONReport(w, results, summary)
+	case formatJUnit:
+		return writeJUnitReport(w, results, summary)
+	default:
+		return writeTableReport(w, results, summary)
+	}
+}
+
+// monitorLabel 监测项标识 provider/service/channel[/model]
+func monitorLabel(r verifyResult) string {
+	label := r.Provider + "/" + r.Service + "/" + r.Channel
+	if r.Model != "" {
+		label += "/" + r.Model
+	}
+	return label
+}
+
+// failureReason 结果说明（细分状态与错误信息）
+func failureReason(r verifyResult) string {
+	reason := r.SubStatus
+	if r.Error != "" {
+		if reason != "" {
+			reason += ": "
+		}
+		reason += r.Error
+	}
+	return reason
+}
+
+// writeTableReport 输出对齐的文本表格
+func writeTableReport(w io.Writer, results []verifyResult, summary batchSummary) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tMONITOR\tHTTP\tLATENCY\tATTEMPTS\tDETAIL")
+	for _, r := range results {
+		icon := "✅"
+		switch r.Status {
+		case resultDegraded:
+			icon = "⚠️"
+		case resultFail:
+			icon = "❌"
+		}
+		detail := failureReason(r)
+		if runes := []rune(detail); len(runes) > 120 {
+			detail = string(runes[:120]) + "..."
+		}
+		fmt.Fprintf(tw, "%s %s\t%s\t%d\t%dms\t%d\t%s\n",
+			icon, r.Status, monitorLabel(r), r.HTTPCode, r.LatencyMs, r.Attempts, detail)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n共 %d 项：通过 %d，降级 %d，失败 %d，耗时 %dms\n",
+		summary.Total, summary.Passed, summary.Degraded, summary.Failed, summary.DurationMs)
+	return err
+}
+
+// writeJSONReport 输出 JSON（summary + results）
+func writeJSONReport(w io.Writer, results []verifyResult, summary batchSummary) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Summary batchSummary   `json:"summary"`
+		Results []verifyResult `json:"results"`
+	}{summary, results})
+}
+
+// junitTestSuites JUnit XML 根节点
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite 每个服务商一个 testsuite
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase 每个监测项一个 testcase
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitFailure 失败详情
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnitReport 输出 JUnit XML（按服务商分组为 testsuite，红色结果记为 failure，黄色结果记入 system-out）
+func writeJUnitReport(w io.Writer, results []verifyResult, summary batchSummary) error {
+	root := junitTestSuites{
+		Name:     "relay-pulse verify",
+		Tests:    summary.Total,
+		Failures: summary.Failed,
+		Time:     fmt.Sprintf("%.3f", float64(summary.DurationMs)/1000),
+	}
+
+	suiteIdx := make(map[string]int)
+	suiteSecs := make(map[string]float64)
+	for _, r := range results {
+		idx, ok := suiteIdx[r.Provider]
+		if !ok {
+			idx = len(root.Suites)
+			suiteIdx[r.Provider] = idx
+			root.Suites = append(root.Suites, junitTestSuite{Name: r.Provider})
+		}
+		suite := &root.Suites[idx]
+
+		secs := r.duration.Seconds()
+		suiteSecs[r.Provider] += secs
+		name := strings.TrimPrefix(monitorLabel(r), r.Provider+"/")
+		tc := junitTestCase{
+			Name:      name,
+			ClassName: r.Provider + "." + r.Service,
+			Time:      fmt.Sprintf("%.3f", secs),
+		}
+		switch r.Status {
+		case resultFail:
+			suite.Failures++
+			msg := r.SubStatus
+			if msg == "" {
+				msg = fmt.Sprintf("HTTP %d", r.HTTPCode)
+			}
+			tc.Failure = &junitFailure{
+				Message: msg,
+				Type:    resultFail,
+				Text:    fmt.Sprintf("HTTP %d, 延迟 %dms, 尝试 %d 次\n%s", r.HTTPCode, r.LatencyMs, r.Attempts, failureReason(r)),
+			}
+		case resultDegraded:
+			tc.SystemOut = fmt.Sprintf("degraded: HTTP %d, 延迟 %dms, %s", r.HTTPCode, r.LatencyMs, failureReason(r))
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, tc)
+	}
+	for i := range root.Suites {
+		root.Suites[i].Time = fmt.Sprintf("%.3f", suiteSecs[root.Suites[i].Name])
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -5,7 +5,7 @@ import { useTranslation } from 'react-i18next';
 import { Helmet } from 'react-helmet-async';
 import { Header } from './components/Header';
 import { Controls } from './components/Controls';
-import { StatusTable } from './components/StatusTable';
+import { StatusTable, TABLE_COLUMNS, type TableColumn } from './components/StatusTable';
 import { StatusCard } from './components/StatusCard';
 import { Tooltip } from './components/Tooltip';
 import { Footer } from './components/Footer';
@@ -16,6 +16,7 @@ import { useSeoMeta } from './hooks/useSeoMeta';
 import { useUrlState } from './hooks/useUrlState';
 import { useFavorites } from './hooks/useFavorites';
 import { useAnnouncements } from './hooks/useAnnouncements';
+import { THEMES } from './hooks/useTheme';
 import { createMediaQueryEffect } from './utils/mediaQuery';
 import { trackPeriodChange, trackServiceFilter, trackEvent } from './utils/analytics';
 import type { TooltipState, ProcessedMonitorData, ChannelOption } from './types';
@@ -33,18 +34,20 @@ function App() {
     return new URLSearchParams(location.search).get('screenshot') === '1';
   }, [location.search]);
 
-  // 截图模式下强制使用 default-dark 主题
+  // 截图模式下使用 theme 参数指定的主题（无效或缺省时为 default-dark）
   useEffect(() => {
     if (!isScreenshotMode) return;
+    const requested = new URLSearchParams(location.search).get('theme');
+    const theme = THEMES.find((item) => item.id === requested) ?? THEMES[0];
     const root = document.documentElement;
-    root.setAttribute('data-theme', 'default-dark');
-    root.style.colorScheme = 'dark';
-  }, [isScreenshotMode]);
+    root.setAttribute('data-theme', theme.id);
+    root.style.colorScheme = theme.isDark ? 'dark' : 'light';
+  }, [isScreenshotMode, location.search]);
 
   // 截图时间戳（组件挂载时记录）
   const screenshotTimestamp = useMemo(() => {
     if (!isScreenshotMode) return '';
-    return new Date().toLocaleString('zh-CN', {
+    return new Date().toLocaleString(i18n.language, {
       year: 'numeric',
       month: '2-digit',
       day: '2-digit',
@@ -53,7 +56,7 @@ function App() {
       hour12: false,
       timeZone: 'Asia/Shanghai',
     });
-  }, [isScreenshotMode]);
+  }, [isScreenshotMode, i18n.language]);
 
   // 截图标题（群名专属标识）
   const screenshotTitle = useMemo(() => {
@@ -66,6 +69,27 @@ function App() {
     return cleaned;
   }, [isScreenshotMode, location.search]);
 
+  // 截图品牌文案（自托管时由截图模板指定）
+  const screenshotBrand = useMemo(() => {
+    if (!isScreenshotMode) return '';
+    const raw = new URLSearchParams(location.search).get('brand') || '';
+    const cleaned = raw.replace(/[\r\n\t]+/g, ' ').trim();
+    const chars = Array.from(cleaned);
+    if (chars.length > 40) return chars.slice(0, 40).join('') + '…';
+    return cleaned;
+  }, [isScreenshotMode, location.search]);
+
+  // 截图展示的表格列（columns=provider,service,...；缺省或全部无效时展示全部列）
+  const screenshotColumns = useMemo<TableColumn[] | undefined>(() => {
+    if (!isScreenshotMode) return undefined;
+    const raw = new URLSearchParams(location.search).get('columns') || '';
+    const columns = raw
+      .split(',')
+      .map((item) => item.trim().toLowerCase())
+      .filter((item): item is TableColumn => (TABLE_COLUMNS as readonly string[]).includes(item));
+    return columns.length > 0 ? columns : undefined;
+  }, [isScreenshotMode, location.search]);
+
   // 使用 URL 状态同步 Hook，支持收藏和分享
   const [urlState, urlActions] = useUrlState();
   const {
@@ -638,17 +662,22 @@ function App() {
           {/* 截图模式标题栏 */}
           {isScreenshotMode && (
             <div className="mb-3 px-3 py-2 bg-elevated border border-default rounded-lg text-xs text-secondary">
-              {/* 群专属标题行 - 仅当有 title 时显示 */}
-              {screenshotTitle && (
-                <div className="text-sm text-primary font-medium mb-1 truncate">
-                  {screenshotTitle}
+              {/* 品牌与群专属标题行 - 仅当有 brand 或 title 时显示 */}
+              {(screenshotBrand || screenshotTitle) && (
+                <div className="flex items-center gap-2 text-sm mb-1 min-w-0">
+                  {screenshotBrand && (
+                    <span className="text-accent font-semibold flex-shrink-0">{screenshotBrand}</span>
+                  )}
+                  {screenshotTitle && (
+                    <span className="text-primary font-medium truncate">{screenshotTitle}</span>
+                  )}
                 </div>
               )}
               {/* 时间和服务信息行 */}
               <div className="flex items-center justify-between">
                 <span className="font-mono">{screenshotTimestamp}</span>
                 <span>
-                  {filteredData.length} 个服务 | {timeRange}
+                  {t('screenshot.summary', { count: filteredData.length, period: timeRange })}
                 </span>
               </div>
             </div>
@@ -694,6 +723,7 @@ function App() {
                   enableBadges={isScreenshotMode ? false : enableBadges}
                   showCategoryTag={!isScreenshotMode}
                   showSponsor={!isScreenshotMode}
+                  columns={screenshotColumns}
                   isFavorite={isFavorite}
                   onToggleFavorite={toggleFavorite}
                   onSort={handleSort}
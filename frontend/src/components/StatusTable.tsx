@@ -19,6 +19,10 @@ import type { ProcessedMonitorData, SortConfig } from '../types';
 
 type HistoryPoint = ProcessedMonitorData['history'][number];
 
+// 桌面表格可选列（截图模式通过 columns 参数选择展示的列）
+export const TABLE_COLUMNS = ['provider', 'service', 'channel', 'price', 'listed', 'status', 'uptime', 'latency', 'trend'] as const;
+export type TableColumn = (typeof TABLE_COLUMNS)[number];
+
 // 虚拟滚动常量
 const MOBILE_ROW_HEIGHT = 160;  // 移动端卡片高度（约 150px 内容 + 10px 间距）
 const MOBILE_MAX_HEIGHT = 800;  // 移动端列表最大高度
@@ -90,6 +94,7 @@ interface StatusTableProps {
   showCategoryTag?: boolean; // 是否显示分类标签（推荐/公益），默认 true
   showProvider?: boolean;    // 是否显示服务商名称，默认 true
   showSponsor?: boolean;     // 是否显示赞助者信息，默认 true
+  columns?: readonly TableColumn[]; // 桌面表格展示的列，默认全部
   isFavorite: (id: string) => boolean;  // 检查是否已收藏
   onToggleFavorite: (id: string) => void; // 切换收藏状态
   onSort: (key: string) => void;
@@ -349,6 +354,7 @@ function StatusTableComponent({
   showCategoryTag = true,
   showProvider = true,
   showSponsor = true,
+  columns,
   isFavorite,
   onToggleFavorite,
   onSort,
@@ -431,6 +437,8 @@ function StatusTableComponent({
 
   // 检查是否有任何徽标需要显示
   const hasBadges = hasAnyBadgeInList(data, { enableBadges, showCategoryTag, showSponsor, showRisk: true });
+  // 列是否展示（未指定 columns 时展示全部）
+  const showColumn = (column: TableColumn) => !columns || columns.includes(column);
 
   // 桌面端：表格视图
   return (
@@ -453,7 +461,7 @@ function StatusTableComponent({
               </th>
             )}
             {/* 服务商列（合并赞助者） */}
-            {showProvider && (
+            {showProvider && showColumn('provider') && (
               <th
                 className="px-3 py-3 font-medium cursor-pointer hover:text-accent transition-colors focus-visible:ring-2 focus-visible:ring-accent/50 focus-visible:outline-none"
                 onClick={() => onSort('providerName')}
@@ -466,107 +474,123 @@ function StatusTableComponent({
                 </div>
               </th>
             )}
-            <th
-              className="px-2 py-3 font-medium cursor-pointer hover:text-accent transition-colors whitespace-nowrap focus-visible:ring-2 focus-visible:ring-accent/50 focus-visible:outline-none"
-              onClick={() => onSort('serviceType')}
-              onKeyDown={(e) => (e.key === 'Enter' || e.key === ' ') && (e.preventDefault(), onSort('serviceType'))}
-              tabIndex={0}
-              role="button"
-            >
-              <div className="flex items-center">
-                {t('table.headers.service')} <SortIcon columnKey="serviceType" />
-              </div>
-            </th>
-            <th
-              className="px-2 py-3 font-medium cursor-pointer hover:text-accent transition-colors focus-visible:ring-2 focus-visible:ring-accent/50 focus-visible:outline-none"
-              onClick={() => onSort('channel')}
-              onKeyDown={(e) => (e.key === 'Enter' || e.key === ' ') && (e.preventDefault(), onSort('channel'))}
-              tabIndex={0}
-              role="button"
-            >
-              <div className="flex items-center">
-                {t('table.headers.channel')} <SortIcon columnKey="channel" />
-              </div>
-            </th>
-            <th
-              className="px-2 py-3 font-medium cursor-pointer hover:text-accent transition-colors focus-visible:ring-2 focus-visible:ring-accent/50 focus-visible:outline-none"
-              onClick={() => onSort('priceRatio')}
-              onKeyDown={(e) => (e.key === 'Enter' || e.key === ' ') && (e.preventDefault(), onSort('priceRatio'))}
-              tabIndex={0}
-              role="button"
-            >
-              <div className="flex items-center">
-                <div className="flex flex-col leading-tight">
-                  <span>{t('table.headers.priceRatio')}</span>
-                  <span className="text-[10px] opacity-50 font-normal">{t('table.headers.priceRatioUnit')}</span>
+            {showColumn('service') && (
+              <th
+                className="px-2 py-3 font-medium cursor-pointer hover:text-accent transition-colors whitespace-nowrap focus-visible:ring-2 focus-visible:ring-accent/50 focus-visible:outline-none"
+                onClick={() => onSort('serviceType')}
+                onKeyDown={(e) => (e.key === 'Enter' || e.key === ' ') && (e.preventDefault(), onSort('serviceType'))}
+                tabIndex={0}
+                role="button"
+              >
+                <div className="flex items-center">
+                  {t('table.headers.service')} <SortIcon columnKey="serviceType" />
                 </div>
-                <SortIcon columnKey="priceRatio" />
-              </div>
-            </th>
-            <th
-              className="px-2 py-3 font-medium cursor-pointer hover:text-accent transition-colors focus-visible:ring-2 focus-visible:ring-accent/50 focus-visible:outline-none"
-              onClick={() => onSort('listedDays')}
-              onKeyDown={(e) => (e.key === 'Enter' || e.key === ' ') && (e.preventDefault(), onSort('listedDays'))}
-              tabIndex={0}
-              role="button"
-            >
-              <div className="flex items-center">
-                <div className="flex flex-col leading-tight max-w-[4.5rem]">
-                  <span>{t('table.headers.listedDaysLine1')}</span>
-                  <span className="text-[10px] opacity-50 font-normal">{t('table.headers.listedDaysLine2')}</span>
+              </th>
+            )}
+            {showColumn('channel') && (
+              <th
+                className="px-2 py-3 font-medium cursor-pointer hover:text-accent transition-colors focus-visible:ring-2 focus-visible:ring-accent/50 focus-visible:outline-none"
+                onClick={() => onSort('channel')}
+                onKeyDown={(e) => (e.key === 'Enter' || e.key === ' ') && (e.preventDefault(), onSort('channel'))}
+                tabIndex={0}
+                role="button"
+              >
+                <div className="flex items-center">
+                  {t('table.headers.channel')} <SortIcon columnKey="channel" />
                 </div>
-                <SortIcon columnKey="listedDays" />
-              </div>
-            </th>
-            <th
-              className="px-2 py-3 font-medium cursor-pointer hover:text-accent transition-colors focus-visible:ring-2 focus-visible:ring-accent/50 focus-visible:outline-none"
-              onClick={() => onSort('currentStatus')}
-              onKeyDown={(e) => (e.key === 'Enter' || e.key === ' ') && (e.preventDefault(), onSort('currentStatus'))}
-              tabIndex={0}
-              role="button"
-            >
-              <div className="flex items-center">
-                <div className="flex flex-col leading-tight max-w-[4rem]">
-                  <span>{t('table.headers.statusLine1')}</span>
-                  <span className="text-[10px] opacity-50 font-normal">{t('table.headers.statusLine2')}</span>
+              </th>
+            )}
+            {showColumn('price') && (
+              <th
+                className="px-2 py-3 font-medium cursor-pointer hover:text-accent transition-colors focus-visible:ring-2 focus-visible:ring-accent/50 focus-visible:outline-none"
+                onClick={() => onSort('priceRatio')}
+                onKeyDown={(e) => (e.key === 'Enter' || e.key === ' ') && (e.preventDefault(), onSort('priceRatio'))}
+                tabIndex={0}
+                role="button"
+              >
+                <div className="flex items-center">
+                  <div className="flex flex-col leading-tight">
+                    <span>{t('table.headers.priceRatio')}</span>
+                    <span className="text-[10px] opacity-50 font-normal">{t('table.headers.priceRatioUnit')}</span>
+                  </div>
+                  <SortIcon columnKey="priceRatio" />
                 </div>
-                <SortIcon columnKey="currentStatus" />
-              </div>
-            </th>
-            <th
-              className="px-2 py-3 font-medium cursor-pointer hover:text-accent transition-colors whitespace-nowrap focus-visible:ring-2 focus-visible:ring-accent/50 focus-visible:outline-none"
-              onClick={() => onSort('uptime')}
-              onKeyDown={(e) => (e.key === 'Enter' || e.key === ' ') && (e.preventDefault(), onSort('uptime'))}
-              tabIndex={0}
-              role="button"
-            >
-              <div className="flex items-center">
-                {t('table.headers.uptime')} <SortIcon columnKey="uptime" />
-              </div>
-            </th>
-            <th
-              className="px-2 py-3 font-medium cursor-pointer hover:text-accent transition-colors focus-visible:ring-2 focus-visible:ring-accent/50 focus-visible:outline-none"
-              onClick={() => onSort('latency')}
-              onKeyDown={(e) => (e.key === 'Enter' || e.key === ' ') && (e.preventDefault(), onSort('latency'))}
-              tabIndex={0}
-              role="button"
-            >
-              <div className="flex items-center">
-                <div className="flex flex-col leading-tight max-w-[4rem]">
-                  <span>{t('table.headers.lastCheckLine1')}</span>
-                  <span className="text-[10px] opacity-50 font-normal">{t('table.headers.lastCheckLine2')}</span>
+              </th>
+            )}
+            {showColumn('listed') && (
+              <th
+                className="px-2 py-3 font-medium cursor-pointer hover:text-accent transition-colors focus-visible:ring-2 focus-visible:ring-accent/50 focus-visible:outline-none"
+                onClick={() => onSort('listedDays')}
+                onKeyDown={(e) => (e.key === 'Enter' || e.key === ' ') && (e.preventDefault(), onSort('listedDays'))}
+                tabIndex={0}
+                role="button"
+              >
+                <div className="flex items-center">
+                  <div className="flex flex-col leading-tight max-w-[4.5rem]">
+                    <span>{t('table.headers.listedDaysLine1')}</span>
+                    <span className="text-[10px] opacity-50 font-normal">{t('table.headers.listedDaysLine2')}</span>
+                  </div>
+                  <SortIcon columnKey="listedDays" />
                 </div>
-                <SortIcon columnKey="latency" />
-              </div>
-            </th>
-            <th className="pl-2 pr-4 py-3 font-medium w-[360px] min-w-[320px]">
-              <div className="flex items-center gap-2">
-                {t('table.headers.trend')}
-                <span className="text-[10px] normal-case opacity-50 border border-default px-1 rounded">
-                  {currentTimeRange?.label}
-                </span>
-              </div>
-            </th>
+              </th>
+            )}
+            {showColumn('status') && (
+              <th
+                className="px-2 py-3 font-medium cursor-pointer hover:text-accent transition-colors focus-visible:ring-2 focus-visible:ring-accent/50 focus-visible:outline-none"
+                onClick={() => onSort('currentStatus')}
+                onKeyDown={(e) => (e.key === 'Enter' || e.key === ' ') && (e.preventDefault(), onSort('currentStatus'))}
+                tabIndex={0}
+                role="button"
+              >
+                <div className="flex items-center">
+                  <div className="flex flex-col leading-tight max-w-[4rem]">
+                    <span>{t('table.headers.statusLine1')}</span>
+                    <span className="text-[10px] opacity-50 font-normal">{t('table.headers.statusLine2')}</span>
+                  </div>
+                  <SortIcon columnKey="currentStatus" />
+                </div>
+              </th>
+            )}
+            {showColumn('uptime') && (
+              <th
+                className="px-2 py-3 font-medium cursor-pointer hover:text-accent transition-colors whitespace-nowrap focus-visible:ring-2 focus-visible:ring-accent/50 focus-visible:outline-none"
+                onClick={() => onSort('uptime')}
+                onKeyDown={(e) => (e.key === 'Enter' || e.key === ' ') && (e.preventDefault(), onSort('uptime'))}
+                tabIndex={0}
+                role="button"
+              >
+                <div className="flex items-center">
+                  {t('table.headers.uptime')} <SortIcon columnKey="uptime" />
+                </div>
+              </th>
+            )}
+            {showColumn('latency') && (
+              <th
+                className="px-2 py-3 font-medium cursor-pointer hover:text-accent transition-colors focus-visible:ring-2 focus-visible:ring-accent/50 focus-visible:outline-none"
+                onClick={() => onSort('latency')}
+                onKeyDown={(e) => (e.key === 'Enter' || e.key === ' ') && (e.preventDefault(), onSort('latency'))}
+                tabIndex={0}
+                role="button"
+              >
+                <div className="flex items-center">
+                  <div className="flex flex-col leading-tight max-w-[4rem]">
+                    <span>{t('table.headers.lastCheckLine1')}</span>
+                    <span className="text-[10px] opacity-50 font-normal">{t('table.headers.lastCheckLine2')}</span>
+                  </div>
+                  <SortIcon columnKey="latency" />
+                </div>
+              </th>
+            )}
+            {showColumn('trend') && (
+              <th className="pl-2 pr-4 py-3 font-medium w-[360px] min-w-[320px]">
+                <div className="flex items-center gap-2">
+                  {t('table.headers.trend')}
+                  <span className="text-[10px] normal-case opacity-50 border border-default px-1 rounded">
+                    {currentTimeRange?.label}
+                  </span>
+                </div>
+              </th>
+            )}
           </tr>
         </thead>
         <tbody className="divide-y divide-default/50 text-sm">
@@ -594,7 +618,7 @@ function StatusTableComponent({
                 </td>
               )}
               {/* 服务商列（两行紧贴，整体垂直居中） */}
-              {showProvider && (
+              {showProvider && showColumn('provider') && (
                 <td className="px-2 py-1.5">
                   <div className="flex items-center h-8 group/provider">
                     <div className="flex flex-col gap-0 flex-1 min-w-0">
@@ -636,115 +660,131 @@ function StatusTableComponent({
                   </div>
                 </td>
               )}
-              <td className="px-2 py-1">
-                <span
-                  className={`inline-flex items-center px-2 py-0.5 rounded text-xs font-mono border ${
-                    item.serviceType === 'cc'
-                      ? 'border-service-cc text-service-cc bg-service-cc'
-                      : item.serviceType === 'gm'
-                      ? 'border-service-gm text-service-gm bg-service-gm'
-                      : 'border-service-cx text-service-cx bg-service-cx'
-                  }`}
-                >
-                  {ServiceIcon ? (
-                    <ServiceIcon className="w-3.5 h-3.5 mr-1 text-primary" />
-                  ) : (
-                    <>
-                      {item.serviceType === 'cc' && <Zap size={10} className="mr-1 text-primary" />}
-                      {item.serviceType === 'cx' && <Shield size={10} className="mr-1 text-primary" />}
-                    </>
-                  )}
-                  {item.serviceName.toUpperCase()}
-                </span>
-              </td>
-              <td className="px-2 py-1 text-secondary text-xs">
-                <ChannelCell
-                  channel={item.channelName || item.channel}
-                  probeUrl={item.probeUrl}
-                  templateName={item.templateName}
-                />
-              </td>
-              <td className="px-2 py-1 font-mono text-xs whitespace-nowrap">
-                {(() => {
-                  const priceData = formatPriceRatioStructured(item.priceMin, item.priceMax);
-                  if (!priceData) return <span className="text-muted">-</span>;
-                  return (
-                    <div className="flex flex-col leading-tight">
-                      <span className="text-secondary">{priceData.base}</span>
-                      {priceData.sub && (
-                        <span className="text-[10px] text-muted">{priceData.sub}</span>
-                      )}
-                    </div>
-                  );
-                })()}
-              </td>
-              <td className="px-2 py-1 font-mono text-xs text-secondary whitespace-nowrap">
-                {item.listedDays != null ? `${item.listedDays}d` : '-'}
-              </td>
-              <td className="px-2 py-1">
-                <div className="flex items-center gap-1.5 whitespace-nowrap">
-                  <StatusDot status={item.currentStatus} size="sm" />
-                  <span className={STATUS[item.currentStatus].text}>
-                    {STATUS[item.currentStatus].label}
-                  </span>
-                </div>
-              </td>
-              <td className="px-2 py-1 font-mono font-bold whitespace-nowrap">
-                <span style={{ color: availabilityToColor(item.uptime) }}>
-                  {item.uptime >= 0 ? `${item.uptime}%` : '--'}
-                </span>
-              </td>
-              <td className="px-2 py-1">
-                {item.lastCheckTimestamp ? (
-                  <div className="text-xs text-secondary font-mono flex flex-col gap-0.5">
-                    <span>{new Date(item.lastCheckTimestamp * 1000).toLocaleString(i18n.language, { month: '2-digit', day: '2-digit', hour: '2-digit', minute: '2-digit' })}</span>
-                    {item.lastCheckLatency !== undefined && (
-                      <span
-                        className="text-[10px] font-mono"
-                        style={{ color: item.currentStatus === 'UNAVAILABLE' ? 'hsl(var(--text-muted))' : latencyToColor(item.lastCheckLatency, item.slowLatencyMs ?? slowLatencyMs) }}
-                      >
-                        {item.lastCheckLatency}ms
-                      </span>
+              {showColumn('service') && (
+                <td className="px-2 py-1">
+                  <span
+                    className={`inline-flex items-center px-2 py-0.5 rounded text-xs font-mono border ${
+                      item.serviceType === 'cc'
+                        ? 'border-service-cc text-service-cc bg-service-cc'
+                        : item.serviceType === 'gm'
+                        ? 'border-service-gm text-service-gm bg-service-gm'
+                        : 'border-service-cx text-service-cx bg-service-cx'
+                    }`}
+                  >
+                    {ServiceIcon ? (
+                      <ServiceIcon className="w-3.5 h-3.5 mr-1 text-primary" />
+                    ) : (
+                      <>
+                        {item.serviceType === 'cc' && <Zap size={10} className="mr-1 text-primary" />}
+                        {item.serviceType === 'cx' && <Shield size={10} className="mr-1 text-primary" />}
+                      </>
                     )}
+                    {item.serviceName.toUpperCase()}
+                  </span>
+                </td>
+              )}
+              {showColumn('channel') && (
+                <td className="px-2 py-1 text-secondary text-xs">
+                  <ChannelCell
+                    channel={item.channelName || item.channel}
+                    probeUrl={item.probeUrl}
+                    templateName={item.templateName}
+                  />
+                </td>
+              )}
+              {showColumn('price') && (
+                <td className="px-2 py-1 font-mono text-xs whitespace-nowrap">
+                  {(() => {
+                    const priceData = formatPriceRatioStructured(item.priceMin, item.priceMax);
+                    if (!priceData) return <span className="text-muted">-</span>;
+                    return (
+                      <div className="flex flex-col leading-tight">
+                        <span className="text-secondary">{priceData.base}</span>
+                        {priceData.sub && (
+                          <span className="text-[10px] text-muted">{priceData.sub}</span>
+                        )}
+                      </div>
+                    );
+                  })()}
+                </td>
+              )}
+              {showColumn('listed') && (
+                <td className="px-2 py-1 font-mono text-xs text-secondary whitespace-nowrap">
+                  {item.listedDays != null ? `${item.listedDays}d` : '-'}
+                </td>
+              )}
+              {showColumn('status') && (
+                <td className="px-2 py-1">
+                  <div className="flex items-center gap-1.5 whitespace-nowrap">
+                    <StatusDot status={item.currentStatus} size="sm" />
+                    <span className={STATUS[item.currentStatus].text}>
+                      {STATUS[item.currentStatus].label}
+                    </span>
                   </div>
-                ) : (
-                  <span className="text-muted text-xs">-</span>
-                )}
-              </td>
-              <td className="pl-2 pr-4 py-1.5 align-middle">
-                <div className="flex items-center gap-[2px] h-5 w-full overflow-hidden rounded-sm">
-                  {/* 热力图：多层 vs 单层 */}
-                  {item.isMultiModel && item.layers ? (
-                    // Phase B: 多层垂直堆叠热力图
-                    item.history.map((_, idx) => (
-                      <LayeredHeatmapBlock
-                        key={idx}
-                        layers={item.layers!}
-                        timeIndex={idx}
-                        width={`${100 / item.history.length}%`}
-                        height="h-full"
-                        onHover={onBlockHover}
-                        onLeave={onBlockLeave}
-                        isMobile={false}
-                        slowLatencyMs={item.slowLatencyMs ?? slowLatencyMs}
-                      />
-                    ))
+                </td>
+              )}
+              {showColumn('uptime') && (
+                <td className="px-2 py-1 font-mono font-bold whitespace-nowrap">
+                  <span style={{ color: availabilityToColor(item.uptime) }}>
+                    {item.uptime >= 0 ? `${item.uptime}%` : '--'}
+                  </span>
+                </td>
+              )}
+              {showColumn('latency') && (
+                <td className="px-2 py-1">
+                  {item.lastCheckTimestamp ? (
+                    <div className="text-xs text-secondary font-mono flex flex-col gap-0.5">
+                      <span>{new Date(item.lastCheckTimestamp * 1000).toLocaleString(i18n.language, { month: '2-digit', day: '2-digit', hour: '2-digit', minute: '2-digit' })}</span>
+                      {item.lastCheckLatency !== undefined && (
+                        <span
+                          className="text-[10px] font-mono"
+                          style={{ color: item.currentStatus === 'UNAVAILABLE' ? 'hsl(var(--text-muted))' : latencyToColor(item.lastCheckLatency, item.slowLatencyMs ?? slowLatencyMs) }}
+                        >
+                          {item.lastCheckLatency}ms
+                        </span>
+                      )}
+                    </div>
                   ) : (
-                    // Phase A: 单层传统热力图
-                    item.history.map((point, idx) => (
-                      <HeatmapBlock
-                        key={idx}
-                        point={point}
-                        width={`${100 / item.history.length}%`}
-                        height="h-full"
-                        onHover={onBlockHover}
-                        onLeave={onBlockLeave}
-                        isMobile={false}
-                      />
-                    ))
+                    <span className="text-muted text-xs">-</span>
                   )}
-                </div>
-              </td>
+                </td>
+              )}
+              {showColumn('trend') && (
+                <td className="pl-2 pr-4 py-1.5 align-middle">
+                  <div className="flex items-center gap-[2px] h-5 w-full overflow-hidden rounded-sm">
+                    {/* 热力图：多层 vs 单层 */}
+                    {item.isMultiModel && item.layers ? (
+                      // Phase B: 多层垂直堆叠热力图
+                      item.history.map((_, idx) => (
+                        <LayeredHeatmapBlock
+                          key={idx}
+                          layers={item.layers!}
+                          timeIndex={idx}
+                          width={`${100 / item.history.length}%`}
+                          height="h-full"
+                          onHover={onBlockHover}
+                          onLeave={onBlockLeave}
+                          isMobile={false}
+                          slowLatencyMs={item.slowLatencyMs ?? slowLatencyMs}
+                        />
+                      ))
+                    ) : (
+                      // Phase A: 单层传统热力图
+                      item.history.map((point, idx) => (
+                        <HeatmapBlock
+                          key={idx}
+                          point={point}
+                          width={`${100 / item.history.length}%`}
+                          height="h-full"
+                          onHover={onBlockHover}
+                          onLeave={onBlockLeave}
+                          isMobile={false}
+                        />
+                      ))
+                    )}
+                  </div>
+                </td>
+              )}
             </tr>
             );
           })}
@@ -6,6 +6,11 @@ export interface TimePoint {
   latency: number;      // 平均延迟(ms)
   availability: number; // 可用率百分比(0-100)，缺失时为 -1
   status_counts?: StatusCounts; // 各状态计数（可选，向后兼容）
+  // 连接阶段平均耗时(ms)，仅统计发生该阶段的探测；无数据时省略
+  dns_ms?: number;
+  connect_ms?: number;
+  tls_ms?: number;
+  ttfb_ms?: number;   // 发起请求到收到响应首字节
 }
 
 export interface StatusCounts {
@@ -0,0 +1,47 @@
+package adminauth
+
+import (
+	"fmt"
+	"time"
+
+	"monitor/internal/storage"
+)
+
+// Bootstrap 数据库中没有任何用户时创建首个管理员，返回是否创建
+// username 为空（未设置 ADMIN_BOOTSTRAP_USERNAME）或存储不支持账号时直接返回
+func Bootstrap(store storage.Storage, username, password string, now time.Time) (bool, error) {
+	us, ok := store.(storage.AdminUserStorage)
+	if !ok || username == "" {
+		return false, nil
+	}
+
+	users, err := us.ListAdminUsers()
+	if err != nil {
+		return false, err
+	}
+	if len(users) > 0 {
+		return false, nil
+	}
+
+	if err := ValidateUsername(username); err != nil {
+		return false, fmt.Errorf("ADMIN_BOOTSTRAP_USERNAME: %w", err)
+	}
+	if err := ValidatePassword(password); err != nil {
+		return false, fmt.Errorf("ADMIN_BOOTSTRAP_PASSWORD: %w", err)
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return false, err
+	}
+	user := &storage.AdminUser{
+		Username:     username,
+		PasswordHash: hash,
+		Role:         string(RoleAdmin),
+		CreatedAt:    now.Unix(),
+		UpdatedAt:    now.Unix(),
+	}
+	if err := us.CreateAdminUser(user); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,117 @@
+package adminauth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// argon2id 参数（OWASP 推荐的最低配置之一：64 MiB 内存、3 次迭代）
+const (
+	argonTime    = 3
+	argonMemory  = 64 * 1024
+	argonThreads = 2
+	argonKeyLen  = 32
+	argonSaltLen = 16
+)
+
+// MaxConcurrentHashes 同时进行的 argon2id 计算上限（每次约占 64 MiB 内存，超出的调用排队等待）
+const MaxConcurrentHashes = 4
+
+// hashSlots 限制并发哈希计算的信号量
+var hashSlots = make(chan struct{}, MaxConcurrentHashes)
+
+// argonIDKey 在信号量保护下计算 argon2id，避免并发登录请求耗尽内存
+func argonIDKey(password, salt []byte, iterations, memory uint32, threads uint8, keyLen uint32) []byte {
+	hashSlots <- struct{}{}
+	defer func() { <-hashSlots }()
+	return argon2.IDKey(password, salt, iterations, memory, threads, keyLen)
+}
+
+// 密码长度限制（上限防止超长输入放大哈希开销）
+const (
+	MinPasswordLen = 10
+	MaxPasswordLen = 128
+)
+
+// usernamePattern 用户名格式
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+// ValidateUsername 校验用户名格式
+func ValidateUsername(username string) error {
+	if !usernamePattern.MatchString(username) {
+		return fmt.Errorf("用户名格式无效: '%s'（字母、数字、_ . -，最长 64 位）", username)
+	}
+	return nil
+}
+
+// ValidatePassword 校验密码长度
+func ValidatePassword(password string) error {
+	n := utf8.RuneCountInString(password)
+	if n < MinPasswordLen || n > MaxPasswordLen {
+		return fmt.Errorf("密码长度需在 %d-%d 个字符之间", MinPasswordLen, MaxPasswordLen)
+	}
+	return nil
+}
+
+// HashPassword 计算密码的 argon2id 哈希，返回 PHC 字符串格式：
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>（base64 无填充）
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, argonSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("生成盐失败: %w", err)
+	}
+	key := argonIDKey([]byte(password), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, argonMemory, argonTime, argonThreads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// VerifyPassword 校验密码是否与哈希匹配（按哈希中记录的参数重新计算，兼容日后调整参数）
+func VerifyPassword(encoded, password string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false, errors.New("不支持的密码哈希格式")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, fmt.Errorf("不支持的 argon2 版本: %s", parts[2])
+	}
+	var memory, iterations uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &threads); err != nil {
+		return false, fmt.Errorf("解析 argon2 参数失败: %w", err)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, fmt.Errorf("解析盐失败: %w", err)
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, fmt.Errorf("解析哈希失败: %w", err)
+	}
+
+	got := argonIDKey([]byte(password), salt, iterations, memory, threads, uint32(len(want)))
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
+
+// dummyHash 用户不存在时参与校验的哈希，使登录失败的耗时与密码错误一致，避免枚举用户名
+var dummyHash = sync.OnceValue(func() string {
+	hash, _ := HashPassword("relay-pulse-dummy-password")
+	return hash
+})
+
+// VerifyDummy 对不存在的用户执行一次等价的哈希计算（结果恒为失败）
+func VerifyDummy(password string) {
+	_, _ = VerifyPassword(dummyHash(), password)
+}
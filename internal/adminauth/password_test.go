@@ -0,0 +1,83 @@
+package adminauth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashAndVerifyPassword(t *testing.T) {
+	t.Parallel()
+
+	hash, err := HashPassword("correct-horse")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$") {
+		t.Fatalf("哈希格式不符合预期: %s", hash)
+	}
+	if ok, err := VerifyPassword(hash, "correct-horse"); err != nil || !ok {
+		t.Fatalf("正确密码应校验通过: ok=%v err=%v", ok, err)
+	}
+	if ok, err := VerifyPassword(hash, "wrong-horse!"); err != nil || ok {
+		t.Fatalf("错误密码应校验失败: ok=%v err=%v", ok, err)
+	}
+	if _, err := VerifyPassword("plain", "correct-horse"); err == nil {
+		t.Fatal("无效的哈希格式应报错")
+	}
+
+	other, err := HashPassword("correct-horse")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	if other == hash {
+		t.Fatal("相同密码的哈希应使用不同的盐")
+	}
+}
+
+func TestValidateCredentials(t *testing.T) {
+	t.Parallel()
+
+	if err := ValidateUsername("ops.team-1"); err != nil {
+		t.Fatalf("合法用户名被拒绝: %v", err)
+	}
+	for _, name := range []string{"", "-ops", "ops team", strings.Repeat("a", 65)} {
+		if ValidateUsername(name) == nil {
+			t.Fatalf("非法用户名应被拒绝: %q", name)
+		}
+	}
+	if ValidatePassword("short") == nil {
+		t.Fatal("过短的密码应被拒绝")
+	}
+	if ValidatePassword(strings.Repeat("x", MaxPasswordLen+1)) == nil {
+		t.Fatal("过长的密码应被拒绝")
+	}
+}
+
+func TestRoleAllows(t *testing.T) {
+	t.Parallel()
+
+	r, err := ParseRole(" Operator ")
+	if err != nil || r != RoleOperator {
+		t.Fatalf("ParseRole = %q, %v", r, err)
+	}
+	if _, err := ParseRole("root"); err == nil {
+		t.Fatal("未知角色应报错")
+	}
+
+	cases := []struct {
+		role, required Role
+		want           bool
+	}{
+		{RoleViewer, RoleViewer, true},
+		{RoleViewer, RoleOperator, false},
+		{RoleOperator, RoleViewer, true},
+		{RoleOperator, RoleAdmin, false},
+		{RoleAdmin, RoleOperator, true},
+		{Role("root"), RoleViewer, false},
+	}
+	for _, tc := range cases {
+		if got := tc.role.Allows(tc.required); got != tc.want {
+			t.Fatalf("%s.Allows(%s) = %v, want %v", tc.role, tc.required, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Package adminauth 提供管理后台本地账号的角色、密码哈希与首个管理员初始化
+package adminauth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Role 管理后台角色（权限逐级包含：viewer < operator < admin）
+type Role string
+
+const (
+	// RoleViewer 只读：查看调度任务、覆盖、标注、申请与死信等运行状态
+	RoleViewer Role = "viewer"
+	// RoleOperator 运维：在 viewer 基础上可修改运行时覆盖、标注，审核元数据申请，查看探测调试快照
+	RoleOperator Role = "operator"
+	// RoleAdmin 管理员：在 operator 基础上可管理用户、服务商令牌并查看审计日志
+	RoleAdmin Role = "admin"
+)
+
+// roleLevels 角色权限等级
+var roleLevels = map[Role]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// ParseRole 解析角色名称（忽略大小写与首尾空格）
+func ParseRole(s string) (Role, error) {
+	r := Role(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := roleLevels[r]; !ok {
+		return "", fmt.Errorf("无效的角色: %s（支持 viewer/operator/admin）", s)
+	}
+	return r, nil
+}
+
+// Allows 判断当前角色是否满足 required 的权限要求
+func (r Role) Allows(required Role) bool {
+	level, ok := roleLevels[r]
+	return ok && level >= roleLevels[required]
+}
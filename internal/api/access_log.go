@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+
+	"monitor/internal/logger"
+)
+
+// requestIDHeader 请求 ID 的请求/响应头
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen 接受的上游请求 ID 最大长度（超出或包含非法字符时重新生成）
+const maxRequestIDLen = 64
+
+// cacheStatus 响应缓存的命中情况（记录在访问日志的 cache 字段）
+type cacheStatus string
+
+const (
+	cacheStatusHit    cacheStatus = "hit"    // 命中未过期的缓存
+	cacheStatusStale  cacheStatus = "stale"  // 命中过期缓存（后台刷新）
+	cacheStatusMiss   cacheStatus = "miss"   // 未命中，执行查询
+	cacheStatusError  cacheStatus = "error"  // 命中缓存的查询错误（negative caching）
+	cacheStatusBypass cacheStatus = "bypass" // 端点关闭缓存或服务商配置了 bypass
+)
+
+// accessRecordKey 访问日志附加信息的 context key
+type accessRecordKey struct{}
+
+// accessRecord 单个请求的访问日志附加信息（由处理器在请求处理过程中填充）
+type accessRecord struct {
+	cache atomic.Value // cacheStatus
+}
+
+// recordCacheStatus 记录本次请求的缓存命中情况（ctx 不属于 API 请求时忽略）
+// 同一请求多次查询缓存时保留最后一次的结果
+func recordCacheStatus(ctx context.Context, status cacheStatus) {
+	if rec, ok := ctx.Value(accessRecordKey{}).(*accessRecord); ok {
+		rec.cache.Store(status)
+	}
+}
+
+// requestIDMiddleware 为每个请求分配 request_id，便于关联处理器与存储层日志
+// 沿用上游（反向代理、调用方）传入的 X-Request-ID，缺失或格式非法时生成短 UUID
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.New().String()[:8] // 使用短 UUID
+		}
+		c.Set("request_id", requestID)
+		c.Header(requestIDHeader, requestID)
+
+		// 将 request_id 注入到 context 供下游使用
+		ctx := logger.WithRequestID(c.Request.Context(), requestID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// validRequestID 上游请求 ID 仅接受字母、数字与 -_.:，防止日志注入与超长字段
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.' || r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// accessLogMiddleware 以结构化日志记录每个请求（组件 access）
+// 需注册在 requestIDMiddleware 与追踪中间件之后，以便日志带上 request_id 与 trace_id；
+// 可通过 logging.components.access 调整级别（如 warn 只保留 5xx）
+func accessLogMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		// 记录最外层的 ResponseWriter：压缩中间件会替换 c.Writer，这里统计实际发送的字节数
+		w := c.Writer
+		rec := &accessRecord{}
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), accessRecordKey{}, rec))
+
+		c.Next()
+
+		status := w.Status()
+		ctx := c.Request.Context()
+		args := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", status,
+			"bytes", max(w.Size(), 0),
+			"latency", time.Since(start).Round(time.Microsecond),
+			"client_ip", c.ClientIP(),
+		}
+		if route := c.FullPath(); route != "" {
+			args = append(args, "route", route)
+		}
+		if cache, ok := rec.cache.Load().(cacheStatus); ok {
+			args = append(args, "cache", string(cache))
+		}
+		if sc := trace.SpanFromContext(ctx).SpanContext(); sc.IsValid() {
+			args = append(args, "trace_id", sc.TraceID().String())
+		}
+		if len(c.Errors) > 0 {
+			args = append(args, "error", c.Errors.String())
+		}
+
+		logger.FromContext(ctx, "access").Log(ctx, accessLogLevel(c.Request.URL.Path, status), "HTTP 请求", args...)
+	}
+}
+
+// accessLogLevel 访问日志级别：5xx 为 ERROR，健康检查与静态资源的成功请求降为 DEBUG，其余为 INFO
+func accessLogLevel(path string, status int) slog.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return slog.LevelError
+	case status < http.StatusBadRequest && (path == "/health" || strings.HasPrefix(path, "/assets/")):
+		return slog.LevelDebug
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/logger"
+)
+
+// captureLogs 将日志以 JSON 格式重定向到缓冲区，测试结束后恢复默认输出
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	if err := logger.Configure(logger.Options{Format: logger.FormatJSON, Level: slog.LevelDebug}); err != nil {
+		t.Fatalf("Configure() failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = logger.Configure(logger.Options{Format: logger.FormatConsole, Level: slog.LevelInfo})
+		logger.SetOutput(os.Stdout)
+	})
+	return &buf
+}
+
+// logLines 解析缓冲区中指定组件的日志
+func logLines(t *testing.T, buf *bytes.Buffer, component string) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		if m["component"] == component {
+			lines = append(lines, m)
+		}
+	}
+	return lines
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(requestIDMiddleware())
+	r.GET("/x", func(c *gin.Context) {
+		reqID, _ := c.Request.Context().Value(logger.RequestIDKey).(string)
+		c.String(http.StatusOK, reqID)
+	})
+
+	cases := []struct {
+		name     string
+		header   string
+		preserve bool
+	}{
+		{"missing", "", false},
+		{"upstream", "req-123_abc.def:1", true},
+		{"invalid chars", "bad id\nlevel=ERROR", false},
+		{"too long", strings.Repeat("a", maxRequestIDLen+1), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/x", nil)
+			if tc.header != "" {
+				req.Header.Set(requestIDHeader, tc.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			got := w.Header().Get(requestIDHeader)
+			if got == "" || w.Body.String() != got {
+				t.Fatalf("request id not propagated: header=%q context=%q", got, w.Body.String())
+			}
+			if tc.preserve && got != tc.header {
+				t.Fatalf("expected upstream id %q, got %q", tc.header, got)
+			}
+			if !tc.preserve && (got == tc.header || len(got) != 8) {
+				t.Fatalf("expected generated id, got %q", got)
+			}
+		})
+	}
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	buf := captureLogs(t)
+	gin.SetMode(gin.TestMode)
+
+	cache := newStatusCache(time.Minute, 10)
+	r := gin.New()
+	r.Use(requestIDMiddleware(), accessLogMiddleware())
+	r.GET("/api/items/:id", func(c *gin.Context) {
+		entry, err := cache.loadEntry(c.Request.Context(), "k", time.Minute, func() ([]byte, error) {
+			logger.FromContext(c.Request.Context(), "storage").Info("查询数据")
+			return []byte(`{"ok":true}`), nil
+		})
+		if err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		writeCached(c, entry, "application/json; charset=utf-8")
+	})
+	r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/boom", func(c *gin.Context) { c.Status(http.StatusBadGateway) })
+
+	for _, path := range []string{"/api/items/1", "/api/items/1", "/health", "/boom"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(requestIDHeader, "trace-me")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// 处理器内的日志与访问日志带有同一 request_id
+	storageLines := logLines(t, buf, "storage")
+	if len(storageLines) != 1 || storageLines[0]["request_id"] != "trace-me" {
+		t.Fatalf("handler log missing request_id: %v", storageLines)
+	}
+
+	lines := logLines(t, buf, "access")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 access log lines, got %d: %s", len(lines), buf.String())
+	}
+	first := lines[0]
+	if first["method"] != "GET" || first["path"] != "/api/items/1" || first["route"] != "/api/items/:id" ||
+		first["status"] != float64(200) || first["bytes"] != float64(len(`{"ok":true}`)) ||
+		first["request_id"] != "trace-me" || first["level"] != "INFO" {
+		t.Fatalf("unexpected access log: %v", first)
+	}
+	if _, ok := first["latency"]; !ok {
+		t.Fatalf("latency missing: %v", first)
+	}
+	if first["cache"] != "miss" || lines[1]["cache"] != "hit" {
+		t.Fatalf("unexpected cache status: %v / %v", first["cache"], lines[1]["cache"])
+	}
+	if _, ok := lines[2]["cache"]; ok || lines[2]["level"] != "DEBUG" {
+		t.Fatalf("unexpected health access log: %v", lines[2])
+	}
+	if lines[3]["status"] != float64(http.StatusBadGateway) || lines[3]["level"] != "ERROR" {
+		t.Fatalf("unexpected 5xx access log: %v", lines[3])
+	}
+}
@@ -0,0 +1,595 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/adminauth"
+	"monitor/internal/audit"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+const (
+	// adminSessionCookie 管理后台会话 Cookie 名称（仅发送到 /api/admin）
+	adminSessionCookie = "relay_pulse_admin_session"
+
+	// adminSessionPrefix 会话令牌明文前缀（与 ADMIN_API_TOKEN 区分）
+	adminSessionPrefix = "rps_"
+
+	// adminPrincipalKey gin.Context 中保存已认证身份的键
+	adminPrincipalKey = "admin_principal"
+
+	// defaultAdminSessionTTL 未规范化配置时的会话有效期
+	defaultAdminSessionTTL = 12 * time.Hour
+)
+
+// adminPrincipal 已通过管理 API 鉴权的身份
+type adminPrincipal struct {
+	Actor       string         // 审计操作者：admin_token 或 user:<username>
+	Role        adminauth.Role // 角色（ADMIN_API_TOKEN 视为 admin）
+	UserID      int64          // 本地用户 ID（ADMIN_API_TOKEN 为 0）
+	Username    string
+	SessionHash string // 当前会话令牌哈希（登出时删除）
+}
+
+// AdminUserItem 管理后台用户（不含密码哈希）
+type AdminUserItem struct {
+	ID        int64  `json:"id"`
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+	Disabled  bool   `json:"disabled"`
+}
+
+// AdminLoginRequest 登录请求
+type AdminLoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// AdminLoginResponse 登录响应（令牌同时写入 HttpOnly Cookie，非浏览器客户端可用 Bearer 方式携带）
+type AdminLoginResponse struct {
+	Token     string        `json:"token"`
+	ExpiresAt int64         `json:"expires_at"`
+	User      AdminUserItem `json:"user"`
+}
+
+// CreateAdminUserRequest 创建用户请求
+type CreateAdminUserRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+// UpdateAdminUserRequest 更新用户请求（字段均可选）
+type UpdateAdminUserRequest struct {
+	Password *string `json:"password"`
+	Role     *string `json:"role"`
+	Disabled *bool   `json:"disabled"`
+}
+
+// authenticateAdmin 校验管理 API 凭据：ADMIN_API_TOKEN（Bearer）或登录会话（Cookie / Bearer）
+// 失败时返回 nil 及对应的状态码与错误信息
+func (h *Handler) authenticateAdmin(c *gin.Context) (*adminPrincipal, int, string) {
+	h.cfgMu.RLock()
+	apiToken := h.config.Audit.APIToken
+	h.cfgMu.RUnlock()
+
+	ctx := c.Request.Context()
+	us, _ := h.storage.WithContext(ctx).(storage.AdminUserStorage)
+
+	var token string
+	if authHeader := c.GetHeader("Authorization"); authHeader != "" {
+		const bearerPrefix = "Bearer "
+		if !strings.HasPrefix(authHeader, bearerPrefix) {
+			return nil, http.StatusUnauthorized, "Authorization 格式错误，应为: Bearer <token>"
+		}
+		token = strings.TrimPrefix(authHeader, bearerPrefix)
+	} else if cookie, err := c.Cookie(adminSessionCookie); err == nil {
+		token = cookie
+	}
+
+	if token == "" {
+		if apiToken == "" && !h.hasAdminUsers(c, us) {
+			return nil, http.StatusServiceUnavailable, "admin API 未配置，请设置 ADMIN_API_TOKEN 环境变量或创建管理员账号"
+		}
+		return nil, http.StatusUnauthorized, "缺少 Authorization 请求头或登录会话"
+	}
+
+	// 使用恒定时间比较，防止时序攻击
+	if apiToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(apiToken)) == 1 {
+		return &adminPrincipal{Actor: adminActor, Role: adminauth.RoleAdmin}, 0, ""
+	}
+	if !strings.HasPrefix(token, adminSessionPrefix) || us == nil {
+		return nil, http.StatusForbidden, "令牌无效"
+	}
+
+	hash := hashProviderToken(token)
+	sess, err := us.GetAdminSessionByHash(hash)
+	if err != nil {
+		logger.FromContext(ctx, "api").Error("查询管理后台会话失败", "error", err)
+		return nil, http.StatusInternalServerError, "会话校验失败"
+	}
+	if sess == nil || sess.ExpiresAt <= time.Now().Unix() {
+		return nil, http.StatusUnauthorized, "登录会话无效或已过期"
+	}
+	user, err := us.GetAdminUser(sess.UserID)
+	if err != nil {
+		logger.FromContext(ctx, "api").Error("查询管理后台用户失败", "user_id", sess.UserID, "error", err)
+		return nil, http.StatusInternalServerError, "会话校验失败"
+	}
+	if user == nil || user.DisabledAt != 0 {
+		return nil, http.StatusUnauthorized, "账号不存在或已停用"
+	}
+	role, err := adminauth.ParseRole(user.Role)
+	if err != nil {
+		return nil, http.StatusForbidden, err.Error()
+	}
+	return &adminPrincipal{
+		Actor:       "user:" + user.Username,
+		Role:        role,
+		UserID:      user.ID,
+		Username:    user.Username,
+		SessionHash: hash,
+	}, 0, ""
+}
+
+// hasAdminUsers 判断是否已创建管理后台用户（仅在未携带凭据时用于区分"未配置"与"未登录"）
+func (h *Handler) hasAdminUsers(c *gin.Context, us storage.AdminUserStorage) bool {
+	if us == nil {
+		return false
+	}
+	users, err := us.ListAdminUsers()
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Warn("查询管理后台用户失败", "error", err)
+		return false
+	}
+	return len(users) > 0
+}
+
+// requireAdminRole 按端点要求的最低角色鉴权（需位于 adminAuth 之后）
+func requireAdminRole(required adminauth.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := adminPrincipalOf(c)
+		if p == nil {
+			// 未经过 adminAuth（路由遗漏或中间件顺序错误）时拒绝，而不是放行
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
+			return
+		}
+		if !p.Role.Allows(required) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": fmt.Sprintf("权限不足：需要 %s 及以上角色", required),
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// adminPrincipalOf 返回当前请求的已认证身份（未经过 adminAuth 时为 nil）
+func adminPrincipalOf(c *gin.Context) *adminPrincipal {
+	v, ok := c.Get(adminPrincipalKey)
+	if !ok {
+		return nil
+	}
+	p, _ := v.(*adminPrincipal)
+	return p
+}
+
+// adminActorOf 返回当前请求的审计操作者（未经过 adminAuth 时视为管理 API 令牌）
+func adminActorOf(c *gin.Context) string {
+	if p := adminPrincipalOf(c); p != nil {
+		return p.Actor
+	}
+	return adminActor
+}
+
+// PostAdminLogin 本地账号登录，签发会话令牌（HttpOnly Cookie + 响应体）
+// POST /api/admin/login  {"username": "xxx", "password": "xxx"}
+// 按来源 IP 与用户名分别限流，超出返回 429 与 Retry-After
+func (h *Handler) PostAdminLogin(c *gin.Context) {
+	ctx := c.Request.Context()
+	us, ok := h.storage.WithContext(ctx).(storage.AdminUserStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "当前存储后端不支持管理后台账号"})
+		return
+	}
+
+	h.cfgMu.RLock()
+	perIP := h.config.AdminAuth.LoginRateLimitPerIP
+	perUser := h.config.AdminAuth.LoginRateLimitPerUser
+	h.cfgMu.RUnlock()
+
+	if !h.allowAdminLogin(c, "ip:"+c.ClientIP(), perIP) {
+		return
+	}
+
+	var req AdminLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("无效的 JSON: %v", err)})
+		return
+	}
+	username := strings.TrimSpace(req.Username)
+	if username == "" || req.Password == "" || len(req.Password) > adminauth.MaxPasswordLen*4 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "用户名和密码不能为空"})
+		return
+	}
+	if !h.allowAdminLogin(c, "user:"+strings.ToLower(username), perUser) {
+		return
+	}
+
+	user, err := us.GetAdminUserByUsername(username)
+	if err != nil {
+		logger.FromContext(ctx, "api").Error("查询管理后台用户失败", "username", username, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "登录失败"})
+		return
+	}
+	var matched bool
+	if user == nil {
+		adminauth.VerifyDummy(req.Password)
+	} else if matched, err = adminauth.VerifyPassword(user.PasswordHash, req.Password); err != nil {
+		logger.FromContext(ctx, "api").Error("校验管理后台密码失败", "username", username, "error", err)
+	}
+	if !matched || user.DisabledAt != 0 {
+		h.recordAdminLogin(c, username, audit.ResultFailure)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户名或密码错误，或账号已停用"})
+		return
+	}
+
+	h.cfgMu.RLock()
+	ttl := h.config.AdminAuth.SessionTTLDuration
+	h.cfgMu.RUnlock()
+	if ttl <= 0 {
+		ttl = defaultAdminSessionTTL
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成会话失败"})
+		return
+	}
+	token := adminSessionPrefix + hex.EncodeToString(buf)
+	now := time.Now()
+	sess := &storage.AdminSession{
+		UserID:    user.ID,
+		TokenHash: hashProviderToken(token),
+		CreatedAt: now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+		IP:        c.ClientIP(),
+	}
+	if err := us.CreateAdminSession(sess); err != nil {
+		logger.FromContext(ctx, "api").Error("保存管理后台会话失败", "username", username, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "登录失败"})
+		return
+	}
+	// 顺带清理过期会话，清理失败不影响登录
+	if _, err := us.DeleteExpiredAdminSessions(now.Unix()); err != nil {
+		logger.FromContext(ctx, "api").Warn("清理过期管理后台会话失败", "error", err)
+	}
+
+	h.recordAdminLogin(c, username, audit.ResultSuccess)
+	setAdminSessionCookie(c, token, int(ttl.Seconds()))
+	c.JSON(http.StatusOK, AdminLoginResponse{
+		Token:     token,
+		ExpiresAt: sess.ExpiresAt,
+		User:      toAdminUserItem(user),
+	})
+}
+
+// allowAdminLogin 检查登录限流，超出时写入 429 响应
+func (h *Handler) allowAdminLogin(c *gin.Context, key string, perMinute int) bool {
+	if perMinute <= 0 {
+		return true
+	}
+	ok, wait := h.loginLimiter.allow(key, perMinute, time.Now())
+	if ok {
+		return true
+	}
+	retryAfter := int(math.Ceil(wait.Seconds()))
+	logger.FromContext(c.Request.Context(), "api").Warn("管理后台登录过于频繁", "key", key, "retry_after", retryAfter)
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": "登录尝试过于频繁，请稍后再试"})
+	return false
+}
+
+// PostAdminLogout 登出（删除当前会话并清除 Cookie）
+// POST /api/admin/logout
+func (h *Handler) PostAdminLogout(c *gin.Context) {
+	if p := adminPrincipalOf(c); p != nil && p.SessionHash != "" {
+		if us, ok := h.storage.WithContext(c.Request.Context()).(storage.AdminUserStorage); ok {
+			if err := us.DeleteAdminSession(p.SessionHash); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "登出失败"})
+				return
+			}
+		}
+	}
+	setAdminSessionCookie(c, "", -1)
+	c.JSON(http.StatusOK, gin.H{"logged_out": true})
+}
+
+// GetAdminMe 返回当前身份与角色（供管理面板决定可见的功能）
+// GET /api/admin/me
+func (h *Handler) GetAdminMe(c *gin.Context) {
+	p := adminPrincipalOf(c)
+	if p == nil {
+		p = &adminPrincipal{Actor: adminActor, Role: adminauth.RoleAdmin}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"actor":    p.Actor,
+		"username": p.Username,
+		"role":     p.Role,
+	})
+}
+
+// GetAdminUsers 查询管理后台用户列表
+// GET /api/admin/users
+func (h *Handler) GetAdminUsers(c *gin.Context) {
+	us, ok := h.storage.WithContext(c.Request.Context()).(storage.AdminUserStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "当前存储后端不支持管理后台账号"})
+		return
+	}
+
+	users, err := us.ListAdminUsers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询管理后台用户失败"})
+		return
+	}
+	items := make([]AdminUserItem, 0, len(users))
+	for _, u := range users {
+		items = append(items, toAdminUserItem(u))
+	}
+	c.JSON(http.StatusOK, gin.H{"users": items})
+}
+
+// PostAdminUser 创建管理后台用户
+// POST /api/admin/users  {"username": "xxx", "password": "xxx", "role": "viewer"}
+func (h *Handler) PostAdminUser(c *gin.Context) {
+	us, ok := h.storage.WithContext(c.Request.Context()).(storage.AdminUserStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "当前存储后端不支持管理后台账号"})
+		return
+	}
+
+	var req CreateAdminUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("无效的 JSON: %v", err)})
+		return
+	}
+	username := strings.TrimSpace(req.Username)
+	if err := adminauth.ValidateUsername(username); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := adminauth.ValidatePassword(req.Password); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	role, err := adminauth.ParseRole(req.Role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	existing, err := us.GetAdminUserByUsername(username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询管理后台用户失败"})
+		return
+	}
+	if existing != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("用户名已存在: %s", username)})
+		return
+	}
+
+	hash, err := adminauth.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "计算密码哈希失败"})
+		return
+	}
+	now := time.Now().Unix()
+	user := &storage.AdminUser{
+		Username:     username,
+		PasswordHash: hash,
+		Role:         string(role),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := us.CreateAdminUser(user); err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("创建管理后台用户失败", "username", username, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建用户失败"})
+		return
+	}
+
+	h.audit.Record(c.Request.Context(), storage.AuditEntry{
+		Actor:  adminActorOf(c),
+		Action: "admin_user.create",
+		Target: username,
+		Detail: "role=" + string(role),
+		IP:     c.ClientIP(),
+	})
+	c.JSON(http.StatusCreated, toAdminUserItem(user))
+}
+
+// PatchAdminUser 修改用户密码、角色或停用状态（修改后该用户的全部会话失效）
+// PATCH /api/admin/users/:id  {"password": "xxx", "role": "operator", "disabled": false}
+func (h *Handler) PatchAdminUser(c *gin.Context) {
+	us, ok := h.storage.WithContext(c.Request.Context()).(storage.AdminUserStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "当前存储后端不支持管理后台账号"})
+		return
+	}
+	user, ok := h.loadAdminUserParam(c, us)
+	if !ok {
+		return
+	}
+
+	var req UpdateAdminUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("无效的 JSON: %v", err)})
+		return
+	}
+	var changes []string
+	if req.Password != nil {
+		if err := adminauth.ValidatePassword(*req.Password); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		hash, err := adminauth.HashPassword(*req.Password)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "计算密码哈希失败"})
+			return
+		}
+		user.PasswordHash = hash
+		changes = append(changes, "password")
+	}
+	if req.Role != nil {
+		role, err := adminauth.ParseRole(*req.Role)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		user.Role = string(role)
+		changes = append(changes, "role="+string(role))
+	}
+	now := time.Now().Unix()
+	if req.Disabled != nil {
+		user.DisabledAt = 0
+		if *req.Disabled {
+			user.DisabledAt = now
+		}
+		changes = append(changes, fmt.Sprintf("disabled=%t", *req.Disabled))
+	}
+	if len(changes) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "未指定要修改的字段"})
+		return
+	}
+	if !h.keepsActiveAdmin(c, us, user) {
+		return
+	}
+
+	user.UpdatedAt = now
+	if updated, err := us.UpdateAdminUser(user); err != nil || !updated {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新用户失败"})
+		return
+	}
+	if err := us.DeleteAdminUserSessions(user.ID); err != nil {
+		logger.FromContext(c.Request.Context(), "api").Warn("清除用户会话失败", "username", user.Username, "error", err)
+	}
+
+	h.audit.Record(c.Request.Context(), storage.AuditEntry{
+		Actor:  adminActorOf(c),
+		Action: "admin_user.update",
+		Target: user.Username,
+		Detail: strings.Join(changes, " "),
+		IP:     c.ClientIP(),
+	})
+	c.JSON(http.StatusOK, toAdminUserItem(user))
+}
+
+// DeleteAdminUser 删除管理后台用户（同时删除其会话）
+// DELETE /api/admin/users/:id
+func (h *Handler) DeleteAdminUser(c *gin.Context) {
+	us, ok := h.storage.WithContext(c.Request.Context()).(storage.AdminUserStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "当前存储后端不支持管理后台账号"})
+		return
+	}
+	user, ok := h.loadAdminUserParam(c, us)
+	if !ok {
+		return
+	}
+	if !h.keepsActiveAdmin(c, us, &storage.AdminUser{ID: user.ID}) {
+		return
+	}
+
+	if _, err := us.DeleteAdminUser(user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除用户失败"})
+		return
+	}
+
+	h.audit.Record(c.Request.Context(), storage.AuditEntry{
+		Actor:  adminActorOf(c),
+		Action: "admin_user.delete",
+		Target: user.Username,
+		IP:     c.ClientIP(),
+	})
+	c.JSON(http.StatusOK, gin.H{"id": user.ID, "deleted": true})
+}
+
+// loadAdminUserParam 按路径参数 :id 加载用户，失败时已写入响应
+func (h *Handler) loadAdminUserParam(c *gin.Context, us storage.AdminUserStorage) (*storage.AdminUser, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的用户 ID"})
+		return nil, false
+	}
+	user, err := us.GetAdminUser(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询管理后台用户失败"})
+		return nil, false
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		return nil, false
+	}
+	return user, true
+}
+
+// keepsActiveAdmin 校验将 user 替换为 next（Role 为空表示删除）后仍至少保留一个启用的管理员，失败时已写入响应
+func (h *Handler) keepsActiveAdmin(c *gin.Context, us storage.AdminUserStorage, next *storage.AdminUser) bool {
+	users, err := us.ListAdminUsers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询管理后台用户失败"})
+		return false
+	}
+	for _, u := range users {
+		if u.ID == next.ID {
+			u = next
+		}
+		if u.Role == string(adminauth.RoleAdmin) && u.DisabledAt == 0 {
+			return true
+		}
+	}
+	c.JSON(http.StatusConflict, gin.H{"error": "至少需要保留一个启用的 admin 用户"})
+	return false
+}
+
+// recordAdminLogin 记录登录尝试（成功与失败均写入审计日志）
+func (h *Handler) recordAdminLogin(c *gin.Context, username, result string) {
+	h.audit.Record(c.Request.Context(), storage.AuditEntry{
+		Actor:  "user:" + username,
+		Action: "admin.login",
+		IP:     c.ClientIP(),
+		Result: result,
+	})
+}
+
+// setAdminSessionCookie 写入（maxAge < 0 时清除）会话 Cookie：HttpOnly、SameSite=Strict，HTTPS 请求附加 Secure
+func setAdminSessionCookie(c *gin.Context, token string, maxAge int) {
+	secure := c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https")
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(adminSessionCookie, token, maxAge, "/api/admin", "", secure, true)
+}
+
+// toAdminUserItem 转换为 API 响应结构
+func toAdminUserItem(u *storage.AdminUser) AdminUserItem {
+	return AdminUserItem{
+		ID:        u.ID,
+		Username:  u.Username,
+		Role:      u.Role,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+		Disabled:  u.DisabledAt != 0,
+	}
+}
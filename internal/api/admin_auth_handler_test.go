@@ -0,0 +1,221 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/adminauth"
+	"monitor/internal/config"
+)
+
+func TestAdminAuthRoles(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	if created, err := adminauth.Bootstrap(store, "root", "root-password", time.Now()); err != nil || !created {
+		t.Fatalf("bootstrap admin: created=%v err=%v", created, err)
+	}
+	srv := NewServer(store, &config.AppConfig{Audit: config.AuditConfig{APIToken: "admin-token"}})
+
+	serve := func(method, target, body string, auth func(*http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if auth != nil {
+			auth(req)
+		}
+		w := httptest.NewRecorder()
+		srv.router.ServeHTTP(w, req)
+		return w
+	}
+	bearer := func(token string) func(*http.Request) {
+		return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }
+	}
+	login := func(username, password string) *httptest.ResponseRecorder {
+		return serve(http.MethodPost, "/api/admin/login", `{"username":"`+username+`","password":"`+password+`"}`, nil)
+	}
+
+	if w := serve(http.MethodGet, "/api/admin/me", "", nil); w.Code != http.StatusUnauthorized {
+		t.Fatalf("missing credentials should return 401, got %d", w.Code)
+	}
+	if w := serve(http.MethodGet, "/api/admin/users", "", bearer("wrong")); w.Code != http.StatusForbidden {
+		t.Fatalf("wrong token should return 403, got %d", w.Code)
+	}
+	if w := serve(http.MethodGet, "/api/admin/users", "", bearer("admin-token")); w.Code != http.StatusOK {
+		t.Fatalf("ADMIN_API_TOKEN should keep admin access, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := login("root", "wrong-password"); w.Code != http.StatusUnauthorized {
+		t.Fatalf("wrong password should return 401, got %d", w.Code)
+	}
+	if w := login("nobody", "root-password"); w.Code != http.StatusUnauthorized {
+		t.Fatalf("unknown user should return 401, got %d", w.Code)
+	}
+
+	// 管理员登录：Cookie 与 Bearer 两种方式均可携带会话
+	w := login("root", "root-password")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected login 200, got %d: %s", w.Code, w.Body.String())
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != adminSessionCookie || !cookies[0].HttpOnly {
+		t.Fatalf("expected HttpOnly session cookie: %+v", cookies)
+	}
+	withCookie := func(r *http.Request) { r.AddCookie(cookies[0]) }
+	if w := serve(http.MethodGet, "/api/admin/me", "", withCookie); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"role":"admin"`) {
+		t.Fatalf("expected admin session: %d %s", w.Code, w.Body.String())
+	}
+
+	w = serve(http.MethodPost, "/api/admin/users", `{"username":"alice","password":"alice-password","role":"viewer"}`, withCookie)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected create 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "argon2") {
+		t.Fatal("user response must not contain password hash")
+	}
+	var alice AdminUserItem
+	if err := json.Unmarshal(w.Body.Bytes(), &alice); err != nil {
+		t.Fatalf("decode user: %v", err)
+	}
+	if w := serve(http.MethodPost, "/api/admin/users", `{"username":"alice","password":"alice-password","role":"viewer"}`, withCookie); w.Code != http.StatusConflict {
+		t.Fatalf("duplicate username should return 409, got %d", w.Code)
+	}
+
+	// viewer 只能访问只读端点
+	w = login("alice", "alice-password")
+	var resp AdminLoginResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || !strings.HasPrefix(resp.Token, adminSessionPrefix) {
+		t.Fatalf("decode login response: %v %s", err, w.Body.String())
+	}
+	if w := serve(http.MethodGet, "/api/admin/me", "", bearer(resp.Token)); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"role":"viewer"`) {
+		t.Fatalf("expected viewer session: %d %s", w.Code, w.Body.String())
+	}
+	if w := serve(http.MethodGet, "/api/admin/users", "", bearer(resp.Token)); w.Code != http.StatusForbidden {
+		t.Fatalf("viewer should not list users, got %d", w.Code)
+	}
+	if w := serve(http.MethodPost, "/api/admin/annotations", `{}`, bearer(resp.Token)); w.Code != http.StatusForbidden {
+		t.Fatalf("viewer should not create annotations, got %d", w.Code)
+	}
+
+	// 修改角色后旧会话失效
+	aliceURL := "/api/admin/users/" + strconv.FormatInt(alice.ID, 10)
+	if w := serve(http.MethodPatch, aliceURL, `{"role":"operator"}`, withCookie); w.Code != http.StatusOK {
+		t.Fatalf("expected update 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := serve(http.MethodGet, "/api/admin/me", "", bearer(resp.Token)); w.Code != http.StatusUnauthorized {
+		t.Fatalf("sessions should be revoked after update, got %d", w.Code)
+	}
+
+	// 不能停用最后一个管理员
+	rootURL := "/api/admin/users/1"
+	if w := serve(http.MethodPatch, rootURL, `{"role":"viewer"}`, withCookie); w.Code != http.StatusConflict {
+		t.Fatalf("demoting last admin should return 409, got %d", w.Code)
+	}
+	if w := serve(http.MethodDelete, rootURL, "", bearer("admin-token")); w.Code != http.StatusConflict {
+		t.Fatalf("deleting last admin should return 409, got %d", w.Code)
+	}
+	if w := serve(http.MethodDelete, aliceURL, "", withCookie); w.Code != http.StatusOK {
+		t.Fatalf("expected delete 200, got %d", w.Code)
+	}
+
+	if w := serve(http.MethodPost, "/api/admin/logout", "", withCookie); w.Code != http.StatusOK {
+		t.Fatalf("expected logout 200, got %d", w.Code)
+	}
+	if w := serve(http.MethodGet, "/api/admin/me", "", withCookie); w.Code != http.StatusUnauthorized {
+		t.Fatalf("session should be invalid after logout, got %d", w.Code)
+	}
+}
+
+func TestAdminAuthNotConfigured(t *testing.T) {
+	srv := NewServer(newTestSQLiteStore(t), &config.AppConfig{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/me", nil)
+	w := httptest.NewRecorder()
+	srv.router.ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without token or users, got %d", w.Code)
+	}
+}
+
+func TestAdminLoginRateLimit(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	if created, err := adminauth.Bootstrap(store, "root", "root-password", time.Now()); err != nil || !created {
+		t.Fatalf("bootstrap admin: created=%v err=%v", created, err)
+	}
+	srv := NewServer(store, &config.AppConfig{AdminAuth: config.AdminAuthConfig{
+		LoginRateLimitPerIP:   3,
+		LoginRateLimitPerUser: 2,
+	}})
+
+	login := func(ip, username string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/login",
+			strings.NewReader(`{"username":"`+username+`","password":"wrong-password"}`))
+		req.RemoteAddr = ip + ":12345"
+		w := httptest.NewRecorder()
+		srv.router.ServeHTTP(w, req)
+		return w
+	}
+	expect429 := func(w *httptest.ResponseRecorder, what string) {
+		t.Helper()
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("%s: expected 429, got %d: %s", what, w.Code, w.Body.String())
+		}
+		if secs, err := strconv.Atoi(w.Header().Get("Retry-After")); err != nil || secs < 1 {
+			t.Fatalf("%s: expected positive Retry-After, got %q", what, w.Header().Get("Retry-After"))
+		}
+	}
+
+	// 按用户名：同一用户名换 IP 也受限
+	for i := 0; i < 2; i++ {
+		if w := login("198.51.100.1", "root"); w.Code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: expected 401, got %d", i+1, w.Code)
+		}
+	}
+	expect429(login("198.51.100.2", "root"), "per-user limit")
+
+	// 按 IP：同一 IP 换用户名也受限（198.51.100.1 已用 2 次）
+	if w := login("198.51.100.1", "alice"); w.Code != http.StatusUnauthorized {
+		t.Fatalf("third attempt from ip: expected 401, got %d", w.Code)
+	}
+	expect429(login("198.51.100.1", "bob"), "per-ip limit")
+}
+
+func TestRequireAdminRoleWithoutPrincipal(t *testing.T) {
+	router := gin.New()
+	router.GET("/unguarded", requireAdminRole(adminauth.RoleViewer), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// 未经过 adminAuth 的路由不能因缺少身份而放行
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/unguarded", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without principal, got %d", w.Code)
+	}
+}
+
+func TestLoginLimiter(t *testing.T) {
+	l := newLoginLimiter()
+	now := time.Unix(1000, 0)
+
+	for i := 0; i < 6; i++ {
+		if ok, _ := l.allow("k", 6, now); !ok {
+			t.Fatalf("attempt %d within burst should pass", i+1)
+		}
+	}
+	ok, wait := l.allow("k", 6, now)
+	if ok || wait <= 0 || wait > 10*time.Second {
+		t.Fatalf("expected rejection with ~10s wait, got ok=%v wait=%v", ok, wait)
+	}
+	if ok, _ := l.allow("k", 6, now.Add(wait)); !ok {
+		t.Fatal("attempt after wait should pass")
+	}
+
+	// 空闲条目被回收
+	l.allow("other", 6, now.Add(loginLimiterTTL+2*time.Minute))
+	if _, exists := l.entries["k"]; exists {
+		t.Fatal("idle entry should be swept")
+	}
+}
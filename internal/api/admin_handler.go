@@ -0,0 +1,384 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/audit"
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+// adminActor 通过管理 API 令牌操作的审计操作者
+const adminActor = "admin_token"
+
+// AuditResponse 审计日志列表响应
+type AuditResponse struct {
+	Entries []AuditItem `json:"entries"`
+	Meta    AuditMeta   `json:"meta"`
+}
+
+// AuditItem 单条审计日志
+type AuditItem struct {
+	ID        int64  `json:"id"`
+	Timestamp int64  `json:"timestamp"`
+	Actor     string `json:"actor"`
+	Action    string `json:"action"`
+	Target    string `json:"target,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+	IP        string `json:"ip,omitempty"`
+	Result    string `json:"result"`
+}
+
+// AuditMeta 分页列表元数据（审计日志、探测调试快照、Webhook 死信共用）
+type AuditMeta struct {
+	NextBeforeID int64 `json:"next_before_id"` // 下一页游标（传给 before_id），无更多数据时为 0
+	HasMore      bool  `json:"has_more"`
+	Count        int   `json:"count"`
+}
+
+// SetAuditRecorder 设置审计日志记录器（可选）
+func (h *Handler) SetAuditRecorder(r *audit.Recorder) {
+	h.audit = r
+}
+
+// adminAuth 管理 API 鉴权中间件
+// 支持 ADMIN_API_TOKEN（视为 admin 角色）与本地账号登录会话；各端点所需角色由 requireAdminRole 校验
+// 所有 /api/admin/* 请求（含鉴权失败）都会写入审计日志
+func (h *Handler) adminAuth(c *gin.Context) {
+	principal, status, msg := h.authenticateAdmin(c)
+	if principal == nil {
+		c.AbortWithStatusJSON(status, gin.H{"error": msg})
+		h.recordAdminCall(c, "anonymous")
+		return
+	}
+
+	c.Set(adminPrincipalKey, principal)
+	c.Next()
+	h.recordAdminCall(c, principal.Actor)
+}
+
+// recordAdminCall 记录一次管理 API 调用
+func (h *Handler) recordAdminCall(c *gin.Context, actor string) {
+	result := audit.ResultSuccess
+	if c.Writer.Status() >= http.StatusBadRequest {
+		result = audit.ResultFailure
+	}
+	h.audit.Record(c.Request.Context(), storage.AuditEntry{
+		Actor:  actor,
+		Action: "admin.api",
+		Target: c.Request.Method + " " + c.Request.URL.Path,
+		Detail: c.Request.URL.RawQuery,
+		IP:     c.ClientIP(),
+		Result: result,
+	})
+}
+
+// GetAdminAudit 查询审计日志
+// GET /api/admin/audit?actor=xxx&action=config.&since=0&until=0&before_id=0&limit=100
+// action 以 "." 结尾时按前缀匹配；按 id 倒序返回，limit 默认 100，最大 200
+func (h *Handler) GetAdminAudit(c *gin.Context) {
+	as, ok := h.storage.WithContext(c.Request.Context()).(storage.AuditStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持审计日志",
+		})
+		return
+	}
+
+	filter := storage.AuditFilter{
+		Actor:  strings.TrimSpace(c.Query("actor")),
+		Action: strings.TrimSpace(c.Query("action")),
+	}
+	filter.Since, _ = strconv.ParseInt(c.Query("since"), 10, 64)
+	filter.Until, _ = strconv.ParseInt(c.Query("until"), 10, 64)
+	filter.BeforeID, _ = strconv.ParseInt(c.Query("before_id"), 10, 64)
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 200 {
+		limit = 200
+	}
+	filter.Limit = limit + 1
+
+	entries, err := as.GetAuditEntries(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "查询审计日志失败",
+		})
+		return
+	}
+
+	// 判断是否还有更多
+	hasMore := len(entries) > limit
+	if hasMore {
+		entries = entries[:limit]
+	}
+
+	items := make([]AuditItem, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, AuditItem{
+			ID:        e.ID,
+			Timestamp: e.Timestamp,
+			Actor:     e.Actor,
+			Action:    e.Action,
+			Target:    e.Target,
+			Detail:    e.Detail,
+			IP:        e.IP,
+			Result:    e.Result,
+		})
+	}
+
+	var nextBeforeID int64
+	if hasMore {
+		nextBeforeID = items[len(items)-1].ID
+	}
+
+	c.JSON(http.StatusOK, AuditResponse{
+		Entries: items,
+		Meta: AuditMeta{
+			NextBeforeID: nextBeforeID,
+			HasMore:      hasMore,
+			Count:        len(items),
+		},
+	})
+}
+
+// ProbeDebugResponse 探测调试快照列表响应
+type ProbeDebugResponse struct {
+	Entries []ProbeDebugItem `json:"entries"`
+	Meta    AuditMeta        `json:"meta"`
+}
+
+// ProbeDebugItem 单条探测调试快照
+type ProbeDebugItem struct {
+	ID              int64             `json:"id"`
+	Provider        string            `json:"provider"`
+	Service         string            `json:"service"`
+	Channel         string            `json:"channel,omitempty"`
+	Model           string            `json:"model,omitempty"`
+	Timestamp       int64             `json:"timestamp"`
+	Status          int               `json:"status"`
+	SubStatus       string            `json:"sub_status,omitempty"`
+	HttpCode        int               `json:"http_code"`
+	Latency         int               `json:"latency"`
+	Attempts        int               `json:"attempts"`
+	Method          string            `json:"method"`
+	URL             string            `json:"url"`
+	RequestHeaders  map[string]string `json:"request_headers"`
+	ResponseHeaders map[string]string `json:"response_headers"`
+	ResponseBody    string            `json:"response_body"`
+	BodyTruncated   bool              `json:"body_truncated"`
+	Error           string            `json:"error,omitempty"`
+	Timings         ProbeDebugTimings `json:"timings"`
+	ExpiresAt       int64             `json:"expires_at"`
+}
+
+// ProbeDebugTimings 连接阶段耗时（毫秒，-1 表示该阶段未发生）
+type ProbeDebugTimings struct {
+	DNSMs     int `json:"dns_ms"`
+	ConnectMs int `json:"connect_ms"`
+	TLSMs     int `json:"tls_ms"`
+	TTFBMs    int `json:"ttfb_ms"`
+}
+
+// GetAdminProbeDebug 查询探测调试快照
+// GET /api/admin/probe-debug?provider=xxx&service=cc&channel=vip&model=xxx&before_id=0&limit=20
+// 按 id 倒序返回，limit 默认 20，最大 100
+func (h *Handler) GetAdminProbeDebug(c *gin.Context) {
+	ds, ok := h.storage.WithContext(c.Request.Context()).(storage.ProbeDebugStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持探测调试捕获",
+		})
+		return
+	}
+
+	filter := storage.ProbeDebugFilter{
+		Provider: strings.TrimSpace(c.Query("provider")),
+		Service:  strings.TrimSpace(c.Query("service")),
+		Channel:  strings.TrimSpace(c.Query("channel")),
+		Model:    strings.TrimSpace(c.Query("model")),
+	}
+	filter.BeforeID, _ = strconv.ParseInt(c.Query("before_id"), 10, 64)
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	filter.Limit = limit + 1
+
+	entries, err := ds.GetProbeDebugEntries(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "查询探测调试快照失败",
+		})
+		return
+	}
+
+	hasMore := len(entries) > limit
+	if hasMore {
+		entries = entries[:limit]
+	}
+
+	// 快照写入时已脱敏，这里按当前策略再次脱敏（策略新增的头部同样对历史快照生效）
+	h.cfgMu.RLock()
+	redact := h.config.Redaction.Clone()
+	h.cfgMu.RUnlock()
+
+	items := make([]ProbeDebugItem, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, ProbeDebugItem{
+			ID:              e.ID,
+			Provider:        e.Provider,
+			Service:         e.Service,
+			Channel:         e.Channel,
+			Model:           e.Model,
+			Timestamp:       e.Timestamp,
+			Status:          e.Status,
+			SubStatus:       string(e.SubStatus),
+			HttpCode:        e.HttpCode,
+			Latency:         e.Latency,
+			Attempts:        e.Attempts,
+			Method:          e.Method,
+			URL:             e.URL,
+			RequestHeaders:  decodeHeaderJSON(e.RequestHeaders, redact),
+			ResponseHeaders: decodeHeaderJSON(e.ResponseHeaders, redact),
+			ResponseBody:    e.ResponseBody,
+			BodyTruncated:   e.BodyTruncated,
+			Error:           e.Error,
+			Timings: ProbeDebugTimings{
+				DNSMs:     e.DNSMs,
+				ConnectMs: e.ConnectMs,
+				TLSMs:     e.TLSMs,
+				TTFBMs:    e.TTFBMs,
+			},
+			ExpiresAt: e.ExpiresAt,
+		})
+	}
+
+	var nextBeforeID int64
+	if hasMore {
+		nextBeforeID = items[len(items)-1].ID
+	}
+
+	c.JSON(http.StatusOK, ProbeDebugResponse{
+		Entries: items,
+		Meta: AuditMeta{
+			NextBeforeID: nextBeforeID,
+			HasMore:      hasMore,
+			Count:        len(items),
+		},
+	})
+}
+
+// decodeHeaderJSON 解析存储的请求头 JSON 并按策略脱敏，格式异常时返回空对象
+func decodeHeaderJSON(raw string, redact config.RedactionConfig) map[string]string {
+	headers := make(map[string]string)
+	if raw != "" {
+		_ = json.Unmarshal([]byte(raw), &headers)
+	}
+	for k, v := range headers {
+		headers[k] = redact.Redact(k, v)
+	}
+	return headers
+}
+
+// WebhookDeadLetterResponse 事件 Webhook 死信列表响应
+type WebhookDeadLetterResponse struct {
+	Entries []WebhookDeadLetterItem `json:"entries"`
+	Meta    AuditMeta               `json:"meta"`
+}
+
+// WebhookDeadLetterItem 单条事件 Webhook 死信
+type WebhookDeadLetterItem struct {
+	ID        int64           `json:"id"`
+	URL       string          `json:"url"`
+	EventID   int64           `json:"event_id"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error,omitempty"`
+	CreatedAt int64           `json:"created_at"`
+}
+
+// GetAdminWebhookDeadLetters 查询重试耗尽的事件 Webhook 投递
+// GET /api/admin/webhook-dead-letters?url=xxx&before_id=0&limit=50
+// 按 id 倒序返回，limit 默认 50，最大 200
+func (h *Handler) GetAdminWebhookDeadLetters(c *gin.Context) {
+	ds, ok := h.storage.WithContext(c.Request.Context()).(storage.WebhookDeadLetterStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持 Webhook 死信",
+		})
+		return
+	}
+
+	filter := storage.WebhookDeadLetterFilter{
+		URL: strings.TrimSpace(c.Query("url")),
+	}
+	filter.BeforeID, _ = strconv.ParseInt(c.Query("before_id"), 10, 64)
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+	filter.Limit = limit + 1
+
+	letters, err := ds.GetWebhookDeadLetters(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "查询 Webhook 死信失败",
+		})
+		return
+	}
+
+	hasMore := len(letters) > limit
+	if hasMore {
+		letters = letters[:limit]
+	}
+
+	items := make([]WebhookDeadLetterItem, 0, len(letters))
+	for _, dl := range letters {
+		payload := json.RawMessage(dl.Payload)
+		if !json.Valid(payload) {
+			payload, _ = json.Marshal(dl.Payload)
+		}
+		items = append(items, WebhookDeadLetterItem{
+			ID:        dl.ID,
+			URL:       dl.URL,
+			EventID:   dl.EventID,
+			EventType: string(dl.EventType),
+			Payload:   payload,
+			Attempts:  dl.Attempts,
+			LastError: dl.LastError,
+			CreatedAt: dl.CreatedAt,
+		})
+	}
+
+	var nextBeforeID int64
+	if hasMore {
+		nextBeforeID = items[len(items)-1].ID
+	}
+
+	c.JSON(http.StatusOK, WebhookDeadLetterResponse{
+		Entries: items,
+		Meta: AuditMeta{
+			NextBeforeID: nextBeforeID,
+			HasMore:      hasMore,
+			Count:        len(items),
+		},
+	})
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// loginLimiterTTL 限流条目空闲多久后回收（令牌早已补满，回收不影响限流效果）
+const loginLimiterTTL = 10 * time.Minute
+
+// loginLimiterEntry 单个键（IP 或用户名）的令牌桶
+type loginLimiterEntry struct {
+	limiter   *rate.Limiter
+	perMinute int
+	lastSeen  time.Time
+}
+
+// loginLimiter 管理后台登录限流（按 IP 与用户名分别计数，使用 token bucket 算法）
+//
+// 登录端点无需鉴权且每次都要计算 argon2id 哈希，限流同时防御口令猜测与资源耗尽。
+// 速率由调用方每次传入（取自当前配置），热更新后已跟踪的键同步调整；
+// 空闲条目在访问时顺带清理，无需后台协程。
+type loginLimiter struct {
+	mu        sync.Mutex
+	entries   map[string]*loginLimiterEntry
+	lastSweep time.Time
+}
+
+func newLoginLimiter() *loginLimiter {
+	return &loginLimiter{entries: make(map[string]*loginLimiterEntry)}
+}
+
+// allow 检查 key 是否还有登录尝试额度（每分钟 perMinute 次，突发容量相同）
+// 超出时返回 false 与下一次可尝试前需等待的时长
+func (l *loginLimiter) allow(key string, perMinute int, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > time.Minute {
+		l.sweep(now)
+	}
+
+	limit := rate.Limit(float64(perMinute) / 60.0)
+	entry, ok := l.entries[key]
+	if !ok {
+		entry = &loginLimiterEntry{limiter: rate.NewLimiter(limit, perMinute), perMinute: perMinute}
+		l.entries[key] = entry
+	} else if entry.perMinute != perMinute {
+		entry.limiter.SetLimitAt(now, limit)
+		entry.limiter.SetBurstAt(now, perMinute)
+		entry.perMinute = perMinute
+	}
+	entry.lastSeen = now
+
+	if entry.limiter.AllowN(now, 1) {
+		return true, 0
+	}
+	missing := 1 - entry.limiter.TokensAt(now)
+	return false, time.Duration(missing / float64(limit) * float64(time.Second))
+}
+
+// sweep 清理长时间未使用的条目（调用方需持有 mu）
+func (l *loginLimiter) sweep(now time.Time) {
+	for key, entry := range l.entries {
+		if now.Sub(entry.lastSeen) > loginLimiterTTL {
+			delete(l.entries, key)
+		}
+	}
+	l.lastSweep = now
+}
@@ -0,0 +1,349 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+const (
+	// annotationTextMaxLen 标注文本最大长度（字符）
+	annotationTextMaxLen = 500
+
+	// annotationLinkMaxLen 标注链接最大长度（字节）
+	annotationLinkMaxLen = 2048
+
+	// annotationDefaultLimit / annotationMaxLimit 管理接口列表条数
+	annotationDefaultLimit = 100
+	annotationMaxLimit     = 500
+)
+
+// AnnotationItem 运维标注（随 /api/status 与 /api/events 内联返回）
+type AnnotationItem struct {
+	ID        int64  `json:"id"`
+	Provider  string `json:"provider"`
+	Service   string `json:"service,omitempty"` // 为空表示适用于该服务商全部服务
+	Channel   string `json:"channel,omitempty"` // 为空表示适用于全部通道
+	Model     string `json:"model,omitempty"`   // 为空表示适用于全部模型
+	StartTime int64  `json:"start_time"`
+	EndTime   int64  `json:"end_time,omitempty"` // 缺省表示尚未结束
+	Text      string `json:"text"`
+	Link      string `json:"link,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// CreateAnnotationRequest 新建标注请求
+type CreateAnnotationRequest struct {
+	Provider  string `json:"provider"` // provider 名称或 slug
+	Service   string `json:"service"`
+	Channel   string `json:"channel"`
+	Model     string `json:"model"`
+	StartTime int64  `json:"start_time"`
+	EndTime   int64  `json:"end_time"`
+	Text      string `json:"text"`
+	Link      string `json:"link"`
+}
+
+// PostAdminAnnotation 为监测项的一段时间添加运维标注
+// POST /api/admin/annotations  {"provider": "xxx", "service": "cc", "start_time": 1700000000, "end_time": 1700003600, "text": "服务商确认上游故障", "link": "https://..."}
+func (h *Handler) PostAdminAnnotation(c *gin.Context) {
+	as, ok := h.storage.WithContext(c.Request.Context()).(storage.AnnotationStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持运维标注",
+		})
+		return
+	}
+
+	var req CreateAnnotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("无效的 JSON: %v", err)})
+		return
+	}
+	annotation, err := h.validateAnnotationRequest(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	annotation.CreatedAt = time.Now().Unix()
+
+	if err := as.CreateAnnotation(annotation); err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("保存运维标注失败", "provider", annotation.Provider, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "保存标注失败"})
+		return
+	}
+	// 标注随状态与事件响应返回，清空缓存使其立即可见
+	h.resetCache()
+
+	h.audit.Record(c.Request.Context(), storage.AuditEntry{
+		Actor:  adminActorOf(c),
+		Action: "annotation.create",
+		Target: annotationTarget(annotation),
+		Detail: fmt.Sprintf("id=%d text=%s", annotation.ID, annotation.Text),
+		IP:     c.ClientIP(),
+	})
+
+	c.JSON(http.StatusCreated, toAnnotationItem(annotation))
+}
+
+// GetAdminAnnotations 查询运维标注列表
+// GET /api/admin/annotations?provider=xxx&since=1700000000&until=1700086400&limit=100
+func (h *Handler) GetAdminAnnotations(c *gin.Context) {
+	as, ok := h.storage.WithContext(c.Request.Context()).(storage.AnnotationStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持运维标注",
+		})
+		return
+	}
+
+	filter := storage.AnnotationFilter{Limit: annotationDefaultLimit}
+	if p := strings.TrimSpace(c.Query("provider")); p != "" {
+		h.cfgMu.RLock()
+		filter.Provider = resolveAnnotationProvider(h.allMonitors(), p)
+		h.cfgMu.RUnlock()
+		if filter.Provider == "" {
+			filter.Provider = p
+		}
+	}
+	for _, param := range []struct {
+		name string
+		dst  *int64
+	}{{"since", &filter.Since}, {"until", &filter.Until}} {
+		if v := c.Query(param.name); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("无效的 %s 参数: %s", param.name, v)})
+				return
+			}
+			*param.dst = n
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			filter.Limit = min(n, annotationMaxLimit)
+		}
+	}
+
+	annotations, err := as.GetAnnotations(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询运维标注失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"annotations": toAnnotationItems(annotations)})
+}
+
+// DeleteAdminAnnotation 删除运维标注
+// DELETE /api/admin/annotations/:id
+func (h *Handler) DeleteAdminAnnotation(c *gin.Context) {
+	as, ok := h.storage.WithContext(c.Request.Context()).(storage.AnnotationStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持运维标注",
+		})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的标注 ID"})
+		return
+	}
+
+	deleted, err := as.DeleteAnnotation(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除标注失败"})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "标注不存在"})
+		return
+	}
+	h.resetCache()
+
+	h.audit.Record(c.Request.Context(), storage.AuditEntry{
+		Actor:  adminActorOf(c),
+		Action: "annotation.delete",
+		Target: strconv.FormatInt(id, 10),
+		IP:     c.ClientIP(),
+	})
+
+	c.JSON(http.StatusOK, gin.H{"id": id, "deleted": true})
+}
+
+// validateAnnotationRequest 校验新建请求并规范化为存储结构
+// provider 可为名称或 slug；provider/service/channel/model 组合必须匹配至少一个已配置的监测项
+func (h *Handler) validateAnnotationRequest(req CreateAnnotationRequest) (*storage.Annotation, error) {
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return nil, fmt.Errorf("text 不能为空")
+	}
+	if utf8.RuneCountInString(text) > annotationTextMaxLen {
+		return nil, fmt.Errorf("text 不能超过 %d 个字符", annotationTextMaxLen)
+	}
+	link := strings.TrimSpace(req.Link)
+	if link != "" {
+		u, err := url.Parse(link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(link) > annotationLinkMaxLen {
+			return nil, fmt.Errorf("link 必须是有效的 http(s) 地址")
+		}
+	}
+	if req.StartTime <= 0 {
+		return nil, fmt.Errorf("start_time 必须为正的 Unix 秒")
+	}
+	if req.EndTime != 0 && req.EndTime < req.StartTime {
+		return nil, fmt.Errorf("end_time 不能早于 start_time")
+	}
+
+	annotation := &storage.Annotation{
+		Service:   strings.TrimSpace(req.Service),
+		Channel:   strings.TrimSpace(req.Channel),
+		Model:     strings.TrimSpace(req.Model),
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Text:      text,
+		Link:      link,
+	}
+
+	h.cfgMu.RLock()
+	defer h.cfgMu.RUnlock()
+	annotation.Provider = resolveAnnotationProvider(h.allMonitors(), req.Provider)
+	if annotation.Provider == "" {
+		return nil, fmt.Errorf("服务商不存在: %s", req.Provider)
+	}
+	for _, m := range h.allMonitors() {
+		if !m.Disabled && annotation.Matches(m.Provider, m.Service, m.Channel, m.Model) {
+			return annotation, nil
+		}
+	}
+	return nil, fmt.Errorf("未找到匹配的监测项: %s", annotationTarget(annotation))
+}
+
+// resolveAnnotationProvider 将 provider 名称或 slug 解析为配置中的 provider 名称，未找到时返回空
+// 调用方需持有 cfgMu 读锁
+func resolveAnnotationProvider(monitors []config.ServiceConfig, provider string) string {
+	normalized := strings.ToLower(strings.TrimSpace(provider))
+	if normalized == "" {
+		return ""
+	}
+	for _, m := range monitors {
+		if m.ProviderSlug == normalized || strings.ToLower(strings.TrimSpace(m.Provider)) == normalized {
+			return m.Provider
+		}
+	}
+	return ""
+}
+
+// loadAnnotations 查询与 [since, until] 重叠的运维标注
+// 存储不支持或查询失败时返回空，不影响状态与事件的正常返回
+func (h *Handler) loadAnnotations(ctx context.Context, since, until int64) []*storage.Annotation {
+	as, ok := h.storage.WithContext(ctx).(storage.AnnotationStorage)
+	if !ok {
+		return nil
+	}
+	annotations, err := as.GetAnnotations(storage.AnnotationFilter{Since: since, Until: until})
+	if err != nil {
+		logger.FromContext(ctx, "api").Warn("查询运维标注失败", "error", err)
+		return nil
+	}
+	return annotations
+}
+
+// attachAnnotations 将标注挂到对应的监测项与监测组（组内任一模型层匹配即挂到组上）
+func attachAnnotations(data []MonitorResult, groups []MonitorGroup, annotations []*storage.Annotation) {
+	for i := range data {
+		m := &data[i]
+		for _, a := range annotations {
+			if a.Matches(m.Provider, m.Service, m.Channel, "") {
+				m.Annotations = append(m.Annotations, toAnnotationItem(a))
+			}
+		}
+	}
+	for i := range groups {
+		g := &groups[i]
+		for _, a := range annotations {
+			for _, layer := range g.Layers {
+				if a.Matches(g.Provider, g.Service, g.Channel, layer.Model) {
+					g.Annotations = append(g.Annotations, toAnnotationItem(a))
+					break
+				}
+			}
+		}
+	}
+}
+
+// eventAnnotations 返回适用于事件（监测项匹配且时间段覆盖观测时间）的标注
+func eventAnnotations(e *storage.StatusEvent, annotations []*storage.Annotation) []*storage.Annotation {
+	var matched []*storage.Annotation
+	for _, a := range annotations {
+		if a.Covers(e.ObservedAt) && a.Matches(e.Provider, e.Service, e.Channel, e.Model) {
+			matched = append(matched, a)
+		}
+	}
+	return matched
+}
+
+// loadEventAnnotations 查询覆盖这批事件观测时间范围的标注
+func (h *Handler) loadEventAnnotations(ctx context.Context, events []*storage.StatusEvent) []*storage.Annotation {
+	if len(events) == 0 {
+		return nil
+	}
+	since, until := events[0].ObservedAt, events[0].ObservedAt
+	for _, e := range events[1:] {
+		since = min(since, e.ObservedAt)
+		until = max(until, e.ObservedAt)
+	}
+	return h.loadAnnotations(ctx, since, until)
+}
+
+// annotationTarget 标注作用对象的可读描述（如 "Relay/cc/*"）
+func annotationTarget(a *storage.Annotation) string {
+	parts := []string{a.Provider}
+	for _, p := range []string{a.Service, a.Channel, a.Model} {
+		if p == "" {
+			p = "*"
+		}
+		parts = append(parts, p)
+	}
+	// 去掉末尾多余的通配
+	for len(parts) > 2 && parts[len(parts)-1] == "*" {
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, "/")
+}
+
+// toAnnotationItem 转换为 API 响应结构
+func toAnnotationItem(a *storage.Annotation) AnnotationItem {
+	return AnnotationItem{
+		ID:        a.ID,
+		Provider:  a.Provider,
+		Service:   a.Service,
+		Channel:   a.Channel,
+		Model:     a.Model,
+		StartTime: a.StartTime,
+		EndTime:   a.EndTime,
+		Text:      a.Text,
+		Link:      a.Link,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+// toAnnotationItems 批量转换（nil 转为空切片）
+func toAnnotationItems(annotations []*storage.Annotation) []AnnotationItem {
+	items := make([]AnnotationItem, 0, len(annotations))
+	for _, a := range annotations {
+		items = append(items, toAnnotationItem(a))
+	}
+	return items
+}
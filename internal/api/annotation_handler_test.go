@@ -0,0 +1,148 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestAnnotationAdminAndEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newTestSQLiteStore(t)
+
+	h := NewHandler(store, &config.AppConfig{
+		Events: config.EventsConfig{APIToken: "events-token"},
+		Monitors: []config.ServiceConfig{
+			{Provider: "Relay", ProviderSlug: "relay", Service: "cc", Channel: "vip"},
+			{Provider: "Other", ProviderSlug: "other", Service: "cc"},
+		},
+	})
+	router := gin.New()
+	router.POST("/api/admin/annotations", h.PostAdminAnnotation)
+	router.GET("/api/admin/annotations", h.GetAdminAnnotations)
+	router.DELETE("/api/admin/annotations/:id", h.DeleteAdminAnnotation)
+	router.GET("/api/events", h.GetEvents)
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer events-token")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, body := range []string{
+		`{"provider":"missing","start_time":100,"text":"x"}`,
+		`{"provider":"relay","service":"cx","start_time":100,"text":"x"}`,
+		`{"provider":"relay","start_time":100,"text":"  "}`,
+		`{"provider":"relay","start_time":200,"end_time":100,"text":"x"}`,
+		`{"provider":"relay","start_time":100,"text":"x","link":"javascript:alert(1)"}`,
+	} {
+		if w := serve(http.MethodPost, "/api/admin/annotations", body); w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", body, w.Code)
+		}
+	}
+
+	w := serve(http.MethodPost, "/api/admin/annotations", `{"provider":"relay","service":"cc","start_time":100,"end_time":300,"text":"服务商确认上游故障","link":"https://status.example.com/1"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created AnnotationItem
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode annotation: %v", err)
+	}
+	if created.ID == 0 || created.Provider != "Relay" || created.Channel != "" {
+		t.Fatalf("unexpected annotation: %+v", created)
+	}
+
+	var list struct {
+		Annotations []AnnotationItem `json:"annotations"`
+	}
+	w = serve(http.MethodGet, "/api/admin/annotations?provider=relay&since=250", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Annotations) != 1 {
+		t.Fatalf("expected overlapping annotation listed, got %s", w.Body.String())
+	}
+	w = serve(http.MethodGet, "/api/admin/annotations?since=301", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Annotations) != 0 {
+		t.Fatalf("expected ended annotation excluded, got %s", w.Body.String())
+	}
+
+	// 事件列表内联覆盖观测时间的标注
+	for _, e := range []*storage.StatusEvent{
+		{Provider: "Relay", Service: "cc", Channel: "vip", EventType: storage.EventTypeDown, ObservedAt: 200, CreatedAt: 200},
+		{Provider: "Relay", Service: "cc", Channel: "vip", EventType: storage.EventTypeUp, ObservedAt: 400, CreatedAt: 400},
+		{Provider: "Other", Service: "cc", EventType: storage.EventTypeDown, ObservedAt: 200, CreatedAt: 200},
+	} {
+		if err := store.SaveStatusEvent(e); err != nil {
+			t.Fatalf("save event: %v", err)
+		}
+	}
+	var events EventsResponse
+	w = serve(http.MethodGet, "/api/events", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &events); err != nil || len(events.Events) != 3 {
+		t.Fatalf("unexpected events response: %s", w.Body.String())
+	}
+	if notes := events.Events[0].Annotations; len(notes) != 1 || notes[0].Text != "服务商确认上游故障" {
+		t.Fatalf("expected annotation on covered event: %+v", events.Events[0])
+	}
+	if len(events.Events[1].Annotations) != 0 || len(events.Events[2].Annotations) != 0 {
+		t.Fatalf("annotation leaked to uncovered events: %+v", events.Events[1:])
+	}
+
+	id := strconv.FormatInt(created.ID, 10)
+	if w := serve(http.MethodDelete, "/api/admin/annotations/"+id, ""); w.Code != http.StatusOK {
+		t.Fatalf("expected delete 200, got %d", w.Code)
+	}
+	if w := serve(http.MethodDelete, "/api/admin/annotations/"+id, ""); w.Code != http.StatusNotFound {
+		t.Fatalf("deleting twice should return 404, got %d", w.Code)
+	}
+}
+
+func TestAttachAnnotations(t *testing.T) {
+	t.Parallel()
+
+	data := []MonitorResult{
+		{Provider: "Relay", Service: "cc", Channel: "vip"},
+		{Provider: "Relay", Service: "cx", Channel: "vip"},
+	}
+	groups := []MonitorGroup{
+		{Provider: "Relay", Service: "cc", Channel: "m", Layers: []MonitorLayer{{Model: "gpt"}, {Model: "mini"}}},
+		{Provider: "Relay", Service: "cc", Channel: "n", Layers: []MonitorLayer{{Model: "gpt"}}},
+	}
+	annotations := []*storage.Annotation{
+		{ID: 1, Provider: "Relay", Service: "cc", Text: "service wide"},
+		{ID: 2, Provider: "Relay", Service: "cc", Channel: "m", Model: "mini", Text: "model only"},
+	}
+
+	attachAnnotations(data, groups, annotations)
+
+	if len(data[0].Annotations) != 1 || len(data[1].Annotations) != 0 {
+		t.Fatalf("unexpected data annotations: %+v", data)
+	}
+	if len(groups[0].Annotations) != 2 || len(groups[1].Annotations) != 1 {
+		t.Fatalf("unexpected group annotations: %+v", groups)
+	}
+}
+
+func TestFeedSummaryIncludesAnnotations(t *testing.T) {
+	t.Parallel()
+
+	monitors := []config.ServiceConfig{{Provider: "Relay", ProviderSlug: "relay", Service: "cc"}}
+	events := []*storage.StatusEvent{{ID: 1, Provider: "Relay", Service: "cc", EventType: storage.EventTypeDown, ObservedAt: 200}}
+	annotations := []*storage.Annotation{{Provider: "Relay", StartTime: 100, Text: "上游故障", Link: "https://example.com/x"}}
+
+	feed := buildAtomFeed(events, annotations, monitors, "https://relaypulse.top", "https://relaypulse.top/feed.xml", 10, time.Unix(1000, 0))
+	if !strings.Contains(feed.Entries[0].Summary.Text, "标注：上游故障（https://example.com/x）") {
+		t.Fatalf("summary should include annotation: %s", feed.Entries[0].Summary.Text)
+	}
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"monitor/internal/baseline"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// attachCorrelations 为当前为红/黄的监测项与模型层附加官方基线归因
+// 未配置基线或查询失败时不做处理，不影响正常响应
+func (h *Handler) attachCorrelations(ctx context.Context, idx *baseline.Index, data []MonitorResult, groups []MonitorGroup) {
+	if idx.Empty() {
+		return
+	}
+	latest, err := baseline.LatestRecords(h.storage.WithContext(ctx), idx.All())
+	if err != nil {
+		logger.FromContext(ctx, "api").Warn("查询官方基线失败，跳过归因", "error", err)
+		return
+	}
+	applyCorrelations(idx, latest, data, groups, time.Now())
+}
+
+// applyCorrelations 按基线最新记录计算归因（基线自身不参与）
+func applyCorrelations(idx *baseline.Index, latest map[storage.MonitorKey]*storage.ProbeRecord, data []MonitorResult, groups []MonitorGroup, now time.Time) {
+	for i := range data {
+		m := &data[i]
+		if m.Current == nil || idx.IsBaseline(storage.MonitorKey{Provider: m.Provider, Service: m.Service, Channel: m.Channel}) {
+			continue
+		}
+		m.Current.Correlation = idx.Correlate(m.Service, "", m.Current.Status, latest, now)
+	}
+	for i := range groups {
+		g := &groups[i]
+		for j := range g.Layers {
+			layer := &g.Layers[j]
+			if idx.IsBaseline(storage.MonitorKey{Provider: g.Provider, Service: g.Service, Channel: g.Channel, Model: layer.Model}) {
+				continue
+			}
+			layer.CurrentStatus.Correlation = idx.Correlate(g.Service, layer.Model, layer.CurrentStatus.Status, latest, now)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"monitor/internal/baseline"
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestApplyCorrelations(t *testing.T) {
+	t.Parallel()
+
+	idx := baseline.NewIndex([]config.ServiceConfig{
+		{Provider: "official", Service: "cc", Channel: "direct", Type: config.MonitorTypeBaseline, IntervalDuration: time.Minute},
+	})
+	now := time.Unix(10_000, 0)
+	latest := map[storage.MonitorKey]*storage.ProbeRecord{
+		{Provider: "official", Service: "cc", Channel: "direct"}: {Status: 0, Timestamp: now.Unix()},
+	}
+
+	data := []MonitorResult{
+		{Provider: "relay", Service: "cc", Channel: "vip", Current: &CurrentStatus{Status: 0}},
+		{Provider: "relay", Service: "cc", Channel: "ok", Current: &CurrentStatus{Status: 1}},
+		{Provider: "official", Service: "cc", Channel: "direct", Current: &CurrentStatus{Status: 0}},
+	}
+	groups := []MonitorGroup{
+		{Provider: "relay", Service: "cc", Channel: "m", Layers: []MonitorLayer{
+			{Model: "opus", CurrentStatus: StatusPoint{Status: 2}},
+		}},
+	}
+
+	applyCorrelations(idx, latest, data, groups, now)
+
+	if c := data[0].Current.Correlation; c == nil || c.Scope != baseline.ScopeUpstream || c.Baseline != "official/cc/direct" {
+		t.Fatalf("expected upstream correlation, got %+v", c)
+	}
+	if data[1].Current.Correlation != nil || data[2].Current.Correlation != nil {
+		t.Fatalf("healthy monitor and baseline itself should not be correlated: %+v %+v", data[1].Current, data[2].Current)
+	}
+	if c := groups[0].Layers[0].CurrentStatus.Correlation; c == nil || c.Scope != baseline.ScopeUpstream {
+		t.Fatalf("expected layer correlation, got %+v", c)
+	}
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/scheduler"
+)
+
+// BudgetsResponse 服务商每日预算用量响应
+type BudgetsResponse struct {
+	Budgets []BudgetItem `json:"budgets"`
+	Meta    BudgetsMeta  `json:"meta"`
+}
+
+// BudgetsMeta 预算用量元数据
+type BudgetsMeta struct {
+	Count     int    `json:"count"`
+	Exhausted int    `json:"exhausted"` // 当日预算已耗尽的服务商数
+	Day       string `json:"day"`       // 统计日期（UTC）
+	ResetAt   int64  `json:"reset_at"`  // 下次清零时间（UTC 零点，Unix 秒）
+}
+
+// BudgetItem 单个服务商的当日预算用量
+type BudgetItem struct {
+	Provider       string `json:"provider"`
+	DailyRequests  int64  `json:"daily_requests"` // 每日请求数上限（0 表示不限制）
+	DailyTokens    int64  `json:"daily_tokens"`   // 每日 token 上限（0 表示不限制）
+	Action         string `json:"action"`         // 耗尽后的处理方式：pause / throttle
+	ThrottleFactor int    `json:"throttle_factor,omitempty"`
+
+	UsedRequests int64    `json:"used_requests"`
+	UsedTokens   int64    `json:"used_tokens"`
+	RequestsPct  *float64 `json:"requests_pct,omitempty"` // 请求数消耗比例（百分比，未限制时省略）
+	TokensPct    *float64 `json:"tokens_pct,omitempty"`   // token 消耗比例（百分比，未限制时省略）
+	Exhausted    bool     `json:"exhausted"`
+	ExhaustedAt  int64    `json:"exhausted_at,omitempty"` // 耗尽时间（Unix 秒）
+	Skipped      int64    `json:"skipped"`                // 当日因预算耗尽跳过的探测次数
+}
+
+// GetAdminBudgets 查询服务商当日探测预算用量
+// GET /api/admin/budgets
+func (h *Handler) GetAdminBudgets(c *gin.Context) {
+	if h.scheduler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "调度器未就绪",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, buildBudgetsResponse(h.scheduler.Budgets(), time.Now()))
+}
+
+// buildBudgetsResponse 将预算快照转换为响应
+func buildBudgetsResponse(infos []scheduler.BudgetInfo, now time.Time) BudgetsResponse {
+	day := now.UTC()
+	resp := BudgetsResponse{
+		Budgets: make([]BudgetItem, 0, len(infos)),
+		Meta: BudgetsMeta{
+			Day:     day.Format("2006-01-02"),
+			ResetAt: time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, time.UTC).Unix(),
+		},
+	}
+	for _, info := range infos {
+		item := BudgetItem{
+			Provider:      info.Provider,
+			DailyRequests: info.DailyRequests,
+			DailyTokens:   info.DailyTokens,
+			Action:        info.Action,
+			UsedRequests:  info.Requests,
+			UsedTokens:    info.Tokens,
+			RequestsPct:   budgetPct(info.Requests, info.DailyRequests),
+			TokensPct:     budgetPct(info.Tokens, info.DailyTokens),
+			Exhausted:     info.Exhausted,
+			Skipped:       info.Skipped,
+		}
+		if info.Action == config.BudgetActionThrottle {
+			item.ThrottleFactor = info.ThrottleFactor
+		}
+		if !info.ExhaustedAt.IsZero() {
+			item.ExhaustedAt = info.ExhaustedAt.Unix()
+		}
+		if info.Exhausted {
+			resp.Meta.Exhausted++
+		}
+		resp.Budgets = append(resp.Budgets, item)
+	}
+	resp.Meta.Count = len(resp.Budgets)
+	return resp
+}
+
+// budgetPct 计算消耗比例（保留两位小数，未限制时返回 nil）
+func budgetPct(used, limit int64) *float64 {
+	if limit <= 0 {
+		return nil
+	}
+	pct := math.Round(float64(used)/float64(limit)*10000) / 100
+	return &pct
+}
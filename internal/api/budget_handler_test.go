@@ -0,0 +1,31 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"monitor/internal/scheduler"
+)
+
+func TestBuildBudgetsResponse(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 16, 12, 0, 0, 0, time.UTC)
+	resp := buildBudgetsResponse([]scheduler.BudgetInfo{
+		{Provider: "Relay", DailyRequests: 200, Action: "pause", ThrottleFactor: 4, Requests: 50, Tokens: 1000},
+		{Provider: "Other", DailyTokens: 1000, Action: "throttle", ThrottleFactor: 3, Tokens: 1200, Exhausted: true, ExhaustedAt: now, Skipped: 7},
+	}, now)
+
+	if resp.Meta.Count != 2 || resp.Meta.Exhausted != 1 || resp.Meta.Day != "2024-05-16" ||
+		resp.Meta.ResetAt != time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC).Unix() {
+		t.Fatalf("unexpected meta: %+v", resp.Meta)
+	}
+	relay := resp.Budgets[0]
+	if relay.RequestsPct == nil || *relay.RequestsPct != 25 || relay.TokensPct != nil || relay.ThrottleFactor != 0 || relay.ExhaustedAt != 0 {
+		t.Fatalf("unexpected relay item: %+v", relay)
+	}
+	other := resp.Budgets[1]
+	if other.TokensPct == nil || *other.TokensPct != 120 || other.ThrottleFactor != 3 || other.ExhaustedAt != now.Unix() || other.Skipped != 7 {
+		t.Fatalf("unexpected other item: %+v", other)
+	}
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"strings"
+
+	"monitor/internal/config"
+)
+
+// initCaches 创建各端点的响应缓存并按 cache_ttl 配置容量
+func (h *Handler) initCaches(cfg *config.AppConfig) {
+	h.cache = newStatusCache(config.DefaultCacheTTLShort, config.DefaultCacheMaxEntries)
+	h.eventsCache = newStatusCache(config.DefaultCacheTTLShort, config.DefaultCacheMaxEntries)
+	h.provCache = newStatusCache(config.DefaultCacheTTLShort, config.DefaultCacheMaxEntries)
+	h.siteCache = newStatusCache(config.DefaultSitemapCacheTTL, config.DefaultCacheMaxEntries)
+	h.configureCaches(cfg)
+}
+
+// configureCaches 按 cache_ttl 更新各端点缓存的容量与过期策略（热更新时调用）
+func (h *Handler) configureCaches(cfg *config.AppConfig) {
+	ttl := cfg.CacheTTL
+	h.cache.configure(ttl.Status.MaxEntries, ttl.StaleDuration, ttl.ErrorDuration)
+	h.eventsCache.configure(ttl.Events.MaxEntries, ttl.StaleDuration, ttl.ErrorDuration)
+	h.provCache.configure(ttl.Provider.MaxEntries, ttl.StaleDuration, ttl.ErrorDuration)
+	h.siteCache.configure(ttl.Sitemap.MaxEntries, 0, 0)
+}
+
+// cacheBypassed 判断按服务商筛选的请求是否跳过缓存（provider 可为名称或 slug，"all"/空值不跳过）
+// 调用方需持有 cfgMu 读锁
+func (h *Handler) cacheBypassed(provider string) bool {
+	provider = strings.TrimSpace(provider)
+	if provider == "" || strings.EqualFold(provider, "all") || len(h.config.CacheTTL.BypassProviders) == 0 {
+		return false
+	}
+	for _, m := range h.config.Monitors {
+		if (strings.EqualFold(m.Provider, provider) || strings.EqualFold(m.ProviderSlug, provider)) &&
+			h.config.CacheTTL.Bypassed(m.Provider, m.ProviderSlug) {
+			return true
+		}
+	}
+	// 未匹配监测项时按参数本身判断（配置中的名称即查询参数）
+	return h.config.CacheTTL.Bypassed(provider, "")
+}
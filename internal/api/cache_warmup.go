@@ -0,0 +1,170 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+)
+
+// warmupJob 单个待预热的 /api/status 查询（其余参数均为前端默认值）
+type warmupJob struct {
+	period   string
+	provider string // "all" 或小写的服务商名称
+}
+
+// cacheWarmupJobs 生成预热任务：各 period 的默认筛选在前，其后为监测项最多的服务商筛选
+func cacheWarmupJobs(cfg config.CacheWarmupConfig, monitors []config.ServiceConfig) []warmupJob {
+	providers := append([]string{"all"}, topProviders(monitors, cfg.TopProvidersValue)...)
+	jobs := make([]warmupJob, 0, len(providers)*len(cfg.Periods))
+	for _, provider := range providers {
+		for _, period := range cfg.Periods {
+			jobs = append(jobs, warmupJob{period: period, provider: provider})
+		}
+	}
+	return jobs
+}
+
+// topProviders 按可见监测项数量降序返回前 n 个服务商（小写，与 provider 参数的归一化一致；数量相同时按配置顺序）
+func topProviders(monitors []config.ServiceConfig, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	counts := make(map[string]int)
+	var order []string
+	for _, m := range monitors {
+		if m.Disabled || m.Hidden {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSpace(m.Provider))
+		if _, ok := counts[name]; !ok {
+			order = append(order, name)
+		}
+		counts[name]++
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return counts[order[i]] > counts[order[j]]
+	})
+	if len(order) > n {
+		order = order[:n]
+	}
+	return order
+}
+
+// WarmCache 同步预热默认命名空间与各命名空间的 /api/status 缓存（启动时在开始监听前调用，受 cache_warmup.timeout 约束）
+func (h *Handler) WarmCache(ctx context.Context) {
+	h.cfgMu.RLock()
+	cfg := h.config.CacheWarmup
+	children := make([]*Handler, 0, len(h.namespaces))
+	for _, child := range h.namespaces {
+		children = append(children, child)
+	}
+	h.cfgMu.RUnlock()
+
+	if !cfg.Enabled {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.TimeoutDuration)
+	defer cancel()
+
+	start := time.Now()
+	warmed, failed := h.warmCache(ctx)
+	for _, child := range children {
+		w, f := child.warmCache(ctx)
+		warmed += w
+		failed += f
+	}
+	logger.Info("api", "启动缓存预热完成",
+		"warmed", warmed, "failed", failed, "namespaces", len(children), "duration", time.Since(start).Round(time.Millisecond))
+}
+
+// resetCache 清空各端点的响应缓存，启用预热时在后台重新预热
+func (h *Handler) resetCache() {
+	h.cache.clear()
+	h.eventsCache.clear()
+	h.provCache.clear()
+	h.siteCache.clear()
+	h.startCacheWarmup()
+}
+
+// startCacheWarmup 后台预热缓存（取消上一轮未完成的预热，不再按旧配置发起查询）
+func (h *Handler) startCacheWarmup() {
+	h.cfgMu.RLock()
+	cfg := h.config.CacheWarmup
+	h.cfgMu.RUnlock()
+
+	h.warmMu.Lock()
+	defer h.warmMu.Unlock()
+	if h.warmCancel != nil {
+		h.warmCancel()
+		h.warmCancel = nil
+	}
+	if !cfg.Enabled {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.TimeoutDuration)
+	h.warmCancel = cancel
+	go func() {
+		defer cancel()
+		start := time.Now()
+		warmed, failed := h.warmCache(ctx)
+		if ctx.Err() == context.Canceled {
+			return // 已被新一轮预热取代
+		}
+		logger.Debug("api", "缓存预热完成",
+			"namespace", h.namespace, "warmed", warmed, "failed", failed, "duration", time.Since(start).Round(time.Millisecond))
+	}()
+}
+
+// warmCache 按 cache_warmup.concurrency 并发执行一轮预热，返回成功与失败的条数
+// ctx 结束后不再发起新的查询并立即返回；已发起的查询使用独立 context 继续完成，
+// 因为与在线请求共用 singleflight，取消它会让等待同一 key 的请求一并失败
+func (h *Handler) warmCache(ctx context.Context) (int, int) {
+	h.cfgMu.RLock()
+	cfg := h.config.CacheWarmup
+	monitors := h.config.Monitors
+	cacheTTL := h.config.CacheTTL
+	h.cfgMu.RUnlock()
+
+	var warmed, failed atomic.Int64
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		g := new(errgroup.Group)
+		g.SetLimit(cfg.Concurrency)
+		for _, job := range cacheWarmupJobs(cfg, monitors) {
+			if ctx.Err() != nil {
+				break
+			}
+			g.Go(func() error {
+				key := statusCacheKey(job.period, "", "", job.provider, "all", "hot", false, false, nil, time.UTC)
+				_, err := h.cache.loadEntry(ctx, key, cacheTTL.TTLForPeriod(job.period), func() ([]byte, error) {
+					queryCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+					defer cancel()
+					return h.queryAndSerialize(queryCtx, job.period, "", time.UTC, nil, nil, job.provider, "all", "hot", false, false)
+				})
+				if err != nil {
+					failed.Add(1)
+					logger.Warn("api", "缓存预热失败", "namespace", h.namespace, "cache_key", key, "error", err)
+					return nil
+				}
+				warmed.Add(1)
+				return nil
+			})
+		}
+		_ = g.Wait()
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	return int(warmed.Load()), int(failed.Load())
+}
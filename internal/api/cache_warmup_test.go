@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestTopProviders(t *testing.T) {
+	t.Parallel()
+
+	monitors := []config.ServiceConfig{
+		{Provider: "Alpha", Service: "cc"},
+		{Provider: "Beta", Service: "cc"},
+		{Provider: " beta ", Service: "cx"},
+		{Provider: "Gamma", Service: "cc"},
+		{Provider: "Gamma", Service: "cx", Hidden: true},
+		{Provider: "Gamma", Service: "gm", Disabled: true},
+	}
+	if got := topProviders(monitors, 2); !reflect.DeepEqual(got, []string{"beta", "alpha"}) {
+		t.Fatalf("unexpected top providers: %v", got)
+	}
+	if got := topProviders(monitors, 0); got != nil {
+		t.Fatalf("expected no providers, got %v", got)
+	}
+
+	jobs := cacheWarmupJobs(config.CacheWarmupConfig{Periods: []string{"24h", "7d"}, TopProvidersValue: 1}, monitors)
+	want := []warmupJob{{"24h", "all"}, {"7d", "all"}, {"24h", "beta"}, {"7d", "beta"}}
+	if !reflect.DeepEqual(jobs, want) {
+		t.Fatalf("unexpected jobs: %+v", jobs)
+	}
+}
+
+func TestWarmCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newTestSQLiteStore(t)
+	if err := store.SaveRecord(&storage.ProbeRecord{Provider: "Relay", Service: "cc", Status: 1, Latency: 100, Timestamp: time.Now().Unix() - 60}); err != nil {
+		t.Fatalf("save record: %v", err)
+	}
+
+	one := 1
+	warmup := config.CacheWarmupConfig{Enabled: true, TopProviders: &one}
+	if err := warmup.Normalize(); err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	h := NewHandler(store, &config.AppConfig{
+		MaxStatusRangeDays: 180,
+		CacheWarmup:        warmup,
+		Monitors: []config.ServiceConfig{
+			{Provider: "Relay", ProviderSlug: "relay", Service: "cc"},
+			{Provider: "Other", ProviderSlug: "other", Service: "cc"},
+		},
+	})
+
+	h.WarmCache(context.Background())
+	for _, provider := range []string{"all", "relay"} {
+		key := statusCacheKey("24h", "", "", provider, "all", "hot", false, false, nil, time.UTC)
+		if _, ok := h.cache.get(key); !ok {
+			t.Fatalf("expected warmed cache key %q", key)
+		}
+	}
+	if n := len(h.cache.entries); n != 2 {
+		t.Fatalf("expected 2 warmed entries, got %d", n)
+	}
+
+	// 前端默认请求应命中预热的 key
+	router := gin.New()
+	router.GET("/api/status", h.GetStatus)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/status?period=24h&board=hot", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if n := len(h.cache.entries); n != 2 {
+		t.Fatalf("default request should hit a warmed key, entries=%d", n)
+	}
+}
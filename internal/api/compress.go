@@ -0,0 +1,227 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// compressMinBytes 小于该长度（已知 Content-Length 时）的响应不压缩，压缩收益抵不上开销
+const compressMinBytes = 1024
+
+// gzipWriterPool 复用 gzip.Writer（DefaultCompression），避免每个响应分配压缩字典
+var gzipWriterPool = sync.Pool{
+	New: func() any {
+		return gzip.NewWriter(io.Discard)
+	},
+}
+
+// gzipBytes 压缩完整的字节切片（用于缓存条目预压缩）
+func gzipBytes(data []byte) []byte {
+	var buf bytes.Buffer
+	buf.Grow(len(data) / 4)
+	gz := gzipWriterPool.Get().(*gzip.Writer)
+	gz.Reset(&buf)
+	gz.Write(data)
+	gz.Close()
+	gzipWriterPool.Put(gz)
+	return buf.Bytes()
+}
+
+// acceptsGzip 按 Accept-Encoding 协商是否可返回 gzip（支持 q 值，q=0 表示拒绝）
+func acceptsGzip(acceptEncoding string) bool {
+	gzipQ, wildcardQ := -1.0, -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(coding)) {
+		case "gzip", "x-gzip":
+			gzipQ = q
+		case "*":
+			wildcardQ = q
+		}
+	}
+	if gzipQ >= 0 {
+		return gzipQ > 0
+	}
+	return wildcardQ > 0
+}
+
+// compressibleContentType 是否为值得压缩的文本类内容（图片、字体、压缩包等已压缩格式跳过）
+func compressibleContentType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(strings.ToLower(contentType), ";")
+	mediaType = strings.TrimSpace(mediaType)
+	if mediaType == "" || strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/x-ndjson", "application/javascript", "application/xml", "application/atom+xml",
+		"application/rss+xml", "application/manifest+json", "image/svg+xml":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml")
+}
+
+// compressionMiddleware 协商式 gzip 压缩中间件
+//
+// - 响应头已设置 Content-Encoding（如 writeCached 输出的预压缩数据）时原样透传，不会重复压缩
+// - 仅压缩文本类内容；小于 compressMinBytes 的响应不压缩
+// - excluded 匹配的路径（如 SSE 进度流）完全跳过
+func compressionMiddleware(excluded ...*regexp.Regexp) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, re := range excluded {
+			if re.MatchString(c.Request.URL.Path) {
+				c.Next()
+				return
+			}
+		}
+
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(c.GetHeader("Accept-Encoding")) || strings.Contains(c.GetHeader("Connection"), "Upgrade") {
+			c.Next()
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: c.Writer}
+		c.Writer = cw
+		defer cw.close()
+		c.Next()
+	}
+}
+
+// compressWriter 在首次写入响应体时根据响应头决定是否压缩；
+// 长度未知时先缓冲，累计达到 compressMinBytes 才启用压缩，小响应原样输出
+type compressWriter struct {
+	gin.ResponseWriter
+	decided bool
+	buf     []byte
+	gz      *gzip.Writer
+}
+
+// skip 根据状态码、Content-Encoding、Content-Type 与 Content-Length 判断是否不压缩
+func (w *compressWriter) skip() bool {
+	h := w.Header()
+	status := w.Status()
+	if h.Get("Content-Encoding") != "" || status < 200 || status == 204 || status == 206 || status == 304 {
+		return true
+	}
+	if !compressibleContentType(h.Get("Content-Type")) {
+		return true
+	}
+	n, err := strconv.Atoi(h.Get("Content-Length"))
+	return err == nil && n < compressMinBytes
+}
+
+// startGzip 启用压缩并写出已缓冲的数据
+func (w *compressWriter) startGzip() error {
+	w.decided = true
+
+	h := w.Header()
+	h.Set("Content-Encoding", "gzip")
+	h.Del("Content-Length")
+	if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		h.Set("ETag", "W/"+etag)
+	}
+	w.gz = gzipWriterPool.Get().(*gzip.Writer)
+	w.gz.Reset(w.ResponseWriter)
+
+	buf := w.buf
+	w.buf = nil
+	_, err := w.gz.Write(buf)
+	return err
+}
+
+// flushIdentity 放弃压缩，原样写出已缓冲的数据
+func (w *compressWriter) flushIdentity() error {
+	w.decided = true
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		if w.buf == nil && w.skip() {
+			w.decided = true
+			return w.ResponseWriter.Write(p)
+		}
+		w.buf = append(w.buf, p...)
+		if len(w.buf) < compressMinBytes {
+			return len(p), nil
+		}
+		if err := w.startGzip(); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if w.gz == nil {
+		return w.ResponseWriter.Write(p)
+	}
+	return w.gz.Write(p)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush 主动刷新时不再等待缓冲达到阈值：已有数据即按压缩输出
+func (w *compressWriter) Flush() {
+	if !w.decided && len(w.buf) > 0 {
+		w.startGzip()
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap 供 http.ResponseController 访问底层连接（如放宽流式导出的写超时）
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// close 输出剩余缓冲（不足阈值时不压缩），写出 gzip 尾部并归还 Writer
+func (w *compressWriter) close() {
+	if !w.decided {
+		w.flushIdentity()
+	}
+	if w.gz == nil {
+		return
+	}
+	w.gz.Close()
+	gzipWriterPool.Put(w.gz)
+	w.gz = nil
+}
+
+// writeCached 输出缓存的响应体：客户端支持 gzip 时直接返回条目的预压缩结果，避免每次命中都重新压缩
+func writeCached(c *gin.Context, entry *cacheEntry, contentType string) {
+	c.Header("Content-Type", contentType)
+	if len(entry.data) >= compressMinBytes && acceptsGzip(c.GetHeader("Accept-Encoding")) {
+		gz := entry.gzipped()
+		if etag := c.Writer.Header().Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			c.Header("ETag", "W/"+etag)
+		}
+		c.Header("Content-Encoding", "gzip")
+		c.Header("Content-Length", strconv.Itoa(len(gz)))
+		c.Writer.Write(gz)
+		return
+	}
+	c.Header("Content-Length", strconv.Itoa(len(entry.data)))
+	c.Writer.Write(entry.data)
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]bool{
+		"":                      false,
+		"gzip":                  true,
+		"gzip, deflate, br":     true,
+		"deflate, br":           false,
+		"GZIP;q=0.5":            true,
+		"gzip;q=0":              false,
+		"*":                     true,
+		"*;q=0":                 false,
+		"gzip;q=0, *":           false,
+		"identity, *;q=0.1":     true,
+		"br;q=1.0, x-gzip;q=.8": true,
+	}
+	for header, want := range tests {
+		if got := acceptsGzip(header); got != want {
+			t.Errorf("acceptsGzip(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
+
+func gunzip(t *testing.T, data []byte) string {
+	t.Helper()
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("gunzip: %v", err)
+	}
+	return string(out)
+}
+
+func TestCompressionMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	large := `{"data":"` + strings.Repeat("x", 4096) + `"}`
+	cache := newStatusCache(time.Minute, 10)
+	loads := 0
+
+	router := gin.New()
+	router.Use(compressionMiddleware(regexp.MustCompile(`^/stream$`)))
+	router.GET("/large", func(c *gin.Context) { c.String(http.StatusOK, large) })
+	router.GET("/small", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) })
+	router.GET("/png", func(c *gin.Context) { c.Data(http.StatusOK, "image/png", []byte(large)) })
+	router.GET("/stream", func(c *gin.Context) { c.String(http.StatusOK, large) })
+	router.GET("/cached", func(c *gin.Context) {
+		entry, err := cache.loadEntry(context.Background(), "k", time.Minute, func() ([]byte, error) {
+			loads++
+			return []byte(large), nil
+		})
+		if err != nil {
+			t.Fatalf("loadEntry: %v", err)
+		}
+		writeCached(c, entry, "application/json; charset=utf-8")
+	})
+
+	serve := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("/large", "gzip")
+	if w.Header().Get("Content-Encoding") != "gzip" || gunzip(t, w.Body.Bytes()) != large {
+		t.Fatalf("expected gzip response, headers=%v", w.Header())
+	}
+	if w.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("expected Vary header, got %v", w.Header())
+	}
+	if w = serve("/large", "br"); w.Header().Get("Content-Encoding") != "" || w.Body.String() != large {
+		t.Fatalf("expected identity response without gzip support")
+	}
+	if w = serve("/small", "gzip"); w.Header().Get("Content-Encoding") != "" || w.Body.String() != `{"ok":true}` {
+		t.Fatalf("small response should not be compressed, headers=%v", w.Header())
+	}
+	for _, path := range []string{"/png", "/stream"} {
+		if w = serve(path, "gzip"); w.Header().Get("Content-Encoding") != "" || w.Body.String() != large {
+			t.Fatalf("%s should not be compressed, headers=%v", path, w.Header())
+		}
+	}
+
+	// 预压缩缓存：多次命中只加载、压缩一次，且不会被中间件重复压缩
+	var first []byte
+	for i := 0; i < 3; i++ {
+		w = serve("/cached", "gzip")
+		if w.Header().Get("Content-Encoding") != "gzip" || gunzip(t, w.Body.Bytes()) != large {
+			t.Fatalf("expected pre-compressed response, headers=%v", w.Header())
+		}
+		if first == nil {
+			first = w.Body.Bytes()
+		}
+	}
+	entry, _ := cache.get("k")
+	if loads != 1 || entry == nil || !bytes.Equal(entry.gz, first) {
+		t.Fatalf("expected single load with reused gzip bytes, loads=%d", loads)
+	}
+	if w = serve("/cached", ""); w.Header().Get("Content-Encoding") != "" || w.Body.String() != large {
+		t.Fatalf("expected identity cached response, headers=%v", w.Header())
+	}
+}
@@ -0,0 +1,253 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/buildinfo"
+	"monitor/internal/scheduler"
+)
+
+const (
+	// pprofMaxSeconds CPU profile / trace 的最长采集时间
+	pprofMaxSeconds = 300
+
+	// pprofWriteMargin 采集结束后写出结果的额外时间（服务端默认 WriteTimeout 为 15s）
+	pprofWriteMargin = 30 * time.Second
+)
+
+// processStart 进程启动时间（用于计算运行时长）
+var processStart = time.Now()
+
+// RuntimeStats 运行时统计（GET /api/admin/runtime 与独立诊断端口的 /debug/runtime）
+type RuntimeStats struct {
+	Now           int64  `json:"now"`            // 快照时间（Unix 秒）
+	UptimeSeconds int64  `json:"uptime_seconds"` // 进程运行时长
+	Version       string `json:"version"`
+	GoVersion     string `json:"go_version"`
+	NumCPU        int    `json:"num_cpu"`
+	GOMAXPROCS    int    `json:"gomaxprocs"`
+	Goroutines    int    `json:"goroutines"`
+	CgoCalls      int64  `json:"cgo_calls"`
+
+	Memory    RuntimeMemoryStats     `json:"memory"`
+	GC        RuntimeGCStats         `json:"gc"`
+	Scheduler *RuntimeSchedulerStats `json:"scheduler,omitempty"` // 调度器未就绪时省略
+}
+
+// RuntimeMemoryStats 内存统计（字节）
+type RuntimeMemoryStats struct {
+	Sys          uint64 `json:"sys"`           // 向操作系统申请的总内存
+	HeapAlloc    uint64 `json:"heap_alloc"`    // 堆上存活对象占用
+	HeapInuse    uint64 `json:"heap_inuse"`    // 使用中的堆 span
+	HeapIdle     uint64 `json:"heap_idle"`     // 空闲的堆 span
+	HeapReleased uint64 `json:"heap_released"` // 已归还操作系统的堆内存
+	HeapObjects  uint64 `json:"heap_objects"`  // 堆上存活对象数
+	StackInuse   uint64 `json:"stack_inuse"`   // goroutine 栈占用
+	TotalAlloc   uint64 `json:"total_alloc"`   // 累计分配量（只增不减）
+	Mallocs      uint64 `json:"mallocs"`       // 累计分配次数
+	Frees        uint64 `json:"frees"`         // 累计释放次数
+}
+
+// RuntimeGCStats GC 统计
+type RuntimeGCStats struct {
+	NumGC        uint32  `json:"num_gc"`
+	NumForcedGC  uint32  `json:"num_forced_gc"`
+	LastGC       int64   `json:"last_gc,omitempty"` // 最近一次 GC 时间（Unix 秒，尚未 GC 时省略）
+	NextGC       uint64  `json:"next_gc"`           // 下次 GC 的堆大小目标（字节）
+	PauseTotalMs float64 `json:"pause_total_ms"`
+	LastPauseMs  float64 `json:"last_pause_ms"`
+	CPUFraction  float64 `json:"cpu_fraction"` // 启动以来 GC 占用的 CPU 比例
+}
+
+// RuntimeSchedulerStats 探测调度器统计（用于判断巡检周期是否被拉长）
+type RuntimeSchedulerStats struct {
+	Tasks           int   `json:"tasks"`
+	Running         int   `json:"running"`
+	Inflight        int   `json:"inflight"`
+	MaxConcurrency  int   `json:"max_concurrency"`
+	Saturated       bool  `json:"saturated"`
+	AvgDurationMs   int64 `json:"avg_duration_ms"`    // 各任务最近一次探测耗时的平均值
+	MaxDurationMs   int64 `json:"max_duration_ms"`    // 各任务最近一次探测耗时的最大值
+	AvgStartDelayMs int64 `json:"avg_start_delay_ms"` // 最近 20 次探测开始的平均延迟
+	MaxStartDelayMs int64 `json:"max_start_delay_ms"` // 最近 20 次探测开始的最大延迟
+	LateStarts      int   `json:"late_starts"`        // 累计延迟开始次数
+	Overruns        int   `json:"overruns"`           // 累计周期超时次数
+	Panics          int64 `json:"panics"`             // 启动以来探测 panic 的总次数
+}
+
+// collectRuntimeStats 采集运行时统计（ReadMemStats 会短暂 stop-the-world，仅用于按需查询）
+func collectRuntimeStats(sched *scheduler.Scheduler, now time.Time) RuntimeStats {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	stats := RuntimeStats{
+		Now:           now.Unix(),
+		UptimeSeconds: int64(now.Sub(processStart).Seconds()),
+		Version:       buildinfo.GetVersion(),
+		GoVersion:     buildinfo.GetGoVersion(),
+		NumCPU:        runtime.NumCPU(),
+		GOMAXPROCS:    runtime.GOMAXPROCS(0),
+		Goroutines:    runtime.NumGoroutine(),
+		CgoCalls:      runtime.NumCgoCall(),
+		Memory: RuntimeMemoryStats{
+			Sys:          ms.Sys,
+			HeapAlloc:    ms.HeapAlloc,
+			HeapInuse:    ms.HeapInuse,
+			HeapIdle:     ms.HeapIdle,
+			HeapReleased: ms.HeapReleased,
+			HeapObjects:  ms.HeapObjects,
+			StackInuse:   ms.StackInuse,
+			TotalAlloc:   ms.TotalAlloc,
+			Mallocs:      ms.Mallocs,
+			Frees:        ms.Frees,
+		},
+		GC: RuntimeGCStats{
+			NumGC:        ms.NumGC,
+			NumForcedGC:  ms.NumForcedGC,
+			NextGC:       ms.NextGC,
+			PauseTotalMs: float64(ms.PauseTotalNs) / 1e6,
+			CPUFraction:  ms.GCCPUFraction,
+		},
+	}
+	if ms.NumGC > 0 {
+		stats.GC.LastGC = time.Unix(0, int64(ms.LastGC)).Unix()
+		stats.GC.LastPauseMs = float64(ms.PauseNs[(ms.NumGC+255)%256]) / 1e6
+	}
+	if sched != nil {
+		stats.Scheduler = buildRuntimeSchedulerStats(sched.Tasks(), sched.Saturation())
+		stats.Scheduler.Panics = sched.Panics()
+	}
+	return stats
+}
+
+// buildRuntimeSchedulerStats 汇总调度任务快照
+func buildRuntimeSchedulerStats(tasks []scheduler.TaskInfo, sat scheduler.SaturationInfo) *RuntimeSchedulerStats {
+	stats := &RuntimeSchedulerStats{
+		Tasks:           len(tasks),
+		Inflight:        sat.Inflight,
+		MaxConcurrency:  sat.MaxConcurrency,
+		Saturated:       sat.Saturated,
+		AvgStartDelayMs: sat.AvgStartDelay.Milliseconds(),
+		MaxStartDelayMs: sat.MaxStartDelay.Milliseconds(),
+	}
+	var total time.Duration
+	var runs int
+	for _, t := range tasks {
+		if t.Running {
+			stats.Running++
+		}
+		stats.LateStarts += t.LateStarts
+		stats.Overruns += t.Overruns
+		if t.LastRun.IsZero() {
+			continue
+		}
+		runs++
+		total += t.LastDuration
+		stats.MaxDurationMs = max(stats.MaxDurationMs, t.LastDuration.Milliseconds())
+	}
+	if runs > 0 {
+		stats.AvgDurationMs = (total / time.Duration(runs)).Milliseconds()
+	}
+	return stats
+}
+
+// GetAdminRuntime 查询运行时统计（goroutine、堆内存、GC、调度器）
+// GET /api/admin/runtime
+func (h *Handler) GetAdminRuntime(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, collectRuntimeStats(h.scheduler, time.Now()))
+}
+
+// GetAdminPprof 提供 net/http/pprof（需启用 diagnostics.pprof）
+// GET /api/admin/debug/pprof/             profile 列表
+// GET /api/admin/debug/pprof/heap         堆内存采样（goroutine、allocs、block、mutex 等同理）
+// GET /api/admin/debug/pprof/profile?seconds=30  CPU profile
+// GET /api/admin/debug/pprof/trace?seconds=5     执行追踪
+func (h *Handler) GetAdminPprof(c *gin.Context) {
+	h.cfgMu.RLock()
+	enabled := h.config.Diagnostics.Pprof
+	h.cfgMu.RUnlock()
+	if !enabled {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "pprof 未启用（需配置 diagnostics.pprof: true）",
+		})
+		return
+	}
+
+	name := strings.TrimPrefix(c.Param("name"), "/")
+	if name == "profile" || name == "trace" {
+		seconds := 30
+		if name == "trace" {
+			seconds = 1
+		}
+		if v := c.Query("seconds"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 || n > pprofMaxSeconds {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": fmt.Sprintf("无效的 seconds 参数: %s (支持 1-%d)", v, pprofMaxSeconds),
+				})
+				return
+			}
+			seconds = n
+		}
+		// 采集时间可能超过服务端 WriteTimeout：放宽本请求的写超时，
+		// 并去掉 context 中的 *http.Server，避免 pprof 按 WriteTimeout 拒绝长时间采集
+		deadline := time.Now().Add(time.Duration(seconds)*time.Second + pprofWriteMargin)
+		if err := http.NewResponseController(c.Writer).SetWriteDeadline(deadline); err == nil {
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), http.ServerContextKey, nil))
+		}
+	}
+
+	c.Header("Cache-Control", "no-store")
+	pprofHandler(name).ServeHTTP(c.Writer, c.Request)
+}
+
+// pprofHandler 按名称返回 pprof 处理器（空名称返回 profile 列表页）
+func pprofHandler(name string) http.Handler {
+	switch name {
+	case "":
+		// Index 仅在路径以 /debug/pprof/ 开头时解析名称，其余情况输出列表页（页面内为相对链接）
+		return http.HandlerFunc(pprof.Index)
+	case "cmdline":
+		return http.HandlerFunc(pprof.Cmdline)
+	case "profile":
+		return http.HandlerFunc(pprof.Profile)
+	case "symbol":
+		return http.HandlerFunc(pprof.Symbol)
+	case "trace":
+		return http.HandlerFunc(pprof.Trace)
+	default:
+		return pprof.Handler(name)
+	}
+}
+
+// newDiagnosticsMux 独立诊断端口的路由（不做鉴权，只应绑定本机或内网地址）
+// 路径与标准库 net/http/pprof、expvar 的默认路径一致，可直接使用 go tool pprof http://host:port/debug/pprof/heap
+func newDiagnosticsMux(h *Handler) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/debug/runtime", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(collectRuntimeStats(h.scheduler, time.Now()))
+	})
+	return mux
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/scheduler"
+)
+
+func TestGetAdminRuntime(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewHandler(nil, &config.AppConfig{})
+	router := gin.New()
+	router.GET("/api/admin/runtime", h.GetAdminRuntime)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/runtime", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var stats RuntimeStats
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if stats.Goroutines <= 0 || stats.Memory.HeapAlloc == 0 || stats.Memory.Sys == 0 || stats.GoVersion == "" {
+		t.Fatalf("unexpected runtime stats: %+v", stats)
+	}
+	if stats.Scheduler != nil {
+		t.Fatalf("scheduler stats should be omitted without scheduler: %+v", stats.Scheduler)
+	}
+}
+
+func TestBuildRuntimeSchedulerStats(t *testing.T) {
+	now := time.Now()
+	tasks := []scheduler.TaskInfo{
+		{Running: true, LastRun: now, LastDuration: 3 * time.Second, LateStarts: 2, Overruns: 1},
+		{LastRun: now, LastDuration: time.Second},
+		{}, // 尚未执行，不计入耗时
+	}
+	sat := scheduler.SaturationInfo{Saturated: true, Inflight: 1, MaxConcurrency: 4, AvgStartDelay: 6 * time.Second, MaxStartDelay: 9 * time.Second}
+
+	stats := buildRuntimeSchedulerStats(tasks, sat)
+	want := RuntimeSchedulerStats{
+		Tasks: 3, Running: 1, Inflight: 1, MaxConcurrency: 4, Saturated: true,
+		AvgDurationMs: 2000, MaxDurationMs: 3000, AvgStartDelayMs: 6000, MaxStartDelayMs: 9000,
+		LateStarts: 2, Overruns: 1,
+	}
+	if *stats != want {
+		t.Fatalf("unexpected scheduler stats:\n got %+v\nwant %+v", *stats, want)
+	}
+}
+
+func TestGetAdminPprof(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.AppConfig{}
+	h := NewHandler(nil, cfg)
+	router := gin.New()
+	router.GET("/api/admin/debug/pprof/*name", h.GetAdminPprof)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	// 默认关闭
+	if w := get("/api/admin/debug/pprof/"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 when disabled, got %d", w.Code)
+	}
+
+	enabled := cfg.Clone()
+	enabled.Diagnostics.Pprof = true
+	h.UpdateConfig(enabled)
+
+	if w := get("/api/admin/debug/pprof/"); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "goroutine") {
+		t.Fatalf("unexpected index: %d %s", w.Code, w.Body.String())
+	}
+	if w := get("/api/admin/debug/pprof/goroutine?debug=1"); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "goroutine profile") {
+		t.Fatalf("unexpected goroutine profile: %d %s", w.Code, w.Body.String())
+	}
+	if w := get("/api/admin/debug/pprof/heap"); w.Code != http.StatusOK || w.Body.Len() == 0 {
+		t.Fatalf("unexpected heap profile: %d", w.Code)
+	}
+	if w := get("/api/admin/debug/pprof/nope"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown profile, got %d", w.Code)
+	}
+	for _, q := range []string{"0", "abc", "301"} {
+		if w := get("/api/admin/debug/pprof/profile?seconds=" + q); w.Code != http.StatusBadRequest {
+			t.Fatalf("seconds=%s: expected 400, got %d", q, w.Code)
+		}
+	}
+}
+
+func TestDiagnosticsMux(t *testing.T) {
+	mux := newDiagnosticsMux(NewHandler(nil, &config.AppConfig{}))
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/goroutine?debug=1", "/debug/vars", "/debug/runtime"} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK || w.Body.Len() == 0 {
+			t.Fatalf("%s: unexpected response %d", path, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	var vars map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &vars); err != nil || vars["memstats"] == nil {
+		t.Fatalf("unexpected expvar output: %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// embedWidgetLabels 嵌入组件文案（按语言）
+type embedWidgetLabels struct {
+	Up       string `json:"up"`
+	Degraded string `json:"degraded"`
+	Down     string `json:"down"`
+	NoData   string `json:"no_data"`
+	Uptime   string `json:"uptime"`
+	Error    string `json:"error"`
+	PowerBy  string `json:"powered_by"`
+}
+
+// embedLabels 嵌入组件支持的语言（键为 lang 参数，与站点路径前缀一致，zh 为默认）
+var embedLabels = map[string]embedWidgetLabels{
+	"zh": {Up: "运行正常", Degraded: "性能下降", Down: "服务异常", NoData: "暂无数据", Uptime: "24h 可用率", Error: "状态加载失败", PowerBy: "RelayPulse 实时监测"},
+	"en": {Up: "Operational", Degraded: "Degraded", Down: "Outage", NoData: "No data", Uptime: "24h uptime", Error: "Failed to load status", PowerBy: "Monitored by RelayPulse"},
+	"ru": {Up: "Работает", Degraded: "Снижение качества", Down: "Сбой", NoData: "Нет данных", Uptime: "Доступность за 24ч", Error: "Не удалось загрузить статус", PowerBy: "Мониторинг RelayPulse"},
+	"ja": {Up: "正常稼働", Degraded: "パフォーマンス低下", Down: "障害発生", NoData: "データなし", Uptime: "24時間稼働率", Error: "ステータスを読み込めません", PowerBy: "RelayPulse で監視中"},
+}
+
+// embedWidgetData 嵌入组件模板数据
+type embedWidgetData struct {
+	Lang   string
+	Theme  string
+	Title  string
+	Config embedWidgetConfig
+}
+
+// embedWidgetConfig 注入到组件脚本的配置（html/template 在 JS 上下文中自动序列化为 JSON）
+type embedWidgetConfig struct {
+	Slug   string            `json:"slug"`
+	Name   string            `json:"name"`
+	Link   string            `json:"link"`
+	Labels embedWidgetLabels `json:"labels"`
+}
+
+// GetEmbedWidget 服务商状态嵌入组件
+// GET /embed/:slug?theme=light|dark|auto&lang=zh|en|ru|ja
+// 返回自包含的 HTML 页面（内联 CSS/JS，可被任意站点 iframe 嵌入），
+// 页面加载后请求 /api/providers/:slug 与 /api/status 渲染当前状态和 24h 可用率走势
+func (h *Handler) GetEmbedWidget(c *gin.Context) {
+	slug := strings.ToLower(strings.TrimSpace(c.Param("slug")))
+	if !isValidProviderSlug(slug) {
+		c.String(http.StatusNotFound, "provider not found")
+		return
+	}
+
+	theme := strings.ToLower(strings.TrimSpace(c.DefaultQuery("theme", "auto")))
+	if theme != "light" && theme != "dark" {
+		theme = "auto"
+	}
+	lang := strings.ToLower(strings.TrimSpace(c.DefaultQuery("lang", "zh")))
+	labels, ok := embedLabels[lang]
+	if !ok {
+		lang = "zh"
+		labels = embedLabels[lang]
+	}
+
+	h.cfgMu.RLock()
+	monitors := h.providerMonitors(slug, false)
+	baseURL := h.config.PublicBaseURL
+	h.cfgMu.RUnlock()
+
+	if len(monitors) == 0 {
+		c.String(http.StatusNotFound, "provider not found")
+		return
+	}
+
+	name := monitors[0].ProviderName
+	if name == "" {
+		name = monitors[0].Provider
+	}
+	link := baseURL + "/p/" + slug
+	if lang != "zh" {
+		link = fmt.Sprintf("%s/%s/p/%s", baseURL, lang, slug)
+	}
+
+	var buf bytes.Buffer
+	err := embedWidgetTemplate.Execute(&buf, embedWidgetData{
+		Lang:  lang,
+		Theme: theme,
+		Title: name + " - RelayPulse",
+		Config: embedWidgetConfig{
+			Slug:   slug,
+			Name:   name,
+			Link:   link,
+			Labels: labels,
+		},
+	})
+	if err != nil {
+		c.String(http.StatusInternalServerError, "failed to render widget")
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300") // 页面本身只含配置，数据由脚本实时请求
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// embedWidgetTemplate 嵌入组件页面
+// 走势图为 24 个小时桶的平均可用率（各通道/模型取平均），无数据的桶绘制为空隙
+var embedWidgetTemplate = template.Must(template.New("embed").Parse(`<!doctype html>
+<html lang="{{.Lang}}" data-theme="{{.Theme}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Title}}</title>
+<style>
+:root{--bg:#fff;--fg:#1f2937;--muted:#6b7280;--border:#e5e7eb;--up:#16a34a;--degraded:#ca8a04;--down:#dc2626;--none:#9ca3af}
+html[data-theme=dark]{--bg:#111827;--fg:#f3f4f6;--muted:#9ca3af;--border:#374151}
+@media (prefers-color-scheme:dark){html[data-theme=auto]{--bg:#111827;--fg:#f3f4f6;--muted:#9ca3af;--border:#374151}}
+*{box-sizing:border-box}
+body{margin:0;font:14px/1.4 -apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,"PingFang SC","Microsoft YaHei",sans-serif;background:transparent;color:var(--fg)}
+a.rp{display:block;padding:12px 14px;border:1px solid var(--border);border-radius:10px;background:var(--bg);color:inherit;text-decoration:none}
+.head{display:flex;align-items:center;justify-content:space-between;gap:8px}
+.name{font-weight:600;overflow:hidden;text-overflow:ellipsis;white-space:nowrap}
+.status{display:flex;align-items:center;gap:6px;font-size:13px;white-space:nowrap}
+.dot{width:9px;height:9px;border-radius:50%;background:var(--none)}
+.spark{display:block;width:100%;height:28px;margin:10px 0 6px}
+.foot{display:flex;justify-content:space-between;font-size:12px;color:var(--muted)}
+</style>
+</head>
+<body>
+<a class="rp" id="rp" target="_blank" rel="noopener">
+<div class="head"><span class="name" id="rp-name"></span><span class="status"><span class="dot" id="rp-dot"></span><span id="rp-status"></span></span></div>
+<svg class="spark" id="rp-spark" viewBox="0 0 240 28" preserveAspectRatio="none" aria-hidden="true"></svg>
+<div class="foot"><span id="rp-uptime"></span><span id="rp-brand"></span></div>
+</a>
+<script>
+(function(){
+  var cfg = {{.Config}};
+  var L = cfg.labels;
+  var colors = {"1":"var(--up)","2":"var(--degraded)","0":"var(--down)","-1":"var(--none)"};
+  var texts = {"1":L.up,"2":L.degraded,"0":L.down,"-1":L.no_data};
+  function $(id){return document.getElementById(id);}
+  $("rp").href = cfg.link;
+  $("rp-name").textContent = cfg.name;
+  $("rp-brand").textContent = L.powered_by;
+  $("rp-status").textContent = L.no_data;
+
+  function get(url){
+    return fetch(url, {headers:{"Accept":"application/json"}}).then(function(r){
+      if(!r.ok){throw new Error("HTTP "+r.status);}
+      return r.json();
+    });
+  }
+
+  function sparkline(resp){
+    var lines = [];
+    (resp.data||[]).forEach(function(m){lines.push(m.timeline||[]);});
+    (resp.groups||[]).forEach(function(g){(g.layers||[]).forEach(function(l){lines.push(l.timeline||[]);});});
+    var n = 0;
+    lines.forEach(function(t){n = Math.max(n, t.length);});
+    if(!n){return;}
+    var w = 240/n, svg = "";
+    for(var i=0;i<n;i++){
+      var sum = 0, cnt = 0;
+      lines.forEach(function(t){var p = t[i]; if(p && p.availability >= 0){sum += p.availability; cnt++;}});
+      if(!cnt){continue;}
+      var avg = sum/cnt, h = Math.max(2, avg/100*28);
+      var color = avg >= 99 ? colors["1"] : (avg >= 90 ? colors["2"] : colors["0"]);
+      svg += '<rect x="'+(i*w+0.5).toFixed(2)+'" y="'+(28-h).toFixed(2)+'" width="'+Math.max(1,w-1).toFixed(2)+'" height="'+h.toFixed(2)+'" rx="1" style="fill:'+color+'"/>';
+    }
+    $("rp-spark").innerHTML = svg;
+  }
+
+  var slug = encodeURIComponent(cfg.slug);
+  get("/api/providers/"+slug).then(function(p){
+    var s = String(p.status);
+    $("rp-dot").style.background = colors[s] || colors["-1"];
+    $("rp-status").textContent = texts[s] || L.no_data;
+    $("rp-uptime").textContent = L.uptime + " " + (p.uptime >= 0 ? p.uptime.toFixed(2)+"%" : "--");
+  }).catch(function(){
+    $("rp-status").textContent = L.error;
+  });
+  get("/api/status?provider="+slug+"&period=24h&board=all").then(sparkline).catch(function(){});
+})();
+</script>
+</body>
+</html>
+`))
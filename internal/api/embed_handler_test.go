@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+)
+
+func TestGetEmbedWidget(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &Handler{
+		config: &config.AppConfig{
+			PublicBaseURL: "https://relaypulse.top",
+			Monitors: []config.ServiceConfig{
+				{Provider: "Relay", ProviderName: "Relay</script>", ProviderSlug: "relay", Service: "cc"},
+				{Provider: "Hidden", Service: "cc", Hidden: true},
+			},
+		},
+	}
+	router := gin.New()
+	router.GET("/embed/:slug", h.GetEmbedWidget)
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	w := serve("/embed/relay?theme=dark&lang=en")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, `data-theme="dark"`) || !strings.Contains(body, "Operational") {
+		t.Fatalf("theme/lang not applied: %s", body)
+	}
+	if !strings.Contains(body, `https://relaypulse.top/en/p/relay`) {
+		t.Fatalf("expected localized provider link in widget config")
+	}
+	if strings.Contains(body, "Relay</script>") {
+		t.Fatalf("provider name must be escaped inside script")
+	}
+
+	if w := serve("/embed/relay?theme=neon&lang=xx"); !strings.Contains(w.Body.String(), `data-theme="auto"`) || !strings.Contains(w.Body.String(), "运行正常") {
+		t.Fatalf("invalid theme/lang should fall back to defaults")
+	}
+	if w := serve("/embed/hidden"); w.Code != http.StatusNotFound {
+		t.Fatalf("hidden provider should return 404, got %d", w.Code)
+	}
+	if w := serve("/embed/Bad_Slug"); w.Code != http.StatusNotFound {
+		t.Fatalf("invalid slug should return 404, got %d", w.Code)
+	}
+}
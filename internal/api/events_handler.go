@@ -1,7 +1,10 @@
 package api
 
 import (
+	"context"
 	"crypto/subtle"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -31,6 +34,9 @@ type EventItem struct {
 	ObservedAt      int64          `json:"observed_at"`
 	CreatedAt       int64          `json:"created_at"`
 	Meta            map[string]any `json:"meta,omitempty"`
+
+	// Annotations 覆盖事件观测时间的运维标注
+	Annotations []AnnotationItem `json:"annotations,omitempty"`
 }
 
 // EventsMeta 事件列表元数据
@@ -48,6 +54,7 @@ type LatestEventResponse struct {
 
 // GetEvents 获取事件列表
 // GET /api/events?since_id=0&limit=20&provider=xxx&service=xxx&channel=xxx&types=DOWN,UP
+// GET /api/{ns}/events 返回指定命名空间的事件；启用多命名空间时 /api/events 仅返回默认命名空间的事件
 // limit 默认 20，最大 100
 func (h *Handler) GetEvents(c *gin.Context) {
 	// 检查 API Token（如果配置了）
@@ -67,39 +74,55 @@ func (h *Handler) GetEvents(c *gin.Context) {
 		limit = 100
 	}
 
-	// 构建过滤器
+	// 构建过滤器（启用多命名空间时仅返回本命名空间的事件）
 	var filters *storage.EventFilters
+	namespace := h.eventNamespace()
 	provider := c.Query("provider")
 	service := c.Query("service")
 	channel := c.Query("channel")
 	typesStr := c.Query("types")
 
-	if provider != "" || service != "" || channel != "" || typesStr != "" {
+	if namespace != nil || provider != "" || service != "" || channel != "" || typesStr != "" {
 		filters = &storage.EventFilters{
-			Provider: provider,
-			Service:  service,
-			Channel:  channel,
+			Namespace: namespace,
+			Provider:  provider,
+			Service:   service,
+			Channel:   channel,
 		}
 
 		if typesStr != "" {
-			types := strings.Split(typesStr, ",")
-			for _, t := range types {
-				t = strings.TrimSpace(t)
-				if t == "DOWN" || t == "UP" {
-					filters.Types = append(filters.Types, storage.EventType(t))
-				}
-			}
+			filters.Types = parseEventTypes(typesStr)
 		}
 	}
 
-	// 查询事件
-	events, err := h.storage.GetStatusEvents(sinceID, limit+1, filters)
+	// 缓存（cache_ttl.events.ttl，默认不缓存；配置了 bypass 的服务商直接查询）
+	h.cfgMu.RLock()
+	cacheTTL := h.config.CacheTTL.Events.TTLDuration
+	if h.cacheBypassed(provider) {
+		cacheTTL = 0
+	}
+	h.cfgMu.RUnlock()
+
+	cacheKey := fmt.Sprintf("events|since=%d|limit=%d|prov=%s|svc=%s|ch=%s|types=%s", sinceID, limit, provider, service, channel, typesStr)
+	reqCtx := context.WithoutCancel(c.Request.Context())
+	entry, err := h.eventsCache.loadEntry(reqCtx, cacheKey, cacheTTL, func() ([]byte, error) {
+		return h.queryEvents(reqCtx, sinceID, limit, filters)
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "查询事件失败",
 		})
 		return
 	}
+	writeNegotiated(c, entry, pbEventsResponse)
+}
+
+// queryEvents 查询 since_id 之后的事件并序列化为 EventsResponse（缓存 miss 时调用）
+func (h *Handler) queryEvents(ctx context.Context, sinceID int64, limit int, filters *storage.EventFilters) ([]byte, error) {
+	events, err := h.storage.GetStatusEvents(sinceID, limit+1, filters)
+	if err != nil {
+		return nil, err
+	}
 
 	// 判断是否还有更多
 	hasMore := len(events) > limit
@@ -116,8 +139,13 @@ func (h *Handler) GetEvents(c *gin.Context) {
 	}
 
 	// 构建响应
+	annotations := h.loadEventAnnotations(ctx, events)
 	items := make([]EventItem, 0, len(events))
 	for _, e := range events {
+		var notes []AnnotationItem
+		for _, a := range eventAnnotations(e, annotations) {
+			notes = append(notes, toAnnotationItem(a))
+		}
 		items = append(items, EventItem{
 			ID:              e.ID,
 			Provider:        e.Provider,
@@ -131,10 +159,11 @@ func (h *Handler) GetEvents(c *gin.Context) {
 			ObservedAt:      e.ObservedAt,
 			CreatedAt:       e.CreatedAt,
 			Meta:            e.Meta,
+			Annotations:     notes,
 		})
 	}
 
-	c.JSON(http.StatusOK, EventsResponse{
+	return json.Marshal(EventsResponse{
 		Events: items,
 		Meta: EventsMeta{
 			NextSinceID: nextSinceID,
@@ -144,6 +173,22 @@ func (h *Handler) GetEvents(c *gin.Context) {
 	})
 }
 
+// parseEventTypes 解析逗号分隔的事件类型列表，忽略未知类型
+func parseEventTypes(typesStr string) []storage.EventType {
+	var types []storage.EventType
+	for _, t := range strings.Split(typesStr, ",") {
+		t = strings.TrimSpace(t)
+		switch storage.EventType(t) {
+		case storage.EventTypeDown, storage.EventTypeUp, storage.EventTypeCertExpiring, storage.EventTypeContentDrift,
+			storage.EventTypeDegradedStart, storage.EventTypeDegradedEnd, storage.EventTypeSchedulerSaturated,
+			storage.EventTypeSLABreachWarning, storage.EventTypeSLABreached,
+			storage.EventTypeBoardDemoted, storage.EventTypeBoardPromoted:
+			types = append(types, storage.EventType(t))
+		}
+	}
+	return types
+}
+
 // GetLatestEventID 获取最新事件ID
 // GET /api/events/latest
 func (h *Handler) GetLatestEventID(c *gin.Context) {
@@ -173,10 +218,17 @@ func (h *Handler) checkEventsAPIToken(c *gin.Context) bool {
 	apiToken := h.config.Events.APIToken
 	h.cfgMu.RUnlock()
 
+	return checkBearerToken(c, apiToken, "events API 未配置，请设置 EVENTS_API_TOKEN 环境变量")
+}
+
+// checkBearerToken 校验 Authorization: Bearer <token> 请求头
+// apiToken 为空时返回 503（notConfiguredMsg 作为错误信息）
+// 返回 true 表示验证通过，false 表示验证失败（已返回错误响应）
+func checkBearerToken(c *gin.Context, apiToken, notConfiguredMsg string) bool {
 	// 未配置 token 时拒绝所有请求
 	if apiToken == "" {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"error": "events API 未配置，请设置 EVENTS_API_TOKEN 环境变量",
+			"error": notConfiguredMsg,
 		})
 		return false
 	}
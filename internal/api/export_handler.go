@@ -0,0 +1,404 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+const (
+	// exportPageSize 每次从存储读取的记录数；写出并 Flush 后才读取下一页，客户端读取慢时自然限速
+	exportPageSize = 1000
+
+	// exportMaxDuration 单次导出的最长写出时间（服务端默认 WriteTimeout 为 15s）
+	exportMaxDuration = 30 * time.Minute
+
+	// exportDefaultRange 未指定 from 时的默认时间跨度
+	exportDefaultRange = 24 * time.Hour
+)
+
+// exportColumns CSV 表头（与 JSON Lines 字段名一致）
+var exportColumns = []string{
+	"timestamp", "provider", "service", "channel", "model", "status", "sub_status",
+	"http_code", "latency", "dns_ms", "connect_ms", "tls_ms", "ttfb_ms", "attempts", "ip_family",
+}
+
+// exportMetadataColumns metadata=true 时追加的 CSV 列（记录时刻生效的监测项元数据）
+var exportMetadataColumns = []string{
+	"provider_name", "provider_slug", "sponsor", "sponsor_url", "price_min", "price_max", "badges",
+}
+
+// exportRecord JSON Lines 导出的单条记录
+type exportRecord struct {
+	Timestamp int64  `json:"timestamp"`
+	Provider  string `json:"provider"`
+	Service   string `json:"service"`
+	Channel   string `json:"channel"`
+	Model     string `json:"model"`
+	Status    int    `json:"status"`
+	SubStatus string `json:"sub_status"`
+	HttpCode  int    `json:"http_code"`
+	Latency   int    `json:"latency"`
+	DNSMs     *int   `json:"dns_ms"`
+	ConnectMs *int   `json:"connect_ms"`
+	TLSMs     *int   `json:"tls_ms"`
+	TTFBMs    *int   `json:"ttfb_ms"`
+	Attempts  int    `json:"attempts"`  // 实际请求次数（含重试，0 表示未记录）
+	IPFamily  string `json:"ip_family"` // 承载请求的连接地址族（ipv4 / ipv6，空表示未知）
+
+	// 记录时刻生效的监测项元数据（仅 metadata=true 且存在对应版本时输出）
+	Metadata *exportMetadata `json:"metadata,omitempty"`
+}
+
+// exportMetadata 导出记录关联的元数据版本
+type exportMetadata struct {
+	ProviderName string   `json:"provider_name"`
+	ProviderSlug string   `json:"provider_slug"`
+	Sponsor      string   `json:"sponsor"`
+	SponsorURL   string   `json:"sponsor_url"`
+	PriceMin     *float64 `json:"price_min"`
+	PriceMax     *float64 `json:"price_max"`
+	Badges       []string `json:"badges"`
+	ValidFrom    int64    `json:"valid_from"` // 该版本开始生效的时间（Unix 秒）
+}
+
+// exportWriter 按格式写出记录（meta 为记录时刻生效的元数据版本，未请求或无版本时为 nil）
+type exportWriter interface {
+	write(r *storage.ProbeRecord, meta *storage.MonitorMetadata) error
+	flush() error
+}
+
+type csvExportWriter struct {
+	w            *csv.Writer
+	withMetadata bool
+}
+
+func (e *csvExportWriter) write(r *storage.ProbeRecord, meta *storage.MonitorMetadata) error {
+	row := []string{
+		strconv.FormatInt(r.Timestamp, 10), r.Provider, r.Service, r.Channel, r.Model,
+		strconv.Itoa(r.Status), string(r.SubStatus), strconv.Itoa(r.HttpCode), strconv.Itoa(r.Latency),
+		formatOptionalInt(r.DNSMs), formatOptionalInt(r.ConnectMs), formatOptionalInt(r.TLSMs), formatOptionalInt(r.TTFBMs),
+		strconv.Itoa(r.Attempts), r.IPFamily,
+	}
+	if e.withMetadata {
+		if meta != nil {
+			row = append(row, meta.ProviderName, meta.ProviderSlug, meta.Sponsor, meta.SponsorURL,
+				formatOptionalFloat(meta.PriceMin), formatOptionalFloat(meta.PriceMax), strings.Join(meta.Badges, ";"))
+		} else {
+			row = append(row, make([]string, len(exportMetadataColumns))...)
+		}
+	}
+	return e.w.Write(row)
+}
+
+func (e *csvExportWriter) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type jsonlExportWriter struct {
+	enc *json.Encoder
+}
+
+func (e *jsonlExportWriter) write(r *storage.ProbeRecord, meta *storage.MonitorMetadata) error {
+	rec := exportRecord{
+		Timestamp: r.Timestamp,
+		Provider:  r.Provider,
+		Service:   r.Service,
+		Channel:   r.Channel,
+		Model:     r.Model,
+		Status:    r.Status,
+		SubStatus: string(r.SubStatus),
+		HttpCode:  r.HttpCode,
+		Latency:   r.Latency,
+		DNSMs:     r.DNSMs,
+		ConnectMs: r.ConnectMs,
+		TLSMs:     r.TLSMs,
+		TTFBMs:    r.TTFBMs,
+		Attempts:  r.Attempts,
+		IPFamily:  r.IPFamily,
+	}
+	if meta != nil {
+		rec.Metadata = &exportMetadata{
+			ProviderName: meta.ProviderName,
+			ProviderSlug: meta.ProviderSlug,
+			Sponsor:      meta.Sponsor,
+			SponsorURL:   meta.SponsorURL,
+			PriceMin:     meta.PriceMin,
+			PriceMax:     meta.PriceMax,
+			Badges:       meta.Badges,
+			ValidFrom:    meta.ValidFrom,
+		}
+	}
+	return e.enc.Encode(rec)
+}
+
+func (e *jsonlExportWriter) flush() error { return nil }
+
+// formatOptionalInt 空值输出为空字符串（pandas 读取为 NaN）
+func formatOptionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+// formatOptionalFloat 空值输出为空字符串
+func formatOptionalFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+// parseExportTime 解析导出时间参数：Unix 秒、RFC3339 或 YYYY-MM-DD（UTC 零点）
+func parseExportTime(value string) (time.Time, error) {
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(n, 0), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("无效的时间: %s（支持 Unix 秒、RFC3339、YYYY-MM-DD）", value)
+}
+
+// selectExportKeys 按过滤条件选出要导出的监测项（排除已禁用项，非管理员排除隐藏项）
+// provider 匹配 provider 名称或 slug（不区分大小写），service/channel/model 精确匹配
+func selectExportKeys(monitors []config.ServiceConfig, provider, service, channel, model string, includeHidden bool) []storage.MonitorKey {
+	provider = strings.ToLower(provider)
+	seen := make(map[storage.MonitorKey]bool)
+	var keys []storage.MonitorKey
+	for _, m := range monitors {
+		if m.Disabled || (m.Hidden && !includeHidden) {
+			continue
+		}
+		if provider != "" && provider != m.ProviderSlug && provider != strings.ToLower(strings.TrimSpace(m.Provider)) {
+			continue
+		}
+		if (service != "" && service != m.Service) || (channel != "" && channel != m.Channel) || (model != "" && model != m.Model) {
+			continue
+		}
+		key := storage.MonitorKey{Provider: m.Provider, Service: m.Service, Channel: m.Channel, Model: m.Model}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// GetExport 流式导出原始探测记录
+// GET /api/export?provider=xxx&service=xxx&channel=xxx&model=xxx&from=2026-01-01&to=2026-01-08&format=csv|jsonl&limit=1000&metadata=true
+// from/to 支持 Unix 秒、RFC3339、YYYY-MM-DD（UTC），区间左闭右开，默认最近 24 小时
+// metadata=true 时按记录时间关联当时生效的监测项元数据（服务商名称、赞助、价格、徽标）
+// 超过 export.max_range 的范围需携带管理 API 令牌；行数超过上限时截断（trailer X-Export-Truncated: true）
+func (h *Handler) GetExport(c *gin.Context) {
+	h.cfgMu.RLock()
+	exportCfg := h.config.Export
+	adminToken := h.config.Audit.APIToken
+	monitors := h.config.Monitors
+	h.cfgMu.RUnlock()
+
+	if !exportCfg.Enabled {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "export API 未启用",
+		})
+		return
+	}
+
+	format := strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", "csv")))
+	switch format {
+	case "csv", "jsonl":
+	case "parquet":
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "暂不支持 parquet 格式（支持: csv, jsonl）",
+		})
+		return
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("无效的 format 参数: %s (支持: csv, jsonl)", format),
+		})
+		return
+	}
+
+	until := time.Now()
+	if v := strings.TrimSpace(c.Query("to")); v != "" {
+		t, err := parseExportTime(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to 参数" + err.Error()})
+			return
+		}
+		until = t
+	}
+	since := until.Add(-exportDefaultRange)
+	if v := strings.TrimSpace(c.Query("from")); v != "" {
+		t, err := parseExportTime(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from 参数" + err.Error()})
+			return
+		}
+		since = t
+	}
+	if !since.Before(until) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "from 必须早于 to",
+		})
+		return
+	}
+
+	// 携带 Authorization 时按管理 API 令牌校验，获得更大的时间范围与行数上限
+	isAdmin := c.GetHeader("Authorization") != ""
+	if isAdmin && !checkBearerToken(c, adminToken, "admin API 未配置，请设置 ADMIN_API_TOKEN 环境变量") {
+		h.recordAdminCall(c, "anonymous")
+		return
+	}
+	if !isAdmin && until.Sub(since) > exportCfg.MaxRangeDuration {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": fmt.Sprintf("时间范围超过 %s，需携带管理 API 令牌（Authorization: Bearer <ADMIN_API_TOKEN>）", exportCfg.MaxRange),
+		})
+		return
+	}
+
+	maxRows := exportCfg.MaxRows
+	if isAdmin {
+		maxRows = exportCfg.AdminMaxRows
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("无效的 limit 参数: %s", v),
+			})
+			return
+		}
+		maxRows = min(maxRows, n)
+	}
+
+	keys := selectExportKeys(monitors,
+		strings.TrimSpace(c.Query("provider")), strings.TrimSpace(c.Query("service")),
+		strings.TrimSpace(c.Query("channel")), strings.TrimSpace(c.Query("model")), isAdmin)
+	if len(keys) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "未找到匹配的监测项",
+		})
+		return
+	}
+
+	scopedStore := h.storage.WithContext(c.Request.Context())
+	es, ok := scopedStore.(storage.ExportStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持数据导出",
+		})
+		return
+	}
+	var ms storage.MonitorMetadataStorage
+	if c.Query("metadata") == "true" {
+		if ms, ok = scopedStore.(storage.MonitorMetadataStorage); !ok {
+			c.JSON(http.StatusNotImplemented, gin.H{
+				"error": "当前存储后端不支持元数据版本",
+			})
+			return
+		}
+	}
+
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(exportMaxDuration)); err != nil {
+		logger.Warn("api", "设置导出写超时失败", "error", err)
+	}
+
+	filename := fmt.Sprintf("relaypulse-export-%s-%s.%s", since.UTC().Format("20060102T150405Z"), until.UTC().Format("20060102T150405Z"), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Accel-Buffering", "no") // 禁用 Nginx 缓冲
+	c.Header("X-Export-Row-Limit", strconv.Itoa(maxRows))
+	c.Header("Trailer", "X-Export-Rows, X-Export-Truncated")
+
+	var out exportWriter
+	if format == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		cw := csv.NewWriter(c.Writer)
+		header := exportColumns
+		if ms != nil {
+			header = append(slices.Clone(exportColumns), exportMetadataColumns...)
+		}
+		_ = cw.Write(header)
+		out = &csvExportWriter{w: cw, withMetadata: ms != nil}
+	} else {
+		c.Header("Content-Type", "application/x-ndjson")
+		out = &jsonlExportWriter{enc: json.NewEncoder(c.Writer)}
+	}
+	c.Status(http.StatusOK)
+
+	rows, truncated, err := streamExport(c, es, ms, keys, since, until, maxRows, out)
+	if err != nil {
+		// 响应头已发送，只能中断输出；客户端可通过缺失的 trailer 判断导出不完整
+		logger.Warn("api", "导出中断", "rows", rows, "error", err)
+		return
+	}
+	c.Writer.Header().Set("X-Export-Rows", strconv.Itoa(rows))
+	c.Writer.Header().Set("X-Export-Truncated", strconv.FormatBool(truncated))
+
+	if isAdmin {
+		h.recordAdminCall(c, adminActor)
+	}
+}
+
+// streamExport 逐个监测项分页读取并写出记录，返回写出行数与是否因行数上限截断
+// ms 非 nil 时为每条记录关联记录时刻生效的元数据版本
+func streamExport(c *gin.Context, es storage.ExportStorage, ms storage.MonitorMetadataStorage, keys []storage.MonitorKey, since, until time.Time, maxRows int, out exportWriter) (int, bool, error) {
+	rows := 0
+	for _, key := range keys {
+		var versions []*storage.MonitorMetadata
+		if ms != nil {
+			var err error
+			if versions, err = ms.GetMonitorMetadataHistory(key, since.Unix(), until.Unix()); err != nil {
+				return rows, false, err
+			}
+		}
+		cursor := storage.HistoryCursor{Timestamp: since.Unix()}
+		for {
+			if err := c.Request.Context().Err(); err != nil {
+				return rows, false, err
+			}
+			page, err := es.GetHistoryPage(key, cursor, until, min(exportPageSize, maxRows-rows+1))
+			if err != nil {
+				return rows, false, err
+			}
+			for _, r := range page {
+				if rows == maxRows {
+					return rows, true, out.flush()
+				}
+				if err := out.write(r, storage.MonitorMetadataAt(versions, r.Timestamp)); err != nil {
+					return rows, false, err
+				}
+				rows++
+			}
+			if err := out.flush(); err != nil {
+				return rows, false, err
+			}
+			c.Writer.Flush()
+
+			if len(page) < exportPageSize {
+				break
+			}
+			last := page[len(page)-1]
+			cursor = storage.HistoryCursor{Timestamp: last.Timestamp, ID: last.ID}
+		}
+	}
+	return rows, false, nil
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestGetExport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newTestSQLiteStore(t)
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	// relay 的记录数超过一页，且存在同秒记录，覆盖游标翻页
+	for i := 0; i < exportPageSize+200; i++ {
+		record := &storage.ProbeRecord{Provider: "Relay", Service: "cc", Channel: "vip", Status: 1, HttpCode: 200, Latency: 100, Timestamp: base + int64(i/2)}
+		if err := store.SaveRecord(record); err != nil {
+			t.Fatalf("save record: %v", err)
+		}
+	}
+	for _, r := range []*storage.ProbeRecord{
+		{Provider: "Other", Service: "cx", Status: 0, SubStatus: storage.SubStatusServerError, HttpCode: 502, Timestamp: base + 10},
+		{Provider: "Hidden", Service: "cc", Status: 1, HttpCode: 200, Timestamp: base + 10},
+	} {
+		if err := store.SaveRecord(r); err != nil {
+			t.Fatalf("save record: %v", err)
+		}
+	}
+
+	cfg := &config.AppConfig{
+		Audit:  config.AuditConfig{APIToken: "admin-token"},
+		Export: config.ExportConfig{Enabled: true},
+		Monitors: []config.ServiceConfig{
+			{Provider: "Relay", ProviderSlug: "relay", Service: "cc", Channel: "vip"},
+			{Provider: "Other", ProviderSlug: "other", Service: "cx"},
+			{Provider: "Hidden", ProviderSlug: "hidden", Service: "cc", Hidden: true},
+		},
+	}
+	if err := cfg.Export.Normalize(); err != nil {
+		t.Fatalf("normalize export: %v", err)
+	}
+	h := NewHandler(store, cfg)
+	router := gin.New()
+	router.GET("/api/export", h.GetExport)
+
+	serve := func(target, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	from := strconv.FormatInt(base, 10)
+	to := strconv.FormatInt(base+86400, 10)
+
+	t.Run("csv", func(t *testing.T) {
+		w := serve("/api/export?from="+from+"&to="+to, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		rows, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+		if err != nil {
+			t.Fatalf("parse csv: %v", err)
+		}
+		if strings.Join(rows[0], ",") != strings.Join(exportColumns, ",") {
+			t.Fatalf("unexpected header: %v", rows[0])
+		}
+		if got := len(rows) - 1; got != exportPageSize+201 {
+			t.Fatalf("expected %d rows (hidden excluded), got %d", exportPageSize+201, got)
+		}
+		if trailer := w.Result().Trailer; trailer.Get("X-Export-Rows") != strconv.Itoa(exportPageSize+201) || trailer.Get("X-Export-Truncated") != "false" {
+			t.Fatalf("unexpected trailer: %v", trailer)
+		}
+	})
+
+	t.Run("jsonl filter", func(t *testing.T) {
+		w := serve("/api/export?format=jsonl&provider=other&from=2026-01-01&to=2026-01-02", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var records []exportRecord
+		sc := bufio.NewScanner(strings.NewReader(w.Body.String()))
+		for sc.Scan() {
+			var r exportRecord
+			if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+				t.Fatalf("decode line %q: %v", sc.Text(), err)
+			}
+			records = append(records, r)
+		}
+		if len(records) != 1 || records[0].Provider != "Other" || records[0].SubStatus != "server_error" || records[0].HttpCode != 502 {
+			t.Fatalf("unexpected records: %+v", records)
+		}
+	})
+
+	t.Run("row limit", func(t *testing.T) {
+		w := serve("/api/export?provider=relay&limit=5&from="+from+"&to="+to, "")
+		if lines := strings.Count(w.Body.String(), "\n"); lines != 6 {
+			t.Fatalf("expected header + 5 rows, got %d lines", lines)
+		}
+		if w.Result().Trailer.Get("X-Export-Truncated") != "true" {
+			t.Fatalf("expected truncated trailer, got %v", w.Result().Trailer)
+		}
+	})
+
+	t.Run("large range requires admin token", func(t *testing.T) {
+		target := "/api/export?provider=hidden&from=2025-12-01&to=2026-01-02"
+		if w := serve(target, ""); w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", w.Code)
+		}
+		if w := serve(target, "wrong"); w.Code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", w.Code)
+		}
+		w := serve(target, "admin-token")
+		if w.Code != http.StatusOK || strings.Count(w.Body.String(), "\n") != 2 {
+			t.Fatalf("expected hidden monitor export for admin, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("metadata join", func(t *testing.T) {
+		price := 0.2
+		renamed := []config.ServiceConfig{{Provider: "Relay", ProviderName: "Relay Old", ProviderSlug: "relay", Service: "cc", Channel: "vip"}}
+		if n, err := storage.SyncMonitorMetadata(store, renamed, time.Unix(base-100, 0)); err != nil || n != 1 {
+			t.Fatalf("sync metadata: n=%d err=%v", n, err)
+		}
+		if n, _ := storage.SyncMonitorMetadata(store, renamed, time.Unix(base-50, 0)); n != 0 {
+			t.Fatalf("unchanged metadata should not create a version, got %d", n)
+		}
+		renamed[0].ProviderName = "Relay New"
+		renamed[0].PriceMax = &price
+		renamed[0].ResolvedBadges = []config.ResolvedBadge{{ID: "promo"}}
+		if n, err := storage.SyncMonitorMetadata(store, renamed, time.Unix(base+100, 0)); err != nil || n != 1 {
+			t.Fatalf("sync metadata: n=%d err=%v", n, err)
+		}
+
+		w := serve("/api/export?format=jsonl&metadata=true&provider=relay&from="+from+"&to="+strconv.FormatInt(base+200, 10), "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		sc := bufio.NewScanner(strings.NewReader(w.Body.String()))
+		for sc.Scan() {
+			var r exportRecord
+			if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+				t.Fatalf("decode line %q: %v", sc.Text(), err)
+			}
+			want := "Relay Old"
+			if r.Timestamp >= base+100 {
+				want = "Relay New"
+			}
+			if r.Metadata == nil || r.Metadata.ProviderName != want {
+				t.Fatalf("record at %d: expected %s, got %+v", r.Timestamp, want, r.Metadata)
+			}
+		}
+
+		w = serve("/api/export?metadata=true&provider=relay&limit=1&from="+strconv.FormatInt(base+150, 10)+"&to="+to, "")
+		rows, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+		if err != nil || len(rows) != 2 {
+			t.Fatalf("parse csv: rows=%d err=%v", len(rows), err)
+		}
+		if got := strings.Join(rows[1][len(exportColumns):], ","); got != "Relay New,relay,,,,0.2,promo" {
+			t.Fatalf("unexpected metadata columns: %s", got)
+		}
+
+		// 未请求 metadata 时格式不变
+		if w := serve("/api/export?format=jsonl&provider=relay&limit=1&from="+from+"&to="+to, ""); strings.Contains(w.Body.String(), "metadata") {
+			t.Fatalf("metadata should be omitted by default: %s", w.Body.String())
+		}
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for target, code := range map[string]int{
+			"/api/export?format=parquet":                              http.StatusBadRequest,
+			"/api/export?format=xml":                                  http.StatusBadRequest,
+			"/api/export?from=yesterday":                              http.StatusBadRequest,
+			"/api/export?from=2026-01-02&to=2026-01-01":               http.StatusBadRequest,
+			"/api/export?limit=0":                                     http.StatusBadRequest,
+			"/api/export?provider=missing&from=" + from + "&to=" + to: http.StatusNotFound,
+			"/api/export?provider=hidden&from=" + from + "&to=" + to:  http.StatusNotFound,
+		} {
+			if w := serve(target, ""); w.Code != code {
+				t.Fatalf("%s: expected %d, got %d", target, code, w.Code)
+			}
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		h.cfgMu.Lock()
+		h.config.Export.Enabled = false
+		h.cfgMu.Unlock()
+		if w := serve("/api/export", ""); w.Code != http.StatusNotFound {
+			t.Fatalf("expected 404, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,321 @@
+package api
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+const (
+	// feedDefaultLimit 订阅源默认条目数
+	feedDefaultLimit = 50
+
+	// feedMaxLimit 订阅源最大条目数
+	feedMaxLimit = 100
+
+	// feedScanFactor 查询事件时的扫描倍数（隐藏/禁用监测项的事件会被剔除）
+	feedScanFactor = 4
+
+	// feedTagDate Atom id 使用的 tag URI 日期（固定值，保证 id 永久不变）
+	feedTagDate = "2025"
+)
+
+// feedDefaultTypes 订阅源默认包含的事件类型（故障与性能下降及其恢复）
+var feedDefaultTypes = []storage.EventType{
+	storage.EventTypeDown,
+	storage.EventTypeUp,
+	storage.EventTypeDegradedStart,
+	storage.EventTypeDegradedEnd,
+}
+
+// feedEventTitles 事件类型对应的条目标题
+var feedEventTitles = map[storage.EventType]string{
+	storage.EventTypeDown:          "🔴 不可用",
+	storage.EventTypeUp:            "🟢 已恢复",
+	storage.EventTypeDegradedStart: "🟡 性能下降",
+	storage.EventTypeDegradedEnd:   "🟢 性能恢复",
+	storage.EventTypeCertExpiring:  "⚠️ 证书即将过期",
+	storage.EventTypeContentDrift:  "⚠️ 响应内容变化",
+}
+
+// atomFeed Atom 1.0 订阅源（RFC 4287）
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID         string      `xml:"id"`
+	Title      string      `xml:"title"`
+	Updated    string      `xml:"updated"`
+	Published  string      `xml:"published"`
+	Link       atomLink    `xml:"link"`
+	Categories []atomTerm  `xml:"category"`
+	Summary    atomSummary `xml:"summary"`
+}
+
+type atomTerm struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomSummary struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+// GetFeed 状态事件 Atom 订阅源
+// GET /feed.xml?provider=slug&service=xxx&channel=xxx&types=DOWN,UP&limit=50
+// 无需鉴权；仅包含可见（未隐藏、未禁用）监测项的事件，最新在前
+func (h *Handler) GetFeed(c *gin.Context) {
+	qProvider := strings.ToLower(strings.TrimSpace(c.Query("provider")))
+	qService := strings.TrimSpace(c.Query("service"))
+	qChannel := strings.TrimSpace(c.Query("channel"))
+	types := feedDefaultTypes
+	if typesStr := strings.TrimSpace(c.Query("types")); typesStr != "" {
+		types = parseEventTypes(typesStr)
+		if len(types) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("无效的 types 参数: %s", typesStr),
+			})
+			return
+		}
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(feedDefaultLimit)))
+	if limit <= 0 {
+		limit = feedDefaultLimit
+	}
+	if limit > feedMaxLimit {
+		limit = feedMaxLimit
+	}
+
+	h.cfgMu.RLock()
+	var monitors []config.ServiceConfig
+	if qProvider != "" {
+		monitors = h.providerMonitors(qProvider, false)
+	} else {
+		monitors = h.filterMonitorsForGroups(h.config.Monitors, "all", "all", "all", false, false)
+	}
+	baseURL := h.config.PublicBaseURL
+	cacheTTL := h.config.CacheTTL.TTLForPeriod("24h")
+	h.cfgMu.RUnlock()
+
+	if qProvider != "" && len(monitors) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("服务商不存在: %s", qProvider),
+		})
+		return
+	}
+
+	typeNames := make([]string, 0, len(types))
+	for _, t := range types {
+		typeNames = append(typeNames, string(t))
+	}
+	cacheKey := fmt.Sprintf("feed|prov=%s|svc=%s|ch=%s|types=%s|limit=%d", qProvider, qService, qChannel, strings.Join(typeNames, ","), limit)
+
+	reqCtx := context.WithoutCancel(c.Request.Context())
+	selfURL := baseURL + c.Request.URL.RequestURI()
+	entry, err := h.cache.loadEntry(reqCtx, cacheKey, cacheTTL, func() ([]byte, error) {
+		ctx, cancel := context.WithTimeout(reqCtx, 30*time.Second)
+		defer cancel()
+
+		filters := &storage.EventFilters{Service: qService, Channel: qChannel, Types: types}
+		if qProvider != "" {
+			filters.Provider = monitors[0].Provider
+		}
+		events, err := h.storage.WithContext(ctx).GetRecentStatusEvents(limit*feedScanFactor, filters)
+		if err != nil {
+			return nil, fmt.Errorf("查询最近事件失败: %w", err)
+		}
+
+		annotations := h.loadEventAnnotations(ctx, events)
+		feed := buildAtomFeed(events, annotations, monitors, baseURL, selfURL, limit, time.Now())
+		if qProvider != "" {
+			name := monitors[0].ProviderName
+			if name == "" {
+				name = monitors[0].Provider
+			}
+			feed.Title += " - " + name
+		}
+		out, err := xml.MarshalIndent(feed, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(xml.Header), out...), nil
+	})
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("GetFeed 失败", "cache_key", cacheKey, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("查询失败: %v", err),
+		})
+		return
+	}
+
+	// ETag 基于内容摘要，订阅器可用 If-None-Match 条件请求避免重复下载
+	sum := sha1.Sum(entry.data)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	ttlSeconds := int(cacheTTL.Seconds())
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d, s-maxage=%d", ttlSeconds, ttlSeconds))
+	// gzip 响应返回弱 ETag（W/ 前缀），条件请求两种形式都视为命中
+	if inm := c.GetHeader("If-None-Match"); inm == etag || inm == "W/"+etag {
+		c.Header("ETag", etag)
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Header("ETag", etag)
+	writeCached(c, entry, "application/atom+xml; charset=utf-8")
+}
+
+// buildAtomFeed 将状态事件转换为 Atom 订阅源
+// 仅保留 monitors 中可见通道的事件，最多 limit 条；条目 id 由事件 ID 派生，保证全局唯一且不变
+// 覆盖事件观测时间的运维标注追加到条目摘要末尾
+func buildAtomFeed(events []*storage.StatusEvent, annotations []*storage.Annotation, monitors []config.ServiceConfig, baseURL, selfURL string, limit int, now time.Time) *atomFeed {
+	tagAuthority := "relay-pulse"
+	if u, err := url.Parse(baseURL); err == nil && u.Hostname() != "" {
+		tagAuthority = u.Hostname()
+	}
+	tagPrefix := fmt.Sprintf("tag:%s,%s:", tagAuthority, feedTagDate)
+
+	// 可见通道索引（provider/service/channel → 首个监测项，用于显示名与 slug）
+	visible := make(map[string]config.ServiceConfig, len(monitors))
+	for _, task := range monitors {
+		psc := task.Provider + "/" + task.Service + "/" + task.Channel
+		if _, ok := visible[psc]; !ok {
+			visible[psc] = task
+		}
+	}
+
+	feed := &atomFeed{
+		ID:    tagPrefix + strings.TrimPrefix(selfURL, baseURL), // 不同过滤条件的订阅源使用不同 id
+		Title: "RelayPulse 状态事件",
+		Links: []atomLink{
+			{Href: selfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: baseURL + "/", Rel: "alternate", Type: "text/html"},
+		},
+		Author:  atomAuthor{Name: "RelayPulse"},
+		Entries: make([]atomEntry, 0, limit),
+	}
+
+	var updated time.Time
+	for _, e := range events {
+		task, ok := visible[e.Provider+"/"+e.Service+"/"+e.Channel]
+		if !ok {
+			continue
+		}
+
+		slug := task.ProviderSlug
+		if slug == "" {
+			slug = strings.ToLower(strings.TrimSpace(task.Provider))
+		}
+		target := feedTargetName(task, e.Model)
+
+		title, ok := feedEventTitles[e.EventType]
+		if !ok {
+			title = string(e.EventType)
+		}
+
+		observed := time.Unix(e.ObservedAt, 0).UTC()
+		if observed.After(updated) {
+			updated = observed
+		}
+
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:         fmt.Sprintf("%sevent-%d", tagPrefix, e.ID),
+			Title:      fmt.Sprintf("%s %s", title, target),
+			Updated:    observed.Format(time.RFC3339),
+			Published:  observed.Format(time.RFC3339),
+			Link:       atomLink{Href: fmt.Sprintf("%s/p/%s", baseURL, slug), Rel: "alternate", Type: "text/html"},
+			Categories: []atomTerm{{Term: string(e.EventType)}, {Term: slug}},
+			Summary:    atomSummary{Type: "text", Text: feedEventSummary(e, target, observed, eventAnnotations(e, annotations))},
+		})
+		if len(feed.Entries) >= limit {
+			break
+		}
+	}
+
+	// 无条目时以当前时间作为更新时间（Atom 要求 updated 必填）
+	if updated.IsZero() {
+		updated = now.UTC()
+	}
+	feed.Updated = updated.Format(time.RFC3339)
+	return feed
+}
+
+// feedTargetName 条目中的监测项名称：优先显示名，如 "88code / Claude Code / VIP / sonnet"
+func feedTargetName(task config.ServiceConfig, model string) string {
+	pick := func(name, fallback string) string {
+		if name != "" {
+			return name
+		}
+		return fallback
+	}
+
+	parts := []string{
+		pick(task.ProviderName, task.Provider),
+		pick(task.ServiceName, task.Service),
+	}
+	if task.Channel != "" {
+		parts = append(parts, pick(task.ChannelName, task.Channel))
+	}
+	if model != "" {
+		parts = append(parts, model)
+	}
+	return strings.Join(parts, " / ")
+}
+
+// feedEventSummary 条目摘要：事件时间、状态变化与附加信息（HTTP 状态码、延迟、受影响模型、运维标注）
+func feedEventSummary(e *storage.StatusEvent, target string, observed time.Time, annotations []*storage.Annotation) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s：%s（状态 %d → %d），观测时间 %s", target, string(e.EventType), e.FromStatus, e.ToStatus, observed.Format(time.RFC3339))
+
+	if code, ok := e.Meta["http_code"]; ok {
+		fmt.Fprintf(&sb, "，HTTP %v", code)
+	}
+	if latency, ok := e.Meta["latency_ms"]; ok {
+		fmt.Fprintf(&sb, "，延迟 %vms", latency)
+	}
+	if sub, ok := e.Meta["sub_status"].(string); ok && sub != "" {
+		fmt.Fprintf(&sb, "，原因 %s", sub)
+	}
+	if models, ok := e.Meta["models"].([]any); ok && len(models) > 0 {
+		names := make([]string, 0, len(models))
+		for _, m := range models {
+			names = append(names, fmt.Sprint(m))
+		}
+		fmt.Fprintf(&sb, "，模型 %s", strings.Join(names, ", "))
+	}
+	for _, a := range annotations {
+		fmt.Fprintf(&sb, "\n标注：%s", a.Text)
+		if a.Link != "" {
+			fmt.Fprintf(&sb, "（%s）", a.Link)
+		}
+	}
+	return sb.String()
+}
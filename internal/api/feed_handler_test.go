@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestBuildAtomFeed(t *testing.T) {
+	t.Parallel()
+
+	monitors := []config.ServiceConfig{
+		{Provider: "Relay", ProviderName: "Relay & Co", ProviderSlug: "relay", Service: "cc", Channel: "vip", Model: "sonnet"},
+		{Provider: "Other", Service: "cx"},
+	}
+	events := []*storage.StatusEvent{
+		{ID: 9, Provider: "Relay", Service: "cc", Channel: "hidden", EventType: storage.EventTypeDown, ObservedAt: 300},
+		{ID: 8, Provider: "Relay", Service: "cc", Channel: "vip", Model: "sonnet", EventType: storage.EventTypeDown, FromStatus: 1, ToStatus: 0, ObservedAt: 200,
+			Meta: map[string]any{"http_code": 502, "latency_ms": 1200}},
+		{ID: 7, Provider: "Other", Service: "cx", EventType: storage.EventTypeUp, FromStatus: 0, ToStatus: 1, ObservedAt: 100},
+	}
+	now := time.Unix(1000, 0)
+
+	feed := buildAtomFeed(events, nil, monitors, "https://relaypulse.top", "https://relaypulse.top/feed.xml", 10, now)
+
+	if feed.ID != "tag:relaypulse.top,2025:/feed.xml" {
+		t.Fatalf("unexpected feed id: %s", feed.ID)
+	}
+	if len(feed.Entries) != 2 {
+		t.Fatalf("expected hidden channel events to be dropped, got %d entries", len(feed.Entries))
+	}
+	if feed.Updated != time.Unix(200, 0).UTC().Format(time.RFC3339) {
+		t.Fatalf("feed updated should follow newest visible entry, got %s", feed.Updated)
+	}
+
+	first := feed.Entries[0]
+	if first.ID != "tag:relaypulse.top,2025:event-8" {
+		t.Fatalf("unexpected entry id: %s", first.ID)
+	}
+	if first.Title != "🔴 不可用 Relay & Co / cc / vip / sonnet" {
+		t.Fatalf("unexpected entry title: %s", first.Title)
+	}
+	if first.Link.Href != "https://relaypulse.top/p/relay" {
+		t.Fatalf("unexpected entry link: %s", first.Link.Href)
+	}
+	if !strings.Contains(first.Summary.Text, "HTTP 502") || !strings.Contains(first.Summary.Text, "1200ms") {
+		t.Fatalf("summary should include meta details: %s", first.Summary.Text)
+	}
+	if feed.Entries[1].Link.Href != "https://relaypulse.top/p/other" {
+		t.Fatalf("slug should fall back to lowercase provider: %s", feed.Entries[1].Link.Href)
+	}
+
+	out, err := xml.Marshal(feed)
+	if err != nil {
+		t.Fatalf("marshal feed: %v", err)
+	}
+	if !strings.Contains(string(out), `xmlns="http://www.w3.org/2005/Atom"`) || !strings.Contains(string(out), "Relay &amp; Co") {
+		t.Fatalf("unexpected xml output: %s", out)
+	}
+
+	limited := buildAtomFeed(events, nil, monitors, "https://relaypulse.top", "https://relaypulse.top/feed.xml", 1, now)
+	if len(limited.Entries) != 1 {
+		t.Fatalf("expected limit to cap entries, got %d", len(limited.Entries))
+	}
+
+	empty := buildAtomFeed(nil, nil, monitors, "", "/feed.xml", 10, now)
+	if empty.Updated != now.UTC().Format(time.RFC3339) || !strings.HasPrefix(empty.ID, "tag:relay-pulse,") {
+		t.Fatalf("unexpected empty feed: %+v", empty)
+	}
+}
@@ -0,0 +1,603 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// graphqlMaxBodyBytes GraphQL 请求体上限
+const graphqlMaxBodyBytes = 64 * 1024
+
+// graphqlEventsAuthKey context 中记录请求是否携带有效 events API Token
+type graphqlEventsAuthKey struct{}
+
+// graphqlRequest GraphQL 请求
+type graphqlRequest struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables"`
+	OperationName string         `json:"operationName"`
+}
+
+// graphqlJSON 任意 JSON 值（原样输出，不可再选择子字段）
+var graphqlJSON = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "JSON",
+	Description: "任意 JSON 值（原样输出）",
+	Serialize:   func(v any) any { return v },
+})
+
+// PostGraphQL 执行 GraphQL 查询
+// POST /api/graphql（application/json：{"query": "...", "variables": {...}, "operationName": "..."}）
+// GET  /api/graphql?query=...&variables=...&operationName=...
+//
+// 解析或校验失败时返回 data=null 与 errors；字段级错误（如 events 缺少 Token）仅将该字段置为 null
+func (h *Handler) PostGraphQL(c *gin.Context) {
+	h.cfgMu.RLock()
+	gqlCfg := h.config.GraphQL
+	eventsToken := h.config.Events.APIToken
+	h.cfgMu.RUnlock()
+
+	if !gqlCfg.Enabled {
+		c.JSON(http.StatusNotFound, gin.H{"error": "GraphQL 端点未启用"})
+		return
+	}
+	if h.graphql == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "GraphQL schema 不可用"})
+		return
+	}
+
+	var req graphqlRequest
+	if c.Request.Method == http.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if vars := c.Query("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "variables 不是有效的 JSON 对象"})
+				return
+			}
+		}
+	} else {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, graphqlMaxBodyBytes)
+		if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("无效的请求体: %v", err)})
+			return
+		}
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query 不能为空"})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), graphqlEventsAuthKey{}, bearerTokenMatches(c, eventsToken))
+	resp := executeGraphQL(ctx, h.graphql, req, gqlCfg.MaxDepth)
+	if len(resp.Errors) > 0 {
+		logger.FromContext(ctx, "api").Debug("GraphQL 查询返回错误", "errors", len(resp.Errors), "first", resp.Errors[0].Message)
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
+
+// executeGraphQL 解析、校验并执行查询
+// 解析或校验失败（含超过嵌套深度上限、内省查询）时 data 为 null；字段解析失败时该字段为 null 并记录错误，其他字段正常返回
+func executeGraphQL(ctx context.Context, schema *graphql.Schema, req graphqlRequest, maxDepth int) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	// 先单独检查片段循环引用：graphql-go 的 OverlappingFieldsCanBeMerged 规则遇到自引用片段会无限递归导致栈溢出
+	for _, rules := range [][]graphql.ValidationRuleFn{{graphql.NoFragmentCyclesRule}, graphql.SpecifiedRules} {
+		if result := graphql.ValidateDocument(schema, doc, rules); !result.IsValid {
+			return &graphql.Result{Errors: result.Errors}
+		}
+	}
+	if err := checkGraphQLSelections(doc, maxDepth); err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        *schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+}
+
+// checkGraphQLSelections 检查各操作的字段嵌套深度（片段展开计入所在层级，maxDepth 为 0 表示不限制），并拒绝内省查询
+// 需在校验之后调用（校验已排除片段循环引用）
+func checkGraphQLSelections(doc *ast.Document, maxDepth int) error {
+	w := &graphqlDepthWalker{fragments: make(map[string]*ast.FragmentDefinition), memo: make(map[string]int)}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			w.fragments[f.Name.Value] = f
+		}
+	}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		depth := w.depth(op.SelectionSet)
+		if w.err != nil {
+			return w.err
+		}
+		if maxDepth > 0 && depth > maxDepth {
+			return fmt.Errorf("查询嵌套深度 %d 超过上限 %d", depth, maxDepth)
+		}
+	}
+	return nil
+}
+
+// graphqlDepthWalker 计算选择集嵌套深度（片段深度按名称缓存，重复展开不会重复遍历）
+type graphqlDepthWalker struct {
+	fragments map[string]*ast.FragmentDefinition
+	memo      map[string]int
+	err       error
+}
+
+func (w *graphqlDepthWalker) depth(set *ast.SelectionSet) int {
+	if set == nil || w.err != nil {
+		return 0
+	}
+	maxDepth := 0
+	for _, sel := range set.Selections {
+		var d int
+		switch s := sel.(type) {
+		case *ast.Field:
+			if name := s.Name.Value; strings.HasPrefix(name, "__") && name != "__typename" {
+				w.err = fmt.Errorf("不支持内省查询（%s）", name)
+				return 0
+			}
+			d = 1 + w.depth(s.SelectionSet)
+		case *ast.InlineFragment:
+			d = w.depth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			cached, ok := w.memo[name]
+			if !ok {
+				w.memo[name] = 0
+				if f := w.fragments[name]; f != nil {
+					cached = w.depth(f.SelectionSet)
+				}
+				w.memo[name] = cached
+			}
+			d = cached
+		}
+		maxDepth = max(maxDepth, d)
+	}
+	return maxDepth
+}
+
+// bearerTokenMatches 请求是否携带与 apiToken 一致的 Bearer Token（apiToken 为空时视为不匹配）
+func bearerTokenMatches(c *gin.Context, apiToken string) bool {
+	const bearerPrefix = "Bearer "
+	authHeader := c.GetHeader("Authorization")
+	if apiToken == "" || !strings.HasPrefix(authHeader, bearerPrefix) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(authHeader, bearerPrefix)), []byte(apiToken)) == 1
+}
+
+// newGraphQLSchema 构建 GraphQL schema
+//
+// 根字段复用 REST 端点的查询与缓存：monitors/providers 与 /api/status 共用缓存，
+// rankings 与 /api/rankings 共用缓存；嵌套字段默认从 JSON 解码后的对象中按字段名取值
+func newGraphQLSchema(h *Handler) (*graphql.Schema, error) {
+	statusArgs := graphql.FieldConfigArgument{
+		"period":   {Type: graphql.String, DefaultValue: "24h", Description: "时间范围：90m/24h/7d/30d/90d/180d（同 /api/status）"},
+		"provider": {Type: graphql.String, Description: "服务商名称或 slug"},
+		"service":  {Type: graphql.String, Description: "服务类型"},
+		"board":    {Type: graphql.String, DefaultValue: "hot", Description: "板块：hot/secondary/cold/all"},
+	}
+
+	annotation := graphql.NewObject(graphql.ObjectConfig{Name: "Annotation", Fields: scalarFields(map[string]graphql.Output{
+		"id": graphql.Int, "provider": graphql.String, "service": graphql.String, "channel": graphql.String,
+		"model": graphql.String, "start_time": graphql.Int, "end_time": graphql.Int, "text": graphql.String,
+		"link": graphql.String, "created_at": graphql.Int,
+	})})
+
+	currentStatus := graphql.NewObject(graphql.ObjectConfig{Name: "CurrentStatus", Fields: scalarFields(map[string]graphql.Output{
+		"status": graphql.Int, "latency": graphql.Int, "timestamp": graphql.Int,
+		"cert_days_remaining": graphql.Int, "correlation": graphqlJSON,
+	})})
+
+	timePoint := graphql.NewObject(graphql.ObjectConfig{Name: "TimePoint", Fields: scalarFields(map[string]graphql.Output{
+		"time": graphql.String, "timestamp": graphql.Int, "status": graphql.Int, "latency": graphql.Int,
+		"availability": graphql.Float, "status_counts": graphqlJSON,
+		"dns_ms": graphql.Int, "connect_ms": graphql.Int, "tls_ms": graphql.Int, "ttfb_ms": graphql.Int,
+	})})
+
+	monitorFields := scalarFields(map[string]graphql.Output{
+		"provider": graphql.String, "provider_name": graphql.String, "provider_slug": graphql.String,
+		"provider_url": graphql.String, "service": graphql.String, "service_name": graphql.String,
+		"channel": graphql.String, "channel_name": graphql.String, "model": graphql.String,
+		"category": graphql.String, "board": graphql.String, "cold_reason": graphql.String,
+		"sponsor": graphql.String, "sponsor_url": graphql.String, "sponsor_level": graphql.String,
+		"price_min": graphql.Float, "price_max": graphql.Float, "listed_days": graphql.Int,
+		"interval_ms": graphql.Int, "slow_latency_ms": graphql.Int,
+		"risks": graphqlJSON, "badges": graphqlJSON,
+	})
+	monitorFields["current_status"] = &graphql.Field{Type: currentStatus}
+	monitorFields["annotations"] = &graphql.Field{Type: graphql.NewList(annotation)}
+	monitorFields["timeline"] = &graphql.Field{
+		Type:        graphql.NewList(timePoint),
+		Args:        graphql.FieldConfigArgument{"last": {Type: graphql.Int, Description: "仅返回最近 N 个时间点"}},
+		Description: "可用性时间线（粒度随 period 变化，同 /api/status）",
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			points, _ := p.Source.(map[string]any)["timeline"].([]any)
+			if last, ok := p.Args["last"].(int); ok && last >= 0 && last < len(points) {
+				points = points[len(points)-last:]
+			}
+			return points, nil
+		},
+	}
+	monitor := graphql.NewObject(graphql.ObjectConfig{Name: "Monitor", Fields: monitorFields})
+
+	service := graphql.NewObject(graphql.ObjectConfig{Name: "Service", Fields: graphql.Fields{
+		"service":      {Type: graphql.String},
+		"service_name": {Type: graphql.String},
+		"channels":     {Type: graphql.NewList(monitor)},
+	}})
+
+	provider := graphql.NewObject(graphql.ObjectConfig{Name: "Provider", Fields: graphql.Fields{
+		"provider":      {Type: graphql.String},
+		"provider_name": {Type: graphql.String},
+		"provider_slug": {Type: graphql.String},
+		"provider_url":  {Type: graphql.String},
+		"services":      {Type: graphql.NewList(service)},
+	}})
+
+	eventFields := scalarFields(map[string]graphql.Output{
+		"id": graphql.Int, "provider": graphql.String, "service": graphql.String, "channel": graphql.String,
+		"model": graphql.String, "type": graphql.String, "from_status": graphql.Int, "to_status": graphql.Int,
+		"trigger_record_id": graphql.Int, "observed_at": graphql.Int, "created_at": graphql.Int, "meta": graphqlJSON,
+	})
+	eventFields["annotations"] = &graphql.Field{Type: graphql.NewList(annotation)}
+	event := graphql.NewObject(graphql.ObjectConfig{Name: "Event", Fields: eventFields})
+
+	ranking := graphql.NewObject(graphql.ObjectConfig{Name: "Ranking", Fields: scalarFields(map[string]graphql.Output{
+		"rank": graphql.Int, "provider": graphql.String, "provider_name": graphql.String,
+		"provider_slug": graphql.String, "service": graphql.String, "service_name": graphql.String,
+		"score": graphql.Float, "uptime": graphql.Float, "latency": graphql.Int, "latency_score": graphql.Float,
+		"flap_rate": graphql.Float, "price": graphql.Float, "price_score": graphql.Float,
+		"probes": graphql.Int, "monitors": graphql.Int,
+	})})
+
+	query := graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+		"monitors": {
+			Type:        graphql.NewList(monitor),
+			Args:        statusArgs,
+			Description: "监测项列表（多模型监测组按模型展开）",
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return h.graphqlMonitors(p.Context, p.Args)
+			},
+		},
+		"providers": {
+			Type:        graphql.NewList(provider),
+			Args:        statusArgs,
+			Description: "按 provider → service → channel 嵌套的监测项",
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				monitors, err := h.graphqlMonitors(p.Context, p.Args)
+				if err != nil {
+					return nil, err
+				}
+				return nestMonitors(monitors), nil
+			},
+		},
+		"events": {
+			Type: graphql.NewList(event),
+			Args: graphql.FieldConfigArgument{
+				"since_id": {Type: graphql.Int, DefaultValue: 0},
+				"limit":    {Type: graphql.Int, DefaultValue: 20, Description: "最大 100"},
+				"provider": {Type: graphql.String},
+				"service":  {Type: graphql.String},
+				"channel":  {Type: graphql.String},
+				"types":    {Type: graphql.NewList(graphql.String), Description: "事件类型：DOWN/UP/DEGRADED_START/DEGRADED_END 等"},
+			},
+			Description: "状态事件（需要 events API Token，同 /api/events）",
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return h.graphqlEvents(p.Context, p.Args)
+			},
+		},
+		"rankings": {
+			Type: graphql.NewList(ranking),
+			Args: graphql.FieldConfigArgument{
+				"period":  {Type: graphql.String, DefaultValue: "7d", Description: "24h/7d/30d"},
+				"service": {Type: graphql.String},
+				"board":   {Type: graphql.String, DefaultValue: "hot"},
+			},
+			Description: "服务商排行榜（同 /api/rankings）",
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				return h.graphqlRankings(p.Context, p.Args)
+			},
+		},
+	}})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: query})
+	if err != nil {
+		return nil, fmt.Errorf("构建 GraphQL schema 失败: %w", err)
+	}
+	return &schema, nil
+}
+
+// scalarFields 批量定义使用默认解析器的标量字段
+func scalarFields(types map[string]graphql.Output) graphql.Fields {
+	fields := make(graphql.Fields, len(types))
+	for name, typ := range types {
+		fields[name] = &graphql.Field{Type: typ}
+	}
+	return fields
+}
+
+// stringArg 读取字符串参数（未传入时返回 fallback）
+func stringArg(args map[string]any, name, fallback string) string {
+	if v, ok := args[name].(string); ok && strings.TrimSpace(v) != "" {
+		return strings.TrimSpace(v)
+	}
+	return fallback
+}
+
+// graphqlMonitors 读取（或填充）/api/status 缓存并展开为监测项列表
+func (h *Handler) graphqlMonitors(ctx context.Context, args map[string]any) ([]map[string]any, error) {
+	period := stringArg(args, "period", "24h")
+	qProvider := strings.ToLower(stringArg(args, "provider", "all"))
+	qService := stringArg(args, "service", "all")
+	qBoard := strings.ToLower(stringArg(args, "board", "hot"))
+
+	h.cfgMu.RLock()
+	maxRangeDays := h.config.MaxStatusRangeDays
+	cacheTTL := h.config.CacheTTL.TTLForPeriod(period)
+	h.cfgMu.RUnlock()
+
+	if _, err := h.parsePeriod(period); err != nil {
+		return nil, fmt.Errorf("无效的时间范围: %s", period)
+	}
+	if days, ok := parsePeriodDays(period); ok && days > maxRangeDays {
+		return nil, fmt.Errorf("时间范围 %s 超出允许的最大天数 %d", period, maxRangeDays)
+	}
+	if qBoard != "hot" && qBoard != "secondary" && qBoard != "cold" && qBoard != "all" {
+		return nil, fmt.Errorf("无效的 board 参数: %s (支持: hot/secondary/cold/all)", qBoard)
+	}
+
+	// 与 GetStatus 默认参数（无对齐、无时段过滤、UTC）的缓存 key 保持一致，两者共享缓存
+	cacheKey := fmt.Sprintf("p=%s|align=%s|tf=%s|prov=%s|svc=%s|board=%s|hidden=%t|retired=%t", period, "", "", qProvider, qService, qBoard, false, false)
+	cacheKey += "|tz=" + time.UTC.String()
+
+	data, err := h.cache.loadWithTTL(ctx, cacheKey, cacheTTL, func() ([]byte, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		return h.queryAndSerialize(ctx, period, "", time.UTC, nil, nil, qProvider, qService, qBoard, false, false)
+	})
+	if err != nil {
+		logger.FromContext(ctx, "api").Error("GraphQL monitors 查询失败", "cache_key", cacheKey, "error", err)
+		return nil, errors.New("查询失败")
+	}
+
+	var status struct {
+		Data   []map[string]any `json:"data"`
+		Groups []map[string]any `json:"groups"`
+	}
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("解析状态数据失败: %w", err)
+	}
+	return flattenStatusMonitors(status.Data, status.Groups), nil
+}
+
+// flattenStatusMonitors 将 /api/status 的 data 与 groups 合并为扁平的监测项列表
+// 监测组的每个模型层展开为一个监测项（继承组的展示字段，current_status/timeline 取自该层）
+func flattenStatusMonitors(data, groups []map[string]any) []map[string]any {
+	monitors := make([]map[string]any, 0, len(data)+len(groups))
+	monitors = append(monitors, data...)
+	for _, g := range groups {
+		layers, _ := g["layers"].([]any)
+		notes, _ := g["annotations"].([]any)
+		for _, raw := range layers {
+			layer, ok := raw.(map[string]any)
+			if !ok {
+				continue
+			}
+			m := make(map[string]any, len(g)+3)
+			for k, v := range g {
+				if k != "layers" && k != "annotations" && k != "current_status" {
+					m[k] = v
+				}
+			}
+			model, _ := layer["model"].(string)
+			m["model"] = model
+			m["current_status"] = layer["current_status"]
+			m["timeline"] = layer["timeline"]
+			if retired, ok := layer["retired"]; ok {
+				m["retired"] = retired
+			}
+
+			// 组级标注为任一模型层匹配，这里仅保留适用于该模型的标注
+			var layerNotes []any
+			for _, n := range notes {
+				if note, ok := n.(map[string]any); ok {
+					if nm, _ := note["model"].(string); nm == "" || nm == model {
+						layerNotes = append(layerNotes, note)
+					}
+				}
+			}
+			if len(layerNotes) > 0 {
+				m["annotations"] = layerNotes
+			}
+			monitors = append(monitors, m)
+		}
+	}
+	return monitors
+}
+
+// nestMonitors 按 provider → service 分组（保持首次出现顺序）
+func nestMonitors(monitors []map[string]any) []map[string]any {
+	var providers []map[string]any
+	providerIdx := make(map[string]int)
+	serviceIdx := make(map[string]int)
+	for _, m := range monitors {
+		slug, _ := m["provider_slug"].(string)
+		svc, _ := m["service"].(string)
+
+		pi, ok := providerIdx[slug]
+		if !ok {
+			pi = len(providers)
+			providerIdx[slug] = pi
+			providers = append(providers, map[string]any{
+				"provider":      m["provider"],
+				"provider_name": m["provider_name"],
+				"provider_slug": slug,
+				"provider_url":  m["provider_url"],
+				"services":      []map[string]any{},
+			})
+		}
+
+		services := providers[pi]["services"].([]map[string]any)
+		key := slug + "\x00" + svc
+		si, ok := serviceIdx[key]
+		if !ok {
+			si = len(services)
+			serviceIdx[key] = si
+			services = append(services, map[string]any{
+				"service":      svc,
+				"service_name": m["service_name"],
+				"channels":     []map[string]any{},
+			})
+		}
+		services[si]["channels"] = append(services[si]["channels"].([]map[string]any), m)
+		providers[pi]["services"] = services
+	}
+	return providers
+}
+
+// graphqlEvents 查询状态事件（与 /api/events 一致，需要 events API Token）
+func (h *Handler) graphqlEvents(ctx context.Context, args map[string]any) ([]map[string]any, error) {
+	if ok, _ := ctx.Value(graphqlEventsAuthKey{}).(bool); !ok {
+		return nil, errors.New("events 字段需要有效的 events API Token（Authorization: Bearer <token>）")
+	}
+
+	sinceID, _ := args["since_id"].(int)
+	limit, _ := args["limit"].(int)
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	var filters *storage.EventFilters
+	provider := stringArg(args, "provider", "")
+	service := stringArg(args, "service", "")
+	channel := stringArg(args, "channel", "")
+	types, _ := args["types"].([]any)
+	if provider != "" || service != "" || channel != "" || len(types) > 0 {
+		filters = &storage.EventFilters{Provider: provider, Service: service, Channel: channel}
+		names := make([]string, 0, len(types))
+		for _, t := range types {
+			names = append(names, t.(string))
+		}
+		if len(names) > 0 {
+			filters.Types = parseEventTypes(strings.Join(names, ","))
+		}
+	}
+
+	events, err := h.storage.WithContext(ctx).GetStatusEvents(int64(sinceID), limit, filters)
+	if err != nil {
+		logger.FromContext(ctx, "api").Error("GraphQL events 查询失败", "error", err)
+		return nil, errors.New("查询事件失败")
+	}
+
+	annotations := h.loadEventAnnotations(ctx, events)
+	items := make([]EventItem, 0, len(events))
+	for _, e := range events {
+		var notes []AnnotationItem
+		for _, a := range eventAnnotations(e, annotations) {
+			notes = append(notes, toAnnotationItem(a))
+		}
+		items = append(items, EventItem{
+			ID:              e.ID,
+			Provider:        e.Provider,
+			Service:         e.Service,
+			Channel:         e.Channel,
+			Model:           e.Model,
+			Type:            string(e.EventType),
+			FromStatus:      e.FromStatus,
+			ToStatus:        e.ToStatus,
+			TriggerRecordID: e.TriggerRecordID,
+			ObservedAt:      e.ObservedAt,
+			CreatedAt:       e.CreatedAt,
+			Meta:            e.Meta,
+			Annotations:     notes,
+		})
+	}
+
+	// 转换为与 /api/events 相同的 JSON 结构，供默认解析器按字段名取值
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	var result []map[string]any
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// graphqlRankings 读取（或填充）/api/rankings 缓存
+func (h *Handler) graphqlRankings(ctx context.Context, args map[string]any) ([]map[string]any, error) {
+	period := stringArg(args, "period", "7d")
+	if period == "1d" {
+		period = "24h"
+	}
+	if period != "24h" && period != "7d" && period != "30d" {
+		return nil, fmt.Errorf("无效的时间范围: %s (支持: 24h/7d/30d)", period)
+	}
+	qService := stringArg(args, "service", "all")
+	qBoard := strings.ToLower(stringArg(args, "board", "hot"))
+	if qBoard != "hot" && qBoard != "secondary" && qBoard != "cold" && qBoard != "all" {
+		return nil, fmt.Errorf("无效的 board 参数: %s (支持: hot/secondary/cold/all)", qBoard)
+	}
+
+	h.cfgMu.RLock()
+	cacheTTL := h.config.CacheTTL.TTLForPeriod(period)
+	h.cfgMu.RUnlock()
+
+	// 与 GetRankings 的缓存 key 保持一致，两者共享缓存
+	cacheKey := fmt.Sprintf("rankings|p=%s|svc=%s|board=%s", period, qService, qBoard)
+	data, err := h.cache.loadWithTTL(ctx, cacheKey, cacheTTL, func() ([]byte, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		resp, err := h.buildRankings(ctx, period, qService, qBoard)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(resp)
+	})
+	if err != nil {
+		logger.FromContext(ctx, "api").Error("GraphQL rankings 查询失败", "cache_key", cacheKey, "error", err)
+		return nil, errors.New("查询失败")
+	}
+
+	var resp struct {
+		Rankings []map[string]any `json:"rankings"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("解析排行榜数据失败: %w", err)
+	}
+	return resp.Rankings, nil
+}
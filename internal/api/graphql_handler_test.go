@@ -0,0 +1,236 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestGraphQLEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newTestSQLiteStore(t)
+
+	now := time.Now().Unix()
+	for _, rec := range []*storage.ProbeRecord{
+		{Provider: "Relay", Service: "cc", Channel: "vip", Status: 1, Latency: 120, Timestamp: now - 60},
+		{Provider: "Relay", Service: "cx", Channel: "std", Status: 0, Latency: 0, Timestamp: now - 60},
+	} {
+		if err := store.SaveRecord(rec); err != nil {
+			t.Fatalf("save record: %v", err)
+		}
+	}
+	if err := store.SaveStatusEvent(&storage.StatusEvent{
+		Provider: "Relay", Service: "cx", Channel: "std", EventType: storage.EventTypeDown,
+		FromStatus: 1, ToStatus: 0, ObservedAt: now - 60, CreatedAt: now - 60,
+	}); err != nil {
+		t.Fatalf("save event: %v", err)
+	}
+
+	cfg := &config.AppConfig{
+		MaxStatusRangeDays: 180,
+		Events:             config.EventsConfig{APIToken: "events-token"},
+		GraphQL:            config.GraphQLConfig{Enabled: true, MaxDepth: 8},
+		Monitors: []config.ServiceConfig{
+			{Provider: "Relay", ProviderSlug: "relay", Service: "cc", Channel: "vip", Board: "hot"},
+			{Provider: "Relay", ProviderSlug: "relay", Service: "cx", Channel: "std", Board: "hot"},
+		},
+	}
+	h := NewHandler(store, cfg)
+	router := gin.New()
+	router.GET("/api/graphql", h.PostGraphQL)
+	router.POST("/api/graphql", h.PostGraphQL)
+
+	serve := func(body, token string) (int, string) {
+		req := httptest.NewRequest(http.MethodPost, "/api/graphql", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code, w.Body.String()
+	}
+
+	code, body := serve(`{"query":"{ providers(period: \"24h\") { provider_slug services { service channels { channel current_status { status } timeline(last: 1) { status } } } } }"}`, "")
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", code, body)
+	}
+	var nested struct {
+		Data struct {
+			Providers []struct {
+				ProviderSlug string `json:"provider_slug"`
+				Services     []struct {
+					Service  string `json:"service"`
+					Channels []struct {
+						Channel       string `json:"channel"`
+						CurrentStatus struct {
+							Status int `json:"status"`
+						} `json:"current_status"`
+						Timeline []map[string]any `json:"timeline"`
+					} `json:"channels"`
+				} `json:"services"`
+			} `json:"providers"`
+		} `json:"data"`
+		Errors []map[string]any `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(body), &nested); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(nested.Errors) != 0 || len(nested.Data.Providers) != 1 || len(nested.Data.Providers[0].Services) != 2 {
+		t.Fatalf("unexpected nested response: %s", body)
+	}
+	for _, svc := range nested.Data.Providers[0].Services {
+		if len(svc.Channels) != 1 || len(svc.Channels[0].Timeline) != 1 {
+			t.Fatalf("unexpected service %s: %s", svc.Service, body)
+		}
+		want := map[string]int{"cc": 1, "cx": 0}[svc.Service]
+		if svc.Channels[0].CurrentStatus.Status != want {
+			t.Fatalf("service %s: expected status %d, got %s", svc.Service, want, body)
+		}
+	}
+
+	// events 字段需要 Token，缺失时仅该字段为 null
+	const eventsQuery = `{"query":"{ monitors { channel } events(types: [\"DOWN\"]) { type channel to_status } }"}`
+	_, body = serve(eventsQuery, "")
+	if !strings.Contains(body, `"events":null`) || !strings.Contains(body, "events API Token") || !strings.Contains(body, `"monitors":[`) {
+		t.Fatalf("expected events field error without token: %s", body)
+	}
+	_, body = serve(eventsQuery, "events-token")
+	var events struct {
+		Data struct {
+			Events []map[string]any `json:"events"`
+		} `json:"data"`
+		Errors []map[string]any `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(body), &events); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	wantEvents := []map[string]any{{"type": "DOWN", "channel": "std", "to_status": float64(0)}}
+	if len(events.Errors) != 0 || !reflect.DeepEqual(events.Data.Events, wantEvents) {
+		t.Fatalf("unexpected events response: %s", body)
+	}
+
+	// 校验错误返回 data=null
+	_, body = serve(`{"query":"{ monitors { secret } }"}`, "")
+	if !strings.Contains(body, `"data":null`) || !strings.Contains(body, "secret") {
+		t.Fatalf("expected validation error: %s", body)
+	}
+	if code, _ = serve(`{"query":"  "}`, ""); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for empty query, got %d", code)
+	}
+
+	// GET 请求
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/graphql?query=%7B+monitors(service%3A+%22cc%22)+%7B+channel+%7D+%7D", nil))
+	if w.Code != http.StatusOK || w.Body.String() != `{"data":{"monitors":[{"channel":"vip"}]}}` {
+		t.Fatalf("unexpected GET response: %d %s", w.Code, w.Body.String())
+	}
+
+	// 未启用时返回 404
+	h.config.GraphQL.Enabled = false
+	if code, _ = serve(`{"query":"{ monitors { channel } }"}`, ""); code != http.StatusNotFound {
+		t.Fatalf("expected 404 when disabled, got %d", code)
+	}
+}
+
+func TestFlattenStatusMonitors(t *testing.T) {
+	t.Parallel()
+
+	groups := []map[string]any{{
+		"provider_slug": "relay", "service": "cx", "channel": "m",
+		"current_status": map[string]any{"status": 2},
+		"annotations": []any{
+			map[string]any{"id": 1, "model": "gpt"},
+			map[string]any{"id": 2, "model": ""},
+		},
+		"layers": []any{
+			map[string]any{"model": "gpt", "current_status": map[string]any{"status": 1}},
+			map[string]any{"model": "mini", "current_status": map[string]any{"status": 0}, "retired": true},
+		},
+	}}
+	data := []map[string]any{{"provider_slug": "relay", "service": "cc", "channel": "vip"}}
+
+	monitors := flattenStatusMonitors(data, groups)
+	if len(monitors) != 3 || monitors[0]["service"] != "cc" {
+		t.Fatalf("unexpected monitors: %+v", monitors)
+	}
+	gpt, mini := monitors[1], monitors[2]
+	if gpt["model"] != "gpt" || gpt["current_status"].(map[string]any)["status"] != 1 || len(gpt["annotations"].([]any)) != 2 {
+		t.Fatalf("unexpected gpt layer: %+v", gpt)
+	}
+	if mini["retired"] != true || len(mini["annotations"].([]any)) != 1 || mini["layers"] != nil {
+		t.Fatalf("unexpected mini layer: %+v", mini)
+	}
+
+	providers := nestMonitors(monitors)
+	services := providers[0]["services"].([]map[string]any)
+	if len(providers) != 1 || len(services) != 2 || len(services[1]["channels"].([]map[string]any)) != 2 {
+		t.Fatalf("unexpected nesting: %+v", providers)
+	}
+}
+
+func TestExecuteGraphQLRejectsInvalidQueries(t *testing.T) {
+	t.Parallel()
+
+	schema, err := newGraphQLSchema(&Handler{})
+	if err != nil {
+		t.Fatalf("newGraphQLSchema: %v", err)
+	}
+	cases := map[string]string{
+		`{ monitors { secret } }`:                                                           `Cannot query field "secret"`,
+		`{ monitors }`:                                                                      "must have a sub selection",
+		`{ monitors { channel { x } } }`:                                                    "must not have a sub selection",
+		`{ monitors(limit: 1) { channel } }`:                                                `Unknown argument "limit"`,
+		`{ monitors(provider: 1) { channel } }`:                                             `Argument "provider" has invalid value`,
+		`{ monitors(provider: $b) { channel } }`:                                            `Variable "$b" is not defined`,
+		`mutation { monitors { channel } }`:                                                 "not configured for mutations",
+		`{ monitors { ...A } } fragment A on Monitor { ...A }`:                              "Cannot spread fragment",
+		`{ monitors(provider: "unterminated) { channel } }`:                                 "Unterminated string",
+		`query A { monitors { channel } } query B { rankings { rank } }`:                    "Must provide operation name",
+		`{ providers { services { channels { channel } } } }`:                               "查询嵌套深度 4 超过上限 2",
+		`{ providers { ...P } } fragment P on Provider { services { channels { model } } }`: "查询嵌套深度 4 超过上限 2",
+		`{ __schema { types { name } } }`:                                                   "不支持内省查询",
+	}
+	for query, want := range cases {
+		resp := executeGraphQL(context.Background(), schema, graphqlRequest{Query: query}, 2)
+		if resp.Data != nil || len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, want) {
+			t.Fatalf("query %s: expected error containing %q, got data=%v errors=%+v", query, want, resp.Data, resp.Errors)
+		}
+	}
+}
+
+func TestExecuteGraphQLFieldErrorKeepsOtherFields(t *testing.T) {
+	t.Parallel()
+
+	schema, err := newGraphQLSchema(&Handler{})
+	if err != nil {
+		t.Fatalf("newGraphQLSchema: %v", err)
+	}
+	// 片段展开不增加深度：events 字段位于第 1 层，其子字段位于第 2 层
+	resp := executeGraphQL(context.Background(), schema, graphqlRequest{
+		Query:     `query Q($n: Int = 5) { __typename ...E } fragment E on Query { events(limit: $n) { id } }`,
+		Variables: map[string]any{"n": float64(3)}, // JSON 解码的数字为 float64
+	}, 2)
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("marshal response: %v", err)
+	}
+	if !strings.Contains(string(data), `"data":{"__typename":"Query","events":null}`) {
+		t.Fatalf("field error should null only that field: %s", data)
+	}
+	if len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "events API Token") ||
+		!reflect.DeepEqual(resp.Errors[0].Path, []any{"events"}) {
+		t.Fatalf("unexpected errors: %s", data)
+	}
+}
@@ -0,0 +1,377 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// 分组来源
+const (
+	groupSourceConfig = "config" // 配置文件 groups
+	groupSourceAdmin  = "admin"  // 管理 API 创建（存储于数据库）
+)
+
+// GroupItem 自定义分组
+type GroupItem struct {
+	ID          string                     `json:"id"`
+	Name        string                     `json:"name"`
+	Description string                     `json:"description,omitempty"`
+	Source      string                     `json:"source"` // config / admin
+	Members     []config.GroupMemberConfig `json:"members"`
+	CreatedAt   int64                      `json:"created_at,omitempty"` // 仅 admin 分组
+	UpdatedAt   int64                      `json:"updated_at,omitempty"` // 仅 admin 分组
+}
+
+// GroupsResponse 分组列表响应（GET /api/groups）
+type GroupsResponse struct {
+	Groups []GroupItem `json:"groups"`
+}
+
+// GroupStatusResponse 分组聚合状态响应（GET /api/groups/:id/status）
+type GroupStatusResponse struct {
+	Group   GroupItem           `json:"group"`
+	Status  string              `json:"status"` // up/down/degraded/unknown
+	Counts  GroupStatusCounts   `json:"counts"`
+	Members []GroupMemberStatus `json:"members"`
+	AsOf    string              `json:"as_of"`
+}
+
+// GroupStatusCounts 按状态统计的成员数
+type GroupStatusCounts struct {
+	Up       int `json:"up"`
+	Degraded int `json:"degraded"`
+	Down     int `json:"down"`
+	Unknown  int `json:"unknown"`   // 已匹配但暂无数据
+	NotFound int `json:"not_found"` // 未匹配任何可见监测项（不存在、已隐藏或已停用）
+}
+
+// GroupMemberStatus 单个成员的当前状态（多模型监测项取各模型的最差状态）
+type GroupMemberStatus struct {
+	config.GroupMemberConfig
+	Found        bool   `json:"found"`
+	ProviderName string `json:"provider_name,omitempty"`
+	ProviderSlug string `json:"provider_slug,omitempty"`
+	ServiceName  string `json:"service_name,omitempty"`
+	ChannelName  string `json:"channel_name,omitempty"`
+	Status       string `json:"status"` // up/down/degraded/unknown
+	LatencyMs    int    `json:"latency_ms,omitempty"`
+	UpdatedAt    string `json:"updated_at,omitempty"`
+}
+
+// SaveGroupRequest 创建或覆盖分组请求（PUT /api/admin/groups/:id）
+type SaveGroupRequest struct {
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	Members     []config.GroupMemberConfig `json:"members"`
+}
+
+// GetGroups 返回全部自定义分组（配置文件分组在前，admin 分组按 id 排序在后）
+// GET /api/groups
+// GET /api/:ns/groups
+func (h *Handler) GetGroups(c *gin.Context) {
+	groups, err := h.loadGroups(c.Request.Context())
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("查询自定义分组失败", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询分组失败"})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, GroupsResponse{Groups: groups})
+}
+
+// GetGroupStatus 返回分组成员的当前状态与聚合状态
+// GET /api/groups/:id/status
+// GET /api/:ns/groups/:id/status（成员仅匹配该命名空间的监测项）
+//
+// 聚合规则（仅统计有数据的成员）：全部正常为 up，全部不可用为 down，其余为 degraded，均无数据时为 unknown
+func (h *Handler) GetGroupStatus(c *gin.Context) {
+	id := c.Param("id")
+	groups, err := h.loadGroups(c.Request.Context())
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("查询自定义分组失败", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询分组失败"})
+		return
+	}
+	var group *GroupItem
+	for i := range groups {
+		if groups[i].ID == id {
+			group = &groups[i]
+			break
+		}
+	}
+	if group == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("分组不存在: %s", id)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+	resp, err := h.queryGroupStatus(ctx, *group)
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("查询分组状态失败", "group", id, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("查询失败: %v", err)})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
+
+// PutAdminGroup 创建或覆盖 admin 分组（与配置文件分组 ID 冲突时返回 409）
+// PUT /api/admin/groups/:id  {"name": "我的 Claude 中转", "members": [{"provider": "88code", "service": "cc"}]}
+func (h *Handler) PutAdminGroup(c *gin.Context) {
+	gs, ok := h.storage.WithContext(c.Request.Context()).(storage.CustomGroupStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "当前存储后端不支持自定义分组"})
+		return
+	}
+
+	var req SaveGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("无效的 JSON: %v", err)})
+		return
+	}
+	def := config.GroupConfig{ID: c.Param("id"), Name: req.Name, Description: req.Description, Members: req.Members}
+	if err := def.Normalize(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if h.configGroup(def.ID) != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("分组 %s 已在配置文件中定义", def.ID)})
+		return
+	}
+
+	now := time.Now().Unix()
+	group := &storage.CustomGroup{
+		ID:          def.ID,
+		Name:        def.Name,
+		Description: def.Description,
+		Members:     make([]storage.CustomGroupMember, 0, len(def.Members)),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	for _, m := range def.Members {
+		group.Members = append(group.Members, storage.CustomGroupMember(m))
+	}
+	if err := gs.SaveCustomGroup(group); err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("保存自定义分组失败", "group", def.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "保存分组失败"})
+		return
+	}
+
+	h.audit.Record(c.Request.Context(), storage.AuditEntry{
+		Actor:  adminActorOf(c),
+		Action: "group.save",
+		Target: group.ID,
+		Detail: fmt.Sprintf("name=%s members=%d", group.Name, len(group.Members)),
+		IP:     c.ClientIP(),
+	})
+
+	c.JSON(http.StatusOK, toGroupItem(group))
+}
+
+// DeleteAdminGroup 删除 admin 分组（配置文件分组只能通过修改配置删除）
+// DELETE /api/admin/groups/:id
+func (h *Handler) DeleteAdminGroup(c *gin.Context) {
+	gs, ok := h.storage.WithContext(c.Request.Context()).(storage.CustomGroupStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "当前存储后端不支持自定义分组"})
+		return
+	}
+
+	id := c.Param("id")
+	if h.configGroup(id) != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("分组 %s 在配置文件中定义，无法通过 API 删除", id)})
+		return
+	}
+	deleted, err := gs.DeleteCustomGroup(id)
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("删除自定义分组失败", "group", id, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除分组失败"})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("分组不存在: %s", id)})
+		return
+	}
+
+	h.audit.Record(c.Request.Context(), storage.AuditEntry{
+		Actor:  adminActorOf(c),
+		Action: "group.delete",
+		Target: id,
+		IP:     c.ClientIP(),
+	})
+
+	c.JSON(http.StatusOK, gin.H{"deleted": id})
+}
+
+// configGroup 返回配置文件中指定 ID 的分组
+func (h *Handler) configGroup(id string) *config.GroupConfig {
+	h.cfgMu.RLock()
+	defer h.cfgMu.RUnlock()
+	for i := range h.config.Groups {
+		if h.config.Groups[i].ID == id {
+			g := h.config.Groups[i]
+			return &g
+		}
+	}
+	return nil
+}
+
+// loadGroups 合并配置文件分组与 admin 分组（ID 冲突时以配置文件为准）
+func (h *Handler) loadGroups(ctx context.Context) ([]GroupItem, error) {
+	h.cfgMu.RLock()
+	groups := make([]GroupItem, 0, len(h.config.Groups))
+	for _, g := range h.config.Groups {
+		groups = append(groups, GroupItem{
+			ID:          g.ID,
+			Name:        g.Name,
+			Description: g.Description,
+			Source:      groupSourceConfig,
+			Members:     append([]config.GroupMemberConfig(nil), g.Members...),
+		})
+	}
+	h.cfgMu.RUnlock()
+
+	gs, ok := h.storage.WithContext(ctx).(storage.CustomGroupStorage)
+	if !ok {
+		return groups, nil
+	}
+	stored, err := gs.ListCustomGroups()
+	if err != nil {
+		return nil, err
+	}
+	configIDs := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		configIDs[g.ID] = true
+	}
+	for _, g := range stored {
+		if configIDs[g.ID] {
+			logger.Warn("api", "admin 分组与配置文件分组 ID 冲突，已忽略", "group", g.ID)
+			continue
+		}
+		groups = append(groups, toGroupItem(g))
+	}
+	return groups, nil
+}
+
+// toGroupItem 转换数据库中的 admin 分组
+func toGroupItem(g *storage.CustomGroup) GroupItem {
+	item := GroupItem{
+		ID:          g.ID,
+		Name:        g.Name,
+		Description: g.Description,
+		Source:      groupSourceAdmin,
+		Members:     make([]config.GroupMemberConfig, 0, len(g.Members)),
+		CreatedAt:   g.CreatedAt,
+		UpdatedAt:   g.UpdatedAt,
+	}
+	for _, m := range g.Members {
+		item.Members = append(item.Members, config.GroupMemberConfig(m))
+	}
+	return item
+}
+
+// queryGroupStatus 查询分组成员的最新状态（排除禁用与隐藏的监测项，全部成员合并为一次 GetLatestBatch）
+// 分组定义全局共享，成员只在当前命名空间内匹配，避免公开接口暴露其他命名空间的监测项
+func (h *Handler) queryGroupStatus(ctx context.Context, group GroupItem) (*GroupStatusResponse, error) {
+	h.cfgMu.RLock()
+	monitors := h.config.Monitors
+	h.cfgMu.RUnlock()
+
+	matched := make([][]config.ServiceConfig, len(group.Members))
+	var keys []storage.MonitorKey
+	for i, m := range group.Members {
+		for _, task := range monitors {
+			if task.Disabled || task.Hidden || !m.Matches(task) {
+				continue
+			}
+			matched[i] = append(matched[i], task)
+			keys = append(keys, storage.MonitorKey{Provider: task.Provider, Service: task.Service, Channel: task.Channel, Model: task.Model})
+		}
+	}
+
+	latest := map[storage.MonitorKey]*storage.ProbeRecord{}
+	if len(keys) > 0 {
+		var err error
+		if latest, err = h.storage.WithContext(ctx).GetLatestBatch(keys); err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &GroupStatusResponse{
+		Group:   group,
+		Members: make([]GroupMemberStatus, 0, len(group.Members)),
+		AsOf:    time.Now().UTC().Format(time.RFC3339),
+	}
+	for i, m := range group.Members {
+		ms := GroupMemberStatus{GroupMemberConfig: m, Status: statusQueryV2Status(-1)}
+		if len(matched[i]) == 0 {
+			resp.Counts.NotFound++
+			resp.Members = append(resp.Members, ms)
+			continue
+		}
+
+		first := matched[i][0]
+		ms.Found = true
+		ms.ProviderName, ms.ServiceName, ms.ChannelName = first.ProviderName, first.ServiceName, first.ChannelName
+		ms.ProviderSlug = first.ProviderSlug
+		if ms.ProviderSlug == "" {
+			ms.ProviderSlug = strings.ToLower(strings.TrimSpace(first.Provider))
+		}
+
+		worst := -1
+		var worstRecord *storage.ProbeRecord
+		for _, task := range matched[i] {
+			rec := latest[storage.MonitorKey{Provider: task.Provider, Service: task.Service, Channel: task.Channel, Model: task.Model}]
+			if rec == nil {
+				continue
+			}
+			if next := pickWorstStatus(worst, rec.Status); next != worst || worstRecord == nil {
+				worst, worstRecord = next, rec
+			}
+		}
+		ms.Status = statusQueryV2Status(worst)
+		if worstRecord != nil {
+			ms.LatencyMs = worstRecord.Latency
+			ms.UpdatedAt = time.Unix(worstRecord.Timestamp, 0).UTC().Format(time.RFC3339)
+		}
+
+		switch worst {
+		case 1:
+			resp.Counts.Up++
+		case 2:
+			resp.Counts.Degraded++
+		case 0:
+			resp.Counts.Down++
+		default:
+			resp.Counts.Unknown++
+		}
+		resp.Members = append(resp.Members, ms)
+	}
+	resp.Status = aggregateGroupStatus(resp.Counts)
+	return resp, nil
+}
+
+// aggregateGroupStatus 计算分组聚合状态（见 GetGroupStatus 的规则说明）
+func aggregateGroupStatus(counts GroupStatusCounts) string {
+	total := counts.Up + counts.Degraded + counts.Down
+	switch {
+	case total == 0:
+		return "unknown"
+	case counts.Up == total:
+		return "up"
+	case counts.Down == total:
+		return "down"
+	default:
+		return "degraded"
+	}
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestGroupsAPI(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	now := time.Now().Unix()
+	for _, r := range []*storage.ProbeRecord{
+		{Provider: "Relay", Service: "cc", Channel: "vip", Model: "opus", Status: 1, Latency: 100, Timestamp: now - 60},
+		{Provider: "Relay", Service: "cc", Channel: "vip", Model: "sonnet", Status: 0, Latency: 0, Timestamp: now - 30},
+		{Provider: "Other", Service: "cc", Status: 1, Latency: 200, Timestamp: now - 10},
+	} {
+		if err := store.SaveRecord(r); err != nil {
+			t.Fatalf("save record: %v", err)
+		}
+	}
+
+	srv := NewServer(store, &config.AppConfig{
+		Audit: config.AuditConfig{APIToken: "admin-token"},
+		Monitors: []config.ServiceConfig{
+			{Provider: "Relay", ProviderSlug: "relay", Service: "cc", Channel: "vip", Model: "opus"},
+			{Provider: "Relay", ProviderSlug: "relay", Service: "cc", Channel: "vip", Model: "sonnet"},
+			{Provider: "Other", ProviderSlug: "other", Service: "cc"},
+			{Provider: "Secret", Service: "cc", Hidden: true},
+		},
+		Groups: []config.GroupConfig{{
+			ID:   "paid",
+			Name: "付费中转",
+			Members: []config.GroupMemberConfig{
+				{Provider: "relay", Service: "cc", Channel: "vip"},
+				{Provider: "Other", Service: "cc"},
+				{Provider: "Secret", Service: "cc"},
+			},
+		}},
+	})
+	serve := func(method, target, body string, admin bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if admin {
+			req.Header.Set("Authorization", "Bearer admin-token")
+		}
+		w := httptest.NewRecorder()
+		srv.router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(http.MethodGet, "/api/groups/paid/status", "", false)
+	var status GroupStatusResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("unexpected status response: %d %s", w.Code, w.Body.String())
+	}
+	if status.Status != "degraded" || status.Counts != (GroupStatusCounts{Up: 1, Down: 1, NotFound: 1}) {
+		t.Fatalf("unexpected aggregate: %s %+v", status.Status, status.Counts)
+	}
+	if m := status.Members[0]; !m.Found || m.Status != "down" || m.ProviderSlug != "relay" {
+		t.Fatalf("multi-model member should take worst status: %+v", m)
+	}
+	if m := status.Members[2]; m.Found || m.Status != "unknown" {
+		t.Fatalf("hidden monitor should not be found: %+v", m)
+	}
+
+	// 管理 API 创建分组；与配置文件分组 ID 冲突时拒绝
+	if w := serve(http.MethodPut, "/api/admin/groups/paid", `{"members":[{"provider":"other","service":"cc"}]}`, true); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for config group id, got %d", w.Code)
+	}
+	if w := serve(http.MethodPut, "/api/admin/groups/Bad%20Id", `{"members":[{"provider":"other","service":"cc"}]}`, true); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid id, got %d", w.Code)
+	}
+	if w := serve(http.MethodPut, "/api/admin/groups/mine", `{"members":[{"provider":"other","service":"cc"}]}`, false); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without credentials, got %d", w.Code)
+	}
+	w = serve(http.MethodPut, "/api/admin/groups/mine", `{"name":"我的","members":[{"provider":"other","service":"cc"}]}`, true)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var list GroupsResponse
+	w = serve(http.MethodGet, "/api/groups", "", false)
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Groups) != 2 {
+		t.Fatalf("unexpected group list: %s", w.Body.String())
+	}
+	if list.Groups[0].Source != "config" || list.Groups[1].Source != "admin" || list.Groups[1].Name != "我的" || list.Groups[1].CreatedAt == 0 {
+		t.Fatalf("unexpected groups: %+v", list.Groups)
+	}
+
+	w = serve(http.MethodGet, "/api/groups/mine/status", "", false)
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil || status.Status != "up" || status.Members[0].LatencyMs != 200 {
+		t.Fatalf("unexpected admin group status: %s", w.Body.String())
+	}
+
+	if w := serve(http.MethodDelete, "/api/admin/groups/mine", "", true); w.Code != http.StatusOK {
+		t.Fatalf("expected delete 200, got %d", w.Code)
+	}
+	if w := serve(http.MethodGet, "/api/groups/mine/status", "", false); w.Code != http.StatusNotFound {
+		t.Fatalf("deleted group should return 404, got %d", w.Code)
+	}
+	if w := serve(http.MethodDelete, "/api/admin/groups/paid", "", true); w.Code != http.StatusConflict {
+		t.Fatalf("config group should not be deletable, got %d", w.Code)
+	}
+}
+
+func TestGroupStatusNamespaceScoped(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	now := time.Now().Unix()
+	for _, r := range []*storage.ProbeRecord{
+		{Provider: "Public", Service: "cc", Status: 1, Latency: 100, Timestamp: now - 30},
+		{Provider: "Tenant", Service: "cc", Status: 0, Timestamp: now - 30},
+	} {
+		if err := store.SaveRecord(r); err != nil {
+			t.Fatalf("save record: %v", err)
+		}
+	}
+
+	// 两个命名空间共用同一分组定义，各自只能看到本命名空间的成员
+	srv := NewServer(store, &config.AppConfig{
+		Namespaces: []config.NamespaceConfig{{Name: "team-a"}},
+		Monitors: []config.ServiceConfig{
+			{Provider: "Public", Service: "cc"},
+			{Provider: "Tenant", Service: "cc", Namespace: "team-a"},
+		},
+		Groups: []config.GroupConfig{{
+			ID: "shared",
+			Members: []config.GroupMemberConfig{
+				{Provider: "Public", Service: "cc"},
+				{Provider: "Tenant", Service: "cc"},
+			},
+		}},
+	})
+	status := func(target string) GroupStatusResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		srv.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		var resp GroupStatusResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("%s: unexpected response: %d %s", target, w.Code, w.Body.String())
+		}
+		return resp
+	}
+
+	def := status("/api/groups/shared/status")
+	if def.Status != "up" || !def.Members[0].Found || def.Members[1].Found || def.Counts != (GroupStatusCounts{Up: 1, NotFound: 1}) {
+		t.Fatalf("default namespace leaked tenant member: %s %+v", def.Status, def.Members)
+	}
+	tenant := status("/api/team-a/groups/shared/status")
+	if tenant.Status != "down" || tenant.Members[0].Found || !tenant.Members[1].Found || tenant.Counts != (GroupStatusCounts{Down: 1, NotFound: 1}) {
+		t.Fatalf("tenant namespace leaked default member: %s %+v", tenant.Status, tenant.Members)
+	}
+}
@@ -4,27 +4,36 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/singleflight"
 
+	"monitor/internal/audit"
+	"monitor/internal/baseline"
+	"monitor/internal/boards"
 	"monitor/internal/config"
 	"monitor/internal/logger"
+	"monitor/internal/scheduler"
 	"monitor/internal/selftest"
+	"monitor/internal/sla"
 	"monitor/internal/storage"
 )
 
-// TimeFilter 每日时段过滤器（UTC 时区）
+// TimeFilter 每日时段过滤器（默认 UTC 时区，可通过 tz 参数指定）
 // 用于过滤特定时间段内的探测记录，如工作时间 09:00-17:00
 // 支持跨午夜的时间范围，如 22:00-04:00（表示 22:00 到次日 04:00）
+// 时区不存储在过滤器内，而是取时间窗口 endTime 的 Location（见 ContainsIn）
 type TimeFilter struct {
 	StartHour     int  // 开始小时 (0-23)
 	StartMinute   int  // 开始分钟 (0 或 30)
@@ -39,7 +48,15 @@ var timeFilterRegex = regexp.MustCompile(`^(\d{2}):(\d{2})-(\d{2}):(\d{2})$`)
 // Contains 检查给定 UTC 时间是否在时段范围内（左闭右开区间）
 // 支持跨午夜的时间范围，如 22:00-04:00
 func (f *TimeFilter) Contains(t time.Time) bool {
-	h, m, _ := t.UTC().Clock()
+	return f.ContainsIn(t, time.UTC)
+}
+
+// ContainsIn 按指定时区的本地时钟检查时间是否在时段范围内（左闭右开区间）
+func (f *TimeFilter) ContainsIn(t time.Time, loc *time.Location) bool {
+	if loc == nil {
+		loc = time.UTC
+	}
+	h, m, _ := t.In(loc).Clock()
 	startMinutes := f.StartHour*60 + f.StartMinute
 	endMinutes := f.EndHour*60 + f.EndMinute
 	currentMinutes := h*60 + m
@@ -113,41 +130,112 @@ func ParseTimeFilter(param string) (*TimeFilter, error) {
 	}, nil
 }
 
+// parseTimezone 解析 tz 参数（IANA 时区名，如 Asia/Shanghai）
+// 空值表示 UTC；不接受 "Local"，避免结果依赖服务器时区
+func parseTimezone(tz string) (*time.Location, error) {
+	tz = strings.TrimSpace(tz)
+	if tz == "" || strings.EqualFold(tz, "UTC") {
+		return time.UTC, nil
+	}
+	if tz == "Local" {
+		return nil, fmt.Errorf("无效的时区: %s（请使用 IANA 时区名，如 Asia/Shanghai）", tz)
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("无效的时区: %s（请使用 IANA 时区名，如 Asia/Shanghai）", tz)
+	}
+	return loc, nil
+}
+
 // statusCache API 响应缓存，防止高频查询打爆数据库
+//
+// 条目过期后的 staleTTL 内仍会返回旧数据并在后台刷新（stale-while-revalidate），
+// 查询失败会缓存 errorTTL（negative caching），数据库抖动时不会让每个请求都重新查询并返回 500
 type statusCache struct {
-	mu      sync.RWMutex
-	entries map[string]*cacheEntry
-	ttl     time.Duration
-	maxSize int                // 最大缓存条目数，防止内存泄漏
-	sf      singleflight.Group // 防止缓存击穿
+	mu       sync.RWMutex
+	entries  map[string]*cacheEntry
+	errors   map[string]*cacheError // 最近失败的查询（negative caching）
+	ttl      time.Duration
+	staleTTL time.Duration      // 过期后仍可返回旧数据的时长（0 表示关闭）
+	errorTTL time.Duration      // 查询失败的缓存时长（0 表示关闭）
+	maxSize  int                // 最大缓存条目数，防止内存泄漏
+	sf       singleflight.Group // 防止缓存击穿
 }
 
 type cacheEntry struct {
-	data     []byte
+	data       []byte
+	expireAt   time.Time
+	staleUntil time.Time // 超过后彻底失效（>= expireAt）
+
+	refreshing atomic.Bool // 是否已有后台刷新在进行
+
+	// 预压缩结果（首次被支持 gzip 的客户端命中时生成，之后复用）
+	gzOnce sync.Once
+	gz     []byte
+
+	// 其他线格式的转码结果（按 Accept 协商，首次请求时生成）
+	variantsMu sync.Mutex
+	variants   map[wireFormat]*cacheEntry
+}
+
+// cacheError 缓存的查询错误
+type cacheError struct {
+	err      error
 	expireAt time.Time
 }
 
+// gzipped 返回 data 的 gzip 压缩结果（同一条目只压缩一次）
+func (e *cacheEntry) gzipped() []byte {
+	e.gzOnce.Do(func() {
+		e.gz = gzipBytes(e.data)
+	})
+	return e.gz
+}
+
 func newStatusCache(ttl time.Duration, maxSize int) *statusCache {
 	return &statusCache{
 		entries: make(map[string]*cacheEntry),
+		errors:  make(map[string]*cacheError),
 		ttl:     ttl,
 		maxSize: maxSize,
 	}
 }
 
-// get 获取缓存，过期则删除并返回 miss
-func (c *statusCache) get(key string) ([]byte, bool) {
+// configure 设置最大条目数与过期数据、错误的缓存时长（配置热更新时调用，对新写入的条目生效）
+// maxSize <= 0 时使用默认值
+func (c *statusCache) configure(maxSize int, staleTTL, errorTTL time.Duration) {
+	if maxSize <= 0 {
+		maxSize = config.DefaultCacheMaxEntries
+	}
+	c.mu.Lock()
+	c.maxSize = maxSize
+	c.staleTTL = staleTTL
+	c.errorTTL = errorTTL
+	c.mu.Unlock()
+}
+
+// get 获取未过期的缓存
+func (c *statusCache) get(key string) (*cacheEntry, bool) {
+	entry, fresh := c.lookup(key)
+	if entry == nil || !fresh {
+		return nil, false
+	}
+	return entry, true
+}
+
+// lookup 获取缓存条目，fresh 为 false 表示已过期但仍在 stale 窗口内；彻底失效则删除并返回 nil
+func (c *statusCache) lookup(key string) (entry *cacheEntry, fresh bool) {
 	now := time.Now()
 	c.mu.RLock()
-	entry := c.entries[key]
+	entry = c.entries[key]
 	c.mu.RUnlock()
 
 	if entry == nil {
 		return nil, false
 	}
 
-	if now.After(entry.expireAt) {
-		// 懒清理：删除过期 key
+	if now.After(entry.staleUntil) {
+		// 懒清理：删除失效 key
 		c.mu.Lock()
 		if cur := c.entries[key]; cur == entry {
 			delete(c.entries, key)
@@ -156,7 +244,7 @@ func (c *statusCache) get(key string) ([]byte, bool) {
 		return nil, false
 	}
 
-	return entry.data, true
+	return entry, !now.After(entry.expireAt)
 }
 
 // set 存入缓存（拷贝数据，防止 buffer 复用问题）
@@ -164,8 +252,9 @@ func (c *statusCache) set(key string, data []byte) {
 	c.setWithTTL(key, data, c.ttl)
 }
 
-// setWithTTL 存入缓存（支持自定义 TTL）
-func (c *statusCache) setWithTTL(key string, data []byte, ttl time.Duration) {
+// setWithTTL 存入缓存（支持自定义 TTL），返回新条目（容量已满未写入时同样返回）
+// 写入成功后清除该 key 缓存的错误
+func (c *statusCache) setWithTTL(key string, data []byte, ttl time.Duration) *cacheEntry {
 	if ttl <= 0 {
 		ttl = c.ttl
 	}
@@ -174,13 +263,21 @@ func (c *statusCache) setWithTTL(key string, data []byte, ttl time.Duration) {
 	copy(buf, data)
 
 	now := time.Now()
+	entry := &cacheEntry{
+		data:     buf,
+		expireAt: now.Add(ttl),
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	// 容量限制：超出时清理过期条目
+	entry.staleUntil = entry.expireAt.Add(c.staleTTL)
+	delete(c.errors, key)
+
+	// 容量限制：超出时清理失效条目
 	if len(c.entries) >= c.maxSize {
 		for k, v := range c.entries {
-			if now.After(v.expireAt) {
+			if now.After(v.staleUntil) {
 				delete(c.entries, k)
 			}
 		}
@@ -188,75 +285,184 @@ func (c *statusCache) setWithTTL(key string, data []byte, ttl time.Duration) {
 
 	// 仍然超出则跳过写入（防止 DoS）
 	if len(c.entries) >= c.maxSize {
+		return entry
+	}
+
+	c.entries[key] = entry
+	return entry
+}
+
+// cachedError 返回该 key 未过期的查询错误
+func (c *statusCache) cachedError(key string) error {
+	c.mu.RLock()
+	ce := c.errors[key]
+	c.mu.RUnlock()
+	if ce == nil || time.Now().After(ce.expireAt) {
+		return nil
+	}
+	return ce.err
+}
+
+// setError 缓存查询错误（errorTTL 为 0 时不缓存）
+func (c *statusCache) setError(key string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.errorTTL <= 0 {
 		return
 	}
 
-	c.entries[key] = &cacheEntry{
-		data:     buf,
-		expireAt: now.Add(ttl),
+	now := time.Now()
+	if len(c.errors) >= c.maxSize {
+		for k, v := range c.errors {
+			if now.After(v.expireAt) {
+				delete(c.errors, k)
+			}
+		}
+		if len(c.errors) >= c.maxSize {
+			return
+		}
 	}
+	c.errors[key] = &cacheError{err: err, expireAt: now.Add(c.errorTTL)}
 }
 
 // clear 清空所有缓存（配置热更新时调用）
 func (c *statusCache) clear() {
 	c.mu.Lock()
 	c.entries = make(map[string]*cacheEntry)
+	c.errors = make(map[string]*cacheError)
 	c.mu.Unlock()
 }
 
-// load 获取缓存，未命中时用 singleflight 合并并发请求
-func (c *statusCache) load(key string, loader func() ([]byte, error)) ([]byte, error) {
-	return c.loadWithTTL(key, c.ttl, loader)
+// loadWithTTL 获取缓存（支持自定义 TTL），未命中时用 singleflight 合并并发请求
+// 命中情况记录到 ctx 所属请求的访问日志
+func (c *statusCache) loadWithTTL(ctx context.Context, key string, ttl time.Duration, loader func() ([]byte, error)) ([]byte, error) {
+	entry, err := c.loadEntry(ctx, key, ttl, loader)
+	if err != nil {
+		return nil, err
+	}
+	return entry.data, nil
 }
 
-// loadWithTTL 获取缓存（支持自定义 TTL），未命中时用 singleflight 合并并发请求
-func (c *statusCache) loadWithTTL(key string, ttl time.Duration, loader func() ([]byte, error)) ([]byte, error) {
+// loadEntry 同 loadWithTTL，但返回缓存条目本身，供 writeCached 复用预压缩结果
+// 命中过期条目时立即返回旧数据并在后台刷新，因此 loader 可能在请求结束后执行，不得引用 gin.Context
+// ttl <= 0 表示不缓存（端点关闭缓存或服务商配置了 bypass），直接执行 loader
+func (c *statusCache) loadEntry(ctx context.Context, key string, ttl time.Duration, loader func() ([]byte, error)) (*cacheEntry, error) {
+	if ttl <= 0 {
+		recordCacheStatus(ctx, cacheStatusBypass)
+		data, err := loader()
+		if err != nil {
+			return nil, err
+		}
+		return &cacheEntry{data: data}, nil
+	}
+
 	// 先检查缓存
-	if data, ok := c.get(key); ok {
-		return data, nil
+	if entry, fresh := c.lookup(key); entry != nil {
+		if !fresh {
+			recordCacheStatus(ctx, cacheStatusStale)
+			c.revalidate(key, entry, ttl, loader)
+		} else {
+			recordCacheStatus(ctx, cacheStatusHit)
+		}
+		return entry, nil
 	}
+	if err := c.cachedError(key); err != nil {
+		recordCacheStatus(ctx, cacheStatusError)
+		return nil, err
+	}
+	recordCacheStatus(ctx, cacheStatusMiss)
 
 	// singleflight: 同 key 多请求只执行一次 loader
 	v, err, _ := c.sf.Do(key, func() (interface{}, error) {
 		// double check：可能在等待期间已被其他 goroutine 填充
-		if data, ok := c.get(key); ok {
-			return data, nil
-		}
-
-		fresh, err := loader()
-		if err != nil {
-			return nil, err // 错误不缓存
+		if entry, ok := c.get(key); ok {
+			return entry, nil
 		}
-
-		c.setWithTTL(key, fresh, ttl)
-		return fresh, nil
+		return c.fill(key, ttl, loader)
 	})
 
 	if err != nil {
 		return nil, err
 	}
-	return v.([]byte), nil
+	return v.(*cacheEntry), nil
+}
+
+// fill 执行 loader 并写入缓存，失败时缓存错误
+func (c *statusCache) fill(key string, ttl time.Duration, loader func() ([]byte, error)) (*cacheEntry, error) {
+	fresh, err := loader()
+	if err != nil {
+		c.setError(key, err)
+		return nil, err
+	}
+	return c.setWithTTL(key, fresh, ttl), nil
+}
+
+// revalidate 在后台刷新过期条目（每个条目同时只有一次刷新；最近刷新失败时等错误缓存过期后再重试）
+func (c *statusCache) revalidate(key string, entry *cacheEntry, ttl time.Duration, loader func() ([]byte, error)) {
+	if c.cachedError(key) != nil || !entry.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer entry.refreshing.Store(false)
+		_, err, _ := c.sf.Do(key, func() (interface{}, error) {
+			return c.fill(key, ttl, loader)
+		})
+		if err != nil {
+			logger.Warn("api", "缓存后台刷新失败，继续返回过期数据", "cache_key", key, "error", err)
+		}
+	}()
 }
 
 // Handler API处理器
 type Handler struct {
 	storage     storage.Storage
-	config      *config.AppConfig
+	config      *config.AppConfig        // 公开 API 使用的配置（启用多命名空间时仅含本命名空间的监测项）
+	fullConfig  *config.AppConfig        // 完整配置（含全部命名空间，供管理 API 与服务商门户使用）
+	baseConfig  *config.AppConfig        // 未叠加板块自动调整结果的完整配置
 	cfgMu       sync.RWMutex             // 保护config的并发访问
-	cache       *statusCache             // API 响应缓存
+	updateMu    sync.Mutex               // 串行化配置更新（热更新与板块自动调整刷新）
+	cache       *statusCache             // API 响应缓存（/api/status 及共用缓存的接口）
+	eventsCache *statusCache             // /api/events 响应缓存
+	provCache   *statusCache             // 服务商页面响应缓存
+	siteCache   *statusCache             // sitemap.xml 缓存
+	namespace   string                   // 所属命名空间（"" 为默认命名空间）
+	namespaces  map[string]*Handler      // 命名空间子处理器（仅默认命名空间处理器持有，各自独立缓存）
 	selfTestMgr *selftest.TestJobManager // 自助测试管理器（可选）
+	audit       *audit.Recorder          // 审计日志记录器（可选）
+	graphql     *graphql.Schema          // GraphQL schema（/api/graphql，构建失败时为 nil，端点返回 503）
+	scheduler   *scheduler.Scheduler     // 调度器（可选，/api/admin/scheduler/tasks）
+	overrides   *config.OverrideStore    // 运行时覆盖存储（可选，/api/admin/overrides）
+	sla         *sla.Evaluator           // SLA 评估任务（可选，/api/sla）
+	boards      *boards.Engine           // 板块自动调整任务（可选，叠加在配置的板块之上）
+	maintainer  *storage.Maintainer      // 数据库维护任务（可选，/api/admin/storage/maintenance）
+
+	loginLimiter *loginLimiter // 管理后台登录限流（/api/admin/login，仅默认命名空间处理器持有）
+
+	warmMu     sync.Mutex         // 保护 warmCancel
+	warmCancel context.CancelFunc // 取消进行中的后台缓存预热（缓存再次清空时重新开始）
 }
 
 // NewHandler 创建处理器
 func NewHandler(store storage.Storage, cfg *config.AppConfig) *Handler {
-	return &Handler{
-		storage: store,
-		config:  cfg,
-		cache:   newStatusCache(10*time.Second, 100), // 10 秒缓存，最多 100 条
+	h := &Handler{
+		storage:    store,
+		config:     cfg.ForNamespace(""),
+		fullConfig: cfg,
+		baseConfig: cfg,
+
+		loginLimiter: newLoginLimiter(),
+	}
+	h.initCaches(cfg)
+	schema, err := newGraphQLSchema(h)
+	if err != nil {
+		logger.Error("api", "GraphQL 端点不可用", "error", err)
 	}
+	h.graphql = schema
+	h.syncNamespaces(cfg)
+	return h
 }
 
-// SetSelfTestManager 设置自助测试管理器（可选）
+// SetSelfTestManager 设置自助测试管理器（可选，未设置或 selftest.enabled 关闭时自助测试接口返回 503）
 func (h *Handler) SetSelfTestManager(mgr *selftest.TestJobManager) {
 	h.selfTestMgr = mgr
 }
@@ -266,6 +472,10 @@ type CurrentStatus struct {
 	Status    int   `json:"status"`
 	Latency   int   `json:"latency"`
 	Timestamp int64 `json:"timestamp"`
+
+	CertDaysRemaining *int `json:"cert_days_remaining,omitempty"` // 服务端证书剩余有效天数（仅 HTTPS）
+
+	Correlation *baseline.Correlation `json:"correlation,omitempty"` // 官方基线归因（仅红/黄且配置了同 service 的基线时出现）
 }
 
 // MonitorResult API返回结构
@@ -274,6 +484,7 @@ type MonitorResult struct {
 	ProviderName  string                 `json:"provider_name,omitempty"` // Provider 显示名称
 	ProviderSlug  string                 `json:"provider_slug"`           // URL slug（用于生成专属页面链接）
 	ProviderURL   string                 `json:"provider_url"`            // 服务商官网链接
+	ProviderLogo  string                 `json:"provider_logo,omitempty"` // 服务商 Logo 图片链接
 	Service       string                 `json:"service"`
 	ServiceName   string                 `json:"service_name,omitempty"`  // Service 显示名称
 	Category      string                 `json:"category"`                // 分类：commercial（商业站）或 public（公益站）
@@ -293,6 +504,9 @@ type MonitorResult struct {
 	TemplateName  string                 `json:"template_name,omitempty"` // 请求体模板名称（如有）
 	IntervalMs    int64                  `json:"interval_ms"`             // 监测间隔（毫秒）
 	SlowLatencyMs int64                  `json:"slow_latency_ms"`         // 慢请求阈值（毫秒）
+	Retired       bool                   `json:"retired,omitempty"`       // 已从配置移除（仅 include_retired=true 时出现）
+	RetiredAt     int64                  `json:"retired_at,omitempty"`    // 下线时间（Unix 秒）
+	Annotations   []AnnotationItem       `json:"annotations,omitempty"`   // 与查询时间范围重叠的运维标注
 	Current       *CurrentStatus         `json:"current_status"`
 	Timeline      []storage.TimePoint    `json:"timeline"`
 }
@@ -313,15 +527,51 @@ func (h *Handler) GetStatus(c *gin.Context) {
 	}
 	// include_hidden 参数：用于内部调试，默认不包含隐藏的监测项
 	includeHidden := strings.EqualFold(strings.TrimSpace(c.DefaultQuery("include_hidden", "false")), "true")
-
-	// 验证 period 参数
-	if _, err := h.parsePeriod(period); err != nil {
+	// include_retired 参数：同时返回宽限期内已从配置移除的监测项（retired=true）
+	includeRetired := strings.EqualFold(strings.TrimSpace(c.DefaultQuery("include_retired", "false")), "true")
+	// from/to 参数：自定义日期范围（YYYY-MM-DD，UTC，含首尾），指定后忽略 period/align
+	qFrom := strings.TrimSpace(c.Query("from"))
+	qTo := strings.TrimSpace(c.Query("to"))
+	// tz 参数：IANA 时区名，影响 time_filter、按天对齐边界与 from/to 日期（默认 UTC）
+	loc, err := parseTimezone(c.Query("tz"))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": fmt.Sprintf("无效的时间范围: %s", period),
+			"error": err.Error(),
 		})
 		return
 	}
 
+	h.cfgMu.RLock()
+	maxRangeDays := h.config.MaxStatusRangeDays
+	h.cfgMu.RUnlock()
+
+	var rng *customRange
+	if qFrom != "" || qTo != "" {
+		rng, err = parseCustomRange(qFrom, qTo, maxRangeDays, time.Now(), loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		period = rng.period()
+		align = ""
+	} else {
+		// 验证 period 参数
+		if _, err := h.parsePeriod(period); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("无效的时间范围: %s", period),
+			})
+			return
+		}
+		if days, ok := parsePeriodDays(period); ok && days > maxRangeDays {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("时间范围 %s 超出允许的最大天数 %d", period, maxRangeDays),
+			})
+			return
+		}
+	}
+
 	// 验证 align 参数
 	if align != "" && align != "hour" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -341,10 +591,10 @@ func (h *Handler) GetStatus(c *gin.Context) {
 	// 验证 time_filter 参数
 	var timeFilter *TimeFilter
 	if timeFilterParam != "" {
-		// 时段过滤仅支持 7d 和 30d 周期
-		if period == "90m" || period == "24h" || period == "1d" {
+		// 时段过滤仅支持按天聚合的周期（7d/30d/90d/180d 及自定义范围）
+		if !usesDailyBuckets(period) {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "时段过滤仅支持 7d 和 30d 周期",
+				"error": "时段过滤仅支持 7d/30d/90d/180d 周期及自定义范围",
 			})
 			return
 		}
@@ -359,20 +609,23 @@ func (h *Handler) GetStatus(c *gin.Context) {
 		}
 	}
 
-	// 构建缓存 key（使用明确的分隔符避免碰撞）
-	cacheKey := fmt.Sprintf("p=%s|align=%s|tf=%s|prov=%s|svc=%s|board=%s|hidden=%t", period, align, timeFilterParam, qProvider, qService, qBoard, includeHidden)
+	cacheKey := statusCacheKey(period, align, timeFilterParam, qProvider, qService, qBoard, includeHidden, includeRetired, rng, loc)
 
-	// 从配置获取缓存 TTL（线程安全）
+	// 从配置获取缓存 TTL（线程安全），故障处理期间配置了 bypass 的服务商直接查询
 	h.cfgMu.RLock()
 	cacheTTL := h.config.CacheTTL.TTLForPeriod(period)
+	if h.cacheBypassed(qProvider) {
+		cacheTTL = 0
+	}
 	h.cfgMu.RUnlock()
 
 	// 使用缓存（singleflight 防止缓存击穿）
-	// 注意：使用独立 context，避免单个请求取消影响其他等待的请求
-	data, err := h.cache.loadWithTTL(cacheKey, cacheTTL, func() ([]byte, error) {
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// 注意：使用独立 context（仅保留追踪信息），避免单个请求取消影响其他等待的请求
+	reqCtx := context.WithoutCancel(c.Request.Context())
+	entry, err := h.cache.loadEntry(reqCtx, cacheKey, cacheTTL, func() ([]byte, error) {
+		ctx, cancel := context.WithTimeout(reqCtx, 30*time.Second)
 		defer cancel()
-		return h.queryAndSerialize(ctx, period, align, timeFilter, qProvider, qService, qBoard, includeHidden)
+		return h.queryAndSerialize(ctx, period, align, loc, rng, timeFilter, qProvider, qService, qBoard, includeHidden, includeRetired)
 	})
 
 	if err != nil {
@@ -383,17 +636,29 @@ func (h *Handler) GetStatus(c *gin.Context) {
 		return
 	}
 
-	// CDN 缓存头：Cloudflare 遵守 s-maxage，浏览器遵守 max-age
-	ttlSeconds := int(cacheTTL.Seconds())
-	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d, s-maxage=%d", ttlSeconds, ttlSeconds))
-	c.Header("Content-Type", "application/json; charset=utf-8")
-	c.Writer.Write(data)
+	setCacheControl(c, cacheTTL)
+	writeCached(c, entry, "application/json; charset=utf-8")
+}
+
+// statusCacheKey 构建 /api/status 缓存 key（使用明确的分隔符避免碰撞，缓存预热复用同一格式）
+func statusCacheKey(period, align, timeFilter, provider, service, board string, includeHidden, includeRetired bool, rng *customRange, loc *time.Location) string {
+	key := fmt.Sprintf("p=%s|align=%s|tf=%s|prov=%s|svc=%s|board=%s|hidden=%t|retired=%t", period, align, timeFilter, provider, service, board, includeHidden, includeRetired)
+	if rng != nil {
+		key += fmt.Sprintf("|range=%s~%s", rng.From, rng.To)
+	}
+	return key + "|tz=" + loc.String()
 }
 
 // queryAndSerialize 查询数据库并序列化为 JSON（缓存 miss 时调用）
-func (h *Handler) queryAndSerialize(ctx context.Context, period, align string, timeFilter *TimeFilter, qProvider, qService, qBoard string, includeHidden bool) ([]byte, error) {
+// loc 为请求时区：endTime 携带该 Location，下游时段过滤与 bucket 标签据此计算
+// rng 非 nil 时使用自定义日期范围，period 为其天数形式（如 "45d"）
+// includeRetired 为 true 时追加宽限期内的已下线监测项（不计入 all_monitor_ids 与服务商综合状态）
+func (h *Handler) queryAndSerialize(ctx context.Context, period, align string, loc *time.Location, rng *customRange, timeFilter *TimeFilter, qProvider, qService, qBoard string, includeHidden, includeRetired bool) ([]byte, error) {
 	// 解析时间范围（支持对齐模式）
-	startTime, endTime := h.parseTimeRange(period, align)
+	startTime, endTime := h.parseTimeRangeIn(period, align, loc)
+	if rng != nil {
+		startTime, endTime = rng.Start, rng.End
+	}
 
 	// 获取配置副本（线程安全）
 	h.cfgMu.RLock()
@@ -408,11 +673,26 @@ func (h *Handler) queryAndSerialize(ctx context.Context, period, align string, t
 	sponsorPin := h.config.SponsorPin
 	enableBadges := h.config.EnableBadges
 	boardsEnabled := h.config.Boards.Enabled
+	providerStatusCfg := h.config.ProviderStatus
+	retiredGraceDays := h.config.Storage.Retention.DecommissionedDays
+	namespaced := len(h.config.Namespaces) > 0
 	h.cfgMu.RUnlock()
 
+	// 候选监测项：当前配置 + 宽限期内的已下线监测项（仅 include_retired=true）
+	// 下线登记不记录命名空间，启用多命名空间时不追加，避免其他命名空间的监测项被当作已下线展示
+	candidates := monitors
+	var retiredAt map[storage.MonitorKey]int64
+	if includeRetired && !namespaced {
+		var retired []config.ServiceConfig
+		retired, retiredAt = h.loadRetiredMonitors(ctx, monitors, retiredGraceDays, time.Now())
+		if len(retired) > 0 {
+			candidates = append(append(make([]config.ServiceConfig, 0, len(monitors)+len(retired)), monitors...), retired...)
+		}
+	}
+
 	// 构建 slug -> provider 映射（slug作为provider的路由别名）
 	slugToProvider := make(map[string]string)
-	for _, task := range monitors {
+	for _, task := range candidates {
 		normalizedProvider := strings.ToLower(strings.TrimSpace(task.Provider))
 		slugToProvider[task.ProviderSlug] = normalizedProvider
 	}
@@ -426,9 +706,9 @@ func (h *Handler) queryAndSerialize(ctx context.Context, period, align string, t
 	// 将监测项拆分为：
 	// - plainCandidates: model 为空（仅进入 data，兼容旧前端）
 	// - layeredCandidates: model 非空（仅进入 groups，新前端使用）
-	plainCandidates := make([]config.ServiceConfig, 0, len(monitors))
-	layeredCandidates := make([]config.ServiceConfig, 0, len(monitors))
-	for _, task := range monitors {
+	plainCandidates := make([]config.ServiceConfig, 0, len(candidates))
+	layeredCandidates := make([]config.ServiceConfig, 0, len(candidates))
+	for _, task := range candidates {
 		if strings.TrimSpace(task.Model) == "" {
 			plainCandidates = append(plainCandidates, task)
 			continue
@@ -446,8 +726,8 @@ func (h *Handler) queryAndSerialize(ctx context.Context, period, align string, t
 	var err error
 	var mode string
 
-	// 批量查询仅针对 7d/30d 的高频大查询场景启用（避免对短周期造成额外复杂度）
-	tryBatch := enableBatchQuery && (period == "7d" || period == "30d") && len(filteredData) <= batchQueryMaxKeys
+	// 批量查询仅针对按天聚合的大查询场景启用（避免对短周期造成额外复杂度）
+	tryBatch := enableBatchQuery && usesDailyBuckets(period) && len(filteredData) <= batchQueryMaxKeys
 	if tryBatch {
 		mode = "batch"
 		response, err = h.getStatusBatch(ctx, filteredData, startTime, endTime, period, degradedWeight, timeFilter, enableBadges, enableDBTimelineAgg)
@@ -477,6 +757,14 @@ func (h *Handler) queryAndSerialize(ctx context.Context, period, align string, t
 		return nil, err
 	}
 
+	if len(retiredAt) > 0 {
+		markRetired(response, groups, retiredAt)
+	}
+	if annotations := h.loadAnnotations(ctx, startTime.Unix(), endTime.Unix()); len(annotations) > 0 {
+		attachAnnotations(response, groups, annotations)
+	}
+	h.attachCorrelations(ctx, baseline.NewIndex(monitors), response, groups)
+
 	logger.Info("api", "GetStatus 查询完成", "mode", mode, "monitors", len(filteredData), "layered", len(filteredLayered), "period", period, "align", align, "count", len(response), "groups", len(groups))
 
 	// 确定 timeline 模式：90m 返回原始记录，其他返回聚合数据
@@ -490,8 +778,12 @@ func (h *Handler) queryAndSerialize(ctx context.Context, period, align string, t
 	allMonitorIDs := h.buildAllMonitorIDs(monitors)
 
 	// 序列化为 JSON
+	metaPeriod := period
+	if rng != nil {
+		metaPeriod = "custom"
+	}
 	meta := gin.H{
-		"period":          period,
+		"period":          metaPeriod,
 		"timeline_mode":   timelineMode,
 		"count":           len(response),
 		"slow_latency_ms": slowLatencyMs,
@@ -508,22 +800,41 @@ func (h *Handler) queryAndSerialize(ctx context.Context, period, align string, t
 		},
 		"all_monitor_ids": allMonitorIDs,
 	}
-	// 仅在使用对齐模式时返回额外的时间范围信息
+	// 仅在使用对齐模式或自定义范围时返回额外的时间范围信息
 	if align != "" {
 		meta["align"] = align
 		meta["start_time"] = startTime.UTC().Format(time.RFC3339)
 		meta["end_time"] = endTime.UTC().Format(time.RFC3339)
 	}
+	if rng != nil {
+		meta["from"] = rng.From
+		meta["to"] = rng.To
+		meta["start_time"] = startTime.UTC().Format(time.RFC3339)
+		meta["end_time"] = endTime.UTC().Format(time.RFC3339)
+	}
+	// 超过 90 天的周期按周聚合，告知前端 bucket 粒度
+	if _, window, _ := h.determineBucketStrategy(period); window > 24*time.Hour {
+		meta["bucket"] = "week"
+	}
 	// 返回时段过滤信息
 	if timeFilter != nil {
 		meta["time_filter"] = timeFilter.String()
-		meta["timezone"] = "UTC"
+	}
+	if timeFilter != nil || loc != time.UTC {
+		meta["timezone"] = loc.String()
 	}
 
+	// 服务商综合状态（基于本次响应中的通道汇总）
+	providerStatus := buildProviderStatuses(
+		collectProviderStatusUnits(response, groups, channelWeights(monitors)),
+		providerStatusCfg, degradedWeight,
+	)
+
 	result := gin.H{
-		"meta":   meta,
-		"data":   response,
-		"groups": groups,
+		"meta":            meta,
+		"data":            response,
+		"groups":          groups,
+		"provider_status": providerStatus,
 	}
 
 	return json.Marshal(result)
@@ -635,12 +946,14 @@ func (h *Handler) getStatusBatch(ctx context.Context, monitors []config.ServiceC
 		return nil, fmt.Errorf("批量查询最新记录失败: %w", err)
 	}
 
-	// 可选：将 timeline 聚合下推到 PostgreSQL（仅 7d/30d）
+	// 可选：将 timeline 聚合下推到数据库（仅按天/按周聚合的周期）
 	//
 	// 保守策略：
-	// - 仅当 enable_db_timeline_agg=true 且存储实现支持 TimelineAggStorage 时启用
+	// - 7d/30d 仅当 enable_db_timeline_agg=true 时启用
+	// - 超过 30 天的长周期（90d/180d/自定义）只要存储实现 TimelineAggStorage 即启用，避免拉取海量原始记录
 	// - 任意错误都回退到原有 GetHistoryBatch + buildTimeline 逻辑，确保不影响功能
-	useDBAgg := enableDBTimelineAgg && (period == "7d" || period == "30d")
+	periodDays, _ := parsePeriodDays(period)
+	useDBAgg := usesDailyBuckets(period) && (enableDBTimelineAgg || periodDays > 30)
 	var aggMap map[storage.MonitorKey][]storage.AggBucketRow
 	if useDBAgg {
 		if aggStore, ok := store.(storage.TimelineAggStorage); ok {
@@ -652,6 +965,7 @@ func (h *Handler) getStatusBatch(ctx context.Context, monitors []config.ServiceC
 						StartMinutes:  timeFilter.StartHour*60 + timeFilter.StartMinute,
 						EndMinutes:    timeFilter.EndHour*60 + timeFilter.EndMinute,
 						CrossMidnight: timeFilter.CrossMidnight,
+						Timezone:      endTime.Location().String(),
 					}
 				}
 				aggMap, err = aggStore.GetTimelineAggBatch(keys, since, endTime, bucketCount, bucketWindow, tf)
@@ -779,6 +1093,8 @@ func (h *Handler) buildMonitorResult(task config.ServiceConfig, latest *storage.
 			Status:    latest.Status,
 			Latency:   latest.Latency,
 			Timestamp: latest.Timestamp,
+
+			CertDaysRemaining: latest.CertDaysRemaining,
 		}
 	}
 
@@ -829,6 +1145,7 @@ func (h *Handler) buildMonitorResult(task config.ServiceConfig, latest *storage.
 		ProviderName:  task.ProviderName,
 		ProviderSlug:  slug,
 		ProviderURL:   task.ProviderURL,
+		ProviderLogo:  task.ProviderLogo,
 		Service:       task.Service,
 		ServiceName:   task.ServiceName,
 		Category:      task.Category,
@@ -889,36 +1206,130 @@ func (h *Handler) parsePeriod(period string) (time.Duration, error) {
 		return 7 * 24 * time.Hour, nil
 	case "30d":
 		return 30 * 24 * time.Hour, nil
+	case "90d":
+		return 90 * 24 * time.Hour, nil
+	case "180d":
+		return 180 * 24 * time.Hour, nil
 	default:
 		return 0, fmt.Errorf("不支持的时间范围")
 	}
 }
 
+// parsePeriodDays 解析按天表示的周期（如 "7d"、"180d"），"1d" 视为 24h 不在此列
+func parsePeriodDays(period string) (int, bool) {
+	if !strings.HasSuffix(period, "d") || period == "1d" {
+		return 0, false
+	}
+	days, err := strconv.Atoi(strings.TrimSuffix(period, "d"))
+	if err != nil || days < 2 {
+		return 0, false
+	}
+	return days, true
+}
+
+// usesDailyBuckets 判断周期是否按天（或按周）聚合
+func usesDailyBuckets(period string) bool {
+	_, ok := parsePeriodDays(period)
+	return ok
+}
+
+// customRange 自定义日期范围（from/to 均为 UTC 日期，含首尾）
+type customRange struct {
+	From  string
+	To    string
+	Start time.Time // from 当天 00:00 UTC
+	End   time.Time // to 次日 00:00 UTC
+	Days  int
+}
+
+// period 返回自定义范围的天数形式（复用按天周期的 bucket 策略）
+func (r *customRange) period() string {
+	return fmt.Sprintf("%dd", r.Days)
+}
+
+// parseCustomRange 解析并校验 from/to 自定义范围（日期按 loc 时区解释）
+// 约束：两者必须同时提供、from <= to、to 不晚于今天、跨度在 [2, maxDays] 天之间
+func parseCustomRange(from, to string, maxDays int, now time.Time, loc *time.Location) (*customRange, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if from == "" || to == "" {
+		return nil, fmt.Errorf("from 和 to 参数必须同时提供（格式 YYYY-MM-DD）")
+	}
+	start, err := time.ParseInLocation("2006-01-02", from, loc)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 from 参数: %s（格式 YYYY-MM-DD）", from)
+	}
+	end, err := time.ParseInLocation("2006-01-02", to, loc)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 to 参数: %s（格式 YYYY-MM-DD）", to)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("from 不能晚于 to")
+	}
+	localNow := now.In(loc)
+	if end.After(time.Date(localNow.Year(), localNow.Month(), localNow.Day(), 0, 0, 0, 0, loc)) {
+		return nil, fmt.Errorf("to 不能晚于今天（%s）", loc.String())
+	}
+
+	// 按日历天计算（夏令时切换日不是 24 小时）
+	days := int(math.Round(end.Sub(start).Hours()/24)) + 1
+	if days < 2 {
+		return nil, fmt.Errorf("自定义范围至少 2 天，单日请使用 period=24h")
+	}
+	if days > maxDays {
+		return nil, fmt.Errorf("自定义范围 %d 天超出允许的最大天数 %d", days, maxDays)
+	}
+
+	return &customRange{
+		From:  from,
+		To:    to,
+		Start: start,
+		End:   end.AddDate(0, 0, 1),
+		Days:  days,
+	}, nil
+}
+
 // parseTimeRange 解析时间范围，返回 (startTime, endTime)
 // align 参数控制时间对齐模式：空=动态滑动窗口, "hour"=整点对齐
-// 注意：90m 固定使用动态窗口，7d/30d 模式自动使用 day 对齐，忽略 align 参数
+// 注意：90m 固定使用动态窗口，7d/30d/90d/180d 模式自动使用 day 对齐，忽略 align 参数
+// 自定义 from/to 范围不经过本函数，见 parseCustomRange
 func (h *Handler) parseTimeRange(period, align string) (startTime, endTime time.Time) {
-	now := time.Now()
+	return h.parseTimeRangeIn(period, align, time.UTC)
+}
+
+// parseTimeRangeIn 按指定时区解析时间范围
+// 按天对齐使用 loc 的本地午夜；返回的时间均位于 loc（bucket 标签与时段过滤据此计算）
+func (h *Handler) parseTimeRangeIn(period, align string, loc *time.Location) (startTime, endTime time.Time) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	now := time.Now().In(loc)
 
 	// 根据 period 计算时间范围
 	// 90m: 固定动态窗口
 	// 24h: 用户可选 align 模式
-	// 7d/30d: 强制使用 day 对齐（包含今天不完整数据）
+	// 7d/30d/90d/180d: 强制使用 day 对齐（包含今天不完整数据）
 	switch period {
 	case "90m":
 		endTime = now // 动态滑动窗口：不对齐
 		startTime = endTime.Add(-90 * time.Minute)
 	case "24h", "1d":
-		endTime = h.alignTimestamp(now, align)
+		endTime = h.alignTimestamp(now, align).In(loc)
 		startTime = endTime.Add(-24 * time.Hour)
 	case "7d":
-		endTime = h.alignTimestamp(now, "day") // 自动按天对齐
+		endTime = h.alignToDayIn(now, loc) // 自动按天对齐
 		startTime = endTime.AddDate(0, 0, -7)
 	case "30d":
-		endTime = h.alignTimestamp(now, "day") // 自动按天对齐
+		endTime = h.alignToDayIn(now, loc) // 自动按天对齐
 		startTime = endTime.AddDate(0, 0, -30)
+	case "90d", "180d":
+		// 起点与 bucket 边界对齐（180d 按周聚合，实际覆盖 26 周 = 182 天）
+		endTime = h.alignToDayIn(now, loc)
+		count, window, _ := h.determineBucketStrategy(period)
+		startTime = endTime.Add(-time.Duration(count) * window)
 	default:
-		endTime = h.alignTimestamp(now, align)
+		endTime = h.alignTimestamp(now, align).In(loc)
 		startTime = endTime.Add(-24 * time.Hour)
 	}
 
@@ -942,26 +1353,33 @@ func (h *Handler) alignTimestamp(t time.Time, align string) time.Time {
 	case "day":
 		// 向上取整到下一天 00:00 UTC（包含今天不完整的数据）
 		// 例如 2024-01-15 12:30 → 2024-01-16 00:00，这样最后一个 bucket 是今天
-		truncated := t.UTC().Truncate(24 * time.Hour)
-		if truncated.Before(t.UTC()) {
-			return truncated.Add(24 * time.Hour)
-		}
-		return truncated
+		return h.alignToDayIn(t, time.UTC)
 	default:
 		return t
 	}
 }
 
+// alignToDayIn 向上取整到 loc 时区的下一个本地午夜（已是午夜则保持不变）
+func (h *Handler) alignToDayIn(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	if midnight.Before(local) {
+		return midnight.AddDate(0, 0, 1)
+	}
+	return midnight
+}
+
 // bucketStats 用于聚合每个 bucket 内的探测数据
 type bucketStats struct {
-	total           int                  // 总探测次数
-	weightedSuccess float64              // 累积成功权重（绿=1.0, 黄=degraded_weight, 红=0.0）
-	latencySum      int64                // 延迟总和（仅统计可用状态）
-	latencyCount    int                  // 有效延迟计数（仅 status > 0 的记录）
-	allLatencySum   int64                // 所有记录延迟总和（用于全不可用时的参考）
-	allLatencyCount int                  // 所有记录计数
-	last            *storage.ProbeRecord // 最新一条记录
-	statusCounts    storage.StatusCounts // 各状态计数
+	total           int                     // 总探测次数
+	weightedSuccess float64                 // 累积成功权重（绿=1.0, 黄=degraded_weight, 红=0.0）
+	latencySum      int64                   // 延迟总和（仅统计可用状态）
+	latencyCount    int                     // 有效延迟计数（仅 status > 0 的记录）
+	allLatencySum   int64                   // 所有记录延迟总和（用于全不可用时的参考）
+	allLatencyCount int                     // 所有记录计数
+	last            *storage.ProbeRecord    // 最新一条记录
+	statusCounts    storage.StatusCounts    // 各状态计数
+	phases          storage.PhaseLatencyAgg // 连接阶段耗时（DNS/TCP/TLS/TTFB）
 }
 
 // buildTimeline 构建固定长度的时间轴，计算每个 bucket 的可用率和平均延迟
@@ -999,7 +1417,7 @@ func (h *Handler) buildTimeline(records []*storage.ProbeRecord, endTime time.Tim
 		t := time.Unix(record.Timestamp, 0)
 
 		// 时段过滤：跳过不在指定时间段内的记录
-		if timeFilter != nil && !timeFilter.Contains(t) {
+		if timeFilter != nil && !timeFilter.ContainsIn(t, endTime.Location()) {
 			continue
 		}
 
@@ -1039,6 +1457,7 @@ func (h *Handler) buildTimeline(records []*storage.ProbeRecord, endTime time.Tim
 			stat.latencyCount++
 		}
 		incrementStatusCount(&stat.statusCounts, record.Status, record.SubStatus, record.HttpCode)
+		stat.phases.Add(record)
 
 		// 保留最新记录
 		if stat.last == nil || record.Timestamp > stat.last.Timestamp {
@@ -1068,6 +1487,7 @@ func (h *Handler) buildTimeline(records []*storage.ProbeRecord, endTime time.Tim
 			avgLatency := float64(stat.allLatencySum) / float64(stat.allLatencyCount)
 			buckets[i].Latency = int(avgLatency + 0.5)
 		}
+		stat.phases.Apply(&buckets[i])
 
 		// 使用最新记录的状态
 		if stat.last != nil {
@@ -1139,6 +1559,7 @@ func (h *Handler) buildTimelineFromAgg(rows []storage.AggBucketRow, endTime time
 			avgLatency := float64(r.AllLatencySum) / float64(r.AllLatencyCount)
 			buckets[i].Latency = int(avgLatency + 0.5)
 		}
+		r.Phases.Apply(&buckets[i])
 
 		// bucket 状态取"最后一条记录"的状态（Timestamp 仍保持 bucket 起始时间）
 		buckets[i].Status = r.LastStatus
@@ -1161,7 +1582,7 @@ func (h *Handler) buildRawTimeline(records []*storage.ProbeRecord, endTime time.
 		}
 
 		// 时段过滤
-		if timeFilter != nil && !timeFilter.Contains(t) {
+		if timeFilter != nil && !timeFilter.ContainsIn(t, endTime.Location()) {
 			continue
 		}
 
@@ -1171,12 +1592,16 @@ func (h *Handler) buildRawTimeline(records []*storage.ProbeRecord, endTime time.
 
 		// 延迟处理：始终返回原始延迟值，由前端决定颜色（可用=渐变，不可用=灰色）
 		timeline = append(timeline, storage.TimePoint{
-			Time:         t.UTC().Format(format),
+			Time:         t.In(endTime.Location()).Format(format),
 			Timestamp:    record.Timestamp,
 			Status:       record.Status,
 			Latency:      record.Latency,
 			Availability: statusToAvailability(record.Status, degradedWeight),
 			StatusCounts: counts,
+			DNSMs:        record.DNSMs,
+			ConnectMs:    record.ConnectMs,
+			TLSMs:        record.TLSMs,
+			TTFBMs:       record.TTFBMs,
 		})
 	}
 
@@ -1195,19 +1620,69 @@ func (h *Handler) determineBucketStrategy(period string) (count int, window time
 		return 7, 24 * time.Hour, "2006-01-02"
 	case "30d":
 		return 30, 24 * time.Hour, "2006-01-02"
-	default:
-		return 24, time.Hour, "15:04"
 	}
+
+	// 90d/180d 及自定义范围：≤ 90 天按天聚合，更长按周聚合（控制 bucket 数量）
+	if days, ok := parsePeriodDays(period); ok {
+		if days <= 90 {
+			return days, 24 * time.Hour, "2006-01-02"
+		}
+		return (days + 6) / 7, 7 * 24 * time.Hour, "2006-01-02"
+	}
+	return 24, time.Hour, "15:04"
 }
 
 // UpdateConfig 更新配置（热更新时调用）
 func (h *Handler) UpdateConfig(cfg *config.AppConfig) {
+	h.updateMu.Lock()
+	defer h.updateMu.Unlock()
+	h.applyConfig(cfg)
+}
+
+// SetBoardEngine 设置板块自动调整任务（可选），并立即叠加当前调整结果
+func (h *Handler) SetBoardEngine(e *boards.Engine) {
+	h.cfgMu.Lock()
+	h.boards = e
+	h.cfgMu.Unlock()
+	h.RefreshBoards()
+}
+
+// RefreshBoards 板块自动调整结果变化后重新叠加到当前配置（清空缓存）
+func (h *Handler) RefreshBoards() {
+	h.updateMu.Lock()
+	defer h.updateMu.Unlock()
+
+	h.cfgMu.RLock()
+	base := h.baseConfig
+	h.cfgMu.RUnlock()
+	h.applyConfig(base)
+}
+
+// applyConfig 叠加板块自动调整结果后替换配置（调用方需持有 updateMu）
+// 叠加结果是副本，调度器仍使用原配置，自动降入冷板的监测项继续探测
+func (h *Handler) applyConfig(cfg *config.AppConfig) {
+	h.cfgMu.Lock()
+	h.baseConfig = cfg
+	engine := h.boards
+	h.cfgMu.Unlock()
+
+	if engine != nil {
+		cfg = engine.Apply(cfg)
+	}
+	h.setConfig(cfg.ForNamespace(""), cfg)
+	h.syncNamespaces(cfg)
+}
+
+// setConfig 替换配置视图与完整配置并清空缓存
+func (h *Handler) setConfig(view, full *config.AppConfig) {
 	h.cfgMu.Lock()
-	h.config = cfg
+	h.config = view
+	h.fullConfig = full
 	h.cfgMu.Unlock()
 
 	// 配置更新后清空缓存，确保禁用/隐藏状态变更立即生效
-	h.cache.clear()
+	h.configureCaches(view)
+	h.resetCache()
 }
 
 // availabilityWeight 根据状态码返回可用率权重
@@ -1249,6 +1724,10 @@ func incrementStatusCount(counts *storage.StatusCounts, status int, subStatus st
 			counts.SlowLatency++
 		case storage.SubStatusRateLimit:
 			counts.RateLimit++
+		case storage.SubStatusCertExpiring:
+			counts.CertExpiring++
+		case storage.SubStatusContentDrift:
+			counts.ContentDrift++
 		}
 	case 0: // 红色
 		counts.Unavailable++
@@ -1269,6 +1748,10 @@ func incrementStatusCount(counts *storage.StatusCounts, status int, subStatus st
 			counts.NetworkError++
 		case storage.SubStatusContentMismatch:
 			counts.ContentMismatch++
+		case storage.SubStatusIPv6OnlyFailure:
+			counts.IPv6OnlyFailure++
+		case storage.SubStatusProbePanic:
+			counts.ProbePanic++
 		}
 	default: // 灰色（3）或其他
 		counts.Missing++
@@ -1295,22 +1778,35 @@ func incrementStatusCount(counts *storage.StatusCounts, status int, subStatus st
 	}
 }
 
-// GetSitemap 生成 sitemap.xml
+// GetSitemap 生成 sitemap.xml（缓存时长由 cache_ttl.sitemap.ttl 决定，默认 1 小时）
 func (h *Handler) GetSitemap(c *gin.Context) {
 	// 获取配置副本
 	h.cfgMu.RLock()
 	monitors := h.config.Monitors
+	cacheTTL := h.config.CacheTTL.Sitemap.TTLDuration
 	h.cfgMu.RUnlock()
 
-	// 提取唯一的 provider slugs
-	providerSlugs := h.extractUniqueProviderSlugs(monitors)
+	entry, _ := h.siteCache.loadEntry(c.Request.Context(), "sitemap", cacheTTL, func() ([]byte, error) {
+		// 提取唯一的 provider slugs 并构建 sitemap XML
+		return []byte(h.buildSitemapXML(h.extractUniqueProviderSlugs(monitors))), nil
+	})
 
-	// 构建 sitemap XML
-	sitemap := h.buildSitemapXML(providerSlugs)
+	if cacheTTL > 0 {
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(cacheTTL.Seconds())))
+	} else {
+		c.Header("Cache-Control", "no-cache")
+	}
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", entry.data)
+}
 
-	c.Header("Content-Type", "application/xml; charset=utf-8")
-	c.Header("Cache-Control", "public, max-age=3600") // 缓存 1 小时
-	c.String(http.StatusOK, sitemap)
+// setCacheControl 设置 CDN 缓存头（Cloudflare 遵守 s-maxage，浏览器遵守 max-age），ttl <= 0 时禁止复用
+func setCacheControl(c *gin.Context, ttl time.Duration) {
+	if ttl <= 0 {
+		c.Header("Cache-Control", "no-cache")
+		return
+	}
+	ttlSeconds := int(ttl.Seconds())
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d, s-maxage=%d", ttlSeconds, ttlSeconds))
 }
 
 // extractUniqueProviderSlugs 从监测配置中提取唯一的 provider slugs（排除禁用和隐藏的）
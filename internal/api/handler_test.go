@@ -1,6 +1,10 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -8,6 +12,20 @@ import (
 	"monitor/internal/storage"
 )
 
+// newTestSQLiteStore 创建已初始化的临时 SQLite 存储（测试结束时关闭）
+func newTestSQLiteStore(t *testing.T) *storage.SQLiteStorage {
+	t.Helper()
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "monitor.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	if err := store.Init(); err != nil {
+		t.Fatalf("init sqlite: %v", err)
+	}
+	return store
+}
+
 // TestBuildTimelineLatencyCalculation 测试延迟统计逻辑
 // 验证：
 // 1. 优先使用 status > 0 的记录计算平均延迟
@@ -143,6 +161,49 @@ func TestBuildTimelineLatencyRounding(t *testing.T) {
 	}
 }
 
+// TestBuildTimelinePhaseLatency 测试连接阶段耗时聚合
+// 验证：各阶段仅统计有值的记录（复用连接时无 DNS/TCP/TLS），无数据时省略
+func TestBuildTimelinePhaseLatency(t *testing.T) {
+	h := &Handler{
+		config: &config.AppConfig{
+			DegradedWeight: 0.7,
+		},
+	}
+
+	now := time.Now()
+	ms := func(v int) *int { return &v }
+
+	records := []*storage.ProbeRecord{
+		{Status: 1, Latency: 300, Timestamp: now.Unix(), DNSMs: ms(20), ConnectMs: ms(30), TTFBMs: ms(250)},
+		{Status: 1, Latency: 200, Timestamp: now.Unix(), TTFBMs: ms(151)}, // 复用连接
+		{Status: 1, Latency: 100, Timestamp: now.Unix()},                  // 旧数据
+	}
+
+	timeline := h.buildTimeline(records, now, "24h", 0.7, nil)
+
+	var point *storage.TimePoint
+	for i := range timeline {
+		if timeline[i].Status != -1 {
+			point = &timeline[i]
+			break
+		}
+	}
+	if point == nil {
+		t.Fatal("未找到有数据的 bucket")
+	}
+
+	if point.DNSMs == nil || *point.DNSMs != 20 || point.ConnectMs == nil || *point.ConnectMs != 30 {
+		t.Errorf("DNS/连接耗时错误: dns=%v connect=%v", point.DNSMs, point.ConnectMs)
+	}
+	if point.TLSMs != nil {
+		t.Errorf("无 TLS 数据时应省略，实际 %d", *point.TLSMs)
+	}
+	// (250+151)/2 = 200.5 四舍五入为 201
+	if point.TTFBMs == nil || *point.TTFBMs != 201 {
+		t.Errorf("TTFB 错误: %v", point.TTFBMs)
+	}
+}
+
 // TestAlignTimestamp 测试时间对齐逻辑
 func TestAlignTimestamp(t *testing.T) {
 	h := &Handler{}
@@ -232,3 +293,262 @@ func TestParseTimeRange7d30dDayAlign(t *testing.T) {
 		}
 	})
 }
+
+// TestLongPeriodBucketStrategy 测试 90d/180d 及自定义范围的 bucket 策略
+func TestLongPeriodBucketStrategy(t *testing.T) {
+	h := &Handler{}
+
+	tests := []struct {
+		period string
+		count  int
+		window time.Duration
+	}{
+		{"7d", 7, 24 * time.Hour},
+		{"30d", 30, 24 * time.Hour},
+		{"45d", 45, 24 * time.Hour},
+		{"90d", 90, 24 * time.Hour},
+		{"180d", 26, 7 * 24 * time.Hour},
+		{"1d", 24, time.Hour},
+	}
+	for _, tt := range tests {
+		count, window, _ := h.determineBucketStrategy(tt.period)
+		if count != tt.count || window != tt.window {
+			t.Errorf("determineBucketStrategy(%q) = (%d, %v)，期望 (%d, %v)", tt.period, count, window, tt.count, tt.window)
+		}
+	}
+
+	// 180d 起点与周 bucket 边界对齐
+	start, end := h.parseTimeRange("180d", "")
+	if got := end.Sub(start); got != 26*7*24*time.Hour {
+		t.Errorf("180d 时间窗口应为 26 周，实际 %v", got)
+	}
+	if usesDailyBuckets("24h") || usesDailyBuckets("1d") || !usesDailyBuckets("90d") {
+		t.Errorf("usesDailyBuckets 判断错误")
+	}
+}
+
+// TestParseCustomRange 测试 from/to 自定义范围解析
+func TestParseCustomRange(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	rng, err := parseCustomRange("2024-06-01", "2024-06-30", 180, now, time.UTC)
+	if err != nil {
+		t.Fatalf("parseCustomRange 失败: %v", err)
+	}
+	if rng.Days != 30 || rng.period() != "30d" {
+		t.Errorf("期望 30 天，实际 %d (%s)", rng.Days, rng.period())
+	}
+	if !rng.Start.Equal(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)) || !rng.End.Equal(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("时间范围错误: %v ~ %v", rng.Start, rng.End)
+	}
+
+	invalid := []struct {
+		name, from, to string
+	}{
+		{"缺少 to", "2024-06-01", ""},
+		{"格式错误", "2024/06/01", "2024-06-30"},
+		{"from 晚于 to", "2024-06-30", "2024-06-01"},
+		{"未来日期", "2024-06-01", "2024-07-01"},
+		{"单日", "2024-06-30", "2024-06-30"},
+		{"超出上限", "2023-01-01", "2024-06-30"},
+	}
+	for _, tt := range invalid {
+		if _, err := parseCustomRange(tt.from, tt.to, 180, now, nil); err == nil {
+			t.Errorf("%s: 期望返回错误", tt.name)
+		}
+	}
+}
+
+// TestTimezoneAwareAlignment 测试 tz 参数对按天对齐、时段过滤与自定义范围的影响
+func TestTimezoneAwareAlignment(t *testing.T) {
+	h := &Handler{}
+
+	shanghai, err := parseTimezone("Asia/Shanghai")
+	if err != nil {
+		t.Fatalf("parseTimezone 失败: %v", err)
+	}
+	for _, tz := range []string{"Local", "Mars/Olympus", "+08:00"} {
+		if _, err := parseTimezone(tz); err == nil {
+			t.Errorf("parseTimezone(%q) 期望返回错误", tz)
+		}
+	}
+	if loc, _ := parseTimezone(""); loc != time.UTC {
+		t.Errorf("空 tz 应为 UTC")
+	}
+
+	// 2024-01-15 20:00 UTC = 2024-01-16 04:00 上海 → 上海次日午夜 2024-01-17 00:00 (+08)
+	now := time.Date(2024, 1, 15, 20, 0, 0, 0, time.UTC)
+	got := h.alignToDayIn(now, shanghai)
+	want := time.Date(2024, 1, 17, 0, 0, 0, 0, shanghai)
+	if !got.Equal(want) {
+		t.Errorf("alignToDayIn = %v，期望 %v", got, want)
+	}
+
+	_, endTime := h.parseTimeRangeIn("7d", "", shanghai)
+	if endTime.Location() != shanghai || endTime.Hour() != 0 || endTime.Minute() != 0 {
+		t.Errorf("7d endTime 应为上海本地午夜，实际 %v", endTime)
+	}
+
+	// 09:00-17:00 上海时间 = 01:00-09:00 UTC
+	filter, _ := ParseTimeFilter("09:00-17:00")
+	probe := time.Date(2024, 1, 15, 2, 0, 0, 0, time.UTC)
+	if !filter.ContainsIn(probe, shanghai) {
+		t.Errorf("02:00 UTC（10:00 上海）应在上海 09:00-17:00 时段内")
+	}
+	if filter.Contains(probe) {
+		t.Errorf("02:00 UTC 不应在 UTC 09:00-17:00 时段内")
+	}
+
+	rng, err := parseCustomRange("2024-01-01", "2024-01-10", 180, now, shanghai)
+	if err != nil {
+		t.Fatalf("parseCustomRange 失败: %v", err)
+	}
+	if !rng.Start.Equal(time.Date(2023, 12, 31, 16, 0, 0, 0, time.UTC)) || rng.Days != 10 {
+		t.Errorf("上海时区自定义范围起点错误: %v (days=%d)", rng.Start, rng.Days)
+	}
+}
+
+// expire 将条目的过期时间前移，模拟 TTL 已过（staleUntil 保持与 staleTTL 的相对关系）
+func expire(c *statusCache, key string, by time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := c.entries[key]
+	e.expireAt = e.expireAt.Add(-by)
+	e.staleUntil = e.staleUntil.Add(-by)
+}
+
+// TestStatusCacheStaleWhileRevalidate 测试过期条目立即返回旧数据并后台刷新
+func TestStatusCacheStaleWhileRevalidate(t *testing.T) {
+	c := newStatusCache(time.Minute, 10)
+	c.configure(10, time.Minute, 0)
+
+	var loads atomic.Int32
+	loader := func() ([]byte, error) {
+		n := loads.Add(1)
+		return []byte{byte('0' + n)}, nil
+	}
+
+	if data, err := c.loadWithTTL(context.Background(), "k", time.Minute, loader); err != nil || string(data) != "1" {
+		t.Fatalf("first load = %q, %v", data, err)
+	}
+
+	// TTL 已过但在 stale 窗口内：返回旧数据，后台刷新
+	expire(c, "k", 90*time.Second)
+	if data, err := c.loadWithTTL(context.Background(), "k", time.Minute, loader); err != nil || string(data) != "1" {
+		t.Fatalf("stale load = %q, %v", data, err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if entry, ok := c.get("k"); ok && string(entry.data) == "2" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("background refresh did not complete, loads=%d", loads.Load())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// 超过 stale 窗口：同步重新加载
+	expire(c, "k", 3*time.Minute)
+	if data, err := c.loadWithTTL(context.Background(), "k", time.Minute, loader); err != nil || string(data) != "3" {
+		t.Fatalf("expired load = %q, %v", data, err)
+	}
+
+	// 关闭 stale 后过期即同步加载
+	c.configure(10, 0, 0)
+	c.clear()
+	_, _ = c.loadWithTTL(context.Background(), "k", time.Minute, loader)
+	expire(c, "k", 2*time.Minute)
+	if data, _ := c.loadWithTTL(context.Background(), "k", time.Minute, loader); string(data) != "5" {
+		t.Fatalf("expected synchronous reload without stale window, got %q", data)
+	}
+}
+
+// TestStatusCacheNegativeCaching 测试查询失败在 errorTTL 内直接返回缓存的错误
+func TestStatusCacheNegativeCaching(t *testing.T) {
+	c := newStatusCache(time.Minute, 10)
+	c.configure(10, time.Minute, time.Minute)
+
+	errDB := errors.New("db down")
+	var calls int
+	failing := func() ([]byte, error) {
+		calls++
+		return nil, errDB
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.loadWithTTL(context.Background(), "k", time.Minute, failing); !errors.Is(err, errDB) {
+			t.Fatalf("expected cached error, got %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected loader to run once within error ttl, got %d", calls)
+	}
+
+	// 错误过期后重新查询，成功写入时清除错误
+	c.mu.Lock()
+	c.errors["k"].expireAt = time.Now().Add(-time.Second)
+	c.mu.Unlock()
+	if data, err := c.loadWithTTL(context.Background(), "k", time.Minute, func() ([]byte, error) { return []byte("ok"), nil }); err != nil || string(data) != "ok" {
+		t.Fatalf("reload after error ttl = %q, %v", data, err)
+	}
+	if err := c.cachedError("k"); err != nil {
+		t.Fatalf("error should be cleared after successful load: %v", err)
+	}
+
+	// 后台刷新失败时继续返回过期数据，且错误缓存期间不再重复刷新
+	expire(c, "k", 90*time.Second)
+	calls = 0
+	if data, err := c.loadWithTTL(context.Background(), "k", time.Minute, failing); err != nil || string(data) != "ok" {
+		t.Fatalf("stale load = %q, %v", data, err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for c.cachedError("k") == nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("background refresh error was not cached")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if data, err := c.loadWithTTL(context.Background(), "k", time.Minute, failing); err != nil || string(data) != "ok" {
+		t.Fatalf("stale load after failed refresh = %q, %v", data, err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single refresh attempt, got %d", calls)
+	}
+}
+
+// TestStatusCacheZeroTTLBypass 测试 ttl <= 0 时不读写缓存
+func TestStatusCacheZeroTTLBypass(t *testing.T) {
+	c := newStatusCache(time.Minute, 10)
+	var loads int
+	loader := func() ([]byte, error) {
+		loads++
+		return []byte("x"), nil
+	}
+	for i := 0; i < 2; i++ {
+		if data, err := c.loadWithTTL(context.Background(), "k", 0, loader); err != nil || string(data) != "x" {
+			t.Fatalf("load = %q, %v", data, err)
+		}
+	}
+	if loads != 2 || len(c.entries) != 0 {
+		t.Fatalf("expected uncached loads, loads=%d entries=%d", loads, len(c.entries))
+	}
+}
+
+// TestCacheBypassed 测试按服务商名称或 slug 跳过缓存
+func TestCacheBypassed(t *testing.T) {
+	h := &Handler{config: &config.AppConfig{
+		CacheTTL: config.CacheTTLConfig{BypassProviders: []string{"Relay"}},
+		Monitors: []config.ServiceConfig{
+			{Provider: "Relay", ProviderSlug: "relay-slug"},
+			{Provider: "Other", ProviderSlug: "other"},
+		},
+	}}
+	for provider, want := range map[string]bool{
+		"relay": true, "relay-slug": true, "other": false, "all": false, "": false,
+	} {
+		if got := h.cacheBypassed(provider); got != want {
+			t.Errorf("cacheBypassed(%q) = %v, want %v", provider, got, want)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// heatmapDays 热力图固定天数（含今天）
+const heatmapDays = 90
+
+// HeatmapResponse 90 天可用性热力图响应（GET /api/heatmap）
+type HeatmapResponse struct {
+	Meta     HeatmapMeta      `json:"meta"`
+	Monitors []HeatmapMonitor `json:"monitors"`
+}
+
+// HeatmapMeta 热力图元数据
+type HeatmapMeta struct {
+	Days  int    `json:"days"`
+	Since string `json:"since"` // 起始日期（UTC，含）
+	Until string `json:"until"` // 截止日期（UTC，含今天）
+	Count int    `json:"count"`
+}
+
+// HeatmapMonitor 单个监测项的热力图数据
+type HeatmapMonitor struct {
+	Provider     string       `json:"provider"`
+	ProviderName string       `json:"provider_name,omitempty"`
+	ProviderSlug string       `json:"provider_slug"`
+	Service      string       `json:"service"`
+	ServiceName  string       `json:"service_name,omitempty"`
+	Channel      string       `json:"channel"`
+	ChannelName  string       `json:"channel_name,omitempty"`
+	Model        string       `json:"model,omitempty"`
+	Days         []HeatmapDay `json:"days"` // 固定 90 个，按日期升序
+}
+
+// HeatmapDay 单日可用性
+// total 为实际探测数（可用率分母），expected 为按巡检间隔估算的应有探测数；
+// total < expected 表示当天数据不完整（服务重启、新增监测项或今天尚未结束）
+type HeatmapDay struct {
+	Date     string  `json:"date"`
+	Uptime   float64 `json:"uptime"` // 可用率百分比（黄色按 degraded_weight 计），无数据时为 -1
+	Total    int     `json:"total"`
+	Expected int     `json:"expected"`
+	Green    int     `json:"green"`
+	Yellow   int     `json:"yellow"`
+	Red      int     `json:"red"`
+}
+
+// GetHeatmap 获取 90 天每日可用性热力图
+// GET /api/heatmap?provider=xxx&service=xxx&board=hot
+// 基于每日汇总（probe_daily）计算，不受 retention 清理影响，与 period 参数无关
+func (h *Handler) GetHeatmap(c *gin.Context) {
+	qProvider := strings.ToLower(strings.TrimSpace(c.DefaultQuery("provider", "all")))
+	qService := c.DefaultQuery("service", "all")
+	qBoard := strings.ToLower(strings.TrimSpace(c.DefaultQuery("board", "hot")))
+	if qBoard == "" {
+		qBoard = "hot"
+	}
+	if qBoard != "hot" && qBoard != "secondary" && qBoard != "cold" && qBoard != "all" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("无效的 board 参数: %s (支持: hot/secondary/cold/all)", qBoard),
+		})
+		return
+	}
+
+	if _, ok := h.storage.WithContext(c.Request.Context()).(storage.DailyRollupStorage); !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持每日汇总",
+		})
+		return
+	}
+
+	cacheKey := fmt.Sprintf("heatmap|prov=%s|svc=%s|board=%s", qProvider, qService, qBoard)
+
+	h.cfgMu.RLock()
+	cacheTTL := h.config.CacheTTL.TTLForPeriod("30d")
+	h.cfgMu.RUnlock()
+
+	reqCtx := context.WithoutCancel(c.Request.Context())
+	entry, err := h.cache.loadEntry(reqCtx, cacheKey, cacheTTL, func() ([]byte, error) {
+		ctx, cancel := context.WithTimeout(reqCtx, 30*time.Second)
+		defer cancel()
+		resp, err := h.buildHeatmap(ctx, qProvider, qService, qBoard, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(resp)
+	})
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("GetHeatmap 失败", "cache_key", cacheKey, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("查询失败: %v", err),
+		})
+		return
+	}
+
+	ttlSeconds := int(cacheTTL.Seconds())
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d, s-maxage=%d", ttlSeconds, ttlSeconds))
+	writeCached(c, entry, "application/json; charset=utf-8")
+}
+
+// buildHeatmap 查询每日汇总并构建热力图
+func (h *Handler) buildHeatmap(ctx context.Context, qProvider, qService, qBoard string, now time.Time) (*HeatmapResponse, error) {
+	h.cfgMu.RLock()
+	monitors := h.config.Monitors
+	degradedWeight := h.config.DegradedWeight
+	boardsEnabled := h.config.Boards.Enabled
+	batchQueryMaxKeys := h.config.BatchQueryMaxKeys
+	h.cfgMu.RUnlock()
+
+	// provider 参数支持 slug 别名（与 /api/status 一致）
+	realProvider := qProvider
+	for _, task := range monitors {
+		if task.ProviderSlug == qProvider {
+			realProvider = strings.ToLower(strings.TrimSpace(task.Provider))
+			break
+		}
+	}
+
+	filtered := h.filterMonitorsForGroups(monitors, realProvider, qService, qBoard, boardsEnabled, false)
+
+	keys := make([]storage.MonitorKey, 0, len(filtered))
+	for _, task := range filtered {
+		keys = append(keys, storage.MonitorKey{Provider: task.Provider, Service: task.Service, Channel: task.Channel, Model: task.Model})
+	}
+
+	store, ok := h.storage.WithContext(ctx).(storage.DailyRollupStorage)
+	if !ok {
+		return nil, fmt.Errorf("当前存储后端不支持每日汇总")
+	}
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	sinceDay := today.AddDate(0, 0, -(heatmapDays - 1)).Format("2006-01-02")
+	untilDay := today.Format("2006-01-02")
+
+	rollupMap := make(map[storage.MonitorKey][]storage.DailyRollupRow, len(keys))
+	if batchQueryMaxKeys <= 0 {
+		batchQueryMaxKeys = len(keys)
+	}
+	for start := 0; start < len(keys); start += batchQueryMaxKeys {
+		end := min(start+batchQueryMaxKeys, len(keys))
+		rollups, err := store.GetDailyRollupBatch(keys[start:end], sinceDay, untilDay)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range rollups {
+			rollupMap[k] = v
+		}
+	}
+
+	result := &HeatmapResponse{
+		Meta: HeatmapMeta{
+			Days:  heatmapDays,
+			Since: sinceDay,
+			Until: untilDay,
+		},
+		Monitors: make([]HeatmapMonitor, 0, len(filtered)),
+	}
+	for i, task := range filtered {
+		slug := task.ProviderSlug
+		if slug == "" {
+			slug = strings.ToLower(strings.TrimSpace(task.Provider))
+		}
+		result.Monitors = append(result.Monitors, HeatmapMonitor{
+			Provider:     task.Provider,
+			ProviderName: task.ProviderName,
+			ProviderSlug: slug,
+			Service:      task.Service,
+			ServiceName:  task.ServiceName,
+			Channel:      task.Channel,
+			ChannelName:  task.ChannelName,
+			Model:        task.Model,
+			Days:         buildHeatmapDays(rollupMap[keys[i]], task, now, degradedWeight),
+		})
+	}
+	result.Meta.Count = len(result.Monitors)
+
+	return result, nil
+}
+
+// buildHeatmapDays 将每日汇总补齐为固定 90 天（含今天），缺失日期 uptime=-1
+func buildHeatmapDays(rows []storage.DailyRollupRow, task config.ServiceConfig, now time.Time, degradedWeight float64) []HeatmapDay {
+	byDay := make(map[string]storage.DailyRollupRow, len(rows))
+	for _, r := range rows {
+		byDay[r.Day] = r
+	}
+
+	now = now.UTC()
+	today := now.Truncate(24 * time.Hour)
+	interval := task.IntervalDuration
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	days := make([]HeatmapDay, heatmapDays)
+	for i := range days {
+		date := today.AddDate(0, 0, i-(heatmapDays-1))
+		day := HeatmapDay{Date: date.Format("2006-01-02"), Uptime: -1}
+
+		// 今天只统计已经过去的时段
+		elapsed := 24 * time.Hour
+		if date.Equal(today) {
+			elapsed = now.Sub(today)
+		}
+		day.Expected = int(elapsed / interval)
+
+		if r, ok := byDay[day.Date]; ok && r.Total > 0 {
+			day.Total = r.Total
+			day.Green = r.Green
+			day.Yellow = r.Yellow
+			day.Red = r.Red
+			weight := float64(r.Green) + float64(r.Yellow)*degradedWeight
+			day.Uptime = roundTo(weight/float64(r.Total)*100, 2)
+		}
+		days[i] = day
+	}
+	return days
+}
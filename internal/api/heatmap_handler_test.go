@@ -0,0 +1,45 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestBuildHeatmapDays(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 31, 6, 0, 0, 0, time.UTC)
+	task := config.ServiceConfig{IntervalDuration: time.Hour}
+	rows := []storage.DailyRollupRow{
+		{Day: "2024-01-02", Total: 24, Green: 24},
+		{Day: "2024-03-30", Total: 12, Green: 9, Yellow: 2, Red: 1},
+		{Day: "2024-03-31", Total: 6, Green: 6},
+	}
+
+	days := buildHeatmapDays(rows, task, now, 0.5)
+	if len(days) != heatmapDays {
+		t.Fatalf("expected %d days, got %d", heatmapDays, len(days))
+	}
+	if days[0].Date != "2024-01-02" || days[0].Uptime != 100 {
+		t.Fatalf("unexpected first day: %+v", days[0])
+	}
+
+	if missing := days[1]; missing.Uptime != -1 || missing.Total != 0 || missing.Expected != 24 {
+		t.Fatalf("missing day should have uptime=-1 and full expected count: %+v", missing)
+	}
+
+	// 部分缺失：分母为实际探测数，expected 仍按全天估算
+	partial := days[heatmapDays-2]
+	if partial.Date != "2024-03-30" || partial.Total != 12 || partial.Expected != 24 || partial.Uptime != 83.33 {
+		t.Fatalf("unexpected partial day: %+v", partial)
+	}
+
+	// 今天只统计已过去的时段
+	today := days[heatmapDays-1]
+	if today.Date != "2024-03-31" || today.Expected != 6 || today.Uptime != 100 {
+		t.Fatalf("unexpected today bucket: %+v", today)
+	}
+}
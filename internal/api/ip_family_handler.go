@@ -0,0 +1,238 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+const (
+	// ipFamilyMinProbes 判定 IPv6 异常时每个地址族至少需要的探测次数
+	ipFamilyMinProbes = 10
+
+	// ipFamilyGapThreshold IPv6 可用率低于 IPv4 超过该百分点时判定为 IPv6 异常
+	ipFamilyGapThreshold = 5.0
+)
+
+// IPFamiliesResponse 按连接地址族统计的可用率响应
+type IPFamiliesResponse struct {
+	Providers []IPFamilyProviderItem `json:"providers"`
+	Monitors  []IPFamilyMonitorItem  `json:"monitors"`
+	Meta      IPFamiliesMeta         `json:"meta"`
+}
+
+// IPFamiliesMeta 地址族统计元数据
+type IPFamiliesMeta struct {
+	Period       string `json:"period"`
+	Since        int64  `json:"since"` // 统计起点（Unix 秒，含）
+	Until        int64  `json:"until"` // 统计终点（Unix 秒，不含）
+	IPv6Degraded int    `json:"ipv6_degraded"`
+}
+
+// IPFamilyProviderItem 单个服务商全部监测项按地址族合并的统计
+type IPFamilyProviderItem struct {
+	Provider         string          `json:"provider"`
+	IPv4             *IPFamilyCounts `json:"ipv4,omitempty"`
+	IPv6             *IPFamilyCounts `json:"ipv6,omitempty"`
+	Unknown          *IPFamilyCounts `json:"unknown,omitempty"` // 地址族未知（auto 模式连接失败、经代理或旧数据）
+	IPv6OnlyFailures int             `json:"ipv6_only_failures"`
+	IPv6Degraded     bool            `json:"ipv6_degraded"` // IPv6 可用率明显低于 IPv4（疑似 AAAA 记录或 IPv6 链路异常）
+}
+
+// IPFamilyMonitorItem 单个监测项按地址族的统计
+type IPFamilyMonitorItem struct {
+	Provider         string          `json:"provider"`
+	Service          string          `json:"service"`
+	Channel          string          `json:"channel,omitempty"`
+	Model            string          `json:"model,omitempty"`
+	IPFamily         string          `json:"ip_family"` // 当前配置的地址族（auto / ipv4 / ipv6，已下线的监测项为空）
+	IPv4             *IPFamilyCounts `json:"ipv4,omitempty"`
+	IPv6             *IPFamilyCounts `json:"ipv6,omitempty"`
+	Unknown          *IPFamilyCounts `json:"unknown,omitempty"`
+	IPv6OnlyFailures int             `json:"ipv6_only_failures"`
+}
+
+// IPFamilyCounts 某一地址族的探测统计
+type IPFamilyCounts struct {
+	Probes       int     `json:"probes"`
+	Available    int     `json:"available"`
+	Degraded     int     `json:"degraded"`
+	Unavailable  int     `json:"unavailable"`
+	Availability float64 `json:"availability"`          // 可用率百分比（黄色按 degraded_weight 计）
+	AvgLatency   int     `json:"avg_latency,omitempty"` // 绿色/黄色记录平均延迟（毫秒）
+
+	latencySum int64
+}
+
+// GetAdminIPFamilies 按连接地址族统计各服务商与监测项的可用率
+// GET /api/admin/ip-families?period=24h&provider=xxx
+// period 支持 24h/7d/30d（默认 24h）
+func (h *Handler) GetAdminIPFamilies(c *gin.Context) {
+	period := c.DefaultQuery("period", "24h")
+	if period == "1d" {
+		period = "24h"
+	}
+	if period != "24h" && period != "7d" && period != "30d" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("无效的时间范围: %s (支持: 24h/7d/30d)", period),
+		})
+		return
+	}
+	duration, _ := h.parsePeriod(period)
+
+	fs, ok := h.storage.WithContext(c.Request.Context()).(storage.IPFamilyStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持地址族统计",
+		})
+		return
+	}
+
+	until := time.Now()
+	since := until.Add(-duration)
+	stats, err := fs.GetIPFamilyStats(since.Unix(), until.Unix()+1)
+	if err != nil {
+		logger.Error("api", "查询地址族统计失败", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "查询地址族统计失败",
+		})
+		return
+	}
+
+	h.cfgMu.RLock()
+	degradedWeight := h.config.DegradedWeight
+	families := make(map[storage.MonitorKey]string)
+	for _, m := range h.allMonitors() {
+		family := m.IPFamily
+		if family == "" {
+			family = config.IPFamilyAuto
+		}
+		families[storage.MonitorKey{Provider: m.Provider, Service: m.Service, Channel: m.Channel, Model: m.Model}] = family
+	}
+	h.cfgMu.RUnlock()
+
+	resp := buildIPFamiliesResponse(stats, families, c.Query("provider"), degradedWeight)
+	resp.Meta.Period = period
+	resp.Meta.Since = since.Unix()
+	resp.Meta.Until = until.Unix() + 1
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
+
+// buildIPFamiliesResponse 将地址族统计行聚合为监测项与服务商两级结果
+// families 为当前配置中各监测项的 ip_family；provider 非空时仅保留该服务商（忽略大小写）
+func buildIPFamiliesResponse(stats []*storage.IPFamilyStat, families map[storage.MonitorKey]string, provider string, degradedWeight float64) IPFamiliesResponse {
+	resp := IPFamiliesResponse{
+		Providers: []IPFamilyProviderItem{},
+		Monitors:  []IPFamilyMonitorItem{},
+	}
+
+	monitorIdx := make(map[storage.MonitorKey]int)
+	providerIdx := make(map[string]int)
+	for _, st := range stats {
+		if provider != "" && !strings.EqualFold(st.Key.Provider, provider) {
+			continue
+		}
+
+		idx, ok := monitorIdx[st.Key]
+		if !ok {
+			idx = len(resp.Monitors)
+			monitorIdx[st.Key] = idx
+			resp.Monitors = append(resp.Monitors, IPFamilyMonitorItem{
+				Provider: st.Key.Provider,
+				Service:  st.Key.Service,
+				Channel:  st.Key.Channel,
+				Model:    st.Key.Model,
+				IPFamily: families[st.Key],
+			})
+		}
+		m := &resp.Monitors[idx]
+		m.IPv6OnlyFailures += st.IPv6OnlyFailures
+		addIPFamilyStat(familySlot(&m.IPv4, &m.IPv6, &m.Unknown, st.Family), st)
+
+		pIdx, ok := providerIdx[st.Key.Provider]
+		if !ok {
+			pIdx = len(resp.Providers)
+			providerIdx[st.Key.Provider] = pIdx
+			resp.Providers = append(resp.Providers, IPFamilyProviderItem{Provider: st.Key.Provider})
+		}
+		p := &resp.Providers[pIdx]
+		p.IPv6OnlyFailures += st.IPv6OnlyFailures
+		addIPFamilyStat(familySlot(&p.IPv4, &p.IPv6, &p.Unknown, st.Family), st)
+	}
+
+	for i := range resp.Monitors {
+		m := &resp.Monitors[i]
+		finishIPFamilyCounts(degradedWeight, m.IPv4, m.IPv6, m.Unknown)
+	}
+	for i := range resp.Providers {
+		p := &resp.Providers[i]
+		finishIPFamilyCounts(degradedWeight, p.IPv4, p.IPv6, p.Unknown)
+		p.IPv6Degraded = ipv6Degraded(p.IPv4, p.IPv6)
+		if p.IPv6Degraded {
+			resp.Meta.IPv6Degraded++
+		}
+	}
+
+	// 存储已按 provider/service/channel/model 排序，服务商按名称排序便于对照
+	sort.SliceStable(resp.Providers, func(i, j int) bool {
+		return strings.ToLower(resp.Providers[i].Provider) < strings.ToLower(resp.Providers[j].Provider)
+	})
+	return resp
+}
+
+// familySlot 按地址族返回对应的统计槽位（首次使用时创建）
+func familySlot(ipv4, ipv6, unknown **IPFamilyCounts, family string) *IPFamilyCounts {
+	slot := unknown
+	switch family {
+	case config.IPFamilyIPv4:
+		slot = ipv4
+	case config.IPFamilyIPv6:
+		slot = ipv6
+	}
+	if *slot == nil {
+		*slot = &IPFamilyCounts{}
+	}
+	return *slot
+}
+
+// addIPFamilyStat 累加一行统计
+func addIPFamilyStat(dst *IPFamilyCounts, st *storage.IPFamilyStat) {
+	dst.Probes += st.Total
+	dst.Available += st.Available
+	dst.Degraded += st.Degraded
+	dst.Unavailable += st.Unavailable
+	dst.latencySum += st.LatencySum
+}
+
+// finishIPFamilyCounts 计算可用率与平均延迟（nil 槽位跳过）
+func finishIPFamilyCounts(degradedWeight float64, counts ...*IPFamilyCounts) {
+	for _, c := range counts {
+		if c == nil || c.Probes == 0 {
+			continue
+		}
+		weight := float64(c.Available) + float64(c.Degraded)*degradedWeight
+		c.Availability = math.Round(weight/float64(c.Probes)*10000) / 100
+		if ok := c.Available + c.Degraded; ok > 0 {
+			c.AvgLatency = int(c.latencySum / int64(ok))
+		}
+	}
+}
+
+// ipv6Degraded IPv4 与 IPv6 均有足够样本且 IPv6 可用率低于 IPv4 超过阈值
+func ipv6Degraded(ipv4, ipv6 *IPFamilyCounts) bool {
+	if ipv4 == nil || ipv6 == nil || ipv4.Probes < ipFamilyMinProbes || ipv6.Probes < ipFamilyMinProbes {
+		return false
+	}
+	return ipv4.Availability-ipv6.Availability > ipFamilyGapThreshold
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestGetAdminIPFamilies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newTestSQLiteStore(t)
+
+	now := time.Now().Unix()
+	save := func(r storage.ProbeRecord, n int) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			rec := r
+			rec.Timestamp = now - int64(i)
+			if err := store.SaveRecord(&rec); err != nil {
+				t.Fatalf("save record: %v", err)
+			}
+		}
+	}
+	// Relay：v4 监测项全部成功，v6 监测项半数仅 IPv6 失败
+	save(storage.ProbeRecord{Provider: "Relay", Service: "cc", Channel: "v4", Status: 1, Latency: 100, IPFamily: "ipv4"}, 10)
+	save(storage.ProbeRecord{Provider: "Relay", Service: "cc", Channel: "v6", Status: 1, Latency: 300, IPFamily: "ipv6"}, 6)
+	save(storage.ProbeRecord{Provider: "Relay", Service: "cc", Channel: "v6", Status: 0, SubStatus: storage.SubStatusIPv6OnlyFailure, IPFamily: "ipv6"}, 6)
+	// Other：auto 监测项，含地址族未知的网络错误
+	save(storage.ProbeRecord{Provider: "Other", Service: "cx", Status: 2, SubStatus: storage.SubStatusSlowLatency, Latency: 50, IPFamily: "ipv6"}, 2)
+	save(storage.ProbeRecord{Provider: "Other", Service: "cx", Status: 0, SubStatus: storage.SubStatusNetworkError}, 1)
+	// 超出统计范围
+	if err := store.SaveRecord(&storage.ProbeRecord{Provider: "Relay", Service: "cc", Channel: "v4", Status: 0, IPFamily: "ipv4", Timestamp: now - 2*86400}); err != nil {
+		t.Fatalf("save record: %v", err)
+	}
+
+	cfg := &config.AppConfig{
+		DegradedWeight: 0.7,
+		Monitors: []config.ServiceConfig{
+			{Provider: "Relay", Service: "cc", Channel: "v4", IPFamily: "ipv4"},
+			{Provider: "Relay", Service: "cc", Channel: "v6", IPFamily: "ipv6"},
+			{Provider: "Other", Service: "cx"},
+		},
+	}
+	h := NewHandler(store, cfg)
+	router := gin.New()
+	router.GET("/api/admin/ip-families", h.GetAdminIPFamilies)
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	w := serve("/api/admin/ip-families")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp IPFamiliesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Meta.Period != "24h" || resp.Meta.IPv6Degraded != 1 || len(resp.Providers) != 2 || len(resp.Monitors) != 3 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	other, relay := resp.Providers[0], resp.Providers[1]
+	if relay.Provider != "Relay" || !relay.IPv6Degraded || relay.IPv6OnlyFailures != 6 || relay.Unknown != nil {
+		t.Fatalf("unexpected relay: %+v", relay)
+	}
+	if relay.IPv4.Probes != 10 || relay.IPv4.Availability != 100 || relay.IPv4.AvgLatency != 100 {
+		t.Fatalf("unexpected relay ipv4: %+v", relay.IPv4)
+	}
+	if relay.IPv6.Probes != 12 || relay.IPv6.Unavailable != 6 || relay.IPv6.Availability != 50 || relay.IPv6.AvgLatency != 300 {
+		t.Fatalf("unexpected relay ipv6: %+v", relay.IPv6)
+	}
+	if other.Provider != "Other" || other.IPv6Degraded || other.IPv4 != nil || other.IPv6.Availability != 70 || other.Unknown.Probes != 1 {
+		t.Fatalf("unexpected other: %+v", other)
+	}
+
+	if m := resp.Monitors[0]; m.Provider != "Other" || m.IPFamily != "auto" {
+		t.Fatalf("unexpected monitor: %+v", m)
+	}
+	if m := resp.Monitors[2]; m.Channel != "v6" || m.IPFamily != "ipv6" || m.IPv4 != nil || m.IPv6OnlyFailures != 6 {
+		t.Fatalf("unexpected monitor: %+v", m)
+	}
+
+	if w := serve("/api/admin/ip-families?provider=other"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	} else if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || len(resp.Providers) != 1 || len(resp.Monitors) != 1 {
+		t.Fatalf("provider filter: %s", w.Body.String())
+	}
+
+	if w := serve("/api/admin/ip-families?period=90d"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
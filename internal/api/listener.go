@@ -0,0 +1,84 @@
+package api
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"sync"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+)
+
+// certReloader 持有当前服务端证书，支持运行时从磁盘重新加载（SIGHUP）
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// reload 重新读取证书文件；失败时保留原证书继续服务
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("加载证书失败: %w", err)
+	}
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+	return nil
+}
+
+// getCertificate 供 tls.Config.GetCertificate 使用
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// listen 按配置创建监听器：Unix socket 优先，否则监听 TCP 地址
+func listen(cfg config.ServerConfig) (net.Listener, error) {
+	if cfg.UnixSocket == "" {
+		return net.Listen("tcp", cfg.ListenAddr())
+	}
+
+	// 清理上次异常退出残留的 socket 文件（仅删除 socket 类型，避免误删普通文件）
+	if info, err := os.Lstat(cfg.UnixSocket); err == nil {
+		if info.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s 已存在且不是 socket 文件", cfg.UnixSocket)
+		}
+		if err := os.Remove(cfg.UnixSocket); err != nil {
+			return nil, fmt.Errorf("删除残留 socket 文件失败: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	ln, err := net.Listen("unix", cfg.UnixSocket)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(cfg.UnixSocket, cfg.UnixSocketModeValue); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("设置 socket 文件权限失败: %w", err)
+	}
+	return ln, nil
+}
+
+// ReloadTLS 从磁盘重新加载 HTTPS 证书（未启用 HTTPS 时为空操作）
+// 证书续期后发送 SIGHUP 即可生效，无需重启；加载失败时继续使用原证书
+func (s *Server) ReloadTLS() error {
+	if s.certs == nil {
+		return nil
+	}
+	if err := s.certs.reload(); err != nil {
+		return err
+	}
+	logger.Info("api", "HTTPS 证书已重新加载", "cert_file", s.certs.certFile)
+	return nil
+}
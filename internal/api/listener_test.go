@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+)
+
+// writeSelfSignedCert 生成自签名证书并写入 certFile/keyFile
+func writeSelfSignedCert(t *testing.T, certFile, keyFile, commonName string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write cert: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+}
+
+// startTestServer 在后台启动仅包含 /health 路由的 Server，测试结束时关闭
+func startTestServer(t *testing.T, listenCfg config.ServerConfig) *Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	if err := listenCfg.Normalize(); err != nil {
+		t.Fatalf("normalize server config: %v", err)
+	}
+	router := gin.New()
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	s := &Server{router: router, listen: listenCfg}
+	if listenCfg.TLS.Enabled() {
+		s.certs = &certReloader{certFile: listenCfg.TLS.CertFile, keyFile: listenCfg.TLS.KeyFile}
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.Start() }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.Stop(ctx)
+		if err := <-errCh; err != nil {
+			t.Errorf("Start() returned error: %v", err)
+		}
+	})
+	return s
+}
+
+func TestServerUnixSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix socket 测试仅在类 Unix 系统运行")
+	}
+
+	socket := filepath.Join(t.TempDir(), "monitor.sock")
+	// 残留的 socket 文件应被清理
+	stale, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("create stale socket: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	startTestServer(t, config.ServerConfig{UnixSocket: socket, UnixSocketMode: "0600"})
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		if resp, err = client.Get("http://unix/health"); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("request over unix socket: %v", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	info, err := os.Stat(socket)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("unexpected socket mode: %v %v", info, err)
+	}
+
+	// 普通文件不会被当作残留 socket 删除
+	regular := filepath.Join(t.TempDir(), "regular")
+	if err := os.WriteFile(regular, nil, 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if _, err := listen(config.ServerConfig{UnixSocket: regular}); err == nil {
+		t.Fatalf("expected error for non-socket file")
+	}
+}
+
+func TestServerTLSReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeSelfSignedCert(t, certFile, keyFile, "first")
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("reserve port: %v", err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	s := startTestServer(t, config.ServerConfig{
+		Address: "127.0.0.1",
+		Port:    port,
+		TLS:     config.ServerTLSConfig{CertFile: certFile, KeyFile: keyFile},
+	})
+
+	peerName := func() string {
+		t.Helper()
+		var conn *tls.Conn
+		var err error
+		for i := 0; i < 50; i++ {
+			conn, err = tls.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), &tls.Config{InsecureSkipVerify: true})
+			if err == nil {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		if err != nil {
+			t.Fatalf("tls dial: %v", err)
+		}
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+	}
+
+	if got := peerName(); got != "first" {
+		t.Fatalf("expected first certificate, got %q", got)
+	}
+
+	writeSelfSignedCert(t, certFile, keyFile, "second")
+	if err := s.ReloadTLS(); err != nil {
+		t.Fatalf("ReloadTLS: %v", err)
+	}
+	if got := peerName(); got != "second" {
+		t.Fatalf("expected reloaded certificate, got %q", got)
+	}
+
+	// 加载失败时继续使用原证书
+	if err := os.WriteFile(keyFile, []byte("broken"), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	if err := s.ReloadTLS(); err == nil {
+		t.Fatalf("expected reload error for broken key")
+	}
+	if got := peerName(); got != "second" {
+		t.Fatalf("expected previous certificate after failed reload, got %q", got)
+	}
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/storage"
+)
+
+// MaintenanceStatusResponse 数据库维护状态响应（GET /api/admin/storage/maintenance）
+type MaintenanceStatusResponse struct {
+	Windows         []string               `json:"windows"`
+	Timezone        string                 `json:"timezone"`
+	IntervalSeconds int64                  `json:"interval_seconds"`
+	Running         bool                   `json:"running"`
+	NextRun         int64                  `json:"next_run,omitempty"` // 下一次自动维护的最早时间（Unix 秒）
+	History         []MaintenanceRunItem   `json:"history"`            // 最近的维护记录（新的在前）
+	Meta            StorageDiagnosticsMeta `json:"meta"`
+}
+
+// MaintenanceRunItem 单轮维护结果
+type MaintenanceRunItem struct {
+	Trigger    string                `json:"trigger"` // schedule / manual
+	StartedAt  int64                 `json:"started_at"`
+	FinishedAt int64                 `json:"finished_at"`
+	DurationMs int64                 `json:"duration_ms"`
+	Steps      []MaintenanceStepItem `json:"steps"`
+	Skipped    string                `json:"skipped,omitempty"`
+	Error      string                `json:"error,omitempty"`
+}
+
+// MaintenanceStepItem 单个维护动作
+type MaintenanceStepItem struct {
+	Action     string `json:"action"` // vacuum / incremental_vacuum / auto_vacuum / analyze / reindex / checkpoint
+	Target     string `json:"target,omitempty"`
+	Detail     string `json:"detail,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// SetMaintainer 设置数据库维护任务（可选，用于 /api/admin/storage/maintenance）
+func (h *Handler) SetMaintainer(m *storage.Maintainer) {
+	h.maintainer = m
+}
+
+// GetAdminStorageMaintenance 查询数据库维护配置、运行状态与最近的维护记录
+// GET /api/admin/storage/maintenance
+func (h *Handler) GetAdminStorageMaintenance(c *gin.Context) {
+	if h.maintainer == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "未启用数据库维护"})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, buildMaintenanceStatusResponse(h.maintainer.Status(), time.Now()))
+}
+
+// PostAdminStorageMaintenanceRun 立即在后台执行一轮数据库维护（忽略维护时段与最小间隔）
+// POST /api/admin/storage/maintenance/run
+func (h *Handler) PostAdminStorageMaintenanceRun(c *gin.Context) {
+	if h.maintainer == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "未启用数据库维护"})
+		return
+	}
+	if _, ok := h.storage.(storage.MaintenanceStorage); !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "当前存储后端不支持数据库维护"})
+		return
+	}
+	if !h.maintainer.RunNow() {
+		c.JSON(http.StatusConflict, gin.H{"error": "数据库维护正在运行"})
+		return
+	}
+
+	h.audit.Record(c.Request.Context(), storage.AuditEntry{
+		Actor:  adminActorOf(c),
+		Action: "storage.maintenance.run",
+		IP:     c.ClientIP(),
+	})
+
+	c.JSON(http.StatusAccepted, gin.H{"status": "started"})
+}
+
+// buildMaintenanceStatusResponse 转换维护任务状态快照
+func buildMaintenanceStatusResponse(st storage.MaintenanceStatus, now time.Time) MaintenanceStatusResponse {
+	resp := MaintenanceStatusResponse{
+		Windows:         st.Windows,
+		Timezone:        st.Timezone,
+		IntervalSeconds: int64(st.Interval.Seconds()),
+		Running:         st.Running,
+		History:         make([]MaintenanceRunItem, 0, len(st.History)),
+		Meta:            StorageDiagnosticsMeta{Now: now.Unix()},
+	}
+	if !st.NextRun.IsZero() {
+		resp.NextRun = st.NextRun.Unix()
+	}
+	for _, r := range st.History {
+		item := MaintenanceRunItem{
+			Trigger:    r.Trigger,
+			StartedAt:  r.StartedAt.Unix(),
+			FinishedAt: r.FinishedAt.Unix(),
+			DurationMs: r.FinishedAt.Sub(r.StartedAt).Milliseconds(),
+			Steps:      make([]MaintenanceStepItem, 0, len(r.Steps)),
+			Skipped:    r.Skipped,
+			Error:      r.Error,
+		}
+		for _, step := range r.Steps {
+			item.Steps = append(item.Steps, MaintenanceStepItem{
+				Action:     step.Action,
+				Target:     step.Target,
+				Detail:     step.Detail,
+				DurationMs: step.Duration.Milliseconds(),
+				Error:      step.Error,
+			})
+		}
+		resp.History = append(resp.History, item)
+	}
+	return resp
+}
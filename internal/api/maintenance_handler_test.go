@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestAdminStorageMaintenance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store, err := storage.New(&config.StorageConfig{
+		Type:   "sqlite",
+		SQLite: config.SQLiteConfig{Path: filepath.Join(t.TempDir(), "monitor.db")},
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer store.Close()
+	if err := store.Init(); err != nil {
+		t.Fatalf("init sqlite: %v", err)
+	}
+
+	// 写入后删除大量记录，制造空闲页
+	now := time.Now().Unix()
+	for i := 0; i < 2000; i++ {
+		if err := store.SaveRecord(&storage.ProbeRecord{Provider: "Relay", Service: "cc", Status: 1, Latency: 100, Timestamp: now - 86400*30 - int64(i)}); err != nil {
+			t.Fatalf("save record: %v", err)
+		}
+	}
+	if _, err := store.PurgeOldRecords(t.Context(), time.Now().AddDate(0, 0, -7), 5000); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+
+	mc := config.MaintenanceConfig{Windows: []string{"00:00-24:00"}}
+	if err := mc.Normalize(); err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	h := NewHandler(store, &config.AppConfig{})
+	router := gin.New()
+	router.GET("/api/admin/storage/maintenance", h.GetAdminStorageMaintenance)
+	router.POST("/api/admin/storage/maintenance/run", h.PostAdminStorageMaintenanceRun)
+
+	// 未启用时返回 404
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/storage/maintenance", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without maintainer, got %d", w.Code)
+	}
+
+	m := storage.NewMaintainer(store, &mc)
+	defer m.Stop()
+	h.SetMaintainer(m)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/storage/maintenance/run", nil))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp MaintenanceStatusResponse
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/storage/maintenance", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(resp.History) > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("maintenance did not finish: %s", w.Body.String())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	run := resp.History[0]
+	if run.Trigger != "manual" || run.Error != "" {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+	actions := make(map[string]bool)
+	for _, step := range run.Steps {
+		if step.Error != "" {
+			t.Fatalf("step %s failed: %s", step.Action, step.Error)
+		}
+		actions[step.Action] = true
+	}
+	if !actions["vacuum"] || !actions["analyze"] {
+		t.Fatalf("expected vacuum and analyze steps, got %+v", run.Steps)
+	}
+	if resp.NextRun == 0 || resp.IntervalSeconds != 86400 {
+		t.Fatalf("unexpected schedule: next_run=%d interval=%d", resp.NextRun, resp.IntervalSeconds)
+	}
+
+	// 首次 VACUUM 后切换为增量回收，第二轮只执行 incremental_vacuum / ANALYZE
+	if !m.RunNow() {
+		t.Fatalf("RunNow() should start a new run")
+	}
+	deadline = time.Now().Add(10 * time.Second)
+	for len(m.Status().History) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("second maintenance did not finish")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	for _, step := range m.Status().History[0].Steps {
+		if step.Action == "vacuum" {
+			t.Fatalf("second run should not full VACUUM: %+v", m.Status().History[0].Steps)
+		}
+	}
+}
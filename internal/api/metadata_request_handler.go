@@ -0,0 +1,428 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+const (
+	// metadataCommentMaxLen 申请说明 / 审核备注最大长度（字符）
+	metadataCommentMaxLen = 500
+
+	// metadataRequestDefaultLimit / metadataRequestMaxLimit 申请列表默认 / 最大条数
+	metadataRequestDefaultLimit = 50
+	metadataRequestMaxLimit     = 500
+
+	// portalRecentRequests 数据门户中返回的最近申请数
+	portalRecentRequests = 20
+)
+
+// MetadataRequestItem 元数据修改申请
+type MetadataRequestItem struct {
+	ID           int64                   `json:"id"`
+	ProviderSlug string                  `json:"provider_slug"`
+	TokenID      int64                   `json:"token_id"`
+	Changes      config.ProviderMetadata `json:"changes"`
+	Comment      string                  `json:"comment,omitempty"`
+	Status       string                  `json:"status"`
+	ReviewNote   string                  `json:"review_note,omitempty"`
+	CreatedAt    int64                   `json:"created_at"`
+	ReviewedAt   int64                   `json:"reviewed_at,omitempty"`
+}
+
+// SubmitMetadataRequest 提交元数据修改申请（字段为 null 或省略表示不修改，空字符串表示清除链接）
+type SubmitMetadataRequest struct {
+	config.ProviderMetadata
+	Comment string `json:"comment"`
+}
+
+// ReviewMetadataRequestBody 审核请求
+type ReviewMetadataRequestBody struct {
+	Note string `json:"note"`
+}
+
+// PortalMetadataResponse 数据门户元数据响应
+type PortalMetadataResponse struct {
+	Enabled      bool                    `json:"enabled"`
+	Fields       []string                `json:"fields"`                  // 允许修改的字段
+	AllowedHosts []string                `json:"allowed_hosts,omitempty"` // 链接允许的域名
+	MaxPrice     float64                 `json:"max_price,omitempty"`     // 倍率上限
+	MaxPending   int                     `json:"max_pending"`             // 同时允许的待审核申请数
+	Current      config.ProviderMetadata `json:"current"`                 // 当前生效值
+	Requests     []MetadataRequestItem   `json:"requests"`                // 最近的申请（最新在前）
+}
+
+// GetProviderPortalMetadata 服务商数据门户：当前元数据、修改限制与最近的申请
+// GET /api/provider-portal/metadata
+func (h *Handler) GetProviderPortalMetadata(c *gin.Context) {
+	ms, ok := h.storage.WithContext(c.Request.Context()).(storage.MetadataRequestStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持元数据修改申请",
+		})
+		return
+	}
+	slug := c.GetString(portalSlugKey)
+
+	h.cfgMu.RLock()
+	monitors := h.providerMonitors(slug, true)
+	limits := h.config.ProviderPortal.Clone().Metadata
+	h.cfgMu.RUnlock()
+	if len(monitors) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("服务商不存在: %s", slug)})
+		return
+	}
+
+	reqs, err := ms.ListMetadataRequests(storage.MetadataRequestFilter{ProviderSlug: slug, Limit: portalRecentRequests})
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("查询元数据修改申请失败", "provider_slug", slug, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询元数据修改申请失败"})
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.JSON(http.StatusOK, PortalMetadataResponse{
+		Enabled:      limits.Enabled,
+		Fields:       limits.Fields,
+		AllowedHosts: limits.AllowedHosts,
+		MaxPrice:     limits.MaxPrice,
+		MaxPending:   limits.MaxPending,
+		Current:      currentProviderMetadata(&monitors[0]),
+		Requests:     toMetadataRequestItems(reqs),
+	})
+}
+
+// PostProviderPortalMetadata 服务商数据门户：提交元数据修改申请（待管理员审核）
+// POST /api/provider-portal/metadata  {"sponsor_url": "...", "price_min": 0.1, "comment": "说明"}
+func (h *Handler) PostProviderPortalMetadata(c *gin.Context) {
+	ms, ok := h.storage.WithContext(c.Request.Context()).(storage.MetadataRequestStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持元数据修改申请",
+		})
+		return
+	}
+	slug := c.GetString(portalSlugKey)
+
+	h.cfgMu.RLock()
+	monitors := h.providerMonitors(slug, true)
+	limits := h.config.ProviderPortal.Clone().Metadata
+	h.cfgMu.RUnlock()
+	if !limits.Enabled {
+		c.JSON(http.StatusNotFound, gin.H{"error": "元数据自助修改未启用"})
+		return
+	}
+	if len(monitors) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("服务商不存在: %s", slug)})
+		return
+	}
+
+	var req SubmitMetadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("无效的 JSON: %v", err)})
+		return
+	}
+	changes := req.ProviderMetadata
+	comment := strings.TrimSpace(req.Comment)
+	if len(changes.Fields()) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "未包含任何修改字段"})
+		return
+	}
+	if utf8.RuneCountInString(comment) > metadataCommentMaxLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("comment 不能超过 %d 个字符", metadataCommentMaxLen)})
+		return
+	}
+	if err := changes.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := limits.CheckLimits(&changes); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// 仅修改一端倍率时与当前值组合校验
+	merged := currentProviderMetadata(&monitors[0])
+	merged.Merge(changes)
+	if err := merged.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pending, err := ms.ListMetadataRequests(storage.MetadataRequestFilter{
+		ProviderSlug: slug,
+		Status:       storage.MetadataRequestPending,
+		Limit:        limits.MaxPending,
+	})
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("查询待审核申请失败", "provider_slug", slug, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "提交申请失败"})
+		return
+	}
+	if len(pending) >= limits.MaxPending {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": fmt.Sprintf("待审核申请已达上限 (%d)，请等待审核后再提交", limits.MaxPending)})
+		return
+	}
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "提交申请失败"})
+		return
+	}
+	tokenID := c.GetInt64(portalTokenIDKey)
+	record := &storage.MetadataRequest{
+		ProviderSlug: slug,
+		TokenID:      tokenID,
+		Changes:      string(data),
+		Comment:      comment,
+		Status:       storage.MetadataRequestPending,
+		CreatedAt:    time.Now().Unix(),
+	}
+	if err := ms.CreateMetadataRequest(record); err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("保存元数据修改申请失败", "provider_slug", slug, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "提交申请失败"})
+		return
+	}
+
+	h.audit.Record(c.Request.Context(), storage.AuditEntry{
+		Actor:  portalActor(tokenID),
+		Action: "provider_metadata.submit",
+		Target: slug,
+		Detail: fmt.Sprintf("id=%d changes=%s", record.ID, data),
+		IP:     c.ClientIP(),
+	})
+
+	c.JSON(http.StatusAccepted, toMetadataRequestItem(record))
+}
+
+// GetAdminMetadataRequests 查询元数据修改申请
+// GET /api/admin/metadata-requests?provider_slug=xxx&status=pending&limit=50
+func (h *Handler) GetAdminMetadataRequests(c *gin.Context) {
+	ms, ok := h.storage.WithContext(c.Request.Context()).(storage.MetadataRequestStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持元数据修改申请",
+		})
+		return
+	}
+
+	filter := storage.MetadataRequestFilter{
+		ProviderSlug: strings.ToLower(strings.TrimSpace(c.Query("provider_slug"))),
+		Status:       strings.ToLower(strings.TrimSpace(c.Query("status"))),
+		Limit:        metadataRequestDefaultLimit,
+	}
+	switch filter.Status {
+	case "", storage.MetadataRequestPending, storage.MetadataRequestApproved, storage.MetadataRequestRejected:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("无效的 status 参数: %s", filter.Status)})
+		return
+	}
+	if v := c.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			filter.Limit = min(n, metadataRequestMaxLimit)
+		}
+	}
+
+	reqs, err := ms.ListMetadataRequests(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询元数据修改申请失败"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"requests": toMetadataRequestItems(reqs)})
+}
+
+// PostAdminApproveMetadataRequest 通过元数据修改申请：写入运行时覆盖（overrides.yaml），热更新后生效
+// POST /api/admin/metadata-requests/:id/approve  {"note": "备注"}
+func (h *Handler) PostAdminApproveMetadataRequest(c *gin.Context) {
+	h.reviewMetadataRequest(c, storage.MetadataRequestApproved)
+}
+
+// PostAdminRejectMetadataRequest 驳回元数据修改申请
+// POST /api/admin/metadata-requests/:id/reject  {"note": "驳回原因"}
+func (h *Handler) PostAdminRejectMetadataRequest(c *gin.Context) {
+	h.reviewMetadataRequest(c, storage.MetadataRequestRejected)
+}
+
+// reviewMetadataRequest 审核元数据修改申请
+func (h *Handler) reviewMetadataRequest(c *gin.Context, status string) {
+	ms, ok := h.storage.WithContext(c.Request.Context()).(storage.MetadataRequestStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持元数据修改申请",
+		})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的申请 ID"})
+		return
+	}
+	var body ReviewMetadataRequestBody
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("无效的 JSON: %v", err)})
+			return
+		}
+	}
+	note := strings.TrimSpace(body.Note)
+	if utf8.RuneCountInString(note) > metadataCommentMaxLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("note 不能超过 %d 个字符", metadataCommentMaxLen)})
+		return
+	}
+
+	record, err := ms.GetMetadataRequest(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询元数据修改申请失败"})
+		return
+	}
+	if record == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "申请不存在"})
+		return
+	}
+	if record.Status != storage.MetadataRequestPending {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("申请已审核: %s", record.Status)})
+		return
+	}
+
+	if status == storage.MetadataRequestApproved {
+		if err := h.applyMetadataRequest(record, adminActorOf(c)); err != nil {
+			code := http.StatusBadRequest
+			switch {
+			case errors.Is(err, errOverridesDisabled):
+				code = http.StatusServiceUnavailable
+			case errors.Is(err, config.ErrOverridesConflict):
+				code = http.StatusConflict
+			}
+			logger.FromContext(c.Request.Context(), "api").Warn("应用元数据修改申请失败", "id", id, "error", err)
+			c.JSON(code, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	reviewed, err := ms.ReviewMetadataRequest(id, status, note, time.Now().Unix())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新申请状态失败"})
+		return
+	}
+	if !reviewed {
+		c.JSON(http.StatusConflict, gin.H{"error": "申请已被其它请求审核"})
+		return
+	}
+
+	action := "provider_metadata.approve"
+	if status == storage.MetadataRequestRejected {
+		action = "provider_metadata.reject"
+	}
+	h.audit.Record(c.Request.Context(), storage.AuditEntry{
+		Actor:  adminActorOf(c),
+		Action: action,
+		Target: record.ProviderSlug,
+		Detail: fmt.Sprintf("id=%d note=%s", id, note),
+		IP:     c.ClientIP(),
+	})
+
+	record.Status = status
+	record.ReviewNote = note
+	record.ReviewedAt = time.Now().Unix()
+	c.JSON(http.StatusOK, toMetadataRequestItem(record))
+}
+
+// errOverridesDisabled 未配置运行时覆盖存储，无法应用通过的申请
+var errOverridesDisabled = errors.New("运行时覆盖未启用，无法应用元数据修改")
+
+// applyMetadataRequest 将申请的修改合并到该服务商的运行时覆盖项
+func (h *Handler) applyMetadataRequest(record *storage.MetadataRequest, actor string) error {
+	if h.overrides == nil {
+		return errOverridesDisabled
+	}
+	var changes config.ProviderMetadata
+	if err := json.Unmarshal([]byte(record.Changes), &changes); err != nil {
+		return fmt.Errorf("解析申请内容失败: %w", err)
+	}
+
+	h.cfgMu.RLock()
+	monitors := h.providerMonitors(record.ProviderSlug, true)
+	h.cfgMu.RUnlock()
+	if len(monitors) == 0 {
+		return fmt.Errorf("服务商不存在: %s", record.ProviderSlug)
+	}
+	provider := monitors[0].Provider
+
+	current, err := h.overrides.Get()
+	if err != nil {
+		return err
+	}
+	// 保留该服务商已有的覆盖项（禁用/隐藏等），仅合并元数据字段
+	po := &config.ProviderOverride{}
+	for key, existing := range current.Providers {
+		if strings.EqualFold(strings.TrimSpace(key), provider) {
+			provider = key
+			copied := *existing
+			po = &copied
+			break
+		}
+	}
+	po.ProviderMetadata.Merge(changes)
+	merged := currentProviderMetadata(&monitors[0])
+	merged.Merge(po.ProviderMetadata)
+	if err := merged.Validate(); err != nil {
+		return err
+	}
+
+	_, err = h.overrides.Patch(config.OverridesPatch{
+		Providers: map[string]*config.ProviderOverride{provider: po},
+	}, current.Revision, actor)
+	return err
+}
+
+// currentProviderMetadata 监测项当前生效的元数据
+func currentProviderMetadata(m *config.ServiceConfig) config.ProviderMetadata {
+	return config.ProviderMetadata{
+		SponsorURL:   &m.SponsorURL,
+		ProviderURL:  &m.ProviderURL,
+		ProviderLogo: &m.ProviderLogo,
+		PriceMin:     m.PriceMin,
+		PriceMax:     m.PriceMax,
+	}
+}
+
+// portalActor 审计日志中的服务商令牌身份
+func portalActor(tokenID int64) string {
+	return fmt.Sprintf("provider_token:%d", tokenID)
+}
+
+// toMetadataRequestItem 转换为 API 响应结构
+func toMetadataRequestItem(r *storage.MetadataRequest) MetadataRequestItem {
+	item := MetadataRequestItem{
+		ID:           r.ID,
+		ProviderSlug: r.ProviderSlug,
+		TokenID:      r.TokenID,
+		Comment:      r.Comment,
+		Status:       r.Status,
+		ReviewNote:   r.ReviewNote,
+		CreatedAt:    r.CreatedAt,
+		ReviewedAt:   r.ReviewedAt,
+	}
+	_ = json.Unmarshal([]byte(r.Changes), &item.Changes)
+	return item
+}
+
+func toMetadataRequestItems(reqs []*storage.MetadataRequest) []MetadataRequestItem {
+	items := make([]MetadataRequestItem, 0, len(reqs))
+	for _, r := range reqs {
+		items = append(items, toMetadataRequestItem(r))
+	}
+	return items
+}
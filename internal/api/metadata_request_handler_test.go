@@ -0,0 +1,153 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestProviderPortalMetadataReview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newTestSQLiteStore(t)
+
+	priceMax := 0.5
+	cfg := &config.AppConfig{
+		ProviderPortal: config.ProviderPortalConfig{Metadata: config.PortalMetadataConfig{
+			Enabled:      true,
+			Fields:       []string{"sponsor_url", "provider_logo", "price_min", "price_max"},
+			AllowedHosts: []string{"relay.example.com"},
+			MaxPrice:     2,
+			MaxPending:   1,
+		}},
+		Monitors: []config.ServiceConfig{
+			{Provider: "Relay", ProviderSlug: "relay", Service: "cc", Channel: "vip", SponsorURL: "https://relay.example.com", PriceMax: &priceMax},
+			{Provider: "Other", ProviderSlug: "other", Service: "cc"},
+		},
+	}
+	if err := cfg.ProviderPortal.Normalize(); err != nil {
+		t.Fatalf("normalize provider_portal: %v", err)
+	}
+	h := NewHandler(store, cfg)
+	overridesPath := filepath.Join(t.TempDir(), config.OverridesFileName)
+	h.SetOverrideStore(config.NewOverrideStore(overridesPath))
+
+	router := gin.New()
+	router.POST("/api/admin/provider-tokens", h.PostAdminProviderToken)
+	router.GET("/api/admin/metadata-requests", h.GetAdminMetadataRequests)
+	router.POST("/api/admin/metadata-requests/:id/approve", h.PostAdminApproveMetadataRequest)
+	router.POST("/api/admin/metadata-requests/:id/reject", h.PostAdminRejectMetadataRequest)
+	router.GET("/api/provider-portal/metadata", h.providerPortalAuth, h.GetProviderPortalMetadata)
+	router.POST("/api/provider-portal/metadata", h.providerPortalAuth, h.PostProviderPortalMetadata)
+
+	serve := func(method, target, body, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(http.MethodPost, "/api/admin/provider-tokens", `{"provider_slug":"relay"}`, "")
+	var created ProviderTokenCreated
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode created token: %v", err)
+	}
+	token := created.Token
+
+	for _, body := range []string{
+		`{}`,
+		`{"provider_url":"https://relay.example.com"}`,       // 字段未允许
+		`{"sponsor_url":"https://evil.example.org"}`,         // 域名不在白名单
+		`{"sponsor_url":"javascript:alert(1)"}`,              // 非 http(s)
+		`{"price_max":3}`,                                    // 超过 max_price
+		`{"price_min":1}`,                                    // 与当前 price_max=0.5 组合后 min > max
+		`{"price_min":-1}`,                                   // 负数
+		`{"sponsor_url":"https://relay.example.com","x":"y"`, // 无效 JSON
+	} {
+		if w := serve(http.MethodPost, "/api/provider-portal/metadata", body, token); w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d: %s", body, w.Code, w.Body.String())
+		}
+	}
+
+	w = serve(http.MethodPost, "/api/provider-portal/metadata", `{"sponsor_url":"https://www.relay.example.com/sponsor","price_min":0.1,"comment":"官网迁移"}`, token)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+	var submitted MetadataRequestItem
+	if err := json.Unmarshal(w.Body.Bytes(), &submitted); err != nil {
+		t.Fatalf("decode request: %v", err)
+	}
+	if submitted.Status != storage.MetadataRequestPending || submitted.TokenID != created.ID || submitted.Changes.PriceMin == nil || *submitted.Changes.PriceMin != 0.1 {
+		t.Fatalf("unexpected request: %+v", submitted)
+	}
+
+	// 待审核申请达到上限
+	if w := serve(http.MethodPost, "/api/provider-portal/metadata", `{"provider_logo":"https://relay.example.com/logo.png"}`, token); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+
+	var portal PortalMetadataResponse
+	w = serve(http.MethodGet, "/api/provider-portal/metadata", "", token)
+	if err := json.Unmarshal(w.Body.Bytes(), &portal); err != nil {
+		t.Fatalf("decode portal metadata: %v", err)
+	}
+	if !portal.Enabled || len(portal.Requests) != 1 || portal.Current.SponsorURL == nil || *portal.Current.SponsorURL != "https://relay.example.com" {
+		t.Fatalf("unexpected portal metadata: %+v", portal)
+	}
+
+	var list struct {
+		Requests []MetadataRequestItem `json:"requests"`
+	}
+	w = serve(http.MethodGet, "/api/admin/metadata-requests?status=pending", "", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Requests) != 1 {
+		t.Fatalf("expected 1 pending request, got %s", w.Body.String())
+	}
+
+	approve := "/api/admin/metadata-requests/" + strconv.FormatInt(submitted.ID, 10) + "/approve"
+	if w := serve(http.MethodPost, approve, `{"note":"已核实"}`, ""); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := serve(http.MethodPost, approve, "", ""); w.Code != http.StatusConflict {
+		t.Fatalf("reviewing twice should return 409, got %d", w.Code)
+	}
+
+	o, err := config.LoadOverrides(overridesPath)
+	if err != nil {
+		t.Fatalf("load overrides: %v", err)
+	}
+	po := o.Providers["Relay"]
+	if po == nil || po.SponsorURL == nil || *po.SponsorURL != "https://www.relay.example.com/sponsor" || po.PriceMin == nil || *po.PriceMin != 0.1 || po.PriceMax != nil {
+		t.Fatalf("approved changes not written to overrides: %+v", o.Providers)
+	}
+
+	// 驳回不修改覆盖
+	w = serve(http.MethodPost, "/api/provider-portal/metadata", `{"provider_logo":"https://relay.example.com/logo.png"}`, token)
+	if err := json.Unmarshal(w.Body.Bytes(), &submitted); err != nil {
+		t.Fatalf("decode request: %v", err)
+	}
+	w = serve(http.MethodPost, "/api/admin/metadata-requests/"+strconv.FormatInt(submitted.ID, 10)+"/reject", `{"note":"图片不清晰"}`, "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"status":"rejected"`) {
+		t.Fatalf("expected rejected, got %d: %s", w.Code, w.Body.String())
+	}
+	if o2, _ := config.LoadOverrides(overridesPath); o2.Revision != o.Revision {
+		t.Fatalf("rejecting must not modify overrides")
+	}
+
+	// 关闭后拒绝提交
+	cfg.ProviderPortal.Metadata.Enabled = false
+	if w := serve(http.MethodPost, "/api/provider-portal/metadata", `{"provider_logo":"https://relay.example.com/a.png"}`, token); w.Code != http.StatusNotFound {
+		t.Fatalf("disabled portal metadata should return 404, got %d", w.Code)
+	}
+}
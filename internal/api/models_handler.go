@@ -0,0 +1,241 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// modelsUptimeWindow 模型矩阵的可用率统计窗口
+const modelsUptimeWindow = 24 * time.Hour
+
+// ModelMatrixResponse 模型可用性矩阵响应（GET /api/models）
+type ModelMatrixResponse struct {
+	Meta ModelMatrixMeta  `json:"meta"`
+	Rows []ModelMatrixRow `json:"rows"`
+}
+
+// ModelMatrixMeta 模型矩阵元数据
+type ModelMatrixMeta struct {
+	Window string   `json:"window"` // 可用率统计窗口（固定 24h）
+	Models []string `json:"models"` // 矩阵列：全部出现过的模型（按名称排序）
+	Count  int      `json:"count"`  // 行数
+}
+
+// ModelMatrixRow 矩阵行：一个 provider/service/channel
+type ModelMatrixRow struct {
+	Provider     string                     `json:"provider"`
+	ProviderName string                     `json:"provider_name,omitempty"`
+	ProviderSlug string                     `json:"provider_slug"`
+	Service      string                     `json:"service"`
+	ServiceName  string                     `json:"service_name,omitempty"`
+	Channel      string                     `json:"channel"`
+	ChannelName  string                     `json:"channel_name,omitempty"`
+	Board        string                     `json:"board"`
+	Status       int                        `json:"status"` // 行级最差状态：0>2>1>-1
+	Cells        map[string]ModelMatrixCell `json:"cells"`  // model -> 单元格（未配置的模型不出现）
+}
+
+// ModelMatrixCell 矩阵单元格：单个模型的当前状态与 24h 可用率
+type ModelMatrixCell struct {
+	Status    int     `json:"status"` // 当前状态：1=绿，0=红，2=黄，-1=无数据
+	SubStatus string  `json:"sub_status,omitempty"`
+	Latency   int     `json:"latency"`
+	Timestamp int64   `json:"timestamp,omitempty"`
+	Uptime    float64 `json:"uptime"` // 24h 可用率百分比（0-100），无数据时为 -1
+	Probes    int     `json:"probes"` // 24h 内探测次数
+}
+
+// GetModels 获取模型级可用性矩阵
+// GET /api/models?provider=xxx&service=xxx&board=hot
+// 仅包含配置了 model 的监测项（父子/多模型结构），按 provider/service/channel 分行
+func (h *Handler) GetModels(c *gin.Context) {
+	qProvider := strings.ToLower(strings.TrimSpace(c.DefaultQuery("provider", "all")))
+	qService := c.DefaultQuery("service", "all")
+	qBoard := strings.ToLower(strings.TrimSpace(c.DefaultQuery("board", "hot")))
+	if qBoard == "" {
+		qBoard = "hot"
+	}
+	if qBoard != "hot" && qBoard != "secondary" && qBoard != "cold" && qBoard != "all" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("无效的 board 参数: %s (支持: hot/secondary/cold/all)", qBoard),
+		})
+		return
+	}
+
+	cacheKey := fmt.Sprintf("models|prov=%s|svc=%s|board=%s", qProvider, qService, qBoard)
+
+	h.cfgMu.RLock()
+	cacheTTL := h.config.CacheTTL.TTLForPeriod("24h")
+	h.cfgMu.RUnlock()
+
+	reqCtx := context.WithoutCancel(c.Request.Context())
+	entry, err := h.cache.loadEntry(reqCtx, cacheKey, cacheTTL, func() ([]byte, error) {
+		ctx, cancel := context.WithTimeout(reqCtx, 30*time.Second)
+		defer cancel()
+		resp, err := h.buildModelMatrix(ctx, qProvider, qService, qBoard)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(resp)
+	})
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("GetModels 失败", "cache_key", cacheKey, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("查询失败: %v", err),
+		})
+		return
+	}
+
+	ttlSeconds := int(cacheTTL.Seconds())
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d, s-maxage=%d", ttlSeconds, ttlSeconds))
+	writeCached(c, entry, "application/json; charset=utf-8")
+}
+
+// buildModelMatrix 查询最新状态与 24h 历史并构建矩阵
+func (h *Handler) buildModelMatrix(ctx context.Context, qProvider, qService, qBoard string) (*ModelMatrixResponse, error) {
+	h.cfgMu.RLock()
+	monitors := h.config.Monitors
+	degradedWeight := h.config.DegradedWeight
+	boardsEnabled := h.config.Boards.Enabled
+	batchQueryMaxKeys := h.config.BatchQueryMaxKeys
+	h.cfgMu.RUnlock()
+
+	// provider 参数支持 slug 别名（与 /api/status 一致）
+	realProvider := qProvider
+	for _, task := range monitors {
+		if task.ProviderSlug == qProvider {
+			realProvider = strings.ToLower(strings.TrimSpace(task.Provider))
+			break
+		}
+	}
+
+	layered := make([]config.ServiceConfig, 0, len(monitors))
+	for _, task := range monitors {
+		if strings.TrimSpace(task.Model) != "" {
+			layered = append(layered, task)
+		}
+	}
+	filtered := h.filterMonitorsForGroups(layered, realProvider, qService, qBoard, boardsEnabled, false)
+
+	keys := make([]storage.MonitorKey, 0, len(filtered))
+	for _, task := range filtered {
+		keys = append(keys, storage.MonitorKey{Provider: task.Provider, Service: task.Service, Channel: task.Channel, Model: task.Model})
+	}
+
+	store := h.storage.WithContext(ctx)
+	since := time.Now().Add(-modelsUptimeWindow)
+	latestMap := make(map[storage.MonitorKey]*storage.ProbeRecord, len(keys))
+	historyMap := make(map[storage.MonitorKey][]*storage.ProbeRecord, len(keys))
+
+	// 按 batch_query_max_keys 分批查询，避免单条 SQL 过大
+	if batchQueryMaxKeys <= 0 {
+		batchQueryMaxKeys = len(keys)
+	}
+	for start := 0; start < len(keys); start += batchQueryMaxKeys {
+		end := min(start+batchQueryMaxKeys, len(keys))
+		chunk := keys[start:end]
+
+		latest, err := store.GetLatestBatch(chunk)
+		if err != nil {
+			return nil, fmt.Errorf("批量查询最新记录失败: %w", err)
+		}
+		for k, v := range latest {
+			latestMap[k] = v
+		}
+
+		history, err := store.GetHistoryBatch(chunk, since)
+		if err != nil {
+			return nil, fmt.Errorf("批量查询历史记录失败: %w", err)
+		}
+		for k, v := range history {
+			historyMap[k] = v
+		}
+	}
+
+	return buildModelMatrixResponse(filtered, latestMap, historyMap, degradedWeight), nil
+}
+
+// buildModelMatrixResponse 将监测项按 provider/service/channel 分行，按 model 分列
+// 行顺序与配置顺序一致（以每个 PSC 首次出现的位置为准）
+func buildModelMatrixResponse(
+	monitors []config.ServiceConfig,
+	latestMap map[storage.MonitorKey]*storage.ProbeRecord,
+	historyMap map[storage.MonitorKey][]*storage.ProbeRecord,
+	degradedWeight float64,
+) *ModelMatrixResponse {
+	rows := make([]ModelMatrixRow, 0)
+	rowIndex := make(map[string]int)
+	modelSet := make(map[string]struct{})
+
+	for _, task := range monitors {
+		psc := task.Provider + "/" + task.Service + "/" + task.Channel
+		idx, ok := rowIndex[psc]
+		if !ok {
+			slug := task.ProviderSlug
+			if slug == "" {
+				slug = strings.ToLower(strings.TrimSpace(task.Provider))
+			}
+			rows = append(rows, ModelMatrixRow{
+				Provider:     task.Provider,
+				ProviderName: task.ProviderName,
+				ProviderSlug: slug,
+				Service:      task.Service,
+				ServiceName:  task.ServiceName,
+				Channel:      task.Channel,
+				ChannelName:  task.ChannelName,
+				Board:        task.Board,
+				Status:       -1,
+				Cells:        make(map[string]ModelMatrixCell),
+			})
+			idx = len(rows) - 1
+			rowIndex[psc] = idx
+		}
+
+		key := storage.MonitorKey{Provider: task.Provider, Service: task.Service, Channel: task.Channel, Model: task.Model}
+		cell := ModelMatrixCell{Status: -1, Uptime: -1}
+		if latest := latestMap[key]; latest != nil {
+			cell.Status = latest.Status
+			cell.SubStatus = string(latest.SubStatus)
+			cell.Latency = latest.Latency
+			cell.Timestamp = latest.Timestamp
+		}
+		if history := historyMap[key]; len(history) > 0 {
+			var weight float64
+			for _, r := range history {
+				weight += availabilityWeight(r.Status, degradedWeight)
+			}
+			cell.Probes = len(history)
+			cell.Uptime = weight / float64(len(history)) * 100
+		}
+
+		rows[idx].Cells[task.Model] = cell
+		rows[idx].Status = pickWorstStatus(rows[idx].Status, cell.Status)
+		modelSet[task.Model] = struct{}{}
+	}
+
+	models := make([]string, 0, len(modelSet))
+	for m := range modelSet {
+		models = append(models, m)
+	}
+	sort.Strings(models)
+
+	return &ModelMatrixResponse{
+		Meta: ModelMatrixMeta{
+			Window: "24h",
+			Models: models,
+			Count:  len(rows),
+		},
+		Rows: rows,
+	}
+}
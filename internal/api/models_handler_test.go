@@ -0,0 +1,57 @@
+package api
+
+import (
+	"testing"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestBuildModelMatrixResponse(t *testing.T) {
+	t.Parallel()
+
+	monitors := []config.ServiceConfig{
+		{Provider: "Relay", Service: "cx", Channel: "vip", Model: "gpt-4o", Board: "hot"},
+		{Provider: "Relay", Service: "cx", Channel: "vip", Model: "o3", Board: "hot"},
+		{Provider: "Other", Service: "cx", Channel: "", Model: "gpt-4o", Board: "hot"},
+	}
+	keyOf := func(m config.ServiceConfig) storage.MonitorKey {
+		return storage.MonitorKey{Provider: m.Provider, Service: m.Service, Channel: m.Channel, Model: m.Model}
+	}
+
+	latest := map[storage.MonitorKey]*storage.ProbeRecord{
+		keyOf(monitors[0]): {Status: 1, Latency: 100, Timestamp: 10},
+		keyOf(monitors[1]): {Status: 2, SubStatus: storage.SubStatusSlowLatency, Latency: 900, Timestamp: 10},
+	}
+	history := map[storage.MonitorKey][]*storage.ProbeRecord{
+		keyOf(monitors[0]): {{Status: 1}, {Status: 1}, {Status: 0}, {Status: 2}},
+	}
+
+	resp := buildModelMatrixResponse(monitors, latest, history, 0.5)
+
+	if len(resp.Rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(resp.Rows))
+	}
+	if got := resp.Meta.Models; len(got) != 2 || got[0] != "gpt-4o" || got[1] != "o3" {
+		t.Fatalf("unexpected model columns: %v", got)
+	}
+
+	row := resp.Rows[0]
+	if row.ProviderSlug != "relay" || row.Status != 2 {
+		t.Fatalf("unexpected first row: slug=%s status=%d", row.ProviderSlug, row.Status)
+	}
+	if cell := row.Cells["gpt-4o"]; cell.Uptime != 62.5 || cell.Probes != 4 {
+		t.Fatalf("unexpected uptime: %+v", cell)
+	}
+	if cell := row.Cells["o3"]; cell.Uptime != -1 || cell.SubStatus != string(storage.SubStatusSlowLatency) {
+		t.Fatalf("unexpected cell without history: %+v", cell)
+	}
+
+	other := resp.Rows[1]
+	if other.Status != -1 || other.Cells["gpt-4o"].Status != -1 {
+		t.Fatalf("expected missing data row, got %+v", other)
+	}
+	if _, ok := other.Cells["o3"]; ok {
+		t.Fatalf("unconfigured model should not appear in row cells")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"monitor/internal/baseline"
 	"monitor/internal/config"
 	"monitor/internal/logger"
 	"monitor/internal/storage"
@@ -15,12 +16,16 @@ type StatusPoint struct {
 	Status    int   `json:"status"`
 	Latency   int   `json:"latency"`
 	Timestamp int64 `json:"timestamp"`
+
+	Correlation *baseline.Correlation `json:"correlation,omitempty"` // 官方基线归因（仅红/黄且配置了同 service 的基线时出现）
 }
 
 // MonitorLayer 监测层（单个 model 的探测结果）
 type MonitorLayer struct {
 	Model         string              `json:"model"`
-	LayerOrder    int                 `json:"layer_order"` // 0=父，1+=子（按配置顺序）
+	LayerOrder    int                 `json:"layer_order"`          // 0=父，1+=子（按配置顺序）
+	Retired       bool                `json:"retired,omitempty"`    // 已从配置移除（仅 include_retired=true 时出现）
+	RetiredAt     int64               `json:"retired_at,omitempty"` // 下线时间（Unix 秒）
 	CurrentStatus StatusPoint         `json:"current_status"`
 	Timeline      []storage.TimePoint `json:"timeline"`
 }
@@ -31,6 +36,7 @@ type MonitorGroup struct {
 	ProviderName  string                 `json:"provider_name,omitempty"`
 	ProviderSlug  string                 `json:"provider_slug"`
 	ProviderURL   string                 `json:"provider_url"`
+	ProviderLogo  string                 `json:"provider_logo,omitempty"`
 	Service       string                 `json:"service"`
 	ServiceName   string                 `json:"service_name,omitempty"`
 	Category      string                 `json:"category"`
@@ -50,6 +56,9 @@ type MonitorGroup struct {
 	TemplateName  string                 `json:"template_name,omitempty"`
 	IntervalMs    int64                  `json:"interval_ms"`
 	SlowLatencyMs int64                  `json:"slow_latency_ms"`
+	Retired       bool                   `json:"retired,omitempty"`     // 父层已从配置移除（仅 include_retired=true 时出现）
+	RetiredAt     int64                  `json:"retired_at,omitempty"`  // 下线时间（Unix 秒）
+	Annotations   []AnnotationItem       `json:"annotations,omitempty"` // 与查询时间范围重叠的运维标注（任一模型层匹配）
 
 	CurrentStatus int            `json:"current_status"` // 组级最差状态：0>2>1>-1
 	Layers        []MonitorLayer `json:"layers"`
@@ -172,6 +181,7 @@ func buildMonitorGroupFromParent(parent config.ServiceConfig, enableBadges bool,
 		ProviderName:  parent.ProviderName,
 		ProviderSlug:  slug,
 		ProviderURL:   parent.ProviderURL,
+		ProviderLogo:  parent.ProviderLogo,
 		Service:       parent.Service,
 		ServiceName:   parent.ServiceName,
 		Category:      parent.Category,
@@ -231,7 +241,7 @@ func (h *Handler) buildMonitorGroups(
 	var layerResults []MonitorResult
 	var err error
 
-	tryBatch := enableBatchQuery && (period == "7d" || period == "30d") && len(layerTasks) <= batchQueryMaxKeys
+	tryBatch := enableBatchQuery && usesDailyBuckets(period) && len(layerTasks) <= batchQueryMaxKeys
 	if tryBatch {
 		layerResults, err = h.getStatusBatch(ctx, layerTasks, since, endTime, period, degradedWeight, timeFilter, enableBadges, enableDBTimelineAgg)
 		if err != nil {
@@ -250,6 +260,20 @@ func (h *Handler) buildMonitorGroups(
 		return nil, err
 	}
 
+	return h.assembleMonitorGroups(layerTasks, layerResults, endTime, period, degradedWeight, timeFilter, enableBadges), nil
+}
+
+// assembleMonitorGroups 按 PSC 将各层查询结果组装为 groups
+// layerTasks 为 Model 非空的监测项，layerResults 与其一一对应
+func (h *Handler) assembleMonitorGroups(
+	layerTasks []config.ServiceConfig,
+	layerResults []MonitorResult,
+	endTime time.Time,
+	period string,
+	degradedWeight float64,
+	timeFilter *TimeFilter,
+	enableBadges bool,
+) []MonitorGroup {
 	// 构建 layerByKey 索引
 	type layerData struct {
 		current  StatusPoint
@@ -374,5 +398,5 @@ func (h *Handler) buildMonitorGroups(
 		groups = append(groups, group)
 	}
 
-	return groups, nil
+	return groups
 }
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+)
+
+// newNamespaceHandler 创建命名空间子处理器（仅服务 /api/{ns}/* 公开路由，缓存独立）
+func newNamespaceHandler(h *Handler, cfg *config.AppConfig, ns string) *Handler {
+	child := &Handler{
+		storage:    h.storage,
+		config:     cfg.ForNamespace(ns),
+		fullConfig: cfg,
+		namespace:  ns,
+	}
+	child.initCaches(cfg)
+	return child
+}
+
+// syncNamespaces 按配置重建命名空间子处理器：沿用仍存在的命名空间（更新配置并清空缓存），移除已删除的
+func (h *Handler) syncNamespaces(cfg *config.AppConfig) {
+	h.cfgMu.Lock()
+	prev := h.namespaces
+	next := make(map[string]*Handler, len(cfg.Namespaces))
+	var reused, created []*Handler
+	for _, ns := range cfg.Namespaces {
+		if child := prev[ns.Name]; child != nil {
+			next[ns.Name] = child
+			reused = append(reused, child)
+			continue
+		}
+		child := newNamespaceHandler(h, cfg, ns.Name)
+		next[ns.Name] = child
+		created = append(created, child)
+	}
+	h.namespaces = next
+	h.cfgMu.Unlock()
+
+	for _, child := range reused {
+		child.setConfig(cfg.ForNamespace(child.namespace), cfg)
+	}
+	// 热更新新增的命名空间同样预热（启动时由 WarmCache 统一预热）
+	if prev != nil {
+		for _, child := range created {
+			child.startCacheWarmup()
+		}
+	}
+}
+
+// inNamespace 将 /api/:ns/* 路由分派到对应命名空间的子处理器，未定义的命名空间返回 404
+func (h *Handler) inNamespace(fn func(*Handler, *gin.Context)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ns := c.Param("ns")
+		h.cfgMu.RLock()
+		child := h.namespaces[ns]
+		h.cfgMu.RUnlock()
+		if child == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("命名空间不存在: %s", ns)})
+			return
+		}
+		fn(child, c)
+	}
+}
+
+// allMonitors 返回全部命名空间的监测项（管理 API 与服务商门户使用），调用方需持有 cfgMu 读锁
+func (h *Handler) allMonitors() []config.ServiceConfig {
+	if h.fullConfig != nil {
+		return h.fullConfig.Monitors
+	}
+	return h.config.Monitors
+}
+
+// eventNamespace 返回事件查询的命名空间过滤条件（未定义命名空间时为 nil，不过滤）
+func (h *Handler) eventNamespace() *string {
+	h.cfgMu.RLock()
+	defer h.cfgMu.RUnlock()
+	if len(h.config.Namespaces) == 0 {
+		return nil
+	}
+	ns := h.namespace
+	return &ns
+}
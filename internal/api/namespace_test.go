@@ -0,0 +1,121 @@
+package api
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestNamespaceRoutes(t *testing.T) {
+	store := newTestSQLiteStore(t)
+
+	now := time.Now().Unix()
+	for _, r := range []*storage.ProbeRecord{
+		{Provider: "Public", Service: "cc", Status: 1, HttpCode: 200, Timestamp: now - 60},
+		{Provider: "Secret", Service: "cc", Status: 1, HttpCode: 200, Timestamp: now - 60},
+	} {
+		if err := store.SaveRecord(r); err != nil {
+			t.Fatalf("save record: %v", err)
+		}
+	}
+	for i, e := range []*storage.StatusEvent{
+		{Provider: "Public", Service: "cc", EventType: storage.EventTypeDown},
+		{Namespace: "team-a", Provider: "Secret", Service: "cc", EventType: storage.EventTypeDown},
+	} {
+		e.TriggerRecordID = int64(i + 1)
+		e.ObservedAt, e.CreatedAt = now, now
+		if err := store.SaveStatusEvent(e); err != nil {
+			t.Fatalf("save event: %v", err)
+		}
+	}
+
+	cfg := &config.AppConfig{
+		Events:     config.EventsConfig{APIToken: "events-token"},
+		Namespaces: []config.NamespaceConfig{{Name: "team-a"}},
+		Monitors: []config.ServiceConfig{
+			{Provider: "Public", ProviderSlug: "public", Service: "cc"},
+			{Provider: "Secret", ProviderSlug: "secret", Service: "cc", Namespace: "team-a"},
+		},
+	}
+	srv := NewServer(store, cfg)
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		req.Header.Set("Authorization", "Bearer events-token")
+		w := httptest.NewRecorder()
+		srv.router.ServeHTTP(w, req)
+		return w
+	}
+	body := func(t *testing.T, w *httptest.ResponseRecorder) string {
+		t.Helper()
+		if w.Header().Get("Content-Encoding") != "gzip" {
+			return w.Body.String()
+		}
+		zr, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatalf("gzip reader: %v", err)
+		}
+		data, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("read gzip body: %v", err)
+		}
+		return string(data)
+	}
+
+	t.Run("status isolated", func(t *testing.T) {
+		w := serve("/api/status")
+		if got := body(t, w); w.Code != http.StatusOK || !strings.Contains(got, `"Public"`) || strings.Contains(got, `"Secret"`) {
+			t.Fatalf("default namespace should only list Public: %d %s", w.Code, got)
+		}
+		w = serve("/api/team-a/status")
+		if got := body(t, w); w.Code != http.StatusOK || !strings.Contains(got, `"Secret"`) || strings.Contains(got, `"Public"`) {
+			t.Fatalf("team-a should only list Secret: %d %s", w.Code, got)
+		}
+	})
+
+	t.Run("provider detail isolated", func(t *testing.T) {
+		if w := serve("/api/providers/secret"); w.Code != http.StatusNotFound {
+			t.Fatalf("default namespace should not expose team-a provider, got %d", w.Code)
+		}
+		if w := serve("/api/team-a/providers/secret"); w.Code != http.StatusOK {
+			t.Fatalf("expected team-a provider detail, got %d: %s", w.Code, body(t, w))
+		}
+	})
+
+	t.Run("events isolated", func(t *testing.T) {
+		for target, provider := range map[string]string{"/api/events": "Public", "/api/team-a/events": "Secret"} {
+			var resp EventsResponse
+			if err := json.Unmarshal([]byte(body(t, serve(target))), &resp); err != nil {
+				t.Fatalf("decode %s: %v", target, err)
+			}
+			if len(resp.Events) != 1 || resp.Events[0].Provider != provider {
+				t.Fatalf("%s should only return %s events: %+v", target, provider, resp.Events)
+			}
+		}
+	})
+
+	t.Run("unknown namespace", func(t *testing.T) {
+		if w := serve("/api/team-b/status"); w.Code != http.StatusNotFound {
+			t.Fatalf("expected 404 for unknown namespace, got %d", w.Code)
+		}
+	})
+
+	t.Run("reload removes namespace", func(t *testing.T) {
+		next := cfg.Clone()
+		next.Namespaces = nil
+		next.Monitors = next.Monitors[:1]
+		srv.handler.UpdateConfig(next)
+		if w := serve("/api/team-a/status"); w.Code != http.StatusNotFound {
+			t.Fatalf("expected 404 after namespace removed, got %d", w.Code)
+		}
+	})
+}
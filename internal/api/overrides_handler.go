@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// OverridesResponse 运行时覆盖响应
+type OverridesResponse struct {
+	Path      string            `json:"path"`
+	Overrides *config.Overrides `json:"overrides"`
+}
+
+// SetOverrideStore 设置运行时覆盖存储（可选，用于 /api/admin/overrides）
+func (h *Handler) SetOverrideStore(s *config.OverrideStore) {
+	h.overrides = s
+}
+
+// GetAdminOverrides 查询运行时覆盖
+// GET /api/admin/overrides
+func (h *Handler) GetAdminOverrides(c *gin.Context) {
+	if h.overrides == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "运行时覆盖未启用"})
+		return
+	}
+
+	o, err := h.overrides.Get()
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("读取运行时覆盖失败", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "读取覆盖配置失败"})
+		return
+	}
+	c.Header("ETag", strconv.FormatInt(o.Revision, 10))
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, OverridesResponse{Path: h.overrides.Path(), Overrides: o})
+}
+
+// PatchAdminOverrides 更新运行时覆盖
+// PATCH /api/admin/overrides
+// 请求体 {"monitors": {"provider/service/channel[/model]": {...} | null}, "providers": {"name": {...} | null}}，
+// 按 key 整体替换覆盖项，null 删除；携带 If-Match: <revision> 时 revision 不一致返回 409。
+// 写入 overrides.yaml 后由配置监听器热更新生效，重启后仍保留
+func (h *Handler) PatchAdminOverrides(c *gin.Context) {
+	if h.overrides == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "运行时覆盖未启用"})
+		return
+	}
+
+	var patch config.OverridesPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("无效的 JSON: %v", err)})
+		return
+	}
+	if len(patch.Monitors) == 0 && len(patch.Providers) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "monitors 与 providers 不能同时为空"})
+		return
+	}
+
+	expectedRevision := int64(-1)
+	if v := strings.Trim(strings.TrimSpace(c.GetHeader("If-Match")), `"`); v != "" {
+		rev, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || rev < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "If-Match 必须为覆盖配置的 revision"})
+			return
+		}
+		expectedRevision = rev
+	}
+
+	h.cfgMu.RLock()
+	err := validateOverridesPatch(h.allMonitors(), patch)
+	h.cfgMu.RUnlock()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	o, err := h.overrides.Patch(patch, expectedRevision, adminActorOf(c))
+	switch {
+	case errors.Is(err, config.ErrOverridesConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		logger.FromContext(c.Request.Context(), "api").Error("写入运行时覆盖失败", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	detail, _ := json.Marshal(patch)
+	h.audit.Record(c.Request.Context(), storage.AuditEntry{
+		Actor:  adminActorOf(c),
+		Action: "config.override",
+		Target: h.overrides.Path(),
+		Detail: fmt.Sprintf("revision=%d patch=%s", o.Revision, detail),
+		IP:     c.ClientIP(),
+	})
+
+	c.Header("ETag", strconv.FormatInt(o.Revision, 10))
+	c.JSON(http.StatusOK, OverridesResponse{Path: h.overrides.Path(), Overrides: o})
+}
+
+// validateOverridesPatch 校验覆盖目标在当前配置中存在（删除覆盖项不校验，允许清理已移除监测项的残留覆盖）
+func validateOverridesPatch(monitors []config.ServiceConfig, patch config.OverridesPatch) error {
+	for key, mo := range patch.Monitors {
+		if mo == nil {
+			continue
+		}
+		found := false
+		for i := range monitors {
+			if config.MatchOverrideKey(strings.TrimSpace(key), &monitors[i]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("监测项不存在: %s", key)
+		}
+	}
+	for provider, po := range patch.Providers {
+		if po == nil {
+			continue
+		}
+		found := false
+		for i := range monitors {
+			if strings.EqualFold(monitors[i].Provider, strings.TrimSpace(provider)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("服务商不存在: %s", provider)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+)
+
+func TestAdminOverridesPatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := NewHandler(nil, &config.AppConfig{
+		Monitors: []config.ServiceConfig{
+			{Provider: "Relay", Service: "cc", Channel: "vip", Model: "sonnet"},
+		},
+	})
+	router := gin.New()
+	router.GET("/api/admin/overrides", h.GetAdminOverrides)
+	router.PATCH("/api/admin/overrides", h.PatchAdminOverrides)
+
+	serve := func(method, body, ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/admin/overrides", strings.NewReader(body))
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := serve(http.MethodGet, "", ""); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("未设置存储时应返回 503，实际 %d", w.Code)
+	}
+
+	path := filepath.Join(t.TempDir(), config.OverridesFileName)
+	h.SetOverrideStore(config.NewOverrideStore(path))
+
+	for _, body := range []string{
+		`{}`,
+		`{"monitors":{"relay/cx/vip":{"disabled":true}}}`,
+		`{"monitors":{"relay":{"disabled":true}}}`,
+		`{"monitors":{"relay/cc/vip":{"interval":"later"}}}`,
+		`{"providers":{"missing":{"hidden":true}}}`,
+	} {
+		if w := serve(http.MethodPatch, body, ""); w.Code != http.StatusBadRequest {
+			t.Fatalf("期望 400: %s，实际 %d %s", body, w.Code, w.Body.String())
+		}
+	}
+
+	w := serve(http.MethodPatch, `{"monitors":{"relay/cc/vip":{"disabled":true,"disabled_reason":"排查中"}},"providers":{"relay":{"hidden":true}}}`, "0")
+	if w.Code != http.StatusOK {
+		t.Fatalf("期望 200，实际 %d %s", w.Code, w.Body.String())
+	}
+	var resp OverridesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if resp.Path != path || resp.Overrides.Revision != 1 || w.Header().Get("ETag") != "1" {
+		t.Fatalf("响应不符合预期: %+v etag=%s", resp, w.Header().Get("ETag"))
+	}
+	if mo := resp.Overrides.Monitors["relay/cc/vip"]; mo == nil || mo.Disabled == nil || !*mo.Disabled {
+		t.Fatalf("监测项覆盖未写入: %+v", resp.Overrides.Monitors)
+	}
+
+	// 过期 revision
+	if w := serve(http.MethodPatch, `{"providers":{"relay":null}}`, "0"); w.Code != http.StatusConflict {
+		t.Fatalf("期望 409，实际 %d", w.Code)
+	}
+
+	// 删除不校验目标是否存在
+	if w := serve(http.MethodPatch, `{"providers":{"relay":null,"gone":null}}`, `"1"`); w.Code != http.StatusOK {
+		t.Fatalf("期望 200，实际 %d %s", w.Code, w.Body.String())
+	}
+
+	w = serve(http.MethodGet, "", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if resp.Overrides.Revision != 2 || len(resp.Overrides.Monitors) != 1 || len(resp.Overrides.Providers) != 0 {
+		t.Fatalf("持久化内容不符合预期: %+v", resp.Overrides)
+	}
+}
@@ -0,0 +1,470 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+const (
+	// providerUptimeWindow 服务商详情的可用率统计窗口
+	providerUptimeWindow = 24 * time.Hour
+
+	// providerIncidentLimit 服务商详情返回的最近事件条数
+	providerIncidentLimit = 20
+
+	// providerIncidentScan 查询事件时的扫描条数（隐藏/禁用通道的事件会被剔除）
+	providerIncidentScan = 100
+)
+
+// ProviderDetailResponse 服务商详情响应（GET /api/providers/:slug）
+// 一次返回 services → channels → models 完整结构、元数据、状态汇总与最近事件
+type ProviderDetailResponse struct {
+	Provider     string                 `json:"provider"`
+	ProviderName string                 `json:"provider_name,omitempty"`
+	ProviderSlug string                 `json:"provider_slug"`
+	ProviderURL  string                 `json:"provider_url"`
+	ProviderLogo string                 `json:"provider_logo,omitempty"`
+	Category     string                 `json:"category"`
+	Sponsor      string                 `json:"sponsor"`
+	SponsorURL   string                 `json:"sponsor_url"`
+	SponsorLevel config.SponsorLevel    `json:"sponsor_level,omitempty"` // 各通道中的最高赞助等级
+	Risks        []config.RiskBadge     `json:"risks,omitempty"`         // 各通道风险徽标（按 label 去重）
+	Badges       []config.ResolvedBadge `json:"badges,omitempty"`        // 各通道通用徽标（按 id 去重）
+	PriceMin     *float64               `json:"price_min,omitempty"`     // 各通道参考倍率下限的最小值
+	PriceMax     *float64               `json:"price_max,omitempty"`     // 各通道参考倍率的最大值
+	ListedDays   *int                   `json:"listed_days,omitempty"`   // 最早收录通道的收录天数
+
+	Window    string            `json:"window"` // 可用率统计窗口（固定 24h）
+	Status    int               `json:"status"` // 服务商级最差状态：0>2>1>-1
+	Uptime    float64           `json:"uptime"` // 24h 可用率百分比（0-100），无数据时为 -1
+	Services  []ProviderService `json:"services"`
+	Incidents []EventItem       `json:"incidents"` // 最近的状态变更事件（最新在前）
+}
+
+// ProviderService 服务商下的单个服务
+type ProviderService struct {
+	Service     string            `json:"service"`
+	ServiceName string            `json:"service_name,omitempty"`
+	Status      int               `json:"status"`
+	Uptime      float64           `json:"uptime"`
+	Channels    []ProviderChannel `json:"channels"`
+}
+
+// ProviderChannel 服务下的单个通道
+type ProviderChannel struct {
+	Channel      string                 `json:"channel"`
+	ChannelName  string                 `json:"channel_name,omitempty"`
+	Board        string                 `json:"board"`
+	ColdReason   string                 `json:"cold_reason,omitempty"`
+	Sponsor      string                 `json:"sponsor"`
+	SponsorURL   string                 `json:"sponsor_url"`
+	SponsorLevel config.SponsorLevel    `json:"sponsor_level,omitempty"`
+	Risks        []config.RiskBadge     `json:"risks,omitempty"`
+	Badges       []config.ResolvedBadge `json:"badges,omitempty"`
+	PriceMin     *float64               `json:"price_min,omitempty"`
+	PriceMax     *float64               `json:"price_max,omitempty"`
+	ListedDays   *int                   `json:"listed_days,omitempty"`
+	IntervalMs   int64                  `json:"interval_ms"`
+	Hidden       bool                   `json:"hidden,omitempty"`        // 隐藏的通道（仅服务商数据门户返回）
+	HiddenReason string                 `json:"hidden_reason,omitempty"` // 隐藏原因
+
+	Status    int             `json:"status"` // 通道级最差状态（含全部模型）
+	Latency   int             `json:"latency"`
+	Timestamp int64           `json:"timestamp,omitempty"`
+	Uptime    float64         `json:"uptime"`
+	Models    []ProviderModel `json:"models"` // 父子/多模型结构的各层（按配置顺序），单模型通道为空
+}
+
+// ProviderModel 通道下的单个模型
+type ProviderModel struct {
+	Model     string  `json:"model"`
+	Status    int     `json:"status"`
+	SubStatus string  `json:"sub_status,omitempty"`
+	Latency   int     `json:"latency"`
+	Timestamp int64   `json:"timestamp,omitempty"`
+	Uptime    float64 `json:"uptime"`
+	Probes    int     `json:"probes"`
+}
+
+// GetProviderDetail 获取服务商详情
+// GET /api/providers/:slug
+// slug 匹配 provider_slug，未配置时匹配 provider 小写；不区分板块，排除隐藏与禁用的监测项
+func (h *Handler) GetProviderDetail(c *gin.Context) {
+	slug := strings.ToLower(strings.TrimSpace(c.Param("slug")))
+
+	h.cfgMu.RLock()
+	monitors := h.providerMonitors(slug, false)
+	cacheTTL := h.config.CacheTTL.Provider.TTLDuration
+	if h.cacheBypassed(slug) {
+		cacheTTL = 0
+	}
+	h.cfgMu.RUnlock()
+
+	if len(monitors) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("服务商不存在: %s", slug),
+		})
+		return
+	}
+
+	cacheKey := "provider|slug=" + slug
+	reqCtx := context.WithoutCancel(c.Request.Context())
+	entry, err := h.provCache.loadEntry(reqCtx, cacheKey, cacheTTL, func() ([]byte, error) {
+		ctx, cancel := context.WithTimeout(reqCtx, 30*time.Second)
+		defer cancel()
+		resp, err := h.buildProviderDetail(ctx, monitors)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(resp)
+	})
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("GetProviderDetail 失败", "cache_key", cacheKey, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("查询失败: %v", err),
+		})
+		return
+	}
+
+	setCacheControl(c, cacheTTL)
+	writeCached(c, entry, "application/json; charset=utf-8")
+}
+
+// providerMonitors 返回 slug 对应服务商的可见监测项（保留配置顺序），调用方需持有 cfgMu 读锁
+// includeHidden 为 true 时包含隐藏的监测项并跨全部命名空间查找（服务商数据门户与管理 API 使用），禁用的监测项始终排除
+func (h *Handler) providerMonitors(slug string, includeHidden bool) []config.ServiceConfig {
+	if slug == "" {
+		return nil
+	}
+
+	monitors := h.config.Monitors
+	if includeHidden {
+		monitors = h.allMonitors()
+	}
+	var provider string
+	for _, task := range monitors {
+		taskSlug := task.ProviderSlug
+		if taskSlug == "" {
+			taskSlug = strings.ToLower(strings.TrimSpace(task.Provider))
+		}
+		if taskSlug == slug {
+			provider = strings.ToLower(strings.TrimSpace(task.Provider))
+			break
+		}
+	}
+	if provider == "" {
+		return nil
+	}
+	return h.filterMonitorsForGroups(monitors, provider, "all", "all", false, includeHidden)
+}
+
+// buildProviderDetail 批量查询最新状态、24h 历史与最近事件并构建详情
+func (h *Handler) buildProviderDetail(ctx context.Context, monitors []config.ServiceConfig) (*ProviderDetailResponse, error) {
+	h.cfgMu.RLock()
+	degradedWeight := h.config.DegradedWeight
+	enableBadges := h.config.EnableBadges
+	batchQueryMaxKeys := h.config.BatchQueryMaxKeys
+	h.cfgMu.RUnlock()
+
+	keys := make([]storage.MonitorKey, 0, len(monitors))
+	for _, task := range monitors {
+		keys = append(keys, storage.MonitorKey{Provider: task.Provider, Service: task.Service, Channel: task.Channel, Model: task.Model})
+	}
+
+	store := h.storage.WithContext(ctx)
+	since := time.Now().Add(-providerUptimeWindow)
+	latestMap := make(map[storage.MonitorKey]*storage.ProbeRecord, len(keys))
+	historyMap := make(map[storage.MonitorKey][]*storage.ProbeRecord, len(keys))
+
+	// 按 batch_query_max_keys 分批查询，避免单条 SQL 过大
+	if batchQueryMaxKeys <= 0 {
+		batchQueryMaxKeys = len(keys)
+	}
+	for start := 0; start < len(keys); start += batchQueryMaxKeys {
+		end := min(start+batchQueryMaxKeys, len(keys))
+		chunk := keys[start:end]
+
+		latest, err := store.GetLatestBatch(chunk)
+		if err != nil {
+			return nil, fmt.Errorf("批量查询最新记录失败: %w", err)
+		}
+		for k, v := range latest {
+			latestMap[k] = v
+		}
+
+		history, err := store.GetHistoryBatch(chunk, since)
+		if err != nil {
+			return nil, fmt.Errorf("批量查询历史记录失败: %w", err)
+		}
+		for k, v := range history {
+			historyMap[k] = v
+		}
+	}
+
+	events, err := store.GetRecentStatusEvents(providerIncidentScan, &storage.EventFilters{Provider: monitors[0].Provider})
+	if err != nil {
+		return nil, fmt.Errorf("查询最近事件失败: %w", err)
+	}
+
+	return buildProviderDetailResponse(monitors, latestMap, historyMap, events, degradedWeight, enableBadges, time.Now()), nil
+}
+
+// uptimeAcc 可用率累加器（按探测次数加权）
+type uptimeAcc struct {
+	weight float64
+	probes int
+}
+
+func (a *uptimeAcc) add(history []*storage.ProbeRecord, degradedWeight float64) {
+	for _, r := range history {
+		a.weight += availabilityWeight(r.Status, degradedWeight)
+	}
+	a.probes += len(history)
+}
+
+// percent 返回可用率百分比，无数据时为 -1
+func (a *uptimeAcc) percent() float64 {
+	if a.probes == 0 {
+		return -1
+	}
+	return a.weight / float64(a.probes) * 100
+}
+
+// buildProviderDetailResponse 将服务商的监测项组装为 services → channels → models 树
+// 服务与通道顺序与配置一致（以首次出现的位置为准）；同一通道的元数据取首个监测项（父层）
+func buildProviderDetailResponse(
+	monitors []config.ServiceConfig,
+	latestMap map[storage.MonitorKey]*storage.ProbeRecord,
+	historyMap map[storage.MonitorKey][]*storage.ProbeRecord,
+	events []*storage.StatusEvent,
+	degradedWeight float64,
+	enableBadges bool,
+	now time.Time,
+) *ProviderDetailResponse {
+	first := monitors[0]
+	slug := first.ProviderSlug
+	if slug == "" {
+		slug = strings.ToLower(strings.TrimSpace(first.Provider))
+	}
+
+	resp := &ProviderDetailResponse{
+		Provider:     first.Provider,
+		ProviderName: first.ProviderName,
+		ProviderSlug: slug,
+		ProviderURL:  first.ProviderURL,
+		ProviderLogo: first.ProviderLogo,
+		Category:     first.Category,
+		Sponsor:      first.Sponsor,
+		SponsorURL:   first.SponsorURL,
+		Window:       "24h",
+		Status:       -1,
+		Services:     make([]ProviderService, 0),
+		Incidents:    make([]EventItem, 0),
+	}
+
+	serviceIndex := make(map[string]int)
+	channelIndex := make(map[string]int)
+	serviceAcc := make(map[string]*uptimeAcc)
+	channelAcc := make(map[string]*uptimeAcc)
+	var providerAcc uptimeAcc
+	riskSeen := make(map[string]bool)
+	badgeSeen := make(map[string]bool)
+
+	for _, task := range monitors {
+		sIdx, ok := serviceIndex[task.Service]
+		if !ok {
+			resp.Services = append(resp.Services, ProviderService{
+				Service:     task.Service,
+				ServiceName: task.ServiceName,
+				Status:      -1,
+				Channels:    make([]ProviderChannel, 0),
+			})
+			sIdx = len(resp.Services) - 1
+			serviceIndex[task.Service] = sIdx
+			serviceAcc[task.Service] = &uptimeAcc{}
+		}
+		svc := &resp.Services[sIdx]
+
+		sc := task.Service + "/" + task.Channel
+		cIdx, ok := channelIndex[sc]
+		if !ok {
+			svc.Channels = append(svc.Channels, buildProviderChannel(task, enableBadges, now))
+			cIdx = len(svc.Channels) - 1
+			channelIndex[sc] = cIdx
+			channelAcc[sc] = &uptimeAcc{}
+
+			ch := svc.Channels[cIdx]
+			resp.PriceMin = minPrice(resp.PriceMin, ch.PriceMin)
+			resp.PriceMax = maxPrice(resp.PriceMax, ch.PriceMax)
+			if ch.ListedDays != nil && (resp.ListedDays == nil || *ch.ListedDays > *resp.ListedDays) {
+				resp.ListedDays = ch.ListedDays
+			}
+			if sponsorLevelRank(ch.SponsorLevel) > sponsorLevelRank(resp.SponsorLevel) {
+				resp.SponsorLevel = ch.SponsorLevel
+			}
+			for _, r := range ch.Risks {
+				if !riskSeen[r.Label] {
+					riskSeen[r.Label] = true
+					resp.Risks = append(resp.Risks, r)
+				}
+			}
+			for _, b := range ch.Badges {
+				if !badgeSeen[b.ID] {
+					badgeSeen[b.ID] = true
+					resp.Badges = append(resp.Badges, b)
+				}
+			}
+		}
+		ch := &svc.Channels[cIdx]
+
+		key := storage.MonitorKey{Provider: task.Provider, Service: task.Service, Channel: task.Channel, Model: task.Model}
+		model := ProviderModel{Model: task.Model, Status: -1, Uptime: -1}
+		if latest := latestMap[key]; latest != nil {
+			model.Status = latest.Status
+			model.SubStatus = string(latest.SubStatus)
+			model.Latency = latest.Latency
+			model.Timestamp = latest.Timestamp
+		}
+		history := historyMap[key]
+		var acc uptimeAcc
+		acc.add(history, degradedWeight)
+		model.Uptime = acc.percent()
+		model.Probes = acc.probes
+
+		channelAcc[sc].add(history, degradedWeight)
+		serviceAcc[task.Service].add(history, degradedWeight)
+		providerAcc.add(history, degradedWeight)
+
+		// 通道延迟与时间戳取最新一次探测
+		if model.Timestamp > ch.Timestamp {
+			ch.Latency = model.Latency
+			ch.Timestamp = model.Timestamp
+		}
+		ch.Status = pickWorstStatus(ch.Status, model.Status)
+		svc.Status = pickWorstStatus(svc.Status, model.Status)
+		resp.Status = pickWorstStatus(resp.Status, model.Status)
+
+		if strings.TrimSpace(task.Model) != "" {
+			ch.Models = append(ch.Models, model)
+		}
+	}
+
+	for i := range resp.Services {
+		svc := &resp.Services[i]
+		svc.Uptime = serviceAcc[svc.Service].percent()
+		for j := range svc.Channels {
+			ch := &svc.Channels[j]
+			ch.Uptime = channelAcc[svc.Service+"/"+ch.Channel].percent()
+		}
+	}
+	resp.Uptime = providerAcc.percent()
+
+	// 仅保留当前可见通道的事件（隐藏/禁用通道的事件不对外展示）
+	for _, e := range events {
+		if _, ok := channelIndex[e.Service+"/"+e.Channel]; !ok {
+			continue
+		}
+		resp.Incidents = append(resp.Incidents, EventItem{
+			ID:              e.ID,
+			Provider:        e.Provider,
+			Service:         e.Service,
+			Channel:         e.Channel,
+			Model:           e.Model,
+			Type:            string(e.EventType),
+			FromStatus:      e.FromStatus,
+			ToStatus:        e.ToStatus,
+			TriggerRecordID: e.TriggerRecordID,
+			ObservedAt:      e.ObservedAt,
+			CreatedAt:       e.CreatedAt,
+			Meta:            e.Meta,
+		})
+		if len(resp.Incidents) >= providerIncidentLimit {
+			break
+		}
+	}
+
+	return resp
+}
+
+// buildProviderChannel 从通道首个监测项构建通道元数据
+// 徽标系统禁用时清空徽标相关字段（与 buildMonitorResult 一致）
+func buildProviderChannel(task config.ServiceConfig, enableBadges bool, now time.Time) ProviderChannel {
+	var listedDays *int
+	if task.ListedSince != "" {
+		if listedDate, err := time.Parse("2006-01-02", task.ListedSince); err == nil {
+			days := int(now.Sub(listedDate).Hours() / 24)
+			if days < 0 {
+				days = 0 // 防止未来日期导致负数
+			}
+			listedDays = &days
+		}
+	}
+
+	ch := ProviderChannel{
+		Channel:      task.Channel,
+		ChannelName:  task.ChannelName,
+		Board:        task.Board,
+		ColdReason:   task.ColdReason,
+		Sponsor:      task.Sponsor,
+		SponsorURL:   task.SponsorURL,
+		SponsorLevel: task.SponsorLevel,
+		Risks:        task.Risks,
+		Badges:       task.ResolvedBadges,
+		PriceMin:     task.PriceMin,
+		PriceMax:     task.PriceMax,
+		ListedDays:   listedDays,
+		IntervalMs:   task.IntervalDuration.Milliseconds(),
+		Hidden:       task.Hidden,
+		HiddenReason: task.HiddenReason,
+		Status:       -1,
+		Uptime:       -1,
+		Models:       make([]ProviderModel, 0),
+	}
+	if !enableBadges {
+		ch.SponsorLevel = ""
+		ch.Risks = nil
+		ch.Badges = nil
+		ch.IntervalMs = 0
+	}
+	return ch
+}
+
+// sponsorLevelRank 赞助等级排序（用于取服务商最高等级）
+func sponsorLevelRank(level config.SponsorLevel) int {
+	switch level {
+	case config.SponsorLevelEnterprise:
+		return 3
+	case config.SponsorLevelAdvanced:
+		return 2
+	case config.SponsorLevelBasic:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// minPrice 返回两个可选倍率中的较小值（nil 表示未配置）
+func minPrice(a, b *float64) *float64 {
+	if b == nil || (a != nil && *a <= *b) {
+		return a
+	}
+	return b
+}
+
+// maxPrice 返回两个可选倍率中的较大值（nil 表示未配置）
+func maxPrice(a, b *float64) *float64 {
+	if b == nil || (a != nil && *a >= *b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestBuildProviderDetailResponse(t *testing.T) {
+	t.Parallel()
+
+	low, high := 0.8, 1.5
+	monitors := []config.ServiceConfig{
+		{Provider: "Relay", ProviderSlug: "relay", Service: "cc", Channel: "vip", Model: "sonnet", Board: "hot", PriceMin: &low, ListedSince: "2024-01-01", SponsorLevel: config.SponsorLevelBasic},
+		{Provider: "Relay", ProviderSlug: "relay", Service: "cc", Channel: "vip", Model: "opus", Parent: "relay/cc/vip", Board: "hot"},
+		{Provider: "Relay", ProviderSlug: "relay", Service: "cx", Channel: "", Board: "cold", PriceMax: &high, ListedSince: "2024-01-11", SponsorLevel: config.SponsorLevelAdvanced},
+	}
+	keyOf := func(m config.ServiceConfig) storage.MonitorKey {
+		return storage.MonitorKey{Provider: m.Provider, Service: m.Service, Channel: m.Channel, Model: m.Model}
+	}
+
+	latest := map[storage.MonitorKey]*storage.ProbeRecord{
+		keyOf(monitors[0]): {Status: 1, Latency: 100, Timestamp: 10},
+		keyOf(monitors[1]): {Status: 0, Latency: 300, Timestamp: 20},
+		keyOf(monitors[2]): {Status: 1, Latency: 50, Timestamp: 30},
+	}
+	history := map[storage.MonitorKey][]*storage.ProbeRecord{
+		keyOf(monitors[0]): {{Status: 1}, {Status: 1}},
+		keyOf(monitors[1]): {{Status: 0}, {Status: 2}},
+	}
+	events := []*storage.StatusEvent{
+		{ID: 3, Provider: "Relay", Service: "cc", Channel: "hidden", EventType: storage.EventTypeDown},
+		{ID: 2, Provider: "Relay", Service: "cc", Channel: "vip", Model: "opus", EventType: storage.EventTypeDown},
+	}
+	now := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	resp := buildProviderDetailResponse(monitors, latest, history, events, 0.5, true, now)
+
+	if resp.ProviderSlug != "relay" || resp.Status != 0 {
+		t.Fatalf("unexpected provider rollup: slug=%s status=%d", resp.ProviderSlug, resp.Status)
+	}
+	if resp.Uptime != 62.5 {
+		t.Fatalf("expected provider uptime 62.5, got %v", resp.Uptime)
+	}
+	if resp.PriceMin == nil || *resp.PriceMin != low || resp.PriceMax == nil || *resp.PriceMax != high {
+		t.Fatalf("unexpected price range: %v-%v", resp.PriceMin, resp.PriceMax)
+	}
+	if resp.ListedDays == nil || *resp.ListedDays != 30 {
+		t.Fatalf("expected listed days from earliest channel, got %v", resp.ListedDays)
+	}
+	if resp.SponsorLevel != config.SponsorLevelAdvanced {
+		t.Fatalf("expected highest sponsor level, got %q", resp.SponsorLevel)
+	}
+
+	if len(resp.Services) != 2 || resp.Services[0].Service != "cc" || resp.Services[1].Service != "cx" {
+		t.Fatalf("unexpected services: %+v", resp.Services)
+	}
+	vip := resp.Services[0].Channels[0]
+	if vip.Status != 0 || vip.Latency != 300 || len(vip.Models) != 2 || vip.Models[1].Model != "opus" {
+		t.Fatalf("unexpected layered channel: %+v", vip)
+	}
+	if vip.Models[0].Uptime != 100 || vip.Models[1].Uptime != 25 {
+		t.Fatalf("unexpected model uptime: %+v", vip.Models)
+	}
+	cx := resp.Services[1].Channels[0]
+	if cx.Status != 1 || cx.Uptime != -1 || len(cx.Models) != 0 {
+		t.Fatalf("unexpected single-model channel: %+v", cx)
+	}
+
+	if len(resp.Incidents) != 1 || resp.Incidents[0].ID != 2 {
+		t.Fatalf("expected only visible channel incidents, got %+v", resp.Incidents)
+	}
+}
@@ -0,0 +1,293 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+const (
+	// providerTokenPrefix 服务商令牌明文前缀（便于在日志/密钥扫描中识别）
+	providerTokenPrefix = "rpp_"
+
+	// providerTokenDisplayLen 列表中展示的令牌前缀长度
+	providerTokenDisplayLen = 12
+
+	// providerTokenNameMaxLen 令牌备注最大长度（字符）
+	providerTokenNameMaxLen = 100
+
+	// portalSlugKey gin.Context 中保存令牌所属服务商的键
+	portalSlugKey = "provider_portal_slug"
+
+	// portalTokenIDKey gin.Context 中保存令牌 ID 的键（用于审计）
+	portalTokenIDKey = "provider_portal_token_id"
+)
+
+// ProviderTokenItem 服务商令牌（不含明文）
+type ProviderTokenItem struct {
+	ID           int64  `json:"id"`
+	ProviderSlug string `json:"provider_slug"`
+	Name         string `json:"name,omitempty"`
+	TokenPrefix  string `json:"token_prefix"`
+	CreatedAt    int64  `json:"created_at"`
+	RevokedAt    int64  `json:"revoked_at,omitempty"`
+	Active       bool   `json:"active"`
+}
+
+// ProviderTokenCreated 签发令牌响应（明文仅在此返回一次）
+type ProviderTokenCreated struct {
+	ProviderTokenItem
+	Token string `json:"token"`
+}
+
+// CreateProviderTokenRequest 签发令牌请求
+type CreateProviderTokenRequest struct {
+	ProviderSlug string `json:"provider_slug"`
+	Name         string `json:"name"`
+}
+
+// PostAdminProviderToken 签发服务商数据访问令牌
+// POST /api/admin/provider-tokens  {"provider_slug": "xxx", "name": "对接人"}
+func (h *Handler) PostAdminProviderToken(c *gin.Context) {
+	ts, ok := h.storage.WithContext(c.Request.Context()).(storage.ProviderTokenStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持服务商令牌",
+		})
+		return
+	}
+
+	var req CreateProviderTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("无效的 JSON: %v", err)})
+		return
+	}
+	slug := strings.ToLower(strings.TrimSpace(req.ProviderSlug))
+	name := strings.TrimSpace(req.Name)
+	if utf8.RuneCountInString(name) > providerTokenNameMaxLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("name 不能超过 %d 个字符", providerTokenNameMaxLen)})
+		return
+	}
+
+	h.cfgMu.RLock()
+	monitors := h.providerMonitors(slug, true)
+	h.cfgMu.RUnlock()
+	if len(monitors) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("服务商不存在: %s", slug)})
+		return
+	}
+
+	plain, err := generateProviderToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成令牌失败"})
+		return
+	}
+	token := &storage.ProviderToken{
+		ProviderSlug: slug,
+		Name:         name,
+		TokenHash:    hashProviderToken(plain),
+		TokenPrefix:  plain[:providerTokenDisplayLen],
+		CreatedAt:    time.Now().Unix(),
+	}
+	if err := ts.CreateProviderToken(token); err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("签发服务商令牌失败", "provider_slug", slug, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "签发令牌失败"})
+		return
+	}
+
+	h.audit.Record(c.Request.Context(), storage.AuditEntry{
+		Actor:  adminActorOf(c),
+		Action: "provider_token.issue",
+		Target: slug,
+		Detail: fmt.Sprintf("id=%d name=%s", token.ID, name),
+		IP:     c.ClientIP(),
+	})
+
+	c.JSON(http.StatusCreated, ProviderTokenCreated{
+		ProviderTokenItem: toProviderTokenItem(token),
+		Token:             plain,
+	})
+}
+
+// GetAdminProviderTokens 查询服务商令牌列表（不含明文）
+// GET /api/admin/provider-tokens?provider_slug=xxx
+func (h *Handler) GetAdminProviderTokens(c *gin.Context) {
+	ts, ok := h.storage.WithContext(c.Request.Context()).(storage.ProviderTokenStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持服务商令牌",
+		})
+		return
+	}
+
+	tokens, err := ts.ListProviderTokens(strings.ToLower(strings.TrimSpace(c.Query("provider_slug"))))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询服务商令牌失败"})
+		return
+	}
+
+	items := make([]ProviderTokenItem, 0, len(tokens))
+	for _, t := range tokens {
+		items = append(items, toProviderTokenItem(t))
+	}
+	c.JSON(http.StatusOK, gin.H{"tokens": items})
+}
+
+// DeleteAdminProviderToken 吊销服务商令牌（立即生效）
+// DELETE /api/admin/provider-tokens/:id
+func (h *Handler) DeleteAdminProviderToken(c *gin.Context) {
+	ts, ok := h.storage.WithContext(c.Request.Context()).(storage.ProviderTokenStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持服务商令牌",
+		})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的令牌 ID"})
+		return
+	}
+
+	revoked, err := ts.RevokeProviderToken(id, time.Now().Unix())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "吊销令牌失败"})
+		return
+	}
+	if !revoked {
+		c.JSON(http.StatusNotFound, gin.H{"error": "令牌不存在或已吊销"})
+		return
+	}
+
+	h.audit.Record(c.Request.Context(), storage.AuditEntry{
+		Actor:  adminActorOf(c),
+		Action: "provider_token.revoke",
+		Target: strconv.FormatInt(id, 10),
+		IP:     c.ClientIP(),
+	})
+
+	c.JSON(http.StatusOK, gin.H{"id": id, "revoked": true})
+}
+
+// providerPortalAuth 服务商数据门户鉴权中间件
+// 校验 Bearer 令牌并将其所属服务商写入上下文，后续处理器仅能访问该服务商的数据
+func (h *Handler) providerPortalAuth(c *gin.Context) {
+	ts, ok := h.storage.WithContext(c.Request.Context()).(storage.ProviderTokenStorage)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持服务商令牌",
+		})
+		return
+	}
+
+	const bearerPrefix = "Bearer "
+	authHeader := c.GetHeader("Authorization")
+	if !strings.HasPrefix(authHeader, bearerPrefix) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"error": "缺少令牌，应为: Authorization: Bearer <token>",
+		})
+		return
+	}
+
+	token, err := ts.GetProviderTokenByHash(hashProviderToken(strings.TrimPrefix(authHeader, bearerPrefix)))
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("查询服务商令牌失败", "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "令牌校验失败"})
+		return
+	}
+	if token == nil || token.RevokedAt != 0 {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "令牌无效或已吊销"})
+		return
+	}
+
+	c.Set(portalSlugKey, token.ProviderSlug)
+	c.Set(portalTokenIDKey, token.ID)
+	c.Next()
+}
+
+// GetProviderPortalStatus 服务商数据门户：令牌所属服务商的详情（含隐藏的监测项）
+// GET /api/provider-portal/status
+// 响应结构同 /api/providers/:slug，隐藏通道带 hidden=true；不区分板块，排除禁用的监测项
+func (h *Handler) GetProviderPortalStatus(c *gin.Context) {
+	slug := c.GetString(portalSlugKey)
+
+	h.cfgMu.RLock()
+	monitors := h.providerMonitors(slug, true)
+	cacheTTL := h.config.CacheTTL.Provider.TTLDuration
+	if h.cacheBypassed(slug) {
+		cacheTTL = 0
+	}
+	h.cfgMu.RUnlock()
+
+	if len(monitors) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("服务商不存在: %s", slug),
+		})
+		return
+	}
+
+	cacheKey := "portal|slug=" + slug
+	reqCtx := context.WithoutCancel(c.Request.Context())
+	entry, err := h.provCache.loadEntry(reqCtx, cacheKey, cacheTTL, func() ([]byte, error) {
+		ctx, cancel := context.WithTimeout(reqCtx, 30*time.Second)
+		defer cancel()
+		resp, err := h.buildProviderDetail(ctx, monitors)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(resp)
+	})
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("GetProviderPortalStatus 失败", "cache_key", cacheKey, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("查询失败: %v", err),
+		})
+		return
+	}
+
+	// 含隐藏数据，禁止 CDN/浏览器共享缓存
+	c.Header("Cache-Control", "private, no-store")
+	writeCached(c, entry, "application/json; charset=utf-8")
+}
+
+// generateProviderToken 生成服务商令牌明文（rpp_ + 48 位十六进制随机数）
+func generateProviderToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return providerTokenPrefix + hex.EncodeToString(buf), nil
+}
+
+// hashProviderToken 计算令牌明文的 SHA-256（存储与查询均使用哈希）
+func hashProviderToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// toProviderTokenItem 转换为 API 响应结构
+func toProviderTokenItem(t *storage.ProviderToken) ProviderTokenItem {
+	return ProviderTokenItem{
+		ID:           t.ID,
+		ProviderSlug: t.ProviderSlug,
+		Name:         t.Name,
+		TokenPrefix:  t.TokenPrefix,
+		CreatedAt:    t.CreatedAt,
+		RevokedAt:    t.RevokedAt,
+		Active:       t.RevokedAt == 0,
+	}
+}
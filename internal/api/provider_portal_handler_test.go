@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+)
+
+func TestProviderPortal(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newTestSQLiteStore(t)
+
+	h := NewHandler(store, &config.AppConfig{
+		Monitors: []config.ServiceConfig{
+			{Provider: "Relay", ProviderSlug: "relay", Service: "cc", Channel: "vip"},
+			{Provider: "Relay", ProviderSlug: "relay", Service: "cc", Channel: "fix", Hidden: true, HiddenReason: "整改中"},
+			{Provider: "Relay", ProviderSlug: "relay", Service: "cx", Channel: "old", Disabled: true},
+			{Provider: "Other", ProviderSlug: "other", Service: "cc"},
+		},
+	})
+	router := gin.New()
+	router.POST("/api/admin/provider-tokens", h.PostAdminProviderToken)
+	router.GET("/api/admin/provider-tokens", h.GetAdminProviderTokens)
+	router.DELETE("/api/admin/provider-tokens/:id", h.DeleteAdminProviderToken)
+	router.GET("/api/provider-portal/status", h.providerPortalAuth, h.GetProviderPortalStatus)
+
+	serve := func(method, target, body, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := serve(http.MethodPost, "/api/admin/provider-tokens", `{"provider_slug":"missing"}`, ""); w.Code != http.StatusBadRequest {
+		t.Fatalf("unknown provider should be rejected, got %d", w.Code)
+	}
+
+	w := serve(http.MethodPost, "/api/admin/provider-tokens", `{"provider_slug":"Relay","name":"ops"}`, "")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created ProviderTokenCreated
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode created token: %v", err)
+	}
+	if created.ProviderSlug != "relay" || !strings.HasPrefix(created.Token, providerTokenPrefix) || !strings.HasPrefix(created.Token, created.TokenPrefix) {
+		t.Fatalf("unexpected created token: %+v", created)
+	}
+
+	// 列表不返回明文
+	if w := serve(http.MethodGet, "/api/admin/provider-tokens?provider_slug=relay", "", ""); strings.Contains(w.Body.String(), created.Token) {
+		t.Fatalf("token list must not contain plaintext token")
+	}
+
+	if w := serve(http.MethodGet, "/api/provider-portal/status", "", ""); w.Code != http.StatusUnauthorized {
+		t.Fatalf("missing token should return 401, got %d", w.Code)
+	}
+	if w := serve(http.MethodGet, "/api/provider-portal/status", "", "rpp_wrong"); w.Code != http.StatusForbidden {
+		t.Fatalf("unknown token should return 403, got %d", w.Code)
+	}
+
+	w = serve(http.MethodGet, "/api/provider-portal/status", "", created.Token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Cache-Control") != "private, no-store" {
+		t.Fatalf("portal response must not be publicly cacheable")
+	}
+	var detail ProviderDetailResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &detail); err != nil {
+		t.Fatalf("decode portal status: %v", err)
+	}
+	if detail.ProviderSlug != "relay" || len(detail.Services) != 1 || len(detail.Services[0].Channels) != 2 {
+		t.Fatalf("expected relay's visible and hidden channels only: %+v", detail.Services)
+	}
+	if fix := detail.Services[0].Channels[1]; !fix.Hidden || fix.HiddenReason != "整改中" {
+		t.Fatalf("expected hidden channel flagged: %+v", fix)
+	}
+
+	if w := serve(http.MethodDelete, "/api/admin/provider-tokens/"+strconv.FormatInt(created.ID, 10), "", ""); w.Code != http.StatusOK {
+		t.Fatalf("expected revoke 200, got %d", w.Code)
+	}
+	if w := serve(http.MethodDelete, "/api/admin/provider-tokens/"+strconv.FormatInt(created.ID, 10), "", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("revoking twice should return 404, got %d", w.Code)
+	}
+	if w := serve(http.MethodGet, "/api/provider-portal/status", "", created.Token); w.Code != http.StatusForbidden {
+		t.Fatalf("revoked token should return 403, got %d", w.Code)
+	}
+}
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"strings"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+// ProviderStatus 服务商综合状态（/api/status 响应的 provider_status，键为 provider_slug）
+type ProviderStatus struct {
+	Provider     string  `json:"provider"`
+	Mode         string  `json:"mode"`         // 生效的计算模式：weighted/worst/quorum
+	Status       int     `json:"status"`       // 综合状态：1=绿，2=黄，0=红，-1=无数据
+	Score        float64 `json:"score"`        // weighted/worst 为加权得分，quorum 为可用权重占比（0-1）
+	Availability float64 `json:"availability"` // 周期内按权重汇总的可用率百分比（0-100），无数据时为 -1
+	Channels     int     `json:"channels"`     // 参与计算的通道数
+}
+
+// providerStatusUnit 参与综合状态计算的通道
+type providerStatusUnit struct {
+	Provider     string
+	Slug         string
+	Weight       float64
+	Status       int     // 当前状态（多模型通道为组级最差状态）
+	Availability float64 // 周期内可用率（0-100），无数据时为 -1
+}
+
+// channelWeights 构建通道权重索引（provider/service/channel → 父层 weight，未配置为 1）
+func channelWeights(monitors []config.ServiceConfig) map[string]float64 {
+	weights := make(map[string]float64)
+	for _, task := range monitors {
+		if strings.TrimSpace(task.Parent) != "" {
+			continue
+		}
+		psc := task.Provider + "/" + task.Service + "/" + task.Channel
+		if _, ok := weights[psc]; ok {
+			continue
+		}
+		weight := 1.0
+		if task.Weight != nil {
+			weight = *task.Weight
+		}
+		weights[psc] = weight
+	}
+	return weights
+}
+
+// collectProviderStatusUnits 从 /api/status 的 data 与 groups 中提取通道
+func collectProviderStatusUnits(data []MonitorResult, groups []MonitorGroup, weights map[string]float64) []providerStatusUnit {
+	weightOf := func(provider, service, channel string) float64 {
+		if w, ok := weights[provider+"/"+service+"/"+channel]; ok {
+			return w
+		}
+		return 1
+	}
+
+	units := make([]providerStatusUnit, 0, len(data)+len(groups))
+	for _, m := range data {
+		// 已下线监测项不计入综合状态
+		if m.Retired {
+			continue
+		}
+		status := -1
+		if m.Current != nil {
+			status = m.Current.Status
+		}
+		units = append(units, providerStatusUnit{
+			Provider:     m.Provider,
+			Slug:         m.ProviderSlug,
+			Weight:       weightOf(m.Provider, m.Service, m.Channel),
+			Status:       status,
+			Availability: timelineAvailability(m.Timeline),
+		})
+	}
+	for _, g := range groups {
+		if g.Retired {
+			continue
+		}
+		// 组级可用率取各层最小值（与前端组级展示一致）
+		availability := -1.0
+		for _, layer := range g.Layers {
+			a := timelineAvailability(layer.Timeline)
+			if a >= 0 && (availability < 0 || a < availability) {
+				availability = a
+			}
+		}
+		units = append(units, providerStatusUnit{
+			Provider:     g.Provider,
+			Slug:         g.ProviderSlug,
+			Weight:       weightOf(g.Provider, g.Service, g.Channel),
+			Status:       g.CurrentStatus,
+			Availability: availability,
+		})
+	}
+	return units
+}
+
+// timelineAvailability 计算时间轴的平均可用率（忽略无数据的 bucket），无数据时为 -1
+func timelineAvailability(timeline []storage.TimePoint) float64 {
+	var sum float64
+	var count int
+	for _, p := range timeline {
+		if p.Availability < 0 {
+			continue
+		}
+		sum += p.Availability
+		count++
+	}
+	if count == 0 {
+		return -1
+	}
+	return sum / float64(count)
+}
+
+// buildProviderStatuses 按 provider 汇总通道并计算综合状态
+func buildProviderStatuses(units []providerStatusUnit, cfg config.ProviderStatusConfig, degradedWeight float64) map[string]ProviderStatus {
+	bySlug := make(map[string][]providerStatusUnit)
+	order := make([]string, 0)
+	for _, u := range units {
+		if _, ok := bySlug[u.Slug]; !ok {
+			order = append(order, u.Slug)
+		}
+		bySlug[u.Slug] = append(bySlug[u.Slug], u)
+	}
+
+	result := make(map[string]ProviderStatus, len(order))
+	for _, slug := range order {
+		group := bySlug[slug]
+		mode, threshold := cfg.ModeFor(group[0].Provider)
+		result[slug] = computeProviderStatus(group, mode, threshold, degradedWeight)
+	}
+	return result
+}
+
+// computeProviderStatus 计算单个服务商的综合状态
+// 权重为 0 的通道不参与计算；当前状态无数据（-1）的通道不参与状态判定，但仍计入周期可用率
+func computeProviderStatus(units []providerStatusUnit, mode string, threshold, degradedWeight float64) ProviderStatus {
+	ps := ProviderStatus{
+		Provider:     units[0].Provider,
+		Mode:         mode,
+		Status:       -1,
+		Availability: -1,
+	}
+
+	var totalWeight, scoreWeight, upWeight float64
+	var availSum, availWeight float64
+	worst := -1
+	allGreen := true
+	for _, u := range units {
+		if u.Weight <= 0 {
+			continue
+		}
+		ps.Channels++
+		if u.Availability >= 0 {
+			availSum += u.Weight * u.Availability
+			availWeight += u.Weight
+		}
+		if u.Status < 0 {
+			continue
+		}
+		totalWeight += u.Weight
+		scoreWeight += u.Weight * availabilityWeight(u.Status, degradedWeight)
+		if u.Status == 1 || u.Status == 2 {
+			upWeight += u.Weight
+		}
+		if u.Status != 1 {
+			allGreen = false
+		}
+		worst = pickWorstStatus(worst, u.Status)
+	}
+
+	if availWeight > 0 {
+		ps.Availability = availSum / availWeight
+	}
+	if totalWeight == 0 {
+		return ps
+	}
+
+	score := scoreWeight / totalWeight
+	ps.Score = score
+	switch mode {
+	case config.ProviderStatusModeWorst:
+		ps.Status = worst
+	case config.ProviderStatusModeQuorum:
+		ps.Score = upWeight / totalWeight
+		switch {
+		case ps.Score < threshold:
+			ps.Status = 0
+		case allGreen:
+			ps.Status = 1
+		default:
+			ps.Status = 2
+		}
+	default:
+		switch {
+		case allGreen:
+			ps.Status = 1
+		case score >= threshold:
+			ps.Status = 2
+		default:
+			ps.Status = 0
+		}
+	}
+	return ps
+}
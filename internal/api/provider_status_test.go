@@ -0,0 +1,90 @@
+package api
+
+import (
+	"testing"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestComputeProviderStatus(t *testing.T) {
+	t.Parallel()
+
+	// VIP 通道权重 3，普通通道权重 1
+	units := []providerStatusUnit{
+		{Provider: "Relay", Slug: "relay", Weight: 3, Status: 1, Availability: 100},
+		{Provider: "Relay", Slug: "relay", Weight: 1, Status: 0, Availability: 60},
+		{Provider: "Relay", Slug: "relay", Weight: 0, Status: 0, Availability: 0}, // 不参与
+	}
+
+	weighted := computeProviderStatus(units, config.ProviderStatusModeWeighted, 0.5, 0.5)
+	if weighted.Status != 2 || weighted.Score != 0.75 || weighted.Channels != 2 {
+		t.Fatalf("unexpected weighted status: %+v", weighted)
+	}
+	if weighted.Availability != 90 {
+		t.Fatalf("expected weighted availability 90, got %v", weighted.Availability)
+	}
+
+	if worst := computeProviderStatus(units, config.ProviderStatusModeWorst, 0.5, 0.5); worst.Status != 0 {
+		t.Fatalf("worst mode should pick red, got %+v", worst)
+	}
+
+	if quorum := computeProviderStatus(units, config.ProviderStatusModeQuorum, 0.7, 0.5); quorum.Status != 2 || quorum.Score != 0.75 {
+		t.Fatalf("quorum reached with a red channel should be yellow: %+v", quorum)
+	}
+	if quorum := computeProviderStatus(units, config.ProviderStatusModeQuorum, 0.8, 0.5); quorum.Status != 0 {
+		t.Fatalf("quorum below threshold should be red: %+v", quorum)
+	}
+
+	// VIP 通道故障时加权得分低于阈值
+	vipDown := []providerStatusUnit{
+		{Provider: "Relay", Slug: "relay", Weight: 3, Status: 0, Availability: -1},
+		{Provider: "Relay", Slug: "relay", Weight: 1, Status: 1, Availability: -1},
+	}
+	if s := computeProviderStatus(vipDown, config.ProviderStatusModeWeighted, 0.5, 0.5); s.Status != 0 || s.Availability != -1 {
+		t.Fatalf("expected red with no availability data, got %+v", s)
+	}
+
+	noData := []providerStatusUnit{{Provider: "Relay", Slug: "relay", Weight: 1, Status: -1, Availability: -1}}
+	if s := computeProviderStatus(noData, config.ProviderStatusModeWeighted, 0.5, 0.5); s.Status != -1 {
+		t.Fatalf("expected no data, got %+v", s)
+	}
+}
+
+func TestCollectProviderStatusUnits(t *testing.T) {
+	t.Parallel()
+
+	vip := 3.0
+	weights := channelWeights([]config.ServiceConfig{
+		{Provider: "Relay", Service: "cc", Channel: "vip", Model: "sonnet", Weight: &vip},
+		{Provider: "Relay", Service: "cc", Channel: "vip", Model: "opus", Parent: "Relay/cc/vip"},
+		{Provider: "Relay", Service: "cc", Channel: "std"},
+	})
+
+	data := []MonitorResult{
+		{Provider: "Relay", ProviderSlug: "relay", Service: "cc", Channel: "std", Current: &CurrentStatus{Status: 1},
+			Timeline: []storage.TimePoint{{Availability: 100}, {Availability: -1}, {Availability: 80}}},
+	}
+	groups := []MonitorGroup{
+		{Provider: "Relay", ProviderSlug: "relay", Service: "cc", Channel: "vip", CurrentStatus: 2, Layers: []MonitorLayer{
+			{Timeline: []storage.TimePoint{{Availability: 100}}},
+			{Timeline: []storage.TimePoint{{Availability: 50}}},
+		}},
+	}
+
+	units := collectProviderStatusUnits(data, groups, weights)
+	if len(units) != 2 {
+		t.Fatalf("expected 2 units, got %d", len(units))
+	}
+	if units[0].Weight != 1 || units[0].Availability != 90 {
+		t.Fatalf("unexpected plain unit: %+v", units[0])
+	}
+	if units[1].Weight != 3 || units[1].Status != 2 || units[1].Availability != 50 {
+		t.Fatalf("unexpected group unit: %+v", units[1])
+	}
+
+	statuses := buildProviderStatuses(units, config.ProviderStatusConfig{}, 0.5)
+	if s, ok := statuses["relay"]; !ok || s.Mode != config.ProviderStatusModeWeighted || s.Status != 2 {
+		t.Fatalf("unexpected provider status: %+v", statuses)
+	}
+}
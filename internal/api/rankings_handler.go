@@ -0,0 +1,344 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// RankingsResponse 服务商排行榜响应（GET /api/rankings）
+type RankingsResponse struct {
+	Meta     RankingsMeta   `json:"meta"`
+	Rankings []RankingEntry `json:"rankings"`
+}
+
+// RankingsMeta 排行榜元数据
+type RankingsMeta struct {
+	Period  string         `json:"period"`
+	Since   string         `json:"since"`
+	Until   string         `json:"until"`
+	Count   int            `json:"count"`
+	Formula RankingFormula `json:"formula"`
+}
+
+// RankingFormula 当前生效的评分参数（便于前端展示评分说明）
+type RankingFormula struct {
+	UptimeWeight      float64 `json:"uptime_weight"`
+	LatencyWeight     float64 `json:"latency_weight"`
+	PriceWeight       float64 `json:"price_weight"`
+	FlapPenalty       float64 `json:"flap_penalty"`
+	LatencyPercentile int     `json:"latency_percentile"`
+	LatencyGoodMs     int     `json:"latency_good_ms"`
+	LatencyBadMs      int     `json:"latency_bad_ms"`
+	MinProbes         int     `json:"min_probes"`
+}
+
+// RankingEntry 排行榜条目（按 provider + service 聚合，包含该服务下全部通道与模型）
+type RankingEntry struct {
+	Rank         int      `json:"rank"`
+	Provider     string   `json:"provider"`
+	ProviderName string   `json:"provider_name,omitempty"`
+	ProviderSlug string   `json:"provider_slug"`
+	Service      string   `json:"service"`
+	ServiceName  string   `json:"service_name,omitempty"`
+	Score        float64  `json:"score"`         // 综合评分（0-100）
+	Uptime       float64  `json:"uptime"`        // 可用率百分比（黄色按 degraded_weight 计）
+	Latency      int      `json:"latency"`       // 分位延迟（ms），无成功探测时为 -1
+	LatencyScore float64  `json:"latency_score"` // 延迟分（0-100）
+	FlapRate     float64  `json:"flap_rate"`     // 抖动率百分比
+	Price        *float64 `json:"price,omitempty"`
+	PriceScore   float64  `json:"price_score"` // 价格分（0-100）
+	Probes       int      `json:"probes"`
+	Monitors     int      `json:"monitors"`
+}
+
+// rankingInput 单个 provider/service 的排名原始数据
+type rankingInput struct {
+	entry   RankingEntry
+	history [][]*storage.ProbeRecord // 每个监测项一组历史记录（用于逐项计算抖动）
+}
+
+// GetRankings 获取服务商排行榜
+// GET /api/rankings?period=7d&service=cc&board=hot
+// period 支持 24h/7d/30d（默认 7d），评分公式见 config.RankingsConfig
+func (h *Handler) GetRankings(c *gin.Context) {
+	period := c.DefaultQuery("period", "7d")
+	if period == "1d" {
+		period = "24h"
+	}
+	if period != "24h" && period != "7d" && period != "30d" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("无效的时间范围: %s (支持: 24h/7d/30d)", period),
+		})
+		return
+	}
+
+	qService := c.DefaultQuery("service", "all")
+	qBoard := strings.ToLower(strings.TrimSpace(c.DefaultQuery("board", "hot")))
+	if qBoard == "" {
+		qBoard = "hot"
+	}
+	if qBoard != "hot" && qBoard != "secondary" && qBoard != "cold" && qBoard != "all" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("无效的 board 参数: %s (支持: hot/secondary/cold/all)", qBoard),
+		})
+		return
+	}
+
+	cacheKey := fmt.Sprintf("rankings|p=%s|svc=%s|board=%s", period, qService, qBoard)
+
+	h.cfgMu.RLock()
+	cacheTTL := h.config.CacheTTL.TTLForPeriod(period)
+	h.cfgMu.RUnlock()
+
+	reqCtx := context.WithoutCancel(c.Request.Context())
+	entry, err := h.cache.loadEntry(reqCtx, cacheKey, cacheTTL, func() ([]byte, error) {
+		ctx, cancel := context.WithTimeout(reqCtx, 30*time.Second)
+		defer cancel()
+		resp, err := h.buildRankings(ctx, period, qService, qBoard)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(resp)
+	})
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("GetRankings 失败", "cache_key", cacheKey, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("查询失败: %v", err),
+		})
+		return
+	}
+
+	ttlSeconds := int(cacheTTL.Seconds())
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d, s-maxage=%d", ttlSeconds, ttlSeconds))
+	writeCached(c, entry, "application/json; charset=utf-8")
+}
+
+// buildRankings 查询窗口内历史记录并计算排行榜
+func (h *Handler) buildRankings(ctx context.Context, period, qService, qBoard string) (*RankingsResponse, error) {
+	h.cfgMu.RLock()
+	monitors := h.config.Monitors
+	degradedWeight := h.config.DegradedWeight
+	boardsEnabled := h.config.Boards.Enabled
+	batchQueryMaxKeys := h.config.BatchQueryMaxKeys
+	rankCfg := h.config.Rankings.Clone()
+	h.cfgMu.RUnlock()
+
+	startTime, endTime := h.parseTimeRange(period, "")
+	filtered := h.filterMonitorsForGroups(monitors, "all", qService, qBoard, boardsEnabled, false)
+
+	keys := make([]storage.MonitorKey, 0, len(filtered))
+	for _, task := range filtered {
+		keys = append(keys, storage.MonitorKey{Provider: task.Provider, Service: task.Service, Channel: task.Channel, Model: task.Model})
+	}
+
+	store := h.storage.WithContext(ctx)
+	historyMap := make(map[storage.MonitorKey][]*storage.ProbeRecord, len(keys))
+	if batchQueryMaxKeys <= 0 {
+		batchQueryMaxKeys = len(keys)
+	}
+	for start := 0; start < len(keys); start += batchQueryMaxKeys {
+		end := min(start+batchQueryMaxKeys, len(keys))
+		history, err := store.GetHistoryBatch(keys[start:end], startTime)
+		if err != nil {
+			return nil, fmt.Errorf("批量查询历史记录失败: %w", err)
+		}
+		for k, v := range history {
+			historyMap[k] = v
+		}
+	}
+
+	// 按 provider + service 聚合（保持配置顺序，便于同分时稳定排序）
+	inputs := make([]*rankingInput, 0)
+	inputIndex := make(map[string]*rankingInput)
+	for _, task := range filtered {
+		ps := task.Provider + "/" + task.Service
+		in, ok := inputIndex[ps]
+		if !ok {
+			slug := task.ProviderSlug
+			if slug == "" {
+				slug = strings.ToLower(strings.TrimSpace(task.Provider))
+			}
+			in = &rankingInput{entry: RankingEntry{
+				Provider:     task.Provider,
+				ProviderName: task.ProviderName,
+				ProviderSlug: slug,
+				Service:      task.Service,
+				ServiceName:  task.ServiceName,
+			}}
+			inputIndex[ps] = in
+			inputs = append(inputs, in)
+		}
+
+		in.entry.Monitors++
+		if price := monitorReferencePrice(task); price != nil && (in.entry.Price == nil || *price < *in.entry.Price) {
+			in.entry.Price = price
+		}
+
+		key := storage.MonitorKey{Provider: task.Provider, Service: task.Service, Channel: task.Channel, Model: task.Model}
+		if history := historyMap[key]; len(history) > 0 {
+			in.history = append(in.history, history)
+		}
+	}
+
+	rankings := computeRankings(inputs, rankCfg, degradedWeight)
+
+	return &RankingsResponse{
+		Meta: RankingsMeta{
+			Period: period,
+			Since:  startTime.UTC().Format(time.RFC3339),
+			Until:  endTime.UTC().Format(time.RFC3339),
+			Count:  len(rankings),
+			Formula: RankingFormula{
+				UptimeWeight:      *rankCfg.UptimeWeight,
+				LatencyWeight:     *rankCfg.LatencyWeight,
+				PriceWeight:       *rankCfg.PriceWeight,
+				FlapPenalty:       *rankCfg.FlapPenalty,
+				LatencyPercentile: rankCfg.LatencyPercentile,
+				LatencyGoodMs:     int(rankCfg.LatencyGoodDuration.Milliseconds()),
+				LatencyBadMs:      int(rankCfg.LatencyBadDuration.Milliseconds()),
+				MinProbes:         rankCfg.MinProbes,
+			},
+		},
+		Rankings: rankings,
+	}, nil
+}
+
+// monitorReferencePrice 返回监测项的参考倍率（price_min 与 price_max 同时配置时取中值）
+func monitorReferencePrice(task config.ServiceConfig) *float64 {
+	switch {
+	case task.PriceMin != nil && task.PriceMax != nil:
+		v := (*task.PriceMin + *task.PriceMax) / 2
+		return &v
+	case task.PriceMin != nil:
+		v := *task.PriceMin
+		return &v
+	case task.PriceMax != nil:
+		v := *task.PriceMax
+		return &v
+	default:
+		return nil
+	}
+}
+
+// computeRankings 计算各条目评分并按分数降序排列（样本不足 min_probes 的条目被剔除）
+func computeRankings(inputs []*rankingInput, cfg config.RankingsConfig, degradedWeight float64) []RankingEntry {
+	entries := make([]RankingEntry, 0, len(inputs))
+	for _, in := range inputs {
+		e := in.entry
+
+		var weight float64
+		var flips, pairs int
+		latencies := make([]int, 0)
+		for _, history := range in.history {
+			for i, r := range history {
+				e.Probes++
+				weight += availabilityWeight(r.Status, degradedWeight)
+				if r.Status != 0 {
+					latencies = append(latencies, r.Latency)
+				}
+				if i > 0 {
+					pairs++
+					if (history[i-1].Status == 0) != (r.Status == 0) {
+						flips++
+					}
+				}
+			}
+		}
+		if e.Probes == 0 || e.Probes < cfg.MinProbes {
+			continue
+		}
+
+		e.Uptime = weight / float64(e.Probes) * 100
+		if pairs > 0 {
+			e.FlapRate = float64(flips) / float64(pairs) * 100
+		}
+
+		e.Latency = -1
+		if len(latencies) > 0 {
+			e.Latency = latencyPercentile(latencies, cfg.LatencyPercentile)
+			e.LatencyScore = latencyScore(e.Latency, cfg.LatencyGoodDuration, cfg.LatencyBadDuration)
+		}
+
+		entries = append(entries, e)
+	}
+
+	// 价格分：在参与排名的条目间归一化
+	minPrice, maxPrice := math.Inf(1), math.Inf(-1)
+	for _, e := range entries {
+		if e.Price != nil {
+			minPrice = math.Min(minPrice, *e.Price)
+			maxPrice = math.Max(maxPrice, *e.Price)
+		}
+	}
+
+	totalWeight := *cfg.UptimeWeight + *cfg.LatencyWeight + *cfg.PriceWeight
+	for i := range entries {
+		e := &entries[i]
+		switch {
+		case e.Price == nil:
+			e.PriceScore = 50
+		case maxPrice > minPrice:
+			e.PriceScore = (maxPrice - *e.Price) / (maxPrice - minPrice) * 100
+		default:
+			e.PriceScore = 100
+		}
+
+		score := (*cfg.UptimeWeight*e.Uptime + *cfg.LatencyWeight*e.LatencyScore + *cfg.PriceWeight*e.PriceScore) / totalWeight
+		score -= *cfg.FlapPenalty * e.FlapRate
+		e.Score = roundTo(math.Max(0, math.Min(100, score)), 2)
+		e.Uptime = roundTo(e.Uptime, 2)
+		e.LatencyScore = roundTo(e.LatencyScore, 2)
+		e.FlapRate = roundTo(e.FlapRate, 2)
+		e.PriceScore = roundTo(e.PriceScore, 2)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		return entries[i].Uptime > entries[j].Uptime
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	return entries
+}
+
+// latencyPercentile 计算延迟分位数（nearest-rank 法，会对入参排序）
+func latencyPercentile(latencies []int, percentile int) int {
+	sort.Ints(latencies)
+	idx := int(math.Ceil(float64(percentile)/100*float64(len(latencies)))) - 1
+	idx = max(0, min(idx, len(latencies)-1))
+	return latencies[idx]
+}
+
+// latencyScore 将延迟映射为 0-100 分：≤good 记 100，≥bad 记 0，中间线性插值
+func latencyScore(latencyMs int, good, bad time.Duration) float64 {
+	latency := time.Duration(latencyMs) * time.Millisecond
+	switch {
+	case latency <= good:
+		return 100
+	case latency >= bad:
+		return 0
+	default:
+		return float64(bad-latency) / float64(bad-good) * 100
+	}
+}
+
+// roundTo 四舍五入到指定小数位
+func roundTo(v float64, digits int) float64 {
+	p := math.Pow(10, float64(digits))
+	return math.Round(v*p) / p
+}
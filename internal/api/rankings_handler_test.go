@@ -0,0 +1,86 @@
+package api
+
+import (
+	"testing"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func rankingHistory(statuses []int, latency int) []*storage.ProbeRecord {
+	records := make([]*storage.ProbeRecord, len(statuses))
+	for i, s := range statuses {
+		records[i] = &storage.ProbeRecord{Status: s, Latency: latency}
+	}
+	return records
+}
+
+func TestComputeRankings(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.RankingsConfig{MinProbes: 4}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("Normalize() failed: %v", err)
+	}
+
+	cheap, pricey := 0.1, 0.5
+	inputs := []*rankingInput{
+		{
+			// 稳定但价格高
+			entry:   RankingEntry{Provider: "stable", Service: "cc", Price: &pricey},
+			history: [][]*storage.ProbeRecord{rankingHistory([]int{1, 1, 1, 1}, 500)},
+		},
+		{
+			// 便宜但频繁抖动
+			entry:   RankingEntry{Provider: "flappy", Service: "cc", Price: &cheap},
+			history: [][]*storage.ProbeRecord{rankingHistory([]int{1, 0, 1, 0}, 500)},
+		},
+		{
+			// 样本不足，不参与排名
+			entry:   RankingEntry{Provider: "sparse", Service: "cc"},
+			history: [][]*storage.ProbeRecord{rankingHistory([]int{1, 1}, 100)},
+		},
+	}
+
+	got := computeRankings(inputs, cfg, 0.7)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(got))
+	}
+	if got[0].Provider != "stable" || got[0].Rank != 1 || got[1].Rank != 2 {
+		t.Fatalf("unexpected order: %+v", got)
+	}
+
+	// stable: (0.6*100 + 0.3*100 + 0.1*0) / 1.0 = 90
+	if got[0].Score != 90 || got[0].FlapRate != 0 || got[0].PriceScore != 0 {
+		t.Fatalf("unexpected stable entry: %+v", got[0])
+	}
+
+	// flappy: (0.6*50 + 0.3*100 + 0.1*100) / 1.0 - 0.5*100 = 20
+	flappy := got[1]
+	if flappy.Uptime != 50 || flappy.FlapRate != 100 || flappy.PriceScore != 100 || flappy.Score != 20 {
+		t.Fatalf("unexpected flappy entry: %+v", flappy)
+	}
+}
+
+func TestLatencyScoreAndPercentile(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.RankingsConfig{}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("Normalize() failed: %v", err)
+	}
+
+	if got := latencyPercentile([]int{500, 100, 400, 200, 300}, 95); got != 500 {
+		t.Fatalf("P95 = %d, want 500", got)
+	}
+	if got := latencyPercentile([]int{500, 100, 400, 200, 300}, 50); got != 300 {
+		t.Fatalf("P50 = %d, want 300", got)
+	}
+
+	cases := map[int]float64{800: 100, 1000: 100, 5500: 50, 10000: 0, 20000: 0}
+	for latency, want := range cases {
+		if got := latencyScore(latency, cfg.LatencyGoodDuration, cfg.LatencyBadDuration); got != want {
+			t.Fatalf("latencyScore(%d) = %v, want %v", latency, got, want)
+		}
+	}
+}
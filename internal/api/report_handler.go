@@ -0,0 +1,436 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+const (
+	// reportDays 服务商报告统计天数（含今天，与热力图一致）
+	reportDays = 90
+
+	// reportIncidentScan 查询事件时的扫描条数（按时间倒序，超出统计区间即停止）
+	reportIncidentScan = 2000
+
+	// reportIncidentLimit 报告中列出的故障条数上限（汇总数量不受限制）
+	reportIncidentLimit = 200
+)
+
+// reportEventTypes 报告统计的事件类型（不可用与性能下降的开始/结束）
+var reportEventTypes = []storage.EventType{
+	storage.EventTypeDown,
+	storage.EventTypeUp,
+	storage.EventTypeDegradedStart,
+	storage.EventTypeDegradedEnd,
+}
+
+// 故障类型
+const (
+	ReportIncidentDown     = "down"     // DOWN → UP
+	ReportIncidentDegraded = "degraded" // DEGRADED_START → DEGRADED_END
+)
+
+// ProviderReport 服务商尽调报告（GET /api/providers/:slug/report）
+// 基于每日汇总（probe_daily）统计 90 天可用率与延迟趋势，不受 retention 清理影响
+type ProviderReport struct {
+	Provider     string                 `json:"provider"`
+	ProviderName string                 `json:"provider_name,omitempty"`
+	ProviderSlug string                 `json:"provider_slug"`
+	ProviderURL  string                 `json:"provider_url"`
+	Category     string                 `json:"category"`
+	Sponsor      string                 `json:"sponsor"`
+	SponsorURL   string                 `json:"sponsor_url"`
+	SponsorLevel config.SponsorLevel    `json:"sponsor_level,omitempty"`
+	Risks        []config.RiskBadge     `json:"risks,omitempty"`
+	Badges       []config.ResolvedBadge `json:"badges,omitempty"`
+	PriceMin     *float64               `json:"price_min,omitempty"`
+	PriceMax     *float64               `json:"price_max,omitempty"`
+	ListedDays   *int                   `json:"listed_days,omitempty"`
+
+	GeneratedAt int64  `json:"generated_at"`
+	Days        int    `json:"days"`
+	Since       string `json:"since"` // 起始日期（UTC，含）
+	Until       string `json:"until"` // 截止日期（UTC，含今天）
+
+	Uptime        float64 `json:"uptime"`         // 统计区间可用率百分比（0-100），无数据时为 -1
+	Latency       int     `json:"latency"`        // 统计区间平均延迟（毫秒，仅可用探测），无数据时为 -1
+	Probes        int     `json:"probes"`         // 统计区间探测总数
+	IncidentCount int     `json:"incident_count"` // 统计区间故障总数（含性能下降）
+	Downtime      int64   `json:"downtime"`       // 不可用故障累计时长（秒，进行中的故障计至生成时间）
+
+	Daily     []ReportDay      `json:"daily"`     // 固定 90 天，按日期升序
+	Channels  []ReportChannel  `json:"channels"`  // 按配置顺序
+	Incidents []ReportIncident `json:"incidents"` // 最新在前，最多 200 条
+}
+
+// ReportDay 服务商单日汇总（各监测项按探测次数合并）
+type ReportDay struct {
+	Date    string  `json:"date"`
+	Uptime  float64 `json:"uptime"`  // 无数据时为 -1
+	Latency int     `json:"latency"` // 平均延迟（毫秒），无数据时为 -1
+	Probes  int     `json:"probes"`
+}
+
+// ReportChannel 单个通道的区间汇总
+type ReportChannel struct {
+	Service     string  `json:"service"`
+	ServiceName string  `json:"service_name,omitempty"`
+	Channel     string  `json:"channel"`
+	ChannelName string  `json:"channel_name,omitempty"`
+	Board       string  `json:"board"`
+	Uptime      float64 `json:"uptime"`
+	Latency     int     `json:"latency"`
+	Probes      int     `json:"probes"`
+	Incidents   int     `json:"incidents"`
+}
+
+// ReportIncident 单次故障（由开始与恢复事件配对得到）
+type ReportIncident struct {
+	Service   string `json:"service"`
+	Channel   string `json:"channel,omitempty"`
+	Model     string `json:"model,omitempty"`
+	Type      string `json:"type"` // down / degraded
+	StartedAt int64  `json:"started_at"`
+	EndedAt   int64  `json:"ended_at,omitempty"` // 未恢复时为空
+	Duration  int64  `json:"duration"`           // 持续时长（秒），未恢复时计至生成时间
+	Ongoing   bool   `json:"ongoing,omitempty"`
+}
+
+// rollupAcc 每日汇总累加器
+type rollupAcc struct {
+	total        int
+	weight       float64
+	latencySum   int64
+	latencyCount int
+}
+
+func (a *rollupAcc) add(r storage.DailyRollupRow, degradedWeight float64) {
+	a.total += r.Total
+	a.weight += float64(r.Green) + float64(r.Yellow)*degradedWeight
+	a.latencySum += r.LatencySum
+	a.latencyCount += r.LatencyCount
+}
+
+// uptime 返回可用率百分比（保留 2 位小数），无数据时为 -1
+func (a *rollupAcc) uptime() float64 {
+	if a.total == 0 {
+		return -1
+	}
+	return roundTo(a.weight/float64(a.total)*100, 2)
+}
+
+// latency 返回平均延迟（毫秒），无数据时为 -1
+func (a *rollupAcc) latency() int {
+	if a.latencyCount == 0 {
+		return -1
+	}
+	return int(a.latencySum / int64(a.latencyCount))
+}
+
+// GetProviderReport 导出服务商尽调报告
+// GET /api/providers/:slug/report?format=pdf|json（默认 pdf）
+// 包含 90 天可用率与延迟趋势、故障列表、风险徽标与赞助信息，以附件形式下载
+func (h *Handler) GetProviderReport(c *gin.Context) {
+	slug := strings.ToLower(strings.TrimSpace(c.Param("slug")))
+	format := strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", "pdf")))
+	if format != "pdf" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("无效的 format 参数: %s (支持: pdf/json)", format),
+		})
+		return
+	}
+
+	if _, ok := h.storage.WithContext(c.Request.Context()).(storage.DailyRollupStorage); !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持每日汇总",
+		})
+		return
+	}
+
+	h.cfgMu.RLock()
+	monitors := h.providerMonitors(slug, false)
+	cacheTTL := h.config.CacheTTL.TTLForPeriod("30d")
+	if h.cacheBypassed(slug) {
+		cacheTTL = 0
+	}
+	h.cfgMu.RUnlock()
+
+	if len(monitors) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("服务商不存在: %s", slug),
+		})
+		return
+	}
+
+	now := time.Now()
+	cacheKey := fmt.Sprintf("report|slug=%s|format=%s", slug, format)
+	reqCtx := context.WithoutCancel(c.Request.Context())
+	data, err := h.cache.loadWithTTL(reqCtx, cacheKey, cacheTTL, func() ([]byte, error) {
+		ctx, cancel := context.WithTimeout(reqCtx, 30*time.Second)
+		defer cancel()
+		report, err := h.buildProviderReport(ctx, monitors, now)
+		if err != nil {
+			return nil, err
+		}
+		if format == "json" {
+			return json.MarshalIndent(report, "", "  ")
+		}
+		return renderProviderReportPDF(report)
+	})
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("GetProviderReport 失败", "cache_key", cacheKey, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("生成报告失败: %v", err),
+		})
+		return
+	}
+
+	contentType := "application/pdf"
+	if format == "json" {
+		contentType = "application/json; charset=utf-8"
+	}
+	filename := fmt.Sprintf("%s-report-%s.%s", slug, now.UTC().Format("2006-01-02"), format)
+	setCacheControl(c, cacheTTL)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// buildProviderReport 查询每日汇总与故障事件并构建报告
+func (h *Handler) buildProviderReport(ctx context.Context, monitors []config.ServiceConfig, now time.Time) (*ProviderReport, error) {
+	h.cfgMu.RLock()
+	degradedWeight := h.config.DegradedWeight
+	enableBadges := h.config.EnableBadges
+	batchQueryMaxKeys := h.config.BatchQueryMaxKeys
+	h.cfgMu.RUnlock()
+
+	keys := make([]storage.MonitorKey, 0, len(monitors))
+	for _, task := range monitors {
+		keys = append(keys, storage.MonitorKey{Provider: task.Provider, Service: task.Service, Channel: task.Channel, Model: task.Model})
+	}
+
+	store := h.storage.WithContext(ctx)
+	rollupStore, ok := store.(storage.DailyRollupStorage)
+	if !ok {
+		return nil, fmt.Errorf("当前存储后端不支持每日汇总")
+	}
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	sinceDay := today.AddDate(0, 0, -(reportDays - 1)).Format("2006-01-02")
+	untilDay := today.Format("2006-01-02")
+
+	rollupMap := make(map[storage.MonitorKey][]storage.DailyRollupRow, len(keys))
+	if batchQueryMaxKeys <= 0 {
+		batchQueryMaxKeys = len(keys)
+	}
+	for start := 0; start < len(keys); start += batchQueryMaxKeys {
+		end := min(start+batchQueryMaxKeys, len(keys))
+		rollups, err := rollupStore.GetDailyRollupBatch(keys[start:end], sinceDay, untilDay)
+		if err != nil {
+			return nil, fmt.Errorf("批量查询每日汇总失败: %w", err)
+		}
+		for k, v := range rollups {
+			rollupMap[k] = v
+		}
+	}
+
+	events, err := store.GetRecentStatusEvents(reportIncidentScan, &storage.EventFilters{
+		Provider: monitors[0].Provider,
+		Types:    reportEventTypes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("查询故障事件失败: %w", err)
+	}
+
+	return buildProviderReportData(monitors, rollupMap, events, degradedWeight, enableBadges, now), nil
+}
+
+// buildProviderReportData 汇总每日数据、通道统计与故障列表
+// 元数据（赞助、徽标、倍率等）复用服务商详情的聚合逻辑
+func buildProviderReportData(
+	monitors []config.ServiceConfig,
+	rollupMap map[storage.MonitorKey][]storage.DailyRollupRow,
+	events []*storage.StatusEvent,
+	degradedWeight float64,
+	enableBadges bool,
+	now time.Time,
+) *ProviderReport {
+	detail := buildProviderDetailResponse(monitors, nil, nil, nil, degradedWeight, enableBadges, now)
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(reportDays - 1))
+
+	report := &ProviderReport{
+		Provider:     detail.Provider,
+		ProviderName: detail.ProviderName,
+		ProviderSlug: detail.ProviderSlug,
+		ProviderURL:  detail.ProviderURL,
+		Category:     detail.Category,
+		Sponsor:      detail.Sponsor,
+		SponsorURL:   detail.SponsorURL,
+		SponsorLevel: detail.SponsorLevel,
+		Risks:        detail.Risks,
+		Badges:       detail.Badges,
+		PriceMin:     detail.PriceMin,
+		PriceMax:     detail.PriceMax,
+		ListedDays:   detail.ListedDays,
+		GeneratedAt:  now.Unix(),
+		Days:         reportDays,
+		Since:        since.Format("2006-01-02"),
+		Until:        today.Format("2006-01-02"),
+		Daily:        make([]ReportDay, reportDays),
+		Channels:     make([]ReportChannel, 0),
+		Incidents:    make([]ReportIncident, 0),
+	}
+
+	// 通道顺序与显示名沿用详情树
+	channelIndex := make(map[string]int)
+	for _, svc := range detail.Services {
+		for _, ch := range svc.Channels {
+			channelIndex[svc.Service+"/"+ch.Channel] = len(report.Channels)
+			report.Channels = append(report.Channels, ReportChannel{
+				Service:     svc.Service,
+				ServiceName: svc.ServiceName,
+				Channel:     ch.Channel,
+				ChannelName: ch.ChannelName,
+				Board:       ch.Board,
+			})
+		}
+	}
+
+	dayAcc := make(map[string]*rollupAcc, reportDays)
+	channelAcc := make([]rollupAcc, len(report.Channels))
+	var total rollupAcc
+	for _, task := range monitors {
+		key := storage.MonitorKey{Provider: task.Provider, Service: task.Service, Channel: task.Channel, Model: task.Model}
+		cIdx := channelIndex[task.Service+"/"+task.Channel]
+		for _, r := range rollupMap[key] {
+			acc, ok := dayAcc[r.Day]
+			if !ok {
+				acc = &rollupAcc{}
+				dayAcc[r.Day] = acc
+			}
+			acc.add(r, degradedWeight)
+			channelAcc[cIdx].add(r, degradedWeight)
+			total.add(r, degradedWeight)
+		}
+	}
+
+	for i := range report.Daily {
+		date := since.AddDate(0, 0, i).Format("2006-01-02")
+		day := ReportDay{Date: date, Uptime: -1, Latency: -1}
+		if acc, ok := dayAcc[date]; ok {
+			day.Uptime = acc.uptime()
+			day.Latency = acc.latency()
+			day.Probes = acc.total
+		}
+		report.Daily[i] = day
+	}
+	for i := range report.Channels {
+		report.Channels[i].Uptime = channelAcc[i].uptime()
+		report.Channels[i].Latency = channelAcc[i].latency()
+		report.Channels[i].Probes = channelAcc[i].total
+	}
+	report.Uptime = total.uptime()
+	report.Latency = total.latency()
+	report.Probes = total.total
+
+	incidents := pairReportIncidents(events, channelIndex, since.Unix(), now.Unix())
+	report.IncidentCount = len(incidents)
+	for _, inc := range incidents {
+		report.Channels[channelIndex[inc.Service+"/"+inc.Channel]].Incidents++
+		if inc.Type == ReportIncidentDown {
+			report.Downtime += inc.Duration
+		}
+	}
+	if len(incidents) > reportIncidentLimit {
+		incidents = incidents[:reportIncidentLimit]
+	}
+	report.Incidents = append(report.Incidents, incidents...)
+
+	return report
+}
+
+// pairReportIncidents 将事件按监测项配对为故障（最新在前）
+// 仅统计可见通道、开始时间不早于 sinceUnix 的故障；开始事件早于统计区间的恢复事件被忽略
+func pairReportIncidents(events []*storage.StatusEvent, channelIndex map[string]int, sinceUnix, nowUnix int64) []ReportIncident {
+	// 事件为时间倒序，按时间正序处理以便配对
+	ordered := make([]*storage.StatusEvent, 0, len(events))
+	for _, e := range events {
+		if e.ObservedAt < sinceUnix {
+			continue
+		}
+		if _, ok := channelIndex[e.Service+"/"+e.Channel]; !ok {
+			continue
+		}
+		ordered = append(ordered, e)
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].ObservedAt != ordered[j].ObservedAt {
+			return ordered[i].ObservedAt < ordered[j].ObservedAt
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	incidents := make([]ReportIncident, 0)
+	open := make(map[string]int) // 监测项 + 故障类型 → incidents 下标
+	for _, e := range ordered {
+		var kind string
+		var starts bool
+		switch e.EventType {
+		case storage.EventTypeDown:
+			kind, starts = ReportIncidentDown, true
+		case storage.EventTypeUp:
+			kind = ReportIncidentDown
+		case storage.EventTypeDegradedStart:
+			kind, starts = ReportIncidentDegraded, true
+		case storage.EventTypeDegradedEnd:
+			kind = ReportIncidentDegraded
+		default:
+			continue
+		}
+
+		key := e.Service + "/" + e.Channel + "/" + e.Model + "/" + kind
+		idx, isOpen := open[key]
+		if starts {
+			if isOpen {
+				continue // 重复的开始事件以首次为准
+			}
+			open[key] = len(incidents)
+			incidents = append(incidents, ReportIncident{
+				Service:   e.Service,
+				Channel:   e.Channel,
+				Model:     e.Model,
+				Type:      kind,
+				StartedAt: e.ObservedAt,
+			})
+			continue
+		}
+		if !isOpen {
+			continue
+		}
+		incidents[idx].EndedAt = e.ObservedAt
+		incidents[idx].Duration = e.ObservedAt - incidents[idx].StartedAt
+		delete(open, key)
+	}
+	for _, idx := range open {
+		incidents[idx].Ongoing = true
+		incidents[idx].Duration = max(nowUnix-incidents[idx].StartedAt, 0)
+	}
+
+	// 最新在前
+	for i, j := 0, len(incidents)-1; i < j; i, j = i+1, j-1 {
+		incidents[i], incidents[j] = incidents[j], incidents[i]
+	}
+	return incidents
+}
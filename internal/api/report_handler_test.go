@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestBuildProviderReportData(t *testing.T) {
+	t.Parallel()
+
+	monitors := []config.ServiceConfig{
+		{Provider: "Relay", ProviderSlug: "relay", ProviderName: "中转站", Service: "cc", Channel: "vip", Model: "sonnet", Board: "hot", Sponsor: "Relay Inc", SponsorLevel: config.SponsorLevelBasic, Risks: []config.RiskBadge{{Label: "跑路风险"}}},
+		{Provider: "Relay", ProviderSlug: "relay", Service: "cc", Channel: "vip", Model: "opus", Parent: "relay/cc/vip", Board: "hot"},
+		{Provider: "Relay", ProviderSlug: "relay", Service: "cx", Board: "cold"},
+	}
+	keyOf := func(m config.ServiceConfig) storage.MonitorKey {
+		return storage.MonitorKey{Provider: m.Provider, Service: m.Service, Channel: m.Channel, Model: m.Model}
+	}
+	rollups := map[storage.MonitorKey][]storage.DailyRollupRow{
+		keyOf(monitors[0]): {
+			{Day: "2024-01-02", Total: 10, Green: 10, LatencySum: 1000, LatencyCount: 10},
+			{Day: "2024-03-31", Total: 4, Green: 2, Yellow: 2, LatencySum: 800, LatencyCount: 4},
+		},
+		keyOf(monitors[1]): {
+			{Day: "2024-03-31", Total: 4, Green: 2, Red: 2, LatencySum: 400, LatencyCount: 2},
+		},
+		keyOf(monitors[2]): {
+			{Day: "2023-12-01", Total: 10, Red: 10}, // 超出统计区间（存储层已按日期过滤，此处仅校验不会越界）
+		},
+	}
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	events := []*storage.StatusEvent{
+		{ID: 5, Service: "cc", Channel: "vip", Model: "opus", EventType: storage.EventTypeDown, ObservedAt: now.Unix() - 600},
+		{ID: 4, Service: "cc", Channel: "vip", Model: "sonnet", EventType: storage.EventTypeDegradedEnd, ObservedAt: now.Unix() - 3600},
+		{ID: 3, Service: "cc", Channel: "hidden", EventType: storage.EventTypeDown, ObservedAt: now.Unix() - 4000},
+		{ID: 2, Service: "cc", Channel: "vip", Model: "sonnet", EventType: storage.EventTypeDegradedStart, ObservedAt: now.Unix() - 7200},
+		{ID: 1, Service: "cx", EventType: storage.EventTypeUp, ObservedAt: now.Unix() - 8000}, // 开始事件不在扫描范围内
+	}
+
+	report := buildProviderReportData(monitors, rollups, events, 0.5, true, now)
+
+	if report.ProviderSlug != "relay" || report.Sponsor != "Relay Inc" || report.SponsorLevel != config.SponsorLevelBasic {
+		t.Fatalf("unexpected metadata: %+v", report)
+	}
+	if len(report.Risks) != 1 || report.Risks[0].Label != "跑路风险" {
+		t.Fatalf("unexpected risks: %+v", report.Risks)
+	}
+	if report.Since != "2024-01-02" || report.Until != "2024-03-31" || len(report.Daily) != reportDays {
+		t.Fatalf("unexpected range: %s ~ %s (%d days)", report.Since, report.Until, len(report.Daily))
+	}
+
+	first, last := report.Daily[0], report.Daily[reportDays-1]
+	if first.Uptime != 100 || first.Latency != 100 || first.Probes != 10 {
+		t.Fatalf("unexpected first day: %+v", first)
+	}
+	// 8 次探测：绿 4 + 黄 2×0.5 = 5 → 62.5%；延迟 (800+400)/6 = 200
+	if last.Uptime != 62.5 || last.Latency != 200 || last.Probes != 8 {
+		t.Fatalf("unexpected last day: %+v", last)
+	}
+	if report.Daily[1].Uptime != -1 || report.Daily[1].Latency != -1 {
+		t.Fatalf("expected empty day to be -1, got %+v", report.Daily[1])
+	}
+
+	if len(report.Channels) != 2 || report.Channels[0].Channel != "vip" || report.Channels[1].Service != "cx" {
+		t.Fatalf("unexpected channels: %+v", report.Channels)
+	}
+	if report.Channels[0].Probes != 18 || report.Channels[0].Incidents != 2 {
+		t.Fatalf("unexpected vip channel: %+v", report.Channels[0])
+	}
+
+	if report.IncidentCount != 2 || len(report.Incidents) != 2 {
+		t.Fatalf("expected 2 incidents, got %d: %+v", report.IncidentCount, report.Incidents)
+	}
+	down, degraded := report.Incidents[0], report.Incidents[1]
+	if down.Type != ReportIncidentDown || !down.Ongoing || down.Duration != 600 || down.EndedAt != 0 {
+		t.Fatalf("unexpected ongoing incident: %+v", down)
+	}
+	if degraded.Type != ReportIncidentDegraded || degraded.Ongoing || degraded.Duration != 3600 {
+		t.Fatalf("unexpected degraded incident: %+v", degraded)
+	}
+	if report.Downtime != 600 {
+		t.Fatalf("expected downtime 600, got %d", report.Downtime)
+	}
+}
+
+func TestRenderProviderReportPDF(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	monitors := []config.ServiceConfig{
+		{Provider: "Relay", ProviderSlug: "relay", Service: "cc", Channel: "vip", Board: "hot"},
+	}
+	incidents := make([]*storage.StatusEvent, 0, 120)
+	for i := range 60 {
+		ts := now.Unix() - int64(i+1)*7200
+		incidents = append(incidents,
+			&storage.StatusEvent{Service: "cc", Channel: "vip", EventType: storage.EventTypeUp, ObservedAt: ts + 600},
+			&storage.StatusEvent{Service: "cc", Channel: "vip", EventType: storage.EventTypeDown, ObservedAt: ts},
+		)
+	}
+	report := buildProviderReportData(monitors, nil, incidents, 0.5, true, now)
+
+	data, err := renderProviderReportPDF(report)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatalf("output is not a PDF document")
+	}
+	// 60 条故障记录需要分页
+	if n := bytes.Count(data, []byte("/Type /Page\n")); n < 2 {
+		t.Fatalf("expected multiple pages, got %d", n)
+	}
+	// 中文字体以子集形式嵌入，并带 ToUnicode 映射以便复制与检索文本
+	for _, want := range []string{"/FontFile2", "/ToUnicode"} {
+		if !bytes.Contains(data, []byte(want)) {
+			t.Fatalf("expected %s in output", want)
+		}
+	}
+}
+
+func TestFormatReportDuration(t *testing.T) {
+	t.Parallel()
+
+	cases := map[int64]string{
+		0:      "0 分钟",
+		30:     "不足 1 分钟",
+		600:    "10 分钟",
+		3900:   "1 小时 5 分钟",
+		180000: "2 天 2 小时",
+	}
+	for in, want := range cases {
+		if got := formatReportDuration(in); got != want {
+			t.Errorf("formatReportDuration(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
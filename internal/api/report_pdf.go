@@ -0,0 +1,457 @@
+package api
+
+import (
+	_ "embed"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/signintech/gopdf"
+
+	"monitor/internal/config"
+)
+
+// reportFont 报告使用的中文字体（文泉驿微米黑，Apache 2.0，见 fonts/README.md）
+// 生成 PDF 时只嵌入用到的字形子集；字体缺失的字符（如 emoji）替换为占位字形
+//
+//go:embed fonts/wqy-microhei.ttf
+var reportFont []byte
+
+const reportFontFamily = "wqy-microhei"
+
+// 报告版式（单位 pt）
+const (
+	reportPageWidth   = 595.28 // A4
+	reportPageHeight  = 841.89
+	reportMargin      = 40.0
+	reportWidth       = reportPageWidth - 2*reportMargin
+	reportChartHeight = 90.0
+)
+
+// reportColor RGB 颜色
+type reportColor struct {
+	R, G, B uint8
+}
+
+// 报告配色（与前端状态色一致）
+var (
+	reportColorText   = reportColor{R: 31, G: 41, B: 55}
+	reportColorMuted  = reportColor{R: 107, G: 114, B: 128}
+	reportColorBorder = reportColor{R: 229, G: 231, B: 235}
+	reportColorEmpty  = reportColor{R: 243, G: 244, B: 246}
+	reportColorUp     = reportColor{R: 22, G: 163, B: 74}
+	reportColorWarn   = reportColor{R: 202, G: 138, B: 4}
+	reportColorDown   = reportColor{R: 220, G: 38, B: 38}
+	reportColorLine   = reportColor{R: 37, G: 99, B: 235}
+)
+
+// reportSponsorLevels 赞助等级显示名
+var reportSponsorLevels = map[config.SponsorLevel]string{
+	config.SponsorLevelBasic:      "节点支持",
+	config.SponsorLevelAdvanced:   "核心服务商",
+	config.SponsorLevelEnterprise: "全球伙伴",
+}
+
+// reportCategories 分类显示名
+var reportCategories = map[string]string{
+	"commercial": "商业站",
+	"public":     "公益站",
+}
+
+// reportIncidentTypes 故障类型显示名
+var reportIncidentTypes = map[string]string{
+	ReportIncidentDown:     "不可用",
+	ReportIncidentDegraded: "性能下降",
+}
+
+// reportWriter 按行向下排版，空间不足时自动换页
+// 坐标系以页面左上角为原点；绘制出错时记录第一个错误，由 renderProviderReportPDF 统一返回
+type reportWriter struct {
+	pdf *gopdf.GoPdf
+	y   float64
+	err error
+}
+
+func (w *reportWriter) setErr(err error) {
+	if w.err == nil && err != nil {
+		w.err = err
+	}
+}
+
+func (w *reportWriter) setFont(size float64) {
+	w.setErr(w.pdf.SetFont(reportFontFamily, "", size))
+}
+
+// text 在 (x, y) 处绘制单行文本，y 为基线位置
+func (w *reportWriter) text(x, y, size float64, c reportColor, s string) {
+	w.setFont(size)
+	w.pdf.SetTextColor(c.R, c.G, c.B)
+	w.pdf.SetXY(x, y)
+	w.setErr(w.pdf.Text(s))
+}
+
+// textWidth 按字体度量计算文本宽度
+func (w *reportWriter) textWidth(s string, size float64) float64 {
+	w.setFont(size)
+	width, err := w.pdf.MeasureTextWidth(s)
+	w.setErr(err)
+	return width
+}
+
+// truncate 截断文本使其宽度不超过 maxWidth（超出时以 "..." 结尾）
+func (w *reportWriter) truncate(s string, size, maxWidth float64) string {
+	if w.textWidth(s, size) <= maxWidth {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		out := string(runes) + "..."
+		if w.textWidth(out, size) <= maxWidth {
+			return out
+		}
+	}
+	return ""
+}
+
+// line 绘制线段
+func (w *reportWriter) line(x1, y1, x2, y2, width float64, c reportColor) {
+	w.pdf.SetLineWidth(width)
+	w.pdf.SetStrokeColor(c.R, c.G, c.B)
+	w.pdf.Line(x1, y1, x2, y2)
+}
+
+// rect 绘制填充矩形，(x, y) 为左上角
+func (w *reportWriter) rect(x, y, width, height float64, c reportColor) {
+	w.pdf.SetFillColor(c.R, c.G, c.B)
+	w.pdf.RectFromUpperLeftWithStyle(x, y, width, height, "F")
+}
+
+// polyline 绘制折线，points 为 [x, y] 序列；少于 2 个点时不绘制
+func (w *reportWriter) polyline(points [][2]float64, width float64, c reportColor) {
+	for i := 1; i < len(points); i++ {
+		w.line(points[i-1][0], points[i-1][1], points[i][0], points[i][1], width, c)
+	}
+}
+
+func (w *reportWriter) newPage() {
+	w.pdf.AddPage()
+	w.y = reportMargin
+	footer := fmt.Sprintf("RelayPulse 服务商监测报告 · 第 %d 页", w.pdf.GetNumberOfPages())
+	w.text(reportMargin, reportPageHeight-reportMargin/2, 8, reportColorMuted, footer)
+}
+
+// ensure 保证剩余高度不小于 h，否则换页
+func (w *reportWriter) ensure(h float64) {
+	if w.pdf.GetNumberOfPages() == 0 || w.y+h > reportPageHeight-reportMargin {
+		w.newPage()
+	}
+}
+
+// heading 小节标题
+func (w *reportWriter) heading(s string) {
+	w.ensure(40)
+	w.y += 18
+	w.text(reportMargin, w.y, 13, reportColorText, s)
+	w.y += 6
+	w.line(reportMargin, w.y, reportMargin+reportWidth, w.y, 0.5, reportColorBorder)
+	w.y += 14
+}
+
+// field 键值行
+func (w *reportWriter) field(label, value string) {
+	w.ensure(16)
+	w.text(reportMargin, w.y, 10, reportColorMuted, label)
+	w.text(reportMargin+90, w.y, 10, reportColorText, w.truncate(value, 10, reportWidth-90))
+	w.y += 16
+}
+
+// row 表格行，widths 为各列宽度
+func (w *reportWriter) row(cells []string, widths []float64, size float64, c reportColor) {
+	w.ensure(size + 6)
+	x := reportMargin
+	for i, cell := range cells {
+		w.text(x, w.y, size, c, w.truncate(cell, size, widths[i]-6))
+		x += widths[i]
+	}
+	w.y += size + 6
+}
+
+// renderProviderReportPDF 将报告渲染为 PDF
+func renderProviderReportPDF(r *ProviderReport) ([]byte, error) {
+	name := r.ProviderName
+	if name == "" {
+		name = r.Provider
+	}
+	generated := time.Unix(r.GeneratedAt, 0).UTC()
+
+	title := name + " 服务商监测报告"
+
+	doc := &gopdf.GoPdf{}
+	doc.Start(gopdf.Config{PageSize: gopdf.Rect{W: reportPageWidth, H: reportPageHeight}})
+	doc.SetInfo(gopdf.PdfInfo{Title: title, Producer: "RelayPulse", CreationDate: generated})
+	if err := doc.AddTTFFontData(reportFontFamily, reportFont); err != nil {
+		return nil, fmt.Errorf("加载报告字体失败: %w", err)
+	}
+	w := &reportWriter{pdf: doc}
+	w.newPage()
+
+	w.y += 10
+	w.text(reportMargin, w.y, 20, reportColorText, title)
+	w.y += 18
+	w.text(reportMargin, w.y, 9, reportColorMuted,
+		fmt.Sprintf("统计区间 %s ~ %s（UTC，%d 天） · 生成于 %s UTC", r.Since, r.Until, r.Days, generated.Format("2006-01-02 15:04")))
+	w.y += 6
+
+	w.heading("基本信息")
+	w.field("服务商", fmt.Sprintf("%s（%s）", name, r.ProviderSlug))
+	w.field("官网", orDash(r.ProviderURL))
+	w.field("分类", orDash(reportCategories[strings.ToLower(r.Category)]))
+	sponsor := orDash(r.Sponsor)
+	if level, ok := reportSponsorLevels[r.SponsorLevel]; ok {
+		sponsor += "（" + level + "）"
+	}
+	w.field("赞助", sponsor)
+	if r.SponsorURL != "" {
+		w.field("赞助链接", r.SponsorURL)
+	}
+	if r.ListedDays != nil {
+		w.field("收录天数", fmt.Sprintf("%d 天", *r.ListedDays))
+	}
+	w.field("参考倍率", formatReportPrice(r.PriceMin, r.PriceMax))
+	risks := make([]string, 0, len(r.Risks))
+	for _, risk := range r.Risks {
+		risks = append(risks, risk.Label)
+	}
+	w.field("风险提示", orDash(strings.Join(risks, "、")))
+	badges := make([]string, 0, len(r.Badges))
+	for _, b := range r.Badges {
+		badges = append(badges, b.ID)
+	}
+	w.field("徽标", orDash(strings.Join(badges, "、")))
+
+	w.heading("可用性概览")
+	w.field("可用率", formatReportUptime(r.Uptime))
+	w.field("平均延迟", formatReportLatency(r.Latency))
+	w.field("探测次数", fmt.Sprintf("%d", r.Probes))
+	w.field("故障次数", fmt.Sprintf("%d", r.IncidentCount))
+	w.field("不可用时长", formatReportDuration(r.Downtime))
+
+	w.heading("每日可用率")
+	drawReportUptimeChart(w, r.Daily)
+
+	w.heading("每日平均延迟")
+	drawReportLatencyChart(w, r.Daily)
+
+	w.heading("通道明细")
+	channelWidths := []float64{215, 60, 70, 70, 60, 40}
+	w.row([]string{"服务 / 通道", "板块", "可用率", "平均延迟", "探测次数", "故障"}, channelWidths, 9, reportColorMuted)
+	for _, ch := range r.Channels {
+		target := pickName(ch.ServiceName, ch.Service)
+		if ch.Channel != "" {
+			target += " / " + pickName(ch.ChannelName, ch.Channel)
+		}
+		w.row([]string{
+			target,
+			ch.Board,
+			formatReportUptime(ch.Uptime),
+			formatReportLatency(ch.Latency),
+			fmt.Sprintf("%d", ch.Probes),
+			fmt.Sprintf("%d", ch.Incidents),
+		}, channelWidths, 9, reportColorText)
+	}
+
+	w.heading("故障记录")
+	if len(r.Incidents) == 0 {
+		w.row([]string{"统计区间内无故障记录"}, []float64{reportWidth}, 9, reportColorMuted)
+	} else {
+		if r.IncidentCount > len(r.Incidents) {
+			w.row([]string{fmt.Sprintf("共 %d 次，仅列出最近 %d 次", r.IncidentCount, len(r.Incidents))}, []float64{reportWidth}, 9, reportColorMuted)
+		}
+		incidentWidths := []float64{95, 60, 230, 70, 60}
+		w.row([]string{"开始时间（UTC）", "类型", "监测项", "持续时长", "状态"}, incidentWidths, 9, reportColorMuted)
+		for _, inc := range r.Incidents {
+			target := inc.Service
+			if inc.Channel != "" {
+				target += " / " + inc.Channel
+			}
+			if inc.Model != "" {
+				target += " / " + inc.Model
+			}
+			state := "已恢复"
+			if inc.Ongoing {
+				state = "进行中"
+			}
+			w.row([]string{
+				time.Unix(inc.StartedAt, 0).UTC().Format("2006-01-02 15:04"),
+				reportIncidentTypes[inc.Type],
+				target,
+				formatReportDuration(inc.Duration),
+				state,
+			}, incidentWidths, 9, reportColorText)
+		}
+	}
+
+	if w.err != nil {
+		return nil, fmt.Errorf("绘制报告失败: %w", w.err)
+	}
+	return doc.GetBytesPdfReturnErr()
+}
+
+// drawReportUptimeChart 每日可用率柱状图（纵轴下限按最低可用率自适应，便于观察波动）
+func drawReportUptimeChart(w *reportWriter, days []ReportDay) {
+	w.ensure(reportChartHeight + 24)
+	floor := 100.0
+	for _, d := range days {
+		if d.Uptime >= 0 && d.Uptime < floor {
+			floor = d.Uptime
+		}
+	}
+	floor = math.Min(math.Floor(floor/10)*10, 90)
+
+	top := w.y
+	left := reportMargin + 36
+	width := reportWidth - 36
+	w.text(reportMargin, top+6, 8, reportColorMuted, "100%")
+	w.text(reportMargin, top+reportChartHeight, 8, reportColorMuted, fmt.Sprintf("%.0f%%", floor))
+	w.line(left, top+reportChartHeight, left+width, top+reportChartHeight, 0.5, reportColorBorder)
+
+	if len(days) > 0 {
+		step := width / float64(len(days))
+		for i, d := range days {
+			x := left + float64(i)*step + step*0.15
+			if d.Uptime < 0 {
+				w.rect(x, top, step*0.7, reportChartHeight, reportColorEmpty)
+				continue
+			}
+			h := math.Max((d.Uptime-floor)/(100-floor)*reportChartHeight, 1)
+			w.rect(x, top+reportChartHeight-h, step*0.7, h, reportUptimeColor(d.Uptime))
+		}
+		w.text(left, top+reportChartHeight+12, 8, reportColorMuted, days[0].Date)
+		last := days[len(days)-1].Date
+		w.text(left+width-w.textWidth(last, 8), top+reportChartHeight+12, 8, reportColorMuted, last)
+	}
+	w.y = top + reportChartHeight + 24
+}
+
+// drawReportLatencyChart 每日平均延迟折线图（无数据的日期断开）
+func drawReportLatencyChart(w *reportWriter, days []ReportDay) {
+	w.ensure(reportChartHeight + 24)
+	maxLatency := 0
+	for _, d := range days {
+		maxLatency = max(maxLatency, d.Latency)
+	}
+
+	top := w.y
+	left := reportMargin + 36
+	width := reportWidth - 36
+	w.line(left, top+reportChartHeight, left+width, top+reportChartHeight, 0.5, reportColorBorder)
+	if maxLatency <= 0 {
+		w.text(left, top+reportChartHeight/2, 9, reportColorMuted, "暂无延迟数据")
+		w.y = top + reportChartHeight + 24
+		return
+	}
+
+	scale := float64(maxLatency) * 1.1
+	w.text(reportMargin, top+6, 8, reportColorMuted, fmt.Sprintf("%dms", maxLatency))
+	w.text(reportMargin, top+reportChartHeight, 8, reportColorMuted, "0")
+
+	step := width / float64(len(days))
+	var segment [][2]float64
+	for i, d := range days {
+		if d.Latency < 0 {
+			w.polyline(segment, 1.2, reportColorLine)
+			segment = segment[:0]
+			continue
+		}
+		x := left + float64(i)*step + step/2
+		y := top + reportChartHeight - float64(d.Latency)/scale*reportChartHeight
+		segment = append(segment, [2]float64{x, y})
+	}
+	w.polyline(segment, 1.2, reportColorLine)
+
+	w.text(left, top+reportChartHeight+12, 8, reportColorMuted, days[0].Date)
+	last := days[len(days)-1].Date
+	w.text(left+width-w.textWidth(last, 8), top+reportChartHeight+12, 8, reportColorMuted, last)
+	w.y = top + reportChartHeight + 24
+}
+
+// reportUptimeColor 可用率对应的颜色
+func reportUptimeColor(uptime float64) reportColor {
+	switch {
+	case uptime >= 99:
+		return reportColorUp
+	case uptime >= 95:
+		return reportColorWarn
+	default:
+		return reportColorDown
+	}
+}
+
+// formatReportUptime 格式化可用率
+func formatReportUptime(uptime float64) string {
+	if uptime < 0 {
+		return "--"
+	}
+	return fmt.Sprintf("%.2f%%", uptime)
+}
+
+// formatReportLatency 格式化延迟
+func formatReportLatency(latency int) string {
+	if latency < 0 {
+		return "--"
+	}
+	return fmt.Sprintf("%dms", latency)
+}
+
+// formatReportPrice 格式化参考倍率区间
+func formatReportPrice(minValue, maxValue *float64) string {
+	switch {
+	case minValue != nil && maxValue != nil && *minValue != *maxValue:
+		return fmt.Sprintf("%g ~ %g", *minValue, *maxValue)
+	case minValue != nil:
+		return fmt.Sprintf("%g", *minValue)
+	case maxValue != nil:
+		return fmt.Sprintf("%g", *maxValue)
+	default:
+		return "--"
+	}
+}
+
+// formatReportDuration 格式化持续时长（精确到分钟）
+func formatReportDuration(seconds int64) string {
+	if seconds < 60 {
+		if seconds <= 0 {
+			return "0 分钟"
+		}
+		return "不足 1 分钟"
+	}
+	d := seconds / 86400
+	h := seconds % 86400 / 3600
+	m := seconds % 3600 / 60
+	switch {
+	case d > 0:
+		return fmt.Sprintf("%d 天 %d 小时", d, h)
+	case h > 0:
+		return fmt.Sprintf("%d 小时 %d 分钟", h, m)
+	default:
+		return fmt.Sprintf("%d 分钟", m)
+	}
+}
+
+// pickName 优先返回显示名
+func pickName(name, fallback string) string {
+	if name != "" {
+		return name
+	}
+	return fallback
+}
+
+// orDash 空值显示为 "--"
+func orDash(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "--"
+	}
+	return s
+}
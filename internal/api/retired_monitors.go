@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// loadRetiredMonitors 查询宽限期内的已下线监测项（include_retired=true 时使用）
+// 存储不支持或查询失败时返回空，不影响正常监测项的展示
+func (h *Handler) loadRetiredMonitors(ctx context.Context, monitors []config.ServiceConfig, graceDays int, now time.Time) ([]config.ServiceConfig, map[storage.MonitorKey]int64) {
+	ds, ok := h.storage.WithContext(ctx).(storage.DecommissionStorage)
+	if !ok {
+		return nil, nil
+	}
+	decommissioned, err := ds.GetDecommissionedMonitors()
+	if err != nil {
+		logger.FromContext(ctx, "api").Warn("查询已下线监测项失败", "error", err)
+		return nil, nil
+	}
+	return retiredServiceConfigs(monitors, decommissioned, now.AddDate(0, 0, -graceDays).Unix())
+}
+
+// retiredServiceConfigs 将下线登记还原为监测项配置（仅含展示元数据），并返回 key -> 下线时间
+// 跳过下线时间早于 cutoff（宽限期已满）或仍在当前配置中的监测项
+func retiredServiceConfigs(monitors []config.ServiceConfig, decommissioned []*storage.DecommissionedMonitor, cutoff int64) ([]config.ServiceConfig, map[storage.MonitorKey]int64) {
+	current := make(map[storage.MonitorKey]bool, len(monitors))
+	for _, m := range monitors {
+		current[storage.MonitorKey{Provider: m.Provider, Service: m.Service, Channel: m.Channel, Model: m.Model}] = true
+	}
+
+	var retired []config.ServiceConfig
+	retiredAt := make(map[storage.MonitorKey]int64)
+	for _, d := range decommissioned {
+		if d.RetiredAt < cutoff || current[d.Key()] {
+			continue
+		}
+		retired = append(retired, config.ServiceConfig{
+			Provider:     d.Provider,
+			ProviderName: d.ProviderName,
+			ProviderSlug: d.ProviderSlug,
+			ProviderURL:  d.ProviderURL,
+			Service:      d.Service,
+			ServiceName:  d.ServiceName,
+			Category:     d.Category,
+			Channel:      d.Channel,
+			ChannelName:  d.ChannelName,
+			Model:        d.Model,
+			Parent:       d.Parent,
+			Board:        d.Board,
+			Hidden:       d.Hidden,
+		})
+		retiredAt[d.Key()] = d.RetiredAt
+	}
+	return retired, retiredAt
+}
+
+// markRetired 在响应中标记已下线的监测项、监测组与模型层
+func markRetired(data []MonitorResult, groups []MonitorGroup, retiredAt map[storage.MonitorKey]int64) {
+	for i := range data {
+		m := &data[i]
+		if at, ok := retiredAt[storage.MonitorKey{Provider: m.Provider, Service: m.Service, Channel: m.Channel}]; ok {
+			m.Retired = true
+			m.RetiredAt = at
+		}
+	}
+	for i := range groups {
+		g := &groups[i]
+		for j := range g.Layers {
+			layer := &g.Layers[j]
+			at, ok := retiredAt[storage.MonitorKey{Provider: g.Provider, Service: g.Service, Channel: g.Channel, Model: layer.Model}]
+			if !ok {
+				continue
+			}
+			layer.Retired = true
+			layer.RetiredAt = at
+			// 父层下线即整组下线
+			if layer.LayerOrder == 0 {
+				g.Retired = true
+				g.RetiredAt = at
+			}
+		}
+	}
+}
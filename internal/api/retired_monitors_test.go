@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestRetiredServiceConfigs(t *testing.T) {
+	t.Parallel()
+
+	monitors := []config.ServiceConfig{
+		{Provider: "Relay", Service: "cc", Channel: "vip"},
+	}
+	decommissioned := []*storage.DecommissionedMonitor{
+		{Provider: "Old", Service: "cc", Channel: "a", ProviderSlug: "old", Board: "hot", RetiredAt: 50},
+		{Provider: "Old", Service: "cc", Channel: "b", ProviderSlug: "old", RetiredAt: 150},
+		{Provider: "Relay", Service: "cc", Channel: "vip", RetiredAt: 200},
+	}
+
+	retired, retiredAt := retiredServiceConfigs(monitors, decommissioned, 100)
+	if len(retired) != 1 || retired[0].Channel != "b" || retired[0].ProviderSlug != "old" {
+		t.Fatalf("expected only in-grace monitor not in config, got %+v", retired)
+	}
+	if retiredAt[storage.MonitorKey{Provider: "Old", Service: "cc", Channel: "b"}] != 150 || len(retiredAt) != 1 {
+		t.Fatalf("unexpected retired_at map: %+v", retiredAt)
+	}
+}
+
+func TestMarkRetired(t *testing.T) {
+	t.Parallel()
+
+	data := []MonitorResult{
+		{Provider: "Relay", Service: "cc", Channel: "vip"},
+		{Provider: "Old", Service: "cc", Channel: "a"},
+	}
+	groups := []MonitorGroup{
+		{Provider: "Relay", Service: "cx", Channel: "m", Layers: []MonitorLayer{
+			{Model: "gpt", LayerOrder: 0},
+			{Model: "mini", LayerOrder: 1},
+		}},
+		{Provider: "Old", Service: "cx", Channel: "m", Layers: []MonitorLayer{
+			{Model: "gpt", LayerOrder: 0},
+		}},
+	}
+	retiredAt := map[storage.MonitorKey]int64{
+		{Provider: "Old", Service: "cc", Channel: "a"}:                  10,
+		{Provider: "Relay", Service: "cx", Channel: "m", Model: "mini"}: 20,
+		{Provider: "Old", Service: "cx", Channel: "m", Model: "gpt"}:    30,
+	}
+
+	markRetired(data, groups, retiredAt)
+
+	if data[0].Retired || !data[1].Retired || data[1].RetiredAt != 10 {
+		t.Fatalf("unexpected data marks: %+v", data)
+	}
+	if groups[0].Retired || groups[0].Layers[0].Retired || !groups[0].Layers[1].Retired {
+		t.Fatalf("expected only child layer retired: %+v", groups[0])
+	}
+	if !groups[1].Retired || groups[1].RetiredAt != 30 {
+		t.Fatalf("expected group retired with parent layer: %+v", groups[1])
+	}
+
+	// 已下线监测项不计入服务商综合状态
+	units := collectProviderStatusUnits(data, groups, nil)
+	for _, u := range units {
+		if u.Provider == "Old" {
+			t.Fatalf("retired monitor counted in provider status: %+v", u)
+		}
+	}
+}
+
+func TestDecommissionLifecycle(t *testing.T) {
+	store := newTestSQLiteStore(t)
+
+	kept := config.ServiceConfig{Provider: "Relay", ProviderSlug: "relay", Service: "cc", Channel: "vip"}
+	removed := config.ServiceConfig{Provider: "Relay", ProviderSlug: "relay", Service: "cc", Channel: "old", ChannelName: "旧通道"}
+	now := time.Now()
+
+	// 热更新移除监测项后登记为已下线
+	retired, restored, err := storage.SyncDecommissionedMonitors(store, []config.ServiceConfig{kept, removed}, []config.ServiceConfig{kept}, now)
+	if err != nil || retired != 1 || restored != 0 {
+		t.Fatalf("expected 1 retired, got retired=%d restored=%d err=%v", retired, restored, err)
+	}
+
+	h := NewHandler(store, &config.AppConfig{})
+	configs, retiredAt := h.loadRetiredMonitors(context.Background(), []config.ServiceConfig{kept}, 30, now)
+	if len(configs) != 1 || configs[0].ChannelName != "旧通道" || retiredAt[storage.MonitorKey{Provider: "Relay", Service: "cc", Channel: "old"}] != now.Unix() {
+		t.Fatalf("unexpected retired monitors: %+v %+v", configs, retiredAt)
+	}
+	if configs, _ := h.loadRetiredMonitors(context.Background(), []config.ServiceConfig{kept}, 30, now.AddDate(0, 0, 31)); len(configs) != 0 {
+		t.Fatalf("expected retired monitor hidden after grace period, got %+v", configs)
+	}
+
+	// 重新加入配置后移除登记
+	retired, restored, err = storage.SyncDecommissionedMonitors(store, []config.ServiceConfig{kept}, []config.ServiceConfig{kept, removed}, now)
+	if err != nil || retired != 0 || restored != 1 {
+		t.Fatalf("expected 1 restored, got retired=%d restored=%d err=%v", retired, restored, err)
+	}
+	if configs, _ := h.loadRetiredMonitors(context.Background(), nil, 30, now); len(configs) != 0 {
+		t.Fatalf("expected registry empty after restore, got %+v", configs)
+	}
+}
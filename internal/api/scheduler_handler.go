@@ -0,0 +1,172 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/scheduler"
+)
+
+// SchedulerTasksResponse 调度任务列表响应
+type SchedulerTasksResponse struct {
+	Tasks []SchedulerTaskItem `json:"tasks"`
+	Meta  SchedulerTasksMeta  `json:"meta"`
+}
+
+// SchedulerTasksMeta 调度任务列表元数据
+type SchedulerTasksMeta struct {
+	Count   int   `json:"count"`
+	Running int   `json:"running"` // 当前在探测中的任务数
+	Panics  int64 `json:"panics"`  // 启动以来探测 panic 的总次数
+	Now     int64 `json:"now"`     // 快照时间（Unix 秒）
+
+	Saturation *SchedulerSaturation `json:"saturation,omitempty"`
+}
+
+// SchedulerSaturation 调度饱和状态（最近 20 次探测开始的滑动窗口）
+type SchedulerSaturation struct {
+	Saturated       bool  `json:"saturated"`
+	Since           int64 `json:"since,omitempty"`    // 进入饱和的时间（Unix 秒）
+	Samples         int   `json:"samples"`            // 窗口内样本数
+	LateStarts      int   `json:"late_starts"`        // 窗口内延迟开始次数
+	AvgStartDelayMs int64 `json:"avg_start_delay_ms"` // 窗口内平均开始延迟
+	MaxStartDelayMs int64 `json:"max_start_delay_ms"` // 窗口内最大开始延迟
+	MaxConcurrency  int   `json:"max_concurrency"`    // 当前并发上限
+	Inflight        int   `json:"inflight"`           // 在途探测数
+}
+
+// SchedulerTaskItem 单个调度任务的运行状态
+type SchedulerTaskItem struct {
+	Provider string `json:"provider"`
+	Service  string `json:"service"`
+	Channel  string `json:"channel,omitempty"`
+	Model    string `json:"model,omitempty"`
+
+	IntervalMs      int64 `json:"interval_ms"`       // 生效的巡检间隔
+	StaggerOffsetMs int64 `json:"stagger_offset_ms"` // 重建任务堆时的首次执行延迟（组间错峰 + 组内间隔）
+	NextRun         int64 `json:"next_run"`          // 下次计划执行时间（Unix 秒）
+	NextRunInMs     int64 `json:"next_run_in_ms"`    // 距下次执行的毫秒数（已到期时为 0）
+
+	Running             bool                 `json:"running"`
+	LastRun             int64                `json:"last_run,omitempty"`         // 最近一次探测开始时间（Unix 秒）
+	LastDurationMs      int64                `json:"last_duration_ms,omitempty"` // 最近一次探测耗时
+	LastResult          *SchedulerTaskResult `json:"last_result,omitempty"`
+	ConsecutiveFailures int                  `json:"consecutive_failures"`
+
+	Starts           int   `json:"starts"`
+	LastQueueWaitMs  int64 `json:"last_queue_wait_ms"`  // 最近一次等待并发槽的时间
+	AvgQueueWaitMs   int64 `json:"avg_queue_wait_ms"`   // 平均等待并发槽的时间
+	MaxQueueWaitMs   int64 `json:"max_queue_wait_ms"`   // 最大等待并发槽的时间
+	LastStartDelayMs int64 `json:"last_start_delay_ms"` // 最近一次开始时间晚于计划时间的时长
+	LateStarts       int   `json:"late_starts"`         // 延迟开始次数（超过巡检间隔的 10%，不低于 5s）
+	Overruns         int   `json:"overruns"`            // 周期超时次数（开始延迟 + 探测耗时超过巡检间隔）
+	BudgetSkips      int   `json:"budget_skips"`        // 因服务商当日预算耗尽跳过的探测次数
+	Panics           int   `json:"panics"`              // 探测 panic 次数（已隔离，最近结果 sub_status=probe_panic）
+}
+
+// SchedulerTaskResult 最近一次探测结果
+type SchedulerTaskResult struct {
+	Status    int    `json:"status"`
+	SubStatus string `json:"sub_status,omitempty"`
+	HttpCode  int    `json:"http_code"`
+	Latency   int    `json:"latency"`
+	Attempts  int    `json:"attempts"`            // 实际发起的请求次数（含重试）
+	Inherited bool   `json:"inherited,omitempty"` // 依赖感知探测沿用了父通道状态
+}
+
+// SetScheduler 设置调度器（可选，用于 /api/admin/scheduler/tasks）
+func (h *Handler) SetScheduler(s *scheduler.Scheduler) {
+	h.scheduler = s
+}
+
+// GetAdminSchedulerTasks 查询调度任务运行状态
+// GET /api/admin/scheduler/tasks?provider=xxx&service=xxx
+// 按下次执行时间升序返回，用于核对错峰与各监测项巡检间隔是否符合配置；
+// meta.saturation 与各任务的排队/延迟字段用于判断 max_concurrency 是否不足
+func (h *Handler) GetAdminSchedulerTasks(c *gin.Context) {
+	if h.scheduler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "调度器未就绪",
+		})
+		return
+	}
+
+	resp := buildSchedulerTasksResponse(h.scheduler.Tasks(), time.Now(),
+		strings.TrimSpace(c.Query("provider")), strings.TrimSpace(c.Query("service")))
+	resp.Meta.Saturation = buildSchedulerSaturation(h.scheduler.Saturation())
+	resp.Meta.Panics = h.scheduler.Panics()
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
+
+// buildSchedulerTasksResponse 将调度任务快照转换为响应（provider 不区分大小写，service 精确匹配）
+func buildSchedulerTasksResponse(infos []scheduler.TaskInfo, now time.Time, provider, service string) SchedulerTasksResponse {
+	resp := SchedulerTasksResponse{Tasks: []SchedulerTaskItem{}, Meta: SchedulerTasksMeta{Now: now.Unix()}}
+	for _, info := range infos {
+		if (provider != "" && !strings.EqualFold(provider, info.Provider)) || (service != "" && service != info.Service) {
+			continue
+		}
+
+		item := SchedulerTaskItem{
+			Provider:            info.Provider,
+			Service:             info.Service,
+			Channel:             info.Channel,
+			Model:               info.Model,
+			IntervalMs:          info.Interval.Milliseconds(),
+			StaggerOffsetMs:     info.StaggerOffset.Milliseconds(),
+			NextRun:             info.NextRun.Unix(),
+			NextRunInMs:         max(info.NextRun.Sub(now), 0).Milliseconds(),
+			Running:             info.Running,
+			LastDurationMs:      info.LastDuration.Milliseconds(),
+			ConsecutiveFailures: info.ConsecutiveFailures,
+			Starts:              info.Starts,
+			LastQueueWaitMs:     info.LastQueueWait.Milliseconds(),
+			AvgQueueWaitMs:      info.AvgQueueWait.Milliseconds(),
+			MaxQueueWaitMs:      info.MaxQueueWait.Milliseconds(),
+			LastStartDelayMs:    info.LastStartDelay.Milliseconds(),
+			LateStarts:          info.LateStarts,
+			Overruns:            info.Overruns,
+			BudgetSkips:         info.BudgetSkips,
+			Panics:              info.Panics,
+		}
+		if !info.LastRun.IsZero() {
+			item.LastRun = info.LastRun.Unix()
+		}
+		if r := info.LastResult; r != nil {
+			item.LastResult = &SchedulerTaskResult{
+				Status:    r.Status,
+				SubStatus: string(r.SubStatus),
+				HttpCode:  r.HttpCode,
+				Latency:   r.Latency,
+				Attempts:  r.Attempts,
+				Inherited: r.Inherited,
+			}
+		}
+		if info.Running {
+			resp.Meta.Running++
+		}
+		resp.Tasks = append(resp.Tasks, item)
+	}
+	resp.Meta.Count = len(resp.Tasks)
+	return resp
+}
+
+// buildSchedulerSaturation 将调度饱和状态快照转换为响应
+func buildSchedulerSaturation(info scheduler.SaturationInfo) *SchedulerSaturation {
+	sat := &SchedulerSaturation{
+		Saturated:       info.Saturated,
+		Samples:         info.Samples,
+		LateStarts:      info.LateStarts,
+		AvgStartDelayMs: info.AvgStartDelay.Milliseconds(),
+		MaxStartDelayMs: info.MaxStartDelay.Milliseconds(),
+		MaxConcurrency:  info.MaxConcurrency,
+		Inflight:        info.Inflight,
+	}
+	if !info.Since.IsZero() {
+		sat.Since = info.Since.Unix()
+	}
+	return sat
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"monitor/internal/scheduler"
+	"monitor/internal/storage"
+)
+
+func TestBuildSchedulerTasksResponse(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	infos := []scheduler.TaskInfo{
+		{
+			Provider: "Relay", Service: "cc", Channel: "vip",
+			Interval: time.Minute, NextRun: now.Add(-time.Second),
+			Running: true, LastRun: now.Add(-2 * time.Second), LastDuration: 1500 * time.Millisecond,
+			LastResult:          &scheduler.TaskResult{Status: 0, SubStatus: storage.SubStatusServerError, HttpCode: 502},
+			ConsecutiveFailures: 3,
+			Starts:              4, AvgQueueWait: 2 * time.Second, LastStartDelay: 12 * time.Second, LateStarts: 2, Overruns: 1,
+		},
+		{
+			Provider: "Other", Service: "cx", Model: "gpt",
+			Interval: 5 * time.Minute, StaggerOffset: 7500 * time.Millisecond, NextRun: now.Add(30 * time.Second),
+		},
+	}
+
+	resp := buildSchedulerTasksResponse(infos, now, "", "")
+	if resp.Meta.Count != 2 || resp.Meta.Running != 1 || resp.Meta.Now != now.Unix() {
+		t.Fatalf("unexpected meta: %+v", resp.Meta)
+	}
+	first := resp.Tasks[0]
+	if first.IntervalMs != 60000 || first.NextRunInMs != 0 || first.LastDurationMs != 1500 ||
+		first.LastResult == nil || first.LastResult.SubStatus != "server_error" || first.ConsecutiveFailures != 3 ||
+		first.Starts != 4 || first.AvgQueueWaitMs != 2000 || first.LastStartDelayMs != 12000 || first.LateStarts != 2 || first.Overruns != 1 {
+		t.Fatalf("unexpected first task: %+v", first)
+	}
+	second := resp.Tasks[1]
+	if second.StaggerOffsetMs != 7500 || second.NextRunInMs != 30000 || second.LastRun != 0 || second.LastResult != nil {
+		t.Fatalf("unexpected second task: %+v", second)
+	}
+
+	filtered := buildSchedulerTasksResponse(infos, now, "relay", "cc")
+	if filtered.Meta.Count != 1 || filtered.Tasks[0].Provider != "Relay" {
+		t.Fatalf("filter not applied: %+v", filtered)
+	}
+
+	sat := buildSchedulerSaturation(scheduler.SaturationInfo{Saturated: true, Since: now, Samples: 20, LateStarts: 12, AvgStartDelay: 8 * time.Second})
+	if !sat.Saturated || sat.Since != now.Unix() || sat.LateStarts != 12 || sat.AvgStartDelayMs != 8000 {
+		t.Fatalf("unexpected saturation: %+v", sat)
+	}
+	if sat := buildSchedulerSaturation(scheduler.SaturationInfo{}); sat.Since != 0 {
+		t.Fatalf("expected zero since when not saturated: %+v", sat)
+	}
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+)
+
+// GetConfigSchema 返回主配置文件的 JSON Schema（GET /api/config/schema）
+// 供编辑器（yaml-language-server 等）提供校验与补全，以及外部工具检查配置；仅描述结构，不含任何配置值
+func (h *Handler) GetConfigSchema(c *gin.Context) {
+	data, err := config.ConfigSchemaJSON()
+	if err != nil {
+		logger.Error("api", "生成配置 Schema 失败", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成配置 Schema 失败"})
+		return
+	}
+	// Schema 由结构体定义生成，同一版本内不变
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/schema+json; charset=utf-8", data)
+}
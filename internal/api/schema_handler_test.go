@@ -0,0 +1,41 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetConfigSchema(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := &Handler{}
+	router := gin.New()
+	router.GET("/api/config/schema", h.GetConfigSchema)
+	router.GET("/api/:ns/status", func(c *gin.Context) { c.Status(http.StatusTeapot) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/config/schema", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/schema+json") {
+		t.Fatalf("unexpected content type: %s", ct)
+	}
+
+	var schema struct {
+		Schema     string                     `json:"$schema"`
+		Properties map[string]json.RawMessage `json:"properties"`
+		Defs       map[string]json.RawMessage `json:"$defs"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &schema); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if schema.Schema == "" || schema.Properties["monitors"] == nil || schema.Defs["ServiceConfig"] == nil {
+		t.Fatalf("unexpected schema: $schema=%q props=%d defs=%d", schema.Schema, len(schema.Properties), len(schema.Defs))
+	}
+}
@@ -2,19 +2,48 @@ package api
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"monitor/internal/logger"
 	"monitor/internal/selftest"
+	"monitor/internal/storage"
 )
 
+// selfTestStreamMaxDuration 单个进度流连接的最长持续时间（超出后客户端可重连或改为轮询）
+const selfTestStreamMaxDuration = 5 * time.Minute
+
 // CreateTestRequest 创建测试请求
 type CreateTestRequest struct {
 	TestType string `json:"test_type" binding:"required"`
 	APIURL   string `json:"api_url" binding:"required,url,max=500"`
 	APIKey   string `json:"api_key" binding:"required,min=10,max=200"`
+
+	// 人机验证（启用时必填）：challenge 为 PoW 题目，challenge_response 为 CAPTCHA 令牌或 PoW 解
+	Challenge         string `json:"challenge" binding:"max=256"`
+	ChallengeResponse string `json:"challenge_response" binding:"max=4096"`
+}
+
+// CreateBatchTestRequest 批量测试请求
+// api_key 为各目标的默认密钥，目标可单独覆盖
+type CreateBatchTestRequest struct {
+	APIKey  string             `json:"api_key" binding:"omitempty,min=10,max=200"`
+	Targets []BatchTargetInput `json:"targets" binding:"required,min=1,dive"`
+
+	Challenge         string `json:"challenge" binding:"max=256"`
+	ChallengeResponse string `json:"challenge_response" binding:"max=4096"`
+}
+
+// BatchTargetInput 批量测试中的单个目标
+type BatchTargetInput struct {
+	TestType string `json:"test_type" binding:"required"`
+	APIURL   string `json:"api_url" binding:"required,url,max=500"`
+	APIKey   string `json:"api_key" binding:"omitempty,min=10,max=200"`
 }
 
 // ErrorResponse 自助测试错误响应（兼容旧版，仅在需要时附带 code）
@@ -53,13 +82,53 @@ type GetTestResponse struct {
 
 // SelfTestConfigResponse 自助测试配置响应
 type SelfTestConfigResponse struct {
-	MaxConcurrent      int `json:"max_concurrent"`
-	MaxQueueSize       int `json:"max_queue_size"`
-	JobTimeoutSeconds  int `json:"job_timeout_seconds"`
-	RateLimitPerMinute int `json:"rate_limit_per_minute"`
+	MaxConcurrent      int    `json:"max_concurrent"`
+	MaxQueueSize       int    `json:"max_queue_size"`
+	JobTimeoutSeconds  int    `json:"job_timeout_seconds"`
+	RateLimitPerMinute int    `json:"rate_limit_per_minute"`
+	BatchMaxTargets    int    `json:"batch_max_targets"`
+	ShareEnabled       bool   `json:"share_enabled"`
+	ChallengeType      string `json:"challenge_type"` // none/turnstile/hcaptcha/pow
 	// 签名密钥不应暴露给客户端
 }
 
+// BatchTestResponse 批量测试响应（创建与查询共用）
+type BatchTestResponse struct {
+	ID        string                `json:"id"`
+	CreatedAt int64                 `json:"created_at"`
+	Summary   selftest.BatchSummary `json:"summary"`
+	Results   []BatchTargetResult   `json:"results"` // 与提交顺序一致（已过期的子任务不返回）
+}
+
+// BatchTargetResult 批量测试中单个目标的结果
+type BatchTargetResult struct {
+	APIURL string `json:"api_url"`
+	GetTestResponse
+}
+
+// ShareSelfTestResponse 创建分享链接响应
+type ShareSelfTestResponse struct {
+	Token     string `json:"token"`
+	URL       string `json:"url"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// SharedSelfTestResponse 分享结果响应（不含 API Key 与响应片段）
+type SharedSelfTestResponse struct {
+	ID           string `json:"id"`
+	TestType     string `json:"test_type"`
+	APIURL       string `json:"api_url"`
+	Status       string `json:"status"`
+	ProbeStatus  int    `json:"probe_status"`
+	SubStatus    string `json:"sub_status,omitempty"`
+	HTTPCode     int    `json:"http_code,omitempty"`
+	Latency      int    `json:"latency,omitempty"`
+	ErrorMessage string `json:"error_message,omitempty"`
+	CreatedAt    int64  `json:"created_at"`
+	FinishedAt   int64  `json:"finished_at"`
+	ExpiresAt    int64  `json:"expires_at"`
+}
+
 // TestTypeInfo 测试类型信息
 type TestTypeInfo struct {
 	ID          string `json:"id"`
@@ -71,7 +140,7 @@ type TestTypeInfo struct {
 // POST /api/selftest
 func (h *Handler) CreateSelfTest(c *gin.Context) {
 	// 检查功能是否启用
-	if h.selfTestMgr == nil {
+	if !h.selfTestMgr.Enabled() {
 		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
 			Code:  string(selftest.ErrCodeFeatureDisabled),
 			Error: "自助测试功能未启用",
@@ -100,6 +169,11 @@ func (h *Handler) CreateSelfTest(c *gin.Context) {
 		return
 	}
 
+	// 人机验证（入队前校验）
+	if !h.verifySelfTestChallenge(c, req.Challenge, req.ChallengeResponse) {
+		return
+	}
+
 	// 创建任务
 	job, err := h.selfTestMgr.CreateJob(
 		req.TestType,
@@ -154,7 +228,7 @@ func (h *Handler) CreateSelfTest(c *gin.Context) {
 // GetSelfTest 获取测试任务状态
 // GET /api/selftest/:id
 func (h *Handler) GetSelfTest(c *gin.Context) {
-	if h.selfTestMgr == nil {
+	if !h.selfTestMgr.Enabled() {
 		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
 			Code:  string(selftest.ErrCodeFeatureDisabled),
 			Error: "自助测试功能未启用",
@@ -190,7 +264,99 @@ func (h *Handler) GetSelfTest(c *gin.Context) {
 		return
 	}
 
-	// 构造响应
+	c.JSON(http.StatusOK, buildGetTestResponse(job))
+}
+
+// StreamSelfTest 以 SSE 推送测试任务进度
+// GET /api/selftest/:id/events
+// 事件：
+//   - state：任务状态变化（data 与 GET /api/selftest/:id 响应一致）
+//   - token：上游流式响应的增量文本（data: {"delta":"..."}）
+//
+// 任务进入终态后推送最终 state 事件并结束；已结束的任务仅推送一次 state
+func (h *Handler) StreamSelfTest(c *gin.Context) {
+	if !h.selfTestMgr.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Code:  string(selftest.ErrCodeFeatureDisabled),
+			Error: "自助测试功能未启用",
+		})
+		return
+	}
+
+	jobID := c.Param("id")
+	snapshot, events, cancel, err := h.selfTestMgr.Subscribe(jobID)
+	if err != nil {
+		var stErr *selftest.Error
+		if errors.As(err, &stErr) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Code:  string(stErr.Code),
+				Error: stErr.Message,
+			})
+			return
+		}
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:  string(selftest.CodeOf(err)),
+			Error: err.Error(),
+		})
+		return
+	}
+	defer cancel()
+
+	// 服务端 WriteTimeout 为 15s，流式连接需单独放宽写超时（gzip 中间件已排除该路径）
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(selfTestStreamMaxDuration)); err != nil {
+		logger.Warn("selftest", "设置 SSE 写超时失败", "job_id", jobID, "error", err)
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 禁用 Nginx 缓冲
+
+	c.SSEvent("state", buildGetTestResponse(snapshot))
+	c.Writer.Flush()
+	if events == nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+	deadline := time.NewTimer(selfTestStreamMaxDuration - time.Second)
+	defer deadline.Stop()
+
+	finished := false
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				// 通道关闭表示任务已结束；终态事件可能因消费过慢被丢弃，这里补发最终结果
+				if !finished {
+					if job, err := h.selfTestMgr.GetJob(jobID); err == nil {
+						c.SSEvent("state", buildGetTestResponse(job))
+					}
+				}
+				return false
+			}
+			switch ev.Type {
+			case selftest.ProgressState:
+				finished = ev.Job.IsTerminal()
+				c.SSEvent("state", buildGetTestResponse(ev.Job))
+			case selftest.ProgressToken:
+				c.SSEvent("token", gin.H{"delta": ev.Delta})
+			}
+			return true
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			return true
+		case <-deadline.C:
+			return false
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// buildGetTestResponse 将任务快照转换为响应（仅终态任务附带结果字段）
+func buildGetTestResponse(job *selftest.TestJob) GetTestResponse {
 	resp := GetTestResponse{
 		ID:        job.ID,
 		Status:    string(job.Status),
@@ -229,13 +395,13 @@ func (h *Handler) GetSelfTest(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, resp)
+	return resp
 }
 
 // GetSelfTestConfig 获取自助测试配置
 // GET /api/selftest/config
 func (h *Handler) GetSelfTestConfig(c *gin.Context) {
-	if h.selfTestMgr == nil {
+	if !h.selfTestMgr.Enabled() {
 		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
 			Code:  string(selftest.ErrCodeFeatureDisabled),
 			Error: "自助测试功能未启用",
@@ -252,16 +418,72 @@ func (h *Handler) GetSelfTestConfig(c *gin.Context) {
 		MaxQueueSize:       cfg.MaxQueueSize,
 		JobTimeoutSeconds:  int(cfg.JobTimeoutDuration.Seconds()),
 		RateLimitPerMinute: cfg.RateLimitPerMinute,
+		BatchMaxTargets:    cfg.BatchMaxTargets,
+		ShareEnabled:       cfg.ShareEnabled,
+		ChallengeType:      cfg.Challenge.Type,
 		// SignatureSecret 不应暴露给客户端
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
 
+// GetSelfTestChallenge 获取人机验证参数
+// GET /api/selftest/challenge
+// 未启用人机验证时返回 {"type":"none"}；PoW 模式每次调用下发新题目
+func (h *Handler) GetSelfTestChallenge(c *gin.Context) {
+	if !h.selfTestMgr.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Code:  string(selftest.ErrCodeFeatureDisabled),
+			Error: "自助测试功能未启用",
+		})
+		return
+	}
+
+	challenge, err := h.selfTestMgr.IssueChallenge()
+	if err != nil {
+		logger.Error("selftest", "生成人机验证参数失败", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "生成人机验证参数失败",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, challenge)
+}
+
+// verifySelfTestChallenge 校验人机验证结果，失败时写入错误响应并返回 false
+func (h *Handler) verifySelfTestChallenge(c *gin.Context, challenge, response string) bool {
+	clientIP := c.ClientIP()
+	err := h.selfTestMgr.VerifyChallenge(c.Request.Context(), challenge, response, clientIP)
+	if err == nil {
+		return true
+	}
+
+	var stErr *selftest.Error
+	if errors.As(err, &stErr) {
+		logger.Warn("selftest", "Challenge verification failed",
+			"ip", clientIP, "code", stErr.Code, "error", stErr.Err)
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Code:  string(stErr.Code),
+			Error: stErr.Message,
+		})
+		return false
+	}
+
+	// 第三方校验服务异常：拒绝请求（fail-closed），避免验证被绕过
+	logger.Error("selftest", "Challenge verification error", "ip", clientIP, "error", err)
+	c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+		Code:  string(selftest.ErrCodeChallengeFailed),
+		Error: "人机验证服务暂不可用，请稍后再试",
+	})
+	return false
+}
+
 // GetTestTypes 获取可用的测试类型
 // GET /api/selftest/types
 func (h *Handler) GetTestTypes(c *gin.Context) {
-	if h.selfTestMgr == nil {
+	if !h.selfTestMgr.Enabled() {
 		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
 			Code:  string(selftest.ErrCodeFeatureDisabled),
 			Error: "自助测试功能未启用",
@@ -281,3 +503,318 @@ func (h *Handler) GetTestTypes(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// CreateSelfTestShare 持久化已完成的测试结果并生成签名分享链接
+// POST /api/selftest/:id/share
+// 任务结果仅在内存中保留 result_ttl，需在此期间内创建分享
+func (h *Handler) CreateSelfTestShare(c *gin.Context) {
+	if !h.selfTestMgr.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Code:  string(selftest.ErrCodeFeatureDisabled),
+			Error: "自助测试功能未启用",
+		})
+		return
+	}
+
+	h.cfgMu.RLock()
+	cfg := h.config.SelfTest
+	publicBaseURL := h.config.PublicBaseURL
+	h.cfgMu.RUnlock()
+
+	if !cfg.ShareEnabled {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Code:  string(selftest.ErrCodeShareDisabled),
+			Error: "结果分享功能未启用",
+		})
+		return
+	}
+
+	store, ok := h.storage.WithContext(c.Request.Context()).(storage.SelfTestResultStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, ErrorResponse{
+			Code:  string(selftest.ErrCodeShareDisabled),
+			Error: "当前存储后端不支持结果分享",
+		})
+		return
+	}
+
+	job, err := h.selfTestMgr.GetJob(c.Param("id"))
+	if err != nil {
+		var stErr *selftest.Error
+		if errors.As(err, &stErr) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Code:  string(stErr.Code),
+				Error: stErr.Message,
+			})
+			return
+		}
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:  string(selftest.CodeOf(err)),
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if !job.IsTerminal() || job.FinishedAt == nil {
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Code:  string(selftest.ErrCodeJobNotFinished),
+			Error: "任务尚未完成，无法分享",
+		})
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(cfg.ShareRetentionDuration)
+	result := &storage.SelfTestResult{
+		ID:           job.ID,
+		TestType:     job.TestType,
+		APIURL:       job.APIURL,
+		Status:       string(job.Status),
+		ProbeStatus:  job.ProbeStatus,
+		SubStatus:    job.SubStatus,
+		HTTPCode:     job.HTTPCode,
+		Latency:      job.Latency,
+		ErrorMessage: job.ErrorMessage,
+		CreatedAt:    job.CreatedAt.Unix(),
+		FinishedAt:   job.FinishedAt.Unix(),
+		ExpiresAt:    expiresAt.Unix(),
+	}
+
+	// 保存前顺带清理已到期的分享，避免表无限增长
+	if purged, err := store.PurgeExpiredSelfTestResults(now.Unix()); err != nil {
+		logger.Warn("selftest", "清理过期分享结果失败", "error", err)
+	} else if purged > 0 {
+		logger.Info("selftest", "已清理过期分享结果", "deleted", purged)
+	}
+
+	if err := store.SaveSelfTestResult(result); err != nil {
+		logger.Error("selftest", "保存分享结果失败", "job_id", job.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "保存分享结果失败",
+		})
+		return
+	}
+
+	token := selftest.NewShareSigner(cfg.ShareSecret).Sign(job.ID, expiresAt)
+
+	logger.Info("selftest", "Job result shared",
+		"job_id", job.ID, "expires_at", expiresAt.Unix(), "ip", c.ClientIP())
+
+	c.JSON(http.StatusCreated, ShareSelfTestResponse{
+		Token:     token,
+		URL:       strings.TrimRight(publicBaseURL, "/") + "/api/selftest/results/" + token,
+		ExpiresAt: expiresAt.Unix(),
+	})
+}
+
+// GetSharedSelfTestResult 通过分享令牌获取已持久化的测试结果
+// GET /api/selftest/results/:token
+func (h *Handler) GetSharedSelfTestResult(c *gin.Context) {
+	h.cfgMu.RLock()
+	cfg := h.config.SelfTest
+	h.cfgMu.RUnlock()
+
+	if !cfg.ShareEnabled {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Code:  string(selftest.ErrCodeShareDisabled),
+			Error: "结果分享功能未启用",
+		})
+		return
+	}
+
+	store, ok := h.storage.WithContext(c.Request.Context()).(storage.SelfTestResultStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, ErrorResponse{
+			Code:  string(selftest.ErrCodeShareDisabled),
+			Error: "当前存储后端不支持结果分享",
+		})
+		return
+	}
+
+	now := time.Now()
+	jobID, _, err := selftest.NewShareSigner(cfg.ShareSecret).Verify(c.Param("token"), now)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:  string(selftest.ErrCodeInvalidShareToken),
+			Error: "分享链接无效或已过期",
+		})
+		return
+	}
+
+	result, err := store.GetSelfTestResult(jobID)
+	if err != nil {
+		logger.Error("selftest", "查询分享结果失败", "job_id", jobID, "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "查询分享结果失败",
+		})
+		return
+	}
+	// 令牌有效但记录已被清理（或保留时间被调短）时同样视为过期
+	if result == nil || result.ExpiresAt <= now.Unix() {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:  string(selftest.ErrCodeInvalidShareToken),
+			Error: "分享链接无效或已过期",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, SharedSelfTestResponse{
+		ID:           result.ID,
+		TestType:     result.TestType,
+		APIURL:       result.APIURL,
+		Status:       result.Status,
+		ProbeStatus:  result.ProbeStatus,
+		SubStatus:    result.SubStatus,
+		HTTPCode:     result.HTTPCode,
+		Latency:      result.Latency,
+		ErrorMessage: result.ErrorMessage,
+		CreatedAt:    result.CreatedAt,
+		FinishedAt:   result.FinishedAt,
+		ExpiresAt:    result.ExpiresAt,
+	})
+}
+
+// CreateSelfTestBatch 创建批量自助测试任务
+// POST /api/selftest/batch
+// 每个目标展开为独立子任务，与普通任务共享队列与并发限制；限流按目标数计入
+func (h *Handler) CreateSelfTestBatch(c *gin.Context) {
+	if !h.selfTestMgr.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Code:  string(selftest.ErrCodeFeatureDisabled),
+			Error: "自助测试功能未启用",
+		})
+		return
+	}
+
+	var req CreateBatchTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:  string(selftest.ErrCodeBadRequest),
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if maxTargets := h.selfTestMgr.BatchMaxTargets(); len(req.Targets) > maxTargets {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:  string(selftest.ErrCodeBatchTooLarge),
+			Error: fmt.Sprintf("单次最多测试 %d 个目标", maxTargets),
+		})
+		return
+	}
+
+	targets := make([]selftest.BatchTarget, 0, len(req.Targets))
+	for i, t := range req.Targets {
+		apiKey := t.APIKey
+		if apiKey == "" {
+			apiKey = req.APIKey
+		}
+		if apiKey == "" {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Code:  string(selftest.ErrCodeBadRequest),
+				Error: fmt.Sprintf("第 %d 个目标缺少 api_key", i+1),
+			})
+			return
+		}
+		targets = append(targets, selftest.BatchTarget{
+			TestType: t.TestType,
+			APIURL:   t.APIURL,
+			APIKey:   apiKey,
+		})
+	}
+
+	// IP 限流检查（按目标数扣除）
+	clientIP := c.ClientIP()
+	if !h.selfTestMgr.CheckRateLimitN(clientIP, len(targets)) {
+		logger.Warn("selftest", "Rate limit exceeded", "ip", clientIP, "targets", len(targets))
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{
+			Code:  string(selftest.ErrCodeRateLimited),
+			Error: "请求过于频繁，请稍后再试",
+		})
+		return
+	}
+
+	// 人机验证（入队前校验）
+	if !h.verifySelfTestChallenge(c, req.Challenge, req.ChallengeResponse) {
+		return
+	}
+
+	batch, jobs, err := h.selfTestMgr.CreateBatch(targets)
+	if err != nil {
+		logger.Error("selftest", "Failed to create batch",
+			"targets", len(targets), "ip", clientIP, "error", err)
+
+		statusCode := http.StatusBadRequest
+		if selftest.CodeOf(err) == selftest.ErrCodeQueueFull {
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		var stErr *selftest.Error
+		if errors.As(err, &stErr) {
+			c.JSON(statusCode, ErrorResponse{
+				Code:  string(stErr.Code),
+				Error: stErr.Message,
+			})
+			return
+		}
+		c.JSON(statusCode, ErrorResponse{
+			Code:  string(selftest.CodeOf(err)),
+			Error: err.Error(),
+		})
+		return
+	}
+
+	logger.Info("selftest", "Batch created",
+		"batch_id", batch.ID, "targets", len(targets), "ip", clientIP)
+
+	c.JSON(http.StatusCreated, buildBatchTestResponse(batch, jobs))
+}
+
+// GetSelfTestBatch 获取批量测试任务状态与聚合结果
+// GET /api/selftest/batch/:id
+func (h *Handler) GetSelfTestBatch(c *gin.Context) {
+	if !h.selfTestMgr.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Code:  string(selftest.ErrCodeFeatureDisabled),
+			Error: "自助测试功能未启用",
+		})
+		return
+	}
+
+	batch, jobs, err := h.selfTestMgr.GetBatch(c.Param("id"))
+	if err != nil {
+		var stErr *selftest.Error
+		if errors.As(err, &stErr) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Code:  string(stErr.Code),
+				Error: stErr.Message,
+			})
+			return
+		}
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:  string(selftest.CodeOf(err)),
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, buildBatchTestResponse(batch, jobs))
+}
+
+// buildBatchTestResponse 聚合批量任务的子任务结果
+func buildBatchTestResponse(batch *selftest.BatchJob, jobs []*selftest.TestJob) BatchTestResponse {
+	resp := BatchTestResponse{
+		ID:        batch.ID,
+		CreatedAt: batch.CreatedAt.Unix(),
+		Summary:   selftest.SummarizeBatch(jobs),
+		Results:   make([]BatchTargetResult, 0, len(jobs)),
+	}
+	for _, job := range jobs {
+		resp.Results = append(resp.Results, BatchTargetResult{
+			APIURL:          job.APIURL,
+			GetTestResponse: buildGetTestResponse(job),
+		})
+	}
+	return resp
+}
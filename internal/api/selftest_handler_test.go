@@ -0,0 +1,44 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"monitor/internal/selftest"
+)
+
+func TestBuildBatchTestResponse(t *testing.T) {
+	t.Parallel()
+
+	created := time.Unix(1700000000, 0)
+	finished := created.Add(2 * time.Second)
+	batch := &selftest.BatchJob{ID: "b1", JobIDs: []string{"j1", "j2", "j3"}, CreatedAt: created}
+	jobs := []*selftest.TestJob{
+		{ID: "j1", TestType: "cc", APIURL: "https://a.example.com", Status: selftest.StatusSuccess, ProbeStatus: 1, Latency: 800, CreatedAt: created, FinishedAt: &finished},
+		{ID: "j2", TestType: "cx", APIURL: "https://b.example.com", Status: selftest.StatusSuccess, ProbeStatus: 2, Latency: 1200, CreatedAt: created, FinishedAt: &finished},
+		{ID: "j3", TestType: "cc", APIURL: "https://c.example.com", Status: selftest.StatusRunning, CreatedAt: created},
+	}
+
+	resp := buildBatchTestResponse(batch, jobs)
+	if resp.ID != "b1" || resp.CreatedAt != created.Unix() {
+		t.Fatalf("unexpected batch meta: %+v", resp)
+	}
+	want := selftest.BatchSummary{Status: "running", Total: 3, Running: 1, Success: 2, AvgLatency: 1000}
+	if resp.Summary != want {
+		t.Fatalf("summary = %+v, want %+v", resp.Summary, want)
+	}
+	if len(resp.Results) != 3 || resp.Results[1].APIURL != "https://b.example.com" {
+		t.Fatalf("results should keep submission order: %+v", resp.Results)
+	}
+	if resp.Results[0].ProbeStatus == nil || *resp.Results[0].ProbeStatus != 1 {
+		t.Fatalf("terminal job should carry probe_status: %+v", resp.Results[0])
+	}
+	if resp.Results[2].ProbeStatus != nil {
+		t.Fatalf("running job should not carry result fields: %+v", resp.Results[2])
+	}
+
+	jobs[2].Status = selftest.StatusTimeout
+	if got := buildBatchTestResponse(batch, jobs).Summary; got.Status != "completed" || got.Failed != 1 {
+		t.Fatalf("expected completed summary with 1 failure, got %+v", got)
+	}
+}
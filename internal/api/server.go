@@ -2,25 +2,33 @@ package api
 
 import (
 	"context"
+	"crypto/tls"
 	"embed"
 	"fmt"
 	"io/fs"
 	"mime"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
-	"github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 
+	"monitor/internal/adminauth"
 	"monitor/internal/buildinfo"
 	"monitor/internal/config"
 	"monitor/internal/logger"
 	"monitor/internal/storage"
+	"monitor/internal/tracing"
 )
 
 //go:embed frontend/dist
@@ -31,19 +39,24 @@ type Server struct {
 	handler    *Handler
 	router     *gin.Engine
 	httpServer *http.Server
-	port       string
+	listen     config.ServerConfig
+	certs      *certReloader // HTTPS 证书（未启用 HTTPS 时为 nil）
+
+	diagnostics config.DiagnosticsConfig
+	diagServer  *http.Server // 独立诊断端口（未配置 diagnostics.listen 时为 nil）
 
 	// 公告处理器（可选，通过 RegisterAnnouncementsHandler 注入）
 	announcementsHandler gin.HandlerFunc
 }
 
-// NewServer 创建服务器
-func NewServer(store storage.Storage, cfg *config.AppConfig, port string) *Server {
+// NewServer 创建服务器（监听参数取自 cfg.Server）
+func NewServer(store storage.Storage, cfg *config.AppConfig) *Server {
 	// 设置gin模式
 	gin.SetMode(gin.ReleaseMode)
 
-	// 创建路由
-	router := gin.Default()
+	// 创建路由（访问日志由 accessLogMiddleware 以结构化日志输出，不使用 gin 默认的文本日志）
+	router := gin.New()
+	router.Use(gin.Recovery())
 
 	// CORS中间件 - 从环境变量获取允许的来源
 	allowedOrigins := []string{"https://relaypulse.top"}
@@ -66,8 +79,8 @@ func NewServer(store storage.Storage, cfg *config.AppConfig, port string) *Serve
 	corsConfig := cors.Config{
 		AllowOrigins:     allowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Request-ID", "Accept-Encoding"},
-		ExposeHeaders:    []string{"Content-Length", "X-Request-ID"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", requestIDHeader, "Accept-Encoding"},
+		ExposeHeaders:    []string{"Content-Length", requestIDHeader},
 		AllowCredentials: false,
 		MaxAge:           12 * time.Hour,
 	}
@@ -92,30 +105,44 @@ func NewServer(store storage.Storage, cfg *config.AppConfig, port string) *Serve
 	})
 
 	// Request ID 中间件 - 为每个请求生成唯一 ID，便于日志追踪
-	router.Use(func(c *gin.Context) {
-		requestID := c.GetHeader("X-Request-ID")
-		if requestID == "" {
-			requestID = uuid.New().String()[:8] // 使用短 UUID
-		}
-		c.Set("request_id", requestID)
-		c.Header("X-Request-ID", requestID)
+	router.Use(requestIDMiddleware())
 
-		// 将 request_id 注入到 context 供下游使用
-		ctx := logger.WithRequestID(c.Request.Context(), requestID)
+	// 分布式追踪中间件（未启用时为空操作）：沿用上游 traceparent，span 随请求 context 传递到存储层
+	router.Use(func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx, span := tracing.StartKind(ctx, trace.SpanKindServer, c.Request.Method,
+			attribute.String("http.request.method", c.Request.Method),
+			attribute.String("url.path", c.Request.URL.Path),
+			attribute.String("request_id", c.GetString("request_id")),
+		)
 		c.Request = c.Request.WithContext(ctx)
 		c.Next()
+
+		// 使用路由模板命名，避免路径参数导致 span 名基数爆炸
+		if route := c.FullPath(); route != "" {
+			span.SetName(c.Request.Method + " " + route)
+			span.SetAttributes(attribute.String("http.route", route))
+		}
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			tracing.RecordError(span, fmt.Errorf("HTTP %d", status))
+		}
+		span.End()
 	})
 
+	// 访问日志中间件（method、path、status、bytes、latency、缓存命中情况，带 request_id 与 trace_id）
+	router.Use(accessLogMiddleware())
+
 	// 强制 gzip 中间件（仅针对大响应 API，保护 4Mb 带宽）
 	// /api/status 响应约 300KB，未压缩会瞬间打满带宽
-	// 注意：仅对 /api/status 精确匹配，不影响 /api/status/query 等小响应接口
+	// 注意：仅对 /api/status 与 /api/{ns}/status 精确匹配，不影响 /api/status/query 等小响应接口
 	router.Use(func(c *gin.Context) {
 		path := c.Request.URL.Path
 
-		// 仅对 /api/status 精确匹配强制要求 gzip
-		if path == "/api/status" {
-			acceptEncoding := c.GetHeader("Accept-Encoding")
-			if !strings.Contains(acceptEncoding, "gzip") {
+		// 仅对 /api/status（含命名空间路由）精确匹配强制要求 gzip
+		if path == "/api/status" || c.FullPath() == "/api/:ns/status" {
+			if !acceptsGzip(c.GetHeader("Accept-Encoding")) {
 				c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{
 					"error": "This endpoint requires gzip support. Add header: Accept-Encoding: gzip",
 				})
@@ -125,8 +152,9 @@ func NewServer(store storage.Storage, cfg *config.AppConfig, port string) *Serve
 		c.Next()
 	})
 
-	// Gzip 压缩中间件
-	router.Use(gzip.Gzip(gzip.DefaultCompression))
+	// Gzip 压缩中间件（SSE 进度流除外：需要逐条 flush 且单独设置写超时）
+	// 缓存型 API 经 writeCached 直接输出预压缩结果，中间件透传不再重复压缩
+	router.Use(compressionMiddleware(regexp.MustCompile(`^/api/selftest/[^/]+/events$`)))
 
 	// 安全头中间件
 	router.Use(func(c *gin.Context) {
@@ -135,8 +163,8 @@ func NewServer(store storage.Storage, cfg *config.AppConfig, port string) *Serve
 		// HSTS（强制 HTTPS，有效期 1 年）- Cloudflare 提供 HTTPS
 		c.Header("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
 
-		// 防止点击劫持 - 对 /p/* 路径允许任意嵌入（iframe 友好）
-		if !strings.HasPrefix(path, "/p/") {
+		// 防止点击劫持 - 对 /p/* 与 /embed/* 路径允许任意嵌入（iframe 友好）
+		if !strings.HasPrefix(path, "/p/") && !strings.HasPrefix(path, "/embed/") {
 			c.Header("X-Frame-Options", "SAMEORIGIN")
 		}
 
@@ -156,21 +184,123 @@ func NewServer(store storage.Storage, cfg *config.AppConfig, port string) *Serve
 	router.GET("/api/status", handler.GetStatus)
 	router.GET("/api/status/query", handler.GetStatusQuery)
 	router.POST("/api/status/batch", handler.PostStatusBatch)
+	router.GET("/api/models", handler.GetModels)
+	router.GET("/api/providers/:slug", handler.GetProviderDetail)
+	router.GET("/api/providers/:slug/report", handler.GetProviderReport)
+	router.GET("/api/transparency", handler.GetTransparency)
+	router.GET("/api/transparency/proofs", handler.GetTransparencyProofs)
+	router.GET("/api/rankings", handler.GetRankings)
+	router.GET("/api/heatmap", handler.GetHeatmap)
+	router.GET("/api/sla", handler.GetSLA)
+
+	// 自定义分组（配置文件 groups 与管理 API 创建的分组）
+	router.GET("/api/groups", handler.GetGroups)
+	router.GET("/api/groups/:id/status", handler.GetGroupStatus)
+
+	// 状态查询 v2（services -> channels -> models，携带管理凭证时含下架/停用项）
+	router.GET("/api/v2/status/query", handler.GetStatusQueryV2)
+	router.POST("/api/v2/status/query", handler.PostStatusQueryV2)
 
 	// 事件 API 路由
 	router.GET("/api/events", handler.GetEvents)
 	router.GET("/api/events/latest", handler.GetLatestEventID)
 
+	// 用量统计 API 路由
+	router.GET("/api/usage", handler.GetUsage)
+
+	// 配置文件 JSON Schema（供编辑器校验与补全）
+	router.GET("/api/config/schema", handler.GetConfigSchema)
+
+	// 命名空间路由（/api/{ns}/*，仅返回该命名空间的监测项与事件，缓存与默认命名空间隔离）
+	router.GET("/api/:ns/status", handler.inNamespace((*Handler).GetStatus))
+	router.GET("/api/:ns/status/query", handler.inNamespace((*Handler).GetStatusQuery))
+	router.POST("/api/:ns/status/batch", handler.inNamespace((*Handler).PostStatusBatch))
+	router.GET("/api/v2/:ns/status/query", handler.inNamespace((*Handler).GetStatusQueryV2))
+	router.POST("/api/v2/:ns/status/query", handler.inNamespace((*Handler).PostStatusQueryV2))
+	router.GET("/api/:ns/models", handler.inNamespace((*Handler).GetModels))
+	router.GET("/api/:ns/providers/:slug", handler.inNamespace((*Handler).GetProviderDetail))
+	router.GET("/api/:ns/providers/:slug/report", handler.inNamespace((*Handler).GetProviderReport))
+	router.GET("/api/:ns/rankings", handler.inNamespace((*Handler).GetRankings))
+	router.GET("/api/:ns/heatmap", handler.inNamespace((*Handler).GetHeatmap))
+	router.GET("/api/:ns/events", handler.inNamespace((*Handler).GetEvents))
+	router.GET("/api/:ns/events/latest", handler.inNamespace((*Handler).GetLatestEventID))
+	router.GET("/api/:ns/groups", handler.inNamespace((*Handler).GetGroups))
+	router.GET("/api/:ns/groups/:id/status", handler.inNamespace((*Handler).GetGroupStatus))
+
+	// GraphQL 查询端点（需启用 graphql.enabled）
+	router.GET("/api/graphql", handler.PostGraphQL)
+	router.POST("/api/graphql", handler.PostGraphQL)
+
+	// 原始探测记录导出（需启用 export.enabled，大范围需 ADMIN_API_TOKEN）
+	router.GET("/api/export", handler.GetExport)
+
+	// 管理后台登录（本地账号，签发会话 Cookie）
+	router.POST("/api/admin/login", handler.PostAdminLogin)
+
+	// 管理 API 路由（需 ADMIN_API_TOKEN 或登录会话，按角色授权，调用均写入审计日志）
+	viewer := adminauth.RoleViewer
+	operator := adminauth.RoleOperator
+	adminRole := adminauth.RoleAdmin
+	admin := router.Group("/api/admin", handler.adminAuth)
+	admin.GET("/me", requireAdminRole(viewer), handler.GetAdminMe)
+	admin.POST("/logout", requireAdminRole(viewer), handler.PostAdminLogout)
+	admin.GET("/audit", requireAdminRole(adminRole), handler.GetAdminAudit)
+	admin.GET("/probe-debug", requireAdminRole(operator), handler.GetAdminProbeDebug)
+	admin.GET("/scheduler/tasks", requireAdminRole(viewer), handler.GetAdminSchedulerTasks)
+	admin.GET("/budgets", requireAdminRole(viewer), handler.GetAdminBudgets)
+	admin.GET("/ip-families", requireAdminRole(viewer), handler.GetAdminIPFamilies)
+	admin.GET("/overrides", requireAdminRole(viewer), handler.GetAdminOverrides)
+	admin.PATCH("/overrides", requireAdminRole(operator), handler.PatchAdminOverrides)
+	admin.GET("/webhook-dead-letters", requireAdminRole(viewer), handler.GetAdminWebhookDeadLetters)
+	admin.GET("/storage/diagnostics", requireAdminRole(viewer), handler.GetAdminStorageDiagnostics)
+	admin.GET("/runtime", requireAdminRole(viewer), handler.GetAdminRuntime)
+	// pprof 可能暴露内存中的敏感数据，仅 admin 角色可访问（需启用 diagnostics.pprof）
+	admin.GET("/debug/pprof/*name", requireAdminRole(adminRole), handler.GetAdminPprof)
+	admin.POST("/debug/pprof/*name", requireAdminRole(adminRole), handler.GetAdminPprof)
+	admin.GET("/storage/maintenance", requireAdminRole(viewer), handler.GetAdminStorageMaintenance)
+	admin.POST("/storage/maintenance/run", requireAdminRole(adminRole), handler.PostAdminStorageMaintenanceRun)
+	admin.GET("/provider-tokens", requireAdminRole(adminRole), handler.GetAdminProviderTokens)
+	admin.POST("/provider-tokens", requireAdminRole(adminRole), handler.PostAdminProviderToken)
+	admin.DELETE("/provider-tokens/:id", requireAdminRole(adminRole), handler.DeleteAdminProviderToken)
+	admin.GET("/annotations", requireAdminRole(viewer), handler.GetAdminAnnotations)
+	admin.POST("/annotations", requireAdminRole(operator), handler.PostAdminAnnotation)
+	admin.DELETE("/annotations/:id", requireAdminRole(operator), handler.DeleteAdminAnnotation)
+	admin.PUT("/groups/:id", requireAdminRole(operator), handler.PutAdminGroup)
+	admin.DELETE("/groups/:id", requireAdminRole(operator), handler.DeleteAdminGroup)
+	admin.GET("/metadata-requests", requireAdminRole(viewer), handler.GetAdminMetadataRequests)
+	admin.POST("/metadata-requests/:id/approve", requireAdminRole(operator), handler.PostAdminApproveMetadataRequest)
+	admin.POST("/metadata-requests/:id/reject", requireAdminRole(operator), handler.PostAdminRejectMetadataRequest)
+	admin.GET("/users", requireAdminRole(adminRole), handler.GetAdminUsers)
+	admin.POST("/users", requireAdminRole(adminRole), handler.PostAdminUser)
+	admin.PATCH("/users/:id", requireAdminRole(adminRole), handler.PatchAdminUser)
+	admin.DELETE("/users/:id", requireAdminRole(adminRole), handler.DeleteAdminUser)
+
+	// 服务商数据门户（服务商令牌鉴权，仅可访问令牌所属服务商的数据）
+	portal := router.Group("/api/provider-portal", handler.providerPortalAuth)
+	portal.GET("/status", handler.GetProviderPortalStatus)
+	portal.GET("/metadata", handler.GetProviderPortalMetadata)
+	portal.POST("/metadata", handler.PostProviderPortalMetadata)
+
 	// 自助测试 API 路由（如果启用）
 	router.POST("/api/selftest", handler.CreateSelfTest)
+	router.POST("/api/selftest/batch", handler.CreateSelfTestBatch)
+	router.GET("/api/selftest/batch/:id", handler.GetSelfTestBatch)
 	router.GET("/api/selftest/config", handler.GetSelfTestConfig)
+	router.GET("/api/selftest/challenge", handler.GetSelfTestChallenge)
 	router.GET("/api/selftest/types", handler.GetTestTypes)
+	router.GET("/api/selftest/results/:token", handler.GetSharedSelfTestResult)
 	router.GET("/api/selftest/:id", handler.GetSelfTest)
+	router.GET("/api/selftest/:id/events", handler.StreamSelfTest)
+	router.POST("/api/selftest/:id/share", handler.CreateSelfTestShare)
 
 	// SEO 路由
 	router.GET("/sitemap.xml", handler.GetSitemap)
+	router.GET("/feed.xml", handler.GetFeed)
 	router.GET("/robots.txt", handler.GetRobots)
 
+	// 嵌入组件（供服务商在自有页面 iframe 嵌入实时状态）
+	router.GET("/embed/:slug", handler.GetEmbedWidget)
+
 	// 版本信息 API
 	router.GET("/api/version", func(c *gin.Context) {
 		c.Header("Cache-Control", "no-store")
@@ -192,29 +322,75 @@ func NewServer(store storage.Storage, cfg *config.AppConfig, port string) *Serve
 	// 静态文件服务（前端）- 传递 handler 以支持动态 Meta 注入
 	setupStaticFiles(router, handler)
 
-	return &Server{
-		handler: handler,
-		router:  router,
-		port:    port,
+	srv := &Server{
+		handler:     handler,
+		router:      router,
+		listen:      cfg.Server,
+		diagnostics: cfg.Diagnostics,
+	}
+	if cfg.Server.TLS.Enabled() {
+		srv.certs = &certReloader{certFile: cfg.Server.TLS.CertFile, keyFile: cfg.Server.TLS.KeyFile}
 	}
+	return srv
 }
 
 // Start 启动服务器
 func (s *Server) Start() error {
 	s.httpServer = &http.Server{
-		Addr:         ":" + s.port,
 		Handler:      s.router,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
 
-	logger.Info("api", "监测服务已启动",
-		"web_ui", fmt.Sprintf("http://localhost:%s", s.port),
-		"api", fmt.Sprintf("http://localhost:%s/api/status", s.port),
-		"health", fmt.Sprintf("http://localhost:%s/health", s.port))
+	tlsCfg := s.listen.TLS
+	if s.certs != nil {
+		if err := s.certs.reload(); err != nil {
+			return fmt.Errorf("启动HTTPS服务失败: %w", err)
+		}
+		s.httpServer.TLSConfig = &tls.Config{
+			MinVersion:     tlsCfg.MinVersionValue,
+			GetCertificate: s.certs.getCertificate,
+		}
+	}
 
-	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	ln, err := listen(s.listen)
+	if err != nil {
+		return fmt.Errorf("启动HTTP服务失败: %w", err)
+	}
+
+	if s.diagnostics.Listen != "" {
+		if err := s.startDiagnostics(); err != nil {
+			ln.Close()
+			return err
+		}
+	}
+
+	if s.listen.UnixSocket != "" {
+		logger.Info("api", "监测服务已启动", "unix_socket", s.listen.UnixSocket, "tls", tlsCfg.Enabled())
+	} else {
+		scheme := "http"
+		if tlsCfg.Enabled() {
+			scheme = "https"
+		}
+		host := s.listen.Address
+		if host == "" || host == "0.0.0.0" || host == "::" {
+			host = "localhost"
+		}
+		base := fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, strconv.Itoa(s.listen.Port)))
+		logger.Info("api", "监测服务已启动",
+			"listen", ln.Addr().String(),
+			"web_ui", base,
+			"api", base+"/api/status",
+			"health", base+"/health")
+	}
+
+	if tlsCfg.Enabled() {
+		err = s.httpServer.ServeTLS(ln, "", "")
+	} else {
+		err = s.httpServer.Serve(ln)
+	}
+	if err != nil && err != http.ErrServerClosed {
 		return fmt.Errorf("启动HTTP服务失败: %w", err)
 	}
 
@@ -225,6 +401,11 @@ func (s *Server) Start() error {
 func (s *Server) Stop(ctx context.Context) error {
 	logger.Info("api", "正在关闭HTTP服务器")
 
+	if s.diagServer != nil {
+		if err := s.diagServer.Shutdown(ctx); err != nil {
+			logger.Warn("api", "关闭诊断端口失败", "error", err)
+		}
+	}
 	if s.httpServer != nil {
 		return s.httpServer.Shutdown(ctx)
 	}
@@ -232,6 +413,36 @@ func (s *Server) Stop(ctx context.Context) error {
 	return nil
 }
 
+// startDiagnostics 启动独立诊断端口（pprof、expvar 与运行时统计，不做鉴权）
+// 非回环地址需显式设置 allow_remote（配置校验已拒绝，这里再兜底一次）
+func (s *Server) startDiagnostics() error {
+	if !s.diagnostics.AllowRemote && !s.diagnostics.ListenIsLoopback() {
+		return fmt.Errorf("诊断端口 %s 不是本机回环地址且未设置 diagnostics.allow_remote，拒绝启动", s.diagnostics.Listen)
+	}
+	ln, err := net.Listen("tcp", s.diagnostics.Listen)
+	if err != nil {
+		return fmt.Errorf("启动诊断端口失败: %w", err)
+	}
+	// 不设置 WriteTimeout：CPU profile 与 trace 按 seconds 参数持续采集
+	s.diagServer = &http.Server{
+		Handler:           newDiagnosticsMux(s.handler),
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+	if !s.diagnostics.ListenIsLoopback() {
+		logger.Warn("api", "诊断端口已按 allow_remote 绑定非回环地址，pprof 与运行时统计不做鉴权，请确保仅内网可达",
+			"listen", ln.Addr().String())
+	}
+	logger.Info("api", "诊断端口已启动", "listen", ln.Addr().String(), "pprof", "/debug/pprof/")
+
+	go func() {
+		if err := s.diagServer.Serve(ln); err != nil && err != http.ErrServerClosed {
+			logger.Error("api", "诊断端口异常退出", "error", err)
+		}
+	}()
+	return nil
+}
+
 // UpdateConfig 更新配置（热更新时调用）
 func (s *Server) UpdateConfig(cfg *config.AppConfig) {
 	s.handler.UpdateConfig(cfg)
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/sla"
+)
+
+// SLAResponse 服务商 SLA 状态（GET /api/sla）
+type SLAResponse struct {
+	Providers []SLAItem `json:"providers"`
+	Meta      SLAMeta   `json:"meta"`
+}
+
+// SLAMeta SLA 状态元数据
+type SLAMeta struct {
+	Count            int     `json:"count"`
+	WarningThreshold float64 `json:"warning_threshold"` // 错误预算消耗预警比例
+}
+
+// SLAItem 单个服务商本周期的 SLA 状态
+type SLAItem struct {
+	Provider    string  `json:"provider"`
+	Target      float64 `json:"target"` // 可用率目标（百分比）
+	Period      string  `json:"period"` // monthly / weekly
+	PeriodStart int64   `json:"period_start"`
+	PeriodEnd   int64   `json:"period_end"`
+
+	Uptime                 float64 `json:"uptime"` // 本周期已统计时长内的可用率百分比，无数据时为 -1
+	DowntimeSeconds        int64   `json:"downtime_seconds"`
+	BudgetSeconds          int64   `json:"budget_seconds"`           // 整个周期的错误预算
+	BudgetRemainingSeconds int64   `json:"budget_remaining_seconds"` // 剩余错误预算（耗尽后为负）
+	BudgetRemaining        float64 `json:"budget_remaining"`         // 剩余错误预算百分比（耗尽后为负）
+	Samples                int     `json:"samples"`
+	State                  string  `json:"state"` // ok / warning / breached / no_data
+	EvaluatedAt            int64   `json:"evaluated_at"`
+}
+
+// SetSLAEvaluator 设置 SLA 评估任务（可选，用于 /api/sla）
+func (h *Handler) SetSLAEvaluator(e *sla.Evaluator) {
+	h.sla = e
+}
+
+// GetSLA 返回配置了 SLA 目标的服务商本周期错误预算
+// GET /api/sla?provider=xxx
+func (h *Handler) GetSLA(c *gin.Context) {
+	h.cfgMu.RLock()
+	cfg := h.config.SLA
+	visible := make(map[string]bool)
+	for _, m := range h.config.Monitors {
+		visible[strings.ToLower(m.Provider)] = true
+	}
+	h.cfgMu.RUnlock()
+
+	if h.sla == nil || !cfg.Enabled() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "未配置 SLA 目标"})
+		return
+	}
+
+	resp := buildSLAResponse(h.sla.Statuses(), visible, c.Query("provider"))
+	resp.Meta.WarningThreshold = cfg.WarningThreshold
+	c.JSON(http.StatusOK, resp)
+}
+
+// buildSLAResponse 转换评估结果，仅返回当前命名空间可见的服务商（provider 过滤不区分大小写）
+func buildSLAResponse(statuses []sla.Status, visible map[string]bool, provider string) SLAResponse {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	resp := SLAResponse{Providers: make([]SLAItem, 0, len(statuses))}
+	for _, st := range statuses {
+		key := strings.ToLower(st.Provider)
+		if !visible[key] || (provider != "" && key != provider) {
+			continue
+		}
+		item := SLAItem{
+			Provider:               st.Provider,
+			Target:                 st.Target,
+			Period:                 st.Period,
+			PeriodStart:            st.PeriodStart.Unix(),
+			PeriodEnd:              st.PeriodEnd.Unix(),
+			Uptime:                 st.Uptime,
+			DowntimeSeconds:        int64(st.Downtime.Seconds()),
+			BudgetSeconds:          int64(st.Budget.Seconds()),
+			BudgetRemainingSeconds: int64(st.BudgetRemaining.Seconds()),
+			BudgetRemaining:        roundTo((1-st.BudgetUsed)*100, 2),
+			Samples:                st.Samples,
+			State:                  st.State,
+			EvaluatedAt:            st.EvaluatedAt.Unix(),
+		}
+		if item.Uptime >= 0 {
+			item.Uptime = roundTo(item.Uptime, 4)
+		}
+		resp.Providers = append(resp.Providers, item)
+	}
+	resp.Meta.Count = len(resp.Providers)
+	return resp
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"monitor/internal/sla"
+)
+
+func TestBuildSLAResponse(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	statuses := []sla.Status{
+		{
+			Provider: "Relay", Target: 99.5, Period: "monthly", PeriodStart: now.Add(-time.Hour), PeriodEnd: now.Add(time.Hour),
+			Uptime: 99.123456, Downtime: 90 * time.Second, Budget: 600 * time.Second, BudgetRemaining: 510 * time.Second,
+			BudgetUsed: 0.15, Samples: 42, State: sla.StateOK, EvaluatedAt: now,
+		},
+		{Provider: "Hidden", Target: 99, Uptime: -1, State: sla.StateNoData},
+		{Provider: "Other", Target: 99, Uptime: -1, BudgetUsed: 0, State: sla.StateNoData},
+	}
+	visible := map[string]bool{"relay": true, "other": true}
+
+	resp := buildSLAResponse(statuses, visible, "")
+	if resp.Meta.Count != 2 || resp.Providers[0].Provider != "Relay" || resp.Providers[1].Provider != "Other" {
+		t.Fatalf("unexpected providers: %+v", resp.Providers)
+	}
+	first := resp.Providers[0]
+	if first.Uptime != 99.1235 || first.DowntimeSeconds != 90 || first.BudgetSeconds != 600 ||
+		first.BudgetRemainingSeconds != 510 || first.BudgetRemaining != 85 || first.Samples != 42 ||
+		first.PeriodStart != now.Add(-time.Hour).Unix() || first.EvaluatedAt != now.Unix() {
+		t.Fatalf("unexpected item: %+v", first)
+	}
+	if resp.Providers[1].Uptime != -1 {
+		t.Fatalf("no-data uptime should stay -1: %+v", resp.Providers[1])
+	}
+
+	filtered := buildSLAResponse(statuses, visible, " RELAY ")
+	if filtered.Meta.Count != 1 || filtered.Providers[0].Provider != "Relay" {
+		t.Fatalf("provider filter not applied: %+v", filtered)
+	}
+}
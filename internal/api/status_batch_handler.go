@@ -0,0 +1,209 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/baseline"
+	"monitor/internal/config"
+	"monitor/internal/logger"
+)
+
+// StatusBatchKey 按监测项精确查询的 key（用于 POST /api/status/batch 的 keys 模式）
+type StatusBatchKey struct {
+	Provider string `json:"provider"` // provider 名称或 slug（忽略大小写）
+	Service  string `json:"service"`
+	Channel  string `json:"channel,omitempty"` // 为空时匹配未配置 channel 的监测项
+}
+
+// postStatusBatchKeys 按 keys 返回指定监测项的状态与时间线（前端收藏夹使用）
+// 响应结构与 /api/status 的 data/groups 一致，仅包含匹配的监测项
+func (h *Handler) postStatusBatchKeys(c *gin.Context, period string, keys []StatusBatchKey) {
+	if period == "" {
+		period = "24h"
+	}
+
+	h.cfgMu.RLock()
+	maxRangeDays := h.config.MaxStatusRangeDays
+	maxKeys := h.config.BatchQueryMaxKeys
+	cacheTTL := h.config.CacheTTL.TTLForPeriod(period)
+	h.cfgMu.RUnlock()
+
+	if _, err := h.parsePeriod(period); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("无效的时间范围: %s", period)})
+		return
+	}
+	if days, ok := parsePeriodDays(period); ok && days > maxRangeDays {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("时间范围 %s 超出允许的最大天数 %d", period, maxRangeDays)})
+		return
+	}
+	if maxKeys > 0 && len(keys) > maxKeys {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("keys 最多支持 %d 个监测项", maxKeys)})
+		return
+	}
+
+	keys, err := normalizeStatusBatchKeys(keys)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 规范化后的 key 集合排序后取摘要，同一组收藏无论顺序都命中同一缓存
+	digest := sha256.New()
+	for _, k := range keys {
+		digest.Write([]byte(k.Provider + "\x00" + k.Service + "\x00" + k.Channel + "\n"))
+	}
+	cacheKey := fmt.Sprintf("batch|p=%s|keys=%s", period, hex.EncodeToString(digest.Sum(nil)))
+
+	reqCtx := context.WithoutCancel(c.Request.Context())
+	entry, err := h.cache.loadEntry(reqCtx, cacheKey, cacheTTL, func() ([]byte, error) {
+		ctx, cancel := context.WithTimeout(reqCtx, 30*time.Second)
+		defer cancel()
+		return h.queryStatusBatchKeys(ctx, period, keys)
+	})
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("PostStatusBatch 失败", "cache_key", cacheKey, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("查询失败: %v", err)})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	writeNegotiated(c, entry, pbStatusBatchResponse)
+}
+
+// normalizeStatusBatchKeys 去除首尾空格、统一小写并去重排序
+func normalizeStatusBatchKeys(keys []StatusBatchKey) ([]StatusBatchKey, error) {
+	seen := make(map[StatusBatchKey]bool, len(keys))
+	out := make([]StatusBatchKey, 0, len(keys))
+	for _, k := range keys {
+		k = StatusBatchKey{
+			Provider: strings.ToLower(strings.TrimSpace(k.Provider)),
+			Service:  strings.ToLower(strings.TrimSpace(k.Service)),
+			Channel:  strings.ToLower(strings.TrimSpace(k.Channel)),
+		}
+		if k.Provider == "" || k.Service == "" {
+			return nil, fmt.Errorf("keys 中的 provider 与 service 为必填字段")
+		}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		out = append(out, k)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return a.Channel < b.Channel
+	})
+	return out, nil
+}
+
+// queryStatusBatchKeys 查询匹配 keys 的监测项并序列化为 JSON（缓存 miss 时调用）
+// 普通监测项与多模型监测组的各层合并为一次 GetLatestBatch + GetHistoryBatch
+func (h *Handler) queryStatusBatchKeys(ctx context.Context, period string, keys []StatusBatchKey) ([]byte, error) {
+	startTime, endTime := h.parseTimeRangeIn(period, "", time.UTC)
+
+	h.cfgMu.RLock()
+	monitors := h.config.Monitors
+	degradedWeight := h.config.DegradedWeight
+	enableDBTimelineAgg := h.config.EnableDBTimelineAgg
+	batchQueryMaxKeys := h.config.BatchQueryMaxKeys
+	slowLatencyMs := int(h.config.SlowLatencyDuration / time.Millisecond)
+	enableBadges := h.config.EnableBadges
+	h.cfgMu.RUnlock()
+
+	// 收藏不受板块限制，但与 /api/status 一样排除禁用与隐藏的监测项
+	wanted := make(map[StatusBatchKey]bool, len(keys))
+	for _, k := range keys {
+		wanted[k] = false
+	}
+	match := func(task config.ServiceConfig) bool {
+		service := strings.ToLower(strings.TrimSpace(task.Service))
+		channel := strings.ToLower(strings.TrimSpace(task.Channel))
+		matched := false
+		for _, provider := range []string{strings.ToLower(strings.TrimSpace(task.Provider)), strings.ToLower(task.ProviderSlug)} {
+			k := StatusBatchKey{Provider: provider, Service: service, Channel: channel}
+			if _, ok := wanted[k]; ok {
+				wanted[k] = true
+				matched = true
+			}
+		}
+		return matched
+	}
+
+	var plain, layered []config.ServiceConfig
+	for _, task := range h.filterMonitors(monitors, "all", "all", "all", false, false) {
+		if strings.TrimSpace(task.Model) == "" && match(task) {
+			plain = append(plain, task)
+		}
+	}
+	for _, task := range h.filterMonitorsForGroups(monitors, "all", "all", "all", false, false) {
+		if strings.TrimSpace(task.Model) != "" && match(task) {
+			layered = append(layered, task)
+		}
+	}
+
+	// 按 batch_query_max_keys 分批，避免单条 SQL 参数过多（keys 数量已受同一上限约束，通常只需一批）
+	tasks := append(append(make([]config.ServiceConfig, 0, len(plain)+len(layered)), plain...), layered...)
+	if batchQueryMaxKeys <= 0 {
+		batchQueryMaxKeys = max(len(tasks), 1)
+	}
+	results := make([]MonitorResult, 0, len(tasks))
+	for start := 0; start < len(tasks); start += batchQueryMaxKeys {
+		end := min(start+batchQueryMaxKeys, len(tasks))
+		part, err := h.getStatusBatch(ctx, tasks[start:end], startTime, endTime, period, degradedWeight, nil, enableBadges, enableDBTimelineAgg)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, part...)
+	}
+
+	data := results[:len(plain)]
+	groups := h.assembleMonitorGroups(layered, results[len(plain):], endTime, period, degradedWeight, nil, enableBadges)
+
+	if annotations := h.loadAnnotations(ctx, startTime.Unix(), endTime.Unix()); len(annotations) > 0 {
+		attachAnnotations(data, groups, annotations)
+	}
+	h.attachCorrelations(ctx, baseline.NewIndex(monitors), data, groups)
+
+	notFound := make([]StatusBatchKey, 0)
+	for _, k := range keys {
+		if !wanted[k] {
+			notFound = append(notFound, k)
+		}
+	}
+
+	timelineMode := "aggregated"
+	if period == "90m" {
+		timelineMode = "raw"
+	}
+
+	logger.Info("api", "PostStatusBatch 查询完成", "keys", len(keys), "monitors", len(plain), "layered", len(layered), "period", period)
+
+	return json.Marshal(gin.H{
+		"meta": gin.H{
+			"period":          period,
+			"timeline_mode":   timelineMode,
+			"count":           len(data),
+			"slow_latency_ms": slowLatencyMs,
+			"enable_badges":   enableBadges,
+			"not_found":       notFound,
+		},
+		"data":   data,
+		"groups": groups,
+	})
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestPostStatusBatchKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newTestSQLiteStore(t)
+
+	now := time.Now().Unix()
+	for _, rec := range []*storage.ProbeRecord{
+		{Provider: "Relay", Service: "cc", Channel: "vip", Status: 1, Latency: 120, Timestamp: now - 60},
+		{Provider: "Relay", Service: "cx", Channel: "m", Model: "gpt", Status: 2, Latency: 300, Timestamp: now - 60},
+		{Provider: "Other", Service: "cc", Status: 0, Timestamp: now - 60},
+	} {
+		if err := store.SaveRecord(rec); err != nil {
+			t.Fatalf("save record: %v", err)
+		}
+	}
+
+	h := NewHandler(store, &config.AppConfig{
+		MaxStatusRangeDays: 180,
+		BatchQueryMaxKeys:  3,
+		Monitors: []config.ServiceConfig{
+			{Provider: "Relay", ProviderSlug: "relay-slug", Service: "cc", Channel: "vip", Board: "cold"},
+			{Provider: "Relay", ProviderSlug: "relay-slug", Service: "cc", Channel: "std"},
+			{Provider: "Relay", ProviderSlug: "relay-slug", Service: "cx", Channel: "m", Model: "gpt"},
+			{Provider: "Relay", ProviderSlug: "relay-slug", Service: "cx", Channel: "m", Model: "mini", Parent: "relay/cx/m"},
+			{Provider: "Other", ProviderSlug: "other", Service: "cc", Hidden: true},
+		},
+	})
+	router := gin.New()
+	router.POST("/api/status/batch", h.PostStatusBatch)
+
+	serve := func(body string) (int, string) {
+		req := httptest.NewRequest(http.MethodPost, "/api/status/batch", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code, w.Body.String()
+	}
+
+	// provider 支持 slug，不受板块限制；隐藏监测项与不存在的 key 进入 not_found
+	code, body := serve(`{"period":"24h","keys":[{"provider":"relay-slug","service":"CC","channel":"vip"},{"provider":"Relay","service":"cx","channel":"m"},{"provider":"other","service":"cc"}]}`)
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", code, body)
+	}
+	var resp struct {
+		Meta struct {
+			Period   string           `json:"period"`
+			Count    int              `json:"count"`
+			NotFound []StatusBatchKey `json:"not_found"`
+		} `json:"meta"`
+		Data   []MonitorResult `json:"data"`
+		Groups []MonitorGroup  `json:"groups"`
+	}
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Meta.Period != "24h" || resp.Meta.Count != 1 || len(resp.Data) != 1 || resp.Data[0].Channel != "vip" {
+		t.Fatalf("unexpected data: %s", body)
+	}
+	if resp.Data[0].Current == nil || resp.Data[0].Current.Status != 1 || len(resp.Data[0].Timeline) == 0 {
+		t.Fatalf("expected current status and timeline: %s", body)
+	}
+	if len(resp.Groups) != 1 || len(resp.Groups[0].Layers) != 2 || resp.Groups[0].Layers[0].CurrentStatus.Status != 2 {
+		t.Fatalf("unexpected groups: %s", body)
+	}
+	if len(resp.Meta.NotFound) != 1 || resp.Meta.NotFound[0].Provider != "other" {
+		t.Fatalf("expected hidden monitor in not_found: %s", body)
+	}
+
+	// 旧的 queries 模式保持不变
+	code, body = serve(`{"queries":[{"provider":"Relay","service":"cc","channel":"vip"}]}`)
+	if code != http.StatusOK || !strings.Contains(body, `"status":"up"`) {
+		t.Fatalf("legacy queries mode broken: %d %s", code, body)
+	}
+
+	for _, bad := range []string{
+		`{"keys":[{"provider":"a","service":"b"},{"provider":"c","service":"d"},{"provider":"e","service":"f"},{"provider":"g","service":"h"}]}`,
+		`{"keys":[{"provider":"relay"}]}`,
+		`{"period":"1y","keys":[{"provider":"relay","service":"cc"}]}`,
+		`{"keys":[{"provider":"relay","service":"cc"}],"queries":[{"provider":"relay"}]}`,
+		`{}`,
+	} {
+		if code, body := serve(bad); code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d: %s", bad, code, body)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"sort"
@@ -18,8 +19,11 @@ import (
 // ===== 请求/响应结构体 =====
 
 // StatusQueryRequest 批量状态查询请求（用于 POST /api/status/batch）
+// queries 与 keys 二选一：queries 返回当前状态，keys 返回指定监测项的状态与时间线
 type StatusQueryRequest struct {
-	Queries []StatusQuery `json:"queries"`
+	Queries []StatusQuery    `json:"queries"`
+	Keys    []StatusBatchKey `json:"keys"`
+	Period  string           `json:"period,omitempty"` // 仅 keys 模式，默认 24h
 }
 
 // StatusQuery 单个查询条件
@@ -132,6 +136,9 @@ func (h *Handler) GetStatusQuery(c *gin.Context) {
 // PostStatusBatch POST /api/status/batch
 // Body: {"queries":[{"provider":"X","service":"Y","channel":"Z"}, ...]}
 // 最多支持 50 组查询
+//
+// Body: {"period":"7d","keys":[{"provider":"X","service":"Y","channel":"Z"}, ...]}
+// 返回指定监测项的状态与时间线（结构同 /api/status），最多 batch_query_max_keys 个
 func (h *Handler) PostStatusBatch(c *gin.Context) {
 	var req StatusQueryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -139,8 +146,17 @@ func (h *Handler) PostStatusBatch(c *gin.Context) {
 		return
 	}
 
+	if len(req.Keys) > 0 {
+		if len(req.Queries) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "queries 与 keys 不能同时使用"})
+			return
+		}
+		h.postStatusBatchKeys(c, strings.TrimSpace(req.Period), req.Keys)
+		return
+	}
+
 	if len(req.Queries) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "queries 不能为空"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "queries 或 keys 不能为空"})
 		return
 	}
 	if len(req.Queries) > maxQueryPOST {
@@ -168,9 +184,14 @@ func (h *Handler) PostStatusBatch(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("查询失败: %v", err)})
 		return
 	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("序列化失败: %v", err)})
+		return
+	}
 
 	c.Header("Cache-Control", "no-store")
-	c.JSON(http.StatusOK, resp)
+	writeNegotiated(c, &cacheEntry{data: data}, pbStatusQueryResponse)
 }
 
 // ===== 内部方法 =====
@@ -0,0 +1,491 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// ===== v2 请求/响应结构体 =====
+//
+// 与 v1 的区别：
+// - 层级为 services -> channels -> models，每个 model 返回自身状态
+// - board 为完整枚举（hot/secondary/cold），channel 级取其下最活跃的 model
+// - 无数据时 status 为 "unknown"（v1 为 "down"）
+// - 错误（含请求级错误）统一为 {"code","message"}，code 为稳定枚举
+// - 携带管理凭证（ADMIN_API_TOKEN 或管理后台会话）时包含已下架/已停用的监测项及原因
+
+// statusQueryV2Version v2 响应中的 api_version
+const statusQueryV2Version = "v2"
+
+// v2 稳定错误码（只增不改）
+const (
+	statusQueryCodeInvalidRequest   = "INVALID_REQUEST"
+	statusQueryCodeTooManyQueries   = "TOO_MANY_QUERIES"
+	statusQueryCodeUnauthorized     = "UNAUTHORIZED"
+	statusQueryCodeForbidden        = "FORBIDDEN"
+	statusQueryCodeAuthUnavailable  = "AUTH_UNAVAILABLE"
+	statusQueryCodeQueryFailed      = "QUERY_FAILED"
+	statusQueryCodeProviderNotFound = "PROVIDER_NOT_FOUND"
+	statusQueryCodeServiceNotFound  = "SERVICE_NOT_FOUND"
+	statusQueryCodeChannelNotFound  = "CHANNEL_NOT_FOUND"
+)
+
+// StatusQueryV2Request 批量查询请求（用于 POST /api/v2/status/query）
+type StatusQueryV2Request struct {
+	Queries []StatusQuery `json:"queries"`
+}
+
+// StatusQueryV2Response v2 状态查询响应
+type StatusQueryV2Response struct {
+	APIVersion string                `json:"api_version"`
+	AsOf       string                `json:"as_of"`
+	Authorized bool                  `json:"authorized"` // 是否按管理凭证返回（含下架/停用项）
+	Results    []StatusQueryV2Result `json:"results"`
+}
+
+// StatusQueryV2Result 单个查询的返回结果
+type StatusQueryV2Result struct {
+	Query        StatusQuery             `json:"query"`
+	Provider     string                  `json:"provider,omitempty"` // 原始标识
+	ProviderName string                  `json:"provider_name,omitempty"`
+	ProviderSlug string                  `json:"provider_slug,omitempty"`
+	Services     []StatusQueryV2Service  `json:"services,omitempty"`
+	Error        *StatusQueryErrorObject `json:"error,omitempty"`
+}
+
+// StatusQueryV2Service 单个 service 的结果
+type StatusQueryV2Service struct {
+	Name        string                 `json:"name"` // 原始标识
+	ServiceName string                 `json:"service_name,omitempty"`
+	Channels    []StatusQueryV2Channel `json:"channels"`
+}
+
+// StatusQueryV2Channel 单个 channel 的结果（status/latency 取其下未停用 model 的最差状态）
+type StatusQueryV2Channel struct {
+	Name        string               `json:"name"` // 原始标识（可能为空字符串）
+	ChannelName string               `json:"channel_name,omitempty"`
+	Status      string               `json:"status"` // up/down/degraded/unknown
+	LatencyMs   int                  `json:"latency_ms,omitempty"`
+	UpdatedAt   string               `json:"updated_at,omitempty"`
+	Board       string               `json:"board"` // hot/secondary/cold
+	Models      []StatusQueryV2Model `json:"models"`
+}
+
+// StatusQueryV2Model 单个 model（监测项）的结果
+type StatusQueryV2Model struct {
+	Name           string `json:"name"`   // 原始标识（可能为空字符串）
+	Status         string `json:"status"` // up/down/degraded/unknown
+	SubStatus      string `json:"sub_status,omitempty"`
+	LatencyMs      int    `json:"latency_ms,omitempty"`
+	UpdatedAt      string `json:"updated_at,omitempty"`
+	Board          string `json:"board"` // hot/secondary/cold
+	ColdReason     string `json:"cold_reason,omitempty"`
+	Hidden         bool   `json:"hidden,omitempty"`
+	HiddenReason   string `json:"hidden_reason,omitempty"`
+	Disabled       bool   `json:"disabled,omitempty"`
+	DisabledReason string `json:"disabled_reason,omitempty"`
+}
+
+// ===== Handler 方法 =====
+
+// GetStatusQueryV2 GET /api/v2/status/query
+// 参数同 v1：provider/service/channel 单查，或 q=provider/service/channel 多查（最多 20 组）
+func (h *Handler) GetStatusQueryV2(c *gin.Context) {
+	authorized, ok := h.statusQueryV2Auth(c)
+	if !ok {
+		return
+	}
+
+	var queries []StatusQuery
+	if rawQs := c.QueryArray("q"); len(rawQs) > 0 {
+		if len(rawQs) > maxQueryGET {
+			writeStatusQueryV2Error(c, http.StatusBadRequest, statusQueryCodeTooManyQueries, fmt.Sprintf("q 最多支持 %d 组查询", maxQueryGET))
+			return
+		}
+		for _, raw := range rawQs {
+			q, err := parsePackedQuery(raw)
+			if err != nil {
+				writeStatusQueryV2Error(c, http.StatusBadRequest, statusQueryCodeInvalidRequest, err.Error())
+				return
+			}
+			queries = append(queries, q)
+		}
+	} else {
+		provider := strings.TrimSpace(c.Query("provider"))
+		if provider == "" {
+			writeStatusQueryV2Error(c, http.StatusBadRequest, statusQueryCodeInvalidRequest, "provider 为必填参数（或使用 q=provider/service/channel）")
+			return
+		}
+		queries = []StatusQuery{{
+			Provider: provider,
+			Service:  strings.TrimSpace(c.Query("service")),
+			Channel:  strings.TrimSpace(c.Query("channel")),
+		}}
+	}
+
+	h.respondStatusQueryV2(c, queries, authorized, 10*time.Second)
+}
+
+// PostStatusQueryV2 POST /api/v2/status/query
+// Body: {"queries":[{"provider":"X","service":"Y","channel":"Z"}, ...]}，最多 50 组查询
+func (h *Handler) PostStatusQueryV2(c *gin.Context) {
+	authorized, ok := h.statusQueryV2Auth(c)
+	if !ok {
+		return
+	}
+
+	var req StatusQueryV2Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeStatusQueryV2Error(c, http.StatusBadRequest, statusQueryCodeInvalidRequest, fmt.Sprintf("无效的 JSON: %v", err))
+		return
+	}
+	if len(req.Queries) == 0 {
+		writeStatusQueryV2Error(c, http.StatusBadRequest, statusQueryCodeInvalidRequest, "queries 不能为空")
+		return
+	}
+	if len(req.Queries) > maxQueryPOST {
+		writeStatusQueryV2Error(c, http.StatusBadRequest, statusQueryCodeTooManyQueries, fmt.Sprintf("queries 最多支持 %d 组查询", maxQueryPOST))
+		return
+	}
+	for i := range req.Queries {
+		req.Queries[i].Provider = strings.TrimSpace(req.Queries[i].Provider)
+		req.Queries[i].Service = strings.TrimSpace(req.Queries[i].Service)
+		req.Queries[i].Channel = strings.TrimSpace(req.Queries[i].Channel)
+		if req.Queries[i].Provider == "" {
+			writeStatusQueryV2Error(c, http.StatusBadRequest, statusQueryCodeInvalidRequest, "provider 为必填字段")
+			return
+		}
+	}
+
+	h.respondStatusQueryV2(c, req.Queries, authorized, 20*time.Second)
+}
+
+// statusQueryV2Auth 携带 Authorization 时按管理凭证校验（失败直接返回错误并写入审计日志），未携带时按匿名处理
+func (h *Handler) statusQueryV2Auth(c *gin.Context) (authorized bool, ok bool) {
+	if c.GetHeader("Authorization") == "" {
+		return false, true
+	}
+	principal, status, msg := h.authenticateAdmin(c)
+	if principal == nil {
+		code := statusQueryCodeForbidden
+		switch status {
+		case http.StatusUnauthorized:
+			code = statusQueryCodeUnauthorized
+		case http.StatusServiceUnavailable:
+			code = statusQueryCodeAuthUnavailable
+		case http.StatusInternalServerError:
+			code = statusQueryCodeQueryFailed
+		}
+		writeStatusQueryV2Error(c, status, code, msg)
+		h.recordAdminCall(c, "anonymous")
+		return false, false
+	}
+	h.recordAdminCall(c, principal.Actor)
+	return true, true
+}
+
+func (h *Handler) respondStatusQueryV2(c *gin.Context, queries []StatusQuery, authorized bool, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	resp, err := h.executeStatusQueryV2(ctx, queries, authorized)
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("StatusQueryV2 失败", "error", err)
+		writeStatusQueryV2Error(c, http.StatusInternalServerError, statusQueryCodeQueryFailed, fmt.Sprintf("查询失败: %v", err))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
+
+// writeStatusQueryV2Error 输出 v2 请求级错误：{"error":{"code","message"}}
+func writeStatusQueryV2Error(c *gin.Context, status int, code, message string) {
+	c.JSON(status, gin.H{"error": StatusQueryErrorObject{Code: code, Message: message}})
+}
+
+// ===== 内部方法 =====
+
+// v2ChannelTarget channel 下的监测项配置（按 model 去重）
+type v2ChannelTarget struct {
+	name     string
+	monitors []config.ServiceConfig
+}
+
+// v2ServiceTarget service 下的 channel 列表
+type v2ServiceTarget struct {
+	name     string
+	monitors []config.ServiceConfig // 该 service 下全部监测项（用于显示名）
+	channels []v2ChannelTarget
+}
+
+// executeStatusQueryV2 执行 v2 状态查询
+func (h *Handler) executeStatusQueryV2(ctx context.Context, queries []StatusQuery, authorized bool) (*StatusQueryV2Response, error) {
+	h.cfgMu.RLock()
+	monitors := h.config.Monitors
+	boardsEnabled := h.config.Boards.Enabled
+	h.cfgMu.RUnlock()
+
+	store := h.storage.WithContext(ctx)
+
+	results := make([]StatusQueryV2Result, 0, len(queries))
+	for _, q := range queries {
+		provider, targets, queryErr := expandQueryTargetsV2(monitors, authorized, q)
+		if queryErr != nil {
+			results = append(results, StatusQueryV2Result{Query: q, Error: queryErr})
+			continue
+		}
+
+		result := StatusQueryV2Result{
+			Query:        q,
+			Provider:     provider.Provider,
+			ProviderName: provider.ProviderName,
+			ProviderSlug: provider.ProviderSlug,
+			Services:     make([]StatusQueryV2Service, 0, len(targets)),
+		}
+		if result.ProviderSlug == "" {
+			result.ProviderSlug = strings.ToLower(strings.TrimSpace(provider.Provider))
+		}
+
+		for _, target := range targets {
+			svc := StatusQueryV2Service{
+				Name:     target.name,
+				Channels: make([]StatusQueryV2Channel, 0, len(target.channels)),
+			}
+			for _, m := range target.monitors {
+				if m.ServiceName != "" {
+					svc.ServiceName = m.ServiceName
+					break
+				}
+			}
+
+			for _, ch := range target.channels {
+				chResult := StatusQueryV2Channel{
+					Name:   ch.name,
+					Models: make([]StatusQueryV2Model, 0, len(ch.monitors)),
+				}
+				worstStatus := -1
+				var worstRecord *storage.ProbeRecord
+				channelBoard := ""
+				for _, m := range ch.monitors {
+					if err := ctx.Err(); err != nil {
+						return nil, fmt.Errorf("查询超时或取消: %w", err)
+					}
+					if chResult.ChannelName == "" {
+						chResult.ChannelName = m.ChannelName
+					}
+
+					latest, err := store.GetLatest(m.Provider, m.Service, m.Channel, m.Model)
+					if err != nil {
+						return nil, fmt.Errorf("查询失败(provider=%s service=%s channel=%s model=%s): %w",
+							m.Provider, m.Service, m.Channel, m.Model, err)
+					}
+
+					board := statusQueryV2Board(m, boardsEnabled)
+					model := StatusQueryV2Model{
+						Name:   strings.TrimSpace(m.Model),
+						Status: statusQueryV2Status(-1),
+						Board:  board,
+					}
+					if board == "cold" {
+						model.ColdReason = m.ColdReason
+					}
+					if authorized {
+						model.Hidden, model.HiddenReason = m.Hidden, m.HiddenReason
+						model.Disabled, model.DisabledReason = m.Disabled, m.DisabledReason
+					}
+					status := -1
+					if latest != nil {
+						status = latest.Status
+						model.Status = statusQueryV2Status(latest.Status)
+						model.SubStatus = string(latest.SubStatus)
+						model.LatencyMs = latest.Latency
+						model.UpdatedAt = time.Unix(latest.Timestamp, 0).UTC().Format(time.RFC3339)
+					}
+					chResult.Models = append(chResult.Models, model)
+
+					// 已停用的监测项不参与 channel 级状态与板块折叠
+					if m.Disabled {
+						continue
+					}
+					if boardRank(board) > boardRank(channelBoard) {
+						channelBoard = board
+					}
+					newWorst := pickWorstStatus(worstStatus, status)
+					if newWorst != worstStatus {
+						worstStatus = newWorst
+						worstRecord = latest
+						continue
+					}
+					// 同一严重程度时，优先选择更新时间更近的记录作为展示信息
+					if latest != nil && worstRecord != nil && latest.Status == worstRecord.Status && latest.Timestamp > worstRecord.Timestamp {
+						worstRecord = latest
+					}
+				}
+
+				chResult.Status = statusQueryV2Status(worstStatus)
+				chResult.Board = channelBoard
+				if chResult.Board == "" {
+					// 全部停用时沿用首个监测项的板块
+					chResult.Board = statusQueryV2Board(ch.monitors[0], boardsEnabled)
+				}
+				if worstRecord != nil {
+					chResult.LatencyMs = worstRecord.Latency
+					chResult.UpdatedAt = time.Unix(worstRecord.Timestamp, 0).UTC().Format(time.RFC3339)
+				}
+				svc.Channels = append(svc.Channels, chResult)
+			}
+			result.Services = append(result.Services, svc)
+		}
+		results = append(results, result)
+	}
+
+	return &StatusQueryV2Response{
+		APIVersion: statusQueryV2Version,
+		AsOf:       time.Now().UTC().Format(time.RFC3339),
+		Authorized: authorized,
+		Results:    results,
+	}, nil
+}
+
+// expandQueryTargetsV2 根据配置展开查询目标（provider/service/channel 忽略大小写，service/channel 为空时展开全部）
+// 未授权时排除已停用与已下架的监测项（与 /api/status 的可见性一致，不泄露其存在）
+// 返回 provider 的首个匹配配置（用于显示名与 slug）；结果按 service/channel/model 名称排序
+func expandQueryTargetsV2(monitors []config.ServiceConfig, authorized bool, q StatusQuery) (config.ServiceConfig, []v2ServiceTarget, *StatusQueryErrorObject) {
+	queryProvider := strings.ToLower(strings.TrimSpace(q.Provider))
+	queryService := strings.ToLower(strings.TrimSpace(q.Service))
+	queryChannel := strings.ToLower(strings.TrimSpace(q.Channel))
+
+	var provider config.ServiceConfig
+	services := make(map[string]*v2ServiceTarget)                 // service lower -> target
+	channels := make(map[string]map[string]*v2ChannelTarget)      // service lower -> channel lower -> target
+	seenModels := make(map[string]map[string]map[string]struct{}) // service -> channel -> model（lower）
+	found := false
+	for _, m := range monitors {
+		if strings.ToLower(strings.TrimSpace(m.Provider)) != queryProvider {
+			continue
+		}
+		if !authorized && (m.Disabled || m.Hidden) {
+			continue
+		}
+		if !found {
+			provider, found = m, true
+		}
+
+		svcLower := strings.ToLower(strings.TrimSpace(m.Service))
+		chLower := strings.ToLower(strings.TrimSpace(m.Channel))
+		modelLower := strings.ToLower(strings.TrimSpace(m.Model))
+		svc, ok := services[svcLower]
+		if !ok {
+			svc = &v2ServiceTarget{name: m.Service}
+			services[svcLower] = svc
+			channels[svcLower] = make(map[string]*v2ChannelTarget)
+			seenModels[svcLower] = make(map[string]map[string]struct{})
+		}
+		svc.monitors = append(svc.monitors, m)
+		ch, ok := channels[svcLower][chLower]
+		if !ok {
+			ch = &v2ChannelTarget{name: m.Channel}
+			channels[svcLower][chLower] = ch
+			seenModels[svcLower][chLower] = make(map[string]struct{})
+		}
+		if _, dup := seenModels[svcLower][chLower][modelLower]; dup {
+			continue
+		}
+		seenModels[svcLower][chLower][modelLower] = struct{}{}
+		ch.monitors = append(ch.monitors, m)
+	}
+	if !found {
+		return provider, nil, &StatusQueryErrorObject{Code: statusQueryCodeProviderNotFound, Message: "provider 不存在"}
+	}
+
+	var svcKeys []string
+	if queryService != "" {
+		if _, ok := services[queryService]; !ok {
+			return provider, nil, &StatusQueryErrorObject{Code: statusQueryCodeServiceNotFound, Message: "service 不存在"}
+		}
+		svcKeys = []string{queryService}
+	} else {
+		for k := range services {
+			svcKeys = append(svcKeys, k)
+		}
+		sort.Strings(svcKeys)
+	}
+
+	targets := make([]v2ServiceTarget, 0, len(svcKeys))
+	for _, svcKey := range svcKeys {
+		svc := *services[svcKey]
+		var chKeys []string
+		if queryChannel != "" {
+			if _, ok := channels[svcKey][queryChannel]; !ok {
+				return provider, nil, &StatusQueryErrorObject{Code: statusQueryCodeChannelNotFound, Message: "channel 不存在"}
+			}
+			chKeys = []string{queryChannel}
+		} else {
+			for k := range channels[svcKey] {
+				chKeys = append(chKeys, k)
+			}
+			sort.Strings(chKeys)
+		}
+		for _, chKey := range chKeys {
+			ch := *channels[svcKey][chKey]
+			sort.SliceStable(ch.monitors, func(i, j int) bool {
+				return strings.ToLower(ch.monitors[i].Model) < strings.ToLower(ch.monitors[j].Model)
+			})
+			svc.channels = append(svc.channels, ch)
+		}
+		targets = append(targets, svc)
+	}
+	return provider, targets, nil
+}
+
+// statusQueryV2Board 返回监测项的板块（未启用板块功能时均为 hot）
+func statusQueryV2Board(m config.ServiceConfig, boardsEnabled bool) string {
+	if !boardsEnabled {
+		return "hot"
+	}
+	switch board := strings.ToLower(strings.TrimSpace(m.Board)); board {
+	case "secondary", "cold":
+		return board
+	default:
+		return "hot"
+	}
+}
+
+// boardRank 板块活跃度排序（hot > secondary > cold，空值最低）
+func boardRank(board string) int {
+	switch board {
+	case "hot":
+		return 3
+	case "secondary":
+		return 2
+	case "cold":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// statusQueryV2Status 将状态码转换为 v2 状态字符串（无数据为 unknown）
+func statusQueryV2Status(status int) string {
+	switch status {
+	case 1:
+		return "up"
+	case 0:
+		return "down"
+	case 2:
+		return "degraded"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestStatusQueryV2(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	now := time.Now().Unix()
+	for _, r := range []*storage.ProbeRecord{
+		{Provider: "Relay", Service: "cc", Channel: "vip", Model: "opus", Status: 1, Latency: 120, Timestamp: now - 60},
+		{Provider: "Relay", Service: "cc", Channel: "vip", Model: "sonnet", Status: 2, SubStatus: storage.SubStatusSlowLatency, Latency: 900, Timestamp: now - 30},
+	} {
+		if err := store.SaveRecord(r); err != nil {
+			t.Fatalf("save record: %v", err)
+		}
+	}
+
+	srv := NewServer(store, &config.AppConfig{
+		Audit:  config.AuditConfig{APIToken: "admin-token"},
+		Boards: config.BoardsConfig{Enabled: true},
+		Monitors: []config.ServiceConfig{
+			{Provider: "Relay", ProviderName: "Relay 中转", Service: "cc", Channel: "vip", ChannelName: "VIP", Model: "opus", Board: "secondary"},
+			{Provider: "Relay", Service: "cc", Channel: "vip", Model: "sonnet", Board: "cold", ColdReason: "低活跃"},
+			{Provider: "Relay", Service: "cc", Channel: "vip", Model: "haiku", Hidden: true, HiddenReason: "维护中"},
+			{Provider: "Relay", Service: "cx", Channel: "old", Disabled: true, DisabledReason: "已停运"},
+		},
+	})
+	serve := func(method, target, body, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		srv.router.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder) StatusQueryV2Response {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp StatusQueryV2Response
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return resp
+	}
+
+	// 匿名：不含下架/停用项，不返回原因
+	resp := decode(serve(http.MethodGet, "/api/v2/status/query?q=relay", "", ""))
+	if resp.APIVersion != "v2" || resp.Authorized || len(resp.Results) != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	res := resp.Results[0]
+	if res.ProviderName != "Relay 中转" || res.ProviderSlug != "relay" || len(res.Services) != 1 {
+		t.Fatalf("disabled service should be excluded for anonymous callers: %+v", res)
+	}
+	ch := res.Services[0].Channels[0]
+	if ch.ChannelName != "VIP" || ch.Status != "degraded" || ch.Board != "secondary" || ch.LatencyMs != 900 || len(ch.Models) != 2 {
+		t.Fatalf("unexpected channel: %+v", ch)
+	}
+	opus, sonnet := ch.Models[0], ch.Models[1]
+	if opus.Name != "opus" || opus.Status != "up" || opus.Board != "secondary" {
+		t.Fatalf("unexpected opus: %+v", opus)
+	}
+	if sonnet.SubStatus != string(storage.SubStatusSlowLatency) || sonnet.Board != "cold" || sonnet.ColdReason != "低活跃" {
+		t.Fatalf("unexpected sonnet: %+v", sonnet)
+	}
+
+	// 授权：包含下架/停用项与原因，停用项不参与 channel 状态
+	resp = decode(serve(http.MethodPost, "/api/v2/status/query", `{"queries":[{"provider":"relay"},{"provider":"relay","service":"cc","channel":"missing"},{"provider":"nobody"}]}`, "admin-token"))
+	if !resp.Authorized || len(resp.Results) != 3 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if svcs := resp.Results[0].Services; len(svcs) != 2 || len(svcs[0].Channels[0].Models) != 3 {
+		t.Fatalf("authorized callers should see hidden and disabled monitors: %+v", svcs)
+	}
+	haiku := resp.Results[0].Services[0].Channels[0].Models[0]
+	if haiku.Name != "haiku" || !haiku.Hidden || haiku.HiddenReason != "维护中" || haiku.Status != "unknown" {
+		t.Fatalf("unexpected hidden model: %+v", haiku)
+	}
+	old := resp.Results[0].Services[1].Channels[0]
+	if !old.Models[0].Disabled || old.Models[0].DisabledReason != "已停运" || old.Status != "unknown" {
+		t.Fatalf("unexpected disabled channel: %+v", old)
+	}
+	if e := resp.Results[1].Error; e == nil || e.Code != "CHANNEL_NOT_FOUND" {
+		t.Fatalf("expected CHANNEL_NOT_FOUND, got %+v", resp.Results[1])
+	}
+	if e := resp.Results[2].Error; e == nil || e.Code != "PROVIDER_NOT_FOUND" {
+		t.Fatalf("expected PROVIDER_NOT_FOUND, got %+v", resp.Results[2])
+	}
+
+	// 请求级错误使用稳定错误码
+	var errResp struct {
+		Error StatusQueryErrorObject `json:"error"`
+	}
+	for _, tc := range []struct {
+		method, target, body, token string
+		status                      int
+		code                        string
+	}{
+		{http.MethodGet, "/api/v2/status/query", "", "", http.StatusBadRequest, "INVALID_REQUEST"},
+		{http.MethodGet, "/api/v2/status/query?q=relay", "", "wrong", http.StatusForbidden, "FORBIDDEN"},
+		{http.MethodPost, "/api/v2/status/query", `{"queries":[]}`, "", http.StatusBadRequest, "INVALID_REQUEST"},
+		{http.MethodGet, "/api/v2/status/query?" + strings.Repeat("q=a&", maxQueryGET+1), "", "", http.StatusBadRequest, "TOO_MANY_QUERIES"},
+	} {
+		w := serve(tc.method, tc.target, tc.body, tc.token)
+		if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil || w.Code != tc.status || errResp.Error.Code != tc.code {
+			t.Fatalf("%s %s: expected %d %s, got %d %s", tc.method, tc.target, tc.status, tc.code, w.Code, w.Body.String())
+		}
+	}
+}
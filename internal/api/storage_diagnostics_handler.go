@@ -0,0 +1,143 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// StorageDiagnosticsResponse 存储诊断响应（GET /api/admin/storage/diagnostics）
+type StorageDiagnosticsResponse struct {
+	Backend   string                  `json:"backend"`
+	Tables    []StorageTableItem      `json:"tables"`
+	Indexes   []StorageIndexItem      `json:"indexes"`
+	SlowQuery StorageSlowQuerySummary `json:"slow_query"`
+	Advice    []string                `json:"advice"`
+	Meta      StorageDiagnosticsMeta  `json:"meta"`
+}
+
+// StorageDiagnosticsMeta 存储诊断元数据
+type StorageDiagnosticsMeta struct {
+	Now int64 `json:"now"` // 快照时间（Unix 秒）
+}
+
+// StorageTableItem 单张表的大小与扫描统计
+type StorageTableItem struct {
+	Name       string `json:"name"`
+	Rows       int64  `json:"rows"`
+	TotalBytes int64  `json:"total_bytes"`
+	IndexBytes int64  `json:"index_bytes"`
+	SeqScans   *int64 `json:"seq_scans,omitempty"`   // 仅 PostgreSQL
+	IndexScans *int64 `json:"index_scans,omitempty"` // 仅 PostgreSQL
+}
+
+// StorageIndexItem 单个索引的大小与使用统计
+type StorageIndexItem struct {
+	Table  string `json:"table"`
+	Name   string `json:"name"`
+	Bytes  int64  `json:"bytes"`
+	Unique bool   `json:"unique"`
+	Scans  *int64 `json:"scans,omitempty"` // 仅 PostgreSQL
+}
+
+// StorageSlowQuerySummary 启动以来的慢查询汇总
+type StorageSlowQuerySummary struct {
+	Enabled     bool               `json:"enabled"`
+	ThresholdMs int64              `json:"threshold_ms,omitempty"`
+	Since       int64              `json:"since,omitempty"` // 开始统计的时间（Unix 秒）
+	Total       int64              `json:"total"`
+	Top         []StorageSlowQuery `json:"top"`
+}
+
+// StorageSlowQuery 按 SQL 文本聚合的慢查询
+type StorageSlowQuery struct {
+	SQL       string `json:"sql"`
+	Count     int64  `json:"count"`
+	TotalMs   int64  `json:"total_ms"`
+	AvgMs     int64  `json:"avg_ms"`
+	MaxMs     int64  `json:"max_ms"`
+	LastMs    int64  `json:"last_ms"`
+	LastSeen  int64  `json:"last_seen"`
+	LastError string `json:"last_error,omitempty"`
+	Plan      string `json:"plan,omitempty"`
+}
+
+// GetAdminStorageDiagnostics 返回表与索引大小、索引使用情况、启动以来的慢查询与索引建议
+// GET /api/admin/storage/diagnostics?top=20
+func (h *Handler) GetAdminStorageDiagnostics(c *gin.Context) {
+	ds, ok := h.storage.WithContext(c.Request.Context()).(storage.DiagnosticsStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持存储诊断",
+		})
+		return
+	}
+
+	top, _ := strconv.Atoi(c.DefaultQuery("top", "20"))
+	if top <= 0 {
+		top = 20
+	}
+	if top > 100 {
+		top = 100
+	}
+
+	d, err := ds.GetStorageDiagnostics(top)
+	if err != nil {
+		logger.Error("api", "查询存储诊断失败", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "查询存储诊断失败",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, buildStorageDiagnosticsResponse(d))
+}
+
+// buildStorageDiagnosticsResponse 转换存储诊断结果
+func buildStorageDiagnosticsResponse(d *storage.StorageDiagnostics) StorageDiagnosticsResponse {
+	resp := StorageDiagnosticsResponse{
+		Backend: d.Backend,
+		Tables:  make([]StorageTableItem, 0, len(d.Tables)),
+		Indexes: make([]StorageIndexItem, 0, len(d.Indexes)),
+		Advice:  append([]string{}, d.Advice...),
+		Meta:    StorageDiagnosticsMeta{Now: time.Now().Unix()},
+	}
+	for _, t := range d.Tables {
+		resp.Tables = append(resp.Tables, StorageTableItem(t))
+	}
+	for _, idx := range d.Indexes {
+		resp.Indexes = append(resp.Indexes, StorageIndexItem(idx))
+	}
+
+	sq := d.SlowQuery
+	resp.SlowQuery = StorageSlowQuerySummary{
+		Enabled: sq.Enabled,
+		Total:   sq.Total,
+		Top:     make([]StorageSlowQuery, 0, len(sq.Top)),
+	}
+	if sq.Enabled {
+		resp.SlowQuery.ThresholdMs = sq.Threshold.Milliseconds()
+		resp.SlowQuery.Since = sq.Since.Unix()
+	}
+	for _, q := range sq.Top {
+		item := StorageSlowQuery{
+			SQL:       q.SQL,
+			Count:     q.Count,
+			TotalMs:   q.TotalTime.Milliseconds(),
+			MaxMs:     q.MaxTime.Milliseconds(),
+			LastMs:    q.LastTime.Milliseconds(),
+			LastSeen:  q.LastSeen.Unix(),
+			LastError: q.LastError,
+			Plan:      q.Plan,
+		}
+		if q.Count > 0 {
+			item.AvgMs = q.TotalTime.Milliseconds() / q.Count
+		}
+		resp.SlowQuery.Top = append(resp.SlowQuery.Top, item)
+	}
+	return resp
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestGetAdminStorageDiagnostics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 阈值 1ns：全部查询都记为慢查询
+	store, err := storage.New(&config.StorageConfig{
+		Type:   "sqlite",
+		SQLite: config.SQLiteConfig{Path: filepath.Join(t.TempDir(), "monitor.db")},
+		SlowQuery: config.SlowQueryConfig{
+			Enabled:           true,
+			ThresholdDuration: time.Nanosecond,
+			MaxEntries:        200,
+		},
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer store.Close()
+	if err := store.Init(); err != nil {
+		t.Fatalf("init sqlite: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := store.SaveRecord(&storage.ProbeRecord{Provider: "Relay", Service: "cc", Status: 1, Latency: 100, Timestamp: time.Now().Unix() - int64(i)}); err != nil {
+			t.Fatalf("save record: %v", err)
+		}
+	}
+	key := storage.MonitorKey{Provider: "Relay", Service: "cc"}
+	if _, err := store.GetHistoryBatch([]storage.MonitorKey{key}, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("get history: %v", err)
+	}
+
+	h := NewHandler(store, &config.AppConfig{})
+	router := gin.New()
+	router.GET("/api/admin/storage/diagnostics", h.GetAdminStorageDiagnostics)
+
+	// 执行计划在后台获取，等待批量查询的执行计划写入
+	var resp StorageDiagnosticsResponse
+	var history *StorageSlowQuery
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/storage/diagnostics?top=100", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		resp = StorageDiagnosticsResponse{}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		history = nil
+		for i, q := range resp.SlowQuery.Top {
+			if strings.Contains(q.SQL, "FROM probe_history p JOIN keys k") {
+				history = &resp.SlowQuery.Top[i]
+			}
+		}
+		if history != nil && history.Plan != "" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("slow query plan not recorded: %+v", history)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if resp.Backend != "sqlite" || !resp.SlowQuery.Enabled || resp.SlowQuery.Total == 0 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if !strings.Contains(history.SQL, "AS (VALUES (...)) SELECT") || !strings.Contains(history.Plan, "probe_history") {
+		t.Fatalf("unexpected slow query: %+v", history)
+	}
+
+	var probe *StorageTableItem
+	for i, tbl := range resp.Tables {
+		if tbl.Name == "probe_history" {
+			probe = &resp.Tables[i]
+		}
+	}
+	if probe == nil || probe.Rows != 3 || probe.TotalBytes <= 0 || probe.IndexBytes <= 0 || probe.SeqScans != nil {
+		t.Fatalf("unexpected probe_history stats: %+v", probe)
+	}
+	if len(resp.Indexes) == 0 || resp.Indexes[0].Scans != nil {
+		t.Fatalf("unexpected indexes: %+v", resp.Indexes)
+	}
+
+	// 未启用慢查询日志时仍返回表与索引统计
+	plain := newTestSQLiteStore(t)
+	router = gin.New()
+	router.GET("/api/admin/storage/diagnostics", NewHandler(plain, &config.AppConfig{}).GetAdminStorageDiagnostics)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/storage/diagnostics", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"slow_query":{"enabled":false,"total":0,"top":[]}`) {
+		t.Fatalf("unexpected response: %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"monitor/internal/storage"
+)
+
+// TestSQLiteTimelineAggMatchesBuildTimeline SQLite 的 DB 侧聚合结果经 buildTimelineFromAgg 转换后应与应用层 buildTimeline 完全一致
+// 聚合本身（bucket 归属、边界、时段过滤、分批）的存储层测试见 storage/sqlite_timeline_agg_test.go
+func TestSQLiteTimelineAggMatchesBuildTimeline(t *testing.T) {
+	store := newTestSQLiteStore(t)
+
+	key := storage.MonitorKey{Provider: "Relay", Service: "cc", Channel: "vip"}
+	other := storage.MonitorKey{Provider: "Other", Service: "cc"}
+	endTime := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	since := endTime.Add(-7 * 24 * time.Hour)
+	dns := 12
+
+	// 每 97 分钟一条，覆盖各种状态、细分原因、HTTP 状态码与阶段耗时，并包含 since 边界与未来数据
+	var records []*storage.ProbeRecord
+	subs := []storage.SubStatus{"", "slow_latency", "server_error", "rate_limit", "network_error", "auth_error", "cert_expiring", "probe_panic"}
+	for i, ts := 0, since.Unix(); ts <= endTime.Unix()+3600; i, ts = i+1, ts+97*60 {
+		rec := &storage.ProbeRecord{
+			Provider: key.Provider, Service: key.Service, Channel: key.Channel,
+			Status: i % 3, SubStatus: subs[i%len(subs)], Latency: 50 + i%400, Timestamp: ts,
+		}
+		if rec.Status == 0 && i%2 == 0 {
+			rec.HttpCode = 500 + i%4
+		}
+		if i%5 == 0 {
+			rec.Latency = 0
+		}
+		if i%4 == 0 {
+			rec.DNSMs = &dns
+		}
+		records = append(records, rec)
+	}
+	records = append(records, &storage.ProbeRecord{Provider: other.Provider, Service: other.Service, Status: 1, Latency: 70, Timestamp: endTime.Unix() - 7200})
+	for _, rec := range records {
+		if err := store.SaveRecord(rec); err != nil {
+			t.Fatalf("save record: %v", err)
+		}
+	}
+
+	h := &Handler{}
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("load timezone: %v", err)
+	}
+	cases := []struct {
+		name   string
+		end    time.Time
+		filter *TimeFilter
+	}{
+		{"全天", endTime, nil},
+		{"工作时间", endTime, &TimeFilter{StartHour: 9, EndHour: 17, EndMinute: 30}},
+		{"跨午夜", endTime, &TimeFilter{StartHour: 22, EndHour: 6, CrossMidnight: true}},
+		{"固定偏移时区", endTime.In(shanghai), &TimeFilter{StartHour: 9, EndHour: 18}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			history, err := store.GetHistoryBatch([]storage.MonitorKey{key, other}, since)
+			if err != nil {
+				t.Fatalf("get history: %v", err)
+			}
+			var tf *storage.DailyTimeFilter
+			if tc.filter != nil {
+				tf = &storage.DailyTimeFilter{
+					StartMinutes:  tc.filter.StartHour*60 + tc.filter.StartMinute,
+					EndMinutes:    tc.filter.EndHour*60 + tc.filter.EndMinute,
+					CrossMidnight: tc.filter.CrossMidnight,
+					Timezone:      tc.end.Location().String(),
+				}
+			}
+			agg, err := store.GetTimelineAggBatch([]storage.MonitorKey{key, other}, since, tc.end, 7, 24*time.Hour, tf)
+			if err != nil {
+				t.Fatalf("get timeline agg: %v", err)
+			}
+			for _, k := range []storage.MonitorKey{key, other} {
+				want := h.buildTimeline(history[k], tc.end, "7d", 0.7, tc.filter)
+				got := h.buildTimelineFromAgg(agg[k], tc.end, "7d", 0.7)
+				// 可用率由加权和计算，累加顺序不同会产生浮点尾差
+				for i := range got {
+					got[i].Availability = math.Round(got[i].Availability*1e6) / 1e6
+					want[i].Availability = math.Round(want[i].Availability*1e6) / 1e6
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("%v timeline mismatch:\n got=%+v\nwant=%+v", k, got, want)
+				}
+			}
+		})
+	}
+}
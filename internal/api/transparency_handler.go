@@ -0,0 +1,322 @@
+package api
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+	"monitor/internal/transparency"
+)
+
+// transparencyMaxHours 单次证明查询的最大小时数
+const transparencyMaxHours = 24
+
+// TransparencyInfo 透明度公钥与格式说明（GET /api/transparency）
+type TransparencyInfo struct {
+	Algorithm     string            `json:"algorithm"`
+	PublicKey     string            `json:"public_key"` // base64 编码的 32 字节 Ed25519 公钥
+	KeyID         string            `json:"key_id"`
+	Hash          string            `json:"hash"`
+	Interval      int               `json:"interval"` // 封存粒度（秒）
+	LeafFormat    string            `json:"leaf_format"`
+	MessageFormat string            `json:"message_format"`
+	Latest        *TransparencyRoot `json:"latest"` // 最近封存的小时根，尚未封存时为 null
+}
+
+// TransparencyRoot 小时根
+type TransparencyRoot struct {
+	Hour      int64  `json:"hour"`
+	LeafCount int    `json:"leaf_count"`
+	Root      string `json:"root"`
+	PrevRoot  string `json:"prev_root"`
+	KeyID     string `json:"key_id"`
+	Signature string `json:"signature"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// TransparencyProofResponse 签名证明（GET /api/transparency/proofs）
+type TransparencyProofResponse struct {
+	PublicKey string             `json:"public_key"`
+	KeyID     string             `json:"key_id"`
+	Since     int64              `json:"since"` // 首个小时（含）
+	Until     int64              `json:"until"` // 最后一个小时（含）
+	Hours     []TransparencyHour `json:"hours"`
+}
+
+// TransparencyHour 单个小时的签名根与记录
+// complete=false 表示部分记录已被 retention 清理，只能校验签名与哈希链，无法重算根
+type TransparencyHour struct {
+	TransparencyRoot
+	Complete bool                 `json:"complete"`
+	Records  []TransparencyRecord `json:"records"`
+}
+
+// TransparencyRecord 叶子记录
+// 隐藏/禁用监测项的记录仅返回 index 与 leaf_hash（redacted=true），不影响重算根
+type TransparencyRecord struct {
+	Index    int               `json:"index"`
+	LeafHash string            `json:"leaf_hash"`
+	Proof    []string          `json:"proof,omitempty"` // 审计路径（自底向上，仅按监测项过滤时返回）
+	Redacted bool              `json:"redacted,omitempty"`
+	Record   *TransparencyLeaf `json:"record,omitempty"`
+}
+
+// TransparencyLeaf 叶子原文字段（按 leaf_format 顺序以换行连接即为叶子数据）
+type TransparencyLeaf struct {
+	ID        int64  `json:"id"`
+	Provider  string `json:"provider"`
+	Service   string `json:"service"`
+	Channel   string `json:"channel"`
+	Model     string `json:"model"`
+	Status    int    `json:"status"`
+	SubStatus string `json:"sub_status"`
+	HttpCode  int    `json:"http_code"`
+	Latency   int    `json:"latency"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// transparencyKey 读取透明度配置并解析公钥（未启用时返回 nil）
+func (h *Handler) transparencyKey() (ed25519.PublicKey, error) {
+	h.cfgMu.RLock()
+	cfg := h.config.Transparency
+	h.cfgMu.RUnlock()
+
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	key, err := cfg.SigningKey()
+	if err != nil {
+		return nil, err
+	}
+	return key.Public().(ed25519.PublicKey), nil
+}
+
+// GetTransparency 返回签名公钥、格式说明与最近的小时根
+// GET /api/transparency
+func (h *Handler) GetTransparency(c *gin.Context) {
+	pub, ts, ok := h.transparencyPrecheck(c)
+	if !ok {
+		return
+	}
+
+	latest, err := ts.GetLatestTransparencyRoot()
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("GetTransparency 失败", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("查询失败: %v", err),
+		})
+		return
+	}
+
+	info := TransparencyInfo{
+		Algorithm:     "Ed25519",
+		PublicKey:     base64.StdEncoding.EncodeToString(pub),
+		KeyID:         transparency.KeyID(pub),
+		Hash:          "SHA-256 Merkle tree (RFC 6962)",
+		Interval:      transparency.HourSeconds,
+		LeafFormat:    "id\\nprovider\\nservice\\nchannel\\nmodel\\nstatus\\nsub_status\\nhttp_code\\nlatency\\ntimestamp",
+		MessageFormat: transparency.MessageVersion + "\\nhour=<unix>\\ncount=<n>\\nroot=<hex>\\nprev=<hex>",
+	}
+	if latest != nil {
+		root := toTransparencyRoot(latest)
+		info.Latest = &root
+	}
+
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(http.StatusOK, info)
+}
+
+// GetTransparencyProofs 返回时间范围内各小时的签名根与叶子记录
+// GET /api/transparency/proofs?since=<unix>&until=<unix>&provider=xxx&service=xxx&channel=xxx&model=xxx
+// 默认最近一个已结束的小时，最多 24 小时；指定监测项时仅返回匹配记录并附带审计路径
+func (h *Handler) GetTransparencyProofs(c *gin.Context) {
+	pub, ts, ok := h.transparencyPrecheck(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now().Unix()
+	until, err := parseUnixParam(c.Query("until"), now-transparency.HourSeconds)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("无效的 until 参数: %v", err)})
+		return
+	}
+	since, err := parseUnixParam(c.Query("since"), until)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("无效的 since 参数: %v", err)})
+		return
+	}
+	sinceHour := since / transparency.HourSeconds * transparency.HourSeconds
+	untilHour := until / transparency.HourSeconds * transparency.HourSeconds
+	if sinceHour > untilHour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "since 不能晚于 until"})
+		return
+	}
+	if (untilHour-sinceHour)/transparency.HourSeconds >= transparencyMaxHours {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("时间范围不能超过 %d 小时", transparencyMaxHours)})
+		return
+	}
+
+	filter := storage.MonitorKey{
+		Provider: strings.TrimSpace(c.Query("provider")),
+		Service:  strings.TrimSpace(c.Query("service")),
+		Channel:  strings.TrimSpace(c.Query("channel")),
+		Model:    strings.TrimSpace(c.Query("model")),
+	}
+
+	h.cfgMu.RLock()
+	visible := make(map[storage.MonitorKey]bool)
+	for _, task := range h.filterMonitorsForGroups(h.config.Monitors, "all", "all", "all", false, false) {
+		visible[storage.MonitorKey{Provider: task.Provider, Service: task.Service, Channel: task.Channel, Model: task.Model}] = true
+	}
+	h.cfgMu.RUnlock()
+
+	roots, err := ts.GetTransparencyRoots(sinceHour, untilHour)
+	var records []*storage.ProbeRecord
+	if err == nil && len(roots) > 0 {
+		records, err = ts.GetRecordsInRange(roots[0].Hour, roots[len(roots)-1].Hour+transparency.HourSeconds)
+	}
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("GetTransparencyProofs 失败", "since", sinceHour, "until", untilHour, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("查询失败: %v", err),
+		})
+		return
+	}
+
+	resp := buildTransparencyProofs(roots, records, visible, filter)
+	resp.PublicKey = base64.StdEncoding.EncodeToString(pub)
+	resp.KeyID = transparency.KeyID(pub)
+	resp.Since = sinceHour
+	resp.Until = untilHour
+
+	// 已封存的小时根与记录不再变化
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, resp)
+}
+
+// transparencyPrecheck 检查功能开关与存储能力，失败时已写入响应
+func (h *Handler) transparencyPrecheck(c *gin.Context) (ed25519.PublicKey, storage.TransparencyStorage, bool) {
+	pub, err := h.transparencyKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	if pub == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "透明度功能未启用"})
+		return nil, nil, false
+	}
+	ts, ok := h.storage.WithContext(c.Request.Context()).(storage.TransparencyStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "当前存储后端不支持透明度功能"})
+		return nil, nil, false
+	}
+	return pub, ts, true
+}
+
+// buildTransparencyProofs 将记录按小时分组并生成叶子哈希与审计路径
+// filter 为空时返回全部叶子（隐藏监测项脱敏）；否则仅返回匹配的可见记录并附带审计路径
+func buildTransparencyProofs(roots []*storage.TransparencyRoot, records []*storage.ProbeRecord, visible map[storage.MonitorKey]bool, filter storage.MonitorKey) *TransparencyProofResponse {
+	filtered := filter != storage.MonitorKey{}
+	byHour := make(map[int64][]*storage.ProbeRecord, len(roots))
+	for _, r := range records {
+		hour := r.Timestamp / transparency.HourSeconds * transparency.HourSeconds
+		byHour[hour] = append(byHour[hour], r)
+	}
+
+	resp := &TransparencyProofResponse{Hours: make([]TransparencyHour, 0, len(roots))}
+	for _, root := range roots {
+		hourRecords := byHour[root.Hour]
+		leaves := transparency.LeafHashes(hourRecords)
+		hour := TransparencyHour{
+			TransparencyRoot: toTransparencyRoot(root),
+			Complete:         len(hourRecords) == root.LeafCount,
+			Records:          make([]TransparencyRecord, 0),
+		}
+
+		for i, r := range hourRecords {
+			key := storage.MonitorKey{Provider: r.Provider, Service: r.Service, Channel: r.Channel, Model: r.Model}
+			if filtered && (!visible[key] || !matchTransparencyFilter(key, filter)) {
+				continue
+			}
+			item := TransparencyRecord{Index: i, LeafHash: hex.EncodeToString(leaves[i])}
+			if !visible[key] {
+				item.Redacted = true
+				hour.Records = append(hour.Records, item)
+				continue
+			}
+			item.Record = &TransparencyLeaf{
+				ID:        r.ID,
+				Provider:  r.Provider,
+				Service:   r.Service,
+				Channel:   r.Channel,
+				Model:     r.Model,
+				Status:    r.Status,
+				SubStatus: string(r.SubStatus),
+				HttpCode:  r.HttpCode,
+				Latency:   r.Latency,
+				Timestamp: r.Timestamp,
+			}
+			if filtered && hour.Complete {
+				for _, p := range transparency.InclusionProof(leaves, i) {
+					item.Proof = append(item.Proof, hex.EncodeToString(p))
+				}
+			}
+			hour.Records = append(hour.Records, item)
+		}
+		resp.Hours = append(resp.Hours, hour)
+	}
+	return resp
+}
+
+// matchTransparencyFilter 按监测项过滤（空字段不过滤，provider 不区分大小写）
+func matchTransparencyFilter(key, filter storage.MonitorKey) bool {
+	if filter.Provider != "" && !strings.EqualFold(key.Provider, filter.Provider) {
+		return false
+	}
+	if filter.Service != "" && key.Service != filter.Service {
+		return false
+	}
+	if filter.Channel != "" && key.Channel != filter.Channel {
+		return false
+	}
+	if filter.Model != "" && key.Model != filter.Model {
+		return false
+	}
+	return true
+}
+
+// toTransparencyRoot 转换为响应结构
+func toTransparencyRoot(r *storage.TransparencyRoot) TransparencyRoot {
+	return TransparencyRoot{
+		Hour:      r.Hour,
+		LeafCount: r.LeafCount,
+		Root:      r.Root,
+		PrevRoot:  r.PrevRoot,
+		KeyID:     r.KeyID,
+		Signature: r.Signature,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+// parseUnixParam 解析 Unix 秒参数（空值返回默认值）
+func parseUnixParam(raw string, def int64) (int64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return def, nil
+	}
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("需为 Unix 秒: %s", raw)
+	}
+	return v, nil
+}
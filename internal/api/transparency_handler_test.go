@@ -0,0 +1,74 @@
+package api
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+
+	"monitor/internal/storage"
+	"monitor/internal/transparency"
+)
+
+func TestBuildTransparencyProofs(t *testing.T) {
+	t.Parallel()
+
+	_, key, _ := ed25519.GenerateKey(nil)
+	records := []*storage.ProbeRecord{
+		{ID: 1, Provider: "Relay", Service: "cc", Channel: "vip", Status: 1, Timestamp: 3600},
+		{ID: 2, Provider: "Hidden", Service: "cc", Channel: "x", Status: 0, Timestamp: 3610},
+		{ID: 3, Provider: "Relay", Service: "cx", Channel: "", Status: 2, Timestamp: 3620},
+		{ID: 4, Provider: "Relay", Service: "cc", Channel: "vip", Status: 1, Timestamp: 7200},
+	}
+	roots := []*storage.TransparencyRoot{
+		transparency.Seal(key, 3600, records[:3], "", 0),
+	}
+	roots = append(roots, transparency.Seal(key, 7200, records[3:], roots[0].Root, 0))
+	visible := map[storage.MonitorKey]bool{
+		{Provider: "Relay", Service: "cc", Channel: "vip"}: true,
+		{Provider: "Relay", Service: "cx"}:                 true,
+	}
+
+	// 不过滤：返回全部叶子，隐藏监测项脱敏
+	resp := buildTransparencyProofs(roots, records, visible, storage.MonitorKey{})
+	if len(resp.Hours) != 2 || len(resp.Hours[0].Records) != 3 || !resp.Hours[0].Complete {
+		t.Fatalf("unexpected hours: %+v", resp.Hours)
+	}
+	hidden := resp.Hours[0].Records[1]
+	if !hidden.Redacted || hidden.Record != nil || hidden.LeafHash == "" {
+		t.Fatalf("expected hidden record to be redacted: %+v", hidden)
+	}
+	leaves := make([][]byte, 0, 3)
+	for _, r := range resp.Hours[0].Records {
+		b, _ := hex.DecodeString(r.LeafHash)
+		leaves = append(leaves, b)
+	}
+	if hex.EncodeToString(transparency.MerkleRoot(leaves)) != roots[0].Root {
+		t.Fatalf("leaf hashes do not reproduce the signed root")
+	}
+
+	// 按监测项过滤：仅返回匹配记录并附带可校验的审计路径
+	resp = buildTransparencyProofs(roots, records, visible, storage.MonitorKey{Provider: "relay", Service: "cc"})
+	if len(resp.Hours[0].Records) != 1 || len(resp.Hours[1].Records) != 1 {
+		t.Fatalf("unexpected filtered records: %+v", resp.Hours)
+	}
+	rec := resp.Hours[0].Records[0]
+	if rec.Record == nil || rec.Record.ID != 1 || rec.Index != 0 {
+		t.Fatalf("unexpected filtered record: %+v", rec)
+	}
+	proof := make([][]byte, 0, len(rec.Proof))
+	for _, p := range rec.Proof {
+		b, _ := hex.DecodeString(p)
+		proof = append(proof, b)
+	}
+	root, _ := hex.DecodeString(roots[0].Root)
+	leaf := transparency.LeafHash(transparency.LeafData(records[0]))
+	if !transparency.VerifyInclusion(leaf, rec.Index, roots[0].LeafCount, proof, root) {
+		t.Fatalf("inclusion proof did not verify")
+	}
+
+	// 过滤隐藏监测项时不返回任何记录
+	resp = buildTransparencyProofs(roots, records, visible, storage.MonitorKey{Provider: "Hidden"})
+	if len(resp.Hours[0].Records) != 0 {
+		t.Fatalf("expected hidden records to be excluded, got %+v", resp.Hours[0].Records)
+	}
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// UsageResponse 用量统计响应
+type UsageResponse struct {
+	Currency string             `json:"currency"`
+	Since    string             `json:"since"` // 起始日期（UTC，含）
+	Until    string             `json:"until"` // 截止日期（UTC，含）
+	Totals   UsageTotals        `json:"totals"`
+	Monitors []UsageMonitorItem `json:"monitors"`
+}
+
+// UsageTotals 用量合计
+type UsageTotals struct {
+	Tokens int64   `json:"tokens"`
+	Cost   float64 `json:"cost"`
+	Probes int     `json:"probes"`
+}
+
+// UsageMonitorItem 单个监测项的用量
+type UsageMonitorItem struct {
+	Provider string       `json:"provider"`
+	Service  string       `json:"service"`
+	Channel  string       `json:"channel,omitempty"`
+	Model    string       `json:"model,omitempty"`
+	Tokens   int64        `json:"tokens"`
+	Cost     float64      `json:"cost"`
+	Probes   int          `json:"probes"`
+	Daily    []UsageDaily `json:"daily"`
+}
+
+// UsageDaily 单日用量
+type UsageDaily struct {
+	Date   string  `json:"date"`
+	Tokens int64   `json:"tokens"`
+	Cost   float64 `json:"cost"`
+	Probes int     `json:"probes"`
+}
+
+// GetUsage 获取监测 API Key 的 token 用量与估算成本
+// GET /api/usage?days=30&provider=xxx&service=xxx&channel=xxx
+// days 默认 30，最大 365（按 UTC 自然日统计，包含今天）
+func (h *Handler) GetUsage(c *gin.Context) {
+	h.cfgMu.RLock()
+	apiToken := h.config.Usage.APIToken
+	currency := h.config.Usage.Currency
+	h.cfgMu.RUnlock()
+
+	if !checkBearerToken(c, apiToken, "usage API 未配置，请设置 USAGE_API_TOKEN 环境变量") {
+		return
+	}
+
+	us, ok := h.storage.WithContext(c.Request.Context()).(storage.UsageStorage)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "当前存储后端不支持用量统计",
+		})
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if days <= 0 {
+		days = 30
+	}
+	if days > 365 {
+		days = 365
+	}
+
+	now := time.Now().UTC()
+	until := now.Format("2006-01-02")
+	since := now.AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+
+	records, err := us.GetUsage(since, until)
+	if err != nil {
+		logger.Error("api", "查询用量失败", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "查询用量失败",
+		})
+		return
+	}
+
+	resp := buildUsageResponse(records, c.Query("provider"), c.Query("service"), c.Query("channel"))
+	resp.Currency = currency
+	resp.Since = since
+	resp.Until = until
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
+
+// buildUsageResponse 按监测项聚合用量记录（records 需按 provider/service/channel/model/day 排序）
+func buildUsageResponse(records []*storage.UsageRecord, provider, service, channel string) UsageResponse {
+	resp := UsageResponse{Monitors: []UsageMonitorItem{}}
+
+	var current *UsageMonitorItem
+	for _, r := range records {
+		if (provider != "" && r.Provider != provider) ||
+			(service != "" && r.Service != service) ||
+			(channel != "" && r.Channel != channel) {
+			continue
+		}
+
+		if current == nil || current.Provider != r.Provider || current.Service != r.Service ||
+			current.Channel != r.Channel || current.Model != r.Model {
+			resp.Monitors = append(resp.Monitors, UsageMonitorItem{
+				Provider: r.Provider,
+				Service:  r.Service,
+				Channel:  r.Channel,
+				Model:    r.Model,
+				Daily:    []UsageDaily{},
+			})
+			current = &resp.Monitors[len(resp.Monitors)-1]
+		}
+
+		current.Daily = append(current.Daily, UsageDaily{Date: r.Day, Tokens: r.Tokens, Cost: r.Cost, Probes: r.Probes})
+		current.Tokens += r.Tokens
+		current.Cost += r.Cost
+		current.Probes += r.Probes
+
+		resp.Totals.Tokens += r.Tokens
+		resp.Totals.Cost += r.Cost
+		resp.Totals.Probes += r.Probes
+	}
+
+	return resp
+}
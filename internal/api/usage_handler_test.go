@@ -0,0 +1,35 @@
+package api
+
+import (
+	"testing"
+
+	"monitor/internal/storage"
+)
+
+func TestBuildUsageResponse(t *testing.T) {
+	t.Parallel()
+
+	records := []*storage.UsageRecord{
+		{Provider: "a", Service: "cc", Day: "2026-01-01", Tokens: 100, Cost: 0.1, Probes: 2},
+		{Provider: "a", Service: "cc", Day: "2026-01-02", Tokens: 50, Cost: 0.05, Probes: 1},
+		{Provider: "a", Service: "cc", Model: "m2", Day: "2026-01-02", Tokens: 10, Cost: 0.01, Probes: 1},
+		{Provider: "b", Service: "cx", Day: "2026-01-02", Tokens: 7, Cost: 0, Probes: 1},
+	}
+
+	resp := buildUsageResponse(records, "", "", "")
+	if len(resp.Monitors) != 3 {
+		t.Fatalf("expected 3 monitors, got %d", len(resp.Monitors))
+	}
+	first := resp.Monitors[0]
+	if first.Tokens != 150 || first.Probes != 3 || len(first.Daily) != 2 {
+		t.Fatalf("unexpected aggregation for first monitor: %+v", first)
+	}
+	if resp.Totals.Tokens != 167 || resp.Totals.Probes != 5 {
+		t.Fatalf("unexpected totals: %+v", resp.Totals)
+	}
+
+	filtered := buildUsageResponse(records, "b", "", "")
+	if len(filtered.Monitors) != 1 || filtered.Totals.Tokens != 7 {
+		t.Fatalf("provider filter not applied: %+v", filtered)
+	}
+}
@@ -0,0 +1,624 @@
+package api
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"monitor/internal/logger"
+)
+
+// wireFormat 响应线格式（按 Accept 头协商，默认 JSON）
+type wireFormat int
+
+const (
+	wireJSON wireFormat = iota
+	wireProtobuf
+	wireMsgpack
+)
+
+// 各线格式的 Content-Type
+const (
+	contentTypeJSON     = "application/json; charset=utf-8"
+	contentTypeProtobuf = "application/x-protobuf"
+	contentTypeMsgpack  = "application/msgpack"
+)
+
+// negotiateWireFormat 按 Accept 头选择线格式（取 q 值最高的受支持类型，q 相同时按出现顺序）
+// 未携带 Accept、仅接受 */* 或均不受支持时返回 JSON
+func negotiateWireFormat(accept string) wireFormat {
+	best, bestQ := wireJSON, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		var f wireFormat
+		switch mediaType {
+		case "application/x-protobuf", "application/protobuf", "application/vnd.google.protobuf":
+			f = wireProtobuf
+		case "application/msgpack", "application/x-msgpack", "application/vnd.msgpack":
+			f = wireMsgpack
+		case "application/json":
+			f = wireJSON
+		default:
+			continue
+		}
+		if q > bestQ {
+			best, bestQ = f, q
+		}
+	}
+	return best
+}
+
+// writeNegotiated 按 Accept 头输出 JSON 条目：protobuf 按 schema 转码（消息类型见 docs/schemas/relay_pulse_api.proto），
+// msgpack 直接由 JSON 转码；转码结果缓存在条目上，同一条目每种格式只转码一次
+func writeNegotiated(c *gin.Context, entry *cacheEntry, schema *pbMessage) {
+	c.Writer.Header().Add("Vary", "Accept")
+
+	format := negotiateWireFormat(c.GetHeader("Accept"))
+	if format == wireJSON {
+		writeCached(c, entry, contentTypeJSON)
+		return
+	}
+
+	variant, err := entry.variant(format, schema)
+	if err != nil {
+		logger.FromContext(c.Request.Context(), "api").Error("响应转码失败", "format", format, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "响应转码失败"})
+		return
+	}
+	contentType := contentTypeMsgpack
+	if format == wireProtobuf {
+		contentType = contentTypeProtobuf + "; messageType=" + schema.name
+	}
+	writeCached(c, variant, contentType)
+}
+
+// variant 返回 data 转码为指定线格式后的条目（转码失败不缓存）
+func (e *cacheEntry) variant(format wireFormat, schema *pbMessage) (*cacheEntry, error) {
+	e.variantsMu.Lock()
+	defer e.variantsMu.Unlock()
+	if v, ok := e.variants[format]; ok {
+		return v, nil
+	}
+
+	var data []byte
+	var err error
+	switch format {
+	case wireProtobuf:
+		data, err = jsonToProtobuf(e.data, schema)
+	case wireMsgpack:
+		data, err = jsonToMsgpack(e.data)
+	default:
+		return e, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if e.variants == nil {
+		e.variants = make(map[wireFormat]*cacheEntry)
+	}
+	v := &cacheEntry{data: data, expireAt: e.expireAt, staleUntil: e.staleUntil}
+	e.variants[format] = v
+	return v, nil
+}
+
+// ===== JSON → MessagePack =====
+
+// jsonToMsgpack 将 JSON 文档转码为 MessagePack（保留对象键顺序；整数使用最短整数编码，其余数字为 float64）
+func jsonToMsgpack(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return appendMsgpackValue(nil, dec)
+}
+
+func appendMsgpackValue(b []byte, dec *json.Decoder) ([]byte, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		var body []byte
+		n := 0
+		for dec.More() {
+			if v == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				body = appendMsgpackString(body, key.(string))
+			}
+			if body, err = appendMsgpackValue(body, dec); err != nil {
+				return nil, err
+			}
+			n++
+		}
+		if _, err := dec.Token(); err != nil { // 结束符
+			return nil, err
+		}
+		if v == '{' {
+			b = appendMsgpackHeader(b, n, 0x80, 0xde, 0xdf)
+		} else {
+			b = appendMsgpackHeader(b, n, 0x90, 0xdc, 0xdd)
+		}
+		return append(b, body...), nil
+	case string:
+		return appendMsgpackString(b, v), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return appendMsgpackInt(b, i), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(f)), nil
+	case bool:
+		if v {
+			return append(b, 0xc3), nil
+		}
+		return append(b, 0xc2), nil
+	case nil:
+		return append(b, 0xc0), nil
+	default:
+		return nil, fmt.Errorf("未知的 JSON token: %v", tok)
+	}
+}
+
+// appendMsgpackHeader 写入 map/array 头（fix 格式最多 15 个元素）
+func appendMsgpackHeader(b []byte, n int, fix, code16, code32 byte) []byte {
+	switch {
+	case n < 16:
+		return append(b, fix|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, code16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, code32), uint32(n))
+	}
+}
+
+func appendMsgpackString(b []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xda), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xdb), uint32(n))
+	}
+	return append(b, s...)
+}
+
+func appendMsgpackInt(b []byte, i int64) []byte {
+	switch {
+	case i >= 0 && i <= 127:
+		return append(b, byte(i))
+	case i >= -32 && i < 0:
+		return append(b, byte(i))
+	case i >= 0 && i <= math.MaxUint8:
+		return append(b, 0xcc, byte(i))
+	case i >= 0 && i <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(i))
+	case i >= 0 && i <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(i))
+	case i >= 0:
+		return binary.BigEndian.AppendUint64(append(b, 0xcf), uint64(i))
+	case i >= math.MinInt8:
+		return append(b, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(i))
+	case i >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(i))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(i))
+	}
+}
+
+// ===== JSON → Protobuf =====
+
+// pbKind protobuf 字段类型
+type pbKind int
+
+const (
+	pbInt64  pbKind = iota // int32 / int64（varint）
+	pbSint32               // sint32（zigzag varint，用于可能为 -1 的状态码）
+	pbDouble               // double
+	pbString               // string
+	pbBool                 // bool
+	pbNested               // 嵌套消息
+	pbJSON                 // string，值为原 JSON 片段（无固定结构的字段）
+)
+
+// pbField 字段定义（JSON 字段名 -> protobuf 字段）
+type pbField struct {
+	num      protowire.Number
+	kind     pbKind
+	repeated bool
+	optional bool // proto3 optional：JSON 中存在即写入（即使为零值）
+	msg      *pbMessage
+}
+
+// pbMessage 消息定义，与 docs/schemas/relay_pulse_api.proto 保持一致
+type pbMessage struct {
+	name   string // 完整消息名（用于 Content-Type 的 messageType 参数）
+	fields map[string]pbField
+}
+
+// jsonToProtobuf 按消息定义将 JSON 文档转码为 protobuf（定义之外的字段忽略，null 视为未设置）
+func jsonToProtobuf(data []byte, schema *pbMessage) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s 需要 JSON 对象", schema.name)
+	}
+	return appendPBMessage(nil, obj, schema)
+}
+
+func appendPBMessage(b []byte, obj map[string]any, m *pbMessage) ([]byte, error) {
+	// 按字段号顺序写入，保证输出稳定
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		if _, ok := m.fields[k]; ok && obj[k] != nil {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return m.fields[keys[i]].num < m.fields[keys[j]].num })
+
+	var err error
+	for _, k := range keys {
+		f := m.fields[k]
+		if !f.repeated {
+			if b, err = appendPBField(b, f, obj[k], f.optional); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", m.name, k, err)
+			}
+			continue
+		}
+		items, ok := obj[k].([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s.%s: 需要数组", m.name, k)
+		}
+		for _, item := range items {
+			if b, err = appendPBField(b, f, item, true); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", m.name, k, err)
+			}
+		}
+	}
+	return b, nil
+}
+
+// appendPBField 写入单个字段值；keepZero 为 false 时按 proto3 规则省略零值
+// repeated 标量逐个写入（未打包编码，proto3 解析器均兼容）
+func appendPBField(b []byte, f pbField, v any, keepZero bool) ([]byte, error) {
+	switch f.kind {
+	case pbInt64, pbSint32:
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("需要数字，实际为 %T", v)
+		}
+		i, err := n.Int64()
+		if err != nil {
+			fl, ferr := n.Float64()
+			if ferr != nil {
+				return nil, err
+			}
+			i = int64(fl)
+		}
+		if i == 0 && !keepZero {
+			return b, nil
+		}
+		b = protowire.AppendTag(b, f.num, protowire.VarintType)
+		if f.kind == pbSint32 {
+			return protowire.AppendVarint(b, protowire.EncodeZigZag(i)), nil
+		}
+		return protowire.AppendVarint(b, uint64(i)), nil
+	case pbDouble:
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("需要数字，实际为 %T", v)
+		}
+		fl, err := n.Float64()
+		if err != nil {
+			return nil, err
+		}
+		if fl == 0 && !keepZero {
+			return b, nil
+		}
+		b = protowire.AppendTag(b, f.num, protowire.Fixed64Type)
+		return protowire.AppendFixed64(b, math.Float64bits(fl)), nil
+	case pbString:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("需要字符串，实际为 %T", v)
+		}
+		if s == "" && !keepZero {
+			return b, nil
+		}
+		b = protowire.AppendTag(b, f.num, protowire.BytesType)
+		return protowire.AppendString(b, s), nil
+	case pbBool:
+		t, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("需要布尔值，实际为 %T", v)
+		}
+		if !t && !keepZero {
+			return b, nil
+		}
+		b = protowire.AppendTag(b, f.num, protowire.VarintType)
+		return protowire.AppendVarint(b, protowire.EncodeBool(t)), nil
+	case pbNested:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("需要对象，实际为 %T", v)
+		}
+		inner, err := appendPBMessage(nil, obj, f.msg)
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, f.num, protowire.BytesType)
+		return protowire.AppendBytes(b, inner), nil
+	case pbJSON:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, f.num, protowire.BytesType)
+		return protowire.AppendBytes(b, raw), nil
+	default:
+		return nil, fmt.Errorf("未知字段类型 %d", f.kind)
+	}
+}
+
+// ===== 消息定义（docs/schemas/relay_pulse_api.proto） =====
+
+var (
+	pbEventsResponse      = &pbMessage{name: "relaypulse.api.v1.EventsResponse"}
+	pbEvent               = &pbMessage{name: "relaypulse.api.v1.Event"}
+	pbEventsMeta          = &pbMessage{name: "relaypulse.api.v1.EventsMeta"}
+	pbAnnotation          = &pbMessage{name: "relaypulse.api.v1.Annotation"}
+	pbStatusQueryResponse = &pbMessage{name: "relaypulse.api.v1.StatusQueryResponse"}
+	pbStatusQuery         = &pbMessage{name: "relaypulse.api.v1.StatusQuery"}
+	pbStatusQueryResult   = &pbMessage{name: "relaypulse.api.v1.StatusQueryResult"}
+	pbStatusQueryError    = &pbMessage{name: "relaypulse.api.v1.StatusQueryError"}
+	pbStatusQueryService  = &pbMessage{name: "relaypulse.api.v1.StatusQueryService"}
+	pbStatusQueryChannel  = &pbMessage{name: "relaypulse.api.v1.StatusQueryChannel"}
+	pbStatusBatchResponse = &pbMessage{name: "relaypulse.api.v1.StatusBatchResponse"}
+	pbStatusBatchMeta     = &pbMessage{name: "relaypulse.api.v1.StatusBatchMeta"}
+	pbMonitor             = &pbMessage{name: "relaypulse.api.v1.Monitor"}
+	pbMonitorGroup        = &pbMessage{name: "relaypulse.api.v1.MonitorGroup"}
+	pbMonitorLayer        = &pbMessage{name: "relaypulse.api.v1.MonitorLayer"}
+	pbRisk                = &pbMessage{name: "relaypulse.api.v1.Risk"}
+	pbBadge               = &pbMessage{name: "relaypulse.api.v1.Badge"}
+	pbCurrentStatus       = &pbMessage{name: "relaypulse.api.v1.CurrentStatus"}
+	pbStatusPoint         = &pbMessage{name: "relaypulse.api.v1.StatusPoint"}
+	pbCorrelation         = &pbMessage{name: "relaypulse.api.v1.Correlation"}
+	pbTimePoint           = &pbMessage{name: "relaypulse.api.v1.TimePoint"}
+	pbStatusCounts        = &pbMessage{name: "relaypulse.api.v1.StatusCounts"}
+)
+
+// 消息之间存在相互引用，在 init 中填充字段
+func init() {
+	scalar := func(num protowire.Number, kind pbKind) pbField { return pbField{num: num, kind: kind} }
+	optional := func(num protowire.Number, kind pbKind) pbField { return pbField{num: num, kind: kind, optional: true} }
+	message := func(num protowire.Number, msg *pbMessage) pbField { return pbField{num: num, kind: pbNested, msg: msg} }
+	repeated := func(num protowire.Number, msg *pbMessage) pbField {
+		return pbField{num: num, kind: pbNested, msg: msg, repeated: true}
+	}
+
+	pbEventsResponse.fields = map[string]pbField{
+		"events": repeated(1, pbEvent),
+		"meta":   message(2, pbEventsMeta),
+	}
+	pbEvent.fields = map[string]pbField{
+		"id":                scalar(1, pbInt64),
+		"provider":          scalar(2, pbString),
+		"service":           scalar(3, pbString),
+		"channel":           scalar(4, pbString),
+		"model":             scalar(5, pbString),
+		"type":              scalar(6, pbString),
+		"from_status":       scalar(7, pbSint32),
+		"to_status":         scalar(8, pbSint32),
+		"trigger_record_id": scalar(9, pbInt64),
+		"observed_at":       scalar(10, pbInt64),
+		"created_at":        scalar(11, pbInt64),
+		"meta":              scalar(12, pbJSON),
+		"annotations":       repeated(13, pbAnnotation),
+	}
+	pbEventsMeta.fields = map[string]pbField{
+		"next_since_id": scalar(1, pbInt64),
+		"has_more":      scalar(2, pbBool),
+		"count":         scalar(3, pbInt64),
+	}
+	pbAnnotation.fields = map[string]pbField{
+		"id":         scalar(1, pbInt64),
+		"provider":   scalar(2, pbString),
+		"service":    scalar(3, pbString),
+		"channel":    scalar(4, pbString),
+		"model":      scalar(5, pbString),
+		"start_time": scalar(6, pbInt64),
+		"end_time":   scalar(7, pbInt64),
+		"text":       scalar(8, pbString),
+		"link":       scalar(9, pbString),
+		"created_at": scalar(10, pbInt64),
+	}
+
+	pbStatusQueryResponse.fields = map[string]pbField{
+		"as_of":   scalar(1, pbString),
+		"results": repeated(2, pbStatusQueryResult),
+	}
+	pbStatusQuery.fields = map[string]pbField{
+		"provider": scalar(1, pbString),
+		"service":  scalar(2, pbString),
+		"channel":  scalar(3, pbString),
+	}
+	pbStatusQueryResult.fields = map[string]pbField{
+		"query":    message(1, pbStatusQuery),
+		"provider": scalar(2, pbString),
+		"services": repeated(3, pbStatusQueryService),
+		"error":    message(4, pbStatusQueryError),
+	}
+	pbStatusQueryError.fields = map[string]pbField{
+		"code":    scalar(1, pbString),
+		"message": scalar(2, pbString),
+	}
+	pbStatusQueryService.fields = map[string]pbField{
+		"name":     scalar(1, pbString),
+		"channels": repeated(2, pbStatusQueryChannel),
+	}
+	pbStatusQueryChannel.fields = map[string]pbField{
+		"name":       scalar(1, pbString),
+		"status":     scalar(2, pbString),
+		"latency_ms": scalar(3, pbInt64),
+		"updated_at": scalar(4, pbString),
+		"board":      scalar(5, pbString),
+	}
+
+	pbStatusBatchResponse.fields = map[string]pbField{
+		"meta":   message(1, pbStatusBatchMeta),
+		"data":   repeated(2, pbMonitor),
+		"groups": repeated(3, pbMonitorGroup),
+	}
+	pbStatusBatchMeta.fields = map[string]pbField{
+		"period":          scalar(1, pbString),
+		"timeline_mode":   scalar(2, pbString),
+		"count":           scalar(3, pbInt64),
+		"slow_latency_ms": scalar(4, pbInt64),
+		"enable_badges":   scalar(5, pbBool),
+		"not_found":       repeated(6, pbStatusQuery),
+	}
+
+	// Monitor 与 MonitorGroup 共用 1-27 号字段
+	monitorFields := func() map[string]pbField {
+		return map[string]pbField{
+			"provider":        scalar(1, pbString),
+			"provider_name":   scalar(2, pbString),
+			"provider_slug":   scalar(3, pbString),
+			"provider_url":    scalar(4, pbString),
+			"provider_logo":   scalar(5, pbString),
+			"service":         scalar(6, pbString),
+			"service_name":    scalar(7, pbString),
+			"category":        scalar(8, pbString),
+			"sponsor":         scalar(9, pbString),
+			"sponsor_url":     scalar(10, pbString),
+			"sponsor_level":   scalar(11, pbString),
+			"risks":           repeated(12, pbRisk),
+			"badges":          repeated(13, pbBadge),
+			"price_min":       optional(14, pbDouble),
+			"price_max":       optional(15, pbDouble),
+			"listed_days":     optional(16, pbInt64),
+			"channel":         scalar(17, pbString),
+			"channel_name":    scalar(18, pbString),
+			"board":           scalar(19, pbString),
+			"cold_reason":     scalar(20, pbString),
+			"probe_url":       scalar(21, pbString),
+			"template_name":   scalar(22, pbString),
+			"interval_ms":     scalar(23, pbInt64),
+			"slow_latency_ms": scalar(24, pbInt64),
+			"retired":         scalar(25, pbBool),
+			"retired_at":      scalar(26, pbInt64),
+			"annotations":     repeated(27, pbAnnotation),
+		}
+	}
+	pbMonitor.fields = monitorFields()
+	pbMonitor.fields["current_status"] = message(28, pbCurrentStatus)
+	pbMonitor.fields["timeline"] = repeated(29, pbTimePoint)
+	pbMonitorGroup.fields = monitorFields()
+	pbMonitorGroup.fields["current_status"] = scalar(28, pbSint32)
+	pbMonitorGroup.fields["layers"] = repeated(29, pbMonitorLayer)
+
+	pbMonitorLayer.fields = map[string]pbField{
+		"model":          scalar(1, pbString),
+		"layer_order":    scalar(2, pbInt64),
+		"retired":        scalar(3, pbBool),
+		"retired_at":     scalar(4, pbInt64),
+		"current_status": message(5, pbStatusPoint),
+		"timeline":       repeated(6, pbTimePoint),
+	}
+	pbRisk.fields = map[string]pbField{
+		"label":         scalar(1, pbString),
+		"discussionUrl": scalar(2, pbString),
+	}
+	pbBadge.fields = map[string]pbField{
+		"id":               scalar(1, pbString),
+		"kind":             scalar(2, pbString),
+		"variant":          scalar(3, pbString),
+		"weight":           scalar(4, pbInt64),
+		"url":              scalar(5, pbString),
+		"tooltip_override": scalar(6, pbString),
+	}
+	pbCurrentStatus.fields = map[string]pbField{
+		"status":              scalar(1, pbSint32),
+		"latency":             scalar(2, pbInt64),
+		"timestamp":           scalar(3, pbInt64),
+		"cert_days_remaining": optional(4, pbInt64),
+		"correlation":         message(5, pbCorrelation),
+	}
+	pbStatusPoint.fields = map[string]pbField{
+		"status":      scalar(1, pbSint32),
+		"latency":     scalar(2, pbInt64),
+		"timestamp":   scalar(3, pbInt64),
+		"correlation": message(4, pbCorrelation),
+	}
+	pbCorrelation.fields = map[string]pbField{
+		"scope":             scalar(1, pbString),
+		"baseline":          scalar(2, pbString),
+		"baseline_status":   scalar(3, pbSint32),
+		"baseline_observed": scalar(4, pbInt64),
+	}
+	pbTimePoint.fields = map[string]pbField{
+		"time":          scalar(1, pbString),
+		"timestamp":     scalar(2, pbInt64),
+		"status":        scalar(3, pbSint32),
+		"latency":       scalar(4, pbInt64),
+		"availability":  scalar(5, pbDouble),
+		"status_counts": message(6, pbStatusCounts),
+		"dns_ms":        optional(7, pbInt64),
+		"connect_ms":    optional(8, pbInt64),
+		"tls_ms":        optional(9, pbInt64),
+		"ttfb_ms":       optional(10, pbInt64),
+	}
+	pbStatusCounts.fields = map[string]pbField{
+		"available":           scalar(1, pbInt64),
+		"degraded":            scalar(2, pbInt64),
+		"unavailable":         scalar(3, pbInt64),
+		"missing":             scalar(4, pbInt64),
+		"slow_latency":        scalar(5, pbInt64),
+		"rate_limit":          scalar(6, pbInt64),
+		"cert_expiring":       scalar(7, pbInt64),
+		"server_error":        scalar(8, pbInt64),
+		"client_error":        scalar(9, pbInt64),
+		"auth_error":          scalar(10, pbInt64),
+		"invalid_request":     scalar(11, pbInt64),
+		"network_error":       scalar(12, pbInt64),
+		"content_mismatch":    scalar(13, pbInt64),
+		"http_code_breakdown": scalar(14, pbJSON),
+		"content_drift":       scalar(15, pbInt64),
+		"ipv6_only_failure":   scalar(16, pbInt64),
+		"probe_panic":         scalar(17, pbInt64),
+	}
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestNegotiateWireFormat(t *testing.T) {
+	cases := map[string]wireFormat{
+		"":                                      wireJSON,
+		"*/*":                                   wireJSON,
+		"application/json":                      wireJSON,
+		"application/x-protobuf":                wireProtobuf,
+		"application/msgpack, application/json": wireMsgpack,
+		"application/json, application/x-protobuf;q=0.5":  wireJSON,
+		"application/json;q=0.1, application/vnd.msgpack": wireMsgpack,
+		"text/html, application/protobuf;q=0.9":           wireProtobuf,
+	}
+	for accept, want := range cases {
+		if got := negotiateWireFormat(accept); got != want {
+			t.Errorf("negotiateWireFormat(%q) = %d, want %d", accept, got, want)
+		}
+	}
+}
+
+func TestJSONToMsgpack(t *testing.T) {
+	got, err := jsonToMsgpack([]byte(`{"a":1,"b":[true,null,-3],"c":"x","d":1.5,"e":300}`))
+	if err != nil {
+		t.Fatalf("jsonToMsgpack failed: %v", err)
+	}
+	want := []byte{
+		0x85,
+		0xa1, 'a', 0x01,
+		0xa1, 'b', 0x93, 0xc3, 0xc0, 0xfd,
+		0xa1, 'c', 0xa1, 'x',
+		0xa1, 'd', 0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0,
+		0xa1, 'e', 0xcd, 0x01, 0x2c,
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("jsonToMsgpack = % x, want % x", got, want)
+	}
+}
+
+// consumeProtoFields 解码一层 protobuf 消息：字段号 -> 值列表（varint 为 uint64，bytes 为 []byte）
+func consumeProtoFields(t *testing.T, b []byte) map[protowire.Number][]any {
+	t.Helper()
+	fields := make(map[protowire.Number][]any)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("invalid tag: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			t.Fatalf("invalid field %d: %v", num, protowire.ParseError(n))
+		}
+		switch typ {
+		case protowire.VarintType:
+			v, _ := protowire.ConsumeVarint(b)
+			fields[num] = append(fields[num], v)
+		case protowire.BytesType:
+			v, _ := protowire.ConsumeBytes(b)
+			fields[num] = append(fields[num], v)
+		case protowire.Fixed64Type:
+			v, _ := protowire.ConsumeFixed64(b)
+			fields[num] = append(fields[num], v)
+		}
+		b = b[n:]
+	}
+	return fields
+}
+
+func TestJSONToProtobufOptionalAndZero(t *testing.T) {
+	b, err := jsonToProtobuf([]byte(`{"time":"10:00","timestamp":0,"status":-1,"availability":-1,"dns_ms":0,"connect_ms":null,"unknown":1}`), pbTimePoint)
+	if err != nil {
+		t.Fatalf("jsonToProtobuf failed: %v", err)
+	}
+	f := consumeProtoFields(t, b)
+	if _, ok := f[2]; ok {
+		t.Fatalf("zero timestamp should be omitted: %v", f)
+	}
+	if got := protowire.DecodeZigZag(f[3][0].(uint64)); got != -1 {
+		t.Fatalf("status = %d, want -1", got)
+	}
+	if v, ok := f[7]; !ok || v[0] != uint64(0) {
+		t.Fatalf("optional dns_ms=0 should be present: %v", f)
+	}
+	if _, ok := f[8]; ok {
+		t.Fatalf("null connect_ms should be omitted: %v", f)
+	}
+}
+
+func TestGetEventsWireFormats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newTestSQLiteStore(t)
+	for _, e := range []*storage.StatusEvent{
+		{Provider: "Relay", Service: "cc", EventType: storage.EventTypeDown, FromStatus: 1, ToStatus: 0, ObservedAt: 200, CreatedAt: 200, Meta: map[string]any{"http_code": 502}},
+		{Provider: "Relay", Service: "cc", EventType: storage.EventTypeUp, FromStatus: 0, ToStatus: 1, ObservedAt: 400, CreatedAt: 400},
+	} {
+		if err := store.SaveStatusEvent(e); err != nil {
+			t.Fatalf("save event: %v", err)
+		}
+	}
+
+	h := NewHandler(store, &config.AppConfig{
+		Events:   config.EventsConfig{APIToken: "events-token"},
+		Monitors: []config.ServiceConfig{{Provider: "Relay", Service: "cc"}},
+	})
+	router := gin.New()
+	router.GET("/api/events", h.GetEvents)
+
+	serve := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+		req.Header.Set("Authorization", "Bearer events-token")
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("application/json")
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		t.Fatalf("unexpected json response: %d %v", w.Code, w.Header())
+	}
+	if w.Header().Get("Vary") != "Accept" {
+		t.Fatalf("expected Vary: Accept, got %q", w.Header().Get("Vary"))
+	}
+	jsonSize := w.Body.Len()
+
+	w = serve("application/x-protobuf")
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-protobuf; messageType=relaypulse.api.v1.EventsResponse" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if w.Body.Len() >= jsonSize {
+		t.Fatalf("protobuf body (%d bytes) should be smaller than json (%d bytes)", w.Body.Len(), jsonSize)
+	}
+	resp := consumeProtoFields(t, w.Body.Bytes())
+	if len(resp[1]) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(resp[1]))
+	}
+	first := consumeProtoFields(t, resp[1][0].([]byte))
+	if string(first[2][0].([]byte)) != "Relay" || string(first[6][0].([]byte)) != "DOWN" {
+		t.Fatalf("unexpected first event: %v", first)
+	}
+	if string(first[12][0].([]byte)) != `{"http_code":502}` {
+		t.Fatalf("unexpected meta_json: %s", first[12][0])
+	}
+	meta := consumeProtoFields(t, resp[2][0].([]byte))
+	if meta[3][0] != uint64(2) {
+		t.Fatalf("meta.count = %v, want 2", meta[3])
+	}
+
+	w = serve("application/msgpack")
+	if ct := w.Header().Get("Content-Type"); ct != "application/msgpack" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	// {"events":[...],"meta":{...}} -> fixmap(2) + fixstr("events") + fixarray(2)
+	if body := w.Body.Bytes(); len(body) < 9 || body[0] != 0x82 || string(body[2:8]) != "events" || body[8] != 0x92 {
+		t.Fatalf("unexpected msgpack body: % x", w.Body.Bytes())
+	}
+}
@@ -0,0 +1,127 @@
+// Package audit 提供管理类操作的审计日志记录
+package audit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// 审计结果
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// purgeInterval 过期审计日志清理间隔
+const purgeInterval = time.Hour
+
+// Recorder 审计日志记录器
+// 审计写入失败只记录告警，不影响业务操作本身
+type Recorder struct {
+	store storage.Storage
+
+	enabled       atomic.Bool
+	retentionDays atomic.Int64
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewRecorder 创建审计日志记录器
+func NewRecorder(store storage.Storage, cfg config.AuditConfig) *Recorder {
+	r := &Recorder{
+		store:  store,
+		stopCh: make(chan struct{}),
+	}
+	r.UpdateConfig(cfg)
+	return r
+}
+
+// UpdateConfig 更新配置（热更新时调用）
+func (r *Recorder) UpdateConfig(cfg config.AuditConfig) {
+	r.enabled.Store(cfg.Enabled)
+	r.retentionDays.Store(int64(cfg.RetentionDays))
+}
+
+// Enabled 是否启用审计
+func (r *Recorder) Enabled() bool {
+	return r != nil && r.enabled.Load()
+}
+
+// Record 写入一条审计日志
+// 未启用或存储不支持时静默忽略；Timestamp 为 0 时取当前时间，Result 为空时记为 success
+func (r *Recorder) Record(ctx context.Context, entry storage.AuditEntry) {
+	if !r.Enabled() {
+		return
+	}
+	as, ok := r.store.WithContext(ctx).(storage.AuditStorage)
+	if !ok {
+		return
+	}
+
+	if entry.Timestamp == 0 {
+		entry.Timestamp = time.Now().Unix()
+	}
+	if entry.Result == "" {
+		entry.Result = ResultSuccess
+	}
+	if err := as.AddAuditEntry(&entry); err != nil {
+		logger.Warn("audit", "写入审计日志失败",
+			"actor", entry.Actor, "action", entry.Action, "error", err)
+	}
+}
+
+// Start 启动过期审计日志清理（阻塞，应在 goroutine 中调用）
+func (r *Recorder) Start(ctx context.Context) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	r.purge(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			r.purge(ctx)
+		case <-ctx.Done():
+			return
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+// Stop 停止清理任务（幂等，可重复调用）
+func (r *Recorder) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+	})
+}
+
+// purge 删除超过保留天数的审计日志
+func (r *Recorder) purge(ctx context.Context) {
+	days := r.retentionDays.Load()
+	if !r.Enabled() || days <= 0 {
+		return
+	}
+	as, ok := r.store.WithContext(ctx).(storage.AuditStorage)
+	if !ok {
+		return
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -int(days)).Unix()
+	deleted, err := as.PurgeAuditEntries(cutoff)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Warn("audit", "清理过期审计日志失败", "error", err)
+		}
+		return
+	}
+	if deleted > 0 {
+		logger.Info("audit", "过期审计日志清理完成", "deleted", deleted, "retention_days", days)
+	}
+}
@@ -0,0 +1,165 @@
+// Package baseline 基于官方基线监测项对中转站异常进行归因
+//
+// 官方基线（type: baseline）使用自有 Key 直连官方上游 API。中转站异常时，
+// 若同 service 的基线同样异常则归为"上游整体故障"，否则归为"中转站自身问题"。
+package baseline
+
+import (
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+// 归因结果
+const (
+	ScopeRelay    = "relay"    // 官方基线正常，异常仅出现在中转站
+	ScopeUpstream = "upstream" // 官方基线同样异常，属于上游整体故障
+)
+
+// staleFactor 基线最新记录超过 staleFactor 个巡检间隔视为过期，不参与归因
+const staleFactor = 3
+
+// Reference 官方基线监测项
+type Reference struct {
+	Key    storage.MonitorKey
+	MaxAge time.Duration // 最新记录的有效期
+}
+
+// Correlation 异常归因结果
+type Correlation struct {
+	Scope      string `json:"scope"`             // relay / upstream
+	Baseline   string `json:"baseline"`          // 参与比对的基线（provider/service/channel[/model]）
+	Status     int    `json:"baseline_status"`   // 基线最新状态（1=绿, 0=红, 2=黄）
+	ObservedAt int64  `json:"baseline_observed"` // 基线最新记录时间（Unix 秒）
+}
+
+// Meta 转换为事件 meta 中 correlation 字段的值
+func (c *Correlation) Meta() map[string]any {
+	return map[string]any{
+		"scope":             c.Scope,
+		"baseline":          c.Baseline,
+		"baseline_status":   c.Status,
+		"baseline_observed": c.ObservedAt,
+	}
+}
+
+// Index 官方基线索引
+type Index struct {
+	refs []Reference // 配置顺序
+	keys map[storage.MonitorKey]bool
+}
+
+// NewIndex 从监测项配置构建基线索引（排除禁用项）
+func NewIndex(monitors []config.ServiceConfig) *Index {
+	idx := &Index{keys: make(map[storage.MonitorKey]bool)}
+	for _, m := range monitors {
+		if !m.IsBaseline() || m.Disabled {
+			continue
+		}
+		key := storage.MonitorKey{Provider: m.Provider, Service: m.Service, Channel: m.Channel, Model: m.Model}
+		if idx.keys[key] {
+			continue
+		}
+		idx.keys[key] = true
+		idx.refs = append(idx.refs, Reference{
+			Key:    key,
+			MaxAge: staleFactor * m.IntervalDuration,
+		})
+	}
+	return idx
+}
+
+// Empty 是否未配置任何基线
+func (idx *Index) Empty() bool {
+	return idx == nil || len(idx.keys) == 0
+}
+
+// IsBaseline 判断监测项是否为基线本身（基线不与自身比对）
+func (idx *Index) IsBaseline(key storage.MonitorKey) bool {
+	return idx != nil && idx.keys[key]
+}
+
+// References 返回用于比对的基线：优先 model 相同（含均为空）的基线，没有时使用该 service 的全部基线
+func (idx *Index) References(service, model string) []Reference {
+	if idx == nil {
+		return nil
+	}
+	var refs, sameModel []Reference
+	for _, r := range idx.refs {
+		if r.Key.Service != service {
+			continue
+		}
+		refs = append(refs, r)
+		if r.Key.Model == model {
+			sameModel = append(sameModel, r)
+		}
+	}
+	if len(sameModel) > 0 {
+		return sameModel
+	}
+	return refs
+}
+
+// All 返回全部基线（配置顺序）
+func (idx *Index) All() []Reference {
+	if idx == nil {
+		return nil
+	}
+	return idx.refs
+}
+
+// Correlate 对处于 status（0=红 或 2=黄）的中转站监测项进行归因
+// latest 为基线最新记录（缺失或过期的基线被忽略）；无可用基线时返回 nil
+func (idx *Index) Correlate(service, model string, status int, latest map[storage.MonitorKey]*storage.ProbeRecord, now time.Time) *Correlation {
+	if status != 0 && status != 2 {
+		return nil
+	}
+
+	var fresh *Correlation
+	for _, ref := range idx.References(service, model) {
+		rec := latest[ref.Key]
+		if rec == nil || (ref.MaxAge > 0 && now.Sub(time.Unix(rec.Timestamp, 0)) > ref.MaxAge) {
+			continue
+		}
+		c := &Correlation{
+			Scope:      ScopeRelay,
+			Baseline:   formatKey(ref.Key),
+			Status:     rec.Status,
+			ObservedAt: rec.Timestamp,
+		}
+		// 基线为红色即归为上游；中转站为黄色时，基线黄色同样归为上游
+		if rec.Status == 0 || (status == 2 && rec.Status == 2) {
+			c.Scope = ScopeUpstream
+			return c
+		}
+		if fresh == nil {
+			fresh = c
+		}
+	}
+	return fresh
+}
+
+// LatestRecords 查询基线的最新记录（无记录的基线不出现在结果中）
+func LatestRecords(store storage.Storage, refs []Reference) (map[storage.MonitorKey]*storage.ProbeRecord, error) {
+	latest := make(map[storage.MonitorKey]*storage.ProbeRecord, len(refs))
+	for _, ref := range refs {
+		rec, err := store.GetLatest(ref.Key.Provider, ref.Key.Service, ref.Key.Channel, ref.Key.Model)
+		if err != nil {
+			return nil, err
+		}
+		if rec != nil {
+			latest[ref.Key] = rec
+		}
+	}
+	return latest, nil
+}
+
+// formatKey 基线标识：provider/service/channel[/model]
+func formatKey(k storage.MonitorKey) string {
+	s := k.Provider + "/" + k.Service + "/" + k.Channel
+	if k.Model != "" {
+		s += "/" + k.Model
+	}
+	return s
+}
@@ -0,0 +1,67 @@
+package baseline
+
+import (
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestCorrelate(t *testing.T) {
+	t.Parallel()
+
+	idx := NewIndex([]config.ServiceConfig{
+		{Provider: "official", Service: "cc", Channel: "direct", Type: config.MonitorTypeBaseline, IntervalDuration: time.Minute},
+		{Provider: "official", Service: "cc", Channel: "direct", Model: "opus", Type: config.MonitorTypeBaseline, IntervalDuration: time.Minute},
+		{Provider: "official", Service: "cx", Channel: "direct", Type: config.MonitorTypeBaseline, Disabled: true},
+		{Provider: "relay", Service: "cc", Channel: "vip"},
+	})
+	if len(idx.All()) != 2 {
+		t.Fatalf("expected 2 enabled baselines, got %d", len(idx.All()))
+	}
+
+	now := time.Unix(10_000, 0)
+	plain := storage.MonitorKey{Provider: "official", Service: "cc", Channel: "direct"}
+	opus := storage.MonitorKey{Provider: "official", Service: "cc", Channel: "direct", Model: "opus"}
+	latest := map[storage.MonitorKey]*storage.ProbeRecord{
+		plain: {Status: 1, Timestamp: now.Unix() - 30},
+		opus:  {Status: 0, Timestamp: now.Unix() - 30},
+	}
+
+	tests := []struct {
+		name   string
+		model  string
+		status int
+		want   string
+	}{
+		{"红色且基线正常", "", 0, ScopeRelay},
+		{"同 model 基线同为红色", "opus", 0, ScopeUpstream},
+		{"无同 model 基线时回退到 service 级", "sonnet", 2, ScopeUpstream},
+		{"绿色不归因", "", 1, ""},
+	}
+	for _, tt := range tests {
+		c := idx.Correlate("cc", tt.model, tt.status, latest, now)
+		got := ""
+		if c != nil {
+			got = c.Scope
+		}
+		if got != tt.want {
+			t.Errorf("%s: scope = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if c := idx.Correlate("cx", "", 0, latest, now); c != nil {
+		t.Errorf("service without baseline should not correlate: %+v", c)
+	}
+
+	// 基线数据过期（超过 3 个巡检间隔）时不归因
+	stale := map[storage.MonitorKey]*storage.ProbeRecord{plain: {Status: 1, Timestamp: now.Unix() - 600}}
+	if c := idx.Correlate("cc", "", 0, stale, now); c != nil {
+		t.Errorf("stale baseline should be ignored: %+v", c)
+	}
+
+	if !idx.IsBaseline(opus) || idx.IsBaseline(storage.MonitorKey{Provider: "relay", Service: "cc", Channel: "vip"}) {
+		t.Errorf("unexpected IsBaseline result")
+	}
+}
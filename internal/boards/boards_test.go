@@ -0,0 +1,154 @@
+package boards
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func testAutoConfig() config.BoardAutoConfig {
+	return config.BoardAutoConfig{
+		Enabled:           true,
+		IntervalDuration:  time.Hour,
+		WindowDays:        14,
+		RecoveryDays:      3,
+		SecondaryBelow:    80,
+		ColdBelow:         50,
+		RecoverAbove:      95,
+		FlappingThreshold: 10,
+		MinSamples:        10,
+	}
+}
+
+func TestDecide(t *testing.T) {
+	cfg := testAutoConfig()
+	cases := []struct {
+		name          string
+		base, current string
+		st            Stats
+		want          string // 空表示不调整
+		reason        string
+	}{
+		{"样本不足", "hot", "hot", Stats{Samples: 5, Uptime: 10}, "", ""},
+		{"可用率正常", "hot", "hot", Stats{Samples: 100, Uptime: 99, RecoverySamples: 20, RecoveryUptime: 99}, "", ""},
+		{"降入 secondary", "hot", "hot", Stats{Samples: 100, Uptime: 70, RecoverySamples: 20, RecoveryUptime: 60}, "secondary", ReasonUptime},
+		{"降入 cold", "hot", "hot", Stats{Samples: 100, Uptime: 30, RecoverySamples: 20, RecoveryUptime: 30}, "cold", ReasonUptime},
+		{"频繁波动", "hot", "hot", Stats{Samples: 100, Uptime: 90, Flaps: 12, RecoverySamples: 20, RecoveryUptime: 90, RecoveryFlaps: 4}, "secondary", ReasonFlapping},
+		{"近期已恢复不降级", "hot", "hot", Stats{Samples: 100, Uptime: 60, RecoverySamples: 20, RecoveryUptime: 100}, "", ""},
+		{"已在 secondary 继续恶化", "hot", "secondary", Stats{Samples: 100, Uptime: 40, RecoverySamples: 20, RecoveryUptime: 20}, "cold", ReasonUptime},
+		{"cold 部分好转保持不变", "hot", "cold", Stats{Samples: 100, Uptime: 70, RecoverySamples: 20, RecoveryUptime: 80}, "", ""},
+		{"恢复后升回配置板块", "secondary", "cold", Stats{Samples: 100, Uptime: 40, RecoverySamples: 20, RecoveryUptime: 96}, "secondary", ReasonRecovered},
+		{"恢复窗口仍在波动", "hot", "secondary", Stats{Samples: 100, Uptime: 90, Flaps: 12, RecoverySamples: 20, RecoveryUptime: 99, RecoveryFlaps: 3}, "", ""},
+		{"配置为 secondary 不重复降级", "secondary", "secondary", Stats{Samples: 100, Uptime: 70, RecoverySamples: 20, RecoveryUptime: 70}, "", ""},
+	}
+	for _, tc := range cases {
+		d := Decide(cfg, tc.base, tc.current, tc.st)
+		switch {
+		case tc.want == "" && d != nil:
+			t.Errorf("%s: expected no change, got %+v", tc.name, d)
+		case tc.want != "" && (d == nil || d.Board != tc.want || d.Reason != tc.reason):
+			t.Errorf("%s: expected %s/%s, got %+v", tc.name, tc.want, tc.reason, d)
+		}
+	}
+}
+
+type fakeEmitter struct {
+	store  storage.Storage
+	events []*storage.StatusEvent
+}
+
+func (f *fakeEmitter) EmitBoardEvent(key storage.MonitorKey, namespace string, eventType storage.EventType, fromStatus, toStatus int, meta map[string]any) (*storage.StatusEvent, error) {
+	now := time.Now()
+	event := &storage.StatusEvent{
+		Namespace: namespace, Provider: key.Provider, Service: key.Service, Channel: key.Channel, Model: key.Model,
+		EventType: eventType, FromStatus: fromStatus, ToStatus: toStatus, TriggerRecordID: now.UnixNano(),
+		ObservedAt: now.Unix(), CreatedAt: now.Unix(), Meta: meta,
+	}
+	f.events = append(f.events, event)
+	return event, f.store.SaveStatusEvent(event)
+}
+
+func TestEngineDemotesAndPromotes(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "monitor.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer store.Close()
+	if err := store.Init(); err != nil {
+		t.Fatalf("init sqlite: %v", err)
+	}
+
+	now := time.Date(2024, 5, 16, 12, 0, 0, 0, time.UTC)
+	save := func(at time.Time, status, n int) {
+		for i := 0; i < n; i++ {
+			if err := store.SaveRecord(&storage.ProbeRecord{
+				Provider: "Relay", Service: "cc", Channel: "vip", Status: status, Timestamp: at.Add(-time.Duration(i) * time.Second).Unix(),
+			}); err != nil {
+				t.Fatalf("save record: %v", err)
+			}
+		}
+	}
+
+	auto := testAutoConfig()
+	auto.FlappingThreshold = 0
+	cfg := &config.AppConfig{
+		DegradedWeight: 0.7,
+		Boards:         config.BoardsConfig{Enabled: true, Auto: auto},
+		Monitors:       []config.ServiceConfig{{Provider: "Relay", Service: "cc", Channel: "vip", Board: "hot"}},
+	}
+	emitter := &fakeEmitter{store: store}
+	e := NewEngine(store, emitter, cfg)
+	changes := 0
+	e.OnChange(func() { changes++ })
+
+	// 持续失败：降入冷板并发出 BOARD_DEMOTED
+	save(now.AddDate(0, 0, -5), 0, 20)
+	save(now, 0, 5)
+	e.run(t.Context(), now)
+	if len(emitter.events) != 1 || emitter.events[0].EventType != storage.EventTypeBoardDemoted || emitter.events[0].ToStatus != 0 {
+		t.Fatalf("expected a single demotion event, got %+v", emitter.events)
+	}
+	if changes != 1 {
+		t.Fatalf("expected OnChange to fire once, got %d", changes)
+	}
+	applied := e.Apply(cfg)
+	if applied == cfg || applied.Monitors[0].Board != "cold" || applied.Monitors[0].ColdReason == "" {
+		t.Fatalf("expected cold board overlay, got %+v", applied.Monitors[0])
+	}
+	if cfg.Monitors[0].Board != "hot" {
+		t.Fatalf("Apply must not modify the original config")
+	}
+
+	// 再次评估不重复发出事件；重启后从事件恢复
+	e.run(t.Context(), now)
+	restarted := NewEngine(store, emitter, cfg)
+	restarted.run(t.Context(), now)
+	if len(emitter.events) != 1 {
+		t.Fatalf("unexpected events: %+v", emitter.events)
+	}
+	if a := restarted.Assignment(monitorKey(&cfg.Monitors[0])); a == nil || a.Board != "cold" {
+		t.Fatalf("assignment not restored: %+v", a)
+	}
+
+	// 恢复窗口可用率达标：升回热板
+	save(now.Add(-time.Hour), 1, 200)
+	restarted.run(t.Context(), now)
+	if len(emitter.events) != 2 || emitter.events[1].EventType != storage.EventTypeBoardPromoted || emitter.events[1].Meta["to_board"] != "hot" {
+		t.Fatalf("expected a promotion event, got %+v", emitter.events)
+	}
+	if restarted.Apply(cfg) != cfg {
+		t.Fatalf("promoted monitor should use the configured board")
+	}
+
+	// board_auto=false 的监测项不参与调整
+	disabled := false
+	cfg.Monitors[0].BoardAuto = &disabled
+	e.UpdateConfig(cfg)
+	e.run(t.Context(), now)
+	if e.Assignment(monitorKey(&cfg.Monitors[0])) != nil || e.Apply(cfg) != cfg {
+		t.Fatalf("opted-out monitor should not be adjusted")
+	}
+}
@@ -0,0 +1,475 @@
+package boards
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// checkInterval 检查是否到达评估时间的间隔（评估间隔由 boards.auto.interval 决定，热更新后立即重新评估）
+const checkInterval = time.Minute
+
+// dayLayout 每日汇总的日期格式（UTC）
+const dayLayout = "2006-01-02"
+
+// boardEventScan 启动时恢复自动调整状态扫描的最近板块事件数
+const boardEventScan = 1000
+
+// flapEventScan 统计状态翻转时每个通道扫描的最近 DOWN/UP 事件数
+const flapEventScan = 500
+
+// EventEmitter 发出板块调整事件（由 events.Service 实现）
+type EventEmitter interface {
+	EmitBoardEvent(key storage.MonitorKey, namespace string, eventType storage.EventType, fromStatus, toStatus int, meta map[string]any) (*storage.StatusEvent, error)
+}
+
+// Assignment 监测项当前的自动调整结果（仅保存已降级的监测项）
+type Assignment struct {
+	Board  string // 生效板块
+	Reason string // 调整原因文案
+	Since  int64  // 调整时间（Unix 秒）
+}
+
+// Engine 板块自动调整后台任务
+type Engine struct {
+	store   storage.Storage
+	emitter EventEmitter // nil 表示事件服务未启用，调整结果仅保存在内存中
+
+	mu             sync.RWMutex
+	cfg            config.BoardAutoConfig
+	enabled        bool
+	monitors       []config.ServiceConfig
+	degradedWeight float64
+	batchMaxKeys   int
+	dirty          bool                               // 配置已变更，下一轮立即评估
+	assignments    map[storage.MonitorKey]*Assignment // 已降级的监测项
+	onChange       func()                             // 调整结果变化后回调（用于刷新 API 配置）
+
+	// 以下字段仅在评估协程内访问
+	lastRun  time.Time
+	restored bool // 是否已从事件恢复调整状态
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewEngine 创建板块自动调整任务（emitter 可为 nil）
+func NewEngine(store storage.Storage, emitter EventEmitter, cfg *config.AppConfig) *Engine {
+	e := &Engine{
+		store:       store,
+		emitter:     emitter,
+		assignments: make(map[storage.MonitorKey]*Assignment),
+		stopCh:      make(chan struct{}),
+	}
+	e.UpdateConfig(cfg)
+	return e
+}
+
+// OnChange 设置调整结果变化后的回调（在评估协程中调用）
+func (e *Engine) OnChange(fn func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.onChange = fn
+}
+
+// UpdateConfig 热更新策略与监测项列表，下一轮检查时立即重新评估
+func (e *Engine) UpdateConfig(cfg *config.AppConfig) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cfg = cfg.Boards.Auto
+	e.enabled = cfg.Boards.Enabled && cfg.Boards.Auto.Enabled
+	e.monitors = cfg.Monitors
+	e.degradedWeight = cfg.DegradedWeight
+	e.batchMaxKeys = cfg.BatchQueryMaxKeys
+	e.dirty = true
+}
+
+// Start 启动评估任务（阻塞，应在 goroutine 中调用）
+func (e *Engine) Start(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	e.tick(ctx, time.Now())
+	for {
+		select {
+		case now := <-ticker.C:
+			e.tick(ctx, now)
+		case <-ctx.Done():
+			return
+		case <-e.stopCh:
+			return
+		}
+	}
+}
+
+// Stop 停止评估任务（幂等，可重复调用）
+func (e *Engine) Stop() {
+	e.stopOnce.Do(func() {
+		close(e.stopCh)
+	})
+}
+
+// Apply 将自动调整后的板块叠加到配置上，没有需要调整的监测项时原样返回
+// 返回的是副本，不修改传入的配置；调度器应继续使用原配置，使自动降入冷板的监测项保持探测
+func (e *Engine) Apply(cfg *config.AppConfig) *config.AppConfig {
+	if !cfg.Boards.Enabled || !cfg.Boards.Auto.Enabled {
+		return cfg
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if len(e.assignments) == 0 {
+		return cfg
+	}
+
+	var clone *config.AppConfig
+	for i := range cfg.Monitors {
+		m := &cfg.Monitors[i]
+		a := e.assignments[monitorKey(m)]
+		if a == nil || !Eligible(m) || rank(a.Board) <= rank(m.Board) {
+			continue
+		}
+		if clone == nil {
+			clone = cfg.Clone()
+		}
+		cm := &clone.Monitors[i]
+		cm.Board = a.Board
+		if a.Board == BoardCold {
+			cm.ColdReason = a.Reason
+		}
+	}
+	if clone == nil {
+		return cfg
+	}
+	return clone
+}
+
+// Assignment 返回监测项当前的自动调整结果（未被降级时返回 nil）
+func (e *Engine) Assignment(key storage.MonitorKey) *Assignment {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if a := e.assignments[key]; a != nil {
+		cp := *a
+		return &cp
+	}
+	return nil
+}
+
+// Eligible 监测项是否参与自动调整：未停用、非官方基线、未手动设为冷板且未通过 board_auto 关闭
+func Eligible(m *config.ServiceConfig) bool {
+	if m.Disabled || m.IsBaseline() || m.Board == BoardCold {
+		return false
+	}
+	return m.BoardAuto == nil || *m.BoardAuto
+}
+
+// monitorKey 监测项的存储维度 key
+func monitorKey(m *config.ServiceConfig) storage.MonitorKey {
+	return storage.MonitorKey{Provider: m.Provider, Service: m.Service, Channel: m.Channel, Model: m.Model}
+}
+
+// tick 到达评估间隔或配置变更时执行一轮评估
+func (e *Engine) tick(ctx context.Context, now time.Time) {
+	e.mu.Lock()
+	due := e.dirty || now.Sub(e.lastRun) >= e.cfg.IntervalDuration
+	e.dirty = false
+	e.mu.Unlock()
+
+	if due {
+		e.lastRun = now
+		e.run(ctx, now)
+	}
+}
+
+// run 评估全部参与自动调整的监测项，查询失败时保留现有调整结果，下一轮重试
+func (e *Engine) run(ctx context.Context, now time.Time) {
+	e.mu.RLock()
+	cfg := e.cfg
+	enabled := e.enabled
+	monitors := e.monitors
+	degradedWeight := e.degradedWeight
+	batchMaxKeys := e.batchMaxKeys
+	e.mu.RUnlock()
+
+	var eligible []*config.ServiceConfig
+	if enabled {
+		for i := range monitors {
+			if Eligible(&monitors[i]) {
+				eligible = append(eligible, &monitors[i])
+			}
+		}
+	}
+	changed := e.prune(eligible)
+
+	if len(eligible) > 0 {
+		store := e.store.WithContext(ctx)
+		if e.evaluate(ctx, store, cfg, eligible, degradedWeight, batchMaxKeys, now) {
+			changed = true
+		}
+	}
+
+	if changed {
+		e.mu.RLock()
+		fn := e.onChange
+		e.mu.RUnlock()
+		if fn != nil {
+			fn()
+		}
+	}
+}
+
+// prune 移除不再参与自动调整的监测项（停用、移出配置、手动固定板块或关闭策略），返回是否有变化
+func (e *Engine) prune(eligible []*config.ServiceConfig) bool {
+	keep := make(map[storage.MonitorKey]bool, len(eligible))
+	for _, m := range eligible {
+		keep[monitorKey(m)] = true
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	changed := false
+	for key := range e.assignments {
+		if !keep[key] {
+			delete(e.assignments, key)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// evaluate 统计并调整监测项板块，返回是否有变化
+func (e *Engine) evaluate(ctx context.Context, store storage.Storage, cfg config.BoardAutoConfig, monitors []*config.ServiceConfig, degradedWeight float64, batchMaxKeys int, now time.Time) bool {
+	rollupStore, ok := store.(storage.DailyRollupStorage)
+	if !ok {
+		logger.Warn("boards", "当前存储后端不支持每日汇总，无法自动调整板块")
+		return false
+	}
+
+	if !e.restored {
+		if err := e.restore(store, monitors); err != nil {
+			logger.Warn("boards", "从事件恢复板块调整状态失败，本轮跳过", "error", err)
+			return false
+		}
+		e.restored = true
+	}
+
+	keys := make([]storage.MonitorKey, len(monitors))
+	for i, m := range monitors {
+		keys[i] = monitorKey(m)
+	}
+	windowStart := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -(cfg.WindowDays - 1))
+	rollups, err := fetchRollups(rollupStore, keys, windowStart.Format(dayLayout), now.UTC().Format(dayLayout), batchMaxKeys)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Warn("boards", "查询每日汇总失败", "error", err)
+		}
+		return false
+	}
+
+	recoveryStart := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -(cfg.RecoveryDays - 1))
+	flaps := newFlapCounter(store, windowStart.Unix(), recoveryStart.Unix())
+	recoveryDay := recoveryStart.Format(dayLayout)
+
+	changed := false
+	for i, m := range monitors {
+		st := computeStats(rollups[keys[i]], recoveryDay, degradedWeight)
+		if cfg.FlappingThreshold > 0 {
+			st.Flaps, st.RecoveryFlaps = flaps.count(m)
+		}
+
+		current := m.Board
+		if a := e.Assignment(keys[i]); a != nil {
+			current = a.Board
+		}
+		d := Decide(cfg, m.Board, current, st)
+		if d == nil {
+			continue
+		}
+		e.transition(m, current, d, cfg, st, now)
+		changed = true
+	}
+	return changed
+}
+
+// transition 保存调整结果并发出事件（事件保存失败只记录错误，调整仍然生效）
+func (e *Engine) transition(m *config.ServiceConfig, from string, d *Decision, cfg config.BoardAutoConfig, st Stats, now time.Time) {
+	key := monitorKey(m)
+	reason := describe(cfg, d, st)
+
+	e.mu.Lock()
+	if d.Board == m.Board {
+		delete(e.assignments, key)
+	} else {
+		e.assignments[key] = &Assignment{Board: d.Board, Reason: reason, Since: now.Unix()}
+	}
+	e.mu.Unlock()
+
+	eventType := storage.EventTypeBoardDemoted
+	if rank(d.Board) < rank(from) {
+		eventType = storage.EventTypeBoardPromoted
+	}
+	logger.Info("boards", "监测项板块已自动调整",
+		"provider", m.Provider, "service", m.Service, "channel", m.Channel, "model", m.Model,
+		"from", from, "to", d.Board, "reason", reason)
+
+	if e.emitter == nil {
+		return
+	}
+	meta := map[string]any{
+		"from_board":      from,
+		"to_board":        d.Board,
+		"base_board":      m.Board,
+		"reason":          d.Reason,
+		"detail":          reason,
+		"uptime":          st.Uptime,
+		"samples":         st.Samples,
+		"recovery_uptime": st.RecoveryUptime,
+		"window_days":     cfg.WindowDays,
+		"recovery_days":   cfg.RecoveryDays,
+	}
+	if cfg.FlappingThreshold > 0 {
+		meta["flaps"] = st.Flaps
+	}
+	if _, err := e.emitter.EmitBoardEvent(key, m.Namespace, eventType, Status(from), Status(d.Board), meta); err != nil {
+		logger.Error("boards", "保存板块调整事件失败",
+			"provider", m.Provider, "service", m.Service, "channel", m.Channel, "model", m.Model,
+			"event_type", eventType, "error", err)
+	}
+}
+
+// restore 从最近的板块事件恢复已降级的监测项（每个监测项以最新一条事件为准）
+func (e *Engine) restore(store storage.Storage, monitors []*config.ServiceConfig) error {
+	events, err := store.GetRecentStatusEvents(boardEventScan, &storage.EventFilters{
+		Types: []storage.EventType{storage.EventTypeBoardDemoted, storage.EventTypeBoardPromoted},
+	})
+	if err != nil {
+		return err
+	}
+
+	bases := make(map[storage.MonitorKey]string, len(monitors))
+	for _, m := range monitors {
+		bases[monitorKey(m)] = m.Board
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	seen := make(map[storage.MonitorKey]bool)
+	for _, ev := range events {
+		key := storage.MonitorKey{Provider: ev.Provider, Service: ev.Service, Channel: ev.Channel, Model: ev.Model}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		base, ok := bases[key]
+		if !ok || ev.EventType != storage.EventTypeBoardDemoted {
+			continue
+		}
+		board, _ := ev.Meta["to_board"].(string)
+		if rank(board) <= rank(base) {
+			continue
+		}
+		detail, _ := ev.Meta["detail"].(string)
+		e.assignments[key] = &Assignment{Board: board, Reason: detail, Since: ev.CreatedAt}
+	}
+	if len(e.assignments) > 0 {
+		logger.Info("boards", "已从事件恢复板块调整状态", "demoted", len(e.assignments))
+	}
+	return nil
+}
+
+// computeStats 由每日汇总计算降级窗口与恢复窗口（recoveryDay 及之后）的可用率
+func computeStats(rows []storage.DailyRollupRow, recoveryDay string, degradedWeight float64) Stats {
+	var st Stats
+	var up, recoveryUp float64
+	for _, r := range rows {
+		weighted := float64(r.Green) + float64(r.Yellow)*degradedWeight
+		st.Samples += r.Total
+		up += weighted
+		if r.Day >= recoveryDay {
+			st.RecoverySamples += r.Total
+			recoveryUp += weighted
+		}
+	}
+	if st.Samples > 0 {
+		st.Uptime = up / float64(st.Samples) * 100
+	}
+	if st.RecoverySamples > 0 {
+		st.RecoveryUptime = recoveryUp / float64(st.RecoverySamples) * 100
+	}
+	return st
+}
+
+// fetchRollups 分批查询 [sinceDay, untilDay] 的每日汇总
+func fetchRollups(store storage.DailyRollupStorage, keys []storage.MonitorKey, sinceDay, untilDay string, batchMaxKeys int) (map[storage.MonitorKey][]storage.DailyRollupRow, error) {
+	if batchMaxKeys <= 0 {
+		batchMaxKeys = len(keys)
+	}
+
+	result := make(map[storage.MonitorKey][]storage.DailyRollupRow, len(keys))
+	for i := 0; i < len(keys); i += batchMaxKeys {
+		end := min(i+batchMaxKeys, len(keys))
+		rollups, err := store.GetDailyRollupBatch(keys[i:end], sinceDay, untilDay)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range rollups {
+			result[k] = v
+		}
+	}
+	return result, nil
+}
+
+// flapCounter 按通道缓存最近的 DOWN/UP 事件，统计监测项的状态翻转次数
+type flapCounter struct {
+	store         storage.Storage
+	since         int64
+	recoverySince int64
+	channels      map[string][]*storage.StatusEvent
+}
+
+func newFlapCounter(store storage.Storage, since, recoverySince int64) *flapCounter {
+	return &flapCounter{
+		store:         store,
+		since:         since,
+		recoverySince: recoverySince,
+		channels:      make(map[string][]*storage.StatusEvent),
+	}
+}
+
+// count 返回降级窗口与恢复窗口内的 DOWN/UP 事件数（通道级事件的 model 为空，计入该通道全部模型）
+func (f *flapCounter) count(m *config.ServiceConfig) (int, int) {
+	psc := strings.Join([]string{m.Provider, m.Service, m.Channel}, "/")
+	events, ok := f.channels[psc]
+	if !ok {
+		var err error
+		events, err = f.store.GetRecentStatusEvents(flapEventScan, &storage.EventFilters{
+			Provider: m.Provider,
+			Service:  m.Service,
+			Channel:  m.Channel,
+			Types:    []storage.EventType{storage.EventTypeDown, storage.EventTypeUp},
+		})
+		if err != nil {
+			logger.Warn("boards", "查询状态翻转事件失败，按无翻转处理",
+				"provider", m.Provider, "service", m.Service, "channel", m.Channel, "error", err)
+		}
+		f.channels[psc] = events
+	}
+
+	total, recovery := 0, 0
+	for _, ev := range events {
+		if ev.ObservedAt < f.since || (ev.Model != "" && ev.Model != m.Model) {
+			continue
+		}
+		total++
+		if ev.ObservedAt >= f.recoverySince {
+			recovery++
+		}
+	}
+	return total, recovery
+}
@@ -0,0 +1,120 @@
+// Package boards 实现板块（hot/secondary/cold）自动调整策略：
+// 按每日汇总统计监测项的可用率与状态翻转次数，不达标时自动降级，恢复后升回配置的板块
+package boards
+
+import (
+	"fmt"
+
+	"monitor/internal/config"
+)
+
+// 板块取值（与 config.ServiceConfig.Board 一致）
+const (
+	BoardHot       = "hot"
+	BoardSecondary = "secondary"
+	BoardCold      = "cold"
+)
+
+// 调整原因（记录在事件 meta.reason 中）
+const (
+	ReasonUptime    = "uptime"    // 窗口可用率低于阈值
+	ReasonFlapping  = "flapping"  // 窗口内状态频繁翻转
+	ReasonRecovered = "recovered" // 恢复窗口可用率达标
+)
+
+// Stats 单个监测项的统计结果
+type Stats struct {
+	Samples int     // 降级窗口内的探测次数
+	Uptime  float64 // 降级窗口可用率（百分比）
+	Flaps   int     // 降级窗口内的 DOWN/UP 事件数
+
+	RecoverySamples int     // 恢复窗口内的探测次数
+	RecoveryUptime  float64 // 恢复窗口可用率（百分比）
+	RecoveryFlaps   int     // 恢复窗口内的 DOWN/UP 事件数
+}
+
+// Decision 一次评估的调整结果
+type Decision struct {
+	Board  string // 调整后的板块
+	Reason string // ReasonUptime / ReasonFlapping / ReasonRecovered
+}
+
+// rank 板块降级程度（hot < secondary < cold）
+func rank(board string) int {
+	switch board {
+	case BoardSecondary:
+		return 1
+	case BoardCold:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// Status 板块映射到事件的状态码（hot=1、secondary=2、cold=0，与红黄绿语义一致）
+func Status(board string) int {
+	switch board {
+	case BoardSecondary:
+		return 2
+	case BoardCold:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// Decide 计算监测项的目标板块，不需要调整时返回 nil
+//
+// base 为配置的板块，current 为当前生效板块（未被自动调整时等于 base）。
+// 降级窗口不达标且恢复窗口也不健康时降级（只会降得更低，不会部分回升）；
+// 已降级的监测项在恢复窗口健康后直接升回 base。
+// 两个条件都要求恢复窗口，避免升回后因降级窗口仍含故障数据而立即再次降级
+func Decide(cfg config.BoardAutoConfig, base, current string, st Stats) *Decision {
+	if st.Samples < cfg.MinSamples {
+		return nil
+	}
+
+	if recoveryHealthy(cfg, st) {
+		if rank(current) > rank(base) {
+			return &Decision{Board: base, Reason: ReasonRecovered}
+		}
+		return nil
+	}
+
+	target, reason := base, ""
+	switch {
+	case cfg.ColdBelow > 0 && st.Uptime < cfg.ColdBelow:
+		target, reason = BoardCold, ReasonUptime
+	case st.Uptime < cfg.SecondaryBelow:
+		target, reason = BoardSecondary, ReasonUptime
+	case cfg.FlappingThreshold > 0 && st.Flaps >= cfg.FlappingThreshold:
+		target, reason = BoardSecondary, ReasonFlapping
+	}
+	if rank(target) <= rank(current) {
+		return nil
+	}
+	return &Decision{Board: target, Reason: reason}
+}
+
+// recoveryHealthy 恢复窗口可用率达标，且波动频率（按窗口天数折算）低于阈值
+func recoveryHealthy(cfg config.BoardAutoConfig, st Stats) bool {
+	if st.RecoverySamples == 0 || st.RecoveryUptime < cfg.RecoverAbove {
+		return false
+	}
+	if cfg.FlappingThreshold > 0 && st.RecoveryFlaps*cfg.WindowDays >= cfg.FlappingThreshold*cfg.RecoveryDays {
+		return false
+	}
+	return true
+}
+
+// describe 调整原因的展示文案（用于冷板原因与日志）
+func describe(cfg config.BoardAutoConfig, d *Decision, st Stats) string {
+	switch d.Reason {
+	case ReasonUptime:
+		return fmt.Sprintf("自动降级：近 %d 天可用率 %.2f%%", cfg.WindowDays, st.Uptime)
+	case ReasonFlapping:
+		return fmt.Sprintf("自动降级：近 %d 天状态翻转 %d 次", cfg.WindowDays, st.Flaps)
+	default:
+		return fmt.Sprintf("自动恢复：近 %d 天可用率 %.2f%%", cfg.RecoveryDays, st.RecoveryUptime)
+	}
+}
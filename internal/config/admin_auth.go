@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// 管理后台账号默认值
+const (
+	defaultAdminSessionTTL = "12h"
+
+	defaultAdminLoginRatePerIP   = 10
+	defaultAdminLoginRatePerUser = 5
+)
+
+// AdminAuthConfig 管理后台账号配置（/api/admin/login 与基于角色的 /api/admin/* 鉴权）
+// 本地用户与会话保存在数据库中；ADMIN_API_TOKEN 仍可访问全部管理 API（视为 admin 角色）
+type AdminAuthConfig struct {
+	// 登录会话有效期（默认 "12h"），过期后需重新登录
+	SessionTTL string `yaml:"session_ttl" json:"session_ttl"`
+
+	SessionTTLDuration time.Duration `yaml:"-" json:"-"`
+
+	// 单个 IP 每分钟允许的登录尝试次数（默认 10），超出返回 429
+	LoginRateLimitPerIP int `yaml:"login_rate_limit_per_ip" json:"login_rate_limit_per_ip"`
+
+	// 单个用户名每分钟允许的登录尝试次数（默认 5，不区分来源 IP），超出返回 429
+	LoginRateLimitPerUser int `yaml:"login_rate_limit_per_user" json:"login_rate_limit_per_user"`
+
+	// 首个管理员账号（仅从 ADMIN_BOOTSTRAP_USERNAME / ADMIN_BOOTSTRAP_PASSWORD 环境变量读取）
+	// 启动时数据库中没有任何用户才会创建，已有用户时忽略
+	BootstrapUsername string `yaml:"-" json:"-"`
+	BootstrapPassword string `yaml:"-" json:"-"`
+}
+
+// Normalize 规范化管理后台账号配置
+func (a *AdminAuthConfig) Normalize() error {
+	if a.SessionTTL == "" {
+		a.SessionTTL = defaultAdminSessionTTL
+	}
+	d, err := time.ParseDuration(a.SessionTTL)
+	if err != nil || d < time.Minute {
+		return fmt.Errorf("admin_auth.session_ttl 无效（需为不小于 1m 的时长，如 12h）: %s", a.SessionTTL)
+	}
+	a.SessionTTLDuration = d
+
+	if a.LoginRateLimitPerIP == 0 {
+		a.LoginRateLimitPerIP = defaultAdminLoginRatePerIP
+	}
+	if a.LoginRateLimitPerIP < 0 {
+		return fmt.Errorf("admin_auth.login_rate_limit_per_ip 必须 > 0，当前值: %d", a.LoginRateLimitPerIP)
+	}
+	if a.LoginRateLimitPerUser == 0 {
+		a.LoginRateLimitPerUser = defaultAdminLoginRatePerUser
+	}
+	if a.LoginRateLimitPerUser < 0 {
+		return fmt.Errorf("admin_auth.login_rate_limit_per_user 必须 > 0，当前值: %d", a.LoginRateLimitPerUser)
+	}
+
+	a.BootstrapUsername = strings.TrimSpace(os.Getenv("ADMIN_BOOTSTRAP_USERNAME"))
+	a.BootstrapPassword = os.Getenv("ADMIN_BOOTSTRAP_PASSWORD")
+	if (a.BootstrapUsername == "") != (a.BootstrapPassword == "") {
+		return fmt.Errorf("ADMIN_BOOTSTRAP_USERNAME 与 ADMIN_BOOTSTRAP_PASSWORD 需同时设置")
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdminAuthConfigNormalize(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("ADMIN_BOOTSTRAP_USERNAME", "")
+		t.Setenv("ADMIN_BOOTSTRAP_PASSWORD", "")
+		a := AdminAuthConfig{}
+		if err := a.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if a.SessionTTL != "12h" || a.SessionTTLDuration != 12*time.Hour {
+			t.Fatalf("unexpected session ttl: %+v", a)
+		}
+		if a.LoginRateLimitPerIP != 10 || a.LoginRateLimitPerUser != 5 {
+			t.Fatalf("unexpected login rate limits: %+v", a)
+		}
+	})
+
+	t.Run("bootstrap env", func(t *testing.T) {
+		t.Setenv("ADMIN_BOOTSTRAP_USERNAME", " root ")
+		t.Setenv("ADMIN_BOOTSTRAP_PASSWORD", "root-password")
+		a := AdminAuthConfig{SessionTTL: "30m"}
+		if err := a.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if a.BootstrapUsername != "root" || a.BootstrapPassword != "root-password" || a.SessionTTLDuration != 30*time.Minute {
+			t.Fatalf("unexpected config: %+v", a)
+		}
+	})
+
+	t.Run("bootstrap password missing", func(t *testing.T) {
+		t.Setenv("ADMIN_BOOTSTRAP_USERNAME", "root")
+		t.Setenv("ADMIN_BOOTSTRAP_PASSWORD", "")
+		a := AdminAuthConfig{}
+		if err := a.Normalize(); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("ttl too short", func(t *testing.T) {
+		a := AdminAuthConfig{SessionTTL: "10s"}
+		if err := a.Normalize(); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+	t.Run("negative login rate limit", func(t *testing.T) {
+		a := AdminAuthConfig{LoginRateLimitPerUser: -1}
+		if err := a.Normalize(); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
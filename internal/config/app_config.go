@@ -91,6 +91,19 @@ type AppConfig struct {
 	// 解析后的按服务抖动比例（内部使用，key 统一小写）
 	RetryJitterByServiceValue map[string]float64 `yaml:"-" json:"-"`
 
+	// 可重试的失败类别（network_error / 5xx / rate_limit / timeout）
+	// nil 表示默认 [network_error, 5xx, rate_limit]；空列表表示任何失败都不重试
+	RetryOn []string `yaml:"retry_on" json:"retry_on"`
+
+	// 解析后的可重试类别（内部使用）
+	RetryOnClasses RetryClass `yaml:"-" json:"-"`
+
+	// 按服务类型覆盖的可重试类别（可选）
+	RetryOnByService map[string][]string `yaml:"retry_on_by_service" json:"retry_on_by_service"`
+
+	// 解析后的按服务可重试类别（内部使用，key 统一小写）
+	RetryOnByServiceClasses map[string]RetryClass `yaml:"-" json:"-"`
+
 	// ===== 运行时配置 =====
 
 	// 可用率中黄色状态的权重（0-1，默认 0.7）
@@ -107,6 +120,17 @@ type AppConfig struct {
 	// 开启后会将监测项均匀分散在整个巡检周期内，避免流量突发
 	StaggerProbes *bool `yaml:"stagger_probes,omitempty" json:"stagger_probes,omitempty"`
 
+	// 依赖感知探测（默认 false）
+	// 开启后父通道在本周期因网络错误或服务端错误失败时，同组子通道不再实际探测，直接记录继承的红色状态以节省 API 配额
+	DependencyAwareProbing bool `yaml:"dependency_aware_probing" json:"dependency_aware_probing"`
+
+	// 关闭时等待在途探测完成的最长时间（默认 "30s"，"0s" 表示不等待）
+	// 超时后仍未完成的探测被取消，其结果不写入存储（避免关闭过程产生虚假的红色记录）
+	ShutdownDrainTimeout string `yaml:"shutdown_drain_timeout" json:"shutdown_drain_timeout"`
+
+	// 解析后的关闭等待时间（内部使用）
+	ShutdownDrainTimeoutDuration time.Duration `yaml:"-" json:"-"`
+
 	// 是否启用并发查询（API 层优化，默认 false）
 	// 开启后 /api/status 接口会使用 goroutine 并发查询多个监测项，显著降低响应时间
 	// 注意：需要确保数据库连接池足够大（建议 max_open_conns >= 50）
@@ -129,10 +153,17 @@ type AppConfig struct {
 	// 注意：SQLite 场景下会自动回退到 249（因为参数上限 999，每 key 需要 4 个参数）
 	BatchQueryMaxKeys int `yaml:"batch_query_max_keys" json:"batch_query_max_keys"`
 
+	// /api/status 允许查询的最大天数（默认 180）
+	// 约束 90d/180d 周期与 from/to 自定义范围，超出时返回 400
+	MaxStatusRangeDays int `yaml:"max_status_range_days" json:"max_status_range_days"`
+
 	// API 响应缓存 TTL 配置（按 period 区分）
 	// 默认值：90m/24h = 10s，7d/30d = 60s
 	CacheTTL CacheTTLConfig `yaml:"cache_ttl" json:"cache_ttl"`
 
+	// /api/status 响应缓存预热（启动与缓存清空后预先计算常用查询）
+	CacheWarmup CacheWarmupConfig `yaml:"cache_warmup" json:"cache_warmup"`
+
 	// ===== 存储配置 =====
 
 	// 存储配置
@@ -190,6 +221,63 @@ type AppConfig struct {
 	// GitHub 通用配置（token/proxy/timeout）
 	GitHub GitHubConfig `yaml:"github" json:"github"`
 
+	// Token 用量与成本统计配置
+	Usage UsageConfig `yaml:"usage" json:"usage"`
+
+	// 服务商排行榜评分配置（/api/rankings）
+	Rankings RankingsConfig `yaml:"rankings" json:"rankings"`
+
+	// 服务商综合状态（多通道按权重/最差/法定数汇总为一个状态灯）
+	ProviderStatus ProviderStatusConfig `yaml:"provider_status" json:"provider_status"`
+
+	// 审计日志配置（/api/admin/audit）
+	Audit AuditConfig `yaml:"audit" json:"audit"`
+
+	// 管理后台账号配置（本地用户、登录会话与角色鉴权）
+	AdminAuth AdminAuthConfig `yaml:"admin_auth" json:"admin_auth"`
+
+	// 日志配置（级别、输出格式、采样与日志文件轮转）
+	Logging LoggingConfig `yaml:"logging" json:"-"`
+
+	// 分布式追踪配置（OpenTelemetry OTLP/HTTP）
+	Tracing TracingConfig `yaml:"tracing" json:"tracing"`
+
+	// 探测调试捕获配置（/api/admin/probe-debug）
+	DebugCapture DebugCaptureConfig `yaml:"debug_capture" json:"debug_capture"`
+
+	// 请求数据脱敏策略（调试捕获、/api/admin/probe-debug、verify 命令输出）
+	Redaction RedactionConfig `yaml:"redaction" json:"-"`
+
+	// 探测数据透明度配置（按小时签名 Merkle 根，/api/transparency）
+	Transparency TransparencyConfig `yaml:"transparency" json:"transparency"`
+
+	// 服务商 SLA 目标配置（错误预算与 SLA 事件，/api/sla）
+	SLA SLAConfig `yaml:"sla" json:"sla"`
+
+	// 服务商每日探测预算（请求数 / token 上限，/api/admin/budgets）
+	Budgets BudgetConfig `yaml:"budgets" json:"-"`
+
+	// GraphQL 查询端点配置（/api/graphql）
+	GraphQL GraphQLConfig `yaml:"graphql" json:"graphql"`
+
+	// 原始探测记录导出配置（/api/export）
+	Export ExportConfig `yaml:"export" json:"export"`
+
+	// 服务商数据门户配置（/api/provider-portal/*，含元数据自助修改）
+	ProviderPortal ProviderPortalConfig `yaml:"provider_portal" json:"provider_portal"`
+
+	// 事件总线发布配置（Kafka / NATS JetStream）
+	EventBus EventBusConfig `yaml:"event_bus" json:"event_bus"`
+
+	// HTTP 服务监听配置（地址、端口、HTTPS、Unix socket）
+	Server ServerConfig `yaml:"server" json:"-"`
+
+	// 运行时诊断配置（pprof、独立诊断端口）
+	Diagnostics DiagnosticsConfig `yaml:"diagnostics" json:"-"`
+
+	// 故障注入配置（仅用于测试/预发环境）
+	Chaos ChaosConfig `yaml:"chaos" json:"-"`
+
 	// ===== 徽标系统 =====
 
 	// 是否启用徽标系统（默认 false）
@@ -205,6 +293,27 @@ type AppConfig struct {
 	// 列表中的 provider 会自动继承 badges 到对应的所有 monitors
 	BadgeProviders []BadgeProviderConfig `yaml:"badge_providers" json:"badge_providers"`
 
+	// ===== 多文件配置 =====
+
+	// 额外配置片段目录（可选，相对路径基于主配置文件所在目录，例如 "conf.d/"）
+	// 目录下的 *.yaml / *.yml 按文件名顺序合并 monitors、badge_providers、risk_providers 等列表字段
+	IncludeDir string `yaml:"include_dir" json:"include_dir,omitempty"`
+
+	// ===== 多命名空间 =====
+
+	// 命名空间（租户）定义（可选）：监测项通过 namespace 字段归属，公开 API 按命名空间隔离
+	Namespaces []NamespaceConfig `yaml:"namespaces" json:"namespaces,omitempty"`
+
+	// ===== 探测提示词库 =====
+
+	// 按名称定义的提示词集合（可选），每次探测按策略轮换请求体
+	Prompts map[string]*PromptSetConfig `yaml:"prompts" json:"-"`
+
+	// ===== 自定义分组 =====
+
+	// 跨服务商的监测项分组（可选），通过 /api/groups 公开，亦可通过管理 API 在数据库中维护
+	Groups []GroupConfig `yaml:"groups" json:"groups,omitempty"`
+
 	// ===== 监测项列表 =====
 
 	Monitors []ServiceConfig `yaml:"monitors"`
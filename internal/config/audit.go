@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// AuditConfig 审计日志配置
+// 记录管理类操作（管理 API 调用、配置热更新等）到 audit_log 表，供 /api/admin/audit 查询
+type AuditConfig struct {
+	// 是否启用审计日志（默认禁用）
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// 保留天数（默认 90，超出后由后台任务清理）
+	RetentionDays int `yaml:"retention_days" json:"retention_days"`
+
+	// 管理 API 访问令牌（/api/admin/* 必须配置，未配置时返回 503）
+	// 支持 ADMIN_API_TOKEN 环境变量覆盖
+	APIToken string `yaml:"api_token" json:"-"`
+}
+
+// Normalize 规范化审计日志配置
+func (a *AuditConfig) Normalize() error {
+	if envToken := strings.TrimSpace(os.Getenv("ADMIN_API_TOKEN")); envToken != "" {
+		a.APIToken = envToken
+	} else {
+		a.APIToken = strings.TrimSpace(a.APIToken)
+	}
+
+	if a.RetentionDays == 0 {
+		a.RetentionDays = 90
+	}
+	if a.RetentionDays < 0 {
+		return fmt.Errorf("audit.retention_days 不能为负数，当前值: %d", a.RetentionDays)
+	}
+	return nil
+}
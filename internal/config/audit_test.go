@@ -0,0 +1,37 @@
+package config
+
+import "testing"
+
+func TestAuditConfigNormalize(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("ADMIN_API_TOKEN", "")
+		a := AuditConfig{APIToken: "  secret  "}
+		if err := a.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if a.RetentionDays != 90 {
+			t.Fatalf("RetentionDays = %d, want 90", a.RetentionDays)
+		}
+		if a.APIToken != "secret" {
+			t.Fatalf("APIToken = %q, want trimmed", a.APIToken)
+		}
+	})
+
+	t.Run("env override", func(t *testing.T) {
+		t.Setenv("ADMIN_API_TOKEN", "from-env")
+		a := AuditConfig{APIToken: "from-yaml", RetentionDays: 30}
+		if err := a.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if a.APIToken != "from-env" || a.RetentionDays != 30 {
+			t.Fatalf("unexpected config: %+v", a)
+		}
+	})
+
+	t.Run("negative retention", func(t *testing.T) {
+		a := AuditConfig{RetentionDays: -1}
+		if err := a.Normalize(); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -90,3 +91,67 @@ type RiskBadge struct {
 	Label         string `yaml:"label" json:"label"`                  // 简短标签，如"跑路风险"
 	DiscussionURL string `yaml:"discussion_url" json:"discussionUrl"` // 讨论页面链接（可选）
 }
+
+// resolveMonitorBadges 从 badge_providers + monitors[].badges 解析监测项徽标
+// 未配置任何徽标时注入默认徽标；按 kind 组顺序 → weight desc → id asc 排序
+func resolveMonitorBadges(ctx *normalizeContext, m *ServiceConfig) []ResolvedBadge {
+	normalizedProvider := strings.ToLower(strings.TrimSpace(m.Provider))
+	var refs []BadgeRef
+	if injected, ok := ctx.badgeProviderMap[normalizedProvider]; ok && len(injected) > 0 {
+		refs = append(refs, injected...)
+	}
+	if len(m.Badges) > 0 {
+		refs = append(refs, m.Badges...)
+	}
+
+	// 如果没有配置任何徽标，注入默认徽标
+	if len(refs) == 0 {
+		refs = []BadgeRef{{ID: "api_key_official"}}
+	}
+
+	// 去重并解析为 ResolvedBadge
+	order := make([]string, 0, len(refs))
+	resolvedMap := make(map[string]ResolvedBadge, len(refs))
+	for _, ref := range refs {
+		id := strings.TrimSpace(ref.ID)
+		if id == "" {
+			continue
+		}
+		def, exists := ctx.badgeDefMap[id]
+		if !exists {
+			continue // 验证阶段已检查，此处跳过
+		}
+
+		// monitor 级 tooltip 覆盖
+		tooltipOverride := strings.TrimSpace(ref.Tooltip)
+
+		if _, seen := resolvedMap[id]; !seen {
+			order = append(order, id)
+		}
+		resolvedMap[id] = ResolvedBadge{
+			ID:              id,
+			Kind:            def.Kind,
+			Variant:         def.Variant,
+			Weight:          def.Weight,
+			URL:             def.URL,
+			TooltipOverride: tooltipOverride,
+		}
+	}
+
+	// 按 kind 组顺序 → weight desc → id asc 排序
+	result := make([]ResolvedBadge, 0, len(order))
+	for _, id := range order {
+		result = append(result, resolvedMap[id])
+	}
+	sort.SliceStable(result, func(a, b int) bool {
+		kindOrder := map[BadgeKind]int{BadgeKindSource: 0, BadgeKindFeature: 1, BadgeKindInfo: 2}
+		if kindOrder[result[a].Kind] != kindOrder[result[b].Kind] {
+			return kindOrder[result[a].Kind] < kindOrder[result[b].Kind]
+		}
+		if result[a].Weight != result[b].Weight {
+			return result[a].Weight > result[b].Weight // desc
+		}
+		return result[a].ID < result[b].ID // asc
+	})
+	return result
+}
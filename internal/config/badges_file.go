@@ -0,0 +1,103 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"monitor/internal/logger"
+)
+
+// BadgesFileName 独立徽标文件名（与主配置文件位于同一目录）
+const BadgesFileName = "badges.yaml"
+
+// BadgesSchemaVersion 当前支持的 badges.yaml 格式版本
+const BadgesSchemaVersion = 1
+
+// BadgesFile 独立徽标文件（badges.yaml）
+// 存在时取代 config.yaml（含 include_dir）中的 badge_definitions / badge_providers，
+// 单独校验、单独热更新，修改徽标不会触发监测配置重载
+type BadgesFile struct {
+	// 格式版本（必填，当前为 1）
+	Version int `yaml:"version" json:"version"`
+
+	BadgeDefs      map[string]BadgeDef   `yaml:"badge_definitions" json:"badge_definitions"`
+	BadgeProviders []BadgeProviderConfig `yaml:"badge_providers" json:"badge_providers"`
+}
+
+// BadgesPath 返回主配置文件对应的徽标文件路径
+func BadgesPath(configFile string) string {
+	return filepath.Join(filepath.Dir(configFile), BadgesFileName)
+}
+
+// LoadBadgesFile 读取并校验徽标文件（不存在时返回 nil）
+func LoadBadgesFile(path string) (*BadgesFile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取徽标文件失败: %w", err)
+	}
+
+	var b BadgesFile
+	if err := yaml.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("解析徽标文件失败: %w", err)
+	}
+	if err := b.Validate(); err != nil {
+		return nil, fmt.Errorf("徽标文件无效: %w", err)
+	}
+	return &b, nil
+}
+
+// Validate 校验徽标文件（不依赖监测配置；监测项 badges 引用在叠加到配置后校验）
+func (b *BadgesFile) Validate() error {
+	if b.Version != BadgesSchemaVersion {
+		return fmt.Errorf("version %d 不受支持，当前版本为 %d", b.Version, BadgesSchemaVersion)
+	}
+	if err := validateBadgeDefs(b.BadgeDefs); err != nil {
+		return err
+	}
+	return validateBadgeProviders(b.BadgeProviders, b.BadgeDefs)
+}
+
+// applyBadgesFile 用徽标文件取代配置中的徽标定义与 badge_providers
+func (c *AppConfig) applyBadgesFile(b *BadgesFile) {
+	if len(c.BadgeDefs) > 0 || len(c.BadgeProviders) > 0 {
+		logger.Warn("config", "已存在 badges.yaml，忽略 config.yaml 中的 badge_definitions / badge_providers")
+	}
+	c.BadgeDefs = make(map[string]BadgeDef, len(b.BadgeDefs))
+	for id, bd := range b.BadgeDefs {
+		c.BadgeDefs[id] = bd
+	}
+	c.BadgeProviders = make([]BadgeProviderConfig, len(b.BadgeProviders))
+	for i, bp := range b.BadgeProviders {
+		bp.Badges = append([]BadgeRef(nil), bp.Badges...)
+		c.BadgeProviders[i] = bp
+	}
+}
+
+// WithBadges 返回替换徽标后的配置副本（仅重新解析徽标，其余字段保持不变）
+// 监测项引用了徽标文件中不存在的定义时返回错误，原配置不受影响
+func (c *AppConfig) WithBadges(b *BadgesFile) (*AppConfig, error) {
+	clone := c.Clone()
+	clone.BadgeDefs = nil
+	clone.BadgeProviders = nil
+	clone.applyBadgesFile(b)
+	if err := clone.validateBadgeConfigs(); err != nil {
+		return nil, err
+	}
+
+	ctx := newNormalizeContext()
+	clone.buildBadgeIndexes(ctx)
+	for i := range clone.Monitors {
+		clone.Monitors[i].ResolvedBadges = nil
+		if clone.EnableBadges {
+			clone.Monitors[i].ResolvedBadges = resolveMonitorBadges(ctx, &clone.Monitors[i])
+		}
+	}
+	return clone, nil
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const badgesMainConfig = `
+interval: "1m"
+enable_badges: true
+badge_definitions:
+  inline_only:
+    kind: "info"
+monitors:
+  - provider: "Alpha"
+    service: "cc"
+    category: "public"
+    sponsor: "alpha"
+    url: "https://alpha.example.com"
+    method: "POST"
+    badges:
+      - "promo"
+  - provider: "Beta"
+    service: "cc"
+    category: "public"
+    sponsor: "beta"
+    url: "https://beta.example.com"
+    method: "POST"
+`
+
+func resolvedBadgeIDs(m ServiceConfig) string {
+	ids := make([]string, 0, len(m.ResolvedBadges))
+	for _, b := range m.ResolvedBadges {
+		ids = append(ids, b.ID+":"+string(b.Variant))
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestLoaderBadgesFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	badgesPath := BadgesPath(configPath)
+	writeTestFile(t, configPath, badgesMainConfig)
+	writeTestFile(t, badgesPath, `
+version: 1
+badge_definitions:
+  promo:
+    kind: "feature"
+    variant: "success"
+badge_providers:
+  - provider: "beta"
+    badges: ["promo"]
+`)
+
+	loader := NewLoader()
+	cfg, err := loader.Load(configPath)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	if _, ok := cfg.BadgeDefs["inline_only"]; ok {
+		t.Fatal("存在 badges.yaml 时应忽略 config.yaml 中的 badge_definitions")
+	}
+	if got := resolvedBadgeIDs(cfg.Monitors[0]); got != "promo:success" {
+		t.Fatalf("Alpha 徽标不符合预期: %s", got)
+	}
+	if got := resolvedBadgeIDs(cfg.Monitors[1]); got != "promo:success" {
+		t.Fatalf("Beta 徽标不符合预期: %s", got)
+	}
+
+	// 仅重载徽标：变更样式、移除 provider 注入
+	writeTestFile(t, badgesPath, `
+version: 1
+badge_definitions:
+  promo:
+    kind: "feature"
+    variant: "warning"
+`)
+	reloaded, err := loader.ReloadBadges(configPath)
+	if err != nil {
+		t.Fatalf("徽标重载失败: %v", err)
+	}
+	if got := resolvedBadgeIDs(reloaded.Monitors[0]); got != "promo:warning" {
+		t.Fatalf("重载后 Alpha 徽标不符合预期: %s", got)
+	}
+	if got := resolvedBadgeIDs(reloaded.Monitors[1]); got != "api_key_official:info" {
+		t.Fatalf("重载后 Beta 应回退为默认徽标: %s", got)
+	}
+	if got := resolvedBadgeIDs(cfg.Monitors[0]); got != "promo:success" {
+		t.Fatalf("重载不应修改原配置: %s", got)
+	}
+
+	// 监测项引用的定义被删除：拒绝并保持当前配置
+	writeTestFile(t, badgesPath, "version: 1\n")
+	if _, err := loader.ReloadBadges(configPath); err == nil {
+		t.Fatal("监测项引用不存在的徽标时应返回错误")
+	}
+	if loader.GetCurrent() != reloaded {
+		t.Fatal("徽标重载失败时应保持当前配置")
+	}
+
+	// 格式版本不受支持：徽标重载失败，完整重载沿用上次成功加载的徽标
+	writeTestFile(t, badgesPath, `
+version: 2
+badge_definitions:
+  promo: {}
+`)
+	if _, err := loader.ReloadBadges(configPath); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Fatalf("不支持的 version 应返回错误: %v", err)
+	}
+	full, err := loader.Load(configPath)
+	if err != nil {
+		t.Fatalf("badges.yaml 无效时完整重载应沿用上次的徽标: %v", err)
+	}
+	if got := resolvedBadgeIDs(full.Monitors[0]); got != "promo:warning" {
+		t.Fatalf("完整重载后徽标不符合预期: %s", got)
+	}
+}
+
+func TestLoaderRejectsInvalidBadgesFileOnStartup(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, configPath, badgesMainConfig)
+	writeTestFile(t, BadgesPath(configPath), `
+badge_definitions:
+  promo:
+    kind: "feature"
+`)
+
+	if _, err := NewLoader().Load(configPath); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Fatalf("缺少 version 的徽标文件应导致启动加载失败: %v", err)
+	}
+}
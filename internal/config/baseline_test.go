@@ -0,0 +1,35 @@
+package config
+
+import "testing"
+
+func TestBaselineMonitorNormalize(t *testing.T) {
+	official := minimalMonitor("official", "cc")
+	official.Type = " Baseline "
+	relay := minimalMonitor("relay", "cc")
+
+	cfg := &AppConfig{
+		Interval:    "1m",
+		SlowLatency: "5s",
+		Monitors:    []ServiceConfig{official, relay},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate 失败: %v", err)
+	}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("Normalize 失败: %v", err)
+	}
+
+	if !cfg.Monitors[0].IsBaseline() || !cfg.Monitors[0].Hidden || cfg.Monitors[0].HiddenReason != "官方基线" {
+		t.Errorf("baseline 应被规范化并自动隐藏: %+v", cfg.Monitors[0])
+	}
+	if cfg.Monitors[1].IsBaseline() || cfg.Monitors[1].Hidden {
+		t.Errorf("普通监测项不应受影响: %+v", cfg.Monitors[1])
+	}
+
+	invalid := minimalMonitor("official", "cc")
+	invalid.Type = "upstream"
+	cfg = &AppConfig{Monitors: []ServiceConfig{invalid}}
+	if err := cfg.Validate(); err == nil {
+		t.Errorf("未知 type 应校验失败")
+	}
+}
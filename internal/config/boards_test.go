@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBoardsConfigNormalize(t *testing.T) {
+	t.Parallel()
+
+	var off BoardsConfig
+	if err := off.Normalize(); err != nil || off.Auto.IntervalDuration != 0 {
+		t.Fatalf("disabled auto policy should be left untouched: %+v %v", off, err)
+	}
+
+	cfg := BoardsConfig{Enabled: true, Auto: BoardAutoConfig{Enabled: true}}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	a := cfg.Auto
+	if a.IntervalDuration != time.Hour || a.WindowDays != 14 || a.RecoveryDays != 3 ||
+		a.SecondaryBelow != 80 || a.ColdBelow != 0 || a.RecoverAbove != 95 || a.MinSamples != 100 {
+		t.Fatalf("unexpected defaults: %+v", a)
+	}
+
+	for name, bad := range map[string]BoardsConfig{
+		"boards disabled":         {Auto: BoardAutoConfig{Enabled: true}},
+		"interval too short":      {Enabled: true, Auto: BoardAutoConfig{Enabled: true, Interval: "30s"}},
+		"recovery beyond window":  {Enabled: true, Auto: BoardAutoConfig{Enabled: true, WindowDays: 7, RecoveryDays: 8}},
+		"cold above secondary":    {Enabled: true, Auto: BoardAutoConfig{Enabled: true, ColdBelow: 90}},
+		"recover below secondary": {Enabled: true, Auto: BoardAutoConfig{Enabled: true, RecoverAbove: 70}},
+		"negative flapping":       {Enabled: true, Auto: BoardAutoConfig{Enabled: true, FlappingThreshold: -1}},
+	} {
+		if err := bad.Normalize(); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// templatePattern 模板表达式：{{NAME}} 或 {{NAME "arg"}}
+// 未识别的表达式原样保留，兼容 body 中本身包含 {{...}} 的场景
+var templatePattern = regexp.MustCompile(`\{\{\s*([A-Z_]+)(?:\s+"([^"]*)")?\s*\}\}`)
+
+// templateVarNamePattern 自定义变量名格式
+var templateVarNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// RenderTemplate 渲染 body / headers 中的模板表达式（每次探测调用，动态值每次重新生成）
+//
+// 支持的表达式：
+//   - {{API_KEY}} / {{MODEL}}：监测项的 API Key 与模型
+//   - {{NOW_ISO}} / {{NOW_UNIX}}：当前时间（RFC3339 UTC / Unix 秒）
+//   - {{RANDOM_UUID}}：随机 UUID（v4）
+//   - {{ENV "NAME"}}：环境变量（未设置时为空）
+//   - {{VAR "name"}}：监测项 vars 中定义的变量（变量值同样支持上述表达式，但不支持嵌套 VAR）
+func (m *ServiceConfig) RenderTemplate(s string, now time.Time) string {
+	return m.renderTemplate(s, now, true)
+}
+
+func (m *ServiceConfig) renderTemplate(s string, now time.Time, allowVars bool) string {
+	if !strings.Contains(s, "{{") {
+		return s
+	}
+	return templatePattern.ReplaceAllStringFunc(s, func(expr string) string {
+		sub := templatePattern.FindStringSubmatch(expr)
+		name, arg := sub[1], sub[2]
+		switch name {
+		case "API_KEY":
+			return m.APIKey
+		case "MODEL":
+			return m.Model
+		case "NOW_ISO":
+			return now.UTC().Format(time.RFC3339)
+		case "NOW_UNIX":
+			return strconv.FormatInt(now.Unix(), 10)
+		case "RANDOM_UUID":
+			return uuid.NewString()
+		case "ENV":
+			return os.Getenv(arg)
+		case "VAR":
+			v, ok := m.Vars[arg]
+			if !ok || !allowVars {
+				return expr
+			}
+			return m.renderTemplate(v, now, false)
+		default:
+			return expr
+		}
+	})
+}
+
+// validateTemplateVars 校验 vars 变量名，以及 body / headers 中引用的 VAR 均已定义
+func (m *ServiceConfig) validateTemplateVars() error {
+	for name, value := range m.Vars {
+		if !templateVarNamePattern.MatchString(name) {
+			return fmt.Errorf("vars: 变量名 '%s' 格式无效（字母或下划线开头，仅含字母、数字、下划线）", name)
+		}
+		for _, sub := range templatePattern.FindAllStringSubmatch(value, -1) {
+			if sub[1] == "VAR" {
+				return fmt.Errorf("vars: 变量 '%s' 的值不能引用其他 VAR", name)
+			}
+		}
+	}
+
+	if err := m.checkTemplateVarRefs(m.Body); err != nil {
+		return fmt.Errorf("body: %w", err)
+	}
+	for k, v := range m.Headers {
+		if err := m.checkTemplateVarRefs(v); err != nil {
+			return fmt.Errorf("headers.%s: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// checkTemplateVarRefs 校验模板中引用的 VAR 均已在 vars 中定义
+func (m *ServiceConfig) checkTemplateVarRefs(s string) error {
+	for _, sub := range templatePattern.FindAllStringSubmatch(s, -1) {
+		if sub[1] != "VAR" {
+			continue
+		}
+		if _, ok := m.Vars[sub[2]]; !ok {
+			return fmt.Errorf("引用了未定义的变量 '%s'（请在 vars 中定义）", sub[2])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	t.Setenv("RP_TEST_REGION", "us-east")
+
+	m := &ServiceConfig{
+		APIKey: "sk-test",
+		Model:  "gpt-4o",
+		Vars: map[string]string{
+			"prompt": "ping {{RANDOM_UUID}}",
+			"region": `{{ENV "RP_TEST_REGION"}}`,
+		},
+	}
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.FixedZone("CST", 8*3600))
+	body := `{"model":"{{MODEL}}","ts":"{{NOW_ISO}}","unix":{{NOW_UNIX}},"content":"{{VAR "prompt"}}","region":"{{ VAR "region" }}","raw":"{{unknown}}"}`
+
+	got := m.RenderTemplate(body, now)
+	for _, want := range []string{`"model":"gpt-4o"`, `"ts":"2025-01-01T19:04:05Z"`, `"unix":1735758245`, `"region":"us-east"`, `"raw":"{{unknown}}"`} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("rendered body missing %s: %s", want, got)
+		}
+	}
+	if strings.Contains(got, "RANDOM_UUID") || got == m.RenderTemplate(body, now) {
+		t.Fatalf("RANDOM_UUID should be regenerated on every render: %s", got)
+	}
+}
+
+func TestNormalizeValidatesTemplateVars(t *testing.T) {
+	newCfg := func(m ServiceConfig) *AppConfig {
+		m.Provider, m.Service, m.Category = "demo", "cc", "public"
+		m.URL, m.Method = "https://example.com", "POST"
+		return &AppConfig{Monitors: []ServiceConfig{m}}
+	}
+
+	cases := map[string]ServiceConfig{
+		"未定义的变量": {Body: `{"x":"{{VAR "missing"}}"}`},
+		"格式无效":   {Vars: map[string]string{"bad-name": "x"}},
+		"不能引用其他": {Vars: map[string]string{"a": "1", "b": `{{VAR "a"}}`}},
+	}
+	for want, m := range cases {
+		cfg := newCfg(m)
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("Validate() failed: %v", err)
+		}
+		if err := cfg.Normalize(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error containing %q, got %v", want, err)
+		}
+	}
+
+	cfg := newCfg(ServiceConfig{
+		Body:    `{"x":"{{VAR "nonce"}}"}`,
+		Headers: map[string]string{"X-Nonce": `{{VAR "nonce"}}`},
+		Vars:    map[string]string{"nonce": "{{RANDOM_UUID}}"},
+	})
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("Normalize() failed: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 预算耗尽后的处理方式
+const (
+	BudgetActionPause    = "pause"    // 当日剩余时间暂停探测该服务商
+	BudgetActionThrottle = "throttle" // 按 throttle_factor 降低探测频率
+)
+
+// 默认降频倍数
+const defaultBudgetThrottleFactor = 4
+
+// BudgetConfig 服务商每日探测预算配置
+//
+// 探测会消耗付费 token，为服务商配置每日请求数 / token 上限后，
+// 当日用量达到上限时调度器暂停或降频探测该服务商的全部监测项（UTC 零点重置）
+type BudgetConfig struct {
+	// 服务商预算（为空时不限制）
+	Providers []ProviderBudget `yaml:"providers" json:"providers,omitempty"`
+}
+
+// ProviderBudget 单个服务商的每日预算
+type ProviderBudget struct {
+	Provider string `yaml:"provider" json:"provider"` // provider 名称，匹配时忽略大小写和首尾空格
+
+	// 每日请求数上限（含重试，0 表示不限制）
+	DailyRequests int64 `yaml:"daily_requests" json:"daily_requests"`
+
+	// 每日 token 上限（0 表示不限制；需启用 usage 统计）
+	DailyTokens int64 `yaml:"daily_tokens" json:"daily_tokens"`
+
+	// 预算耗尽后的处理方式：pause（默认）/ throttle
+	Action string `yaml:"action" json:"action"`
+
+	// throttle 时的降频倍数：每 N 个巡检周期才实际探测一次（默认 4，至少 2）
+	ThrottleFactor int `yaml:"throttle_factor" json:"throttle_factor"`
+}
+
+// Enabled 是否配置了服务商预算
+func (b BudgetConfig) Enabled() bool {
+	return len(b.Providers) > 0
+}
+
+// Normalize 规范化预算配置（usageEnabled 为是否启用了 usage 统计，token 预算依赖用量解析）
+func (b *BudgetConfig) Normalize(usageEnabled bool) error {
+	seen := make(map[string]bool, len(b.Providers))
+	for i := range b.Providers {
+		p := &b.Providers[i]
+		p.Provider = strings.TrimSpace(p.Provider)
+		if p.Provider == "" {
+			return fmt.Errorf("budgets.providers[%d]: provider 不能为空", i)
+		}
+		key := strings.ToLower(p.Provider)
+		if seen[key] {
+			return fmt.Errorf("budgets.providers[%d]: provider 重复: %s", i, p.Provider)
+		}
+		seen[key] = true
+
+		if p.DailyRequests < 0 || p.DailyTokens < 0 {
+			return fmt.Errorf("budgets.providers[%d]: daily_requests / daily_tokens 不能为负数", i)
+		}
+		if p.DailyRequests == 0 && p.DailyTokens == 0 {
+			return fmt.Errorf("budgets.providers[%d]: daily_requests 与 daily_tokens 至少配置一个", i)
+		}
+		if p.DailyTokens > 0 && !usageEnabled {
+			return fmt.Errorf("budgets.providers[%d]: daily_tokens 需要启用 usage.enabled", i)
+		}
+
+		p.Action = strings.ToLower(strings.TrimSpace(p.Action))
+		if p.Action == "" {
+			p.Action = BudgetActionPause
+		}
+		if p.Action != BudgetActionPause && p.Action != BudgetActionThrottle {
+			return fmt.Errorf("budgets.providers[%d]: action 无效: %s（支持 pause/throttle）", i, p.Action)
+		}
+		if p.ThrottleFactor == 0 {
+			p.ThrottleFactor = defaultBudgetThrottleFactor
+		}
+		if p.ThrottleFactor < 2 {
+			return fmt.Errorf("budgets.providers[%d]: throttle_factor 至少为 2，当前值: %d", i, p.ThrottleFactor)
+		}
+	}
+	return nil
+}
+
+// Lookup 按 provider 查找预算（忽略大小写和首尾空格），未配置时返回 nil
+func (b BudgetConfig) Lookup(provider string) *ProviderBudget {
+	provider = strings.TrimSpace(provider)
+	for i := range b.Providers {
+		if strings.EqualFold(b.Providers[i].Provider, provider) {
+			return &b.Providers[i]
+		}
+	}
+	return nil
+}
+
+// Clone 深拷贝预算配置
+func (b BudgetConfig) Clone() BudgetConfig {
+	b.Providers = append([]ProviderBudget(nil), b.Providers...)
+	return b
+}
@@ -0,0 +1,49 @@
+package config
+
+import "testing"
+
+func TestBudgetConfigNormalize(t *testing.T) {
+	t.Parallel()
+
+	cfg := BudgetConfig{Providers: []ProviderBudget{
+		{Provider: " Relay ", DailyRequests: 1000},
+		{Provider: "other", DailyTokens: 5000, Action: " Throttle "},
+	}}
+	if err := cfg.Normalize(true); err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if !cfg.Enabled() || cfg.Providers[0].Provider != "Relay" || cfg.Providers[0].Action != BudgetActionPause ||
+		cfg.Providers[1].Action != BudgetActionThrottle || cfg.Providers[1].ThrottleFactor != defaultBudgetThrottleFactor {
+		t.Fatalf("unexpected providers: %+v", cfg.Providers)
+	}
+	if b := cfg.Lookup("relay"); b == nil || b.DailyRequests != 1000 {
+		t.Fatalf("lookup should ignore case: %+v", b)
+	}
+	if cfg.Lookup("missing") != nil {
+		t.Fatalf("lookup of unknown provider should return nil")
+	}
+
+	clone := cfg.Clone()
+	clone.Providers[0].DailyRequests = 1
+	if cfg.Providers[0].DailyRequests != 1000 {
+		t.Fatalf("clone shares providers slice")
+	}
+
+	for name, bad := range map[string]BudgetConfig{
+		"empty provider":     {Providers: []ProviderBudget{{DailyRequests: 1}}},
+		"duplicate provider": {Providers: []ProviderBudget{{Provider: "a", DailyRequests: 1}, {Provider: "A", DailyRequests: 2}}},
+		"no limit":           {Providers: []ProviderBudget{{Provider: "a"}}},
+		"negative limit":     {Providers: []ProviderBudget{{Provider: "a", DailyRequests: -1}}},
+		"unknown action":     {Providers: []ProviderBudget{{Provider: "a", DailyRequests: 1, Action: "stop"}}},
+		"throttle factor 1":  {Providers: []ProviderBudget{{Provider: "a", DailyRequests: 1, ThrottleFactor: 1}}},
+	} {
+		if err := bad.Normalize(true); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+
+	tokens := BudgetConfig{Providers: []ProviderBudget{{Provider: "a", DailyTokens: 100}}}
+	if err := tokens.Normalize(false); err == nil {
+		t.Fatalf("daily_tokens without usage should fail")
+	}
+}
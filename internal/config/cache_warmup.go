@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// cacheWarmupPeriods 可预热的 period（与 /api/status 支持的预设周期一致）
+var cacheWarmupPeriods = map[string]bool{
+	"90m": true, "24h": true, "1d": true, "7d": true, "30d": true, "90d": true, "180d": true,
+}
+
+// CacheWarmupConfig /api/status 响应缓存预热配置
+//
+// 启动时以及缓存被清空（配置热更新、运维标注变更）后，后台预先计算最常用的缓存 key：
+// 各 period 的默认筛选（provider=all、service=all、board=hot），以及监测项最多的前 top_providers 个服务商筛选，
+// 避免重启或热更新后第一波请求同时穿透到数据库
+type CacheWarmupConfig struct {
+	// 是否启用（默认 false）
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// 预热的 period（默认 ["24h"]）
+	Periods []string `yaml:"periods" json:"periods"`
+
+	// 额外预热的服务商筛选数量（按监测项数量降序，默认 5，0 表示仅预热默认筛选）
+	TopProviders *int `yaml:"top_providers" json:"top_providers"`
+
+	// 预热并发数（默认 2）
+	Concurrency int `yaml:"concurrency" json:"concurrency"`
+
+	// 单轮预热超时（默认 "30s"），启动时服务器在预热完成或超时后才开始监听
+	Timeout string `yaml:"timeout" json:"timeout"`
+
+	TopProvidersValue int           `yaml:"-" json:"-"`
+	TimeoutDuration   time.Duration `yaml:"-" json:"-"`
+}
+
+// Normalize 规范化 cache_warmup 配置（填充默认值并校验）
+func (c *CacheWarmupConfig) Normalize() error {
+	if len(c.Periods) == 0 {
+		c.Periods = []string{"24h"}
+	}
+	seen := make(map[string]bool, len(c.Periods))
+	periods := make([]string, 0, len(c.Periods))
+	for _, p := range c.Periods {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if !cacheWarmupPeriods[p] {
+			return fmt.Errorf("cache_warmup.periods 无效: %s（支持 90m/24h/1d/7d/30d/90d/180d）", p)
+		}
+		if !seen[p] {
+			seen[p] = true
+			periods = append(periods, p)
+		}
+	}
+	c.Periods = periods
+
+	c.TopProvidersValue = 5
+	if c.TopProviders != nil {
+		if *c.TopProviders < 0 {
+			return fmt.Errorf("cache_warmup.top_providers 不能为负数，当前值: %d", *c.TopProviders)
+		}
+		c.TopProvidersValue = *c.TopProviders
+	}
+
+	if c.Concurrency == 0 {
+		c.Concurrency = 2
+	}
+	if c.Concurrency < 1 {
+		return fmt.Errorf("cache_warmup.concurrency 必须 >= 1，当前值: %d", c.Concurrency)
+	}
+
+	if c.Timeout == "" {
+		c.Timeout = "30s"
+	}
+	timeout, err := time.ParseDuration(c.Timeout)
+	if err != nil || timeout <= 0 {
+		return fmt.Errorf("cache_warmup.timeout 无效: %s", c.Timeout)
+	}
+	c.TimeoutDuration = timeout
+	return nil
+}
+
+// Clone 深拷贝预热配置
+func (c CacheWarmupConfig) Clone() CacheWarmupConfig {
+	c.Periods = append([]string(nil), c.Periods...)
+	c.TopProviders = cloneIntPtr(c.TopProviders)
+	return c
+}
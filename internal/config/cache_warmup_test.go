@@ -0,0 +1,48 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCacheWarmupConfigNormalize(t *testing.T) {
+	t.Parallel()
+
+	var empty CacheWarmupConfig
+	if err := empty.Normalize(); err != nil {
+		t.Fatalf("empty config should normalize: %v", err)
+	}
+	if !reflect.DeepEqual(empty.Periods, []string{"24h"}) || empty.TopProvidersValue != 5 || empty.Concurrency != 2 || empty.TimeoutDuration != 30*time.Second {
+		t.Fatalf("unexpected defaults: %+v", empty)
+	}
+
+	zero := 0
+	cfg := CacheWarmupConfig{Periods: []string{" 7D ", "24h", "7d"}, TopProviders: &zero, Concurrency: 4, Timeout: "1m"}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Periods, []string{"7d", "24h"}) || cfg.TopProvidersValue != 0 || cfg.TimeoutDuration != time.Minute {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+
+	clone := cfg.Clone()
+	clone.Periods[0] = "30d"
+	*clone.TopProviders = 3
+	if cfg.Periods[0] != "7d" || *cfg.TopProviders != 0 {
+		t.Fatalf("clone shares state with original")
+	}
+
+	negative := -1
+	for name, bad := range map[string]CacheWarmupConfig{
+		"unknown period":       {Periods: []string{"45d"}},
+		"negative top":         {TopProviders: &negative},
+		"negative concurrency": {Concurrency: -1},
+		"invalid timeout":      {Timeout: "soon"},
+		"non-positive timeout": {Timeout: "0s"},
+	} {
+		if err := bad.Normalize(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
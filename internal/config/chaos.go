@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"monitor/internal/logger"
+)
+
+// 默认注入的服务端错误状态码
+const defaultChaosServerErrorCode = 503
+
+// ChaosConfig 故障注入配置（仅用于测试/预发环境）
+// 按概率让选中监测项的探测模拟超时、5xx 或慢响应，
+// 用于端到端验证事件检测、通知管道与看板，无需等待真实故障
+type ChaosConfig struct {
+	// 是否启用（默认禁用；生产环境切勿开启，注入结果会正常写入存储并触发事件）
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// 注入目标（可选，为空表示全部监测项）
+	// 格式：provider、provider/service、provider/service/channel 或 provider/service/channel/model（不区分大小写）
+	Monitors []string `yaml:"monitors" json:"monitors"`
+
+	// 模拟超时的概率（0-1）：等待监测项 timeout 后记为红色 network_error，不发起真实请求
+	TimeoutRate float64 `yaml:"timeout_rate" json:"timeout_rate"`
+
+	// 模拟服务端错误的概率（0-1）：直接记为红色 server_error，不发起真实请求
+	ServerErrorRate float64 `yaml:"server_error_rate" json:"server_error_rate"`
+
+	// 模拟服务端错误时记录的 HTTP 状态码（500-599，默认 503）
+	ServerErrorCode int `yaml:"server_error_code" json:"server_error_code"`
+
+	// 模拟慢响应的概率（0-1）：真实探测完成后额外等待 slow_delay 并计入延迟
+	SlowRate float64 `yaml:"slow_rate" json:"slow_rate"`
+
+	// 慢响应额外延迟（可选，默认为监测项 slow_latency + 1s，确保触发黄色）
+	SlowDelay string `yaml:"slow_delay" json:"slow_delay"`
+
+	SlowDelayDuration time.Duration `yaml:"-" json:"-"`
+}
+
+// ChaosSettings 监测项生效的故障注入参数（解析后，nil 表示未命中注入目标）
+type ChaosSettings struct {
+	TimeoutRate     float64
+	ServerErrorRate float64
+	ServerErrorCode int
+	SlowRate        float64
+	SlowDelay       time.Duration
+}
+
+// Normalize 规范化故障注入配置
+func (c *ChaosConfig) Normalize() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	rates := []struct {
+		name  string
+		value float64
+	}{
+		{"timeout_rate", c.TimeoutRate},
+		{"server_error_rate", c.ServerErrorRate},
+		{"slow_rate", c.SlowRate},
+	}
+	for _, r := range rates {
+		if r.value < 0 || r.value > 1 {
+			return fmt.Errorf("chaos.%s 必须在 0-1 范围内，当前值: %g", r.name, r.value)
+		}
+	}
+	if sum := c.TimeoutRate + c.ServerErrorRate + c.SlowRate; sum > 1 {
+		return fmt.Errorf("chaos.timeout_rate + server_error_rate + slow_rate 不能超过 1，当前值: %g", sum)
+	}
+
+	if c.ServerErrorCode == 0 {
+		c.ServerErrorCode = defaultChaosServerErrorCode
+	}
+	if c.ServerErrorCode < 500 || c.ServerErrorCode > 599 {
+		return fmt.Errorf("chaos.server_error_code 必须在 500-599 范围内，当前值: %d", c.ServerErrorCode)
+	}
+
+	c.SlowDelayDuration = 0
+	if strings.TrimSpace(c.SlowDelay) != "" {
+		d, err := time.ParseDuration(strings.TrimSpace(c.SlowDelay))
+		if err != nil || d <= 0 {
+			return fmt.Errorf("chaos.slow_delay 无效: %s", c.SlowDelay)
+		}
+		c.SlowDelayDuration = d
+	}
+
+	for i, target := range c.Monitors {
+		target = strings.Trim(strings.TrimSpace(target), "/")
+		if target == "" || strings.Count(target, "/") > 3 {
+			return fmt.Errorf("chaos.monitors[%d] 格式无效: '%s'（应为 provider[/service[/channel[/model]]]）", i, c.Monitors[i])
+		}
+		c.Monitors[i] = target
+	}
+
+	logger.Warn("config", "故障注入（chaos）已启用，探测结果将按概率被模拟为超时/5xx/慢响应，仅用于测试环境",
+		"timeout_rate", c.TimeoutRate, "server_error_rate", c.ServerErrorRate, "slow_rate", c.SlowRate,
+		"targets", len(c.Monitors))
+	return nil
+}
+
+// matches 监测项是否命中注入目标（按路径前缀逐段匹配）
+func (c *ChaosConfig) matches(m *ServiceConfig) bool {
+	if len(c.Monitors) == 0 {
+		return true
+	}
+	path := []string{m.Provider, m.Service, m.Channel, m.Model}
+	for _, target := range c.Monitors {
+		segments := strings.Split(target, "/")
+		matched := true
+		for i, seg := range segments {
+			if !strings.EqualFold(strings.TrimSpace(seg), path[i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveMonitorChaos 解析监测项的故障注入参数（继承后调用）
+func (c *AppConfig) resolveMonitorChaos(m *ServiceConfig) {
+	if !c.Chaos.Enabled || !c.Chaos.matches(m) {
+		m.ChaosSettings = nil
+		return
+	}
+	slowDelay := c.Chaos.SlowDelayDuration
+	if slowDelay == 0 {
+		slowDelay = m.SlowLatencyDuration + time.Second
+	}
+	m.ChaosSettings = &ChaosSettings{
+		TimeoutRate:     c.Chaos.TimeoutRate,
+		ServerErrorRate: c.Chaos.ServerErrorRate,
+		ServerErrorCode: c.Chaos.ServerErrorCode,
+		SlowRate:        c.Chaos.SlowRate,
+		SlowDelay:       slowDelay,
+	}
+}
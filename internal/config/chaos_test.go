@@ -0,0 +1,80 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChaosConfigNormalize(t *testing.T) {
+	t.Parallel()
+
+	disabled := &ChaosConfig{TimeoutRate: 5}
+	if err := disabled.Normalize(); err != nil {
+		t.Fatalf("未启用时不应校验: %v", err)
+	}
+
+	cfg := &ChaosConfig{Enabled: true, TimeoutRate: 0.1, ServerErrorRate: 0.2, SlowRate: 0.3, Monitors: []string{" demo/cc/ "}}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("规范化失败: %v", err)
+	}
+	if cfg.ServerErrorCode != 503 || cfg.SlowDelayDuration != 0 || cfg.Monitors[0] != "demo/cc" {
+		t.Fatalf("默认值错误: %+v", cfg)
+	}
+
+	tests := []struct {
+		name string
+		cfg  ChaosConfig
+	}{
+		{"概率越界", ChaosConfig{Enabled: true, SlowRate: 1.5}},
+		{"负数概率", ChaosConfig{Enabled: true, TimeoutRate: -0.1}},
+		{"概率之和超过 1", ChaosConfig{Enabled: true, TimeoutRate: 0.5, ServerErrorRate: 0.6}},
+		{"非 5xx 状态码", ChaosConfig{Enabled: true, ServerErrorCode: 404}},
+		{"无效延迟", ChaosConfig{Enabled: true, SlowDelay: "soon"}},
+		{"目标层级过深", ChaosConfig{Enabled: true, Monitors: []string{"a/b/c/d/e"}}},
+		{"空目标", ChaosConfig{Enabled: true, Monitors: []string{" "}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Normalize(); err == nil {
+				t.Fatalf("期望报错")
+			}
+		})
+	}
+}
+
+func TestResolveMonitorChaos(t *testing.T) {
+	t.Parallel()
+
+	c := &AppConfig{Chaos: ChaosConfig{Enabled: true, ServerErrorRate: 0.5, Monitors: []string{"Demo/cc", "other/cx/vip/gpt-4o"}}}
+	if err := c.Chaos.Normalize(); err != nil {
+		t.Fatalf("规范化失败: %v", err)
+	}
+
+	tests := []struct {
+		m    ServiceConfig
+		want bool
+	}{
+		{ServiceConfig{Provider: "demo", Service: "cc", Channel: "vip", Model: "opus"}, true},
+		{ServiceConfig{Provider: "demo", Service: "cx", Channel: "vip"}, false},
+		{ServiceConfig{Provider: "other", Service: "cx", Channel: "vip", Model: "gpt-4o"}, true},
+		{ServiceConfig{Provider: "other", Service: "cx", Channel: "vip", Model: "gpt-4o-mini"}, false},
+	}
+	for _, tt := range tests {
+		m := tt.m
+		m.SlowLatencyDuration = 5 * time.Second
+		c.resolveMonitorChaos(&m)
+		if (m.ChaosSettings != nil) != tt.want {
+			t.Fatalf("%s/%s/%s/%s: 期望命中=%v", m.Provider, m.Service, m.Channel, m.Model, tt.want)
+		}
+		if m.ChaosSettings != nil && (m.ChaosSettings.SlowDelay != 6*time.Second || m.ChaosSettings.ServerErrorCode != 503) {
+			t.Fatalf("解析参数错误: %+v", m.ChaosSettings)
+		}
+	}
+
+	c.Chaos.Enabled = false
+	m := ServiceConfig{Provider: "demo", Service: "cc"}
+	c.resolveMonitorChaos(&m)
+	if m.ChaosSettings != nil {
+		t.Fatalf("未启用时不应注入")
+	}
+}
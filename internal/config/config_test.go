@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -707,6 +708,80 @@ func TestCacheTTLNormalize(t *testing.T) {
 	}
 }
 
+// TestCacheTTLStaleAndError tests stale-while-revalidate and error TTL defaults
+func TestCacheTTLStaleAndError(t *testing.T) {
+	t.Parallel()
+
+	var cfg CacheTTLConfig
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("Normalize() error: %v", err)
+	}
+	if cfg.StaleDuration != DefaultCacheStaleTTL || cfg.ErrorDuration != DefaultCacheErrorTTL {
+		t.Fatalf("unexpected defaults: stale=%v error=%v", cfg.StaleDuration, cfg.ErrorDuration)
+	}
+
+	// "0s" 关闭
+	cfg = CacheTTLConfig{Stale: "0s", Error: "0s"}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("Normalize() error: %v", err)
+	}
+	if cfg.StaleDuration != 0 || cfg.ErrorDuration != 0 {
+		t.Fatalf("expected disabled, got stale=%v error=%v", cfg.StaleDuration, cfg.ErrorDuration)
+	}
+
+	for _, bad := range []CacheTTLConfig{{Stale: "-1s"}, {Error: "soon"}} {
+		if err := bad.Normalize(); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
+// TestCacheTTLEndpoints tests per-endpoint cache settings and bypass providers
+func TestCacheTTLEndpoints(t *testing.T) {
+	t.Parallel()
+
+	cfg := CacheTTLConfig{TTL24h: "15s"}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("Normalize() error: %v", err)
+	}
+	if cfg.Status.MaxEntries != DefaultCacheMaxEntries || cfg.Events.TTLDuration != 0 ||
+		cfg.Provider.TTLDuration != 15*time.Second || cfg.Sitemap.TTLDuration != DefaultSitemapCacheTTL {
+		t.Fatalf("unexpected endpoint defaults: %+v", cfg)
+	}
+
+	cfg = CacheTTLConfig{
+		Events:          CacheEndpointConfig{TTL: "3s", MaxEntries: 20},
+		Provider:        CacheEndpointConfig{TTL: "0s"},
+		BypassProviders: []string{" Relay ", "relay", "other-slug"},
+	}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("Normalize() error: %v", err)
+	}
+	if cfg.Events.TTLDuration != 3*time.Second || cfg.Events.MaxEntries != 20 || cfg.Provider.TTLDuration != 0 {
+		t.Fatalf("unexpected endpoints: events=%+v provider=%+v", cfg.Events, cfg.Provider)
+	}
+	if len(cfg.BypassProviders) != 2 || !cfg.Bypassed("RELAY", "") || !cfg.Bypassed("Other", "other-slug") || cfg.Bypassed("Other", "") {
+		t.Fatalf("unexpected bypass providers: %v", cfg.BypassProviders)
+	}
+
+	clone := cfg.Clone()
+	clone.BypassProviders[0] = "changed"
+	if cfg.BypassProviders[0] != "Relay" {
+		t.Fatalf("clone shares bypass_providers slice")
+	}
+
+	for _, bad := range []CacheTTLConfig{
+		{Status: CacheEndpointConfig{TTL: "5s"}},
+		{Events: CacheEndpointConfig{TTL: "-1s"}},
+		{Sitemap: CacheEndpointConfig{MaxEntries: -1}},
+		{BypassProviders: []string{" "}},
+	} {
+		if err := bad.Normalize(); err == nil {
+			t.Errorf("expected error for %+v", bad)
+		}
+	}
+}
+
 // TestCacheTTLForPeriod tests CacheTTLConfig.TTLForPeriod() returns correct TTL
 func TestCacheTTLForPeriod(t *testing.T) {
 	t.Parallel()
@@ -730,6 +805,8 @@ func TestCacheTTLForPeriod(t *testing.T) {
 		{"1d", "15s"}, // 1d 和 24h 相同
 		{"7d", "2m0s"},
 		{"30d", "5m0s"},
+		{"90d", "5m0s"}, // 长周期与自定义范围复用 30d
+		{"45d", "5m0s"},
 		{"unknown", "10s"}, // 未知周期使用默认值
 	}
 
@@ -836,3 +913,76 @@ func TestAppConfigNormalizeWithInvalidCacheTTL(t *testing.T) {
 		t.Errorf("error should mention cache_ttl, got: %v", err)
 	}
 }
+
+// TestSelfTestShareNormalize tests share_retention defaults and share_secret requirement
+func TestSelfTestShareNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		share         SelfTestConfig
+		wantEnabled   bool
+		wantRetention time.Duration
+	}{
+		{"默认禁用", SelfTestConfig{}, false, 168 * time.Hour},
+		{"启用且配置密钥", SelfTestConfig{ShareEnabled: true, ShareSecret: "s3cret", ShareRetention: "24h"}, true, 24 * time.Hour},
+		{"缺少密钥时禁用", SelfTestConfig{ShareEnabled: true}, false, 168 * time.Hour},
+		{"无效保留时间回退默认值", SelfTestConfig{ShareEnabled: true, ShareSecret: "s3cret", ShareRetention: "abc"}, true, 168 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &AppConfig{SelfTest: tt.share}
+			if err := cfg.Normalize(); err != nil {
+				t.Fatalf("Normalize() failed: %v", err)
+			}
+			if cfg.SelfTest.ShareEnabled != tt.wantEnabled {
+				t.Errorf("ShareEnabled = %v, want %v", cfg.SelfTest.ShareEnabled, tt.wantEnabled)
+			}
+			if cfg.SelfTest.ShareRetentionDuration != tt.wantRetention {
+				t.Errorf("ShareRetentionDuration = %v, want %v", cfg.SelfTest.ShareRetentionDuration, tt.wantRetention)
+			}
+		})
+	}
+}
+
+// TestSelfTestChallengeNormalize tests challenge type validation and PoW defaults
+func TestSelfTestChallengeNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		challenge SelfTestChallengeConfig
+		wantType  string
+		wantErr   bool
+	}{
+		{"默认不校验", SelfTestChallengeConfig{}, SelfTestChallengeNone, false},
+		{"类型大小写归一", SelfTestChallengeConfig{Type: " PoW "}, SelfTestChallengePoW, false},
+		{"turnstile 缺少密钥", SelfTestChallengeConfig{Type: "turnstile", SiteKey: "site"}, "", true},
+		{"hcaptcha 完整配置", SelfTestChallengeConfig{Type: "hcaptcha", SiteKey: "site", SecretKey: "secret"}, SelfTestChallengeHCaptcha, false},
+		{"pow 难度超出范围", SelfTestChallengeConfig{Type: "pow", PoWDifficulty: 40}, "", true},
+		{"未知类型", SelfTestChallengeConfig{Type: "recaptcha"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.challenge
+			err := c.Normalize()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Normalize() should return error for %+v", tt.challenge)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Normalize() failed: %v", err)
+			}
+			if c.Type != tt.wantType {
+				t.Errorf("Type = %q, want %q", c.Type, tt.wantType)
+			}
+			if c.Type == SelfTestChallengePoW && (c.PoWDifficulty != 18 || c.PoWTTLDuration != 2*time.Minute) {
+				t.Errorf("PoW defaults = (%d, %v), want (18, 2m)", c.PoWDifficulty, c.PoWTTLDuration)
+			}
+		})
+	}
+}
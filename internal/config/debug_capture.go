@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DebugCaptureConfig 探测调试捕获配置
+// 记录探测的请求/响应元数据（状态码、按 redaction 策略脱敏后的请求头、响应头、截断的响应体、DNS/连接/TLS/首字节耗时）
+// 到 probe_debug 表，供 /api/admin/probe-debug 排查服务商故障
+type DebugCaptureConfig struct {
+	// 全局开关：对所有监测项启用（默认禁用；单个监测项可通过 monitors[].debug_capture 覆盖）
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// 采样率（0-1，默认 1）：满足捕获条件的探测按该比例保存
+	SampleRate *float64 `yaml:"sample_rate" json:"sample_rate"`
+
+	// 仅捕获最终失败（红色）的探测（默认 true）
+	FailuresOnly *bool `yaml:"failures_only" json:"failures_only"`
+
+	// 响应体最大保存字节数（默认 4096，超出部分截断）
+	MaxBodyBytes int `yaml:"max_body_bytes" json:"max_body_bytes"`
+
+	// 保留时长（默认 "72h"，过期记录由探测器定期清理）
+	TTL string `yaml:"ttl" json:"ttl"`
+
+	TTLDuration time.Duration `yaml:"-" json:"-"`
+}
+
+// DebugCaptureSettings 监测项生效的调试捕获参数（解析后，nil 表示未启用）
+type DebugCaptureSettings struct {
+	SampleRate   float64
+	FailuresOnly bool
+	MaxBodyBytes int
+	TTL          time.Duration
+	Redaction    RedactionConfig // 请求头、响应头与查询参数的脱敏策略
+}
+
+// Normalize 规范化调试捕获配置
+func (d *DebugCaptureConfig) Normalize() error {
+	if d.SampleRate == nil {
+		rate := 1.0
+		d.SampleRate = &rate
+	}
+	if *d.SampleRate < 0 || *d.SampleRate > 1 {
+		return fmt.Errorf("debug_capture.sample_rate 必须在 0-1 范围内，当前值: %g", *d.SampleRate)
+	}
+
+	if d.FailuresOnly == nil {
+		v := true
+		d.FailuresOnly = &v
+	}
+
+	if d.MaxBodyBytes == 0 {
+		d.MaxBodyBytes = 4096
+	}
+	if d.MaxBodyBytes < 0 || d.MaxBodyBytes > 1<<20 {
+		return fmt.Errorf("debug_capture.max_body_bytes 必须在 1-1048576 范围内，当前值: %d", d.MaxBodyBytes)
+	}
+
+	if d.TTL == "" {
+		d.TTL = "72h"
+	}
+	ttl, err := time.ParseDuration(d.TTL)
+	if err != nil || ttl <= 0 {
+		return fmt.Errorf("debug_capture.ttl 无效: %s", d.TTL)
+	}
+	d.TTLDuration = ttl
+	return nil
+}
+
+// resolveMonitorDebugCapture 解析监测项的调试捕获参数（继承后调用）
+// 优先级：monitor.debug_capture > 全局 debug_capture.enabled
+func (c *AppConfig) resolveMonitorDebugCapture(m *ServiceConfig) {
+	enabled := c.DebugCapture.Enabled
+	if m.DebugCapture != nil {
+		enabled = *m.DebugCapture
+	}
+	if !enabled {
+		m.DebugSettings = nil
+		return
+	}
+	m.DebugSettings = &DebugCaptureSettings{
+		SampleRate:   *c.DebugCapture.SampleRate,
+		FailuresOnly: *c.DebugCapture.FailuresOnly,
+		MaxBodyBytes: c.DebugCapture.MaxBodyBytes,
+		TTL:          c.DebugCapture.TTLDuration,
+		Redaction:    c.Redaction,
+	}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDebugCaptureConfigNormalize(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		var d DebugCaptureConfig
+		if err := d.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if d.SampleRate == nil || *d.SampleRate != 1 || d.FailuresOnly == nil || !*d.FailuresOnly {
+			t.Fatalf("unexpected defaults: %+v", d)
+		}
+		if d.MaxBodyBytes != 4096 || d.TTLDuration != 72*time.Hour {
+			t.Fatalf("unexpected defaults: %+v", d)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		bad := -0.1
+		cases := map[string]DebugCaptureConfig{
+			"bad rate": {SampleRate: &bad},
+			"bad body": {MaxBodyBytes: -1},
+			"bad ttl":  {TTL: "0s"},
+		}
+		for name, d := range cases {
+			if err := d.Normalize(); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+}
+
+func TestResolveMonitorDebugCapture(t *testing.T) {
+	off, on := false, true
+	cfg := &AppConfig{DebugCapture: DebugCaptureConfig{Enabled: true}}
+	if err := cfg.DebugCapture.Normalize(); err != nil {
+		t.Fatalf("Normalize() failed: %v", err)
+	}
+
+	m := ServiceConfig{}
+	cfg.resolveMonitorDebugCapture(&m)
+	if m.DebugSettings == nil || m.DebugSettings.MaxBodyBytes != 4096 || m.DebugSettings.TTL != 72*time.Hour {
+		t.Fatalf("global enabled: unexpected settings %+v", m.DebugSettings)
+	}
+
+	m = ServiceConfig{DebugCapture: &off}
+	cfg.resolveMonitorDebugCapture(&m)
+	if m.DebugSettings != nil {
+		t.Fatalf("monitor override false: expected nil settings")
+	}
+
+	cfg.DebugCapture.Enabled = false
+	m = ServiceConfig{DebugCapture: &on}
+	cfg.resolveMonitorDebugCapture(&m)
+	if m.DebugSettings == nil {
+		t.Fatalf("monitor override true: expected settings")
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// DiagnosticsConfig 运行时诊断配置（pprof 与运行时统计）
+// 用于排查自托管实例的内存增长、goroutine 泄漏等问题，无需重新编译定制版本
+//
+// GET /api/admin/runtime 始终可用（viewer 角色）；pprof 默认关闭
+type DiagnosticsConfig struct {
+	// 是否在 /api/admin/debug/pprof/ 提供 pprof（需 admin 角色，默认 false，支持热更新）
+	Pprof bool `yaml:"pprof" json:"-"`
+
+	// 独立诊断端口的监听地址（如 "127.0.0.1:6060"，默认空表示不启用）
+	// 提供 /debug/pprof/、/debug/vars 与 /debug/runtime，不做鉴权；默认只允许本机回环地址，仅在启动时生效
+	Listen string `yaml:"listen" json:"-"`
+
+	// 允许独立诊断端口绑定非回环地址（默认 false）
+	// 该端口不做鉴权，开启前需确保网络层已限制访问（防火墙、内网或 sidecar 代理）
+	AllowRemote bool `yaml:"allow_remote" json:"-"`
+}
+
+// Normalize 规范化诊断配置
+func (d *DiagnosticsConfig) Normalize() error {
+	d.Listen = strings.TrimSpace(d.Listen)
+	if d.Listen == "" {
+		return nil
+	}
+	_, port, err := net.SplitHostPort(d.Listen)
+	if err != nil {
+		return fmt.Errorf("diagnostics.listen '%s' 无效，必须是 host:port 格式（如 127.0.0.1:6060）", d.Listen)
+	}
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return fmt.Errorf("diagnostics.listen '%s' 端口无效，必须在 1-65535 之间", d.Listen)
+	}
+	if !d.AllowRemote && !d.ListenIsLoopback() {
+		return fmt.Errorf("diagnostics.listen '%s' 不是本机回环地址：诊断端口不做鉴权，如确需对外监听请设置 diagnostics.allow_remote: true", d.Listen)
+	}
+	return nil
+}
+
+// ListenIsLoopback 独立诊断端口是否只绑定本机回环地址
+func (d *DiagnosticsConfig) ListenIsLoopback() bool {
+	host, _, err := net.SplitHostPort(d.Listen)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
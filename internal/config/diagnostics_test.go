@@ -0,0 +1,50 @@
+package config
+
+import "testing"
+
+func TestDiagnosticsConfigNormalize(t *testing.T) {
+	t.Parallel()
+
+	cfg := &DiagnosticsConfig{Listen: " 127.0.0.1:6060 "}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("规范化失败: %v", err)
+	}
+	if cfg.Listen != "127.0.0.1:6060" || !cfg.ListenIsLoopback() {
+		t.Fatalf("解析结果错误: %+v", cfg)
+	}
+
+	loopback := map[string]bool{
+		"localhost:6060": true,
+		"[::1]:6060":     true,
+		":6060":          false,
+		"0.0.0.0:6060":   false,
+		"10.0.0.5:6060":  false,
+	}
+	for listen, want := range loopback {
+		d := DiagnosticsConfig{Listen: listen, AllowRemote: true}
+		if err := d.Normalize(); err != nil {
+			t.Fatalf("%s: 规范化失败: %v", listen, err)
+		}
+		if got := d.ListenIsLoopback(); got != want {
+			t.Errorf("%s: ListenIsLoopback() = %v, want %v", listen, got, want)
+		}
+
+		// 未显式允许时拒绝非回环地址
+		strict := DiagnosticsConfig{Listen: listen}
+		if err := strict.Normalize(); (err == nil) != want {
+			t.Errorf("%s: 未设置 allow_remote 时 Normalize() error = %v", listen, err)
+		}
+	}
+
+	for _, listen := range []string{"6060", "127.0.0.1", "127.0.0.1:0", "127.0.0.1:70000", "127.0.0.1:http"} {
+		d := DiagnosticsConfig{Listen: listen}
+		if err := d.Normalize(); err == nil {
+			t.Errorf("%s: 期望返回错误", listen)
+		}
+	}
+
+	empty := &DiagnosticsConfig{}
+	if err := empty.Normalize(); err != nil || empty.Listen != "" {
+		t.Fatalf("空配置应通过: %v", err)
+	}
+}
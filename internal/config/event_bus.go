@@ -0,0 +1,208 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// 事件总线默认值
+const (
+	defaultEventBusEventTopic    = "relay-pulse.events"
+	defaultEventBusTimeout       = "10s"
+	defaultEventBusQueueSize     = 10000
+	defaultEventBusBatchSize     = 500
+	defaultEventBusFlushInterval = "1s"
+)
+
+// EventBusConfig 事件总线发布配置（Kafka / NATS JetStream）
+// 将每条状态事件（及可选的每条探测记录）镜像发布到消息主题，供下游自行分析；修改后需重启生效
+type EventBusConfig struct {
+	// 是否启用（默认禁用）
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// 消息系统类型：kafka / nats
+	Type string `yaml:"type" json:"type"`
+
+	// 消息编码：json（默认）/ protobuf（schema 见 docs/user/config.md）
+	Format string `yaml:"format" json:"format"`
+
+	// Kafka 引导 broker 列表（host:port），type=kafka 时必填
+	Brokers []string `yaml:"brokers" json:"brokers"`
+
+	// NATS 服务地址（nats://host:4222 或 tls://host:4222），type=nats 时必填
+	URL string `yaml:"url" json:"url"`
+
+	// 是否使用 TLS 连接（nats 地址为 tls:// 时自动启用）
+	TLS bool `yaml:"tls" json:"tls"`
+
+	// 用户名/密码（NATS 鉴权或 Kafka SASL）与 NATS 令牌（可选），
+	// 支持 EVENT_BUS_USERNAME / EVENT_BUS_PASSWORD / EVENT_BUS_TOKEN 环境变量覆盖
+	Username string `yaml:"username" json:"-"`
+	Password string `yaml:"password" json:"-"`
+	Token    string `yaml:"token" json:"-"`
+
+	// Kafka SASL 机制：plain / scram-sha-256 / scram-sha-512（留空不启用 SASL）
+	SASLMechanism string `yaml:"sasl_mechanism" json:"sasl_mechanism"`
+
+	// 状态事件主题（Kafka topic / NATS subject，默认 relay-pulse.events）
+	EventTopic string `yaml:"event_topic" json:"event_topic"`
+
+	// 探测记录主题（留空则不发布探测记录）
+	RecordTopic string `yaml:"record_topic" json:"record_topic"`
+
+	// NATS 是否等待 JetStream 确认（默认 true；false 时为 core NATS 发布，不保证落盘）
+	JetStream *bool `yaml:"jetstream" json:"jetstream"`
+
+	// Kafka 确认级别：all（默认，等待全部 ISR）/ leader
+	Acks string `yaml:"acks" json:"acks"`
+
+	// 单次请求超时（默认 "10s"）
+	Timeout string `yaml:"timeout" json:"timeout"`
+
+	TimeoutDuration time.Duration `yaml:"-" json:"-"`
+
+	// 待发布消息缓冲大小（默认 10000），满时丢弃新消息（发布不能拖慢探测）
+	QueueSize int `yaml:"queue_size" json:"queue_size"`
+
+	// 单批最大消息数（默认 500）
+	BatchSize int `yaml:"batch_size" json:"batch_size"`
+
+	// 攒批最长等待时间（默认 "1s"）
+	FlushInterval string `yaml:"flush_interval" json:"flush_interval"`
+
+	FlushIntervalDuration time.Duration `yaml:"-" json:"-"`
+}
+
+// UseJetStream 返回 NATS 是否等待 JetStream 确认
+func (e *EventBusConfig) UseJetStream() bool {
+	return e.JetStream == nil || *e.JetStream
+}
+
+// Normalize 规范化事件总线配置
+func (e *EventBusConfig) Normalize() error {
+	if v := strings.TrimSpace(os.Getenv("EVENT_BUS_USERNAME")); v != "" {
+		e.Username = v
+	}
+	if v := os.Getenv("EVENT_BUS_PASSWORD"); v != "" {
+		e.Password = v
+	}
+	if v := strings.TrimSpace(os.Getenv("EVENT_BUS_TOKEN")); v != "" {
+		e.Token = v
+	}
+
+	if !e.Enabled {
+		return nil
+	}
+
+	e.Type = strings.ToLower(strings.TrimSpace(e.Type))
+	switch e.Type {
+	case "kafka":
+		brokers := make([]string, 0, len(e.Brokers))
+		for _, b := range e.Brokers {
+			b = strings.TrimSpace(b)
+			if b == "" {
+				continue
+			}
+			if !strings.Contains(b, ":") {
+				return fmt.Errorf("event_bus.brokers 需为 host:port 格式，当前值: %s", b)
+			}
+			brokers = append(brokers, b)
+		}
+		if len(brokers) == 0 {
+			return fmt.Errorf("event_bus.type=kafka 时必须配置 event_bus.brokers")
+		}
+		e.Brokers = brokers
+
+		e.Acks = strings.ToLower(strings.TrimSpace(e.Acks))
+		if e.Acks == "" {
+			e.Acks = "all"
+		}
+		if e.Acks != "all" && e.Acks != "leader" {
+			return fmt.Errorf("event_bus.acks 只能为 all 或 leader，当前值: %s", e.Acks)
+		}
+
+		e.SASLMechanism = strings.ToLower(strings.TrimSpace(e.SASLMechanism))
+		switch e.SASLMechanism {
+		case "":
+		case "plain", "scram-sha-256", "scram-sha-512":
+			if e.Username == "" || e.Password == "" {
+				return fmt.Errorf("event_bus.sasl_mechanism=%s 时必须配置 username 与 password", e.SASLMechanism)
+			}
+		default:
+			return fmt.Errorf("event_bus.sasl_mechanism 只能为 plain、scram-sha-256 或 scram-sha-512，当前值: %s", e.SASLMechanism)
+		}
+	case "nats":
+		e.URL = strings.TrimSpace(e.URL)
+		if e.URL == "" {
+			return fmt.Errorf("event_bus.type=nats 时必须配置 event_bus.url")
+		}
+		u, err := url.Parse(e.URL)
+		if err != nil || u.Host == "" || (u.Scheme != "nats" && u.Scheme != "tls") {
+			return fmt.Errorf("event_bus.url 需为 nats://host:port 或 tls://host:port，当前值: %s", e.URL)
+		}
+		if u.Scheme == "tls" {
+			e.TLS = true
+		}
+	case "":
+		return fmt.Errorf("event_bus.enabled=true 时必须配置 event_bus.type（kafka / nats）")
+	default:
+		return fmt.Errorf("event_bus.type 只能为 kafka 或 nats，当前值: %s", e.Type)
+	}
+
+	e.Format = strings.ToLower(strings.TrimSpace(e.Format))
+	if e.Format == "" {
+		e.Format = "json"
+	}
+	if e.Format != "json" && e.Format != "protobuf" {
+		return fmt.Errorf("event_bus.format 只能为 json 或 protobuf，当前值: %s", e.Format)
+	}
+
+	e.EventTopic = strings.TrimSpace(e.EventTopic)
+	if e.EventTopic == "" {
+		e.EventTopic = defaultEventBusEventTopic
+	}
+	e.RecordTopic = strings.TrimSpace(e.RecordTopic)
+	for _, topic := range []string{e.EventTopic, e.RecordTopic} {
+		if strings.ContainsAny(topic, " \t\r\n") {
+			return fmt.Errorf("event_bus 主题不能包含空白字符: %q", topic)
+		}
+	}
+	if e.RecordTopic != "" && e.RecordTopic == e.EventTopic {
+		return fmt.Errorf("event_bus.record_topic 不能与 event_topic 相同: %s", e.RecordTopic)
+	}
+
+	if e.Timeout == "" {
+		e.Timeout = defaultEventBusTimeout
+	}
+	d, err := time.ParseDuration(e.Timeout)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("event_bus.timeout 无效: %s", e.Timeout)
+	}
+	e.TimeoutDuration = d
+
+	if e.QueueSize == 0 {
+		e.QueueSize = defaultEventBusQueueSize
+	}
+	if e.QueueSize < 0 {
+		return fmt.Errorf("event_bus.queue_size 不能为负数，当前值: %d", e.QueueSize)
+	}
+	if e.BatchSize == 0 {
+		e.BatchSize = defaultEventBusBatchSize
+	}
+	if e.BatchSize < 0 || e.BatchSize > e.QueueSize {
+		return fmt.Errorf("event_bus.batch_size 必须在 1-%d（queue_size）范围内，当前值: %d", e.QueueSize, e.BatchSize)
+	}
+
+	if e.FlushInterval == "" {
+		e.FlushInterval = defaultEventBusFlushInterval
+	}
+	fi, err := time.ParseDuration(e.FlushInterval)
+	if err != nil || fi <= 0 {
+		return fmt.Errorf("event_bus.flush_interval 无效: %s", e.FlushInterval)
+	}
+	e.FlushIntervalDuration = fi
+	return nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventBusConfigNormalize(t *testing.T) {
+	t.Setenv("EVENT_BUS_USERNAME", "")
+	t.Setenv("EVENT_BUS_PASSWORD", "")
+	t.Setenv("EVENT_BUS_TOKEN", "")
+
+	t.Run("disabled skips validation", func(t *testing.T) {
+		eb := EventBusConfig{Type: "rabbitmq"}
+		if err := eb.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+	})
+
+	t.Run("kafka defaults", func(t *testing.T) {
+		eb := EventBusConfig{Enabled: true, Type: "Kafka", Brokers: []string{" kafka-1:9092 ", ""}}
+		if err := eb.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if len(eb.Brokers) != 1 || eb.Brokers[0] != "kafka-1:9092" {
+			t.Fatalf("unexpected brokers: %v", eb.Brokers)
+		}
+		if eb.Type != "kafka" || eb.Format != "json" || eb.Acks != "all" || eb.EventTopic != "relay-pulse.events" {
+			t.Fatalf("unexpected defaults: %+v", eb)
+		}
+		if eb.TimeoutDuration != 10*time.Second || eb.FlushIntervalDuration != time.Second {
+			t.Fatalf("unexpected durations: %+v", eb)
+		}
+		if eb.QueueSize != 10000 || eb.BatchSize != 500 {
+			t.Fatalf("unexpected sizes: queue=%d batch=%d", eb.QueueSize, eb.BatchSize)
+		}
+	})
+
+	t.Run("kafka sasl", func(t *testing.T) {
+		t.Setenv("EVENT_BUS_PASSWORD", "s3cret")
+		eb := EventBusConfig{Enabled: true, Type: "kafka", Brokers: []string{"k:9093"}, SASLMechanism: " SCRAM-SHA-512 ", Username: "relay"}
+		if err := eb.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if eb.SASLMechanism != "scram-sha-512" || eb.Password != "s3cret" {
+			t.Fatalf("unexpected config: %+v", eb)
+		}
+	})
+
+	t.Run("nats tls scheme", func(t *testing.T) {
+		t.Setenv("EVENT_BUS_TOKEN", "s3cret")
+		eb := EventBusConfig{Enabled: true, Type: "nats", URL: "tls://nats.example.com:4222", Format: "protobuf"}
+		if err := eb.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if !eb.TLS || !eb.UseJetStream() || eb.Token != "s3cret" {
+			t.Fatalf("unexpected config: %+v", eb)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		cases := map[string]EventBusConfig{
+			"missing type":      {Enabled: true},
+			"unknown type":      {Enabled: true, Type: "rabbitmq"},
+			"no brokers":        {Enabled: true, Type: "kafka"},
+			"broker no port":    {Enabled: true, Type: "kafka", Brokers: []string{"kafka-1"}},
+			"bad acks":          {Enabled: true, Type: "kafka", Brokers: []string{"k:9092"}, Acks: "none"},
+			"bad sasl":          {Enabled: true, Type: "kafka", Brokers: []string{"k:9092"}, SASLMechanism: "gssapi", Username: "u", Password: "p"},
+			"sasl no password":  {Enabled: true, Type: "kafka", Brokers: []string{"k:9092"}, SASLMechanism: "plain", Username: "u"},
+			"no url":            {Enabled: true, Type: "nats"},
+			"bad scheme":        {Enabled: true, Type: "nats", URL: "http://nats:4222"},
+			"bad format":        {Enabled: true, Type: "nats", URL: "nats://nats:4222", Format: "avro"},
+			"same topics":       {Enabled: true, Type: "nats", URL: "nats://nats:4222", EventTopic: "a", RecordTopic: "a"},
+			"topic whitespace":  {Enabled: true, Type: "nats", URL: "nats://nats:4222", RecordTopic: "a b"},
+			"bad timeout":       {Enabled: true, Type: "nats", URL: "nats://nats:4222", Timeout: "abc"},
+			"batch over queue":  {Enabled: true, Type: "nats", URL: "nats://nats:4222", QueueSize: 10, BatchSize: 20},
+			"bad flush":         {Enabled: true, Type: "nats", URL: "nats://nats:4222", FlushInterval: "-1s"},
+			"negative queue":    {Enabled: true, Type: "nats", URL: "nats://nats:4222", QueueSize: -1},
+			"negative batch sz": {Enabled: true, Type: "nats", URL: "nats://nats:4222", BatchSize: -1},
+		}
+		for name, eb := range cases {
+			if err := eb.Normalize(); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+}
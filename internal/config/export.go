@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// 原始探测记录导出默认值
+const (
+	defaultExportMaxRange     = "168h"
+	defaultExportMaxRows      = 100000
+	defaultExportAdminMaxRows = 5000000
+)
+
+// ExportConfig 原始探测记录导出配置（/api/export）
+// 以 CSV / JSON Lines 流式输出 probe_history，供分析工具直接拉取
+type ExportConfig struct {
+	// 是否启用（默认禁用，未启用时端点返回 404）
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// 匿名请求允许的最大时间跨度（默认 "168h"），更大的范围需携带管理 API 令牌（ADMIN_API_TOKEN）
+	MaxRange string `yaml:"max_range" json:"max_range"`
+
+	MaxRangeDuration time.Duration `yaml:"-" json:"-"`
+
+	// 匿名请求单次导出的最大行数（默认 100000），超出部分截断
+	MaxRows int `yaml:"max_rows" json:"max_rows"`
+
+	// 携带管理 API 令牌时单次导出的最大行数（默认 5000000）
+	AdminMaxRows int `yaml:"admin_max_rows" json:"admin_max_rows"`
+}
+
+// Normalize 规范化导出配置
+func (e *ExportConfig) Normalize() error {
+	if e.MaxRange == "" {
+		e.MaxRange = defaultExportMaxRange
+	}
+	d, err := time.ParseDuration(e.MaxRange)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("export.max_range 无效（需为正的时长，如 168h）: %s", e.MaxRange)
+	}
+	e.MaxRangeDuration = d
+
+	if e.MaxRows == 0 {
+		e.MaxRows = defaultExportMaxRows
+	}
+	if e.MaxRows < 0 {
+		return fmt.Errorf("export.max_rows 不能为负数，当前值: %d", e.MaxRows)
+	}
+	if e.AdminMaxRows == 0 {
+		e.AdminMaxRows = defaultExportAdminMaxRows
+	}
+	if e.AdminMaxRows < e.MaxRows {
+		return fmt.Errorf("export.admin_max_rows (%d) 不能小于 export.max_rows (%d)", e.AdminMaxRows, e.MaxRows)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -178,8 +179,25 @@ func (c *AnnouncementsConfig) Normalize() error {
 const (
 	DefaultCacheTTLShort = 10 * time.Second // 90m, 24h 默认 TTL
 	DefaultCacheTTLLong  = 60 * time.Second // 7d, 30d 默认 TTL
+
+	DefaultCacheStaleTTL = 30 * time.Second // 过期条目继续返回（同时后台刷新）的默认时长
+	DefaultCacheErrorTTL = 2 * time.Second  // 查询失败的默认缓存时长
+
+	DefaultCacheMaxEntries = 100       // 各端点缓存默认最大条目数
+	DefaultSitemapCacheTTL = time.Hour // sitemap.xml 默认缓存时长
 )
 
+// CacheEndpointConfig 单个端点的响应缓存配置
+type CacheEndpointConfig struct {
+	// 缓存 TTL（"0s" 表示不缓存）
+	TTL string `yaml:"ttl" json:"ttl"`
+
+	// 最大缓存条目数（默认 100）
+	MaxEntries int `yaml:"max_entries" json:"max_entries"`
+
+	TTLDuration time.Duration `yaml:"-" json:"-"`
+}
+
 // CacheTTLConfig API 响应缓存 TTL 配置（按 period 区分）
 type CacheTTLConfig struct {
 	// 近 90 分钟（90m）的缓存 TTL（默认 10s）
@@ -193,12 +211,39 @@ type CacheTTLConfig struct {
 
 	// 近 30 天（30d）的缓存 TTL（默认 60s）
 	TTL30d string `yaml:"30d" json:"30d"`
+	// 注意：90d/180d 与 from/to 自定义范围复用 30d 的 TTL
+
+	// 条目过期后仍可返回旧数据的时长（stale-while-revalidate，默认 30s，"0s" 关闭）
+	// 期间命中过期条目时立即返回旧数据，同时在后台刷新
+	Stale string `yaml:"stale" json:"stale"`
+
+	// 查询失败的缓存时长（默认 2s，"0s" 关闭）
+	// 期间同一 key 的请求直接返回该错误，避免数据库故障时每个请求都重新查询
+	Error string `yaml:"error" json:"error"`
+
+	// /api/status 及共用缓存的接口（排行、热力图、GraphQL 等），TTL 按上方 period 配置，仅 max_entries 生效
+	Status CacheEndpointConfig `yaml:"status" json:"status"`
+
+	// /api/events（默认 ttl "0s" 不缓存）
+	Events CacheEndpointConfig `yaml:"events" json:"events"`
+
+	// 服务商页面（/api/providers/:slug 与服务商门户，默认 ttl 沿用 24h 的 TTL）
+	Provider CacheEndpointConfig `yaml:"provider" json:"provider"`
+
+	// /sitemap.xml（默认 ttl "1h"，同时作为 Cache-Control 的 max-age）
+	Sitemap CacheEndpointConfig `yaml:"sitemap" json:"sitemap"`
+
+	// 不使用缓存的服务商（名称或 slug，不区分大小写），故障处理期间让该服务商的查询直接读取数据库
+	// 作用于按服务商筛选的 /api/status、/api/events 与服务商页面；也可通过 overrides 的 providers.<name>.no_cache 临时加入
+	BypassProviders []string `yaml:"bypass_providers" json:"bypass_providers"`
 
 	// 解析后的缓存 TTL（内部使用，不序列化）
 	TTL90mDuration time.Duration `yaml:"-" json:"-"`
 	TTL24hDuration time.Duration `yaml:"-" json:"-"`
 	TTL7dDuration  time.Duration `yaml:"-" json:"-"`
 	TTL30dDuration time.Duration `yaml:"-" json:"-"`
+	StaleDuration  time.Duration `yaml:"-" json:"-"`
+	ErrorDuration  time.Duration `yaml:"-" json:"-"`
 }
 
 // Normalize 规范化 cache_ttl 配置（填充默认值并解析 duration）
@@ -235,9 +280,89 @@ func (c *CacheTTLConfig) Normalize() error {
 		return err
 	}
 
+	// stale/error 允许 "0s" 关闭
+	parseOptional := func(name, raw string, defaultDur time.Duration) (time.Duration, error) {
+		if strings.TrimSpace(raw) == "" {
+			return defaultDur, nil
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil {
+			return 0, fmt.Errorf("cache_ttl.%s 解析失败: %w", name, err)
+		}
+		if d < 0 {
+			return 0, fmt.Errorf("cache_ttl.%s 不能为负数", name)
+		}
+		return d, nil
+	}
+	c.StaleDuration, err = parseOptional("stale", c.Stale, DefaultCacheStaleTTL)
+	if err != nil {
+		return err
+	}
+	c.ErrorDuration, err = parseOptional("error", c.Error, DefaultCacheErrorTTL)
+	if err != nil {
+		return err
+	}
+
+	// 各端点缓存（status 的 TTL 按 period 区分，不支持单独配置）
+	if strings.TrimSpace(c.Status.TTL) != "" {
+		return fmt.Errorf("cache_ttl.status.ttl 不支持，请按 period 配置 cache_ttl.90m/24h/7d/30d")
+	}
+	endpoints := []struct {
+		name       string
+		endpoint   *CacheEndpointConfig
+		defaultTTL time.Duration
+	}{
+		{"status", &c.Status, 0},
+		{"events", &c.Events, 0},
+		{"provider", &c.Provider, c.TTL24hDuration},
+		{"sitemap", &c.Sitemap, DefaultSitemapCacheTTL},
+	}
+	for _, ep := range endpoints {
+		ep.endpoint.TTLDuration, err = parseOptional(ep.name+".ttl", ep.endpoint.TTL, ep.defaultTTL)
+		if err != nil {
+			return err
+		}
+		if ep.endpoint.MaxEntries == 0 {
+			ep.endpoint.MaxEntries = DefaultCacheMaxEntries
+		}
+		if ep.endpoint.MaxEntries < 0 {
+			return fmt.Errorf("cache_ttl.%s.max_entries 不能为负数，当前值: %d", ep.name, ep.endpoint.MaxEntries)
+		}
+	}
+
+	seen := make(map[string]bool, len(c.BypassProviders))
+	bypass := make([]string, 0, len(c.BypassProviders))
+	for i, p := range c.BypassProviders {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			return fmt.Errorf("cache_ttl.bypass_providers[%d] 不能为空", i)
+		}
+		if key := strings.ToLower(p); !seen[key] {
+			seen[key] = true
+			bypass = append(bypass, p)
+		}
+	}
+	c.BypassProviders = bypass
+
 	return nil
 }
 
+// Bypassed 判断服务商是否配置为不使用缓存（name 与 slug 任一命中即可，不区分大小写）
+func (c *CacheTTLConfig) Bypassed(name, slug string) bool {
+	for _, p := range c.BypassProviders {
+		if strings.EqualFold(p, strings.TrimSpace(name)) || (slug != "" && strings.EqualFold(p, slug)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Clone 深拷贝缓存配置
+func (c CacheTTLConfig) Clone() CacheTTLConfig {
+	c.BypassProviders = append([]string(nil), c.BypassProviders...)
+	return c
+}
+
 // TTLForPeriod 根据 period 获取缓存 TTL（未配置/无效时回退默认值）
 func (c *CacheTTLConfig) TTLForPeriod(period string) time.Duration {
 	switch period {
@@ -262,6 +387,10 @@ func (c *CacheTTLConfig) TTLForPeriod(period string) time.Duration {
 		}
 		return DefaultCacheTTLLong
 	default:
+		// 90d/180d 及自定义范围（按天数表示，如 "45d"）沿用 30d 的 TTL
+		if days, err := strconv.Atoi(strings.TrimSuffix(period, "d")); err == nil && strings.HasSuffix(period, "d") && days > 1 {
+			return c.TTLForPeriod("30d")
+		}
 		return DefaultCacheTTLShort
 	}
 }
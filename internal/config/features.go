@@ -1,6 +1,10 @@
 package config
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // SelfTestConfig 自助测试功能配置
 type SelfTestConfig struct {
@@ -10,11 +14,87 @@ type SelfTestConfig struct {
 	JobTimeout         string `yaml:"job_timeout" json:"job_timeout"`                     // 单任务超时时间（默认 "30s"）
 	ResultTTL          string `yaml:"result_ttl" json:"result_ttl"`                       // 结果保留时间（默认 "2m"）
 	RateLimitPerMinute int    `yaml:"rate_limit_per_minute" json:"rate_limit_per_minute"` // IP 限流（次/分钟，默认 10）
+	BatchMaxTargets    int    `yaml:"batch_max_targets" json:"batch_max_targets"`         // 批量测试单次最多目标数（默认 5，按目标数计入限流）
 	SignatureSecret    string `yaml:"signature_secret" json:"-"`                          // 签名密钥（不返回给前端）
 
+	// 结果分享（可选）：已完成的测试结果可持久化，并通过 HMAC 签名令牌对外分享
+	ShareEnabled   bool   `yaml:"share_enabled" json:"share_enabled"`     // 是否允许生成分享链接（默认禁用）
+	ShareRetention string `yaml:"share_retention" json:"share_retention"` // 分享结果保留时间（默认 "168h"）
+	ShareSecret    string `yaml:"share_secret" json:"-"`                  // 分享令牌签名密钥（支持 SELFTEST_SHARE_SECRET 环境变量）
+
+	// 人机验证（可选）：创建任务前校验 Turnstile/hCaptcha 令牌或工作量证明
+	Challenge SelfTestChallengeConfig `yaml:"challenge" json:"challenge"`
+
 	// 解析后的时间间隔（内部使用，不序列化）
-	JobTimeoutDuration time.Duration `yaml:"-" json:"-"`
-	ResultTTLDuration  time.Duration `yaml:"-" json:"-"`
+	JobTimeoutDuration     time.Duration `yaml:"-" json:"-"`
+	ResultTTLDuration      time.Duration `yaml:"-" json:"-"`
+	ShareRetentionDuration time.Duration `yaml:"-" json:"-"`
+}
+
+// 自助测试人机验证类型
+const (
+	SelfTestChallengeNone      = "none"      // 不校验（默认）
+	SelfTestChallengeTurnstile = "turnstile" // Cloudflare Turnstile
+	SelfTestChallengeHCaptcha  = "hcaptcha"  // hCaptcha
+	SelfTestChallengePoW       = "pow"       // 工作量证明（无需第三方服务）
+)
+
+// SelfTestChallengeConfig 自助测试人机验证配置
+//
+// 客户端先调用 GET /api/selftest/challenge 获取验证参数，
+// 提交任务时在请求体中携带 challenge / challenge_response，服务端校验通过后才入队
+type SelfTestChallengeConfig struct {
+	// 验证类型：none（默认）/ turnstile / hcaptcha / pow
+	Type string `yaml:"type" json:"type"`
+
+	// CAPTCHA 站点公钥（turnstile/hcaptcha 必填，返回给前端渲染组件）
+	SiteKey string `yaml:"site_key" json:"site_key"`
+
+	// CAPTCHA 服务端密钥（turnstile/hcaptcha 必填，支持 SELFTEST_CAPTCHA_SECRET 环境变量）
+	SecretKey string `yaml:"secret_key" json:"-"`
+
+	// 工作量证明难度：sha256 结果需要的前导零比特数（默认 18，范围 8-32）
+	PoWDifficulty int `yaml:"pow_difficulty" json:"pow_difficulty"`
+
+	// 工作量证明题目有效期（默认 "2m"）
+	PoWTTL string `yaml:"pow_ttl" json:"pow_ttl"`
+
+	// 解析后的题目有效期（内部使用）
+	PoWTTLDuration time.Duration `yaml:"-" json:"-"`
+}
+
+// Normalize 规范化人机验证配置（填充默认值并校验）
+func (c *SelfTestChallengeConfig) Normalize() error {
+	c.Type = strings.ToLower(strings.TrimSpace(c.Type))
+	if c.Type == "" {
+		c.Type = SelfTestChallengeNone
+	}
+
+	switch c.Type {
+	case SelfTestChallengeNone:
+	case SelfTestChallengeTurnstile, SelfTestChallengeHCaptcha:
+		if strings.TrimSpace(c.SiteKey) == "" || strings.TrimSpace(c.SecretKey) == "" {
+			return fmt.Errorf("selftest.challenge.type=%s 时必须配置 site_key 和 secret_key", c.Type)
+		}
+	case SelfTestChallengePoW:
+		if c.PoWDifficulty == 0 {
+			c.PoWDifficulty = 18
+		}
+		if c.PoWDifficulty < 8 || c.PoWDifficulty > 32 {
+			return fmt.Errorf("selftest.challenge.pow_difficulty 必须在 8-32 之间，当前值: %d", c.PoWDifficulty)
+		}
+		if strings.TrimSpace(c.PoWTTL) == "" {
+			c.PoWTTL = "2m"
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(c.PoWTTL))
+		if err != nil || d <= 0 {
+			return fmt.Errorf("selftest.challenge.pow_ttl 格式无效: %s", c.PoWTTL)
+		}
+		c.PoWTTLDuration = d
+	default:
+		return fmt.Errorf("selftest.challenge.type 必须是 none/turnstile/hcaptcha/pow，当前值: %s", c.Type)
+	}
+	return nil
 }
 
 // EventsConfig 状态订阅通知（事件）配置
@@ -41,9 +121,26 @@ type EventsConfig struct {
 	// - "incremental"：增量维护计数，性能最优，适合大规模稳定运行的系统
 	ChannelCountMode string `yaml:"channel_count_mode" json:"channel_count_mode"`
 
+	// 证书剩余天数低于该值时触发 CERT_EXPIRING 事件（默认 14，0 表示关闭）
+	// 使用 *int 以区分"未设置(nil)"和"显式设置为 0"
+	CertExpiryDays *int `yaml:"cert_expiry_days" json:"cert_expiry_days"`
+
+	// 连续 N 次黄色（性能下降）触发 DEGRADED_START 事件（默认 0，表示关闭）
+	// 按监测项独立判定，mode=channel 时同样按模型触发
+	DegradedThreshold int `yaml:"degraded_threshold" json:"degraded_threshold"`
+
+	// 性能下降后连续 N 次绿色触发 DEGRADED_END 事件（默认 1）
+	DegradedRecoverThreshold int `yaml:"degraded_recover_threshold" json:"degraded_recover_threshold"`
+
 	// API 访问令牌（可选，空值表示无鉴权）
 	// 配置后需要在请求头中携带 Authorization: Bearer <token>
 	APIToken string `yaml:"api_token" json:"-"`
+
+	// Webhook 推送目标（可选），事件产生后由主服务直接推送
+	Webhooks []EventWebhookConfig `yaml:"webhooks" json:"-"`
+
+	// 事件发件箱（可选）：事件与待投递记录在同一事务中落库，由后台任务投递 Webhook，进程崩溃或重启后继续投递
+	Outbox EventOutboxConfig `yaml:"outbox" json:"-"`
 }
 
 // SponsorPinConfig 赞助商置顶配置
@@ -79,4 +176,102 @@ func (c *SponsorPinConfig) IsEnabled() bool {
 type BoardsConfig struct {
 	// 是否启用热板/冷板功能（默认 false，保持向后兼容）
 	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// 板块自动调整策略（默认关闭，需 enabled=true）
+	Auto BoardAutoConfig `yaml:"auto" json:"auto"`
+}
+
+// BoardAutoConfig 板块自动调整策略
+//
+// 后台按 interval 统计每个监测项最近 window_days 天的可用率与状态翻转次数，
+// 不达标时自动降入 secondary/cold，最近 recovery_days 天可用率恢复到 recover_above 后升回配置的板块；
+// 每次调整都记录为 BOARD_DEMOTED / BOARD_PROMOTED 事件。
+// 自动降入冷板的监测项继续探测（否则无法判断恢复），配置中手动设为 cold 的监测项不参与
+type BoardAutoConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// 评估间隔（默认 "1h"，不能小于 1m）
+	Interval         string        `yaml:"interval" json:"interval"`
+	IntervalDuration time.Duration `yaml:"-" json:"-"`
+
+	// 降级统计窗口天数（默认 14）
+	WindowDays int `yaml:"window_days" json:"window_days"`
+
+	// 窗口可用率（百分比）低于该值降入 secondary（默认 80）
+	SecondaryBelow float64 `yaml:"secondary_below" json:"secondary_below"`
+
+	// 窗口可用率（百分比）低于该值降入 cold（默认 0，表示不自动降入冷板）
+	ColdBelow float64 `yaml:"cold_below" json:"cold_below"`
+
+	// 窗口内 DOWN/UP 事件数达到该值视为频繁波动，降入 secondary（默认 0 表示不检测，需 events.enabled）
+	FlappingThreshold int `yaml:"flapping_threshold" json:"flapping_threshold"`
+
+	// 恢复统计窗口天数（默认 3）
+	RecoveryDays int `yaml:"recovery_days" json:"recovery_days"`
+
+	// 恢复窗口可用率（百分比）达到该值且不再频繁波动时升回配置的板块（默认 95）
+	RecoverAbove float64 `yaml:"recover_above" json:"recover_above"`
+
+	// 统计窗口内探测次数少于该值时不调整（默认 100）
+	MinSamples int `yaml:"min_samples" json:"min_samples"`
+}
+
+// Normalize 规范化板块配置（填充自动调整策略默认值并校验）
+func (b *BoardsConfig) Normalize() error {
+	a := &b.Auto
+	if !a.Enabled {
+		return nil
+	}
+	if !b.Enabled {
+		return fmt.Errorf("boards.auto.enabled 需要同时开启 boards.enabled")
+	}
+
+	if a.Interval == "" {
+		a.Interval = "1h"
+	}
+	interval, err := time.ParseDuration(a.Interval)
+	if err != nil || interval < time.Minute {
+		return fmt.Errorf("boards.auto.interval 无效（不能小于 1m）: %s", a.Interval)
+	}
+	a.IntervalDuration = interval
+
+	if a.WindowDays == 0 {
+		a.WindowDays = 14
+	}
+	if a.RecoveryDays == 0 {
+		a.RecoveryDays = 3
+	}
+	if a.WindowDays < 1 || a.WindowDays > 90 {
+		return fmt.Errorf("boards.auto.window_days 必须在 1-90 之间，当前值: %d", a.WindowDays)
+	}
+	if a.RecoveryDays < 1 || a.RecoveryDays > a.WindowDays {
+		return fmt.Errorf("boards.auto.recovery_days 必须在 1-%d 之间，当前值: %d", a.WindowDays, a.RecoveryDays)
+	}
+
+	if a.SecondaryBelow == 0 {
+		a.SecondaryBelow = 80
+	}
+	if a.RecoverAbove == 0 {
+		a.RecoverAbove = 95
+	}
+	if a.SecondaryBelow < 0 || a.SecondaryBelow > 100 {
+		return fmt.Errorf("boards.auto.secondary_below 必须在 0-100 之间（百分比），当前值: %g", a.SecondaryBelow)
+	}
+	if a.ColdBelow < 0 || a.ColdBelow > a.SecondaryBelow {
+		return fmt.Errorf("boards.auto.cold_below 必须在 0-%g 之间（不能高于 secondary_below），当前值: %g", a.SecondaryBelow, a.ColdBelow)
+	}
+	if a.RecoverAbove <= a.SecondaryBelow || a.RecoverAbove > 100 {
+		return fmt.Errorf("boards.auto.recover_above 必须高于 secondary_below 且不超过 100，当前值: %g", a.RecoverAbove)
+	}
+	if a.FlappingThreshold < 0 {
+		return fmt.Errorf("boards.auto.flapping_threshold 不能为负数，当前值: %d", a.FlappingThreshold)
+	}
+
+	if a.MinSamples == 0 {
+		a.MinSamples = 100
+	}
+	if a.MinSamples < 1 {
+		return fmt.Errorf("boards.auto.min_samples 必须大于 0，当前值: %d", a.MinSamples)
+	}
+	return nil
 }
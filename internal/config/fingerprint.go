@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 默认连续多少次探测指纹变化才判定为内容漂移
+const defaultFingerprintPersist = 3
+
+// maxFingerprintFields 指纹字段数量上限
+const maxFingerprintFields = 20
+
+// FingerprintConfig 监测项级响应指纹配置（内容漂移检测）
+//
+// 从成功响应中按路径提取若干 JSON 字段，规范化后计算哈希作为指纹；
+// 指纹持续偏离基线时将探测结果标记为黄色 content_drift 并触发 CONTENT_DRIFT 事件，
+// 用于发现服务商悄悄更换模型或调整响应格式
+type FingerprintConfig struct {
+	// 参与指纹计算的 JSON 点分路径（数字段表示数组下标，如 "model"、"content.0.type"）
+	// SSE 流式响应中每个字段取最后一次出现的值；字段缺失也会计入指纹
+	Fields []string `yaml:"fields" json:"-"`
+
+	// 连续多少次探测指纹与基线不同才判定为漂移（默认 3），用于过滤偶发的异常响应
+	Persist int `yaml:"persist" json:"-"`
+}
+
+// Normalize 校验并填充默认值
+func (f *FingerprintConfig) Normalize() error {
+	if f == nil {
+		return nil
+	}
+	fields := make([]string, 0, len(f.Fields))
+	seen := make(map[string]bool, len(f.Fields))
+	for _, p := range f.Fields {
+		p = strings.TrimSpace(p)
+		if p == "" || seen[p] {
+			continue
+		}
+		if strings.HasPrefix(p, ".") || strings.HasSuffix(p, ".") || strings.Contains(p, "..") {
+			return fmt.Errorf("fingerprint.fields: 路径 '%s' 格式无效", p)
+		}
+		seen[p] = true
+		fields = append(fields, p)
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("fingerprint.fields 不能为空")
+	}
+	if len(fields) > maxFingerprintFields {
+		return fmt.Errorf("fingerprint.fields 最多 %d 个，当前: %d", maxFingerprintFields, len(fields))
+	}
+	f.Fields = fields
+
+	if f.Persist < 0 {
+		return fmt.Errorf("fingerprint.persist 不能为负数，当前值: %d", f.Persist)
+	}
+	if f.Persist == 0 {
+		f.Persist = defaultFingerprintPersist
+	}
+	return nil
+}
+
+// Clone 深拷贝指纹配置
+func (f *FingerprintConfig) Clone() *FingerprintConfig {
+	if f == nil {
+		return nil
+	}
+	cp := *f
+	cp.Fields = append([]string(nil), f.Fields...)
+	return &cp
+}
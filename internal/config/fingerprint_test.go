@@ -0,0 +1,87 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFingerprintConfigNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     *FingerprintConfig
+		wantErr bool
+	}{
+		{"未配置", nil, false},
+		{"正常", &FingerprintConfig{Fields: []string{"model", "content.0.type"}}, false},
+		{"字段为空", &FingerprintConfig{Fields: []string{" "}}, true},
+		{"路径格式无效", &FingerprintConfig{Fields: []string{"content..type"}}, true},
+		{"负数次数", &FingerprintConfig{Fields: []string{"model"}, Persist: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Normalize()
+			if tt.wantErr && err == nil {
+				t.Fatalf("期望报错")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("不期望报错: %v", err)
+			}
+		})
+	}
+
+	cfg := &FingerprintConfig{Fields: []string{" model ", "model", "type"}}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("规范化失败: %v", err)
+	}
+	if len(cfg.Fields) != 2 || cfg.Fields[0] != "model" || cfg.Persist != defaultFingerprintPersist {
+		t.Fatalf("规范化结果错误: %+v", cfg)
+	}
+}
+
+const fingerprintConfig = `
+interval: "1m"
+monitors:
+  - provider: "Alpha"
+    service: "cc"
+    channel: "vip"
+    category: "public"
+    sponsor: "alpha"
+    url: "https://alpha.example.com"
+    method: "POST"
+    model: "haiku"
+    body: '{"model":"{{MODEL}}"}'
+    fingerprint:
+      fields: ["model", "content.0.type"]
+      persist: 2
+  - provider: "Alpha"
+    service: "cc"
+    channel: "vip"
+    model: "opus"
+    parent: "Alpha/cc/vip"
+`
+
+func TestLoaderInheritsFingerprint(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, configPath, fingerprintConfig)
+
+	cfg, err := NewLoader().Load(configPath)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	parent, child := cfg.Monitors[0].Fingerprint, cfg.Monitors[1].Fingerprint
+	if child == nil || child.Persist != 2 || len(child.Fields) != 2 {
+		t.Fatalf("子通道未继承 fingerprint: %+v", child)
+	}
+	if child == parent {
+		t.Fatalf("继承的 fingerprint 应为独立副本")
+	}
+	if clone := cfg.Clone(); clone.Monitors[0].Fingerprint == parent {
+		t.Fatalf("Clone 应深拷贝 fingerprint")
+	}
+}
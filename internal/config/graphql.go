@@ -0,0 +1,28 @@
+package config
+
+import "fmt"
+
+// 默认 GraphQL 查询嵌套深度上限
+const defaultGraphQLMaxDepth = 8
+
+// GraphQLConfig GraphQL 查询端点配置（/api/graphql）
+// 以单次查询获取 provider → service → channel → timeline 等嵌套数据，
+// 底层复用 /api/status、/api/rankings 的批量查询与响应缓存
+type GraphQLConfig struct {
+	// 是否启用（默认禁用，未启用时端点返回 404）
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// 查询嵌套深度上限（默认 8），防止构造过深的查询
+	MaxDepth int `yaml:"max_depth" json:"max_depth"`
+}
+
+// Normalize 规范化 GraphQL 配置
+func (g *GraphQLConfig) Normalize() error {
+	if g.MaxDepth == 0 {
+		g.MaxDepth = defaultGraphQLMaxDepth
+	}
+	if g.MaxDepth < 0 {
+		return fmt.Errorf("graphql.max_depth 不能为负数，当前值: %d", g.MaxDepth)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"monitor/internal/logger"
+)
+
+// GroupConfig 自定义分组：跨服务商的一组监测项（如"我付费的全部 Claude 中转"）
+//
+// 公开 API 通过 /api/groups 与 /api/groups/{id}/status 返回分组及其聚合状态；
+// 除配置文件外，也可通过管理 API（/api/admin/groups）创建，两者 ID 不可重复
+type GroupConfig struct {
+	// 分组标识（必填，小写字母/数字/连字符，用作 URL 路径段）
+	ID string `yaml:"id" json:"id"`
+
+	// 显示名称（可选，默认同 id）
+	Name string `yaml:"name" json:"name"`
+
+	// 说明（可选）
+	Description string `yaml:"description" json:"description,omitempty"`
+
+	// 成员监测项（至少一个）
+	Members []GroupMemberConfig `yaml:"members" json:"members"`
+}
+
+// GroupMemberConfig 分组成员（与 POST /api/status/batch 的 keys 一致）
+type GroupMemberConfig struct {
+	Provider string `yaml:"provider" json:"provider"` // provider 名称或 slug（忽略大小写）
+	Service  string `yaml:"service" json:"service"`
+	Channel  string `yaml:"channel" json:"channel,omitempty"` // 为空时匹配未配置 channel 的监测项
+}
+
+// groupIDPattern 分组标识格式
+var groupIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,63}$`)
+
+// maxGroupMembers 单个分组的成员上限
+const maxGroupMembers = 200
+
+// Normalize 规范化并校验分组定义（成员去除首尾空格并去重，保持原有顺序）
+func (g *GroupConfig) Normalize() error {
+	g.ID = strings.TrimSpace(g.ID)
+	if !groupIDPattern.MatchString(g.ID) {
+		return fmt.Errorf("id 格式无效: '%s'（小写字母、数字或连字符，最长 64 位）", g.ID)
+	}
+	g.Name = strings.TrimSpace(g.Name)
+	if g.Name == "" {
+		g.Name = g.ID
+	}
+	g.Description = strings.TrimSpace(g.Description)
+	if len(g.Members) == 0 {
+		return fmt.Errorf("members 不能为空")
+	}
+	if len(g.Members) > maxGroupMembers {
+		return fmt.Errorf("members 最多 %d 个", maxGroupMembers)
+	}
+
+	seen := make(map[GroupMemberConfig]bool, len(g.Members))
+	members := make([]GroupMemberConfig, 0, len(g.Members))
+	for i, m := range g.Members {
+		m = GroupMemberConfig{
+			Provider: strings.TrimSpace(m.Provider),
+			Service:  strings.TrimSpace(m.Service),
+			Channel:  strings.TrimSpace(m.Channel),
+		}
+		if m.Provider == "" || m.Service == "" {
+			return fmt.Errorf("members[%d]: provider 与 service 为必填字段", i)
+		}
+		key := GroupMemberConfig{Provider: strings.ToLower(m.Provider), Service: strings.ToLower(m.Service), Channel: strings.ToLower(m.Channel)}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		members = append(members, m)
+	}
+	g.Members = members
+	return nil
+}
+
+// Matches 判断成员是否指向指定监测项（provider 匹配名称或 slug，均忽略大小写）
+func (m GroupMemberConfig) Matches(task ServiceConfig) bool {
+	if !strings.EqualFold(m.Service, strings.TrimSpace(task.Service)) || !strings.EqualFold(m.Channel, strings.TrimSpace(task.Channel)) {
+		return false
+	}
+	return strings.EqualFold(m.Provider, strings.TrimSpace(task.Provider)) ||
+		(task.ProviderSlug != "" && strings.EqualFold(m.Provider, task.ProviderSlug))
+}
+
+// validateGroups 校验自定义分组定义；成员未匹配任何监测项时仅警告（可能是尚未上线或已下线的监测项）
+func (c *AppConfig) validateGroups() error {
+	seen := make(map[string]bool, len(c.Groups))
+	for i := range c.Groups {
+		g := &c.Groups[i]
+		if err := g.Normalize(); err != nil {
+			return fmt.Errorf("groups[%d]: %w", i, err)
+		}
+		if seen[g.ID] {
+			return fmt.Errorf("groups[%d]: id '%s' 重复", i, g.ID)
+		}
+		seen[g.ID] = true
+
+		for _, m := range g.Members {
+			found := false
+			for _, task := range c.Monitors {
+				if m.Matches(task) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				logger.Warn("config", "分组成员未匹配任何监测项", "group", g.ID,
+					"provider", m.Provider, "service", m.Service, "channel", m.Channel)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateGroups(t *testing.T) {
+	t.Parallel()
+
+	cfg := AppConfig{
+		Monitors: []ServiceConfig{{Provider: "88code", ProviderSlug: "eighty-eight", Service: "cc", Channel: "vip"}},
+		Groups: []GroupConfig{{
+			ID: " my-claude ",
+			Members: []GroupMemberConfig{
+				{Provider: "88code", Service: "cc", Channel: "vip"},
+				{Provider: " 88CODE ", Service: "CC", Channel: "VIP"}, // 忽略大小写去重
+				{Provider: "other", Service: "cc"},                    // 未匹配监测项仅警告
+			},
+		}},
+	}
+	if err := cfg.validateGroups(); err != nil {
+		t.Fatalf("validateGroups failed: %v", err)
+	}
+	g := cfg.Groups[0]
+	if g.ID != "my-claude" || g.Name != "my-claude" || len(g.Members) != 2 {
+		t.Fatalf("unexpected normalized group: %+v", g)
+	}
+	if !g.Members[0].Matches(cfg.Monitors[0]) {
+		t.Fatalf("member should match monitor by provider name")
+	}
+	if !(GroupMemberConfig{Provider: "Eighty-Eight", Service: "cc", Channel: "vip"}).Matches(cfg.Monitors[0]) {
+		t.Fatalf("member should match monitor by provider slug")
+	}
+	if (GroupMemberConfig{Provider: "88code", Service: "cc"}).Matches(cfg.Monitors[0]) {
+		t.Fatalf("empty channel should only match monitors without channel")
+	}
+
+	cases := []struct {
+		name   string
+		groups []GroupConfig
+		want   string
+	}{
+		{"invalid id", []GroupConfig{{ID: "My Group", Members: []GroupMemberConfig{{Provider: "a", Service: "cc"}}}}, "格式无效"},
+		{"empty members", []GroupConfig{{ID: "a"}}, "members 不能为空"},
+		{"missing service", []GroupConfig{{ID: "a", Members: []GroupMemberConfig{{Provider: "a"}}}}, "必填"},
+		{"duplicate id", []GroupConfig{
+			{ID: "a", Members: []GroupMemberConfig{{Provider: "a", Service: "cc"}}},
+			{ID: "a", Members: []GroupMemberConfig{{Provider: "b", Service: "cc"}}},
+		}, "重复"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := AppConfig{Groups: tc.groups}
+			if err := c.validateGroups(); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("期望包含 %q 的错误，实际: %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFragment include_dir 中单个 YAML 文件允许出现的字段
+// 仅包含可合并的列表/映射字段，全局标量配置（interval、storage 等）只能写在主配置文件中
+type configFragment struct {
+	// 本文件监测项的默认命名空间（可选，监测项显式配置的 namespace 必须与之一致）
+	Namespace string `yaml:"namespace"`
+
+	Monitors                []ServiceConfig                `yaml:"monitors"`
+	DisabledProviders       []DisabledProviderConfig       `yaml:"disabled_providers"`
+	HiddenProviders         []HiddenProviderConfig         `yaml:"hidden_providers"`
+	RiskProviders           []RiskProviderConfig           `yaml:"risk_providers"`
+	BadgeProviders          []BadgeProviderConfig          `yaml:"badge_providers"`
+	ChannelDetailsProviders []ChannelDetailsProviderConfig `yaml:"channel_details_providers"`
+	BadgeDefs               map[string]BadgeDef            `yaml:"badge_definitions"`
+}
+
+// ResolveIncludeDir 返回 include_dir 的绝对路径（相对路径基于配置文件所在目录）
+// 未配置时返回空字符串
+func (c *AppConfig) ResolveIncludeDir(configDir string) string {
+	dir := strings.TrimSpace(c.IncludeDir)
+	if dir == "" {
+		return ""
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(configDir, dir)
+	}
+	return filepath.Clean(dir)
+}
+
+// MergeIncludeDir 将 include_dir 下的 *.yaml / *.yml 文件合并到当前配置
+// 文件按文件名字典序加载，保证合并结果确定；同一 provider 或徽标 ID 在多个文件中重复定义时报错
+// 返回实际加载的文件列表（用于日志）
+func (c *AppConfig) MergeIncludeDir(configDir string) ([]string, error) {
+	dir := c.ResolveIncludeDir(configDir)
+	if dir == "" {
+		return nil, nil
+	}
+
+	files, err := listIncludeFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// 记录主配置中已存在的 key，用于跨文件重复检测
+	seen := newIncludeSeen(c)
+
+	for _, file := range files {
+		frag, err := loadConfigFragment(file)
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.Base(file)
+		if err := seen.check(frag, name); err != nil {
+			return nil, err
+		}
+		if err := frag.applyNamespace(name); err != nil {
+			return nil, err
+		}
+
+		c.Monitors = append(c.Monitors, frag.Monitors...)
+		c.DisabledProviders = append(c.DisabledProviders, frag.DisabledProviders...)
+		c.HiddenProviders = append(c.HiddenProviders, frag.HiddenProviders...)
+		c.RiskProviders = append(c.RiskProviders, frag.RiskProviders...)
+		c.BadgeProviders = append(c.BadgeProviders, frag.BadgeProviders...)
+		c.ChannelDetailsProviders = append(c.ChannelDetailsProviders, frag.ChannelDetailsProviders...)
+		if len(frag.BadgeDefs) > 0 && c.BadgeDefs == nil {
+			c.BadgeDefs = make(map[string]BadgeDef, len(frag.BadgeDefs))
+		}
+		for id, bd := range frag.BadgeDefs {
+			c.BadgeDefs[id] = bd
+		}
+	}
+
+	return files, nil
+}
+
+// listIncludeFiles 列出目录下的 YAML 文件（按文件名排序，忽略隐藏文件和子目录）
+func listIncludeFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取 include_dir 失败: %w", err)
+	}
+
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !isIncludeFileName(entry.Name()) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// isIncludeFileName 判断文件名是否应作为 include 片段加载
+// 跳过隐藏文件（编辑器临时文件如 .foo.yaml.swp 等）
+func isIncludeFileName(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return false
+	}
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// loadConfigFragment 解析单个 include 文件
+// 使用 KnownFields 拒绝不可合并的字段，避免全局配置被静默忽略
+func loadConfigFragment(path string) (*configFragment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取 include 文件 %s 失败: %w", filepath.Base(path), err)
+	}
+
+	var frag configFragment
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&frag); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("解析 include 文件 %s 失败: %w", filepath.Base(path), err)
+	}
+	return &frag, nil
+}
+
+// applyNamespace 将文件级 namespace 应用到本文件的监测项
+func (frag *configFragment) applyNamespace(file string) error {
+	ns := strings.TrimSpace(frag.Namespace)
+	if ns == "" {
+		return nil
+	}
+	for i := range frag.Monitors {
+		m := &frag.Monitors[i]
+		if cur := strings.TrimSpace(m.Namespace); cur != "" && cur != ns {
+			return fmt.Errorf("include 文件 %s: monitors[%d] 的 namespace '%s' 与文件级 namespace '%s' 不一致", file, i, cur, ns)
+		}
+		m.Namespace = ns
+	}
+	return nil
+}
+
+// includeSeen 跨文件重复检测状态（key -> 来源文件）
+type includeSeen struct {
+	disabled       map[string]string
+	hidden         map[string]string
+	risk           map[string]string
+	badge          map[string]string
+	channelDetails map[string]string
+	badgeDefs      map[string]string
+}
+
+// newIncludeSeen 以主配置内容初始化重复检测状态
+func newIncludeSeen(c *AppConfig) *includeSeen {
+	const mainFile = "主配置文件"
+	s := &includeSeen{
+		disabled:       make(map[string]string),
+		hidden:         make(map[string]string),
+		risk:           make(map[string]string),
+		badge:          make(map[string]string),
+		channelDetails: make(map[string]string),
+		badgeDefs:      make(map[string]string),
+	}
+	for _, p := range c.DisabledProviders {
+		s.disabled[normalizeIncludeKey(p.Provider)] = mainFile
+	}
+	for _, p := range c.HiddenProviders {
+		s.hidden[normalizeIncludeKey(p.Provider)] = mainFile
+	}
+	for _, p := range c.RiskProviders {
+		s.risk[normalizeIncludeKey(p.Provider)] = mainFile
+	}
+	for _, p := range c.BadgeProviders {
+		s.badge[normalizeIncludeKey(p.Provider)] = mainFile
+	}
+	for _, p := range c.ChannelDetailsProviders {
+		s.channelDetails[normalizeIncludeKey(p.Provider)] = mainFile
+	}
+	for id := range c.BadgeDefs {
+		s.badgeDefs[id] = mainFile
+	}
+	return s
+}
+
+// check 检查片段中的 key 是否已在之前的文件中出现，并登记本文件的 key
+// 同一文件内部的重复交由 Validate() 处理
+func (s *includeSeen) check(frag *configFragment, file string) error {
+	type entry struct {
+		field string
+		set   map[string]string
+		keys  []string
+	}
+
+	entries := []entry{
+		{"disabled_providers", s.disabled, providerKeys(frag.DisabledProviders, func(p DisabledProviderConfig) string { return p.Provider })},
+		{"hidden_providers", s.hidden, providerKeys(frag.HiddenProviders, func(p HiddenProviderConfig) string { return p.Provider })},
+		{"risk_providers", s.risk, providerKeys(frag.RiskProviders, func(p RiskProviderConfig) string { return p.Provider })},
+		{"badge_providers", s.badge, providerKeys(frag.BadgeProviders, func(p BadgeProviderConfig) string { return p.Provider })},
+		{"channel_details_providers", s.channelDetails, providerKeys(frag.ChannelDetailsProviders, func(p ChannelDetailsProviderConfig) string { return p.Provider })},
+	}
+
+	badgeIDs := make([]string, 0, len(frag.BadgeDefs))
+	for id := range frag.BadgeDefs {
+		badgeIDs = append(badgeIDs, id)
+	}
+	sort.Strings(badgeIDs)
+	entries = append(entries, entry{"badge_definitions", s.badgeDefs, badgeIDs})
+
+	for _, e := range entries {
+		for _, key := range e.keys {
+			if prev, exists := e.set[key]; exists && prev != file {
+				return fmt.Errorf("include 文件 %s: %s '%s' 已在 %s 中定义", file, e.field, key, prev)
+			}
+		}
+		for _, key := range e.keys {
+			e.set[key] = file
+		}
+	}
+	return nil
+}
+
+// providerKeys 提取 provider 列表的归一化 key
+func providerKeys[T any](items []T, provider func(T) string) []string {
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, normalizeIncludeKey(provider(item)))
+	}
+	return keys
+}
+
+// normalizeIncludeKey provider 名称归一化（忽略大小写和首尾空格）
+func normalizeIncludeKey(provider string) string {
+	return strings.ToLower(strings.TrimSpace(provider))
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+}
+
+const includeMainConfig = `
+interval: "1m"
+include_dir: "conf.d"
+monitors:
+  - provider: "main"
+    service: "cc"
+    category: "public"
+    sponsor: "main"
+    url: "https://main.example.com"
+    method: "POST"
+`
+
+func TestLoaderMergesIncludeDirInOrder(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "config.yaml"), includeMainConfig)
+	writeTestFile(t, filepath.Join(dir, "conf.d", "20-b.yaml"), `
+monitors:
+  - provider: "b"
+    service: "cc"
+    category: "public"
+    sponsor: "b"
+    url: "https://b.example.com"
+    method: "POST"
+risk_providers:
+  - provider: "b"
+    risks:
+      - label: "跑路风险"
+`)
+	writeTestFile(t, filepath.Join(dir, "conf.d", "10-a.yml"), `
+monitors:
+  - provider: "a"
+    service: "cc"
+    category: "public"
+    sponsor: "a"
+    url: "https://a.example.com"
+    method: "POST"
+`)
+	// 非 YAML 与隐藏文件应被忽略
+	writeTestFile(t, filepath.Join(dir, "conf.d", "README.md"), "ignored")
+	writeTestFile(t, filepath.Join(dir, "conf.d", ".c.yaml"), "not: [valid")
+
+	loader := NewLoader()
+	cfg, err := loader.Load(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	var providers []string
+	for _, m := range cfg.Monitors {
+		providers = append(providers, m.Provider)
+	}
+	if got := strings.Join(providers, ","); got != "main,a,b" {
+		t.Fatalf("合并顺序不符合预期: %s", got)
+	}
+	if len(cfg.RiskProviders) != 1 || cfg.RiskProviders[0].Provider != "b" {
+		t.Fatalf("risk_providers 未合并: %+v", cfg.RiskProviders)
+	}
+	if want := filepath.Join(dir, "conf.d"); loader.GetIncludeDir() != want {
+		t.Fatalf("include_dir 路径错误: got=%s want=%s", loader.GetIncludeDir(), want)
+	}
+}
+
+func TestLoaderIncludeDirDuplicateProvider(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "config.yaml"), includeMainConfig+`
+hidden_providers:
+  - provider: "Main"
+`)
+	writeTestFile(t, filepath.Join(dir, "conf.d", "a.yaml"), `
+hidden_providers:
+  - provider: "main"
+`)
+
+	_, err := NewLoader().Load(filepath.Join(dir, "config.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "hidden_providers") {
+		t.Fatalf("期望跨文件重复 provider 报错, got=%v", err)
+	}
+}
+
+func TestLoaderIncludeDirDuplicateMonitor(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "config.yaml"), includeMainConfig)
+	writeTestFile(t, filepath.Join(dir, "conf.d", "a.yaml"), `
+monitors:
+  - provider: "main"
+    service: "cc"
+    category: "public"
+    sponsor: "main"
+    url: "https://main.example.com"
+    method: "POST"
+`)
+
+	_, err := NewLoader().Load(filepath.Join(dir, "config.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "重复的监测项") {
+		t.Fatalf("期望重复监测项报错, got=%v", err)
+	}
+}
+
+func TestLoaderIncludeDirRejectsGlobalFields(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "config.yaml"), includeMainConfig)
+	writeTestFile(t, filepath.Join(dir, "conf.d", "a.yaml"), `interval: "5m"`)
+
+	if _, err := NewLoader().Load(filepath.Join(dir, "config.yaml")); err == nil {
+		t.Fatalf("期望 include 文件包含全局字段时报错")
+	}
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 探测连接使用的 IP 地址族
+const (
+	IPFamilyAuto = "auto" // 默认：双栈地址按系统解析顺序并发拨号（Happy Eyeballs）
+	IPFamilyIPv4 = "ipv4" // 仅连接 A 记录地址
+	IPFamilyIPv6 = "ipv6" // 仅连接 AAAA 记录地址
+)
+
+// normalizeIPFamily 校验并规范化 ip_family（auto 统一存为空字符串）
+// 经代理时拨号目标是代理服务器，无法控制到服务商的地址族，因此不允许与 proxy 同时配置
+func (m *ServiceConfig) normalizeIPFamily() error {
+	family := strings.ToLower(strings.TrimSpace(m.IPFamily))
+	switch family {
+	case "", IPFamilyAuto:
+		m.IPFamily = ""
+		return nil
+	case IPFamilyIPv4, IPFamilyIPv6:
+	default:
+		return fmt.Errorf("ip_family 无效: %s（支持 auto/ipv4/ipv6）", m.IPFamily)
+	}
+	if m.Proxy != "" {
+		return fmt.Errorf("ip_family=%s 不能与 proxy 同时配置（经代理时无法控制到目标地址的地址族）", family)
+	}
+	m.IPFamily = family
+	return nil
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeIPFamily(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		family  string
+		proxy   string
+		want    string
+		wantErr bool
+	}{
+		{"未配置", "", "", "", false},
+		{"auto", " Auto ", "", "", false},
+		{"ipv4", "IPv4", "", IPFamilyIPv4, false},
+		{"ipv6", "ipv6", "", IPFamilyIPv6, false},
+		{"无效值", "ipv5", "", "", true},
+		{"auto 允许代理", "auto", "http://127.0.0.1:8080", "", false},
+		{"强制地址族不能使用代理", "ipv6", "http://127.0.0.1:8080", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &ServiceConfig{IPFamily: tt.family, Proxy: tt.proxy}
+			err := m.normalizeIPFamily()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("期望报错")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("不期望报错: %v", err)
+			}
+			if m.IPFamily != tt.want {
+				t.Fatalf("ip_family = %q, want %q", m.IPFamily, tt.want)
+			}
+		})
+	}
+}
+
+const ipFamilyConfig = `
+interval: "1m"
+monitors:
+  - provider: "Alpha"
+    service: "cc"
+    channel: "v6"
+    category: "public"
+    sponsor: "alpha"
+    url: "https://alpha.example.com"
+    method: "POST"
+    model: "haiku"
+    body: '{"model":"{{MODEL}}"}'
+    ip_family: "IPv6"
+  - provider: "Alpha"
+    service: "cc"
+    channel: "v6"
+    model: "opus"
+    parent: "Alpha/cc/v6"
+  - provider: "Alpha"
+    service: "cc"
+    channel: "v6"
+    model: "sonnet"
+    parent: "Alpha/cc/v6"
+    ip_family: "auto"
+`
+
+func TestLoaderInheritsIPFamily(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, configPath, ipFamilyConfig)
+
+	cfg, err := NewLoader().Load(configPath)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	for i, want := range []string{IPFamilyIPv6, IPFamilyIPv6, ""} {
+		if got := cfg.Monitors[i].IPFamily; got != want {
+			t.Errorf("monitors[%d].ip_family = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestLoaderRejectsIPFamilyWithProxy(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	// 代理配置在继承 ipv6 的子通道上（显式 auto 的子通道不受限制）
+	writeTestFile(t, configPath, strings.Replace(ipFamilyConfig, `    parent: "Alpha/cc/v6"
+  - provider`, `    parent: "Alpha/cc/v6"
+    proxy: "socks5://127.0.0.1:1080"
+  - provider`, 1))
+
+	_, err := NewLoader().Load(configPath)
+	if err == nil || !strings.Contains(err.Error(), "ip_family") {
+		t.Fatalf("期望 ip_family 与 proxy 冲突报错, got %v", err)
+	}
+}
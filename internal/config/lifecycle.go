@@ -53,6 +53,14 @@ func (c *AppConfig) ApplyEnvOverrides() {
 		c.Events.APIToken = envToken
 	}
 
+	// 自助测试密钥环境变量覆盖（结果分享 / 人机验证）
+	if envSecret := os.Getenv("SELFTEST_SHARE_SECRET"); envSecret != "" {
+		c.SelfTest.ShareSecret = envSecret
+	}
+	if envSecret := os.Getenv("SELFTEST_CAPTCHA_SECRET"); envSecret != "" {
+		c.SelfTest.Challenge.SecretKey = envSecret
+	}
+
 	// API Key 覆盖
 	for i := range c.Monitors {
 		m := &c.Monitors[i]
@@ -94,6 +102,14 @@ func (c *AppConfig) ResolveBodyIncludes(configDir string) error {
 			return err
 		}
 	}
+	for name, set := range c.Prompts {
+		if set == nil {
+			continue
+		}
+		if err := set.resolveIncludes(configDir, name); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -148,15 +164,21 @@ func (c *AppConfig) Clone() *AppConfig {
 		RetryJitterValue:                c.RetryJitterValue,
 		RetryJitterByService:            make(map[string]float64, len(c.RetryJitterByService)),
 		RetryJitterByServiceValue:       make(map[string]float64, len(c.RetryJitterByServiceValue)),
+		RetryOnClasses:                  c.RetryOnClasses,
 		DegradedWeight:                  c.DegradedWeight,
 		MaxConcurrency:                  c.MaxConcurrency,
 		StaggerProbes:                   staggerPtr,
+		DependencyAwareProbing:          c.DependencyAwareProbing,
+		ShutdownDrainTimeout:            c.ShutdownDrainTimeout,
+		ShutdownDrainTimeoutDuration:    c.ShutdownDrainTimeoutDuration,
 		EnableConcurrentQuery:           c.EnableConcurrentQuery,
 		ConcurrentQueryLimit:            c.ConcurrentQueryLimit,
 		EnableBatchQuery:                c.EnableBatchQuery,
 		EnableDBTimelineAgg:             c.EnableDBTimelineAgg,
 		BatchQueryMaxKeys:               c.BatchQueryMaxKeys,
-		CacheTTL:                        c.CacheTTL, // CacheTTL 是值类型，直接复制
+		MaxStatusRangeDays:              c.MaxStatusRangeDays,
+		CacheTTL:                        c.CacheTTL.Clone(),
+		CacheWarmup:                     c.CacheWarmup.Clone(),
 		Storage:                         c.Storage,
 		PublicBaseURL:                   c.PublicBaseURL,
 		DisabledProviders:               make([]DisabledProviderConfig, len(c.DisabledProviders)),
@@ -175,12 +197,54 @@ func (c *AppConfig) Clone() *AppConfig {
 			MinUptime:    c.SponsorPin.MinUptime,
 			MinLevel:     c.SponsorPin.MinLevel,
 		},
-		SelfTest:      c.SelfTest,      // SelfTest 是值类型，直接复制
-		Events:        c.Events,        // Events 是值类型，直接复制
-		Announcements: c.Announcements, // Announcements 是值类型，直接复制
-		GitHub:        c.GitHub,        // GitHub 是值类型，直接复制
-		Monitors:      make([]ServiceConfig, len(c.Monitors)),
+		SelfTest:       c.SelfTest,      // SelfTest 是值类型，直接复制
+		Events:         c.Events,        // Events 是值类型，直接复制（指针字段下方深拷贝）
+		Announcements:  c.Announcements, // Announcements 是值类型，直接复制
+		GitHub:         c.GitHub,        // GitHub 是值类型，直接复制
+		Usage:          c.Usage,
+		Rankings:       c.Rankings.Clone(),
+		ProviderStatus: c.ProviderStatus.Clone(),
+		Audit:          c.Audit,
+		AdminAuth:      c.AdminAuth,
+		Logging:        c.Logging.Clone(),
+		Tracing:        c.Tracing,
+		DebugCapture:   c.DebugCapture,
+		Redaction:      c.Redaction.Clone(),
+		Transparency:   c.Transparency,
+		SLA:            c.SLA.Clone(),
+		Budgets:        c.Budgets.Clone(),
+		GraphQL:        c.GraphQL,
+		Export:         c.Export,
+		ProviderPortal: c.ProviderPortal.Clone(),
+		EventBus:       c.EventBus,
+		Server:         c.Server,
+		Diagnostics:    c.Diagnostics,
+		Chaos:          c.Chaos,
+		IncludeDir:     c.IncludeDir,
+		Namespaces:     append([]NamespaceConfig(nil), c.Namespaces...),
+		Groups:         make([]GroupConfig, len(c.Groups)),
+		Monitors:       make([]ServiceConfig, len(c.Monitors)),
+	}
+
+	clone.Events.CertExpiryDays = cloneIntPtr(c.Events.CertExpiryDays)
+	if c.Events.Webhooks != nil {
+		clone.Events.Webhooks = make([]EventWebhookConfig, len(c.Events.Webhooks))
+		for i, w := range c.Events.Webhooks {
+			w.Types = append([]string(nil), w.Types...)
+			clone.Events.Webhooks[i] = w
+		}
 	}
+	for i, g := range c.Groups {
+		g.Members = append([]GroupMemberConfig(nil), g.Members...)
+		clone.Groups[i] = g
+	}
+	clone.Usage.TokenPaths = append([]string(nil), c.Usage.TokenPaths...)
+	clone.Chaos.Monitors = append([]string(nil), c.Chaos.Monitors...)
+	clone.EventBus.Brokers = append([]string(nil), c.EventBus.Brokers...)
+	clone.EventBus.JetStream = cloneBoolPtr(c.EventBus.JetStream)
+	clone.Tracing.SampleRatio = cloneFloat64Ptr(c.Tracing.SampleRatio)
+	clone.DebugCapture.SampleRate = cloneFloat64Ptr(c.DebugCapture.SampleRate)
+	clone.DebugCapture.FailuresOnly = cloneBoolPtr(c.DebugCapture.FailuresOnly)
 
 	// 复制 slice
 	copy(clone.DisabledProviders, c.DisabledProviders)
@@ -227,9 +291,30 @@ func (c *AppConfig) Clone() *AppConfig {
 	for k, v := range c.RetryJitterByServiceValue {
 		clone.RetryJitterByServiceValue[k] = v
 	}
+	if c.RetryOn != nil {
+		clone.RetryOn = append([]string{}, c.RetryOn...)
+	}
+	if c.RetryOnByService != nil {
+		clone.RetryOnByService = make(map[string][]string, len(c.RetryOnByService))
+		for k, v := range c.RetryOnByService {
+			clone.RetryOnByService[k] = append([]string{}, v...)
+		}
+	}
+	if c.RetryOnByServiceClasses != nil {
+		clone.RetryOnByServiceClasses = make(map[string]RetryClass, len(c.RetryOnByServiceClasses))
+		for k, v := range c.RetryOnByServiceClasses {
+			clone.RetryOnByServiceClasses[k] = v
+		}
+	}
 	for id, bd := range c.BadgeDefs {
 		clone.BadgeDefs[id] = bd
 	}
+	if c.Prompts != nil {
+		clone.Prompts = make(map[string]*PromptSetConfig, len(c.Prompts))
+		for name, set := range c.Prompts {
+			clone.Prompts[name] = set.Clone()
+		}
+	}
 
 	// 深拷贝 risk_providers 中的 risks slice
 	for i := range clone.RiskProviders {
@@ -249,6 +334,10 @@ func (c *AppConfig) Clone() *AppConfig {
 
 	// 深拷贝 monitors 中的 slice/map/指针 字段
 	for i := range clone.Monitors {
+		// 提示词集合指向副本中的同名集合
+		if c.Monitors[i].PromptSet != nil {
+			clone.Monitors[i].PromptSet = clone.Prompts[c.Monitors[i].Prompt]
+		}
 		// headers map
 		if c.Monitors[i].Headers != nil {
 			clone.Monitors[i].Headers = make(map[string]string, len(c.Monitors[i].Headers))
@@ -256,6 +345,20 @@ func (c *AppConfig) Clone() *AppConfig {
 				clone.Monitors[i].Headers[k] = v
 			}
 		}
+		// resolve map
+		if c.Monitors[i].Resolve != nil {
+			clone.Monitors[i].Resolve = make(map[string]string, len(c.Monitors[i].Resolve))
+			for k, v := range c.Monitors[i].Resolve {
+				clone.Monitors[i].Resolve[k] = v
+			}
+		}
+		// vars map
+		if c.Monitors[i].Vars != nil {
+			clone.Monitors[i].Vars = make(map[string]string, len(c.Monitors[i].Vars))
+			for k, v := range c.Monitors[i].Vars {
+				clone.Monitors[i].Vars[k] = v
+			}
+		}
 		// risks slice
 		if len(c.Monitors[i].Risks) > 0 {
 			clone.Monitors[i].Risks = make([]RiskBadge, len(c.Monitors[i].Risks))
@@ -276,6 +379,33 @@ func (c *AppConfig) Clone() *AppConfig {
 		clone.Monitors[i].PriceMax = cloneFloat64Ptr(c.Monitors[i].PriceMax)
 		clone.Monitors[i].Retry = cloneIntPtr(c.Monitors[i].Retry)
 		clone.Monitors[i].RetryJitter = cloneFloat64Ptr(c.Monitors[i].RetryJitter)
+		clone.Monitors[i].TLS = c.Monitors[i].TLS.Clone()
+		clone.Monitors[i].Fingerprint = c.Monitors[i].Fingerprint.Clone()
+		clone.Monitors[i].StatusRules = cloneStatusRules(c.Monitors[i].StatusRules)
+		if c.Monitors[i].RetryOn != nil {
+			clone.Monitors[i].RetryOn = append([]string{}, c.Monitors[i].RetryOn...)
+		}
+		if len(c.Monitors[i].ExpectedStatusCodes) > 0 {
+			clone.Monitors[i].ExpectedStatusCodes = append([]int(nil), c.Monitors[i].ExpectedStatusCodes...)
+		}
+		clone.Monitors[i].PricePer1KTokens = cloneFloat64Ptr(c.Monitors[i].PricePer1KTokens)
+		clone.Monitors[i].DebugCapture = cloneBoolPtr(c.Monitors[i].DebugCapture)
+		if c.Monitors[i].DebugSettings != nil {
+			settings := *c.Monitors[i].DebugSettings
+			settings.Redaction = settings.Redaction.Clone()
+			clone.Monitors[i].DebugSettings = &settings
+		}
+		if c.Monitors[i].ChaosSettings != nil {
+			settings := *c.Monitors[i].ChaosSettings
+			clone.Monitors[i].ChaosSettings = &settings
+		}
+		// 用量提取路径 slice
+		if len(c.Monitors[i].UsageTokenPaths) > 0 {
+			clone.Monitors[i].UsageTokenPaths = append([]string(nil), c.Monitors[i].UsageTokenPaths...)
+		}
+		if len(c.Monitors[i].UsagePaths) > 0 {
+			clone.Monitors[i].UsagePaths = append([]string(nil), c.Monitors[i].UsagePaths...)
+		}
 	}
 
 	return clone
@@ -299,6 +429,15 @@ func cloneFloat64Ptr(p *float64) *float64 {
 	return &v
 }
 
+// cloneBoolPtr 深拷贝 *bool 指针
+func cloneBoolPtr(p *bool) *bool {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
 // ShouldStaggerProbes 返回当前配置是否启用错峰探测
 func (c *AppConfig) ShouldStaggerProbes() bool {
 	if c == nil {
@@ -6,11 +6,15 @@ import (
 	"path/filepath"
 
 	"gopkg.in/yaml.v3"
+
+	"monitor/internal/logger"
 )
 
 // Loader 配置加载器
 type Loader struct {
 	currentConfig *AppConfig
+	includeDir    string      // 最近一次成功加载时 include_dir 的绝对路径
+	badges        *BadgesFile // 最近一次成功加载的 badges.yaml（不存在时为 nil）
 }
 
 // NewLoader 创建配置加载器
@@ -38,14 +42,49 @@ func (l *Loader) Load(filename string) (*AppConfig, error) {
 	}
 	configDir := filepath.Dir(absPath)
 
+	// 合并 include_dir 中的配置片段（必须在验证前完成）
+	includeFiles, err := cfg.MergeIncludeDir(configDir)
+	if err != nil {
+		return nil, fmt.Errorf("合并 include_dir 失败: %w", err)
+	}
+	if len(includeFiles) > 0 {
+		logger.Info("config", "已合并 include_dir 配置片段",
+			"dir", cfg.ResolveIncludeDir(configDir), "files", len(includeFiles))
+	}
+
+	// 叠加独立徽标文件（badges.yaml，需在验证前完成以校验监测项的徽标引用）
+	badges, err := l.loadBadges(BadgesPath(absPath))
+	if err != nil {
+		return nil, err
+	}
+	if badges != nil {
+		cfg.applyBadgesFile(badges)
+	}
+
 	// 验证配置
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("配置验证失败: %w", err)
 	}
 
+	// 叠加运行时覆盖（overrides.yaml，需在 Validate 补全子通道 provider/service/channel 之后）
+	overrides, err := LoadOverrides(OverridesPath(absPath))
+	if err != nil {
+		return nil, err
+	}
+	if !overrides.Empty() {
+		overrides.Apply(&cfg)
+		logger.Info("config", "已叠加运行时覆盖",
+			"monitors", len(overrides.Monitors), "providers", len(overrides.Providers), "revision", overrides.Revision)
+	}
+
 	// 应用环境变量覆盖
 	cfg.ApplyEnvOverrides()
 
+	// 解析 api_key 中的密钥引用（file:/vault:/aws-sm:），热更新时同样重新解析
+	if err := cfg.ResolveSecrets(); err != nil {
+		return nil, err
+	}
+
 	// 解析 body include
 	if err := cfg.ResolveBodyIncludes(configDir); err != nil {
 		return nil, err
@@ -62,9 +101,52 @@ func (l *Loader) Load(filename string) (*AppConfig, error) {
 	}
 
 	l.currentConfig = &cfg
+	l.includeDir = cfg.ResolveIncludeDir(configDir)
+	l.badges = badges
 	return &cfg, nil
 }
 
+// loadBadges 读取 badges.yaml；文件无效时沿用上次成功加载的徽标，避免徽标错误阻塞监测配置热更新
+func (l *Loader) loadBadges(path string) (*BadgesFile, error) {
+	badges, err := LoadBadgesFile(path)
+	if err == nil {
+		return badges, nil
+	}
+	if l.badges != nil {
+		logger.Error("config", "badges.yaml 无效，继续使用上次成功加载的徽标", "error", err)
+		return l.badges, nil
+	}
+	return nil, err
+}
+
+// ReloadBadges 仅重新加载 badges.yaml 并返回替换徽标后的配置（监测项等其余配置保持不变）
+// 失败时保持当前配置；badges.yaml 被删除时回退为完整加载（恢复 config.yaml 中的徽标）
+func (l *Loader) ReloadBadges(filename string) (*AppConfig, error) {
+	if l.currentConfig == nil {
+		return l.Load(filename)
+	}
+	absPath, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, fmt.Errorf("解析配置文件路径失败: %w", err)
+	}
+
+	badges, err := LoadBadgesFile(BadgesPath(absPath))
+	if err != nil {
+		return nil, err
+	}
+	if badges == nil {
+		return l.Load(filename)
+	}
+
+	newConfig, err := l.currentConfig.WithBadges(badges)
+	if err != nil {
+		return nil, fmt.Errorf("徽标配置验证失败: %w", err)
+	}
+	l.currentConfig = newConfig
+	l.badges = badges
+	return newConfig, nil
+}
+
 // LoadOrRollback 加载配置，失败时保持旧配置
 func (l *Loader) LoadOrRollback(filename string) (*AppConfig, error) {
 	newConfig, err := l.Load(filename)
@@ -82,3 +164,8 @@ func (l *Loader) LoadOrRollback(filename string) (*AppConfig, error) {
 func (l *Loader) GetCurrent() *AppConfig {
 	return l.currentConfig
 }
+
+// GetIncludeDir 获取当前生效的 include_dir 绝对路径（未配置时为空）
+func (l *Loader) GetIncludeDir() string {
+	return l.includeDir
+}
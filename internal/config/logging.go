@@ -0,0 +1,261 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"monitor/internal/logger"
+)
+
+// 日志配置默认值
+const (
+	defaultLogSamplingInitial    = 10
+	defaultLogSamplingThereafter = 100
+	defaultLogSamplingTick       = "1m"
+	defaultLogFileMaxSizeMB      = 100
+	defaultLogFileMaxAge         = "168h"
+	defaultLogFileMaxBackups     = 10
+)
+
+// logLevels 支持的日志级别
+var logLevels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// LoggingConfig 日志配置
+// 级别、格式与采样支持热更新；日志文件参数变更时热更新会切换到新文件
+type LoggingConfig struct {
+	// 默认日志级别：debug / info / warn / error（默认 info），支持 LOG_LEVEL 环境变量覆盖
+	Level string `yaml:"level" json:"level"`
+
+	// 按组件覆盖的日志级别（组件即日志中的 component 字段，如 probe: warn、scheduler: debug）
+	Components map[string]string `yaml:"components" json:"components,omitempty"`
+
+	// 输出格式：console（key=value 文本，默认）/ json（每行一个 JSON，便于 Loki 等采集），支持 LOG_FORMAT 环境变量覆盖
+	Format string `yaml:"format" json:"format"`
+
+	// 重复日志采样
+	Sampling LogSamplingConfig `yaml:"sampling" json:"sampling"`
+
+	// 日志文件（内置轮转，无需外部 logrotate）
+	File LogFileConfig `yaml:"file" json:"file"`
+
+	// 解析后的默认级别与组件级别（内部使用）
+	LevelValue      slog.Level            `yaml:"-" json:"-"`
+	ComponentLevels map[string]slog.Level `yaml:"-" json:"-"`
+}
+
+// LogSamplingConfig 重复日志采样配置
+// 每个窗口内，同一组件、级别与消息的日志先完整输出 initial 条，之后每 thereafter 条输出 1 条；
+// 下一窗口开始时输出一条丢弃汇总
+type LogSamplingConfig struct {
+	// 是否启用（默认禁用）
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// 每个窗口内完整输出的条数（默认 10）
+	Initial int `yaml:"initial" json:"initial"`
+
+	// 超出 initial 后每多少条输出 1 条（默认 100）
+	Thereafter int `yaml:"thereafter" json:"thereafter"`
+
+	// 采样窗口（默认 "1m"）
+	Tick string `yaml:"tick" json:"tick"`
+
+	TickDuration time.Duration `yaml:"-" json:"-"`
+
+	// 参与采样的组件（默认 ["probe"]，"*" 表示全部组件）
+	Components []string `yaml:"components" json:"components"`
+}
+
+// LogFileConfig 日志文件配置
+type LogFileConfig struct {
+	// 日志文件路径（为空表示只输出到 stdout），相对路径基于工作目录
+	Path string `yaml:"path" json:"path"`
+
+	// 单个文件的最大大小（MB，默认 100），超过后轮转
+	MaxSizeMB int `yaml:"max_size_mb" json:"max_size_mb"`
+
+	// 按时间轮转的间隔（如 "24h"，默认为空表示仅按大小轮转）
+	RotateInterval string `yaml:"rotate_interval" json:"rotate_interval"`
+
+	RotateIntervalDuration time.Duration `yaml:"-" json:"-"`
+
+	// 轮转文件的最长保留时间（默认 "168h"，"0s" 表示不按时间清理）
+	MaxAge string `yaml:"max_age" json:"max_age"`
+
+	MaxAgeDuration time.Duration `yaml:"-" json:"-"`
+
+	// 最多保留的轮转文件数（默认 10，-1 表示不按数量清理）
+	MaxBackups int `yaml:"max_backups" json:"max_backups"`
+
+	// 轮转后是否 gzip 压缩（默认 false）
+	Compress bool `yaml:"compress" json:"compress"`
+
+	// 写文件的同时是否输出到 stdout（默认 false）
+	Stdout bool `yaml:"stdout" json:"stdout"`
+}
+
+// Normalize 规范化日志配置
+func (l *LoggingConfig) Normalize() error {
+	if v := strings.TrimSpace(os.Getenv("LOG_LEVEL")); v != "" {
+		l.Level = v
+	}
+	if v := strings.TrimSpace(os.Getenv("LOG_FORMAT")); v != "" {
+		l.Format = v
+	}
+
+	l.Level = strings.ToLower(strings.TrimSpace(l.Level))
+	if l.Level == "" {
+		l.Level = "info"
+	}
+	level, ok := logLevels[l.Level]
+	if !ok {
+		return fmt.Errorf("logging.level 无效: %s（支持 debug/info/warn/error）", l.Level)
+	}
+	l.LevelValue = level
+
+	l.ComponentLevels = make(map[string]slog.Level, len(l.Components))
+	for component, v := range l.Components {
+		level, ok := logLevels[strings.ToLower(strings.TrimSpace(v))]
+		if !ok {
+			return fmt.Errorf("logging.components.%s 无效: %s（支持 debug/info/warn/error）", component, v)
+		}
+		l.ComponentLevels[strings.TrimSpace(component)] = level
+	}
+
+	l.Format = strings.ToLower(strings.TrimSpace(l.Format))
+	switch l.Format {
+	case "":
+		l.Format = logger.FormatConsole
+	case logger.FormatConsole, logger.FormatJSON:
+	default:
+		return fmt.Errorf("logging.format 无效: %s（支持 console/json）", l.Format)
+	}
+
+	if err := l.Sampling.normalize(); err != nil {
+		return err
+	}
+	return l.File.normalize()
+}
+
+// normalize 规范化采样配置
+func (s *LogSamplingConfig) normalize() error {
+	if s.Initial == 0 {
+		s.Initial = defaultLogSamplingInitial
+	}
+	if s.Initial < 0 {
+		return fmt.Errorf("logging.sampling.initial 不能为负数，当前值: %d", s.Initial)
+	}
+	if s.Thereafter == 0 {
+		s.Thereafter = defaultLogSamplingThereafter
+	}
+	if s.Thereafter < 0 {
+		return fmt.Errorf("logging.sampling.thereafter 不能为负数，当前值: %d", s.Thereafter)
+	}
+	if s.Tick == "" {
+		s.Tick = defaultLogSamplingTick
+	}
+	d, err := time.ParseDuration(s.Tick)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("logging.sampling.tick 无效（需为正的时长，如 1m）: %s", s.Tick)
+	}
+	s.TickDuration = d
+	if s.Components == nil {
+		s.Components = []string{"probe"}
+	}
+	for i := range s.Components {
+		s.Components[i] = strings.TrimSpace(s.Components[i])
+	}
+	return nil
+}
+
+// normalize 规范化日志文件配置
+func (f *LogFileConfig) normalize() error {
+	f.Path = strings.TrimSpace(f.Path)
+	if f.MaxSizeMB == 0 {
+		f.MaxSizeMB = defaultLogFileMaxSizeMB
+	}
+	if f.MaxSizeMB < 0 {
+		return fmt.Errorf("logging.file.max_size_mb 不能为负数，当前值: %d", f.MaxSizeMB)
+	}
+
+	f.RotateIntervalDuration = 0
+	if f.RotateInterval != "" {
+		d, err := time.ParseDuration(f.RotateInterval)
+		if err != nil || d < time.Minute {
+			return fmt.Errorf("logging.file.rotate_interval 无效（需不小于 1m，如 24h）: %s", f.RotateInterval)
+		}
+		f.RotateIntervalDuration = d
+	}
+
+	if f.MaxAge == "" {
+		f.MaxAge = defaultLogFileMaxAge
+	}
+	d, err := time.ParseDuration(f.MaxAge)
+	if err != nil || d < 0 {
+		return fmt.Errorf("logging.file.max_age 无效（如 168h，0s 表示不按时间清理）: %s", f.MaxAge)
+	}
+	f.MaxAgeDuration = d
+
+	if f.MaxBackups == 0 {
+		f.MaxBackups = defaultLogFileMaxBackups
+	}
+	if f.MaxBackups < -1 {
+		return fmt.Errorf("logging.file.max_backups 无效（-1 表示不按数量清理），当前值: %d", f.MaxBackups)
+	}
+	return nil
+}
+
+// Clone 深拷贝日志配置
+func (l LoggingConfig) Clone() LoggingConfig {
+	clone := l
+	if l.Components != nil {
+		clone.Components = make(map[string]string, len(l.Components))
+		for k, v := range l.Components {
+			clone.Components[k] = v
+		}
+	}
+	if l.ComponentLevels != nil {
+		clone.ComponentLevels = make(map[string]slog.Level, len(l.ComponentLevels))
+		for k, v := range l.ComponentLevels {
+			clone.ComponentLevels[k] = v
+		}
+	}
+	clone.Sampling.Components = append([]string(nil), l.Sampling.Components...)
+	return clone
+}
+
+// LoggerOptions 转换为 logger.Configure 的参数（需先 Normalize）
+func (l *LoggingConfig) LoggerOptions() logger.Options {
+	maxBackups := l.File.MaxBackups
+	if maxBackups < 0 {
+		maxBackups = 0
+	}
+	return logger.Options{
+		Level:      l.LevelValue,
+		Components: l.ComponentLevels,
+		Format:     l.Format,
+		Sampling: logger.SamplingOptions{
+			Enabled:    l.Sampling.Enabled,
+			Initial:    l.Sampling.Initial,
+			Thereafter: l.Sampling.Thereafter,
+			Tick:       l.Sampling.TickDuration,
+			Components: l.Sampling.Components,
+		},
+		File: logger.FileOptions{
+			Path:           l.File.Path,
+			MaxSizeMB:      l.File.MaxSizeMB,
+			RotateInterval: l.File.RotateIntervalDuration,
+			MaxAge:         l.File.MaxAgeDuration,
+			MaxBackups:     maxBackups,
+			Compress:       l.File.Compress,
+			Stdout:         l.File.Stdout,
+		},
+	}
+}
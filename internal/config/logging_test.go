@@ -0,0 +1,135 @@
+package config
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestLoggingConfigNormalize(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "")
+		t.Setenv("LOG_FORMAT", "")
+		var lc LoggingConfig
+		if err := lc.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if lc.Level != "info" || lc.LevelValue != slog.LevelInfo || lc.Format != "console" {
+			t.Fatalf("unexpected defaults: level=%s format=%s", lc.Level, lc.Format)
+		}
+		s := lc.Sampling
+		if s.Enabled || s.Initial != 10 || s.Thereafter != 100 || s.TickDuration != time.Minute {
+			t.Fatalf("unexpected sampling defaults: %+v", s)
+		}
+		if len(s.Components) != 1 || s.Components[0] != "probe" {
+			t.Fatalf("unexpected sampling components: %v", s.Components)
+		}
+		f := lc.File
+		if f.Path != "" || f.MaxSizeMB != 100 || f.MaxAgeDuration != 168*time.Hour || f.MaxBackups != 10 || f.RotateIntervalDuration != 0 {
+			t.Fatalf("unexpected file defaults: %+v", f)
+		}
+	})
+
+	t.Run("component levels", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "")
+		t.Setenv("LOG_FORMAT", "")
+		lc := LoggingConfig{
+			Level:      "WARN",
+			Format:     "json",
+			Components: map[string]string{"probe": "error", " scheduler ": "Debug"},
+		}
+		if err := lc.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if lc.LevelValue != slog.LevelWarn || lc.Format != "json" {
+			t.Fatalf("unexpected config: %+v", lc)
+		}
+		if lc.ComponentLevels["probe"] != slog.LevelError || lc.ComponentLevels["scheduler"] != slog.LevelDebug {
+			t.Fatalf("unexpected component levels: %v", lc.ComponentLevels)
+		}
+	})
+
+	t.Run("env override", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "debug")
+		t.Setenv("LOG_FORMAT", "json")
+		lc := LoggingConfig{Level: "error", Format: "console"}
+		if err := lc.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if lc.LevelValue != slog.LevelDebug || lc.Format != "json" {
+			t.Fatalf("env override not applied: level=%s format=%s", lc.Level, lc.Format)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "")
+		t.Setenv("LOG_FORMAT", "")
+		cases := map[string]LoggingConfig{
+			"level":           {Level: "verbose"},
+			"format":          {Format: "xml"},
+			"component level": {Components: map[string]string{"probe": "trace"}},
+			"sampling tick":   {Sampling: LogSamplingConfig{Tick: "0s"}},
+			"sampling initial": {
+				Sampling: LogSamplingConfig{Initial: -1},
+			},
+			"rotate interval": {File: LogFileConfig{RotateInterval: "30s"}},
+			"max age":         {File: LogFileConfig{MaxAge: "forever"}},
+			"max backups":     {File: LogFileConfig{MaxBackups: -2}},
+			"max size":        {File: LogFileConfig{MaxSizeMB: -1}},
+		}
+		for name, lc := range cases {
+			if err := lc.Normalize(); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+}
+
+func TestLoggingConfigLoggerOptions(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("LOG_FORMAT", "")
+	lc := LoggingConfig{
+		Components: map[string]string{"probe": "warn"},
+		Sampling:   LogSamplingConfig{Enabled: true, Initial: 3, Thereafter: 5, Tick: "10s", Components: []string{"*"}},
+		File: LogFileConfig{
+			Path:           "logs/relay-pulse.log",
+			RotateInterval: "24h",
+			MaxAge:         "0s",
+			MaxBackups:     -1,
+			Compress:       true,
+		},
+	}
+	if err := lc.Normalize(); err != nil {
+		t.Fatalf("Normalize() failed: %v", err)
+	}
+
+	opts := lc.LoggerOptions()
+	if opts.Level != slog.LevelInfo || opts.Components["probe"] != slog.LevelWarn {
+		t.Fatalf("unexpected levels: %+v", opts)
+	}
+	if !opts.Sampling.Enabled || opts.Sampling.Initial != 3 || opts.Sampling.Thereafter != 5 || opts.Sampling.Tick != 10*time.Second {
+		t.Fatalf("unexpected sampling: %+v", opts.Sampling)
+	}
+	f := opts.File
+	if f.Path != "logs/relay-pulse.log" || f.RotateInterval != 24*time.Hour || f.MaxAge != 0 || f.MaxBackups != 0 || !f.Compress || f.MaxSizeMB != 100 {
+		t.Fatalf("unexpected file options: %+v", f)
+	}
+}
+
+func TestLoggingConfigClone(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("LOG_FORMAT", "")
+	lc := LoggingConfig{Components: map[string]string{"probe": "warn"}}
+	if err := lc.Normalize(); err != nil {
+		t.Fatalf("Normalize() failed: %v", err)
+	}
+
+	clone := lc.Clone()
+	clone.Components["probe"] = "error"
+	clone.ComponentLevels["probe"] = slog.LevelError
+	clone.Sampling.Components[0] = "*"
+
+	if lc.Components["probe"] != "warn" || lc.ComponentLevels["probe"] != slog.LevelWarn || lc.Sampling.Components[0] != "probe" {
+		t.Fatalf("Clone() shares state with original: %+v", lc)
+	}
+}
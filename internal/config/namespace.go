@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NamespaceConfig 命名空间（租户）定义
+//
+// 监测项通过 namespace 字段归属到命名空间，公开 API 通过 /api/{ns}/status 等路由按命名空间隔离；
+// 未设置 namespace 的监测项属于默认命名空间（/api/status 等原有路由）。
+// 命名空间只影响展示隔离，监测项四元组（provider/service/channel/model）仍在全部命名空间内唯一
+type NamespaceConfig struct {
+	// 命名空间标识（必填，小写字母/数字/连字符，用作 URL 路径段）
+	Name string `yaml:"name" json:"name"`
+
+	// 显示名称（可选）
+	Title string `yaml:"title" json:"title,omitempty"`
+}
+
+// namespaceNamePattern 命名空间标识格式
+var namespaceNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// reservedNamespaceNames 与 /api/{segment} 既有路由冲突的保留名称
+var reservedNamespaceNames = map[string]bool{
+	"admin":           true,
+	"announcements":   true,
+	"config":          true,
+	"events":          true,
+	"export":          true,
+	"graphql":         true,
+	"groups":          true,
+	"heatmap":         true,
+	"models":          true,
+	"provider-portal": true,
+	"providers":       true,
+	"rankings":        true,
+	"selftest":        true,
+	"sla":             true,
+	"status":          true,
+	"transparency":    true,
+	"usage":           true,
+	"v2":              true,
+	"version":         true,
+}
+
+// HasNamespace 判断是否定义了指定命名空间
+func (c *AppConfig) HasNamespace(name string) bool {
+	for _, ns := range c.Namespaces {
+		if ns.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ForNamespace 返回仅包含指定命名空间监测项的配置视图（"" 为默认命名空间）
+// 未定义任何命名空间时直接返回自身；视图与原配置共享除 Monitors 外的全部字段，调用方不得修改
+func (c *AppConfig) ForNamespace(name string) *AppConfig {
+	if len(c.Namespaces) == 0 {
+		return c
+	}
+	view := *c
+	view.Monitors = make([]ServiceConfig, 0, len(c.Monitors))
+	for _, m := range c.Monitors {
+		if m.Namespace == name {
+			view.Monitors = append(view.Monitors, m)
+		}
+	}
+	return &view
+}
+
+// validateNamespaces 校验命名空间定义与监测项归属
+// 子通道的 namespace 为空时继承父通道，显式配置时必须与父通道一致
+func (c *AppConfig) validateNamespaces() error {
+	seen := make(map[string]bool, len(c.Namespaces))
+	for i := range c.Namespaces {
+		ns := &c.Namespaces[i]
+		ns.Name = strings.TrimSpace(ns.Name)
+		if !namespaceNamePattern.MatchString(ns.Name) {
+			return fmt.Errorf("namespaces[%d]: name 格式无效: '%s'（小写字母、数字或连字符，最长 32 位）", i, ns.Name)
+		}
+		if reservedNamespaceNames[ns.Name] {
+			return fmt.Errorf("namespaces[%d]: name '%s' 与既有 API 路由冲突", i, ns.Name)
+		}
+		if seen[ns.Name] {
+			return fmt.Errorf("namespaces[%d]: name '%s' 重复", i, ns.Name)
+		}
+		seen[ns.Name] = true
+	}
+
+	parents := make(map[string]string)
+	for i := range c.Monitors {
+		m := &c.Monitors[i]
+		m.Namespace = strings.TrimSpace(m.Namespace)
+		if m.Namespace != "" && !seen[m.Namespace] {
+			return fmt.Errorf("monitor[%d]: namespace '%s' 未在 namespaces 中定义", i, m.Namespace)
+		}
+		if strings.TrimSpace(m.Parent) == "" {
+			parents[fmt.Sprintf("%s/%s/%s", m.Provider, m.Service, m.Channel)] = m.Namespace
+		}
+	}
+	for i, m := range c.Monitors {
+		parentPath := strings.TrimSpace(m.Parent)
+		if parentPath == "" || m.Namespace == "" {
+			continue
+		}
+		if ns, ok := parents[parentPath]; ok && ns != m.Namespace {
+			return fmt.Errorf("monitor[%d]: namespace '%s' 与父通道 %s 的 namespace '%s' 不一致", i, m.Namespace, parentPath, ns)
+		}
+	}
+	return nil
+}
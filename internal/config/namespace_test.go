@@ -0,0 +1,165 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const namespaceMainConfig = `
+interval: "1m"
+include_dir: "conf.d"
+namespaces:
+  - name: "team-a"
+    title: "Team A"
+monitors:
+  - provider: "main"
+    service: "cc"
+    category: "public"
+    sponsor: "main"
+    url: "https://main.example.com"
+    method: "POST"
+    model: "m1"
+  - provider: "main"
+    service: "cc"
+    parent: "main/cc/"
+    model: "m2"
+`
+
+func TestLoaderNamespaces(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "config.yaml"), namespaceMainConfig)
+	writeTestFile(t, filepath.Join(dir, "conf.d", "team-a.yaml"), `
+namespace: "team-a"
+monitors:
+  - provider: "a"
+    service: "cc"
+    category: "public"
+    sponsor: "a"
+    url: "https://a.example.com"
+    method: "POST"
+    model: "m1"
+  - provider: "a"
+    service: "cc"
+    parent: "a/cc/"
+    model: "m2"
+`)
+
+	cfg, err := NewLoader().Load(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	for _, m := range cfg.Monitors {
+		want := ""
+		if m.Provider == "a" {
+			want = "team-a"
+		}
+		if m.Namespace != want {
+			t.Fatalf("%s/%s namespace = %q, want %q", m.Provider, m.Model, m.Namespace, want)
+		}
+	}
+
+	if got := cfg.ForNamespace("team-a").Monitors; len(got) != 2 || got[0].Provider != "a" {
+		t.Fatalf("team-a 视图不符合预期: %+v", got)
+	}
+	if got := cfg.ForNamespace("").Monitors; len(got) != 2 || got[0].Provider != "main" {
+		t.Fatalf("默认命名空间视图不符合预期: %+v", got)
+	}
+	if len(cfg.Monitors) != 4 {
+		t.Fatalf("ForNamespace 不应修改原配置: %d", len(cfg.Monitors))
+	}
+	if clone := cfg.Clone(); len(clone.Namespaces) != 1 || clone.Namespaces[0].Title != "Team A" {
+		t.Fatalf("Clone 未复制 namespaces: %+v", clone.Namespaces)
+	}
+}
+
+func TestForNamespaceWithoutNamespaces(t *testing.T) {
+	t.Parallel()
+
+	cfg := &AppConfig{Monitors: []ServiceConfig{{Provider: "a"}}}
+	if cfg.ForNamespace("") != cfg {
+		t.Fatal("未定义命名空间时应返回原配置")
+	}
+}
+
+func TestValidateNamespaces(t *testing.T) {
+	t.Parallel()
+
+	monitor := func(provider, ns string) ServiceConfig {
+		return ServiceConfig{Provider: provider, Service: "cc", Namespace: ns}
+	}
+	cases := []struct {
+		name string
+		cfg  AppConfig
+		want string
+	}{
+		{
+			name: "invalid name",
+			cfg:  AppConfig{Namespaces: []NamespaceConfig{{Name: "Team_A"}}},
+			want: "格式无效",
+		},
+		{
+			name: "reserved name",
+			cfg:  AppConfig{Namespaces: []NamespaceConfig{{Name: "status"}}},
+			want: "冲突",
+		},
+		{
+			name: "reserved sla route",
+			cfg:  AppConfig{Namespaces: []NamespaceConfig{{Name: "sla"}}},
+			want: "冲突",
+		},
+		{
+			name: "duplicate name",
+			cfg:  AppConfig{Namespaces: []NamespaceConfig{{Name: "a"}, {Name: "a"}}},
+			want: "重复",
+		},
+		{
+			name: "undefined namespace",
+			cfg:  AppConfig{Monitors: []ServiceConfig{monitor("x", "team-b")}},
+			want: "未在 namespaces 中定义",
+		},
+		{
+			name: "child mismatch",
+			cfg: AppConfig{
+				Namespaces: []NamespaceConfig{{Name: "a"}, {Name: "b"}},
+				Monitors: []ServiceConfig{
+					monitor("x", "a"),
+					{Provider: "x", Service: "cc", Parent: "x/cc/", Model: "m", Namespace: "b"},
+				},
+			},
+			want: "不一致",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.validateNamespaces()
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("期望包含 %q 的错误，实际: %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestIncludeNamespaceConflict(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "config.yaml"), namespaceMainConfig)
+	writeTestFile(t, filepath.Join(dir, "conf.d", "team-a.yaml"), `
+namespace: "team-a"
+monitors:
+  - provider: "a"
+    service: "cc"
+    category: "public"
+    sponsor: "a"
+    url: "https://a.example.com"
+    method: "POST"
+    namespace: "team-b"
+`)
+
+	if _, err := NewLoader().Load(filepath.Join(dir, "config.yaml")); err == nil || !strings.Contains(err.Error(), "不一致") {
+		t.Fatalf("文件级 namespace 冲突应报错: %v", err)
+	}
+}
@@ -20,7 +20,7 @@ func (c *AppConfig) Normalize() error {
 		return err
 	}
 
-	// 3. 功能模块配置（sponsor_pin, selftest, events, github, announcements）
+	// 3. 功能模块配置（sponsor_pin, selftest, events, github, announcements, usage）
 	if err := c.normalizeFeatureConfigs(); err != nil {
 		return err
 	}
@@ -334,6 +334,38 @@ func (c *AppConfig) normalizeGlobalTimings() error {
 		c.RetryJitterByServiceValue = nil
 	}
 
+	// 可重试类别（默认网络错误、5xx、限流）
+	if c.RetryOn == nil {
+		c.RetryOnClasses = DefaultRetryOn
+	} else {
+		classes, err := parseRetryOn(c.RetryOn, "retry_on")
+		if err != nil {
+			return err
+		}
+		c.RetryOnClasses = classes
+	}
+
+	// 按服务类型覆盖的可重试类别
+	if len(c.RetryOnByService) > 0 {
+		c.RetryOnByServiceClasses = make(map[string]RetryClass, len(c.RetryOnByService))
+		for service, values := range c.RetryOnByService {
+			key := strings.ToLower(strings.TrimSpace(service))
+			if key == "" {
+				return fmt.Errorf("retry_on_by_service: service 名称不能为空")
+			}
+			if _, exists := c.RetryOnByServiceClasses[key]; exists {
+				return fmt.Errorf("retry_on_by_service: service '%s' 重复配置（大小写不敏感）", key)
+			}
+			classes, err := parseRetryOn(values, fmt.Sprintf("retry_on_by_service[%s]", service))
+			if err != nil {
+				return err
+			}
+			c.RetryOnByServiceClasses[key] = classes
+		}
+	} else {
+		c.RetryOnByServiceClasses = nil
+	}
+
 	return nil
 }
 
@@ -377,6 +409,20 @@ func (c *AppConfig) normalizeGlobalDefaults() error {
 		c.StaggerProbes = &defaultValue
 	}
 
+	// 关闭时在途探测等待时间（默认 30s）
+	if c.ShutdownDrainTimeout == "" {
+		c.ShutdownDrainTimeoutDuration = 30 * time.Second
+	} else {
+		d, err := time.ParseDuration(c.ShutdownDrainTimeout)
+		if err != nil {
+			return fmt.Errorf("解析 shutdown_drain_timeout 失败: %w", err)
+		}
+		if d < 0 {
+			return fmt.Errorf("shutdown_drain_timeout 不能为负数")
+		}
+		c.ShutdownDrainTimeoutDuration = d
+	}
+
 	// 并发查询限制（默认 10）
 	if c.ConcurrentQueryLimit == 0 {
 		c.ConcurrentQueryLimit = 10
@@ -393,11 +439,24 @@ func (c *AppConfig) normalizeGlobalDefaults() error {
 		return fmt.Errorf("batch_query_max_keys 必须 >= 1，当前值: %d", c.BatchQueryMaxKeys)
 	}
 
+	// /api/status 最大查询天数（默认 180）
+	if c.MaxStatusRangeDays == 0 {
+		c.MaxStatusRangeDays = 180
+	}
+	if c.MaxStatusRangeDays < 1 {
+		return fmt.Errorf("max_status_range_days 必须 >= 1，当前值: %d", c.MaxStatusRangeDays)
+	}
+
 	// 缓存 TTL 配置
 	if err := c.CacheTTL.Normalize(); err != nil {
 		return err
 	}
 
+	// 缓存预热配置
+	if err := c.CacheWarmup.Normalize(); err != nil {
+		return err
+	}
+
 	// 通道技术细节暴露配置（默认 true，保持向后兼容）
 	if c.ExposeChannelDetails == nil {
 		defaultValue := true
@@ -441,8 +500,7 @@ func (c *AppConfig) normalizeFeatureConfigs() error {
 		c.SponsorPin.MinLevel = SponsorLevelBasic
 	}
 
-	// 自助测试配置默认值与解析（确保运行期与 /api/selftest/config 一致）
-	// 注意：默认值与 cmd/server/main.go 保持一致
+	// 自助测试配置默认值与解析（确保运行期与 /api/selftest/config 一致，TestJobManager 直接使用规范化后的值）
 	if c.SelfTest.MaxConcurrent <= 0 {
 		c.SelfTest.MaxConcurrent = 10
 	}
@@ -452,6 +510,9 @@ func (c *AppConfig) normalizeFeatureConfigs() error {
 	if c.SelfTest.RateLimitPerMinute <= 0 {
 		c.SelfTest.RateLimitPerMinute = 10
 	}
+	if c.SelfTest.BatchMaxTargets <= 0 {
+		c.SelfTest.BatchMaxTargets = 5
+	}
 
 	if strings.TrimSpace(c.SelfTest.JobTimeout) == "" {
 		c.SelfTest.JobTimeout = "30s"
@@ -480,6 +541,28 @@ func (c *AppConfig) normalizeFeatureConfigs() error {
 		c.SelfTest.ResultTTLDuration = d
 	}
 
+	// 结果分享：启用但缺少密钥时禁用分享（避免生成无法校验的链接）
+	if strings.TrimSpace(c.SelfTest.ShareRetention) == "" {
+		c.SelfTest.ShareRetention = "168h"
+	}
+	{
+		d, err := time.ParseDuration(strings.TrimSpace(c.SelfTest.ShareRetention))
+		if err != nil || d <= 0 {
+			logger.Warn("config", "selftest.share_retention 无效，已回退默认值", "value", c.SelfTest.ShareRetention, "default", "168h")
+			d = 168 * time.Hour
+			c.SelfTest.ShareRetention = "168h"
+		}
+		c.SelfTest.ShareRetentionDuration = d
+	}
+	if c.SelfTest.ShareEnabled && strings.TrimSpace(c.SelfTest.ShareSecret) == "" {
+		logger.Warn("config", "selftest.share_enabled 已开启但未配置 share_secret，结果分享已禁用")
+		c.SelfTest.ShareEnabled = false
+	}
+
+	if err := c.SelfTest.Challenge.Normalize(); err != nil {
+		return err
+	}
+
 	// Events 配置默认值
 	if c.Events.Mode == "" {
 		c.Events.Mode = "model" // 默认按模型独立触发事件
@@ -511,6 +594,30 @@ func (c *AppConfig) normalizeFeatureConfigs() error {
 	if c.Events.ChannelCountMode != "incremental" && c.Events.ChannelCountMode != "recompute" {
 		return fmt.Errorf("events.channel_count_mode 必须是 'incremental' 或 'recompute'，当前值: %s", c.Events.ChannelCountMode)
 	}
+	if c.Events.CertExpiryDays == nil {
+		days := 14 // 默认证书剩余不足 14 天触发 CERT_EXPIRING
+		c.Events.CertExpiryDays = &days
+	}
+	if *c.Events.CertExpiryDays < 0 {
+		return fmt.Errorf("events.cert_expiry_days 不能为负数，当前值: %d", *c.Events.CertExpiryDays)
+	}
+	if c.Events.DegradedThreshold < 0 {
+		return fmt.Errorf("events.degraded_threshold 不能为负数，当前值: %d", c.Events.DegradedThreshold)
+	}
+	if c.Events.DegradedRecoverThreshold == 0 {
+		c.Events.DegradedRecoverThreshold = 1 // 默认 1 次绿色触发 DEGRADED_END
+	}
+	if c.Events.DegradedRecoverThreshold < 1 {
+		return fmt.Errorf("events.degraded_recover_threshold 必须 >= 1，当前值: %d", c.Events.DegradedRecoverThreshold)
+	}
+	for i := range c.Events.Webhooks {
+		if err := c.Events.Webhooks[i].Normalize(i); err != nil {
+			return err
+		}
+	}
+	if err := c.Events.Outbox.Normalize(c.Events.Webhooks); err != nil {
+		return err
+	}
 
 	// GitHub 配置默认值与环境变量覆盖
 	if err := c.GitHub.Normalize(); err != nil {
@@ -522,6 +629,116 @@ func (c *AppConfig) normalizeFeatureConfigs() error {
 		return err
 	}
 
+	// 用量统计配置
+	if err := c.Usage.Normalize(); err != nil {
+		return err
+	}
+
+	// 排行榜评分配置
+	if err := c.Rankings.Normalize(); err != nil {
+		return err
+	}
+
+	// 服务商综合状态配置
+	if err := c.ProviderStatus.Normalize(); err != nil {
+		return err
+	}
+
+	// 审计日志配置
+	if err := c.Audit.Normalize(); err != nil {
+		return err
+	}
+
+	// 管理后台账号配置
+	if err := c.AdminAuth.Normalize(); err != nil {
+		return err
+	}
+
+	// 日志配置
+	if err := c.Logging.Normalize(); err != nil {
+		return err
+	}
+
+	// 分布式追踪配置
+	if err := c.Tracing.Normalize(); err != nil {
+		return err
+	}
+
+	// 探测调试捕获配置
+	if err := c.DebugCapture.Normalize(); err != nil {
+		return err
+	}
+
+	// 请求数据脱敏策略
+	if err := c.Redaction.Normalize(); err != nil {
+		return err
+	}
+
+	// 探测数据透明度配置
+	if err := c.Transparency.Normalize(); err != nil {
+		return err
+	}
+
+	// 探测提示词库
+	for name, set := range c.Prompts {
+		if strings.TrimSpace(name) == "" || set == nil {
+			return fmt.Errorf("prompts: 提示词集合名称与内容不能为空")
+		}
+		if err := set.Normalize(name); err != nil {
+			return err
+		}
+	}
+
+	// 板块自动调整策略
+	if err := c.Boards.Normalize(); err != nil {
+		return err
+	}
+
+	// 服务商 SLA 目标配置
+	if err := c.SLA.Normalize(); err != nil {
+		return err
+	}
+
+	// 服务商每日探测预算（token 预算依赖 usage 统计，需在 usage 之后处理）
+	if err := c.Budgets.Normalize(c.Usage.Enabled); err != nil {
+		return err
+	}
+
+	// GraphQL 查询端点配置
+	if err := c.GraphQL.Normalize(); err != nil {
+		return err
+	}
+
+	// 原始探测记录导出配置
+	if err := c.Export.Normalize(); err != nil {
+		return err
+	}
+
+	// 服务商数据门户配置
+	if err := c.ProviderPortal.Normalize(); err != nil {
+		return err
+	}
+
+	// 事件总线发布配置
+	if err := c.EventBus.Normalize(); err != nil {
+		return err
+	}
+
+	// HTTP 服务监听配置
+	if err := c.Server.Normalize(); err != nil {
+		return err
+	}
+
+	// 运行时诊断配置
+	if err := c.Diagnostics.Normalize(); err != nil {
+		return err
+	}
+
+	// 故障注入配置
+	if err := c.Chaos.Normalize(); err != nil {
+		return err
+	}
+
 	// 公告启用但未配置 token：仅警告（可匿名访问，但容易被限流）
 	if c.Announcements.IsEnabled() && strings.TrimSpace(c.GitHub.Token) == "" {
 		logger.Warn("config", "announcements 已启用但未配置 GITHUB_TOKEN，将使用匿名请求（可能触发限流）")
@@ -531,7 +748,7 @@ func (c *AppConfig) normalizeFeatureConfigs() error {
 }
 
 // normalizeStorageConfig 规范化存储配置
-// 包括：SQLite/PostgreSQL 配置默认值、连接池参数、retention/archive 配置
+// 包括：SQLite/PostgreSQL 配置默认值、连接池参数、retention/archive/write_batch 配置
 func (c *AppConfig) normalizeStorageConfig() error {
 	// 存储配置默认值
 	if c.Storage.Type == "" {
@@ -554,9 +771,6 @@ func (c *AppConfig) normalizeStorageConfig() error {
 
 	// DB 侧 timeline 聚合相关验证
 	if c.EnableDBTimelineAgg {
-		if c.Storage.Type != "postgres" {
-			logger.Warn("config", "enable_db_timeline_agg 仅支持 PostgreSQL，将自动回退到应用层聚合", "storage_type", c.Storage.Type)
-		}
 		if !c.EnableBatchQuery {
 			logger.Info("config", "enable_db_timeline_agg 依赖 enable_batch_query=true 才会生效")
 		}
@@ -611,6 +825,21 @@ func (c *AppConfig) normalizeStorageConfig() error {
 		return err
 	}
 
+	// 探测记录批量写入配置
+	if err := c.Storage.WriteBatch.Normalize(); err != nil {
+		return err
+	}
+
+	// 慢查询日志配置
+	if err := c.Storage.SlowQuery.Normalize(); err != nil {
+		return err
+	}
+
+	// 数据库维护配置
+	if err := c.Storage.Maintenance.Normalize(); err != nil {
+		return err
+	}
+
 	// 历史数据归档配置（仅在启用时校验）
 	if c.Storage.Archive.IsEnabled() {
 		if err := c.Storage.Archive.Normalize(); err != nil {
@@ -680,6 +909,13 @@ func (c *AppConfig) buildNormalizeIndexes(ctx *normalizeContext) error {
 		ctx.riskProviderMap[provider] = rp.Risks
 	}
 
+	c.buildBadgeIndexes(ctx)
+	return nil
+}
+
+// buildBadgeIndexes 构建徽标定义与 badge_providers 索引
+// 独立于 buildNormalizeIndexes，供 badges.yaml 单独热更新时复用
+func (c *AppConfig) buildBadgeIndexes(ctx *normalizeContext) {
 	// 构建 badges 定义 map（id -> def），并填充默认值
 	// 先加载内置默认徽标，再加载用户配置（用户配置可覆盖内置）
 
@@ -706,6 +942,4 @@ func (c *AppConfig) buildNormalizeIndexes(ctx *normalizeContext) error {
 		provider := strings.ToLower(strings.TrimSpace(bp.Provider))
 		ctx.badgeProviderMap[provider] = bp.Badges
 	}
-
-	return nil
 }
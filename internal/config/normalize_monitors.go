@@ -3,7 +3,6 @@ package config
 import (
 	"fmt"
 	"net/url"
-	"sort"
 	"strings"
 	"time"
 
@@ -24,6 +23,7 @@ func (c *AppConfig) normalizeMonitorsPreInheritance(ctx *normalizeContext) error
 		c.Monitors[i].RetryBaseDelayDuration = 0
 		c.Monitors[i].RetryMaxDelayDuration = 0
 		c.Monitors[i].RetryJitterValue = 0
+		c.Monitors[i].RetryOnClasses = 0
 		c.Monitors[i].Risks = nil          // 由 ctx.riskProviderMap 重新注入
 		c.Monitors[i].ResolvedBadges = nil // 由徽标解析逻辑重新计算（在 post-inheritance 阶段）
 
@@ -166,6 +166,20 @@ func (c *AppConfig) normalizeMonitorsPreInheritance(ctx *normalizeContext) error
 			c.Monitors[i].RetryJitterValue = c.RetryJitterValue
 		}
 
+		// retry_on 下发：monitor > by_service > global
+		if c.Monitors[i].RetryOn != nil {
+			classes, err := parseRetryOn(c.Monitors[i].RetryOn, "retry_on")
+			if err != nil {
+				return fmt.Errorf("monitor[%d] (provider=%s, service=%s, channel=%s): %w",
+					i, c.Monitors[i].Provider, c.Monitors[i].Service, c.Monitors[i].Channel, err)
+			}
+			c.Monitors[i].RetryOnClasses = classes
+		} else if v, ok := c.RetryOnByServiceClasses[serviceKey]; ok {
+			c.Monitors[i].RetryOnClasses = v
+		} else {
+			c.Monitors[i].RetryOnClasses = c.RetryOnClasses
+		}
+
 		// 最终校验：max >= base
 		if c.Monitors[i].RetryMaxDelayDuration < c.Monitors[i].RetryBaseDelayDuration {
 			return fmt.Errorf("monitor[%d] (provider=%s, service=%s, channel=%s): retry_max_delay 必须 >= retry_base_delay",
@@ -183,6 +197,7 @@ func (c *AppConfig) normalizeMonitorsPreInheritance(ctx *normalizeContext) error
 		// 规范化 URLs：去除首尾空格和末尾的 /
 		c.Monitors[i].ProviderURL = strings.TrimRight(strings.TrimSpace(c.Monitors[i].ProviderURL), "/")
 		c.Monitors[i].SponsorURL = strings.TrimRight(strings.TrimSpace(c.Monitors[i].SponsorURL), "/")
+		c.Monitors[i].ProviderLogo = strings.TrimSpace(c.Monitors[i].ProviderLogo)
 
 		// provider_slug 仅做 trim，不填充默认值（留给 post-inheritance 处理）
 		// 这样子通道可以正确继承父通道的 provider_slug 配置
@@ -215,6 +230,15 @@ func (c *AppConfig) normalizeMonitorsPreInheritance(ctx *normalizeContext) error
 			}
 		}
 
+		// 官方基线仅用于异常归因，始终隐藏（继续探测与存储）
+		c.Monitors[i].Type = strings.ToLower(strings.TrimSpace(c.Monitors[i].Type))
+		if c.Monitors[i].IsBaseline() && !c.Monitors[i].Disabled {
+			c.Monitors[i].Hidden = true
+			if strings.TrimSpace(c.Monitors[i].HiddenReason) == "" {
+				c.Monitors[i].HiddenReason = "官方基线"
+			}
+		}
+
 		// 计算最终隐藏状态：providerHidden || monitorHidden（仅对未禁用的项）
 		// 原因优先级：monitor.HiddenReason > provider.Reason
 		// 已禁用的监测项无需再覆盖隐藏原因
@@ -293,69 +317,64 @@ func (c *AppConfig) normalizeMonitorsPostInheritance(ctx *normalizeContext) erro
 		}
 		c.Monitors[i].ProviderSlug = slug
 
-		// 从 badge_providers + monitors[].badges 解析徽标
-		// 必须在继承后处理，确保子通道继承的 Badges 能正确解析为 ResolvedBadges
-		// 仅在启用徽标系统时处理
-		if c.EnableBadges {
-			normalizedProvider := strings.ToLower(strings.TrimSpace(c.Monitors[i].Provider))
-			var refs []BadgeRef
-			if injected, ok := ctx.badgeProviderMap[normalizedProvider]; ok && len(injected) > 0 {
-				refs = append(refs, injected...)
-			}
-			if len(c.Monitors[i].Badges) > 0 {
-				refs = append(refs, c.Monitors[i].Badges...)
-			}
+		// TLS 派生字段解析（继承后处理，确保子通道继承的 TLS 配置同样生效）
+		if err := c.Monitors[i].TLS.Normalize(); err != nil {
+			return fmt.Errorf("monitor[%d]: %w", i, err)
+		}
 
-			// 如果没有配置任何徽标，注入默认徽标
-			if len(refs) == 0 {
-				refs = []BadgeRef{{ID: "api_key_official"}}
-			}
+		// 响应指纹校验（继承后处理，子通道继承的指纹配置同样需要填充默认值）
+		if err := c.Monitors[i].Fingerprint.Normalize(); err != nil {
+			return fmt.Errorf("monitor[%d]: %w", i, err)
+		}
 
-			// 去重并解析为 ResolvedBadge
-			order := make([]string, 0, len(refs))
-			resolvedMap := make(map[string]ResolvedBadge, len(refs))
-			for _, ref := range refs {
-				id := strings.TrimSpace(ref.ID)
-				if id == "" {
-					continue
-				}
-				def, exists := ctx.badgeDefMap[id]
-				if !exists {
-					continue // 验证阶段已检查，此处跳过
-				}
+		// 地址族校验（继承后处理，proxy / ip_family 均可能来自父通道）
+		if err := c.Monitors[i].normalizeIPFamily(); err != nil {
+			return fmt.Errorf("monitor[%d]: %w", i, err)
+		}
 
-				// monitor 级 tooltip 覆盖
-				tooltipOverride := strings.TrimSpace(ref.Tooltip)
+		// 解析覆盖与自定义 DNS 校验（依赖已规范化的 ip_family）
+		if err := c.Monitors[i].normalizeResolve(); err != nil {
+			return fmt.Errorf("monitor[%d]: %w", i, err)
+		}
 
-				if _, seen := resolvedMap[id]; !seen {
-					order = append(order, id)
-				}
-				resolvedMap[id] = ResolvedBadge{
-					ID:              id,
-					Kind:            def.Kind,
-					Variant:         def.Variant,
-					Weight:          def.Weight,
-					URL:             def.URL,
-					TooltipOverride: tooltipOverride,
-				}
-			}
+		// 模板变量校验（继承后处理，body / headers / vars 均可能来自父通道）
+		if err := c.Monitors[i].validateTemplateVars(); err != nil {
+			return fmt.Errorf("monitor[%d]: %w", i, err)
+		}
 
-			// 按 kind 组顺序 → weight desc → id asc 排序
-			result := make([]ResolvedBadge, 0, len(order))
-			for _, id := range order {
-				result = append(result, resolvedMap[id])
+		// 提示词集合绑定（继承后处理，prompt / body 均可能来自父通道）
+		if err := c.Monitors[i].resolvePrompt(c.Prompts); err != nil {
+			return fmt.Errorf("monitor[%d]: %w", i, err)
+		}
+
+		// 期望状态码校验（继承后处理）
+		for _, code := range c.Monitors[i].ExpectedStatusCodes {
+			if code < 100 || code > 599 {
+				return fmt.Errorf("monitor[%d]: expected_status_codes 包含无效状态码 %d", i, code)
 			}
-			sort.SliceStable(result, func(a, b int) bool {
-				kindOrder := map[BadgeKind]int{BadgeKindSource: 0, BadgeKindFeature: 1, BadgeKindInfo: 2}
-				if kindOrder[result[a].Kind] != kindOrder[result[b].Kind] {
-					return kindOrder[result[a].Kind] < kindOrder[result[b].Kind]
-				}
-				if result[a].Weight != result[b].Weight {
-					return result[a].Weight > result[b].Weight // desc
-				}
-				return result[a].ID < result[b].ID // asc
-			})
-			c.Monitors[i].ResolvedBadges = result
+		}
+
+		// 状态映射规则解析（继承后处理，子通道继承的规则同样需要解析派生字段）
+		if err := normalizeStatusRules(c.Monitors[i].StatusRules); err != nil {
+			return fmt.Errorf("monitor[%d]: %w", i, err)
+		}
+
+		// 用量统计参数解析（继承后处理，子通道可继承父通道的提取路径与单价）
+		if err := c.resolveMonitorUsage(&c.Monitors[i]); err != nil {
+			return fmt.Errorf("monitor[%d]: %w", i, err)
+		}
+
+		// 调试捕获参数解析（继承后处理，子通道可继承父通道的开关）
+		c.resolveMonitorDebugCapture(&c.Monitors[i])
+
+		// 故障注入参数解析（仅测试环境，依赖继承后的 slow_latency）
+		c.resolveMonitorChaos(&c.Monitors[i])
+
+		// 从 badge_providers + monitors[].badges 解析徽标
+		// 必须在继承后处理，确保子通道继承的 Badges 能正确解析为 ResolvedBadges
+		// 仅在启用徽标系统时处理
+		if c.EnableBadges {
+			c.Monitors[i].ResolvedBadges = resolveMonitorBadges(ctx, &c.Monitors[i])
 		}
 	}
 
@@ -0,0 +1,351 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"monitor/internal/logger"
+)
+
+// OverridesFileName 运行时覆盖文件名（与主配置文件位于同一目录）
+const OverridesFileName = "overrides.yaml"
+
+// Overrides 运行时覆盖（overrides.yaml）
+// 由 PATCH /api/admin/overrides 维护，加载配置时叠加在 config.yaml（含 include_dir）之上，
+// 避免紧急下线或调整巡检间隔时直接修改生产环境的配置文件
+type Overrides struct {
+	Revision  int64  `yaml:"revision" json:"revision"`                         // 每次写入递增，用于并发写入检测
+	UpdatedAt int64  `yaml:"updated_at,omitempty" json:"updated_at,omitempty"` // 最近一次写入时间（Unix 秒）
+	UpdatedBy string `yaml:"updated_by,omitempty" json:"updated_by,omitempty"`
+
+	// 监测项覆盖，key 为 provider/service/channel 或 provider/service/channel/model
+	Monitors map[string]*MonitorOverride `yaml:"monitors,omitempty" json:"monitors"`
+
+	// 服务商覆盖，key 为 provider 名称（不区分大小写）
+	Providers map[string]*ProviderOverride `yaml:"providers,omitempty" json:"providers"`
+}
+
+// MonitorOverride 单个监测项的覆盖项（nil 字段表示沿用 config.yaml）
+// 不含 model 的 key 同时作用于该通道下的全部模型
+type MonitorOverride struct {
+	Disabled       *bool   `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	DisabledReason string  `yaml:"disabled_reason,omitempty" json:"disabled_reason,omitempty"`
+	Hidden         *bool   `yaml:"hidden,omitempty" json:"hidden,omitempty"`
+	HiddenReason   string  `yaml:"hidden_reason,omitempty" json:"hidden_reason,omitempty"`
+	Interval       *string `yaml:"interval,omitempty" json:"interval,omitempty"`
+	Board          *string `yaml:"board,omitempty" json:"board,omitempty"`           // 指定板块（hot/secondary/cold）
+	BoardAuto      *bool   `yaml:"board_auto,omitempty" json:"board_auto,omitempty"` // 是否参与板块自动调整
+}
+
+// ProviderOverride 服务商覆盖项（true 加入 disabled_providers / hidden_providers / cache_ttl.bypass_providers，false 从中移除）
+// 元数据字段作用于该服务商的全部监测项（服务商自助修改经审核后写入此处）
+type ProviderOverride struct {
+	Disabled *bool  `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	Hidden   *bool  `yaml:"hidden,omitempty" json:"hidden,omitempty"`
+	NoCache  *bool  `yaml:"no_cache,omitempty" json:"no_cache,omitempty"` // 故障处理期间跳过 API 响应缓存
+	Reason   string `yaml:"reason,omitempty" json:"reason,omitempty"`
+
+	ProviderMetadata `yaml:",inline"`
+}
+
+// OverridesPatch 覆盖更新请求：按 key 整体替换覆盖项，值为 null 时删除该 key 的覆盖
+type OverridesPatch struct {
+	Monitors  map[string]*MonitorOverride  `json:"monitors"`
+	Providers map[string]*ProviderOverride `json:"providers"`
+}
+
+// ErrOverridesConflict 写入时 revision 与当前不一致（其它请求已先行修改）
+var ErrOverridesConflict = errors.New("覆盖配置已被修改，请重新读取后再提交")
+
+// OverridesPath 返回主配置文件对应的覆盖文件路径
+func OverridesPath(configFile string) string {
+	return filepath.Join(filepath.Dir(configFile), OverridesFileName)
+}
+
+// LoadOverrides 读取覆盖文件（不存在时返回空覆盖）
+func LoadOverrides(path string) (*Overrides, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Overrides{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取覆盖文件失败: %w", err)
+	}
+
+	var o Overrides
+	if err := yaml.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("解析覆盖文件失败: %w", err)
+	}
+	if err := o.validate(); err != nil {
+		return nil, fmt.Errorf("覆盖文件无效: %w", err)
+	}
+	return &o, nil
+}
+
+// validate 校验覆盖项格式（目标是否存在由调用方结合当前配置判断）
+func (o *Overrides) validate() error {
+	for key, mo := range o.Monitors {
+		if _, err := parseOverrideKey(key); err != nil {
+			return err
+		}
+		if mo == nil {
+			return fmt.Errorf("monitors[%s] 不能为空", key)
+		}
+		if mo.Interval != nil {
+			d, err := time.ParseDuration(strings.TrimSpace(*mo.Interval))
+			if err != nil || d <= 0 {
+				return fmt.Errorf("monitors[%s].interval 无效: %q", key, *mo.Interval)
+			}
+		}
+		if mo.Board != nil {
+			switch strings.ToLower(strings.TrimSpace(*mo.Board)) {
+			case "hot", "secondary", "cold":
+			default:
+				return fmt.Errorf("monitors[%s].board 无效: %q（必须是 hot/secondary/cold）", key, *mo.Board)
+			}
+		}
+	}
+	for provider, po := range o.Providers {
+		if strings.TrimSpace(provider) == "" {
+			return fmt.Errorf("providers: provider 名称不能为空")
+		}
+		if po == nil {
+			return fmt.Errorf("providers[%s] 不能为空", provider)
+		}
+		if err := po.ProviderMetadata.Validate(); err != nil {
+			return fmt.Errorf("providers[%s]: %w", provider, err)
+		}
+	}
+	return nil
+}
+
+// parseOverrideKey 解析监测项覆盖 key（provider/service/channel[/model]）
+func parseOverrideKey(key string) ([]string, error) {
+	parts := strings.Split(key, "/")
+	if len(parts) < 3 || len(parts) > 4 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return nil, fmt.Errorf("监测项 key 格式应为 provider/service/channel[/model]: %q", key)
+	}
+	return parts, nil
+}
+
+// MatchOverrideKey 判断监测项是否命中覆盖 key（provider 不区分大小写，不含 model 的 key 匹配整个通道）
+func MatchOverrideKey(key string, m *ServiceConfig) bool {
+	parts, err := parseOverrideKey(key)
+	if err != nil {
+		return false
+	}
+	if !strings.EqualFold(parts[0], m.Provider) || parts[1] != m.Service || parts[2] != m.Channel {
+		return false
+	}
+	return len(parts) == 3 || parts[3] == m.Model
+}
+
+// Empty 是否不含任何覆盖项
+func (o *Overrides) Empty() bool {
+	return len(o.Monitors) == 0 && len(o.Providers) == 0
+}
+
+// Apply 将覆盖叠加到配置上（需在 Validate 补全子通道 provider/service/channel 之后、Normalize 之前调用）
+// 未命中任何监测项的覆盖仅记录警告，避免监测项从 config.yaml 移除后配置无法加载
+func (o *Overrides) Apply(c *AppConfig) {
+	for key, mo := range o.Monitors {
+		matched := 0
+		for i := range c.Monitors {
+			m := &c.Monitors[i]
+			if !MatchOverrideKey(key, m) {
+				continue
+			}
+			matched++
+			if mo.Disabled != nil {
+				m.Disabled = *mo.Disabled
+				if *mo.Disabled && mo.DisabledReason != "" {
+					m.DisabledReason = mo.DisabledReason
+				}
+			}
+			if mo.Hidden != nil {
+				m.Hidden = *mo.Hidden
+				if *mo.Hidden && mo.HiddenReason != "" {
+					m.HiddenReason = mo.HiddenReason
+				}
+			}
+			if mo.Interval != nil {
+				m.Interval = strings.TrimSpace(*mo.Interval)
+			}
+			if mo.Board != nil {
+				m.Board = strings.ToLower(strings.TrimSpace(*mo.Board))
+			}
+			if mo.BoardAuto != nil {
+				m.BoardAuto = mo.BoardAuto
+			}
+		}
+		if matched == 0 {
+			logger.Warn("config", "覆盖项未匹配任何监测项，已忽略", "key", key)
+		}
+	}
+
+	for provider, po := range o.Providers {
+		provider = strings.TrimSpace(provider)
+		if len(po.ProviderMetadata.Fields()) > 0 {
+			for i := range c.Monitors {
+				if strings.EqualFold(c.Monitors[i].Provider, provider) {
+					po.ProviderMetadata.applyTo(&c.Monitors[i])
+				}
+			}
+		}
+		if po.Disabled != nil {
+			c.DisabledProviders = removeDisabledProvider(c.DisabledProviders, provider)
+			if *po.Disabled {
+				c.DisabledProviders = append(c.DisabledProviders, DisabledProviderConfig{Provider: provider, Reason: po.Reason})
+			}
+		}
+		if po.Hidden != nil {
+			c.HiddenProviders = removeHiddenProvider(c.HiddenProviders, provider)
+			if *po.Hidden {
+				c.HiddenProviders = append(c.HiddenProviders, HiddenProviderConfig{Provider: provider, Reason: po.Reason})
+			}
+		}
+		if po.NoCache != nil {
+			c.CacheTTL.BypassProviders = removeBypassProvider(c.CacheTTL.BypassProviders, provider)
+			if *po.NoCache {
+				c.CacheTTL.BypassProviders = append(c.CacheTTL.BypassProviders, provider)
+			}
+		}
+	}
+}
+
+func removeDisabledProvider(list []DisabledProviderConfig, provider string) []DisabledProviderConfig {
+	out := list[:0:0]
+	for _, dp := range list {
+		if !strings.EqualFold(strings.TrimSpace(dp.Provider), provider) {
+			out = append(out, dp)
+		}
+	}
+	return out
+}
+
+func removeBypassProvider(list []string, provider string) []string {
+	out := list[:0:0]
+	for _, p := range list {
+		if !strings.EqualFold(strings.TrimSpace(p), provider) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func removeHiddenProvider(list []HiddenProviderConfig, provider string) []HiddenProviderConfig {
+	out := list[:0:0]
+	for _, hp := range list {
+		if !strings.EqualFold(strings.TrimSpace(hp.Provider), provider) {
+			out = append(out, hp)
+		}
+	}
+	return out
+}
+
+// OverrideStore 覆盖文件的并发安全读写
+// 进程内写入串行化；跨请求的并发修改通过 revision 检测（乐观锁）
+type OverrideStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewOverrideStore 创建覆盖文件存储
+func NewOverrideStore(path string) *OverrideStore {
+	return &OverrideStore{path: path}
+}
+
+// Path 覆盖文件路径
+func (s *OverrideStore) Path() string {
+	return s.path
+}
+
+// Get 读取当前覆盖
+func (s *OverrideStore) Get() (*Overrides, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return LoadOverrides(s.path)
+}
+
+// Patch 合并覆盖更新并写回文件
+// expectedRevision >= 0 时要求与当前 revision 一致，否则返回 ErrOverridesConflict
+func (s *OverrideStore) Patch(patch OverridesPatch, expectedRevision int64, actor string) (*Overrides, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := LoadOverrides(s.path)
+	if err != nil {
+		return nil, err
+	}
+	if expectedRevision >= 0 && expectedRevision != current.Revision {
+		return nil, ErrOverridesConflict
+	}
+
+	for key, mo := range patch.Monitors {
+		key = strings.TrimSpace(key)
+		if mo == nil {
+			delete(current.Monitors, key)
+			continue
+		}
+		if current.Monitors == nil {
+			current.Monitors = make(map[string]*MonitorOverride)
+		}
+		current.Monitors[key] = mo
+	}
+	for provider, po := range patch.Providers {
+		provider = strings.TrimSpace(provider)
+		if po == nil {
+			delete(current.Providers, provider)
+			continue
+		}
+		if current.Providers == nil {
+			current.Providers = make(map[string]*ProviderOverride)
+		}
+		current.Providers[provider] = po
+	}
+
+	if err := current.validate(); err != nil {
+		return nil, err
+	}
+
+	current.Revision++
+	current.UpdatedAt = time.Now().Unix()
+	current.UpdatedBy = actor
+	if err := s.write(current); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// write 原子写入覆盖文件（临时文件 + rename，配置监听器只会看到完整内容）
+func (s *OverrideStore) write(o *Overrides) error {
+	data, err := yaml.Marshal(o)
+	if err != nil {
+		return fmt.Errorf("序列化覆盖配置失败: %w", err)
+	}
+	header := []byte("# 由 PATCH /api/admin/overrides 维护，加载时叠加在 config.yaml 之上；请勿与 API 同时手工编辑\n")
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".overrides-*.yaml")
+	if err != nil {
+		return fmt.Errorf("创建临时覆盖文件失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(header, data...)); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入覆盖文件失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入覆盖文件失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("替换覆盖文件失败: %w", err)
+	}
+	return nil
+}
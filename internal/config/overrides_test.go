@@ -0,0 +1,164 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const overridesMainConfig = `
+interval: "1m"
+disabled_providers:
+  - provider: "Beta"
+    reason: "config.yaml 下线"
+monitors:
+  - provider: "Alpha"
+    service: "cc"
+    channel: "vip"
+    category: "public"
+    sponsor: "alpha"
+    url: "https://alpha.example.com"
+    method: "POST"
+  - provider: "Beta"
+    service: "cc"
+    category: "public"
+    sponsor: "beta"
+    url: "https://beta.example.com"
+    method: "POST"
+`
+
+func boolPtr(v bool) *bool { return &v }
+
+func TestOverrideStorePatchMergesAndDetectsConflict(t *testing.T) {
+	t.Parallel()
+
+	store := NewOverrideStore(filepath.Join(t.TempDir(), OverridesFileName))
+	interval := "5m"
+	o, err := store.Patch(OverridesPatch{
+		Monitors: map[string]*MonitorOverride{
+			"alpha/cc/vip": {Disabled: boolPtr(true), DisabledReason: "排查中"},
+			"Beta/cc/":     {Interval: &interval},
+		},
+	}, 0, "tester")
+	if err != nil {
+		t.Fatalf("写入覆盖失败: %v", err)
+	}
+	if o.Revision != 1 || o.UpdatedBy != "tester" || len(o.Monitors) != 2 {
+		t.Fatalf("覆盖内容不符合预期: %+v", o)
+	}
+
+	// revision 不一致应拒绝写入
+	if _, err := store.Patch(OverridesPatch{Providers: map[string]*ProviderOverride{"Beta": {Hidden: boolPtr(true)}}}, 0, "tester"); !errors.Is(err, ErrOverridesConflict) {
+		t.Fatalf("期望 ErrOverridesConflict，实际: %v", err)
+	}
+
+	// null 删除，其它 key 保留
+	o, err = store.Patch(OverridesPatch{
+		Monitors:  map[string]*MonitorOverride{"Beta/cc/": nil},
+		Providers: map[string]*ProviderOverride{"Beta": {Disabled: boolPtr(false)}},
+	}, 1, "tester")
+	if err != nil {
+		t.Fatalf("写入覆盖失败: %v", err)
+	}
+	if o.Revision != 2 || len(o.Monitors) != 1 || o.Monitors["alpha/cc/vip"] == nil || o.Providers["Beta"] == nil {
+		t.Fatalf("合并结果不符合预期: %+v", o)
+	}
+
+	reloaded, err := store.Get()
+	if err != nil {
+		t.Fatalf("读取覆盖失败: %v", err)
+	}
+	if reloaded.Revision != 2 || len(reloaded.Monitors) != 1 || len(reloaded.Providers) != 1 {
+		t.Fatalf("持久化内容不符合预期: %+v", reloaded)
+	}
+
+	// 格式错误不写入
+	bad := "soon"
+	if _, err := store.Patch(OverridesPatch{Monitors: map[string]*MonitorOverride{"alpha/cc/vip": {Interval: &bad}}}, -1, "tester"); err == nil {
+		t.Fatal("无效 interval 应返回错误")
+	}
+	board := "frozen"
+	if _, err := store.Patch(OverridesPatch{Monitors: map[string]*MonitorOverride{"alpha/cc/vip": {Board: &board}}}, -1, "tester"); err == nil {
+		t.Fatal("无效 board 应返回错误")
+	}
+	if _, err := store.Patch(OverridesPatch{Monitors: map[string]*MonitorOverride{"alpha": {Hidden: boolPtr(true)}}}, -1, "tester"); err == nil {
+		t.Fatal("无效 key 应返回错误")
+	}
+	if o, _ := store.Get(); o.Revision != 2 {
+		t.Fatalf("失败的写入不应修改 revision: %d", o.Revision)
+	}
+}
+
+func TestLoaderAppliesOverrides(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, configPath, overridesMainConfig)
+	writeTestFile(t, OverridesPath(configPath), `
+revision: 3
+monitors:
+  alpha/cc/vip:
+    interval: "5m"
+    hidden: true
+    hidden_reason: "临时隐藏"
+    board: "Secondary"
+    board_auto: false
+  ghost/cc/:
+    disabled: true
+providers:
+  beta:
+    disabled: false
+    hidden: true
+    no_cache: true
+    reason: "观察中"
+    sponsor_url: "https://beta.example.com/sponsor"
+    price_min: 0.2
+`)
+
+	cfg, err := NewLoader().Load(configPath)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	alpha, beta := cfg.Monitors[0], cfg.Monitors[1]
+	if alpha.IntervalDuration != 5*time.Minute || !alpha.Hidden || alpha.HiddenReason != "临时隐藏" {
+		t.Fatalf("监测项覆盖未生效: interval=%v hidden=%v reason=%q", alpha.IntervalDuration, alpha.Hidden, alpha.HiddenReason)
+	}
+	if alpha.Board != "secondary" || alpha.BoardAuto == nil || *alpha.BoardAuto || beta.Board != "hot" {
+		t.Fatalf("板块覆盖未生效: alpha=%q/%v beta=%q", alpha.Board, alpha.BoardAuto, beta.Board)
+	}
+	if beta.IntervalDuration != time.Minute {
+		t.Fatalf("未覆盖的监测项 interval 被修改: %v", beta.IntervalDuration)
+	}
+	if beta.Disabled || !beta.Hidden || beta.HiddenReason != "观察中" {
+		t.Fatalf("服务商覆盖未生效: disabled=%v hidden=%v reason=%q", beta.Disabled, beta.Hidden, beta.HiddenReason)
+	}
+	if beta.SponsorURL != "https://beta.example.com/sponsor" || beta.PriceMin == nil || *beta.PriceMin != 0.2 || alpha.SponsorURL != "" {
+		t.Fatalf("服务商元数据覆盖未生效: sponsor_url=%q price_min=%v", beta.SponsorURL, beta.PriceMin)
+	}
+	if len(cfg.DisabledProviders) != 0 || len(cfg.HiddenProviders) != 1 {
+		t.Fatalf("服务商列表不符合预期: disabled=%+v hidden=%+v", cfg.DisabledProviders, cfg.HiddenProviders)
+	}
+	if !cfg.CacheTTL.Bypassed("Beta", "") || cfg.CacheTTL.Bypassed("alpha", "") {
+		t.Fatalf("no_cache 覆盖未生效: %v", cfg.CacheTTL.BypassProviders)
+	}
+}
+
+func TestLoaderRejectsInvalidOverrides(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, configPath, overridesMainConfig)
+	writeTestFile(t, OverridesPath(configPath), `
+monitors:
+  alpha/cc/vip:
+    interval: "-1m"
+`)
+
+	if _, err := NewLoader().Load(configPath); err == nil {
+		t.Fatal("无效覆盖文件应导致加载失败")
+	}
+}
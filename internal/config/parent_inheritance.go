@@ -64,7 +64,7 @@ func (c *AppConfig) applyParentInheritance() error {
 }
 
 // inheritCoreBehavior 继承核心监测行为配置
-// 包括：APIKey、URL、Method、Body、BodyTemplateName、SuccessContains、EnvVarName、Proxy、Headers
+// 包括：APIKey、URL、Method、Prompt、Body、BodyTemplateName、SuccessContains、ExpectedStatusCodes、EnvVarName、Proxy、TLS、响应指纹、地址族、解析覆盖、自定义 DNS、状态映射规则、用量统计参数、调试捕获开关、Headers、Vars
 func inheritCoreBehavior(child, parent *ServiceConfig) {
 	if child.APIKey == "" {
 		child.APIKey = parent.APIKey
@@ -75,6 +75,10 @@ func inheritCoreBehavior(child, parent *ServiceConfig) {
 	if child.Method == "" {
 		child.Method = parent.Method
 	}
+	// 提示词集合：子通道自己配置了 body 时不继承
+	if child.Prompt == "" && child.Body == "" {
+		child.Prompt = parent.Prompt
+	}
 	if child.Body == "" {
 		child.Body = parent.Body
 		// Body 继承时一并继承 BodyTemplateName（用于 API 返回 template_name）
@@ -85,6 +89,9 @@ func inheritCoreBehavior(child, parent *ServiceConfig) {
 	if child.SuccessContains == "" {
 		child.SuccessContains = parent.SuccessContains
 	}
+	if len(child.ExpectedStatusCodes) == 0 && len(parent.ExpectedStatusCodes) > 0 {
+		child.ExpectedStatusCodes = append([]int(nil), parent.ExpectedStatusCodes...)
+	}
 	// 自定义环境变量名（用于 API Key 查找）
 	if child.EnvVarName == "" {
 		child.EnvVarName = parent.EnvVarName
@@ -96,6 +103,58 @@ func inheritCoreBehavior(child, parent *ServiceConfig) {
 		child.Proxy = parent.Proxy
 	}
 
+	// TLS 继承（子通道未配置时整体继承父通道 TLS 配置）
+	if child.TLS == nil {
+		child.TLS = parent.TLS.Clone()
+	}
+
+	// 响应指纹继承（子通道未配置时整体继承）
+	if child.Fingerprint == nil {
+		child.Fingerprint = parent.Fingerprint.Clone()
+	}
+
+	// 地址族继承（子通道显式配置 auto 时不继承）
+	if strings.TrimSpace(child.IPFamily) == "" {
+		child.IPFamily = parent.IPFamily
+	}
+
+	// 自定义 DNS 继承
+	if strings.TrimSpace(child.DNSServer) == "" {
+		child.DNSServer = parent.DNSServer
+	}
+
+	// 解析覆盖继承（合并策略：父为基础，子覆盖）
+	if len(parent.Resolve) > 0 {
+		merged := make(map[string]string, len(parent.Resolve)+len(child.Resolve))
+		for k, v := range parent.Resolve {
+			merged[k] = v
+		}
+		for k, v := range child.Resolve {
+			merged[k] = v
+		}
+		child.Resolve = merged
+	}
+
+	// 状态映射规则继承（子通道未配置时整体继承）
+	if len(child.StatusRules) == 0 {
+		child.StatusRules = cloneStatusRules(parent.StatusRules)
+	}
+
+	// 用量统计参数继承
+	if len(child.UsageTokenPaths) == 0 && len(parent.UsageTokenPaths) > 0 {
+		child.UsageTokenPaths = append([]string(nil), parent.UsageTokenPaths...)
+	}
+	if child.PricePer1KTokens == nil && parent.PricePer1KTokens != nil {
+		v := *parent.PricePer1KTokens
+		child.PricePer1KTokens = &v
+	}
+
+	// 调试捕获开关继承
+	if child.DebugCapture == nil && parent.DebugCapture != nil {
+		v := *parent.DebugCapture
+		child.DebugCapture = &v
+	}
+
 	// Headers 继承（合并策略：父为基础，子覆盖）
 	if len(parent.Headers) > 0 {
 		merged := make(map[string]string, len(parent.Headers)+len(child.Headers))
@@ -107,6 +166,18 @@ func inheritCoreBehavior(child, parent *ServiceConfig) {
 		}
 		child.Headers = merged
 	}
+
+	// Vars 继承（合并策略：父为基础，子覆盖）
+	if len(parent.Vars) > 0 {
+		merged := make(map[string]string, len(parent.Vars)+len(child.Vars))
+		for k, v := range parent.Vars {
+			merged[k] = v
+		}
+		for k, v := range child.Vars {
+			merged[k] = v
+		}
+		child.Vars = merged
+	}
 }
 
 // inheritedTimingsFlags 记录哪些时间配置字段是从 parent 继承的
@@ -174,12 +245,17 @@ func inheritRetry(child, parent *ServiceConfig) inheritedRetryFlags {
 		child.RetryJitterValue = v
 		flags.RetryJitter = true
 	}
+	// RetryOn: nil 表示未配置，从 parent 继承（parent 已在 pre-inheritance 阶段解析）
+	if child.RetryOn == nil && parent.RetryOn != nil {
+		child.RetryOn = append([]string{}, parent.RetryOn...)
+		child.RetryOnClasses = parent.RetryOnClasses
+	}
 
 	return flags
 }
 
 // inheritMeta 继承元数据配置
-// 包括：Category、Sponsor、Provider 相关元数据、Board 配置
+// 包括：Category、Sponsor、Provider 相关元数据、Board 配置、监测类型、命名空间
 func inheritMeta(child, parent *ServiceConfig) {
 	// Category: 必填字段，但子通道可能想继承
 	if child.Category == "" {
@@ -199,6 +275,9 @@ func inheritMeta(child, parent *ServiceConfig) {
 	if child.ProviderURL == "" {
 		child.ProviderURL = parent.ProviderURL
 	}
+	if child.ProviderLogo == "" {
+		child.ProviderLogo = parent.ProviderLogo
+	}
 	if child.ProviderSlug == "" {
 		child.ProviderSlug = parent.ProviderSlug
 	}
@@ -217,6 +296,19 @@ func inheritMeta(child, parent *ServiceConfig) {
 	if child.ColdReason == "" && parent.ColdReason != "" {
 		child.ColdReason = parent.ColdReason
 	}
+	if child.BoardAuto == nil && parent.BoardAuto != nil {
+		child.BoardAuto = parent.BoardAuto
+	}
+
+	// 监测类型：官方基线的子通道同为基线
+	if child.Type == "" {
+		child.Type = parent.Type
+	}
+
+	// 命名空间：子通道与父通道同属一个命名空间（显式配置的一致性已在 Validate 中校验）
+	if child.Namespace == "" {
+		child.Namespace = parent.Namespace
+	}
 }
 
 // inheritState 继承状态配置（级联 OR 逻辑）
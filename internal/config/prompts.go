@@ -0,0 +1,171 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 提示词轮换策略
+const (
+	PromptStrategyRoundRobin = "round_robin" // 按顺序轮换（每个监测项独立计数）
+	PromptStrategyRandom     = "random"      // 每次探测随机选择
+)
+
+// maxPromptVariants 单个提示词集合的变体数量上限
+const maxPromptVariants = 50
+
+// PromptSetConfig 探测提示词集合（prompts.<name>）
+//
+// 同一 service 的监测项使用完全相同的请求体时，服务商可能针对性地识别并特殊处理探测请求；
+// 配置多个变体后每次探测按 strategy 选择一个作为请求体，降低被识别的可能。
+// 集合名与 service 相同时，自动用于该 service 下未配置 body 的监测项；监测项也可通过 prompt 显式引用
+type PromptSetConfig struct {
+	// 轮换策略：round_robin（默认）/ random
+	Strategy string `yaml:"strategy" json:"strategy"`
+
+	// 提示词变体（至少 1 个）
+	Variants []PromptVariant `yaml:"variants" json:"variants"`
+}
+
+// PromptVariant 单个提示词变体
+type PromptVariant struct {
+	// 变体名称（集合内唯一，用于日志与调试）
+	Name string `yaml:"name" json:"name"`
+
+	// 请求体，支持 "!include data/xxx.json" 引用 data/ 目录下的文件
+	// 与监测项 body 一样支持 {{MODEL}}、{{API_KEY}}、{{RANDOM_UUID}}、{{VAR "name"}} 等模板表达式
+	Body string `yaml:"body" json:"-"`
+
+	// 响应体需包含的关键字（可选，未配置时沿用监测项的 success_contains）
+	SuccessContains string `yaml:"success_contains" json:"success_contains,omitempty"`
+
+	// TemplateName 引用的模板文件名（由 !include 解析得到）
+	TemplateName string `yaml:"-" json:"template_name,omitempty"`
+}
+
+// Normalize 规范化提示词集合（name 为集合名，用于错误信息）
+func (p *PromptSetConfig) Normalize(name string) error {
+	field := fmt.Sprintf("prompts.%s", name)
+
+	p.Strategy = strings.ToLower(strings.TrimSpace(p.Strategy))
+	if p.Strategy == "" {
+		p.Strategy = PromptStrategyRoundRobin
+	}
+	if p.Strategy != PromptStrategyRoundRobin && p.Strategy != PromptStrategyRandom {
+		return fmt.Errorf("%s.strategy 无效: %s（支持 round_robin/random）", field, p.Strategy)
+	}
+
+	if len(p.Variants) == 0 {
+		return fmt.Errorf("%s.variants 不能为空", field)
+	}
+	if len(p.Variants) > maxPromptVariants {
+		return fmt.Errorf("%s.variants 最多 %d 个，当前: %d", field, maxPromptVariants, len(p.Variants))
+	}
+
+	seen := make(map[string]bool, len(p.Variants))
+	for i := range p.Variants {
+		v := &p.Variants[i]
+		v.Name = strings.TrimSpace(v.Name)
+		if v.Name == "" {
+			v.Name = fmt.Sprintf("v%d", i+1)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("%s.variants[%d]: name 重复: %s", field, i, v.Name)
+		}
+		seen[v.Name] = true
+		if strings.TrimSpace(v.Body) == "" {
+			return fmt.Errorf("%s.variants[%d]: body 不能为空", field, i)
+		}
+	}
+	return nil
+}
+
+// Clone 深拷贝提示词集合
+func (p *PromptSetConfig) Clone() *PromptSetConfig {
+	if p == nil {
+		return nil
+	}
+	cp := *p
+	cp.Variants = append([]PromptVariant(nil), p.Variants...)
+	return &cp
+}
+
+// resolveIncludes 解析变体 body 中的 !include 指令
+func (p *PromptSetConfig) resolveIncludes(configDir, name string) error {
+	for i := range p.Variants {
+		v := &p.Variants[i]
+		content, templateName, err := resolveDataInclude(configDir, v.Body)
+		if err != nil {
+			return fmt.Errorf("prompts.%s.variants[%d]: %w", name, i, err)
+		}
+		if templateName != "" {
+			v.Body, v.TemplateName = content, templateName
+		}
+	}
+	return nil
+}
+
+// resolvePrompt 为监测项绑定提示词集合（继承后调用）
+// 显式配置 prompt 时必须引用已定义的集合（优先于 body）；未配置 body 时自动使用与 service 同名的集合
+func (m *ServiceConfig) resolvePrompt(prompts map[string]*PromptSetConfig) error {
+	m.Prompt = strings.TrimSpace(m.Prompt)
+	m.PromptSet = nil
+
+	name := m.Prompt
+	if name == "" {
+		if strings.TrimSpace(m.Body) != "" {
+			return nil
+		}
+		name = m.Service
+	}
+
+	set, ok := prompts[name]
+	if !ok {
+		if m.Prompt != "" {
+			return fmt.Errorf("prompt 引用了未定义的提示词集合 '%s'", m.Prompt)
+		}
+		return nil
+	}
+	for i, v := range set.Variants {
+		if err := m.checkTemplateVarRefs(v.Body); err != nil {
+			return fmt.Errorf("prompts.%s.variants[%d]: %w", name, i, err)
+		}
+	}
+	m.Prompt = name
+	m.PromptSet = set
+	return nil
+}
+
+// resolveDataInclude 解析 "!include data/xxx" 指令，返回文件内容与文件名（非 include 时文件名为空）
+func resolveDataInclude(configDir, body string) (string, string, error) {
+	const includePrefix = "!include "
+	trimmed := strings.TrimSpace(body)
+	if trimmed == "" || !strings.HasPrefix(trimmed, includePrefix) {
+		return body, "", nil
+	}
+
+	relativePath := strings.TrimSpace(trimmed[len(includePrefix):])
+	if relativePath == "" {
+		return "", "", fmt.Errorf("body include 路径不能为空")
+	}
+	if filepath.IsAbs(relativePath) {
+		return "", "", fmt.Errorf("body include 必须使用相对路径")
+	}
+
+	cleanPath := filepath.Clean(relativePath)
+	targetPath := filepath.Clean(filepath.Join(configDir, cleanPath))
+	dataDir := filepath.Clean(filepath.Join(configDir, "data"))
+
+	// 确保引用的文件位于 data/ 目录内
+	if targetPath != dataDir && !strings.HasPrefix(targetPath, dataDir+string(os.PathSeparator)) {
+		return "", "", fmt.Errorf("body include 路径必须位于 data/ 目录")
+	}
+
+	content, err := os.ReadFile(targetPath)
+	if err != nil {
+		return "", "", fmt.Errorf("读取 body include 文件失败: %w", err)
+	}
+	return string(content), filepath.Base(cleanPath), nil
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const promptsMainConfig = `
+interval: "1m"
+prompts:
+  cc:
+    variants:
+      - name: "pong"
+        body: "!include data/cc_pong.json"
+        success_contains: "pong"
+      - body: '{"messages":[{"role":"user","content":"{{VAR "greeting"}}"}]}'
+  alt:
+    strategy: " Random "
+    variants:
+      - body: '{"prompt":"hi"}'
+monitors:
+  - provider: "Alpha"
+    service: "cc"
+    channel: "vip"
+    category: "public"
+    sponsor: "alpha"
+    url: "https://alpha.example.com"
+    method: "POST"
+    model: "haiku"
+    vars:
+      greeting: "hello"
+  - provider: "Alpha"
+    service: "cc"
+    channel: "vip"
+    model: "opus"
+    parent: "Alpha/cc/vip"
+  - provider: "Beta"
+    service: "cc"
+    category: "public"
+    sponsor: "beta"
+    url: "https://beta.example.com"
+    method: "POST"
+    body: '{"static":true}'
+  - provider: "Gamma"
+    service: "cx"
+    category: "public"
+    sponsor: "gamma"
+    url: "https://gamma.example.com"
+    method: "POST"
+    prompt: "alt"
+`
+
+func TestLoaderResolvesPrompts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, configPath, promptsMainConfig)
+	writeTestFile(t, filepath.Join(dir, "data", "cc_pong.json"), `{"messages":[{"role":"user","content":"ping {{MODEL}}"}]}`)
+
+	cfg, err := NewLoader().Load(configPath)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	cc := cfg.Prompts["cc"]
+	if cc.Strategy != PromptStrategyRoundRobin || cc.Variants[0].TemplateName != "cc_pong.json" ||
+		!strings.Contains(cc.Variants[0].Body, "ping {{MODEL}}") || cc.Variants[1].Name != "v2" {
+		t.Fatalf("提示词集合解析不符合预期: %+v", cc)
+	}
+	if cfg.Prompts["alt"].Strategy != PromptStrategyRandom {
+		t.Fatalf("strategy 应规范化为 random: %q", cfg.Prompts["alt"].Strategy)
+	}
+
+	alpha, child, beta, gamma := cfg.Monitors[0], cfg.Monitors[1], cfg.Monitors[2], cfg.Monitors[3]
+	if alpha.PromptSet != cc || alpha.Prompt != "cc" {
+		t.Fatalf("未配置 body 的监测项应自动使用同名集合: %+v", alpha.Prompt)
+	}
+	if child.PromptSet != cc {
+		t.Fatalf("子通道应继承提示词集合")
+	}
+	if beta.PromptSet != nil {
+		t.Fatalf("配置了 body 的监测项不应使用提示词集合")
+	}
+	if gamma.PromptSet != cfg.Prompts["alt"] {
+		t.Fatalf("显式 prompt 未生效")
+	}
+
+	clone := cfg.Clone()
+	if clone.Monitors[0].PromptSet != clone.Prompts["cc"] || clone.Prompts["cc"] == cc {
+		t.Fatalf("Clone 应深拷贝提示词集合并重新指向副本")
+	}
+}
+
+func TestPromptsValidation(t *testing.T) {
+	t.Parallel()
+
+	for name, bad := range map[string]PromptSetConfig{
+		"no variants":      {},
+		"unknown strategy": {Strategy: "weighted", Variants: []PromptVariant{{Body: "x"}}},
+		"empty body":       {Variants: []PromptVariant{{Name: "a"}}},
+		"duplicate name":   {Variants: []PromptVariant{{Name: "a", Body: "x"}, {Name: "a", Body: "y"}}},
+	} {
+		if err := bad.Normalize("cc"); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+
+	m := ServiceConfig{Service: "cc", Prompt: "missing"}
+	if err := m.resolvePrompt(map[string]*PromptSetConfig{}); err == nil {
+		t.Fatal("引用未定义集合应返回错误")
+	}
+	m = ServiceConfig{Service: "cc"}
+	set := &PromptSetConfig{Variants: []PromptVariant{{Body: `{{VAR "missing"}}`}}}
+	if err := m.resolvePrompt(map[string]*PromptSetConfig{"cc": set}); err == nil {
+		t.Fatal("变体引用未定义的 VAR 应返回错误")
+	}
+}
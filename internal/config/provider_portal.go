@@ -0,0 +1,255 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// 服务商可自助修改的元数据字段
+const (
+	MetadataFieldSponsorURL   = "sponsor_url"
+	MetadataFieldProviderURL  = "provider_url"
+	MetadataFieldProviderLogo = "provider_logo"
+	MetadataFieldPriceMin     = "price_min"
+	MetadataFieldPriceMax     = "price_max"
+)
+
+// metadataFields 全部可自助修改的字段（默认全部允许）
+var metadataFields = []string{
+	MetadataFieldSponsorURL,
+	MetadataFieldProviderURL,
+	MetadataFieldProviderLogo,
+	MetadataFieldPriceMin,
+	MetadataFieldPriceMax,
+}
+
+// defaultMetadataMaxPending 每个服务商默认允许同时存在的待审核申请数
+const defaultMetadataMaxPending = 3
+
+// ProviderMetadata 服务商级展示元数据（nil 字段表示不修改）
+// 同时用于数据门户的修改申请与运行时覆盖（overrides.yaml 的 providers 项）
+type ProviderMetadata struct {
+	SponsorURL   *string  `yaml:"sponsor_url,omitempty" json:"sponsor_url,omitempty"`
+	ProviderURL  *string  `yaml:"provider_url,omitempty" json:"provider_url,omitempty"`
+	ProviderLogo *string  `yaml:"provider_logo,omitempty" json:"provider_logo,omitempty"`
+	PriceMin     *float64 `yaml:"price_min,omitempty" json:"price_min,omitempty"`
+	PriceMax     *float64 `yaml:"price_max,omitempty" json:"price_max,omitempty"`
+}
+
+// Fields 返回已设置的字段名
+func (p *ProviderMetadata) Fields() []string {
+	var fields []string
+	if p.SponsorURL != nil {
+		fields = append(fields, MetadataFieldSponsorURL)
+	}
+	if p.ProviderURL != nil {
+		fields = append(fields, MetadataFieldProviderURL)
+	}
+	if p.ProviderLogo != nil {
+		fields = append(fields, MetadataFieldProviderLogo)
+	}
+	if p.PriceMin != nil {
+		fields = append(fields, MetadataFieldPriceMin)
+	}
+	if p.PriceMax != nil {
+		fields = append(fields, MetadataFieldPriceMax)
+	}
+	return fields
+}
+
+// Validate 校验字段格式：链接为空（清除）或 http(s) 地址，倍率非负且 price_min 不大于 price_max
+func (p *ProviderMetadata) Validate() error {
+	for _, f := range []struct {
+		name  string
+		value *string
+	}{
+		{MetadataFieldSponsorURL, p.SponsorURL},
+		{MetadataFieldProviderURL, p.ProviderURL},
+		{MetadataFieldProviderLogo, p.ProviderLogo},
+	} {
+		if f.value == nil {
+			continue
+		}
+		if err := validateURL(*f.value, f.name); err != nil {
+			return err
+		}
+	}
+	if p.PriceMin != nil && *p.PriceMin < 0 {
+		return fmt.Errorf("price_min 不能为负数")
+	}
+	if p.PriceMax != nil && *p.PriceMax < 0 {
+		return fmt.Errorf("price_max 不能为负数")
+	}
+	if p.PriceMin != nil && p.PriceMax != nil && *p.PriceMin > *p.PriceMax {
+		return fmt.Errorf("price_min 不能大于 price_max")
+	}
+	return nil
+}
+
+// Merge 用 other 中已设置的字段覆盖当前值
+func (p *ProviderMetadata) Merge(other ProviderMetadata) {
+	if other.SponsorURL != nil {
+		p.SponsorURL = other.SponsorURL
+	}
+	if other.ProviderURL != nil {
+		p.ProviderURL = other.ProviderURL
+	}
+	if other.ProviderLogo != nil {
+		p.ProviderLogo = other.ProviderLogo
+	}
+	if other.PriceMin != nil {
+		p.PriceMin = other.PriceMin
+	}
+	if other.PriceMax != nil {
+		p.PriceMax = other.PriceMax
+	}
+}
+
+// applyTo 将已设置的字段写入监测项（链接规范化规则与 Normalize 一致）
+func (p *ProviderMetadata) applyTo(m *ServiceConfig) {
+	if p.SponsorURL != nil {
+		m.SponsorURL = strings.TrimSpace(*p.SponsorURL)
+	}
+	if p.ProviderURL != nil {
+		m.ProviderURL = strings.TrimSpace(*p.ProviderURL)
+	}
+	if p.ProviderLogo != nil {
+		m.ProviderLogo = strings.TrimSpace(*p.ProviderLogo)
+	}
+	if p.PriceMin != nil {
+		m.PriceMin = cloneFloat64Ptr(p.PriceMin)
+	}
+	if p.PriceMax != nil {
+		m.PriceMax = cloneFloat64Ptr(p.PriceMax)
+	}
+}
+
+// ProviderPortalConfig 服务商数据门户配置（/api/provider-portal/*）
+type ProviderPortalConfig struct {
+	// 服务商自助修改元数据（/api/provider-portal/metadata）
+	Metadata PortalMetadataConfig `yaml:"metadata" json:"metadata"`
+}
+
+// PortalMetadataConfig 服务商自助修改元数据的限制
+// 服务商提交的修改先进入待审核队列，管理员通过后写入运行时覆盖（overrides.yaml）生效
+type PortalMetadataConfig struct {
+	// 是否启用（默认禁用）
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// 允许修改的字段（默认全部：sponsor_url、provider_url、provider_logo、price_min、price_max）
+	Fields []string `yaml:"fields" json:"fields"`
+
+	// 链接允许的域名（可选，含子域名；留空不限制）
+	AllowedHosts []string `yaml:"allowed_hosts" json:"allowed_hosts"`
+
+	// price_min / price_max 的上限（可选，0 表示不限制）
+	MaxPrice float64 `yaml:"max_price" json:"max_price"`
+
+	// 每个服务商同时允许的待审核申请数（默认 3）
+	MaxPending int `yaml:"max_pending" json:"max_pending"`
+}
+
+// Normalize 规范化服务商数据门户配置
+func (p *ProviderPortalConfig) Normalize() error {
+	m := &p.Metadata
+	if len(m.Fields) == 0 {
+		m.Fields = append([]string(nil), metadataFields...)
+	}
+	for i, f := range m.Fields {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if !isMetadataField(f) {
+			return fmt.Errorf("provider_portal.metadata.fields[%d]: 未知字段 %q，可选值: %s", i, m.Fields[i], strings.Join(metadataFields, ", "))
+		}
+		m.Fields[i] = f
+	}
+	for i, h := range m.AllowedHosts {
+		h = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(h), "."))
+		if h == "" {
+			return fmt.Errorf("provider_portal.metadata.allowed_hosts[%d] 不能为空", i)
+		}
+		m.AllowedHosts[i] = h
+	}
+	if m.MaxPrice < 0 {
+		return fmt.Errorf("provider_portal.metadata.max_price 不能为负数")
+	}
+	if m.MaxPending == 0 {
+		m.MaxPending = defaultMetadataMaxPending
+	}
+	if m.MaxPending < 0 {
+		return fmt.Errorf("provider_portal.metadata.max_pending 不能为负数，当前值: %d", m.MaxPending)
+	}
+	return nil
+}
+
+// Clone 深拷贝
+func (p ProviderPortalConfig) Clone() ProviderPortalConfig {
+	p.Metadata.Fields = append([]string(nil), p.Metadata.Fields...)
+	p.Metadata.AllowedHosts = append([]string(nil), p.Metadata.AllowedHosts...)
+	return p
+}
+
+// CheckLimits 校验修改申请是否在管理员设置的范围内（字段、链接域名、倍率上限）
+func (m *PortalMetadataConfig) CheckLimits(md *ProviderMetadata) error {
+	for _, f := range md.Fields() {
+		allowed := false
+		for _, a := range m.Fields {
+			if a == f {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("不允许修改字段: %s", f)
+		}
+	}
+
+	if len(m.AllowedHosts) > 0 {
+		for _, f := range []struct {
+			name  string
+			value *string
+		}{
+			{MetadataFieldSponsorURL, md.SponsorURL},
+			{MetadataFieldProviderURL, md.ProviderURL},
+			{MetadataFieldProviderLogo, md.ProviderLogo},
+		} {
+			if f.value == nil || strings.TrimSpace(*f.value) == "" {
+				continue
+			}
+			u, err := url.Parse(strings.TrimSpace(*f.value))
+			if err != nil || !m.hostAllowed(u.Hostname()) {
+				return fmt.Errorf("%s 的域名不在允许范围内: %s", f.name, *f.value)
+			}
+		}
+	}
+
+	if m.MaxPrice > 0 {
+		if md.PriceMin != nil && *md.PriceMin > m.MaxPrice {
+			return fmt.Errorf("price_min 不能超过 %g", m.MaxPrice)
+		}
+		if md.PriceMax != nil && *md.PriceMax > m.MaxPrice {
+			return fmt.Errorf("price_max 不能超过 %g", m.MaxPrice)
+		}
+	}
+	return nil
+}
+
+// hostAllowed 判断域名是否命中 allowed_hosts（含子域名）
+func (m *PortalMetadataConfig) hostAllowed(host string) bool {
+	host = strings.ToLower(host)
+	for _, h := range m.AllowedHosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+func isMetadataField(name string) bool {
+	for _, f := range metadataFields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
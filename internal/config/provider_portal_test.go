@@ -0,0 +1,47 @@
+package config
+
+import "testing"
+
+func TestProviderPortalNormalizeAndLimits(t *testing.T) {
+	t.Parallel()
+
+	var p ProviderPortalConfig
+	if err := p.Normalize(); err != nil {
+		t.Fatalf("默认配置应有效: %v", err)
+	}
+	if len(p.Metadata.Fields) != len(metadataFields) || p.Metadata.MaxPending != defaultMetadataMaxPending {
+		t.Fatalf("默认值不符合预期: %+v", p.Metadata)
+	}
+
+	bad := ProviderPortalConfig{Metadata: PortalMetadataConfig{Fields: []string{"sponsor"}}}
+	if err := bad.Normalize(); err == nil {
+		t.Fatal("未知字段应返回错误")
+	}
+
+	limits := ProviderPortalConfig{Metadata: PortalMetadataConfig{
+		Fields:       []string{" Sponsor_URL ", "price_max"},
+		AllowedHosts: []string{".Example.com"},
+		MaxPrice:     1,
+	}}
+	if err := limits.Normalize(); err != nil {
+		t.Fatalf("规范化失败: %v", err)
+	}
+
+	u := func(s string) *string { return &s }
+	f := func(v float64) *float64 { return &v }
+	for _, tc := range []struct {
+		name string
+		md   ProviderMetadata
+		ok   bool
+	}{
+		{"允许的字段与子域名", ProviderMetadata{SponsorURL: u("https://www.example.com/a"), PriceMax: f(0.5)}, true},
+		{"清除链接", ProviderMetadata{SponsorURL: u("")}, true},
+		{"未允许的字段", ProviderMetadata{ProviderURL: u("https://example.com")}, false},
+		{"域名不匹配", ProviderMetadata{SponsorURL: u("https://notexample.com")}, false},
+		{"超过倍率上限", ProviderMetadata{PriceMax: f(1.5)}, false},
+	} {
+		if err := limits.Metadata.CheckLimits(&tc.md); (err == nil) != tc.ok {
+			t.Fatalf("%s: ok=%v err=%v", tc.name, tc.ok, err)
+		}
+	}
+}
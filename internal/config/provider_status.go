@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 服务商综合状态计算模式
+const (
+	ProviderStatusModeWeighted = "weighted" // 按权重加权可用性（默认）
+	ProviderStatusModeWorst    = "worst"    // 取最差通道状态
+	ProviderStatusModeQuorum   = "quorum"   // 可用通道权重占比达到阈值即视为可用
+)
+
+// defaultProviderStatusThreshold 综合状态默认阈值
+const defaultProviderStatusThreshold = 0.5
+
+// ProviderStatusConfig 服务商综合状态（/api/status 的 provider_status）配置
+//
+// 以通道为单位（多模型通道取组级最差状态），权重取通道（父层）的 weight，默认 1，0 表示不参与计算：
+//   - weighted：加权得分 = Σ(权重 × 可用性) / Σ权重（绿=1，黄=degraded_weight，红=0），
+//     全部通道绿灯时绿灯，得分不低于 threshold 时黄灯，否则红灯
+//   - worst：任一通道红灯即红灯，其次黄灯
+//   - quorum：绿/黄通道的权重占比不低于 threshold 时可用（全部绿灯为绿灯，否则黄灯），否则红灯
+type ProviderStatusConfig struct {
+	// 计算模式：weighted（默认）/ worst / quorum
+	Mode string `yaml:"mode" json:"mode"`
+
+	// 阈值（0-1，默认 0.5）：weighted 模式为黄/红分界得分，quorum 模式为可用权重占比下限
+	// 使用 *float64 以区分"未设置(nil)"和"显式设置为 0"
+	Threshold *float64 `yaml:"threshold" json:"threshold"`
+
+	// provider 级覆盖（如仅关心 VIP 通道的服务商使用 worst 模式）
+	Providers []ProviderStatusOverride `yaml:"providers" json:"providers,omitempty"`
+}
+
+// ProviderStatusOverride provider 级综合状态配置覆盖
+type ProviderStatusOverride struct {
+	Provider  string   `yaml:"provider" json:"provider"`   // provider 名称，匹配时忽略大小写和首尾空格
+	Mode      string   `yaml:"mode" json:"mode"`           // 为空时沿用全局 mode
+	Threshold *float64 `yaml:"threshold" json:"threshold"` // 为空时沿用全局 threshold
+}
+
+// Normalize 规范化综合状态配置（填充默认值并校验）
+func (p *ProviderStatusConfig) Normalize() error {
+	p.Mode = strings.ToLower(strings.TrimSpace(p.Mode))
+	if p.Mode == "" {
+		p.Mode = ProviderStatusModeWeighted
+	}
+	if !isValidProviderStatusMode(p.Mode) {
+		return fmt.Errorf("provider_status.mode 无效: %s（支持 weighted/worst/quorum）", p.Mode)
+	}
+	if p.Threshold == nil {
+		v := defaultProviderStatusThreshold
+		p.Threshold = &v
+	}
+	if *p.Threshold < 0 || *p.Threshold > 1 {
+		return fmt.Errorf("provider_status.threshold 必须在 0-1 之间，当前值: %g", *p.Threshold)
+	}
+
+	for i := range p.Providers {
+		o := &p.Providers[i]
+		if strings.TrimSpace(o.Provider) == "" {
+			return fmt.Errorf("provider_status.providers[%d]: provider 不能为空", i)
+		}
+		o.Mode = strings.ToLower(strings.TrimSpace(o.Mode))
+		if o.Mode != "" && !isValidProviderStatusMode(o.Mode) {
+			return fmt.Errorf("provider_status.providers[%d]: mode 无效: %s（支持 weighted/worst/quorum）", i, o.Mode)
+		}
+		if o.Threshold != nil && (*o.Threshold < 0 || *o.Threshold > 1) {
+			return fmt.Errorf("provider_status.providers[%d]: threshold 必须在 0-1 之间，当前值: %g", i, *o.Threshold)
+		}
+	}
+	return nil
+}
+
+// ModeFor 返回指定 provider 生效的计算模式与阈值（provider 级覆盖优先）
+func (p ProviderStatusConfig) ModeFor(provider string) (string, float64) {
+	mode := p.Mode
+	if mode == "" {
+		mode = ProviderStatusModeWeighted
+	}
+	threshold := defaultProviderStatusThreshold
+	if p.Threshold != nil {
+		threshold = *p.Threshold
+	}
+
+	normalized := strings.ToLower(strings.TrimSpace(provider))
+	for _, o := range p.Providers {
+		if strings.ToLower(strings.TrimSpace(o.Provider)) != normalized {
+			continue
+		}
+		if o.Mode != "" {
+			mode = o.Mode
+		}
+		if o.Threshold != nil {
+			threshold = *o.Threshold
+		}
+		break
+	}
+	return mode, threshold
+}
+
+// Clone 深拷贝综合状态配置
+func (p ProviderStatusConfig) Clone() ProviderStatusConfig {
+	clone := p
+	clone.Threshold = cloneFloat64Ptr(p.Threshold)
+	if p.Providers != nil {
+		clone.Providers = make([]ProviderStatusOverride, len(p.Providers))
+		for i, o := range p.Providers {
+			o.Threshold = cloneFloat64Ptr(o.Threshold)
+			clone.Providers[i] = o
+		}
+	}
+	return clone
+}
+
+func isValidProviderStatusMode(mode string) bool {
+	switch mode {
+	case ProviderStatusModeWeighted, ProviderStatusModeWorst, ProviderStatusModeQuorum:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,51 @@
+package config
+
+import "testing"
+
+func TestProviderStatusConfigNormalize(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		var p ProviderStatusConfig
+		if err := p.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if p.Mode != ProviderStatusModeWeighted || *p.Threshold != 0.5 {
+			t.Fatalf("unexpected defaults: mode=%s threshold=%v", p.Mode, *p.Threshold)
+		}
+	})
+
+	t.Run("provider override", func(t *testing.T) {
+		p := ProviderStatusConfig{
+			Mode: "Quorum",
+			Providers: []ProviderStatusOverride{
+				{Provider: " VIPRelay ", Mode: "worst"},
+				{Provider: "half", Threshold: floatPtr(0.8)},
+			},
+		}
+		if err := p.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if mode, _ := p.ModeFor("viprelay"); mode != ProviderStatusModeWorst {
+			t.Fatalf("expected override mode worst, got %s", mode)
+		}
+		if mode, threshold := p.ModeFor("half"); mode != ProviderStatusModeQuorum || threshold != 0.8 {
+			t.Fatalf("expected global mode with overridden threshold, got %s/%v", mode, threshold)
+		}
+		if mode, threshold := p.ModeFor("other"); mode != ProviderStatusModeQuorum || threshold != 0.5 {
+			t.Fatalf("expected global settings, got %s/%v", mode, threshold)
+		}
+	})
+
+	invalid := map[string]ProviderStatusConfig{
+		"bad mode":          {Mode: "best"},
+		"bad threshold":     {Threshold: floatPtr(1.5)},
+		"empty provider":    {Providers: []ProviderStatusOverride{{Mode: "worst"}}},
+		"bad override mode": {Providers: []ProviderStatusOverride{{Provider: "x", Mode: "best"}}},
+	}
+	for name, p := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if err := p.Normalize(); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
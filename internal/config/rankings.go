@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// 排行榜评分默认参数
+const (
+	defaultRankingUptimeWeight      = 0.6
+	defaultRankingLatencyWeight     = 0.3
+	defaultRankingPriceWeight       = 0.1
+	defaultRankingFlapPenalty       = 0.5
+	defaultRankingLatencyPercentile = 95
+	defaultRankingLatencyGood       = "1s"
+	defaultRankingLatencyBad        = "10s"
+	defaultRankingMinProbes         = 10
+)
+
+// RankingsConfig 服务商排行榜（/api/rankings）评分配置
+//
+// 评分公式（0-100）：
+//
+//	score = (uptime_weight*可用率 + latency_weight*延迟分 + price_weight*价格分) / 权重和
+//	        - flap_penalty * 抖动率(%)
+//
+// 其中：
+//   - 延迟分：latency_percentile 分位延迟 ≤ latency_good 记 100，≥ latency_bad 记 0，中间线性插值
+//   - 价格分：按 price_min/price_max 参考倍率在参与排名的条目间归一化，最便宜记 100、最贵记 0，未配置价格记 50
+//   - 抖动率：相邻两次探测可用性发生翻转（可用 ↔ 不可用）的比例
+//
+// 权重使用 *float64 以区分"未设置(nil)"和"显式设置为 0"，Normalize 后均不为 nil
+type RankingsConfig struct {
+	// 可用率权重（默认 0.6）
+	UptimeWeight *float64 `yaml:"uptime_weight" json:"uptime_weight"`
+
+	// 延迟分权重（默认 0.3）
+	LatencyWeight *float64 `yaml:"latency_weight" json:"latency_weight"`
+
+	// 价格分权重（默认 0.1）
+	PriceWeight *float64 `yaml:"price_weight" json:"price_weight"`
+
+	// 抖动惩罚：每 1% 抖动率扣除的分数（默认 0.5）
+	FlapPenalty *float64 `yaml:"flap_penalty" json:"flap_penalty"`
+
+	// 延迟分位数（1-100，默认 95 即 P95）
+	LatencyPercentile int `yaml:"latency_percentile" json:"latency_percentile"`
+
+	// 延迟满分阈值（默认 "1s"）
+	LatencyGood string `yaml:"latency_good" json:"latency_good"`
+
+	// 延迟零分阈值（默认 "10s"，必须大于 latency_good）
+	LatencyBad string `yaml:"latency_bad" json:"latency_bad"`
+
+	// 参与排名所需的最少探测次数（默认 10，样本不足的条目不参与排名）
+	MinProbes int `yaml:"min_probes" json:"min_probes"`
+
+	// 解析后的延迟阈值（内部使用）
+	LatencyGoodDuration time.Duration `yaml:"-" json:"-"`
+	LatencyBadDuration  time.Duration `yaml:"-" json:"-"`
+}
+
+// Normalize 规范化排行榜配置（填充默认值并校验）
+func (r *RankingsConfig) Normalize() error {
+	weights := []struct {
+		name string
+		ptr  **float64
+		def  float64
+	}{
+		{"uptime_weight", &r.UptimeWeight, defaultRankingUptimeWeight},
+		{"latency_weight", &r.LatencyWeight, defaultRankingLatencyWeight},
+		{"price_weight", &r.PriceWeight, defaultRankingPriceWeight},
+		{"flap_penalty", &r.FlapPenalty, defaultRankingFlapPenalty},
+	}
+	for _, w := range weights {
+		if *w.ptr == nil {
+			v := w.def
+			*w.ptr = &v
+		}
+		if **w.ptr < 0 {
+			return fmt.Errorf("rankings.%s 不能为负数，当前值: %g", w.name, **w.ptr)
+		}
+	}
+	if *r.UptimeWeight+*r.LatencyWeight+*r.PriceWeight <= 0 {
+		return fmt.Errorf("rankings.uptime_weight/latency_weight/price_weight 至少有一个必须大于 0")
+	}
+
+	if r.LatencyPercentile == 0 {
+		r.LatencyPercentile = defaultRankingLatencyPercentile
+	}
+	if r.LatencyPercentile < 1 || r.LatencyPercentile > 100 {
+		return fmt.Errorf("rankings.latency_percentile 必须在 1-100 之间，当前值: %d", r.LatencyPercentile)
+	}
+
+	if r.LatencyGood == "" {
+		r.LatencyGood = defaultRankingLatencyGood
+	}
+	if r.LatencyBad == "" {
+		r.LatencyBad = defaultRankingLatencyBad
+	}
+	good, err := time.ParseDuration(r.LatencyGood)
+	if err != nil || good < 0 {
+		return fmt.Errorf("rankings.latency_good 格式无效: %s", r.LatencyGood)
+	}
+	bad, err := time.ParseDuration(r.LatencyBad)
+	if err != nil || bad <= good {
+		return fmt.Errorf("rankings.latency_bad 必须是大于 latency_good 的有效时长: %s", r.LatencyBad)
+	}
+	r.LatencyGoodDuration = good
+	r.LatencyBadDuration = bad
+
+	if r.MinProbes == 0 {
+		r.MinProbes = defaultRankingMinProbes
+	}
+	if r.MinProbes < 0 {
+		return fmt.Errorf("rankings.min_probes 不能为负数，当前值: %d", r.MinProbes)
+	}
+	return nil
+}
+
+// Clone 深拷贝排行榜配置
+func (r RankingsConfig) Clone() RankingsConfig {
+	clone := r
+	clone.UptimeWeight = cloneFloat64Ptr(r.UptimeWeight)
+	clone.LatencyWeight = cloneFloat64Ptr(r.LatencyWeight)
+	clone.PriceWeight = cloneFloat64Ptr(r.PriceWeight)
+	clone.FlapPenalty = cloneFloat64Ptr(r.FlapPenalty)
+	return clone
+}
@@ -0,0 +1,47 @@
+package config
+
+import "testing"
+
+func TestRankingsConfigNormalize(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		var r RankingsConfig
+		if err := r.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if *r.UptimeWeight != 0.6 || *r.LatencyWeight != 0.3 || *r.PriceWeight != 0.1 || *r.FlapPenalty != 0.5 {
+			t.Fatalf("unexpected default weights: %+v", r)
+		}
+		if r.LatencyPercentile != 95 || r.MinProbes != 10 {
+			t.Fatalf("unexpected defaults: percentile=%d min_probes=%d", r.LatencyPercentile, r.MinProbes)
+		}
+	})
+
+	t.Run("explicit zero weight kept", func(t *testing.T) {
+		zero := 0.0
+		r := RankingsConfig{PriceWeight: &zero}
+		if err := r.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if *r.PriceWeight != 0 {
+			t.Fatalf("显式设置的 0 不应被默认值覆盖, got=%v", *r.PriceWeight)
+		}
+	})
+
+	invalid := map[string]RankingsConfig{
+		"negative weight":  {FlapPenalty: floatPtr(-1)},
+		"all zero weights": {UptimeWeight: floatPtr(0), LatencyWeight: floatPtr(0), PriceWeight: floatPtr(0)},
+		"bad percentile":   {LatencyPercentile: 101},
+		"bad order":        {LatencyGood: "5s", LatencyBad: "2s"},
+	}
+	for name, r := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if err := r.Normalize(); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// redactMarker 脱敏后替换凭证主体的标记
+const redactMarker = "***"
+
+// defaultRedactKeepPrefix 默认保留的凭证前缀字符数
+const defaultRedactKeepPrefix = 4
+
+// defaultRedactHeaders 默认脱敏的请求头/响应头
+var defaultRedactHeaders = []string{"authorization", "proxy-authorization", "x-api-key", "api-key", "x-goog-api-key", "cookie", "set-cookie"}
+
+// sensitiveKeyParts 名称包含这些片段的请求头/查询参数无论是否在列表中都按敏感信息脱敏
+var sensitiveKeyParts = []string{"key", "token", "auth", "secret", "cookie", "session", "password"}
+
+// RedactionConfig 请求数据脱敏策略
+//
+// 作用于所有可能记录或保存请求数据的位置：调试捕获快照（probe_debug）、
+// /api/admin/probe-debug 返回的请求头/响应头、verify 命令的 -v 输出。
+// 零值等价于默认策略
+type RedactionConfig struct {
+	// 需要脱敏的头部/查询参数名称（忽略大小写）
+	// 未配置时为 authorization、proxy-authorization、x-api-key、api-key、x-goog-api-key、cookie、set-cookie；
+	// 名称包含 key/token/auth/secret/cookie/session/password 的始终脱敏
+	Headers []string `yaml:"headers" json:"headers"`
+
+	// 保留的凭证前缀字符数（默认 4，0 表示完全隐藏），用于辨认使用的是哪个密钥
+	KeepPrefix *int `yaml:"keep_prefix" json:"keep_prefix"`
+}
+
+// Normalize 规范化脱敏策略（名称小写去重，补全默认值）
+func (r *RedactionConfig) Normalize() error {
+	if r.Headers == nil {
+		r.Headers = append([]string(nil), defaultRedactHeaders...)
+	}
+	seen := make(map[string]bool, len(r.Headers))
+	headers := make([]string, 0, len(r.Headers))
+	for _, h := range r.Headers {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h == "" {
+			return fmt.Errorf("redaction.headers 不能包含空名称")
+		}
+		if !seen[h] {
+			seen[h] = true
+			headers = append(headers, h)
+		}
+	}
+	r.Headers = headers
+
+	if r.KeepPrefix == nil {
+		v := defaultRedactKeepPrefix
+		r.KeepPrefix = &v
+	}
+	if *r.KeepPrefix < 0 || *r.KeepPrefix > 16 {
+		return fmt.Errorf("redaction.keep_prefix 必须在 0-16 范围内，当前值: %d", *r.KeepPrefix)
+	}
+	return nil
+}
+
+// Clone 深拷贝脱敏策略
+func (r RedactionConfig) Clone() RedactionConfig {
+	if r.Headers != nil {
+		r.Headers = append([]string(nil), r.Headers...)
+	}
+	r.KeepPrefix = cloneIntPtr(r.KeepPrefix)
+	return r
+}
+
+// Sensitive 判断请求头或查询参数名是否需要脱敏
+func (r RedactionConfig) Sensitive(name string) bool {
+	lower := strings.ToLower(strings.TrimSpace(name))
+	headers := r.Headers
+	if headers == nil {
+		headers = defaultRedactHeaders
+	}
+	for _, h := range headers {
+		if lower == h {
+			return true
+		}
+	}
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact 按策略脱敏单个头部值（非敏感名称原样返回）
+// 已脱敏的值（含 ***）原样返回，重复应用不会丢失保留的前缀
+func (r RedactionConfig) Redact(name, value string) string {
+	if value == "" || !r.Sensitive(name) || strings.Contains(value, redactMarker) {
+		return value
+	}
+	return r.Mask(value)
+}
+
+// Mask 脱敏凭证：保留认证方案（如 "Bearer "）与凭证前 keep_prefix 个字符，其余替换为 ***
+// 凭证长度不足 keep_prefix 两倍时完全隐藏，避免短凭证泄露过多内容
+func (r RedactionConfig) Mask(value string) string {
+	keep := defaultRedactKeepPrefix
+	if r.KeepPrefix != nil {
+		keep = *r.KeepPrefix
+	}
+
+	scheme, credential := "", strings.TrimSpace(value)
+	if i := strings.IndexByte(credential, ' '); i > 0 && !strings.ContainsAny(credential[:i], "=;,:") {
+		scheme, credential = credential[:i+1], strings.TrimSpace(credential[i+1:])
+	}
+
+	runes := []rune(credential)
+	if keep == 0 || len(runes) < keep*2 {
+		return scheme + redactMarker
+	}
+	return scheme + string(runes[:keep]) + redactMarker
+}
+
+// RedactURL 去除 URL 中的用户凭据，并对敏感查询参数脱敏（如 Gemini 的 ?key=）
+func (r RedactionConfig) RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	masked := *u
+	masked.User = nil
+	query := masked.Query()
+	changed := false
+	for k, values := range query {
+		if !r.Sensitive(k) {
+			continue
+		}
+		for i := range values {
+			values[i] = r.Mask(values[i])
+		}
+		changed = true
+	}
+	if changed {
+		masked.RawQuery = query.Encode()
+	}
+	return masked.String()
+}
@@ -0,0 +1,351 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"monitor/internal/logger"
+)
+
+// 远程配置相关环境变量
+//   - MONITOR_CONFIG_SOURCE: 配置来源，支持 https://、http://、s3://bucket/key、git+https://host/repo.git#ref:path
+//   - MONITOR_CONFIG_POLL_INTERVAL: 轮询间隔（默认 1m）
+//   - MONITOR_CONFIG_TOKEN: HTTP 来源的 Bearer Token（可选）
+//   - MONITOR_CONFIG_VERIFY: 校验方式 none/sha256/sha256-sidecar/ed25519（默认 none）
+//   - MONITOR_CONFIG_SHA256: 固定的 sha256 摘要（sha256 模式必填）
+//   - MONITOR_CONFIG_PUBLIC_KEY: ed25519 公钥（base64），签名读取 <source>.sig
+const (
+	envConfigSource       = "MONITOR_CONFIG_SOURCE"
+	envConfigPollInterval = "MONITOR_CONFIG_POLL_INTERVAL"
+	envConfigToken        = "MONITOR_CONFIG_TOKEN"
+	envConfigVerify       = "MONITOR_CONFIG_VERIFY"
+	envConfigSHA256       = "MONITOR_CONFIG_SHA256"
+	envConfigPublicKey    = "MONITOR_CONFIG_PUBLIC_KEY"
+
+	defaultRemotePollInterval = time.Minute
+	remoteFetchTimeout        = 30 * time.Second
+	remoteMaxConfigSize       = 10 << 20 // 10MB
+)
+
+// RemoteSource 远程配置来源
+// suffix 为空时拉取配置本体；非空时拉取同位置的附属文件（如 ".sha256"、".sig"）
+type RemoteSource interface {
+	Fetch(ctx context.Context, suffix string) ([]byte, error)
+	String() string
+}
+
+// NewRemoteSource 根据 URL scheme 创建远程配置来源
+func NewRemoteSource(rawURL string) (RemoteSource, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	switch {
+	case strings.HasPrefix(rawURL, "http://"), strings.HasPrefix(rawURL, "https://"):
+		if _, err := url.Parse(rawURL); err != nil {
+			return nil, fmt.Errorf("无效的配置来源 URL: %w", err)
+		}
+		return &httpSource{
+			url:    rawURL,
+			token:  strings.TrimSpace(os.Getenv(envConfigToken)),
+			client: &http.Client{Timeout: remoteFetchTimeout},
+		}, nil
+	case strings.HasPrefix(rawURL, "s3://"):
+		return newS3Source(rawURL)
+	case strings.HasPrefix(rawURL, "git+"):
+		return newGitSource(rawURL)
+	default:
+		return nil, fmt.Errorf("不支持的配置来源: %s（仅支持 http(s)://、s3://、git+）", rawURL)
+	}
+}
+
+// httpSource 通过 HTTP(S) GET 拉取配置
+type httpSource struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func (s *httpSource) Fetch(ctx context.Context, suffix string) ([]byte, error) {
+	target := s.url
+	if suffix != "" {
+		u, err := url.Parse(s.url)
+		if err != nil {
+			return nil, err
+		}
+		u.Path += suffix
+		target = u.String()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return readRemoteBody(resp)
+}
+
+func (s *httpSource) String() string {
+	return redactURL(s.url)
+}
+
+// readRemoteBody 读取响应体（限制大小，非 2xx 视为失败）
+func readRemoteBody(resp *http.Response) ([]byte, error) {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, remoteMaxConfigSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > remoteMaxConfigSize {
+		return nil, fmt.Errorf("远程配置超过大小限制 (%d bytes)", remoteMaxConfigSize)
+	}
+	return data, nil
+}
+
+// redactURL 去除 URL 中的用户凭据和查询参数（用于日志）
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	u.User = nil
+	u.RawQuery = ""
+	return u.String()
+}
+
+// RemoteVerifier 远程配置校验
+//
+// 能证明配置来源可信的只有 sha256（摘要经部署环境固定）与 ed25519（私钥不随配置分发）；
+// sha256-sidecar 的摘要与配置来自同一位置，能改写配置的一方同样能改写摘要，仅用于发现传输截断或损坏
+type RemoteVerifier struct {
+	mode      string // none / sha256 / sha256-sidecar / ed25519
+	sha256    string // 固定摘要（小写 hex）
+	publicKey ed25519.PublicKey
+}
+
+// newRemoteVerifierFromEnv 从环境变量构建校验器
+func newRemoteVerifierFromEnv() (*RemoteVerifier, error) {
+	mode := strings.ToLower(strings.TrimSpace(os.Getenv(envConfigVerify)))
+	if mode == "" {
+		mode = "none"
+	}
+
+	v := &RemoteVerifier{mode: mode}
+	switch mode {
+	case "none":
+	case "sha256":
+		v.sha256 = strings.ToLower(strings.TrimSpace(os.Getenv(envConfigSHA256)))
+		if len(v.sha256) != sha256.Size*2 {
+			return nil, fmt.Errorf("%s=sha256 时必须配置 64 位十六进制的 %s（仅需完整性校验可使用 sha256-sidecar）", envConfigVerify, envConfigSHA256)
+		}
+		if _, err := hex.DecodeString(v.sha256); err != nil {
+			return nil, fmt.Errorf("%s 必须是十六进制的 sha256 摘要", envConfigSHA256)
+		}
+	case "sha256-sidecar":
+		logger.Warn("config", "sha256-sidecar 仅校验传输完整性：摘要文件与配置同源，无法防止来源被篡改；需要防篡改请使用 sha256（固定摘要）或 ed25519")
+	case "ed25519":
+		raw := strings.TrimSpace(os.Getenv(envConfigPublicKey))
+		if raw == "" {
+			return nil, fmt.Errorf("%s=ed25519 时必须配置 %s", envConfigVerify, envConfigPublicKey)
+		}
+		key, err := base64.StdEncoding.DecodeString(raw)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%s 必须是 base64 编码的 ed25519 公钥", envConfigPublicKey)
+		}
+		v.publicKey = ed25519.PublicKey(key)
+	default:
+		return nil, fmt.Errorf("%s 无效: %s（可选 none/sha256/sha256-sidecar/ed25519）", envConfigVerify, mode)
+	}
+	return v, nil
+}
+
+// Verify 校验配置内容
+func (v *RemoteVerifier) Verify(ctx context.Context, src RemoteSource, data []byte) error {
+	switch v.mode {
+	case "sha256":
+		return verifySHA256(data, v.sha256)
+	case "sha256-sidecar":
+		sidecar, err := src.Fetch(ctx, ".sha256")
+		if err != nil {
+			return fmt.Errorf("拉取 sha256 摘要失败: %w", err)
+		}
+		// 兼容 sha256sum 输出格式："<hex>  <filename>"
+		fields := strings.Fields(string(sidecar))
+		if len(fields) == 0 {
+			return fmt.Errorf("sha256 摘要文件为空")
+		}
+		return verifySHA256(data, strings.ToLower(fields[0]))
+	case "ed25519":
+		sigData, err := src.Fetch(ctx, ".sig")
+		if err != nil {
+			return fmt.Errorf("拉取签名失败: %w", err)
+		}
+		sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sigData)))
+		if err != nil {
+			return fmt.Errorf("签名格式无效: %w", err)
+		}
+		if !ed25519.Verify(v.publicKey, data, sig) {
+			return fmt.Errorf("ed25519 签名校验失败")
+		}
+	}
+	return nil
+}
+
+func verifySHA256(data []byte, expected string) error {
+	sum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return fmt.Errorf("sha256 校验失败: expected=%s actual=%s", expected, actual)
+	}
+	return nil
+}
+
+// RemoteSyncer 周期性拉取远程配置并写入本地配置文件
+// 写入后由 Watcher 走现有热更新流程（验证 → 回调），无需额外的应用逻辑
+type RemoteSyncer struct {
+	source   RemoteSource
+	verifier *RemoteVerifier
+	target   string
+	interval time.Duration
+}
+
+// NewRemoteSyncerFromEnv 根据环境变量创建远程配置同步器
+// 未设置 MONITOR_CONFIG_SOURCE 时返回 (nil, nil)
+func NewRemoteSyncerFromEnv(target string) (*RemoteSyncer, error) {
+	raw := strings.TrimSpace(os.Getenv(envConfigSource))
+	if raw == "" {
+		return nil, nil
+	}
+
+	src, err := NewRemoteSource(raw)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := newRemoteVerifierFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	interval := defaultRemotePollInterval
+	if v := strings.TrimSpace(os.Getenv(envConfigPollInterval)); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 10*time.Second {
+			return nil, fmt.Errorf("%s 无效: %s（最小 10s）", envConfigPollInterval, v)
+		}
+		interval = d
+	}
+
+	return &RemoteSyncer{
+		source:   src,
+		verifier: verifier,
+		target:   target,
+		interval: interval,
+	}, nil
+}
+
+// NewRemoteSyncer 使用指定来源创建同步器（便于测试和自定义来源）
+func NewRemoteSyncer(src RemoteSource, verifier *RemoteVerifier, target string, interval time.Duration) *RemoteSyncer {
+	if verifier == nil {
+		verifier = &RemoteVerifier{mode: "none"}
+	}
+	if interval <= 0 {
+		interval = defaultRemotePollInterval
+	}
+	return &RemoteSyncer{source: src, verifier: verifier, target: target, interval: interval}
+}
+
+// Sync 拉取一次远程配置，内容变化且校验通过时原子替换本地文件
+// 返回 changed=true 表示本地文件已更新
+func (s *RemoteSyncer) Sync(ctx context.Context) (bool, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, remoteFetchTimeout)
+	defer cancel()
+
+	data, err := s.source.Fetch(fetchCtx, "")
+	if err != nil {
+		return false, fmt.Errorf("拉取远程配置失败 (%s): %w", s.source, err)
+	}
+	if err := s.verifier.Verify(fetchCtx, s.source, data); err != nil {
+		return false, err
+	}
+
+	// 内容未变化时跳过，避免无意义的热更新
+	if current, err := os.ReadFile(s.target); err == nil && bytes.Equal(current, data) {
+		return false, nil
+	}
+
+	// 先写入同目录的隐藏临时文件并完整加载校验，再 rename 覆盖
+	// 同目录保证 include_dir / data/ 等相对路径解析一致，且 rename 为原子操作
+	dir := filepath.Dir(s.target)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(s.target)+".remote-*")
+	if err != nil {
+		return false, fmt.Errorf("创建临时配置文件失败: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return false, fmt.Errorf("写入临时配置文件失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return false, fmt.Errorf("写入临时配置文件失败: %w", err)
+	}
+
+	if _, err := NewLoader().Load(tmpPath); err != nil {
+		return false, fmt.Errorf("远程配置校验失败: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, s.target); err != nil {
+		return false, fmt.Errorf("替换配置文件失败: %w", err)
+	}
+	return true, nil
+}
+
+// Start 启动后台轮询（阻塞直到 ctx 取消）
+func (s *RemoteSyncer) Start(ctx context.Context) {
+	logger.Info("config", "远程配置轮询已启动", "source", s.source.String(), "interval", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("config", "远程配置轮询已停止")
+			return
+		case <-ticker.C:
+			changed, err := s.Sync(ctx)
+			if err != nil {
+				logger.Warn("config", "远程配置同步失败，保持当前配置", "error", err)
+				continue
+			}
+			if changed {
+				logger.Info("config", "远程配置已更新，等待热更新生效", "source", s.source.String())
+			}
+		}
+	}
+}
+
+// Source 返回配置来源描述（已脱敏）
+func (s *RemoteSyncer) Source() string {
+	return s.source.String()
+}
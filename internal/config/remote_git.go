@@ -0,0 +1,102 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// gitSource 通过 git 仓库拉取配置
+// URL 格式：git+https://host/org/repo.git#<ref>:<path>，例如 git+https://github.com/org/ops.git#main:relay/config.yaml
+// 依赖系统 git 命令；仓库浅克隆到临时目录，每次拉取执行 fetch + reset
+// repo/ref 来自环境变量，传给 git 时均置于 "--" 之后，避免以 "-" 开头的值被解析为选项
+type gitSource struct {
+	repo     string
+	ref      string
+	path     string
+	cacheDir string
+
+	mu sync.Mutex
+}
+
+func newGitSource(rawURL string) (*gitSource, error) {
+	trimmed := strings.TrimPrefix(rawURL, "git+")
+	repo, fragment, ok := strings.Cut(trimmed, "#")
+	if !ok {
+		return nil, fmt.Errorf("git 来源格式应为 git+<repo>#<ref>:<path>: %s", rawURL)
+	}
+	ref, path, ok := strings.Cut(fragment, ":")
+	if !ok || strings.TrimSpace(ref) == "" || strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("git 来源格式应为 git+<repo>#<ref>:<path>: %s", rawURL)
+	}
+
+	cleanPath := filepath.Clean(strings.TrimPrefix(path, "/"))
+	if cleanPath == ".." || strings.HasPrefix(cleanPath, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("git 来源路径不能越出仓库: %s", path)
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git 来源需要系统安装 git 命令: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(repo + "#" + ref))
+	return &gitSource{
+		repo:     repo,
+		ref:      ref,
+		path:     cleanPath,
+		cacheDir: filepath.Join(os.TempDir(), "relay-pulse-config-"+hex.EncodeToString(sum[:8])),
+	}, nil
+}
+
+// Fetch suffix 为空时先同步仓库再读取文件；附属文件直接读取已同步的工作区
+func (s *gitSource) Fetch(ctx context.Context, suffix string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if suffix == "" {
+		if err := s.sync(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.cacheDir, s.path+suffix))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > remoteMaxConfigSize {
+		return nil, fmt.Errorf("远程配置超过大小限制 (%d bytes)", remoteMaxConfigSize)
+	}
+	return data, nil
+}
+
+// sync 克隆或更新本地缓存仓库
+func (s *gitSource) sync(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(s.cacheDir, ".git")); err != nil {
+		_ = os.RemoveAll(s.cacheDir)
+		return s.run(ctx, "", "clone", "--depth", "1", "--branch="+s.ref, "--", s.repo, s.cacheDir)
+	}
+	if err := s.run(ctx, s.cacheDir, "fetch", "--depth", "1", "--", "origin", s.ref); err != nil {
+		return err
+	}
+	return s.run(ctx, s.cacheDir, "reset", "--hard", "FETCH_HEAD")
+}
+
+func (s *gitSource) run(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s 失败: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (s *gitSource) String() string {
+	return "git+" + redactURL(s.repo) + "#" + s.ref + ":" + s.path
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// s3Source 通过 S3 REST API 拉取配置（s3://bucket/key）
+// 凭据读取标准 AWS 环境变量（AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY / AWS_SESSION_TOKEN），未配置时匿名访问
+// 区域读取 AWS_REGION（默认 us-east-1）；MONITOR_CONFIG_S3_ENDPOINT 可指定 MinIO 等兼容服务（使用 path-style）
+type s3Source struct {
+	bucket   string
+	key      string
+	region   string
+	endpoint string // 自定义 endpoint（为空时使用 AWS virtual-hosted 风格）
+	creds    awsCredentials
+	client   *http.Client
+}
+
+func newS3Source(rawURL string) (*s3Source, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 S3 URL: %w", err)
+	}
+	key := strings.TrimPrefix(u.Path, "/")
+	if u.Host == "" || key == "" {
+		return nil, fmt.Errorf("S3 URL 格式应为 s3://bucket/key: %s", rawURL)
+	}
+
+	return &s3Source{
+		bucket:   u.Host,
+		key:      key,
+		region:   awsRegionFromEnv(),
+		endpoint: strings.TrimRight(strings.TrimSpace(os.Getenv("MONITOR_CONFIG_S3_ENDPOINT")), "/"),
+		creds:    awsCredentialsFromEnv(),
+		client:   &http.Client{Timeout: remoteFetchTimeout},
+	}, nil
+}
+
+func (s *s3Source) Fetch(ctx context.Context, suffix string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(s.key+suffix), nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.creds.valid() {
+		signAWSV4(req, "UNSIGNED-PAYLOAD", "s3", s.region, s.creds, time.Now())
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return readRemoteBody(resp)
+}
+
+func (s *s3Source) String() string {
+	return "s3://" + s.bucket + "/" + s.key
+}
+
+// objectURL 构造对象访问 URL
+func (s *s3Source) objectURL(key string) string {
+	escaped := (&url.URL{Path: "/" + key}).EscapedPath()
+	if s.endpoint != "" {
+		return s.endpoint + "/" + s.bucket + escaped
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com%s", s.bucket, s.region, escaped)
+}
@@ -0,0 +1,242 @@
+package config
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const remoteTestConfig = `
+interval: "1m"
+monitors:
+  - provider: "remote"
+    service: "cc"
+    category: "public"
+    sponsor: "remote"
+    url: "https://remote.example.com"
+    method: "POST"
+`
+
+func newRemoteTestServer(t *testing.T, files map[string]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRemoteSyncerWritesVerifiedConfig(t *testing.T) {
+	t.Parallel()
+
+	sum := sha256.Sum256([]byte(remoteTestConfig))
+	srv := newRemoteTestServer(t, map[string]string{
+		"/config.yaml":        remoteTestConfig,
+		"/config.yaml.sha256": hex.EncodeToString(sum[:]) + "  config.yaml\n",
+	})
+
+	src, err := NewRemoteSource(srv.URL + "/config.yaml")
+	if err != nil {
+		t.Fatalf("创建来源失败: %v", err)
+	}
+
+	target := filepath.Join(t.TempDir(), "config.yaml")
+	syncer := NewRemoteSyncer(src, &RemoteVerifier{mode: "sha256-sidecar"}, target, 0)
+
+	changed, err := syncer.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("同步失败: %v", err)
+	}
+	if !changed {
+		t.Fatalf("首次同步应写入文件")
+	}
+	data, err := os.ReadFile(target)
+	if err != nil || string(data) != remoteTestConfig {
+		t.Fatalf("本地文件内容不符合预期: %v", err)
+	}
+
+	// 内容未变化时不应重复写入
+	changed, err = syncer.Sync(context.Background())
+	if err != nil || changed {
+		t.Fatalf("内容未变化时应跳过, changed=%v err=%v", changed, err)
+	}
+}
+
+func TestRemoteSyncerRejectsChecksumMismatch(t *testing.T) {
+	t.Parallel()
+
+	srv := newRemoteTestServer(t, map[string]string{"/config.yaml": remoteTestConfig})
+	src, _ := NewRemoteSource(srv.URL + "/config.yaml")
+
+	target := filepath.Join(t.TempDir(), "config.yaml")
+	verifier := &RemoteVerifier{mode: "sha256", sha256: strings.Repeat("0", 64)}
+	if _, err := NewRemoteSyncer(src, verifier, target, 0).Sync(context.Background()); err == nil {
+		t.Fatalf("期望 sha256 不匹配时报错")
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Fatalf("校验失败时不应写入本地文件")
+	}
+}
+
+func TestRemoteSyncerRejectsInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	srv := newRemoteTestServer(t, map[string]string{"/config.yaml": "interval: \"1m\"\n"})
+	src, _ := NewRemoteSource(srv.URL + "/config.yaml")
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "config.yaml")
+	if _, err := NewRemoteSyncer(src, nil, target, 0).Sync(context.Background()); err == nil {
+		t.Fatalf("期望无效配置被拒绝")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Fatalf("临时文件未清理: %v", entries)
+	}
+}
+
+func TestRemoteVerifierFromEnv(t *testing.T) {
+	sum := sha256.Sum256([]byte(remoteTestConfig))
+	digest := hex.EncodeToString(sum[:])
+
+	cases := []struct {
+		name    string
+		mode    string
+		sha     string
+		wantErr bool
+	}{
+		{"默认不校验", "", "", false},
+		{"固定摘要", "sha256", strings.ToUpper(digest), false},
+		{"sha256 缺少固定摘要", "sha256", "", true},
+		{"sha256 摘要格式错误", "sha256", strings.Repeat("z", 64), true},
+		{"同源摘要", "sha256-sidecar", "", false},
+		{"ed25519 缺少公钥", "ed25519", "", true},
+		{"未知模式", "md5", "", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(envConfigVerify, tc.mode)
+			t.Setenv(envConfigSHA256, tc.sha)
+			t.Setenv(envConfigPublicKey, "")
+			v, err := newRemoteVerifierFromEnv()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err=%v, wantErr=%v", err, tc.wantErr)
+			}
+			if err != nil || tc.mode != "sha256" {
+				return
+			}
+			// 固定摘要不读取同源摘要文件（来源为 nil 时若访问会 panic）
+			if err := v.Verify(context.Background(), nil, []byte(remoteTestConfig)); err != nil {
+				t.Fatalf("固定摘要校验应通过: %v", err)
+			}
+			if err := v.Verify(context.Background(), nil, []byte(remoteTestConfig+"#")); err == nil {
+				t.Fatalf("内容被篡改时固定摘要校验应失败")
+			}
+		})
+	}
+}
+
+func TestGitSourceFetch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("未安装 git")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(repo, "config.yaml"), []byte(remoteTestConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "config.yaml")
+	git("commit", "-q", "-m", "init")
+
+	src, err := newGitSource("git+file://" + repo + "#main:config.yaml")
+	if err != nil {
+		t.Fatalf("创建 git 来源失败: %v", err)
+	}
+	src.cacheDir = filepath.Join(t.TempDir(), "cache")
+
+	// 首次克隆，再次拉取走 fetch + reset
+	for i := 0; i < 2; i++ {
+		data, err := src.Fetch(context.Background(), "")
+		if err != nil || string(data) != remoteTestConfig {
+			t.Fatalf("第 %d 次拉取失败: %v", i+1, err)
+		}
+	}
+
+	// 以 "-" 开头的 ref 只能被当作 refspec，不能注入 git 选项（复用已克隆的缓存，直接走 fetch）
+	marker := filepath.Join(t.TempDir(), "injected")
+	evil, err := newGitSource("git+file://" + repo + "#--upload-pack=touch " + marker + ":config.yaml")
+	if err != nil {
+		t.Fatalf("创建 git 来源失败: %v", err)
+	}
+	evil.cacheDir = src.cacheDir
+	if _, err := evil.Fetch(context.Background(), ""); err == nil {
+		t.Fatalf("期望无效 ref 拉取失败")
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Fatalf("ref 被解析为 git 选项")
+	}
+}
+
+func TestRemoteVerifierEd25519(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(remoteTestConfig)))
+	srv := newRemoteTestServer(t, map[string]string{
+		"/config.yaml":     remoteTestConfig,
+		"/config.yaml.sig": sig,
+	})
+	src, _ := NewRemoteSource(srv.URL + "/config.yaml")
+	verifier := &RemoteVerifier{mode: "ed25519", publicKey: pub}
+
+	if err := verifier.Verify(context.Background(), src, []byte(remoteTestConfig)); err != nil {
+		t.Fatalf("签名校验应通过: %v", err)
+	}
+	if err := verifier.Verify(context.Background(), src, []byte(remoteTestConfig+"#")); err == nil {
+		t.Fatalf("内容被篡改时签名校验应失败")
+	}
+}
+
+func TestNewRemoteSourceSchemes(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewRemoteSource("ftp://example.com/config.yaml"); err == nil {
+		t.Fatalf("期望不支持的 scheme 报错")
+	}
+	if _, err := NewRemoteSource("s3://bucket"); err == nil {
+		t.Fatalf("期望缺少 key 的 S3 URL 报错")
+	}
+	src, err := NewRemoteSource("s3://bucket/path/config.yaml")
+	if err != nil {
+		t.Fatalf("解析 S3 URL 失败: %v", err)
+	}
+	if got := src.String(); got != "s3://bucket/path/config.yaml" {
+		t.Fatalf("S3 来源描述错误: %s", got)
+	}
+}
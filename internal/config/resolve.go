@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// normalizeResolve 校验并规范化 resolve（主机名小写、IP 规范化）与 dns_server（缺省端口补 53）
+// 两者都作用于直连拨号，经代理时由代理服务器解析域名，因此不允许与 proxy 同时配置
+func (m *ServiceConfig) normalizeResolve() error {
+	if len(m.Resolve) > 0 {
+		resolve := make(map[string]string, len(m.Resolve))
+		for host, ip := range m.Resolve {
+			key := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "."))
+			if key == "" {
+				return fmt.Errorf("resolve: 主机名不能为空")
+			}
+			if strings.Contains(key, ":") || strings.Contains(key, "/") {
+				return fmt.Errorf("resolve: '%s' 应为主机名（不含端口和路径）", host)
+			}
+			if _, exists := resolve[key]; exists {
+				return fmt.Errorf("resolve: 主机名重复: %s", key)
+			}
+			addr := net.ParseIP(strings.TrimSpace(ip))
+			if addr == nil {
+				return fmt.Errorf("resolve: '%s' 的目标 '%s' 不是有效的 IP 地址", host, ip)
+			}
+			if (m.IPFamily == IPFamilyIPv4 && addr.To4() == nil) || (m.IPFamily == IPFamilyIPv6 && addr.To4() != nil) {
+				return fmt.Errorf("resolve: '%s' 的目标 %s 与 ip_family=%s 不一致", host, addr, m.IPFamily)
+			}
+			resolve[key] = addr.String()
+		}
+		m.Resolve = resolve
+	}
+
+	m.DNSServer = strings.TrimSpace(m.DNSServer)
+	if m.DNSServer != "" {
+		host, port, err := net.SplitHostPort(m.DNSServer)
+		if err != nil {
+			// 未指定端口（含不带方括号的 IPv6 地址）
+			host, port = strings.Trim(m.DNSServer, "[]"), "53"
+		}
+		if net.ParseIP(host) == nil {
+			return fmt.Errorf("dns_server '%s' 无效：需为 IP 地址（可带端口，如 8.8.8.8:53）", m.DNSServer)
+		}
+		m.DNSServer = net.JoinHostPort(host, port)
+	}
+
+	if m.Proxy != "" && (len(m.Resolve) > 0 || m.DNSServer != "") {
+		return fmt.Errorf("resolve / dns_server 不能与 proxy 同时配置（经代理时由代理服务器解析域名）")
+	}
+	return nil
+}
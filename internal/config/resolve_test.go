@@ -0,0 +1,136 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeResolve(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		resolve   map[string]string
+		dnsServer string
+		family    string
+		proxy     string
+		want      map[string]string
+		wantDNS   string
+		wantErr   bool
+	}{
+		{"未配置", nil, "", "", "", nil, "", false},
+		{"主机名规范化", map[string]string{" API.Example.com. ": " 203.0.113.10 "}, "", "", "", map[string]string{"api.example.com": "203.0.113.10"}, "", false},
+		{"IPv6 目标", map[string]string{"api.example.com": "2001:DB8::1"}, "", IPFamilyIPv6, "", map[string]string{"api.example.com": "2001:db8::1"}, "", false},
+		{"DNS 补默认端口", nil, "8.8.8.8", "", "", nil, "8.8.8.8:53", false},
+		{"DNS 带端口", nil, "8.8.8.8:5353", "", "", nil, "8.8.8.8:5353", false},
+		{"IPv6 DNS", nil, "2001:4860:4860::8888", "", "", nil, "[2001:4860:4860::8888]:53", false},
+		{"目标不是 IP", map[string]string{"api.example.com": "backup.example.com"}, "", "", "", nil, "", true},
+		{"主机名带端口", map[string]string{"api.example.com:443": "203.0.113.10"}, "", "", "", nil, "", true},
+		{"主机名重复", map[string]string{"api.example.com": "203.0.113.10", "API.example.com": "203.0.113.11"}, "", "", "", nil, "", true},
+		{"与地址族不一致", map[string]string{"api.example.com": "203.0.113.10"}, "", IPFamilyIPv6, "", nil, "", true},
+		{"DNS 不是 IP", nil, "dns.google", "", "", nil, "", true},
+		{"不能使用代理", map[string]string{"api.example.com": "203.0.113.10"}, "", "", "http://127.0.0.1:8080", nil, "", true},
+		{"DNS 不能使用代理", nil, "8.8.8.8", "", "http://127.0.0.1:8080", nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &ServiceConfig{Resolve: tt.resolve, DNSServer: tt.dnsServer, IPFamily: tt.family, Proxy: tt.proxy}
+			err := m.normalizeResolve()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("期望报错")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("不期望报错: %v", err)
+			}
+			if len(m.Resolve) != len(tt.want) {
+				t.Fatalf("resolve = %v, want %v", m.Resolve, tt.want)
+			}
+			for host, ip := range tt.want {
+				if m.Resolve[host] != ip {
+					t.Fatalf("resolve[%s] = %q, want %q", host, m.Resolve[host], ip)
+				}
+			}
+			if m.DNSServer != tt.wantDNS {
+				t.Fatalf("dns_server = %q, want %q", m.DNSServer, tt.wantDNS)
+			}
+		})
+	}
+}
+
+const resolveConfig = `
+interval: "1m"
+monitors:
+  - provider: "Alpha"
+    service: "cc"
+    channel: "hk"
+    category: "public"
+    sponsor: "alpha"
+    url: "https://api.alpha.example.com"
+    method: "POST"
+    model: "haiku"
+    body: '{"model":"{{MODEL}}"}'
+    resolve:
+      api.alpha.example.com: "203.0.113.10"
+      auth.alpha.example.com: "203.0.113.20"
+    dns_server: "1.1.1.1"
+  - provider: "Alpha"
+    service: "cc"
+    channel: "hk"
+    model: "opus"
+    parent: "Alpha/cc/hk"
+    resolve:
+      api.alpha.example.com: "203.0.113.11"
+`
+
+func TestLoaderInheritsResolve(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, configPath, resolveConfig)
+
+	cfg, err := NewLoader().Load(configPath)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	child := cfg.Monitors[1]
+	if got := child.Resolve["api.alpha.example.com"]; got != "203.0.113.11" {
+		t.Errorf("子通道应覆盖父通道的 resolve, got %q", got)
+	}
+	if got := child.Resolve["auth.alpha.example.com"]; got != "203.0.113.20" {
+		t.Errorf("子通道应继承父通道的 resolve, got %q", got)
+	}
+	if child.DNSServer != "1.1.1.1:53" {
+		t.Errorf("dns_server = %q, want 1.1.1.1:53", child.DNSServer)
+	}
+	if got := cfg.Monitors[0].Resolve["api.alpha.example.com"]; got != "203.0.113.10" {
+		t.Errorf("合并不应修改父通道的 resolve, got %q", got)
+	}
+
+	clone := cfg.Clone()
+	clone.Monitors[1].Resolve["api.alpha.example.com"] = "203.0.113.99"
+	if cfg.Monitors[1].Resolve["api.alpha.example.com"] != "203.0.113.11" {
+		t.Errorf("Clone 应深拷贝 resolve")
+	}
+}
+
+func TestLoaderRejectsResolveWithProxy(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, configPath, strings.Replace(resolveConfig, `    parent: "Alpha/cc/hk"
+`, `    parent: "Alpha/cc/hk"
+    proxy: "socks5://127.0.0.1:1080"
+`, 1))
+
+	_, err := NewLoader().Load(configPath)
+	if err == nil || !strings.Contains(err.Error(), "resolve") {
+		t.Fatalf("期望 resolve 与 proxy 冲突报错, got %v", err)
+	}
+}
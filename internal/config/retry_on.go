@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RetryClass 可重试的失败类别（位掩码），由 retry_on 配置解析得到
+type RetryClass uint8
+
+const (
+	RetryOnNetworkError RetryClass = 1 << iota // 连接失败、连接被重置等网络错误（不含超时）
+	RetryOn5xx                                 // HTTP 5xx
+	RetryOnRateLimit                           // HTTP 429（或状态映射规则判定为 rate_limit）
+	RetryOnTimeout                             // 请求超时（启用后每次尝试独立计时）
+)
+
+// DefaultRetryOn 未配置 retry_on 时的可重试类别：网络错误、5xx、限流
+// 认证失败、参数错误等 4xx 与内容校验失败重试也不会恢复，默认不重试
+const DefaultRetryOn = RetryOnNetworkError | RetryOn5xx | RetryOnRateLimit
+
+// retryClassNames retry_on 可选值
+var retryClassNames = map[string]RetryClass{
+	"network_error": RetryOnNetworkError,
+	"5xx":           RetryOn5xx,
+	"rate_limit":    RetryOnRateLimit,
+	"timeout":       RetryOnTimeout,
+}
+
+// Has 判断是否包含指定类别
+func (c RetryClass) Has(class RetryClass) bool {
+	return c&class != 0
+}
+
+// parseRetryOn 解析 retry_on 列表（大小写不敏感）；空列表表示任何失败都不重试
+func parseRetryOn(values []string, field string) (RetryClass, error) {
+	var classes RetryClass
+	for _, v := range values {
+		class, ok := retryClassNames[strings.ToLower(strings.TrimSpace(v))]
+		if !ok {
+			return 0, fmt.Errorf("%s 包含未知类别: %q（可选 network_error、5xx、rate_limit、timeout）", field, v)
+		}
+		classes |= class
+	}
+	return classes, nil
+}
@@ -0,0 +1,66 @@
+package config
+
+import "testing"
+
+func TestRetryOnResolution(t *testing.T) {
+	base := func(provider, model, parent string, retryOn []string) ServiceConfig {
+		m := ServiceConfig{Provider: provider, Service: "cc", Channel: "vip", Model: model, Parent: parent, Category: "public", RetryOn: retryOn}
+		if parent == "" {
+			m.URL, m.Method = "https://example.com", "POST"
+		}
+		return m
+	}
+	cfg := &AppConfig{
+		RetryOnByService: map[string][]string{"CC": {"5xx", "Timeout"}},
+		Monitors: []ServiceConfig{
+			base("a", "", "", nil),
+			base("b", "", "", []string{}),
+			base("c", "base", "", []string{"rate_limit"}),
+			base("c", "child", "c/cc/vip", nil),
+		},
+	}
+	cfg.Monitors = append(cfg.Monitors, ServiceConfig{Provider: "d", Service: "cx", Category: "public", URL: "https://example.com", Method: "POST"})
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("Normalize() failed: %v", err)
+	}
+	if cfg.RetryOnClasses != DefaultRetryOn {
+		t.Errorf("global default = %b, want %b", cfg.RetryOnClasses, DefaultRetryOn)
+	}
+
+	want := map[string]RetryClass{
+		"a":       RetryOn5xx | RetryOnTimeout, // retry_on_by_service
+		"b":       0,                           // 显式空列表：不重试
+		"c/base":  RetryOnRateLimit,
+		"c/child": RetryOnRateLimit, // 从父通道继承
+		"d":       DefaultRetryOn,
+	}
+	for _, m := range cfg.Monitors {
+		key := m.Provider
+		if m.Model != "" {
+			key += "/" + m.Model
+		}
+		if m.RetryOnClasses != want[key] {
+			t.Errorf("%s: RetryOnClasses = %b, want %b", key, m.RetryOnClasses, want[key])
+		}
+	}
+
+	clone := cfg.Clone()
+	clone.RetryOnByService["CC"][0] = "timeout"
+	if cfg.RetryOnByService["CC"][0] != "5xx" {
+		t.Error("clone shares retry_on_by_service slices")
+	}
+}
+
+func TestParseRetryOnRejectsUnknownClass(t *testing.T) {
+	if _, err := parseRetryOn([]string{"network_error", "4xx"}, "retry_on"); err == nil {
+		t.Fatal("expected error for unknown class")
+	}
+	classes, err := parseRetryOn([]string{" NETWORK_ERROR ", "5xx"}, "retry_on")
+	if err != nil || classes != RetryOnNetworkError|RetryOn5xx {
+		t.Fatalf("classes = %b, err = %v", classes, err)
+	}
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jsonSchemaDialect 生成的 JSON Schema 版本
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// goDurationPattern Go duration 字符串（time.ParseDuration 可解析的格式，允许留空使用默认值）
+const goDurationPattern = `^([-+]?(((\d+(\.\d*)?|\.\d+)(ns|us|µs|μs|ms|s|m|h))+|0))?$`
+
+var (
+	durationType    = reflect.TypeOf(time.Duration(0))
+	durationMapType = reflect.TypeOf(map[string]time.Duration(nil))
+)
+
+// schemaEnums 具名字符串类型的取值范围
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeOf(SponsorLevel("")): {string(SponsorLevelNone), string(SponsorLevelBasic), string(SponsorLevelAdvanced), string(SponsorLevelEnterprise)},
+	reflect.TypeOf(BadgeKind("")):    {string(BadgeKindSource), string(BadgeKindInfo), string(BadgeKindFeature)},
+	reflect.TypeOf(BadgeVariant("")): {string(BadgeVariantDefault), string(BadgeVariantSuccess), string(BadgeVariantWarning), string(BadgeVariantDanger), string(BadgeVariantInfo)},
+}
+
+// schemaOverrides 自定义 UnmarshalYAML 的类型（反射无法得出实际接受的 YAML 形态）
+var schemaOverrides = map[reflect.Type]func() map[string]any{
+	// BadgeRef 支持字符串或 { id, tooltip_override } 对象
+	reflect.TypeOf(BadgeRef{}): func() map[string]any {
+		return map[string]any{
+			"oneOf": []any{
+				map[string]any{"type": "string"},
+				map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"id":               map[string]any{"type": "string"},
+						"tooltip_override": map[string]any{"type": "string"},
+					},
+					"required":             []string{"id"},
+					"additionalProperties": false,
+				},
+			},
+		}
+	},
+}
+
+// configSchemaJSON 主配置文件的 JSON Schema（结构体定义在运行期不变，只生成一次）
+var configSchemaJSON = sync.OnceValues(func() ([]byte, error) {
+	return json.MarshalIndent(ConfigSchema(), "", "  ")
+})
+
+// ConfigSchemaJSON 返回主配置文件（config.yaml）JSON Schema 的 JSON 编码
+func ConfigSchemaJSON() ([]byte, error) {
+	return configSchemaJSON()
+}
+
+// ConfigSchema 由 AppConfig 结构体反射生成主配置文件（config.yaml）的 JSON Schema
+//
+// 字段名取 yaml 标签，yaml:"-" 的内部字段不输出；嵌套结构体放入 $defs 复用。
+// 存在对应 XxxDuration 解析字段的字符串按 Go duration 格式校验。
+// 顶层允许 x- 前缀的扩展键（便于放置 YAML 锚点），其余未知字段视为拼写错误
+func ConfigSchema() map[string]any {
+	b := &schemaBuilder{defs: make(map[string]any)}
+	root := b.structSchema(reflect.TypeOf(AppConfig{}))
+	root["$schema"] = jsonSchemaDialect
+	root["title"] = "RelayPulse 配置文件 (config.yaml)"
+	root["patternProperties"] = map[string]any{"^x-": map[string]any{}}
+	root["$defs"] = b.defs
+	return root
+}
+
+// schemaBuilder 递归生成 JSON Schema，具名结构体只生成一次并以 $ref 引用
+type schemaBuilder struct {
+	defs map[string]any
+}
+
+// typeSchema 生成单个 Go 类型的 schema
+func (b *schemaBuilder) typeSchema(t reflect.Type) map[string]any {
+	if override, ok := schemaOverrides[t]; ok {
+		return override()
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return b.typeSchema(t.Elem())
+	case reflect.String:
+		s := map[string]any{"type": "string"}
+		if enum, ok := schemaEnums[t]; ok {
+			s["enum"] = enum
+		}
+		return s
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": b.typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		if _, ok := b.defs[t.Name()]; !ok {
+			// 先占位再生成，避免自引用结构体无限递归
+			b.defs[t.Name()] = map[string]any{}
+			b.defs[t.Name()] = b.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	default:
+		// interface 等任意类型
+		return map[string]any{}
+	}
+}
+
+// structSchema 生成结构体的 object schema
+func (b *schemaBuilder) structSchema(t reflect.Type) map[string]any {
+	props := make(map[string]any)
+	b.collectFields(t, props)
+	return map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+}
+
+// collectFields 按 yaml 标签收集结构体字段（展开 ,inline 嵌入字段）
+func (b *schemaBuilder) collectFields(t reflect.Type, props map[string]any) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(opts, "inline") {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				b.collectFields(ft, props)
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+
+		s := b.typeSchema(f.Type)
+		// 约定：Xxx 字符串由同结构体的 XxxDuration 字段保存解析结果
+		if parsed, ok := t.FieldByName(f.Name + "Duration"); ok {
+			switch {
+			case parsed.Type == durationType && f.Type.Kind() == reflect.String:
+				s["pattern"] = goDurationPattern
+			case parsed.Type == durationMapType && f.Type.Kind() == reflect.Map && f.Type.Elem().Kind() == reflect.String:
+				s["additionalProperties"] = map[string]any{"type": "string", "pattern": goDurationPattern}
+			}
+		}
+		props[name] = s
+	}
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// checkAgainstSchema 按 schema 的 properties / additionalProperties / items / pattern / enum 检查 YAML 值
+// 仅覆盖 ConfigSchema 用到的关键字，用于发现示例配置中 schema 不认识的字段
+func checkAgainstSchema(defs map[string]any, schema map[string]any, value any, path string) []string {
+	if len(schema) == 0 {
+		return nil // 空 schema 接受任意值
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		schema = defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+	}
+	if alts, ok := schema["oneOf"].([]any); ok {
+		for _, alt := range alts {
+			if len(checkAgainstSchema(defs, alt.(map[string]any), value, path)) == 0 {
+				return nil
+			}
+		}
+		return []string{path + ": 不匹配任何候选 schema"}
+	}
+
+	var errs []string
+	switch v := value.(type) {
+	case map[string]any:
+		if schema["type"] != "object" {
+			return []string{fmt.Sprintf("%s: 期望 %v，实际为对象", path, schema["type"])}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		patterns, _ := schema["patternProperties"].(map[string]any)
+		for key, child := range v {
+			var sub map[string]any
+			if p, ok := props[key]; ok {
+				sub = p.(map[string]any)
+			} else if extra, ok := schema["additionalProperties"].(map[string]any); ok {
+				sub = extra
+			} else {
+				for pattern, p := range patterns {
+					if regexp.MustCompile(pattern).MatchString(key) {
+						sub = p.(map[string]any)
+					}
+				}
+			}
+			if sub == nil {
+				errs = append(errs, path+"."+key+": 未知字段")
+				continue
+			}
+			errs = append(errs, checkAgainstSchema(defs, sub, child, path+"."+key)...)
+		}
+	case []any:
+		if schema["type"] != "array" {
+			return []string{fmt.Sprintf("%s: 期望 %v，实际为数组", path, schema["type"])}
+		}
+		for i, item := range v {
+			errs = append(errs, checkAgainstSchema(defs, schema["items"].(map[string]any), item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case string:
+		if schema["type"] != nil && schema["type"] != "string" {
+			return []string{fmt.Sprintf("%s: 期望 %v，实际为字符串 %q", path, schema["type"], v)}
+		}
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(v) {
+			errs = append(errs, fmt.Sprintf("%s: %q 不匹配 %s", path, v, pattern))
+		}
+	case bool:
+		if schema["type"] != nil && schema["type"] != "boolean" {
+			return []string{fmt.Sprintf("%s: 期望 %v，实际为布尔值", path, schema["type"])}
+		}
+	case int:
+		if schema["type"] != nil && schema["type"] != "integer" && schema["type"] != "number" {
+			return []string{fmt.Sprintf("%s: 期望 %v，实际为整数", path, schema["type"])}
+		}
+	case float64:
+		if schema["type"] != nil && schema["type"] != "number" {
+			return []string{fmt.Sprintf("%s: 期望 %v，实际为小数", path, schema["type"])}
+		}
+	}
+	return errs
+}
+
+// loadSchema 以 JSON 往返得到与 /api/config/schema 输出一致的通用结构
+func loadSchema(t *testing.T) map[string]any {
+	t.Helper()
+	data, err := ConfigSchemaJSON()
+	if err != nil {
+		t.Fatalf("ConfigSchemaJSON 失败: %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("schema 不是合法 JSON: %v", err)
+	}
+	return schema
+}
+
+func TestConfigSchemaStructure(t *testing.T) {
+	schema := loadSchema(t)
+	if schema["$schema"] != jsonSchemaDialect || schema["additionalProperties"] != false {
+		t.Fatalf("根节点缺少 $schema 或未禁止未知字段: %v %v", schema["$schema"], schema["additionalProperties"])
+	}
+
+	props := schema["properties"].(map[string]any)
+	for _, key := range []string{"interval", "monitors", "storage", "events", "badge_definitions", "badge_providers"} {
+		if _, ok := props[key]; !ok {
+			t.Errorf("缺少顶层字段 %s", key)
+		}
+	}
+	// yaml:"-" 的内部字段不应出现
+	for _, key := range []string{"intervalduration", "IntervalDuration"} {
+		if _, ok := props[key]; ok {
+			t.Errorf("内部字段 %s 不应出现在 schema 中", key)
+		}
+	}
+	if props["interval"].(map[string]any)["pattern"] != goDurationPattern {
+		t.Error("interval 应按 Go duration 格式校验")
+	}
+
+	defs := schema["$defs"].(map[string]any)
+	monitor := defs["ServiceConfig"].(map[string]any)["properties"].(map[string]any)
+	for _, key := range []string{"provider", "service", "parent", "model", "headers", "badges", "resolve", "ip_family"} {
+		if _, ok := monitor[key]; !ok {
+			t.Errorf("ServiceConfig 缺少字段 %s", key)
+		}
+	}
+	level := monitor["sponsor_level"].(map[string]any)
+	if enum, _ := level["enum"].([]any); len(enum) != 4 {
+		t.Errorf("sponsor_level 应为枚举: %v", level)
+	}
+	if _, ok := monitor["badges"].(map[string]any)["items"].(map[string]any)["oneOf"]; !ok {
+		t.Error("badges 元素应支持字符串或对象")
+	}
+}
+
+func TestConfigSchemaAcceptsExamples(t *testing.T) {
+	schema := loadSchema(t)
+	defs := schema["$defs"].(map[string]any)
+
+	for _, path := range []string{"../../config.yaml.example", "../../config.production.yaml.example"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("读取 %s 失败: %v", path, err)
+		}
+		var doc map[string]any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			t.Fatalf("解析 %s 失败: %v", path, err)
+		}
+		for _, e := range checkAgainstSchema(defs, schema, doc, "$") {
+			t.Errorf("%s: %s", path, e)
+		}
+	}
+}
+
+func TestConfigSchemaRejectsTypos(t *testing.T) {
+	schema := loadSchema(t)
+	defs := schema["$defs"].(map[string]any)
+
+	var doc map[string]any
+	if err := yaml.Unmarshal([]byte(`
+interval: "1 minute"
+x-defaults: &defaults
+  category: commercial
+monitors:
+  - provider: demo
+    servce: cc
+    badges: [api_key_user, {id: api_key_official, tooltip_override: hi}]
+`), &doc); err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	errs := strings.Join(checkAgainstSchema(defs, schema, doc, "$"), "\n")
+	for _, want := range []string{"$.interval", "$.monitors[0].servce: 未知字段"} {
+		if !strings.Contains(errs, want) {
+			t.Errorf("期望报告 %s，实际:\n%s", want, errs)
+		}
+	}
+	if strings.Contains(errs, "x-defaults") || strings.Contains(errs, "badges") {
+		t.Errorf("x- 扩展键与两种 badges 写法应通过校验:\n%s", errs)
+	}
+}
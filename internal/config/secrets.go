@@ -0,0 +1,235 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// 密钥引用前缀
+//   - file:/run/secrets/x        读取文件内容（去除首尾空白）
+//   - vault:secret/data/relay#key  读取 HashiCorp Vault（VAULT_ADDR + VAULT_TOKEN，兼容 KV v1/v2）
+//   - aws-sm:name[#key]          读取 AWS Secrets Manager（可选 #key 提取 JSON 字段）
+const (
+	secretPrefixFile  = "file:"
+	secretPrefixVault = "vault:"
+	secretPrefixAWSSM = "aws-sm:"
+
+	secretResolveTimeout = 10 * time.Second
+)
+
+// SecretResolver 按前缀解析密钥引用
+type SecretResolver interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// secretResolvers 前缀 -> 解析器（包级注册表，测试中可替换）
+var secretResolvers = map[string]SecretResolver{
+	secretPrefixFile:  fileSecretResolver{},
+	secretPrefixVault: &vaultSecretResolver{client: &http.Client{Timeout: secretResolveTimeout}},
+	secretPrefixAWSSM: &awsSecretResolver{client: &http.Client{Timeout: secretResolveTimeout}},
+}
+
+// splitSecretRef 拆分前缀与引用内容
+func splitSecretRef(value string) (prefix, ref string, ok bool) {
+	value = strings.TrimSpace(value)
+	for p := range secretResolvers {
+		if strings.HasPrefix(value, p) {
+			return p, strings.TrimPrefix(value, p), true
+		}
+	}
+	return "", "", false
+}
+
+// ResolveSecrets 解析所有监测项 api_key 中的密钥引用
+// 在 ApplyEnvOverrides 之后执行，因此环境变量中的值同样支持引用语法；
+// 同一次加载中相同引用只解析一次
+func (c *AppConfig) ResolveSecrets() error {
+	ctx, cancel := context.WithTimeout(context.Background(), secretResolveTimeout*2)
+	defer cancel()
+
+	cache := make(map[string]string)
+	for i := range c.Monitors {
+		m := &c.Monitors[i]
+		prefix, ref, ok := splitSecretRef(m.APIKey)
+		if !ok {
+			continue
+		}
+
+		key := prefix + ref
+		if v, cached := cache[key]; cached {
+			m.APIKey = v
+			continue
+		}
+
+		v, err := secretResolvers[prefix].Resolve(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("monitor %s/%s/%s: 解析 api_key 密钥引用 %s 失败: %w",
+				m.Provider, m.Service, m.Channel, prefix, err)
+		}
+		cache[key] = v
+		m.APIKey = v
+	}
+	return nil
+}
+
+// fileSecretResolver 从文件读取密钥（Docker/K8s secrets 挂载）
+type fileSecretResolver struct{}
+
+func (fileSecretResolver) Resolve(_ context.Context, ref string) (string, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	v := strings.TrimSpace(string(data))
+	if v == "" {
+		return "", fmt.Errorf("密钥文件为空")
+	}
+	return v, nil
+}
+
+// vaultSecretResolver 从 HashiCorp Vault 读取密钥
+// 引用格式：<path>#<key>，path 为完整 API 路径（KV v2 需包含 data/，如 secret/data/relay）
+type vaultSecretResolver struct {
+	client *http.Client
+}
+
+func (r *vaultSecretResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || field == "" {
+		return "", fmt.Errorf("vault 引用格式应为 vault:<path>#<key>")
+	}
+	addr := strings.TrimRight(strings.TrimSpace(os.Getenv("VAULT_ADDR")), "/")
+	token := strings.TrimSpace(os.Getenv("VAULT_TOKEN"))
+	if addr == "" || token == "" {
+		return "", fmt.Errorf("需要配置 VAULT_ADDR 和 VAULT_TOKEN")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := strings.TrimSpace(os.Getenv("VAULT_NAMESPACE")); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := readRemoteBody(resp)
+	if err != nil {
+		return "", err
+	}
+
+	var payload struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", fmt.Errorf("解析 vault 响应失败: %w", err)
+	}
+
+	// KV v2: data.data.<key>；KV v1: data.<key>
+	fields := payload.Data
+	if nested, ok := payload.Data["data"]; ok {
+		var inner map[string]json.RawMessage
+		if err := json.Unmarshal(nested, &inner); err == nil {
+			fields = inner
+		}
+	}
+	return extractSecretField(fields, field)
+}
+
+// awsSecretResolver 从 AWS Secrets Manager 读取密钥
+// 引用格式：<secret-id>[#<json-key>]
+type awsSecretResolver struct {
+	client *http.Client
+}
+
+func (r *awsSecretResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	secretID, field, _ := strings.Cut(ref, "#")
+	if secretID == "" {
+		return "", fmt.Errorf("aws-sm 引用格式应为 aws-sm:<secret-id>[#<key>]")
+	}
+	creds := awsCredentialsFromEnv()
+	if !creds.valid() {
+		return "", fmt.Errorf("需要配置 AWS_ACCESS_KEY_ID 和 AWS_SECRET_ACCESS_KEY")
+	}
+	region := awsRegionFromEnv()
+
+	reqBody, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return "", err
+	}
+	endpoint := fmt.Sprintf("https://secretsmanager.%s.amazonaws.com/", region)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	bodyHash := sha256.Sum256(reqBody)
+	signAWSV4(req, hex.EncodeToString(bodyHash[:]), "secretsmanager", region, creds, time.Now())
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	body, err := readRemoteBody(resp)
+	if err != nil {
+		return "", err
+	}
+
+	var payload struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", fmt.Errorf("解析 Secrets Manager 响应失败: %w", err)
+	}
+	if field == "" {
+		if payload.SecretString == "" {
+			return "", fmt.Errorf("密钥为空（不支持二进制密钥）")
+		}
+		return payload.SecretString, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(payload.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("密钥不是 JSON 对象，无法提取字段 %s", field)
+	}
+	return extractSecretField(fields, field)
+}
+
+// extractSecretField 从 JSON 对象中提取字符串字段
+func extractSecretField(fields map[string]json.RawMessage, field string) (string, error) {
+	raw, ok := fields[field]
+	if !ok {
+		return "", fmt.Errorf("字段 %s 不存在", field)
+	}
+	var v string
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "", fmt.Errorf("字段 %s 不是字符串", field)
+	}
+	if v == "" {
+		return "", fmt.Errorf("字段 %s 为空", field)
+	}
+	return v, nil
+}
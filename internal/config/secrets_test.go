@@ -0,0 +1,95 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveSecretsFile(t *testing.T) {
+	t.Parallel()
+
+	secretPath := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(secretPath, []byte("sk-file\n"), 0o600); err != nil {
+		t.Fatalf("写入密钥文件失败: %v", err)
+	}
+
+	cfg := AppConfig{
+		Monitors: []ServiceConfig{
+			{Provider: "a", Service: "cc", APIKey: "file:" + secretPath},
+			{Provider: "b", Service: "cc", APIKey: "sk-plain"},
+		},
+	}
+	if err := cfg.ResolveSecrets(); err != nil {
+		t.Fatalf("解析密钥失败: %v", err)
+	}
+	if cfg.Monitors[0].APIKey != "sk-file" {
+		t.Fatalf("file 引用解析错误: %q", cfg.Monitors[0].APIKey)
+	}
+	if cfg.Monitors[1].APIKey != "sk-plain" {
+		t.Fatalf("普通 api_key 不应被修改: %q", cfg.Monitors[1].APIKey)
+	}
+}
+
+func TestResolveSecretsFileMissing(t *testing.T) {
+	t.Parallel()
+
+	cfg := AppConfig{
+		Monitors: []ServiceConfig{
+			{Provider: "a", Service: "cc", APIKey: "file:" + filepath.Join(t.TempDir(), "missing")},
+		},
+	}
+	if err := cfg.ResolveSecrets(); err == nil {
+		t.Fatalf("期望密钥文件不存在时报错")
+	}
+}
+
+func TestResolveSecretsVault(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/relay":
+			_, _ = w.Write([]byte(`{"data":{"data":{"cc":"sk-v2"},"metadata":{}}}`))
+		case "/v1/kv/relay":
+			_, _ = w.Write([]byte(`{"data":{"cc":"sk-v1"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("VAULT_ADDR", srv.URL)
+	t.Setenv("VAULT_TOKEN", "root")
+
+	cfg := AppConfig{
+		Monitors: []ServiceConfig{
+			{Provider: "a", Service: "cc", APIKey: "vault:secret/data/relay#cc"},
+			{Provider: "b", Service: "cc", APIKey: "vault:secret/data/relay#cc"},
+			{Provider: "c", Service: "cc", APIKey: "vault:kv/relay#cc"},
+		},
+	}
+	if err := cfg.ResolveSecrets(); err != nil {
+		t.Fatalf("解析 vault 密钥失败: %v", err)
+	}
+	if cfg.Monitors[0].APIKey != "sk-v2" || cfg.Monitors[1].APIKey != "sk-v2" {
+		t.Fatalf("KV v2 解析错误: %q %q", cfg.Monitors[0].APIKey, cfg.Monitors[1].APIKey)
+	}
+	if cfg.Monitors[2].APIKey != "sk-v1" {
+		t.Fatalf("KV v1 解析错误: %q", cfg.Monitors[2].APIKey)
+	}
+	if requests != 2 {
+		t.Fatalf("相同引用应只请求一次, requests=%d", requests)
+	}
+
+	cfg.Monitors = []ServiceConfig{{Provider: "a", Service: "cc", APIKey: "vault:secret/data/relay#missing"}}
+	if err := cfg.ResolveSecrets(); err == nil {
+		t.Fatalf("期望字段不存在时报错")
+	}
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 默认监听端口
+const defaultServerPort = 8080
+
+// 默认 Unix socket 文件权限
+const defaultUnixSocketMode = "0660"
+
+// ServerConfig HTTP 服务监听配置
+// 无反向代理的部署可直接监听指定地址并提供 HTTPS；位于同机反向代理之后时可改用 Unix socket
+//
+// 监听参数仅在启动时生效，热更新时修改需重启；证书文件内容可通过 SIGHUP 重新加载
+type ServerConfig struct {
+	// 绑定地址（默认空，监听所有网卡；如 "127.0.0.1"、"::1"）
+	Address string `yaml:"address" json:"-"`
+
+	// 监听端口（默认 8080）
+	Port int `yaml:"port" json:"-"`
+
+	// Unix domain socket 路径（设置后改为监听该 socket，忽略 address/port）
+	UnixSocket string `yaml:"unix_socket" json:"-"`
+
+	// Unix socket 文件权限（八进制字符串，默认 "0660"）
+	UnixSocketMode string `yaml:"unix_socket_mode" json:"-"`
+
+	// HTTPS 证书配置（cert_file 与 key_file 同时配置时启用）
+	TLS ServerTLSConfig `yaml:"tls" json:"-"`
+
+	// 解析后的 Unix socket 权限（内部使用）
+	UnixSocketModeValue os.FileMode `yaml:"-" json:"-"`
+}
+
+// ServerTLSConfig HTTP 服务端证书配置
+type ServerTLSConfig struct {
+	// 证书链与私钥文件（PEM）
+	CertFile string `yaml:"cert_file" json:"-"`
+	KeyFile  string `yaml:"key_file" json:"-"`
+
+	// 最低 TLS 版本："1.2"（默认）或 "1.3"
+	MinVersion string `yaml:"min_version" json:"-"`
+
+	// 解析后的最低 TLS 版本（内部使用）
+	MinVersionValue uint16 `yaml:"-" json:"-"`
+}
+
+// Enabled 是否启用 HTTPS
+func (t *ServerTLSConfig) Enabled() bool {
+	return t.CertFile != "" && t.KeyFile != ""
+}
+
+// ListenAddr TCP 监听地址（host:port）
+func (s *ServerConfig) ListenAddr() string {
+	return net.JoinHostPort(s.Address, strconv.Itoa(s.Port))
+}
+
+// Normalize 规范化服务监听配置（启用 HTTPS 时会实际加载证书，确保启动前即可发现问题）
+func (s *ServerConfig) Normalize() error {
+	s.Address = strings.TrimSpace(s.Address)
+	s.UnixSocket = strings.TrimSpace(s.UnixSocket)
+
+	if s.Port == 0 {
+		s.Port = defaultServerPort
+	}
+	if s.Port < 1 || s.Port > 65535 {
+		return fmt.Errorf("server.port 必须在 1-65535 之间，当前值: %d", s.Port)
+	}
+
+	mode := strings.TrimSpace(s.UnixSocketMode)
+	if mode == "" {
+		mode = defaultUnixSocketMode
+	}
+	parsed, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || parsed > 0o777 {
+		return fmt.Errorf("server.unix_socket_mode '%s' 无效，必须是八进制权限（如 0660）", s.UnixSocketMode)
+	}
+	s.UnixSocketMode = mode
+	s.UnixSocketModeValue = os.FileMode(parsed)
+
+	t := &s.TLS
+	t.CertFile = strings.TrimSpace(t.CertFile)
+	t.KeyFile = strings.TrimSpace(t.KeyFile)
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("server.tls.cert_file 与 server.tls.key_file 必须同时配置")
+	}
+
+	switch v := strings.TrimSpace(t.MinVersion); v {
+	case "", "1.2":
+		t.MinVersionValue = tls.VersionTLS12
+	case "1.3":
+		t.MinVersionValue = tls.VersionTLS13
+	default:
+		return fmt.Errorf("server.tls.min_version '%s' 无效，必须是 1.2/1.3 之一", t.MinVersion)
+	}
+
+	if t.Enabled() {
+		if _, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile); err != nil {
+			return fmt.Errorf("加载 server.tls 证书失败: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestServerConfigNormalize(t *testing.T) {
+	t.Parallel()
+
+	cfg := &ServerConfig{Address: " 127.0.0.1 "}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("规范化失败: %v", err)
+	}
+	if cfg.Port != defaultServerPort || cfg.ListenAddr() != "127.0.0.1:8080" {
+		t.Fatalf("默认端口错误: %+v", cfg)
+	}
+	if cfg.UnixSocketModeValue != 0o660 || cfg.TLS.MinVersionValue != tls.VersionTLS12 || cfg.TLS.Enabled() {
+		t.Fatalf("默认值错误: %+v", cfg)
+	}
+
+	ipv6 := &ServerConfig{Address: "::1", Port: 9443, UnixSocketMode: "600", TLS: ServerTLSConfig{MinVersion: "1.3"}}
+	if err := ipv6.Normalize(); err != nil {
+		t.Fatalf("规范化失败: %v", err)
+	}
+	if ipv6.ListenAddr() != "[::1]:9443" || ipv6.UnixSocketModeValue != 0o600 || ipv6.TLS.MinVersionValue != tls.VersionTLS13 {
+		t.Fatalf("解析结果错误: %+v", ipv6)
+	}
+
+	tests := []struct {
+		name string
+		cfg  ServerConfig
+	}{
+		{"端口越界", ServerConfig{Port: 70000}},
+		{"负数端口", ServerConfig{Port: -1}},
+		{"无效 socket 权限", ServerConfig{UnixSocketMode: "rw"}},
+		{"socket 权限越界", ServerConfig{UnixSocketMode: "1777"}},
+		{"证书与私钥不成对", ServerConfig{TLS: ServerTLSConfig{CertFile: "server.pem"}}},
+		{"无效版本", ServerConfig{TLS: ServerTLSConfig{MinVersion: "1.1"}}},
+		{"证书文件不存在", ServerConfig{TLS: ServerTLSConfig{CertFile: "/nonexistent/cert.pem", KeyFile: "/nonexistent/key.pem"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Normalize(); err == nil {
+				t.Fatalf("期望报错")
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SLA 统计周期
+const (
+	SLAPeriodMonthly = "monthly" // 自然月（UTC）
+	SLAPeriodWeekly  = "weekly"  // 自然周（UTC，周一开始）
+)
+
+// defaultSLAWarningThreshold 错误预算消耗达到该比例时发出预警
+const defaultSLAWarningThreshold = 0.8
+
+// SLAConfig 服务商可用性 SLA 目标配置
+//
+// 后台按 interval 统计每个目标服务商本周期的累计停机时长（按每日汇总的红/黄探测占比折算），
+// 与错误预算（周期时长 × (1 - target)）比较，通过 /api/sla 公开剩余预算；
+// 消耗达到 warning_threshold 时发出 SLA_BREACH_WARNING，耗尽时发出 SLA_BREACHED（每周期各一次）
+type SLAConfig struct {
+	// 评估间隔（默认 "5m"）
+	Interval string `yaml:"interval" json:"interval"`
+
+	IntervalDuration time.Duration `yaml:"-" json:"-"`
+
+	// 错误预算消耗预警比例（0-1，默认 0.8）
+	WarningThreshold float64 `yaml:"warning_threshold" json:"warning_threshold"`
+
+	// 服务商 SLA 目标（为空时不评估）
+	Targets []SLATarget `yaml:"targets" json:"targets,omitempty"`
+}
+
+// SLATarget 单个服务商的 SLA 目标
+type SLATarget struct {
+	Provider string  `yaml:"provider" json:"provider"` // provider 名称，匹配时忽略大小写和首尾空格
+	Target   float64 `yaml:"target" json:"target"`     // 可用率目标（百分比，如 99.5）
+	Period   string  `yaml:"period" json:"period"`     // 统计周期：monthly（默认）/ weekly
+}
+
+// Enabled 是否配置了 SLA 目标
+func (s SLAConfig) Enabled() bool {
+	return len(s.Targets) > 0
+}
+
+// Normalize 规范化 SLA 配置（填充默认值并校验）
+func (s *SLAConfig) Normalize() error {
+	if s.Interval == "" {
+		s.Interval = "5m"
+	}
+	interval, err := time.ParseDuration(s.Interval)
+	if err != nil || interval < time.Minute {
+		return fmt.Errorf("sla.interval 无效（不能小于 1m）: %s", s.Interval)
+	}
+	s.IntervalDuration = interval
+
+	if s.WarningThreshold == 0 {
+		s.WarningThreshold = defaultSLAWarningThreshold
+	}
+	if s.WarningThreshold <= 0 || s.WarningThreshold >= 1 {
+		return fmt.Errorf("sla.warning_threshold 必须在 0-1 之间（不含边界），当前值: %g", s.WarningThreshold)
+	}
+
+	seen := make(map[string]bool, len(s.Targets))
+	for i := range s.Targets {
+		t := &s.Targets[i]
+		t.Provider = strings.TrimSpace(t.Provider)
+		if t.Provider == "" {
+			return fmt.Errorf("sla.targets[%d]: provider 不能为空", i)
+		}
+		key := strings.ToLower(t.Provider)
+		if seen[key] {
+			return fmt.Errorf("sla.targets[%d]: provider 重复: %s", i, t.Provider)
+		}
+		seen[key] = true
+
+		if t.Target <= 0 || t.Target > 100 {
+			return fmt.Errorf("sla.targets[%d]: target 必须在 0-100 之间（百分比），当前值: %g", i, t.Target)
+		}
+		t.Period = strings.ToLower(strings.TrimSpace(t.Period))
+		if t.Period == "" {
+			t.Period = SLAPeriodMonthly
+		}
+		if t.Period != SLAPeriodMonthly && t.Period != SLAPeriodWeekly {
+			return fmt.Errorf("sla.targets[%d]: period 无效: %s（支持 monthly/weekly）", i, t.Period)
+		}
+	}
+	return nil
+}
+
+// Clone 深拷贝 SLA 配置
+func (s SLAConfig) Clone() SLAConfig {
+	s.Targets = append([]SLATarget(nil), s.Targets...)
+	return s
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSLAConfigNormalize(t *testing.T) {
+	t.Parallel()
+
+	var empty SLAConfig
+	if err := empty.Normalize(); err != nil {
+		t.Fatalf("empty config should normalize: %v", err)
+	}
+	if empty.Enabled() || empty.IntervalDuration != 5*time.Minute || empty.WarningThreshold != defaultSLAWarningThreshold {
+		t.Fatalf("unexpected defaults: %+v", empty)
+	}
+
+	cfg := SLAConfig{Targets: []SLATarget{{Provider: " Relay ", Target: 99.5}, {Provider: "other", Target: 99, Period: " Weekly "}}}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if cfg.Targets[0].Provider != "Relay" || cfg.Targets[0].Period != SLAPeriodMonthly || cfg.Targets[1].Period != SLAPeriodWeekly {
+		t.Fatalf("unexpected targets: %+v", cfg.Targets)
+	}
+
+	clone := cfg.Clone()
+	clone.Targets[0].Target = 90
+	if cfg.Targets[0].Target != 99.5 {
+		t.Fatalf("clone shares targets slice")
+	}
+
+	for name, bad := range map[string]SLAConfig{
+		"interval too short":  {Interval: "30s"},
+		"threshold >= 1":      {WarningThreshold: 1},
+		"empty provider":      {Targets: []SLATarget{{Target: 99}}},
+		"duplicate provider":  {Targets: []SLATarget{{Provider: "a", Target: 99}, {Provider: "A", Target: 98}}},
+		"target out of range": {Targets: []SLATarget{{Provider: "a", Target: 100.5}}},
+		"unknown period":      {Targets: []SLATarget{{Provider: "a", Target: 99, Period: "daily"}}},
+	} {
+		if err := bad.Normalize(); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
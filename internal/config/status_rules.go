@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// StatusRule 监测项级自定义状态映射规则
+// 用于内置映射不适用的服务商（如 200 返回错误 JSON、503 表示维护中）
+//
+// 条件之间为"与"关系：http_codes 命中且 json_path/header 取值满足 equals/contains。
+// 多条规则按配置顺序匹配，第一条命中的规则决定最终状态。
+type StatusRule struct {
+	// 匹配的 HTTP 状态码（可选，为空时不限制）
+	HTTPCodes []int `yaml:"http_codes" json:"-"`
+
+	// 响应 JSON 字段路径（点分，数字段表示数组下标；SSE 响应取最后一次出现的值）
+	JSONPath string `yaml:"json_path" json:"-"`
+
+	// 响应头名称（与 json_path 二选一）
+	Header string `yaml:"header" json:"-"`
+
+	// 取值比较：equals 完全相等、contains 包含子串（二选一；均不配置时仅要求字段/响应头存在）
+	Equals   string `yaml:"equals" json:"-"`
+	Contains string `yaml:"contains" json:"-"`
+
+	// 命中后的状态：green / yellow / red
+	Status string `yaml:"status" json:"-"`
+
+	// 命中后的细分状态（可选，小写字母、数字、下划线）
+	SubStatus string `yaml:"sub_status" json:"-"`
+
+	// 解析后的状态值（内部使用，1=绿, 2=黄, 0=红）
+	StatusValue int `yaml:"-" json:"-"`
+}
+
+// statusRuleValues status 取值映射
+var statusRuleValues = map[string]int{
+	"green":  1,
+	"yellow": 2,
+	"red":    0,
+}
+
+// subStatusPattern 自定义 sub_status 格式
+var subStatusPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// StatusRulesNeedBody 状态映射规则是否需要读取响应体（存在 json_path 条件）
+func (m *ServiceConfig) StatusRulesNeedBody() bool {
+	for i := range m.StatusRules {
+		if m.StatusRules[i].JSONPath != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeStatusRules 校验并解析状态映射规则的派生字段（继承后调用）
+func normalizeStatusRules(rules []StatusRule) error {
+	for i := range rules {
+		r := &rules[i]
+		r.JSONPath = strings.TrimSpace(r.JSONPath)
+		r.Header = strings.TrimSpace(r.Header)
+		r.Status = strings.ToLower(strings.TrimSpace(r.Status))
+		r.SubStatus = strings.TrimSpace(r.SubStatus)
+
+		value, ok := statusRuleValues[r.Status]
+		if !ok {
+			return fmt.Errorf("status_rules[%d]: status 必须是 green/yellow/red，当前值: '%s'", i, r.Status)
+		}
+		r.StatusValue = value
+
+		if r.SubStatus != "" && !subStatusPattern.MatchString(r.SubStatus) {
+			return fmt.Errorf("status_rules[%d]: sub_status '%s' 格式无效（小写字母开头，仅含小写字母、数字、下划线，最长 32 字符）", i, r.SubStatus)
+		}
+
+		for _, code := range r.HTTPCodes {
+			if code < 100 || code > 599 {
+				return fmt.Errorf("status_rules[%d]: http_codes 包含无效状态码 %d", i, code)
+			}
+		}
+
+		if r.JSONPath != "" && r.Header != "" {
+			return fmt.Errorf("status_rules[%d]: json_path 与 header 不能同时配置", i)
+		}
+		if r.JSONPath != "" && (strings.HasPrefix(r.JSONPath, ".") || strings.HasSuffix(r.JSONPath, ".") || strings.Contains(r.JSONPath, "..")) {
+			return fmt.Errorf("status_rules[%d]: json_path '%s' 格式无效", i, r.JSONPath)
+		}
+		if r.Header != "" {
+			r.Header = http.CanonicalHeaderKey(r.Header)
+		}
+		if r.Equals != "" && r.Contains != "" {
+			return fmt.Errorf("status_rules[%d]: equals 与 contains 不能同时配置", i)
+		}
+		if (r.Equals != "" || r.Contains != "") && r.JSONPath == "" && r.Header == "" {
+			return fmt.Errorf("status_rules[%d]: equals/contains 需要配合 json_path 或 header 使用", i)
+		}
+		if len(r.HTTPCodes) == 0 && r.JSONPath == "" && r.Header == "" {
+			return fmt.Errorf("status_rules[%d]: 至少需要配置 http_codes、json_path 或 header 之一", i)
+		}
+	}
+	return nil
+}
+
+// cloneStatusRules 深拷贝状态映射规则
+func cloneStatusRules(rules []StatusRule) []StatusRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	clone := make([]StatusRule, len(rules))
+	for i, r := range rules {
+		clone[i] = r
+		if len(r.HTTPCodes) > 0 {
+			clone[i].HTTPCodes = append([]int(nil), r.HTTPCodes...)
+		}
+	}
+	return clone
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeResolvesStatusRules(t *testing.T) {
+	cfg := &AppConfig{
+		Monitors: []ServiceConfig{
+			{
+				Provider: "demo",
+				Service:  "cc",
+				Channel:  "vip",
+				Model:    "base",
+				URL:      "https://example.com",
+				Method:   "POST",
+				Category: "public",
+				StatusRules: []StatusRule{
+					{HTTPCodes: []int{503}, Status: " Yellow ", SubStatus: "maintenance"},
+					{JSONPath: "error.type", Equals: "overloaded_error", Status: "red", SubStatus: "overloaded"},
+				},
+			},
+			{
+				Provider: "demo",
+				Service:  "cc",
+				Channel:  "vip",
+				Model:    "child",
+				Parent:   "demo/cc/vip",
+				Category: "public",
+			},
+		},
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("Normalize() failed: %v", err)
+	}
+
+	child := cfg.Monitors[1]
+	if len(child.StatusRules) != 2 || child.StatusRules[0].StatusValue != 2 || child.StatusRules[1].StatusValue != 0 {
+		t.Fatalf("子通道应继承父通道状态映射规则: %+v", child.StatusRules)
+	}
+	if !child.StatusRulesNeedBody() {
+		t.Fatalf("json_path 规则需要读取响应体")
+	}
+
+	// 继承的规则与父通道互不影响
+	child.StatusRules[0].HTTPCodes[0] = 502
+	if cfg.Monitors[0].StatusRules[0].HTTPCodes[0] != 503 {
+		t.Fatalf("继承的规则应为深拷贝")
+	}
+}
+
+func TestNormalizeStatusRulesRejectsInvalid(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]StatusRule{
+		"status 必须是":       {HTTPCodes: []int{503}, Status: "blue"},
+		"sub_status":       {HTTPCodes: []int{503}, Status: "red", SubStatus: "Bad-Name"},
+		"无效状态码":            {HTTPCodes: []int{999}, Status: "red"},
+		"不能同时配置":           {JSONPath: "a", Header: "X-A", Status: "red"},
+		"需要配合":             {Equals: "x", Status: "red"},
+		"至少需要配置":           {Status: "red"},
+		"json_path 'a..b'": {JSONPath: "a..b", Status: "red"},
+	}
+	for want, rule := range cases {
+		err := normalizeStatusRules([]StatusRule{rule})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error containing %q for %+v, got %v", want, rule, err)
+		}
+	}
+}
+
+func TestNormalizeExpectedStatusCodes(t *testing.T) {
+	newCfg := func(codes []int) *AppConfig {
+		return &AppConfig{
+			Monitors: []ServiceConfig{
+				{
+					Provider:            "demo",
+					Service:             "cc",
+					Channel:             "vip",
+					Model:               "base",
+					URL:                 "https://example.com",
+					Method:              "GET",
+					Category:            "public",
+					ExpectedStatusCodes: codes,
+				},
+				{
+					Provider: "demo",
+					Service:  "cc",
+					Channel:  "vip",
+					Model:    "child",
+					Parent:   "demo/cc/vip",
+					Category: "public",
+				},
+			},
+		}
+	}
+
+	cfg := newCfg([]int{200, 401})
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("Normalize() failed: %v", err)
+	}
+	child := cfg.Monitors[1]
+	if len(child.ExpectedStatusCodes) != 2 || child.ExpectedStatusCodes[1] != 401 {
+		t.Fatalf("子通道应继承父通道期望状态码: %v", child.ExpectedStatusCodes)
+	}
+	child.ExpectedStatusCodes[0] = 204
+	if cfg.Monitors[0].ExpectedStatusCodes[0] != 200 {
+		t.Fatalf("继承的期望状态码应为深拷贝")
+	}
+
+	invalid := newCfg([]int{200, 99})
+	if err := invalid.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+	if err := invalid.Normalize(); err == nil || !strings.Contains(err.Error(), "expected_status_codes") {
+		t.Fatalf("期望无效状态码报错，实际: %v", err)
+	}
+}
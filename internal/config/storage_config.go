@@ -21,6 +21,267 @@ type StorageConfig struct {
 
 	// 历史数据归档配置（默认禁用）
 	Archive ArchiveConfig `yaml:"archive" json:"archive"`
+
+	// 探测记录批量写入配置（默认禁用）
+	WriteBatch WriteBatchConfig `yaml:"write_batch" json:"write_batch"`
+
+	// 慢查询日志配置（默认禁用）
+	SlowQuery SlowQueryConfig `yaml:"slow_query" json:"slow_query"`
+
+	// 数据库维护配置（默认禁用）
+	Maintenance MaintenanceConfig `yaml:"maintenance" json:"maintenance"`
+}
+
+// WriteBatchConfig 探测记录批量写入配置
+// 启用后 SaveRecord 进入缓冲队列，由后台协程按条数或时间间隔合并为多行 INSERT（单事务），
+// 调用方阻塞到所在批次提交后返回（仍可拿到记录 ID 与写入错误）
+type WriteBatchConfig struct {
+	// 是否启用（默认 false）
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// 单批最大条数，达到即刷新（默认 200）
+	MaxBatchSize int `yaml:"max_batch_size" json:"max_batch_size"`
+
+	// 最长攒批时间（默认 "100ms"）
+	FlushInterval string `yaml:"flush_interval" json:"flush_interval"`
+
+	// 队列容量（默认 1000），队列满时 SaveRecord 阻塞等待（背压）
+	QueueSize int `yaml:"queue_size" json:"queue_size"`
+
+	FlushIntervalDuration time.Duration `yaml:"-" json:"-"`
+}
+
+// Normalize 规范化批量写入配置
+func (c *WriteBatchConfig) Normalize() error {
+	if c.MaxBatchSize == 0 {
+		c.MaxBatchSize = 200
+	}
+	if c.MaxBatchSize < 1 || c.MaxBatchSize > 5000 {
+		return fmt.Errorf("storage.write_batch.max_batch_size 必须在 1-5000 范围内，当前值: %d", c.MaxBatchSize)
+	}
+
+	if strings.TrimSpace(c.FlushInterval) == "" {
+		c.FlushInterval = "100ms"
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(c.FlushInterval))
+	if err != nil {
+		return fmt.Errorf("storage.write_batch.flush_interval 解析失败: %w", err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("storage.write_batch.flush_interval 必须 > 0")
+	}
+	c.FlushIntervalDuration = d
+
+	if c.QueueSize == 0 {
+		c.QueueSize = 1000
+	}
+	if c.QueueSize < c.MaxBatchSize {
+		return fmt.Errorf("storage.write_batch.queue_size(%d) 不能小于 max_batch_size(%d)", c.QueueSize, c.MaxBatchSize)
+	}
+	return nil
+}
+
+// SlowQueryConfig 慢查询日志配置
+// 启用后耗时超过阈值的 SQL 连同 EXPLAIN 执行计划写入告警日志，并按 SQL 文本汇总到 /api/admin/storage/diagnostics；
+// 与其他存储配置一样修改后需重启生效
+type SlowQueryConfig struct {
+	// 是否启用（默认 false）
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// 慢查询阈值（默认 "500ms"）
+	Threshold string `yaml:"threshold" json:"threshold"`
+
+	// 是否获取执行计划（默认 true；同一 SQL 每 10 分钟最多 EXPLAIN 一次，不使用 ANALYZE，不会重复执行语句）
+	Explain *bool `yaml:"explain" json:"explain"`
+
+	// 诊断端点保留的慢查询条数（按 SQL 文本聚合，默认 50）
+	MaxEntries int `yaml:"max_entries" json:"max_entries"`
+
+	ThresholdDuration time.Duration `yaml:"-" json:"-"`
+}
+
+// ExplainEnabled 返回是否获取执行计划
+func (c *SlowQueryConfig) ExplainEnabled() bool {
+	return c.Explain == nil || *c.Explain
+}
+
+// Normalize 规范化慢查询日志配置
+func (c *SlowQueryConfig) Normalize() error {
+	if strings.TrimSpace(c.Threshold) == "" {
+		c.Threshold = "500ms"
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(c.Threshold))
+	if err != nil {
+		return fmt.Errorf("storage.slow_query.threshold 解析失败: %w", err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("storage.slow_query.threshold 必须 > 0")
+	}
+	c.ThresholdDuration = d
+
+	if c.MaxEntries == 0 {
+		c.MaxEntries = 50
+	}
+	if c.MaxEntries < 1 || c.MaxEntries > 1000 {
+		return fmt.Errorf("storage.slow_query.max_entries 必须在 1-1000 范围内，当前值: %d", c.MaxEntries)
+	}
+	return nil
+}
+
+// MaintenanceConfig 数据库维护配置
+// 启用后在低峰时段定期执行 VACUUM/ANALYZE，并在索引碎片率超过阈值时 REINDEX；修改后需重启生效
+type MaintenanceConfig struct {
+	// 是否启用（默认 false）
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// 允许执行维护的低峰时段（HH:MM-HH:MM，可跨午夜，默认 ["03:00-05:00"]）
+	Windows []string `yaml:"windows" json:"windows"`
+
+	// 时段所在时区（IANA 名称，默认 "UTC"）
+	Timezone string `yaml:"timezone" json:"timezone"`
+
+	// 两轮维护的最小间隔（默认 "24h"）
+	Interval string `yaml:"interval" json:"interval"`
+
+	// 单轮维护超时（默认 "1h"）
+	Timeout string `yaml:"timeout" json:"timeout"`
+
+	// VACUUM 触发比例（默认 0.1）：SQLite 为空闲页占比，PostgreSQL 为表的死元组占比
+	VacuumThreshold float64 `yaml:"vacuum_threshold" json:"vacuum_threshold"`
+
+	// REINDEX 触发比例（默认 0.4）：索引页内未使用空间占比（PostgreSQL 需安装 pgstattuple 扩展），1 表示不 REINDEX
+	ReindexThreshold float64 `yaml:"reindex_threshold" json:"reindex_threshold"`
+
+	// SQLite 每轮 incremental_vacuum 最多回收的页数（默认 2000）
+	IncrementalPages int `yaml:"incremental_pages" json:"incremental_pages"`
+
+	WindowRanges     []MaintenanceWindow `yaml:"-" json:"-"`
+	Location         *time.Location      `yaml:"-" json:"-"`
+	IntervalDuration time.Duration       `yaml:"-" json:"-"`
+	TimeoutDuration  time.Duration       `yaml:"-" json:"-"`
+}
+
+// MaintenanceWindow 维护时段（一天内的分钟数，左闭右开；Start >= End 表示跨午夜）
+type MaintenanceWindow struct {
+	Start int
+	End   int
+}
+
+// Contains 判断一天内的分钟数是否落在时段内
+func (w MaintenanceWindow) Contains(minute int) bool {
+	if w.Start < w.End {
+		return minute >= w.Start && minute < w.End
+	}
+	return minute >= w.Start || minute < w.End
+}
+
+// InWindow 判断时间是否落在任一维护时段内（按 timezone 的本地时钟）
+func (c *MaintenanceConfig) InWindow(t time.Time) bool {
+	loc := c.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	for _, w := range c.WindowRanges {
+		if w.Contains(minute) {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize 规范化数据库维护配置
+func (c *MaintenanceConfig) Normalize() error {
+	if len(c.Windows) == 0 {
+		c.Windows = []string{"03:00-05:00"}
+	}
+	c.WindowRanges = make([]MaintenanceWindow, 0, len(c.Windows))
+	for _, raw := range c.Windows {
+		w, err := parseMaintenanceWindow(raw)
+		if err != nil {
+			return err
+		}
+		c.WindowRanges = append(c.WindowRanges, w)
+	}
+
+	if strings.TrimSpace(c.Timezone) == "" {
+		c.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(strings.TrimSpace(c.Timezone))
+	if err != nil {
+		return fmt.Errorf("storage.maintenance.timezone 无效: %s", c.Timezone)
+	}
+	c.Location = loc
+
+	if strings.TrimSpace(c.Interval) == "" {
+		c.Interval = "24h"
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(c.Interval))
+	if err != nil || d < time.Hour {
+		return fmt.Errorf("storage.maintenance.interval 必须为 >= 1h 的时长，当前值: %s", c.Interval)
+	}
+	c.IntervalDuration = d
+
+	if strings.TrimSpace(c.Timeout) == "" {
+		c.Timeout = "1h"
+	}
+	d, err = time.ParseDuration(strings.TrimSpace(c.Timeout))
+	if err != nil || d <= 0 {
+		return fmt.Errorf("storage.maintenance.timeout 无效: %s", c.Timeout)
+	}
+	c.TimeoutDuration = d
+
+	if c.VacuumThreshold == 0 {
+		c.VacuumThreshold = 0.1
+	}
+	if c.VacuumThreshold < 0 || c.VacuumThreshold > 1 {
+		return fmt.Errorf("storage.maintenance.vacuum_threshold 必须在 (0,1] 范围内，当前值: %g", c.VacuumThreshold)
+	}
+	if c.ReindexThreshold == 0 {
+		c.ReindexThreshold = 0.4
+	}
+	if c.ReindexThreshold < 0 || c.ReindexThreshold > 1 {
+		return fmt.Errorf("storage.maintenance.reindex_threshold 必须在 (0,1] 范围内，当前值: %g", c.ReindexThreshold)
+	}
+
+	if c.IncrementalPages == 0 {
+		c.IncrementalPages = 2000
+	}
+	if c.IncrementalPages < 1 {
+		return fmt.Errorf("storage.maintenance.incremental_pages 必须 >= 1，当前值: %d", c.IncrementalPages)
+	}
+	return nil
+}
+
+// parseMaintenanceWindow 解析 HH:MM-HH:MM 格式的维护时段（结束可为 24:00）
+func parseMaintenanceWindow(raw string) (MaintenanceWindow, error) {
+	start, end, ok := strings.Cut(strings.TrimSpace(raw), "-")
+	if !ok {
+		return MaintenanceWindow{}, fmt.Errorf("storage.maintenance.windows 格式无效: %q（应为 HH:MM-HH:MM）", raw)
+	}
+	startMin, err1 := parseClockMinutes(start, false)
+	endMin, err2 := parseClockMinutes(end, true)
+	if err1 != nil || err2 != nil {
+		return MaintenanceWindow{}, fmt.Errorf("storage.maintenance.windows 格式无效: %q（应为 HH:MM-HH:MM）", raw)
+	}
+	if startMin == endMin {
+		return MaintenanceWindow{}, fmt.Errorf("storage.maintenance.windows 开始时间不能等于结束时间: %q", raw)
+	}
+	return MaintenanceWindow{Start: startMin, End: endMin}, nil
+}
+
+// parseClockMinutes 解析 HH:MM 为一天内的分钟数（allow24 允许 24:00）
+func parseClockMinutes(s string, allow24 bool) (int, error) {
+	s = strings.TrimSpace(s)
+	if allow24 && s == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
 }
 
 // SQLiteConfig SQLite 配置
@@ -68,6 +329,10 @@ type RetentionConfig struct {
 	// 取值范围 [0,1]，用于在 interval 基础上增加随机偏移，避免多实例同刻执行
 	Jitter float64 `yaml:"jitter" json:"jitter"`
 
+	// 已下线监测项的宽限天数（默认 30）
+	// 监测项从配置移除后，宽限期内仍可通过 include_retired=true 查询其历史，期满后由清理任务删除其全部数据
+	DecommissionedDays int `yaml:"decommissioned_days" json:"decommissioned_days"`
+
 	// 解析后的时间间隔（内部使用，不序列化）
 	CleanupIntervalDuration time.Duration `yaml:"-" json:"-"`
 	StartupDelayDuration    time.Duration `yaml:"-" json:"-"`
@@ -141,6 +406,14 @@ func (c *RetentionConfig) Normalize() error {
 		return fmt.Errorf("storage.retention.jitter 必须在 [0,1] 范围内，当前值: %g", c.Jitter)
 	}
 
+	// 已下线监测项宽限天数（默认 30）
+	if c.DecommissionedDays == 0 {
+		c.DecommissionedDays = 30
+	}
+	if c.DecommissionedDays < 1 {
+		return fmt.Errorf("storage.retention.decommissioned_days 必须 >= 1，当前值: %d", c.DecommissionedDays)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWriteBatchConfigNormalize(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		var wb WriteBatchConfig
+		if err := wb.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if wb.MaxBatchSize != 200 || wb.QueueSize != 1000 || wb.FlushIntervalDuration != 100*time.Millisecond {
+			t.Fatalf("unexpected defaults: %+v", wb)
+		}
+	})
+
+	invalid := map[string]WriteBatchConfig{
+		"batch too large":       {MaxBatchSize: 5001},
+		"negative batch":        {MaxBatchSize: -1},
+		"bad interval":          {FlushInterval: "soon"},
+		"zero interval":         {FlushInterval: "0s"},
+		"queue below batch":     {MaxBatchSize: 500, QueueSize: 100},
+		"default queue < batch": {MaxBatchSize: 2000},
+	}
+	for name, wb := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if err := wb.Normalize(); err == nil {
+				t.Fatalf("expected error for %+v", wb)
+			}
+		})
+	}
+}
+
+func TestSlowQueryConfigNormalize(t *testing.T) {
+	var sq SlowQueryConfig
+	if err := sq.Normalize(); err != nil {
+		t.Fatalf("Normalize() failed: %v", err)
+	}
+	if sq.ThresholdDuration != 500*time.Millisecond || sq.MaxEntries != 50 || !sq.ExplainEnabled() {
+		t.Fatalf("unexpected defaults: %+v", sq)
+	}
+
+	off := false
+	sq = SlowQueryConfig{Threshold: "2s", Explain: &off}
+	if err := sq.Normalize(); err != nil {
+		t.Fatalf("Normalize() failed: %v", err)
+	}
+	if sq.ThresholdDuration != 2*time.Second || sq.ExplainEnabled() {
+		t.Fatalf("unexpected config: %+v", sq)
+	}
+
+	invalid := map[string]SlowQueryConfig{
+		"bad threshold":    {Threshold: "slow"},
+		"zero threshold":   {Threshold: "0s"},
+		"negative entries": {MaxEntries: -1},
+		"too many entries": {MaxEntries: 1001},
+	}
+	for name, sq := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if err := sq.Normalize(); err == nil {
+				t.Fatalf("expected error for %+v", sq)
+			}
+		})
+	}
+}
+
+func TestMaintenanceConfigNormalize(t *testing.T) {
+	var mc MaintenanceConfig
+	if err := mc.Normalize(); err != nil {
+		t.Fatalf("Normalize() failed: %v", err)
+	}
+	if mc.IntervalDuration != 24*time.Hour || mc.TimeoutDuration != time.Hour ||
+		mc.VacuumThreshold != 0.1 || mc.ReindexThreshold != 0.4 || mc.IncrementalPages != 2000 {
+		t.Fatalf("unexpected defaults: %+v", mc)
+	}
+	if !mc.InWindow(time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC)) || mc.InWindow(time.Date(2026, 1, 1, 5, 0, 0, 0, time.UTC)) {
+		t.Fatalf("default window should be [03:00, 05:00) UTC")
+	}
+
+	// 跨午夜时段与时区
+	mc = MaintenanceConfig{Windows: []string{"23:30-01:00", "12:00-24:00"}, Timezone: "Asia/Shanghai"}
+	if err := mc.Normalize(); err != nil {
+		t.Fatalf("Normalize() failed: %v", err)
+	}
+	shanghai := mc.Location
+	cases := map[string]bool{
+		"23:30": true,
+		"00:59": true,
+		"01:00": false,
+		"11:59": false,
+		"12:00": true,
+		"23:59": true,
+	}
+	for clock, want := range cases {
+		tm, _ := time.ParseInLocation("2006-01-02 15:04", "2026-01-01 "+clock, shanghai)
+		if got := mc.InWindow(tm.UTC()); got != want {
+			t.Errorf("InWindow(%s) = %v, want %v", clock, got, want)
+		}
+	}
+
+	invalid := map[string]MaintenanceConfig{
+		"bad window":       {Windows: []string{"03:00"}},
+		"bad clock":        {Windows: []string{"25:00-26:00"}},
+		"empty window":     {Windows: []string{"03:00-03:00"}},
+		"bad timezone":     {Timezone: "Mars/Base"},
+		"short interval":   {Interval: "10m"},
+		"bad timeout":      {Timeout: "0s"},
+		"vacuum too large": {VacuumThreshold: 1.5},
+		"negative reindex": {ReindexThreshold: -0.1},
+		"negative pages":   {IncrementalPages: -1},
+	}
+	for name, mc := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if err := mc.Normalize(); err == nil {
+				t.Fatalf("expected error for %+v", mc)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// 默认证书到期预警天数
+const defaultTLSExpiryWarningDays = 7
+
+// TLSConfig 监测项级 TLS 配置
+// 用于需要客户端证书（mTLS）、自定义 CA 或 SNI 的中转站
+type TLSConfig struct {
+	// 自定义 CA 证书文件（PEM），追加到系统根证书之上
+	CAFile string `yaml:"ca_file" json:"-"`
+
+	// 客户端证书与私钥（PEM），必须同时配置
+	CertFile string `yaml:"cert_file" json:"-"`
+	KeyFile  string `yaml:"key_file" json:"-"`
+
+	// 跳过服务端证书校验（仅用于测试环境）
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify" json:"-"`
+
+	// 最低 TLS 版本："1.0"、"1.1"、"1.2"、"1.3"（默认由 Go 决定，当前为 1.2）
+	MinVersion string `yaml:"min_version" json:"-"`
+
+	// SNI / 证书校验使用的主机名覆盖（可选）
+	ServerName string `yaml:"server_name" json:"-"`
+
+	// 证书到期预警天数（默认 7；0 表示关闭）
+	// 服务端证书或客户端证书剩余有效期低于该值时，绿色结果降级为黄色 cert_expiring
+	// 使用 *int 以区分"未设置(nil)"和"显式设置为 0"
+	ExpiryWarningDays *int `yaml:"expiry_warning_days" json:"-"`
+
+	// 解析后的最低 TLS 版本（内部使用）
+	MinVersionValue uint16 `yaml:"-" json:"-"`
+
+	// 解析后的到期预警窗口（内部使用，0 表示关闭）
+	ExpiryWarning time.Duration `yaml:"-" json:"-"`
+
+	// 客户端证书到期时间（内部使用，未配置客户端证书时为零值）
+	ClientCertNotAfter time.Time `yaml:"-" json:"-"`
+}
+
+// tlsVersions min_version 取值映射
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Validate 校验 TLS 配置（会实际加载证书文件，确保热更新前即可发现问题）
+func (t *TLSConfig) Validate() error {
+	if t == nil {
+		return nil
+	}
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("tls.cert_file 与 tls.key_file 必须同时配置")
+	}
+	if v := strings.TrimSpace(t.MinVersion); v != "" {
+		if _, ok := tlsVersions[v]; !ok {
+			return fmt.Errorf("tls.min_version '%s' 无效，必须是 1.0/1.1/1.2/1.3 之一", t.MinVersion)
+		}
+	}
+	if t.ExpiryWarningDays != nil && *t.ExpiryWarningDays < 0 {
+		return fmt.Errorf("tls.expiry_warning_days 不能为负数")
+	}
+	if _, err := t.Build(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Normalize 解析 TLS 配置的派生字段
+func (t *TLSConfig) Normalize() error {
+	if t == nil {
+		return nil
+	}
+	t.CAFile = strings.TrimSpace(t.CAFile)
+	t.CertFile = strings.TrimSpace(t.CertFile)
+	t.KeyFile = strings.TrimSpace(t.KeyFile)
+	t.ServerName = strings.TrimSpace(t.ServerName)
+	t.MinVersion = strings.TrimSpace(t.MinVersion)
+	t.MinVersionValue = tlsVersions[t.MinVersion]
+
+	days := defaultTLSExpiryWarningDays
+	if t.ExpiryWarningDays != nil {
+		days = *t.ExpiryWarningDays
+	}
+	t.ExpiryWarning = time.Duration(days) * 24 * time.Hour
+
+	t.ClientCertNotAfter = time.Time{}
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return fmt.Errorf("加载客户端证书失败: %w", err)
+		}
+		if leaf, err := x509.ParseCertificate(cert.Certificate[0]); err == nil {
+			t.ClientCertNotAfter = leaf.NotAfter
+		}
+	}
+	return nil
+}
+
+// Build 根据配置构建 *tls.Config（每次调用都会重新读取证书文件）
+func (t *TLSConfig) Build() (*tls.Config, error) {
+	if t == nil {
+		return nil, nil
+	}
+
+	tlsCfg := &tls.Config{
+		InsecureSkipVerify: t.InsecureSkipVerify, //nolint:gosec // 由用户显式配置
+		ServerName:         strings.TrimSpace(t.ServerName),
+		MinVersion:         tlsVersions[strings.TrimSpace(t.MinVersion)],
+	}
+
+	if caFile := strings.TrimSpace(t.CAFile); caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取 tls.ca_file 失败: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tls.ca_file 中没有有效的 PEM 证书")
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	if certFile := strings.TrimSpace(t.CertFile); certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, strings.TrimSpace(t.KeyFile))
+		if err != nil {
+			return nil, fmt.Errorf("加载客户端证书失败: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsCfg, nil
+}
+
+// CacheKey 返回用于 HTTP 客户端池的缓存键（相同 TLS 配置复用同一客户端）
+func (t *TLSConfig) CacheKey() string {
+	if t == nil {
+		return ""
+	}
+	return fmt.Sprintf("ca=%s;cert=%s;key=%s;insecure=%t;min=%s;sni=%s",
+		t.CAFile, t.CertFile, t.KeyFile, t.InsecureSkipVerify, t.MinVersion, t.ServerName)
+}
+
+// FilesVersion 返回 CA、客户端证书与私钥文件的版本标识（修改时间与大小）
+// 证书轮换后文件版本变化，HTTP 客户端池据此重建客户端以重新读取证书
+func (t *TLSConfig) FilesVersion() string {
+	if t == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, path := range []string{t.CAFile, t.CertFile, t.KeyFile} {
+		if path == "" {
+			sb.WriteString("-;")
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			sb.WriteString("missing;")
+			continue
+		}
+		fmt.Fprintf(&sb, "%d/%d;", info.ModTime().UnixNano(), info.Size())
+	}
+	return sb.String()
+}
+
+// Clone 深拷贝 TLS 配置
+func (t *TLSConfig) Clone() *TLSConfig {
+	if t == nil {
+		return nil
+	}
+	clone := *t
+	clone.ExpiryWarningDays = cloneIntPtr(t.ExpiryWarningDays)
+	return &clone
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTLSConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	negative := -1
+	tests := []struct {
+		name    string
+		cfg     *TLSConfig
+		wantErr bool
+	}{
+		{"未配置", nil, false},
+		{"仅 SNI 与版本", &TLSConfig{ServerName: "api.example.com", MinVersion: "1.3"}, false},
+		{"证书与私钥不成对", &TLSConfig{CertFile: "client.pem"}, true},
+		{"无效版本", &TLSConfig{MinVersion: "1.4"}, true},
+		{"负数预警天数", &TLSConfig{ExpiryWarningDays: &negative}, true},
+		{"CA 文件不存在", &TLSConfig{CAFile: "/nonexistent/ca.pem"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr && err == nil {
+				t.Fatalf("期望报错")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("不期望报错: %v", err)
+			}
+		})
+	}
+}
+
+func TestTLSConfigNormalize(t *testing.T) {
+	t.Parallel()
+
+	cfg := &TLSConfig{MinVersion: " 1.2 "}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("规范化失败: %v", err)
+	}
+	if cfg.MinVersionValue == 0 {
+		t.Fatalf("min_version 未解析")
+	}
+	if cfg.ExpiryWarning != defaultTLSExpiryWarningDays*24*time.Hour {
+		t.Fatalf("默认预警窗口错误: %v", cfg.ExpiryWarning)
+	}
+
+	zero := 0
+	disabled := &TLSConfig{ExpiryWarningDays: &zero}
+	if err := disabled.Normalize(); err != nil {
+		t.Fatalf("规范化失败: %v", err)
+	}
+	if disabled.ExpiryWarning != 0 {
+		t.Fatalf("expiry_warning_days=0 应关闭预警")
+	}
+}
+
+func TestTLSInheritanceAndClone(t *testing.T) {
+	t.Parallel()
+
+	parent := ServiceConfig{TLS: &TLSConfig{ServerName: "relay.internal"}}
+	child := ServiceConfig{}
+	inheritCoreBehavior(&child, &parent)
+
+	if child.TLS == nil || child.TLS.ServerName != "relay.internal" {
+		t.Fatalf("子通道未继承 TLS 配置")
+	}
+	if child.TLS == parent.TLS {
+		t.Fatalf("继承的 TLS 配置应为独立副本")
+	}
+
+	cfg := &AppConfig{Monitors: []ServiceConfig{parent}}
+	clone := cfg.Clone()
+	clone.Monitors[0].TLS.ServerName = "changed"
+	if cfg.Monitors[0].TLS.ServerName != "relay.internal" {
+		t.Fatalf("Clone 未深拷贝 TLS 配置")
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TracingConfig 分布式追踪配置（OTLP/HTTP 导出）
+// 覆盖 API 请求 → 存储查询、调度器 → 探测 → 存储 → 事件 两条链路；修改后需重启生效
+type TracingConfig struct {
+	// 是否启用追踪（默认禁用）
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// OTLP/HTTP 接收端基础地址（默认 http://localhost:4318，实际上报到 <endpoint>/v1/traces）
+	// 支持 OTEL_EXPORTER_OTLP_ENDPOINT 环境变量覆盖
+	Endpoint string `yaml:"endpoint" json:"endpoint"`
+
+	// 上报的服务名（默认 relay-pulse），支持 OTEL_SERVICE_NAME 环境变量覆盖
+	ServiceName string `yaml:"service_name" json:"service_name"`
+
+	// 根 span 采样率（0-1，默认 1；下游请求携带 traceparent 时沿用上游采样决策）
+	SampleRatio *float64 `yaml:"sample_ratio" json:"sample_ratio"`
+
+	// 附加请求头（如鉴权），支持 OTEL_EXPORTER_OTLP_HEADERS 环境变量覆盖（格式 k1=v1,k2=v2）
+	Headers map[string]string `yaml:"headers" json:"-"`
+
+	// 上报超时（默认 "10s"）
+	Timeout string `yaml:"timeout" json:"timeout"`
+
+	TimeoutDuration time.Duration `yaml:"-" json:"-"`
+}
+
+// Normalize 规范化追踪配置
+func (t *TracingConfig) Normalize() error {
+	if v := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")); v != "" {
+		t.Endpoint = v
+	}
+	if v := strings.TrimSpace(os.Getenv("OTEL_SERVICE_NAME")); v != "" {
+		t.ServiceName = v
+	}
+	if v := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS")); v != "" {
+		t.Headers = make(map[string]string)
+		for _, pair := range strings.Split(v, ",") {
+			k, val, ok := strings.Cut(pair, "=")
+			if !ok || strings.TrimSpace(k) == "" {
+				return fmt.Errorf("OTEL_EXPORTER_OTLP_HEADERS 格式错误: %q（应为 k1=v1,k2=v2）", pair)
+			}
+			t.Headers[strings.TrimSpace(k)] = strings.TrimSpace(val)
+		}
+	}
+
+	t.Endpoint = strings.TrimRight(strings.TrimSpace(t.Endpoint), "/")
+	if t.Endpoint == "" {
+		t.Endpoint = "http://localhost:4318"
+	}
+	if !strings.HasPrefix(t.Endpoint, "http://") && !strings.HasPrefix(t.Endpoint, "https://") {
+		return fmt.Errorf("tracing.endpoint 必须以 http:// 或 https:// 开头，当前值: %s", t.Endpoint)
+	}
+
+	if strings.TrimSpace(t.ServiceName) == "" {
+		t.ServiceName = "relay-pulse"
+	}
+
+	if t.SampleRatio == nil {
+		ratio := 1.0
+		t.SampleRatio = &ratio
+	}
+	if *t.SampleRatio < 0 || *t.SampleRatio > 1 {
+		return fmt.Errorf("tracing.sample_ratio 必须在 0-1 范围内，当前值: %s", strconv.FormatFloat(*t.SampleRatio, 'f', -1, 64))
+	}
+
+	if t.Timeout == "" {
+		t.Timeout = "10s"
+	}
+	d, err := time.ParseDuration(t.Timeout)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("tracing.timeout 无效: %s", t.Timeout)
+	}
+	t.TimeoutDuration = d
+	return nil
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTracingConfigNormalize(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+		t.Setenv("OTEL_SERVICE_NAME", "")
+		t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "")
+		var tc TracingConfig
+		if err := tc.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if tc.Endpoint != "http://localhost:4318" || tc.ServiceName != "relay-pulse" {
+			t.Fatalf("unexpected defaults: endpoint=%s service=%s", tc.Endpoint, tc.ServiceName)
+		}
+		if tc.SampleRatio == nil || *tc.SampleRatio != 1 || tc.TimeoutDuration != 10*time.Second {
+			t.Fatalf("unexpected defaults: %+v", tc)
+		}
+	})
+
+	t.Run("env override", func(t *testing.T) {
+		t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "https://otel.example.com/")
+		t.Setenv("OTEL_SERVICE_NAME", "relay-pulse-prod")
+		t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "Authorization=Bearer x, X-Tenant=a")
+		var tc TracingConfig
+		if err := tc.Normalize(); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if tc.Endpoint != "https://otel.example.com" || tc.ServiceName != "relay-pulse-prod" {
+			t.Fatalf("unexpected config: %+v", tc)
+		}
+		if tc.Headers["Authorization"] != "Bearer x" || tc.Headers["X-Tenant"] != "a" {
+			t.Fatalf("unexpected headers: %v", tc.Headers)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+		t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "")
+		bad := 1.5
+		cases := map[string]TracingConfig{
+			"bad ratio":    {SampleRatio: &bad},
+			"bad endpoint": {Endpoint: "localhost:4318"},
+			"bad timeout":  {Timeout: "abc"},
+		}
+		for name, tc := range cases {
+			if err := tc.Normalize(); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+}
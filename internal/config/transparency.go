@@ -0,0 +1,83 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// TransparencyConfig 探测数据透明度配置
+// 按小时将探测记录构建为 Merkle 树并用 Ed25519 签名根哈希（各小时根串成哈希链），
+// 公钥与签名证明通过 /api/transparency 公开，第三方可据此校验历史数据未被篡改
+type TransparencyConfig struct {
+	// 是否启用（默认禁用）
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// Ed25519 私钥（base64 编码的 32 字节种子或 64 字节私钥），启用时必填
+	// 支持 TRANSPARENCY_PRIVATE_KEY 环境变量覆盖
+	PrivateKey string `yaml:"private_key" json:"-"`
+
+	// 小时结束后等待多久再封存（默认 "5m"，等待在途探测写入完成）
+	SealDelay string `yaml:"seal_delay" json:"seal_delay"`
+
+	SealDelayDuration time.Duration `yaml:"-" json:"-"`
+
+	// 首次启用时向前补封存的小时数（默认 24，受 retention 限制）
+	BackfillHours int `yaml:"backfill_hours" json:"backfill_hours"`
+}
+
+// Normalize 规范化透明度配置
+func (t *TransparencyConfig) Normalize() error {
+	if envKey := strings.TrimSpace(os.Getenv("TRANSPARENCY_PRIVATE_KEY")); envKey != "" {
+		t.PrivateKey = envKey
+	} else {
+		t.PrivateKey = strings.TrimSpace(t.PrivateKey)
+	}
+
+	if t.SealDelay == "" {
+		t.SealDelay = "5m"
+	}
+	delay, err := time.ParseDuration(t.SealDelay)
+	if err != nil || delay < 0 || delay >= time.Hour {
+		return fmt.Errorf("transparency.seal_delay 无效（需在 0-1h 之间）: %s", t.SealDelay)
+	}
+	t.SealDelayDuration = delay
+
+	if t.BackfillHours == 0 {
+		t.BackfillHours = 24
+	}
+	if t.BackfillHours < 0 {
+		return fmt.Errorf("transparency.backfill_hours 不能为负数，当前值: %d", t.BackfillHours)
+	}
+
+	if !t.Enabled {
+		return nil
+	}
+	if t.PrivateKey == "" {
+		return fmt.Errorf("transparency.enabled=true 时必须配置 transparency.private_key（或 TRANSPARENCY_PRIVATE_KEY 环境变量）")
+	}
+	if _, err := t.SigningKey(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SigningKey 解析 Ed25519 私钥
+func (t *TransparencyConfig) SigningKey() (ed25519.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(t.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("transparency.private_key 不是有效的 base64: %w", err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("transparency.private_key 长度无效: %d 字节（需为 %d 字节种子或 %d 字节私钥）",
+			len(raw), ed25519.SeedSize, ed25519.PrivateKeySize)
+	}
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestTransparencyConfigNormalize(t *testing.T) {
+	t.Setenv("TRANSPARENCY_PRIVATE_KEY", "")
+
+	var disabled TransparencyConfig
+	if err := disabled.Normalize(); err != nil {
+		t.Fatalf("disabled config should normalize: %v", err)
+	}
+	if disabled.SealDelayDuration != 5*time.Minute || disabled.BackfillHours != 24 {
+		t.Fatalf("unexpected defaults: %+v", disabled)
+	}
+
+	missing := TransparencyConfig{Enabled: true}
+	if err := missing.Normalize(); err == nil {
+		t.Fatalf("expected error when private key is missing")
+	}
+
+	invalid := TransparencyConfig{Enabled: true, PrivateKey: base64.StdEncoding.EncodeToString([]byte("short"))}
+	if err := invalid.Normalize(); err == nil {
+		t.Fatalf("expected error for invalid key length")
+	}
+
+	badDelay := TransparencyConfig{SealDelay: "2h"}
+	if err := badDelay.Normalize(); err == nil {
+		t.Fatalf("expected error for seal_delay >= 1h")
+	}
+
+	_, key, _ := ed25519.GenerateKey(nil)
+	for _, raw := range [][]byte{key.Seed(), key} {
+		cfg := TransparencyConfig{Enabled: true, PrivateKey: base64.StdEncoding.EncodeToString(raw)}
+		if err := cfg.Normalize(); err != nil {
+			t.Fatalf("valid key (%d bytes) rejected: %v", len(raw), err)
+		}
+		parsed, err := cfg.SigningKey()
+		if err != nil || !parsed.Equal(key) {
+			t.Fatalf("parsed key mismatch (%d bytes): %v", len(raw), err)
+		}
+	}
+}
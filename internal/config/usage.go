@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 默认 token 用量提取路径（按顺序尝试，命中第一个即停止）
+//   - OpenAI / 兼容接口：usage.total_tokens
+//   - Gemini：usageMetadata.totalTokenCount
+//   - Anthropic Messages：usage.input_tokens + usage.output_tokens
+//     （流式响应的 input_tokens 位于 message_start 事件的 message.usage 中，两者互斥，一并求和）
+var defaultUsageTokenPaths = []string{
+	"usage.total_tokens",
+	"usageMetadata.totalTokenCount",
+	"usage.input_tokens+message.usage.input_tokens+usage.output_tokens",
+}
+
+// UsageConfig 探测 token 用量与成本统计配置
+// 用于估算监测 API Key 的消耗，配合 /api/usage 做预算跟踪
+type UsageConfig struct {
+	// 是否启用用量统计（默认禁用）
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// 全局 token 提取路径（点分路径，+ 表示多个字段求和；数组下标写作数字，如 choices.0.usage.total_tokens）
+	// 未配置时使用内置默认路径（OpenAI / Gemini / Anthropic）
+	TokenPaths []string `yaml:"token_paths" json:"token_paths"`
+
+	// 全局单价：每 1k tokens 的价格（默认 0，仅统计 token 不估算成本）
+	PricePer1KTokens float64 `yaml:"price_per_1k_tokens" json:"price_per_1k_tokens"`
+
+	// 价格币种（仅用于展示，默认 "USD"）
+	Currency string `yaml:"currency" json:"currency"`
+
+	// API 访问令牌（必须配置，未配置时 /api/usage 返回 503）
+	// 支持 USAGE_API_TOKEN 环境变量覆盖
+	APIToken string `yaml:"api_token" json:"-"`
+}
+
+// Normalize 规范化用量统计配置
+func (u *UsageConfig) Normalize() error {
+	if envToken := strings.TrimSpace(os.Getenv("USAGE_API_TOKEN")); envToken != "" {
+		u.APIToken = envToken
+	} else {
+		u.APIToken = strings.TrimSpace(u.APIToken)
+	}
+
+	paths, err := normalizeUsagePaths(u.TokenPaths)
+	if err != nil {
+		return fmt.Errorf("usage.token_paths: %w", err)
+	}
+	if len(paths) == 0 {
+		paths = append([]string(nil), defaultUsageTokenPaths...)
+	}
+	u.TokenPaths = paths
+
+	if u.PricePer1KTokens < 0 {
+		return fmt.Errorf("usage.price_per_1k_tokens 不能为负数，当前值: %g", u.PricePer1KTokens)
+	}
+
+	u.Currency = strings.ToUpper(strings.TrimSpace(u.Currency))
+	if u.Currency == "" {
+		u.Currency = "USD"
+	}
+	return nil
+}
+
+// normalizeUsagePaths 去除空白并校验路径格式
+func normalizeUsagePaths(paths []string) ([]string, error) {
+	result := make([]string, 0, len(paths))
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		for _, term := range strings.Split(p, "+") {
+			term = strings.TrimSpace(term)
+			if term == "" || strings.HasPrefix(term, ".") || strings.HasSuffix(term, ".") || strings.Contains(term, "..") {
+				return nil, fmt.Errorf("路径 '%s' 格式无效", p)
+			}
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// resolveMonitorUsage 解析监测项的用量统计参数（继承后调用）
+// 优先级：monitor.usage_token_paths / price_per_1k_tokens > 全局 usage 配置
+// 未启用用量统计时清空派生字段，探测器据此跳过解析
+func (c *AppConfig) resolveMonitorUsage(m *ServiceConfig) error {
+	m.UsagePaths = nil
+	m.UsagePrice = 0
+	if !c.Usage.Enabled {
+		return nil
+	}
+
+	paths, err := normalizeUsagePaths(m.UsageTokenPaths)
+	if err != nil {
+		return fmt.Errorf("usage_token_paths: %w", err)
+	}
+	if len(paths) == 0 {
+		paths = c.Usage.TokenPaths
+	}
+	m.UsagePaths = paths
+
+	m.UsagePrice = c.Usage.PricePer1KTokens
+	if m.PricePer1KTokens != nil {
+		if *m.PricePer1KTokens < 0 {
+			return fmt.Errorf("price_per_1k_tokens 不能为负数，当前值: %g", *m.PricePer1KTokens)
+		}
+		m.UsagePrice = *m.PricePer1KTokens
+	}
+	return nil
+}
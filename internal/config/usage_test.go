@@ -0,0 +1,88 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeResolvesMonitorUsage(t *testing.T) {
+	price := 0.5
+	cfg := &AppConfig{
+		Usage: UsageConfig{Enabled: true, PricePer1KTokens: 0.002},
+		Monitors: []ServiceConfig{
+			{
+				Provider:         "demo",
+				Service:          "cc",
+				Channel:          "vip",
+				Model:            "base",
+				URL:              "https://example.com",
+				Method:           "POST",
+				Category:         "public",
+				UsageTokenPaths:  []string{" usage.input_tokens + usage.output_tokens "},
+				PricePer1KTokens: &price,
+			},
+			{
+				Provider: "demo",
+				Service:  "cc",
+				Channel:  "vip",
+				Model:    "child",
+				Parent:   "demo/cc/vip",
+				Category: "public",
+			},
+			{
+				Provider: "other",
+				Service:  "cx",
+				URL:      "https://example.com",
+				Method:   "POST",
+				Category: "public",
+			},
+		},
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("Normalize() failed: %v", err)
+	}
+
+	if cfg.Usage.Currency != "USD" {
+		t.Fatalf("默认币种应为 USD, got=%s", cfg.Usage.Currency)
+	}
+	child := cfg.Monitors[1]
+	if !reflect.DeepEqual(child.UsagePaths, []string{"usage.input_tokens + usage.output_tokens"}) || child.UsagePrice != 0.5 {
+		t.Fatalf("子通道应继承父通道用量配置, paths=%v price=%v", child.UsagePaths, child.UsagePrice)
+	}
+	other := cfg.Monitors[2]
+	if !reflect.DeepEqual(other.UsagePaths, defaultUsageTokenPaths) || other.UsagePrice != 0.002 {
+		t.Fatalf("未覆盖的监测项应使用全局配置, paths=%v price=%v", other.UsagePaths, other.UsagePrice)
+	}
+}
+
+func TestNormalizeUsageDisabledClearsPaths(t *testing.T) {
+	cfg := &AppConfig{
+		Monitors: []ServiceConfig{
+			{
+				Provider:        "demo",
+				Service:         "cc",
+				URL:             "https://example.com",
+				Method:          "POST",
+				Category:        "public",
+				UsageTokenPaths: []string{"usage.total_tokens"},
+			},
+		},
+	}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("Normalize() failed: %v", err)
+	}
+	if len(cfg.Monitors[0].UsagePaths) != 0 {
+		t.Fatalf("usage 未启用时不应解析用量路径")
+	}
+}
+
+func TestUsageConfigRejectsInvalidPath(t *testing.T) {
+	u := UsageConfig{TokenPaths: []string{"usage..total"}}
+	if err := u.Normalize(); err == nil {
+		t.Fatalf("期望无效路径报错")
+	}
+}
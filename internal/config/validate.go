@@ -76,6 +76,16 @@ func (c *AppConfig) Validate() error {
 		return err
 	}
 
+	// 9. 命名空间校验
+	if err := c.validateNamespaces(); err != nil {
+		return err
+	}
+
+	// 10. 自定义分组校验
+	if err := c.validateGroups(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -315,6 +325,14 @@ func (c *AppConfig) validateMonitorFields() error {
 		}
 		// 注意：cold_reason 的有效性检查在 Normalize() 中进行（非致命，仅警告并清空）
 
+		// Type 枚举检查（可选字段，空值视为普通中转站监测项）
+		switch strings.ToLower(strings.TrimSpace(m.Type)) {
+		case "", MonitorTypeBaseline:
+			// 有效值
+		default:
+			return fmt.Errorf("monitor[%d]: type '%s' 无效，必须是 baseline（或留空）", i, m.Type)
+		}
+
 		// PriceMin/PriceMax 验证（可选字段）
 		if m.PriceMin != nil && *m.PriceMin < 0 {
 			return fmt.Errorf("monitor[%d]: price_min 不能为负数", i)
@@ -327,6 +345,11 @@ func (c *AppConfig) validateMonitorFields() error {
 			return fmt.Errorf("monitor[%d]: price_min 不能大于 price_max", i)
 		}
 
+		// Weight 验证（可选字段）
+		if m.Weight != nil && *m.Weight < 0 {
+			return fmt.Errorf("monitor[%d]: weight 不能为负数", i)
+		}
+
 		// ListedSince 验证（可选字段，格式必须为 "2006-01-02"）
 		if m.ListedSince != "" {
 			if _, err := time.Parse("2006-01-02", m.ListedSince); err != nil {
@@ -348,12 +371,24 @@ func (c *AppConfig) validateMonitorFields() error {
 			}
 		}
 
+		// ProviderLogo 验证（可选字段）
+		if m.ProviderLogo != "" {
+			if err := validateURL(m.ProviderLogo, "provider_logo"); err != nil {
+				return fmt.Errorf("monitor[%d]: %w", i, err)
+			}
+		}
+
 		// Proxy 验证（可选字段）
 		if trimmedProxy := strings.TrimSpace(m.Proxy); trimmedProxy != "" {
 			if err := validateProxyURL(trimmedProxy); err != nil {
 				return fmt.Errorf("monitor[%d]: %w", i, err)
 			}
 		}
+
+		// TLS 验证（可选字段）
+		if err := m.TLS.Validate(); err != nil {
+			return fmt.Errorf("monitor[%d]: %w", i, err)
+		}
 	}
 	return nil
 }
@@ -398,8 +433,35 @@ func (c *AppConfig) validateProviderConfigs() error {
 
 // validateBadgeConfigs 校验 Badge 相关配置
 func (c *AppConfig) validateBadgeConfigs() error {
-	// 验证 badges（全局徽标定义）
-	for id, bd := range c.BadgeDefs {
+	if err := validateBadgeDefs(c.BadgeDefs); err != nil {
+		return err
+	}
+	if err := validateBadgeProviders(c.BadgeProviders, c.BadgeDefs); err != nil {
+		return err
+	}
+
+	// 验证 monitors[].badges
+	for i, m := range c.Monitors {
+		for j, ref := range m.Badges {
+			refID := strings.TrimSpace(ref.ID)
+			if refID == "" {
+				return fmt.Errorf("monitors[%d].badges[%d]: id 不能为空", i, j)
+			}
+			// 检查用户配置和内置默认徽标
+			_, inUserDefs := c.BadgeDefs[refID]
+			_, inDefaultDefs := defaultBadgeDefs[refID]
+			if !inUserDefs && !inDefaultDefs {
+				return fmt.Errorf("monitors[%d].badges[%d]: 未找到徽标定义 '%s'", i, j, refID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateBadgeDefs 校验全局徽标定义（badge_definitions）
+func validateBadgeDefs(defs map[string]BadgeDef) error {
+	for id, bd := range defs {
 		if strings.TrimSpace(id) == "" {
 			return fmt.Errorf("badge_definitions: id 不能为空")
 		}
@@ -420,10 +482,13 @@ func (c *AppConfig) validateBadgeConfigs() error {
 			}
 		}
 	}
+	return nil
+}
 
-	// 验证 badge_providers
+// validateBadgeProviders 校验 badge_providers（引用的徽标需在 defs 或内置默认徽标中定义）
+func validateBadgeProviders(providers []BadgeProviderConfig, defs map[string]BadgeDef) error {
 	badgeProviderSet := make(map[string]struct{})
-	for i, bp := range c.BadgeProviders {
+	for i, bp := range providers {
 		provider := strings.ToLower(strings.TrimSpace(bp.Provider))
 		if provider == "" {
 			return fmt.Errorf("badge_providers[%d]: provider 不能为空", i)
@@ -438,29 +503,12 @@ func (c *AppConfig) validateBadgeConfigs() error {
 				return fmt.Errorf("badge_providers[%d].badges[%d]: id 不能为空", i, j)
 			}
 			// 检查用户配置和内置默认徽标
-			_, inUserDefs := c.BadgeDefs[refID]
+			_, inUserDefs := defs[refID]
 			_, inDefaultDefs := defaultBadgeDefs[refID]
 			if !inUserDefs && !inDefaultDefs {
 				return fmt.Errorf("badge_providers[%d].badges[%d]: 未找到徽标定义 '%s'", i, j, refID)
 			}
 		}
 	}
-
-	// 验证 monitors[].badges
-	for i, m := range c.Monitors {
-		for j, ref := range m.Badges {
-			refID := strings.TrimSpace(ref.ID)
-			if refID == "" {
-				return fmt.Errorf("monitors[%d].badges[%d]: id 不能为空", i, j)
-			}
-			// 检查用户配置和内置默认徽标
-			_, inUserDefs := c.BadgeDefs[refID]
-			_, inDefaultDefs := defaultBadgeDefs[refID]
-			if !inUserDefs && !inDefaultDefs {
-				return fmt.Errorf("monitors[%d].badges[%d]: 未找到徽标定义 '%s'", i, j, refID)
-			}
-		}
-	}
-
 	return nil
 }
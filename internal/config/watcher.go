@@ -15,13 +15,16 @@ import (
 
 // Watcher 配置文件监听器
 type Watcher struct {
-	loader       *Loader
-	filename     string
-	watcher      *fsnotify.Watcher
-	onReload     func(*AppConfig)
-	debounceTime time.Duration
-	watchMu      sync.Mutex
-	watchedDirs  map[string]struct{}
+	loader         *Loader
+	filename       string
+	watcher        *fsnotify.Watcher
+	onReload       func(*AppConfig)
+	onBadgesReload func(*AppConfig) // badges.yaml 变更回调（未设置时按完整配置重载）
+	debounceTime   time.Duration
+	reloadMu       sync.Mutex // 串行化完整重载与徽标重载
+	watchMu        sync.Mutex
+	watchedDirs    map[string]struct{}
+	includeDir     string // include_dir 绝对路径（受 watchMu 保护，热更新后可能变化）
 }
 
 // NewWatcher 创建配置监听器
@@ -40,11 +43,19 @@ func NewWatcher(loader *Loader, filename string, onReload func(*AppConfig)) (*Wa
 	}, nil
 }
 
+// SetBadgesReloadHandler 设置 badges.yaml 变更回调（需在 Start 前调用）
+// 设置后修改 badges.yaml 仅重新解析徽标并回调，不触发监测配置重载
+func (w *Watcher) SetBadgesReloadHandler(fn func(*AppConfig)) {
+	w.onBadgesReload = fn
+}
+
 // Start 启动监听（监听父目录以兼容不同编辑器）
 func (w *Watcher) Start(ctx context.Context) error {
 	// 监听父目录而非文件本身，避免编辑器 rename 导致监听失效
 	dir := filepath.Dir(w.filename)
 	targetFile := filepath.Clean(w.filename) // 归一化配置文件路径
+	overridesFile := filepath.Clean(OverridesPath(w.filename))
+	badgesFile := filepath.Clean(BadgesPath(w.filename))
 	if err := w.addWatch(dir); err != nil {
 		return err
 	}
@@ -58,10 +69,15 @@ func (w *Watcher) Start(ctx context.Context) error {
 		}
 	}
 
+	// include_dir 目录（多文件配置片段）
+	if err := w.watchIncludeDir(w.loader.GetIncludeDir()); err != nil {
+		return err
+	}
+
 	logger.Info("config", "开始监听配置文件", "file", w.filename, "dir", dir)
 
 	go func() {
-		var debounceTimer *time.Timer
+		var debounceTimer, badgesTimer *time.Timer
 		for {
 			select {
 			case <-ctx.Done():
@@ -74,16 +90,42 @@ func (w *Watcher) Start(ctx context.Context) error {
 					return
 				}
 
-				// 只关心目标配置文件和 data/ 目录下 JSON 的写入/创建/重命名事件
+				// 只关心目标配置文件、覆盖文件、data/ 目录下 JSON 和 include_dir 中 YAML 的写入/创建/重命名事件
 				eventPath := filepath.Clean(event.Name) // 归一化事件路径
-				isConfigFile := eventPath == targetFile
+
+				// badges.yaml 单独防抖、单独重载；删除时按完整配置重载以恢复 config.yaml 中的徽标
+				if eventPath == badgesFile && w.onBadgesReload != nil && event.Op&fsnotify.Remove == 0 {
+					if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+						if badgesTimer != nil {
+							badgesTimer.Stop()
+						}
+						badgesTimer = time.AfterFunc(w.debounceTime, func() {
+							logger.Info("config", "检测到徽标文件变更，正在重载徽标")
+							w.reloadBadges()
+						})
+					}
+					if event.Op&fsnotify.Rename != 0 {
+						if err := w.rewatchPath(eventPath); err != nil {
+							logger.Error("config", "重新监听目录失败", "error", err)
+						}
+					}
+					continue
+				}
+
+				isConfigFile := eventPath == targetFile || eventPath == overridesFile || eventPath == badgesFile
 				isDataFile := strings.HasPrefix(eventPath, dataDirPrefix)
-				if !isConfigFile && !isDataFile {
+				isIncludeFile := w.isIncludeFile(eventPath)
+				if !isConfigFile && !isDataFile && !isIncludeFile {
 					continue
 				}
 
 				// 监听 Write/Create/Rename 事件（vim/nano 等编辑器使用 rename 保存）
-				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				// include_dir 中删除片段文件、删除覆盖文件同样需要重载
+				reloadOps := fsnotify.Write | fsnotify.Create | fsnotify.Rename
+				if isIncludeFile || eventPath == overridesFile || eventPath == badgesFile {
+					reloadOps |= fsnotify.Remove
+				}
+				if event.Op&reloadOps != 0 {
 					// 防抖：延迟执行，避免编辑器多次写入
 					if debounceTimer != nil {
 						debounceTimer.Stop()
@@ -96,7 +138,7 @@ func (w *Watcher) Start(ctx context.Context) error {
 				}
 
 				// 处理 Remove/Rename：重新监听，避免 inode 变化后事件丢失
-				if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 && (isConfigFile || isDataFile) {
+				if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 && (isConfigFile || isDataFile || isIncludeFile) {
 					if err := w.rewatchPath(eventPath); err != nil {
 						logger.Error("config", "重新监听目录失败", "error", err)
 					}
@@ -117,6 +159,9 @@ func (w *Watcher) Start(ctx context.Context) error {
 
 // reload 重新加载配置
 func (w *Watcher) reload() {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
 	newConfig, err := w.loader.LoadOrRollback(w.filename)
 	if err != nil {
 		logger.Error("config", "重载失败", "error", err)
@@ -125,12 +170,33 @@ func (w *Watcher) reload() {
 
 	logger.Info("config", "热更新成功", "monitors", len(newConfig.Monitors))
 
+	// include_dir 可能在热更新中新增或变更
+	if err := w.watchIncludeDir(w.loader.GetIncludeDir()); err != nil {
+		logger.Error("config", "监听 include_dir 失败", "error", err)
+	}
+
 	// 回调通知
 	if w.onReload != nil {
 		w.onReload(newConfig)
 	}
 }
 
+// reloadBadges 仅重新加载 badges.yaml（失败时保持当前徽标）
+func (w *Watcher) reloadBadges() {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	newConfig, err := w.loader.ReloadBadges(w.filename)
+	if err != nil {
+		logger.Error("config", "徽标热更新失败，保持当前徽标", "error", err)
+		return
+	}
+
+	logger.Info("config", "徽标热更新成功",
+		"definitions", len(newConfig.BadgeDefs), "providers", len(newConfig.BadgeProviders))
+	w.onBadgesReload(newConfig)
+}
+
 // Stop 停止监听
 func (w *Watcher) Stop() error {
 	return w.watcher.Close()
@@ -170,3 +236,30 @@ func (w *Watcher) rewatchPath(path string) error {
 	}
 	return w.addWatch(filepath.Dir(path))
 }
+
+// watchIncludeDir 监听 include_dir 目录（目录不存在时跳过）
+func (w *Watcher) watchIncludeDir(dir string) error {
+	w.watchMu.Lock()
+	w.includeDir = dir
+	w.watchMu.Unlock()
+
+	if dir == "" {
+		return nil
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil
+	}
+	return w.addWatch(dir)
+}
+
+// isIncludeFile 判断事件路径是否为 include_dir 中的 YAML 片段
+func (w *Watcher) isIncludeFile(path string) bool {
+	w.watchMu.Lock()
+	dir := w.includeDir
+	w.watchMu.Unlock()
+
+	if dir == "" || filepath.Dir(path) != dir {
+		return false
+	}
+	return isIncludeFileName(filepath.Base(path))
+}
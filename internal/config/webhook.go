@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EventWebhookConfig 事件 Webhook 推送目标
+// 主服务在事件落库后直接 POST 到该地址（JSON 格式与 /api/events 的单个事件一致），
+// 简单集成无需部署 notifier；修改后需重启生效
+type EventWebhookConfig struct {
+	// 接收地址（http:// 或 https://）
+	URL string `yaml:"url" json:"url"`
+
+	// HMAC-SHA256 签名密钥（可选）
+	// 配置后请求头携带 X-RelayPulse-Timestamp 与 X-RelayPulse-Signature: sha256=hex(HMAC(secret, "<timestamp>.<body>"))
+	Secret string `yaml:"secret" json:"-"`
+
+	// 仅推送指定类型的事件（可选，空表示全部，如 ["DOWN", "UP"]）
+	Types []string `yaml:"types" json:"types"`
+
+	// 单次请求超时（默认 "10s"）
+	Timeout string `yaml:"timeout" json:"timeout"`
+
+	// 最大尝试次数（含首次，默认 5）；失败后按 1s、2s、4s… 指数退避（上限 5m），耗尽后写入死信
+	MaxAttempts int `yaml:"max_attempts" json:"max_attempts"`
+
+	TimeoutDuration time.Duration `yaml:"-" json:"-"`
+}
+
+// eventTypeNames 可订阅的事件类型（与 storage.EventType 取值一致）
+var eventTypeNames = map[string]bool{
+	"DOWN":           true,
+	"UP":             true,
+	"CERT_EXPIRING":  true,
+	"CONTENT_DRIFT":  true,
+	"DEGRADED_START": true,
+	"DEGRADED_END":   true,
+
+	"SCHEDULER_SATURATED": true,
+
+	"SLA_BREACH_WARNING": true,
+	"SLA_BREACHED":       true,
+
+	"BOARD_DEMOTED":  true,
+	"BOARD_PROMOTED": true,
+}
+
+// Normalize 规范化 Webhook 配置
+func (w *EventWebhookConfig) Normalize(index int) error {
+	field := fmt.Sprintf("events.webhooks[%d]", index)
+
+	w.URL = strings.TrimSpace(w.URL)
+	if w.URL == "" {
+		return fmt.Errorf("%s.url 不能为空", field)
+	}
+	if err := validateURL(w.URL, field+".url"); err != nil {
+		return err
+	}
+
+	for i, t := range w.Types {
+		t = strings.ToUpper(strings.TrimSpace(t))
+		if !eventTypeNames[t] {
+			return fmt.Errorf("%s.types 包含未知事件类型: %s", field, w.Types[i])
+		}
+		w.Types[i] = t
+	}
+
+	if w.Timeout == "" {
+		w.Timeout = "10s"
+	}
+	d, err := time.ParseDuration(w.Timeout)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("%s.timeout 无效: %s", field, w.Timeout)
+	}
+	w.TimeoutDuration = d
+
+	if w.MaxAttempts == 0 {
+		w.MaxAttempts = 5
+	}
+	if w.MaxAttempts < 1 || w.MaxAttempts > 20 {
+		return fmt.Errorf("%s.max_attempts 必须在 1-20 范围内，当前值: %d", field, w.MaxAttempts)
+	}
+	return nil
+}
+
+// EventOutboxConfig 事件发件箱（outbox）配置
+// 启用后事件与每个 Webhook 目标的待投递记录在同一事务中写入数据库，由后台任务轮询投递（至少一次），
+// 重试进度同样持久化；未启用时在内存中异步推送，进程退出时未完成的投递写入死信。修改后需重启生效
+type EventOutboxConfig struct {
+	// 是否启用（默认 false）
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// 轮询间隔（默认 "1s"），新事件落库后会立即唤醒投递，轮询用于重试与崩溃恢复
+	PollInterval string `yaml:"poll_interval" json:"poll_interval"`
+
+	// 单次领取的最大条数（默认 100）
+	BatchSize int `yaml:"batch_size" json:"batch_size"`
+
+	// 领取租约（默认 "2m"）：领取后租约期内其它实例不会重复投递；持有租约的进程崩溃后，记录在租约到期后被重新投递
+	Lease string `yaml:"lease" json:"lease"`
+
+	// 已完成记录的保留时长（默认 "72h"）
+	Retention string `yaml:"retention" json:"retention"`
+
+	PollIntervalDuration time.Duration `yaml:"-" json:"-"`
+	LeaseDuration        time.Duration `yaml:"-" json:"-"`
+	RetentionDuration    time.Duration `yaml:"-" json:"-"`
+}
+
+// Normalize 规范化事件发件箱配置（租约需长于任一 Webhook 的单次请求超时）
+func (c *EventOutboxConfig) Normalize(webhooks []EventWebhookConfig) error {
+	if c.PollInterval == "" {
+		c.PollInterval = "1s"
+	}
+	d, err := time.ParseDuration(c.PollInterval)
+	if err != nil || d < 100*time.Millisecond {
+		return fmt.Errorf("events.outbox.poll_interval 必须为 >= 100ms 的时长，当前值: %s", c.PollInterval)
+	}
+	c.PollIntervalDuration = d
+
+	if c.BatchSize == 0 {
+		c.BatchSize = 100
+	}
+	if c.BatchSize < 1 || c.BatchSize > 1000 {
+		return fmt.Errorf("events.outbox.batch_size 必须在 1-1000 范围内，当前值: %d", c.BatchSize)
+	}
+
+	if c.Lease == "" {
+		c.Lease = "2m"
+	}
+	d, err = time.ParseDuration(c.Lease)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("events.outbox.lease 无效: %s", c.Lease)
+	}
+	c.LeaseDuration = d
+	for i, w := range webhooks {
+		if w.TimeoutDuration >= c.LeaseDuration {
+			return fmt.Errorf("events.outbox.lease (%s) 必须大于 events.webhooks[%d].timeout (%s)", c.Lease, i, w.Timeout)
+		}
+	}
+
+	if c.Retention == "" {
+		c.Retention = "72h"
+	}
+	d, err = time.ParseDuration(c.Retention)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("events.outbox.retention 无效: %s", c.Retention)
+	}
+	c.RetentionDuration = d
+	return nil
+}
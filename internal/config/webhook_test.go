@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventWebhookConfigNormalize(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		w := EventWebhookConfig{URL: " https://hooks.example.com/relay ", Types: []string{"down", " UP "}}
+		if err := w.Normalize(0); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+		if w.URL != "https://hooks.example.com/relay" || w.TimeoutDuration != 10*time.Second || w.MaxAttempts != 5 {
+			t.Fatalf("unexpected defaults: %+v", w)
+		}
+		if w.Types[0] != "DOWN" || w.Types[1] != "UP" {
+			t.Fatalf("types not normalized: %v", w.Types)
+		}
+	})
+
+	invalid := map[string]EventWebhookConfig{
+		"empty url":        {},
+		"bad scheme":       {URL: "ftp://hooks.example.com"},
+		"unknown type":     {URL: "https://hooks.example.com", Types: []string{"FLAP"}},
+		"bad timeout":      {URL: "https://hooks.example.com", Timeout: "soon"},
+		"too many retries": {URL: "https://hooks.example.com", MaxAttempts: 21},
+	}
+	for name, w := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if err := w.Normalize(0); err == nil {
+				t.Fatalf("expected error for %+v", w)
+			}
+		})
+	}
+}
+
+func TestEventOutboxConfigNormalize(t *testing.T) {
+	var o EventOutboxConfig
+	if err := o.Normalize(nil); err != nil {
+		t.Fatalf("Normalize() failed: %v", err)
+	}
+	if o.PollIntervalDuration != time.Second || o.BatchSize != 100 || o.LeaseDuration != 2*time.Minute || o.RetentionDuration != 72*time.Hour {
+		t.Fatalf("unexpected defaults: %+v", o)
+	}
+
+	webhooks := []EventWebhookConfig{{URL: "https://hooks.example.com", Timeout: "30s", TimeoutDuration: 30 * time.Second}}
+	invalid := map[string]EventOutboxConfig{
+		"fast poll":       {PollInterval: "10ms"},
+		"bad batch":       {BatchSize: 1001},
+		"bad lease":       {Lease: "soon"},
+		"lease < timeout": {Lease: "30s"},
+		"bad retention":   {Retention: "0s"},
+	}
+	for name, o := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if err := o.Normalize(webhooks); err == nil {
+				t.Fatalf("expected error for %+v", o)
+			}
+		})
+	}
+}
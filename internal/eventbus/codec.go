@@ -0,0 +1,249 @@
+package eventbus
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"monitor/internal/storage"
+)
+
+// 消息类型（随消息头 type 下发，便于下游在同一主题混合消费时区分）
+const (
+	messageTypeEvent  = "status_event"
+	messageTypeRecord = "probe_record"
+)
+
+// message 待发布的一条消息
+type message struct {
+	topic string
+	key   []byte // 分区键：provider/service/channel/model，保证同一监测项有序
+	value []byte
+	id    string // 去重标识（NATS Nats-Msg-Id），如 event-123
+	kind  string // messageTypeEvent / messageTypeRecord
+}
+
+// EventMessage 状态事件的 JSON 消息体（字段与 /api/events 保持一致）
+type EventMessage struct {
+	ID              int64          `json:"id"`
+	Namespace       string         `json:"namespace,omitempty"`
+	Provider        string         `json:"provider"`
+	Service         string         `json:"service"`
+	Channel         string         `json:"channel,omitempty"`
+	Model           string         `json:"model,omitempty"`
+	Type            string         `json:"type"`
+	FromStatus      int            `json:"from_status"`
+	ToStatus        int            `json:"to_status"`
+	TriggerRecordID int64          `json:"trigger_record_id"`
+	ObservedAt      int64          `json:"observed_at"`
+	CreatedAt       int64          `json:"created_at"`
+	Meta            map[string]any `json:"meta,omitempty"`
+}
+
+// RecordMessage 探测记录的 JSON 消息体（字段与 /api/export 保持一致）
+type RecordMessage struct {
+	ID                int64  `json:"id"`
+	Namespace         string `json:"namespace,omitempty"`
+	Provider          string `json:"provider"`
+	Service           string `json:"service"`
+	Channel           string `json:"channel"`
+	Model             string `json:"model"`
+	Status            int    `json:"status"`
+	SubStatus         string `json:"sub_status"`
+	HttpCode          int    `json:"http_code"`
+	Latency           int    `json:"latency"`
+	Timestamp         int64  `json:"timestamp"`
+	DNSMs             *int   `json:"dns_ms"`
+	ConnectMs         *int   `json:"connect_ms"`
+	TLSMs             *int   `json:"tls_ms"`
+	TTFBMs            *int   `json:"ttfb_ms"`
+	Attempts          int    `json:"attempts"`
+	IPFamily          string `json:"ip_family"`
+	CertDaysRemaining *int   `json:"cert_days_remaining,omitempty"`
+}
+
+// codec 消息编码器
+type codec interface {
+	contentType() string
+	encodeEvent(e *storage.StatusEvent) ([]byte, error)
+	encodeRecord(r *storage.ProbeRecord) ([]byte, error)
+}
+
+func newCodec(format string) (codec, error) {
+	switch format {
+	case "json":
+		return jsonCodec{}, nil
+	case "protobuf":
+		return protobufCodec{}, nil
+	default:
+		return nil, fmt.Errorf("不支持的消息编码: %s", format)
+	}
+}
+
+// partitionKey 监测项分区键
+func partitionKey(provider, service, channel, model string) []byte {
+	return []byte(provider + "/" + service + "/" + channel + "/" + model)
+}
+
+// ===== JSON =====
+
+type jsonCodec struct{}
+
+func (jsonCodec) contentType() string { return "application/json" }
+
+func (jsonCodec) encodeEvent(e *storage.StatusEvent) ([]byte, error) {
+	return json.Marshal(EventMessage{
+		ID:              e.ID,
+		Namespace:       e.Namespace,
+		Provider:        e.Provider,
+		Service:         e.Service,
+		Channel:         e.Channel,
+		Model:           e.Model,
+		Type:            string(e.EventType),
+		FromStatus:      e.FromStatus,
+		ToStatus:        e.ToStatus,
+		TriggerRecordID: e.TriggerRecordID,
+		ObservedAt:      e.ObservedAt,
+		CreatedAt:       e.CreatedAt,
+		Meta:            e.Meta,
+	})
+}
+
+func (jsonCodec) encodeRecord(r *storage.ProbeRecord) ([]byte, error) {
+	return json.Marshal(RecordMessage{
+		ID:                r.ID,
+		Namespace:         r.Namespace,
+		Provider:          r.Provider,
+		Service:           r.Service,
+		Channel:           r.Channel,
+		Model:             r.Model,
+		Status:            r.Status,
+		SubStatus:         string(r.SubStatus),
+		HttpCode:          r.HttpCode,
+		Latency:           r.Latency,
+		Timestamp:         r.Timestamp,
+		DNSMs:             r.DNSMs,
+		ConnectMs:         r.ConnectMs,
+		TLSMs:             r.TLSMs,
+		TTFBMs:            r.TTFBMs,
+		Attempts:          r.Attempts,
+		IPFamily:          r.IPFamily,
+		CertDaysRemaining: r.CertDaysRemaining,
+	})
+}
+
+// ===== Protobuf（proto3，schema 见 docs/user/config.md 的 relaypulse.eventbus.v1） =====
+
+type protobufCodec struct{}
+
+func (protobufCodec) contentType() string { return "application/x-protobuf" }
+
+// encodeEvent 编码为 relaypulse.eventbus.v1.StatusEvent
+// meta 无固定结构，以 JSON 字符串放入 meta_json 字段
+func (protobufCodec) encodeEvent(e *storage.StatusEvent) ([]byte, error) {
+	var b []byte
+	b = appendInt64(b, 1, e.ID)
+	b = appendString(b, 2, e.Namespace)
+	b = appendString(b, 3, e.Provider)
+	b = appendString(b, 4, e.Service)
+	b = appendString(b, 5, e.Channel)
+	b = appendString(b, 6, e.Model)
+	b = appendString(b, 7, string(e.EventType))
+	b = appendInt64(b, 8, int64(e.FromStatus))
+	b = appendInt64(b, 9, int64(e.ToStatus))
+	b = appendInt64(b, 10, e.TriggerRecordID)
+	b = appendInt64(b, 11, e.ObservedAt)
+	b = appendInt64(b, 12, e.CreatedAt)
+	if len(e.Meta) > 0 {
+		meta, err := json.Marshal(e.Meta)
+		if err != nil {
+			return nil, fmt.Errorf("编码事件 meta 失败: %w", err)
+		}
+		b = appendString(b, 13, string(meta))
+	}
+	return b, nil
+}
+
+// encodeRecord 编码为 relaypulse.eventbus.v1.ProbeRecord
+// 连接阶段耗时与证书剩余天数为 optional 字段，nil 时不写入
+func (protobufCodec) encodeRecord(r *storage.ProbeRecord) ([]byte, error) {
+	var b []byte
+	b = appendInt64(b, 1, r.ID)
+	b = appendString(b, 2, r.Namespace)
+	b = appendString(b, 3, r.Provider)
+	b = appendString(b, 4, r.Service)
+	b = appendString(b, 5, r.Channel)
+	b = appendString(b, 6, r.Model)
+	b = appendInt64(b, 7, int64(r.Status))
+	b = appendString(b, 8, string(r.SubStatus))
+	b = appendInt64(b, 9, int64(r.HttpCode))
+	b = appendInt64(b, 10, int64(r.Latency))
+	b = appendInt64(b, 11, r.Timestamp)
+	b = appendOptionalInt(b, 12, r.DNSMs)
+	b = appendOptionalInt(b, 13, r.ConnectMs)
+	b = appendOptionalInt(b, 14, r.TLSMs)
+	b = appendOptionalInt(b, 15, r.TTFBMs)
+	b = appendInt64(b, 16, int64(r.Attempts))
+	b = appendOptionalInt(b, 17, r.CertDaysRemaining)
+	b = appendString(b, 18, r.IPFamily)
+	return b, nil
+}
+
+// appendInt64 写入 int32/int64 标量字段（proto3 零值不写入，负数按 64 位补码编码）
+func appendInt64(b []byte, num protowire.Number, v int64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(v))
+}
+
+// appendOptionalInt 写入 optional int32 字段（有值时即使为 0 也写入）
+func appendOptionalInt(b []byte, num protowire.Number, v *int) []byte {
+	if v == nil {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(int64(*v)))
+}
+
+// appendString 写入 string 字段（proto3 空串不写入）
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+// eventMessage 构造状态事件消息
+func eventMessage(c codec, topic string, e *storage.StatusEvent) (message, error) {
+	value, err := c.encodeEvent(e)
+	if err != nil {
+		return message{}, err
+	}
+	return message{
+		topic: topic,
+		key:   partitionKey(e.Provider, e.Service, e.Channel, e.Model),
+		value: value,
+		id:    "event-" + strconv.FormatInt(e.ID, 10),
+		kind:  messageTypeEvent,
+	}, nil
+}
+
+// recordMessage 构造探测记录消息
+func recordMessage(c codec, topic string, r *storage.ProbeRecord) (message, error) {
+	value, err := c.encodeRecord(r)
+	if err != nil {
+		return message{}, err
+	}
+	return message{
+		topic: topic,
+		key:   partitionKey(r.Provider, r.Service, r.Channel, r.Model),
+		value: value,
+		id:    "record-" + strconv.FormatInt(r.ID, 10),
+		kind:  messageTypeRecord,
+	}, nil
+}
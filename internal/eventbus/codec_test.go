@@ -0,0 +1,111 @@
+package eventbus
+
+import (
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"monitor/internal/storage"
+)
+
+// decodeProto 将 protobuf 消息解码为 字段号 -> 原始值（varint 为 uint64，bytes 为 string）
+func decodeProto(t *testing.T, b []byte) map[protowire.Number]any {
+	t.Helper()
+	fields := make(map[protowire.Number]any)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("invalid tag: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		switch typ {
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				t.Fatalf("invalid varint: %v", protowire.ParseError(n))
+			}
+			fields[num] = v
+			b = b[n:]
+		case protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				t.Fatalf("invalid bytes: %v", protowire.ParseError(n))
+			}
+			fields[num] = v
+			b = b[n:]
+		default:
+			t.Fatalf("unexpected wire type %d for field %d", typ, num)
+		}
+	}
+	return fields
+}
+
+func TestProtobufCodec(t *testing.T) {
+	c := protobufCodec{}
+
+	t.Run("event", func(t *testing.T) {
+		b, err := c.encodeEvent(&storage.StatusEvent{
+			ID: 42, Provider: "p", Service: "cc", Model: "m",
+			EventType: storage.EventTypeDown, FromStatus: 1, ToStatus: 0,
+			ObservedAt: 1700000000, Meta: map[string]any{"http_code": 502},
+		})
+		if err != nil {
+			t.Fatalf("encodeEvent failed: %v", err)
+		}
+		f := decodeProto(t, b)
+		if f[1] != uint64(42) || f[3] != "p" || f[4] != "cc" || f[6] != "m" || f[7] != "DOWN" {
+			t.Fatalf("unexpected fields: %v", f)
+		}
+		if _, ok := f[9]; ok {
+			t.Fatalf("zero to_status should be omitted: %v", f)
+		}
+		if _, ok := f[5]; ok {
+			t.Fatalf("empty channel should be omitted: %v", f)
+		}
+		if f[13] != `{"http_code":502}` {
+			t.Fatalf("unexpected meta_json: %v", f[13])
+		}
+	})
+
+	t.Run("record optional fields", func(t *testing.T) {
+		zero, dns := 0, 12
+		b, err := c.encodeRecord(&storage.ProbeRecord{
+			ID: 7, Provider: "p", Service: "cc", Status: 1, Latency: 300,
+			DNSMs: &dns, TLSMs: &zero, CertDaysRemaining: func() *int { v := -3; return &v }(),
+		})
+		if err != nil {
+			t.Fatalf("encodeRecord failed: %v", err)
+		}
+		f := decodeProto(t, b)
+		if f[12] != uint64(12) {
+			t.Fatalf("dns_ms = %v, want 12", f[12])
+		}
+		if v, ok := f[14]; !ok || v != uint64(0) {
+			t.Fatalf("optional tls_ms=0 should be present, got %v (present=%v)", v, ok)
+		}
+		if _, ok := f[13]; ok {
+			t.Fatalf("nil connect_ms should be omitted: %v", f)
+		}
+		if got := int32(f[17].(uint64)); got != -3 {
+			t.Fatalf("cert_days_remaining = %d, want -3", got)
+		}
+	})
+}
+
+func TestJSONCodecRecord(t *testing.T) {
+	b, err := jsonCodec{}.encodeRecord(&storage.ProbeRecord{ID: 1, Provider: "p", Service: "cc", SubStatus: storage.SubStatusSlowLatency})
+	if err != nil {
+		t.Fatalf("encodeRecord failed: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if got["sub_status"] != string(storage.SubStatusSlowLatency) || got["dns_ms"] != nil {
+		t.Fatalf("unexpected message: %s", b)
+	}
+	if _, ok := got["cert_days_remaining"]; ok {
+		t.Fatalf("cert_days_remaining should be omitted when unknown: %s", b)
+	}
+}
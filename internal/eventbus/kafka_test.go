@@ -0,0 +1,151 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"monitor/internal/config"
+)
+
+const (
+	testEventTopic  = "relay-pulse.events"
+	testRecordTopic = "relay-pulse.records"
+)
+
+// newKafkaTestCluster 启动进程内 Kafka 集群（3 分区的事件与记录主题）
+func newKafkaTestCluster(t *testing.T, opts ...kfake.Opt) *kfake.Cluster {
+	t.Helper()
+	c, err := kfake.NewCluster(append([]kfake.Opt{kfake.NumBrokers(1), kfake.SeedTopics(3, testEventTopic, testRecordTopic)}, opts...)...)
+	if err != nil {
+		t.Fatalf("kfake.NewCluster: %v", err)
+	}
+	t.Cleanup(c.Close)
+	return c
+}
+
+// consumeKafka 从头读取主题中的 n 条消息
+func consumeKafka(t *testing.T, c *kfake.Cluster, n int, topics ...string) []*kgo.Record {
+	t.Helper()
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(c.ListenAddrs()...),
+		kgo.ConsumeTopics(topics...),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+	)
+	if err != nil {
+		t.Fatalf("kgo.NewClient: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var records []*kgo.Record
+	for len(records) < n {
+		fetches := client.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("consumed %d records, want %d", len(records), n)
+		}
+		fetches.EachRecord(func(r *kgo.Record) { records = append(records, r) })
+	}
+	return records
+}
+
+func recordHeader(r *kgo.Record, key string) string {
+	for _, h := range r.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func TestKafkaSinkPublish(t *testing.T) {
+	for _, acks := range []string{"all", "leader"} {
+		t.Run("acks="+acks, func(t *testing.T) {
+			cluster := newKafkaTestCluster(t)
+			sink, err := newKafkaSink(config.EventBusConfig{
+				Brokers:         cluster.ListenAddrs(),
+				Acks:            acks,
+				TimeoutDuration: 5 * time.Second,
+			})
+			if err != nil {
+				t.Fatalf("newKafkaSink failed: %v", err)
+			}
+			defer sink.close()
+
+			var msgs []message
+			for i := 1; i <= 6; i++ {
+				msgs = append(msgs, message{
+					topic: testRecordTopic,
+					key:   []byte(fmt.Sprintf("relay/cc/ch%d/", i%2)),
+					value: []byte(fmt.Sprint(i)),
+					id:    fmt.Sprintf("record-%d", i),
+					kind:  messageTypeRecord,
+				})
+			}
+			msgs = append(msgs, message{topic: testEventTopic, key: []byte("relay/cc/ch0/"), value: []byte("7"), id: "event-7", kind: messageTypeEvent})
+			if err := sink.publish(context.Background(), msgs); err != nil {
+				t.Fatalf("publish failed: %v", err)
+			}
+
+			records := consumeKafka(t, cluster, len(msgs), testEventTopic, testRecordTopic)
+			partitions := make(map[string]int32)
+			for _, r := range records {
+				wantKind := messageTypeRecord
+				if r.Topic == testEventTopic {
+					wantKind = messageTypeEvent
+				}
+				if recordHeader(r, "type") != wantKind || !strings.HasSuffix(recordHeader(r, "id"), "-"+string(r.Value)) {
+					t.Fatalf("unexpected headers for %s/%s: %v", r.Topic, r.Value, r.Headers)
+				}
+				// 同一主题内同一监测项的消息落在同一分区
+				k := r.Topic + "|" + string(r.Key)
+				if p, ok := partitions[k]; ok && p != r.Partition {
+					t.Fatalf("key %s spread across partitions %d and %d", k, p, r.Partition)
+				}
+				partitions[k] = r.Partition
+			}
+		})
+	}
+}
+
+func TestKafkaSinkSASL(t *testing.T) {
+	cluster := newKafkaTestCluster(t, kfake.EnableSASL(), kfake.Superuser("SCRAM-SHA-512", "relay", "s3cret"))
+
+	cases := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{"valid credentials", "s3cret", false},
+		{"wrong password", "wrong", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sink, err := newKafkaSink(config.EventBusConfig{
+				Brokers:         cluster.ListenAddrs(),
+				Acks:            "all",
+				SASLMechanism:   "scram-sha-512",
+				Username:        "relay",
+				Password:        tc.password,
+				TimeoutDuration: 2 * time.Second,
+			})
+			if err != nil {
+				t.Fatalf("newKafkaSink failed: %v", err)
+			}
+			defer sink.close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+			err = sink.publish(ctx, []message{{topic: testEventTopic, key: []byte("a"), value: []byte("1"), id: "event-1", kind: messageTypeEvent}})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("publish error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package eventbus
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+)
+
+// natsSink 基于 nats.go 的发布客户端：消息带 Nats-Msg-Id 头（JetStream 在去重窗口内按此丢弃重复发布），
+// JetStream 模式下异步发布整批消息后逐条等待 stream 确认
+type natsSink struct {
+	conn    *nats.Conn
+	js      jetstream.JetStream // core NATS 模式下为 nil
+	timeout time.Duration
+}
+
+func newNATSSink(cfg config.EventBusConfig) (*natsSink, error) {
+	opts := []nats.Option{
+		nats.Name(clientID),
+		nats.Timeout(cfg.TimeoutDuration),
+		// 启动时连不上不影响服务启动，后台持续重连；断线期间不缓冲，发布直接失败并计数
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.ReconnectBufSize(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				logger.Warn("eventbus", "NATS 连接断开，后台重连中", "error", err)
+			}
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			logger.Info("eventbus", "NATS 已重新连接", "server", nc.ConnectedUrlRedacted())
+		}),
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+			logger.Warn("eventbus", "NATS 异步错误", "error", err)
+		}),
+	}
+	// 显式配置的凭据优先，否则使用地址中的 user:pass@ / token@
+	if cfg.Username != "" {
+		opts = append(opts, nats.UserInfo(cfg.Username, cfg.Password))
+	}
+	if cfg.Token != "" {
+		opts = append(opts, nats.Token(cfg.Token))
+	}
+	if cfg.TLS {
+		opts = append(opts, nats.Secure(&tls.Config{MinVersion: tls.VersionTLS12}))
+	}
+
+	conn, err := nats.Connect(cfg.URL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("创建 NATS 连接失败: %w", err)
+	}
+	s := &natsSink{conn: conn, timeout: cfg.TimeoutDuration}
+	if cfg.UseJetStream() {
+		var jsOpts []jetstream.JetStreamOpt
+		if cfg.BatchSize > 0 {
+			// 一批消息全部异步发出后再等待确认，待确认上限不能小于批大小
+			jsOpts = append(jsOpts, jetstream.WithPublishAsyncMaxPending(cfg.BatchSize))
+		}
+		if s.js, err = jetstream.New(conn, jsOpts...); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("创建 JetStream 上下文失败: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// natsMsg 构造带去重与类型头的 NATS 消息
+func natsMsg(m message) *nats.Msg {
+	msg := nats.NewMsg(m.topic)
+	msg.Header.Set(nats.MsgIdHdr, m.id)
+	msg.Header.Set("Relay-Pulse-Type", m.kind)
+	msg.Data = m.value
+	return msg
+}
+
+// publish 发布一批消息并等待确认
+// core NATS 以 flush（PING/PONG）确认服务端已处理；JetStream 逐条等待 stream 的 PubAck
+func (n *natsSink) publish(ctx context.Context, msgs []message) error {
+	if n.js == nil {
+		for _, m := range msgs {
+			if err := n.conn.PublishMsg(natsMsg(m)); err != nil {
+				return fmt.Errorf("发送 NATS 消息失败: %w", err)
+			}
+		}
+		// flush 要求 ctx 带截止时间，取与单次请求超时中较早者
+		ctx, cancel := context.WithTimeout(ctx, n.timeout)
+		defer cancel()
+		if err := n.conn.FlushWithContext(ctx); err != nil {
+			return fmt.Errorf("等待 NATS 确认失败: %w", err)
+		}
+		return nil
+	}
+
+	futures := make([]jetstream.PubAckFuture, 0, len(msgs))
+	for _, m := range msgs {
+		f, err := n.js.PublishMsgAsync(natsMsg(m))
+		if err != nil {
+			return fmt.Errorf("发送 NATS 消息失败: %w", err)
+		}
+		futures = append(futures, f)
+	}
+
+	var failed int
+	var firstErr error
+	for i, f := range futures {
+		select {
+		case <-f.Ok():
+		case err := <-f.Err():
+			failed++
+			if firstErr == nil {
+				firstErr = fmt.Errorf("JetStream 拒绝消息（subject=%s）: %w", msgs[i].topic, err)
+			}
+		case <-ctx.Done():
+			return fmt.Errorf("等待 JetStream 确认超时: %w", ctx.Err())
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d 条消息未被确认: %w", failed, firstErr)
+	}
+	return nil
+}
+
+func (n *natsSink) close() error {
+	n.conn.Close()
+	return nil
+}
@@ -0,0 +1,177 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"monitor/internal/config"
+)
+
+// newNATSTestServer 启动进程内 NATS 服务端（启用 JetStream），configure 可调整鉴权等选项
+func newNATSTestServer(t *testing.T, configure func(*server.Options)) *server.Server {
+	t.Helper()
+	opts := &server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	}
+	if configure != nil {
+		configure(opts)
+	}
+	s, err := server.NewServer(opts)
+	if err != nil {
+		t.Fatalf("server.NewServer: %v", err)
+	}
+	go s.Start()
+	if !s.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server not ready")
+	}
+	t.Cleanup(s.Shutdown)
+	return s
+}
+
+func newTestNATSSink(t *testing.T, cfg config.EventBusConfig) *natsSink {
+	t.Helper()
+	cfg.TimeoutDuration = 2 * time.Second
+	sink, err := newNATSSink(cfg)
+	if err != nil {
+		t.Fatalf("newNATSSink failed: %v", err)
+	}
+	t.Cleanup(func() { sink.close() })
+	return sink
+}
+
+func TestNATSSinkJetStream(t *testing.T) {
+	srv := newNATSTestServer(t, nil)
+	ctx := context.Background()
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("nats.Connect: %v", err)
+	}
+	defer nc.Close()
+	js, err := jetstream.New(nc)
+	if err != nil {
+		t.Fatalf("jetstream.New: %v", err)
+	}
+	stream, err := js.CreateStream(ctx, jetstream.StreamConfig{Name: "RELAY", Subjects: []string{"relay-pulse.>"}, Duplicates: time.Minute})
+	if err != nil {
+		t.Fatalf("CreateStream: %v", err)
+	}
+	streamMsgs := func() uint64 {
+		t.Helper()
+		info, err := stream.Info(ctx)
+		if err != nil {
+			t.Fatalf("stream info: %v", err)
+		}
+		return info.State.Msgs
+	}
+
+	sink := newTestNATSSink(t, config.EventBusConfig{URL: srv.ClientURL(), BatchSize: 10})
+	msgs := []message{
+		{topic: "relay-pulse.events", value: []byte("1"), id: "event-1", kind: messageTypeEvent},
+		{topic: "relay-pulse.records", value: []byte("2"), id: "record-2", kind: messageTypeRecord},
+	}
+	if err := sink.publish(ctx, msgs); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+	if got := streamMsgs(); got != 2 {
+		t.Fatalf("stream messages = %d, want 2", got)
+	}
+	stored, err := stream.GetMsg(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetMsg: %v", err)
+	}
+	if stored.Header.Get(nats.MsgIdHdr) != "record-2" || stored.Header.Get("Relay-Pulse-Type") != messageTypeRecord {
+		t.Fatalf("unexpected headers: %v", stored.Header)
+	}
+
+	// 相同 Nats-Msg-Id 在去重窗口内重复发布时被 stream 丢弃
+	if err := sink.publish(ctx, msgs[:1]); err != nil {
+		t.Fatalf("republish failed: %v", err)
+	}
+	if got := streamMsgs(); got != 2 {
+		t.Fatalf("stream messages after duplicate = %d, want 2", got)
+	}
+
+	// 没有 stream 捕获的 subject 返回错误，但连接保持可用
+	err = sink.publish(ctx, []message{{topic: "nostream.x", value: []byte("3"), id: "event-3", kind: messageTypeEvent}})
+	if !errors.Is(err, jetstream.ErrNoStreamResponse) {
+		t.Fatalf("expected no stream response error, got %v", err)
+	}
+	if err := sink.publish(ctx, []message{{topic: "relay-pulse.events", value: []byte("4"), id: "event-4", kind: messageTypeEvent}}); err != nil {
+		t.Fatalf("publish after rejection failed: %v", err)
+	}
+	if got := streamMsgs(); got != 3 {
+		t.Fatalf("stream messages = %d, want 3", got)
+	}
+}
+
+func TestNATSSinkCoreWithAuth(t *testing.T) {
+	srv := newNATSTestServer(t, func(o *server.Options) {
+		o.Username, o.Password = "relay", "s3cret"
+	})
+
+	nc, err := nats.Connect(srv.ClientURL(), nats.UserInfo("relay", "s3cret"))
+	if err != nil {
+		t.Fatalf("nats.Connect: %v", err)
+	}
+	defer nc.Close()
+	sub, err := nc.SubscribeSync("relay-pulse.events")
+	if err != nil {
+		t.Fatalf("SubscribeSync: %v", err)
+	}
+	if err := nc.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	jetStream := false
+	sink := newTestNATSSink(t, config.EventBusConfig{URL: srv.ClientURL(), Username: "relay", Password: "s3cret", JetStream: &jetStream})
+	var msgs []message
+	for i := 1; i <= 3; i++ {
+		msgs = append(msgs, message{topic: "relay-pulse.events", value: []byte(fmt.Sprint(i)), id: fmt.Sprintf("event-%d", i), kind: messageTypeEvent})
+	}
+	if err := sink.publish(context.Background(), msgs); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+	for _, want := range msgs {
+		got, err := sub.NextMsg(time.Second)
+		if err != nil {
+			t.Fatalf("NextMsg: %v", err)
+		}
+		if string(got.Data) != string(want.value) || got.Header.Get(nats.MsgIdHdr) != want.id {
+			t.Fatalf("got message %q id=%s, want %q id=%s", got.Data, got.Header.Get(nats.MsgIdHdr), want.value, want.id)
+		}
+	}
+}
+
+func TestNATSSinkUnavailableServer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	// 服务端不可用时仍可创建（后台重连），发布直接返回错误而不是缓冲等待
+	for _, jetStream := range []bool{true, false} {
+		sink := newTestNATSSink(t, config.EventBusConfig{URL: "nats://" + addr, JetStream: &jetStream, BatchSize: 10})
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err := sink.publish(ctx, []message{{topic: "relay-pulse.events", value: []byte("1"), id: "event-1", kind: messageTypeEvent}})
+		cancel()
+		if err == nil {
+			t.Fatalf("jetstream=%v: expected publish error while disconnected", jetStream)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// clientID 连接 Kafka / NATS 时上报的客户端名
+const clientID = "relay-pulse"
+
+// sink 消息系统客户端（Kafka / NATS）
+type sink interface {
+	// publish 同步发布一批消息，返回时全部消息已被消息系统确认（或返回错误）
+	publish(ctx context.Context, msgs []message) error
+	close() error
+}
+
+// Publisher 事件总线发布器
+// 状态事件与探测记录先进入内存缓冲，由后台任务按批发布；缓冲满或发布失败时丢弃并计数（发布不能拖慢探测）
+type Publisher struct {
+	config      config.EventBusConfig
+	codec       codec
+	sink        sink
+	eventTopic  string
+	recordTopic string
+
+	mu      sync.RWMutex // 保护 closed 与 queue 的关闭
+	closed  bool
+	queue   chan message
+	done    chan struct{}
+	dropped atomic.Int64 // 因缓冲满被丢弃的消息数
+	failed  atomic.Int64 // 因发布失败被丢弃的消息数
+}
+
+// New 创建事件总线发布器并启动后台发布任务
+func New(cfg config.EventBusConfig) (*Publisher, error) {
+	c, err := newCodec(cfg.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	var s sink
+	switch cfg.Type {
+	case "kafka":
+		s, err = newKafkaSink(cfg)
+	case "nats":
+		s, err = newNATSSink(cfg)
+	default:
+		err = fmt.Errorf("不支持的事件总线类型: %s", cfg.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newPublisher(cfg, c, s), nil
+}
+
+func newPublisher(cfg config.EventBusConfig, c codec, s sink) *Publisher {
+	p := &Publisher{
+		config:      cfg,
+		codec:       c,
+		sink:        s,
+		eventTopic:  cfg.EventTopic,
+		recordTopic: cfg.RecordTopic,
+		queue:       make(chan message, cfg.QueueSize),
+		done:        make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// PublishEvent 提交一条已持久化的状态事件（非阻塞，p 为 nil 时忽略）
+func (p *Publisher) PublishEvent(e *storage.StatusEvent) {
+	if p == nil || e == nil || e.ID == 0 {
+		return
+	}
+	msg, err := eventMessage(p.codec, p.eventTopic, e)
+	if err != nil {
+		logger.Warn("eventbus", "编码状态事件失败", "event_id", e.ID, "error", err)
+		return
+	}
+	p.enqueue(msg)
+}
+
+// PublishRecord 提交一条已持久化的探测记录（非阻塞，p 为 nil 或未配置 record_topic 时忽略）
+func (p *Publisher) PublishRecord(r *storage.ProbeRecord) {
+	if p == nil || r == nil || p.recordTopic == "" {
+		return
+	}
+	msg, err := recordMessage(p.codec, p.recordTopic, r)
+	if err != nil {
+		logger.Warn("eventbus", "编码探测记录失败", "record_id", r.ID, "error", err)
+		return
+	}
+	p.enqueue(msg)
+}
+
+// enqueue 将消息放入缓冲（非阻塞）
+func (p *Publisher) enqueue(msg message) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return
+	}
+	select {
+	case p.queue <- msg:
+	default:
+		p.dropped.Add(1)
+	}
+}
+
+// run 后台按批发布，队列关闭后发布剩余消息并退出
+func (p *Publisher) run() {
+	defer close(p.done)
+
+	logger.Info("eventbus", "事件总线发布任务已启动",
+		"type", p.config.Type,
+		"format", p.config.Format,
+		"event_topic", p.eventTopic,
+		"record_topic", p.recordTopic)
+
+	ticker := time.NewTicker(p.config.FlushIntervalDuration)
+	defer ticker.Stop()
+
+	batch := make([]message, 0, p.config.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), p.config.TimeoutDuration)
+		err := p.sink.publish(ctx, batch)
+		cancel()
+		if err != nil {
+			p.failed.Add(int64(len(batch)))
+			logger.Warn("eventbus", "发布消息失败，本批消息已丢弃", "messages", len(batch), "error", err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case msg, ok := <-p.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, msg)
+			if len(batch) >= p.config.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+			if dropped := p.dropped.Swap(0); dropped > 0 {
+				logger.Warn("eventbus", "事件总线缓冲已满，部分消息被丢弃", "dropped", dropped)
+			}
+		}
+	}
+}
+
+// Stop 关闭缓冲并等待剩余消息发布完成（受 ctx 截止时间约束），随后断开连接
+func (p *Publisher) Stop(ctx context.Context) error {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	var err error
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		err = fmt.Errorf("等待事件总线消息发布超时: %w", ctx.Err())
+	}
+	if cerr := p.sink.close(); cerr != nil && err == nil {
+		err = cerr
+	}
+	if failed := p.failed.Load(); failed > 0 {
+		logger.Warn("eventbus", "事件总线运行期间有消息发布失败", "failed", failed)
+	}
+	return err
+}
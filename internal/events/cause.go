@@ -0,0 +1,79 @@
+package events
+
+import (
+	"time"
+
+	"monitor/internal/baseline"
+	"monitor/internal/storage"
+)
+
+// 事件可能原因（写入 DOWN 事件 meta.cause）
+const (
+	CauseAuthExpired    = "auth_expired"    // 认证失败（401/403）：Key 过期、额度耗尽或被封禁
+	CauseUpstreamOutage = "upstream_outage" // 上游故障：官方基线同样异常，或网关错误（502/504）
+	CauseRateLimited    = "rate_limited"    // 限流（429）
+	CauseNetwork        = "network"         // 网络错误（连接失败、超时）
+	CauseContentChange  = "content_change"  // 响应内容/请求格式变化（内容校验失败、400）
+)
+
+const (
+	// causeWindow 分类时回看的探测记录时间窗口
+	causeWindow = 15 * time.Minute
+
+	// causeMinShare 主导原因在失败记录中的最低占比
+	causeMinShare = 0.5
+)
+
+// ClassifyCause 根据近期失败记录的 sub_status / http_code 分布与官方基线归因推断 DOWN 事件的可能原因
+// 基线同样异常时直接归为上游故障；否则取失败记录中占比不低于 50% 的原因，无法判断时返回空
+func ClassifyCause(records []*storage.ProbeRecord, correlation *baseline.Correlation) string {
+	if correlation != nil && correlation.Scope == baseline.ScopeUpstream {
+		return CauseUpstreamOutage
+	}
+
+	counts := make(map[string]int)
+	failures := 0
+	for _, r := range records {
+		if r.Status != 0 {
+			continue
+		}
+		failures++
+		if cause := recordCause(r); cause != "" {
+			counts[cause]++
+		}
+	}
+	if failures == 0 {
+		return ""
+	}
+
+	best, bestCount := "", 0
+	for _, cause := range []string{CauseAuthExpired, CauseRateLimited, CauseNetwork, CauseContentChange, CauseUpstreamOutage} {
+		if counts[cause] > bestCount {
+			best, bestCount = cause, counts[cause]
+		}
+	}
+	if float64(bestCount) < causeMinShare*float64(failures) {
+		return ""
+	}
+	return best
+}
+
+// recordCause 单条失败记录对应的原因
+func recordCause(r *storage.ProbeRecord) string {
+	switch r.SubStatus {
+	case storage.SubStatusAuthError:
+		return CauseAuthExpired
+	case storage.SubStatusRateLimit:
+		return CauseRateLimited
+	case storage.SubStatusNetworkError, storage.SubStatusIPv6OnlyFailure:
+		return CauseNetwork
+	case storage.SubStatusContentMismatch, storage.SubStatusInvalidRequest:
+		return CauseContentChange
+	case storage.SubStatusServerError:
+		// 502/504 表示中转站到上游的请求失败
+		if r.HttpCode == 502 || r.HttpCode == 504 {
+			return CauseUpstreamOutage
+		}
+	}
+	return ""
+}
@@ -0,0 +1,40 @@
+package events
+
+import (
+	"testing"
+
+	"monitor/internal/baseline"
+	"monitor/internal/storage"
+)
+
+func TestClassifyCause(t *testing.T) {
+	t.Parallel()
+
+	fail := func(sub storage.SubStatus, code int) *storage.ProbeRecord {
+		return &storage.ProbeRecord{Status: 0, SubStatus: sub, HttpCode: code}
+	}
+	ok := &storage.ProbeRecord{Status: 1}
+
+	tests := []struct {
+		name        string
+		records     []*storage.ProbeRecord
+		correlation *baseline.Correlation
+		want        string
+	}{
+		{"认证失败占多数", []*storage.ProbeRecord{ok, fail(storage.SubStatusAuthError, 401), fail(storage.SubStatusAuthError, 403), fail(storage.SubStatusNetworkError, 0)}, nil, CauseAuthExpired},
+		{"限流", []*storage.ProbeRecord{fail(storage.SubStatusRateLimit, 429), fail(storage.SubStatusRateLimit, 429)}, nil, CauseRateLimited},
+		{"网络错误", []*storage.ProbeRecord{fail(storage.SubStatusNetworkError, 0)}, nil, CauseNetwork},
+		{"内容校验失败", []*storage.ProbeRecord{fail(storage.SubStatusContentMismatch, 200), fail(storage.SubStatusInvalidRequest, 400)}, nil, CauseContentChange},
+		{"网关错误视为上游故障", []*storage.ProbeRecord{fail(storage.SubStatusServerError, 502), fail(storage.SubStatusServerError, 504)}, nil, CauseUpstreamOutage},
+		{"普通 5xx 无法判断", []*storage.ProbeRecord{fail(storage.SubStatusServerError, 500)}, nil, ""},
+		{"无主导原因", []*storage.ProbeRecord{fail(storage.SubStatusAuthError, 401), fail(storage.SubStatusNetworkError, 0), fail(storage.SubStatusServerError, 500)}, nil, ""},
+		{"基线同样异常优先", []*storage.ProbeRecord{fail(storage.SubStatusAuthError, 401)}, &baseline.Correlation{Scope: baseline.ScopeUpstream}, CauseUpstreamOutage},
+		{"基线正常不影响分类", []*storage.ProbeRecord{fail(storage.SubStatusRateLimit, 429)}, &baseline.Correlation{Scope: baseline.ScopeRelay}, CauseRateLimited},
+		{"无失败记录", []*storage.ProbeRecord{ok}, nil, ""},
+	}
+	for _, tt := range tests {
+		if got := ClassifyCause(tt.records, tt.correlation); got != tt.want {
+			t.Errorf("%s: ClassifyCause() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
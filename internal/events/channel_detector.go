@@ -195,6 +195,7 @@ func (d *ChannelDetector) detectStateChangeIncremental(
 			// 首次初始化时，如果第一个模型就是 DOWN，需要触发通道 DOWN
 			if prevStable == 1 || newModelStable == 0 {
 				event = &StatusEvent{
+					Namespace:       record.Namespace,
 					Provider:        record.Provider,
 					Service:         record.Service,
 					Channel:         record.Channel,
@@ -226,6 +227,7 @@ func (d *ChannelDetector) detectStateChangeIncremental(
 	// 条件：之前不可用，现在 down_count == 0 且所有模型都已知
 	if prevStable == 0 && newChannel.DownCount == 0 && newChannel.KnownCount == totalModels && totalModels > 0 {
 		event = &StatusEvent{
+			Namespace:       record.Namespace,
 			Provider:        record.Provider,
 			Service:         record.Service,
 			Channel:         record.Channel,
@@ -273,6 +275,7 @@ func (d *ChannelDetector) detectStateChange(
 		// 只有当之前是可用状态，或首次初始化且有已知模型时才触发 DOWN 事件
 		if prevStable == 1 || (prevStable == -1 && newChannel.KnownCount >= 1) {
 			event = &StatusEvent{
+				Namespace:       record.Namespace,
 				Provider:        record.Provider,
 				Service:         record.Service,
 				Channel:         record.Channel,
@@ -303,6 +306,7 @@ func (d *ChannelDetector) detectStateChange(
 	// 条件：之前不可用，现在 down_count == 0 且所有模型都已知
 	if prevStable == 0 && newChannel.DownCount == 0 && newChannel.KnownCount == totalModels && totalModels > 0 {
 		event = &StatusEvent{
+			Namespace:       record.Namespace,
 			Provider:        record.Provider,
 			Service:         record.Service,
 			Channel:         record.Channel,
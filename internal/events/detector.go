@@ -21,6 +21,15 @@ func NewDetector(cfg DetectorConfig) (*Detector, error) {
 	if cfg.UpThreshold < 1 {
 		return nil, fmt.Errorf("up_threshold 必须 >= 1，当前值: %d", cfg.UpThreshold)
 	}
+	if cfg.CertExpiryDays < 0 {
+		return nil, fmt.Errorf("cert_expiry_days 不能为负数，当前值: %d", cfg.CertExpiryDays)
+	}
+	if cfg.DegradedThreshold < 0 {
+		return nil, fmt.Errorf("degraded_threshold 不能为负数，当前值: %d", cfg.DegradedThreshold)
+	}
+	if cfg.DegradedThreshold > 0 && cfg.DegradedRecoverThreshold < 1 {
+		return nil, fmt.Errorf("degraded_recover_threshold 必须 >= 1，当前值: %d", cfg.DegradedRecoverThreshold)
+	}
 	return &Detector{cfg: cfg}, nil
 }
 
@@ -100,6 +109,7 @@ func (d *Detector) Detect(prev *ServiceState, record *storage.ProbeRecord) (*Ser
 		// 当前稳定态是"可用"，检测是否触发 DOWN
 		if newState.StreakCount >= d.cfg.DownThreshold {
 			event = &StatusEvent{
+				Namespace:       record.Namespace,
 				Provider:        record.Provider,
 				Service:         record.Service,
 				Channel:         record.Channel,
@@ -125,6 +135,7 @@ func (d *Detector) Detect(prev *ServiceState, record *storage.ProbeRecord) (*Ser
 		// 当前稳定态是"不可用"，检测是否触发 UP
 		if newState.StreakCount >= d.cfg.UpThreshold {
 			event = &StatusEvent{
+				Namespace:       record.Namespace,
 				Provider:        record.Provider,
 				Service:         record.Service,
 				Channel:         record.Channel,
@@ -149,3 +160,148 @@ func (d *Detector) Detect(prev *ServiceState, record *storage.ProbeRecord) (*Ser
 
 	return newState, event, nil
 }
+
+// DetectCertExpiry 检测证书到期
+//
+// 输入：
+//   - prevWarned: 当前证书是否已触发过 CERT_EXPIRING 事件
+//   - record: 最新的探测记录
+//
+// 输出：
+//   - warned: 更新后的告警标记
+//   - event: 产生的事件（nil 表示无事件）
+//
+// 逻辑：
+//   - 剩余天数未知（非 HTTPS 或请求失败）时保持原标记
+//   - 剩余天数首次低于阈值时触发一次事件，之后不再重复
+//   - 剩余天数回到阈值以上（证书已续期）时清除标记，下次低于阈值重新触发
+func (d *Detector) DetectCertExpiry(prevWarned bool, record *storage.ProbeRecord) (bool, *StatusEvent) {
+	if d.cfg.CertExpiryDays <= 0 || record == nil || record.CertDaysRemaining == nil {
+		return prevWarned, nil
+	}
+
+	days := *record.CertDaysRemaining
+	if days >= d.cfg.CertExpiryDays {
+		return false, nil
+	}
+	if prevWarned {
+		return true, nil
+	}
+
+	event := &StatusEvent{
+		Namespace:       record.Namespace,
+		Provider:        record.Provider,
+		Service:         record.Service,
+		Channel:         record.Channel,
+		Model:           record.Model,
+		EventType:       EventTypeCertExpiring,
+		FromStatus:      record.Status,
+		ToStatus:        record.Status,
+		TriggerRecordID: record.ID,
+		ObservedAt:      record.Timestamp,
+		CreatedAt:       time.Now().Unix(),
+		Meta: map[string]any{
+			"cert_days_remaining": days,
+			"threshold_days":      d.cfg.CertExpiryDays,
+		},
+	}
+	return true, event
+}
+
+// DetectContentDrift 检测内容漂移
+//
+// 指纹基线与连续次数由探测器维护，记录携带 ContentDrift 即表示本次探测确认了漂移，
+// 每次确认只会出现在一条记录上，因此无需额外的去重状态
+func (d *Detector) DetectContentDrift(record *storage.ProbeRecord) *StatusEvent {
+	if record == nil || record.ContentDrift == nil {
+		return nil
+	}
+	return &StatusEvent{
+		Namespace:       record.Namespace,
+		Provider:        record.Provider,
+		Service:         record.Service,
+		Channel:         record.Channel,
+		Model:           record.Model,
+		EventType:       EventTypeContentDrift,
+		FromStatus:      record.Status,
+		ToStatus:        record.Status,
+		TriggerRecordID: record.ID,
+		ObservedAt:      record.Timestamp,
+		CreatedAt:       time.Now().Unix(),
+		Meta: map[string]any{
+			"from_fingerprint": record.ContentDrift.From,
+			"to_fingerprint":   record.ContentDrift.To,
+		},
+	}
+}
+
+// DetectDegradation 检测性能下降（黄色）的开始与结束
+//
+// 输入：
+//   - prev: 上一次的检测状态（零值表示未下降）
+//   - record: 最新的探测记录
+//
+// 输出：
+//   - state: 更新后的检测状态
+//   - event: 产生的事件（nil 表示无事件）
+//
+// 逻辑：
+//   - 未下降时，连续 N 次黄色触发 DEGRADED_START，其它状态重置计数
+//   - 下降中，连续 M 次绿色触发 DEGRADED_END；黄色或红色重置计数（红色由 DOWN/UP 状态机负责）
+//   - degraded_threshold=0 时关闭，始终返回零值状态
+func (d *Detector) DetectDegradation(prev DegradationState, record *storage.ProbeRecord) (DegradationState, *StatusEvent) {
+	if d.cfg.DegradedThreshold <= 0 || record == nil {
+		return DegradationState{}, nil
+	}
+
+	state := prev
+	var eventType EventType
+	var fromStatus int
+
+	if !prev.Degraded {
+		if record.Status != 2 {
+			return DegradationState{}, nil
+		}
+		state.Streak++
+		if state.Streak < d.cfg.DegradedThreshold {
+			return state, nil
+		}
+		state = DegradationState{Degraded: true}
+		eventType = EventTypeDegradedStart
+		fromStatus = 1
+	} else {
+		if record.Status != 1 {
+			state.Streak = 0
+			return state, nil
+		}
+		state.Streak++
+		if state.Streak < d.cfg.DegradedRecoverThreshold {
+			return state, nil
+		}
+		state = DegradationState{}
+		eventType = EventTypeDegradedEnd
+		fromStatus = 2
+	}
+
+	event := &StatusEvent{
+		Namespace:       record.Namespace,
+		Provider:        record.Provider,
+		Service:         record.Service,
+		Channel:         record.Channel,
+		Model:           record.Model,
+		EventType:       eventType,
+		FromStatus:      fromStatus,
+		ToStatus:        record.Status,
+		TriggerRecordID: record.ID,
+		ObservedAt:      record.Timestamp,
+		CreatedAt:       time.Now().Unix(),
+		Meta: map[string]any{
+			"http_code":  record.HttpCode,
+			"latency_ms": record.Latency,
+		},
+	}
+	if eventType == EventTypeDegradedStart {
+		event.Meta["sub_status"] = string(record.SubStatus)
+	}
+	return state, event
+}
@@ -32,6 +32,21 @@ func TestNewDetector(t *testing.T) {
 			cfg:     DetectorConfig{DownThreshold: -1, UpThreshold: -1},
 			wantErr: true,
 		},
+		{
+			name:    "cert_expiry_days negative",
+			cfg:     DetectorConfig{DownThreshold: 2, UpThreshold: 1, CertExpiryDays: -1},
+			wantErr: true,
+		},
+		{
+			name:    "degraded_threshold negative",
+			cfg:     DetectorConfig{DownThreshold: 2, UpThreshold: 1, DegradedThreshold: -1},
+			wantErr: true,
+		},
+		{
+			name:    "degraded_recover_threshold zero",
+			cfg:     DetectorConfig{DownThreshold: 2, UpThreshold: 1, DegradedThreshold: 3},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -315,3 +330,152 @@ func TestDetector_Detect_NilRecord(t *testing.T) {
 		t.Error("应返回错误当 record 为 nil")
 	}
 }
+
+func TestDetector_DetectCertExpiry(t *testing.T) {
+	detector, _ := NewDetector(DetectorConfig{DownThreshold: 2, UpThreshold: 1, CertExpiryDays: 14})
+
+	recordWithDays := func(id int64, days int) *storage.ProbeRecord {
+		return &storage.ProbeRecord{
+			ID:                id,
+			Provider:          "test-provider",
+			Service:           "test-service",
+			Status:            1,
+			Timestamp:         1000 + id,
+			CertDaysRemaining: &days,
+		}
+	}
+
+	// 高于阈值：不触发
+	warned, event := detector.DetectCertExpiry(false, recordWithDays(1, 30))
+	if warned || event != nil {
+		t.Fatalf("剩余 30 天不应触发事件")
+	}
+
+	// 首次低于阈值：触发一次
+	warned, event = detector.DetectCertExpiry(warned, recordWithDays(2, 13))
+	if !warned || event == nil {
+		t.Fatalf("剩余 13 天应触发 CERT_EXPIRING")
+	}
+	if event.EventType != EventTypeCertExpiring {
+		t.Errorf("expected CERT_EXPIRING, got %s", event.EventType)
+	}
+	if event.Meta["cert_days_remaining"] != 13 {
+		t.Errorf("meta cert_days_remaining = %v", event.Meta["cert_days_remaining"])
+	}
+
+	// 持续低于阈值：不重复触发
+	warned, event = detector.DetectCertExpiry(warned, recordWithDays(3, 12))
+	if !warned || event != nil {
+		t.Fatalf("已告警后不应重复触发")
+	}
+
+	// 剩余天数未知：保持标记
+	warned, event = detector.DetectCertExpiry(warned, &storage.ProbeRecord{ID: 4, Status: 0})
+	if !warned || event != nil {
+		t.Fatalf("剩余天数未知时应保持告警标记")
+	}
+
+	// 证书续期后清除标记，再次低于阈值重新触发
+	warned, _ = detector.DetectCertExpiry(warned, recordWithDays(5, 90))
+	if warned {
+		t.Fatalf("续期后应清除告警标记")
+	}
+	if _, event = detector.DetectCertExpiry(warned, recordWithDays(6, 5)); event == nil {
+		t.Fatalf("续期后再次低于阈值应重新触发")
+	}
+}
+
+func TestDetector_DetectCertExpiryDisabled(t *testing.T) {
+	detector, _ := NewDetector(DetectorConfig{DownThreshold: 2, UpThreshold: 1, CertExpiryDays: 0})
+
+	days := 1
+	warned, event := detector.DetectCertExpiry(false, &storage.ProbeRecord{ID: 1, Status: 1, CertDaysRemaining: &days})
+	if warned || event != nil {
+		t.Fatalf("cert_expiry_days=0 时不应触发事件")
+	}
+}
+
+func TestDetector_DetectDegradation(t *testing.T) {
+	detector, _ := NewDetector(DetectorConfig{DownThreshold: 2, UpThreshold: 1, DegradedThreshold: 2, DegradedRecoverThreshold: 2})
+
+	record := func(id int64, status int) *storage.ProbeRecord {
+		return &storage.ProbeRecord{ID: id, Provider: "test-provider", Service: "test-service", Status: status, Timestamp: 1000 + id}
+	}
+
+	// 单次黄色后被绿色打断：不触发
+	state, event := detector.DetectDegradation(DegradationState{}, record(1, 2))
+	if event != nil || state.Streak != 1 {
+		t.Fatalf("首次黄色不应触发，state=%+v", state)
+	}
+	state, event = detector.DetectDegradation(state, record(2, 1))
+	if event != nil || state != (DegradationState{}) {
+		t.Fatalf("绿色应重置计数，state=%+v", state)
+	}
+
+	// 连续 2 次黄色：触发 DEGRADED_START
+	state, _ = detector.DetectDegradation(state, record(3, 2))
+	state, event = detector.DetectDegradation(state, record(4, 2))
+	if event == nil || event.EventType != EventTypeDegradedStart || !state.Degraded {
+		t.Fatalf("连续 2 次黄色应触发 DEGRADED_START，state=%+v event=%+v", state, event)
+	}
+	if event.FromStatus != 1 || event.ToStatus != 2 || event.TriggerRecordID != 4 {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	// 下降中继续黄色：不重复触发
+	state, event = detector.DetectDegradation(state, record(5, 2))
+	if event != nil || !state.Degraded {
+		t.Fatalf("下降中不应重复触发")
+	}
+
+	// 绿色后被红色打断：重新计数
+	state, _ = detector.DetectDegradation(state, record(6, 1))
+	state, event = detector.DetectDegradation(state, record(7, 0))
+	if event != nil || !state.Degraded || state.Streak != 0 {
+		t.Fatalf("红色应重置恢复计数，state=%+v", state)
+	}
+
+	// 连续 2 次绿色：触发 DEGRADED_END
+	state, _ = detector.DetectDegradation(state, record(8, 1))
+	state, event = detector.DetectDegradation(state, record(9, 1))
+	if event == nil || event.EventType != EventTypeDegradedEnd || state.Degraded {
+		t.Fatalf("连续 2 次绿色应触发 DEGRADED_END，state=%+v event=%+v", state, event)
+	}
+	if event.FromStatus != 2 || event.ToStatus != 1 {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
+
+func TestDetector_DetectDegradationDisabled(t *testing.T) {
+	detector, _ := NewDetector(DetectorConfig{DownThreshold: 2, UpThreshold: 1})
+
+	state := DegradationState{}
+	for i := int64(1); i <= 5; i++ {
+		var event *StatusEvent
+		state, event = detector.DetectDegradation(state, &storage.ProbeRecord{ID: i, Status: 2})
+		if event != nil {
+			t.Fatalf("degraded_threshold=0 时不应触发事件")
+		}
+	}
+}
+
+func TestDetector_DetectContentDrift(t *testing.T) {
+	detector, _ := NewDetector(DetectorConfig{DownThreshold: 2, UpThreshold: 1})
+
+	if event := detector.DetectContentDrift(&storage.ProbeRecord{ID: 1, Status: 1}); event != nil {
+		t.Fatalf("未携带漂移信息时不应触发事件")
+	}
+
+	record := &storage.ProbeRecord{
+		ID: 2, Provider: "test-provider", Service: "test-service", Status: 2,
+		SubStatus: storage.SubStatusContentDrift, Timestamp: 1002,
+		ContentDrift: &storage.ContentDrift{From: "aaaa", To: "bbbb"},
+	}
+	event := detector.DetectContentDrift(record)
+	if event == nil || event.EventType != EventTypeContentDrift {
+		t.Fatalf("应触发 CONTENT_DRIFT，got %+v", event)
+	}
+	if event.TriggerRecordID != 2 || event.Meta["from_fingerprint"] != "aaaa" || event.Meta["to_fingerprint"] != "bbbb" {
+		t.Errorf("事件字段错误: %+v", event)
+	}
+}
@@ -0,0 +1,198 @@
+package events
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// outboxPurgeInterval 清理已完成待投递记录的间隔
+const outboxPurgeInterval = time.Hour
+
+// EnableOutbox 启用事件发件箱并启动后台投递任务
+// 存储不支持发件箱（或未配置 Webhook 目标）时返回 false，此时仍使用内存推送
+func (d *WebhookDispatcher) EnableOutbox(cfg config.EventOutboxConfig) bool {
+	if d == nil {
+		return false
+	}
+	ob, ok := d.storage.(storage.EventOutboxStorage)
+	if !ok {
+		return false
+	}
+	d.outbox = ob
+	d.outboxCfg = cfg
+	d.wake = make(chan struct{}, 1)
+
+	d.wg.Add(1)
+	go d.runOutbox()
+	return true
+}
+
+// Durable 返回是否通过发件箱投递
+func (d *WebhookDispatcher) Durable() bool {
+	return d != nil && d.outbox != nil
+}
+
+// SaveWithOutbox 在同一事务中保存事件与各订阅目标的待投递记录，并唤醒投递任务
+// 重复事件（event.ID 为 0）不产生待投递记录
+func (d *WebhookDispatcher) SaveWithOutbox(event *StatusEvent) error {
+	var destinations []string
+	for _, t := range d.targets {
+		if t.accepts(event.EventType) {
+			destinations = append(destinations, t.url)
+		}
+	}
+	if err := d.outbox.SaveStatusEventWithOutbox(event, destinations); err != nil {
+		return err
+	}
+	if event.ID != 0 && len(destinations) > 0 {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// runOutbox 轮询发件箱并投递到期记录，直到 Stop
+func (d *WebhookDispatcher) runOutbox() {
+	defer d.wg.Done()
+
+	logger.Info("events", "事件发件箱投递任务已启动",
+		"poll_interval", d.outboxCfg.PollIntervalDuration,
+		"batch_size", d.outboxCfg.BatchSize,
+		"lease", d.outboxCfg.LeaseDuration)
+
+	ticker := time.NewTicker(d.outboxCfg.PollIntervalDuration)
+	defer ticker.Stop()
+	var lastPurge time.Time
+	for {
+		d.drainOutbox()
+		if time.Since(lastPurge) >= outboxPurgeInterval {
+			d.purgeOutbox()
+			lastPurge = time.Now()
+		}
+
+		select {
+		case <-ticker.C:
+		case <-d.wake:
+		case <-d.ctx.Done():
+			return
+		}
+	}
+}
+
+// drainOutbox 逐批领取并投递到期记录，一批全部结束后再领取下一批（租约需覆盖一批的投递时间）
+func (d *WebhookDispatcher) drainOutbox() {
+	for d.ctx.Err() == nil {
+		entries, err := d.outbox.ClaimOutboxEntries(time.Now().Unix(), d.outboxCfg.LeaseDuration, d.outboxCfg.BatchSize)
+		if err != nil {
+			logger.Warn("events", "领取事件待投递记录失败", "error", err)
+			return
+		}
+
+		var batch sync.WaitGroup
+		for _, e := range entries {
+			batch.Add(1)
+			go func() {
+				defer batch.Done()
+				d.deliverOutbox(e)
+			}()
+		}
+		batch.Wait()
+
+		if len(entries) < d.outboxCfg.BatchSize {
+			return
+		}
+	}
+}
+
+// deliverOutbox 对一条待投递记录进行一次投递尝试，并持久化结果
+// 关闭过程中排队未发出的记录保持未完成状态，租约到期后由本实例（重启后）或其它实例重新投递
+func (d *WebhookDispatcher) deliverOutbox(e *storage.OutboxEntry) {
+	if e.Event == nil {
+		d.completeOutbox(e, e.Attempts, "事件已删除，放弃投递")
+		return
+	}
+	t := d.targetByURL(e.Destination)
+	if t == nil {
+		d.completeOutbox(e, e.Attempts, "投递目标已从配置中移除，放弃投递")
+		return
+	}
+	if !t.accepts(e.Event.EventType) {
+		d.completeOutbox(e, e.Attempts, "投递目标不再订阅该类型事件，放弃投递")
+		return
+	}
+
+	body, err := webhookBody(e.Event)
+	if err != nil {
+		logger.Error("events", "编码 Webhook 事件失败", "event_id", e.EventID, "error", err)
+		d.completeOutbox(e, e.Attempts, fmt.Sprintf("编码事件失败: %v", err))
+		return
+	}
+
+	select {
+	case d.sem <- struct{}{}:
+	case <-d.ctx.Done():
+		return
+	}
+	attempts := e.Attempts + 1
+	retryable, err := d.send(t, e.EventID, e.Event.EventType, body)
+	<-d.sem
+
+	if err == nil {
+		logger.Debug("events", "Webhook 推送成功", "url", t.url, "event_id", e.EventID, "attempts", attempts)
+		d.completeOutbox(e, attempts, "")
+		return
+	}
+	logger.Warn("events", "Webhook 推送失败", "url", t.url, "event_id", e.EventID, "attempt", attempts, "error", err)
+
+	if retryable && attempts < t.maxAttempts {
+		next := time.Now().Add(webhookBackoff(attempts)).Unix()
+		if rerr := d.outbox.RetryOutboxEntry(e.ID, attempts, next, err.Error()); rerr != nil {
+			logger.Error("events", "更新事件待投递记录失败，租约到期后重试", "outbox_id", e.ID, "error", rerr)
+		}
+		return
+	}
+
+	d.deadLetter(t, e.EventID, e.Event.EventType, body, attempts, err)
+	d.completeOutbox(e, attempts, err.Error())
+}
+
+// completeOutbox 标记待投递记录完成（失败时租约到期后会被重新投递）
+func (d *WebhookDispatcher) completeOutbox(e *storage.OutboxEntry, attempts int, reason string) {
+	if reason != "" && attempts == e.Attempts {
+		logger.Warn("events", "事件待投递记录已丢弃",
+			"outbox_id", e.ID, "event_id", e.EventID, "url", e.Destination, "reason", reason)
+	}
+	if err := d.outbox.CompleteOutboxEntry(e.ID, attempts, time.Now().Unix(), reason); err != nil {
+		logger.Error("events", "标记事件待投递记录完成失败，租约到期后可能重复投递", "outbox_id", e.ID, "error", err)
+	}
+}
+
+// purgeOutbox 删除超过保留时长的已完成记录
+func (d *WebhookDispatcher) purgeOutbox() {
+	before := time.Now().Add(-d.outboxCfg.RetentionDuration).Unix()
+	n, err := d.outbox.PurgeCompletedOutbox(before)
+	if err != nil {
+		logger.Warn("events", "清理已完成的事件待投递记录失败", "error", err)
+		return
+	}
+	if n > 0 {
+		logger.Info("events", "已清理完成的事件待投递记录", "deleted", n)
+	}
+}
+
+// targetByURL 按地址查找投递目标
+func (d *WebhookDispatcher) targetByURL(url string) *webhookTarget {
+	for _, t := range d.targets {
+		if t.url == url {
+			return t
+		}
+	}
+	return nil
+}
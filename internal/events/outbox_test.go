@@ -0,0 +1,137 @@
+package events
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func newOutboxTestStore(t *testing.T) *storage.SQLiteStorage {
+	t.Helper()
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "monitor.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	if err := store.Init(); err != nil {
+		t.Fatalf("init sqlite: %v", err)
+	}
+	return store
+}
+
+func newOutboxConfig(t *testing.T) config.EventOutboxConfig {
+	t.Helper()
+	cfg := config.EventOutboxConfig{Enabled: true, PollInterval: "100ms"}
+	if err := cfg.Normalize(nil); err != nil {
+		t.Fatalf("Normalize() failed: %v", err)
+	}
+	return cfg
+}
+
+func TestWebhookDispatcher_OutboxRetryAndDeadLetter(t *testing.T) {
+	orig := webhookBaseBackoff
+	webhookBaseBackoff = time.Millisecond
+	defer func() { webhookBaseBackoff = orig }()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 前两次 503，之后 DOWN 成功、UP 返回 400
+		n := calls.Add(1)
+		switch {
+		case n <= 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.Header.Get(WebhookHeaderEvent) == "UP":
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	store := newOutboxTestStore(t)
+	d := newTestWebhookDispatcher(t, store, config.EventWebhookConfig{URL: srv.URL, MaxAttempts: 3})
+	if !d.EnableOutbox(newOutboxConfig(t)) {
+		t.Fatal("SQLite 应支持事件发件箱")
+	}
+	defer d.Stop()
+
+	now := time.Now().Unix()
+	down := &StatusEvent{Provider: "p", Service: "s", EventType: EventTypeDown, TriggerRecordID: 1, ObservedAt: now, CreatedAt: now}
+	if err := d.SaveWithOutbox(down); err != nil || down.ID == 0 {
+		t.Fatalf("SaveWithOutbox() = %v, id = %d", err, down.ID)
+	}
+	// 重复事件不产生新的待投递记录
+	dup := *down
+	dup.ID = 0
+	if err := d.SaveWithOutbox(&dup); err != nil || dup.ID != 0 {
+		t.Fatalf("duplicate SaveWithOutbox() = %v, id = %d", err, dup.ID)
+	}
+	waitFor(t, func() bool { return calls.Load() == 3 })
+
+	up := &StatusEvent{Provider: "p", Service: "s", EventType: EventTypeUp, TriggerRecordID: 2, ObservedAt: now, CreatedAt: now}
+	if err := d.SaveWithOutbox(up); err != nil {
+		t.Fatalf("SaveWithOutbox() = %v", err)
+	}
+	waitFor(t, func() bool {
+		letters, _ := store.GetWebhookDeadLetters(storage.WebhookDeadLetterFilter{})
+		return len(letters) == 1
+	})
+	d.Stop()
+
+	if n := calls.Load(); n != 4 {
+		t.Fatalf("calls = %d, want 4", n)
+	}
+	letters, _ := store.GetWebhookDeadLetters(storage.WebhookDeadLetterFilter{})
+	if letters[0].EventID != up.ID || letters[0].Attempts != 1 {
+		t.Errorf("unexpected dead letter: %+v", letters[0])
+	}
+	entries, err := store.ClaimOutboxEntries(time.Now().Add(time.Hour).Unix(), time.Minute, 10)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("outbox should be drained, got %d entries (err=%v)", len(entries), err)
+	}
+}
+
+func TestWebhookDispatcher_OutboxSurvivesRestart(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	// 事件已落库但进程在投递前退出（未启动投递任务）
+	store := newOutboxTestStore(t)
+	now := time.Now().Unix()
+	event := &StatusEvent{Provider: "p", Service: "s", EventType: EventTypeDown, TriggerRecordID: 1, ObservedAt: now, CreatedAt: now}
+	if err := store.SaveStatusEventWithOutbox(event, []string{srv.URL}); err != nil {
+		t.Fatalf("SaveStatusEventWithOutbox() = %v", err)
+	}
+
+	// 重启后由投递任务补发
+	d := newTestWebhookDispatcher(t, store, config.EventWebhookConfig{URL: srv.URL})
+	d.EnableOutbox(newOutboxConfig(t))
+	waitFor(t, func() bool { return calls.Load() == 1 })
+	d.Stop()
+
+	// 领取后未完成的记录在租约期内不会被再次领取
+	event2 := &StatusEvent{Provider: "p", Service: "s", EventType: EventTypeUp, TriggerRecordID: 2, ObservedAt: now, CreatedAt: now}
+	if err := store.SaveStatusEventWithOutbox(event2, []string{srv.URL}); err != nil {
+		t.Fatalf("SaveStatusEventWithOutbox() = %v", err)
+	}
+	claimed, err := store.ClaimOutboxEntries(now, time.Minute, 10)
+	if err != nil || len(claimed) != 1 || claimed[0].Event == nil || claimed[0].Event.EventType != EventTypeUp {
+		t.Fatalf("unexpected claim: %+v (err=%v)", claimed, err)
+	}
+	if again, _ := store.ClaimOutboxEntries(now+30, time.Minute, 10); len(again) != 0 {
+		t.Fatalf("entry claimed twice within lease: %+v", again)
+	}
+	if again, _ := store.ClaimOutboxEntries(now+61, time.Minute, 10); len(again) != 1 {
+		t.Fatalf("entry should be reclaimable after lease expiry, got %d", len(again))
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("calls = %d, want 1", calls.Load())
+	}
+}
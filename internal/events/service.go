@@ -1,20 +1,34 @@
 package events
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"sync"
+	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
+	"monitor/internal/baseline"
 	"monitor/internal/config"
+	"monitor/internal/eventbus"
 	"monitor/internal/logger"
 	"monitor/internal/storage"
+	"monitor/internal/tracing"
 )
 
 // Service 事件服务
 // 协调检测器和存储层，处理探测结果并生成事件
 type Service struct {
+	storage storage.Storage
+
+	// 运行参数（启动与热更新时由 UpdateConfig 替换）
+	// 处理探测记录与发出事件期间持有读锁，保证单次处理使用同一份配置
+	cfgMu            sync.RWMutex
+	cfg              ServiceConfig // 当前生效的配置
+	applied          bool          // 是否已应用过配置（区分启动与热更新）
 	detector         *Detector
 	channelDetector  *ChannelDetector
-	storage          storage.Storage
 	enabled          bool
 	mode             string // "model" 或 "channel"
 	channelCountMode string // "incremental" 或 "recompute"
@@ -25,6 +39,27 @@ type Service struct {
 	// 活跃模型索引（用于 channel 模式）
 	activeModels   map[string][]string // "provider/service/channel" -> []model
 	activeModelsMu sync.RWMutex
+
+	// 证书到期告警标记（仅内存，重启后证书仍低于阈值时会重新触发一次）
+	certWarned   map[string]bool // "provider/service/channel/model" -> 是否已告警
+	certWarnedMu sync.Mutex
+
+	// 性能下降检测状态（仅内存，重启后从零计数，下降中重启不会补发 DEGRADED_END）
+	degraded   map[string]DegradationState // "provider/service/channel/model" -> 检测状态
+	degradedMu sync.Mutex
+
+	// Webhook 推送器（nil 表示未配置），首次启用时创建，之后 webhooks / outbox 的变更需重启后生效
+	webhooks      *WebhookDispatcher
+	webhooksReady bool
+	webhookCfgs   []config.EventWebhookConfig // 创建推送器时的配置
+	outboxCfg     config.EventOutboxConfig
+
+	// 事件总线发布器（nil 表示未配置）
+	eventBus *eventbus.Publisher
+
+	// 官方基线索引（用于 DOWN / DEGRADED_START 事件的异常归因）
+	baselines   *baseline.Index
+	baselinesMu sync.RWMutex
 }
 
 // ServiceConfig 事件服务配置
@@ -34,51 +69,151 @@ type ServiceConfig struct {
 	Mode                  string // "model" 或 "channel"
 	ChannelCountMode      string // "incremental" 或 "recompute"
 	Enabled               bool
+	Webhooks              []config.EventWebhookConfig
+	Outbox                config.EventOutboxConfig
+}
+
+// NewServiceConfig 从 events 配置构建事件服务配置（配置需已规范化）
+func NewServiceConfig(cfg *config.EventsConfig) ServiceConfig {
+	certExpiryDays := 0
+	if cfg.CertExpiryDays != nil {
+		certExpiryDays = *cfg.CertExpiryDays
+	}
+	return ServiceConfig{
+		DetectorConfig: DetectorConfig{
+			DownThreshold:            cfg.DownThreshold,
+			UpThreshold:              cfg.UpThreshold,
+			CertExpiryDays:           certExpiryDays,
+			DegradedThreshold:        cfg.DegradedThreshold,
+			DegradedRecoverThreshold: cfg.DegradedRecoverThreshold,
+		},
+		ChannelDetectorConfig: ChannelDetectorConfig{
+			DownThreshold: cfg.ChannelDownThreshold,
+		},
+		Mode:             cfg.Mode,
+		ChannelCountMode: cfg.ChannelCountMode,
+		Enabled:          cfg.Enabled,
+		Webhooks:         cfg.Webhooks,
+		Outbox:           cfg.Outbox,
+	}
 }
 
 // NewService 创建事件服务
+// 未启用时同样返回可用的服务（不处理探测记录），便于热更新开启
 func NewService(cfg ServiceConfig, store storage.Storage) (*Service, error) {
-	if !cfg.Enabled {
-		return &Service{
-			enabled: false,
-		}, nil
+	svc := &Service{
+		storage:      store,
+		activeModels: make(map[string][]string),
+		certWarned:   make(map[string]bool),
+		degraded:     make(map[string]DegradationState),
 	}
-
-	detector, err := NewDetector(cfg.DetectorConfig)
-	if err != nil {
+	if err := svc.UpdateConfig(cfg); err != nil {
 		return nil, err
 	}
+	return svc, nil
+}
+
+// UpdateConfig 应用事件配置（启动与热更新时调用）
+//
+// 阈值、事件模式、通道计数模式与启用状态立即生效：已累计的连续计数沿用，按新阈值判定；
+// 关闭期间不处理探测记录，重新开启后从存储中的状态继续检测。
+// Webhook 推送器在首次启用时创建，之后 webhooks / outbox 的变更需重启后生效。
+// 配置无效时返回错误，此时沿用原配置
+func (s *Service) UpdateConfig(cfg ServiceConfig) error {
+	var detector *Detector
+	if cfg.Enabled {
+		d, err := NewDetector(cfg.DetectorConfig)
+		if err != nil {
+			return err
+		}
+		detector = d
+	}
 
-	mode := cfg.Mode
-	if mode == "" {
-		mode = "model"
+	if cfg.Mode == "" {
+		cfg.Mode = "model"
+	}
+	if cfg.ChannelCountMode == "" {
+		cfg.ChannelCountMode = "recompute" // 默认使用重算模式
 	}
 
-	channelCountMode := cfg.ChannelCountMode
-	if channelCountMode == "" {
-		channelCountMode = "recompute" // 默认使用重算模式
+	s.cfgMu.Lock()
+	defer s.cfgMu.Unlock()
+
+	prev, reload := s.cfg, s.applied
+	if cfg.Enabled {
+		s.detector = detector
+		s.channelDetector = NewChannelDetector(cfg.ChannelDetectorConfig)
+		s.mode = cfg.Mode
+		s.channelCountMode = cfg.ChannelCountMode
+		s.initWebhooksLocked(cfg)
 	}
+	s.enabled = cfg.Enabled
+	s.cfg = cfg
+	s.applied = true
 
-	svc := &Service{
-		detector:         detector,
-		channelDetector:  NewChannelDetector(cfg.ChannelDetectorConfig),
-		storage:          store,
-		enabled:          true,
-		mode:             mode,
-		channelCountMode: channelCountMode,
-		activeModels:     make(map[string][]string),
+	if reload && !sameDetection(prev, cfg) {
+		logger.Info("events", "事件配置已更新",
+			"enabled", cfg.Enabled,
+			"mode", cfg.Mode,
+			"channel_count_mode", cfg.ChannelCountMode,
+			"down_threshold", cfg.DetectorConfig.DownThreshold,
+			"up_threshold", cfg.DetectorConfig.UpThreshold,
+			"channel_down_threshold", cfg.ChannelDetectorConfig.DownThreshold,
+			"cert_expiry_days", cfg.DetectorConfig.CertExpiryDays,
+			"degraded_threshold", cfg.DetectorConfig.DegradedThreshold)
 	}
+	return nil
+}
 
-	return svc, nil
+// initWebhooksLocked 首次启用时创建 Webhook 推送器，之后仅在配置变更时提示需要重启（调用方需持有 cfgMu 写锁）
+func (s *Service) initWebhooksLocked(cfg ServiceConfig) {
+	if s.webhooksReady {
+		if !reflect.DeepEqual(cfg.Webhooks, s.webhookCfgs) || cfg.Outbox != s.outboxCfg {
+			logger.Warn("events", "events.webhooks / events.outbox 已变更，需重启后生效")
+		}
+		return
+	}
+	s.webhooks = NewWebhookDispatcher(cfg.Webhooks, s.storage)
+	s.webhooksReady = true
+	s.webhookCfgs, s.outboxCfg = cfg.Webhooks, cfg.Outbox
+	if cfg.Outbox.Enabled && len(cfg.Webhooks) > 0 && !s.webhooks.EnableOutbox(cfg.Outbox) {
+		logger.Warn("events", "当前存储后端不支持事件发件箱，Webhook 使用内存推送")
+	}
+}
+
+// sameDetection 判断两份配置的检测参数（阈值、模式与启用状态）是否一致
+func sameDetection(a, b ServiceConfig) bool {
+	return a.DetectorConfig == b.DetectorConfig &&
+		a.ChannelDetectorConfig == b.ChannelDetectorConfig &&
+		a.Mode == b.Mode &&
+		a.ChannelCountMode == b.ChannelCountMode &&
+		a.Enabled == b.Enabled
 }
 
 // IsEnabled 返回事件服务是否启用
 func (s *Service) IsEnabled() bool {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
 	return s.enabled
 }
 
+// SetEventBus 设置事件总线发布器（启动时调用），保存成功的事件会镜像发布到 Kafka / NATS
+func (s *Service) SetEventBus(p *eventbus.Publisher) {
+	s.eventBus = p
+}
+
+// Stop 停止事件服务（等待进行中的 Webhook 投递结束）
+func (s *Service) Stop() {
+	s.cfgMu.RLock()
+	webhooks := s.webhooks
+	s.cfgMu.RUnlock()
+	webhooks.Stop()
+}
+
 // GetMode 返回当前事件模式
 func (s *Service) GetMode() string {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
 	return s.mode
 }
 
@@ -118,6 +253,17 @@ func (s *Service) UpdateActiveModels(monitors []config.ServiceConfig, boardsEnab
 	logger.Debug("events", "活跃模型索引已更新", "channels", len(index))
 }
 
+// UpdateBaselines 从配置更新官方基线索引
+func (s *Service) UpdateBaselines(monitors []config.ServiceConfig) {
+	idx := baseline.NewIndex(monitors)
+
+	s.baselinesMu.Lock()
+	s.baselines = idx
+	s.baselinesMu.Unlock()
+
+	logger.Debug("events", "官方基线索引已更新", "baselines", len(idx.All()))
+}
+
 // lockForModel 获取指定模型的锁（model 模式使用）
 func (s *Service) lockForModel(provider, service, channel, model string) *sync.Mutex {
 	key := "model:" + provider + "\n" + service + "\n" + channel + "\n" + model
@@ -137,20 +283,304 @@ func (s *Service) lockForChannel(provider, service, channel string) *sync.Mutex
 //
 // 返回产生的事件（如有），用于日志记录等
 func (s *Service) ProcessRecord(record *storage.ProbeRecord) (*StatusEvent, error) {
+	return s.ProcessRecordContext(context.Background(), record)
+}
+
+// ProcessRecordContext 与 ProcessRecord 相同，ctx 仅用于追踪链路（不参与取消）
+func (s *Service) ProcessRecordContext(ctx context.Context, record *storage.ProbeRecord) (event *StatusEvent, err error) {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
 	if !s.enabled {
 		return nil, nil
 	}
 
+	_, span := tracing.Start(ctx, "events.ProcessRecord", attribute.String("events.mode", s.mode))
+	defer func() {
+		if event != nil {
+			span.SetAttributes(attribute.String("events.type", string(event.EventType)))
+		}
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
 	if record == nil {
 		return nil, fmt.Errorf("record 不能为空")
 	}
 
+	// 证书到期、内容漂移、性能下降检测独立于 DOWN/UP 状态机，两种模式均按监测项触发
+	s.processCertExpiry(record)
+	s.processContentDrift(record)
+	s.processDegradation(record)
+
 	if s.mode == "channel" {
 		return s.processRecordChannelMode(record)
 	}
 	return s.processRecordModelMode(record)
 }
 
+// saveEvent 保存事件，成功后推送到已配置的 Webhook（启用发件箱时与待投递记录在同一事务中写入）
+// 调用方需持有 cfgMu 读锁
+func (s *Service) saveEvent(event *StatusEvent) error {
+	correlation := s.correlateEvent(event)
+	if event.EventType == EventTypeDown {
+		s.classifyEvent(event, correlation)
+	}
+	if s.webhooks.Durable() {
+		if err := s.webhooks.SaveWithOutbox(event); err != nil {
+			return err
+		}
+		s.eventBus.PublishEvent(event)
+		return nil
+	}
+	if err := s.storage.SaveStatusEvent(event); err != nil {
+		return err
+	}
+	s.webhooks.Publish(event)
+	s.eventBus.PublishEvent(event)
+	return nil
+}
+
+// correlateEvent 为中转站的 DOWN / DEGRADED_START 事件附加官方基线归因
+// 归因结果写入事件 meta 的 correlation 字段（通道级事件的 meta.scope 另有含义，不能平铺）；
+// 未配置基线、基线无新鲜数据或查询失败时不做处理并返回 nil
+func (s *Service) correlateEvent(event *StatusEvent) *baseline.Correlation {
+	status := 0
+	switch event.EventType {
+	case EventTypeDown:
+	case EventTypeDegradedStart:
+		status = 2
+	default:
+		return nil
+	}
+
+	s.baselinesMu.RLock()
+	idx := s.baselines
+	s.baselinesMu.RUnlock()
+
+	key := storage.MonitorKey{Provider: event.Provider, Service: event.Service, Channel: event.Channel, Model: event.Model}
+	if idx.Empty() || idx.IsBaseline(key) {
+		return nil
+	}
+
+	latest, err := baseline.LatestRecords(s.storage, idx.References(event.Service, event.Model))
+	if err != nil {
+		logger.Warn("events", "查询官方基线失败，跳过归因",
+			"provider", event.Provider, "service", event.Service, "channel", event.Channel,
+			"error", err)
+		return nil
+	}
+	c := idx.Correlate(event.Service, event.Model, status, latest, time.Unix(event.ObservedAt, 0))
+	if c == nil {
+		return nil
+	}
+	if event.Meta == nil {
+		event.Meta = make(map[string]any)
+	}
+	event.Meta["correlation"] = c.Meta()
+	return c
+}
+
+// classifyEvent 推断 DOWN 事件的可能原因并写入 meta.cause（供通知文案使用），无法判断时不写入
+// 通道级事件按触发模型回看探测记录
+func (s *Service) classifyEvent(event *StatusEvent, correlation *baseline.Correlation) {
+	model := event.Model
+	if model == "" {
+		if trigger, ok := event.Meta["trigger_model"].(string); ok {
+			model = trigger
+		}
+	}
+
+	since := time.Unix(event.ObservedAt, 0).Add(-causeWindow)
+	records, err := s.storage.GetHistory(event.Provider, event.Service, event.Channel, model, since)
+	if err != nil {
+		// 查询失败时仍可依据基线归因分类
+		logger.Warn("events", "查询近期探测记录失败，原因分类仅参考基线",
+			"provider", event.Provider, "service", event.Service, "channel", event.Channel,
+			"error", err)
+		records = nil
+	}
+
+	cause := ClassifyCause(records, correlation)
+	if cause == "" {
+		return
+	}
+	if event.Meta == nil {
+		event.Meta = make(map[string]any)
+	}
+	event.Meta["cause"] = cause
+}
+
+// processCertExpiry 证书到期事件处理
+// 事件直接落库，不作为 ProcessRecord 的返回值（返回值仅表示可用性变更）
+func (s *Service) processCertExpiry(record *storage.ProbeRecord) {
+	if record.CertDaysRemaining == nil {
+		return
+	}
+
+	key := record.Provider + "/" + record.Service + "/" + record.Channel + "/" + record.Model
+
+	s.certWarnedMu.Lock()
+	defer s.certWarnedMu.Unlock()
+
+	warned, event := s.detector.DetectCertExpiry(s.certWarned[key], record)
+	if event == nil {
+		if warned {
+			s.certWarned[key] = true
+		} else {
+			delete(s.certWarned, key)
+		}
+		return
+	}
+
+	if err := s.saveEvent(event); err != nil {
+		// 保存失败时不置位，下次探测重试
+		logger.Error("events", "保存证书到期事件失败",
+			"provider", record.Provider, "service", record.Service, "channel", record.Channel, "model", record.Model,
+			"error", err)
+		return
+	}
+	s.certWarned[key] = true
+
+	logger.Info("events", "证书即将到期事件",
+		"provider", record.Provider, "service", record.Service, "channel", record.Channel, "model", record.Model,
+		"cert_days_remaining", *record.CertDaysRemaining)
+}
+
+// processContentDrift 内容漂移事件处理
+// 与证书到期事件相同，事件直接落库，不作为 ProcessRecord 的返回值
+func (s *Service) processContentDrift(record *storage.ProbeRecord) {
+	event := s.detector.DetectContentDrift(record)
+	if event == nil {
+		return
+	}
+
+	if err := s.saveEvent(event); err != nil {
+		logger.Error("events", "保存内容漂移事件失败",
+			"provider", record.Provider, "service", record.Service, "channel", record.Channel, "model", record.Model,
+			"error", err)
+		return
+	}
+
+	logger.Info("events", "响应内容漂移事件",
+		"provider", record.Provider, "service", record.Service, "channel", record.Channel, "model", record.Model,
+		"from_fingerprint", record.ContentDrift.From, "to_fingerprint", record.ContentDrift.To)
+}
+
+// processDegradation 性能下降事件处理
+// 与证书到期事件相同，事件直接落库，不作为 ProcessRecord 的返回值
+func (s *Service) processDegradation(record *storage.ProbeRecord) {
+	key := record.Provider + "/" + record.Service + "/" + record.Channel + "/" + record.Model
+
+	s.degradedMu.Lock()
+	defer s.degradedMu.Unlock()
+
+	state, event := s.detector.DetectDegradation(s.degraded[key], record)
+	if event != nil {
+		if err := s.saveEvent(event); err != nil {
+			// 保存失败时不推进状态，下次探测重试
+			logger.Error("events", "保存性能下降事件失败",
+				"provider", record.Provider, "service", record.Service, "channel", record.Channel, "model", record.Model,
+				"event_type", event.EventType,
+				"error", err)
+			return
+		}
+		logger.Info("events", "性能下降状态变更事件",
+			"provider", record.Provider, "service", record.Service, "channel", record.Channel, "model", record.Model,
+			"event_type", event.EventType)
+	}
+
+	if state == (DegradationState{}) {
+		delete(s.degraded, key)
+	} else {
+		s.degraded[key] = state
+	}
+}
+
+// EmitSchedulerSaturated 记录调度器饱和事件（SCHEDULER_SATURATED）
+// 该事件不属于任何监测项（provider/service/channel 为空），没有触发记录，
+// trigger_record_id 取毫秒时间戳以满足唯一索引
+func (s *Service) EmitSchedulerSaturated(meta map[string]any) (*StatusEvent, error) {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	if !s.enabled {
+		return nil, nil
+	}
+
+	now := time.Now()
+	event := &StatusEvent{
+		EventType:       EventTypeSchedulerSaturated,
+		FromStatus:      1,
+		ToStatus:        2,
+		TriggerRecordID: now.UnixMilli(),
+		ObservedAt:      now.Unix(),
+		CreatedAt:       now.Unix(),
+		Meta:            meta,
+	}
+	if err := s.saveEvent(event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// EmitSLAEvent 记录服务商 SLA 事件（SLA_BREACH_WARNING / SLA_BREACHED）
+// 该事件属于服务商整体（service/channel 为空），没有触发记录，
+// trigger_record_id 取统计周期开始时间，同一服务商每个周期每类事件只保存一次
+func (s *Service) EmitSLAEvent(namespace, provider string, eventType EventType, periodStart int64, fromStatus, toStatus int, meta map[string]any) (*StatusEvent, error) {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	if !s.enabled {
+		return nil, nil
+	}
+
+	now := time.Now()
+	event := &StatusEvent{
+		Namespace:       namespace,
+		Provider:        provider,
+		EventType:       eventType,
+		FromStatus:      fromStatus,
+		ToStatus:        toStatus,
+		TriggerRecordID: periodStart,
+		ObservedAt:      now.Unix(),
+		CreatedAt:       now.Unix(),
+		Meta:            meta,
+	}
+	if err := s.saveEvent(event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// EmitBoardEvent 记录板块自动调整事件（BOARD_DEMOTED / BOARD_PROMOTED）
+// 该事件没有触发记录，trigger_record_id 取毫秒时间戳以满足唯一索引；
+// from_status/to_status 按板块映射（hot=1、secondary=2、cold=0），原始板块名见 meta
+func (s *Service) EmitBoardEvent(key storage.MonitorKey, namespace string, eventType EventType, fromStatus, toStatus int, meta map[string]any) (*StatusEvent, error) {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	if !s.enabled {
+		return nil, nil
+	}
+
+	now := time.Now()
+	event := &StatusEvent{
+		Namespace:       namespace,
+		Provider:        key.Provider,
+		Service:         key.Service,
+		Channel:         key.Channel,
+		Model:           key.Model,
+		EventType:       eventType,
+		FromStatus:      fromStatus,
+		ToStatus:        toStatus,
+		TriggerRecordID: now.UnixMilli(),
+		ObservedAt:      now.Unix(),
+		CreatedAt:       now.Unix(),
+		Meta:            meta,
+	}
+	if err := s.saveEvent(event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
 // processRecordModelMode 模型级事件处理（原有逻辑）
 func (s *Service) processRecordModelMode(record *storage.ProbeRecord) (*StatusEvent, error) {
 	// 同一监测项串行化：否则 Scheduler 允许同任务重叠时，会出现：
@@ -193,7 +623,7 @@ func (s *Service) processRecordModelMode(record *storage.ProbeRecord) (*StatusEv
 
 	// 保存事件（如有）
 	if event != nil {
-		if err := s.storage.SaveStatusEvent(event); err != nil {
+		if err := s.saveEvent(event); err != nil {
 			logger.Error("events", "保存状态事件失败",
 				"provider", record.Provider, "service", record.Service, "channel", record.Channel,
 				"event_type", event.EventType,
@@ -386,7 +816,7 @@ func (s *Service) processRecordChannelMode(record *storage.ProbeRecord) (*Status
 		}
 
 		// 保存事件
-		if err := s.storage.SaveStatusEvent(result.Event); err != nil {
+		if err := s.saveEvent(result.Event); err != nil {
 			logger.Error("events", "保存通道状态事件失败",
 				"provider", record.Provider, "service", record.Service, "channel", record.Channel,
 				"event_type", result.Event.EventType,
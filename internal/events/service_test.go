@@ -0,0 +1,77 @@
+package events
+
+import (
+	"testing"
+
+	"monitor/internal/storage"
+)
+
+func TestService_UpdateConfig(t *testing.T) {
+	store := newOutboxTestStore(t)
+
+	disabled := ServiceConfig{DetectorConfig: DetectorConfig{DownThreshold: 3, UpThreshold: 1}}
+	svc, err := NewService(disabled, store)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	defer svc.Stop()
+
+	var id int64
+	probe := func(status int) *StatusEvent {
+		t.Helper()
+		id++
+		event, err := svc.ProcessRecord(&storage.ProbeRecord{
+			ID: id, Provider: "p", Service: "cc", Status: status, Timestamp: 1000 + id,
+		})
+		if err != nil {
+			t.Fatalf("ProcessRecord() error = %v", err)
+		}
+		return event
+	}
+
+	// 未启用时不处理探测记录
+	if svc.IsEnabled() || probe(0) != nil {
+		t.Fatal("未启用时不应处理探测记录")
+	}
+	if event, err := svc.EmitSchedulerSaturated(nil); event != nil || err != nil {
+		t.Fatalf("未启用时不应发出事件: %+v, %v", event, err)
+	}
+
+	// 热更新开启
+	enabled := disabled
+	enabled.Enabled = true
+	if err := svc.UpdateConfig(enabled); err != nil {
+		t.Fatalf("UpdateConfig() error = %v", err)
+	}
+	if !svc.IsEnabled() || svc.GetMode() != "model" {
+		t.Fatalf("开启后状态错误: enabled=%v mode=%q", svc.IsEnabled(), svc.GetMode())
+	}
+	if probe(1) != nil || probe(0) != nil || probe(0) != nil {
+		t.Fatal("连续 2 次不可用未达到 down_threshold=3，不应触发事件")
+	}
+
+	// 无效配置被拒绝，沿用原阈值
+	invalid := enabled
+	invalid.DetectorConfig.DownThreshold = 0
+	if err := svc.UpdateConfig(invalid); err == nil {
+		t.Fatal("down_threshold=0 应返回错误")
+	}
+
+	// 调低阈值后已累计的连续计数按新阈值判定
+	lowered := enabled
+	lowered.DetectorConfig.DownThreshold = 2
+	if err := svc.UpdateConfig(lowered); err != nil {
+		t.Fatalf("UpdateConfig() error = %v", err)
+	}
+	if event := probe(0); event == nil || event.EventType != EventTypeDown {
+		t.Fatalf("down_threshold=2 时第 3 次不可用应触发 DOWN, got %+v", event)
+	}
+
+	// 热更新关闭
+	if err := svc.UpdateConfig(disabled); err != nil {
+		t.Fatalf("UpdateConfig() error = %v", err)
+	}
+	if svc.IsEnabled() || probe(1) != nil {
+		t.Fatal("关闭后不应处理探测记录")
+	}
+}
@@ -10,6 +10,20 @@ type EventType = storage.EventType
 const (
 	EventTypeDown = storage.EventTypeDown // 可用 → 不可用
 	EventTypeUp   = storage.EventTypeUp   // 不可用 → 可用
+
+	EventTypeCertExpiring = storage.EventTypeCertExpiring // 证书剩余天数低于阈值
+	EventTypeContentDrift = storage.EventTypeContentDrift // 响应指纹持续偏离基线
+
+	EventTypeDegradedStart = storage.EventTypeDegradedStart // 连续黄色（性能下降）
+	EventTypeDegradedEnd   = storage.EventTypeDegradedEnd   // 从性能下降恢复为绿色
+
+	EventTypeSchedulerSaturated = storage.EventTypeSchedulerSaturated // 调度器并发饱和（内部事件）
+
+	EventTypeSLABreachWarning = storage.EventTypeSLABreachWarning // 服务商错误预算即将耗尽
+	EventTypeSLABreached      = storage.EventTypeSLABreached      // 服务商错误预算已耗尽
+
+	EventTypeBoardDemoted  = storage.EventTypeBoardDemoted  // 监测项自动降级板块
+	EventTypeBoardPromoted = storage.EventTypeBoardPromoted // 监测项自动升回板块
 )
 
 // ServiceState 服务状态（复用 storage 定义）
@@ -28,12 +42,33 @@ type DetectorConfig struct {
 
 	// UpThreshold 连续 N 次可用触发 UP 事件（默认 1）
 	UpThreshold int
+
+	// CertExpiryDays 证书剩余天数低于该值时触发 CERT_EXPIRING 事件（默认 14，0 表示关闭）
+	CertExpiryDays int
+
+	// DegradedThreshold 连续 N 次黄色触发 DEGRADED_START 事件（默认 0，表示关闭）
+	DegradedThreshold int
+
+	// DegradedRecoverThreshold 性能下降后连续 N 次绿色触发 DEGRADED_END 事件（默认 1）
+	DegradedRecoverThreshold int
+}
+
+// DegradationState 单个监测项的性能下降检测状态（独立于 DOWN/UP 状态机）
+type DegradationState struct {
+	// Degraded 是否处于性能下降状态（已触发 DEGRADED_START 且未恢复）
+	Degraded bool
+
+	// Streak 未下降时为连续黄色次数，下降中为连续绿色次数
+	Streak int
 }
 
 // DefaultConfig 返回默认配置
 func DefaultConfig() DetectorConfig {
 	return DetectorConfig{
-		DownThreshold: 2,
-		UpThreshold:   1,
+		DownThreshold:            2,
+		UpThreshold:              1,
+		CertExpiryDays:           14,
+		DegradedThreshold:        0,
+		DegradedRecoverThreshold: 1,
 	}
 }
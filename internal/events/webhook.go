@@ -0,0 +1,293 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+const (
+	webhookMaxConcurrent = 16              // 同时进行中的投递数上限（含退避等待）
+	webhookMaxBackoff    = 5 * time.Minute // 单次重试间隔上限
+)
+
+// webhookBaseBackoff 首次重试间隔，之后按 2 倍递增（测试中可替换）
+var webhookBaseBackoff = time.Second
+
+// Webhook 请求头
+const (
+	WebhookHeaderEvent     = "X-RelayPulse-Event"     // 事件类型
+	WebhookHeaderDelivery  = "X-RelayPulse-Delivery"  // 事件 ID（接收端可据此去重）
+	WebhookHeaderTimestamp = "X-RelayPulse-Timestamp" // 签名时间戳（Unix 秒）
+	WebhookHeaderSignature = "X-RelayPulse-Signature" // sha256=<hex>
+)
+
+// WebhookPayload 推送的事件内容（字段与 /api/events 返回的单个事件一致）
+type WebhookPayload struct {
+	ID              int64          `json:"id"`
+	Provider        string         `json:"provider"`
+	Service         string         `json:"service"`
+	Channel         string         `json:"channel,omitempty"`
+	Model           string         `json:"model,omitempty"`
+	Type            string         `json:"type"`
+	FromStatus      int            `json:"from_status"`
+	ToStatus        int            `json:"to_status"`
+	TriggerRecordID int64          `json:"trigger_record_id"`
+	ObservedAt      int64          `json:"observed_at"`
+	CreatedAt       int64          `json:"created_at"`
+	Meta            map[string]any `json:"meta,omitempty"`
+}
+
+// webhookTarget 单个推送目标（已规范化）
+type webhookTarget struct {
+	url         string
+	secret      string
+	types       map[EventType]bool // nil 表示全部
+	maxAttempts int
+	client      *http.Client
+}
+
+// WebhookDispatcher 事件 Webhook 推送器
+//
+// 每个 (事件, 目标) 独立投递：2xx 视为成功；网络错误、408/429 与 5xx 按指数退避重试，
+// 其它 4xx 不重试。重试耗尽（或关闭时仍未成功）的投递写入死信表并记录错误日志。
+// 启用发件箱后投递与重试进度持久化在数据库中，关闭时未完成的投递留待重启后继续（见 outbox.go）。
+type WebhookDispatcher struct {
+	targets []*webhookTarget
+	storage storage.Storage
+
+	sem    chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// 事件发件箱（nil 表示未启用，见 EnableOutbox）
+	outbox    storage.EventOutboxStorage
+	outboxCfg config.EventOutboxConfig
+	wake      chan struct{}
+}
+
+// NewWebhookDispatcher 创建事件 Webhook 推送器（未配置目标时返回 nil）
+func NewWebhookDispatcher(cfgs []config.EventWebhookConfig, store storage.Storage) *WebhookDispatcher {
+	if len(cfgs) == 0 {
+		return nil
+	}
+
+	targets := make([]*webhookTarget, 0, len(cfgs))
+	for _, c := range cfgs {
+		t := &webhookTarget{
+			url:         c.URL,
+			secret:      c.Secret,
+			maxAttempts: c.MaxAttempts,
+			client:      &http.Client{Timeout: c.TimeoutDuration},
+		}
+		if len(c.Types) > 0 {
+			t.types = make(map[EventType]bool, len(c.Types))
+			for _, typ := range c.Types {
+				t.types[EventType(typ)] = true
+			}
+		}
+		targets = append(targets, t)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WebhookDispatcher{
+		targets: targets,
+		storage: store,
+		sem:     make(chan struct{}, webhookMaxConcurrent),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Publish 异步推送已落库的事件（nil 推送器或事件 ID 为 0 的重复事件直接忽略）
+func (d *WebhookDispatcher) Publish(event *StatusEvent) {
+	if d == nil || event == nil || event.ID == 0 || d.ctx.Err() != nil {
+		return
+	}
+
+	body, err := webhookBody(event)
+	if err != nil {
+		logger.Error("events", "编码 Webhook 事件失败", "event_id", event.ID, "error", err)
+		return
+	}
+
+	for _, t := range d.targets {
+		if !t.accepts(event.EventType) {
+			continue
+		}
+		d.wg.Add(1)
+		go d.deliver(t, event.ID, event.EventType, body)
+	}
+}
+
+// accepts 判断目标是否订阅该类型的事件
+func (t *webhookTarget) accepts(eventType EventType) bool {
+	return t.types == nil || t.types[eventType]
+}
+
+// webhookBody 编码推送的事件内容
+func webhookBody(event *StatusEvent) ([]byte, error) {
+	return json.Marshal(WebhookPayload{
+		ID:              event.ID,
+		Provider:        event.Provider,
+		Service:         event.Service,
+		Channel:         event.Channel,
+		Model:           event.Model,
+		Type:            string(event.EventType),
+		FromStatus:      event.FromStatus,
+		ToStatus:        event.ToStatus,
+		TriggerRecordID: event.TriggerRecordID,
+		ObservedAt:      event.ObservedAt,
+		CreatedAt:       event.CreatedAt,
+		Meta:            event.Meta,
+	})
+}
+
+// Stop 取消退避中的重试（未成功的投递写入死信），等待所有投递结束
+func (d *WebhookDispatcher) Stop() {
+	if d == nil {
+		return
+	}
+	d.cancel()
+	d.wg.Wait()
+}
+
+// deliver 向单个目标投递事件（含重试）
+func (d *WebhookDispatcher) deliver(t *webhookTarget, eventID int64, eventType EventType, body []byte) {
+	defer d.wg.Done()
+
+	// 有空闲名额时直接投递（关闭过程中也保证首次尝试），否则排队等待
+	select {
+	case d.sem <- struct{}{}:
+	default:
+		select {
+		case d.sem <- struct{}{}:
+		case <-d.ctx.Done():
+			d.deadLetter(t, eventID, eventType, body, 0, fmt.Errorf("服务关闭，投递排队中被取消"))
+			return
+		}
+	}
+	defer func() { <-d.sem }()
+
+	var lastErr error
+	attempts := 0
+	for attempts < t.maxAttempts {
+		if attempts > 0 {
+			timer := time.NewTimer(webhookBackoff(attempts))
+			select {
+			case <-timer.C:
+			case <-d.ctx.Done():
+				timer.Stop()
+				d.deadLetter(t, eventID, eventType, body, attempts, fmt.Errorf("服务关闭，放弃重试（上次错误: %v）", lastErr))
+				return
+			}
+		}
+
+		attempts++
+		retryable, err := d.send(t, eventID, eventType, body)
+		if err == nil {
+			logger.Debug("events", "Webhook 推送成功", "url", t.url, "event_id", eventID, "attempts", attempts)
+			return
+		}
+		lastErr = err
+		logger.Warn("events", "Webhook 推送失败", "url", t.url, "event_id", eventID, "attempt", attempts, "error", err)
+		if !retryable {
+			break
+		}
+	}
+
+	d.deadLetter(t, eventID, eventType, body, attempts, lastErr)
+}
+
+// send 发送一次请求，返回错误是否可重试
+func (d *WebhookDispatcher) send(t *webhookTarget, eventID int64, eventType EventType, body []byte) (bool, error) {
+	// 不绑定 d.ctx：关闭时让进行中的请求在超时内完成，仅取消后续重试
+	req, err := http.NewRequest(http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "relay-pulse-webhook")
+	req.Header.Set(WebhookHeaderEvent, string(eventType))
+	req.Header.Set(WebhookHeaderDelivery, strconv.FormatInt(eventID, 10))
+	if t.secret != "" {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookHeaderTimestamp, ts)
+		req.Header.Set(WebhookHeaderSignature, SignWebhook(t.secret, ts, body))
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout
+	return retryable, fmt.Errorf("接收端返回 HTTP %d", resp.StatusCode)
+}
+
+// deadLetter 记录投递失败的事件
+func (d *WebhookDispatcher) deadLetter(t *webhookTarget, eventID int64, eventType EventType, body []byte, attempts int, lastErr error) {
+	errMsg := ""
+	if lastErr != nil {
+		errMsg = lastErr.Error()
+	}
+	logger.Error("events", "Webhook 推送最终失败，已写入死信",
+		"url", t.url, "event_id", eventID, "event_type", eventType, "attempts", attempts, "error", errMsg)
+
+	ds, ok := d.storage.(storage.WebhookDeadLetterStorage)
+	if !ok {
+		return
+	}
+	if err := ds.SaveWebhookDeadLetter(&storage.WebhookDeadLetter{
+		URL:       t.url,
+		EventID:   eventID,
+		EventType: eventType,
+		Payload:   string(body),
+		Attempts:  attempts,
+		LastError: errMsg,
+		CreatedAt: time.Now().Unix(),
+	}); err != nil {
+		logger.Error("events", "保存 Webhook 死信失败", "url", t.url, "event_id", eventID, "error", err)
+	}
+}
+
+// webhookBackoff 第 n 次重试前的等待时间（n 从 1 开始）
+func webhookBackoff(n int) time.Duration {
+	if n > 20 {
+		return webhookMaxBackoff
+	}
+	backoff := webhookBaseBackoff << (n - 1)
+	if backoff > webhookMaxBackoff {
+		return webhookMaxBackoff
+	}
+	return backoff
+}
+
+// SignWebhook 计算 Webhook 签名：sha256=hex(HMAC-SHA256(secret, "<timestamp>.<body>"))
+// 接收端按同样方式计算并以常量时间比较，同时校验时间戳新鲜度以防重放
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,186 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+// deadLetterStore 仅实现死信写入的存储桩
+type deadLetterStore struct {
+	storage.Storage
+	mu      sync.Mutex
+	letters []*storage.WebhookDeadLetter
+}
+
+func (s *deadLetterStore) SaveWebhookDeadLetter(dl *storage.WebhookDeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.letters = append(s.letters, dl)
+	return nil
+}
+
+func (s *deadLetterStore) GetWebhookDeadLetters(storage.WebhookDeadLetterFilter) ([]*storage.WebhookDeadLetter, error) {
+	return nil, nil
+}
+
+func newTestWebhookDispatcher(t *testing.T, store storage.Storage, cfgs ...config.EventWebhookConfig) *WebhookDispatcher {
+	t.Helper()
+	for i := range cfgs {
+		if err := cfgs[i].Normalize(i); err != nil {
+			t.Fatalf("Normalize() failed: %v", err)
+		}
+	}
+	return NewWebhookDispatcher(cfgs, store)
+}
+
+func TestWebhookDispatcher_SignedDelivery(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	got := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- received{header: r.Header.Clone(), body: body}
+	}))
+	defer srv.Close()
+
+	d := newTestWebhookDispatcher(t, &deadLetterStore{}, config.EventWebhookConfig{URL: srv.URL, Secret: "s3cret"})
+	d.Publish(&StatusEvent{ID: 42, Provider: "p", Service: "s", EventType: EventTypeDown, ToStatus: 0})
+	d.Stop()
+
+	var r received
+	select {
+	case r = <-got:
+	default:
+		t.Fatal("Webhook 未收到请求")
+	}
+
+	if r.header.Get(WebhookHeaderEvent) != "DOWN" || r.header.Get(WebhookHeaderDelivery) != "42" {
+		t.Errorf("unexpected headers: %v", r.header)
+	}
+	ts := r.header.Get(WebhookHeaderTimestamp)
+	if want := SignWebhook("s3cret", ts, r.body); r.header.Get(WebhookHeaderSignature) != want {
+		t.Errorf("signature = %s, want %s", r.header.Get(WebhookHeaderSignature), want)
+	}
+
+	var payload WebhookPayload
+	if err := json.Unmarshal(r.body, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.ID != 42 || payload.Type != "DOWN" || payload.Provider != "p" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestWebhookDispatcher_TypeFilterAndDuplicate(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	d := newTestWebhookDispatcher(t, &deadLetterStore{}, config.EventWebhookConfig{URL: srv.URL, Types: []string{"up"}})
+	d.Publish(&StatusEvent{ID: 1, EventType: EventTypeDown})
+	d.Publish(&StatusEvent{ID: 0, EventType: EventTypeUp}) // 重复事件（未落库）
+	d.Publish(&StatusEvent{ID: 2, EventType: EventTypeUp})
+	d.Stop()
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("calls = %d, want 1", n)
+	}
+}
+
+func TestWebhookDispatcher_RetryThenDeadLetter(t *testing.T) {
+	orig := webhookBaseBackoff
+	webhookBaseBackoff = time.Millisecond
+	defer func() { webhookBaseBackoff = orig }()
+
+	var calls atomic.Int32
+	var reject atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case reject.Load():
+			w.WriteHeader(http.StatusBadRequest)
+		case calls.Load() < 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	store := &deadLetterStore{}
+	d := newTestWebhookDispatcher(t, store, config.EventWebhookConfig{URL: srv.URL, MaxAttempts: 3})
+
+	// 第 3 次成功：不写死信
+	d.Publish(&StatusEvent{ID: 1, EventType: EventTypeDown})
+	waitFor(t, func() bool { return calls.Load() == 3 })
+
+	// 400 不重试，直接写死信
+	reject.Store(true)
+	d.Publish(&StatusEvent{ID: 2, EventType: EventTypeUp})
+	d.Stop()
+
+	if n := calls.Load(); n != 4 {
+		t.Fatalf("calls = %d, want 4", n)
+	}
+	if len(store.letters) != 1 {
+		t.Fatalf("dead letters = %d, want 1", len(store.letters))
+	}
+	dl := store.letters[0]
+	if dl.EventID != 2 || dl.Attempts != 1 || dl.URL != srv.URL || dl.LastError == "" {
+		t.Errorf("unexpected dead letter: %+v", dl)
+	}
+}
+
+func TestWebhookDispatcher_StopDeadLettersPendingRetry(t *testing.T) {
+	orig := webhookBaseBackoff
+	webhookBaseBackoff = time.Hour
+	defer func() { webhookBaseBackoff = orig }()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	store := &deadLetterStore{}
+	d := newTestWebhookDispatcher(t, store, config.EventWebhookConfig{URL: srv.URL})
+	d.Publish(&StatusEvent{ID: 7, EventType: EventTypeDown})
+	waitFor(t, func() bool { return calls.Load() == 1 })
+	d.Stop()
+
+	if len(store.letters) != 1 || store.letters[0].EventID != 7 || store.letters[0].Attempts != 1 {
+		t.Fatalf("unexpected dead letters: %+v", store.letters)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	if webhookBackoff(1) != time.Second || webhookBackoff(3) != 4*time.Second {
+		t.Errorf("unexpected backoff: %v %v", webhookBackoff(1), webhookBackoff(3))
+	}
+	if webhookBackoff(12) != webhookMaxBackoff || webhookBackoff(100) != webhookMaxBackoff {
+		t.Errorf("backoff should be capped at %v", webhookMaxBackoff)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("等待条件超时")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
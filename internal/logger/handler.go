@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// componentHandler 按组件级别过滤并对重复日志采样的 slog.Handler
+type componentHandler struct {
+	next      slog.Handler
+	level     slog.Level
+	component string
+	sampler   *sampler // nil 表示不采样
+}
+
+// Enabled 按组件生效级别过滤
+func (h *componentHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level && h.next.Enabled(ctx, level)
+}
+
+// Handle 输出日志（采样丢弃的日志直接返回）
+func (h *componentHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.sampler != nil && !h.sampler.allow(h.component, r.Level, r.Message, r.Time) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs 附加属性（保留级别与采样配置）
+func (h *componentHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithAttrs(attrs)
+	return &clone
+}
+
+// WithGroup 开启属性分组（保留级别与采样配置）
+func (h *componentHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	return &clone
+}
+
+// sampleKey 采样计数键：同一组件、级别与消息视为重复日志（不区分属性）
+type sampleKey struct {
+	component string
+	level     slog.Level
+	msg       string
+}
+
+// sampler 按时间窗口对重复日志采样
+// 窗口结束后的第一条采样日志触发上一窗口的丢弃汇总，便于在日志平台中确认被采样的量
+type sampler struct {
+	initial    int
+	thereafter int
+	tick       time.Duration
+	all        bool
+	components map[string]bool
+	report     slog.Handler // 输出丢弃汇总（不经过采样）
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[sampleKey]int
+}
+
+func newSampler(opts SamplingOptions, report slog.Handler) *sampler {
+	s := &sampler{
+		initial:    opts.Initial,
+		thereafter: opts.Thereafter,
+		tick:       opts.Tick,
+		all:        slices.Contains(opts.Components, "*"),
+		components: make(map[string]bool, len(opts.Components)),
+		report:     report,
+		counts:     make(map[sampleKey]int),
+	}
+	for _, c := range opts.Components {
+		s.components[c] = true
+	}
+	return s
+}
+
+// covers 组件是否参与采样
+func (s *sampler) covers(component string) bool {
+	return s.all || s.components[component]
+}
+
+// allow 判断本条日志是否输出
+func (s *sampler) allow(component string, level slog.Level, msg string, now time.Time) bool {
+	key := sampleKey{component: component, level: level, msg: msg}
+
+	s.mu.Lock()
+	var dropped map[sampleKey]int
+	if now.Sub(s.windowStart) >= s.tick {
+		dropped = s.droppedLocked()
+		s.windowStart = now
+		clear(s.counts)
+	}
+	s.counts[key]++
+	n := s.counts[key]
+	windowStart := s.windowStart
+	s.mu.Unlock()
+
+	for k, count := range dropped {
+		s.reportDropped(k, count, windowStart)
+	}
+
+	if n <= s.initial {
+		return true
+	}
+	return s.thereafter > 0 && (n-s.initial)%s.thereafter == 0
+}
+
+// droppedLocked 统计当前窗口各键被丢弃的条数（调用方持有 mu）
+func (s *sampler) droppedLocked() map[sampleKey]int {
+	var dropped map[sampleKey]int
+	for k, n := range s.counts {
+		if n <= s.initial {
+			continue
+		}
+		kept := 0
+		if s.thereafter > 0 {
+			kept = (n - s.initial) / s.thereafter
+		}
+		if d := n - s.initial - kept; d > 0 {
+			if dropped == nil {
+				dropped = make(map[sampleKey]int)
+			}
+			dropped[k] = d
+		}
+	}
+	return dropped
+}
+
+// reportDropped 输出上一窗口的丢弃汇总
+func (s *sampler) reportDropped(k sampleKey, count int, now time.Time) {
+	r := slog.NewRecord(now, slog.LevelInfo, "日志采样：上一窗口丢弃了重复日志", 0)
+	r.AddAttrs(
+		slog.String("component", "logger"),
+		slog.String("sampled_component", k.component),
+		slog.String("sampled_level", k.level.String()),
+		slog.String("sampled_msg", k.msg),
+		slog.Int("dropped", count),
+		slog.Duration("window", s.tick),
+	)
+	_ = s.report.Handle(context.Background(), r)
+}
@@ -1,36 +1,185 @@
 // Package logger 提供统一的结构化日志支持
 // 基于 Go 1.21+ 标准库 log/slog，不引入额外依赖
+//
+// 支持按组件设置日志级别、console / json 两种输出格式、重复日志采样与按大小/时间轮转的日志文件，
+// 通过 Configure 在启动和配置热更新时应用
 package logger
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// 输出格式
+const (
+	FormatConsole = "console" // 人类可读的 key=value 文本（默认）
+	FormatJSON    = "json"    // 每行一个 JSON 对象，便于 Loki / ELK 采集
+)
+
+// Options 日志配置（由 config.LoggingConfig 转换）
+type Options struct {
+	Level      slog.Level            // 默认级别
+	Components map[string]slog.Level // 按组件覆盖的级别（key 为 logger.Info 等的 component 参数）
+	Format     string                // console / json
+	Sampling   SamplingOptions
+	File       FileOptions
+}
+
+// SamplingOptions 重复日志采样配置
+// 每个 tick 窗口内，同一组件、级别与消息的日志先完整输出 Initial 条，之后每 Thereafter 条输出 1 条
+type SamplingOptions struct {
+	Enabled    bool
+	Initial    int
+	Thereafter int
+	Tick       time.Duration
+	Components []string // 参与采样的组件（包含 "*" 表示全部）
+}
+
+// FileOptions 日志文件配置（Path 为空表示只输出到 stdout）
+type FileOptions struct {
+	Path           string
+	MaxSizeMB      int           // 单个文件的最大大小，超过后轮转
+	RotateInterval time.Duration // 按时间轮转的间隔（0 表示仅按大小轮转）
+	MaxAge         time.Duration // 轮转文件的最长保留时间（0 表示不按时间清理）
+	MaxBackups     int           // 最多保留的轮转文件数（0 表示不按数量清理）
+	Compress       bool          // 轮转后 gzip 压缩
+	Stdout         bool          // 写文件的同时输出到 stdout
+}
+
+// state 当前生效的日志配置（整体替换，读取无锁）
+type state struct {
+	root       slog.Handler // 格式化输出的底层 handler（级别过滤由 componentHandler 完成）
+	level      slog.Level
+	components map[string]slog.Level
+	sampler    *sampler
+}
+
 var (
-	defaultLogger *slog.Logger
-	initOnce      sync.Once
+	current atomic.Pointer[state]
+
+	// configMu 串行化 Configure / SetOutput，并保护以下字段
+	configMu sync.Mutex
+	options  = Options{Format: FormatConsole, Level: slog.LevelInfo}
+	console  = io.Writer(os.Stdout)
+	file     *rotatingFile
 )
 
 // 初始化默认 logger
 func init() {
-	initOnce.Do(func() {
-		defaultLogger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-			Level: slog.LevelInfo,
-		})).With("app", "relay-pulse")
-	})
+	current.Store(newState(options, console))
+}
+
+// newState 按配置构建日志状态
+func newState(opts Options, w io.Writer) *state {
+	handlerOpts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var root slog.Handler
+	if opts.Format == FormatJSON {
+		root = slog.NewJSONHandler(w, handlerOpts)
+	} else {
+		root = slog.NewTextHandler(w, handlerOpts)
+	}
+	root = root.WithAttrs([]slog.Attr{slog.String("app", "relay-pulse")})
+
+	st := &state{root: root, level: opts.Level, components: opts.Components}
+	if opts.Sampling.Enabled {
+		st.sampler = newSampler(opts.Sampling, root)
+	}
+	return st
+}
+
+// levelFor 返回组件生效的日志级别
+func (s *state) levelFor(component string) slog.Level {
+	if level, ok := s.components[component]; ok {
+		return level
+	}
+	return s.level
+}
+
+// Configure 应用日志配置（启动与配置热更新时调用）
+// 日志文件路径或轮转参数变化时打开新文件并关闭旧文件
+func Configure(opts Options) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	var prevFile *rotatingFile
+	if opts.File.Path != "" {
+		if file == nil || file.opts != opts.File {
+			f, err := openRotatingFile(opts.File)
+			if err != nil {
+				return fmt.Errorf("打开日志文件失败: %w", err)
+			}
+			prevFile, file = file, f
+		}
+	} else if file != nil {
+		prevFile, file = file, nil
+	}
+
+	options = opts
+	current.Store(newState(options, outputWriter()))
+	if prevFile != nil {
+		_ = prevFile.Close()
+	}
+	return nil
+}
+
+// SetOutput 将日志的控制台输出重定向到 w（需在产生日志前调用）
+// 命令行工具输出结构化结果到 stdout 时，可将日志改写到 stderr
+func SetOutput(w io.Writer) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	console = w
+	current.Store(newState(options, outputWriter()))
+}
+
+// Close 关闭日志文件（进程退出前调用，确保缓冲写入落盘）
+func Close() error {
+	configMu.Lock()
+	defer configMu.Unlock()
+	if file == nil {
+		return nil
+	}
+	err := file.Close()
+	file = nil
+	current.Store(newState(options, console))
+	return err
+}
+
+// outputWriter 返回当前配置下的输出目标（调用方持有 configMu）
+func outputWriter() io.Writer {
+	switch {
+	case file == nil:
+		return console
+	case options.File.Stdout:
+		return io.MultiWriter(file, console)
+	default:
+		return file
+	}
 }
 
 // Default 返回默认 logger
 func Default() *slog.Logger {
-	return defaultLogger
+	st := current.Load()
+	return slog.New(&componentHandler{next: st.root, level: st.level})
 }
 
-// WithComponent 创建带有组件标识的 logger
+// WithComponent 创建带有组件标识的 logger（应用组件级别与采样配置）
 func WithComponent(component string) *slog.Logger {
-	return defaultLogger.With("component", component)
+	st := current.Load()
+	h := &componentHandler{
+		next:      st.root.WithAttrs([]slog.Attr{slog.String("component", component)}),
+		level:     st.levelFor(component),
+		component: component,
+	}
+	if st.sampler != nil && st.sampler.covers(component) {
+		h.sampler = st.sampler
+	}
+	return slog.New(h)
 }
 
 // context key 类型（避免与其他包冲突）
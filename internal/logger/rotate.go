@@ -0,0 +1,241 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat 轮转文件名中的时间戳格式（relay-pulse-2006-01-02T15-04-05.000.log）
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// rotatingFile 按大小 / 时间轮转的日志文件
+// 轮转时将当前文件重命名为带时间戳的备份文件，随后在后台压缩并按数量与保留时间清理旧备份
+type rotatingFile struct {
+	opts FileOptions
+
+	mu       sync.Mutex
+	f        *os.File
+	size     int64
+	openedAt time.Time
+
+	// millMu 串行化后台压缩与清理
+	millMu sync.Mutex
+	wg     sync.WaitGroup
+}
+
+// openRotatingFile 打开（追加）日志文件，目录不存在时自动创建
+func openRotatingFile(opts FileOptions) (*rotatingFile, error) {
+	r := &rotatingFile{opts: opts}
+	if err := os.MkdirAll(filepath.Dir(opts.Path), 0o755); err != nil {
+		return nil, err
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open 打开当前日志文件（调用方持有 mu 或尚未共享）
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.opts.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f = f
+	r.size = info.Size()
+	r.openedAt = time.Now()
+	return nil
+}
+
+// Write 写入一条日志，写入前检查是否需要轮转
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return 0, os.ErrClosed
+	}
+
+	if r.size > 0 && r.shouldRotate(int64(len(p)), time.Now()) {
+		if err := r.rotate(); err != nil {
+			// 轮转失败时继续写入当前文件，避免丢日志
+			fmt.Fprintf(os.Stderr, "日志文件轮转失败: %v\n", err)
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// shouldRotate 写入 n 字节前是否需要轮转
+func (r *rotatingFile) shouldRotate(n int64, now time.Time) bool {
+	if r.opts.MaxSizeMB > 0 && r.size+n > int64(r.opts.MaxSizeMB)*1024*1024 {
+		return true
+	}
+	return r.opts.RotateInterval > 0 && now.Sub(r.openedAt) >= r.opts.RotateInterval
+}
+
+// rotate 将当前文件重命名为备份并重新打开（调用方持有 mu）
+func (r *rotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+	backup := r.backupName(time.Now())
+	if err := os.Rename(r.opts.Path, backup); err != nil {
+		// 重命名失败：重新打开原文件继续写入
+		if openErr := r.open(); openErr != nil {
+			return openErr
+		}
+		return err
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.mill(backup)
+	}()
+	return nil
+}
+
+// backupName 生成备份文件名：<name>-<时间戳><ext>
+func (r *rotatingFile) backupName(t time.Time) string {
+	dir := filepath.Dir(r.opts.Path)
+	base := filepath.Base(r.opts.Path)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext)
+	return filepath.Join(dir, prefix+"-"+t.Format(backupTimeFormat)+ext)
+}
+
+// mill 压缩刚轮转的备份，并清理超出数量或保留时间的旧备份
+func (r *rotatingFile) mill(backup string) {
+	r.millMu.Lock()
+	defer r.millMu.Unlock()
+
+	if r.opts.Compress {
+		// 备份可能已被更早触发的清理删除（短时间内连续轮转）
+		if err := compressFile(backup); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "压缩轮转日志失败: %v\n", err)
+		}
+	}
+	if err := r.cleanup(time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "清理轮转日志失败: %v\n", err)
+	}
+}
+
+// logBackup 已轮转的备份文件
+type logBackup struct {
+	path string
+	at   time.Time
+}
+
+// backups 列出当前日志文件的备份（按时间从新到旧）
+func (r *rotatingFile) backups() ([]logBackup, error) {
+	dir := filepath.Dir(r.opts.Path)
+	base := filepath.Base(r.opts.Path)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var list []logBackup
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimPrefix(name, prefix)
+		stamp = strings.TrimSuffix(stamp, ".gz")
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		at, err := time.ParseInLocation(backupTimeFormat, strings.TrimSuffix(stamp, ext), time.Local)
+		if err != nil {
+			continue
+		}
+		list = append(list, logBackup{path: filepath.Join(dir, name), at: at})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].at.After(list[j].at) })
+	return list, nil
+}
+
+// cleanup 删除超出 MaxBackups 或早于 MaxAge 的备份
+func (r *rotatingFile) cleanup(now time.Time) error {
+	if r.opts.MaxBackups <= 0 && r.opts.MaxAge <= 0 {
+		return nil
+	}
+	list, err := r.backups()
+	if err != nil {
+		return err
+	}
+	for i, b := range list {
+		expired := r.opts.MaxAge > 0 && now.Sub(b.at) > r.opts.MaxAge
+		excess := r.opts.MaxBackups > 0 && i >= r.opts.MaxBackups
+		if expired || excess {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// compressFile 将文件压缩为 <path>.gz 并删除原文件
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		gz.Close()
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	src.Close()
+	return os.Remove(path)
+}
+
+// Close 关闭日志文件并等待后台压缩与清理完成
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	var err error
+	if r.f != nil {
+		err = r.f.Close()
+		r.f = nil
+	}
+	r.mu.Unlock()
+	r.wg.Wait()
+	return err
+}
@@ -0,0 +1,94 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// chaosFault 故障注入类型
+type chaosFault int
+
+const (
+	chaosNone chaosFault = iota
+	chaosTimeout
+	chaosServerError
+	chaosSlow
+)
+
+func (f chaosFault) String() string {
+	switch f {
+	case chaosTimeout:
+		return "timeout"
+	case chaosServerError:
+		return "server_error"
+	case chaosSlow:
+		return "slow"
+	}
+	return "none"
+}
+
+// rollChaos 按配置概率抽取本次探测注入的故障类型（roll 取值 [0,1)，settings 为 nil 表示未启用）
+func rollChaos(settings *config.ChaosSettings, roll float64) chaosFault {
+	if settings == nil {
+		return chaosNone
+	}
+	switch {
+	case roll < settings.TimeoutRate:
+		return chaosTimeout
+	case roll < settings.TimeoutRate+settings.ServerErrorRate:
+		return chaosServerError
+	case roll < settings.TimeoutRate+settings.ServerErrorRate+settings.SlowRate:
+		return chaosSlow
+	}
+	return chaosNone
+}
+
+// injectChaosFault 模拟超时或服务端错误（不发起真实请求）
+// 超时会等待 ctx 到期（即监测项 timeout），与真实超时占用相同的探测时长
+func injectChaosFault(ctx context.Context, cfg *config.ServiceConfig, fault chaosFault, result *ProbeResult) {
+	start := time.Now()
+	switch fault {
+	case chaosTimeout:
+		<-ctx.Done()
+		result.Status = 0
+		result.SubStatus = storage.SubStatusNetworkError
+		result.Error = fmt.Errorf("chaos: 注入超时: %w", ctx.Err())
+	case chaosServerError:
+		result.Status = 0
+		result.SubStatus = storage.SubStatusServerError
+		result.HttpCode = cfg.ChaosSettings.ServerErrorCode
+		result.Error = errors.New("chaos: 注入服务端错误")
+	}
+	result.Latency = int(time.Since(start) / time.Millisecond)
+
+	logger.Warn("probe", "chaos 故障注入",
+		"provider", cfg.Provider, "service", cfg.Service, "channel", cfg.Channel, "model", cfg.Model,
+		"fault", fault.String(), "status", result.Status, "sub_status", result.SubStatus, "http_code", result.HttpCode)
+}
+
+// applyChaosSlow 模拟慢响应：真实探测完成后额外等待并计入延迟，绿色结果超过 slow_latency 时降级为黄色
+func applyChaosSlow(ctx context.Context, cfg *config.ServiceConfig, result *ProbeResult) {
+	delay := cfg.ChaosSettings.SlowDelay
+	start := time.Now()
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+	}
+	result.Latency += int(time.Since(start) / time.Millisecond)
+
+	slowLatency := cfg.SlowLatencyDuration
+	if result.Status == 1 && slowLatency > 0 && result.Latency > int(slowLatency/time.Millisecond) {
+		result.Status = 2
+		result.SubStatus = storage.SubStatusSlowLatency
+	}
+
+	logger.Warn("probe", "chaos 故障注入",
+		"provider", cfg.Provider, "service", cfg.Service, "channel", cfg.Channel, "model", cfg.Model,
+		"fault", chaosSlow.String(), "delay", delay, "latency_ms", result.Latency, "status", result.Status)
+}
@@ -0,0 +1,71 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestRollChaos(t *testing.T) {
+	t.Parallel()
+
+	settings := &config.ChaosSettings{TimeoutRate: 0.1, ServerErrorRate: 0.2, SlowRate: 0.3}
+	tests := []struct {
+		roll float64
+		want chaosFault
+	}{
+		{0, chaosTimeout},
+		{0.15, chaosServerError},
+		{0.45, chaosSlow},
+		{0.65, chaosNone},
+		{0.99, chaosNone},
+	}
+	for _, tt := range tests {
+		if got := rollChaos(settings, tt.roll); got != tt.want {
+			t.Errorf("rollChaos(%g) = %s, want %s", tt.roll, got, tt.want)
+		}
+	}
+	if got := rollChaos(nil, 0); got != chaosNone {
+		t.Fatalf("expected no fault without settings, got %s", got)
+	}
+}
+
+func TestInjectChaos(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.ServiceConfig{
+		Provider:            "demo",
+		SlowLatencyDuration: 20 * time.Millisecond,
+		ChaosSettings:       &config.ChaosSettings{ServerErrorCode: 502, SlowDelay: 30 * time.Millisecond},
+	}
+
+	result := &ProbeResult{}
+	injectChaosFault(context.Background(), cfg, chaosServerError, result)
+	if result.Status != 0 || result.SubStatus != storage.SubStatusServerError || result.HttpCode != 502 || result.Error == nil {
+		t.Fatalf("expected injected server error, got %+v", result)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	result = &ProbeResult{}
+	injectChaosFault(ctx, cfg, chaosTimeout, result)
+	if result.Status != 0 || result.SubStatus != storage.SubStatusNetworkError || result.Latency < 10 {
+		t.Fatalf("expected injected timeout after ctx deadline, got %+v", result)
+	}
+
+	result = &ProbeResult{Status: 1, Latency: 5}
+	applyChaosSlow(context.Background(), cfg, result)
+	if result.Status != 2 || result.SubStatus != storage.SubStatusSlowLatency || result.Latency < 35 {
+		t.Fatalf("expected slow_latency after injected delay, got %+v", result)
+	}
+
+	// 已失败的结果只叠加延迟
+	result = &ProbeResult{Status: 0, SubStatus: storage.SubStatusAuthError}
+	applyChaosSlow(context.Background(), cfg, result)
+	if result.Status != 0 || result.SubStatus != storage.SubStatusAuthError {
+		t.Fatalf("expected red result unchanged, got %+v", result)
+	}
+}
@@ -1,49 +1,70 @@
 package monitor
 
 import (
+	"context"
 	"fmt"
+	"maps"
+	"net"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"golang.org/x/net/proxy"
+
+	"monitor/internal/config"
 )
 
-// ClientPool HTTP客户端池（按 provider+proxy 组合管理，复用连接）
+// ClientPool HTTP客户端池（按 provider+proxy+tls+拨号选项 组合管理，复用连接）
 type ClientPool struct {
 	mu      sync.RWMutex
-	clients map[string]*http.Client
+	clients map[string]*pooledClient
+}
+
+// pooledClient 池中的客户端及创建时的 TLS 文件版本
+type pooledClient struct {
+	client     *http.Client
+	tlsVersion string // TLSConfig.FilesVersion()，证书文件轮换后与当前版本不一致
 }
 
 // NewClientPool 创建客户端池
 func NewClientPool() *ClientPool {
 	return &ClientPool{
-		clients: make(map[string]*http.Client),
+		clients: make(map[string]*pooledClient),
 	}
 }
 
 // clientKey 生成客户端缓存键
-// 相同 provider 和 proxy 组合复用同一个客户端
-func clientKey(provider, proxyURL string) string {
-	if proxyURL == "" {
-		return provider
+// 相同 provider、proxy、TLS 配置和拨号选项组合复用同一个客户端
+func clientKey(provider, proxyURL string, tlsCfg *config.TLSConfig, dial dialOptions) string {
+	key := provider
+	if proxyURL != "" {
+		key = fmt.Sprintf("%s|%s", key, proxyURL)
+	}
+	if tlsCfg != nil {
+		key = fmt.Sprintf("%s|%s", key, tlsCfg.CacheKey())
 	}
-	return fmt.Sprintf("%s|%s", provider, proxyURL)
+	if dial.enabled() {
+		key = fmt.Sprintf("%s|%s", key, dial.cacheKey())
+	}
+	return key
 }
 
 // GetClient 获取或创建客户端
-// proxyURL 为空时使用系统环境变量代理
-func (p *ClientPool) GetClient(provider, proxyURL string) (*http.Client, error) {
-	key := clientKey(provider, proxyURL)
+// proxyURL 为空时使用系统环境变量代理；tlsCfg 为 nil 时使用默认 TLS 行为；dial 为零值时使用默认拨号
+// CA、客户端证书或私钥文件变化（修改时间或大小）后重建客户端，轮换的证书无需重启即可生效
+func (p *ClientPool) GetClient(provider, proxyURL string, tlsCfg *config.TLSConfig, dial dialOptions) (*http.Client, error) {
+	key := clientKey(provider, proxyURL, tlsCfg, dial)
+	tlsVersion := tlsCfg.FilesVersion()
 
 	p.mu.RLock()
-	client, exists := p.clients[key]
+	pc, exists := p.clients[key]
 	p.mu.RUnlock()
 
-	if exists {
-		return client, nil
+	if exists && pc.tlsVersion == tlsVersion {
+		return pc.client, nil
 	}
 
 	// 创建新客户端
@@ -51,29 +72,34 @@ func (p *ClientPool) GetClient(provider, proxyURL string) (*http.Client, error)
 	defer p.mu.Unlock()
 
 	// 双重检查
-	if client, exists := p.clients[key]; exists {
-		return client, nil
+	pc, exists = p.clients[key]
+	if exists && pc.tlsVersion == tlsVersion {
+		return pc.client, nil
 	}
 
 	// 创建 Transport
-	transport, err := createTransport(proxyURL)
+	transport, err := createTransport(proxyURL, tlsCfg, dial)
 	if err != nil {
 		return nil, fmt.Errorf("创建 Transport 失败: %w", err)
 	}
 
 	// 创建带连接池的HTTP客户端
 	// 注意：不设置 Timeout，由 probe.go 使用 context.WithTimeout 控制每个请求的超时
-	client = &http.Client{
+	client := &http.Client{
 		Transport: transport,
 	}
 
-	p.clients[key] = client
+	// 证书已轮换：替换旧客户端并关闭其空闲连接（进行中的请求不受影响）
+	if exists {
+		pc.client.CloseIdleConnections()
+	}
+	p.clients[key] = &pooledClient{client: client, tlsVersion: tlsVersion}
 	return client, nil
 }
 
-// createTransport 创建 HTTP Transport，支持代理配置
+// createTransport 创建 HTTP Transport，支持代理、TLS 和拨号选项（地址族、解析覆盖、自定义 DNS）
 // proxyURL 为空时使用系统环境变量代理
-func createTransport(proxyURL string) (http.RoundTripper, error) {
+func createTransport(proxyURL string, tlsCfg *config.TLSConfig, dial dialOptions) (http.RoundTripper, error) {
 	baseTransport := &http.Transport{
 		MaxIdleConns:        100,
 		MaxIdleConnsPerHost: 10,
@@ -81,6 +107,23 @@ func createTransport(proxyURL string) (http.RoundTripper, error) {
 		DisableKeepAlives:   false,
 	}
 
+	// 自定义 TLS（CA、客户端证书、SNI、最低版本）
+	if tlsCfg != nil {
+		clientTLS, err := tlsCfg.Build()
+		if err != nil {
+			return nil, err
+		}
+		baseTransport.TLSClientConfig = clientTLS
+		// 显式设置 TLSClientConfig 后需要手动开启 HTTP/2
+		baseTransport.ForceAttemptHTTP2 = true
+	}
+
+	// 自定义拨号：直连目标地址（配置层已禁止与 proxy 同时使用，这里同样忽略环境变量代理）
+	if dial.enabled() {
+		baseTransport.DialContext = dial.dialContext()
+		return baseTransport, nil
+	}
+
 	// 无自定义代理时，使用系统环境变量
 	if proxyURL == "" {
 		baseTransport.Proxy = http.ProxyFromEnvironment
@@ -111,6 +154,70 @@ func createTransport(proxyURL string) (http.RoundTripper, error) {
 	}
 }
 
+// dialOptions 直连拨号选项，零值表示使用 Transport 默认拨号
+type dialOptions struct {
+	ipFamily  string            // 强制地址族（ipv4 / ipv6）
+	resolve   map[string]string // 小写主机名 -> IP 的解析覆盖
+	dnsServer string            // 自定义 DNS 服务器（host:port）
+}
+
+// dialOptionsOf 从监测项配置提取拨号选项
+func dialOptionsOf(cfg *config.ServiceConfig) dialOptions {
+	return dialOptions{ipFamily: cfg.IPFamily, resolve: cfg.Resolve, dnsServer: cfg.DNSServer}
+}
+
+// enabled 是否需要自定义拨号
+func (o dialOptions) enabled() bool {
+	return o.ipFamily != "" || len(o.resolve) > 0 || o.dnsServer != ""
+}
+
+// cacheKey 返回拨号选项的缓存键片段（resolve 按主机名排序，保证稳定）
+func (o dialOptions) cacheKey() string {
+	pins := make([]string, 0, len(o.resolve))
+	for _, host := range slices.Sorted(maps.Keys(o.resolve)) {
+		pins = append(pins, host+"="+o.resolve[host])
+	}
+	return fmt.Sprintf("family=%s;dns=%s;resolve=%s", o.ipFamily, o.dnsServer, strings.Join(pins, ","))
+}
+
+// dialContext 返回按选项拨号的 DialContext
+// 命中 resolve 的主机名直接连接固定 IP，其余主机名通过自定义 DNS（未配置时为系统解析）解析；
+// 强制地址族时 tcp → tcp4 / tcp6，域名只有另一地址族的记录时解析失败，按网络错误处理
+func (o dialOptions) dialContext() func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if o.dnsServer != "" {
+		server := o.dnsServer
+		dialer.Resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, server)
+			},
+		}
+	}
+	suffix := ""
+	switch o.ipFamily {
+	case config.IPFamilyIPv4:
+		suffix = "4"
+	case config.IPFamilyIPv6:
+		suffix = "6"
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if network == "tcp" {
+			network += suffix
+		}
+		if len(o.resolve) > 0 {
+			if host, port, err := net.SplitHostPort(addr); err == nil {
+				if ip, ok := o.resolve[strings.ToLower(strings.TrimSuffix(host, "."))]; ok {
+					addr = net.JoinHostPort(ip, port)
+				}
+			}
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+}
+
 // createSOCKS5Transport 创建 SOCKS5 代理的 Transport
 func createSOCKS5Transport(proxyURL *url.URL, baseTransport *http.Transport) (*http.Transport, error) {
 	// 提取认证信息
@@ -146,9 +253,9 @@ func (p *ClientPool) Close() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	for _, client := range p.clients {
-		client.CloseIdleConnections()
+	for _, pc := range p.clients {
+		pc.client.CloseIdleConnections()
 	}
 
-	p.clients = make(map[string]*http.Client)
+	p.clients = make(map[string]*pooledClient)
 }
@@ -0,0 +1,64 @@
+package monitor
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+)
+
+func TestClientPoolReloadsRotatedTLSFiles(t *testing.T) {
+	writeCA := func(path string, srv *httptest.Server, mtime time.Time) {
+		t.Helper()
+		data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatalf("write ca: %v", err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	oldSrv := httptest.NewTLSServer(handler)
+	defer oldSrv.Close()
+	newSrv := httptest.NewTLSServer(handler)
+	defer newSrv.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	writeCA(caFile, oldSrv, time.Now().Add(-time.Hour))
+	tlsCfg := &config.TLSConfig{CAFile: caFile}
+
+	pool := NewClientPool()
+	defer pool.Close()
+	first, err := pool.GetClient("p", "", tlsCfg, dialOptions{})
+	if err != nil {
+		t.Fatalf("GetClient: %v", err)
+	}
+	if again, _ := pool.GetClient("p", "", tlsCfg, dialOptions{}); again != first {
+		t.Fatal("unchanged TLS files should reuse the cached client")
+	}
+
+	// 证书轮换：文件路径不变，修改时间变化（httptest 服务器共用同一测试证书，内容相同时也应按修改时间重建）
+	writeCA(caFile, newSrv, time.Now())
+	rotated, err := pool.GetClient("p", "", tlsCfg, dialOptions{})
+	if err != nil {
+		t.Fatalf("GetClient after rotation: %v", err)
+	}
+	if rotated == first {
+		t.Fatal("rotated TLS files should rebuild the client")
+	}
+	resp, err := rotated.Get(newSrv.URL)
+	if err != nil {
+		t.Fatalf("rotated CA should be trusted: %v", err)
+	}
+	resp.Body.Close()
+	if len(pool.clients) != 1 {
+		t.Fatalf("rotation should replace the pooled client, got %d entries", len(pool.clients))
+	}
+}
@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+// debugPurgeInterval 过期调试快照的清理间隔（随探测写入顺带执行）
+const debugPurgeInterval = 10 * time.Minute
+
+// encodeHeaders 将请求头/响应头编码为 JSON 对象（多值以 ", " 拼接），敏感值按策略脱敏
+func encodeHeaders(h http.Header, redact config.RedactionConfig) string {
+	out := make(map[string]string, len(h))
+	for k, values := range h {
+		out[k] = redact.Redact(k, strings.Join(values, ", "))
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+// captureDebug 记录一次请求尝试的调试快照（resp 为 nil 表示网络错误）
+// 最终状态、错误与过期时间在探测结束后由 finalizeDebug 填充
+func captureDebug(req *http.Request, resp *http.Response, body []byte, settings *config.DebugCaptureSettings, timer *phaseTimer) *storage.ProbeDebugEntry {
+	t := timer.timings()
+	entry := &storage.ProbeDebugEntry{
+		Method:          req.Method,
+		URL:             settings.Redaction.RedactURL(req.URL),
+		RequestHeaders:  encodeHeaders(req.Header, settings.Redaction),
+		ResponseHeaders: "{}",
+		DNSMs:           t.DNSMs,
+		ConnectMs:       t.ConnectMs,
+		TLSMs:           t.TLSMs,
+		TTFBMs:          t.TTFBMs,
+	}
+	if resp != nil {
+		entry.ResponseHeaders = encodeHeaders(resp.Header, settings.Redaction)
+	}
+	if len(body) > settings.MaxBodyBytes {
+		body = body[:settings.MaxBodyBytes]
+		entry.BodyTruncated = true
+	}
+	entry.ResponseBody = strings.ToValidUTF8(string(body), "�")
+	return entry
+}
+
+// finalizeDebug 按采样规则决定是否保留调试快照，并补全最终探测结果
+// 返回 nil 表示本次不保存
+func finalizeDebug(entry *storage.ProbeDebugEntry, settings *config.DebugCaptureSettings, result *ProbeResult, attempts int) *storage.ProbeDebugEntry {
+	if entry == nil || settings == nil {
+		return nil
+	}
+	if settings.FailuresOnly && result.Status != 0 {
+		return nil
+	}
+	if rand.Float64() >= settings.SampleRate {
+		return nil
+	}
+
+	entry.Provider = result.Provider
+	entry.Service = result.Service
+	entry.Channel = result.Channel
+	entry.Model = result.Model
+	entry.Timestamp = result.Timestamp
+	entry.Status = result.Status
+	entry.SubStatus = result.SubStatus
+	entry.HttpCode = result.HttpCode
+	entry.Latency = result.Latency
+	entry.Attempts = attempts
+	if result.Error != nil {
+		entry.Error = result.Error.Error()
+	}
+	entry.ExpiresAt = time.Now().Add(settings.TTL).Unix()
+	return entry
+}
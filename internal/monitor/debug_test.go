@@ -0,0 +1,70 @@
+package monitor
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestEncodeHeadersMasksCredentials(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "Bearer sk-1234567890abcdef")
+	h.Set("X-Api-Key", "abcdefghijklmnop")
+	h.Set("Content-Type", "application/json")
+	got := encodeHeaders(h, config.RedactionConfig{})
+	if strings.Contains(got, "1234567890") || strings.Contains(got, "efghijkl") {
+		t.Fatalf("credentials not masked: %s", got)
+	}
+	if !strings.Contains(got, "application/json") {
+		t.Fatalf("plain header lost: %s", got)
+	}
+}
+
+func TestCaptureDebugTruncatesBody(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "https://api.example.com/v1/messages", nil)
+	resp := &http.Response{Header: http.Header{"X-Request-Id": {"abc"}}}
+	entry := captureDebug(req, resp, []byte("0123456789"), &config.DebugCaptureSettings{MaxBodyBytes: 4}, newPhaseTimer())
+	if entry.ResponseBody != "0123" || !entry.BodyTruncated {
+		t.Fatalf("unexpected body: %q truncated=%v", entry.ResponseBody, entry.BodyTruncated)
+	}
+	if entry.DNSMs != -1 || entry.TTFBMs != -1 {
+		t.Fatalf("expected missing phases to be -1: %+v", entry)
+	}
+}
+
+func TestFinalizeDebug(t *testing.T) {
+	settings := &config.DebugCaptureSettings{SampleRate: 1, FailuresOnly: true, MaxBodyBytes: 16, TTL: time.Hour}
+	red := &ProbeResult{Provider: "p", Service: "s", Status: 0, SubStatus: storage.SubStatusServerError, Error: errors.New("boom")}
+	green := &ProbeResult{Provider: "p", Service: "s", Status: 1}
+
+	if finalizeDebug(&storage.ProbeDebugEntry{}, settings, green, 1) != nil {
+		t.Fatal("failures_only should drop green results")
+	}
+	entry := finalizeDebug(&storage.ProbeDebugEntry{}, settings, red, 2)
+	if entry == nil || entry.Provider != "p" || entry.Attempts != 2 || entry.Error != "boom" || entry.ExpiresAt <= time.Now().Unix() {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+
+	settings.SampleRate = 0
+	if finalizeDebug(&storage.ProbeDebugEntry{}, settings, red, 1) != nil {
+		t.Fatal("sample_rate=0 should drop all results")
+	}
+}
+
+func TestEncodeHeadersUsesRedactionList(t *testing.T) {
+	h := http.Header{}
+	h.Set("OpenAI-Organization", "org-1234567890abcdef")
+	h.Set("Authorization", "Bearer sk-1234567890abcdef")
+	got := encodeHeaders(h, config.RedactionConfig{Headers: []string{"openai-organization"}})
+	if strings.Contains(got, "567890") {
+		t.Fatalf("listed header not masked: %s", got)
+	}
+	if !strings.Contains(got, `"Bearer sk-1***"`) || !strings.Contains(got, `"org-***"`) {
+		t.Fatalf("masked values should keep prefix: %s", got)
+	}
+}
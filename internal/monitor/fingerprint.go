@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+// computeFingerprint 按配置字段计算响应指纹
+//
+// 每个字段取最后一次出现的值（SSE 流式响应与用量解析一致），取值规则与状态映射规则的 json_path 相同
+// （对象/数组按 JSON 编码，键有序），去除首尾空白后参与哈希；缺失的字段同样计入指纹。
+// 响应不是 JSON / SSE 或所有字段均缺失时返回 false（不参与漂移判断）
+func computeFingerprint(body []byte, fields []string) (string, bool) {
+	if len(body) == 0 || len(fields) == 0 {
+		return "", false
+	}
+	docs := decodeUsageDocuments(body)
+	if len(docs) == 0 {
+		return "", false
+	}
+
+	var b strings.Builder
+	found := false
+	for _, field := range fields {
+		b.WriteString(field)
+		b.WriteByte('=')
+		if v, ok := lastJSONValue(docs, field); ok {
+			b.WriteString(strings.TrimSpace(v))
+			found = true
+		} else {
+			b.WriteString("<missing>")
+		}
+		b.WriteByte('\n')
+	}
+	if !found {
+		return "", false
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:8]), true
+}
+
+// fingerprintTracker 按监测项维护指纹基线（仅内存，重启后首次成功探测的指纹作为新基线）
+type fingerprintTracker struct {
+	mu     sync.Mutex
+	states map[string]*fingerprintState // provider/service/channel/model -> 状态
+}
+
+// fingerprintState 单个监测项的指纹状态
+type fingerprintState struct {
+	fields    string // 计算基线时使用的字段（配置变更后重新建立基线）
+	baseline  string // 当前基线指纹
+	candidate string // 与基线不同、正在累计的新指纹
+	count     int    // candidate 连续出现次数
+}
+
+// observe 记录一次指纹观测，新指纹连续出现 persist 次时返回漂移信息并将其作为新基线
+//
+// 只有与基线不同且彼此相同的指纹才会累计；中途出现基线指纹或其他指纹都会重新计数，
+// 避免偶发的异常响应或多个上游轮询造成误报
+func (t *fingerprintTracker) observe(cfg *config.ServiceConfig, fingerprint string) *storage.ContentDrift {
+	key := cfg.Provider + "/" + cfg.Service + "/" + cfg.Channel + "/" + cfg.Model
+	fields := strings.Join(cfg.Fingerprint.Fields, ",")
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.states == nil {
+		t.states = make(map[string]*fingerprintState)
+	}
+	st, ok := t.states[key]
+	if !ok || st.fields != fields {
+		t.states[key] = &fingerprintState{fields: fields, baseline: fingerprint}
+		return nil
+	}
+
+	if fingerprint == st.baseline {
+		st.candidate, st.count = "", 0
+		return nil
+	}
+	if fingerprint == st.candidate {
+		st.count++
+	} else {
+		st.candidate, st.count = fingerprint, 1
+	}
+	if st.count < cfg.Fingerprint.Persist {
+		return nil
+	}
+
+	drift := &storage.ContentDrift{From: st.baseline, To: fingerprint}
+	st.baseline, st.candidate, st.count = fingerprint, "", 0
+	return drift
+}
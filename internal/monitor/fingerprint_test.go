@@ -0,0 +1,94 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestComputeFingerprint(t *testing.T) {
+	fields := []string{"model", "content.0.type"}
+
+	a, ok := computeFingerprint([]byte(`{"model":"claude-haiku","content":[{"type":"text","text":"hi"}]}`), fields)
+	if !ok {
+		t.Fatalf("expected fingerprint for JSON response")
+	}
+	// 未参与指纹的字段变化不影响结果
+	if b, _ := computeFingerprint([]byte(`{"content":[{"text":"other","type":"text"}],"model":" claude-haiku "}`), fields); b != a {
+		t.Fatalf("fingerprint should ignore unselected fields: %s != %s", b, a)
+	}
+	if c, _ := computeFingerprint([]byte(`{"model":"gpt-4o","content":[{"type":"text"}]}`), fields); c == a {
+		t.Fatalf("fingerprint should change with model")
+	}
+
+	// SSE：取最后一次出现的值
+	sse := "data: {\"model\":\"claude-haiku\"}\n\ndata: {\"content\":[{\"type\":\"text\"}]}\n\ndata: [DONE]\n"
+	if s, _ := computeFingerprint([]byte(sse), fields); s != a {
+		t.Fatalf("SSE fingerprint mismatch: %s != %s", s, a)
+	}
+
+	if _, ok := computeFingerprint([]byte(`plain text`), fields); ok {
+		t.Fatalf("non-JSON response should not produce a fingerprint")
+	}
+	if _, ok := computeFingerprint([]byte(`{"error":"x"}`), fields); ok {
+		t.Fatalf("response without any selected field should not produce a fingerprint")
+	}
+}
+
+func TestProbeDetectsContentDrift(t *testing.T) {
+	model := "claude-haiku"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"model":"` + model + `"}`))
+	}))
+	defer srv.Close()
+
+	cfg := &config.ServiceConfig{
+		Provider: "demo", Service: "cc", Model: "haiku", Method: http.MethodPost, URL: srv.URL,
+		Body: "{}", TimeoutDuration: 5 * time.Second,
+		Fingerprint: &config.FingerprintConfig{Fields: []string{"model"}, Persist: 2},
+	}
+	p := NewProber(nil)
+	probe := func() *ProbeResult {
+		t.Helper()
+		return p.Probe(context.Background(), cfg)
+	}
+
+	// 首次探测建立基线
+	if r := probe(); r.Status != 1 || r.ContentDrift != nil {
+		t.Fatalf("baseline probe: %+v", r)
+	}
+
+	// 偶发一次变化不判定为漂移
+	model = "gpt-4o-mini"
+	if r := probe(); r.Status != 1 || r.ContentDrift != nil {
+		t.Fatalf("single change should not be drift: %+v", r)
+	}
+	model = "claude-haiku"
+	if r := probe(); r.Status != 1 || r.ContentDrift != nil {
+		t.Fatalf("baseline again: %+v", r)
+	}
+
+	// 连续 persist 次变化：判定为漂移并采用新基线
+	model = "gpt-4o-mini"
+	if r := probe(); r.ContentDrift != nil {
+		t.Fatalf("drift confirmed too early: %+v", r)
+	}
+	r := probe()
+	if r.Status != 2 || r.SubStatus != storage.SubStatusContentDrift || r.ContentDrift == nil || r.ContentDrift.From == r.ContentDrift.To {
+		t.Fatalf("expected content_drift, got %+v", r)
+	}
+	if r := probe(); r.Status != 1 || r.ContentDrift != nil {
+		t.Fatalf("new fingerprint should become the baseline: %+v", r)
+	}
+
+	// 字段配置变更后重新建立基线
+	cfg.Fingerprint = &config.FingerprintConfig{Fields: []string{"model", "id"}, Persist: 1}
+	if r := probe(); r.ContentDrift != nil {
+		t.Fatalf("changing fields should reset the baseline: %+v", r)
+	}
+}
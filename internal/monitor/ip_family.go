@@ -0,0 +1,75 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// ipv4CheckTimeout 强制 IPv6 探测失败后，对比拨号 IPv4 的超时时间
+const ipv4CheckTimeout = 3 * time.Second
+
+// directConnection 请求是否直连目标地址（经代理时连接的地址族属于代理服务器，不记录）
+func directConnection(cfg *config.ServiceConfig, req *http.Request) bool {
+	if cfg.Proxy != "" {
+		return false
+	}
+	if dialOptionsOf(cfg).enabled() {
+		// 强制地址族、解析覆盖或自定义 DNS 时忽略环境变量代理
+		return true
+	}
+	proxyURL, err := http.ProxyFromEnvironment(req)
+	return err == nil && proxyURL == nil
+}
+
+// detectIPv6OnlyFailure 强制 IPv6 探测因网络错误失败时，对同一地址拨号 IPv4 对比
+// IPv4 可达说明问题出在 AAAA 记录或 IPv6 链路，将细分状态改为 ipv6_only_failure
+func detectIPv6OnlyFailure(ctx context.Context, cfg *config.ServiceConfig, result *ProbeResult) {
+	if cfg.IPFamily != config.IPFamilyIPv6 || result.Status != 0 || result.SubStatus != storage.SubStatusNetworkError {
+		return
+	}
+	addr := dialAddress(cfg.URL)
+	if addr == "" {
+		return
+	}
+
+	// 探测 context 可能已超时，对比拨号单独计时；沿用自定义 DNS，但不使用 resolve 固定的 IPv6 地址
+	dialCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), ipv4CheckTimeout)
+	defer cancel()
+	dial := dialOptions{ipFamily: config.IPFamilyIPv4, dnsServer: cfg.DNSServer}.dialContext()
+	conn, err := dial(dialCtx, "tcp", addr)
+	if err != nil {
+		logger.Debug("probe", "IPv6 探测失败，IPv4 同样不可达",
+			"provider", cfg.Provider, "service", cfg.Service, "channel", cfg.Channel, "model", cfg.Model, "error", err)
+		return
+	}
+	_ = conn.Close()
+
+	result.SubStatus = storage.SubStatusIPv6OnlyFailure
+	logger.Warn("probe", "IPv6 连接失败但 IPv4 可达，疑似 AAAA 记录或 IPv6 链路异常",
+		"provider", cfg.Provider, "service", cfg.Service, "channel", cfg.Channel, "model", cfg.Model, "addr", addr)
+}
+
+// dialAddress 从探测 URL 提取拨号地址（host:port，缺省端口按 scheme 补全），解析失败返回空字符串
+func dialAddress(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	port := u.Port()
+	if port == "" {
+		if strings.EqualFold(u.Scheme, "http") {
+			port = "80"
+		} else {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
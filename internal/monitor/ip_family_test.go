@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestAddrFamily(t *testing.T) {
+	tests := map[string]string{
+		"127.0.0.1:443":         config.IPFamilyIPv4,
+		"[::1]:443":             config.IPFamilyIPv6,
+		"[2001:db8::1]:8080":    config.IPFamilyIPv6,
+		"[::ffff:10.0.0.1]:443": config.IPFamilyIPv4,
+		"10.0.0.1":              config.IPFamilyIPv4,
+		"example.com:443":       "",
+		"":                      "",
+	}
+	for addr, want := range tests {
+		if got := addrFamily(addr); got != want {
+			t.Errorf("addrFamily(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
+
+func TestDialAddress(t *testing.T) {
+	tests := map[string]string{
+		"https://api.example.com/v1/messages": "api.example.com:443",
+		"http://api.example.com/v1":           "api.example.com:80",
+		"https://api.example.com:8443/v1":     "api.example.com:8443",
+		"https://[2001:db8::1]/v1":            "[2001:db8::1]:443",
+		"::invalid":                           "",
+	}
+	for raw, want := range tests {
+		if got := dialAddress(raw); got != want {
+			t.Errorf("dialAddress(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestProbeRecordsIPFamily(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	newCfg := func(family string) *config.ServiceConfig {
+		return &config.ServiceConfig{
+			Provider: "demo", Service: "cc", Method: http.MethodPost, URL: srv.URL,
+			Body: "{}", TimeoutDuration: 5 * time.Second, IPFamily: family,
+		}
+	}
+	p := NewProber(nil)
+
+	// httptest 监听 127.0.0.1：auto 与强制 ipv4 均由 IPv4 连接承载
+	for _, family := range []string{"", config.IPFamilyIPv4} {
+		r := p.Probe(context.Background(), newCfg(family))
+		if r.Status != 1 || r.IPFamily != config.IPFamilyIPv4 || r.IPv6Fallback {
+			t.Fatalf("ip_family=%q: %+v", family, r)
+		}
+	}
+
+	// 强制 ipv6 连接 IPv4 地址失败，对比拨号 IPv4 可达
+	cfg := newCfg(config.IPFamilyIPv6)
+	cfg.URL = strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+	r := p.Probe(context.Background(), cfg)
+	if r.Status != 0 || r.SubStatus != storage.SubStatusIPv6OnlyFailure || r.IPFamily != config.IPFamilyIPv6 {
+		t.Fatalf("expected ipv6_only_failure, got %+v", r)
+	}
+
+	// IPv4 同样不可达时保持 network_error
+	srv.Close()
+	r = p.Probe(context.Background(), cfg)
+	if r.Status != 0 || r.SubStatus != storage.SubStatusNetworkError {
+		t.Fatalf("expected network_error, got %+v", r)
+	}
+}
@@ -5,22 +5,32 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"math/rand"
 	"net/http"
+	"net/http/httptrace"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"monitor/internal/config"
 	"monitor/internal/logger"
 	"monitor/internal/storage"
+	"monitor/internal/tracing"
 )
 
 // ProbeResult 探测结果
 type ProbeResult struct {
+	Namespace string // 所属命名空间（"" 为默认命名空间）
 	Provider  string
 	Service   string
 	Channel   string
@@ -31,12 +41,49 @@ type ProbeResult struct {
 	Latency   int               // ms
 	Timestamp int64
 	Error     error
+
+	// CertDaysRemaining 服务端证书剩余有效天数（仅 HTTPS 响应有值）
+	CertDaysRemaining *int
+
+	// UsageTokens 从响应体解析出的 token 用量（未启用 usage 或未命中时为 0）
+	UsageTokens int64
+	// UsageCost 按单价估算的成本
+	UsageCost float64
+
+	// Timings 最后一次尝试的连接阶段耗时（DNS/TCP/TLS/TTFB）
+	Timings PhaseTimings
+
+	// Attempts 实际发起的请求次数（含首次；0 表示未发起请求，如故障注入或沿用父通道状态）
+	Attempts int
+
+	// Debug 调试快照（仅启用 debug_capture 且命中采样时非 nil）
+	Debug *storage.ProbeDebugEntry
+
+	// Prompt 本次使用的提示词变体名称（未绑定提示词集合时为空）
+	Prompt string
+
+	// ContentDrift 本次探测确认的响应指纹变化（仅配置 fingerprint 且确认漂移时非 nil）
+	ContentDrift *storage.ContentDrift
+
+	// IPFamily 最后一次尝试承载请求的连接地址族（ipv4 / ipv6；经代理或 auto 模式下未建立连接时为空）
+	IPFamily string
+	// IPv6Fallback 最后一次尝试中 IPv6 拨号失败、由 IPv4 连接承载请求（Happy Eyeballs 回退）
+	IPv6Fallback bool
 }
 
 // Prober 探测器
 type Prober struct {
 	clientPool *ClientPool
 	storage    storage.Storage
+
+	// lastDebugPurge 上次清理过期调试快照的时间（Unix 秒）
+	lastDebugPurge atomic.Int64
+
+	// prompts 提示词轮换位置
+	prompts promptRotator
+
+	// fingerprints 响应指纹基线（内容漂移检测）
+	fingerprints fingerprintTracker
 }
 
 // NewProber 创建探测器
@@ -50,6 +97,7 @@ func NewProber(storage storage.Storage) *Prober {
 // Probe 执行单次探测（支持可配置重试）
 func (p *Prober) Probe(ctx context.Context, cfg *config.ServiceConfig) *ProbeResult {
 	result := &ProbeResult{
+		Namespace: cfg.Namespace,
 		Provider:  cfg.Provider,
 		Service:   cfg.Service,
 		Channel:   cfg.Channel,
@@ -57,25 +105,13 @@ func (p *Prober) Probe(ctx context.Context, cfg *config.ServiceConfig) *ProbeRes
 		Timestamp: time.Now().Unix(),
 		SubStatus: storage.SubStatusNone,
 		HttpCode:  0, // 默认为 0，表示非 HTTP 错误
+		Timings:   PhaseTimings{DNSMs: -1, ConnectMs: -1, TLSMs: -1, TTFBMs: -1},
 	}
 
-	// 使用配置的超时时间包装 context
-	// 兜底：防止 TimeoutDuration 未下发导致请求无期限挂起
-	timeout := cfg.TimeoutDuration
-	if timeout <= 0 {
-		timeout = 10 * time.Second
-	}
-	var cancel context.CancelFunc
-	ctx, cancel = context.WithTimeout(ctx, timeout)
-	defer cancel()
-
-	// 获取对应 provider 的客户端（考虑代理配置）
-	client, err := p.clientPool.GetClient(cfg.Provider, cfg.Proxy)
-	if err != nil {
-		result.Error = fmt.Errorf("获取 HTTP 客户端失败: %w", err)
-		result.Status = 0
-		result.SubStatus = storage.SubStatusNetworkError
-		return result
+	// 提示词库：本次探测（含重试）使用同一个变体
+	if variant := p.prompts.pick(cfg); variant != nil {
+		cfg = withPrompt(cfg, variant)
+		result.Prompt = variant.Name
 	}
 
 	// 重试配置：从 config 获取（已在 Normalize 阶段下发到 monitor 级别）
@@ -98,6 +134,64 @@ func (p *Prober) Probe(ctx context.Context, cfg *config.ServiceConfig) *ProbeRes
 	if jitter > 1 {
 		jitter = 1
 	}
+	retryOn := cfg.RetryOnClasses
+	// retry_on 包含 timeout 时每次尝试独立计时，否则 timeout 为整个探测（含重试）的总超时
+	retryTimeouts := maxAttempts > 1 && retryOn.Has(config.RetryOnTimeout)
+
+	// 使用配置的超时时间包装 context
+	// 兜底：防止 TimeoutDuration 未下发导致请求无期限挂起
+	timeout := cfg.TimeoutDuration
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	probeTimeout := timeout
+	if retryTimeouts {
+		probeTimeout = time.Duration(maxAttempts)*timeout + time.Duration(maxAttempts-1)*maxDelay
+	}
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	ctx, span := tracing.StartKind(ctx, trace.SpanKindClient, "prober.Probe",
+		attribute.String("provider", cfg.Provider),
+		attribute.String("service", cfg.Service),
+		attribute.String("channel", cfg.Channel),
+		attribute.String("model", cfg.Model),
+	)
+	defer func() {
+		span.SetAttributes(
+			attribute.Int("probe.status", result.Status),
+			attribute.String("probe.sub_status", string(result.SubStatus)),
+			attribute.Int("http.response.status_code", result.HttpCode),
+			attribute.Int("probe.latency_ms", result.Latency),
+			attribute.Int("probe.dns_ms", result.Timings.DNSMs),
+			attribute.Int("probe.connect_ms", result.Timings.ConnectMs),
+			attribute.Int("probe.tls_ms", result.Timings.TLSMs),
+			attribute.Int("probe.ttfb_ms", result.Timings.TTFBMs),
+			attribute.String("probe.prompt", result.Prompt),
+			attribute.Bool("probe.content_drift", result.ContentDrift != nil),
+			attribute.String("probe.ip_family", result.IPFamily),
+			attribute.Bool("probe.ipv6_fallback", result.IPv6Fallback),
+		)
+		tracing.RecordError(span, result.Error)
+		span.End()
+	}()
+
+	// 故障注入（chaos，仅测试环境）：超时/5xx 直接返回模拟结果，不发起真实请求
+	chaos := rollChaos(cfg.ChaosSettings, rand.Float64())
+	if chaos == chaosTimeout || chaos == chaosServerError {
+		injectChaosFault(ctx, cfg, chaos, result)
+		return result
+	}
+
+	// 获取对应 provider 的客户端（考虑代理配置）
+	client, err := p.clientPool.GetClient(cfg.Provider, cfg.Proxy, cfg.TLS, dialOptionsOf(cfg))
+	if err != nil {
+		result.Error = fmt.Errorf("获取 HTTP 客户端失败: %w", err)
+		result.Status = 0
+		result.SubStatus = storage.SubStatusNetworkError
+		return result
+	}
 
 	// 累计延迟（所有 attempt 的延迟总和）
 	var totalLatency int
@@ -105,6 +199,9 @@ func (p *Prober) Probe(ctx context.Context, cfg *config.ServiceConfig) *ProbeRes
 	var actualAttempts int
 	// 保存最后一次的响应体（用于最终诊断日志）
 	var lastBodyBytes []byte
+	// 调试捕获：记录最后一次尝试的请求/响应元数据
+	captureDebugInfo := cfg.DebugSettings != nil
+	var lastDebug *storage.ProbeDebugEntry
 
 	// 重试循环（使用标签以便从 select 中正确跳出）
 retryLoop:
@@ -128,9 +225,17 @@ retryLoop:
 			break retryLoop
 		}
 
-		// 准备请求体（去除首尾空白，某些 API 对此敏感）
-		reqBody := bytes.NewBuffer([]byte(strings.TrimSpace(cfg.Body)))
-		req, err := http.NewRequestWithContext(ctx, cfg.Method, cfg.URL, reqBody)
+		// 准备请求体（渲染模板表达式后去除首尾空白，某些 API 对此敏感）
+		// 每次尝试重新渲染，确保 {{RANDOM_UUID}} / {{NOW_ISO}} 等动态值不复用
+		now := time.Now()
+		reqBody := bytes.NewBuffer([]byte(strings.TrimSpace(cfg.RenderTemplate(cfg.Body, now))))
+		attemptCtx := ctx
+		if retryTimeouts {
+			var cancelAttempt context.CancelFunc
+			attemptCtx, cancelAttempt = context.WithTimeout(ctx, timeout)
+			defer cancelAttempt()
+		}
+		req, err := http.NewRequestWithContext(attemptCtx, cfg.Method, cfg.URL, reqBody)
 		if err != nil {
 			result.Error = fmt.Errorf("创建请求失败: %w", err)
 			result.Status = 0
@@ -139,48 +244,75 @@ retryLoop:
 			break retryLoop
 		}
 
-		// 设置 Headers（已处理过占位符）
+		// 设置 Headers（已处理过静态占位符，动态表达式在此渲染）
 		for k, v := range cfg.Headers {
-			req.Header.Set(k, v)
+			req.Header.Set(k, cfg.RenderTemplate(v, now))
 		}
 
+		// 记录连接阶段耗时，用于区分"服务商慢"与"网络慢"
+		timer := newPhaseTimer()
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), timer.trace()))
+
 		// 发送请求并计时
 		start := time.Now()
 		resp, err := client.Do(req)
 		latency := int(time.Since(start).Milliseconds())
 		totalLatency += latency
+		result.Timings = timer.timings()
+		result.IPFamily, result.IPv6Fallback = "", false
+		if directConnection(cfg, req) {
+			result.IPFamily, result.IPv6Fallback = timer.ipFamily()
+			if result.IPFamily == "" {
+				// 强制地址族时连接失败同样计入该地址族
+				result.IPFamily = cfg.IPFamily
+			}
+		}
 
 		if err != nil {
 			// 极少数情况下 err != nil 但 resp != nil，需要关闭 body，避免资源泄漏
 			drainAndClose(resp)
+			if captureDebugInfo {
+				lastDebug = captureDebug(req, nil, nil, cfg.DebugSettings, timer)
+			}
 
-			// 超时/取消：不重试（总超时已到，继续重试无意义）
-			if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
-				if errors.Is(err, context.DeadlineExceeded) {
+			// 超时/取消：取消不重试；超时仅在 retry_on 包含 timeout 且整个探测未到期时重试（每次尝试独立计时）
+			timedOut := errors.Is(err, context.DeadlineExceeded)
+			if timedOut || errors.Is(err, context.Canceled) {
+				if timedOut {
 					err = fmt.Errorf("请求超时(%v): %w", timeout, err)
 				} else {
 					err = fmt.Errorf("请求取消: %w", err)
 				}
-				logger.Error("probe", "请求失败（不重试）",
-					"provider", cfg.Provider, "service", cfg.Service, "channel", cfg.Channel, "model", cfg.Model,
-					"attempt", attempt+1, "max_attempts", maxAttempts, "error", err)
 				result.Error = err
 				result.Status = 0
 				result.SubStatus = storage.SubStatusNetworkError
 				result.Latency = totalLatency
-				break retryLoop // 超时不重试
+				result.HttpCode = 0
+				lastBodyBytes = nil
+				if !timedOut || !retryTimeouts || ctx.Err() != nil || attempt+1 >= maxAttempts {
+					logger.Error("probe", "请求失败（不重试）",
+						"provider", cfg.Provider, "service", cfg.Service, "channel", cfg.Channel, "model", cfg.Model,
+						"attempt", attempt+1, "max_attempts", maxAttempts, "error", err)
+					break retryLoop
+				}
+			} else {
+				// 其他网络错误，设置结果，按 retry_on 决定是否重试
+				result.Error = err
+				result.Status = 0
+				result.SubStatus = storage.SubStatusNetworkError
+				result.Latency = totalLatency
+				result.HttpCode = 0
+				lastBodyBytes = nil // 网络错误无响应体
+				if !retryOn.Has(config.RetryOnNetworkError) {
+					logger.Error("probe", "请求失败（retry_on 未包含 network_error，不重试）",
+						"provider", cfg.Provider, "service", cfg.Service, "channel", cfg.Channel, "model", cfg.Model,
+						"attempt", attempt+1, "max_attempts", maxAttempts, "error", err)
+					break retryLoop
+				}
 			}
-
-			// 其他网络错误，设置结果并继续重试
 			logger.Error("probe", "请求失败",
 				"provider", cfg.Provider, "service", cfg.Service, "channel", cfg.Channel, "model", cfg.Model,
 				"attempt", attempt+1, "max_attempts", maxAttempts, "error", err)
-			result.Error = err
-			result.Status = 0
-			result.SubStatus = storage.SubStatusNetworkError
-			result.Latency = totalLatency
-			result.HttpCode = 0
-			lastBodyBytes = nil // 网络错误无响应体
 
 			// 检查是否需要重试
 			if attempt+1 < maxAttempts {
@@ -205,9 +337,9 @@ retryLoop:
 		// 记录 HTTP 状态码
 		result.HttpCode = resp.StatusCode
 
-		// 完整读取响应体（避免连接泄漏），在需要内容匹配时保留文本
+		// 完整读取响应体（避免连接泄漏），在需要内容匹配、状态映射规则、用量解析或指纹计算时保留文本
 		var bodyBytes []byte
-		if cfg.SuccessContains != "" {
+		if cfg.SuccessContains != "" || cfg.StatusRulesNeedBody() || len(cfg.UsagePaths) > 0 || cfg.Fingerprint != nil || captureDebugInfo {
 			data, readErr := io.ReadAll(resp.Body)
 			switch {
 			case readErr == nil:
@@ -232,18 +364,32 @@ retryLoop:
 
 		// 保存响应体用于最终诊断
 		lastBodyBytes = bodyBytes
+		if captureDebugInfo {
+			lastDebug = captureDebug(req, resp, bodyBytes, cfg.DebugSettings, timer)
+		}
 
-		// 判定状态（先按 HTTP/延迟，再根据响应内容做二次判断）
+		// 判定状态（先按 HTTP/延迟，再根据响应内容做二次判断；自定义状态映射规则命中时覆盖内置判定）
 		status, subStatus := p.determineStatus(resp.StatusCode, latency, cfg.SlowLatencyDuration)
+		status, subStatus = applyExpectedStatusCodes(status, subStatus, resp.StatusCode, latency, cfg.SlowLatencyDuration, cfg.ExpectedStatusCodes)
 		result.Status = status
 		result.SubStatus = subStatus
 		result.Status, result.SubStatus = evaluateStatus(result.Status, result.SubStatus, bodyBytes, cfg.SuccessContains)
+		if ruleStatus, ruleSubStatus, ok := applyStatusRules(cfg.StatusRules, resp.StatusCode, resp.Header, bodyBytes, latency, cfg.SlowLatencyDuration); ok {
+			result.Status, result.SubStatus = ruleStatus, ruleSubStatus
+		}
+		result.Status, result.SubStatus = evaluateCertExpiry(result.Status, result.SubStatus, resp.TLS, cfg.TLS, time.Now())
+		result.CertDaysRemaining = certDaysRemaining(resp.TLS, time.Now())
+		result.UsageTokens, result.UsageCost = 0, 0
+		if len(cfg.UsagePaths) > 0 && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			result.UsageTokens = extractTokenUsage(bodyBytes, cfg.UsagePaths)
+			result.UsageCost = float64(result.UsageTokens) / 1000 * cfg.UsagePrice
+		}
 		result.Latency = totalLatency
 		result.Error = nil
 
 		// 检查是否需要重试
-		// 重试条件：status=0（红色）且非超时
-		if result.Status == 0 && attempt+1 < maxAttempts {
+		// 重试条件：status=0（红色）且失败类别（5xx / 限流）在 retry_on 中；认证失败等 4xx 与内容校验失败重试无意义
+		if result.Status == 0 && attempt+1 < maxAttempts && retryOn.Has(httpRetryClass(result.HttpCode, result.SubStatus)) {
 			// 输出诊断信息（重试前输出）
 			p.logFailedProbe(cfg, result, bodyBytes)
 
@@ -268,6 +414,17 @@ retryLoop:
 		break retryLoop
 	}
 
+	p.detectContentDrift(cfg, result, lastBodyBytes)
+	detectIPv6OnlyFailure(ctx, cfg, result)
+	if result.IPv6Fallback {
+		logger.Info("probe", "IPv6 拨号失败，已回退到 IPv4 连接",
+			"provider", cfg.Provider, "service", cfg.Service, "channel", cfg.Channel, "model", cfg.Model)
+	}
+
+	if chaos == chaosSlow {
+		applyChaosSlow(ctx, cfg, result)
+	}
+
 	// 最终诊断日志（仅在最终结果为红色时输出）
 	if result.Status == 0 {
 		// 输出诊断信息（使用保存的最后一次响应体）
@@ -280,6 +437,9 @@ retryLoop:
 			"http_code", result.HttpCode)
 	}
 
+	result.Attempts = actualAttempts
+	result.Debug = finalizeDebug(lastDebug, cfg.DebugSettings, result, actualAttempts)
+
 	// 日志（不打印敏感信息）
 	logger.Info("probe", "探测完成",
 		"provider", cfg.Provider, "service", cfg.Service, "channel", cfg.Channel, "model", cfg.Model,
@@ -324,6 +484,25 @@ func (p *Prober) logFailedProbe(cfg *config.ServiceConfig, result *ProbeResult,
 	}
 }
 
+// applyExpectedStatusCodes 按监测项的期望状态码列表修正 HTTP 基础判定（未配置时原样返回）
+// 命中列表视为成功（仍按 slow_latency 降级、仍需通过 success_contains 校验）；
+// 未命中时内置判定为成功的状态码（2xx/3xx）改判为红色 client_error，其余保留内置细分状态
+func applyExpectedStatusCodes(baseStatus int, baseSubStatus storage.SubStatus, statusCode, latency int, slowLatency time.Duration, expected []int) (int, storage.SubStatus) {
+	if len(expected) == 0 {
+		return baseStatus, baseSubStatus
+	}
+	if slices.Contains(expected, statusCode) {
+		if slowLatency > 0 && latency > int(slowLatency/time.Millisecond) {
+			return 2, storage.SubStatusSlowLatency
+		}
+		return 1, storage.SubStatusNone
+	}
+	if baseStatus != 0 {
+		return 0, storage.SubStatusClientError
+	}
+	return baseStatus, baseSubStatus
+}
+
 // evaluateStatus 在基础状态上叠加响应内容匹配规则
 // 对所有 2xx 响应（绿色和慢速黄色）进行内容校验
 // 红色（已失败）和 429 黄色（非正常响应）不做校验。
@@ -358,6 +537,55 @@ func evaluateStatus(baseStatus int, baseSubStatus storage.SubStatus, body []byte
 	return baseStatus, baseSubStatus
 }
 
+// detectContentDrift 计算成功响应的指纹，确认漂移时将结果降级为黄色 content_drift
+// 仅绿色/黄色且 HTTP 2xx 的响应参与，失败响应的内容不代表服务商的正常输出
+func (p *Prober) detectContentDrift(cfg *config.ServiceConfig, result *ProbeResult, body []byte) {
+	if cfg.Fingerprint == nil || result.Status == 0 || result.HttpCode < 200 || result.HttpCode >= 300 {
+		return
+	}
+	fingerprint, ok := computeFingerprint(body, cfg.Fingerprint.Fields)
+	if !ok {
+		return
+	}
+	drift := p.fingerprints.observe(cfg, fingerprint)
+	if drift == nil {
+		return
+	}
+
+	result.ContentDrift = drift
+	result.Status, result.SubStatus = 2, storage.SubStatusContentDrift
+	logger.Warn("probe", "响应指纹持续变化，判定为内容漂移",
+		"provider", cfg.Provider, "service", cfg.Service, "channel", cfg.Channel, "model", cfg.Model,
+		"from_fingerprint", drift.From, "to_fingerprint", drift.To, "persist", cfg.Fingerprint.Persist)
+}
+
+// evaluateCertExpiry 证书即将过期时将绿色结果降级为黄色 cert_expiring
+// 仅对配置了 tls 的监测项生效；检查服务端叶子证书与客户端证书中较早到期者
+func evaluateCertExpiry(baseStatus int, baseSubStatus storage.SubStatus, state *tls.ConnectionState, tlsCfg *config.TLSConfig, now time.Time) (int, storage.SubStatus) {
+	if baseStatus != 1 || tlsCfg == nil || tlsCfg.ExpiryWarning <= 0 {
+		return baseStatus, baseSubStatus
+	}
+
+	deadline := now.Add(tlsCfg.ExpiryWarning)
+	if state != nil && len(state.PeerCertificates) > 0 && state.PeerCertificates[0].NotAfter.Before(deadline) {
+		return 2, storage.SubStatusCertExpiring
+	}
+	if !tlsCfg.ClientCertNotAfter.IsZero() && tlsCfg.ClientCertNotAfter.Before(deadline) {
+		return 2, storage.SubStatusCertExpiring
+	}
+	return baseStatus, baseSubStatus
+}
+
+// certDaysRemaining 计算服务端叶子证书剩余有效天数（向下取整，已过期为负数）
+// 非 TLS 连接返回 nil
+func certDaysRemaining(state *tls.ConnectionState, now time.Time) *int {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	days := int(math.Floor(state.PeerCertificates[0].NotAfter.Sub(now).Hours() / 24))
+	return &days
+}
+
 // decompressGzipIfNeeded 检测并解压 gzip 压缩的响应体
 // 当 Content-Encoding 包含 gzip 时进行解压，失败则保留原始数据
 // 额外检测 gzip 魔术头（0x1f 0x8b）作为兜底，处理服务器漏写 Content-Encoding 的情况
@@ -603,9 +831,12 @@ func extractTextFromSSE(body []byte) string {
 }
 
 // SaveResult 保存探测结果到存储
-// 返回保存后的记录（包含生成的 ID）和错误
-func (p *Prober) SaveResult(result *ProbeResult) (*storage.ProbeRecord, error) {
+// 返回保存后的记录（包含生成的 ID）和错误；ctx 仅用于追踪链路，写入不随 ctx 取消而中断
+func (p *Prober) SaveResult(ctx context.Context, result *ProbeResult) (*storage.ProbeRecord, error) {
+	store := p.storage.WithContext(context.WithoutCancel(ctx))
+
 	record := &storage.ProbeRecord{
+		Namespace: result.Namespace,
 		Provider:  result.Provider,
 		Service:   result.Service,
 		Channel:   result.Channel,
@@ -615,14 +846,66 @@ func (p *Prober) SaveResult(result *ProbeResult) (*storage.ProbeRecord, error) {
 		HttpCode:  result.HttpCode,
 		Latency:   result.Latency,
 		Timestamp: result.Timestamp,
+
+		CertDaysRemaining: result.CertDaysRemaining,
+
+		DNSMs:     phaseMsPtr(result.Timings.DNSMs),
+		ConnectMs: phaseMsPtr(result.Timings.ConnectMs),
+		TLSMs:     phaseMsPtr(result.Timings.TLSMs),
+		TTFBMs:    phaseMsPtr(result.Timings.TTFBMs),
+
+		Attempts: result.Attempts,
+		IPFamily: result.IPFamily,
+
+		ContentDrift: result.ContentDrift,
 	}
 
-	if err := p.storage.SaveRecord(record); err != nil {
+	if err := store.SaveRecord(record); err != nil {
 		return nil, err
 	}
+
+	// 用量累计失败不影响探测记录本身
+	if result.UsageTokens > 0 {
+		if us, ok := store.(storage.UsageStorage); ok {
+			key := storage.MonitorKey{Provider: result.Provider, Service: result.Service, Channel: result.Channel, Model: result.Model}
+			day := time.Unix(result.Timestamp, 0).UTC().Format("2006-01-02")
+			if err := us.AddUsage(key, day, result.UsageTokens, result.UsageCost); err != nil {
+				logger.Warn("probe", "累计 token 用量失败",
+					"provider", result.Provider, "service", result.Service, "channel", result.Channel, "model", result.Model, "error", err)
+			}
+		}
+	}
+
+	// 调试快照保存失败同样不影响探测记录
+	if result.Debug != nil {
+		if ds, ok := store.(storage.ProbeDebugStorage); ok {
+			if err := ds.SaveProbeDebug(result.Debug); err != nil {
+				logger.Warn("probe", "保存探测调试快照失败",
+					"provider", result.Provider, "service", result.Service, "channel", result.Channel, "model", result.Model, "error", err)
+			}
+			p.purgeExpiredDebug(ds)
+		}
+	}
 	return record, nil
 }
 
+// purgeExpiredDebug 按 debugPurgeInterval 节流清理过期调试快照
+func (p *Prober) purgeExpiredDebug(ds storage.ProbeDebugStorage) {
+	now := time.Now().Unix()
+	last := p.lastDebugPurge.Load()
+	if now-last < int64(debugPurgeInterval/time.Second) || !p.lastDebugPurge.CompareAndSwap(last, now) {
+		return
+	}
+	deleted, err := ds.PurgeExpiredProbeDebug(now)
+	if err != nil {
+		logger.Warn("probe", "清理过期调试快照失败", "error", err)
+		return
+	}
+	if deleted > 0 {
+		logger.Info("probe", "已清理过期调试快照", "deleted", deleted)
+	}
+}
+
 // Close 关闭探测器
 func (p *Prober) Close() {
 	p.clientPool.Close()
@@ -683,6 +966,18 @@ func isTolerableReadError(err error) bool {
 	return false
 }
 
+// httpRetryClass 将收到响应的红色结果归入 retry_on 类别（0 表示不可重试）
+// 限流优先于 5xx：状态映射规则可将 503 等判定为 rate_limit
+func httpRetryClass(httpCode int, subStatus storage.SubStatus) config.RetryClass {
+	switch {
+	case httpCode == 429 || subStatus == storage.SubStatusRateLimit:
+		return config.RetryOnRateLimit
+	case httpCode >= 500:
+		return config.RetryOn5xx
+	}
+	return 0
+}
+
 // computeRetryDelay 计算指数退避延迟
 // 公式: delay = min(maxDelay, baseDelay * 2^retryIndex) * (1 ± jitter)
 // retryIndex 从 0 开始（首次重试 retryIndex=0）
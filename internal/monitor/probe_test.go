@@ -1,8 +1,12 @@
 package monitor
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"testing"
+	"time"
 
+	"monitor/internal/config"
 	"monitor/internal/storage"
 )
 
@@ -100,3 +104,98 @@ func TestEvaluateStatusWithGeminiSSENoEventLine(t *testing.T) {
 		t.Fatalf("expected SubStatusNone, got %s", subStatus)
 	}
 }
+
+func TestApplyExpectedStatusCodes(t *testing.T) {
+	t.Parallel()
+
+	p := &Prober{}
+	expected := []int{200, 401}
+	tests := []struct {
+		code          int
+		latency       int
+		wantStatus    int
+		wantSubStatus storage.SubStatus
+	}{
+		{200, 100, 1, storage.SubStatusNone},
+		{401, 100, 1, storage.SubStatusNone},
+		{401, 6000, 2, storage.SubStatusSlowLatency},
+		{204, 100, 0, storage.SubStatusClientError},
+		{302, 100, 0, storage.SubStatusClientError},
+		{403, 100, 0, storage.SubStatusAuthError},
+		{503, 100, 0, storage.SubStatusServerError},
+	}
+	for _, tt := range tests {
+		base, baseSub := p.determineStatus(tt.code, tt.latency, 5*time.Second)
+		status, subStatus := applyExpectedStatusCodes(base, baseSub, tt.code, tt.latency, 5*time.Second, expected)
+		if status != tt.wantStatus || subStatus != tt.wantSubStatus {
+			t.Errorf("code %d: got %d/%s, want %d/%s", tt.code, status, subStatus, tt.wantStatus, tt.wantSubStatus)
+		}
+	}
+
+	// 未配置时沿用内置判定
+	if status, subStatus := applyExpectedStatusCodes(0, storage.SubStatusAuthError, 401, 100, 0, nil); status != 0 || subStatus != storage.SubStatusAuthError {
+		t.Fatalf("expected built-in status without expected_status_codes, got %d/%s", status, subStatus)
+	}
+
+	// 期望状态码命中后仍需通过 success_contains 校验
+	status, subStatus := applyExpectedStatusCodes(0, storage.SubStatusAuthError, 401, 100, 0, expected)
+	status, subStatus = evaluateStatus(status, subStatus, []byte(`{"error":"unauthorized"}`), "pong")
+	if status != 0 || subStatus != storage.SubStatusContentMismatch {
+		t.Fatalf("expected content_mismatch after expected status, got %d/%s", status, subStatus)
+	}
+}
+
+func TestEvaluateCertExpiry(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	tlsCfg := &config.TLSConfig{ExpiryWarning: 7 * 24 * time.Hour}
+	stateExpiring := &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{NotAfter: now.Add(3 * 24 * time.Hour)}}}
+	stateHealthy := &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{NotAfter: now.Add(90 * 24 * time.Hour)}}}
+
+	status, subStatus := evaluateCertExpiry(1, storage.SubStatusNone, stateExpiring, tlsCfg, now)
+	if status != 2 || subStatus != storage.SubStatusCertExpiring {
+		t.Fatalf("expected cert_expiring for server cert, got %d/%s", status, subStatus)
+	}
+
+	status, subStatus = evaluateCertExpiry(1, storage.SubStatusNone, stateHealthy, tlsCfg, now)
+	if status != 1 || subStatus != storage.SubStatusNone {
+		t.Fatalf("expected healthy cert to stay green, got %d/%s", status, subStatus)
+	}
+
+	// 客户端证书即将过期同样降级
+	clientExpiring := &config.TLSConfig{ExpiryWarning: 7 * 24 * time.Hour, ClientCertNotAfter: now.Add(time.Hour)}
+	status, subStatus = evaluateCertExpiry(1, storage.SubStatusNone, stateHealthy, clientExpiring, now)
+	if status != 2 || subStatus != storage.SubStatusCertExpiring {
+		t.Fatalf("expected cert_expiring for client cert, got %d/%s", status, subStatus)
+	}
+
+	// 非绿色结果与未配置 tls 的监测项不受影响
+	status, subStatus = evaluateCertExpiry(0, storage.SubStatusServerError, stateExpiring, tlsCfg, now)
+	if status != 0 || subStatus != storage.SubStatusServerError {
+		t.Fatalf("expected red status unchanged, got %d/%s", status, subStatus)
+	}
+	status, _ = evaluateCertExpiry(1, storage.SubStatusNone, stateExpiring, nil, now)
+	if status != 1 {
+		t.Fatalf("expected monitor without tls config unchanged, got %d", status)
+	}
+}
+
+func TestCertDaysRemaining(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	if got := certDaysRemaining(nil, now); got != nil {
+		t.Fatalf("expected nil for non-TLS connection, got %d", *got)
+	}
+
+	state := &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{NotAfter: now.Add(10*24*time.Hour + time.Hour)}}}
+	if got := certDaysRemaining(state, now); got == nil || *got != 10 {
+		t.Fatalf("expected 10 days remaining, got %v", got)
+	}
+
+	expired := &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{NotAfter: now.Add(-time.Hour)}}}
+	if got := certDaysRemaining(expired, now); got == nil || *got != -1 {
+		t.Fatalf("expected -1 for expired cert, got %v", got)
+	}
+}
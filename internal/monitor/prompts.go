@@ -0,0 +1,40 @@
+package monitor
+
+import (
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+
+	"monitor/internal/config"
+)
+
+// promptRotator 按监测项记录提示词轮换位置（round_robin 策略）
+type promptRotator struct {
+	cursors sync.Map // provider/service/channel/model -> *atomic.Uint64
+}
+
+// pick 选择本次探测使用的提示词变体，未绑定提示词集合时返回 nil
+func (r *promptRotator) pick(cfg *config.ServiceConfig) *config.PromptVariant {
+	set := cfg.PromptSet
+	if set == nil || len(set.Variants) == 0 {
+		return nil
+	}
+	if set.Strategy == config.PromptStrategyRandom {
+		return &set.Variants[rand.IntN(len(set.Variants))]
+	}
+
+	key := cfg.Provider + "/" + cfg.Service + "/" + cfg.Channel + "/" + cfg.Model
+	v, _ := r.cursors.LoadOrStore(key, new(atomic.Uint64))
+	n := v.(*atomic.Uint64).Add(1) - 1
+	return &set.Variants[n%uint64(len(set.Variants))]
+}
+
+// withPrompt 返回使用指定变体请求体的监测项副本（变体未配置 success_contains 时沿用监测项的）
+func withPrompt(cfg *config.ServiceConfig, v *config.PromptVariant) *config.ServiceConfig {
+	cp := *cfg
+	cp.Body = v.Body
+	if v.SuccessContains != "" {
+		cp.SuccessContains = v.SuccessContains
+	}
+	return &cp
+}
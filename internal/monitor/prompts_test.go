@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+)
+
+func TestProbeRotatesPrompts(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		// 回显请求体中的关键字
+		_, _ = w.Write([]byte(`{"text":"` + strings.TrimPrefix(string(data), "say ") + `"}`))
+	}))
+	defer srv.Close()
+
+	set := &config.PromptSetConfig{
+		Strategy: config.PromptStrategyRoundRobin,
+		Variants: []config.PromptVariant{
+			{Name: "a", Body: "say pong", SuccessContains: "pong"},
+			{Name: "b", Body: "say {{MODEL}}"},
+		},
+	}
+	cfg := &config.ServiceConfig{
+		Provider: "demo", Service: "cc", Model: "haiku", Method: http.MethodPost, URL: srv.URL,
+		Body: "unused", SuccessContains: "haiku", TimeoutDuration: 5 * time.Second,
+		Prompt: "cc", PromptSet: set,
+	}
+
+	p := NewProber(nil)
+	var names []string
+	for i := 0; i < 3; i++ {
+		result := p.Probe(context.Background(), cfg)
+		if result.Status != 1 {
+			t.Fatalf("probe %d: status=%d sub_status=%s", i, result.Status, result.SubStatus)
+		}
+		names = append(names, result.Prompt)
+	}
+	if strings.Join(names, ",") != "a,b,a" || strings.Join(bodies, "|") != "say pong|say haiku|say pong" {
+		t.Fatalf("unexpected rotation: prompts=%v bodies=%v", names, bodies)
+	}
+	if cfg.Body != "unused" || cfg.SuccessContains != "haiku" {
+		t.Fatalf("probe must not modify the monitor config: %+v", cfg)
+	}
+
+	// 各监测项独立计数
+	other := *cfg
+	other.Model = "opus"
+	if v := p.prompts.pick(&other); v.Name != "a" {
+		t.Fatalf("expected independent cursor, got %s", v.Name)
+	}
+
+	set.Strategy = config.PromptStrategyRandom
+	for i := 0; i < 20; i++ {
+		if v := p.prompts.pick(cfg); v != &set.Variants[0] && v != &set.Variants[1] {
+			t.Fatalf("random pick returned unknown variant: %+v", v)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+)
+
+func TestDialOptionsCacheKey(t *testing.T) {
+	a := dialOptions{resolve: map[string]string{"a.example.com": "203.0.113.1", "b.example.com": "203.0.113.2"}}
+	b := dialOptions{resolve: map[string]string{"b.example.com": "203.0.113.2", "a.example.com": "203.0.113.1"}}
+	if a.cacheKey() != b.cacheKey() {
+		t.Fatalf("cacheKey 应与 map 顺序无关: %q vs %q", a.cacheKey(), b.cacheKey())
+	}
+	c := dialOptions{resolve: map[string]string{"a.example.com": "203.0.113.3"}}
+	if a.cacheKey() == c.cacheKey() {
+		t.Fatalf("不同的 resolve 应使用不同的缓存键")
+	}
+	if (dialOptions{}).enabled() {
+		t.Fatalf("零值不应启用自定义拨号")
+	}
+	if !(dialOptions{dnsServer: "1.1.1.1:53"}).enabled() {
+		t.Fatalf("dns_server 应启用自定义拨号")
+	}
+}
+
+func TestProbeResolveOverride(t *testing.T) {
+	var host string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 保留域名 .test 不可解析，只能经 resolve 固定地址连接
+	u.Host = "cluster.relay-pulse.test:" + u.Port()
+	cfg := &config.ServiceConfig{
+		Provider: "demo", Service: "cc", Method: http.MethodPost, URL: u.String(),
+		Body: "{}", TimeoutDuration: 5 * time.Second,
+		Resolve: map[string]string{"cluster.relay-pulse.test": "127.0.0.1"},
+	}
+
+	r := NewProber(nil).Probe(context.Background(), cfg)
+	if r.Status != 1 || r.IPFamily != config.IPFamilyIPv4 {
+		t.Fatalf("expected green via pinned address, got %+v", r)
+	}
+	if host != u.Host {
+		t.Fatalf("Host 请求头应保留原主机名, got %q", host)
+	}
+}
@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestProbeRetryOn(t *testing.T) {
+	tests := []struct {
+		name         string
+		codes        []int // 依次返回的状态码，超出后重复最后一个
+		retryOn      config.RetryClass
+		wantAttempts int
+		wantStatus   int
+	}{
+		{"auth error not retried", []int{401}, config.DefaultRetryOn, 1, 0},
+		{"5xx retried until success", []int{503, 200}, config.DefaultRetryOn, 2, 1},
+		{"rate limit exhausts retries", []int{429}, config.DefaultRetryOn, 3, 0},
+		{"5xx excluded by retry_on", []int{502, 200}, config.RetryOnNetworkError, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(calls.Add(1)) - 1
+				w.WriteHeader(tt.codes[min(n, len(tt.codes)-1)])
+			}))
+			defer srv.Close()
+
+			cfg := &config.ServiceConfig{
+				Provider: "demo", Service: "cc", Method: http.MethodGet, URL: srv.URL,
+				TimeoutDuration: 5 * time.Second, RetryCount: 2, RetryBaseDelayDuration: time.Millisecond,
+				RetryMaxDelayDuration: time.Millisecond, RetryOnClasses: tt.retryOn,
+			}
+			result := NewProber(nil).Probe(context.Background(), cfg)
+			if result.Attempts != tt.wantAttempts || int(calls.Load()) != tt.wantAttempts || result.Status != tt.wantStatus {
+				t.Fatalf("attempts=%d calls=%d status=%d, want attempts=%d status=%d",
+					result.Attempts, calls.Load(), result.Status, tt.wantAttempts, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestProbeRetryOnTimeout(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := &config.ServiceConfig{
+		Provider: "demo", Service: "cc", Method: http.MethodGet, URL: srv.URL,
+		TimeoutDuration: 200 * time.Millisecond, RetryCount: 1, RetryBaseDelayDuration: time.Millisecond,
+		RetryMaxDelayDuration: time.Millisecond,
+	}
+
+	// 未包含 timeout：超时即整个探测结束
+	cfg.RetryOnClasses = config.DefaultRetryOn
+	if result := NewProber(nil).Probe(context.Background(), cfg); result.Attempts != 1 || result.SubStatus != storage.SubStatusNetworkError {
+		t.Fatalf("expected single timed out attempt, got attempts=%d sub_status=%s", result.Attempts, result.SubStatus)
+	}
+
+	// 包含 timeout：每次尝试独立计时，第二次成功
+	calls.Store(0)
+	cfg.RetryOnClasses = config.DefaultRetryOn | config.RetryOnTimeout
+	if result := NewProber(nil).Probe(context.Background(), cfg); result.Attempts != 2 || result.Status != 1 {
+		t.Fatalf("expected retry after timeout, got attempts=%d status=%d err=%v", result.Attempts, result.Status, result.Error)
+	}
+}
@@ -0,0 +1,92 @@
+package monitor
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+// applyStatusRules 按配置顺序匹配自定义状态映射规则，第一条命中的规则决定最终状态
+// 映射为绿色时仍按 slow_latency 降级；未命中任何规则时返回 matched=false，保留内置判定结果
+func applyStatusRules(rules []config.StatusRule, statusCode int, header http.Header, body []byte, latency int, slowLatency time.Duration) (status int, subStatus storage.SubStatus, matched bool) {
+	if len(rules) == 0 {
+		return 0, storage.SubStatusNone, false
+	}
+
+	var docs []any
+	docsDecoded := false
+	for i := range rules {
+		r := &rules[i]
+		if len(r.HTTPCodes) > 0 && !slices.Contains(r.HTTPCodes, statusCode) {
+			continue
+		}
+
+		switch {
+		case r.JSONPath != "":
+			if !docsDecoded {
+				docs = decodeUsageDocuments(body)
+				docsDecoded = true
+			}
+			value, ok := lastJSONValue(docs, r.JSONPath)
+			if !ok || !matchRuleValue(r, value) {
+				continue
+			}
+		case r.Header != "":
+			values := header.Values(r.Header)
+			if len(values) == 0 || !matchRuleValue(r, strings.Join(values, ", ")) {
+				continue
+			}
+		}
+
+		if r.StatusValue == 1 && slowLatency > 0 && latency > int(slowLatency/time.Millisecond) {
+			return 2, storage.SubStatusSlowLatency, true
+		}
+		return r.StatusValue, storage.SubStatus(r.SubStatus), true
+	}
+	return 0, storage.SubStatusNone, false
+}
+
+// matchRuleValue 按 equals / contains 比较取值；均未配置时仅要求存在
+func matchRuleValue(r *config.StatusRule, value string) bool {
+	switch {
+	case r.Equals != "":
+		return value == r.Equals
+	case r.Contains != "":
+		return strings.Contains(value, r.Contains)
+	default:
+		return true
+	}
+}
+
+// lastJSONValue 返回路径在文档列表中最后一次出现的值（转换为字符串，便于与配置比较）
+func lastJSONValue(docs []any, path string) (string, bool) {
+	for i := len(docs) - 1; i >= 0; i-- {
+		v, ok := lookupJSONPath(docs[i], path)
+		if !ok {
+			continue
+		}
+		switch val := v.(type) {
+		case string:
+			return val, true
+		case float64:
+			return strconv.FormatFloat(val, 'f', -1, 64), true
+		case bool:
+			return strconv.FormatBool(val), true
+		case nil:
+			return "null", true
+		default:
+			data, err := json.Marshal(val)
+			if err != nil {
+				return "", false
+			}
+			return string(data), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,50 @@
+package monitor
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestApplyStatusRules(t *testing.T) {
+	t.Parallel()
+
+	rules := []config.StatusRule{
+		{HTTPCodes: []int{503}, Header: "Retry-After", Status: "yellow", StatusValue: 2, SubStatus: "maintenance"},
+		{HTTPCodes: []int{200}, JSONPath: "error.code", Equals: "1001", Status: "red", StatusValue: 0, SubStatus: "quota_exhausted"},
+		{JSONPath: "choices.0.finish_reason", Contains: "stop", Status: "green", StatusValue: 1},
+	}
+	header := http.Header{}
+	header.Set("Retry-After", "600")
+
+	tests := []struct {
+		name      string
+		code      int
+		header    http.Header
+		body      string
+		latency   int
+		wantOK    bool
+		status    int
+		subStatus storage.SubStatus
+	}{
+		{name: "503 with header", code: 503, header: header, wantOK: true, status: 2, subStatus: "maintenance"},
+		{name: "503 without header", code: 503, header: http.Header{}},
+		{name: "200 error json", code: 200, body: `{"error":{"code":1001}}`, wantOK: true, status: 0, subStatus: "quota_exhausted"},
+		{name: "sse last event", code: 200, body: "data: {\"choices\":[{\"finish_reason\":null}]}\n\ndata: {\"choices\":[{\"finish_reason\":\"stop\"}]}\n\n", wantOK: true, status: 1},
+		{name: "green keeps slow latency", code: 200, body: `{"choices":[{"finish_reason":"stop"}]}`, latency: 6000, wantOK: true, status: 2, subStatus: storage.SubStatusSlowLatency},
+		{name: "no match", code: 200, body: `{"ok":true}`},
+	}
+
+	for _, tt := range tests {
+		status, subStatus, ok := applyStatusRules(rules, tt.code, tt.header, []byte(tt.body), tt.latency, 5*time.Second)
+		if ok != tt.wantOK {
+			t.Fatalf("%s: matched=%v, want %v", tt.name, ok, tt.wantOK)
+		}
+		if ok && (status != tt.status || subStatus != tt.subStatus) {
+			t.Fatalf("%s: got (%d, %q), want (%d, %q)", tt.name, status, subStatus, tt.status, tt.subStatus)
+		}
+	}
+}
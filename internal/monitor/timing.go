@@ -0,0 +1,140 @@
+package monitor
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"monitor/internal/config"
+)
+
+// PhaseTimings 单次 HTTP 请求各连接阶段耗时（毫秒，-1 表示该阶段未发生，如复用连接时无 DNS/TCP/TLS）
+type PhaseTimings struct {
+	DNSMs     int
+	ConnectMs int
+	TLSMs     int
+	TTFBMs    int // 从发起请求到收到响应首字节
+}
+
+// phaseTimer 基于 httptrace 记录连接阶段时间点
+// 回调可能来自 Transport 的不同 goroutine（如 Happy Eyeballs 并发拨号），需加锁
+type phaseTimer struct {
+	mu sync.Mutex
+
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	firstByte    time.Time
+
+	remoteFamily string // 承载请求的连接地址族（ipv4 / ipv6，含复用连接）
+	ipv6Failed   bool   // 存在失败或被取消的 IPv6 拨号（Happy Eyeballs 回退到 IPv4 时为 true）
+}
+
+// newPhaseTimer 创建计时器，以当前时间作为请求起点
+func newPhaseTimer() *phaseTimer {
+	return &phaseTimer{start: time.Now()}
+}
+
+// trace 返回挂载到请求 context 上的 httptrace 回调
+func (t *phaseTimer) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart, false) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.mark(&t.dnsDone, true) },
+		// 并发拨号时取最早开始与最后一次成功完成
+		ConnectStart: func(string, string) { t.mark(&t.connectStart, false) },
+		ConnectDone: func(_, addr string, err error) {
+			if err == nil {
+				t.mark(&t.connectDone, true)
+			} else if addrFamily(addr) == config.IPFamilyIPv6 {
+				t.mu.Lock()
+				t.ipv6Failed = true
+				t.mu.Unlock()
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Conn == nil {
+				return
+			}
+			family := addrFamily(info.Conn.RemoteAddr().String())
+			t.mu.Lock()
+			t.remoteFamily = family
+			t.mu.Unlock()
+		},
+		TLSHandshakeStart: func() { t.mark(&t.tlsStart, false) },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				t.mark(&t.tlsDone, true)
+			}
+		},
+		GotFirstResponseByte: func() { t.mark(&t.firstByte, false) },
+	}
+}
+
+// mark 记录时间点；overwrite=false 时仅记录第一次
+func (t *phaseTimer) mark(dst *time.Time, overwrite bool) {
+	now := time.Now()
+	t.mu.Lock()
+	if overwrite || dst.IsZero() {
+		*dst = now
+	}
+	t.mu.Unlock()
+}
+
+// timings 计算各阶段耗时
+func (t *phaseTimer) timings() PhaseTimings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return PhaseTimings{
+		DNSMs:     spanMs(t.dnsStart, t.dnsDone),
+		ConnectMs: spanMs(t.connectStart, t.connectDone),
+		TLSMs:     spanMs(t.tlsStart, t.tlsDone),
+		TTFBMs:    spanMs(t.start, t.firstByte),
+	}
+}
+
+// ipFamily 返回承载请求的连接地址族（未建立连接时为空），
+// 以及是否发生了 IPv6 拨号失败后由 IPv4 连接承载请求（Happy Eyeballs 回退）
+func (t *phaseTimer) ipFamily() (family string, ipv6Fallback bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.remoteFamily, t.ipv6Failed && t.remoteFamily == config.IPFamilyIPv4
+}
+
+// addrFamily 返回 "host:port" 地址的地址族（ipv4 / ipv6），无法解析为 IP 时返回空字符串
+func addrFamily(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	ip := net.ParseIP(host)
+	switch {
+	case ip == nil:
+		return ""
+	case ip.To4() != nil:
+		return config.IPFamilyIPv4
+	default:
+		return config.IPFamilyIPv6
+	}
+}
+
+// spanMs 返回两个时间点间隔的毫秒数，任一时间点缺失时返回 -1
+func spanMs(from, to time.Time) int {
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return -1
+	}
+	return int(to.Sub(from).Milliseconds())
+}
+
+// phaseMsPtr 将阶段耗时转换为存储字段（-1 表示未发生，存为 NULL）
+func phaseMsPtr(ms int) *int {
+	if ms < 0 {
+		return nil
+	}
+	return &ms
+}
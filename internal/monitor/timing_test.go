@@ -0,0 +1,42 @@
+package monitor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"testing"
+)
+
+func TestPhaseTimerPlainHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	timer := newPhaseTimer()
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), timer.trace()))
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	got := timer.timings()
+	// IP 直连：无 DNS；明文 HTTP：无 TLS
+	if got.DNSMs != -1 || got.TLSMs != -1 {
+		t.Errorf("expected no DNS/TLS phase, got %+v", got)
+	}
+	if got.ConnectMs < 0 || got.TTFBMs < 0 {
+		t.Errorf("expected connect/ttfb to be recorded, got %+v", got)
+	}
+}
+
+func TestPhaseMsPtr(t *testing.T) {
+	if phaseMsPtr(-1) != nil {
+		t.Error("-1 should map to nil")
+	}
+	if v := phaseMsPtr(0); v == nil || *v != 0 {
+		t.Errorf("0 should be kept, got %v", v)
+	}
+}
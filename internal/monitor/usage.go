@@ -0,0 +1,130 @@
+package monitor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// extractTokenUsage 按配置路径从响应体中提取 token 用量
+//
+// 支持两种响应形态：
+//   - 普通 JSON 响应：直接按路径取值
+//   - SSE 流式响应：逐条解析 data: 事件，每个字段取最后一次出现的值
+//     （流式接口通常在最后的事件中给出累计用量，Anthropic 的 input/output 分散在不同事件中）
+//
+// paths 按顺序尝试，第一个命中任意字段的路径生效；"a+b" 表示多个字段求和。
+// 未命中任何路径时返回 0。
+func extractTokenUsage(body []byte, paths []string) int64 {
+	if len(body) == 0 || len(paths) == 0 {
+		return 0
+	}
+
+	docs := decodeUsageDocuments(body)
+	if len(docs) == 0 {
+		return 0
+	}
+
+	for _, expr := range paths {
+		var total int64
+		matched := false
+		for _, term := range strings.Split(expr, "+") {
+			term = strings.TrimSpace(term)
+			if v, ok := lastUsageValue(docs, term); ok {
+				total += v
+				matched = true
+			}
+		}
+		if matched {
+			return total
+		}
+	}
+	return 0
+}
+
+// decodeUsageDocuments 将响应体解析为 JSON 文档列表（普通 JSON 为单个文档，SSE 为每个事件一个文档）
+func decodeUsageDocuments(body []byte) []any {
+	var doc any
+	if err := json.Unmarshal(bytes.TrimSpace(body), &doc); err == nil {
+		return []any{doc}
+	}
+
+	var docs []any
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "" || payload == "[DONE]" {
+			continue
+		}
+		var event any
+		if err := json.Unmarshal([]byte(payload), &event); err == nil {
+			docs = append(docs, event)
+		}
+	}
+	return docs
+}
+
+// lastUsageValue 返回路径在文档列表中最后一次出现的数值
+func lastUsageValue(docs []any, path string) (int64, bool) {
+	for i := len(docs) - 1; i >= 0; i-- {
+		if v, ok := lookupUsagePath(docs[i], path); ok {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// lookupUsagePath 按点分路径取非负整数值
+func lookupUsagePath(doc any, path string) (int64, bool) {
+	cur, ok := lookupJSONPath(doc, path)
+	if !ok {
+		return 0, false
+	}
+
+	switch v := cur.(type) {
+	case float64:
+		if v < 0 {
+			return 0, false
+		}
+		return int64(v), true
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return 0, false
+		}
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// lookupJSONPath 按点分路径取值（数字段表示数组下标）
+func lookupJSONPath(doc any, path string) (any, bool) {
+	cur := doc
+	for _, seg := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]any:
+			next, ok := node[seg]
+			if !ok {
+				return nil, false
+			}
+			cur = next
+		case []any:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, false
+			}
+			cur = node[idx]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
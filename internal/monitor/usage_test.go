@@ -0,0 +1,81 @@
+package monitor
+
+import (
+	"testing"
+
+	"monitor/internal/config"
+)
+
+func TestExtractTokenUsageOpenAI(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"id":"x","usage":{"prompt_tokens":12,"completion_tokens":30,"total_tokens":42}}`)
+	if got := extractTokenUsage(body, []string{"usage.total_tokens"}); got != 42 {
+		t.Fatalf("expected 42 tokens, got %d", got)
+	}
+}
+
+func TestExtractTokenUsageSumAndFallback(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"usage":{"input_tokens":10,"output_tokens":5}}`)
+	paths := []string{"usage.total_tokens", "usage.input_tokens+usage.output_tokens"}
+	if got := extractTokenUsage(body, paths); got != 15 {
+		t.Fatalf("expected 15 tokens, got %d", got)
+	}
+}
+
+func TestExtractTokenUsageSSE(t *testing.T) {
+	t.Parallel()
+
+	// Anthropic 流式：input 在 message_start，output 在 message_delta（取最后一次出现）
+	body := []byte("event: message_start\n" +
+		`data: {"type":"message_start","message":{"usage":{"input_tokens":20}}}` + "\n\n" +
+		"event: message_delta\n" +
+		`data: {"type":"message_delta","usage":{"output_tokens":3}}` + "\n\n" +
+		`data: {"type":"message_delta","usage":{"output_tokens":7}}` + "\n\n" +
+		"data: [DONE]\n")
+	got := extractTokenUsage(body, []string{"message.usage.input_tokens+usage.output_tokens"})
+	if got != 27 {
+		t.Fatalf("expected 27 tokens, got %d", got)
+	}
+}
+
+func TestExtractTokenUsageArrayIndexAndMiss(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"choices":[{"usage":{"total_tokens":"9"}}]}`)
+	if got := extractTokenUsage(body, []string{"choices.0.usage.total_tokens"}); got != 9 {
+		t.Fatalf("expected 9 tokens, got %d", got)
+	}
+	if got := extractTokenUsage(body, []string{"usage.total_tokens"}); got != 0 {
+		t.Fatalf("expected 0 for missing path, got %d", got)
+	}
+	if got := extractTokenUsage([]byte("not json"), []string{"usage.total_tokens"}); got != 0 {
+		t.Fatalf("expected 0 for non-json body, got %d", got)
+	}
+}
+
+func TestExtractTokenUsageDefaultPathsAnthropic(t *testing.T) {
+	t.Parallel()
+
+	var u config.UsageConfig
+	if err := u.Normalize(); err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+
+	// 流式：input 只出现在 message_start 的 message.usage 中
+	stream := []byte("event: message_start\n" +
+		`data: {"type":"message_start","message":{"usage":{"input_tokens":20,"output_tokens":1}}}` + "\n\n" +
+		"event: message_delta\n" +
+		`data: {"type":"message_delta","usage":{"output_tokens":7}}` + "\n\n")
+	if got := extractTokenUsage(stream, u.TokenPaths); got != 27 {
+		t.Fatalf("expected 27 tokens for streaming response, got %d", got)
+	}
+
+	// 非流式：input/output 均在顶层 usage 中
+	plain := []byte(`{"type":"message","usage":{"input_tokens":20,"output_tokens":7}}`)
+	if got := extractTokenUsage(plain, u.TokenPaths); got != 27 {
+		t.Fatalf("expected 27 tokens for non-streaming response, got %d", got)
+	}
+}
@@ -0,0 +1,252 @@
+package scheduler
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/monitor"
+	"monitor/internal/storage"
+)
+
+// budgetTracker 服务商每日预算用量（按 UTC 自然日统计，跨日自动清零）
+type budgetTracker struct {
+	mu    sync.Mutex
+	day   string
+	usage map[string]*budgetUsage // 小写 provider -> 当日用量
+}
+
+// budgetUsage 单个服务商的当日用量
+type budgetUsage struct {
+	provider    string
+	requests    int64
+	tokens      int64
+	exhaustedAt time.Time                  // 首次耗尽时间（零值表示未耗尽）
+	skipped     int64                      // 因预算耗尽跳过的探测次数
+	rounds      map[storage.MonitorKey]int // throttle 模式下各监测项耗尽后的巡检周期计数
+}
+
+// BudgetInfo 服务商当日预算快照（/api/admin/budgets）
+type BudgetInfo struct {
+	Provider       string
+	DailyRequests  int64 // 0 表示不限制
+	DailyTokens    int64 // 0 表示不限制
+	Action         string
+	ThrottleFactor int
+
+	Day         string    // 统计日期（UTC）
+	Requests    int64     // 当日已发起的请求数（含重试）
+	Tokens      int64     // 当日已消耗的 token 数
+	Exhausted   bool      // 当日预算是否已耗尽
+	ExhaustedAt time.Time // 耗尽时间（零值表示未耗尽）
+	Skipped     int64     // 当日因预算耗尽跳过的探测次数
+}
+
+// budgetDay 预算统计使用的日期（UTC，与 usage 用量统计一致）
+func budgetDay(now time.Time) string {
+	return now.UTC().Format("2006-01-02")
+}
+
+// usageLocked 返回服务商当日用量，跨日时清空全部统计（需持有 t.mu）
+func (t *budgetTracker) usageLocked(provider string, now time.Time) *budgetUsage {
+	if day := budgetDay(now); t.day != day {
+		t.day = day
+		t.usage = nil
+	}
+	if t.usage == nil {
+		t.usage = make(map[string]*budgetUsage)
+	}
+	key := strings.ToLower(strings.TrimSpace(provider))
+	u := t.usage[key]
+	if u == nil {
+		u = &budgetUsage{provider: provider}
+		t.usage[key] = u
+	}
+	return u
+}
+
+// exhausted 当日用量是否已达到预算上限
+func (u *budgetUsage) exhausted(b *config.ProviderBudget) bool {
+	return (b.DailyRequests > 0 && u.requests >= b.DailyRequests) ||
+		(b.DailyTokens > 0 && u.tokens >= b.DailyTokens)
+}
+
+// budget 返回监测项所属服务商的预算配置（未配置时返回 nil）
+func (s *Scheduler) budget(m *config.ServiceConfig) *config.ProviderBudget {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	if s.cfg == nil {
+		return nil
+	}
+	return s.cfg.Budgets.Lookup(m.Provider)
+}
+
+// budgetAllows 判断本周期是否允许探测该监测项
+// 预算耗尽后 pause 模式跳过全部探测；throttle 模式每 throttle_factor 个周期放行一次
+func (s *Scheduler) budgetAllows(m *config.ServiceConfig, now time.Time) bool {
+	b := s.budget(m)
+	if b == nil {
+		return true
+	}
+
+	s.budgets.mu.Lock()
+	defer s.budgets.mu.Unlock()
+	u := s.budgets.usageLocked(m.Provider, now)
+	if !u.exhausted(b) {
+		// 热更新调高上限后恢复探测
+		u.exhaustedAt, u.rounds = time.Time{}, nil
+		return true
+	}
+
+	if b.Action == config.BudgetActionThrottle {
+		if u.rounds == nil {
+			u.rounds = make(map[storage.MonitorKey]int)
+		}
+		key := monitorKeyOf(m)
+		n := u.rounds[key]
+		u.rounds[key] = n + 1
+		if n%b.ThrottleFactor == b.ThrottleFactor-1 {
+			return true
+		}
+	}
+	u.skipped++
+	return false
+}
+
+// chargeBudget 累计探测消耗的请求数与 token，首次耗尽时输出日志
+func (s *Scheduler) chargeBudget(m *config.ServiceConfig, result *monitor.ProbeResult, now time.Time) {
+	if result == nil || (result.Attempts == 0 && result.UsageTokens == 0) {
+		return
+	}
+	b := s.budget(m)
+	if b == nil {
+		return
+	}
+
+	s.budgets.mu.Lock()
+	u := s.budgets.usageLocked(m.Provider, now)
+	u.requests += int64(result.Attempts)
+	u.tokens += result.UsageTokens
+	justExhausted := u.exhaustedAt.IsZero() && u.exhausted(b)
+	if justExhausted {
+		u.exhaustedAt = now
+	}
+	requests, tokens := u.requests, u.tokens
+	s.budgets.mu.Unlock()
+
+	if justExhausted {
+		logger.Warn("scheduler", "服务商当日探测预算已耗尽",
+			"provider", b.Provider, "requests", requests, "daily_requests", b.DailyRequests,
+			"tokens", tokens, "daily_tokens", b.DailyTokens, "action", b.Action)
+	}
+}
+
+// recordBudgetSkip 记录因预算耗尽跳过的探测（任务最近结果标记为 budget_exhausted，不写入 probe_history）
+func (s *Scheduler) recordBudgetSkip(m *config.ServiceConfig, now time.Time) {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	st := s.statsLocked(monitorKeyOf(m))
+	st.lastRun = now
+	st.lastDuration = 0
+	st.lastResult = &TaskResult{Status: -1, SubStatus: storage.SubStatusBudgetExhausted}
+	st.budgetSkips++
+}
+
+// restoreBudgets 从每日汇总与 token 用量恢复当日已消耗的预算（启动时调用）
+// 请求数按当日探测记录数估算（重试次数不计入汇总），token 数取 usage 统计
+func (s *Scheduler) restoreBudgets(store storage.Storage, cfg *config.AppConfig, now time.Time) {
+	if store == nil || cfg == nil || !cfg.Budgets.Enabled() {
+		return
+	}
+	day := budgetDay(now)
+
+	var keys []storage.MonitorKey
+	for i := range cfg.Monitors {
+		if cfg.Budgets.Lookup(cfg.Monitors[i].Provider) != nil {
+			keys = append(keys, monitorKeyOf(&cfg.Monitors[i]))
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	s.budgets.mu.Lock()
+	defer s.budgets.mu.Unlock()
+
+	if rs, ok := store.(storage.DailyRollupStorage); ok {
+		rows, err := rs.GetDailyRollupBatch(keys, day, day)
+		if err != nil {
+			logger.Warn("scheduler", "恢复当日探测预算失败", "error", err)
+		}
+		for key, days := range rows {
+			for _, row := range days {
+				s.budgets.usageLocked(key.Provider, now).requests += int64(row.Total)
+			}
+		}
+	}
+
+	if us, ok := store.(storage.UsageStorage); ok && cfg.Usage.Enabled {
+		records, err := us.GetUsage(day, day)
+		if err != nil {
+			logger.Warn("scheduler", "恢复当日 token 预算失败", "error", err)
+		}
+		for _, r := range records {
+			if cfg.Budgets.Lookup(r.Provider) != nil {
+				s.budgets.usageLocked(r.Provider, now).tokens += r.Tokens
+			}
+		}
+	}
+
+	for _, u := range s.budgets.usage {
+		if b := cfg.Budgets.Lookup(u.provider); b != nil && u.exhausted(b) {
+			u.exhaustedAt = now
+			logger.Warn("scheduler", "服务商当日探测预算已耗尽（启动时恢复）",
+				"provider", b.Provider, "requests", u.requests, "tokens", u.tokens, "action", b.Action)
+		}
+	}
+}
+
+// Budgets 返回已配置预算的服务商当日用量，按 provider 排序
+func (s *Scheduler) Budgets() []BudgetInfo {
+	return s.budgetsAt(time.Now())
+}
+
+// budgetsAt 返回 now 所在日期的预算用量快照
+func (s *Scheduler) budgetsAt(now time.Time) []BudgetInfo {
+	s.cfgMu.RLock()
+	var budgets []config.ProviderBudget
+	if s.cfg != nil {
+		budgets = append(budgets, s.cfg.Budgets.Providers...)
+	}
+	s.cfgMu.RUnlock()
+
+	s.budgets.mu.Lock()
+	infos := make([]BudgetInfo, 0, len(budgets))
+	for i := range budgets {
+		b := &budgets[i]
+		u := s.budgets.usageLocked(b.Provider, now)
+		info := BudgetInfo{
+			Provider:       b.Provider,
+			DailyRequests:  b.DailyRequests,
+			DailyTokens:    b.DailyTokens,
+			Action:         b.Action,
+			ThrottleFactor: b.ThrottleFactor,
+			Day:            s.budgets.day,
+			Requests:       u.requests,
+			Tokens:         u.tokens,
+			Exhausted:      u.exhausted(b),
+			Skipped:        u.skipped,
+		}
+		if info.Exhausted {
+			info.ExhaustedAt = u.exhaustedAt
+		}
+		infos = append(infos, info)
+	}
+	s.budgets.mu.Unlock()
+
+	sort.Slice(infos, func(i, j int) bool { return strings.ToLower(infos[i].Provider) < strings.ToLower(infos[j].Provider) })
+	return infos
+}
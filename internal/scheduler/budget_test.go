@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/monitor"
+	"monitor/internal/storage"
+)
+
+func TestBudgetPauseAndThrottle(t *testing.T) {
+	now := time.Date(2024, 5, 16, 12, 0, 0, 0, time.UTC)
+	paused := config.ServiceConfig{Provider: "Relay", Service: "cc", Channel: "vip"}
+	throttled := config.ServiceConfig{Provider: "Other", Service: "cc"}
+	free := config.ServiceConfig{Provider: "Free", Service: "cc"}
+	s := &Scheduler{cfg: &config.AppConfig{Budgets: config.BudgetConfig{Providers: []config.ProviderBudget{
+		{Provider: "relay", DailyRequests: 3, Action: config.BudgetActionPause},
+		{Provider: "other", DailyRequests: 1, Action: config.BudgetActionThrottle, ThrottleFactor: 3},
+	}}}}
+
+	// 未达到上限时照常探测；未配置预算的服务商不受限制
+	s.chargeBudget(&paused, &monitor.ProbeResult{Attempts: 2}, now)
+	if !s.budgetAllows(&paused, now) || !s.budgetAllows(&free, now) {
+		t.Fatal("expected probes allowed before budget is exhausted")
+	}
+
+	// pause：耗尽后当日跳过全部探测
+	s.chargeBudget(&paused, &monitor.ProbeResult{Attempts: 1}, now)
+	for i := 0; i < 3; i++ {
+		if s.budgetAllows(&paused, now) {
+			t.Fatalf("round %d: paused provider should be skipped", i)
+		}
+	}
+
+	// throttle：每 throttle_factor 个周期放行一次
+	s.chargeBudget(&throttled, &monitor.ProbeResult{Attempts: 1}, now)
+	var allowed []bool
+	for i := 0; i < 6; i++ {
+		allowed = append(allowed, s.budgetAllows(&throttled, now))
+	}
+	if allowed[0] || allowed[1] || !allowed[2] || allowed[3] || allowed[4] || !allowed[5] {
+		t.Fatalf("unexpected throttle pattern: %v", allowed)
+	}
+
+	infos := s.budgetsAt(now)
+	if len(infos) != 2 || infos[0].Provider != "other" || infos[1].Provider != "relay" {
+		t.Fatalf("unexpected budgets: %+v", infos)
+	}
+	if r := infos[1]; !r.Exhausted || r.Requests != 3 || r.Skipped != 3 || !r.ExhaustedAt.Equal(now) {
+		t.Fatalf("unexpected relay budget: %+v", r)
+	}
+	if o := infos[0]; !o.Exhausted || o.Skipped != 4 {
+		t.Fatalf("unexpected throttled budget: %+v", o)
+	}
+
+	// 跨日后清零
+	if !s.budgetAllows(&paused, now.Add(24*time.Hour)) {
+		t.Fatal("budget should reset on the next UTC day")
+	}
+}
+
+func TestRestoreBudgets(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "monitor.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer store.Close()
+	if err := store.Init(); err != nil {
+		t.Fatalf("init sqlite: %v", err)
+	}
+
+	now := time.Now().UTC()
+	for i := 0; i < 5; i++ {
+		if err := store.SaveRecord(&storage.ProbeRecord{Provider: "Relay", Service: "cc", Channel: "vip", Status: 1, Timestamp: now.Unix() - int64(i)}); err != nil {
+			t.Fatalf("save record: %v", err)
+		}
+	}
+	if err := store.AddUsage(storage.MonitorKey{Provider: "Relay", Service: "cc", Channel: "vip"}, budgetDay(now), 700, 0); err != nil {
+		t.Fatalf("add usage: %v", err)
+	}
+
+	cfg := &config.AppConfig{
+		Usage:    config.UsageConfig{Enabled: true},
+		Budgets:  config.BudgetConfig{Providers: []config.ProviderBudget{{Provider: "Relay", DailyRequests: 100, DailyTokens: 500, Action: config.BudgetActionPause}}},
+		Monitors: []config.ServiceConfig{{Provider: "Relay", Service: "cc", Channel: "vip"}},
+	}
+	s := &Scheduler{cfg: cfg}
+	s.restoreBudgets(store, cfg, now)
+
+	infos := s.Budgets()
+	if len(infos) != 1 || infos[0].Requests != 5 || infos[0].Tokens != 700 || !infos[0].Exhausted {
+		t.Fatalf("unexpected restored budget: %+v", infos)
+	}
+	if s.budgetAllows(&cfg.Monitors[0], now) {
+		t.Fatal("restored exhausted budget should pause probing")
+	}
+}
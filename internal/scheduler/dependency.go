@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/monitor"
+	"monitor/internal/storage"
+)
+
+// parentOutcome 父通道在当前周期的探测结果（依赖感知探测使用）
+type parentOutcome struct {
+	started time.Time
+	done    chan struct{}        // 探测结束后关闭
+	result  *monitor.ProbeResult // 已保存的探测结果；探测被丢弃或保存失败时为 nil
+}
+
+// parentPath 父通道的引用路径（与子通道 parent 字段格式一致：provider/service/channel）
+func parentPath(m *config.ServiceConfig) string {
+	return fmt.Sprintf("%s/%s/%s", m.Provider, m.Service, m.Channel)
+}
+
+// beginParentProbe 登记父通道本周期的探测，返回的 outcome 需在探测结束后调用 finishParentProbe
+// 非父层监测项返回 nil
+func (s *Scheduler) beginParentProbe(m *config.ServiceConfig) *parentOutcome {
+	if strings.TrimSpace(m.Parent) != "" {
+		return nil
+	}
+	outcome := &parentOutcome{started: time.Now(), done: make(chan struct{})}
+
+	s.parentMu.Lock()
+	if s.parents == nil {
+		s.parents = make(map[string]*parentOutcome)
+	}
+	s.parents[parentPath(m)] = outcome
+	s.parentMu.Unlock()
+	return outcome
+}
+
+// finishParentProbe 记录父通道探测结果并唤醒等待中的子通道（result 为 nil 表示结果未保存）
+func finishParentProbe(outcome *parentOutcome, result *monitor.ProbeResult) {
+	if outcome == nil {
+		return
+	}
+	outcome.result = result
+	close(outcome.done)
+}
+
+// parentFailedHard 父通道是否因网络错误或服务端错误失败（此时子通道探测同一端点必然失败）
+func parentFailedHard(result *monitor.ProbeResult) bool {
+	return result != nil && result.Status == 0 &&
+		(result.SubStatus == storage.SubStatusNetworkError || result.SubStatus == storage.SubStatusServerError)
+}
+
+// inheritedResult 依赖感知探测：父通道在本周期内已因网络/服务端错误失败时，子通道沿用其状态而不实际发起请求
+// 父通道仍在探测中时等待其完成；父通道不在本周期（距上次开始超过子通道 interval）或结果不可用时返回 nil，照常探测
+func (s *Scheduler) inheritedResult(ctx context.Context, m *config.ServiceConfig, interval time.Duration) *monitor.ProbeResult {
+	path := strings.TrimSpace(m.Parent)
+	if path == "" {
+		return nil
+	}
+
+	s.parentMu.Lock()
+	outcome := s.parents[path]
+	s.parentMu.Unlock()
+	if outcome == nil || time.Since(outcome.started) >= interval {
+		return nil
+	}
+
+	select {
+	case <-outcome.done:
+	case <-ctx.Done():
+		return nil
+	}
+
+	parent := outcome.result
+	if !parentFailedHard(parent) {
+		return nil
+	}
+
+	logger.Info("scheduler", "父通道本周期探测失败，跳过子通道探测",
+		"provider", m.Provider, "service", m.Service, "channel", m.Channel, "model", m.Model,
+		"parent", path, "sub_status", parent.SubStatus, "http_code", parent.HttpCode)
+
+	return &monitor.ProbeResult{
+		Namespace: m.Namespace,
+		Provider:  m.Provider,
+		Service:   m.Service,
+		Channel:   m.Channel,
+		Model:     m.Model,
+		Status:    parent.Status,
+		SubStatus: parent.SubStatus,
+		HttpCode:  parent.HttpCode,
+		Timestamp: time.Now().Unix(),
+		Error:     fmt.Errorf("父通道 %s 探测失败，沿用其状态", path),
+		Timings:   monitor.PhaseTimings{DNSMs: -1, ConnectMs: -1, TLSMs: -1, TTFBMs: -1},
+	}
+}
@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/monitor"
+	"monitor/internal/storage"
+)
+
+func TestInheritedResultFromFailedParent(t *testing.T) {
+	s := &Scheduler{}
+	parent := config.ServiceConfig{Provider: "demo", Service: "cc", Channel: "vip", Model: "base"}
+	child := config.ServiceConfig{Provider: "demo", Service: "cc", Channel: "vip", Model: "opus", Parent: "demo/cc/vip"}
+	ctx := context.Background()
+
+	// 无父通道记录时照常探测
+	if got := s.inheritedResult(ctx, &child, time.Minute); got != nil {
+		t.Fatalf("expected nil without parent outcome, got %+v", got)
+	}
+	if s.beginParentProbe(&child) != nil {
+		t.Fatal("child monitor should not register as parent")
+	}
+
+	// 子通道等待进行中的父通道探测完成后沿用其状态
+	outcome := s.beginParentProbe(&parent)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		finishParentProbe(outcome, &monitor.ProbeResult{Status: 0, SubStatus: storage.SubStatusServerError, HttpCode: 502})
+	}()
+	got := s.inheritedResult(ctx, &child, time.Minute)
+	if got == nil || got.Status != 0 || got.SubStatus != storage.SubStatusServerError || got.HttpCode != 502 || got.Model != "opus" {
+		t.Fatalf("expected inherited server_error result, got %+v", got)
+	}
+
+	// 父通道上一周期的结果不沿用
+	if got := s.inheritedResult(ctx, &child, time.Nanosecond); got != nil {
+		t.Fatalf("expected nil for stale parent outcome, got %+v", got)
+	}
+
+	// 父通道因其它原因失败、成功或结果被丢弃时照常探测
+	for _, result := range []*monitor.ProbeResult{
+		{Status: 0, SubStatus: storage.SubStatusAuthError, HttpCode: 401},
+		{Status: 0, SubStatus: storage.SubStatusContentMismatch, HttpCode: 200},
+		{Status: 1},
+		nil,
+	} {
+		outcome := s.beginParentProbe(&parent)
+		finishParentProbe(outcome, result)
+		if got := s.inheritedResult(ctx, &child, time.Minute); got != nil {
+			t.Fatalf("expected probe for parent result %+v, got %+v", result, got)
+		}
+	}
+
+	// 等待父通道时 context 取消则放弃沿用
+	s.beginParentProbe(&parent)
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if got := s.inheritedResult(canceled, &child, time.Minute); got != nil {
+		t.Fatalf("expected nil after context canceled, got %+v", got)
+	}
+}
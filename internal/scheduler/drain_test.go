@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+)
+
+func TestDrainWaitsForInflightProbes(t *testing.T) {
+	s := &Scheduler{cfg: &config.AppConfig{ShutdownDrainTimeoutDuration: time.Second}}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	finished := make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		time.Sleep(20 * time.Millisecond)
+		if ctx.Err() != nil {
+			t.Error("probe canceled before drain timeout")
+		}
+		close(finished)
+	}()
+
+	s.drain(cancel)
+
+	select {
+	case <-finished:
+	default:
+		t.Fatal("drain returned before in-flight probe finished")
+	}
+}
+
+func TestDrainCancelsAfterTimeout(t *testing.T) {
+	s := &Scheduler{cfg: &config.AppConfig{ShutdownDrainTimeoutDuration: 20 * time.Millisecond}}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		<-ctx.Done() // 模拟挂起的探测，仅在取消后退出
+	}()
+
+	start := time.Now()
+	s.drain(cancel)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("drain took too long: %v", elapsed)
+	}
+	if ctx.Err() == nil {
+		t.Fatal("expected probes to be canceled after timeout")
+	}
+}
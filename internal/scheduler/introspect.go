@@ -0,0 +1,210 @@
+package scheduler
+
+import (
+	"sort"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/monitor"
+	"monitor/internal/storage"
+)
+
+// taskStats 监测项的运行统计
+// 按监测项 key 索引而非挂在 task 上：配置热更新重建任务堆后统计仍保留
+type taskStats struct {
+	running             bool
+	lastRun             time.Time
+	lastDuration        time.Duration
+	lastResult          *TaskResult
+	consecutiveFailures int
+
+	// 排队与延迟（max_concurrency 不足时到期任务在信号量上排队）
+	interval       time.Duration // 最近一次开始时的巡检间隔（用于判定周期超时）
+	starts         int
+	lastQueueWait  time.Duration
+	maxQueueWait   time.Duration
+	totalQueueWait time.Duration
+	lastStartDelay time.Duration
+	lateStarts     int
+	overruns       int
+
+	budgetSkips int // 因服务商预算耗尽跳过的探测次数
+	panics      int // 探测 panic 次数
+}
+
+// TaskResult 最近一次探测结果
+type TaskResult struct {
+	Status    int // 1=绿, 0=红, 2=黄；-1 表示未产生探测结果（预算耗尽跳过 budget_exhausted）
+	SubStatus storage.SubStatus
+	HttpCode  int
+	Latency   int  // ms
+	Attempts  int  // 实际发起的请求次数（含重试）
+	Inherited bool // 依赖感知探测沿用了父通道状态（未实际发起请求）
+}
+
+// TaskInfo 调度任务的运行状态快照（用于运维核对错峰与巡检间隔是否符合配置）
+type TaskInfo struct {
+	Provider string
+	Service  string
+	Channel  string
+	Model    string
+
+	Interval      time.Duration // 生效的巡检间隔（监测项 interval，未配置时为全局 interval）
+	StaggerOffset time.Duration // 最近一次重建任务堆时的首次执行延迟（组间错峰 + 组内间隔）
+	NextRun       time.Time     // 下次计划执行时间
+
+	Running             bool          // 当前是否在探测中
+	LastRun             time.Time     // 最近一次探测开始时间（零值表示尚未执行）
+	LastDuration        time.Duration // 最近一次探测耗时（含等待父通道）
+	LastResult          *TaskResult   // 最近一次探测结果（nil 表示尚未执行）
+	ConsecutiveFailures int           // 连续红色次数
+
+	Starts         int           // 自启动（或监测项加入调度）以来的探测次数
+	LastQueueWait  time.Duration // 最近一次等待并发槽的时间
+	AvgQueueWait   time.Duration // 平均等待并发槽的时间
+	MaxQueueWait   time.Duration // 最大等待并发槽的时间
+	LastStartDelay time.Duration // 最近一次开始时间晚于计划时间的时长（含排队与派发延迟）
+	LateStarts     int           // 开始时间晚于计划超过阈值（巡检间隔的 10%，不低于 5s）的次数
+	Overruns       int           // 周期超时次数：开始延迟 + 探测耗时超过巡检间隔
+	BudgetSkips    int           // 因服务商当日预算耗尽跳过的探测次数
+	Panics         int           // 探测 panic 次数（已隔离，不写入 probe_history）
+}
+
+func monitorKeyOf(m *config.ServiceConfig) storage.MonitorKey {
+	return storage.MonitorKey{Provider: m.Provider, Service: m.Service, Channel: m.Channel, Model: m.Model}
+}
+
+// Tasks 返回全部调度任务的运行状态，按下次执行时间升序
+func (s *Scheduler) Tasks() []TaskInfo {
+	s.mu.Lock()
+	infos := make([]TaskInfo, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		infos = append(infos, TaskInfo{
+			Provider:      t.monitor.Provider,
+			Service:       t.monitor.Service,
+			Channel:       t.monitor.Channel,
+			Model:         t.monitor.Model,
+			Interval:      t.interval,
+			StaggerOffset: t.staggerOffset,
+			NextRun:       t.nextRun,
+		})
+	}
+	s.mu.Unlock()
+
+	s.statsMu.Lock()
+	for i := range infos {
+		info := &infos[i]
+		st := s.stats[storage.MonitorKey{Provider: info.Provider, Service: info.Service, Channel: info.Channel, Model: info.Model}]
+		if st == nil {
+			continue
+		}
+		info.Running = st.running
+		info.LastRun = st.lastRun
+		info.LastDuration = st.lastDuration
+		if st.lastResult != nil {
+			result := *st.lastResult
+			info.LastResult = &result
+		}
+		info.ConsecutiveFailures = st.consecutiveFailures
+		info.Starts = st.starts
+		info.LastQueueWait = st.lastQueueWait
+		info.MaxQueueWait = st.maxQueueWait
+		if st.starts > 0 {
+			info.AvgQueueWait = st.totalQueueWait / time.Duration(st.starts)
+		}
+		info.LastStartDelay = st.lastStartDelay
+		info.LateStarts = st.lateStarts
+		info.Overruns = st.overruns
+		info.BudgetSkips = st.budgetSkips
+		info.Panics = st.panics
+	}
+	s.statsMu.Unlock()
+
+	sort.SliceStable(infos, func(i, j int) bool { return infos[i].NextRun.Before(infos[j].NextRun) })
+	return infos
+}
+
+// statsLocked 返回监测项统计，不存在时创建（需持有 s.statsMu）
+func (s *Scheduler) statsLocked(key storage.MonitorKey) *taskStats {
+	if s.stats == nil {
+		s.stats = make(map[storage.MonitorKey]*taskStats)
+	}
+	st := s.stats[key]
+	if st == nil {
+		st = &taskStats{}
+		s.stats[key] = st
+	}
+	return st
+}
+
+// recordStart 记录监测项获取到并发槽时的排队时间与开始延迟，返回是否为延迟开始
+func (s *Scheduler) recordStart(key storage.MonitorKey, interval, queueWait, startDelay time.Duration) bool {
+	late := startDelay > lateStartThreshold(interval)
+
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	st := s.statsLocked(key)
+	st.interval = interval
+	st.starts++
+	st.lastQueueWait = queueWait
+	st.maxQueueWait = max(st.maxQueueWait, queueWait)
+	st.totalQueueWait += queueWait
+	st.lastStartDelay = startDelay
+	if late {
+		st.lateStarts++
+	}
+	return late
+}
+
+// markRunning 记录监测项开始探测，返回开始时间
+func (s *Scheduler) markRunning(key storage.MonitorKey) time.Time {
+	now := time.Now()
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	st := s.statsLocked(key)
+	st.running = true
+	st.lastRun = now
+	return now
+}
+
+// recordRun 记录监测项探测结束（result 为 nil 表示探测未产生结果）
+func (s *Scheduler) recordRun(key storage.MonitorKey, started time.Time, result *monitor.ProbeResult, inherited bool) {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	st := s.stats[key]
+	if st == nil {
+		return
+	}
+	st.running = false
+	st.lastDuration = time.Since(started)
+	if st.interval > 0 && st.lastStartDelay+st.lastDuration > st.interval {
+		st.overruns++
+	}
+	if result == nil {
+		return
+	}
+	st.lastResult = &TaskResult{
+		Status:    result.Status,
+		SubStatus: result.SubStatus,
+		HttpCode:  result.HttpCode,
+		Latency:   result.Latency,
+		Attempts:  result.Attempts,
+		Inherited: inherited,
+	}
+	if result.Status == 0 {
+		st.consecutiveFailures++
+	} else {
+		st.consecutiveFailures = 0
+	}
+}
+
+// pruneStats 清理已不在调度中的监测项统计（需在重建任务堆后调用）
+func (s *Scheduler) pruneStats(active map[storage.MonitorKey]bool) {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	for key := range s.stats {
+		if !active[key] {
+			delete(s.stats, key)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/monitor"
+	"monitor/internal/storage"
+)
+
+func TestTasksIntrospection(t *testing.T) {
+	s := &Scheduler{fallback: 5 * time.Minute, wakeCh: make(chan struct{}, 1)}
+	defer func() {
+		s.mu.Lock()
+		s.tasks = s.tasks[:0]
+		s.resetTimerLocked()
+		s.mu.Unlock()
+	}()
+
+	solo := config.ServiceConfig{Provider: "a", Service: "cc", Channel: "vip", IntervalDuration: time.Minute}
+	parent := config.ServiceConfig{Provider: "b", Service: "cc", Channel: "vip", Model: "base"}
+	child := config.ServiceConfig{Provider: "b", Service: "cc", Channel: "vip", Model: "opus", Parent: "b/cc/vip"}
+	s.rebuildTasks(&config.AppConfig{Monitors: []config.ServiceConfig{solo, parent, child}}, false)
+
+	tasks := s.Tasks()
+	if len(tasks) != 3 {
+		t.Fatalf("expected 3 tasks, got %d", len(tasks))
+	}
+	// 第一组基准延迟为 0，仅有组级抖动（±5% 组间间隔）
+	if tasks[0].Provider != "a" || tasks[0].Interval != time.Minute || tasks[0].StaggerOffset > 2*time.Second {
+		t.Fatalf("unexpected first task: %+v", tasks[0])
+	}
+	// 组间错峰：第二组至少延后 5s（±5% 抖动）；组内子通道比父通道晚 2s
+	if tasks[1].Model != "base" || tasks[1].Interval != 5*time.Minute || tasks[1].StaggerOffset < 4*time.Second {
+		t.Fatalf("unexpected parent task: %+v", tasks[1])
+	}
+	if tasks[2].Model != "opus" || tasks[2].StaggerOffset-tasks[1].StaggerOffset != 2*time.Second {
+		t.Fatalf("unexpected child task: %+v", tasks[2])
+	}
+	if tasks[0].LastResult != nil || !tasks[0].LastRun.IsZero() {
+		t.Fatalf("expected no run stats before first probe: %+v", tasks[0])
+	}
+
+	key := monitorKeyOf(&solo)
+	started := s.markRunning(key)
+	if !s.Tasks()[0].Running {
+		t.Fatal("expected task to be running")
+	}
+	s.recordRun(key, started, &monitor.ProbeResult{Status: 0, SubStatus: storage.SubStatusServerError, HttpCode: 502}, false)
+	s.recordRun(key, s.markRunning(key), &monitor.ProbeResult{Status: 0, SubStatus: storage.SubStatusNetworkError}, true)
+
+	info := s.Tasks()[0]
+	if info.Running || info.ConsecutiveFailures != 2 || info.LastResult == nil ||
+		info.LastResult.SubStatus != storage.SubStatusNetworkError || !info.LastResult.Inherited {
+		t.Fatalf("unexpected stats after failures: %+v %+v", info, info.LastResult)
+	}
+
+	s.recordRun(key, s.markRunning(key), &monitor.ProbeResult{Status: 2, SubStatus: storage.SubStatusSlowLatency, Latency: 9000}, false)
+	if info := s.Tasks()[0]; info.ConsecutiveFailures != 0 || info.LastResult.Status != 2 {
+		t.Fatalf("expected failures reset after yellow result: %+v", info)
+	}
+
+	// 热更新保留仍在调度中的监测项统计，移除的监测项统计被清理
+	s.markRunning(monitorKeyOf(&parent))
+	s.rebuildTasks(&config.AppConfig{Monitors: []config.ServiceConfig{solo}}, false)
+	s.statsMu.Lock()
+	_, keptSolo := s.stats[key]
+	_, keptParent := s.stats[monitorKeyOf(&parent)]
+	s.statsMu.Unlock()
+	if !keptSolo || keptParent {
+		t.Fatalf("unexpected stats after rebuild: solo=%v parent=%v", keptSolo, keptParent)
+	}
+}
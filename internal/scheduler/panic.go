@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/monitor"
+	"monitor/internal/storage"
+	"monitor/internal/tracing"
+)
+
+// recoverProbe 捕获单次探测中的 panic（需直接 defer 调用）
+// 只影响出错的监测项：记录堆栈与 probe_panic 状态并计数，并发槽与在途计数由探测 goroutine 的其余 defer 照常释放，
+// 其他监测项与后续巡检周期不受影响。本周期尚未保存结果时写入一条红色 probe_panic 记录，
+// 使其出现在状态历史中并计为不可用；saved 同步更新，供依赖感知探测的子通道沿用
+func (s *Scheduler) recoverProbe(ctx context.Context, m *config.ServiceConfig, span trace.Span, saved **monitor.ProbeResult) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	now := time.Now()
+	s.panics.Add(1)
+	s.recordPanic(monitorKeyOf(m), now)
+	tracing.RecordError(span, fmt.Errorf("probe panic: %v", r))
+	logger.Error("scheduler", "探测发生 panic，已隔离该监测项，其他监测项继续运行",
+		"provider", m.Provider, "service", m.Service, "channel", m.Channel, "model", m.Model,
+		"panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+
+	// 保存结果之后才 panic（如事件检测）时记录已写入，不再重复写入
+	if *saved != nil {
+		return
+	}
+	result := panicResult(m, now)
+	if err := s.savePanicResult(ctx, result); err != nil {
+		tracing.RecordError(span, err)
+		logger.Error("scheduler", "保存 probe_panic 记录失败",
+			"provider", m.Provider, "service", m.Service, "channel", m.Channel, "model", m.Model, "error", err)
+		return
+	}
+	*saved = result
+}
+
+// panicResult 构造探测 panic 对应的红色结果（未得到有效响应，不含延迟与连接阶段耗时）
+func panicResult(m *config.ServiceConfig, now time.Time) *monitor.ProbeResult {
+	return &monitor.ProbeResult{
+		Namespace: m.Namespace,
+		Provider:  m.Provider,
+		Service:   m.Service,
+		Channel:   m.Channel,
+		Model:     m.Model,
+		Status:    0,
+		SubStatus: storage.SubStatusProbePanic,
+		Timestamp: now.Unix(),
+		Timings:   monitor.PhaseTimings{DNSMs: -1, ConnectMs: -1, TLSMs: -1, TTFBMs: -1},
+	}
+}
+
+// savePanicResult 经正常的保存路径写入 probe_panic 记录
+// 保存路径本身 panic 时（如存储层缺陷）同样隔离，转为错误返回
+func (s *Scheduler) savePanicResult(ctx context.Context, result *monitor.ProbeResult) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("保存时发生 panic: %v", r)
+		}
+	}()
+	_, err = s.prober.SaveResult(ctx, result)
+	return err
+}
+
+// recordPanic 记录监测项探测 panic（任务最近结果标记为红色 probe_panic）
+func (s *Scheduler) recordPanic(key storage.MonitorKey, now time.Time) {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	st := s.statsLocked(key)
+	if st.running {
+		st.running = false
+		st.lastDuration = now.Sub(st.lastRun)
+	}
+	st.lastResult = &TaskResult{Status: 0, SubStatus: storage.SubStatusProbePanic}
+	st.panics++
+}
+
+// Panics 返回启动以来探测 panic 的总次数
+func (s *Scheduler) Panics() int64 {
+	return s.panics.Load()
+}
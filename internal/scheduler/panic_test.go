@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/monitor"
+	"monitor/internal/storage"
+)
+
+// panickingProber 对 provider 为 bad 的监测项在 Probe 中 panic，其余返回绿色；保存走真实的 monitor.Prober
+type panickingProber struct {
+	*monitor.Prober
+	panics []func()
+}
+
+func (p *panickingProber) Probe(ctx context.Context, cfg *config.ServiceConfig) *monitor.ProbeResult {
+	if cfg.Provider == "bad" {
+		next := p.panics[0]
+		p.panics = p.panics[1:]
+		next()
+	}
+	return &monitor.ProbeResult{
+		Provider: cfg.Provider, Service: cfg.Service, Channel: cfg.Channel, Model: cfg.Model,
+		Status: 1, Latency: 100, Timestamp: time.Now().Unix(), Attempts: 1,
+		Timings: monitor.PhaseTimings{DNSMs: -1, ConnectMs: -1, TLSMs: -1, TTFBMs: -1},
+	}
+}
+
+func TestRunTaskIsolatesProbePanic(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "monitor.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer store.Close()
+	if err := store.Init(); err != nil {
+		t.Fatalf("init sqlite: %v", err)
+	}
+
+	prober := &panickingProber{Prober: monitor.NewProber(store), panics: []func(){
+		func() {
+			var resp map[string]string
+			resp["body"] = "x" // nil map 写入触发 panic
+		},
+		func() { panic("boom") },
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &Scheduler{prober: prober, ctx: ctx, probeCtx: ctx, sem: make(chan struct{}, 1)}
+
+	bad := config.ServiceConfig{Provider: "bad", Service: "cc"}
+	good := config.ServiceConfig{Provider: "good", Service: "cc"}
+	run := func(m config.ServiceConfig) {
+		s.runTask(&task{monitor: m, interval: time.Minute, nextRun: time.Now()})
+		s.wg.Wait()
+	}
+	run(bad)
+	run(good)
+	run(bad)
+
+	if s.Panics() != 2 || s.inflight.Load() != 0 || len(s.sem) != 0 {
+		t.Fatalf("unexpected state: panics=%d inflight=%d sem=%d", s.Panics(), s.inflight.Load(), len(s.sem))
+	}
+
+	s.statsMu.Lock()
+	badStats := *s.stats[monitorKeyOf(&bad)]
+	goodStats := *s.stats[monitorKeyOf(&good)]
+	s.statsMu.Unlock()
+
+	if badStats.running || badStats.panics != 2 || badStats.lastResult == nil ||
+		badStats.lastResult.Status != 0 || badStats.lastResult.SubStatus != storage.SubStatusProbePanic {
+		t.Fatalf("unexpected panicking monitor stats: %+v %+v", badStats, badStats.lastResult)
+	}
+	if badStats.lastDuration < 0 || badStats.lastDuration > time.Second {
+		t.Fatalf("unexpected panic duration: %v", badStats.lastDuration)
+	}
+	if goodStats.panics != 0 || goodStats.lastResult == nil || goodStats.lastResult.Status != 1 {
+		t.Fatalf("healthy monitor affected: %+v", goodStats)
+	}
+
+	// panic 周期经正常保存路径写入红色 probe_panic 记录，出现在状态历史中
+	since := time.Now().Add(-time.Hour)
+	badHistory, err := store.GetHistory("bad", "cc", "", "", since)
+	if err != nil {
+		t.Fatalf("get history: %v", err)
+	}
+	if len(badHistory) != 2 {
+		t.Fatalf("expected 2 probe_panic records, got %d", len(badHistory))
+	}
+	for _, rec := range badHistory {
+		if rec.Status != 0 || rec.SubStatus != storage.SubStatusProbePanic || rec.HttpCode != 0 || rec.Latency != 0 || rec.DNSMs != nil {
+			t.Fatalf("unexpected probe_panic record: %+v", rec)
+		}
+	}
+	goodHistory, err := store.GetHistory("good", "cc", "", "", since)
+	if err != nil {
+		t.Fatalf("get history: %v", err)
+	}
+	if len(goodHistory) != 1 || goodHistory[0].Status != 1 {
+		t.Fatalf("unexpected healthy monitor history: %+v", goodHistory)
+	}
+}
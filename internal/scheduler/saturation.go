@@ -0,0 +1,162 @@
+package scheduler
+
+import (
+	"time"
+
+	"monitor/internal/events"
+	"monitor/internal/logger"
+)
+
+// 调度饱和检测
+// max_concurrency 低于实际所需时，到期任务在信号量上排队，探测开始时间持续晚于计划、巡检间隔漂移。
+// 以最近 N 次探测开始为滑动窗口统计延迟占比，超过阈值判定饱和并发出 SCHEDULER_SATURATED 事件；
+// 恢复时仅记录日志，再次饱和时重新发出事件
+const (
+	saturationWindow      = 20              // 滑动窗口：最近 20 次探测开始
+	saturationEnterLate   = 10              // 窗口内延迟次数达到 10（50%）判定饱和
+	saturationExitLate    = 2               // 饱和后窗口内延迟次数降到 2（10%）及以下判定恢复
+	minLateStartThreshold = 5 * time.Second // 延迟判定下限
+)
+
+// lateStartThreshold 开始时间晚于计划超过该值视为延迟：巡检间隔的 10%，不低于 5s
+func lateStartThreshold(interval time.Duration) time.Duration {
+	return max(interval/10, minLateStartThreshold)
+}
+
+// saturationChange 饱和状态变化
+type saturationChange int
+
+const (
+	saturationUnchanged saturationChange = iota
+	saturationEntered
+	saturationRecovered
+)
+
+// saturationState 调度饱和检测的滑动窗口
+type saturationState struct {
+	delays    [saturationWindow]time.Duration
+	late      [saturationWindow]bool
+	next      int // 下一个写入位置
+	count     int // 已填充样本数
+	lateCount int // 窗口内延迟次数
+	saturated bool
+	since     time.Time // 进入饱和的时间
+}
+
+// SaturationInfo 调度饱和状态快照
+type SaturationInfo struct {
+	Saturated      bool
+	Since          time.Time     // 进入饱和的时间（未饱和时为零值）
+	Samples        int           // 窗口内样本数（最多 20）
+	LateStarts     int           // 窗口内延迟开始次数
+	AvgStartDelay  time.Duration // 窗口内平均开始延迟
+	MaxStartDelay  time.Duration // 窗口内最大开始延迟
+	MaxConcurrency int           // 当前并发上限
+	Inflight       int           // 在途探测数
+}
+
+// observeStart 记录一次探测开始并返回饱和状态变化
+func (s *Scheduler) observeStart(delay time.Duration, late bool) saturationChange {
+	s.satMu.Lock()
+	defer s.satMu.Unlock()
+
+	w := &s.saturation
+	if w.count == saturationWindow {
+		if w.late[w.next] {
+			w.lateCount--
+		}
+	} else {
+		w.count++
+	}
+	w.delays[w.next] = delay
+	w.late[w.next] = late
+	if late {
+		w.lateCount++
+	}
+	w.next = (w.next + 1) % saturationWindow
+
+	// 窗口填满前不做判定，避免启动或重建后少量样本误判
+	if w.count < saturationWindow {
+		return saturationUnchanged
+	}
+	switch {
+	case !w.saturated && w.lateCount >= saturationEnterLate:
+		w.saturated = true
+		w.since = time.Now()
+		return saturationEntered
+	case w.saturated && w.lateCount <= saturationExitLate:
+		w.saturated = false
+		w.since = time.Time{}
+		return saturationRecovered
+	}
+	return saturationUnchanged
+}
+
+// resetSaturationWindow 清空滑动窗口（重建任务堆后并发上限可能变化），保留饱和状态
+func (s *Scheduler) resetSaturationWindow() {
+	s.satMu.Lock()
+	defer s.satMu.Unlock()
+	saturated, since := s.saturation.saturated, s.saturation.since
+	s.saturation = saturationState{saturated: saturated, since: since}
+}
+
+// Saturation 返回调度饱和状态快照
+func (s *Scheduler) Saturation() SaturationInfo {
+	s.mu.Lock()
+	maxConcurrency := cap(s.sem)
+	s.mu.Unlock()
+
+	s.satMu.Lock()
+	defer s.satMu.Unlock()
+
+	w := &s.saturation
+	info := SaturationInfo{
+		Saturated:      w.saturated,
+		Since:          w.since,
+		Samples:        w.count,
+		LateStarts:     w.lateCount,
+		MaxConcurrency: maxConcurrency,
+		Inflight:       int(s.inflight.Load()),
+	}
+	var total time.Duration
+	for i := 0; i < w.count; i++ {
+		total += w.delays[i]
+		info.MaxStartDelay = max(info.MaxStartDelay, w.delays[i])
+	}
+	if w.count > 0 {
+		info.AvgStartDelay = total / time.Duration(w.count)
+	}
+	return info
+}
+
+// reportSaturation 记录饱和状态变化：进入饱和时告警并发出 SCHEDULER_SATURATED 事件，恢复时记录日志
+func (s *Scheduler) reportSaturation(change saturationChange, eventSvc *events.Service) {
+	info := s.Saturation()
+	fields := []any{
+		"late_starts", info.LateStarts, "window", info.Samples,
+		"avg_start_delay", info.AvgStartDelay.Round(time.Millisecond),
+		"max_start_delay", info.MaxStartDelay.Round(time.Millisecond),
+		"max_concurrency", info.MaxConcurrency,
+	}
+
+	if change == saturationRecovered {
+		logger.Info("scheduler", "调度饱和已恢复", fields...)
+		return
+	}
+
+	logger.Warn("scheduler", "调度饱和：探测持续晚于计划时间开始，请调大 max_concurrency 或巡检间隔", fields...)
+	if eventSvc == nil || !eventSvc.IsEnabled() {
+		return
+	}
+	meta := map[string]any{
+		"late_starts":        info.LateStarts,
+		"window":             info.Samples,
+		"avg_start_delay_ms": info.AvgStartDelay.Milliseconds(),
+		"max_start_delay_ms": info.MaxStartDelay.Milliseconds(),
+		"max_concurrency":    info.MaxConcurrency,
+		"inflight":           info.Inflight,
+	}
+	if _, err := eventSvc.EmitSchedulerSaturated(meta); err != nil {
+		logger.Error("scheduler", "保存调度饱和事件失败", "error", err)
+	}
+}
@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+)
+
+func TestSaturationDetection(t *testing.T) {
+	s := &Scheduler{sem: make(chan struct{}, 4)}
+
+	// 窗口填满前不判定
+	for i := 0; i < saturationWindow-1; i++ {
+		if change := s.observeStart(time.Minute, true); change != saturationUnchanged {
+			t.Fatalf("unexpected change before window filled: %v", change)
+		}
+	}
+	if change := s.observeStart(time.Minute, true); change != saturationEntered {
+		t.Fatalf("expected saturation entered, got %v", change)
+	}
+	info := s.Saturation()
+	if !info.Saturated || info.Since.IsZero() || info.LateStarts != saturationWindow ||
+		info.AvgStartDelay != time.Minute || info.MaxConcurrency != 4 {
+		t.Fatalf("unexpected saturation info: %+v", info)
+	}
+
+	// 滞回：延迟占比降到 50% 以下但高于恢复阈值时仍保持饱和
+	for i := 0; i < saturationWindow-saturationEnterLate+1; i++ {
+		if change := s.observeStart(0, false); change != saturationUnchanged {
+			t.Fatalf("unexpected change at sample %d: %v", i, change)
+		}
+	}
+
+	// 重建任务堆清空窗口但保留饱和状态
+	s.resetSaturationWindow()
+	if info := s.Saturation(); !info.Saturated || info.Samples != 0 {
+		t.Fatalf("expected saturated state kept after reset: %+v", info)
+	}
+	var recovered bool
+	for i := 0; i < saturationWindow; i++ {
+		recovered = s.observeStart(0, false) == saturationRecovered
+	}
+	if !recovered || s.Saturation().Saturated {
+		t.Fatalf("expected recovery after on-time window: %+v", s.Saturation())
+	}
+}
+
+func TestQueueStats(t *testing.T) {
+	s := &Scheduler{}
+	m := config.ServiceConfig{Provider: "a", Service: "cc"}
+	key := monitorKeyOf(&m)
+
+	if late := s.recordStart(key, time.Minute, 2*time.Second, 3*time.Second); late {
+		t.Fatal("3s delay should be below the 5s minimum threshold")
+	}
+	s.recordRun(key, s.markRunning(key), nil, false)
+	if late := s.recordStart(key, 5*time.Minute, 4*time.Second, 40*time.Second); !late {
+		t.Fatal("40s delay should exceed 10% of a 5m interval")
+	}
+	// 开始延迟 + 耗时超过巡检间隔计为周期超时
+	s.recordRun(key, s.markRunning(key).Add(-5*time.Minute), nil, false)
+
+	s.statsMu.Lock()
+	st := *s.stats[key]
+	s.statsMu.Unlock()
+	if st.starts != 2 || st.maxQueueWait != 4*time.Second || st.totalQueueWait != 6*time.Second ||
+		st.lateStarts != 1 || st.overruns != 1 {
+		t.Fatalf("unexpected stats: %+v", st)
+	}
+}
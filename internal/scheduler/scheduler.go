@@ -8,13 +8,18 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"monitor/internal/config"
+	"monitor/internal/eventbus"
 	"monitor/internal/events"
 	"monitor/internal/logger"
 	"monitor/internal/monitor"
 	"monitor/internal/storage"
+	"monitor/internal/tracing"
 )
 
 // task 表示一个待调度的探测任务
@@ -23,6 +28,8 @@ type task struct {
 	interval time.Duration        // 该任务的巡检间隔
 	nextRun  time.Time            // 下次执行时间
 	index    int                  // 在堆中的索引（heap.Interface 需要）
+
+	staggerOffset time.Duration // 重建任务堆时的首次执行延迟（组间错峰 + 组内间隔）
 }
 
 // monitorGroup 表示一个多模型监测组
@@ -61,11 +68,19 @@ func (h *taskHeap) Pop() any {
 	return item
 }
 
+// probeRunner 执行探测并保存结果（由 *monitor.Prober 实现）
+type probeRunner interface {
+	Probe(ctx context.Context, cfg *config.ServiceConfig) *monitor.ProbeResult
+	SaveResult(ctx context.Context, result *monitor.ProbeResult) (*storage.ProbeRecord, error)
+	Close()
+}
+
 // Scheduler 调度器（最小堆调度架构）
 // 支持每个监测项独立的巡检间隔
 type Scheduler struct {
-	prober       *monitor.Prober
-	eventService *events.Service // 事件服务（可选）
+	prober       probeRunner
+	eventService *events.Service     // 事件服务（可选）
+	eventBus     *eventbus.Publisher // 事件总线发布器（可选）
 
 	mu      sync.Mutex
 	running bool
@@ -77,16 +92,40 @@ type Scheduler struct {
 	cancel  context.CancelFunc
 	wg      sync.WaitGroup // 追踪在途探测 goroutine
 
+	// 在途探测使用独立的 context：关闭时不随 ctx 立即取消，排空超时后才取消
+	probeCtx    context.Context
+	probeCancel context.CancelFunc
+	inflight    atomic.Int64  // 在途探测数（用于关闭日志）
+	panics      atomic.Int64  // 启动以来探测 panic 的次数
+	stopped     chan struct{} // Stop 完成后关闭，并发调用 Stop 时等待同一次排空
+
 	// 配置引用（支持热更新）
 	cfg      *config.AppConfig
 	cfgMu    sync.RWMutex
 	fallback time.Duration // 默认巡检间隔（创建时传入）
+
+	// 依赖感知探测：父通道最近一次探测（按 provider/service/channel 索引）
+	parentMu sync.Mutex
+	parents  map[string]*parentOutcome
+
+	// 各监测项运行统计（/api/admin/scheduler/tasks）
+	statsMu sync.Mutex
+	stats   map[storage.MonitorKey]*taskStats
+
+	// 调度饱和检测（最近 N 次探测开始的延迟窗口）
+	satMu      sync.Mutex
+	saturation saturationState
+
+	// 服务商每日探测预算（启动时从存储恢复当日用量）
+	store   storage.Storage
+	budgets budgetTracker
 }
 
 // NewScheduler 创建调度器
 func NewScheduler(store storage.Storage, interval time.Duration) *Scheduler {
 	return &Scheduler{
 		prober:   monitor.NewProber(store),
+		store:    store,
 		fallback: interval,
 		wakeCh:   make(chan struct{}, 1),
 	}
@@ -100,6 +139,14 @@ func (s *Scheduler) SetEventService(svc *events.Service) {
 	s.eventService = svc
 }
 
+// SetEventBus 设置事件总线发布器
+// 用于将保存成功的探测记录镜像发布到 Kafka / NATS
+func (s *Scheduler) SetEventBus(p *eventbus.Publisher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.eventBus = p
+}
+
 // Start 启动调度器
 func (s *Scheduler) Start(ctx context.Context, cfg *config.AppConfig) {
 	s.mu.Lock()
@@ -109,8 +156,13 @@ func (s *Scheduler) Start(ctx context.Context, cfg *config.AppConfig) {
 	}
 	s.running = true
 	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.probeCtx, s.probeCancel = context.WithCancel(context.WithoutCancel(ctx))
+	s.stopped = make(chan struct{})
 	s.mu.Unlock()
 
+	// 恢复当日已消耗的服务商预算，避免重启后重复消耗
+	s.restoreBudgets(s.store, cfg, time.Now())
+
 	// 保存初始配置并初始化任务堆（启动时错峰）
 	s.rebuildTasks(cfg, true)
 
@@ -123,9 +175,10 @@ func (s *Scheduler) Start(ctx context.Context, cfg *config.AppConfig) {
 // UpdateConfig 更新配置（热更新时调用）
 func (s *Scheduler) UpdateConfig(cfg *config.AppConfig) {
 	// 先更新事件服务的活跃模型索引（在任务重建之前）
-	// 确保新任务执行时能读到最新的活跃模型列表
-	if s.eventService != nil && s.eventService.IsEnabled() {
+	// 确保新任务执行时能读到最新的活跃模型列表；事件服务未启用时同样更新，热更新开启后即可使用
+	if s.eventService != nil {
 		s.eventService.UpdateActiveModels(cfg.Monitors, cfg.Boards.Enabled)
+		s.eventService.UpdateBaselines(cfg.Monitors)
 	}
 
 	// 再重建任务堆（会唤醒调度循环，新任务可能立即执行）
@@ -156,10 +209,17 @@ func (s *Scheduler) TriggerNow() {
 }
 
 // Stop 停止调度器
+// 先停止派发新任务，再在 shutdown_drain_timeout 内等待在途探测完成（含结果写入与事件检测），
+// 超时后取消剩余探测。返回时所有探测 goroutine 均已退出
 func (s *Scheduler) Stop() {
 	s.mu.Lock()
 	if !s.running {
+		// 其他 goroutine（如 loop 感知 ctx 取消）已在执行 Stop，等待其排空完成
+		stopped := s.stopped
 		s.mu.Unlock()
+		if stopped != nil {
+			<-stopped
+		}
 		return
 	}
 
@@ -180,15 +240,59 @@ func (s *Scheduler) Stop() {
 	}
 	// 唤醒 loop 以便退出
 	s.notifyWakeLocked()
+	probeCancel := s.probeCancel
+	stopped := s.stopped
 	s.mu.Unlock()
 
 	// 等待在途探测 goroutine 完成
-	s.wg.Wait()
+	s.drain(probeCancel)
 
 	s.prober.Close()
+	close(stopped)
 	logger.Info("scheduler", "调度器已停止")
 }
 
+// drain 等待在途探测完成，超过 shutdown_drain_timeout 后取消剩余探测并等待其退出
+func (s *Scheduler) drain(cancelProbes context.CancelFunc) {
+	defer cancelProbes()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return
+	default:
+	}
+
+	timeout := s.drainTimeout()
+	logger.Info("scheduler", "等待在途探测完成", "inflight", s.inflight.Load(), "timeout", timeout)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		logger.Info("scheduler", "在途探测已全部完成")
+	case <-timer.C:
+		logger.Warn("scheduler", "等待在途探测超时，取消剩余探测", "inflight", s.inflight.Load(), "timeout", timeout)
+		cancelProbes()
+		<-done
+	}
+}
+
+// drainTimeout 返回当前配置的关闭等待时间
+func (s *Scheduler) drainTimeout() time.Duration {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	if s.cfg == nil {
+		return 0
+	}
+	return s.cfg.ShutdownDrainTimeoutDuration
+}
+
 // rebuildTasks 根据配置重建调度任务堆
 // startup=true 时使用启动模式错峰（固定 2 秒间隔）
 func (s *Scheduler) rebuildTasks(cfg *config.AppConfig, startup bool) {
@@ -207,6 +311,7 @@ func (s *Scheduler) rebuildTasks(cfg *config.AppConfig, startup bool) {
 	monitorCount := len(cfg.Monitors)
 	if monitorCount == 0 {
 		s.tasks = s.tasks[:0]
+		s.pruneStats(nil)
 		s.resetTimerLocked()
 		s.notifyWakeLocked() // 唤醒 loop 以便重新检查状态
 		return
@@ -230,6 +335,7 @@ func (s *Scheduler) rebuildTasks(cfg *config.AppConfig, startup bool) {
 	// 如果所有监测项都被禁用或冷板，清空任务
 	if activeCount == 0 {
 		s.tasks = s.tasks[:0]
+		s.pruneStats(nil)
 		s.resetTimerLocked()
 		s.notifyWakeLocked()
 		logger.Info("scheduler", "所有监测项已禁用/冷板，调度器无任务",
@@ -246,6 +352,7 @@ func (s *Scheduler) rebuildTasks(cfg *config.AppConfig, startup bool) {
 		maxConcurrency = 1
 	}
 	s.sem = make(chan struct{}, maxConcurrency)
+	s.resetSaturationWindow()
 	logger.Info("scheduler", "并发控制已更新",
 		"max_concurrency", maxConcurrency, "total", monitorCount,
 		"disabled", disabledCount, "active", activeCount)
@@ -310,6 +417,7 @@ func (s *Scheduler) rebuildTasks(cfg *config.AppConfig, startup bool) {
 	s.tasks = s.tasks[:0]
 	heap.Init(&s.tasks)
 	now := time.Now()
+	active := make(map[storage.MonitorKey]bool, activeCount)
 
 	// 按组遍历，实现组间错峰、组内紧凑
 	for groupIdx, group := range groups {
@@ -339,12 +447,15 @@ func (s *Scheduler) rebuildTasks(cfg *config.AppConfig, startup bool) {
 			}
 
 			heap.Push(&s.tasks, &task{
-				monitor:  m,
-				interval: interval,
-				nextRun:  nextRun,
+				monitor:       m,
+				interval:      interval,
+				nextRun:       nextRun,
+				staggerOffset: nextRun.Sub(now),
 			})
+			active[monitorKeyOf(&m)] = true
 		}
 	}
+	s.pruneStats(active)
 
 	s.resetTimerLocked()
 	s.notifyWakeLocked()
@@ -542,40 +653,108 @@ func (s *Scheduler) dispatchDue() {
 func (s *Scheduler) runTask(t *task) {
 	s.mu.Lock()
 	ctx := s.ctx
+	probeCtx := s.probeCtx
 	sem := s.sem
 	eventSvc := s.eventService
+	bus := s.eventBus
 	s.mu.Unlock()
 
 	if ctx == nil || sem == nil {
 		return
 	}
 
-	// 获取信号量
+	// 服务商当日预算耗尽：暂停或降频（跳过的周期不发起请求，也不写入探测记录）
+	if now := time.Now(); !s.budgetAllows(&t.monitor, now) {
+		s.recordBudgetSkip(&t.monitor, now)
+		return
+	}
+
+	s.cfgMu.RLock()
+	dependencyAware := s.cfg != nil && s.cfg.DependencyAwareProbing
+	s.cfgMu.RUnlock()
+
+	// 在派发顺序中登记父通道探测，确保同组随后派发的子通道能找到本周期的父通道结果
+	var outcome *parentOutcome
+	if dependencyAware {
+		outcome = s.beginParentProbe(&t.monitor)
+	}
+
+	// 获取信号量（并发槽不足时在此排队，记录排队时间与相对计划时间的开始延迟）
+	planned := t.nextRun
+	queued := time.Now()
 	select {
 	case sem <- struct{}{}:
 	case <-ctx.Done():
+		finishParentProbe(outcome, nil)
 		return
 	}
+	acquired := time.Now()
+	startDelay := max(acquired.Sub(planned), 0)
+	late := s.recordStart(monitorKeyOf(&t.monitor), t.interval, acquired.Sub(queued), startDelay)
+	if change := s.observeStart(startDelay, late); change != saturationUnchanged {
+		s.reportSaturation(change, eventSvc)
+	}
 
 	// 追踪在途 goroutine
 	s.wg.Add(1)
+	s.inflight.Add(1)
 
 	// 异步执行，释放信号量
-	go func(m config.ServiceConfig) {
+	// 探测使用 probeCtx：调度器停止后在途探测可继续完成，仅在排空超时后被取消
+	go func(m config.ServiceConfig, interval time.Duration) {
 		defer s.wg.Done()
+		defer s.inflight.Add(-1)
 		defer func() { <-sem }()
 
-		result := s.prober.Probe(ctx, &m)
-		record, err := s.prober.SaveResult(result)
+		var saved *monitor.ProbeResult
+		defer func() { finishParentProbe(outcome, saved) }()
+
+		// 每次探测一条追踪链路：probe → prober.Probe → storage.SaveRecord → events.ProcessRecord
+		probeCtx, span := tracing.Start(probeCtx, "probe",
+			attribute.String("provider", m.Provider),
+			attribute.String("service", m.Service),
+			attribute.String("channel", m.Channel),
+			attribute.String("model", m.Model),
+		)
+		defer span.End()
+		// 单个监测项的 panic 不会终止进程或影响其他监测项（需最先执行，确保其余 defer 照常释放资源）
+		defer s.recoverProbe(probeCtx, &m, span, &saved)
+
+		key := monitorKeyOf(&m)
+		started := s.markRunning(key)
+
+		// 依赖感知探测：父通道本周期已因网络/服务端错误失败时沿用其状态，节省 API 配额
+		var result *monitor.ProbeResult
+		if dependencyAware {
+			result = s.inheritedResult(probeCtx, &m, interval)
+		}
+		inherited := result != nil
+		if result == nil {
+			result = s.prober.Probe(probeCtx, &m)
+			s.chargeBudget(&m, result, time.Now())
+		}
+		s.recordRun(key, started, result, inherited)
+
+		// 排空超时被取消的探测：红色结果可能只是取消导致的，丢弃以免关闭过程产生虚假故障
+		if probeCtx.Err() != nil && result.Status == 0 {
+			logger.Warn("scheduler", "探测在关闭时被取消，丢弃结果",
+				"provider", m.Provider, "service", m.Service, "channel", m.Channel, "model", m.Model)
+			return
+		}
+
+		record, err := s.prober.SaveResult(probeCtx, result)
 		if err != nil {
+			tracing.RecordError(span, err)
 			logger.Error("scheduler", "保存结果失败",
 				"provider", m.Provider, "service", m.Service, "channel", m.Channel, "model", m.Model, "error", err)
 			return
 		}
+		saved = result
+		bus.PublishRecord(record)
 
 		// 事件检测（如果启用）
 		if eventSvc != nil && eventSvc.IsEnabled() {
-			if event, err := eventSvc.ProcessRecord(record); err != nil {
+			if event, err := eventSvc.ProcessRecordContext(probeCtx, record); err != nil {
 				logger.Error("scheduler", "事件检测失败",
 					"provider", m.Provider, "service", m.Service, "channel", m.Channel, "model", m.Model, "error", err)
 			} else if event != nil {
@@ -584,7 +763,7 @@ func (s *Scheduler) runTask(t *task) {
 					"event_type", event.EventType, "from", event.FromStatus, "to", event.ToStatus)
 			}
 		}
-	}(t.monitor)
+	}(t.monitor, t.interval)
 }
 
 // resetTimerLocked 重置定时器到下一个任务（需持有 s.mu）
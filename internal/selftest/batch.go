@@ -0,0 +1,178 @@
+package selftest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"monitor/internal/logger"
+)
+
+// defaultBatchMaxTargets 单个批量任务默认允许的最大目标数
+const defaultBatchMaxTargets = 5
+
+// BatchTarget 批量测试中的单个目标
+type BatchTarget struct {
+	TestType string
+	APIURL   string
+	APIKey   string
+}
+
+// BatchJob 批量测试任务：一次提交多个目标，每个目标展开为独立的 TestJob
+// 子任务与普通任务共用队列和并发限制，结果按提交顺序聚合
+type BatchJob struct {
+	ID        string    `json:"id"`
+	JobIDs    []string  `json:"job_ids"` // 子任务 ID（与提交顺序一致）
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BatchSummary 批量任务聚合统计
+type BatchSummary struct {
+	Status     string `json:"status"` // queued/running/completed
+	Total      int    `json:"total"`
+	Queued     int    `json:"queued"`
+	Running    int    `json:"running"`
+	Success    int    `json:"success"`
+	Failed     int    `json:"failed"`      // 含 failed/timeout/canceled
+	AvgLatency int    `json:"avg_latency"` // 成功目标的平均延迟（毫秒），无成功目标时为 0
+}
+
+// CreateBatch 创建批量测试任务
+// 所有目标先整体校验（任一不合法则整体拒绝），再一次性入队，避免部分入队
+func (m *TestJobManager) CreateBatch(targets []BatchTarget) (*BatchJob, []*TestJob, error) {
+	if len(targets) == 0 {
+		return nil, nil, &Error{
+			Code:    ErrCodeBadRequest,
+			Message: "至少需要一个测试目标",
+			Err:     fmt.Errorf("empty batch"),
+		}
+	}
+	if batchMax := m.BatchMaxTargets(); len(targets) > batchMax {
+		return nil, nil, &Error{
+			Code:    ErrCodeBatchTooLarge,
+			Message: fmt.Sprintf("单次最多测试 %d 个目标", batchMax),
+			Err:     fmt.Errorf("batch too large: %d > %d", len(targets), batchMax),
+		}
+	}
+
+	for i, t := range targets {
+		if err := m.validateTarget(t.TestType, t.APIURL); err != nil {
+			var stErr *Error
+			if !errors.As(err, &stErr) {
+				return nil, nil, err
+			}
+			return nil, nil, &Error{
+				Code:    stErr.Code,
+				Message: fmt.Sprintf("第 %d 个目标：%s", i+1, stErr.Message),
+				Err:     stErr.Err,
+			}
+		}
+	}
+
+	m.mu.Lock()
+	if len(m.queue)+len(targets) > m.maxQueueSize {
+		m.mu.Unlock()
+		return nil, nil, &Error{
+			Code:    ErrCodeQueueFull,
+			Message: "队列已满，请稍后再试",
+			Err:     fmt.Errorf("queue is full (max: %d, need: %d)", m.maxQueueSize, len(targets)),
+		}
+	}
+
+	batch := &BatchJob{
+		ID:        uuid.New().String(),
+		JobIDs:    make([]string, 0, len(targets)),
+		CreatedAt: time.Now(),
+	}
+	jobs := make([]*TestJob, 0, len(targets))
+	for _, t := range targets {
+		job := m.enqueueLocked(t.TestType, t.APIURL, t.APIKey)
+		batch.JobIDs = append(batch.JobIDs, job.ID)
+		jobs = append(jobs, job)
+	}
+	m.batches[batch.ID] = batch
+	m.mu.Unlock()
+
+	logger.Info("selftest", "Batch created and queued",
+		"batch_id", batch.ID, "targets", len(targets))
+
+	// 每次 scheduleNext 最多调度一个任务，逐个尝试直到占满并发
+	for range jobs {
+		m.scheduleNext()
+	}
+
+	snapshots := make([]*TestJob, len(jobs))
+	for i, job := range jobs {
+		snapshots[i] = job.Snapshot()
+	}
+	return batch, snapshots, nil
+}
+
+// GetBatch 获取批量任务及其子任务快照（按提交顺序）
+// 已被清理的子任务不返回
+func (m *TestJobManager) GetBatch(id string) (*BatchJob, []*TestJob, error) {
+	m.mu.RLock()
+	batch, ok := m.batches[id]
+	if !ok {
+		m.mu.RUnlock()
+		return nil, nil, &Error{
+			Code:    ErrCodeBatchNotFound,
+			Message: "批量任务不存在或已过期",
+			Err:     fmt.Errorf("batch not found: %s", id),
+		}
+	}
+	jobs := make([]*TestJob, 0, len(batch.JobIDs))
+	for _, jobID := range batch.JobIDs {
+		if job, ok := m.jobs[jobID]; ok {
+			jobs = append(jobs, job)
+		}
+	}
+	m.mu.RUnlock()
+
+	snapshots := make([]*TestJob, len(jobs))
+	for i, job := range jobs {
+		snapshots[i] = job.Snapshot()
+	}
+	return batch, snapshots, nil
+}
+
+// BatchMaxTargets 返回单个批量任务允许的最大目标数
+func (m *TestJobManager) BatchMaxTargets() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.batchMax
+}
+
+// SummarizeBatch 聚合子任务状态与延迟
+func SummarizeBatch(jobs []*TestJob) BatchSummary {
+	summary := BatchSummary{Total: len(jobs)}
+	var latencySum int
+	for _, job := range jobs {
+		switch job.Status {
+		case StatusQueued:
+			summary.Queued++
+		case StatusRunning:
+			summary.Running++
+		case StatusSuccess:
+			summary.Success++
+			latencySum += job.Latency
+		default:
+			summary.Failed++
+		}
+	}
+	if summary.Success > 0 {
+		summary.AvgLatency = latencySum / summary.Success
+	}
+
+	switch {
+	case summary.Queued+summary.Running == 0:
+		summary.Status = "completed"
+	case summary.Queued == summary.Total:
+		summary.Status = string(StatusQueued)
+	default:
+		summary.Status = string(StatusRunning)
+	}
+	return summary
+}
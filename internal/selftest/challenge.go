@@ -0,0 +1,245 @@
+package selftest
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"monitor/internal/config"
+)
+
+// CAPTCHA 服务端校验地址
+const (
+	turnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+	hcaptchaVerifyURL  = "https://api.hcaptcha.com/siteverify"
+)
+
+// Challenge 下发给客户端的人机验证参数（GET /api/selftest/challenge）
+type Challenge struct {
+	Type string `json:"type"` // none/turnstile/hcaptcha/pow
+
+	// turnstile/hcaptcha：前端渲染组件所需的站点公钥
+	SiteKey string `json:"site_key,omitempty"`
+
+	// pow：题目与难度，客户端需找到 solution 使 sha256(challenge + ":" + solution) 具有 difficulty 个前导零比特
+	Challenge  string `json:"challenge,omitempty"`
+	Difficulty int    `json:"difficulty,omitempty"`
+	ExpiresAt  int64  `json:"expires_at,omitempty"`
+}
+
+// ChallengeVerifier 人机验证器
+type ChallengeVerifier interface {
+	// Issue 生成下发给客户端的验证参数
+	Issue() (*Challenge, error)
+
+	// Verify 校验客户端提交的验证结果
+	// challenge 为 Issue 下发的题目（CAPTCHA 模式下忽略），response 为 CAPTCHA 令牌或 PoW 解
+	Verify(ctx context.Context, challenge, response, remoteIP string) error
+}
+
+// NewChallengeVerifier 根据配置创建人机验证器；type=none 时返回 nil
+func NewChallengeVerifier(cfg config.SelfTestChallengeConfig) (ChallengeVerifier, error) {
+	switch cfg.Type {
+	case "", config.SelfTestChallengeNone:
+		return nil, nil
+	case config.SelfTestChallengeTurnstile:
+		return newCaptchaVerifier(cfg.Type, cfg.SiteKey, cfg.SecretKey, turnstileVerifyURL), nil
+	case config.SelfTestChallengeHCaptcha:
+		return newCaptchaVerifier(cfg.Type, cfg.SiteKey, cfg.SecretKey, hcaptchaVerifyURL), nil
+	case config.SelfTestChallengePoW:
+		return newPoWVerifier(cfg.PoWDifficulty, cfg.PoWTTLDuration)
+	default:
+		return nil, fmt.Errorf("unknown challenge type: %s", cfg.Type)
+	}
+}
+
+func challengeFailed(err error) error {
+	return &Error{
+		Code:    ErrCodeChallengeFailed,
+		Message: "人机验证失败，请重新验证",
+		Err:     err,
+	}
+}
+
+// ===== Turnstile / hCaptcha =====
+
+// captchaVerifier 通过第三方 siteverify 接口校验 CAPTCHA 令牌
+// Turnstile 与 hCaptcha 的校验协议一致（表单提交 secret/response/remoteip，返回 success）
+type captchaVerifier struct {
+	typ       string
+	siteKey   string
+	secretKey string
+	verifyURL string
+	client    *http.Client
+}
+
+func newCaptchaVerifier(typ, siteKey, secretKey, verifyURL string) *captchaVerifier {
+	return &captchaVerifier{
+		typ:       typ,
+		siteKey:   siteKey,
+		secretKey: secretKey,
+		verifyURL: verifyURL,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (v *captchaVerifier) Issue() (*Challenge, error) {
+	return &Challenge{Type: v.typ, SiteKey: v.siteKey}, nil
+}
+
+func (v *captchaVerifier) Verify(ctx context.Context, _, response, remoteIP string) error {
+	if strings.TrimSpace(response) == "" {
+		return &Error{
+			Code:    ErrCodeChallengeRequired,
+			Message: "请先完成人机验证",
+			Err:     fmt.Errorf("missing %s token", v.typ),
+		}
+	}
+
+	form := url.Values{}
+	form.Set("secret", v.secretKey)
+	form.Set("response", response)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("创建 %s 校验请求失败: %w", v.typ, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s 校验请求失败: %w", v.typ, err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Success    bool     `json:"success"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("解析 %s 校验响应失败: %w", v.typ, err)
+	}
+	if !result.Success {
+		return challengeFailed(fmt.Errorf("%s verification failed: %v", v.typ, result.ErrorCodes))
+	}
+	return nil
+}
+
+// ===== Proof-of-Work =====
+
+// powVerifier 无状态签发、单次使用的工作量证明
+// 题目格式："<nonce>.<expires_unix>.<signature>"，签名使用进程内随机密钥（重启后旧题目失效）
+type powVerifier struct {
+	secret     []byte
+	difficulty int
+	ttl        time.Duration
+
+	mu   sync.Mutex
+	used map[string]time.Time // 已使用的题目 -> 到期时间（防重放）
+}
+
+func newPoWVerifier(difficulty int, ttl time.Duration) (*powVerifier, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("生成 PoW 密钥失败: %w", err)
+	}
+	return &powVerifier{
+		secret:     secret,
+		difficulty: difficulty,
+		ttl:        ttl,
+		used:       make(map[string]time.Time),
+	}, nil
+}
+
+func (v *powVerifier) Issue() (*Challenge, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("生成 PoW 题目失败: %w", err)
+	}
+	n := hex.EncodeToString(nonce)
+	exp := time.Now().Add(v.ttl).Unix()
+	return &Challenge{
+		Type:       config.SelfTestChallengePoW,
+		Challenge:  fmt.Sprintf("%s.%d.%s", n, exp, v.sign(n, exp)),
+		Difficulty: v.difficulty,
+		ExpiresAt:  exp,
+	}, nil
+}
+
+func (v *powVerifier) Verify(_ context.Context, challenge, response, _ string) error {
+	if challenge == "" || response == "" {
+		return &Error{
+			Code:    ErrCodeChallengeRequired,
+			Message: "请先完成人机验证",
+			Err:     fmt.Errorf("missing pow challenge or solution"),
+		}
+	}
+
+	parts := strings.Split(challenge, ".")
+	if len(parts) != 3 {
+		return challengeFailed(fmt.Errorf("malformed challenge"))
+	}
+	exp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return challengeFailed(fmt.Errorf("invalid expiry: %w", err))
+	}
+	if !hmac.Equal([]byte(v.sign(parts[0], exp)), []byte(parts[2])) {
+		return challengeFailed(fmt.Errorf("invalid signature"))
+	}
+	now := time.Now()
+	if now.Unix() >= exp {
+		return challengeFailed(fmt.Errorf("challenge expired"))
+	}
+
+	sum := sha256.Sum256([]byte(challenge + ":" + response))
+	if leadingZeroBits(sum[:]) < v.difficulty {
+		return challengeFailed(fmt.Errorf("insufficient work"))
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for c, e := range v.used {
+		if !now.Before(e) {
+			delete(v.used, c)
+		}
+	}
+	if _, ok := v.used[challenge]; ok {
+		return challengeFailed(fmt.Errorf("challenge already used"))
+	}
+	v.used[challenge] = time.Unix(exp, 0)
+	return nil
+}
+
+func (v *powVerifier) sign(nonce string, exp int64) string {
+	h := hmac.New(sha256.New, v.secret)
+	fmt.Fprintf(h, "pow:%s:%d", nonce, exp)
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// leadingZeroBits 统计字节序列的前导零比特数
+func leadingZeroBits(b []byte) int {
+	n := 0
+	for _, x := range b {
+		if x == 0 {
+			n += 8
+			continue
+		}
+		return n + bits.LeadingZeros8(x)
+	}
+	return n
+}
@@ -20,6 +20,20 @@ const (
 	ErrCodeQueueFull ErrorCode = "queue_full"
 	// ErrCodeJobNotFound 任务不存在或已过期
 	ErrCodeJobNotFound ErrorCode = "job_not_found"
+	// ErrCodeBatchTooLarge 批量目标数超出上限
+	ErrCodeBatchTooLarge ErrorCode = "batch_too_large"
+	// ErrCodeBatchNotFound 批量任务不存在或已过期
+	ErrCodeBatchNotFound ErrorCode = "batch_not_found"
+	// ErrCodeChallengeRequired 缺少人机验证结果
+	ErrCodeChallengeRequired ErrorCode = "challenge_required"
+	// ErrCodeChallengeFailed 人机验证未通过
+	ErrCodeChallengeFailed ErrorCode = "challenge_failed"
+	// ErrCodeJobNotFinished 任务尚未完成（无法分享）
+	ErrCodeJobNotFinished ErrorCode = "job_not_finished"
+	// ErrCodeShareDisabled 结果分享未启用
+	ErrCodeShareDisabled ErrorCode = "share_disabled"
+	// ErrCodeInvalidShareToken 分享令牌无效或已过期
+	ErrCodeInvalidShareToken ErrorCode = "invalid_share_token"
 )
 
 // Error 自助测试领域错误（对外 Message + 稳定 Code；Err 用于内部诊断）
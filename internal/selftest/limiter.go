@@ -51,6 +51,12 @@ func NewIPLimiter(perMinute int, burst int) *IPLimiter {
 // Allow 检查来自给定 IP 的请求是否被允许
 // 返回 true 表示允许，false 表示超出限流
 func (l *IPLimiter) Allow(ip string) bool {
+	return l.AllowN(ip, 1)
+}
+
+// AllowN 检查来自给定 IP 的 n 个请求是否被允许（批量测试按目标数计入）
+// 仅在令牌足够时整体扣除，不足时不消耗令牌
+func (l *IPLimiter) AllowN(ip string, n int) bool {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -66,7 +72,21 @@ func (l *IPLimiter) Allow(ip string) bool {
 		entry.lastSeen = time.Now()
 	}
 
-	return entry.limiter.Allow()
+	return entry.limiter.AllowN(time.Now(), n)
+}
+
+// SetRate 更新限流速率（热更新时调用），已跟踪 IP 的令牌桶同步调整，已消耗的令牌保留
+func (l *IPLimiter) SetRate(perMinute int, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rateVal = rate.Limit(float64(perMinute) / 60.0)
+	l.burst = burst
+	now := time.Now()
+	for _, entry := range l.limiters {
+		entry.limiter.SetLimitAt(now, l.rateVal)
+		entry.limiter.SetBurstAt(now, l.burst)
+	}
 }
 
 // GetLimiter 返回指定 IP 的限流器（用于测试/调试）
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 
+	"monitor/internal/config"
 	"monitor/internal/logger"
 )
 
@@ -23,45 +25,73 @@ type TestJobManagerOption func(*TestJobManager)
 // WithSlowLatencyByService 设置按服务类型的 slow_latency 覆盖
 func WithSlowLatencyByService(m map[string]time.Duration) TestJobManagerOption {
 	return func(mgr *TestJobManager) {
-		if len(m) == 0 {
-			return
-		}
-		// 构建归一化的 lookup 函数
-		normalized := make(map[string]time.Duration, len(m))
-		for service, d := range m {
-			key := strings.ToLower(strings.TrimSpace(service))
-			if key == "" || d <= 0 {
-				continue
-			}
-			normalized[key] = d
+		if lookup := slowLatencyLookupFor(m); lookup != nil {
+			mgr.slowLatencyLookup = lookup
 		}
-		if len(normalized) == 0 {
-			return
+	}
+}
+
+// slowLatencyLookupFor 构建归一化的 slow_latency lookup 函数（没有有效覆盖时返回 nil）
+func slowLatencyLookupFor(m map[string]time.Duration) SlowLatencyLookupFunc {
+	normalized := make(map[string]time.Duration, len(m))
+	for service, d := range m {
+		key := strings.ToLower(strings.TrimSpace(service))
+		if key == "" || d <= 0 {
+			continue
 		}
-		mgr.slowLatencyLookup = func(service string) (time.Duration, bool) {
-			d, ok := normalized[service]
-			return d, ok
+		normalized[key] = d
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+	return func(service string) (time.Duration, bool) {
+		d, ok := normalized[service]
+		return d, ok
+	}
+}
+
+// WithBatchMaxTargets 设置单个批量任务允许的最大目标数
+func WithBatchMaxTargets(n int) TestJobManagerOption {
+	return func(mgr *TestJobManager) {
+		if n > 0 {
+			mgr.batchMax = n
 		}
 	}
 }
 
+// WithChallengeVerifier 设置创建任务前的人机验证器（nil 表示不校验）
+func WithChallengeVerifier(v ChallengeVerifier) TestJobManagerOption {
+	return func(mgr *TestJobManager) {
+		mgr.challenge = v
+	}
+}
+
 // TestJobManager manages the lifecycle of self-test jobs
 type TestJobManager struct {
-	mu      sync.RWMutex
-	jobs    map[string]*TestJob // id -> job
-	queue   []*TestJob          // waiting queue (FIFO)
-	running map[string]*TestJob // currently running jobs
+	mu      sync.RWMutex         // 同时保护下方的运行参数（热更新时替换）
+	jobs    map[string]*TestJob  // id -> job
+	queue   []*TestJob           // waiting queue (FIFO)
+	running map[string]*TestJob  // currently running jobs
+	batches map[string]*BatchJob // batch id -> batch
+
+	subMu       sync.Mutex                      // 保护 subscribers（锁顺序：mu → subMu → job.mu）
+	subscribers map[string][]chan ProgressEvent // job id -> 进度订阅者
 
 	maxConcurrent int           // Maximum concurrent jobs (default 10)
 	maxQueueSize  int           // Maximum queue length (default 50)
 	jobTimeout    time.Duration // Job timeout (default 30s)
 	resultTTL     time.Duration // Result retention time (2 minutes)
+	batchMax      int           // Maximum targets per batch (default 5)
 
 	prober    *SelfTestProber // 自助测试专用探测器（安全 HTTP 客户端）
 	limiter   *IPLimiter      // IP rate limiter
 	ssrfGuard *SSRFGuard      // SSRF protection
 
 	slowLatencyLookup SlowLatencyLookupFunc // 按服务类型的 slow_latency 覆盖
+	challenge         ChallengeVerifier     // 人机验证（可选）
+
+	enabled      atomic.Bool                    // 是否对外提供自助测试（selftest.enabled，支持热更新）
+	challengeCfg config.SelfTestChallengeConfig // 当前人机验证配置（热更新时仅在变更后重建验证器，避免丢失 PoW 题目状态）
 
 	stopCleanup chan struct{}  // Signal to stop cleanup goroutine
 	stopOnce    sync.Once      // Ensure Stop is called only once
@@ -81,10 +111,13 @@ func NewTestJobManager(
 		jobs:          make(map[string]*TestJob),
 		queue:         make([]*TestJob, 0),
 		running:       make(map[string]*TestJob),
+		batches:       make(map[string]*BatchJob),
+		subscribers:   make(map[string][]chan ProgressEvent),
 		maxConcurrent: maxConcurrent,
 		maxQueueSize:  maxQueueSize,
 		jobTimeout:    jobTimeout,
 		resultTTL:     resultTTL,
+		batchMax:      defaultBatchMaxTargets,
 		limiter:       NewIPLimiter(rateLimitPerMinute, rateLimitPerMinute),
 		ssrfGuard:     NewSSRFGuard(),
 		stopCleanup:   make(chan struct{}),
@@ -99,6 +132,8 @@ func NewTestJobManager(
 		}
 	}
 
+	mgr.enabled.Store(true)
+
 	// Start cleanup worker
 	mgr.wg.Add(1)
 	go mgr.cleanupWorker()
@@ -106,28 +141,92 @@ func NewTestJobManager(
 	return mgr
 }
 
+// NewTestJobManagerFromConfig 根据 selftest 配置创建管理器（配置需已规范化）
+// selftest.enabled 为 false 时同样创建管理器但不对外提供服务，便于热更新开启
+func NewTestJobManagerFromConfig(cfg config.SelfTestConfig, slowLatencyByService map[string]time.Duration) (*TestJobManager, error) {
+	challenge, err := NewChallengeVerifier(cfg.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	mgr := NewTestJobManager(
+		cfg.MaxConcurrent,
+		cfg.MaxQueueSize,
+		cfg.JobTimeoutDuration,
+		cfg.ResultTTLDuration,
+		cfg.RateLimitPerMinute,
+		WithSlowLatencyByService(slowLatencyByService),
+		WithBatchMaxTargets(cfg.BatchMaxTargets),
+		WithChallengeVerifier(challenge),
+	)
+	mgr.challengeCfg = cfg.Challenge
+	mgr.enabled.Store(cfg.Enabled)
+	return mgr, nil
+}
+
+// UpdateConfig 热更新自助测试配置（配置需已规范化）
+// 并发数、队列长度、超时、结果保留时间、IP 限流、批量上限、slow_latency 覆盖、人机验证与启用状态均立即生效：
+// 已在运行的任务沿用原超时，已排队的任务不受新队列长度影响，调大并发数后立即调度排队中的任务。
+// 人机验证器创建失败时返回错误，此时所有配置保持不变
+func (m *TestJobManager) UpdateConfig(cfg config.SelfTestConfig, slowLatencyByService map[string]time.Duration) error {
+	m.mu.RLock()
+	challenge, challengeChanged := m.challenge, cfg.Challenge != m.challengeCfg
+	m.mu.RUnlock()
+	if challengeChanged {
+		v, err := NewChallengeVerifier(cfg.Challenge)
+		if err != nil {
+			return err
+		}
+		challenge = v
+	}
+
+	m.mu.Lock()
+	if cfg.MaxConcurrent > 0 {
+		m.maxConcurrent = cfg.MaxConcurrent
+	}
+	if cfg.MaxQueueSize > 0 {
+		m.maxQueueSize = cfg.MaxQueueSize
+	}
+	if cfg.JobTimeoutDuration > 0 {
+		m.jobTimeout = cfg.JobTimeoutDuration
+	}
+	if cfg.ResultTTLDuration > 0 {
+		m.resultTTL = cfg.ResultTTLDuration
+	}
+	if cfg.BatchMaxTargets > 0 {
+		m.batchMax = cfg.BatchMaxTargets
+	}
+	m.slowLatencyLookup = slowLatencyLookupFor(slowLatencyByService)
+	m.challenge = challenge
+	m.challengeCfg = cfg.Challenge
+	m.mu.Unlock()
+
+	if cfg.RateLimitPerMinute > 0 {
+		m.limiter.SetRate(cfg.RateLimitPerMinute, cfg.RateLimitPerMinute)
+	}
+	if m.enabled.Swap(cfg.Enabled) != cfg.Enabled {
+		logger.Info("selftest", "自助测试启用状态已变更", "enabled", cfg.Enabled)
+	}
+
+	// 调大并发数后补足运行中的任务
+	for range cfg.MaxConcurrent {
+		m.scheduleNext()
+	}
+	return nil
+}
+
+// Enabled 返回是否对外提供自助测试（selftest.enabled）
+func (m *TestJobManager) Enabled() bool {
+	return m != nil && m.enabled.Load()
+}
+
 // CreateJob creates a new test job and enqueues it
 // Returns the job ID and any error
 func (m *TestJobManager) CreateJob(
 	testType, apiURL, apiKey string,
 ) (*TestJob, error) {
-	// 1. SSRF protection
-	if err := m.ssrfGuard.ValidateURL(apiURL); err != nil {
-		return nil, &Error{
-			Code:    ErrCodeInvalidURL,
-			Message: "API 地址不安全或不合法",
-			Err:     err,
-		}
-	}
-
-	// 2. Test type validation
-	testTypeDef, ok := GetTestType(testType)
-	if !ok {
-		return nil, &Error{
-			Code:    ErrCodeUnknownTestType,
-			Message: "不支持的测试类型",
-			Err:     fmt.Errorf("unknown test type: %s", testType),
-		}
+	// 1-2. SSRF protection & test type validation
+	if err := m.validateTarget(testType, apiURL); err != nil {
+		return nil, err
 	}
 
 	// 4. Check queue capacity
@@ -141,20 +240,8 @@ func (m *TestJobManager) CreateJob(
 		}
 	}
 
-	// 5. Create job
-	job := &TestJob{
-		ID:        uuid.New().String(),
-		TestType:  testType,
-		APIURL:    apiURL,
-		APIKey:    apiKey, // Stored in memory only, never serialized
-		Status:    StatusQueued,
-		QueuePos:  len(m.queue) + 1,
-		CreatedAt: time.Now(),
-	}
-
-	// 6. Enqueue
-	m.queue = append(m.queue, job)
-	m.jobs[job.ID] = job
+	// 5-6. Create job & enqueue
+	job := m.enqueueLocked(testType, apiURL, apiKey)
 	m.mu.Unlock()
 
 	logger.Info("selftest", "Job created and queued",
@@ -163,14 +250,47 @@ func (m *TestJobManager) CreateJob(
 	// 7. Try to schedule immediately
 	m.scheduleNext()
 
-	// Store test type definition for later use
-	_ = testTypeDef // We'll use this in worker
-
 	// 8. Return snapshot to avoid data race with worker
 	// Worker may have already started modifying job fields after scheduleNext()
 	return job.Snapshot(), nil
 }
 
+// validateTarget 校验测试目标（SSRF 防护 + 测试类型）
+func (m *TestJobManager) validateTarget(testType, apiURL string) error {
+	if err := m.ssrfGuard.ValidateURL(apiURL); err != nil {
+		return &Error{
+			Code:    ErrCodeInvalidURL,
+			Message: "API 地址不安全或不合法",
+			Err:     err,
+		}
+	}
+
+	if _, ok := GetTestType(testType); !ok {
+		return &Error{
+			Code:    ErrCodeUnknownTestType,
+			Message: "不支持的测试类型",
+			Err:     fmt.Errorf("unknown test type: %s", testType),
+		}
+	}
+	return nil
+}
+
+// enqueueLocked 创建任务并加入队列（调用方需持有 m.mu）
+func (m *TestJobManager) enqueueLocked(testType, apiURL, apiKey string) *TestJob {
+	job := &TestJob{
+		ID:        uuid.New().String(),
+		TestType:  testType,
+		APIURL:    apiURL,
+		APIKey:    apiKey, // Stored in memory only, never serialized
+		Status:    StatusQueued,
+		QueuePos:  len(m.queue) + 1,
+		CreatedAt: time.Now(),
+	}
+	m.queue = append(m.queue, job)
+	m.jobs[job.ID] = job
+	return job
+}
+
 // GetJob retrieves a job by ID and returns a snapshot (copy) to avoid data races
 func (m *TestJobManager) GetJob(id string) (*TestJob, error) {
 	m.mu.RLock()
@@ -207,12 +327,14 @@ func (m *TestJobManager) scheduleNext() {
 		queuedJob.mu.Lock()
 		queuedJob.QueuePos = i + 1
 		queuedJob.mu.Unlock()
+		m.publishState(queuedJob)
 	}
 
 	// Mark as running
 	m.running[job.ID] = job
 	now := time.Now()
 	job.setRunning(now)
+	m.publishState(job)
 
 	logger.Info("selftest", "Job started",
 		"job_id", job.ID, "test_type", job.TestType, "running_count", len(m.running))
@@ -225,6 +347,9 @@ func (m *TestJobManager) scheduleNext() {
 // worker executes a test job
 func (m *TestJobManager) worker(job *TestJob) {
 	defer func() {
+		// 推送终态（所有退出路径均已写入最终状态）
+		m.publishState(job)
+
 		// Release semaphore and try to schedule next job
 		m.mu.Lock()
 		delete(m.running, job.ID)
@@ -264,22 +389,29 @@ func (m *TestJobManager) worker(job *TestJob) {
 		return
 	}
 
+	m.mu.RLock()
+	slowLatencyLookup := m.slowLatencyLookup
+	jobTimeout := m.jobTimeout
+	m.mu.RUnlock()
+
 	// 按服务类型覆盖 slow_latency（如有配置）
 	// 否则保持 builder 的默认值（向后兼容：默认 5s）
-	if m.slowLatencyLookup != nil {
+	if slowLatencyLookup != nil {
 		serviceKey := strings.ToLower(strings.TrimSpace(cfg.Service))
 		if serviceKey != "" {
-			if d, ok := m.slowLatencyLookup(serviceKey); ok && d > 0 {
+			if d, ok := slowLatencyLookup(serviceKey); ok && d > 0 {
 				cfg.SlowLatencyDuration = d
 			}
 		}
 	}
 
 	// Execute probe with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), m.jobTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), jobTimeout)
 	defer cancel()
 
-	result := m.prober.Probe(ctx, cfg)
+	result := m.prober.ProbeStream(ctx, cfg, func(delta string) {
+		m.publishToken(job.ID, delta)
+	})
 
 	// 写入结果（持 job 独立锁，避免 data race）
 	now := time.Now()
@@ -347,6 +479,21 @@ func (m *TestJobManager) cleanup() {
 				"job_id", id, "finished_at", finishedAt)
 		}
 	}
+
+	// 批量任务的子任务全部被清理后，回收批量任务本身
+	for id, batch := range m.batches {
+		alive := false
+		for _, jobID := range batch.JobIDs {
+			if _, ok := m.jobs[jobID]; ok {
+				alive = true
+				break
+			}
+		}
+		if !alive {
+			delete(m.batches, id)
+			logger.Info("selftest", "Batch cleaned up", "batch_id", id)
+		}
+	}
 }
 
 // Stop gracefully stops the manager (idempotent, safe to call multiple times)
@@ -358,7 +505,34 @@ func (m *TestJobManager) Stop() {
 	})
 }
 
+// IssueChallenge 生成人机验证参数；未启用时返回 type=none
+func (m *TestJobManager) IssueChallenge() (*Challenge, error) {
+	m.mu.RLock()
+	challenge := m.challenge
+	m.mu.RUnlock()
+	if challenge == nil {
+		return &Challenge{Type: config.SelfTestChallengeNone}, nil
+	}
+	return challenge.Issue()
+}
+
+// VerifyChallenge 校验人机验证结果；未启用时直接通过
+func (m *TestJobManager) VerifyChallenge(ctx context.Context, challenge, response, remoteIP string) error {
+	m.mu.RLock()
+	verifier := m.challenge
+	m.mu.RUnlock()
+	if verifier == nil {
+		return nil
+	}
+	return verifier.Verify(ctx, challenge, response, remoteIP)
+}
+
 // CheckRateLimit checks if the IP is allowed to make a request
 func (m *TestJobManager) CheckRateLimit(ip string) bool {
 	return m.limiter.Allow(ip)
 }
+
+// CheckRateLimitN checks if the IP is allowed to make n requests at once (batch)
+func (m *TestJobManager) CheckRateLimitN(ip string, n int) bool {
+	return m.limiter.AllowN(ip, n)
+}
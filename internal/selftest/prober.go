@@ -1,8 +1,10 @@
 package selftest
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -44,6 +46,12 @@ func NewSelfTestProber(guard *SSRFGuard, maxBodyBytes int64) *SelfTestProber {
 
 // Probe 执行一次自助测试探测（带响应体大小限制，且禁用重定向）
 func (p *SelfTestProber) Probe(ctx context.Context, cfg *config.ServiceConfig) *ProbeResult {
+	return p.ProbeStream(ctx, cfg, nil)
+}
+
+// ProbeStream 与 Probe 相同，但当上游返回 text/event-stream 时逐行读取，
+// 并将解析出的增量文本回调给 onDelta（用于进度推送）；onDelta 为 nil 时等价于 Probe
+func (p *SelfTestProber) ProbeStream(ctx context.Context, cfg *config.ServiceConfig, onDelta func(string)) *ProbeResult {
 	result := &ProbeResult{
 		Status:    0,
 		SubStatus: "none",
@@ -78,7 +86,12 @@ func (p *SelfTestProber) Probe(ctx context.Context, cfg *config.ServiceConfig) *
 	result.HTTPCode = resp.StatusCode
 
 	// 始终读取响应体（用于内容校验和错误排查）
-	body, err := p.readBodyLimited(resp.Body, p.maxBodyBytes)
+	var body []byte
+	if onDelta != nil && strings.Contains(resp.Header.Get("Content-Type"), "text/event-stream") {
+		body, err = p.readSSELimited(resp.Body, p.maxBodyBytes, onDelta)
+	} else {
+		body, err = p.readBodyLimited(resp.Body, p.maxBodyBytes)
+	}
 	if err != nil {
 		result.SubStatus = "response_too_large"
 		result.Err = err
@@ -126,6 +139,87 @@ func (p *SelfTestProber) readBodyLimited(r io.Reader, limit int64) ([]byte, erro
 	return data, nil
 }
 
+// readSSELimited 逐行读取 SSE 响应体（上限同 readBodyLimited），每行 data 解析出的增量文本回调 onDelta
+// 返回完整的原始响应体，供后续状态判定与内容校验使用
+func (p *SelfTestProber) readSSELimited(r io.Reader, limit int64, onDelta func(string)) ([]byte, error) {
+	if limit <= 0 {
+		limit = DefaultMaxResponseBytes
+	}
+	var buf bytes.Buffer
+	reader := bufio.NewReader(io.LimitReader(r, limit+1))
+	for {
+		line, err := reader.ReadBytes('\n')
+		buf.Write(line)
+		if int64(buf.Len()) > limit {
+			return buf.Bytes()[:limit], fmt.Errorf("响应体超过上限 %d bytes", limit)
+		}
+		if delta := sseDelta(line); delta != "" {
+			onDelta(delta)
+		}
+		if err == io.EOF {
+			return buf.Bytes(), nil
+		}
+		if err != nil {
+			return buf.Bytes(), err
+		}
+	}
+}
+
+// sseDelta 从单行 SSE data 中提取增量文本
+// 支持 Anthropic（delta.text）、OpenAI Chat（choices[].delta.content）、
+// OpenAI Responses（顶层 delta 字符串）与 Gemini（candidates[].content.parts[].text）
+func sseDelta(line []byte) string {
+	payload, ok := bytes.CutPrefix(bytes.TrimSpace(line), []byte("data:"))
+	if !ok {
+		return ""
+	}
+	payload = bytes.TrimSpace(payload)
+	if len(payload) == 0 || bytes.Equal(payload, []byte("[DONE]")) {
+		return ""
+	}
+
+	var obj struct {
+		Delta   json.RawMessage `json:"delta"`
+		Choices []struct {
+			Delta struct {
+				Content string `json:"content"`
+			} `json:"delta"`
+		} `json:"choices"`
+		Candidates []struct {
+			Content struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+		} `json:"candidates"`
+	}
+	if err := json.Unmarshal(payload, &obj); err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	if len(obj.Delta) > 0 {
+		var text string
+		var block struct {
+			Text string `json:"text"`
+		}
+		if json.Unmarshal(obj.Delta, &text) == nil {
+			b.WriteString(text)
+		} else if json.Unmarshal(obj.Delta, &block) == nil {
+			b.WriteString(block.Text)
+		}
+	}
+	for _, ch := range obj.Choices {
+		b.WriteString(ch.Delta.Content)
+	}
+	for _, c := range obj.Candidates {
+		for _, part := range c.Content.Parts {
+			b.WriteString(part.Text)
+		}
+	}
+	return b.String()
+}
+
 func determineSelfTestStatus(statusCode, latency int, slowLatency time.Duration) (int, string) {
 	// 2xx = 绿（或慢速黄）
 	if statusCode >= 200 && statusCode < 300 {
@@ -0,0 +1,124 @@
+package selftest
+
+import (
+	"fmt"
+)
+
+// progressBufferSize 每个订阅者的事件缓冲（满时丢弃 token 事件，保证状态事件优先）
+const progressBufferSize = 64
+
+// ProgressType 进度事件类型
+type ProgressType string
+
+const (
+	// ProgressState 任务状态变化（queued → running → success/failed/timeout/canceled，含排队位置变化）
+	ProgressState ProgressType = "state"
+	// ProgressToken 流式响应的增量文本（仅当上游返回 text/event-stream 时产生）
+	ProgressToken ProgressType = "token"
+)
+
+// ProgressEvent 任务进度事件
+type ProgressEvent struct {
+	Type  ProgressType
+	Job   *TestJob // 状态事件携带任务快照（不含敏感字段）
+	Delta string   // token 事件携带的增量文本
+}
+
+// Subscribe 订阅任务进度
+// 返回订阅时刻的任务快照与事件通道；任务进入终态后通道会被关闭。
+// 若任务已处于终态，返回的通道为 nil，调用方只需使用快照。
+// cancel 用于提前取消订阅（幂等）。
+func (m *TestJobManager) Subscribe(jobID string) (*TestJob, <-chan ProgressEvent, func(), error) {
+	m.mu.RLock()
+	job, ok := m.jobs[jobID]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, nil, nil, &Error{
+			Code:    ErrCodeJobNotFound,
+			Message: "任务不存在或已过期",
+			Err:     fmt.Errorf("job not found: %s", jobID),
+		}
+	}
+
+	if job.IsTerminal() {
+		return job.Snapshot(), nil, func() {}, nil
+	}
+
+	// 先注册再取快照：之后发生的状态变化要么已体现在快照中，要么会推送到通道
+	ch := make(chan ProgressEvent, progressBufferSize)
+	m.subMu.Lock()
+	m.subscribers[jobID] = append(m.subscribers[jobID], ch)
+	m.subMu.Unlock()
+
+	cancel := func() {
+		m.subMu.Lock()
+		defer m.subMu.Unlock()
+		subs := m.subscribers[jobID]
+		for i, c := range subs {
+			if c == ch {
+				m.subscribers[jobID] = append(subs[:i], subs[i+1:]...)
+				close(ch)
+				break
+			}
+		}
+		if len(m.subscribers[jobID]) == 0 {
+			delete(m.subscribers, jobID)
+		}
+	}
+
+	// 注册前后任务可能恰好结束（终态事件已发出），此时直接返回快照
+	snapshot := job.Snapshot()
+	if snapshot.isTerminalLocked() {
+		cancel()
+		return snapshot, nil, func() {}, nil
+	}
+	return snapshot, ch, cancel, nil
+}
+
+// publishState 推送任务状态事件；终态时推送后关闭该任务的全部订阅
+// 订阅者消费过慢时事件会被丢弃，通道关闭后调用方应重新读取一次最终结果
+func (m *TestJobManager) publishState(job *TestJob) {
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+
+	subs := m.subscribers[job.ID]
+	if len(subs) == 0 {
+		return
+	}
+
+	snapshot := job.Snapshot()
+	terminal := snapshot.isTerminalLocked()
+	for _, ch := range subs {
+		select {
+		case ch <- ProgressEvent{Type: ProgressState, Job: snapshot}:
+		default:
+		}
+	}
+	if terminal {
+		for _, ch := range subs {
+			close(ch)
+		}
+		delete(m.subscribers, snapshot.ID)
+	}
+}
+
+// publishToken 推送流式响应的增量文本
+func (m *TestJobManager) publishToken(jobID, delta string) {
+	if delta == "" {
+		return
+	}
+
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+
+	for _, ch := range m.subscribers[jobID] {
+		// 至少为终态事件预留一个缓冲位
+		if len(ch) >= cap(ch)-1 {
+			continue
+		}
+		select {
+		case ch <- ProgressEvent{Type: ProgressToken, Delta: delta}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package selftest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ShareSigner 生成与校验测试结果分享令牌（HMAC-SHA256）
+//
+// 令牌格式："<job_id>.<expires_unix>.<signature>"，签名为 base64url（无填充），
+// 签名内容包含到期时间，篡改 ID 或延长有效期都会导致校验失败
+type ShareSigner struct {
+	secretKey string
+}
+
+// NewShareSigner creates a new share token signer
+func NewShareSigner(secretKey string) *ShareSigner {
+	return &ShareSigner{secretKey: secretKey}
+}
+
+// Sign 为指定任务生成分享令牌
+func (s *ShareSigner) Sign(jobID string, expiresAt time.Time) string {
+	exp := expiresAt.Unix()
+	return fmt.Sprintf("%s.%d.%s", jobID, exp, s.signature(jobID, exp))
+}
+
+// Verify 校验分享令牌，返回任务 ID 与到期时间
+// 签名无效或已过期时返回 ErrCodeInvalidShareToken 错误
+func (s *ShareSigner) Verify(token string, now time.Time) (string, time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", time.Time{}, invalidShareToken(fmt.Errorf("malformed token"))
+	}
+
+	jobID := parts[0]
+	exp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", time.Time{}, invalidShareToken(fmt.Errorf("invalid expiry: %w", err))
+	}
+
+	// Use constant-time comparison to prevent timing attacks
+	expected := s.signature(jobID, exp)
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return "", time.Time{}, invalidShareToken(fmt.Errorf("invalid signature"))
+	}
+
+	expiresAt := time.Unix(exp, 0)
+	if !now.Before(expiresAt) {
+		return "", time.Time{}, invalidShareToken(fmt.Errorf("token expired at %s", expiresAt.UTC().Format(time.RFC3339)))
+	}
+
+	return jobID, expiresAt, nil
+}
+
+// signature 计算 "share:<job_id>:<expires_unix>" 的 HMAC-SHA256 签名
+func (s *ShareSigner) signature(jobID string, exp int64) string {
+	h := hmac.New(sha256.New, []byte(s.secretKey))
+	fmt.Fprintf(h, "share:%s:%d", jobID, exp)
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+func invalidShareToken(err error) error {
+	return &Error{
+		Code:    ErrCodeInvalidShareToken,
+		Message: "分享链接无效或已过期",
+		Err:     err,
+	}
+}
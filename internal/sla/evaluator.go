@@ -0,0 +1,309 @@
+package sla
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+// checkInterval 检查是否到达评估时间的间隔（评估间隔由 sla.interval 决定，热更新后立即重新评估）
+const checkInterval = time.Minute
+
+// slaEventScan 查询本周期已发出的 SLA 事件时扫描的最近事件数
+const slaEventScan = 20
+
+// EventEmitter 发出 SLA 事件（由 events.Service 实现）
+type EventEmitter interface {
+	EmitSLAEvent(namespace, provider string, eventType storage.EventType, periodStart int64, fromStatus, toStatus int, meta map[string]any) (*storage.StatusEvent, error)
+}
+
+// notifyState 服务商本周期已发出的 SLA 事件
+type notifyState struct {
+	periodStart int64
+	warned      bool
+	breached    bool
+}
+
+// Evaluator SLA 后台评估任务
+type Evaluator struct {
+	store   storage.Storage
+	emitter EventEmitter // nil 表示事件服务未启用，仅计算状态
+
+	mu             sync.RWMutex
+	cfg            config.SLAConfig
+	monitors       []config.ServiceConfig
+	degradedWeight float64
+	batchMaxKeys   int
+	dirty          bool     // 配置已变更，下一轮立即评估
+	statuses       []Status // 最近一次评估结果（按 targets 顺序）
+
+	// 以下字段仅在评估协程内访问
+	lastRun  time.Time
+	notified map[string]*notifyState // 小写 provider -> 本周期已发出的事件
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewEvaluator 创建 SLA 评估任务（emitter 可为 nil）
+func NewEvaluator(store storage.Storage, emitter EventEmitter, cfg *config.AppConfig) *Evaluator {
+	e := &Evaluator{
+		store:    store,
+		emitter:  emitter,
+		notified: make(map[string]*notifyState),
+		stopCh:   make(chan struct{}),
+	}
+	e.UpdateConfig(cfg)
+	return e
+}
+
+// UpdateConfig 热更新 SLA 目标与监测项列表，下一轮检查时立即重新评估
+func (e *Evaluator) UpdateConfig(cfg *config.AppConfig) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cfg = cfg.SLA.Clone()
+	e.monitors = cfg.Monitors
+	e.degradedWeight = cfg.DegradedWeight
+	e.batchMaxKeys = cfg.BatchQueryMaxKeys
+	e.dirty = true
+
+	for _, target := range e.cfg.Targets {
+		if keys, _, _ := providerKeys(e.monitors, target.Provider); len(keys) == 0 {
+			logger.Warn("sla", "SLA 目标没有匹配的启用监测项", "provider", target.Provider)
+		}
+	}
+}
+
+// Start 启动评估任务（阻塞，应在 goroutine 中调用）
+func (e *Evaluator) Start(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	e.tick(ctx, time.Now())
+	for {
+		select {
+		case now := <-ticker.C:
+			e.tick(ctx, now)
+		case <-ctx.Done():
+			return
+		case <-e.stopCh:
+			return
+		}
+	}
+}
+
+// Stop 停止评估任务（幂等，可重复调用）
+func (e *Evaluator) Stop() {
+	e.stopOnce.Do(func() {
+		close(e.stopCh)
+	})
+}
+
+// Statuses 返回最近一次评估结果（未配置目标时为空）
+func (e *Evaluator) Statuses() []Status {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return append([]Status(nil), e.statuses...)
+}
+
+// tick 到达评估间隔或配置变更时执行一轮评估
+func (e *Evaluator) tick(ctx context.Context, now time.Time) {
+	e.mu.Lock()
+	due := e.dirty || now.Sub(e.lastRun) >= e.cfg.IntervalDuration
+	e.dirty = false
+	e.mu.Unlock()
+
+	if due {
+		e.lastRun = now
+		e.run(ctx, now)
+	}
+}
+
+// run 评估全部 SLA 目标，失败的目标只记录告警，下一轮重试
+func (e *Evaluator) run(ctx context.Context, now time.Time) {
+	e.mu.RLock()
+	cfg := e.cfg
+	monitors := e.monitors
+	degradedWeight := e.degradedWeight
+	batchMaxKeys := e.batchMaxKeys
+	e.mu.RUnlock()
+
+	if !cfg.Enabled() {
+		e.setStatuses(nil)
+		return
+	}
+
+	store := e.store.WithContext(ctx)
+	rollupStore, ok := store.(storage.DailyRollupStorage)
+	if !ok {
+		logger.Warn("sla", "当前存储后端不支持每日汇总，无法评估 SLA")
+		return
+	}
+
+	statuses := make([]Status, 0, len(cfg.Targets))
+	for _, target := range cfg.Targets {
+		keys, provider, namespace := providerKeys(monitors, target.Provider)
+		if len(keys) == 0 {
+			statuses = append(statuses, Compute(target, nil, degradedWeight, cfg.WarningThreshold, now))
+			continue
+		}
+
+		start, _ := PeriodBounds(target.Period, now)
+		rollups, err := fetchRollups(rollupStore, keys, start, now, batchMaxKeys)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Warn("sla", "查询每日汇总失败", "provider", provider, "error", err)
+			}
+			continue
+		}
+
+		target.Provider = provider
+		st := Compute(target, mergeDaily(rollups), degradedWeight, cfg.WarningThreshold, now)
+		statuses = append(statuses, st)
+		e.notify(store, &st, namespace)
+	}
+	e.setStatuses(statuses)
+}
+
+// setStatuses 保存最近一次评估结果
+func (e *Evaluator) setStatuses(statuses []Status) {
+	e.mu.Lock()
+	e.statuses = statuses
+	e.mu.Unlock()
+}
+
+// providerKeys 返回服务商的全部启用监测项，以及配置中的 provider 原始名称与命名空间
+func providerKeys(monitors []config.ServiceConfig, provider string) ([]storage.MonitorKey, string, string) {
+	var keys []storage.MonitorKey
+	name, namespace := provider, ""
+	for _, m := range monitors {
+		if m.Disabled || !strings.EqualFold(strings.TrimSpace(m.Provider), provider) {
+			continue
+		}
+		if len(keys) == 0 {
+			name, namespace = m.Provider, m.Namespace
+		}
+		keys = append(keys, storage.MonitorKey{Provider: m.Provider, Service: m.Service, Channel: m.Channel, Model: m.Model})
+	}
+	return keys, name, namespace
+}
+
+// fetchRollups 分批查询 [start, now] 的每日汇总
+func fetchRollups(store storage.DailyRollupStorage, keys []storage.MonitorKey, start, now time.Time, batchMaxKeys int) (map[storage.MonitorKey][]storage.DailyRollupRow, error) {
+	sinceDay := start.Format(dayLayout)
+	untilDay := now.UTC().Format(dayLayout)
+	if batchMaxKeys <= 0 {
+		batchMaxKeys = len(keys)
+	}
+
+	result := make(map[storage.MonitorKey][]storage.DailyRollupRow, len(keys))
+	for i := 0; i < len(keys); i += batchMaxKeys {
+		end := min(i+batchMaxKeys, len(keys))
+		rollups, err := store.GetDailyRollupBatch(keys[i:end], sinceDay, untilDay)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range rollups {
+			result[k] = v
+		}
+	}
+	return result, nil
+}
+
+// notify 错误预算消耗达到预警比例或耗尽时发出事件（每个周期各一次，重启后依据已保存的事件去重）
+func (e *Evaluator) notify(store storage.Storage, st *Status, namespace string) {
+	if e.emitter == nil {
+		return
+	}
+
+	key := strings.ToLower(st.Provider)
+	periodStart := st.PeriodStart.Unix()
+	ns := e.notified[key]
+	if ns == nil || ns.periodStart != periodStart {
+		loaded, err := loadNotified(store, st.Provider, periodStart)
+		if err != nil {
+			logger.Warn("sla", "查询已发出的 SLA 事件失败，跳过本轮通知", "provider", st.Provider, "error", err)
+			return
+		}
+		ns = loaded
+		e.notified[key] = ns
+	}
+
+	var eventType storage.EventType
+	fromStatus, toStatus := 1, 2
+	switch {
+	case st.State == StateBreached && !ns.breached:
+		eventType = storage.EventTypeSLABreached
+		if ns.warned {
+			fromStatus = 2
+		}
+		toStatus = 0
+	case st.State == StateWarning && !ns.warned && !ns.breached:
+		eventType = storage.EventTypeSLABreachWarning
+	default:
+		return
+	}
+
+	event, err := e.emitter.EmitSLAEvent(namespace, st.Provider, eventType, periodStart, fromStatus, toStatus, eventMeta(st))
+	if err != nil {
+		logger.Error("sla", "保存 SLA 事件失败", "provider", st.Provider, "event_type", eventType, "error", err)
+		return
+	}
+	if event == nil {
+		// 事件服务当前未启用（可热更新开启）：不标记已通知，开启后本周期仍会发出
+		return
+	}
+	ns.warned = true
+	if eventType == storage.EventTypeSLABreached {
+		ns.breached = true
+	}
+	logger.Warn("sla", "服务商 SLA 错误预算告警",
+		"provider", st.Provider, "event_type", eventType,
+		"target", st.Target, "uptime", st.Uptime, "budget_used", st.BudgetUsed)
+}
+
+// loadNotified 从最近事件中恢复服务商本周期已发出的 SLA 事件（trigger_record_id 为周期开始时间）
+func loadNotified(store storage.Storage, provider string, periodStart int64) (*notifyState, error) {
+	events, err := store.GetRecentStatusEvents(slaEventScan, &storage.EventFilters{
+		Provider: provider,
+		Types:    []storage.EventType{storage.EventTypeSLABreachWarning, storage.EventTypeSLABreached},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ns := &notifyState{periodStart: periodStart}
+	for _, ev := range events {
+		if ev.TriggerRecordID != periodStart {
+			continue
+		}
+		switch ev.EventType {
+		case storage.EventTypeSLABreachWarning:
+			ns.warned = true
+		case storage.EventTypeSLABreached:
+			ns.warned = true
+			ns.breached = true
+		}
+	}
+	return ns, nil
+}
+
+// eventMeta SLA 事件附加信息
+func eventMeta(st *Status) map[string]any {
+	return map[string]any{
+		"target":                   st.Target,
+		"period":                   st.Period,
+		"period_start":             st.PeriodStart.Unix(),
+		"period_end":               st.PeriodEnd.Unix(),
+		"uptime":                   st.Uptime,
+		"downtime_seconds":         int64(st.Downtime.Seconds()),
+		"budget_seconds":           int64(st.Budget.Seconds()),
+		"budget_remaining_seconds": int64(st.BudgetRemaining.Seconds()),
+		"budget_used":              st.BudgetUsed,
+	}
+}
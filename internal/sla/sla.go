@@ -0,0 +1,122 @@
+// Package sla 服务商可用性 SLA 目标评估
+// 按每日汇总统计本周期累计停机时长，计算错误预算剩余量，并在预算即将耗尽/已耗尽时发出事件
+package sla
+
+import (
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+// SLA 状态
+const (
+	StateOK       = "ok"       // 错误预算消耗低于预警比例
+	StateWarning  = "warning"  // 错误预算消耗达到预警比例
+	StateBreached = "breached" // 错误预算已耗尽
+	StateNoData   = "no_data"  // 本周期尚无探测数据
+)
+
+// dayLayout 每日汇总的日期格式（UTC）
+const dayLayout = "2006-01-02"
+
+// Status 单个服务商本周期的 SLA 状态
+type Status struct {
+	Provider    string
+	Target      float64 // 可用率目标（百分比）
+	Period      string
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+
+	Uptime          float64       // 已统计时长内的可用率（百分比），无数据时为 -1
+	Downtime        time.Duration // 累计停机时长（红色全额计入，黄色按 1 - degraded_weight 折算）
+	Budget          time.Duration // 整个周期的错误预算
+	BudgetRemaining time.Duration // 剩余错误预算（耗尽后为负）
+	BudgetUsed      float64       // 错误预算已消耗比例（超过 1 表示已耗尽）
+	Samples         int           // 本周期探测次数
+	State           string
+
+	EvaluatedAt time.Time
+}
+
+// PeriodBounds 返回 now 所在统计周期的起止时间（UTC，左闭右开）
+func PeriodBounds(period string, now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+	if period == config.SLAPeriodWeekly {
+		day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		offset := (int(day.Weekday()) + 6) % 7 // 周一为 0
+		start := day.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 7)
+	}
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// Compute 按服务商的每日汇总（各监测项已按日合并，key 为 UTC 日期）计算本周期 SLA 状态
+// 每日停机时长 = 当日已过去时长 × 不可用占比；无数据的日期不计入已统计时长
+func Compute(target config.SLATarget, daily map[string]storage.DailyRollupRow, degradedWeight, warningThreshold float64, now time.Time) Status {
+	start, end := PeriodBounds(target.Period, now)
+	st := Status{
+		Provider:    target.Provider,
+		Target:      target.Target,
+		Period:      target.Period,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Uptime:      -1,
+		Budget:      time.Duration(float64(end.Sub(start)) * (1 - target.Target/100)),
+		EvaluatedAt: now,
+	}
+
+	var covered, downtime float64 // 秒
+	for day := start; day.Before(end) && day.Before(now); day = day.AddDate(0, 0, 1) {
+		row, ok := daily[day.Format(dayLayout)]
+		if !ok || row.Total == 0 {
+			continue
+		}
+		elapsed := min(now.Sub(day), 24*time.Hour).Seconds()
+		available := (float64(row.Green) + float64(row.Yellow)*degradedWeight) / float64(row.Total)
+		covered += elapsed
+		downtime += elapsed * (1 - available)
+		st.Samples += row.Total
+	}
+
+	st.Downtime = time.Duration(downtime * float64(time.Second)).Round(time.Second)
+	st.BudgetRemaining = st.Budget - st.Downtime
+	switch {
+	case st.Budget > 0:
+		st.BudgetUsed = float64(st.Downtime) / float64(st.Budget)
+	case st.Downtime > 0:
+		st.BudgetUsed = 1 // 目标为 100% 时任何停机即耗尽
+	}
+
+	switch {
+	case covered == 0:
+		st.State = StateNoData
+		return st
+	case st.Downtime > st.Budget:
+		st.State = StateBreached
+	case st.BudgetUsed >= warningThreshold:
+		st.State = StateWarning
+	default:
+		st.State = StateOK
+	}
+	st.Uptime = (1 - downtime/covered) * 100
+	return st
+}
+
+// mergeDaily 将服务商各监测项的每日汇总按日期合并
+func mergeDaily(rollups map[storage.MonitorKey][]storage.DailyRollupRow) map[string]storage.DailyRollupRow {
+	daily := make(map[string]storage.DailyRollupRow)
+	for _, rows := range rollups {
+		for _, r := range rows {
+			acc := daily[r.Day]
+			acc.Day = r.Day
+			acc.Total += r.Total
+			acc.Green += r.Green
+			acc.Yellow += r.Yellow
+			acc.Red += r.Red
+			daily[r.Day] = acc
+		}
+	}
+	return daily
+}
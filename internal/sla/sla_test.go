@@ -0,0 +1,159 @@
+package sla
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestPeriodBounds(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 16, 10, 0, 0, 0, time.UTC) // 周四
+	start, end := PeriodBounds(config.SLAPeriodMonthly, now)
+	if !start.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected monthly bounds: %v - %v", start, end)
+	}
+	start, end = PeriodBounds(config.SLAPeriodWeekly, now)
+	if !start.Equal(time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected weekly bounds: %v - %v", start, end)
+	}
+	// 周日仍属于本周（周一开始）
+	start, _ = PeriodBounds(config.SLAPeriodWeekly, time.Date(2024, 5, 19, 23, 0, 0, 0, time.UTC))
+	if !start.Equal(time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("sunday should belong to the week starting monday: %v", start)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	t.Parallel()
+
+	target := config.SLATarget{Provider: "relay", Target: 99, Period: config.SLAPeriodWeekly}
+	now := time.Date(2024, 5, 14, 12, 0, 0, 0, time.UTC) // 周二中午，本周已过 36h
+
+	// 周期错误预算 = 7d × 1% = 100.8m
+	empty := Compute(target, nil, 0.5, 0.8, now)
+	if empty.State != StateNoData || empty.Uptime != -1 || empty.Budget != 6048*time.Second {
+		t.Fatalf("unexpected empty status: %+v", empty)
+	}
+
+	// 周一 1% 红色 → 14.4m；周二半天 2% 黄色（degraded_weight=0.5 折算 1%）→ 7.2m
+	daily := map[string]storage.DailyRollupRow{
+		"2024-05-13": {Day: "2024-05-13", Total: 100, Green: 99, Red: 1},
+		"2024-05-14": {Day: "2024-05-14", Total: 100, Green: 98, Yellow: 2},
+		"2024-05-12": {Day: "2024-05-12", Total: 100, Red: 100}, // 上周，不计入
+	}
+	st := Compute(target, daily, 0.5, 0.8, now)
+	if st.State != StateOK || st.Downtime != 1296*time.Second || st.BudgetRemaining != 4752*time.Second || st.Samples != 200 {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+	if st.Uptime < 98.99 || st.Uptime > 99.01 {
+		t.Fatalf("unexpected uptime: %v", st.Uptime)
+	}
+
+	daily["2024-05-13"] = storage.DailyRollupRow{Day: "2024-05-13", Total: 100, Green: 94, Red: 6} // 86.4m + 7.2m ≈ 93% 预算
+	if st := Compute(target, daily, 0.5, 0.8, now); st.State != StateWarning {
+		t.Fatalf("expected warning, got %+v", st)
+	}
+	daily["2024-05-13"] = storage.DailyRollupRow{Day: "2024-05-13", Total: 100, Green: 90, Red: 10}
+	if st := Compute(target, daily, 0.5, 0.8, now); st.State != StateBreached || st.BudgetRemaining >= 0 || st.BudgetUsed <= 1 {
+		t.Fatalf("expected breached, got %+v", st)
+	}
+
+	strict := config.SLATarget{Provider: "relay", Target: 100, Period: config.SLAPeriodWeekly}
+	if st := Compute(strict, daily, 0.5, 0.8, now); st.State != StateBreached || st.BudgetUsed != 1 {
+		t.Fatalf("100%% target should breach on any downtime: %+v", st)
+	}
+}
+
+type recordedEvent struct {
+	provider    string
+	eventType   storage.EventType
+	periodStart int64
+}
+
+type fakeEmitter struct {
+	store  storage.Storage
+	events []recordedEvent
+}
+
+func (f *fakeEmitter) EmitSLAEvent(namespace, provider string, eventType storage.EventType, periodStart int64, fromStatus, toStatus int, meta map[string]any) (*storage.StatusEvent, error) {
+	f.events = append(f.events, recordedEvent{provider, eventType, periodStart})
+	event := &storage.StatusEvent{
+		Namespace: namespace, Provider: provider, EventType: eventType,
+		FromStatus: fromStatus, ToStatus: toStatus, TriggerRecordID: periodStart,
+		ObservedAt: time.Now().Unix(), CreatedAt: time.Now().Unix(), Meta: meta,
+	}
+	return event, f.store.SaveStatusEvent(event)
+}
+
+func TestEvaluatorEmitsOncePerPeriod(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "monitor.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer store.Close()
+	if err := store.Init(); err != nil {
+		t.Fatalf("init sqlite: %v", err)
+	}
+
+	now := time.Date(2024, 5, 16, 12, 0, 0, 0, time.UTC)
+	save := func(status int, n int) {
+		for i := 0; i < n; i++ {
+			if err := store.SaveRecord(&storage.ProbeRecord{
+				Provider: "Relay", Service: "cc", Channel: "vip", Status: status, Timestamp: now.Add(-time.Duration(i) * time.Second).Unix(),
+			}); err != nil {
+				t.Fatalf("save record: %v", err)
+			}
+		}
+	}
+
+	cfg := &config.AppConfig{
+		DegradedWeight: 0.7,
+		Monitors:       []config.ServiceConfig{{Provider: "Relay", Service: "cc", Channel: "vip"}},
+		SLA: config.SLAConfig{
+			IntervalDuration: 5 * time.Minute,
+			WarningThreshold: 0.8,
+			Targets:          []config.SLATarget{{Provider: "relay", Target: 99.9, Period: config.SLAPeriodMonthly}},
+		},
+	}
+	emitter := &fakeEmitter{store: store}
+	e := NewEvaluator(store, emitter, cfg)
+
+	// 全部绿色：不发事件
+	save(1, 10)
+	e.run(t.Context(), now)
+	if len(emitter.events) != 0 {
+		t.Fatalf("unexpected events: %+v", emitter.events)
+	}
+	statuses := e.Statuses()
+	if len(statuses) != 1 || statuses[0].Provider != "Relay" || statuses[0].State != StateOK {
+		t.Fatalf("unexpected statuses: %+v", statuses)
+	}
+
+	// 大量红色：直接耗尽预算，仅发出 SLA_BREACHED，再次评估不重复
+	save(0, 90)
+	e.run(t.Context(), now)
+	e.run(t.Context(), now)
+	if len(emitter.events) != 1 || emitter.events[0].eventType != storage.EventTypeSLABreached || emitter.events[0].provider != "Relay" {
+		t.Fatalf("expected a single breach event, got %+v", emitter.events)
+	}
+
+	// 重启后依据已保存的事件去重
+	restarted := NewEvaluator(store, emitter, cfg)
+	restarted.run(t.Context(), now)
+	if len(emitter.events) != 1 {
+		t.Fatalf("breach event re-emitted after restart: %+v", emitter.events)
+	}
+
+	// 移除目标后不再返回状态
+	cfg.SLA.Targets = nil
+	restarted.UpdateConfig(cfg)
+	restarted.run(t.Context(), now)
+	if len(restarted.Statuses()) != 0 {
+		t.Fatalf("statuses should be cleared: %+v", restarted.Statuses())
+	}
+}
@@ -0,0 +1,13 @@
+package storage
+
+// adminUserColumns admin_users 查询列（与 scanAdminUser 的扫描顺序一致）
+const adminUserColumns = `id, username, password_hash, role, created_at, updated_at, disabled_at`
+
+// scanAdminUser 扫描单个管理后台用户（SQLite 与 PostgreSQL 共用）
+func scanAdminUser(row interface{ Scan(...any) error }) (*AdminUser, error) {
+	var u AdminUser
+	if err := row.Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Role, &u.CreatedAt, &u.UpdatedAt, &u.DisabledAt); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
@@ -16,7 +16,7 @@ import (
 )
 
 // Cleaner 历史数据清理任务调度器
-// 负责定期清理过期的探测记录，避免数据库无限增长
+// 负责定期清理过期的探测记录与宽限期已满的下线监测项数据，避免数据库无限增长
 type Cleaner struct {
 	storage  Storage
 	config   *config.RetentionConfig
@@ -160,4 +160,68 @@ func (c *Cleaner) runCleanup(ctx context.Context) {
 			"elapsed", elapsed,
 			"cutoff", cutoff.Format(time.RFC3339))
 	}
+
+	c.purgeDecommissioned(ctx)
+}
+
+// purgeDecommissioned 清理宽限期已满的下线监测项
+// 先分批删除探测记录（单轮最多 max_batches_per_run 批，未删完时下一轮继续），
+// 再删除其每日汇总、状态机状态与下线登记
+func (c *Cleaner) purgeDecommissioned(ctx context.Context) {
+	ds, ok := c.storage.(DecommissionStorage)
+	if !ok {
+		return
+	}
+
+	monitors, err := ds.GetDecommissionedMonitors()
+	if err != nil {
+		logger.Warn("cleaner", "查询已下线监测项失败", "error", err)
+		return
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -c.config.DecommissionedDays).Unix()
+	batches := 0
+	for _, m := range monitors {
+		if m.RetiredAt >= cutoff {
+			break // 按 retired_at 升序，后续均在宽限期内
+		}
+
+		key := m.Key()
+		var deleted int64
+		done := false
+		for batches < c.config.MaxBatchesPerRun {
+			if ctx.Err() != nil {
+				return
+			}
+			n, err := ds.PurgeMonitorRecords(ctx, key, c.config.BatchSize)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Warn("cleaner", "清理已下线监测项记录失败，下一轮重试",
+						"provider", key.Provider, "service", key.Service, "channel", key.Channel, "model", key.Model,
+						"error", err)
+				}
+				return
+			}
+			batches++
+			deleted += n
+			if n < int64(c.config.BatchSize) {
+				done = true
+				break
+			}
+		}
+		if !done {
+			return // 本轮批次已用尽
+		}
+
+		if err := ds.DropDecommissionedMonitor(ctx, key); err != nil {
+			logger.Warn("cleaner", "删除已下线监测项登记失败，下一轮重试",
+				"provider", key.Provider, "service", key.Service, "channel", key.Channel, "model", key.Model,
+				"error", err)
+			return
+		}
+		logger.Info("cleaner", "已下线监测项数据已清理",
+			"provider", key.Provider, "service", key.Service, "channel", key.Channel, "model", key.Model,
+			"retired_at", time.Unix(m.RetiredAt, 0).UTC().Format(time.RFC3339),
+			"deleted", deleted)
+	}
 }
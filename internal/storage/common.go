@@ -1,9 +1,34 @@
 // Package storage 提供数据存储相关的公共工具函数
 package storage
 
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"monitor/internal/tracing"
+)
+
 // reverseRecords 反转记录数组（DESC 取数后翻转为时间升序）
 func reverseRecords(records []*ProbeRecord) {
 	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
 		records[i], records[j] = records[j], records[i]
 	}
 }
+
+// escapeLikePattern 转义 LIKE 模式中的通配符（配合 ESCAPE '\' 使用）
+func escapeLikePattern(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "%", `\%`)
+	return strings.ReplaceAll(s, "_", `\_`)
+}
+
+// startSpan 为存储操作创建追踪 span（未启用追踪时为空操作）
+func startSpan(ctx context.Context, system, operation string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "storage."+operation,
+		attribute.String("db.system", system),
+		attribute.String("db.operation", operation),
+	)
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// customGroupColumns custom_groups 查询列（与 scanCustomGroup 的扫描顺序一致）
+const customGroupColumns = `id, name, description, members, created_at, updated_at`
+
+// encodeCustomGroupMembers 将成员列表序列化为 JSON 文本（SQLite 与 PostgreSQL 共用）
+func encodeCustomGroupMembers(members []CustomGroupMember) (string, error) {
+	if members == nil {
+		members = []CustomGroupMember{}
+	}
+	b, err := json.Marshal(members)
+	if err != nil {
+		return "", fmt.Errorf("序列化分组成员失败: %w", err)
+	}
+	return string(b), nil
+}
+
+// scanCustomGroup 扫描单个自定义分组（SQLite 与 PostgreSQL 共用）
+func scanCustomGroup(row interface{ Scan(...any) error }) (*CustomGroup, error) {
+	var g CustomGroup
+	var members string
+	if err := row.Scan(&g.ID, &g.Name, &g.Description, &members, &g.CreatedAt, &g.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(members), &g.Members); err != nil {
+		return nil, fmt.Errorf("解析分组 %s 的成员失败: %w", g.ID, err)
+	}
+	return &g, nil
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"time"
+
+	"monitor/internal/config"
+)
+
+// SyncDecommissionedMonitors 根据配置变更维护已下线监测项登记
+// - oldMonitors 中存在（且未禁用）、newMonitors 中已不存在的监测项登记为已下线
+// - newMonitors 中存在的监测项移除登记（重新加入配置后恢复正常展示，历史数据保留）
+// oldMonitors 为 nil 时仅做恢复（用于启动时对账）；存储不支持时直接返回
+func SyncDecommissionedMonitors(store Storage, oldMonitors, newMonitors []config.ServiceConfig, now time.Time) (retired, restored int, err error) {
+	ds, ok := store.(DecommissionStorage)
+	if !ok {
+		return 0, 0, nil
+	}
+
+	current := make(map[MonitorKey]bool, len(newMonitors))
+	for _, m := range newMonitors {
+		current[monitorKeyOf(m)] = true
+	}
+
+	existing, err := ds.GetDecommissionedMonitors()
+	if err != nil {
+		return 0, 0, err
+	}
+	var restoredKeys []MonitorKey
+	for _, m := range existing {
+		if current[m.Key()] {
+			restoredKeys = append(restoredKeys, m.Key())
+		}
+	}
+	if err := ds.DeleteDecommissionedMonitors(restoredKeys); err != nil {
+		return 0, 0, err
+	}
+
+	var removed []*DecommissionedMonitor
+	seen := make(map[MonitorKey]bool)
+	for _, m := range oldMonitors {
+		key := monitorKeyOf(m)
+		if m.Disabled || current[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		removed = append(removed, &DecommissionedMonitor{
+			Provider:     m.Provider,
+			Service:      m.Service,
+			Channel:      m.Channel,
+			Model:        m.Model,
+			Parent:       m.Parent,
+			ProviderName: m.ProviderName,
+			ProviderSlug: m.ProviderSlug,
+			ProviderURL:  m.ProviderURL,
+			ServiceName:  m.ServiceName,
+			ChannelName:  m.ChannelName,
+			Category:     m.Category,
+			Board:        m.Board,
+			Hidden:       m.Hidden,
+			RetiredAt:    now.Unix(),
+		})
+	}
+	if err := ds.SaveDecommissionedMonitors(removed); err != nil {
+		return 0, len(restoredKeys), err
+	}
+
+	return len(removed), len(restoredKeys), nil
+}
+
+// monitorKeyOf 返回配置监测项的存储键
+func monitorKeyOf(m config.ServiceConfig) MonitorKey {
+	return MonitorKey{Provider: m.Provider, Service: m.Service, Channel: m.Channel, Model: m.Model}
+}
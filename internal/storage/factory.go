@@ -10,10 +10,18 @@ import (
 // New 创建存储实例（工厂模式）
 func New(cfg *config.StorageConfig) (Storage, error) {
 	storageType := strings.ToLower(strings.TrimSpace(cfg.Type))
+	slow := newSlowQueryLog(cfg.SlowQuery)
 
 	switch storageType {
 	case "postgres", "postgresql":
-		return NewPostgresStorage(&cfg.Postgres)
+		s, err := newPostgresStorage(&cfg.Postgres, slow)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.WriteBatch.Enabled {
+			s.enableWriteBatch(cfg.WriteBatch)
+		}
+		return s, nil
 
 	case "sqlite", "":
 		// 默认使用 SQLite
@@ -21,7 +29,14 @@ func New(cfg *config.StorageConfig) (Storage, error) {
 		if dbPath == "" {
 			dbPath = "monitor.db"
 		}
-		return NewSQLiteStorage(dbPath)
+		s, err := newSQLiteStorage(dbPath, slow)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.WriteBatch.Enabled {
+			s.enableWriteBatch(cfg.WriteBatch)
+		}
+		return s, nil
 
 	default:
 		return nil, fmt.Errorf("不支持的存储类型: %s (支持: sqlite, postgres)", cfg.Type)
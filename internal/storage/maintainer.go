@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+)
+
+// maintenanceTick 维护时段检查间隔
+const maintenanceTick = time.Minute
+
+// maintenanceHistorySize 保留的最近维护记录条数
+const maintenanceHistorySize = 10
+
+// MaintenanceResult 单轮数据库维护结果
+type MaintenanceResult struct {
+	Trigger    string // schedule（维护时段自动触发）/ manual（管理 API 手动触发）
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Steps      []MaintenanceStep
+	Skipped    string // 未执行的原因（如其他实例正在维护）
+	Error      string
+}
+
+// MaintenanceStatus 维护任务状态快照
+type MaintenanceStatus struct {
+	Windows  []string // 维护时段（HH:MM-HH:MM）
+	Timezone string
+	Interval time.Duration
+	Running  bool
+	NextRun  time.Time           // 下一次自动维护的最早时间（按维护时段与最小间隔推算）
+	History  []MaintenanceResult // 最近的维护记录（新的在前）
+}
+
+// Maintainer 数据库维护任务调度器
+// 在配置的低峰时段内按最小间隔执行 VACUUM / ANALYZE / REINDEX，避免长期运行后查询性能退化
+type Maintainer struct {
+	storage  Storage
+	config   *config.MaintenanceConfig
+	running  atomic.Bool
+	stopCh   chan struct{}
+	stopOnce sync.Once
+
+	mu        sync.Mutex
+	lastStart time.Time // 最近一轮维护的开始时间（用于最小间隔判断）
+	history   []MaintenanceResult
+}
+
+// NewMaintainer 创建数据库维护任务调度器
+func NewMaintainer(storage Storage, cfg *config.MaintenanceConfig) *Maintainer {
+	return &Maintainer{
+		storage: storage,
+		config:  cfg,
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Start 启动维护任务（阻塞，应在 goroutine 中调用）
+func (m *Maintainer) Start(ctx context.Context) {
+	if _, ok := m.storage.(MaintenanceStorage); !ok {
+		logger.Warn("maintenance", "当前存储后端不支持数据库维护，维护任务未启动")
+		return
+	}
+	logger.Info("maintenance", "数据库维护任务已启动",
+		"windows", m.config.Windows,
+		"timezone", m.config.Timezone,
+		"interval", m.config.IntervalDuration)
+
+	ticker := time.NewTicker(maintenanceTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			if m.due(now) && m.running.CompareAndSwap(false, true) {
+				m.run(ctx, "schedule")
+			}
+		case <-ctx.Done():
+			logger.Info("maintenance", "维护任务收到取消信号，正在退出")
+			return
+		case <-m.stopCh:
+			logger.Info("maintenance", "维护任务收到停止信号，正在退出")
+			return
+		}
+	}
+}
+
+// Stop 停止维护任务（幂等，可重复调用），进行中的维护会被取消
+func (m *Maintainer) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stopCh)
+	})
+}
+
+// RunNow 立即在后台执行一轮维护（忽略维护时段与最小间隔），已有维护在运行时返回 false
+func (m *Maintainer) RunNow() bool {
+	if _, ok := m.storage.(MaintenanceStorage); !ok {
+		return false
+	}
+	if !m.running.CompareAndSwap(false, true) {
+		return false
+	}
+	go m.run(context.Background(), "manual")
+	return true
+}
+
+// Status 返回维护任务状态快照
+func (m *Maintainer) Status() MaintenanceStatus {
+	m.mu.Lock()
+	lastStart := m.lastStart
+	history := make([]MaintenanceResult, len(m.history))
+	copy(history, m.history)
+	m.mu.Unlock()
+
+	return MaintenanceStatus{
+		Windows:  append([]string(nil), m.config.Windows...),
+		Timezone: m.config.Timezone,
+		Interval: m.config.IntervalDuration,
+		Running:  m.running.Load(),
+		NextRun:  m.nextRun(time.Now(), lastStart),
+		History:  history,
+	}
+}
+
+// due 判断当前是否应执行自动维护：处于维护时段且距上一轮开始已超过最小间隔
+// （扣除一个检查周期的容差，避免每天的开始时间逐渐推迟到时段之外）
+func (m *Maintainer) due(now time.Time) bool {
+	if !m.config.InWindow(now) {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastStart.IsZero() || now.Sub(m.lastStart) >= m.config.IntervalDuration-maintenanceTick
+}
+
+// nextRun 推算下一次自动维护的最早时间（按分钟向后查找落在维护时段内的时间点）
+func (m *Maintainer) nextRun(now, lastStart time.Time) time.Time {
+	from := now
+	if !lastStart.IsZero() {
+		if earliest := lastStart.Add(m.config.IntervalDuration - maintenanceTick); earliest.After(from) {
+			from = earliest
+		}
+	}
+	from = from.Truncate(time.Minute)
+	for t := from; t.Sub(from) <= 48*time.Hour; t = t.Add(time.Minute) {
+		if m.config.InWindow(t) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// run 执行一轮维护（调用方需已将 running 置为 true）
+func (m *Maintainer) run(ctx context.Context, trigger string) {
+	defer m.running.Store(false)
+
+	ctx, cancel := context.WithTimeout(ctx, m.config.TimeoutDuration)
+	defer cancel()
+	go func() {
+		select {
+		case <-m.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	result := MaintenanceResult{Trigger: trigger, StartedAt: time.Now()}
+	m.mu.Lock()
+	m.lastStart = result.StartedAt
+	m.mu.Unlock()
+
+	logger.Info("maintenance", "开始数据库维护", "trigger", trigger)
+	ms := m.storage.(MaintenanceStorage)
+	steps, err := ms.RunMaintenance(ctx, MaintenanceOptions{
+		VacuumThreshold:  m.config.VacuumThreshold,
+		ReindexThreshold: m.config.ReindexThreshold,
+		IncrementalPages: m.config.IncrementalPages,
+	})
+	result.FinishedAt = time.Now()
+	result.Steps = steps
+
+	for _, step := range steps {
+		if step.Error != "" {
+			logger.Warn("maintenance", "维护步骤失败",
+				"action", step.Action, "target", step.Target, "detail", step.Detail,
+				"duration", step.Duration.Round(time.Millisecond), "error", step.Error)
+			continue
+		}
+		logger.Info("maintenance", "维护步骤完成",
+			"action", step.Action, "target", step.Target, "detail", step.Detail,
+			"duration", step.Duration.Round(time.Millisecond))
+	}
+
+	switch {
+	case errors.Is(err, ErrMaintenanceLockNotAcquired):
+		result.Skipped = "其他实例正在维护"
+		logger.Info("maintenance", "其他实例正在维护，跳过本轮")
+	case err != nil:
+		result.Error = err.Error()
+		logger.Error("maintenance", "数据库维护失败",
+			"trigger", trigger, "steps", len(steps), "elapsed", result.FinishedAt.Sub(result.StartedAt), "error", err)
+	default:
+		logger.Info("maintenance", "数据库维护完成",
+			"trigger", trigger, "steps", len(steps), "elapsed", result.FinishedAt.Sub(result.StartedAt))
+	}
+
+	m.mu.Lock()
+	m.history = append([]MaintenanceResult{result}, m.history...)
+	if len(m.history) > maintenanceHistorySize {
+		m.history = m.history[:maintenanceHistorySize]
+	}
+	m.mu.Unlock()
+}
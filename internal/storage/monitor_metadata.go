@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"monitor/internal/config"
+)
+
+// SyncMonitorMetadata 为元数据发生变化（或首次出现）的监测项写入新版本，返回写入的版本数
+// 已禁用的监测项不记录；存储不支持时直接返回
+func SyncMonitorMetadata(store Storage, monitors []config.ServiceConfig, now time.Time) (int, error) {
+	ms, ok := store.(MonitorMetadataStorage)
+	if !ok {
+		return 0, nil
+	}
+
+	latest, err := ms.GetLatestMonitorMetadata()
+	if err != nil {
+		return 0, err
+	}
+
+	var changed []*MonitorMetadata
+	seen := make(map[MonitorKey]bool)
+	for _, m := range monitors {
+		key := monitorKeyOf(m)
+		if m.Disabled || seen[key] {
+			continue
+		}
+		seen[key] = true
+		snapshot := monitorMetadataOf(m, now)
+		if prev := latest[key]; prev != nil && prev.sameContent(snapshot) {
+			continue
+		}
+		changed = append(changed, snapshot)
+	}
+	if err := ms.SaveMonitorMetadata(changed); err != nil {
+		return 0, err
+	}
+	return len(changed), nil
+}
+
+// MonitorMetadataAt 返回 ts 时刻生效的版本（versions 需按 valid_from 升序），早于首个版本时返回 nil
+func MonitorMetadataAt(versions []*MonitorMetadata, ts int64) *MonitorMetadata {
+	i := sort.Search(len(versions), func(i int) bool { return versions[i].ValidFrom > ts })
+	if i == 0 {
+		return nil
+	}
+	return versions[i-1]
+}
+
+// monitorMetadataOf 从配置监测项生成元数据快照
+func monitorMetadataOf(m config.ServiceConfig, now time.Time) *MonitorMetadata {
+	badges := make([]string, 0, len(m.ResolvedBadges))
+	for _, b := range m.ResolvedBadges {
+		badges = append(badges, b.ID)
+	}
+	return &MonitorMetadata{
+		Provider:     m.Provider,
+		Service:      m.Service,
+		Channel:      m.Channel,
+		Model:        m.Model,
+		ProviderName: m.ProviderName,
+		ProviderSlug: m.ProviderSlug,
+		Sponsor:      m.Sponsor,
+		SponsorURL:   m.SponsorURL,
+		PriceMin:     m.PriceMin,
+		PriceMax:     m.PriceMax,
+		Badges:       badges,
+		ValidFrom:    now.Unix(),
+	}
+}
+
+// sameContent 判断两个版本的元数据内容是否一致（不比较 valid_from）
+func (m *MonitorMetadata) sameContent(o *MonitorMetadata) bool {
+	return m.ProviderName == o.ProviderName &&
+		m.ProviderSlug == o.ProviderSlug &&
+		m.Sponsor == o.Sponsor &&
+		m.SponsorURL == o.SponsorURL &&
+		equalOptionalFloat(m.PriceMin, o.PriceMin) &&
+		equalOptionalFloat(m.PriceMax, o.PriceMax) &&
+		slices.Equal(m.Badges, o.Badges)
+}
+
+func equalOptionalFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// joinBadges / splitBadges 徽标 ID 列表的存储格式（逗号分隔）
+func joinBadges(badges []string) string {
+	return strings.Join(badges, ",")
+}
+
+func splitBadges(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
@@ -6,10 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/attribute"
 
 	"monitor/internal/config"
 	"monitor/internal/logger"
@@ -17,12 +20,19 @@ import (
 
 // PostgresStorage PostgreSQL 存储实现
 type PostgresStorage struct {
-	pool *pgxpool.Pool
-	ctx  context.Context
+	pool  *pgxpool.Pool
+	ctx   context.Context
+	queue *writeQueue   // 批量写入队列（nil 表示逐条写入）
+	slow  *slowQueryLog // 慢查询日志（nil 表示未启用）
 }
 
 // NewPostgresStorage 创建 PostgreSQL 存储
 func NewPostgresStorage(cfg *config.PostgresConfig) (*PostgresStorage, error) {
+	return newPostgresStorage(cfg, nil)
+}
+
+// newPostgresStorage 创建 PostgreSQL 存储（slow 非 nil 时通过 pgx QueryTracer 记录慢查询）
+func newPostgresStorage(cfg *config.PostgresConfig, slow *slowQueryLog) (*PostgresStorage, error) {
 	// 构建连接字符串
 	dsn := fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
@@ -56,6 +66,10 @@ func NewPostgresStorage(cfg *config.PostgresConfig) (*PostgresStorage, error) {
 		poolConfig.MaxConnLifetime = time.Hour
 	}
 
+	if slow != nil {
+		poolConfig.ConnConfig.Tracer = &pgxSlowQueryTracer{slow: slow}
+	}
+
 	// 创建连接池
 	ctx := context.Background()
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
@@ -69,10 +83,13 @@ func NewPostgresStorage(cfg *config.PostgresConfig) (*PostgresStorage, error) {
 		return nil, fmt.Errorf("连接 PostgreSQL 失败: %w", err)
 	}
 
-	return &PostgresStorage{
+	s := &PostgresStorage{
 		pool: pool,
 		ctx:  ctx,
-	}, nil
+		slow: slow,
+	}
+	slow.setExplain(s.explain)
+	return s, nil
 }
 
 // WithContext 返回绑定指定 context 的存储实例
@@ -81,8 +98,10 @@ func (s *PostgresStorage) WithContext(ctx context.Context) Storage {
 		return s
 	}
 	return &PostgresStorage{
-		pool: s.pool,
-		ctx:  ctx,
+		pool:  s.pool,
+		ctx:   ctx,
+		queue: s.queue,
+		slow:  s.slow,
 	}
 }
 
@@ -129,6 +148,20 @@ func (s *PostgresStorage) Init() error {
 	if err := s.ensureModelColumn(); err != nil {
 		return err
 	}
+	if err := s.ensureProbeHistoryColumn("cert_days_remaining", "INTEGER"); err != nil {
+		return err
+	}
+	for _, column := range []string{"dns_ms", "connect_ms", "tls_ms", "ttfb_ms"} {
+		if err := s.ensureProbeHistoryColumn(column, "INTEGER"); err != nil {
+			return err
+		}
+	}
+	if err := s.ensureProbeHistoryColumn("attempts", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.ensureProbeHistoryColumn("ip_family", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
 
 	// 在列迁移完成后创建索引
 	//
@@ -176,6 +209,81 @@ func (s *PostgresStorage) Init() error {
 		return err
 	}
 
+	// 用量统计表
+	if err := s.initUsageTable(ctx); err != nil {
+		return err
+	}
+
+	// 每日可用性汇总表
+	if err := s.initDailyRollupTable(ctx); err != nil {
+		return err
+	}
+
+	// 自助测试分享结果表
+	if err := s.initSelfTestResultTable(ctx); err != nil {
+		return err
+	}
+
+	// 审计日志表
+	if err := s.initAuditTable(ctx); err != nil {
+		return err
+	}
+
+	// 探测调试快照表
+	if err := s.initProbeDebugTable(ctx); err != nil {
+		return err
+	}
+
+	// 事件 Webhook 死信表
+	if err := s.initWebhookDeadLetterTable(ctx); err != nil {
+		return err
+	}
+
+	// 事件发件箱表
+	if err := s.initEventOutboxTable(ctx); err != nil {
+		return err
+	}
+
+	// 探测数据透明度小时根表
+	if err := s.initTransparencyTable(ctx); err != nil {
+		return err
+	}
+
+	// 已下线监测项登记表
+	if err := s.initDecommissionTable(ctx); err != nil {
+		return err
+	}
+
+	// 服务商数据访问令牌表
+	if err := s.initProviderTokenTable(ctx); err != nil {
+		return err
+	}
+
+	// 运维标注表
+	if err := s.initAnnotationTable(ctx); err != nil {
+		return err
+	}
+
+	// 自定义分组表
+	if err := s.initCustomGroupTable(ctx); err != nil {
+		return err
+	}
+
+	// 服务商元数据修改申请表
+	if err := s.initMetadataRequestTable(ctx); err != nil {
+		return err
+	}
+
+	// 监测项元数据版本表
+	if err := s.initMonitorMetadataTable(ctx); err != nil {
+		return err
+	}
+
+	// 管理后台用户与会话表
+	if err := s.initAdminUserTables(ctx); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -294,6 +402,32 @@ func (s *PostgresStorage) ensureModelColumn() error {
 	return nil
 }
 
+// ensureProbeHistoryColumn 确保 probe_history 表存在指定列（不存在时按 ddl 类型添加）
+func (s *PostgresStorage) ensureProbeHistoryColumn(column, ddl string) error {
+	ctx := s.effectiveCtx()
+	checkQuery := `
+		SELECT COUNT(*)
+		FROM information_schema.columns
+		WHERE table_name = 'probe_history' AND column_name = $1
+	`
+
+	var count int
+	if err := s.pool.QueryRow(ctx, checkQuery, column).Scan(&count); err != nil {
+		return fmt.Errorf("查询 PostgreSQL 表结构失败: %w", err)
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	if _, err := s.pool.Exec(ctx, fmt.Sprintf(`ALTER TABLE probe_history ADD COLUMN %s %s`, column, ddl)); err != nil {
+		return fmt.Errorf("添加 %s 列失败: %w", column, err)
+	}
+
+	logger.Info("storage", "已为 probe_history 表添加列 (PostgreSQL)", "column", column)
+	return nil
+}
+
 // MigrateChannelData 根据配置将 channel 为空的旧数据迁移到指定 channel
 func (s *PostgresStorage) MigrateChannelData(mappings []ChannelMigrationMapping) error {
 	ctx := s.effectiveCtx()
@@ -351,18 +485,32 @@ func (s *PostgresStorage) MigrateChannelData(mappings []ChannelMigrationMapping)
 	return nil
 }
 
-// Close 关闭数据库连接
+// enableWriteBatch 启用探测记录批量写入
+func (s *PostgresStorage) enableWriteBatch(cfg config.WriteBatchConfig) {
+	s.queue = newWriteQueue(cfg, s.saveRecordsBatch)
+}
+
+// Close 关闭数据库连接（先写完批量队列中的剩余记录）
 func (s *PostgresStorage) Close() error {
+	if s.queue != nil {
+		s.queue.close()
+	}
 	s.pool.Close()
 	return nil
 }
 
 // SaveRecord 保存探测记录
 func (s *PostgresStorage) SaveRecord(record *ProbeRecord) error {
-	ctx := s.effectiveCtx()
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "SaveRecord")
+	defer span.End()
+	if s.queue != nil {
+		return s.queue.submit(ctx, record)
+	}
+
 	query := `
-		INSERT INTO probe_history (provider, service, channel, model, status, sub_status, http_code, latency, timestamp)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO probe_history (provider, service, channel, model, status, sub_status, http_code, latency, timestamp, cert_days_remaining,
+			dns_ms, connect_ms, tls_ms, ttfb_ms, attempts, ip_family)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		RETURNING id
 	`
 
@@ -376,12 +524,101 @@ func (s *PostgresStorage) SaveRecord(record *ProbeRecord) error {
 		record.HttpCode,
 		record.Latency,
 		record.Timestamp,
+		record.CertDaysRemaining,
+		record.DNSMs,
+		record.ConnectMs,
+		record.TLSMs,
+		record.TTFBMs,
+		record.Attempts,
+		record.IPFamily,
 	).Scan(&record.ID)
 
 	if err != nil {
 		return fmt.Errorf("保存 PostgreSQL 记录失败: %w", err)
 	}
 
+	// 同步累加每日汇总（失败仅记录警告，不影响原始记录写入）
+	if err := s.addDailyRollup(ctx, record); err != nil {
+		logger.Warn("storage", "累加 PostgreSQL 每日汇总失败", "provider", record.Provider, "service", record.Service, "error", err)
+	}
+	return nil
+}
+
+// postgresInsertChunk 单条多行 INSERT 的最大行数（16 列 × 500 行，远低于 65535 个参数上限）
+const postgresInsertChunk = 500
+
+// saveRecordsBatch 在单个事务内以多行 INSERT 写入一批探测记录（批量写入队列调用）
+func (s *PostgresStorage) saveRecordsBatch(ctx context.Context, records []*ProbeRecord) error {
+	ctx, span := startSpan(ctx, "postgresql", "SaveRecordsBatch")
+	defer span.End()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("开启 PostgreSQL 批量写入事务失败: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for start := 0; start < len(records); start += postgresInsertChunk {
+		chunk := records[start:min(start+postgresInsertChunk, len(records))]
+
+		var sb strings.Builder
+		sb.WriteString(`INSERT INTO probe_history (provider, service, channel, model, status, sub_status, http_code, latency, timestamp, cert_days_remaining,
+			dns_ms, connect_ms, tls_ms, ttfb_ms, attempts, ip_family) VALUES `)
+		args := make([]any, 0, len(chunk)*16)
+		argIndex := 1
+		for i, r := range chunk {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString("(")
+			for col := 0; col < 16; col++ {
+				if col > 0 {
+					sb.WriteString(", ")
+				}
+				fmt.Fprintf(&sb, "$%d", argIndex)
+				argIndex++
+			}
+			sb.WriteString(")")
+			args = append(args,
+				r.Provider, r.Service, r.Channel, r.Model, r.Status, string(r.SubStatus), r.HttpCode,
+				r.Latency, r.Timestamp, r.CertDaysRemaining, r.DNSMs, r.ConnectMs, r.TLSMs, r.TTFBMs, r.Attempts, r.IPFamily,
+			)
+		}
+		sb.WriteString(" RETURNING id")
+
+		// RETURNING 按 VALUES 顺序返回，逐行回填 ID
+		rows, err := tx.Query(ctx, sb.String(), args...)
+		if err != nil {
+			return fmt.Errorf("批量保存 PostgreSQL 记录失败: %w", err)
+		}
+		i := 0
+		for rows.Next() {
+			if i >= len(chunk) {
+				break
+			}
+			if err := rows.Scan(&chunk[i].ID); err != nil {
+				rows.Close()
+				return fmt.Errorf("读取批量写入 ID 失败: %w", err)
+			}
+			i++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("批量保存 PostgreSQL 记录失败: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("提交 PostgreSQL 批量写入事务失败: %w", err)
+	}
+
+	// 按 (监测项, 日期) 合并后累加每日汇总（失败仅记录警告，不影响原始记录写入）
+	for _, d := range groupDailyRollups(records) {
+		if err := s.applyDailyRollup(ctx, d); err != nil {
+			logger.Warn("storage", "累加 PostgreSQL 每日汇总失败", "provider", d.Key.Provider, "service", d.Key.Service, "error", err)
+		}
+	}
+	span.SetAttributes(attribute.Int("db.batch_size", len(records)))
 	return nil
 }
 
@@ -391,7 +628,8 @@ func (s *PostgresStorage) SaveRecord(record *ProbeRecord) error {
 // - 使用 CTE(keys) 承载入参列表，避免拼接 IN (...) 的多列比较复杂度
 // - 使用 DISTINCT ON + ORDER BY timestamp DESC 取每个 (provider,service,channel) 的最新一条
 func (s *PostgresStorage) GetLatestBatch(keys []MonitorKey) (map[MonitorKey]*ProbeRecord, error) {
-	ctx := s.effectiveCtx()
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "GetLatestBatch")
+	defer span.End()
 	result := make(map[MonitorKey]*ProbeRecord, len(keys))
 	if len(keys) == 0 {
 		return result, nil
@@ -412,7 +650,7 @@ func (s *PostgresStorage) GetLatestBatch(keys []MonitorKey) (map[MonitorKey]*Pro
 	b.WriteString(")\n")
 	b.WriteString(`
 SELECT DISTINCT ON (p.provider, p.service, p.channel, p.model)
-	p.id, p.provider, p.service, p.channel, p.model, p.status, p.sub_status, p.http_code, p.latency, p.timestamp
+	p.id, p.provider, p.service, p.channel, p.model, p.status, p.sub_status, p.http_code, p.latency, p.timestamp, p.cert_days_remaining
 FROM probe_history p
 JOIN keys k
 	ON p.provider = k.provider AND p.service = k.service AND p.channel = k.channel AND p.model = k.model
@@ -439,6 +677,7 @@ ORDER BY p.provider, p.service, p.channel, p.model, p.timestamp DESC, p.id DESC
 			&rec.HttpCode,
 			&rec.Latency,
 			&rec.Timestamp,
+			&rec.CertDaysRemaining,
 		); err != nil {
 			return nil, fmt.Errorf("扫描 PostgreSQL 最新记录失败: %w", err)
 		}
@@ -460,7 +699,8 @@ ORDER BY p.provider, p.service, p.channel, p.model, p.timestamp DESC, p.id DESC
 // - ORDER BY 按 (provider,service,channel,timestamp DESC) 输出，便于按 key 聚合且尽量利用索引顺序
 // - 最终对每个 key 的切片做 reverse，保证返回时间升序（与 GetHistory 一致）
 func (s *PostgresStorage) GetHistoryBatch(keys []MonitorKey, since time.Time) (map[MonitorKey][]*ProbeRecord, error) {
-	ctx := s.effectiveCtx()
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "GetHistoryBatch")
+	defer span.End()
 	result := make(map[MonitorKey][]*ProbeRecord, len(keys))
 	if len(keys) == 0 {
 		return result, nil
@@ -485,7 +725,8 @@ func (s *PostgresStorage) GetHistoryBatch(keys []MonitorKey, since time.Time) (m
 	b.WriteString(")\n")
 	fmt.Fprintf(&b, `
 SELECT
-	p.id, p.provider, p.service, p.channel, p.model, p.status, p.sub_status, p.http_code, p.latency, p.timestamp
+	p.id, p.provider, p.service, p.channel, p.model, p.status, p.sub_status, p.http_code, p.latency, p.timestamp,
+	p.dns_ms, p.connect_ms, p.tls_ms, p.ttfb_ms
 FROM probe_history p
 JOIN keys k
 	ON p.provider = k.provider AND p.service = k.service AND p.channel = k.channel AND p.model = k.model
@@ -513,6 +754,10 @@ ORDER BY p.provider, p.service, p.channel, p.model, p.timestamp DESC
 			&rec.HttpCode,
 			&rec.Latency,
 			&rec.Timestamp,
+			&rec.DNSMs,
+			&rec.ConnectMs,
+			&rec.TLSMs,
+			&rec.TTFBMs,
 		); err != nil {
 			return nil, fmt.Errorf("扫描 PostgreSQL 历史记录失败: %w", err)
 		}
@@ -533,13 +778,122 @@ ORDER BY p.provider, p.service, p.channel, p.model, p.timestamp DESC
 	return result, nil
 }
 
+// GetIPFamilyStats 按 ip_family 分组统计 [since, until) 时间范围内的探测结果（/api/admin/ip-families）
+func (s *PostgresStorage) GetIPFamilyStats(since, until int64) ([]*IPFamilyStat, error) {
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "GetIPFamilyStats")
+	defer span.End()
+	query := `
+		SELECT provider, service, channel, model, ip_family,
+			COUNT(*)::int,
+			COALESCE(SUM(CASE WHEN status = 1 THEN 1 ELSE 0 END), 0)::int,
+			COALESCE(SUM(CASE WHEN status = 2 THEN 1 ELSE 0 END), 0)::int,
+			COALESCE(SUM(CASE WHEN status = 0 THEN 1 ELSE 0 END), 0)::int,
+			COALESCE(SUM(CASE WHEN status IN (1, 2) THEN latency ELSE 0 END), 0)::bigint,
+			COALESCE(SUM(CASE WHEN status = 0 AND sub_status = 'ipv6_only_failure' THEN 1 ELSE 0 END), 0)::int
+		FROM probe_history
+		WHERE timestamp >= $1 AND timestamp < $2
+		GROUP BY provider, service, channel, model, ip_family
+		ORDER BY provider, service, channel, model, ip_family
+	`
+
+	rows, err := s.pool.Query(ctx, query, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("查询 PostgreSQL 地址族统计失败: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*IPFamilyStat
+	for rows.Next() {
+		st := &IPFamilyStat{}
+		if err := rows.Scan(
+			&st.Key.Provider,
+			&st.Key.Service,
+			&st.Key.Channel,
+			&st.Key.Model,
+			&st.Family,
+			&st.Total,
+			&st.Available,
+			&st.Degraded,
+			&st.Unavailable,
+			&st.LatencySum,
+			&st.IPv6OnlyFailures,
+		); err != nil {
+			return nil, fmt.Errorf("扫描 PostgreSQL 地址族统计失败: %w", err)
+		}
+		stats = append(stats, st)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代 PostgreSQL 地址族统计失败: %w", err)
+	}
+	return stats, nil
+}
+
+// GetHistoryPage 按 (timestamp, id) 升序分页获取单个监测项的原始记录（/api/export）
+func (s *PostgresStorage) GetHistoryPage(key MonitorKey, after HistoryCursor, until time.Time, limit int) ([]*ProbeRecord, error) {
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "GetHistoryPage")
+	defer span.End()
+	// 游标条件拆为 timestamp 范围 + 同秒 id 过滤，保证走 (provider, service, channel, model, timestamp) 索引
+	query := `
+		SELECT id, provider, service, channel, model, status, sub_status, http_code, latency, timestamp,
+			dns_ms, connect_ms, tls_ms, ttfb_ms, attempts, ip_family
+		FROM probe_history
+		WHERE provider = $1 AND service = $2 AND channel = $3 AND model = $4
+			AND timestamp >= $5 AND timestamp < $6
+			AND (timestamp > $5 OR id > $7)
+		ORDER BY timestamp, id
+		LIMIT $8
+	`
+
+	rows, err := s.pool.Query(ctx, query, key.Provider, key.Service, key.Channel, key.Model,
+		after.Timestamp, until.Unix(), after.ID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("分页查询PostgreSQL 历史记录失败: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]*ProbeRecord, 0, limit)
+	for rows.Next() {
+		rec := &ProbeRecord{}
+		var subStatusStr string
+		if err := rows.Scan(
+			&rec.ID,
+			&rec.Provider,
+			&rec.Service,
+			&rec.Channel,
+			&rec.Model,
+			&rec.Status,
+			&subStatusStr,
+			&rec.HttpCode,
+			&rec.Latency,
+			&rec.Timestamp,
+			&rec.DNSMs,
+			&rec.ConnectMs,
+			&rec.TLSMs,
+			&rec.TTFBMs,
+			&rec.Attempts,
+			&rec.IPFamily,
+		); err != nil {
+			return nil, fmt.Errorf("扫描PostgreSQL 历史记录失败: %w", err)
+		}
+		rec.SubStatus = SubStatus(subStatusStr)
+		records = append(records, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代PostgreSQL 历史记录失败: %w", err)
+	}
+	return records, nil
+}
+
 // GetTimelineAggBatch 批量获取多个监测项的时间轴 bucket 聚合结果（时间范围）
 //
 // 设计目标：
 // - 将 7d/30d 场景的聚合下推到 PostgreSQL，避免应用层拉取百万级原始记录
 // - 输出语义与 api.buildTimeline 完全一致（bucket 归属、边界排除、时段过滤、统计口径）
 func (s *PostgresStorage) GetTimelineAggBatch(keys []MonitorKey, since, endTime time.Time, bucketCount int, bucketWindow time.Duration, timeFilter *DailyTimeFilter) (map[MonitorKey][]AggBucketRow, error) {
-	ctx := s.effectiveCtx()
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "GetTimelineAggBatch")
+	defer span.End()
 	result := make(map[MonitorKey][]AggBucketRow, len(keys))
 	if len(keys) == 0 {
 		return result, nil
@@ -581,15 +935,22 @@ func (s *PostgresStorage) GetTimelineAggBatch(keys []MonitorKey, since, endTime
 	bucketCountArg := len(args) + 1
 	args = append(args, bucketCount)
 
-	// 时段过滤条件（UTC，左闭右开）
+	// 时段过滤条件（按 timeFilter.Timezone 的本地时钟，默认 UTC，左闭右开）
 	timeFilterCond := ""
 	if timeFilter != nil {
 		startMinArg := len(args) + 1
 		args = append(args, timeFilter.StartMinutes)
 		endMinArg := len(args) + 1
 		args = append(args, timeFilter.EndMinutes)
+		tz := timeFilter.Timezone
+		if tz == "" {
+			tz = "UTC"
+		}
+		tzArg := len(args) + 1
+		args = append(args, tz)
 
-		minutesExpr := "(EXTRACT(HOUR FROM timezone('UTC', to_timestamp(p.timestamp)))::int * 60 + EXTRACT(MINUTE FROM timezone('UTC', to_timestamp(p.timestamp)))::int)"
+		localExpr := fmt.Sprintf("timezone($%d::text, to_timestamp(p.timestamp))", tzArg)
+		minutesExpr := fmt.Sprintf("(EXTRACT(HOUR FROM %s)::int * 60 + EXTRACT(MINUTE FROM %s)::int)", localExpr, localExpr)
 		if timeFilter.CrossMidnight {
 			// 跨午夜： [start, 24:00) ∪ [00:00, end)
 			timeFilterCond = fmt.Sprintf(" AND (%s >= $%d OR %s < $%d)", minutesExpr, startMinArg, minutesExpr, endMinArg)
@@ -622,6 +983,10 @@ func (s *PostgresStorage) GetTimelineAggBatch(keys []MonitorKey, since, endTime
 		p.http_code,
 		p.latency,
 		p.timestamp,
+		p.dns_ms,
+		p.connect_ms,
+		p.tls_ms,
+		p.ttfb_ms,
 		($%d::int - 1 - (($%d::bigint - p.timestamp) / $%d::bigint))::int AS bucket_idx
 	FROM probe_history p
 	JOIN keys k
@@ -672,6 +1037,15 @@ SELECT
 	COALESCE(SUM(CASE WHEN f.latency > 0 THEN f.latency ELSE 0 END), 0)::bigint AS all_latency_sum,
 	COALESCE(SUM(CASE WHEN f.latency > 0 THEN 1 ELSE 0 END), 0)::int AS all_latency_count,
 
+	COALESCE(SUM(f.dns_ms), 0)::bigint AS dns_sum,
+	COUNT(f.dns_ms)::int AS dns_count,
+	COALESCE(SUM(f.connect_ms), 0)::bigint AS connect_sum,
+	COUNT(f.connect_ms)::int AS connect_count,
+	COALESCE(SUM(f.tls_ms), 0)::bigint AS tls_sum,
+	COUNT(f.tls_ms)::int AS tls_count,
+	COALESCE(SUM(f.ttfb_ms), 0)::bigint AS ttfb_sum,
+	COUNT(f.ttfb_ms)::int AS ttfb_count,
+
 	COALESCE(SUM(CASE WHEN f.status = 1 THEN 1 ELSE 0 END), 0)::int AS available,
 	COALESCE(SUM(CASE WHEN f.status = 2 THEN 1 ELSE 0 END), 0)::int AS degraded,
 	COALESCE(SUM(CASE WHEN f.status = 0 THEN 1 ELSE 0 END), 0)::int AS unavailable,
@@ -679,6 +1053,8 @@ SELECT
 
 	COALESCE(SUM(CASE WHEN f.status = 2 AND f.sub_status = 'slow_latency' THEN 1 ELSE 0 END), 0)::int AS slow_latency,
 	COALESCE(SUM(CASE WHEN f.sub_status = 'rate_limit' AND f.status IN (0,2) THEN 1 ELSE 0 END), 0)::int AS rate_limit,
+	COALESCE(SUM(CASE WHEN f.status = 2 AND f.sub_status = 'cert_expiring' THEN 1 ELSE 0 END), 0)::int AS cert_expiring,
+	COALESCE(SUM(CASE WHEN f.status = 2 AND f.sub_status = 'content_drift' THEN 1 ELSE 0 END), 0)::int AS content_drift,
 
 	COALESCE(SUM(CASE WHEN f.status = 0 AND f.sub_status = 'server_error' THEN 1 ELSE 0 END), 0)::int AS server_error,
 	COALESCE(SUM(CASE WHEN f.status = 0 AND f.sub_status = 'client_error' THEN 1 ELSE 0 END), 0)::int AS client_error,
@@ -686,6 +1062,8 @@ SELECT
 	COALESCE(SUM(CASE WHEN f.status = 0 AND f.sub_status = 'invalid_request' THEN 1 ELSE 0 END), 0)::int AS invalid_request,
 	COALESCE(SUM(CASE WHEN f.status = 0 AND f.sub_status = 'network_error' THEN 1 ELSE 0 END), 0)::int AS network_error,
 	COALESCE(SUM(CASE WHEN f.status = 0 AND f.sub_status = 'content_mismatch' THEN 1 ELSE 0 END), 0)::int AS content_mismatch,
+	COALESCE(SUM(CASE WHEN f.status = 0 AND f.sub_status = 'ipv6_only_failure' THEN 1 ELSE 0 END), 0)::int AS ipv6_only_failure,
+	COALESCE(SUM(CASE WHEN f.status = 0 AND f.sub_status = 'probe_panic' THEN 1 ELSE 0 END), 0)::int AS probe_panic,
 
 	COALESCE(h.breakdown, '{}'::jsonb) AS http_code_breakdown
 FROM filtered f
@@ -713,6 +1091,7 @@ ORDER BY
 			latencyCount                      int
 			allLatencySum                     int64
 			allLatencyCount                   int
+			phases                            PhaseLatencyAgg
 
 			available       int
 			degraded        int
@@ -720,12 +1099,16 @@ ORDER BY
 			missing         int
 			slowLatency     int
 			rateLimit       int
+			certExpiring    int
+			contentDrift    int
 			serverError     int
 			clientError     int
 			authError       int
 			invalidRequest  int
 			networkError    int
 			contentMismatch int
+			ipv6OnlyFailure int
+			probePanic      int
 
 			breakdownRaw []byte
 		)
@@ -742,18 +1125,30 @@ ORDER BY
 			&latencyCount,
 			&allLatencySum,
 			&allLatencyCount,
+			&phases.DNSSum,
+			&phases.DNSCount,
+			&phases.ConnectSum,
+			&phases.ConnectCount,
+			&phases.TLSSum,
+			&phases.TLSCount,
+			&phases.TTFBSum,
+			&phases.TTFBCount,
 			&available,
 			&degraded,
 			&unavailable,
 			&missing,
 			&slowLatency,
 			&rateLimit,
+			&certExpiring,
+			&contentDrift,
 			&serverError,
 			&clientError,
 			&authError,
 			&invalidRequest,
 			&networkError,
 			&contentMismatch,
+			&ipv6OnlyFailure,
+			&probePanic,
 			&breakdownRaw,
 		); err != nil {
 			return nil, fmt.Errorf("扫描 PostgreSQL 时间轴聚合结果失败: %w", err)
@@ -780,6 +1175,7 @@ ORDER BY
 			LatencyCount:    latencyCount,
 			AllLatencySum:   allLatencySum,
 			AllLatencyCount: allLatencyCount,
+			Phases:          phases,
 			StatusCounts: StatusCounts{
 				Available:         available,
 				Degraded:          degraded,
@@ -787,11 +1183,15 @@ ORDER BY
 				Missing:           missing,
 				SlowLatency:       slowLatency,
 				RateLimit:         rateLimit,
+				CertExpiring:      certExpiring,
+				ContentDrift:      contentDrift,
 				ServerError:       serverError,
 				ClientError:       clientError,
 				AuthError:         authError,
 				InvalidRequest:    invalidRequest,
 				NetworkError:      networkError,
+				IPv6OnlyFailure:   ipv6OnlyFailure,
+				ProbePanic:        probePanic,
 				ContentMismatch:   contentMismatch,
 				HttpCodeBreakdown: httpCodeBreakdown,
 			},
@@ -807,9 +1207,10 @@ ORDER BY
 
 // GetLatest 获取最新记录
 func (s *PostgresStorage) GetLatest(provider, service, channel, model string) (*ProbeRecord, error) {
-	ctx := s.effectiveCtx()
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "GetLatest")
+	defer span.End()
 	query := `
-		SELECT id, provider, service, channel, model, status, sub_status, http_code, latency, timestamp
+		SELECT id, provider, service, channel, model, status, sub_status, http_code, latency, timestamp, cert_days_remaining
 		FROM probe_history
 		WHERE provider = $1 AND service = $2 AND channel = $3 AND model = $4
 		ORDER BY timestamp DESC, id DESC
@@ -829,6 +1230,7 @@ func (s *PostgresStorage) GetLatest(provider, service, channel, model string) (*
 		&record.HttpCode,
 		&record.Latency,
 		&record.Timestamp,
+		&record.CertDaysRemaining,
 	)
 
 	if err != nil {
@@ -845,11 +1247,13 @@ func (s *PostgresStorage) GetLatest(provider, service, channel, model string) (*
 
 // GetHistory 获取历史记录
 func (s *PostgresStorage) GetHistory(provider, service, channel, model string, since time.Time) ([]*ProbeRecord, error) {
-	ctx := s.effectiveCtx()
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "GetHistory")
+	defer span.End()
 	// 使用 ORDER BY timestamp DESC 以利用索引（索引是 timestamp DESC）
 	// 返回前在 Go 代码中反转为时间升序
 	query := `
-		SELECT id, provider, service, channel, model, status, sub_status, http_code, latency, timestamp
+		SELECT id, provider, service, channel, model, status, sub_status, http_code, latency, timestamp,
+			dns_ms, connect_ms, tls_ms, ttfb_ms
 		FROM probe_history
 		WHERE provider = $1 AND service = $2 AND channel = $3 AND model = $4 AND timestamp >= $5
 		ORDER BY timestamp DESC
@@ -876,6 +1280,10 @@ func (s *PostgresStorage) GetHistory(provider, service, channel, model string, s
 			&record.HttpCode,
 			&record.Latency,
 			&record.Timestamp,
+			&record.DNSMs,
+			&record.ConnectMs,
+			&record.TLSMs,
+			&record.TTFBMs,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("扫描 PostgreSQL 记录失败: %w", err)
@@ -946,6 +1354,10 @@ func (s *PostgresStorage) initEventTables(ctx context.Context) error {
 	if err := s.ensureStatusEventsModelColumn(); err != nil {
 		return err
 	}
+	// 多命名空间：事件表补齐 namespace 列（旧数据归入默认命名空间）
+	if err := s.ensureStatusEventsNamespaceColumn(); err != nil {
+		return err
+	}
 
 	// 通道状态表（通道级状态机持久化，用于 events.mode=channel）
 	channelStatesSchema := `
@@ -969,6 +1381,8 @@ func (s *PostgresStorage) initEventTables(ctx context.Context) error {
 	eventsIndexSQL := `
 	CREATE INDEX IF NOT EXISTS idx_status_events_psc_id
 	ON status_events(provider, service, channel, id);
+	CREATE INDEX IF NOT EXISTS idx_status_events_ns_id
+	ON status_events(namespace, id);
 	`
 	if _, err := s.pool.Exec(ctx, eventsIndexSQL); err != nil {
 		return fmt.Errorf("创建 status_events 索引失败 (PostgreSQL): %w", err)
@@ -1117,9 +1531,19 @@ func (s *PostgresStorage) ensureStatusEventsModelColumn() error {
 	return nil
 }
 
+func (s *PostgresStorage) ensureStatusEventsNamespaceColumn() error {
+	ctx := s.effectiveCtx()
+	alterQuery := `ALTER TABLE status_events ADD COLUMN IF NOT EXISTS namespace TEXT NOT NULL DEFAULT ''`
+	if _, err := s.pool.Exec(ctx, alterQuery); err != nil {
+		return fmt.Errorf("添加 status_events.namespace 列失败: %w", err)
+	}
+	return nil
+}
+
 // GetServiceState 获取服务状态机持久化状态
 func (s *PostgresStorage) GetServiceState(provider, service, channel, model string) (*ServiceState, error) {
-	ctx := s.effectiveCtx()
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "GetServiceState")
+	defer span.End()
 	query := `
 		SELECT provider, service, channel, model, stable_available, streak_count, streak_status, last_record_id, last_timestamp
 		FROM service_states
@@ -1157,7 +1581,8 @@ func (s *PostgresStorage) GetServiceState(provider, service, channel, model stri
 
 // UpsertServiceState 写入或更新服务状态机持久化状态
 func (s *PostgresStorage) UpsertServiceState(state *ServiceState) error {
-	ctx := s.effectiveCtx()
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "UpsertServiceState")
+	defer span.End()
 	query := `
 		INSERT INTO service_states (provider, service, channel, model, stable_available, streak_count, streak_status, last_record_id, last_timestamp)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
@@ -1190,16 +1615,32 @@ func (s *PostgresStorage) UpsertServiceState(state *ServiceState) error {
 
 // SaveStatusEvent 保存状态变更事件
 func (s *PostgresStorage) SaveStatusEvent(event *StatusEvent) error {
-	ctx := s.effectiveCtx()
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "SaveStatusEvent")
+	defer span.End()
 
-	query := `
-		INSERT INTO status_events (provider, service, channel, model, event_type, from_status, to_status, trigger_record_id, observed_at, created_at, meta)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-		ON CONFLICT (provider, service, channel, event_type, trigger_record_id) DO NOTHING
-		RETURNING id
-	`
+	err := s.pool.QueryRow(ctx, postgresInsertStatusEventSQL, postgresStatusEventArgs(event)...).Scan(&event.ID)
+	if err != nil {
+		// ON CONFLICT DO NOTHING 不返回行，这是幂等处理
+		if err.Error() == "no rows in result set" {
+			return nil // 重复事件，视为成功
+		}
+		return fmt.Errorf("保存状态事件失败 (PostgreSQL): %w", err)
+	}
 
-	err := s.pool.QueryRow(ctx, query,
+	return nil
+}
+
+// postgresInsertStatusEventSQL 写入状态事件（参数见 postgresStatusEventArgs），重复事件不返回行
+const postgresInsertStatusEventSQL = `
+	INSERT INTO status_events (provider, service, channel, model, event_type, from_status, to_status, trigger_record_id, observed_at, created_at, meta, namespace)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	ON CONFLICT (provider, service, channel, event_type, trigger_record_id) DO NOTHING
+	RETURNING id
+`
+
+// postgresStatusEventArgs 构造写入状态事件的参数
+func postgresStatusEventArgs(event *StatusEvent) []any {
+	return []any{
 		event.Provider,
 		event.Service,
 		event.Channel,
@@ -1211,22 +1652,26 @@ func (s *PostgresStorage) SaveStatusEvent(event *StatusEvent) error {
 		event.ObservedAt,
 		event.CreatedAt,
 		event.Meta,
-	).Scan(&event.ID)
-
-	if err != nil {
-		// ON CONFLICT DO NOTHING 不返回行，这是幂等处理
-		if err.Error() == "no rows in result set" {
-			return nil // 重复事件，视为成功
-		}
-		return fmt.Errorf("保存状态事件失败 (PostgreSQL): %w", err)
+		event.Namespace,
 	}
-
-	return nil
 }
 
 // GetStatusEvents 查询状态变更事件列表
 func (s *PostgresStorage) GetStatusEvents(sinceID int64, limit int, filters *EventFilters) ([]*StatusEvent, error) {
-	ctx := s.effectiveCtx()
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "GetStatusEvents")
+	defer span.End()
+	return s.queryStatusEvents(ctx, sinceID, limit, filters, false)
+}
+
+// GetRecentStatusEvents 查询最近的状态变更事件（按 id 倒序）
+func (s *PostgresStorage) GetRecentStatusEvents(limit int, filters *EventFilters) ([]*StatusEvent, error) {
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "GetRecentStatusEvents")
+	defer span.End()
+	return s.queryStatusEvents(ctx, 0, limit, filters, true)
+}
+
+// queryStatusEvents 按游标与过滤条件查询状态事件，desc 为 true 时按 id 倒序
+func (s *PostgresStorage) queryStatusEvents(ctx context.Context, sinceID int64, limit int, filters *EventFilters, desc bool) ([]*StatusEvent, error) {
 
 	var conditions []string
 	var args []any
@@ -1239,6 +1684,11 @@ func (s *PostgresStorage) GetStatusEvents(sinceID int64, limit int, filters *Eve
 
 	// 可选过滤条件
 	if filters != nil {
+		if filters.Namespace != nil {
+			conditions = append(conditions, fmt.Sprintf("namespace = $%d", argIndex))
+			args = append(args, *filters.Namespace)
+			argIndex++
+		}
 		if filters.Provider != "" {
 			conditions = append(conditions, fmt.Sprintf("provider = $%d", argIndex))
 			args = append(args, filters.Provider)
@@ -1273,13 +1723,18 @@ func (s *PostgresStorage) GetStatusEvents(sinceID int64, limit int, filters *Eve
 		limit = 500
 	}
 
+	order := "ASC"
+	if desc {
+		order = "DESC"
+	}
+
 	query := fmt.Sprintf(`
-		SELECT id, provider, service, channel, model, event_type, from_status, to_status, trigger_record_id, observed_at, created_at, meta
+		SELECT id, namespace, provider, service, channel, model, event_type, from_status, to_status, trigger_record_id, observed_at, created_at, meta
 		FROM status_events
 		WHERE %s
-		ORDER BY id ASC
+		ORDER BY id %s
 		LIMIT $%d
-	`, strings.Join(conditions, " AND "), argIndex)
+	`, strings.Join(conditions, " AND "), order, argIndex)
 	args = append(args, limit)
 
 	rows, err := s.pool.Query(ctx, query, args...)
@@ -1296,6 +1751,7 @@ func (s *PostgresStorage) GetStatusEvents(sinceID int64, limit int, filters *Eve
 
 		err := rows.Scan(
 			&event.ID,
+			&event.Namespace,
 			&event.Provider,
 			&event.Service,
 			&event.Channel,
@@ -1472,6 +1928,12 @@ const archiveLockNamespace int32 = 23456
 // ErrArchiveAdvisoryLockNotAcquired 表示未获取归档 advisory lock（其他实例正在归档）
 var ErrArchiveAdvisoryLockNotAcquired = errors.New("未获取归档 advisory lock")
 
+// maintenanceLockID PostgreSQL advisory lock ID，用于数据库维护任务多实例互斥
+const maintenanceLockID = 34567
+
+// ErrMaintenanceLockNotAcquired 表示未获取维护 advisory lock（其他实例正在维护）
+var ErrMaintenanceLockNotAcquired = errors.New("未获取维护 advisory lock")
+
 // PurgeOldRecords 清理指定时间之前的历史记录
 // PostgreSQL 实现：使用 advisory lock 确保多实例互斥
 func (s *PostgresStorage) PurgeOldRecords(ctx context.Context, before time.Time, batchSize int) (int64, error) {
@@ -1577,3 +2039,2135 @@ func (s *PostgresStorage) ExportDayToWriter(ctx context.Context, dayStart, dayEn
 
 	return tag.RowsAffected(), nil
 }
+
+// ===== Token 用量统计相关方法 =====
+
+// initUsageTable 初始化用量统计表
+func (s *PostgresStorage) initUsageTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS usage_daily (
+		provider TEXT NOT NULL,
+		service TEXT NOT NULL,
+		channel TEXT NOT NULL DEFAULT '',
+		model TEXT NOT NULL DEFAULT '',
+		day TEXT NOT NULL,
+		tokens BIGINT NOT NULL DEFAULT 0,
+		cost DOUBLE PRECISION NOT NULL DEFAULT 0,
+		probes INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (provider, service, channel, model, day)
+	);
+	`
+	if _, err := s.pool.Exec(ctx, schema); err != nil {
+		return fmt.Errorf("创建 usage_daily 表失败: %w", err)
+	}
+	if _, err := s.pool.Exec(ctx, `CREATE INDEX IF NOT EXISTS idx_usage_daily_day ON usage_daily (day)`); err != nil {
+		return fmt.Errorf("创建 usage_daily 索引失败: %w", err)
+	}
+	return nil
+}
+
+// AddUsage 累加指定监测项某日的用量
+func (s *PostgresStorage) AddUsage(key MonitorKey, day string, tokens int64, cost float64) error {
+	ctx := s.effectiveCtx()
+	query := `
+		INSERT INTO usage_daily (provider, service, channel, model, day, tokens, cost, probes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, 1)
+		ON CONFLICT (provider, service, channel, model, day) DO UPDATE SET
+			tokens = usage_daily.tokens + EXCLUDED.tokens,
+			cost = usage_daily.cost + EXCLUDED.cost,
+			probes = usage_daily.probes + 1
+	`
+	if _, err := s.pool.Exec(ctx, query, key.Provider, key.Service, key.Channel, key.Model, day, tokens, cost); err != nil {
+		return fmt.Errorf("累加 PostgreSQL 用量失败: %w", err)
+	}
+	return nil
+}
+
+// GetUsage 查询日期范围内的用量汇总
+func (s *PostgresStorage) GetUsage(sinceDay, untilDay string) ([]*UsageRecord, error) {
+	ctx := s.effectiveCtx()
+	query := `
+		SELECT provider, service, channel, model, day, tokens, cost, probes
+		FROM usage_daily
+		WHERE day >= $1 AND day <= $2
+		ORDER BY provider, service, channel, model, day
+	`
+
+	rows, err := s.pool.Query(ctx, query, sinceDay, untilDay)
+	if err != nil {
+		return nil, fmt.Errorf("查询 PostgreSQL 用量失败: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*UsageRecord
+	for rows.Next() {
+		var r UsageRecord
+		if err := rows.Scan(&r.Provider, &r.Service, &r.Channel, &r.Model, &r.Day, &r.Tokens, &r.Cost, &r.Probes); err != nil {
+			return nil, fmt.Errorf("扫描 PostgreSQL 用量记录失败: %w", err)
+		}
+		records = append(records, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代 PostgreSQL 用量记录失败: %w", err)
+	}
+	return records, nil
+}
+
+// initDailyRollupTable 初始化每日可用性汇总表
+// 首次建表时从 probe_history 回填已有数据，保证升级后热力图立即可用
+func (s *PostgresStorage) initDailyRollupTable(ctx context.Context) error {
+	var exists bool
+	if err := s.pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = 'probe_daily')`,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("检查 probe_daily 表失败: %w", err)
+	}
+
+	schema := `
+	CREATE TABLE IF NOT EXISTS probe_daily (
+		provider TEXT NOT NULL,
+		service TEXT NOT NULL,
+		channel TEXT NOT NULL DEFAULT '',
+		model TEXT NOT NULL DEFAULT '',
+		day TEXT NOT NULL,
+		total INTEGER NOT NULL DEFAULT 0,
+		green INTEGER NOT NULL DEFAULT 0,
+		yellow INTEGER NOT NULL DEFAULT 0,
+		red INTEGER NOT NULL DEFAULT 0,
+		latency_sum BIGINT NOT NULL DEFAULT 0,
+		latency_count INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (provider, service, channel, model, day)
+	);
+	`
+	if _, err := s.pool.Exec(ctx, schema); err != nil {
+		return fmt.Errorf("创建 probe_daily 表失败: %w", err)
+	}
+	if exists {
+		return s.ensureDailyRollupLatencyColumns(ctx)
+	}
+
+	backfill := `
+		INSERT INTO probe_daily (provider, service, channel, model, day, total, green, yellow, red, latency_sum, latency_count)
+		SELECT provider, service, channel, model,
+			to_char(to_timestamp(timestamp) AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = 1),
+			COUNT(*) FILTER (WHERE status = 2),
+			COUNT(*) FILTER (WHERE status = 0),
+			COALESCE(SUM(latency) FILTER (WHERE status > 0), 0),
+			COUNT(*) FILTER (WHERE status > 0)
+		FROM probe_history
+		GROUP BY provider, service, channel, model, day
+	`
+	tag, err := s.pool.Exec(ctx, backfill)
+	if err != nil {
+		return fmt.Errorf("回填 probe_daily 失败: %w", err)
+	}
+	if n := tag.RowsAffected(); n > 0 {
+		logger.Info("storage", "已从 probe_history 回填每日汇总", "rows", n)
+	}
+	return nil
+}
+
+// ensureDailyRollupLatencyColumns 为旧版 probe_daily 表补充延迟聚合列（旧汇总行保持为 0）
+func (s *PostgresStorage) ensureDailyRollupLatencyColumns(ctx context.Context) error {
+	alter := `
+		ALTER TABLE probe_daily
+			ADD COLUMN IF NOT EXISTS latency_sum BIGINT NOT NULL DEFAULT 0,
+			ADD COLUMN IF NOT EXISTS latency_count INTEGER NOT NULL DEFAULT 0
+	`
+	if _, err := s.pool.Exec(ctx, alter); err != nil {
+		return fmt.Errorf("为 probe_daily 添加延迟聚合列失败: %w", err)
+	}
+	return nil
+}
+
+// addDailyRollup 将单条探测记录累加到每日汇总
+func (s *PostgresStorage) addDailyRollup(ctx context.Context, record *ProbeRecord) error {
+	return s.applyDailyRollup(ctx, groupDailyRollups([]*ProbeRecord{record})[0])
+}
+
+// applyDailyRollup 将汇总增量累加到每日汇总
+func (s *PostgresStorage) applyDailyRollup(ctx context.Context, d dailyRollupDelta) error {
+	query := `
+		INSERT INTO probe_daily (provider, service, channel, model, day, total, green, yellow, red, latency_sum, latency_count)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (provider, service, channel, model, day) DO UPDATE SET
+			total = probe_daily.total + EXCLUDED.total,
+			green = probe_daily.green + EXCLUDED.green,
+			yellow = probe_daily.yellow + EXCLUDED.yellow,
+			red = probe_daily.red + EXCLUDED.red,
+			latency_sum = probe_daily.latency_sum + EXCLUDED.latency_sum,
+			latency_count = probe_daily.latency_count + EXCLUDED.latency_count
+	`
+	_, err := s.pool.Exec(ctx, query,
+		d.Key.Provider, d.Key.Service, d.Key.Channel, d.Key.Model,
+		d.Day, d.Total, d.Green, d.Yellow, d.Red, d.LatencySum, d.LatencyCount,
+	)
+	return err
+}
+
+// GetDailyRollupBatch 批量查询每日可用性汇总
+func (s *PostgresStorage) GetDailyRollupBatch(keys []MonitorKey, sinceDay, untilDay string) (map[MonitorKey][]DailyRollupRow, error) {
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "GetDailyRollupBatch")
+	defer span.End()
+	result := make(map[MonitorKey][]DailyRollupRow, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	var b strings.Builder
+	args := make([]any, 0, len(keys)*4+2)
+
+	b.WriteString("WITH keys(provider, service, channel, model) AS (VALUES ")
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		base := i*4 + 1
+		fmt.Fprintf(&b, "($%d,$%d,$%d,$%d)", base, base+1, base+2, base+3)
+		args = append(args, k.Provider, k.Service, k.Channel, k.Model)
+	}
+	fmt.Fprintf(&b, `)
+SELECT d.provider, d.service, d.channel, d.model, d.day, d.total, d.green, d.yellow, d.red, d.latency_sum, d.latency_count
+FROM probe_daily d
+JOIN keys k
+	ON d.provider = k.provider AND d.service = k.service AND d.channel = k.channel AND d.model = k.model
+WHERE d.day >= $%d AND d.day <= $%d
+ORDER BY d.provider, d.service, d.channel, d.model, d.day
+`, len(keys)*4+1, len(keys)*4+2)
+	args = append(args, sinceDay, untilDay)
+
+	rows, err := s.pool.Query(ctx, b.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("批量查询 PostgreSQL 每日汇总失败: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key MonitorKey
+		var row DailyRollupRow
+		if err := rows.Scan(&key.Provider, &key.Service, &key.Channel, &key.Model, &row.Day, &row.Total, &row.Green, &row.Yellow, &row.Red, &row.LatencySum, &row.LatencyCount); err != nil {
+			return nil, fmt.Errorf("扫描 PostgreSQL 每日汇总失败: %w", err)
+		}
+		result[key] = append(result[key], row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代 PostgreSQL 每日汇总失败: %w", err)
+	}
+	return result, nil
+}
+
+// ===== 自助测试分享结果相关方法 =====
+
+// initSelfTestResultTable 初始化自助测试分享结果表
+func (s *PostgresStorage) initSelfTestResultTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS selftest_results (
+		id TEXT PRIMARY KEY,
+		test_type TEXT NOT NULL,
+		api_url TEXT NOT NULL,
+		status TEXT NOT NULL,
+		probe_status INTEGER NOT NULL DEFAULT 0,
+		sub_status TEXT NOT NULL DEFAULT '',
+		http_code INTEGER NOT NULL DEFAULT 0,
+		latency INTEGER NOT NULL DEFAULT 0,
+		error_message TEXT NOT NULL DEFAULT '',
+		created_at BIGINT NOT NULL,
+		finished_at BIGINT NOT NULL,
+		expires_at BIGINT NOT NULL
+	);
+	`
+	if _, err := s.pool.Exec(ctx, schema); err != nil {
+		return fmt.Errorf("创建 selftest_results 表失败: %w", err)
+	}
+	if _, err := s.pool.Exec(ctx, `CREATE INDEX IF NOT EXISTS idx_selftest_results_expires ON selftest_results (expires_at)`); err != nil {
+		return fmt.Errorf("创建 selftest_results 索引失败: %w", err)
+	}
+	return nil
+}
+
+// SaveSelfTestResult 保存自助测试分享结果
+func (s *PostgresStorage) SaveSelfTestResult(r *SelfTestResult) error {
+	ctx := s.effectiveCtx()
+	query := `
+		INSERT INTO selftest_results (id, test_type, api_url, status, probe_status, sub_status, http_code, latency, error_message, created_at, finished_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (id) DO UPDATE SET expires_at = EXCLUDED.expires_at
+	`
+	if _, err := s.pool.Exec(ctx, query,
+		r.ID, r.TestType, r.APIURL, r.Status, r.ProbeStatus, r.SubStatus,
+		r.HTTPCode, r.Latency, r.ErrorMessage, r.CreatedAt, r.FinishedAt, r.ExpiresAt,
+	); err != nil {
+		return fmt.Errorf("保存 PostgreSQL 自助测试分享结果失败: %w", err)
+	}
+	return nil
+}
+
+// GetSelfTestResult 按任务 ID 查询自助测试分享结果
+func (s *PostgresStorage) GetSelfTestResult(id string) (*SelfTestResult, error) {
+	ctx := s.effectiveCtx()
+	query := `
+		SELECT id, test_type, api_url, status, probe_status, sub_status, http_code, latency, error_message, created_at, finished_at, expires_at
+		FROM selftest_results
+		WHERE id = $1
+	`
+	var r SelfTestResult
+	err := s.pool.QueryRow(ctx, query, id).Scan(
+		&r.ID, &r.TestType, &r.APIURL, &r.Status, &r.ProbeStatus, &r.SubStatus,
+		&r.HTTPCode, &r.Latency, &r.ErrorMessage, &r.CreatedAt, &r.FinishedAt, &r.ExpiresAt,
+	)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查询 PostgreSQL 自助测试分享结果失败: %w", err)
+	}
+	return &r, nil
+}
+
+// PurgeExpiredSelfTestResults 删除已到期的自助测试分享结果
+func (s *PostgresStorage) PurgeExpiredSelfTestResults(now int64) (int64, error) {
+	ctx := s.effectiveCtx()
+	tag, err := s.pool.Exec(ctx, `DELETE FROM selftest_results WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, fmt.Errorf("清理 PostgreSQL 自助测试分享结果失败: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ===== 审计日志相关方法 =====
+
+// initAuditTable 初始化审计日志表
+func (s *PostgresStorage) initAuditTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS audit_log (
+		id BIGSERIAL PRIMARY KEY,
+		timestamp BIGINT NOT NULL,
+		actor TEXT NOT NULL,
+		action TEXT NOT NULL,
+		target TEXT NOT NULL DEFAULT '',
+		detail TEXT NOT NULL DEFAULT '',
+		ip TEXT NOT NULL DEFAULT '',
+		result TEXT NOT NULL DEFAULT 'success'
+	);
+	`
+	if _, err := s.pool.Exec(ctx, schema); err != nil {
+		return fmt.Errorf("创建 audit_log 表失败: %w", err)
+	}
+	if _, err := s.pool.Exec(ctx, `CREATE INDEX IF NOT EXISTS idx_audit_log_timestamp ON audit_log (timestamp)`); err != nil {
+		return fmt.Errorf("创建 audit_log 索引失败: %w", err)
+	}
+	return nil
+}
+
+// AddAuditEntry 写入一条审计日志
+func (s *PostgresStorage) AddAuditEntry(entry *AuditEntry) error {
+	ctx := s.effectiveCtx()
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO audit_log (timestamp, actor, action, target, detail, ip, result)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, entry.Timestamp, entry.Actor, entry.Action, entry.Target, entry.Detail, entry.IP, entry.Result).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("写入 PostgreSQL 审计日志失败: %w", err)
+	}
+	return nil
+}
+
+// GetAuditEntries 按条件查询审计日志
+func (s *PostgresStorage) GetAuditEntries(filter AuditFilter) ([]*AuditEntry, error) {
+	ctx := s.effectiveCtx()
+
+	conditions := []string{"1 = 1"}
+	var args []any
+	argIndex := 1
+	if filter.Actor != "" {
+		conditions = append(conditions, fmt.Sprintf("actor = $%d", argIndex))
+		args = append(args, filter.Actor)
+		argIndex++
+	}
+	if filter.Action != "" {
+		if strings.HasSuffix(filter.Action, ".") {
+			conditions = append(conditions, fmt.Sprintf(`action LIKE $%d ESCAPE '\'`, argIndex))
+			args = append(args, escapeLikePattern(filter.Action)+"%")
+		} else {
+			conditions = append(conditions, fmt.Sprintf("action = $%d", argIndex))
+			args = append(args, filter.Action)
+		}
+		argIndex++
+	}
+	if filter.Since > 0 {
+		conditions = append(conditions, fmt.Sprintf("timestamp >= $%d", argIndex))
+		args = append(args, filter.Since)
+		argIndex++
+	}
+	if filter.Until > 0 {
+		conditions = append(conditions, fmt.Sprintf("timestamp <= $%d", argIndex))
+		args = append(args, filter.Until)
+		argIndex++
+	}
+	if filter.BeforeID > 0 {
+		conditions = append(conditions, fmt.Sprintf("id < $%d", argIndex))
+		args = append(args, filter.BeforeID)
+		argIndex++
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, timestamp, actor, action, target, detail, ip, result
+		FROM audit_log
+		WHERE %s
+		ORDER BY id DESC
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), argIndex)
+	args = append(args, limit)
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询 PostgreSQL 审计日志失败: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		if err := rows.Scan(&e.ID, &e.Timestamp, &e.Actor, &e.Action, &e.Target, &e.Detail, &e.IP, &e.Result); err != nil {
+			return nil, fmt.Errorf("扫描 PostgreSQL 审计日志失败: %w", err)
+		}
+		entries = append(entries, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代 PostgreSQL 审计日志失败: %w", err)
+	}
+	return entries, nil
+}
+
+// PurgeAuditEntries 删除过期的审计日志
+func (s *PostgresStorage) PurgeAuditEntries(before int64) (int64, error) {
+	ctx := s.effectiveCtx()
+	tag, err := s.pool.Exec(ctx, `DELETE FROM audit_log WHERE timestamp < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("清理 PostgreSQL 审计日志失败: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ===== 探测调试捕获相关方法 =====
+
+// initProbeDebugTable 初始化探测调试快照表
+func (s *PostgresStorage) initProbeDebugTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS probe_debug (
+		id BIGSERIAL PRIMARY KEY,
+		provider TEXT NOT NULL,
+		service TEXT NOT NULL,
+		channel TEXT NOT NULL DEFAULT '',
+		model TEXT NOT NULL DEFAULT '',
+		timestamp BIGINT NOT NULL,
+		status INTEGER NOT NULL,
+		sub_status TEXT NOT NULL DEFAULT '',
+		http_code INTEGER NOT NULL DEFAULT 0,
+		latency INTEGER NOT NULL DEFAULT 0,
+		attempts INTEGER NOT NULL DEFAULT 1,
+		method TEXT NOT NULL DEFAULT '',
+		url TEXT NOT NULL DEFAULT '',
+		request_headers TEXT NOT NULL DEFAULT '{}',
+		response_headers TEXT NOT NULL DEFAULT '{}',
+		response_body TEXT NOT NULL DEFAULT '',
+		body_truncated BOOLEAN NOT NULL DEFAULT FALSE,
+		error TEXT NOT NULL DEFAULT '',
+		dns_ms INTEGER NOT NULL DEFAULT -1,
+		connect_ms INTEGER NOT NULL DEFAULT -1,
+		tls_ms INTEGER NOT NULL DEFAULT -1,
+		ttfb_ms INTEGER NOT NULL DEFAULT -1,
+		expires_at BIGINT NOT NULL
+	);
+	`
+	if _, err := s.pool.Exec(ctx, schema); err != nil {
+		return fmt.Errorf("创建 probe_debug 表失败: %w", err)
+	}
+	indexes := []string{
+		`CREATE INDEX IF NOT EXISTS idx_probe_debug_psc_id ON probe_debug (provider, service, channel, id)`,
+		`CREATE INDEX IF NOT EXISTS idx_probe_debug_expires_at ON probe_debug (expires_at)`,
+	}
+	for _, stmt := range indexes {
+		if _, err := s.pool.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("创建 probe_debug 索引失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// SaveProbeDebug 写入一条探测调试快照
+func (s *PostgresStorage) SaveProbeDebug(entry *ProbeDebugEntry) error {
+	ctx := s.effectiveCtx()
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO probe_debug (
+			provider, service, channel, model, timestamp,
+			status, sub_status, http_code, latency, attempts,
+			method, url, request_headers, response_headers, response_body, body_truncated, error,
+			dns_ms, connect_ms, tls_ms, ttfb_ms, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
+		RETURNING id
+	`, entry.Provider, entry.Service, entry.Channel, entry.Model, entry.Timestamp,
+		entry.Status, string(entry.SubStatus), entry.HttpCode, entry.Latency, entry.Attempts,
+		entry.Method, entry.URL, entry.RequestHeaders, entry.ResponseHeaders, entry.ResponseBody, entry.BodyTruncated, entry.Error,
+		entry.DNSMs, entry.ConnectMs, entry.TLSMs, entry.TTFBMs, entry.ExpiresAt).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("写入 PostgreSQL 探测调试快照失败: %w", err)
+	}
+	return nil
+}
+
+// GetProbeDebugEntries 按条件查询探测调试快照
+func (s *PostgresStorage) GetProbeDebugEntries(filter ProbeDebugFilter) ([]*ProbeDebugEntry, error) {
+	ctx := s.effectiveCtx()
+
+	conditions := []string{"1 = 1"}
+	var args []any
+	argIndex := 1
+	for _, f := range []struct {
+		column string
+		value  string
+	}{
+		{"provider", filter.Provider},
+		{"service", filter.Service},
+		{"channel", filter.Channel},
+		{"model", filter.Model},
+	} {
+		if f.value != "" {
+			conditions = append(conditions, fmt.Sprintf("%s = $%d", f.column, argIndex))
+			args = append(args, f.value)
+			argIndex++
+		}
+	}
+	if filter.BeforeID > 0 {
+		conditions = append(conditions, fmt.Sprintf("id < $%d", argIndex))
+		args = append(args, filter.BeforeID)
+		argIndex++
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, provider, service, channel, model, timestamp,
+			status, sub_status, http_code, latency, attempts,
+			method, url, request_headers, response_headers, response_body, body_truncated, error,
+			dns_ms, connect_ms, tls_ms, ttfb_ms, expires_at
+		FROM probe_debug
+		WHERE %s
+		ORDER BY id DESC
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), argIndex)
+	args = append(args, limit)
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询 PostgreSQL 探测调试快照失败: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*ProbeDebugEntry
+	for rows.Next() {
+		var e ProbeDebugEntry
+		var subStatus string
+		if err := rows.Scan(
+			&e.ID, &e.Provider, &e.Service, &e.Channel, &e.Model, &e.Timestamp,
+			&e.Status, &subStatus, &e.HttpCode, &e.Latency, &e.Attempts,
+			&e.Method, &e.URL, &e.RequestHeaders, &e.ResponseHeaders, &e.ResponseBody, &e.BodyTruncated, &e.Error,
+			&e.DNSMs, &e.ConnectMs, &e.TLSMs, &e.TTFBMs, &e.ExpiresAt,
+		); err != nil {
+			return nil, fmt.Errorf("扫描 PostgreSQL 探测调试快照失败: %w", err)
+		}
+		e.SubStatus = SubStatus(subStatus)
+		entries = append(entries, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代 PostgreSQL 探测调试快照失败: %w", err)
+	}
+	return entries, nil
+}
+
+// PurgeExpiredProbeDebug 删除过期的探测调试快照
+func (s *PostgresStorage) PurgeExpiredProbeDebug(now int64) (int64, error) {
+	ctx := s.effectiveCtx()
+	tag, err := s.pool.Exec(ctx, `DELETE FROM probe_debug WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, fmt.Errorf("清理 PostgreSQL 探测调试快照失败: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ===== 事件 Webhook 死信相关方法 =====
+
+// initWebhookDeadLetterTable 初始化事件 Webhook 死信表
+func (s *PostgresStorage) initWebhookDeadLetterTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+		id BIGSERIAL PRIMARY KEY,
+		url TEXT NOT NULL,
+		event_id BIGINT NOT NULL,
+		event_type TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		attempts INTEGER NOT NULL,
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at BIGINT NOT NULL
+	);
+	`
+	if _, err := s.pool.Exec(ctx, schema); err != nil {
+		return fmt.Errorf("创建 webhook_dead_letters 表失败: %w", err)
+	}
+	return nil
+}
+
+// SaveWebhookDeadLetter 写入一条事件 Webhook 死信
+func (s *PostgresStorage) SaveWebhookDeadLetter(dl *WebhookDeadLetter) error {
+	ctx := s.effectiveCtx()
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO webhook_dead_letters (url, event_id, event_type, payload, attempts, last_error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, dl.URL, dl.EventID, string(dl.EventType), dl.Payload, dl.Attempts, dl.LastError, dl.CreatedAt).Scan(&dl.ID)
+	if err != nil {
+		return fmt.Errorf("写入 PostgreSQL Webhook 死信失败: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookDeadLetters 按条件查询事件 Webhook 死信
+func (s *PostgresStorage) GetWebhookDeadLetters(filter WebhookDeadLetterFilter) ([]*WebhookDeadLetter, error) {
+	ctx := s.effectiveCtx()
+
+	conditions := []string{"1 = 1"}
+	var args []any
+	argIndex := 1
+	if filter.URL != "" {
+		conditions = append(conditions, fmt.Sprintf("url = $%d", argIndex))
+		args = append(args, filter.URL)
+		argIndex++
+	}
+	if filter.BeforeID > 0 {
+		conditions = append(conditions, fmt.Sprintf("id < $%d", argIndex))
+		args = append(args, filter.BeforeID)
+		argIndex++
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, url, event_id, event_type, payload, attempts, last_error, created_at
+		FROM webhook_dead_letters
+		WHERE %s
+		ORDER BY id DESC
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), argIndex)
+	args = append(args, limit)
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询 PostgreSQL Webhook 死信失败: %w", err)
+	}
+	defer rows.Close()
+
+	var letters []*WebhookDeadLetter
+	for rows.Next() {
+		var dl WebhookDeadLetter
+		var eventType string
+		if err := rows.Scan(&dl.ID, &dl.URL, &dl.EventID, &eventType, &dl.Payload, &dl.Attempts, &dl.LastError, &dl.CreatedAt); err != nil {
+			return nil, fmt.Errorf("扫描 PostgreSQL Webhook 死信失败: %w", err)
+		}
+		dl.EventType = EventType(eventType)
+		letters = append(letters, &dl)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代 PostgreSQL Webhook 死信失败: %w", err)
+	}
+	return letters, nil
+}
+
+// ===== 事件发件箱相关方法 =====
+
+// initEventOutboxTable 初始化事件发件箱表（done_at = 0 表示未完成）
+func (s *PostgresStorage) initEventOutboxTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS event_outbox (
+		id BIGSERIAL PRIMARY KEY,
+		event_id BIGINT NOT NULL,
+		destination TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at BIGINT NOT NULL,
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at BIGINT NOT NULL,
+		done_at BIGINT NOT NULL DEFAULT 0
+	);
+	CREATE INDEX IF NOT EXISTS idx_event_outbox_pending ON event_outbox (next_attempt_at) WHERE done_at = 0;
+	CREATE INDEX IF NOT EXISTS idx_event_outbox_done ON event_outbox (done_at) WHERE done_at > 0;
+	`
+	if _, err := s.pool.Exec(ctx, schema); err != nil {
+		return fmt.Errorf("创建 event_outbox 表失败: %w", err)
+	}
+	return nil
+}
+
+// SaveStatusEventWithOutbox 在同一事务中保存事件并写入待投递记录
+func (s *PostgresStorage) SaveStatusEventWithOutbox(event *StatusEvent, destinations []string) error {
+	if len(destinations) == 0 {
+		return s.SaveStatusEvent(event)
+	}
+
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "SaveStatusEventWithOutbox")
+	defer span.End()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("开始事务失败 (PostgreSQL): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var id int64
+	if err := tx.QueryRow(ctx, postgresInsertStatusEventSQL, postgresStatusEventArgs(event)...).Scan(&id); err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil // 重复事件，视为成功（已在首次保存时写入待投递记录）
+		}
+		return fmt.Errorf("保存状态事件失败 (PostgreSQL): %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO event_outbox (event_id, destination, next_attempt_at, created_at)
+		SELECT $1, dest, $2, $2 FROM unnest($3::text[]) AS dest
+	`, id, event.CreatedAt, destinations); err != nil {
+		return fmt.Errorf("写入事件待投递记录失败 (PostgreSQL): %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("提交事务失败 (PostgreSQL): %w", err)
+	}
+	event.ID = id
+	return nil
+}
+
+// ClaimOutboxEntries 领取到期的待投递记录并设置租约
+// 使用 FOR UPDATE SKIP LOCKED，多实例并发领取时互不阻塞且不会领取到同一条记录
+func (s *PostgresStorage) ClaimOutboxEntries(now int64, lease time.Duration, limit int) ([]*OutboxEntry, error) {
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "ClaimOutboxEntries")
+	defer span.End()
+
+	rows, err := s.pool.Query(ctx, `
+		WITH claimed AS (
+			SELECT id FROM event_outbox
+			WHERE done_at = 0 AND next_attempt_at <= $1
+			ORDER BY id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE event_outbox o SET next_attempt_at = $3
+		FROM claimed
+		WHERE o.id = claimed.id
+		RETURNING o.id, o.event_id, o.destination, o.attempts, o.next_attempt_at, o.last_error, o.created_at
+	`, now, limit, now+int64(lease/time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("领取待投递记录失败 (PostgreSQL): %w", err)
+	}
+	var entries []*OutboxEntry
+	for rows.Next() {
+		var e OutboxEntry
+		if err := rows.Scan(&e.ID, &e.EventID, &e.Destination, &e.Attempts, &e.NextAttemptAt, &e.LastError, &e.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("扫描待投递记录失败 (PostgreSQL): %w", err)
+		}
+		entries = append(entries, &e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代待投递记录失败 (PostgreSQL): %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	// UPDATE ... RETURNING 不保证顺序
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+
+	eventIDs := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		eventIDs = append(eventIDs, e.EventID)
+	}
+	rows, err = s.pool.Query(ctx, `
+		SELECT id, namespace, provider, service, channel, model, event_type, from_status, to_status, trigger_record_id, observed_at, created_at, meta
+		FROM status_events
+		WHERE id = ANY($1)
+	`, eventIDs)
+	if err != nil {
+		return nil, fmt.Errorf("查询状态事件失败 (PostgreSQL): %w", err)
+	}
+	defer rows.Close()
+
+	events := make(map[int64]*StatusEvent, len(eventIDs))
+	for rows.Next() {
+		var event StatusEvent
+		var eventTypeStr string
+		var meta map[string]any
+		if err := rows.Scan(
+			&event.ID, &event.Namespace, &event.Provider, &event.Service, &event.Channel, &event.Model,
+			&eventTypeStr, &event.FromStatus, &event.ToStatus, &event.TriggerRecordID,
+			&event.ObservedAt, &event.CreatedAt, &meta,
+		); err != nil {
+			return nil, fmt.Errorf("扫描状态事件失败 (PostgreSQL): %w", err)
+		}
+		event.EventType = EventType(eventTypeStr)
+		event.Meta = meta
+		events[event.ID] = &event
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代状态事件失败 (PostgreSQL): %w", err)
+	}
+	for _, e := range entries {
+		e.Event = events[e.EventID]
+	}
+	return entries, nil
+}
+
+// CompleteOutboxEntry 标记待投递记录已完成
+func (s *PostgresStorage) CompleteOutboxEntry(id int64, attempts int, doneAt int64, lastError string) error {
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "CompleteOutboxEntry")
+	defer span.End()
+
+	if _, err := s.pool.Exec(ctx, `
+		UPDATE event_outbox SET attempts = $1, done_at = $2, last_error = $3 WHERE id = $4
+	`, attempts, doneAt, lastError, id); err != nil {
+		return fmt.Errorf("标记待投递记录完成失败 (PostgreSQL): %w", err)
+	}
+	return nil
+}
+
+// RetryOutboxEntry 记录失败尝试并设置下次尝试时间
+func (s *PostgresStorage) RetryOutboxEntry(id int64, attempts int, nextAttemptAt int64, lastError string) error {
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "RetryOutboxEntry")
+	defer span.End()
+
+	if _, err := s.pool.Exec(ctx, `
+		UPDATE event_outbox SET attempts = $1, next_attempt_at = $2, last_error = $3 WHERE id = $4 AND done_at = 0
+	`, attempts, nextAttemptAt, lastError, id); err != nil {
+		return fmt.Errorf("更新待投递记录失败 (PostgreSQL): %w", err)
+	}
+	return nil
+}
+
+// PurgeCompletedOutbox 删除过期的已完成记录
+func (s *PostgresStorage) PurgeCompletedOutbox(before int64) (int64, error) {
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "PurgeCompletedOutbox")
+	defer span.End()
+
+	result, err := s.pool.Exec(ctx, `DELETE FROM event_outbox WHERE done_at > 0 AND done_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("清理已完成的待投递记录失败 (PostgreSQL): %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// ===== 探测数据透明度相关方法 =====
+
+// initTransparencyTable 初始化透明度小时根表
+func (s *PostgresStorage) initTransparencyTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS transparency_roots (
+		hour BIGINT PRIMARY KEY,
+		leaf_count INTEGER NOT NULL,
+		root TEXT NOT NULL,
+		prev_root TEXT NOT NULL DEFAULT '',
+		key_id TEXT NOT NULL,
+		signature TEXT NOT NULL,
+		created_at BIGINT NOT NULL
+	);
+	`
+	if _, err := s.pool.Exec(ctx, schema); err != nil {
+		return fmt.Errorf("创建 transparency_roots 表失败: %w", err)
+	}
+	return nil
+}
+
+// GetRecordsInRange 查询时间范围内的全部探测记录
+func (s *PostgresStorage) GetRecordsInRange(since, until int64) ([]*ProbeRecord, error) {
+	ctx := s.effectiveCtx()
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, provider, service, channel, model, status, sub_status, http_code, latency, timestamp
+		FROM probe_history
+		WHERE timestamp >= $1 AND timestamp < $2
+		ORDER BY id ASC
+	`, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("查询 PostgreSQL 时间范围内的探测记录失败: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*ProbeRecord
+	for rows.Next() {
+		var r ProbeRecord
+		var subStatus string
+		if err := rows.Scan(&r.ID, &r.Provider, &r.Service, &r.Channel, &r.Model, &r.Status, &subStatus, &r.HttpCode, &r.Latency, &r.Timestamp); err != nil {
+			return nil, fmt.Errorf("扫描 PostgreSQL 探测记录失败: %w", err)
+		}
+		r.SubStatus = SubStatus(subStatus)
+		records = append(records, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代 PostgreSQL 探测记录失败: %w", err)
+	}
+	return records, nil
+}
+
+// SaveTransparencyRoot 保存小时根（已存在时忽略）
+func (s *PostgresStorage) SaveTransparencyRoot(root *TransparencyRoot) error {
+	ctx := s.effectiveCtx()
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO transparency_roots (hour, leaf_count, root, prev_root, key_id, signature, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (hour) DO NOTHING
+	`, root.Hour, root.LeafCount, root.Root, root.PrevRoot, root.KeyID, root.Signature, root.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("保存 PostgreSQL 透明度小时根失败: %w", err)
+	}
+	return nil
+}
+
+// GetLatestTransparencyRoot 查询最近封存的小时根
+func (s *PostgresStorage) GetLatestTransparencyRoot() (*TransparencyRoot, error) {
+	ctx := s.effectiveCtx()
+	var r TransparencyRoot
+	err := s.pool.QueryRow(ctx, `
+		SELECT hour, leaf_count, root, prev_root, key_id, signature, created_at
+		FROM transparency_roots
+		ORDER BY hour DESC
+		LIMIT 1
+	`).Scan(&r.Hour, &r.LeafCount, &r.Root, &r.PrevRoot, &r.KeyID, &r.Signature, &r.CreatedAt)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查询 PostgreSQL 最近透明度小时根失败: %w", err)
+	}
+	return &r, nil
+}
+
+// GetTransparencyRoots 查询范围内的小时根
+func (s *PostgresStorage) GetTransparencyRoots(sinceHour, untilHour int64) ([]*TransparencyRoot, error) {
+	ctx := s.effectiveCtx()
+	rows, err := s.pool.Query(ctx, `
+		SELECT hour, leaf_count, root, prev_root, key_id, signature, created_at
+		FROM transparency_roots
+		WHERE hour >= $1 AND hour <= $2
+		ORDER BY hour ASC
+	`, sinceHour, untilHour)
+	if err != nil {
+		return nil, fmt.Errorf("查询 PostgreSQL 透明度小时根失败: %w", err)
+	}
+	defer rows.Close()
+
+	var roots []*TransparencyRoot
+	for rows.Next() {
+		var r TransparencyRoot
+		if err := rows.Scan(&r.Hour, &r.LeafCount, &r.Root, &r.PrevRoot, &r.KeyID, &r.Signature, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("扫描 PostgreSQL 透明度小时根失败: %w", err)
+		}
+		roots = append(roots, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代 PostgreSQL 透明度小时根失败: %w", err)
+	}
+	return roots, nil
+}
+
+// ===== 已下线监测项相关方法 =====
+
+// initDecommissionTable 初始化已下线监测项登记表
+func (s *PostgresStorage) initDecommissionTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS decommissioned_monitors (
+		provider TEXT NOT NULL,
+		service TEXT NOT NULL,
+		channel TEXT NOT NULL DEFAULT '',
+		model TEXT NOT NULL DEFAULT '',
+		parent TEXT NOT NULL DEFAULT '',
+		provider_name TEXT NOT NULL DEFAULT '',
+		provider_slug TEXT NOT NULL DEFAULT '',
+		provider_url TEXT NOT NULL DEFAULT '',
+		service_name TEXT NOT NULL DEFAULT '',
+		channel_name TEXT NOT NULL DEFAULT '',
+		category TEXT NOT NULL DEFAULT '',
+		board TEXT NOT NULL DEFAULT '',
+		hidden BOOLEAN NOT NULL DEFAULT FALSE,
+		retired_at BIGINT NOT NULL,
+		PRIMARY KEY (provider, service, channel, model)
+	);
+	`
+	if _, err := s.pool.Exec(ctx, schema); err != nil {
+		return fmt.Errorf("创建 decommissioned_monitors 表失败: %w", err)
+	}
+	return nil
+}
+
+// SaveDecommissionedMonitors 登记已下线监测项（已登记时保留原下线时间）
+func (s *PostgresStorage) SaveDecommissionedMonitors(monitors []*DecommissionedMonitor) error {
+	if len(monitors) == 0 {
+		return nil
+	}
+	ctx := s.effectiveCtx()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("开启 PostgreSQL 下线登记事务失败: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, m := range monitors {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO decommissioned_monitors (provider, service, channel, model, parent, provider_name, provider_slug,
+				provider_url, service_name, channel_name, category, board, hidden, retired_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			ON CONFLICT (provider, service, channel, model) DO NOTHING
+		`, m.Provider, m.Service, m.Channel, m.Model, m.Parent, m.ProviderName, m.ProviderSlug,
+			m.ProviderURL, m.ServiceName, m.ChannelName, m.Category, m.Board, m.Hidden, m.RetiredAt); err != nil {
+			return fmt.Errorf("登记 PostgreSQL 已下线监测项失败: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("提交 PostgreSQL 下线登记事务失败: %w", err)
+	}
+	return nil
+}
+
+// DeleteDecommissionedMonitors 移除下线登记（保留历史数据）
+func (s *PostgresStorage) DeleteDecommissionedMonitors(keys []MonitorKey) error {
+	ctx := s.effectiveCtx()
+	for _, k := range keys {
+		if _, err := s.pool.Exec(ctx, `
+			DELETE FROM decommissioned_monitors
+			WHERE provider = $1 AND service = $2 AND channel = $3 AND model = $4
+		`, k.Provider, k.Service, k.Channel, k.Model); err != nil {
+			return fmt.Errorf("移除 PostgreSQL 下线登记失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetDecommissionedMonitors 查询全部已下线监测项
+func (s *PostgresStorage) GetDecommissionedMonitors() ([]*DecommissionedMonitor, error) {
+	ctx := s.effectiveCtx()
+	rows, err := s.pool.Query(ctx, `
+		SELECT provider, service, channel, model, parent, provider_name, provider_slug,
+			provider_url, service_name, channel_name, category, board, hidden, retired_at
+		FROM decommissioned_monitors
+		ORDER BY retired_at ASC, provider, service, channel, model
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("查询 PostgreSQL 已下线监测项失败: %w", err)
+	}
+	defer rows.Close()
+
+	var monitors []*DecommissionedMonitor
+	for rows.Next() {
+		var m DecommissionedMonitor
+		if err := rows.Scan(&m.Provider, &m.Service, &m.Channel, &m.Model, &m.Parent, &m.ProviderName, &m.ProviderSlug,
+			&m.ProviderURL, &m.ServiceName, &m.ChannelName, &m.Category, &m.Board, &m.Hidden, &m.RetiredAt); err != nil {
+			return nil, fmt.Errorf("扫描 PostgreSQL 已下线监测项失败: %w", err)
+		}
+		monitors = append(monitors, &m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代 PostgreSQL 已下线监测项失败: %w", err)
+	}
+	return monitors, nil
+}
+
+// PurgeMonitorRecords 删除指定监测项的一批探测记录
+func (s *PostgresStorage) PurgeMonitorRecords(ctx context.Context, key MonitorKey, batchSize int) (int64, error) {
+	result, err := s.pool.Exec(ctx, `
+		WITH d AS (
+			SELECT id FROM probe_history
+			WHERE provider = $1 AND service = $2 AND channel = $3 AND model = $4
+			LIMIT $5
+		)
+		DELETE FROM probe_history
+		WHERE id IN (SELECT id FROM d)
+	`, key.Provider, key.Service, key.Channel, key.Model, batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("删除 PostgreSQL 已下线监测项记录失败: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// DropDecommissionedMonitor 删除下线登记及该监测项的每日汇总、状态机状态与元数据版本
+func (s *PostgresStorage) DropDecommissionedMonitor(ctx context.Context, key MonitorKey) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("开启 PostgreSQL 下线清理事务失败: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, table := range []string{"probe_daily", "service_states", "monitor_metadata", "decommissioned_monitors"} {
+		query := fmt.Sprintf(`DELETE FROM %s WHERE provider = $1 AND service = $2 AND channel = $3 AND model = $4`, table)
+		if _, err := tx.Exec(ctx, query, key.Provider, key.Service, key.Channel, key.Model); err != nil {
+			return fmt.Errorf("清理 PostgreSQL %s 失败: %w", table, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("提交 PostgreSQL 下线清理事务失败: %w", err)
+	}
+	return nil
+}
+
+// ===== 服务商数据访问令牌相关方法 =====
+
+// initProviderTokenTable 初始化服务商数据访问令牌表
+func (s *PostgresStorage) initProviderTokenTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS provider_tokens (
+		id BIGSERIAL PRIMARY KEY,
+		provider_slug TEXT NOT NULL,
+		name TEXT NOT NULL DEFAULT '',
+		token_hash TEXT NOT NULL UNIQUE,
+		token_prefix TEXT NOT NULL,
+		created_at BIGINT NOT NULL,
+		revoked_at BIGINT NOT NULL DEFAULT 0
+	);
+	`
+	if _, err := s.pool.Exec(ctx, schema); err != nil {
+		return fmt.Errorf("创建 provider_tokens 表失败: %w", err)
+	}
+	if _, err := s.pool.Exec(ctx, `CREATE INDEX IF NOT EXISTS idx_provider_tokens_slug ON provider_tokens (provider_slug)`); err != nil {
+		return fmt.Errorf("创建 provider_tokens 索引失败: %w", err)
+	}
+	return nil
+}
+
+// CreateProviderToken 保存新令牌
+func (s *PostgresStorage) CreateProviderToken(token *ProviderToken) error {
+	ctx := s.effectiveCtx()
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO provider_tokens (provider_slug, name, token_hash, token_prefix, created_at, revoked_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, token.ProviderSlug, token.Name, token.TokenHash, token.TokenPrefix, token.CreatedAt, token.RevokedAt).Scan(&token.ID)
+	if err != nil {
+		return fmt.Errorf("保存 PostgreSQL 服务商令牌失败: %w", err)
+	}
+	return nil
+}
+
+// GetProviderTokenByHash 按令牌哈希查询
+func (s *PostgresStorage) GetProviderTokenByHash(tokenHash string) (*ProviderToken, error) {
+	ctx := s.effectiveCtx()
+	var t ProviderToken
+	err := s.pool.QueryRow(ctx, `
+		SELECT id, provider_slug, name, token_hash, token_prefix, created_at, revoked_at
+		FROM provider_tokens
+		WHERE token_hash = $1
+	`, tokenHash).Scan(&t.ID, &t.ProviderSlug, &t.Name, &t.TokenHash, &t.TokenPrefix, &t.CreatedAt, &t.RevokedAt)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查询 PostgreSQL 服务商令牌失败: %w", err)
+	}
+	return &t, nil
+}
+
+// ListProviderTokens 查询令牌列表
+func (s *PostgresStorage) ListProviderTokens(providerSlug string) ([]*ProviderToken, error) {
+	ctx := s.effectiveCtx()
+	query := `
+		SELECT id, provider_slug, name, token_hash, token_prefix, created_at, revoked_at
+		FROM provider_tokens
+	`
+	var args []any
+	if providerSlug != "" {
+		query += ` WHERE provider_slug = $1`
+		args = append(args, providerSlug)
+	}
+	query += ` ORDER BY id DESC`
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询 PostgreSQL 服务商令牌列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []*ProviderToken
+	for rows.Next() {
+		var t ProviderToken
+		if err := rows.Scan(&t.ID, &t.ProviderSlug, &t.Name, &t.TokenHash, &t.TokenPrefix, &t.CreatedAt, &t.RevokedAt); err != nil {
+			return nil, fmt.Errorf("扫描 PostgreSQL 服务商令牌失败: %w", err)
+		}
+		tokens = append(tokens, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代 PostgreSQL 服务商令牌失败: %w", err)
+	}
+	return tokens, nil
+}
+
+// RevokeProviderToken 吊销令牌
+func (s *PostgresStorage) RevokeProviderToken(id, revokedAt int64) (bool, error) {
+	ctx := s.effectiveCtx()
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE provider_tokens SET revoked_at = $1 WHERE id = $2 AND revoked_at = 0
+	`, revokedAt, id)
+	if err != nil {
+		return false, fmt.Errorf("吊销 PostgreSQL 服务商令牌失败: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ===== 运维标注相关方法 =====
+
+// initAnnotationTable 初始化运维标注表
+func (s *PostgresStorage) initAnnotationTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS annotations (
+		id BIGSERIAL PRIMARY KEY,
+		provider TEXT NOT NULL,
+		service TEXT NOT NULL DEFAULT '',
+		channel TEXT NOT NULL DEFAULT '',
+		model TEXT NOT NULL DEFAULT '',
+		start_time BIGINT NOT NULL,
+		end_time BIGINT NOT NULL DEFAULT 0,
+		text TEXT NOT NULL,
+		link TEXT NOT NULL DEFAULT '',
+		created_at BIGINT NOT NULL
+	);
+	`
+	if _, err := s.pool.Exec(ctx, schema); err != nil {
+		return fmt.Errorf("创建 annotations 表失败: %w", err)
+	}
+	if _, err := s.pool.Exec(ctx, `CREATE INDEX IF NOT EXISTS idx_annotations_provider_start ON annotations (provider, start_time)`); err != nil {
+		return fmt.Errorf("创建 annotations 索引失败: %w", err)
+	}
+	return nil
+}
+
+// CreateAnnotation 保存新标注
+func (s *PostgresStorage) CreateAnnotation(annotation *Annotation) error {
+	ctx := s.effectiveCtx()
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO annotations (provider, service, channel, model, start_time, end_time, text, link, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`, annotation.Provider, annotation.Service, annotation.Channel, annotation.Model,
+		annotation.StartTime, annotation.EndTime, annotation.Text, annotation.Link, annotation.CreatedAt).Scan(&annotation.ID)
+	if err != nil {
+		return fmt.Errorf("保存 PostgreSQL 运维标注失败: %w", err)
+	}
+	return nil
+}
+
+// GetAnnotations 查询与时间范围重叠的标注
+func (s *PostgresStorage) GetAnnotations(filter AnnotationFilter) ([]*Annotation, error) {
+	ctx := s.effectiveCtx()
+	query := `
+		SELECT id, provider, service, channel, model, start_time, end_time, text, link, created_at
+		FROM annotations
+		WHERE 1=1
+	`
+	var args []any
+	if filter.Provider != "" {
+		args = append(args, filter.Provider)
+		query += fmt.Sprintf(` AND provider = $%d`, len(args))
+	}
+	if filter.Since > 0 {
+		args = append(args, filter.Since)
+		query += fmt.Sprintf(` AND (end_time = 0 OR end_time >= $%d)`, len(args))
+	}
+	if filter.Until > 0 {
+		args = append(args, filter.Until)
+		query += fmt.Sprintf(` AND start_time <= $%d`, len(args))
+	}
+	query += ` ORDER BY start_time ASC, id ASC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(` LIMIT $%d`, len(args))
+	}
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询 PostgreSQL 运维标注失败: %w", err)
+	}
+	defer rows.Close()
+
+	var annotations []*Annotation
+	for rows.Next() {
+		var a Annotation
+		if err := rows.Scan(&a.ID, &a.Provider, &a.Service, &a.Channel, &a.Model, &a.StartTime, &a.EndTime, &a.Text, &a.Link, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("扫描 PostgreSQL 运维标注失败: %w", err)
+		}
+		annotations = append(annotations, &a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代 PostgreSQL 运维标注失败: %w", err)
+	}
+	return annotations, nil
+}
+
+// DeleteAnnotation 删除标注
+func (s *PostgresStorage) DeleteAnnotation(id int64) (bool, error) {
+	ctx := s.effectiveCtx()
+	tag, err := s.pool.Exec(ctx, `DELETE FROM annotations WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("删除 PostgreSQL 运维标注失败: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ===== 自定义分组相关方法 =====
+
+// initCustomGroupTable 初始化自定义分组表
+func (s *PostgresStorage) initCustomGroupTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS custom_groups (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		members TEXT NOT NULL,
+		created_at BIGINT NOT NULL,
+		updated_at BIGINT NOT NULL
+	);
+	`
+	if _, err := s.pool.Exec(ctx, schema); err != nil {
+		return fmt.Errorf("创建 custom_groups 表失败: %w", err)
+	}
+	return nil
+}
+
+// SaveCustomGroup 创建或覆盖自定义分组
+func (s *PostgresStorage) SaveCustomGroup(group *CustomGroup) error {
+	ctx := s.effectiveCtx()
+	members, err := encodeCustomGroupMembers(group.Members)
+	if err != nil {
+		return err
+	}
+	err = s.pool.QueryRow(ctx, `
+		INSERT INTO custom_groups (id, name, description, members, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			description = EXCLUDED.description,
+			members = EXCLUDED.members,
+			updated_at = EXCLUDED.updated_at
+		RETURNING created_at
+	`, group.ID, group.Name, group.Description, members, group.CreatedAt, group.UpdatedAt).Scan(&group.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("保存 PostgreSQL 自定义分组失败: %w", err)
+	}
+	return nil
+}
+
+// ListCustomGroups 查询全部自定义分组
+func (s *PostgresStorage) ListCustomGroups() ([]*CustomGroup, error) {
+	ctx := s.effectiveCtx()
+	rows, err := s.pool.Query(ctx, `SELECT `+customGroupColumns+` FROM custom_groups ORDER BY id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("查询 PostgreSQL 自定义分组失败: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []*CustomGroup
+	for rows.Next() {
+		g, err := scanCustomGroup(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描自定义分组失败: %w", err)
+		}
+		groups = append(groups, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代自定义分组失败: %w", err)
+	}
+	return groups, nil
+}
+
+// DeleteCustomGroup 删除自定义分组
+func (s *PostgresStorage) DeleteCustomGroup(id string) (bool, error) {
+	ctx := s.effectiveCtx()
+	tag, err := s.pool.Exec(ctx, `DELETE FROM custom_groups WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("删除 PostgreSQL 自定义分组失败: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ===== 服务商元数据修改申请相关方法 =====
+
+// initMetadataRequestTable 初始化服务商元数据修改申请表
+func (s *PostgresStorage) initMetadataRequestTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS metadata_requests (
+		id BIGSERIAL PRIMARY KEY,
+		provider_slug TEXT NOT NULL,
+		token_id BIGINT NOT NULL DEFAULT 0,
+		changes TEXT NOT NULL,
+		comment TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL,
+		review_note TEXT NOT NULL DEFAULT '',
+		created_at BIGINT NOT NULL,
+		reviewed_at BIGINT NOT NULL DEFAULT 0
+	);
+	`
+	if _, err := s.pool.Exec(ctx, schema); err != nil {
+		return fmt.Errorf("创建 metadata_requests 表失败: %w", err)
+	}
+	if _, err := s.pool.Exec(ctx, `CREATE INDEX IF NOT EXISTS idx_metadata_requests_slug_status ON metadata_requests (provider_slug, status)`); err != nil {
+		return fmt.Errorf("创建 metadata_requests 索引失败: %w", err)
+	}
+	return nil
+}
+
+// CreateMetadataRequest 保存新申请
+func (s *PostgresStorage) CreateMetadataRequest(req *MetadataRequest) error {
+	ctx := s.effectiveCtx()
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO metadata_requests (provider_slug, token_id, changes, comment, status, review_note, created_at, reviewed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`, req.ProviderSlug, req.TokenID, req.Changes, req.Comment, req.Status, req.ReviewNote, req.CreatedAt, req.ReviewedAt).Scan(&req.ID)
+	if err != nil {
+		return fmt.Errorf("保存 PostgreSQL 元数据修改申请失败: %w", err)
+	}
+	return nil
+}
+
+// GetMetadataRequest 按 ID 查询申请
+func (s *PostgresStorage) GetMetadataRequest(id int64) (*MetadataRequest, error) {
+	ctx := s.effectiveCtx()
+	var r MetadataRequest
+	err := s.pool.QueryRow(ctx, `
+		SELECT id, provider_slug, token_id, changes, comment, status, review_note, created_at, reviewed_at
+		FROM metadata_requests
+		WHERE id = $1
+	`, id).Scan(&r.ID, &r.ProviderSlug, &r.TokenID, &r.Changes, &r.Comment, &r.Status, &r.ReviewNote, &r.CreatedAt, &r.ReviewedAt)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查询 PostgreSQL 元数据修改申请失败: %w", err)
+	}
+	return &r, nil
+}
+
+// ListMetadataRequests 查询申请列表
+func (s *PostgresStorage) ListMetadataRequests(filter MetadataRequestFilter) ([]*MetadataRequest, error) {
+	ctx := s.effectiveCtx()
+	query := `
+		SELECT id, provider_slug, token_id, changes, comment, status, review_note, created_at, reviewed_at
+		FROM metadata_requests
+		WHERE 1=1
+	`
+	var args []any
+	if filter.ProviderSlug != "" {
+		args = append(args, filter.ProviderSlug)
+		query += fmt.Sprintf(` AND provider_slug = $%d`, len(args))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		query += fmt.Sprintf(` AND status = $%d`, len(args))
+	}
+	query += ` ORDER BY id DESC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(` LIMIT $%d`, len(args))
+	}
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询 PostgreSQL 元数据修改申请列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	var reqs []*MetadataRequest
+	for rows.Next() {
+		var r MetadataRequest
+		if err := rows.Scan(&r.ID, &r.ProviderSlug, &r.TokenID, &r.Changes, &r.Comment, &r.Status, &r.ReviewNote, &r.CreatedAt, &r.ReviewedAt); err != nil {
+			return nil, fmt.Errorf("扫描 PostgreSQL 元数据修改申请失败: %w", err)
+		}
+		reqs = append(reqs, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代 PostgreSQL 元数据修改申请失败: %w", err)
+	}
+	return reqs, nil
+}
+
+// ReviewMetadataRequest 审核待处理的申请
+func (s *PostgresStorage) ReviewMetadataRequest(id int64, status, reviewNote string, reviewedAt int64) (bool, error) {
+	ctx := s.effectiveCtx()
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE metadata_requests SET status = $1, review_note = $2, reviewed_at = $3
+		WHERE id = $4 AND status = $5
+	`, status, reviewNote, reviewedAt, id, MetadataRequestPending)
+	if err != nil {
+		return false, fmt.Errorf("审核 PostgreSQL 元数据修改申请失败: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ===== 监测项元数据版本相关方法 =====
+
+// initMonitorMetadataTable 初始化监测项元数据版本表
+func (s *PostgresStorage) initMonitorMetadataTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS monitor_metadata (
+		provider TEXT NOT NULL,
+		service TEXT NOT NULL,
+		channel TEXT NOT NULL DEFAULT '',
+		model TEXT NOT NULL DEFAULT '',
+		provider_name TEXT NOT NULL DEFAULT '',
+		provider_slug TEXT NOT NULL DEFAULT '',
+		sponsor TEXT NOT NULL DEFAULT '',
+		sponsor_url TEXT NOT NULL DEFAULT '',
+		price_min DOUBLE PRECISION,
+		price_max DOUBLE PRECISION,
+		badges TEXT NOT NULL DEFAULT '',
+		valid_from BIGINT NOT NULL,
+		PRIMARY KEY (provider, service, channel, model, valid_from)
+	);
+	`
+	if _, err := s.pool.Exec(ctx, schema); err != nil {
+		return fmt.Errorf("创建 PostgreSQL monitor_metadata 表失败: %w", err)
+	}
+	return nil
+}
+
+// SaveMonitorMetadata 写入元数据版本
+func (s *PostgresStorage) SaveMonitorMetadata(versions []*MonitorMetadata) error {
+	if len(versions) == 0 {
+		return nil
+	}
+	ctx := s.effectiveCtx()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("开启 PostgreSQL 元数据版本事务失败: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, m := range versions {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO monitor_metadata (provider, service, channel, model, provider_name, provider_slug,
+				sponsor, sponsor_url, price_min, price_max, badges, valid_from)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			ON CONFLICT (provider, service, channel, model, valid_from) DO UPDATE SET
+				provider_name = EXCLUDED.provider_name,
+				provider_slug = EXCLUDED.provider_slug,
+				sponsor = EXCLUDED.sponsor,
+				sponsor_url = EXCLUDED.sponsor_url,
+				price_min = EXCLUDED.price_min,
+				price_max = EXCLUDED.price_max,
+				badges = EXCLUDED.badges
+		`, m.Provider, m.Service, m.Channel, m.Model, m.ProviderName, m.ProviderSlug,
+			m.Sponsor, m.SponsorURL, m.PriceMin, m.PriceMax, joinBadges(m.Badges), m.ValidFrom); err != nil {
+			return fmt.Errorf("写入 PostgreSQL 元数据版本失败: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("提交 PostgreSQL 元数据版本事务失败: %w", err)
+	}
+	return nil
+}
+
+// GetLatestMonitorMetadata 查询每个监测项的当前版本
+func (s *PostgresStorage) GetLatestMonitorMetadata() (map[MonitorKey]*MonitorMetadata, error) {
+	ctx := s.effectiveCtx()
+	rows, err := s.pool.Query(ctx, `
+		SELECT DISTINCT ON (provider, service, channel, model)
+			provider, service, channel, model, provider_name, provider_slug,
+			sponsor, sponsor_url, price_min, price_max, badges, valid_from
+		FROM monitor_metadata
+		ORDER BY provider, service, channel, model, valid_from DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("查询 PostgreSQL 当前元数据版本失败: %w", err)
+	}
+	defer rows.Close()
+
+	versions, err := scanPostgresMonitorMetadataRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[MonitorKey]*MonitorMetadata, len(versions))
+	for _, m := range versions {
+		latest[m.Key()] = m
+	}
+	return latest, nil
+}
+
+// GetMonitorMetadataHistory 查询时间范围内生效过的元数据版本
+func (s *PostgresStorage) GetMonitorMetadataHistory(key MonitorKey, since, until int64) ([]*MonitorMetadata, error) {
+	ctx := s.effectiveCtx()
+	rows, err := s.pool.Query(ctx, `
+		SELECT provider, service, channel, model, provider_name, provider_slug,
+			sponsor, sponsor_url, price_min, price_max, badges, valid_from
+		FROM monitor_metadata
+		WHERE provider = $1 AND service = $2 AND channel = $3 AND model = $4
+			AND valid_from < $5
+			AND valid_from >= COALESCE((
+				SELECT MAX(valid_from) FROM monitor_metadata
+				WHERE provider = $1 AND service = $2 AND channel = $3 AND model = $4 AND valid_from <= $6
+			), 0)
+		ORDER BY valid_from
+	`, key.Provider, key.Service, key.Channel, key.Model, until, since)
+	if err != nil {
+		return nil, fmt.Errorf("查询 PostgreSQL 元数据版本历史失败: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPostgresMonitorMetadataRows(rows)
+}
+
+// scanPostgresMonitorMetadataRows 扫描元数据版本查询结果
+func scanPostgresMonitorMetadataRows(rows pgx.Rows) ([]*MonitorMetadata, error) {
+	var versions []*MonitorMetadata
+	for rows.Next() {
+		var m MonitorMetadata
+		var badges string
+		if err := rows.Scan(&m.Provider, &m.Service, &m.Channel, &m.Model, &m.ProviderName, &m.ProviderSlug,
+			&m.Sponsor, &m.SponsorURL, &m.PriceMin, &m.PriceMax, &badges, &m.ValidFrom); err != nil {
+			return nil, fmt.Errorf("扫描 PostgreSQL 元数据版本失败: %w", err)
+		}
+		m.Badges = splitBadges(badges)
+		versions = append(versions, &m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代 PostgreSQL 元数据版本失败: %w", err)
+	}
+	return versions, nil
+}
+
+// ===== 通道改名/合并相关方法 =====
+
+// RenameChannel 在单个事务内将通道的历史数据迁移到目标通道
+func (s *PostgresStorage) RenameChannel(ctx context.Context, r ChannelRename, dryRun bool) ([]ChannelRenameCount, error) {
+	src := []any{r.Provider, r.Service, r.From}
+	move := []any{r.To, r.Provider, r.Service, r.From}
+	staleSrc := []any{r.Provider, r.Service, r.From, r.To} // 源通道中已被目标通道较新状态取代的行
+	staleDst := []any{r.Provider, r.Service, r.To, r.From} // 目标通道中将被源通道较新状态取代的行
+	steps := []struct {
+		table   string
+		query   string
+		args    []any
+		counted bool
+	}{
+		{"probe_history", `UPDATE probe_history SET channel = $1 WHERE provider = $2 AND service = $3 AND channel = $4`, move, true},
+		{"status_events", `UPDATE status_events SET channel = $1 WHERE provider = $2 AND service = $3 AND channel = $4`, move, true},
+		{"probe_debug", `UPDATE probe_debug SET channel = $1 WHERE provider = $2 AND service = $3 AND channel = $4`, move, true},
+		{"annotations", `UPDATE annotations SET channel = $1 WHERE provider = $2 AND service = $3 AND channel = $4`, move, true},
+		{"probe_daily", `
+			INSERT INTO probe_daily (provider, service, channel, model, day, total, green, yellow, red, latency_sum, latency_count)
+			SELECT provider, service, $1::text, model, day, total, green, yellow, red, latency_sum, latency_count
+			FROM probe_daily WHERE provider = $2 AND service = $3 AND channel = $4
+			ON CONFLICT (provider, service, channel, model, day) DO UPDATE SET
+				total = probe_daily.total + EXCLUDED.total,
+				green = probe_daily.green + EXCLUDED.green,
+				yellow = probe_daily.yellow + EXCLUDED.yellow,
+				red = probe_daily.red + EXCLUDED.red,
+				latency_sum = probe_daily.latency_sum + EXCLUDED.latency_sum,
+				latency_count = probe_daily.latency_count + EXCLUDED.latency_count
+		`, move, true},
+		{"probe_daily", `DELETE FROM probe_daily WHERE provider = $1 AND service = $2 AND channel = $3`, src, false},
+		{"usage_daily", `
+			INSERT INTO usage_daily (provider, service, channel, model, day, tokens, cost, probes)
+			SELECT provider, service, $1::text, model, day, tokens, cost, probes
+			FROM usage_daily WHERE provider = $2 AND service = $3 AND channel = $4
+			ON CONFLICT (provider, service, channel, model, day) DO UPDATE SET
+				tokens = usage_daily.tokens + EXCLUDED.tokens,
+				cost = usage_daily.cost + EXCLUDED.cost,
+				probes = usage_daily.probes + EXCLUDED.probes
+		`, move, true},
+		{"usage_daily", `DELETE FROM usage_daily WHERE provider = $1 AND service = $2 AND channel = $3`, src, false},
+		// 状态机状态：两侧同一 model 均有状态时保留 last_timestamp 较新的一条
+		{"service_states", `
+			DELETE FROM service_states WHERE provider = $1 AND service = $2 AND channel = $3 AND EXISTS (
+				SELECT 1 FROM service_states t
+				WHERE t.provider = service_states.provider AND t.service = service_states.service
+					AND t.channel = $4 AND t.model = service_states.model AND t.last_timestamp >= service_states.last_timestamp
+			)
+		`, staleSrc, false},
+		{"service_states", `
+			DELETE FROM service_states WHERE provider = $1 AND service = $2 AND channel = $3 AND EXISTS (
+				SELECT 1 FROM service_states f
+				WHERE f.provider = service_states.provider AND f.service = service_states.service
+					AND f.channel = $4 AND f.model = service_states.model
+			)
+		`, staleDst, false},
+		{"service_states", `UPDATE service_states SET channel = $1 WHERE provider = $2 AND service = $3 AND channel = $4`, move, true},
+		{"channel_states", `
+			DELETE FROM channel_states WHERE provider = $1 AND service = $2 AND channel = $3 AND EXISTS (
+				SELECT 1 FROM channel_states t
+				WHERE t.provider = channel_states.provider AND t.service = channel_states.service
+					AND t.channel = $4 AND t.last_timestamp >= channel_states.last_timestamp
+			)
+		`, staleSrc, false},
+		{"channel_states", `
+			DELETE FROM channel_states WHERE provider = $1 AND service = $2 AND channel = $3 AND EXISTS (
+				SELECT 1 FROM channel_states f
+				WHERE f.provider = channel_states.provider AND f.service = channel_states.service AND f.channel = $4
+			)
+		`, staleDst, false},
+		{"channel_states", `UPDATE channel_states SET channel = $1 WHERE provider = $2 AND service = $3 AND channel = $4`, move, true},
+		{"monitor_metadata", `
+			INSERT INTO monitor_metadata (provider, service, channel, model, provider_name, provider_slug,
+				sponsor, sponsor_url, price_min, price_max, badges, valid_from)
+			SELECT provider, service, $1::text, model, provider_name, provider_slug,
+				sponsor, sponsor_url, price_min, price_max, badges, valid_from
+			FROM monitor_metadata WHERE provider = $2 AND service = $3 AND channel = $4
+			ON CONFLICT (provider, service, channel, model, valid_from) DO NOTHING
+		`, move, true},
+		{"monitor_metadata", `DELETE FROM monitor_metadata WHERE provider = $1 AND service = $2 AND channel = $3`, src, false},
+		// 原通道的下线登记失效（历史已迁移到目标通道）
+		{"decommissioned_monitors", `DELETE FROM decommissioned_monitors WHERE provider = $1 AND service = $2 AND channel = $3`, src, true},
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("开启 PostgreSQL 通道迁移事务失败: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var counts []ChannelRenameCount
+	for _, step := range steps {
+		tag, err := tx.Exec(ctx, step.query, step.args...)
+		if err != nil {
+			return nil, fmt.Errorf("迁移 PostgreSQL %s 失败: %w", step.table, err)
+		}
+		if step.counted {
+			counts = append(counts, ChannelRenameCount{Table: step.table, Rows: tag.RowsAffected()})
+		}
+	}
+
+	if dryRun {
+		return counts, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("提交 PostgreSQL 通道迁移事务失败: %w", err)
+	}
+	return counts, nil
+}
+
+// ===== 管理后台用户与会话相关方法 =====
+
+// initAdminUserTables 初始化管理后台用户表与会话表
+func (s *PostgresStorage) initAdminUserTables(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS admin_users (
+		id BIGSERIAL PRIMARY KEY,
+		username TEXT NOT NULL UNIQUE,
+		password_hash TEXT NOT NULL,
+		role TEXT NOT NULL,
+		created_at BIGINT NOT NULL,
+		updated_at BIGINT NOT NULL,
+		disabled_at BIGINT NOT NULL DEFAULT 0
+	);
+	CREATE TABLE IF NOT EXISTS admin_sessions (
+		id BIGSERIAL PRIMARY KEY,
+		user_id BIGINT NOT NULL,
+		token_hash TEXT NOT NULL UNIQUE,
+		created_at BIGINT NOT NULL,
+		expires_at BIGINT NOT NULL,
+		ip TEXT NOT NULL DEFAULT ''
+	);
+	`
+	if _, err := s.pool.Exec(ctx, schema); err != nil {
+		return fmt.Errorf("创建 admin_users / admin_sessions 表失败 (PostgreSQL): %w", err)
+	}
+	if _, err := s.pool.Exec(ctx, `CREATE INDEX IF NOT EXISTS idx_admin_sessions_user ON admin_sessions (user_id)`); err != nil {
+		return fmt.Errorf("创建 admin_sessions 索引失败 (PostgreSQL): %w", err)
+	}
+	return nil
+}
+
+// CreateAdminUser 保存新用户
+func (s *PostgresStorage) CreateAdminUser(user *AdminUser) error {
+	ctx := s.effectiveCtx()
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO admin_users (username, password_hash, role, created_at, updated_at, disabled_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, user.Username, user.PasswordHash, user.Role, user.CreatedAt, user.UpdatedAt, user.DisabledAt).Scan(&user.ID)
+	if err != nil {
+		return fmt.Errorf("保存 PostgreSQL 管理后台用户失败: %w", err)
+	}
+	return nil
+}
+
+// GetAdminUser 按 ID 查询用户
+func (s *PostgresStorage) GetAdminUser(id int64) (*AdminUser, error) {
+	ctx := s.effectiveCtx()
+	u, err := scanAdminUser(s.pool.QueryRow(ctx, `SELECT `+adminUserColumns+` FROM admin_users WHERE id = $1`, id))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查询 PostgreSQL 管理后台用户失败: %w", err)
+	}
+	return u, nil
+}
+
+// GetAdminUserByUsername 按用户名查询用户
+func (s *PostgresStorage) GetAdminUserByUsername(username string) (*AdminUser, error) {
+	ctx := s.effectiveCtx()
+	u, err := scanAdminUser(s.pool.QueryRow(ctx, `SELECT `+adminUserColumns+` FROM admin_users WHERE username = $1`, username))
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查询 PostgreSQL 管理后台用户失败: %w", err)
+	}
+	return u, nil
+}
+
+// ListAdminUsers 查询全部用户
+func (s *PostgresStorage) ListAdminUsers() ([]*AdminUser, error) {
+	ctx := s.effectiveCtx()
+	rows, err := s.pool.Query(ctx, `SELECT `+adminUserColumns+` FROM admin_users ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("查询 PostgreSQL 管理后台用户列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*AdminUser
+	for rows.Next() {
+		u, err := scanAdminUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描 PostgreSQL 管理后台用户失败: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代 PostgreSQL 管理后台用户失败: %w", err)
+	}
+	return users, nil
+}
+
+// UpdateAdminUser 更新用户的密码哈希、角色与停用状态
+func (s *PostgresStorage) UpdateAdminUser(user *AdminUser) (bool, error) {
+	ctx := s.effectiveCtx()
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE admin_users SET password_hash = $1, role = $2, updated_at = $3, disabled_at = $4 WHERE id = $5
+	`, user.PasswordHash, user.Role, user.UpdatedAt, user.DisabledAt, user.ID)
+	if err != nil {
+		return false, fmt.Errorf("更新 PostgreSQL 管理后台用户失败: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// DeleteAdminUser 删除用户及其全部会话
+func (s *PostgresStorage) DeleteAdminUser(id int64) (bool, error) {
+	ctx := s.effectiveCtx()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("开启 PostgreSQL 事务失败: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM admin_sessions WHERE user_id = $1`, id); err != nil {
+		return false, fmt.Errorf("删除 PostgreSQL 管理后台会话失败: %w", err)
+	}
+	tag, err := tx.Exec(ctx, `DELETE FROM admin_users WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("删除 PostgreSQL 管理后台用户失败: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("提交 PostgreSQL 事务失败: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// CreateAdminSession 保存新会话
+func (s *PostgresStorage) CreateAdminSession(session *AdminSession) error {
+	ctx := s.effectiveCtx()
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO admin_sessions (user_id, token_hash, created_at, expires_at, ip)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, session.UserID, session.TokenHash, session.CreatedAt, session.ExpiresAt, session.IP).Scan(&session.ID)
+	if err != nil {
+		return fmt.Errorf("保存 PostgreSQL 管理后台会话失败: %w", err)
+	}
+	return nil
+}
+
+// GetAdminSessionByHash 按令牌哈希查询会话
+func (s *PostgresStorage) GetAdminSessionByHash(tokenHash string) (*AdminSession, error) {
+	ctx := s.effectiveCtx()
+	var sess AdminSession
+	err := s.pool.QueryRow(ctx, `
+		SELECT id, user_id, token_hash, created_at, expires_at, ip
+		FROM admin_sessions
+		WHERE token_hash = $1
+	`, tokenHash).Scan(&sess.ID, &sess.UserID, &sess.TokenHash, &sess.CreatedAt, &sess.ExpiresAt, &sess.IP)
+	if err != nil {
+		if err.Error() == "no rows in result set" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查询 PostgreSQL 管理后台会话失败: %w", err)
+	}
+	return &sess, nil
+}
+
+// DeleteAdminSession 删除会话
+func (s *PostgresStorage) DeleteAdminSession(tokenHash string) error {
+	ctx := s.effectiveCtx()
+	if _, err := s.pool.Exec(ctx, `DELETE FROM admin_sessions WHERE token_hash = $1`, tokenHash); err != nil {
+		return fmt.Errorf("删除 PostgreSQL 管理后台会话失败: %w", err)
+	}
+	return nil
+}
+
+// DeleteAdminUserSessions 删除用户的全部会话
+func (s *PostgresStorage) DeleteAdminUserSessions(userID int64) error {
+	ctx := s.effectiveCtx()
+	if _, err := s.pool.Exec(ctx, `DELETE FROM admin_sessions WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("删除 PostgreSQL 管理后台会话失败: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredAdminSessions 删除已过期的会话
+func (s *PostgresStorage) DeleteExpiredAdminSessions(now int64) (int64, error) {
+	ctx := s.effectiveCtx()
+	tag, err := s.pool.Exec(ctx, `DELETE FROM admin_sessions WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, fmt.Errorf("清理 PostgreSQL 过期管理后台会话失败: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// explain 获取 PostgreSQL 执行计划（EXPLAIN，不使用 ANALYZE，不会执行语句）
+func (s *PostgresStorage) explain(ctx context.Context, query string, args []any) (string, error) {
+	rows, err := s.pool.Query(ctx, "EXPLAIN "+query, args...)
+	if err != nil {
+		return "", fmt.Errorf("EXPLAIN 失败: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return "", fmt.Errorf("扫描执行计划失败: %w", err)
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("迭代执行计划失败: %w", err)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// GetStorageDiagnostics 返回表与索引大小、扫描统计（pg_stat_user_tables/pg_stat_user_indexes）、慢查询汇总与索引建议
+func (s *PostgresStorage) GetStorageDiagnostics(topSlow int) (*StorageDiagnostics, error) {
+	ctx, span := startSpan(s.effectiveCtx(), "postgresql", "GetStorageDiagnostics")
+	defer span.End()
+
+	d := &StorageDiagnostics{Backend: "postgres"}
+	rows, err := s.pool.Query(ctx, `
+		SELECT relname, n_live_tup, pg_total_relation_size(relid), pg_indexes_size(relid), seq_scan, idx_scan
+		FROM pg_stat_user_tables
+		ORDER BY pg_total_relation_size(relid) DESC, relname`)
+	if err != nil {
+		return nil, fmt.Errorf("查询 PostgreSQL 表统计失败: %w", err)
+	}
+	for rows.Next() {
+		var t TableDiagnostics
+		var seqScans int64
+		if err := rows.Scan(&t.Name, &t.Rows, &t.TotalBytes, &t.IndexBytes, &seqScans, &t.IndexScans); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("扫描 PostgreSQL 表统计失败: %w", err)
+		}
+		t.SeqScans = &seqScans
+		d.Tables = append(d.Tables, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代 PostgreSQL 表统计失败: %w", err)
+	}
+
+	rows, err = s.pool.Query(ctx, `
+		SELECT s.relname, s.indexrelname, pg_relation_size(s.indexrelid), i.indisunique OR i.indisprimary, s.idx_scan
+		FROM pg_stat_user_indexes s
+		JOIN pg_index i ON i.indexrelid = s.indexrelid
+		ORDER BY pg_relation_size(s.indexrelid) DESC, s.indexrelname`)
+	if err != nil {
+		return nil, fmt.Errorf("查询 PostgreSQL 索引统计失败: %w", err)
+	}
+	for rows.Next() {
+		var idx IndexDiagnostics
+		var scans int64
+		if err := rows.Scan(&idx.Table, &idx.Name, &idx.Bytes, &idx.Unique, &scans); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("扫描 PostgreSQL 索引统计失败: %w", err)
+		}
+		idx.Scans = &scans
+		d.Indexes = append(d.Indexes, idx)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代 PostgreSQL 索引统计失败: %w", err)
+	}
+
+	d.SlowQuery = s.slow.summary(topSlow)
+	d.Advice = buildIndexAdvice(d)
+	return d, nil
+}
+
+// RunMaintenance 执行一轮数据库维护
+// 1) 死元组占比达到阈值的表执行 VACUUM (ANALYZE)，其余有修改的表执行 ANALYZE
+// 2) 安装了 pgstattuple 扩展时，叶子页填充率过低的 B-tree 索引执行 REINDEX CONCURRENTLY（不阻塞读写）
+// 使用 advisory lock 确保多实例互斥
+func (s *PostgresStorage) RunMaintenance(ctx context.Context, opts MaintenanceOptions) ([]MaintenanceStep, error) {
+	ctx, span := startSpan(ctx, "postgresql", "RunMaintenance")
+	defer span.End()
+
+	// VACUUM / REINDEX CONCURRENTLY 不能在事务中执行，锁与维护语句使用同一连接
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取连接失败: %w", err)
+	}
+	defer conn.Release()
+
+	var acquired bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", maintenanceLockID).Scan(&acquired); err != nil {
+		return nil, fmt.Errorf("获取 advisory lock 失败: %w", err)
+	}
+	if !acquired {
+		return nil, ErrMaintenanceLockNotAcquired
+	}
+	defer func() {
+		unlockCtx, unlockCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer unlockCancel()
+		if _, err := conn.Exec(unlockCtx, "SELECT pg_advisory_unlock($1)", maintenanceLockID); err != nil {
+			logger.Warn("maintenance", "释放 advisory lock 失败", "error", err)
+		}
+	}()
+
+	var steps []MaintenanceStep
+	run := func(action, target, detail, stmt string) {
+		start := time.Now()
+		_, err := conn.Exec(ctx, stmt)
+		step := MaintenanceStep{Action: action, Target: target, Detail: detail, Duration: time.Since(start)}
+		if err != nil {
+			step.Error = err.Error()
+		}
+		steps = append(steps, step)
+	}
+
+	rows, err := conn.Query(ctx, `
+		SELECT relname, n_live_tup, n_dead_tup, n_mod_since_analyze
+		FROM pg_stat_user_tables
+		WHERE schemaname = current_schema()
+		ORDER BY relname`)
+	if err != nil {
+		return nil, fmt.Errorf("查询表统计失败: %w", err)
+	}
+	type tableStat struct {
+		name                 string
+		live, dead, modified int64
+	}
+	var tables []tableStat
+	for rows.Next() {
+		var t tableStat
+		if err := rows.Scan(&t.name, &t.live, &t.dead, &t.modified); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("扫描表统计失败: %w", err)
+		}
+		tables = append(tables, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代表统计失败: %w", err)
+	}
+
+	for _, t := range tables {
+		if ctx.Err() != nil {
+			return steps, ctx.Err()
+		}
+		ident := pgx.Identifier{t.name}.Sanitize()
+		var deadRatio float64
+		if total := t.live + t.dead; total > 0 {
+			deadRatio = float64(t.dead) / float64(total)
+		}
+		detail := fmt.Sprintf("dead_tuples=%d dead_ratio=%.3f", t.dead, deadRatio)
+		switch {
+		case t.dead > 0 && deadRatio >= opts.VacuumThreshold:
+			run("vacuum", t.name, detail, "VACUUM (ANALYZE) "+ident)
+		case t.modified > 0:
+			run("analyze", t.name, fmt.Sprintf("modified_since_analyze=%d", t.modified), "ANALYZE "+ident)
+		}
+	}
+
+	if opts.ReindexThreshold >= 1 {
+		return steps, nil
+	}
+	var hasPgstattuple bool
+	if err := conn.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pgstattuple')`).Scan(&hasPgstattuple); err != nil {
+		return steps, fmt.Errorf("查询 pgstattuple 扩展失败: %w", err)
+	}
+	if !hasPgstattuple {
+		return steps, nil
+	}
+
+	// avg_leaf_density 为叶子页平均填充率（百分比），新建索引约为 90
+	rows, err = conn.Query(ctx, `
+		SELECT i.indexrelname, s.avg_leaf_density
+		FROM pg_stat_user_indexes i
+		JOIN pg_class c ON c.oid = i.indexrelid
+		JOIN pg_am am ON am.oid = c.relam AND am.amname = 'btree'
+		CROSS JOIN LATERAL pgstatindex(i.indexrelid) s
+		WHERE i.schemaname = current_schema() AND s.leaf_pages > 1
+		ORDER BY i.indexrelname`)
+	if err != nil {
+		return steps, fmt.Errorf("查询索引碎片率失败: %w", err)
+	}
+	type fragIndex struct {
+		name  string
+		ratio float64
+	}
+	var fragmented []fragIndex
+	for rows.Next() {
+		var name string
+		var density float64
+		if err := rows.Scan(&name, &density); err != nil {
+			rows.Close()
+			return steps, fmt.Errorf("扫描索引碎片率失败: %w", err)
+		}
+		if ratio := 1 - density/100; ratio >= opts.ReindexThreshold {
+			fragmented = append(fragmented, fragIndex{name: name, ratio: ratio})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return steps, fmt.Errorf("迭代索引碎片率失败: %w", err)
+	}
+	for _, idx := range fragmented {
+		if ctx.Err() != nil {
+			return steps, ctx.Err()
+		}
+		run("reindex", idx.name, fmt.Sprintf("unused_ratio=%.3f", idx.ratio),
+			"REINDEX INDEX CONCURRENTLY "+pgx.Identifier{idx.name}.Sanitize())
+	}
+	return steps, nil
+}
@@ -0,0 +1,432 @@
+package storage
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	sqlite "modernc.org/sqlite"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+)
+
+// slowQueryExplainInterval 同一 SQL 重新获取执行计划的最小间隔
+const slowQueryExplainInterval = 10 * time.Minute
+
+// slowQueryExplainTimeout 单次 EXPLAIN 的超时
+const slowQueryExplainTimeout = 5 * time.Second
+
+// slowQueryLogMaxSQL 日志中 SQL 与执行计划的最大长度
+const slowQueryLogMaxSQL = 2000
+
+// slowQuerySkipKey 标记不参与慢查询统计的查询（EXPLAIN 自身）
+type slowQuerySkipKey struct{}
+
+// explainFunc 获取 SQL 的执行计划（由各存储后端提供）
+type explainFunc func(ctx context.Context, query string, args []any) (string, error)
+
+// slowQueryLog 慢查询日志：记录超过阈值的 SQL，并按 SQL 文本汇总供诊断端点查询
+// 由同一存储的全部 WithContext 副本共享
+type slowQueryLog struct {
+	threshold  time.Duration
+	maxEntries int
+	since      time.Time
+	explain    explainFunc   // nil 表示不获取执行计划
+	explainSem chan struct{} // 同时最多一个 EXPLAIN，避免慢查询集中出现时放大数据库压力
+
+	mu    sync.Mutex
+	total int64
+	stats map[string]*slowQueryEntry
+}
+
+// slowQueryEntry 单条 SQL 的汇总（explainedAt 仅内部使用）
+type slowQueryEntry struct {
+	SlowQueryStat
+	explainedAt time.Time
+}
+
+// newSlowQueryLog 按配置创建慢查询日志，未启用时返回 nil
+func newSlowQueryLog(cfg config.SlowQueryConfig) *slowQueryLog {
+	if !cfg.Enabled {
+		return nil
+	}
+	threshold := cfg.ThresholdDuration
+	if threshold <= 0 {
+		threshold = 500 * time.Millisecond
+	}
+	maxEntries := cfg.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = 50
+	}
+	l := &slowQueryLog{
+		threshold:  threshold,
+		maxEntries: maxEntries,
+		since:      time.Now(),
+		stats:      make(map[string]*slowQueryEntry),
+	}
+	if cfg.ExplainEnabled() {
+		l.explainSem = make(chan struct{}, 1)
+	}
+	return l
+}
+
+// setExplain 设置执行计划获取函数（存储实例创建后调用）
+func (l *slowQueryLog) setExplain(fn explainFunc) {
+	if l != nil && l.explainSem != nil {
+		l.explain = fn
+	}
+}
+
+// observe 记录一次 SQL 执行；未超过阈值时直接返回
+func (l *slowQueryLog) observe(ctx context.Context, query string, args []any, elapsed time.Duration, err error) {
+	if l == nil || elapsed < l.threshold || ctx.Value(slowQuerySkipKey{}) != nil {
+		return
+	}
+	text := normalizeSQL(query)
+	errText := ""
+	if err != nil {
+		errText = err.Error()
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	l.total++
+	entry := l.stats[text]
+	if entry == nil && len(l.stats) >= l.maxEntries && !l.evictLocked(elapsed) {
+		l.mu.Unlock()
+		l.log(ctx, text, elapsed, errText, "")
+		return
+	}
+	if entry == nil {
+		entry = &slowQueryEntry{SlowQueryStat: SlowQueryStat{SQL: text}}
+		l.stats[text] = entry
+	}
+	entry.Count++
+	entry.TotalTime += elapsed
+	entry.MaxTime = max(entry.MaxTime, elapsed)
+	entry.LastTime = elapsed
+	entry.LastSeen = now
+	entry.LastError = errText
+	plan := entry.Plan
+	needExplain := l.explain != nil && explainable(text) && now.Sub(entry.explainedAt) >= slowQueryExplainInterval
+	if needExplain {
+		select {
+		case l.explainSem <- struct{}{}:
+			entry.explainedAt = now
+		default:
+			needExplain = false // 已有 EXPLAIN 在执行，本次沿用缓存的执行计划
+		}
+	}
+	l.mu.Unlock()
+
+	if !needExplain {
+		l.log(ctx, text, elapsed, errText, plan)
+		return
+	}
+
+	// 执行计划在后台获取后随日志一起输出（不阻塞调用方；SQLite 单连接时需等待当前查询释放连接）
+	argsCopy := append([]any(nil), args...)
+	go func() {
+		defer func() { <-l.explainSem }()
+		explainCtx, cancel := context.WithTimeout(context.WithValue(context.Background(), slowQuerySkipKey{}, true), slowQueryExplainTimeout)
+		defer cancel()
+		plan, err := l.explain(explainCtx, query, argsCopy)
+		if err != nil {
+			logger.Debug("storage", "获取慢查询执行计划失败", "error", err)
+		} else {
+			l.mu.Lock()
+			if e := l.stats[text]; e != nil {
+				e.Plan = plan
+			}
+			l.mu.Unlock()
+		}
+		l.log(ctx, text, elapsed, errText, plan)
+	}()
+}
+
+// evictLocked 条数已满时淘汰最大耗时最小的一条（新查询更慢时才淘汰），返回是否腾出了位置
+func (l *slowQueryLog) evictLocked(elapsed time.Duration) bool {
+	var victim string
+	var victimMax time.Duration
+	for text, e := range l.stats {
+		if victim == "" || e.MaxTime < victimMax {
+			victim, victimMax = text, e.MaxTime
+		}
+	}
+	if victim == "" || elapsed <= victimMax {
+		return false
+	}
+	delete(l.stats, victim)
+	return true
+}
+
+// log 输出慢查询告警日志（ctx 来自 API 请求时附带 request_id）
+func (l *slowQueryLog) log(ctx context.Context, text string, elapsed time.Duration, errText, plan string) {
+	args := []any{"duration", elapsed.Round(time.Millisecond), "threshold", l.threshold, "sql", truncateText(text, slowQueryLogMaxSQL)}
+	if errText != "" {
+		args = append(args, "error", errText)
+	}
+	if plan != "" {
+		args = append(args, "plan", truncateText(plan, slowQueryLogMaxSQL))
+	}
+	logger.FromContext(ctx, "storage").Warn("慢查询", args...)
+}
+
+// summary 返回启动以来的慢查询汇总（按累计耗时降序取前 top 条）
+func (l *slowQueryLog) summary(top int) SlowQuerySummary {
+	if l == nil {
+		return SlowQuerySummary{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := make([]SlowQueryStat, 0, len(l.stats))
+	for _, e := range l.stats {
+		stats = append(stats, e.SlowQueryStat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TotalTime != stats[j].TotalTime {
+			return stats[i].TotalTime > stats[j].TotalTime
+		}
+		return stats[i].SQL < stats[j].SQL
+	})
+	if top > 0 && len(stats) > top {
+		stats = stats[:top]
+	}
+	return SlowQuerySummary{
+		Enabled:   true,
+		Threshold: l.threshold,
+		Since:     l.since,
+		Total:     l.total,
+		Top:       stats,
+	}
+}
+
+var (
+	// placeholderListRe 匹配由多个占位符组成的列表（VALUES 元组、IN 列表）
+	placeholderListRe = regexp.MustCompile(`\((?:\?|\$\d+)(?:\s*,\s*(?:\?|\$\d+))+\)`)
+	// repeatedListRe 匹配折叠后连续重复的列表（批量 keys CTE、多行 INSERT）
+	repeatedListRe = regexp.MustCompile(`\(\.\.\.\)(?:\s*,\s*\(\.\.\.\))+`)
+)
+
+// normalizeSQL 折叠空白与占位符列表，不同批次大小的同一条 SQL 聚合到同一个 key
+func normalizeSQL(query string) string {
+	text := strings.Join(strings.Fields(query), " ")
+	text = placeholderListRe.ReplaceAllString(text, "(...)")
+	return repeatedListRe.ReplaceAllString(text, "(...)")
+}
+
+// explainable 仅对查询与 DML 获取执行计划（DDL、事务控制等语句不支持 EXPLAIN）
+func explainable(text string) bool {
+	head, _, _ := strings.Cut(text, " ")
+	switch strings.ToUpper(head) {
+	case "SELECT", "WITH", "UPDATE", "DELETE":
+		return true
+	}
+	return false
+}
+
+// truncateText 截断过长的文本
+func truncateText(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
+var (
+	// sqliteFullScanRe SQLite 执行计划中的全表扫描（"SCAN t"，不含 USING INDEX 的覆盖扫描）
+	sqliteFullScanRe = regexp.MustCompile(`(?m)^\s*SCAN (\w+)\s*$`)
+	// postgresSeqScanRe PostgreSQL 执行计划中的顺序扫描
+	postgresSeqScanRe = regexp.MustCompile(`Seq Scan on (\w+)`)
+	// tableAliasRe SQL 中的 FROM/JOIN 表名与别名（SQLite 执行计划使用别名）
+	tableAliasRe = regexp.MustCompile(`(?i)\b(?:FROM|JOIN)\s+(\w+)(?:\s+(?:AS\s+)?(\w+))?`)
+)
+
+// sqlKeywords 紧跟表名时不是别名的关键字
+var sqlKeywords = map[string]bool{
+	"WHERE": true, "JOIN": true, "LEFT": true, "RIGHT": true, "INNER": true, "CROSS": true, "ON": true,
+	"GROUP": true, "ORDER": true, "LIMIT": true, "UNION": true, "USING": true, "SET": true, "RETURNING": true,
+}
+
+// fullScanTables 从执行计划中提取全表扫描的表名（别名解析为真实表名）
+func fullScanTables(query, plan string) []string {
+	aliases := make(map[string]string)
+	for _, m := range tableAliasRe.FindAllStringSubmatch(query, -1) {
+		aliases[m[1]] = m[1]
+		if m[2] != "" && !sqlKeywords[strings.ToUpper(m[2])] {
+			aliases[m[2]] = m[1]
+		}
+	}
+
+	var tables []string
+	seen := make(map[string]bool)
+	for _, re := range []*regexp.Regexp{sqliteFullScanRe, postgresSeqScanRe} {
+		for _, m := range re.FindAllStringSubmatch(plan, -1) {
+			table := m[1]
+			if real, ok := aliases[table]; ok {
+				table = real
+			}
+			if !seen[table] {
+				seen[table] = true
+				tables = append(tables, table)
+			}
+		}
+	}
+	return tables
+}
+
+// indexAdviceMinRows 全表扫描建议的最小行数（小表全表扫描无需索引）
+const indexAdviceMinRows = 1000
+
+// buildIndexAdvice 基于慢查询执行计划与索引使用统计生成索引建议
+func buildIndexAdvice(d *StorageDiagnostics) []string {
+	rows := make(map[string]int64, len(d.Tables))
+	for _, t := range d.Tables {
+		rows[t.Name] = t.Rows
+	}
+
+	var advice []string
+	for _, q := range d.SlowQuery.Top {
+		for _, table := range fullScanTables(q.SQL, q.Plan) {
+			n, ok := rows[table]
+			if !ok || n < indexAdviceMinRows {
+				continue // CTE、子查询或小表
+			}
+			advice = append(advice, fmt.Sprintf("慢查询对表 %s 全表扫描（约 %d 行），建议为其过滤条件添加索引: %s", table, n, truncateText(q.SQL, 200)))
+		}
+	}
+	for _, idx := range d.Indexes {
+		if idx.Scans != nil && *idx.Scans == 0 && !idx.Unique {
+			advice = append(advice, fmt.Sprintf("索引 %s（表 %s，%.1f MB）自统计重置以来未被使用，可考虑删除", idx.Name, idx.Table, float64(idx.Bytes)/(1<<20)))
+		}
+	}
+	return advice
+}
+
+// sqliteConnector 为 SQLite 连接包装慢查询计时（仅启用慢查询日志时使用）
+type sqliteConnector struct {
+	dsn  string
+	slow *slowQueryLog
+}
+
+// sqliteDriverConn modernc.org/sqlite 连接实现的驱动接口
+type sqliteDriverConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+// Connect 打开连接并包装慢查询计时
+func (c *sqliteConnector) Connect(context.Context) (driver.Conn, error) {
+	conn, err := c.Driver().Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	dc, ok := conn.(sqliteDriverConn)
+	if !ok {
+		return conn, nil // 驱动接口变化时退化为不计时
+	}
+	return &slowQueryConn{sqliteDriverConn: dc, slow: c.slow}, nil
+}
+
+// Driver 返回底层驱动
+func (c *sqliteConnector) Driver() driver.Driver {
+	return &sqlite.Driver{}
+}
+
+// slowQueryConn 记录 ExecContext/QueryContext 耗时的连接（查询耗时统计到结果集关闭为止）
+type slowQueryConn struct {
+	sqliteDriverConn
+	slow *slowQueryLog
+}
+
+// ExecContext 执行语句并记录耗时
+func (c *slowQueryConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	res, err := c.sqliteDriverConn.ExecContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		c.slow.observe(ctx, query, namedValues(args), time.Since(start), err)
+	}
+	return res, err
+}
+
+// QueryContext 执行查询，结果集关闭时记录耗时
+func (c *slowQueryConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := c.sqliteDriverConn.QueryContext(ctx, query, args)
+	if err != nil {
+		if err != driver.ErrSkip {
+			c.slow.observe(ctx, query, namedValues(args), time.Since(start), err)
+		}
+		return nil, err
+	}
+	return &slowQueryRows{Rows: rows, ctx: ctx, query: query, args: args, start: start, slow: c.slow}, nil
+}
+
+// slowQueryRows 关闭时记录查询总耗时（含逐行读取）
+type slowQueryRows struct {
+	driver.Rows
+	ctx   context.Context
+	query string
+	args  []driver.NamedValue
+	start time.Time
+	slow  *slowQueryLog
+}
+
+// Close 关闭结果集并记录耗时
+func (r *slowQueryRows) Close() error {
+	err := r.Rows.Close()
+	r.slow.observe(r.ctx, r.query, namedValues(r.args), time.Since(r.start), nil)
+	return err
+}
+
+// namedValues 转换驱动参数为 EXPLAIN 可用的位置参数
+func namedValues(args []driver.NamedValue) []any {
+	values := make([]any, len(args))
+	for i, a := range args {
+		values[i] = a.Value
+	}
+	return values
+}
+
+// pgxSlowQueryTracer 基于 pgx QueryTracer 的慢查询计时（查询耗时统计到结果集关闭为止）
+type pgxSlowQueryTracer struct {
+	slow *slowQueryLog
+}
+
+// pgxTraceKey pgx 查询开始信息在 context 中的 key
+type pgxTraceKey struct{}
+
+// pgxTraceData 查询开始信息
+type pgxTraceData struct {
+	start time.Time
+	sql   string
+	args  []any
+}
+
+// TraceQueryStart 记录查询开始时间
+func (t *pgxSlowQueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, pgxTraceKey{}, &pgxTraceData{start: time.Now(), sql: data.SQL, args: data.Args})
+}
+
+// TraceQueryEnd 记录查询耗时
+func (t *pgxSlowQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	td, ok := ctx.Value(pgxTraceKey{}).(*pgxTraceData)
+	if !ok {
+		return
+	}
+	t.slow.observe(ctx, td.sql, td.args, time.Since(td.start), data.Err)
+}
@@ -5,9 +5,13 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
+	"monitor/internal/config"
 	"monitor/internal/logger"
 
 	_ "modernc.org/sqlite" // 纯Go实现的SQLite驱动
@@ -15,17 +19,30 @@ import (
 
 // SQLiteStorage SQLite存储实现
 type SQLiteStorage struct {
-	db  *sql.DB
-	ctx context.Context
+	db    *sql.DB
+	ctx   context.Context
+	queue *writeQueue   // 批量写入队列（nil 表示逐条写入）
+	slow  *slowQueryLog // 慢查询日志（nil 表示未启用）
 }
 
 // NewSQLiteStorage 创建SQLite存储
 func NewSQLiteStorage(dbPath string) (*SQLiteStorage, error) {
+	return newSQLiteStorage(dbPath, nil)
+}
+
+// newSQLiteStorage 创建SQLite存储（slow 非 nil 时包装驱动连接记录慢查询）
+func newSQLiteStorage(dbPath string, slow *slowQueryLog) (*SQLiteStorage, error) {
 	// 使用WAL模式和其他参数解决并发锁问题
 	dsn := fmt.Sprintf("file:%s?_journal_mode=WAL&_timeout=5000&_busy_timeout=5000", dbPath)
-	db, err := sql.Open("sqlite", dsn)
-	if err != nil {
-		return nil, fmt.Errorf("打开数据库失败: %w", err)
+	var db *sql.DB
+	if slow != nil {
+		db = sql.OpenDB(&sqliteConnector{dsn: dsn, slow: slow})
+	} else {
+		var err error
+		db, err = sql.Open("sqlite", dsn)
+		if err != nil {
+			return nil, fmt.Errorf("打开数据库失败: %w", err)
+		}
 	}
 
 	// 设置连接池参数（WAL模式支持更好的并发）
@@ -33,7 +50,9 @@ func NewSQLiteStorage(dbPath string) (*SQLiteStorage, error) {
 	db.SetMaxIdleConns(1)
 	db.SetConnMaxLifetime(time.Hour)
 
-	return &SQLiteStorage{db: db, ctx: context.Background()}, nil
+	s := &SQLiteStorage{db: db, ctx: context.Background(), slow: slow}
+	slow.setExplain(s.explain)
+	return s, nil
 }
 
 // WithContext 返回绑定指定 context 的存储实例
@@ -42,8 +61,10 @@ func (s *SQLiteStorage) WithContext(ctx context.Context) Storage {
 		return s
 	}
 	return &SQLiteStorage{
-		db:  s.db,
-		ctx: ctx,
+		db:    s.db,
+		ctx:   ctx,
+		queue: s.queue,
+		slow:  s.slow,
 	}
 }
 
@@ -90,6 +111,20 @@ func (s *SQLiteStorage) Init() error {
 	if err := s.ensureModelColumn(); err != nil {
 		return err
 	}
+	if err := s.ensureProbeHistoryColumn("cert_days_remaining", "INTEGER"); err != nil {
+		return err
+	}
+	for _, column := range []string{"dns_ms", "connect_ms", "tls_ms", "ttfb_ms"} {
+		if err := s.ensureProbeHistoryColumn(column, "INTEGER"); err != nil {
+			return err
+		}
+	}
+	if err := s.ensureProbeHistoryColumn("attempts", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := s.ensureProbeHistoryColumn("ip_family", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
 
 	// 在列迁移完成后创建索引
 	//
@@ -136,6 +171,81 @@ func (s *SQLiteStorage) Init() error {
 		return err
 	}
 
+	// 用量统计表
+	if err := s.initUsageTable(ctx); err != nil {
+		return err
+	}
+
+	// 每日可用性汇总表
+	if err := s.initDailyRollupTable(ctx); err != nil {
+		return err
+	}
+
+	// 自助测试分享结果表
+	if err := s.initSelfTestResultTable(ctx); err != nil {
+		return err
+	}
+
+	// 审计日志表
+	if err := s.initAuditTable(ctx); err != nil {
+		return err
+	}
+
+	// 探测调试快照表
+	if err := s.initProbeDebugTable(ctx); err != nil {
+		return err
+	}
+
+	// 事件 Webhook 死信表
+	if err := s.initWebhookDeadLetterTable(ctx); err != nil {
+		return err
+	}
+
+	// 事件发件箱表
+	if err := s.initEventOutboxTable(ctx); err != nil {
+		return err
+	}
+
+	// 探测数据透明度小时根表
+	if err := s.initTransparencyTable(ctx); err != nil {
+		return err
+	}
+
+	// 已下线监测项登记表
+	if err := s.initDecommissionTable(ctx); err != nil {
+		return err
+	}
+
+	// 服务商数据访问令牌表
+	if err := s.initProviderTokenTable(ctx); err != nil {
+		return err
+	}
+
+	// 运维标注表
+	if err := s.initAnnotationTable(ctx); err != nil {
+		return err
+	}
+
+	// 自定义分组表
+	if err := s.initCustomGroupTable(ctx); err != nil {
+		return err
+	}
+
+	// 服务商元数据修改申请表
+	if err := s.initMetadataRequestTable(ctx); err != nil {
+		return err
+	}
+
+	// 监测项元数据版本表
+	if err := s.initMonitorMetadataTable(ctx); err != nil {
+		return err
+	}
+
+	// 管理后台用户与会话表
+	if err := s.initAdminUserTables(ctx); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -319,6 +429,50 @@ func (s *SQLiteStorage) ensureModelColumn() error {
 	return nil
 }
 
+// ensureProbeHistoryColumn 确保 probe_history 表存在指定列（不存在时按 ddl 类型添加）
+func (s *SQLiteStorage) ensureProbeHistoryColumn(column, ddl string) error {
+	ctx := s.effectiveCtx()
+	rows, err := s.db.QueryContext(ctx, `PRAGMA table_info(probe_history)`)
+	if err != nil {
+		return fmt.Errorf("查询表结构失败: %w", err)
+	}
+	defer rows.Close()
+
+	hasColumn := false
+	for rows.Next() {
+		var (
+			cid          int
+			name         string
+			colType      string
+			notNull      int
+			defaultValue sql.NullString
+			pk           int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return fmt.Errorf("扫描表结构失败: %w", err)
+		}
+		if name == column {
+			hasColumn = true
+			break
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("遍历表结构失败: %w", err)
+	}
+
+	if hasColumn {
+		return nil
+	}
+
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE probe_history ADD COLUMN %s %s`, column, ddl)); err != nil {
+		return fmt.Errorf("添加 %s 列失败: %w", column, err)
+	}
+
+	logger.Info("storage", "已为 probe_history 表添加列", "column", column)
+	return nil
+}
+
 // MigrateChannelData 根据配置将 channel 为空的旧数据迁移到指定 channel
 func (s *SQLiteStorage) MigrateChannelData(mappings []ChannelMigrationMapping) error {
 	ctx := s.effectiveCtx()
@@ -380,17 +534,31 @@ func (s *SQLiteStorage) MigrateChannelData(mappings []ChannelMigrationMapping) e
 	return nil
 }
 
-// Close 关闭数据库
+// enableWriteBatch 启用探测记录批量写入
+func (s *SQLiteStorage) enableWriteBatch(cfg config.WriteBatchConfig) {
+	s.queue = newWriteQueue(cfg, s.saveRecordsBatch)
+}
+
+// Close 关闭数据库（先写完批量队列中的剩余记录）
 func (s *SQLiteStorage) Close() error {
+	if s.queue != nil {
+		s.queue.close()
+	}
 	return s.db.Close()
 }
 
 // SaveRecord 保存探测记录
 func (s *SQLiteStorage) SaveRecord(record *ProbeRecord) error {
-	ctx := s.effectiveCtx()
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "SaveRecord")
+	defer span.End()
+	if s.queue != nil {
+		return s.queue.submit(ctx, record)
+	}
+
 	query := `
-		INSERT INTO probe_history (provider, service, channel, model, status, sub_status, http_code, latency, timestamp)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO probe_history (provider, service, channel, model, status, sub_status, http_code, latency, timestamp, cert_days_remaining,
+			dns_ms, connect_ms, tls_ms, ttfb_ms, attempts, ip_family)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := s.db.ExecContext(ctx, query,
@@ -403,6 +571,13 @@ func (s *SQLiteStorage) SaveRecord(record *ProbeRecord) error {
 		record.HttpCode,
 		record.Latency,
 		record.Timestamp,
+		record.CertDaysRemaining,
+		record.DNSMs,
+		record.ConnectMs,
+		record.TLSMs,
+		record.TTFBMs,
+		record.Attempts,
+		record.IPFamily,
 	)
 
 	if err != nil {
@@ -411,6 +586,86 @@ func (s *SQLiteStorage) SaveRecord(record *ProbeRecord) error {
 
 	id, _ := result.LastInsertId()
 	record.ID = id
+
+	// 同步累加每日汇总（失败仅记录警告，不影响原始记录写入）
+	if err := s.addDailyRollup(ctx, record); err != nil {
+		logger.Warn("storage", "累加每日汇总失败", "provider", record.Provider, "service", record.Service, "error", err)
+	}
+	return nil
+}
+
+const (
+	// sqliteMaxVariables SQLite 单条语句默认的绑定参数上限（SQLITE_MAX_VARIABLE_NUMBER，3.32 之前为 999）
+	sqliteMaxVariables = 999
+
+	// sqliteInsertColumns 批量写入 probe_history 的列数（增减列时同步修改）
+	sqliteInsertColumns = 16
+
+	// sqliteInsertChunk 单条多行 INSERT 的最大行数，由参数上限与列数推导
+	sqliteInsertChunk = sqliteMaxVariables / sqliteInsertColumns
+
+	// sqliteKeyChunk 单条查询 keys CTE 的最大监测项数（每个 key 4 个绑定参数）
+	sqliteKeyChunk = sqliteMaxVariables / 4
+)
+
+// saveRecordsBatch 在单个事务内以多行 INSERT 写入一批探测记录（批量写入队列调用）
+func (s *SQLiteStorage) saveRecordsBatch(ctx context.Context, records []*ProbeRecord) error {
+	ctx, span := startSpan(ctx, "sqlite", "SaveRecordsBatch")
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开启批量写入事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 占位符按列数生成：列清单与参数不一致时直接报错，而不是悄悄突破参数上限
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", sqliteInsertColumns), ", ") + ")"
+	for start := 0; start < len(records); start += sqliteInsertChunk {
+		chunk := records[start:min(start+sqliteInsertChunk, len(records))]
+
+		var sb strings.Builder
+		sb.WriteString(`INSERT INTO probe_history (provider, service, channel, model, status, sub_status, http_code, latency, timestamp, cert_days_remaining,
+			dns_ms, connect_ms, tls_ms, ttfb_ms, attempts, ip_family) VALUES `)
+		args := make([]any, 0, len(chunk)*sqliteInsertColumns)
+		for i, r := range chunk {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(row)
+			args = append(args,
+				r.Provider, r.Service, r.Channel, r.Model, r.Status, string(r.SubStatus), r.HttpCode,
+				r.Latency, r.Timestamp, r.CertDaysRemaining, r.DNSMs, r.ConnectMs, r.TLSMs, r.TTFBMs, r.Attempts, r.IPFamily,
+			)
+		}
+
+		result, err := tx.ExecContext(ctx, sb.String(), args...)
+		if err != nil {
+			return fmt.Errorf("批量保存记录失败: %w", err)
+		}
+
+		// AUTOINCREMENT 且事务内独占连接：本条语句分配的 ID 连续，按 VALUES 顺序回填
+		lastID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("获取批量写入 ID 失败: %w", err)
+		}
+		firstID := lastID - int64(len(chunk)) + 1
+		for i, r := range chunk {
+			r.ID = firstID + int64(i)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交批量写入事务失败: %w", err)
+	}
+
+	// 按 (监测项, 日期) 合并后累加每日汇总（失败仅记录警告，不影响原始记录写入）
+	for _, d := range groupDailyRollups(records) {
+		if err := s.applyDailyRollup(ctx, d); err != nil {
+			logger.Warn("storage", "累加每日汇总失败", "provider", d.Key.Provider, "service", d.Key.Service, "error", err)
+		}
+	}
+	span.SetAttributes(attribute.Int("db.batch_size", len(records)))
 	return nil
 }
 
@@ -420,7 +675,8 @@ func (s *SQLiteStorage) SaveRecord(record *ProbeRecord) error {
 // - 使用 CTE(keys) 承载入参列表
 // - 使用窗口函数 ROW_NUMBER() 分组取最新一条（rn=1）
 func (s *SQLiteStorage) GetLatestBatch(keys []MonitorKey) (map[MonitorKey]*ProbeRecord, error) {
-	ctx := s.effectiveCtx()
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "GetLatestBatch")
+	defer span.End()
 	result := make(map[MonitorKey]*ProbeRecord, len(keys))
 	if len(keys) == 0 {
 		return result, nil
@@ -440,13 +696,13 @@ func (s *SQLiteStorage) GetLatestBatch(keys []MonitorKey) (map[MonitorKey]*Probe
 	b.WriteString(`),
 ranked AS (
 	SELECT
-		p.id, p.provider, p.service, p.channel, p.model, p.status, p.sub_status, p.http_code, p.latency, p.timestamp,
+		p.id, p.provider, p.service, p.channel, p.model, p.status, p.sub_status, p.http_code, p.latency, p.timestamp, p.cert_days_remaining,
 		ROW_NUMBER() OVER (PARTITION BY p.provider, p.service, p.channel, p.model ORDER BY p.timestamp DESC, p.id DESC) AS rn
 	FROM probe_history p
 	JOIN keys k
 		ON p.provider = k.provider AND p.service = k.service AND p.channel = k.channel AND p.model = k.model
 )
-SELECT id, provider, service, channel, model, status, sub_status, http_code, latency, timestamp
+SELECT id, provider, service, channel, model, status, sub_status, http_code, latency, timestamp, cert_days_remaining
 FROM ranked
 WHERE rn = 1
 `)
@@ -471,6 +727,7 @@ WHERE rn = 1
 			&rec.HttpCode,
 			&rec.Latency,
 			&rec.Timestamp,
+			&rec.CertDaysRemaining,
 		); err != nil {
 			return nil, fmt.Errorf("扫描最新记录失败: %w", err)
 		}
@@ -492,7 +749,8 @@ WHERE rn = 1
 // - ORDER BY 按 (provider,service,channel,timestamp DESC) 输出
 // - 返回前对每个 key 的切片做 reverse，保证时间升序（与 GetHistory 一致）
 func (s *SQLiteStorage) GetHistoryBatch(keys []MonitorKey, since time.Time) (map[MonitorKey][]*ProbeRecord, error) {
-	ctx := s.effectiveCtx()
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "GetHistoryBatch")
+	defer span.End()
 	result := make(map[MonitorKey][]*ProbeRecord, len(keys))
 	if len(keys) == 0 {
 		return result, nil
@@ -512,7 +770,8 @@ func (s *SQLiteStorage) GetHistoryBatch(keys []MonitorKey, since time.Time) (map
 	b.WriteString(")\n")
 	b.WriteString(`
 SELECT
-	p.id, p.provider, p.service, p.channel, p.model, p.status, p.sub_status, p.http_code, p.latency, p.timestamp
+	p.id, p.provider, p.service, p.channel, p.model, p.status, p.sub_status, p.http_code, p.latency, p.timestamp,
+	p.dns_ms, p.connect_ms, p.tls_ms, p.ttfb_ms
 FROM probe_history p
 JOIN keys k
 	ON p.provider = k.provider AND p.service = k.service AND p.channel = k.channel AND p.model = k.model
@@ -541,6 +800,10 @@ ORDER BY p.provider, p.service, p.channel, p.model, p.timestamp DESC
 			&rec.HttpCode,
 			&rec.Latency,
 			&rec.Timestamp,
+			&rec.DNSMs,
+			&rec.ConnectMs,
+			&rec.TLSMs,
+			&rec.TTFBMs,
 		); err != nil {
 			return nil, fmt.Errorf("扫描历史记录失败: %w", err)
 		}
@@ -561,87 +824,469 @@ ORDER BY p.provider, p.service, p.channel, p.model, p.timestamp DESC
 	return result, nil
 }
 
-// GetLatest 获取最新记录
-func (s *SQLiteStorage) GetLatest(provider, service, channel, model string) (*ProbeRecord, error) {
-	ctx := s.effectiveCtx()
-	query := `
-		SELECT id, provider, service, channel, model, status, sub_status, http_code, latency, timestamp
-		FROM probe_history
-		WHERE provider = ? AND service = ? AND channel = ? AND model = ?
-		ORDER BY timestamp DESC, id DESC
-		LIMIT 1
-	`
-
-	var record ProbeRecord
-	var subStatusStr string
-	err := s.db.QueryRowContext(ctx, query, provider, service, channel, model).Scan(
-		&record.ID,
-		&record.Provider,
-		&record.Service,
-		&record.Channel,
-		&record.Model,
-		&record.Status,
-		&subStatusStr,
-		&record.HttpCode,
-		&record.Latency,
-		&record.Timestamp,
-	)
+// GetTimelineAggBatch 批量获取多个监测项的时间轴 bucket 聚合结果（时间范围）
+//
+// 与 PostgreSQL 实现语义一致（bucket 归属、边界排除、时段过滤、统计口径均对齐 api.buildTimeline），
+// 小型部署查询长周期时无需把全部原始记录拉到应用层。
+//
+// SQLite 差异：
+// - 仅 keys 使用绑定参数（每个 key 4 个），其余整数直接写入 SQL；keys 按 sqliteKeyChunk 分批查询，避免超出参数上限
+// - SQLite 没有时区库，时段过滤按固定 UTC 偏移换算本地时钟；查询范围内跨越夏令时切换时返回错误，由 API 回退到应用层聚合
+func (s *SQLiteStorage) GetTimelineAggBatch(keys []MonitorKey, since, endTime time.Time, bucketCount int, bucketWindow time.Duration, timeFilter *DailyTimeFilter) (map[MonitorKey][]AggBucketRow, error) {
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "GetTimelineAggBatch")
+	defer span.End()
+	result := make(map[MonitorKey][]AggBucketRow, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+	if bucketCount <= 0 {
+		return result, nil
+	}
 
-	if err == sql.ErrNoRows {
-		return nil, nil // 没有记录不算错误
+	windowSec := int64(bucketWindow / time.Second)
+	if windowSec <= 0 {
+		return nil, fmt.Errorf("无效的 bucketWindow: %s", bucketWindow)
 	}
 
-	if err != nil {
-		return nil, fmt.Errorf("查询最新记录失败: %w", err)
+	sinceUnix := since.Unix()
+	endUnix := endTime.Unix()
+
+	// 时段过滤条件（按 timeFilter.Timezone 的本地时钟，默认 UTC，左闭右开）
+	timeFilterCond := ""
+	if timeFilter != nil {
+		offset, err := fixedZoneOffset(timeFilter.Timezone, since, endTime)
+		if err != nil {
+			return nil, err
+		}
+		localExpr := fmt.Sprintf("p.timestamp + %d", offset)
+		minutesExpr := fmt.Sprintf("(CAST(strftime('%%H', %s, 'unixepoch') AS INTEGER) * 60 + CAST(strftime('%%M', %s, 'unixepoch') AS INTEGER))", localExpr, localExpr)
+		if timeFilter.CrossMidnight {
+			// 跨午夜： [start, 24:00) ∪ [00:00, end)
+			timeFilterCond = fmt.Sprintf(" AND (%s >= %d OR %s < %d)", minutesExpr, timeFilter.StartMinutes, minutesExpr, timeFilter.EndMinutes)
+		} else {
+			// 正常： [start, end)
+			timeFilterCond = fmt.Sprintf(" AND (%s >= %d AND %s < %d)", minutesExpr, timeFilter.StartMinutes, minutesExpr, timeFilter.EndMinutes)
+		}
 	}
 
-	record.SubStatus = SubStatus(subStatusStr)
-	return &record, nil
+	var b strings.Builder
+
+	// bucketed：计算 bucket_idx（整数除法，与 api.buildTimeline 一致），排除 (since, endTime] 以外与超出窗口的边界数据
+	// filtered：按 (timestamp DESC, id DESC) 标记 bucket 内最新一条，用于 last_status
+	fmt.Fprintf(&b, `
+, bucketed AS (
+	SELECT
+		p.id, p.provider, p.service, p.channel, p.model, p.status, p.sub_status, p.http_code, p.latency, p.timestamp,
+		p.dns_ms, p.connect_ms, p.tls_ms, p.ttfb_ms,
+		%d - 1 - ((%d - p.timestamp) / %d) AS bucket_idx
+	FROM probe_history p
+	JOIN keys k
+		ON p.provider = k.provider AND p.service = k.service AND p.channel = k.channel AND p.model = k.model
+	WHERE p.timestamp > %d
+	  AND p.timestamp <= %d
+	  AND ((%d - p.timestamp) / %d) < %d
+%s
+)
+, filtered AS (
+	SELECT
+		*,
+		ROW_NUMBER() OVER (PARTITION BY provider, service, channel, model, bucket_idx ORDER BY timestamp DESC, id DESC) AS rn
+	FROM bucketed
+)
+`, bucketCount, endUnix, windowSec, sinceUnix, endUnix, endUnix, windowSec, bucketCount, timeFilterCond)
+
+	// http_code_breakdown：仅统计红色(status==0)且有有效 http_code 的记录
+	// sub_status 范围需与 api.incrementStatusCount 完全一致
+	b.WriteString(`
+, http_code_counts AS (
+	SELECT
+		provider, service, channel, model, bucket_idx, sub_status, http_code, COUNT(*) AS cnt
+	FROM filtered
+	WHERE status = 0
+	  AND http_code > 0
+	  AND sub_status IN ('server_error','client_error','auth_error','invalid_request','rate_limit')
+	GROUP BY provider, service, channel, model, bucket_idx, sub_status, http_code
+)
+, http_code_sub_agg AS (
+	SELECT
+		provider, service, channel, model, bucket_idx, sub_status,
+		json_group_object(CAST(http_code AS TEXT), cnt) AS codes
+	FROM http_code_counts
+	GROUP BY provider, service, channel, model, bucket_idx, sub_status
+)
+, http_code_bucket_agg AS (
+	SELECT
+		provider, service, channel, model, bucket_idx,
+		json_group_object(sub_status, json(codes)) AS breakdown
+	FROM http_code_sub_agg
+	GROUP BY provider, service, channel, model, bucket_idx
+)
+SELECT
+	f.provider,
+	f.service,
+	f.channel,
+	f.model,
+	f.bucket_idx,
+	COUNT(*) AS total,
+	MAX(CASE WHEN f.rn = 1 THEN f.status END) AS last_status,
+	COALESCE(SUM(CASE WHEN f.status > 0 THEN f.latency ELSE 0 END), 0) AS latency_sum,
+	COALESCE(SUM(CASE WHEN f.status > 0 THEN 1 ELSE 0 END), 0) AS latency_count,
+	COALESCE(SUM(CASE WHEN f.latency > 0 THEN f.latency ELSE 0 END), 0) AS all_latency_sum,
+	COALESCE(SUM(CASE WHEN f.latency > 0 THEN 1 ELSE 0 END), 0) AS all_latency_count,
+
+	COALESCE(SUM(f.dns_ms), 0) AS dns_sum,
+	COUNT(f.dns_ms) AS dns_count,
+	COALESCE(SUM(f.connect_ms), 0) AS connect_sum,
+	COUNT(f.connect_ms) AS connect_count,
+	COALESCE(SUM(f.tls_ms), 0) AS tls_sum,
+	COUNT(f.tls_ms) AS tls_count,
+	COALESCE(SUM(f.ttfb_ms), 0) AS ttfb_sum,
+	COUNT(f.ttfb_ms) AS ttfb_count,
+
+	COALESCE(SUM(CASE WHEN f.status = 1 THEN 1 ELSE 0 END), 0) AS available,
+	COALESCE(SUM(CASE WHEN f.status = 2 THEN 1 ELSE 0 END), 0) AS degraded,
+	COALESCE(SUM(CASE WHEN f.status = 0 THEN 1 ELSE 0 END), 0) AS unavailable,
+	COALESCE(SUM(CASE WHEN f.status NOT IN (0,1,2) THEN 1 ELSE 0 END), 0) AS missing,
+
+	COALESCE(SUM(CASE WHEN f.status = 2 AND f.sub_status = 'slow_latency' THEN 1 ELSE 0 END), 0) AS slow_latency,
+	COALESCE(SUM(CASE WHEN f.sub_status = 'rate_limit' AND f.status IN (0,2) THEN 1 ELSE 0 END), 0) AS rate_limit,
+	COALESCE(SUM(CASE WHEN f.status = 2 AND f.sub_status = 'cert_expiring' THEN 1 ELSE 0 END), 0) AS cert_expiring,
+	COALESCE(SUM(CASE WHEN f.status = 2 AND f.sub_status = 'content_drift' THEN 1 ELSE 0 END), 0) AS content_drift,
+
+	COALESCE(SUM(CASE WHEN f.status = 0 AND f.sub_status = 'server_error' THEN 1 ELSE 0 END), 0) AS server_error,
+	COALESCE(SUM(CASE WHEN f.status = 0 AND f.sub_status = 'client_error' THEN 1 ELSE 0 END), 0) AS client_error,
+	COALESCE(SUM(CASE WHEN f.status = 0 AND f.sub_status = 'auth_error' THEN 1 ELSE 0 END), 0) AS auth_error,
+	COALESCE(SUM(CASE WHEN f.status = 0 AND f.sub_status = 'invalid_request' THEN 1 ELSE 0 END), 0) AS invalid_request,
+	COALESCE(SUM(CASE WHEN f.status = 0 AND f.sub_status = 'network_error' THEN 1 ELSE 0 END), 0) AS network_error,
+	COALESCE(SUM(CASE WHEN f.status = 0 AND f.sub_status = 'content_mismatch' THEN 1 ELSE 0 END), 0) AS content_mismatch,
+	COALESCE(SUM(CASE WHEN f.status = 0 AND f.sub_status = 'ipv6_only_failure' THEN 1 ELSE 0 END), 0) AS ipv6_only_failure,
+	COALESCE(SUM(CASE WHEN f.status = 0 AND f.sub_status = 'probe_panic' THEN 1 ELSE 0 END), 0) AS probe_panic,
+
+	h.breakdown AS http_code_breakdown
+FROM filtered f
+LEFT JOIN http_code_bucket_agg h
+	ON h.provider = f.provider AND h.service = f.service AND h.channel = f.channel AND h.model = f.model AND h.bucket_idx = f.bucket_idx
+GROUP BY
+	f.provider, f.service, f.channel, f.model, f.bucket_idx, h.breakdown
+ORDER BY
+	f.provider, f.service, f.channel, f.model, f.bucket_idx
+`)
+
+	// 各批 key 互不重叠，结果直接合并
+	for start := 0; start < len(keys); start += sqliteKeyChunk {
+		chunk := keys[start:min(start+sqliteKeyChunk, len(keys))]
+		if err := s.queryTimelineAgg(ctx, chunk, b.String(), result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
 }
 
-// GetHistory 获取历史记录
-func (s *SQLiteStorage) GetHistory(provider, service, channel, model string, since time.Time) ([]*ProbeRecord, error) {
-	ctx := s.effectiveCtx()
-	// 使用 ORDER BY timestamp DESC 以利用索引（索引是 timestamp DESC）
-	// 返回前在 Go 代码中反转为时间升序
-	query := `
-		SELECT id, provider, service, channel, model, status, sub_status, http_code, latency, timestamp
-		FROM probe_history
-		WHERE provider = ? AND service = ? AND channel = ? AND model = ? AND timestamp >= ?
-		ORDER BY timestamp DESC
-	`
+// queryTimelineAgg 以 keys CTE 加聚合查询体执行一批时间轴聚合，结果写入 result
+func (s *SQLiteStorage) queryTimelineAgg(ctx context.Context, keys []MonitorKey, body string, result map[MonitorKey][]AggBucketRow) error {
+	var b strings.Builder
+	args := make([]any, 0, len(keys)*4)
 
-	rows, err := s.db.QueryContext(ctx, query, provider, service, channel, model, since.Unix())
+	b.WriteString("WITH keys(provider, service, channel, model) AS (VALUES ")
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("(?, ?, ?, ?)")
+		args = append(args, k.Provider, k.Service, k.Channel, k.Model)
+	}
+	b.WriteString(")\n")
+	b.WriteString(body)
+
+	rows, err := s.db.QueryContext(ctx, b.String(), args...)
 	if err != nil {
-		return nil, fmt.Errorf("查询历史记录失败: %w", err)
+		return fmt.Errorf("批量查询时间轴聚合失败: %w", err)
 	}
 	defer rows.Close()
 
-	var records []*ProbeRecord
 	for rows.Next() {
-		var record ProbeRecord
-		var subStatusStr string
-		err := rows.Scan(
-			&record.ID,
-			&record.Provider,
-			&record.Service,
-			&record.Channel,
-			&record.Model,
-			&record.Status,
-			&subStatusStr,
-			&record.HttpCode,
-			&record.Latency,
-			&record.Timestamp,
+		var (
+			key          MonitorKey
+			row          AggBucketRow
+			breakdownRaw sql.NullString
 		)
-		if err != nil {
-			return nil, fmt.Errorf("扫描记录失败: %w", err)
+		sc := &row.StatusCounts
+		if err := rows.Scan(
+			&key.Provider,
+			&key.Service,
+			&key.Channel,
+			&key.Model,
+			&row.BucketIndex,
+			&row.Total,
+			&row.LastStatus,
+			&row.LatencySum,
+			&row.LatencyCount,
+			&row.AllLatencySum,
+			&row.AllLatencyCount,
+			&row.Phases.DNSSum,
+			&row.Phases.DNSCount,
+			&row.Phases.ConnectSum,
+			&row.Phases.ConnectCount,
+			&row.Phases.TLSSum,
+			&row.Phases.TLSCount,
+			&row.Phases.TTFBSum,
+			&row.Phases.TTFBCount,
+			&sc.Available,
+			&sc.Degraded,
+			&sc.Unavailable,
+			&sc.Missing,
+			&sc.SlowLatency,
+			&sc.RateLimit,
+			&sc.CertExpiring,
+			&sc.ContentDrift,
+			&sc.ServerError,
+			&sc.ClientError,
+			&sc.AuthError,
+			&sc.InvalidRequest,
+			&sc.NetworkError,
+			&sc.ContentMismatch,
+			&sc.IPv6OnlyFailure,
+			&sc.ProbePanic,
+			&breakdownRaw,
+		); err != nil {
+			return fmt.Errorf("扫描时间轴聚合结果失败: %w", err)
 		}
-		record.SubStatus = SubStatus(subStatusStr)
-		records = append(records, &record)
-	}
 
-	// 检查迭代过程中是否发生错误
-	if err := rows.Err(); err != nil {
+		// 没有红色 HTTP 错误的 bucket 为 NULL，保持 nil（omitempty 会省略）
+		if breakdownRaw.Valid && breakdownRaw.String != "" {
+			if err := json.Unmarshal([]byte(breakdownRaw.String), &sc.HttpCodeBreakdown); err != nil {
+				return fmt.Errorf("解析 http_code_breakdown 失败: %w", err)
+			}
+			if len(sc.HttpCodeBreakdown) == 0 {
+				sc.HttpCodeBreakdown = nil
+			}
+		}
+
+		result[key] = append(result[key], row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("迭代时间轴聚合结果失败: %w", err)
+	}
+	return nil
+}
+
+// fixedZoneOffset 返回时区在 [since, endTime] 内的固定 UTC 偏移（秒）
+// 按天采样偏移量，范围内跨越夏令时切换时返回错误
+func fixedZoneOffset(tz string, since, endTime time.Time) (int, error) {
+	if tz == "" || tz == "UTC" {
+		return 0, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return 0, fmt.Errorf("无效的时区 %q: %w", tz, err)
+	}
+	_, offset := endTime.In(loc).Zone()
+	for t := since; t.Before(endTime); t = t.Add(24 * time.Hour) {
+		if _, o := t.In(loc).Zone(); o != offset {
+			return 0, fmt.Errorf("时区 %s 在查询范围内存在夏令时切换，SQLite 无法按本地时钟过滤时段", tz)
+		}
+	}
+	return offset, nil
+}
+
+// GetHistoryPage 按 (timestamp, id) 升序分页获取单个监测项的原始记录（/api/export）
+func (s *SQLiteStorage) GetHistoryPage(key MonitorKey, after HistoryCursor, until time.Time, limit int) ([]*ProbeRecord, error) {
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "GetHistoryPage")
+	defer span.End()
+	// 游标条件拆为 timestamp 范围 + 同秒 id 过滤，保证走 (provider, service, channel, model, timestamp) 索引
+	query := `
+		SELECT id, provider, service, channel, model, status, sub_status, http_code, latency, timestamp,
+			dns_ms, connect_ms, tls_ms, ttfb_ms, attempts, ip_family
+		FROM probe_history
+		WHERE provider = ? AND service = ? AND channel = ? AND model = ?
+			AND timestamp >= ? AND timestamp < ?
+			AND (timestamp > ? OR id > ?)
+		ORDER BY timestamp, id
+		LIMIT ?
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, key.Provider, key.Service, key.Channel, key.Model,
+		after.Timestamp, until.Unix(), after.Timestamp, after.ID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("分页查询历史记录失败: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]*ProbeRecord, 0, limit)
+	for rows.Next() {
+		rec := &ProbeRecord{}
+		var subStatusStr string
+		if err := rows.Scan(
+			&rec.ID,
+			&rec.Provider,
+			&rec.Service,
+			&rec.Channel,
+			&rec.Model,
+			&rec.Status,
+			&subStatusStr,
+			&rec.HttpCode,
+			&rec.Latency,
+			&rec.Timestamp,
+			&rec.DNSMs,
+			&rec.ConnectMs,
+			&rec.TLSMs,
+			&rec.TTFBMs,
+			&rec.Attempts,
+			&rec.IPFamily,
+		); err != nil {
+			return nil, fmt.Errorf("扫描历史记录失败: %w", err)
+		}
+		rec.SubStatus = SubStatus(subStatusStr)
+		records = append(records, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代历史记录失败: %w", err)
+	}
+	return records, nil
+}
+
+// GetIPFamilyStats 按 ip_family 分组统计 [since, until) 时间范围内的探测结果（/api/admin/ip-families）
+func (s *SQLiteStorage) GetIPFamilyStats(since, until int64) ([]*IPFamilyStat, error) {
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "GetIPFamilyStats")
+	defer span.End()
+	query := `
+		SELECT provider, service, channel, model, ip_family,
+			COUNT(*),
+			COALESCE(SUM(CASE WHEN status = 1 THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN status = 2 THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN status = 0 THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN status IN (1, 2) THEN latency ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN status = 0 AND sub_status = 'ipv6_only_failure' THEN 1 ELSE 0 END), 0)
+		FROM probe_history
+		WHERE timestamp >= ? AND timestamp < ?
+		GROUP BY provider, service, channel, model, ip_family
+		ORDER BY provider, service, channel, model, ip_family
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("查询地址族统计失败: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*IPFamilyStat
+	for rows.Next() {
+		st := &IPFamilyStat{}
+		if err := rows.Scan(
+			&st.Key.Provider,
+			&st.Key.Service,
+			&st.Key.Channel,
+			&st.Key.Model,
+			&st.Family,
+			&st.Total,
+			&st.Available,
+			&st.Degraded,
+			&st.Unavailable,
+			&st.LatencySum,
+			&st.IPv6OnlyFailures,
+		); err != nil {
+			return nil, fmt.Errorf("扫描地址族统计失败: %w", err)
+		}
+		stats = append(stats, st)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代地址族统计失败: %w", err)
+	}
+	return stats, nil
+}
+
+// GetLatest 获取最新记录
+func (s *SQLiteStorage) GetLatest(provider, service, channel, model string) (*ProbeRecord, error) {
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "GetLatest")
+	defer span.End()
+	query := `
+		SELECT id, provider, service, channel, model, status, sub_status, http_code, latency, timestamp, cert_days_remaining
+		FROM probe_history
+		WHERE provider = ? AND service = ? AND channel = ? AND model = ?
+		ORDER BY timestamp DESC, id DESC
+		LIMIT 1
+	`
+
+	var record ProbeRecord
+	var subStatusStr string
+	err := s.db.QueryRowContext(ctx, query, provider, service, channel, model).Scan(
+		&record.ID,
+		&record.Provider,
+		&record.Service,
+		&record.Channel,
+		&record.Model,
+		&record.Status,
+		&subStatusStr,
+		&record.HttpCode,
+		&record.Latency,
+		&record.Timestamp,
+		&record.CertDaysRemaining,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // 没有记录不算错误
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("查询最新记录失败: %w", err)
+	}
+
+	record.SubStatus = SubStatus(subStatusStr)
+	return &record, nil
+}
+
+// GetHistory 获取历史记录
+func (s *SQLiteStorage) GetHistory(provider, service, channel, model string, since time.Time) ([]*ProbeRecord, error) {
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "GetHistory")
+	defer span.End()
+	// 使用 ORDER BY timestamp DESC 以利用索引（索引是 timestamp DESC）
+	// 返回前在 Go 代码中反转为时间升序
+	query := `
+		SELECT id, provider, service, channel, model, status, sub_status, http_code, latency, timestamp,
+			dns_ms, connect_ms, tls_ms, ttfb_ms
+		FROM probe_history
+		WHERE provider = ? AND service = ? AND channel = ? AND model = ? AND timestamp >= ?
+		ORDER BY timestamp DESC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, provider, service, channel, model, since.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("查询历史记录失败: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*ProbeRecord
+	for rows.Next() {
+		var record ProbeRecord
+		var subStatusStr string
+		err := rows.Scan(
+			&record.ID,
+			&record.Provider,
+			&record.Service,
+			&record.Channel,
+			&record.Model,
+			&record.Status,
+			&subStatusStr,
+			&record.HttpCode,
+			&record.Latency,
+			&record.Timestamp,
+			&record.DNSMs,
+			&record.ConnectMs,
+			&record.TLSMs,
+			&record.TTFBMs,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("扫描记录失败: %w", err)
+		}
+		record.SubStatus = SubStatus(subStatusStr)
+		records = append(records, &record)
+	}
+
+	// 检查迭代过程中是否发生错误
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("迭代记录失败: %w", err)
 	}
 
@@ -720,11 +1365,17 @@ func (s *SQLiteStorage) initEventTables(ctx context.Context) error {
 	if err := s.ensureStatusEventsModelColumn(); err != nil {
 		return err
 	}
+	// 多命名空间：事件表补齐 namespace 列（旧数据归入默认命名空间）
+	if err := s.ensureStatusEventsNamespaceColumn(); err != nil {
+		return err
+	}
 
 	// 创建索引
 	eventsIndexSQL := `
 	CREATE INDEX IF NOT EXISTS idx_status_events_psc_id
 	ON status_events(provider, service, channel, id);
+	CREATE INDEX IF NOT EXISTS idx_status_events_ns_id
+	ON status_events(namespace, id);
 	`
 	if _, err := s.db.ExecContext(ctx, eventsIndexSQL); err != nil {
 		return fmt.Errorf("创建 status_events 索引失败: %w", err)
@@ -897,9 +1548,30 @@ func (s *SQLiteStorage) ensureStatusEventsModelColumn() error {
 	return nil
 }
 
+func (s *SQLiteStorage) ensureStatusEventsNamespaceColumn() error {
+	ctx := s.effectiveCtx()
+	var count int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM pragma_table_info('status_events') WHERE name = 'namespace'`,
+	).Scan(&count); err != nil {
+		return fmt.Errorf("查询 status_events 表结构失败: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if _, err := s.db.ExecContext(ctx, `ALTER TABLE status_events ADD COLUMN namespace TEXT NOT NULL DEFAULT ''`); err != nil {
+		return fmt.Errorf("添加 status_events.namespace 列失败: %w", err)
+	}
+
+	logger.Info("storage", "已为 status_events 表添加 namespace 列")
+	return nil
+}
+
 // GetServiceState 获取服务状态机持久化状态
 func (s *SQLiteStorage) GetServiceState(provider, service, channel, model string) (*ServiceState, error) {
-	ctx := s.effectiveCtx()
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "GetServiceState")
+	defer span.End()
 	query := `
 		SELECT provider, service, channel, model, stable_available, streak_count, streak_status, last_record_id, last_timestamp
 		FROM service_states
@@ -937,7 +1609,8 @@ func (s *SQLiteStorage) GetServiceState(provider, service, channel, model string
 
 // UpsertServiceState 写入或更新服务状态机持久化状态
 func (s *SQLiteStorage) UpsertServiceState(state *ServiceState) error {
-	ctx := s.effectiveCtx()
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "UpsertServiceState")
+	defer span.End()
 	query := `
 		INSERT INTO service_states (provider, service, channel, model, stable_available, streak_count, streak_status, last_record_id, last_timestamp)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
@@ -1089,24 +1762,44 @@ func (s *SQLiteStorage) GetModelStatesForChannel(provider, service, channel stri
 
 // SaveStatusEvent 保存状态变更事件
 func (s *SQLiteStorage) SaveStatusEvent(event *StatusEvent) error {
-	ctx := s.effectiveCtx()
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "SaveStatusEvent")
+	defer span.End()
+
+	args, err := sqliteStatusEventArgs(event)
+	if err != nil {
+		return err
+	}
+	result, err := s.db.ExecContext(ctx, sqliteInsertStatusEventSQL, args...)
+	if err != nil {
+		// 检查是否是唯一约束冲突（幂等处理）
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return nil // 重复事件，视为成功
+		}
+		return fmt.Errorf("保存状态事件失败: %w", err)
+	}
 
-	// 序列化 meta 为 JSON
+	id, _ := result.LastInsertId()
+	event.ID = id
+	return nil
+}
+
+// sqliteInsertStatusEventSQL 写入状态事件（参数见 sqliteStatusEventArgs）
+const sqliteInsertStatusEventSQL = `
+	INSERT INTO status_events (provider, service, channel, model, event_type, from_status, to_status, trigger_record_id, observed_at, created_at, meta, namespace)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+// sqliteStatusEventArgs 构造写入状态事件的参数（meta 序列化为 JSON）
+func sqliteStatusEventArgs(event *StatusEvent) ([]any, error) {
 	var metaJSON sql.NullString
-	if event.Meta != nil && len(event.Meta) > 0 {
+	if len(event.Meta) > 0 {
 		metaBytes, err := json.Marshal(event.Meta)
 		if err != nil {
-			return fmt.Errorf("序列化事件 meta 失败: %w", err)
+			return nil, fmt.Errorf("序列化事件 meta 失败: %w", err)
 		}
 		metaJSON = sql.NullString{String: string(metaBytes), Valid: true}
 	}
-
-	query := `
-		INSERT INTO status_events (provider, service, channel, model, event_type, from_status, to_status, trigger_record_id, observed_at, created_at, meta)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-
-	result, err := s.db.ExecContext(ctx, query,
+	return []any{
 		event.Provider,
 		event.Service,
 		event.Channel,
@@ -1118,24 +1811,26 @@ func (s *SQLiteStorage) SaveStatusEvent(event *StatusEvent) error {
 		event.ObservedAt,
 		event.CreatedAt,
 		metaJSON,
-	)
-
-	if err != nil {
-		// 检查是否是唯一约束冲突（幂等处理）
-		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
-			return nil // 重复事件，视为成功
-		}
-		return fmt.Errorf("保存状态事件失败: %w", err)
-	}
-
-	id, _ := result.LastInsertId()
-	event.ID = id
-	return nil
+		event.Namespace,
+	}, nil
 }
 
 // GetStatusEvents 查询状态变更事件列表
 func (s *SQLiteStorage) GetStatusEvents(sinceID int64, limit int, filters *EventFilters) ([]*StatusEvent, error) {
-	ctx := s.effectiveCtx()
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "GetStatusEvents")
+	defer span.End()
+	return s.queryStatusEvents(ctx, sinceID, limit, filters, false)
+}
+
+// GetRecentStatusEvents 查询最近的状态变更事件（按 id 倒序）
+func (s *SQLiteStorage) GetRecentStatusEvents(limit int, filters *EventFilters) ([]*StatusEvent, error) {
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "GetRecentStatusEvents")
+	defer span.End()
+	return s.queryStatusEvents(ctx, 0, limit, filters, true)
+}
+
+// queryStatusEvents 按游标与过滤条件查询状态事件，desc 为 true 时按 id 倒序
+func (s *SQLiteStorage) queryStatusEvents(ctx context.Context, sinceID int64, limit int, filters *EventFilters, desc bool) ([]*StatusEvent, error) {
 
 	var conditions []string
 	var args []any
@@ -1146,6 +1841,10 @@ func (s *SQLiteStorage) GetStatusEvents(sinceID int64, limit int, filters *Event
 
 	// 可选过滤条件
 	if filters != nil {
+		if filters.Namespace != nil {
+			conditions = append(conditions, "namespace = ?")
+			args = append(args, *filters.Namespace)
+		}
 		if filters.Provider != "" {
 			conditions = append(conditions, "provider = ?")
 			args = append(args, filters.Provider)
@@ -1176,13 +1875,18 @@ func (s *SQLiteStorage) GetStatusEvents(sinceID int64, limit int, filters *Event
 		limit = 500
 	}
 
+	order := "ASC"
+	if desc {
+		order = "DESC"
+	}
+
 	query := fmt.Sprintf(`
-		SELECT id, provider, service, channel, model, event_type, from_status, to_status, trigger_record_id, observed_at, created_at, meta
+		SELECT id, namespace, provider, service, channel, model, event_type, from_status, to_status, trigger_record_id, observed_at, created_at, meta
 		FROM status_events
 		WHERE %s
-		ORDER BY id ASC
+		ORDER BY id %s
 		LIMIT ?
-	`, strings.Join(conditions, " AND "))
+	`, strings.Join(conditions, " AND "), order)
 	args = append(args, limit)
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
@@ -1199,6 +1903,7 @@ func (s *SQLiteStorage) GetStatusEvents(sinceID int64, limit int, filters *Event
 
 		err := rows.Scan(
 			&event.ID,
+			&event.Namespace,
 			&event.Provider,
 			&event.Service,
 			&event.Channel,
@@ -1281,3 +1986,2219 @@ func (s *SQLiteStorage) PurgeOldRecords(ctx context.Context, before time.Time, b
 
 	return affected, nil
 }
+
+// ===== Token 用量统计相关方法 =====
+
+// initUsageTable 初始化用量统计表
+func (s *SQLiteStorage) initUsageTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS usage_daily (
+		provider TEXT NOT NULL,
+		service TEXT NOT NULL,
+		channel TEXT NOT NULL DEFAULT '',
+		model TEXT NOT NULL DEFAULT '',
+		day TEXT NOT NULL,
+		tokens INTEGER NOT NULL DEFAULT 0,
+		cost REAL NOT NULL DEFAULT 0,
+		probes INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (provider, service, channel, model, day)
+	);
+	`
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("创建 usage_daily 表失败: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_usage_daily_day ON usage_daily(day)`); err != nil {
+		return fmt.Errorf("创建 usage_daily 索引失败: %w", err)
+	}
+	return nil
+}
+
+// AddUsage 累加指定监测项某日的用量
+func (s *SQLiteStorage) AddUsage(key MonitorKey, day string, tokens int64, cost float64) error {
+	ctx := s.effectiveCtx()
+	query := `
+		INSERT INTO usage_daily (provider, service, channel, model, day, tokens, cost, probes)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 1)
+		ON CONFLICT(provider, service, channel, model, day) DO UPDATE SET
+			tokens = tokens + excluded.tokens,
+			cost = cost + excluded.cost,
+			probes = probes + 1
+	`
+	if _, err := s.db.ExecContext(ctx, query, key.Provider, key.Service, key.Channel, key.Model, day, tokens, cost); err != nil {
+		return fmt.Errorf("累加用量失败: %w", err)
+	}
+	return nil
+}
+
+// GetUsage 查询日期范围内的用量汇总
+func (s *SQLiteStorage) GetUsage(sinceDay, untilDay string) ([]*UsageRecord, error) {
+	ctx := s.effectiveCtx()
+	query := `
+		SELECT provider, service, channel, model, day, tokens, cost, probes
+		FROM usage_daily
+		WHERE day >= ? AND day <= ?
+		ORDER BY provider, service, channel, model, day
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, sinceDay, untilDay)
+	if err != nil {
+		return nil, fmt.Errorf("查询用量失败: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*UsageRecord
+	for rows.Next() {
+		var r UsageRecord
+		if err := rows.Scan(&r.Provider, &r.Service, &r.Channel, &r.Model, &r.Day, &r.Tokens, &r.Cost, &r.Probes); err != nil {
+			return nil, fmt.Errorf("扫描用量记录失败: %w", err)
+		}
+		records = append(records, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代用量记录失败: %w", err)
+	}
+	return records, nil
+}
+
+// initDailyRollupTable 初始化每日可用性汇总表
+// 首次建表时从 probe_history 回填已有数据，保证升级后热力图立即可用
+func (s *SQLiteStorage) initDailyRollupTable(ctx context.Context) error {
+	var exists int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'probe_daily'`,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("检查 probe_daily 表失败: %w", err)
+	}
+
+	schema := `
+	CREATE TABLE IF NOT EXISTS probe_daily (
+		provider TEXT NOT NULL,
+		service TEXT NOT NULL,
+		channel TEXT NOT NULL DEFAULT '',
+		model TEXT NOT NULL DEFAULT '',
+		day TEXT NOT NULL,
+		total INTEGER NOT NULL DEFAULT 0,
+		green INTEGER NOT NULL DEFAULT 0,
+		yellow INTEGER NOT NULL DEFAULT 0,
+		red INTEGER NOT NULL DEFAULT 0,
+		latency_sum INTEGER NOT NULL DEFAULT 0,
+		latency_count INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (provider, service, channel, model, day)
+	);
+	`
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("创建 probe_daily 表失败: %w", err)
+	}
+	if exists > 0 {
+		return s.ensureDailyRollupLatencyColumns(ctx)
+	}
+
+	backfill := `
+		INSERT INTO probe_daily (provider, service, channel, model, day, total, green, yellow, red, latency_sum, latency_count)
+		SELECT provider, service, channel, model,
+			strftime('%Y-%m-%d', timestamp, 'unixepoch') AS day,
+			COUNT(*),
+			SUM(CASE WHEN status = 1 THEN 1 ELSE 0 END),
+			SUM(CASE WHEN status = 2 THEN 1 ELSE 0 END),
+			SUM(CASE WHEN status = 0 THEN 1 ELSE 0 END),
+			SUM(CASE WHEN status > 0 THEN latency ELSE 0 END),
+			SUM(CASE WHEN status > 0 THEN 1 ELSE 0 END)
+		FROM probe_history
+		GROUP BY provider, service, channel, model, day
+	`
+	result, err := s.db.ExecContext(ctx, backfill)
+	if err != nil {
+		return fmt.Errorf("回填 probe_daily 失败: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		logger.Info("storage", "已从 probe_history 回填每日汇总", "rows", n)
+	}
+	return nil
+}
+
+// ensureDailyRollupLatencyColumns 为旧版 probe_daily 表补充延迟聚合列（旧汇总行保持为 0）
+func (s *SQLiteStorage) ensureDailyRollupLatencyColumns(ctx context.Context) error {
+	rows, err := s.db.QueryContext(ctx, `PRAGMA table_info(probe_daily)`)
+	if err != nil {
+		return fmt.Errorf("查询 probe_daily 表结构失败: %w", err)
+	}
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid          int
+			name         string
+			colType      string
+			notNull      int
+			defaultValue sql.NullString
+			pk           int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			rows.Close()
+			return fmt.Errorf("扫描 probe_daily 表结构失败: %w", err)
+		}
+		columns[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("遍历 probe_daily 表结构失败: %w", err)
+	}
+	rows.Close()
+
+	for _, column := range []string{"latency_sum", "latency_count"} {
+		if columns[column] {
+			continue
+		}
+		if _, err := s.db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE probe_daily ADD COLUMN %s INTEGER NOT NULL DEFAULT 0`, column)); err != nil {
+			return fmt.Errorf("添加 %s 列失败: %w", column, err)
+		}
+		logger.Info("storage", "已为 probe_daily 表添加列", "column", column)
+	}
+	return nil
+}
+
+// addDailyRollup 将单条探测记录累加到每日汇总
+func (s *SQLiteStorage) addDailyRollup(ctx context.Context, record *ProbeRecord) error {
+	return s.applyDailyRollup(ctx, groupDailyRollups([]*ProbeRecord{record})[0])
+}
+
+// applyDailyRollup 将汇总增量累加到每日汇总
+func (s *SQLiteStorage) applyDailyRollup(ctx context.Context, d dailyRollupDelta) error {
+	query := `
+		INSERT INTO probe_daily (provider, service, channel, model, day, total, green, yellow, red, latency_sum, latency_count)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(provider, service, channel, model, day) DO UPDATE SET
+			total = total + excluded.total,
+			green = green + excluded.green,
+			yellow = yellow + excluded.yellow,
+			red = red + excluded.red,
+			latency_sum = latency_sum + excluded.latency_sum,
+			latency_count = latency_count + excluded.latency_count
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		d.Key.Provider, d.Key.Service, d.Key.Channel, d.Key.Model,
+		d.Day, d.Total, d.Green, d.Yellow, d.Red, d.LatencySum, d.LatencyCount,
+	)
+	return err
+}
+
+// GetDailyRollupBatch 批量查询每日可用性汇总
+func (s *SQLiteStorage) GetDailyRollupBatch(keys []MonitorKey, sinceDay, untilDay string) (map[MonitorKey][]DailyRollupRow, error) {
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "GetDailyRollupBatch")
+	defer span.End()
+	result := make(map[MonitorKey][]DailyRollupRow, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	var b strings.Builder
+	args := make([]any, 0, len(keys)*4+2)
+
+	b.WriteString("WITH keys(provider, service, channel, model) AS (VALUES ")
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("(?, ?, ?, ?)")
+		args = append(args, k.Provider, k.Service, k.Channel, k.Model)
+	}
+	b.WriteString(`)
+SELECT d.provider, d.service, d.channel, d.model, d.day, d.total, d.green, d.yellow, d.red, d.latency_sum, d.latency_count
+FROM probe_daily d
+JOIN keys k
+	ON d.provider = k.provider AND d.service = k.service AND d.channel = k.channel AND d.model = k.model
+WHERE d.day >= ? AND d.day <= ?
+ORDER BY d.provider, d.service, d.channel, d.model, d.day
+`)
+	args = append(args, sinceDay, untilDay)
+
+	rows, err := s.db.QueryContext(ctx, b.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("批量查询每日汇总失败: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key MonitorKey
+		var row DailyRollupRow
+		if err := rows.Scan(&key.Provider, &key.Service, &key.Channel, &key.Model, &row.Day, &row.Total, &row.Green, &row.Yellow, &row.Red, &row.LatencySum, &row.LatencyCount); err != nil {
+			return nil, fmt.Errorf("扫描每日汇总失败: %w", err)
+		}
+		result[key] = append(result[key], row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代每日汇总失败: %w", err)
+	}
+	return result, nil
+}
+
+// ===== 自助测试分享结果相关方法 =====
+
+// initSelfTestResultTable 初始化自助测试分享结果表
+func (s *SQLiteStorage) initSelfTestResultTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS selftest_results (
+		id TEXT PRIMARY KEY,
+		test_type TEXT NOT NULL,
+		api_url TEXT NOT NULL,
+		status TEXT NOT NULL,
+		probe_status INTEGER NOT NULL DEFAULT 0,
+		sub_status TEXT NOT NULL DEFAULT '',
+		http_code INTEGER NOT NULL DEFAULT 0,
+		latency INTEGER NOT NULL DEFAULT 0,
+		error_message TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL,
+		finished_at INTEGER NOT NULL,
+		expires_at INTEGER NOT NULL
+	);
+	`
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("创建 selftest_results 表失败: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_selftest_results_expires ON selftest_results(expires_at)`); err != nil {
+		return fmt.Errorf("创建 selftest_results 索引失败: %w", err)
+	}
+	return nil
+}
+
+// SaveSelfTestResult 保存自助测试分享结果
+func (s *SQLiteStorage) SaveSelfTestResult(r *SelfTestResult) error {
+	ctx := s.effectiveCtx()
+	query := `
+		INSERT INTO selftest_results (id, test_type, api_url, status, probe_status, sub_status, http_code, latency, error_message, created_at, finished_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET expires_at = excluded.expires_at
+	`
+	if _, err := s.db.ExecContext(ctx, query,
+		r.ID, r.TestType, r.APIURL, r.Status, r.ProbeStatus, r.SubStatus,
+		r.HTTPCode, r.Latency, r.ErrorMessage, r.CreatedAt, r.FinishedAt, r.ExpiresAt,
+	); err != nil {
+		return fmt.Errorf("保存自助测试分享结果失败: %w", err)
+	}
+	return nil
+}
+
+// GetSelfTestResult 按任务 ID 查询自助测试分享结果
+func (s *SQLiteStorage) GetSelfTestResult(id string) (*SelfTestResult, error) {
+	ctx := s.effectiveCtx()
+	query := `
+		SELECT id, test_type, api_url, status, probe_status, sub_status, http_code, latency, error_message, created_at, finished_at, expires_at
+		FROM selftest_results
+		WHERE id = ?
+	`
+	var r SelfTestResult
+	err := s.db.QueryRowContext(ctx, query, id).Scan(
+		&r.ID, &r.TestType, &r.APIURL, &r.Status, &r.ProbeStatus, &r.SubStatus,
+		&r.HTTPCode, &r.Latency, &r.ErrorMessage, &r.CreatedAt, &r.FinishedAt, &r.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询自助测试分享结果失败: %w", err)
+	}
+	return &r, nil
+}
+
+// PurgeExpiredSelfTestResults 删除已到期的自助测试分享结果
+func (s *SQLiteStorage) PurgeExpiredSelfTestResults(now int64) (int64, error) {
+	ctx := s.effectiveCtx()
+	result, err := s.db.ExecContext(ctx, `DELETE FROM selftest_results WHERE expires_at <= ?`, now)
+	if err != nil {
+		return 0, fmt.Errorf("清理自助测试分享结果失败: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// ===== 审计日志相关方法 =====
+
+// initAuditTable 初始化审计日志表
+func (s *SQLiteStorage) initAuditTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS audit_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		timestamp INTEGER NOT NULL,
+		actor TEXT NOT NULL,
+		action TEXT NOT NULL,
+		target TEXT NOT NULL DEFAULT '',
+		detail TEXT NOT NULL DEFAULT '',
+		ip TEXT NOT NULL DEFAULT '',
+		result TEXT NOT NULL DEFAULT 'success'
+	);
+	`
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("创建 audit_log 表失败: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_audit_log_timestamp ON audit_log(timestamp)`); err != nil {
+		return fmt.Errorf("创建 audit_log 索引失败: %w", err)
+	}
+	return nil
+}
+
+// AddAuditEntry 写入一条审计日志
+func (s *SQLiteStorage) AddAuditEntry(entry *AuditEntry) error {
+	ctx := s.effectiveCtx()
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO audit_log (timestamp, actor, action, target, detail, ip, result)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, entry.Timestamp, entry.Actor, entry.Action, entry.Target, entry.Detail, entry.IP, entry.Result)
+	if err != nil {
+		return fmt.Errorf("写入审计日志失败: %w", err)
+	}
+	entry.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// GetAuditEntries 按条件查询审计日志
+func (s *SQLiteStorage) GetAuditEntries(filter AuditFilter) ([]*AuditEntry, error) {
+	ctx := s.effectiveCtx()
+
+	conditions := []string{"1 = 1"}
+	var args []any
+	if filter.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, filter.Actor)
+	}
+	if filter.Action != "" {
+		if strings.HasSuffix(filter.Action, ".") {
+			conditions = append(conditions, `action LIKE ? ESCAPE '\'`)
+			args = append(args, escapeLikePattern(filter.Action)+"%")
+		} else {
+			conditions = append(conditions, "action = ?")
+			args = append(args, filter.Action)
+		}
+	}
+	if filter.Since > 0 {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, filter.Since)
+	}
+	if filter.Until > 0 {
+		conditions = append(conditions, "timestamp <= ?")
+		args = append(args, filter.Until)
+	}
+	if filter.BeforeID > 0 {
+		conditions = append(conditions, "id < ?")
+		args = append(args, filter.BeforeID)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, timestamp, actor, action, target, detail, ip, result
+		FROM audit_log
+		WHERE %s
+		ORDER BY id DESC
+		LIMIT ?
+	`, strings.Join(conditions, " AND "))
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询审计日志失败: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		if err := rows.Scan(&e.ID, &e.Timestamp, &e.Actor, &e.Action, &e.Target, &e.Detail, &e.IP, &e.Result); err != nil {
+			return nil, fmt.Errorf("扫描审计日志失败: %w", err)
+		}
+		entries = append(entries, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代审计日志失败: %w", err)
+	}
+	return entries, nil
+}
+
+// PurgeAuditEntries 删除过期的审计日志
+func (s *SQLiteStorage) PurgeAuditEntries(before int64) (int64, error) {
+	ctx := s.effectiveCtx()
+	result, err := s.db.ExecContext(ctx, `DELETE FROM audit_log WHERE timestamp < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("清理审计日志失败: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// ===== 探测调试捕获相关方法 =====
+
+// initProbeDebugTable 初始化探测调试快照表
+func (s *SQLiteStorage) initProbeDebugTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS probe_debug (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		provider TEXT NOT NULL,
+		service TEXT NOT NULL,
+		channel TEXT NOT NULL DEFAULT '',
+		model TEXT NOT NULL DEFAULT '',
+		timestamp INTEGER NOT NULL,
+		status INTEGER NOT NULL,
+		sub_status TEXT NOT NULL DEFAULT '',
+		http_code INTEGER NOT NULL DEFAULT 0,
+		latency INTEGER NOT NULL DEFAULT 0,
+		attempts INTEGER NOT NULL DEFAULT 1,
+		method TEXT NOT NULL DEFAULT '',
+		url TEXT NOT NULL DEFAULT '',
+		request_headers TEXT NOT NULL DEFAULT '{}',
+		response_headers TEXT NOT NULL DEFAULT '{}',
+		response_body TEXT NOT NULL DEFAULT '',
+		body_truncated INTEGER NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT '',
+		dns_ms INTEGER NOT NULL DEFAULT -1,
+		connect_ms INTEGER NOT NULL DEFAULT -1,
+		tls_ms INTEGER NOT NULL DEFAULT -1,
+		ttfb_ms INTEGER NOT NULL DEFAULT -1,
+		expires_at INTEGER NOT NULL
+	);
+	`
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("创建 probe_debug 表失败: %w", err)
+	}
+	indexes := []string{
+		`CREATE INDEX IF NOT EXISTS idx_probe_debug_psc_id ON probe_debug(provider, service, channel, id)`,
+		`CREATE INDEX IF NOT EXISTS idx_probe_debug_expires_at ON probe_debug(expires_at)`,
+	}
+	for _, stmt := range indexes {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("创建 probe_debug 索引失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// SaveProbeDebug 写入一条探测调试快照
+func (s *SQLiteStorage) SaveProbeDebug(entry *ProbeDebugEntry) error {
+	ctx := s.effectiveCtx()
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO probe_debug (
+			provider, service, channel, model, timestamp,
+			status, sub_status, http_code, latency, attempts,
+			method, url, request_headers, response_headers, response_body, body_truncated, error,
+			dns_ms, connect_ms, tls_ms, ttfb_ms, expires_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, entry.Provider, entry.Service, entry.Channel, entry.Model, entry.Timestamp,
+		entry.Status, string(entry.SubStatus), entry.HttpCode, entry.Latency, entry.Attempts,
+		entry.Method, entry.URL, entry.RequestHeaders, entry.ResponseHeaders, entry.ResponseBody, entry.BodyTruncated, entry.Error,
+		entry.DNSMs, entry.ConnectMs, entry.TLSMs, entry.TTFBMs, entry.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("写入探测调试快照失败: %w", err)
+	}
+	entry.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// GetProbeDebugEntries 按条件查询探测调试快照
+func (s *SQLiteStorage) GetProbeDebugEntries(filter ProbeDebugFilter) ([]*ProbeDebugEntry, error) {
+	ctx := s.effectiveCtx()
+
+	conditions := []string{"1 = 1"}
+	var args []any
+	for _, f := range []struct {
+		column string
+		value  string
+	}{
+		{"provider", filter.Provider},
+		{"service", filter.Service},
+		{"channel", filter.Channel},
+		{"model", filter.Model},
+	} {
+		if f.value != "" {
+			conditions = append(conditions, f.column+" = ?")
+			args = append(args, f.value)
+		}
+	}
+	if filter.BeforeID > 0 {
+		conditions = append(conditions, "id < ?")
+		args = append(args, filter.BeforeID)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, provider, service, channel, model, timestamp,
+			status, sub_status, http_code, latency, attempts,
+			method, url, request_headers, response_headers, response_body, body_truncated, error,
+			dns_ms, connect_ms, tls_ms, ttfb_ms, expires_at
+		FROM probe_debug
+		WHERE %s
+		ORDER BY id DESC
+		LIMIT ?
+	`, strings.Join(conditions, " AND "))
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询探测调试快照失败: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*ProbeDebugEntry
+	for rows.Next() {
+		var e ProbeDebugEntry
+		var subStatus string
+		if err := rows.Scan(
+			&e.ID, &e.Provider, &e.Service, &e.Channel, &e.Model, &e.Timestamp,
+			&e.Status, &subStatus, &e.HttpCode, &e.Latency, &e.Attempts,
+			&e.Method, &e.URL, &e.RequestHeaders, &e.ResponseHeaders, &e.ResponseBody, &e.BodyTruncated, &e.Error,
+			&e.DNSMs, &e.ConnectMs, &e.TLSMs, &e.TTFBMs, &e.ExpiresAt,
+		); err != nil {
+			return nil, fmt.Errorf("扫描探测调试快照失败: %w", err)
+		}
+		e.SubStatus = SubStatus(subStatus)
+		entries = append(entries, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代探测调试快照失败: %w", err)
+	}
+	return entries, nil
+}
+
+// PurgeExpiredProbeDebug 删除过期的探测调试快照
+func (s *SQLiteStorage) PurgeExpiredProbeDebug(now int64) (int64, error) {
+	ctx := s.effectiveCtx()
+	result, err := s.db.ExecContext(ctx, `DELETE FROM probe_debug WHERE expires_at <= ?`, now)
+	if err != nil {
+		return 0, fmt.Errorf("清理探测调试快照失败: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// ===== 事件 Webhook 死信相关方法 =====
+
+// initWebhookDeadLetterTable 初始化事件 Webhook 死信表
+func (s *SQLiteStorage) initWebhookDeadLetterTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		url TEXT NOT NULL,
+		event_id INTEGER NOT NULL,
+		event_type TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		attempts INTEGER NOT NULL,
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL
+	);
+	`
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("创建 webhook_dead_letters 表失败: %w", err)
+	}
+	return nil
+}
+
+// SaveWebhookDeadLetter 写入一条事件 Webhook 死信
+func (s *SQLiteStorage) SaveWebhookDeadLetter(dl *WebhookDeadLetter) error {
+	ctx := s.effectiveCtx()
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO webhook_dead_letters (url, event_id, event_type, payload, attempts, last_error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, dl.URL, dl.EventID, string(dl.EventType), dl.Payload, dl.Attempts, dl.LastError, dl.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("写入 Webhook 死信失败: %w", err)
+	}
+	dl.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// GetWebhookDeadLetters 按条件查询事件 Webhook 死信
+func (s *SQLiteStorage) GetWebhookDeadLetters(filter WebhookDeadLetterFilter) ([]*WebhookDeadLetter, error) {
+	ctx := s.effectiveCtx()
+
+	conditions := []string{"1 = 1"}
+	var args []any
+	if filter.URL != "" {
+		conditions = append(conditions, "url = ?")
+		args = append(args, filter.URL)
+	}
+	if filter.BeforeID > 0 {
+		conditions = append(conditions, "id < ?")
+		args = append(args, filter.BeforeID)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, url, event_id, event_type, payload, attempts, last_error, created_at
+		FROM webhook_dead_letters
+		WHERE %s
+		ORDER BY id DESC
+		LIMIT ?
+	`, strings.Join(conditions, " AND "))
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询 Webhook 死信失败: %w", err)
+	}
+	defer rows.Close()
+
+	var letters []*WebhookDeadLetter
+	for rows.Next() {
+		var dl WebhookDeadLetter
+		var eventType string
+		if err := rows.Scan(&dl.ID, &dl.URL, &dl.EventID, &eventType, &dl.Payload, &dl.Attempts, &dl.LastError, &dl.CreatedAt); err != nil {
+			return nil, fmt.Errorf("扫描 Webhook 死信失败: %w", err)
+		}
+		dl.EventType = EventType(eventType)
+		letters = append(letters, &dl)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代 Webhook 死信失败: %w", err)
+	}
+	return letters, nil
+}
+
+// ===== 事件发件箱相关方法 =====
+
+// initEventOutboxTable 初始化事件发件箱表（done_at = 0 表示未完成）
+func (s *SQLiteStorage) initEventOutboxTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS event_outbox (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		event_id INTEGER NOT NULL,
+		destination TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at INTEGER NOT NULL,
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL,
+		done_at INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX IF NOT EXISTS idx_event_outbox_pending ON event_outbox (next_attempt_at) WHERE done_at = 0;
+	CREATE INDEX IF NOT EXISTS idx_event_outbox_done ON event_outbox (done_at) WHERE done_at > 0;
+	`
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("创建 event_outbox 表失败: %w", err)
+	}
+	return nil
+}
+
+// SaveStatusEventWithOutbox 在同一事务中保存事件并写入待投递记录
+func (s *SQLiteStorage) SaveStatusEventWithOutbox(event *StatusEvent, destinations []string) error {
+	if len(destinations) == 0 {
+		return s.SaveStatusEvent(event)
+	}
+
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "SaveStatusEventWithOutbox")
+	defer span.End()
+
+	args, err := sqliteStatusEventArgs(event)
+	if err != nil {
+		return err
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, sqliteInsertStatusEventSQL, args...)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return nil // 重复事件，视为成功（已在首次保存时写入待投递记录）
+		}
+		return fmt.Errorf("保存状态事件失败: %w", err)
+	}
+	id, _ := result.LastInsertId()
+
+	for _, dest := range destinations {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO event_outbox (event_id, destination, next_attempt_at, created_at)
+			VALUES (?, ?, ?, ?)
+		`, id, dest, event.CreatedAt, event.CreatedAt); err != nil {
+			return fmt.Errorf("写入事件待投递记录失败: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+	event.ID = id
+	return nil
+}
+
+// ClaimOutboxEntries 领取到期的待投递记录并设置租约
+func (s *SQLiteStorage) ClaimOutboxEntries(now int64, lease time.Duration, limit int) ([]*OutboxEntry, error) {
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "ClaimOutboxEntries")
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, event_id, destination, attempts, next_attempt_at, last_error, created_at
+		FROM event_outbox
+		WHERE done_at = 0 AND next_attempt_at <= ?
+		ORDER BY id
+		LIMIT ?
+	`, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("查询待投递记录失败: %w", err)
+	}
+	var entries []*OutboxEntry
+	for rows.Next() {
+		var e OutboxEntry
+		if err := rows.Scan(&e.ID, &e.EventID, &e.Destination, &e.Attempts, &e.NextAttemptAt, &e.LastError, &e.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("扫描待投递记录失败: %w", err)
+		}
+		entries = append(entries, &e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代待投递记录失败: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]any, 0, len(entries))
+	placeholders := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+		placeholders = append(placeholders, "?")
+	}
+	leaseUntil := now + int64(lease/time.Second)
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE event_outbox SET next_attempt_at = ? WHERE id IN (`+strings.Join(placeholders, ",")+`)`,
+		append([]any{leaseUntil}, ids...)...); err != nil {
+		return nil, fmt.Errorf("设置待投递记录租约失败: %w", err)
+	}
+
+	eventIDs := make([]any, 0, len(entries))
+	for _, e := range entries {
+		eventIDs = append(eventIDs, e.EventID)
+	}
+	events, err := s.getStatusEventsByIDs(ctx, tx, eventIDs, placeholders)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("提交事务失败: %w", err)
+	}
+	for _, e := range entries {
+		e.Event = events[e.EventID]
+	}
+	return entries, nil
+}
+
+// getStatusEventsByIDs 按 ID 批量加载状态事件（placeholders 与 ids 等长）
+func (s *SQLiteStorage) getStatusEventsByIDs(ctx context.Context, tx *sql.Tx, ids []any, placeholders []string) (map[int64]*StatusEvent, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, namespace, provider, service, channel, model, event_type, from_status, to_status, trigger_record_id, observed_at, created_at, meta
+		FROM status_events
+		WHERE id IN (`+strings.Join(placeholders, ",")+`)
+	`, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("查询状态事件失败: %w", err)
+	}
+	defer rows.Close()
+
+	events := make(map[int64]*StatusEvent, len(ids))
+	for rows.Next() {
+		var event StatusEvent
+		var eventTypeStr string
+		var metaJSON sql.NullString
+		if err := rows.Scan(
+			&event.ID, &event.Namespace, &event.Provider, &event.Service, &event.Channel, &event.Model,
+			&eventTypeStr, &event.FromStatus, &event.ToStatus, &event.TriggerRecordID,
+			&event.ObservedAt, &event.CreatedAt, &metaJSON,
+		); err != nil {
+			return nil, fmt.Errorf("扫描状态事件失败: %w", err)
+		}
+		event.EventType = EventType(eventTypeStr)
+		if metaJSON.Valid && metaJSON.String != "" {
+			var meta map[string]any
+			if err := json.Unmarshal([]byte(metaJSON.String), &meta); err == nil {
+				event.Meta = meta
+			}
+		}
+		events[event.ID] = &event
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代状态事件失败: %w", err)
+	}
+	return events, nil
+}
+
+// CompleteOutboxEntry 标记待投递记录已完成
+func (s *SQLiteStorage) CompleteOutboxEntry(id int64, attempts int, doneAt int64, lastError string) error {
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "CompleteOutboxEntry")
+	defer span.End()
+
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE event_outbox SET attempts = ?, done_at = ?, last_error = ? WHERE id = ?
+	`, attempts, doneAt, lastError, id); err != nil {
+		return fmt.Errorf("标记待投递记录完成失败: %w", err)
+	}
+	return nil
+}
+
+// RetryOutboxEntry 记录失败尝试并设置下次尝试时间
+func (s *SQLiteStorage) RetryOutboxEntry(id int64, attempts int, nextAttemptAt int64, lastError string) error {
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "RetryOutboxEntry")
+	defer span.End()
+
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE event_outbox SET attempts = ?, next_attempt_at = ?, last_error = ? WHERE id = ? AND done_at = 0
+	`, attempts, nextAttemptAt, lastError, id); err != nil {
+		return fmt.Errorf("更新待投递记录失败: %w", err)
+	}
+	return nil
+}
+
+// PurgeCompletedOutbox 删除过期的已完成记录
+func (s *SQLiteStorage) PurgeCompletedOutbox(before int64) (int64, error) {
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "PurgeCompletedOutbox")
+	defer span.End()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM event_outbox WHERE done_at > 0 AND done_at < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("清理已完成的待投递记录失败: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// ===== 探测数据透明度相关方法 =====
+
+// initTransparencyTable 初始化透明度小时根表
+func (s *SQLiteStorage) initTransparencyTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS transparency_roots (
+		hour INTEGER PRIMARY KEY,
+		leaf_count INTEGER NOT NULL,
+		root TEXT NOT NULL,
+		prev_root TEXT NOT NULL DEFAULT '',
+		key_id TEXT NOT NULL,
+		signature TEXT NOT NULL,
+		created_at INTEGER NOT NULL
+	);
+	`
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("创建 transparency_roots 表失败: %w", err)
+	}
+	return nil
+}
+
+// GetRecordsInRange 查询时间范围内的全部探测记录
+func (s *SQLiteStorage) GetRecordsInRange(since, until int64) ([]*ProbeRecord, error) {
+	ctx := s.effectiveCtx()
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, provider, service, channel, model, status, sub_status, http_code, latency, timestamp
+		FROM probe_history
+		WHERE timestamp >= ? AND timestamp < ?
+		ORDER BY id ASC
+	`, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("查询时间范围内的探测记录失败: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*ProbeRecord
+	for rows.Next() {
+		var r ProbeRecord
+		var subStatus string
+		if err := rows.Scan(&r.ID, &r.Provider, &r.Service, &r.Channel, &r.Model, &r.Status, &subStatus, &r.HttpCode, &r.Latency, &r.Timestamp); err != nil {
+			return nil, fmt.Errorf("扫描探测记录失败: %w", err)
+		}
+		r.SubStatus = SubStatus(subStatus)
+		records = append(records, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代探测记录失败: %w", err)
+	}
+	return records, nil
+}
+
+// SaveTransparencyRoot 保存小时根（已存在时忽略）
+func (s *SQLiteStorage) SaveTransparencyRoot(root *TransparencyRoot) error {
+	ctx := s.effectiveCtx()
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO transparency_roots (hour, leaf_count, root, prev_root, key_id, signature, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(hour) DO NOTHING
+	`, root.Hour, root.LeafCount, root.Root, root.PrevRoot, root.KeyID, root.Signature, root.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("保存透明度小时根失败: %w", err)
+	}
+	return nil
+}
+
+// GetLatestTransparencyRoot 查询最近封存的小时根
+func (s *SQLiteStorage) GetLatestTransparencyRoot() (*TransparencyRoot, error) {
+	ctx := s.effectiveCtx()
+	var r TransparencyRoot
+	err := s.db.QueryRowContext(ctx, `
+		SELECT hour, leaf_count, root, prev_root, key_id, signature, created_at
+		FROM transparency_roots
+		ORDER BY hour DESC
+		LIMIT 1
+	`).Scan(&r.Hour, &r.LeafCount, &r.Root, &r.PrevRoot, &r.KeyID, &r.Signature, &r.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询最近透明度小时根失败: %w", err)
+	}
+	return &r, nil
+}
+
+// GetTransparencyRoots 查询范围内的小时根
+func (s *SQLiteStorage) GetTransparencyRoots(sinceHour, untilHour int64) ([]*TransparencyRoot, error) {
+	ctx := s.effectiveCtx()
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT hour, leaf_count, root, prev_root, key_id, signature, created_at
+		FROM transparency_roots
+		WHERE hour >= ? AND hour <= ?
+		ORDER BY hour ASC
+	`, sinceHour, untilHour)
+	if err != nil {
+		return nil, fmt.Errorf("查询透明度小时根失败: %w", err)
+	}
+	defer rows.Close()
+
+	var roots []*TransparencyRoot
+	for rows.Next() {
+		var r TransparencyRoot
+		if err := rows.Scan(&r.Hour, &r.LeafCount, &r.Root, &r.PrevRoot, &r.KeyID, &r.Signature, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("扫描透明度小时根失败: %w", err)
+		}
+		roots = append(roots, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代透明度小时根失败: %w", err)
+	}
+	return roots, nil
+}
+
+// ===== 已下线监测项相关方法 =====
+
+// initDecommissionTable 初始化已下线监测项登记表
+func (s *SQLiteStorage) initDecommissionTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS decommissioned_monitors (
+		provider TEXT NOT NULL,
+		service TEXT NOT NULL,
+		channel TEXT NOT NULL DEFAULT '',
+		model TEXT NOT NULL DEFAULT '',
+		parent TEXT NOT NULL DEFAULT '',
+		provider_name TEXT NOT NULL DEFAULT '',
+		provider_slug TEXT NOT NULL DEFAULT '',
+		provider_url TEXT NOT NULL DEFAULT '',
+		service_name TEXT NOT NULL DEFAULT '',
+		channel_name TEXT NOT NULL DEFAULT '',
+		category TEXT NOT NULL DEFAULT '',
+		board TEXT NOT NULL DEFAULT '',
+		hidden INTEGER NOT NULL DEFAULT 0,
+		retired_at INTEGER NOT NULL,
+		PRIMARY KEY (provider, service, channel, model)
+	);
+	`
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("创建 decommissioned_monitors 表失败: %w", err)
+	}
+	return nil
+}
+
+// SaveDecommissionedMonitors 登记已下线监测项（已登记时保留原下线时间）
+func (s *SQLiteStorage) SaveDecommissionedMonitors(monitors []*DecommissionedMonitor) error {
+	if len(monitors) == 0 {
+		return nil
+	}
+	ctx := s.effectiveCtx()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开启下线登记事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, m := range monitors {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO decommissioned_monitors (provider, service, channel, model, parent, provider_name, provider_slug,
+				provider_url, service_name, channel_name, category, board, hidden, retired_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(provider, service, channel, model) DO NOTHING
+		`, m.Provider, m.Service, m.Channel, m.Model, m.Parent, m.ProviderName, m.ProviderSlug,
+			m.ProviderURL, m.ServiceName, m.ChannelName, m.Category, m.Board, m.Hidden, m.RetiredAt); err != nil {
+			return fmt.Errorf("登记已下线监测项失败: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交下线登记事务失败: %w", err)
+	}
+	return nil
+}
+
+// DeleteDecommissionedMonitors 移除下线登记（保留历史数据）
+func (s *SQLiteStorage) DeleteDecommissionedMonitors(keys []MonitorKey) error {
+	ctx := s.effectiveCtx()
+	for _, k := range keys {
+		if _, err := s.db.ExecContext(ctx, `
+			DELETE FROM decommissioned_monitors
+			WHERE provider = ? AND service = ? AND channel = ? AND model = ?
+		`, k.Provider, k.Service, k.Channel, k.Model); err != nil {
+			return fmt.Errorf("移除下线登记失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetDecommissionedMonitors 查询全部已下线监测项
+func (s *SQLiteStorage) GetDecommissionedMonitors() ([]*DecommissionedMonitor, error) {
+	ctx := s.effectiveCtx()
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT provider, service, channel, model, parent, provider_name, provider_slug,
+			provider_url, service_name, channel_name, category, board, hidden, retired_at
+		FROM decommissioned_monitors
+		ORDER BY retired_at ASC, provider, service, channel, model
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("查询已下线监测项失败: %w", err)
+	}
+	defer rows.Close()
+
+	var monitors []*DecommissionedMonitor
+	for rows.Next() {
+		var m DecommissionedMonitor
+		if err := rows.Scan(&m.Provider, &m.Service, &m.Channel, &m.Model, &m.Parent, &m.ProviderName, &m.ProviderSlug,
+			&m.ProviderURL, &m.ServiceName, &m.ChannelName, &m.Category, &m.Board, &m.Hidden, &m.RetiredAt); err != nil {
+			return nil, fmt.Errorf("扫描已下线监测项失败: %w", err)
+		}
+		monitors = append(monitors, &m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代已下线监测项失败: %w", err)
+	}
+	return monitors, nil
+}
+
+// PurgeMonitorRecords 删除指定监测项的一批探测记录
+func (s *SQLiteStorage) PurgeMonitorRecords(ctx context.Context, key MonitorKey, batchSize int) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM probe_history
+		WHERE id IN (
+			SELECT id FROM probe_history
+			WHERE provider = ? AND service = ? AND channel = ? AND model = ?
+			LIMIT ?
+		)
+	`, key.Provider, key.Service, key.Channel, key.Model, batchSize)
+	if err != nil {
+		// SQLite 锁冲突由调用方（Cleaner）处理重试逻辑
+		return 0, fmt.Errorf("删除已下线监测项记录失败: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("获取删除行数失败: %w", err)
+	}
+	return affected, nil
+}
+
+// DropDecommissionedMonitor 删除下线登记及该监测项的每日汇总、状态机状态与元数据版本
+func (s *SQLiteStorage) DropDecommissionedMonitor(ctx context.Context, key MonitorKey) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开启下线清理事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"probe_daily", "service_states", "monitor_metadata", "decommissioned_monitors"} {
+		query := fmt.Sprintf(`DELETE FROM %s WHERE provider = ? AND service = ? AND channel = ? AND model = ?`, table)
+		if _, err := tx.ExecContext(ctx, query, key.Provider, key.Service, key.Channel, key.Model); err != nil {
+			return fmt.Errorf("清理 %s 失败: %w", table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交下线清理事务失败: %w", err)
+	}
+	return nil
+}
+
+// ===== 服务商数据访问令牌相关方法 =====
+
+// initProviderTokenTable 初始化服务商数据访问令牌表
+func (s *SQLiteStorage) initProviderTokenTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS provider_tokens (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		provider_slug TEXT NOT NULL,
+		name TEXT NOT NULL DEFAULT '',
+		token_hash TEXT NOT NULL UNIQUE,
+		token_prefix TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		revoked_at INTEGER NOT NULL DEFAULT 0
+	);
+	`
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("创建 provider_tokens 表失败: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_provider_tokens_slug ON provider_tokens(provider_slug)`); err != nil {
+		return fmt.Errorf("创建 provider_tokens 索引失败: %w", err)
+	}
+	return nil
+}
+
+// CreateProviderToken 保存新令牌
+func (s *SQLiteStorage) CreateProviderToken(token *ProviderToken) error {
+	ctx := s.effectiveCtx()
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO provider_tokens (provider_slug, name, token_hash, token_prefix, created_at, revoked_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, token.ProviderSlug, token.Name, token.TokenHash, token.TokenPrefix, token.CreatedAt, token.RevokedAt)
+	if err != nil {
+		return fmt.Errorf("保存服务商令牌失败: %w", err)
+	}
+	token.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// GetProviderTokenByHash 按令牌哈希查询
+func (s *SQLiteStorage) GetProviderTokenByHash(tokenHash string) (*ProviderToken, error) {
+	ctx := s.effectiveCtx()
+	var t ProviderToken
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, provider_slug, name, token_hash, token_prefix, created_at, revoked_at
+		FROM provider_tokens
+		WHERE token_hash = ?
+	`, tokenHash).Scan(&t.ID, &t.ProviderSlug, &t.Name, &t.TokenHash, &t.TokenPrefix, &t.CreatedAt, &t.RevokedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询服务商令牌失败: %w", err)
+	}
+	return &t, nil
+}
+
+// ListProviderTokens 查询令牌列表
+func (s *SQLiteStorage) ListProviderTokens(providerSlug string) ([]*ProviderToken, error) {
+	ctx := s.effectiveCtx()
+	query := `
+		SELECT id, provider_slug, name, token_hash, token_prefix, created_at, revoked_at
+		FROM provider_tokens
+	`
+	var args []any
+	if providerSlug != "" {
+		query += ` WHERE provider_slug = ?`
+		args = append(args, providerSlug)
+	}
+	query += ` ORDER BY id DESC`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询服务商令牌列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []*ProviderToken
+	for rows.Next() {
+		var t ProviderToken
+		if err := rows.Scan(&t.ID, &t.ProviderSlug, &t.Name, &t.TokenHash, &t.TokenPrefix, &t.CreatedAt, &t.RevokedAt); err != nil {
+			return nil, fmt.Errorf("扫描服务商令牌失败: %w", err)
+		}
+		tokens = append(tokens, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代服务商令牌失败: %w", err)
+	}
+	return tokens, nil
+}
+
+// RevokeProviderToken 吊销令牌
+func (s *SQLiteStorage) RevokeProviderToken(id, revokedAt int64) (bool, error) {
+	ctx := s.effectiveCtx()
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE provider_tokens SET revoked_at = ? WHERE id = ? AND revoked_at = 0
+	`, revokedAt, id)
+	if err != nil {
+		return false, fmt.Errorf("吊销服务商令牌失败: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("获取更新行数失败: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// ===== 运维标注相关方法 =====
+
+// initAnnotationTable 初始化运维标注表
+func (s *SQLiteStorage) initAnnotationTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS annotations (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		provider TEXT NOT NULL,
+		service TEXT NOT NULL DEFAULT '',
+		channel TEXT NOT NULL DEFAULT '',
+		model TEXT NOT NULL DEFAULT '',
+		start_time INTEGER NOT NULL,
+		end_time INTEGER NOT NULL DEFAULT 0,
+		text TEXT NOT NULL,
+		link TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL
+	);
+	`
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("创建 annotations 表失败: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_annotations_provider_start ON annotations(provider, start_time)`); err != nil {
+		return fmt.Errorf("创建 annotations 索引失败: %w", err)
+	}
+	return nil
+}
+
+// CreateAnnotation 保存新标注
+func (s *SQLiteStorage) CreateAnnotation(annotation *Annotation) error {
+	ctx := s.effectiveCtx()
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO annotations (provider, service, channel, model, start_time, end_time, text, link, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, annotation.Provider, annotation.Service, annotation.Channel, annotation.Model,
+		annotation.StartTime, annotation.EndTime, annotation.Text, annotation.Link, annotation.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("保存运维标注失败: %w", err)
+	}
+	annotation.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// GetAnnotations 查询与时间范围重叠的标注
+func (s *SQLiteStorage) GetAnnotations(filter AnnotationFilter) ([]*Annotation, error) {
+	ctx := s.effectiveCtx()
+	query := `
+		SELECT id, provider, service, channel, model, start_time, end_time, text, link, created_at
+		FROM annotations
+		WHERE 1=1
+	`
+	var args []any
+	if filter.Provider != "" {
+		query += ` AND provider = ?`
+		args = append(args, filter.Provider)
+	}
+	if filter.Since > 0 {
+		query += ` AND (end_time = 0 OR end_time >= ?)`
+		args = append(args, filter.Since)
+	}
+	if filter.Until > 0 {
+		query += ` AND start_time <= ?`
+		args = append(args, filter.Until)
+	}
+	query += ` ORDER BY start_time ASC, id ASC`
+	if filter.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询运维标注失败: %w", err)
+	}
+	defer rows.Close()
+
+	var annotations []*Annotation
+	for rows.Next() {
+		var a Annotation
+		if err := rows.Scan(&a.ID, &a.Provider, &a.Service, &a.Channel, &a.Model, &a.StartTime, &a.EndTime, &a.Text, &a.Link, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("扫描运维标注失败: %w", err)
+		}
+		annotations = append(annotations, &a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代运维标注失败: %w", err)
+	}
+	return annotations, nil
+}
+
+// DeleteAnnotation 删除标注
+func (s *SQLiteStorage) DeleteAnnotation(id int64) (bool, error) {
+	ctx := s.effectiveCtx()
+	result, err := s.db.ExecContext(ctx, `DELETE FROM annotations WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("删除运维标注失败: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("获取删除行数失败: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// ===== 自定义分组相关方法 =====
+
+// initCustomGroupTable 初始化自定义分组表
+func (s *SQLiteStorage) initCustomGroupTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS custom_groups (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		members TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	);
+	`
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("创建 custom_groups 表失败: %w", err)
+	}
+	return nil
+}
+
+// SaveCustomGroup 创建或覆盖自定义分组
+func (s *SQLiteStorage) SaveCustomGroup(group *CustomGroup) error {
+	ctx := s.effectiveCtx()
+	members, err := encodeCustomGroupMembers(group.Members)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO custom_groups (id, name, description, members, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			description = excluded.description,
+			members = excluded.members,
+			updated_at = excluded.updated_at
+	`, group.ID, group.Name, group.Description, members, group.CreatedAt, group.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("保存自定义分组失败: %w", err)
+	}
+	// 覆盖已有分组时回读原 created_at
+	return s.db.QueryRowContext(ctx, `SELECT created_at FROM custom_groups WHERE id = ?`, group.ID).Scan(&group.CreatedAt)
+}
+
+// ListCustomGroups 查询全部自定义分组
+func (s *SQLiteStorage) ListCustomGroups() ([]*CustomGroup, error) {
+	ctx := s.effectiveCtx()
+	rows, err := s.db.QueryContext(ctx, `SELECT `+customGroupColumns+` FROM custom_groups ORDER BY id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("查询自定义分组失败: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []*CustomGroup
+	for rows.Next() {
+		g, err := scanCustomGroup(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描自定义分组失败: %w", err)
+		}
+		groups = append(groups, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代自定义分组失败: %w", err)
+	}
+	return groups, nil
+}
+
+// DeleteCustomGroup 删除自定义分组
+func (s *SQLiteStorage) DeleteCustomGroup(id string) (bool, error) {
+	ctx := s.effectiveCtx()
+	result, err := s.db.ExecContext(ctx, `DELETE FROM custom_groups WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("删除自定义分组失败: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("获取删除行数失败: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// ===== 服务商元数据修改申请相关方法 =====
+
+// initMetadataRequestTable 初始化服务商元数据修改申请表
+func (s *SQLiteStorage) initMetadataRequestTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS metadata_requests (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		provider_slug TEXT NOT NULL,
+		token_id INTEGER NOT NULL DEFAULT 0,
+		changes TEXT NOT NULL,
+		comment TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL,
+		review_note TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL,
+		reviewed_at INTEGER NOT NULL DEFAULT 0
+	);
+	`
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("创建 metadata_requests 表失败: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_metadata_requests_slug_status ON metadata_requests(provider_slug, status)`); err != nil {
+		return fmt.Errorf("创建 metadata_requests 索引失败: %w", err)
+	}
+	return nil
+}
+
+// CreateMetadataRequest 保存新申请
+func (s *SQLiteStorage) CreateMetadataRequest(req *MetadataRequest) error {
+	ctx := s.effectiveCtx()
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO metadata_requests (provider_slug, token_id, changes, comment, status, review_note, created_at, reviewed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, req.ProviderSlug, req.TokenID, req.Changes, req.Comment, req.Status, req.ReviewNote, req.CreatedAt, req.ReviewedAt)
+	if err != nil {
+		return fmt.Errorf("保存元数据修改申请失败: %w", err)
+	}
+	req.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// GetMetadataRequest 按 ID 查询申请
+func (s *SQLiteStorage) GetMetadataRequest(id int64) (*MetadataRequest, error) {
+	ctx := s.effectiveCtx()
+	var r MetadataRequest
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, provider_slug, token_id, changes, comment, status, review_note, created_at, reviewed_at
+		FROM metadata_requests
+		WHERE id = ?
+	`, id).Scan(&r.ID, &r.ProviderSlug, &r.TokenID, &r.Changes, &r.Comment, &r.Status, &r.ReviewNote, &r.CreatedAt, &r.ReviewedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询元数据修改申请失败: %w", err)
+	}
+	return &r, nil
+}
+
+// ListMetadataRequests 查询申请列表
+func (s *SQLiteStorage) ListMetadataRequests(filter MetadataRequestFilter) ([]*MetadataRequest, error) {
+	ctx := s.effectiveCtx()
+	query := `
+		SELECT id, provider_slug, token_id, changes, comment, status, review_note, created_at, reviewed_at
+		FROM metadata_requests
+		WHERE 1=1
+	`
+	var args []any
+	if filter.ProviderSlug != "" {
+		query += ` AND provider_slug = ?`
+		args = append(args, filter.ProviderSlug)
+	}
+	if filter.Status != "" {
+		query += ` AND status = ?`
+		args = append(args, filter.Status)
+	}
+	query += ` ORDER BY id DESC`
+	if filter.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询元数据修改申请列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	var reqs []*MetadataRequest
+	for rows.Next() {
+		var r MetadataRequest
+		if err := rows.Scan(&r.ID, &r.ProviderSlug, &r.TokenID, &r.Changes, &r.Comment, &r.Status, &r.ReviewNote, &r.CreatedAt, &r.ReviewedAt); err != nil {
+			return nil, fmt.Errorf("扫描元数据修改申请失败: %w", err)
+		}
+		reqs = append(reqs, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代元数据修改申请失败: %w", err)
+	}
+	return reqs, nil
+}
+
+// ReviewMetadataRequest 审核待处理的申请
+func (s *SQLiteStorage) ReviewMetadataRequest(id int64, status, reviewNote string, reviewedAt int64) (bool, error) {
+	ctx := s.effectiveCtx()
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE metadata_requests SET status = ?, review_note = ?, reviewed_at = ?
+		WHERE id = ? AND status = ?
+	`, status, reviewNote, reviewedAt, id, MetadataRequestPending)
+	if err != nil {
+		return false, fmt.Errorf("审核元数据修改申请失败: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("获取更新行数失败: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// ===== 监测项元数据版本相关方法 =====
+
+// initMonitorMetadataTable 初始化监测项元数据版本表
+func (s *SQLiteStorage) initMonitorMetadataTable(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS monitor_metadata (
+		provider TEXT NOT NULL,
+		service TEXT NOT NULL,
+		channel TEXT NOT NULL DEFAULT '',
+		model TEXT NOT NULL DEFAULT '',
+		provider_name TEXT NOT NULL DEFAULT '',
+		provider_slug TEXT NOT NULL DEFAULT '',
+		sponsor TEXT NOT NULL DEFAULT '',
+		sponsor_url TEXT NOT NULL DEFAULT '',
+		price_min REAL,
+		price_max REAL,
+		badges TEXT NOT NULL DEFAULT '',
+		valid_from INTEGER NOT NULL,
+		PRIMARY KEY (provider, service, channel, model, valid_from)
+	);
+	`
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("创建 monitor_metadata 表失败: %w", err)
+	}
+	return nil
+}
+
+// SaveMonitorMetadata 写入元数据版本
+func (s *SQLiteStorage) SaveMonitorMetadata(versions []*MonitorMetadata) error {
+	if len(versions) == 0 {
+		return nil
+	}
+	ctx := s.effectiveCtx()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开启元数据版本事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, m := range versions {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO monitor_metadata (provider, service, channel, model, provider_name, provider_slug,
+				sponsor, sponsor_url, price_min, price_max, badges, valid_from)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(provider, service, channel, model, valid_from) DO UPDATE SET
+				provider_name = excluded.provider_name,
+				provider_slug = excluded.provider_slug,
+				sponsor = excluded.sponsor,
+				sponsor_url = excluded.sponsor_url,
+				price_min = excluded.price_min,
+				price_max = excluded.price_max,
+				badges = excluded.badges
+		`, m.Provider, m.Service, m.Channel, m.Model, m.ProviderName, m.ProviderSlug,
+			m.Sponsor, m.SponsorURL, m.PriceMin, m.PriceMax, joinBadges(m.Badges), m.ValidFrom); err != nil {
+			return fmt.Errorf("写入元数据版本失败: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交元数据版本事务失败: %w", err)
+	}
+	return nil
+}
+
+// GetLatestMonitorMetadata 查询每个监测项的当前版本
+func (s *SQLiteStorage) GetLatestMonitorMetadata() (map[MonitorKey]*MonitorMetadata, error) {
+	ctx := s.effectiveCtx()
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT m.provider, m.service, m.channel, m.model, m.provider_name, m.provider_slug,
+			m.sponsor, m.sponsor_url, m.price_min, m.price_max, m.badges, m.valid_from
+		FROM monitor_metadata m
+		WHERE m.valid_from = (
+			SELECT MAX(valid_from) FROM monitor_metadata
+			WHERE provider = m.provider AND service = m.service AND channel = m.channel AND model = m.model
+		)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("查询当前元数据版本失败: %w", err)
+	}
+	defer rows.Close()
+
+	versions, err := scanMonitorMetadataRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[MonitorKey]*MonitorMetadata, len(versions))
+	for _, m := range versions {
+		latest[m.Key()] = m
+	}
+	return latest, nil
+}
+
+// GetMonitorMetadataHistory 查询时间范围内生效过的元数据版本
+func (s *SQLiteStorage) GetMonitorMetadataHistory(key MonitorKey, since, until int64) ([]*MonitorMetadata, error) {
+	ctx := s.effectiveCtx()
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT provider, service, channel, model, provider_name, provider_slug,
+			sponsor, sponsor_url, price_min, price_max, badges, valid_from
+		FROM monitor_metadata
+		WHERE provider = ? AND service = ? AND channel = ? AND model = ?
+			AND valid_from < ?
+			AND valid_from >= COALESCE((
+				SELECT MAX(valid_from) FROM monitor_metadata
+				WHERE provider = ? AND service = ? AND channel = ? AND model = ? AND valid_from <= ?
+			), 0)
+		ORDER BY valid_from
+	`, key.Provider, key.Service, key.Channel, key.Model, until,
+		key.Provider, key.Service, key.Channel, key.Model, since)
+	if err != nil {
+		return nil, fmt.Errorf("查询元数据版本历史失败: %w", err)
+	}
+	defer rows.Close()
+
+	return scanMonitorMetadataRows(rows)
+}
+
+// scanMonitorMetadataRows 扫描元数据版本查询结果
+func scanMonitorMetadataRows(rows *sql.Rows) ([]*MonitorMetadata, error) {
+	var versions []*MonitorMetadata
+	for rows.Next() {
+		var m MonitorMetadata
+		var badges string
+		if err := rows.Scan(&m.Provider, &m.Service, &m.Channel, &m.Model, &m.ProviderName, &m.ProviderSlug,
+			&m.Sponsor, &m.SponsorURL, &m.PriceMin, &m.PriceMax, &badges, &m.ValidFrom); err != nil {
+			return nil, fmt.Errorf("扫描元数据版本失败: %w", err)
+		}
+		m.Badges = splitBadges(badges)
+		versions = append(versions, &m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代元数据版本失败: %w", err)
+	}
+	return versions, nil
+}
+
+// ===== 通道改名/合并相关方法 =====
+
+// RenameChannel 在单个事务内将通道的历史数据迁移到目标通道
+func (s *SQLiteStorage) RenameChannel(ctx context.Context, r ChannelRename, dryRun bool) ([]ChannelRenameCount, error) {
+	src := []any{r.Provider, r.Service, r.From}
+	move := []any{r.To, r.Provider, r.Service, r.From}
+	staleSrc := []any{r.Provider, r.Service, r.From, r.To} // 源通道中已被目标通道较新状态取代的行
+	staleDst := []any{r.Provider, r.Service, r.To, r.From} // 目标通道中将被源通道较新状态取代的行
+	steps := []struct {
+		table   string
+		query   string
+		args    []any
+		counted bool
+	}{
+		{"probe_history", `UPDATE probe_history SET channel = ? WHERE provider = ? AND service = ? AND channel = ?`, move, true},
+		{"status_events", `UPDATE status_events SET channel = ? WHERE provider = ? AND service = ? AND channel = ?`, move, true},
+		{"probe_debug", `UPDATE probe_debug SET channel = ? WHERE provider = ? AND service = ? AND channel = ?`, move, true},
+		{"annotations", `UPDATE annotations SET channel = ? WHERE provider = ? AND service = ? AND channel = ?`, move, true},
+		{"probe_daily", `
+			INSERT INTO probe_daily (provider, service, channel, model, day, total, green, yellow, red, latency_sum, latency_count)
+			SELECT provider, service, ?, model, day, total, green, yellow, red, latency_sum, latency_count
+			FROM probe_daily WHERE provider = ? AND service = ? AND channel = ?
+			ON CONFLICT(provider, service, channel, model, day) DO UPDATE SET
+				total = probe_daily.total + excluded.total,
+				green = probe_daily.green + excluded.green,
+				yellow = probe_daily.yellow + excluded.yellow,
+				red = probe_daily.red + excluded.red,
+				latency_sum = probe_daily.latency_sum + excluded.latency_sum,
+				latency_count = probe_daily.latency_count + excluded.latency_count
+		`, move, true},
+		{"probe_daily", `DELETE FROM probe_daily WHERE provider = ? AND service = ? AND channel = ?`, src, false},
+		{"usage_daily", `
+			INSERT INTO usage_daily (provider, service, channel, model, day, tokens, cost, probes)
+			SELECT provider, service, ?, model, day, tokens, cost, probes
+			FROM usage_daily WHERE provider = ? AND service = ? AND channel = ?
+			ON CONFLICT(provider, service, channel, model, day) DO UPDATE SET
+				tokens = usage_daily.tokens + excluded.tokens,
+				cost = usage_daily.cost + excluded.cost,
+				probes = usage_daily.probes + excluded.probes
+		`, move, true},
+		{"usage_daily", `DELETE FROM usage_daily WHERE provider = ? AND service = ? AND channel = ?`, src, false},
+		// 状态机状态：两侧同一 model 均有状态时保留 last_timestamp 较新的一条
+		{"service_states", `
+			DELETE FROM service_states WHERE provider = ? AND service = ? AND channel = ? AND EXISTS (
+				SELECT 1 FROM service_states t
+				WHERE t.provider = service_states.provider AND t.service = service_states.service
+					AND t.channel = ? AND t.model = service_states.model AND t.last_timestamp >= service_states.last_timestamp
+			)
+		`, staleSrc, false},
+		{"service_states", `
+			DELETE FROM service_states WHERE provider = ? AND service = ? AND channel = ? AND EXISTS (
+				SELECT 1 FROM service_states f
+				WHERE f.provider = service_states.provider AND f.service = service_states.service
+					AND f.channel = ? AND f.model = service_states.model
+			)
+		`, staleDst, false},
+		{"service_states", `UPDATE service_states SET channel = ? WHERE provider = ? AND service = ? AND channel = ?`, move, true},
+		{"channel_states", `
+			DELETE FROM channel_states WHERE provider = ? AND service = ? AND channel = ? AND EXISTS (
+				SELECT 1 FROM channel_states t
+				WHERE t.provider = channel_states.provider AND t.service = channel_states.service
+					AND t.channel = ? AND t.last_timestamp >= channel_states.last_timestamp
+			)
+		`, staleSrc, false},
+		{"channel_states", `
+			DELETE FROM channel_states WHERE provider = ? AND service = ? AND channel = ? AND EXISTS (
+				SELECT 1 FROM channel_states f
+				WHERE f.provider = channel_states.provider AND f.service = channel_states.service AND f.channel = ?
+			)
+		`, staleDst, false},
+		{"channel_states", `UPDATE channel_states SET channel = ? WHERE provider = ? AND service = ? AND channel = ?`, move, true},
+		{"monitor_metadata", `
+			INSERT INTO monitor_metadata (provider, service, channel, model, provider_name, provider_slug,
+				sponsor, sponsor_url, price_min, price_max, badges, valid_from)
+			SELECT provider, service, ?, model, provider_name, provider_slug,
+				sponsor, sponsor_url, price_min, price_max, badges, valid_from
+			FROM monitor_metadata WHERE provider = ? AND service = ? AND channel = ?
+			ON CONFLICT(provider, service, channel, model, valid_from) DO NOTHING
+		`, move, true},
+		{"monitor_metadata", `DELETE FROM monitor_metadata WHERE provider = ? AND service = ? AND channel = ?`, src, false},
+		// 原通道的下线登记失效（历史已迁移到目标通道）
+		{"decommissioned_monitors", `DELETE FROM decommissioned_monitors WHERE provider = ? AND service = ? AND channel = ?`, src, true},
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("开启通道迁移事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	var counts []ChannelRenameCount
+	for _, step := range steps {
+		result, err := tx.ExecContext(ctx, step.query, step.args...)
+		if err != nil {
+			return nil, fmt.Errorf("迁移 %s 失败: %w", step.table, err)
+		}
+		if !step.counted {
+			continue
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("获取 %s 迁移行数失败: %w", step.table, err)
+		}
+		counts = append(counts, ChannelRenameCount{Table: step.table, Rows: affected})
+	}
+
+	if dryRun {
+		return counts, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("提交通道迁移事务失败: %w", err)
+	}
+	return counts, nil
+}
+
+// ===== 管理后台用户与会话相关方法 =====
+
+// initAdminUserTables 初始化管理后台用户表与会话表
+func (s *SQLiteStorage) initAdminUserTables(ctx context.Context) error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS admin_users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		username TEXT NOT NULL UNIQUE,
+		password_hash TEXT NOT NULL,
+		role TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL,
+		disabled_at INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE TABLE IF NOT EXISTS admin_sessions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		token_hash TEXT NOT NULL UNIQUE,
+		created_at INTEGER NOT NULL,
+		expires_at INTEGER NOT NULL,
+		ip TEXT NOT NULL DEFAULT ''
+	);
+	`
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("创建 admin_users / admin_sessions 表失败: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_admin_sessions_user ON admin_sessions(user_id)`); err != nil {
+		return fmt.Errorf("创建 admin_sessions 索引失败: %w", err)
+	}
+	return nil
+}
+
+// CreateAdminUser 保存新用户
+func (s *SQLiteStorage) CreateAdminUser(user *AdminUser) error {
+	ctx := s.effectiveCtx()
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO admin_users (username, password_hash, role, created_at, updated_at, disabled_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, user.Username, user.PasswordHash, user.Role, user.CreatedAt, user.UpdatedAt, user.DisabledAt)
+	if err != nil {
+		return fmt.Errorf("保存管理后台用户失败: %w", err)
+	}
+	user.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// GetAdminUser 按 ID 查询用户
+func (s *SQLiteStorage) GetAdminUser(id int64) (*AdminUser, error) {
+	ctx := s.effectiveCtx()
+	u, err := scanAdminUser(s.db.QueryRowContext(ctx, `SELECT `+adminUserColumns+` FROM admin_users WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询管理后台用户失败: %w", err)
+	}
+	return u, nil
+}
+
+// GetAdminUserByUsername 按用户名查询用户
+func (s *SQLiteStorage) GetAdminUserByUsername(username string) (*AdminUser, error) {
+	ctx := s.effectiveCtx()
+	u, err := scanAdminUser(s.db.QueryRowContext(ctx, `SELECT `+adminUserColumns+` FROM admin_users WHERE username = ?`, username))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询管理后台用户失败: %w", err)
+	}
+	return u, nil
+}
+
+// ListAdminUsers 查询全部用户
+func (s *SQLiteStorage) ListAdminUsers() ([]*AdminUser, error) {
+	ctx := s.effectiveCtx()
+	rows, err := s.db.QueryContext(ctx, `SELECT `+adminUserColumns+` FROM admin_users ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("查询管理后台用户列表失败: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*AdminUser
+	for rows.Next() {
+		u, err := scanAdminUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("扫描管理后台用户失败: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代管理后台用户失败: %w", err)
+	}
+	return users, nil
+}
+
+// UpdateAdminUser 更新用户的密码哈希、角色与停用状态
+func (s *SQLiteStorage) UpdateAdminUser(user *AdminUser) (bool, error) {
+	ctx := s.effectiveCtx()
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE admin_users SET password_hash = ?, role = ?, updated_at = ?, disabled_at = ? WHERE id = ?
+	`, user.PasswordHash, user.Role, user.UpdatedAt, user.DisabledAt, user.ID)
+	if err != nil {
+		return false, fmt.Errorf("更新管理后台用户失败: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("获取更新行数失败: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// DeleteAdminUser 删除用户及其全部会话
+func (s *SQLiteStorage) DeleteAdminUser(id int64) (bool, error) {
+	ctx := s.effectiveCtx()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("开启事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM admin_sessions WHERE user_id = ?`, id); err != nil {
+		return false, fmt.Errorf("删除管理后台会话失败: %w", err)
+	}
+	result, err := tx.ExecContext(ctx, `DELETE FROM admin_users WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("删除管理后台用户失败: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("获取删除行数失败: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("提交事务失败: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// CreateAdminSession 保存新会话
+func (s *SQLiteStorage) CreateAdminSession(session *AdminSession) error {
+	ctx := s.effectiveCtx()
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO admin_sessions (user_id, token_hash, created_at, expires_at, ip)
+		VALUES (?, ?, ?, ?, ?)
+	`, session.UserID, session.TokenHash, session.CreatedAt, session.ExpiresAt, session.IP)
+	if err != nil {
+		return fmt.Errorf("保存管理后台会话失败: %w", err)
+	}
+	session.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// GetAdminSessionByHash 按令牌哈希查询会话
+func (s *SQLiteStorage) GetAdminSessionByHash(tokenHash string) (*AdminSession, error) {
+	ctx := s.effectiveCtx()
+	var sess AdminSession
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, user_id, token_hash, created_at, expires_at, ip
+		FROM admin_sessions
+		WHERE token_hash = ?
+	`, tokenHash).Scan(&sess.ID, &sess.UserID, &sess.TokenHash, &sess.CreatedAt, &sess.ExpiresAt, &sess.IP)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询管理后台会话失败: %w", err)
+	}
+	return &sess, nil
+}
+
+// DeleteAdminSession 删除会话
+func (s *SQLiteStorage) DeleteAdminSession(tokenHash string) error {
+	ctx := s.effectiveCtx()
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM admin_sessions WHERE token_hash = ?`, tokenHash); err != nil {
+		return fmt.Errorf("删除管理后台会话失败: %w", err)
+	}
+	return nil
+}
+
+// DeleteAdminUserSessions 删除用户的全部会话
+func (s *SQLiteStorage) DeleteAdminUserSessions(userID int64) error {
+	ctx := s.effectiveCtx()
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM admin_sessions WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("删除管理后台会话失败: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredAdminSessions 删除已过期的会话
+func (s *SQLiteStorage) DeleteExpiredAdminSessions(now int64) (int64, error) {
+	ctx := s.effectiveCtx()
+	result, err := s.db.ExecContext(ctx, `DELETE FROM admin_sessions WHERE expires_at <= ?`, now)
+	if err != nil {
+		return 0, fmt.Errorf("清理过期管理后台会话失败: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("获取删除行数失败: %w", err)
+	}
+	return affected, nil
+}
+
+// explain 获取 SQLite 执行计划（EXPLAIN QUERY PLAN，按层级缩进）
+func (s *SQLiteStorage) explain(ctx context.Context, query string, args []any) (string, error) {
+	rows, err := s.db.QueryContext(ctx, "EXPLAIN QUERY PLAN "+query, args...)
+	if err != nil {
+		return "", fmt.Errorf("EXPLAIN 失败: %w", err)
+	}
+	defer rows.Close()
+
+	depth := make(map[int]int)
+	var lines []string
+	for rows.Next() {
+		var id, parent, notUsed int
+		var detail string
+		if err := rows.Scan(&id, &parent, &notUsed, &detail); err != nil {
+			return "", fmt.Errorf("扫描执行计划失败: %w", err)
+		}
+		d := 0
+		if parent != 0 {
+			d = depth[parent] + 1
+		}
+		depth[id] = d
+		lines = append(lines, strings.Repeat("  ", d)+detail)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("迭代执行计划失败: %w", err)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// GetStorageDiagnostics 返回表与索引大小（dbstat 虚拟表）、表行数、慢查询汇总与索引建议
+// SQLite 不统计索引使用次数，索引建议仅基于慢查询执行计划
+func (s *SQLiteStorage) GetStorageDiagnostics(topSlow int) (*StorageDiagnostics, error) {
+	ctx, span := startSpan(s.effectiveCtx(), "sqlite", "GetStorageDiagnostics")
+	defer span.End()
+
+	sizes := make(map[string]int64)
+	rows, err := s.db.QueryContext(ctx, `SELECT name, SUM(pgsize) FROM dbstat GROUP BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("查询表大小失败: %w", err)
+	}
+	for rows.Next() {
+		var name string
+		var size int64
+		if err := rows.Scan(&name, &size); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("扫描表大小失败: %w", err)
+		}
+		sizes[name] = size
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代表大小失败: %w", err)
+	}
+
+	rows, err = s.db.QueryContext(ctx, `
+		SELECT type, name, tbl_name, sql FROM sqlite_schema
+		WHERE type IN ('table', 'index') AND tbl_name NOT LIKE 'sqlite_%'
+		ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("查询表结构失败: %w", err)
+	}
+	d := &StorageDiagnostics{Backend: "sqlite"}
+	tableIndex := make(map[string]int)
+	var indexes []IndexDiagnostics
+	for rows.Next() {
+		var typ, name, table string
+		var ddl sql.NullString
+		if err := rows.Scan(&typ, &name, &table, &ddl); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("扫描表结构失败: %w", err)
+		}
+		if typ == "table" {
+			tableIndex[name] = len(d.Tables)
+			d.Tables = append(d.Tables, TableDiagnostics{Name: name, TotalBytes: sizes[name]})
+			continue
+		}
+		// 自动索引（UNIQUE/PRIMARY KEY 约束）没有 DDL
+		unique := !ddl.Valid || strings.HasPrefix(strings.ToUpper(strings.TrimSpace(ddl.String)), "CREATE UNIQUE")
+		indexes = append(indexes, IndexDiagnostics{Table: table, Name: name, Bytes: sizes[name], Unique: unique})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("迭代表结构失败: %w", err)
+	}
+
+	for _, idx := range indexes {
+		if i, ok := tableIndex[idx.Table]; ok {
+			d.Tables[i].IndexBytes += idx.Bytes
+			d.Tables[i].TotalBytes += idx.Bytes
+		}
+	}
+	for i := range d.Tables {
+		quoted := `"` + strings.ReplaceAll(d.Tables[i].Name, `"`, `""`) + `"`
+		if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+quoted).Scan(&d.Tables[i].Rows); err != nil {
+			return nil, fmt.Errorf("统计表 %s 行数失败: %w", d.Tables[i].Name, err)
+		}
+	}
+
+	sort.SliceStable(d.Tables, func(i, j int) bool { return d.Tables[i].TotalBytes > d.Tables[j].TotalBytes })
+	sort.SliceStable(indexes, func(i, j int) bool { return indexes[i].Bytes > indexes[j].Bytes })
+	d.Indexes = indexes
+	d.SlowQuery = s.slow.summary(topSlow)
+	d.Advice = buildIndexAdvice(d)
+	return d, nil
+}
+
+// RunMaintenance 执行一轮数据库维护
+//  1. 空闲页回收：auto_vacuum=INCREMENTAL 时执行 incremental_vacuum；否则空闲页占比达到阈值时切换为
+//     INCREMENTAL 并整库 VACUUM（仅首次需要，之后均为增量回收），随后截断 WAL
+//  2. 索引页内未使用空间占比达到阈值的索引执行 REINDEX
+//  3. ANALYZE 更新查询规划器统计信息
+//
+// 注意：连接池仅有一个连接，VACUUM / REINDEX 期间其他读写会排队等待，应在低峰时段执行
+func (s *SQLiteStorage) RunMaintenance(ctx context.Context, opts MaintenanceOptions) ([]MaintenanceStep, error) {
+	ctx, span := startSpan(ctx, "sqlite", "RunMaintenance")
+	defer span.End()
+
+	var steps []MaintenanceStep
+	run := func(action, target, detail, stmt string) {
+		start := time.Now()
+		_, err := s.db.ExecContext(ctx, stmt)
+		step := MaintenanceStep{Action: action, Target: target, Detail: detail, Duration: time.Since(start)}
+		if err != nil {
+			step.Error = err.Error()
+		}
+		steps = append(steps, step)
+	}
+
+	var autoVacuum, pageCount, freePages int64
+	if err := s.db.QueryRowContext(ctx, `PRAGMA auto_vacuum`).Scan(&autoVacuum); err != nil {
+		return nil, fmt.Errorf("查询 auto_vacuum 失败: %w", err)
+	}
+	if err := s.db.QueryRowContext(ctx, `PRAGMA page_count`).Scan(&pageCount); err != nil {
+		return nil, fmt.Errorf("查询 page_count 失败: %w", err)
+	}
+	if err := s.db.QueryRowContext(ctx, `PRAGMA freelist_count`).Scan(&freePages); err != nil {
+		return nil, fmt.Errorf("查询 freelist_count 失败: %w", err)
+	}
+
+	var freeRatio float64
+	if pageCount > 0 {
+		freeRatio = float64(freePages) / float64(pageCount)
+	}
+	detail := fmt.Sprintf("free_pages=%d page_count=%d free_ratio=%.3f", freePages, pageCount, freeRatio)
+	switch {
+	case autoVacuum == 2 && freePages > 0:
+		run("incremental_vacuum", "", detail, fmt.Sprintf(`PRAGMA incremental_vacuum(%d)`, opts.IncrementalPages))
+		run("checkpoint", "", "", `PRAGMA wal_checkpoint(TRUNCATE)`)
+	case autoVacuum != 2 && freeRatio >= opts.VacuumThreshold && freePages > 0:
+		run("auto_vacuum", "", "incremental", `PRAGMA auto_vacuum = INCREMENTAL`)
+		run("vacuum", "", detail, `VACUUM`)
+		run("checkpoint", "", "", `PRAGMA wal_checkpoint(TRUNCATE)`)
+	}
+	if ctx.Err() != nil {
+		return steps, ctx.Err()
+	}
+
+	if opts.ReindexThreshold < 1 {
+		rows, err := s.db.QueryContext(ctx, `
+			SELECT d.name, COUNT(*), SUM(d.unused), SUM(d.pgsize)
+			FROM dbstat d
+			JOIN sqlite_schema m ON m.name = d.name AND m.type = 'index'
+			GROUP BY d.name`)
+		if err != nil {
+			return steps, fmt.Errorf("查询索引碎片率失败: %w", err)
+		}
+		type fragIndex struct {
+			name  string
+			ratio float64
+		}
+		var fragmented []fragIndex
+		for rows.Next() {
+			var name string
+			var pages, unused, size int64
+			if err := rows.Scan(&name, &pages, &unused, &size); err != nil {
+				rows.Close()
+				return steps, fmt.Errorf("扫描索引碎片率失败: %w", err)
+			}
+			// 只有一页的小索引不值得重建
+			if pages < 2 || size == 0 {
+				continue
+			}
+			if ratio := float64(unused) / float64(size); ratio >= opts.ReindexThreshold {
+				fragmented = append(fragmented, fragIndex{name: name, ratio: ratio})
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return steps, fmt.Errorf("迭代索引碎片率失败: %w", err)
+		}
+		for _, idx := range fragmented {
+			if ctx.Err() != nil {
+				return steps, ctx.Err()
+			}
+			run("reindex", idx.name, fmt.Sprintf("unused_ratio=%.3f", idx.ratio), `REINDEX `+quoteSQLiteIdent(idx.name))
+		}
+	}
+
+	if ctx.Err() != nil {
+		return steps, ctx.Err()
+	}
+	run("analyze", "", "", `ANALYZE`)
+	return steps, nil
+}
+
+// quoteSQLiteIdent 引用 SQLite 标识符（表名、索引名）
+func quoteSQLiteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestSQLiteStore 创建已初始化的临时 SQLite 存储（测试结束时关闭）
+func newTestSQLiteStore(t *testing.T) *SQLiteStorage {
+	t.Helper()
+	store, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "monitor.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	if err := store.Init(); err != nil {
+		t.Fatalf("init sqlite: %v", err)
+	}
+	return store
+}
+
+func TestSQLiteSaveRecordsBatchChunks(t *testing.T) {
+	if sqliteInsertChunk*sqliteInsertColumns > sqliteMaxVariables {
+		t.Fatalf("单条 INSERT 参数数 %d 超过上限 %d", sqliteInsertChunk*sqliteInsertColumns, sqliteMaxVariables)
+	}
+
+	store := newTestSQLiteStore(t)
+
+	// 跨越多个分块（最后一块不满）
+	n := sqliteInsertChunk*2 + 5
+	base := time.Now().Add(-time.Hour).Unix()
+	records := make([]*ProbeRecord, n)
+	for i := range records {
+		records[i] = &ProbeRecord{Provider: "p", Service: "cc", Status: 1, Latency: i, Timestamp: base + int64(i)}
+	}
+	if err := store.saveRecordsBatch(context.Background(), records); err != nil {
+		t.Fatalf("saveRecordsBatch: %v", err)
+	}
+
+	got, err := store.GetHistory("p", "cc", "", "", time.Unix(base-1, 0))
+	if err != nil {
+		t.Fatalf("GetHistory: %v", err)
+	}
+	if len(got) != n {
+		t.Fatalf("expected %d records, got %d", n, len(got))
+	}
+	byID := make(map[int64]*ProbeRecord, len(got))
+	for _, r := range got {
+		byID[r.ID] = r
+	}
+	for i, r := range records {
+		saved := byID[r.ID]
+		if saved == nil || saved.Latency != i || saved.Timestamp != r.Timestamp {
+			t.Fatalf("record %d: backfilled ID %d does not match stored row %+v", i, r.ID, saved)
+		}
+	}
+}
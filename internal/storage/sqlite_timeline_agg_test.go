@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTimelineAggTestStore(t *testing.T, records []*ProbeRecord) *SQLiteStorage {
+	t.Helper()
+	store := newTestSQLiteStore(t)
+	if err := store.saveRecordsBatch(context.Background(), records); err != nil {
+		t.Fatalf("save records: %v", err)
+	}
+	return store
+}
+
+func TestSQLiteTimelineAggBuckets(t *testing.T) {
+	key := MonitorKey{Provider: "Relay", Service: "cc", Channel: "vip"}
+	endTime := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	since := endTime.Add(-3 * time.Hour)
+	at := func(d time.Duration) int64 { return endTime.Add(-d).Unix() }
+	dns := 10
+
+	rec := func(ts int64, status int, sub SubStatus, httpCode, latency int) *ProbeRecord {
+		return &ProbeRecord{Provider: key.Provider, Service: key.Service, Channel: key.Channel,
+			Status: status, SubStatus: sub, HttpCode: httpCode, Latency: latency, Timestamp: ts}
+	}
+	first := rec(at(150*time.Minute), 1, "", 0, 100)
+	first.DNSMs = &dns
+	store := newTimelineAggTestStore(t, []*ProbeRecord{
+		rec(since.Unix(), 1, "", 0, 50), // since 边界排除
+		first,
+		rec(at(140*time.Minute), 0, SubStatusServerError, 502, 300),
+		rec(at(30*time.Minute), 2, SubStatusSlowLatency, 0, 900),
+		rec(at(10*time.Minute), 0, SubStatusProbePanic, 0, 0),
+		rec(endTime.Unix()+60, 1, "", 0, 80), // 未来数据排除
+	})
+
+	agg, err := store.GetTimelineAggBatch([]MonitorKey{key}, since, endTime, 3, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("GetTimelineAggBatch: %v", err)
+	}
+	want := []AggBucketRow{
+		{
+			BucketIndex: 0, Total: 2, LastStatus: 0,
+			LatencySum: 100, LatencyCount: 1, AllLatencySum: 400, AllLatencyCount: 2,
+			Phases: PhaseLatencyAgg{DNSSum: 10, DNSCount: 1},
+			StatusCounts: StatusCounts{
+				Available: 1, Unavailable: 1, ServerError: 1,
+				HttpCodeBreakdown: map[string]map[int]int{"server_error": {502: 1}},
+			},
+		},
+		{
+			BucketIndex: 2, Total: 2, LastStatus: 0,
+			LatencySum: 900, LatencyCount: 1, AllLatencySum: 900, AllLatencyCount: 1,
+			StatusCounts: StatusCounts{Degraded: 1, SlowLatency: 1, Unavailable: 1, ProbePanic: 1},
+		},
+	}
+	if got := agg[key]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected buckets:\n got=%+v\nwant=%+v", got, want)
+	}
+
+	// 时段过滤：Asia/Shanghai（UTC+8）本地 07:00-08:00，仅保留最后一个 bucket 的两条记录
+	if _, err := time.LoadLocation("Asia/Shanghai"); err != nil {
+		t.Skipf("load timezone: %v", err)
+	}
+	filtered, err := store.GetTimelineAggBatch([]MonitorKey{key}, since, endTime, 3, time.Hour,
+		&DailyTimeFilter{StartMinutes: 7 * 60, EndMinutes: 8 * 60, Timezone: "Asia/Shanghai"})
+	if err != nil {
+		t.Fatalf("GetTimelineAggBatch with time filter: %v", err)
+	}
+	if rows := filtered[key]; len(rows) != 1 || rows[0].BucketIndex != 2 || rows[0].Total != 2 {
+		t.Fatalf("unexpected filtered buckets: %+v", rows)
+	}
+
+	// 查询范围内存在夏令时切换时无法按固定偏移换算，应返回错误由调用方回退
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skipf("load timezone: %v", err)
+	}
+	dstEnd := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	if _, err := store.GetTimelineAggBatch([]MonitorKey{key}, dstEnd.AddDate(0, 0, -7), dstEnd, 7, 24*time.Hour,
+		&DailyTimeFilter{StartMinutes: 540, EndMinutes: 1020, Timezone: "America/New_York"}); err == nil {
+		t.Fatal("expected error for timezone with DST transition")
+	}
+}
+
+func TestSQLiteTimelineAggChunksKeys(t *testing.T) {
+	if sqliteKeyChunk*4 > sqliteMaxVariables {
+		t.Fatalf("单条查询参数数 %d 超过上限 %d", sqliteKeyChunk*4, sqliteMaxVariables)
+	}
+
+	endTime := time.Now().Truncate(time.Hour)
+	since := endTime.Add(-time.Hour)
+
+	// 跨越多个分块（最后一块不满）
+	keys := make([]MonitorKey, sqliteKeyChunk*2+5)
+	records := make([]*ProbeRecord, len(keys))
+	for i := range keys {
+		keys[i] = MonitorKey{Provider: "p", Service: "cc", Model: fmt.Sprintf("m%d", i)}
+		records[i] = &ProbeRecord{Provider: "p", Service: "cc", Model: keys[i].Model, Status: 1, Latency: i + 1, Timestamp: endTime.Unix() - 60}
+	}
+	store := newTimelineAggTestStore(t, records)
+
+	agg, err := store.GetTimelineAggBatch(keys, since, endTime, 1, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("GetTimelineAggBatch: %v", err)
+	}
+	if len(agg) != len(keys) {
+		t.Fatalf("expected %d keys, got %d", len(keys), len(agg))
+	}
+	for i, k := range keys {
+		if rows := agg[k]; len(rows) != 1 || rows[0].Total != 1 || rows[0].LatencySum != int64(i+1) {
+			t.Fatalf("key %v: unexpected rows %+v", k, rows)
+		}
+	}
+}
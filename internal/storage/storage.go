@@ -19,6 +19,19 @@ const (
 	SubStatusInvalidRequest  SubStatus = "invalid_request"  // 请求参数错误（400）
 	SubStatusNetworkError    SubStatus = "network_error"    // 网络错误（连接失败）
 	SubStatusContentMismatch SubStatus = "content_mismatch" // 内容校验失败
+	SubStatusCertExpiring    SubStatus = "cert_expiring"    // TLS 证书即将过期
+	SubStatusContentDrift    SubStatus = "content_drift"    // 响应指纹持续变化（内容漂移）
+
+	// SubStatusBudgetExhausted 服务商当日探测预算耗尽而跳过探测
+	// 仅出现在调度任务状态（/api/admin/scheduler/tasks）中，不写入 probe_history，不影响可用率
+	SubStatusBudgetExhausted SubStatus = "budget_exhausted"
+
+	// SubStatusIPv6OnlyFailure 强制 IPv6 探测时连接失败，而同一地址的 IPv4 可达（AAAA 记录或 IPv6 链路异常）
+	SubStatusIPv6OnlyFailure SubStatus = "ipv6_only_failure"
+
+	// SubStatusProbePanic 探测过程中发生 panic（程序缺陷，已被调度器隔离）
+	// 以红色记录写入 probe_history（本周期未得到有效结果，计为不可用）
+	SubStatusProbePanic SubStatus = "probe_panic"
 )
 
 // ProbeRecord 探测记录
@@ -33,6 +46,40 @@ type ProbeRecord struct {
 	HttpCode  int       // HTTP 状态码（0 表示非 HTTP 错误，如网络错误）
 	Latency   int       // ms
 	Timestamp int64     // Unix时间戳
+
+	// CertDaysRemaining 服务端证书剩余有效天数（仅 HTTPS 探测有值，nil 表示未知）
+	// 仅 GetLatest/GetLatestBatch 回填，历史查询不读取该列
+	CertDaysRemaining *int
+
+	// 连接阶段耗时（毫秒，nil 表示该阶段未发生或为旧数据，如复用连接时无 DNS/TCP/TLS）
+	// 仅 GetHistory/GetHistoryBatch 回填，用于时间轴区分"服务商慢"与"网络慢"
+	DNSMs     *int
+	ConnectMs *int
+	TLSMs     *int
+	TTFBMs    *int // 发起请求到收到响应首字节
+
+	// Attempts 实际发起的请求次数（含重试；0 表示未记录或未发起请求，如旧数据、导入数据）
+	// 仅 GetHistoryPage 回填，用于导出时观察服务商的抖动
+	Attempts int
+
+	// IPFamily 实际承载请求的连接地址族："ipv4" / "ipv6"（强制地址族时连接失败也记为该地址族；
+	// "" 表示未知，如 auto 模式下连接失败、经代理或旧数据）
+	// 仅 GetHistoryPage 回填，按地址族的可用率统计见 IPFamilyStorage
+	IPFamily string
+
+	// Namespace 监测项所属命名空间（"" 为默认命名空间）
+	// 不写入 probe_history（监测项键在全部命名空间内唯一），仅随记录传递给事件服务写入 status_events
+	Namespace string
+
+	// ContentDrift 本次探测确认的内容漂移（nil 表示未漂移）
+	// 不写入 probe_history，仅随记录传递给事件服务生成 CONTENT_DRIFT 事件
+	ContentDrift *ContentDrift
+}
+
+// ContentDrift 响应指纹变化（from 为原基线指纹，to 为持续出现的新指纹）
+type ContentDrift struct {
+	From string
+	To   string
 }
 
 // TimePoint 时间轴数据点（用于前端展示）
@@ -43,6 +90,56 @@ type TimePoint struct {
 	Latency      int          `json:"latency"`       // 平均延迟（毫秒）
 	Availability float64      `json:"availability"`  // 可用率百分比（0-100），缺失时为 -1
 	StatusCounts StatusCounts `json:"status_counts"` // 各状态计数
+
+	// 连接阶段平均耗时（毫秒，仅统计发生该阶段的记录；bucket 内无数据时省略）
+	DNSMs     *int `json:"dns_ms,omitempty"`
+	ConnectMs *int `json:"connect_ms,omitempty"`
+	TLSMs     *int `json:"tls_ms,omitempty"`
+	TTFBMs    *int `json:"ttfb_ms,omitempty"`
+}
+
+// PhaseLatencyAgg 连接阶段耗时聚合（各阶段独立计数，nil 值不参与统计）
+type PhaseLatencyAgg struct {
+	DNSSum       int64
+	DNSCount     int
+	ConnectSum   int64
+	ConnectCount int
+	TLSSum       int64
+	TLSCount     int
+	TTFBSum      int64
+	TTFBCount    int
+}
+
+// Add 累加一条记录的阶段耗时
+func (a *PhaseLatencyAgg) Add(r *ProbeRecord) {
+	addPhase(&a.DNSSum, &a.DNSCount, r.DNSMs)
+	addPhase(&a.ConnectSum, &a.ConnectCount, r.ConnectMs)
+	addPhase(&a.TLSSum, &a.TLSCount, r.TLSMs)
+	addPhase(&a.TTFBSum, &a.TTFBCount, r.TTFBMs)
+}
+
+// Apply 将平均值写入时间轴数据点（取整规则与 Latency 一致：+0.5 四舍五入）
+func (a *PhaseLatencyAgg) Apply(tp *TimePoint) {
+	tp.DNSMs = avgPhase(a.DNSSum, a.DNSCount)
+	tp.ConnectMs = avgPhase(a.ConnectSum, a.ConnectCount)
+	tp.TLSMs = avgPhase(a.TLSSum, a.TLSCount)
+	tp.TTFBMs = avgPhase(a.TTFBSum, a.TTFBCount)
+}
+
+func addPhase(sum *int64, count *int, v *int) {
+	if v == nil {
+		return
+	}
+	*sum += int64(*v)
+	*count++
+}
+
+func avgPhase(sum int64, count int) *int {
+	if count == 0 {
+		return nil
+	}
+	v := int(float64(sum)/float64(count) + 0.5)
+	return &v
 }
 
 // StatusCounts 记录一个时间块内各状态出现次数
@@ -53,8 +150,10 @@ type StatusCounts struct {
 	Missing     int `json:"missing"`     // 灰色（无数据/未配置）次数
 
 	// 细分统计（黄色波动细分）
-	SlowLatency int `json:"slow_latency"` // 黄色-响应慢次数
-	RateLimit   int `json:"rate_limit"`   // 限流次数（HTTP 429，当前视为红色不可用）
+	SlowLatency  int `json:"slow_latency"`  // 黄色-响应慢次数
+	RateLimit    int `json:"rate_limit"`    // 限流次数（HTTP 429，当前视为红色不可用）
+	CertExpiring int `json:"cert_expiring"` // 黄色-TLS 证书即将过期次数
+	ContentDrift int `json:"content_drift"` // 黄色-响应指纹变化（内容漂移）次数
 
 	// 细分统计（红色不可用细分）
 	ServerError     int `json:"server_error"`     // 红色-服务器错误次数（5xx）
@@ -64,6 +163,11 @@ type StatusCounts struct {
 	NetworkError    int `json:"network_error"`    // 红色-连接失败次数
 	ContentMismatch int `json:"content_mismatch"` // 红色-内容校验失败次数
 
+	// 红色-仅 IPv6 连接失败次数（强制 IPv6 探测失败而 IPv4 可达）
+	IPv6OnlyFailure int `json:"ipv6_only_failure"`
+	// 红色-探测 panic 次数（程序缺陷，本周期未得到有效结果）
+	ProbePanic int `json:"probe_panic"`
+
 	// HTTP 错误码细分统计
 	// key: SubStatus 类型（如 "server_error", "client_error"）
 	// value: 错误码 -> 出现次数 的映射
@@ -94,6 +198,20 @@ type EventType string
 const (
 	EventTypeDown EventType = "DOWN" // 可用 → 不可用
 	EventTypeUp   EventType = "UP"   // 不可用 → 可用
+
+	EventTypeCertExpiring EventType = "CERT_EXPIRING" // 证书剩余天数低于阈值
+	EventTypeContentDrift EventType = "CONTENT_DRIFT" // 响应指纹持续偏离基线（疑似更换模型或响应格式变化）
+
+	EventTypeDegradedStart EventType = "DEGRADED_START" // 连续黄色（性能下降）
+	EventTypeDegradedEnd   EventType = "DEGRADED_END"   // 从性能下降恢复为绿色
+
+	EventTypeSchedulerSaturated EventType = "SCHEDULER_SATURATED" // 调度器并发饱和，探测持续晚于计划时间开始（内部事件，不属于任何监测项）
+
+	EventTypeSLABreachWarning EventType = "SLA_BREACH_WARNING" // 服务商错误预算消耗达到预警比例（服务商级，service/channel 为空）
+	EventTypeSLABreached      EventType = "SLA_BREACHED"       // 服务商错误预算耗尽，本周期可用率已低于 SLA 目标
+
+	EventTypeBoardDemoted  EventType = "BOARD_DEMOTED"  // 监测项被自动降入 secondary/cold 板块
+	EventTypeBoardPromoted EventType = "BOARD_PROMOTED" // 监测项恢复后自动升回配置的板块
 )
 
 // ServiceState 服务状态机持久化状态
@@ -145,13 +263,14 @@ type ChannelState struct {
 
 // StatusEvent 状态变更事件
 type StatusEvent struct {
-	ID       int64
-	Provider string
-	Service  string
-	Channel  string
-	Model    string
+	ID        int64
+	Namespace string // 所属命名空间（"" 为默认命名空间，含 SCHEDULER_SATURATED 等内部事件）
+	Provider  string
+	Service   string
+	Channel   string
+	Model     string
 
-	// EventType 事件类型（DOWN/UP）
+	// EventType 事件类型（DOWN/UP/CERT_EXPIRING/CONTENT_DRIFT/DEGRADED_START/DEGRADED_END/SCHEDULER_SATURATED/SLA_BREACH_WARNING/SLA_BREACHED/BOARD_DEMOTED/BOARD_PROMOTED）
 	EventType EventType
 
 	// FromStatus 变更前状态码（0/1/2）
@@ -175,10 +294,11 @@ type StatusEvent struct {
 
 // EventFilters 事件查询过滤器
 type EventFilters struct {
-	Provider string      // 按 provider 过滤（可选）
-	Service  string      // 按 service 过滤（可选）
-	Channel  string      // 按 channel 过滤（可选）
-	Types    []EventType // 按事件类型过滤（可选，如 ["DOWN", "UP"]）
+	Namespace *string     // 按命名空间过滤（可选，指向 "" 表示仅默认命名空间）
+	Provider  string      // 按 provider 过滤（可选）
+	Service   string      // 按 service 过滤（可选）
+	Channel   string      // 按 channel 过滤（可选）
+	Types     []EventType // 按事件类型过滤（可选，如 ["DOWN", "UP"]）
 }
 
 // Storage 存储接口
@@ -253,6 +373,10 @@ type Storage interface {
 	// filters: 可选过滤条件
 	GetStatusEvents(sinceID int64, limit int, filters *EventFilters) ([]*StatusEvent, error)
 
+	// GetRecentStatusEvents 查询最近的状态变更事件（按 id 倒序，最新在前）
+	// limit: 最多返回条数；filters: 可选过滤条件
+	GetRecentStatusEvents(limit int, filters *EventFilters) ([]*StatusEvent, error)
+
 	// GetLatestEventID 获取最新事件 ID（用于客户端初始化游标）
 	// 返回 0 表示没有任何事件
 	GetLatestEventID() (int64, error)
@@ -269,16 +393,18 @@ type Storage interface {
 
 // ===== DB 侧时间轴聚合相关类型 =====
 
-// DailyTimeFilter 每日时段过滤器（UTC 时区）
+// DailyTimeFilter 每日时段过滤器
 //
 // 说明：
 // - StartMinutes/EndMinutes 的取值范围为 [0, 1440]（1440 表示 24:00）
 // - CrossMidnight 为 true 表示跨午夜：如 22:00-04:00
 // - 语义为左闭右开区间：[start, end)
+// - Timezone 为 IANA 时区名，按该时区的本地时钟判断时段（空值表示 UTC）
 type DailyTimeFilter struct {
 	StartMinutes  int
 	EndMinutes    int
 	CrossMidnight bool
+	Timezone      string
 }
 
 // AggBucketRow 表示单个监测项在某个 bucket 内的聚合结果（由数据库返回）
@@ -290,6 +416,7 @@ type DailyTimeFilter struct {
 //   - LastStatus 为 bucket 内最新一条记录的状态（用于 TimePoint.Status）
 //   - LatencySum/LatencyCount 为 status > 0 的延迟聚合（与 buildTimeline 一致）
 //   - AllLatencySum/AllLatencyCount 为 latency > 0 的延迟聚合（用于"全不可用时"参考）
+//   - Phases 为连接阶段耗时聚合（各阶段仅统计非 NULL 值）
 type AggBucketRow struct {
 	BucketIndex     int
 	Total           int
@@ -299,11 +426,12 @@ type AggBucketRow struct {
 	AllLatencySum   int64
 	AllLatencyCount int
 	StatusCounts    StatusCounts
+	Phases          PhaseLatencyAgg // 连接阶段耗时聚合
 }
 
 // TimelineAggStorage 为"时间轴聚合下推到数据库"提供的可选能力接口
 //
-// 仅 PostgreSQL 实现；SQLite 不实现该接口，API 层会自动回退到原有逻辑。
+// PostgreSQL 与 SQLite 均实现；查询失败时 API 层会自动回退到原有逻辑。
 type TimelineAggStorage interface {
 	// GetTimelineAggBatch 批量获取多个监测项的时间轴 bucket 聚合结果（时间范围）
 	//
@@ -325,3 +453,859 @@ type ArchiveStorage interface {
 	// 输出格式：CSV（包含表头），字段顺序与 ProbeRecord 一致
 	ExportDayToWriter(ctx context.Context, dayStart, dayEnd int64, w io.Writer) (rowCount int64, err error)
 }
+
+// HistoryCursor 原始记录分页游标（按 timestamp, id 升序）
+type HistoryCursor struct {
+	Timestamp int64
+	ID        int64
+}
+
+// ExportStorage 为"原始探测记录导出"（/api/export）提供的可选能力接口
+//
+// 使用键集分页而非长时间持有结果集：SQLite 仅有单个连接，导出期间客户端读取缓慢时不能阻塞探测写入。
+type ExportStorage interface {
+	// GetHistoryPage 按 (timestamp, id) 升序获取单个监测项在游标之后、until 之前（不包含）的记录
+	// 首页游标传 {Timestamp: since.Unix(), ID: 0}（包含 timestamp == since 的记录）
+	GetHistoryPage(key MonitorKey, after HistoryCursor, until time.Time, limit int) ([]*ProbeRecord, error)
+}
+
+// IPFamilyStat 单个监测项在某一连接地址族上的探测统计
+type IPFamilyStat struct {
+	Key         MonitorKey
+	Family      string // "ipv4" / "ipv6"，"" 表示未知（连接失败、经代理或旧数据）
+	Total       int
+	Available   int   // 绿色次数
+	Degraded    int   // 黄色次数
+	Unavailable int   // 红色次数
+	LatencySum  int64 // 绿色/黄色记录延迟之和（毫秒）
+
+	IPv6OnlyFailures int // ipv6_only_failure 次数（强制 IPv6 失败而 IPv4 可达）
+}
+
+// IPFamilyStorage 为"按地址族统计可用率"（/api/admin/ip-families）提供的可选能力接口
+type IPFamilyStorage interface {
+	// GetIPFamilyStats 统计 [since, until) 时间范围内各监测项按 ip_family 分组的探测结果
+	// 结果按 provider, service, channel, model, ip_family 升序排列
+	GetIPFamilyStats(since, until int64) ([]*IPFamilyStat, error)
+}
+
+// ===== Token 用量统计相关类型 =====
+
+// UsageRecord 单个监测项某日的 token 用量汇总
+type UsageRecord struct {
+	Provider string
+	Service  string
+	Channel  string
+	Model    string
+
+	// Day 日期（UTC，格式 2006-01-02）
+	Day string
+
+	// Tokens 当日累计 token 数
+	Tokens int64
+
+	// Cost 当日累计估算成本（按配置单价计算）
+	Cost float64
+
+	// Probes 当日解析到用量的探测次数
+	Probes int
+}
+
+// UsageStorage 为"token 用量与成本统计"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现；未实现时探测器跳过累计，/api/usage 返回 501。
+type UsageStorage interface {
+	// AddUsage 累加指定监测项某日的用量（按 (provider, service, channel, model, day) UPSERT）
+	AddUsage(key MonitorKey, day string, tokens int64, cost float64) error
+
+	// GetUsage 查询 [sinceDay, untilDay] 日期范围内的用量汇总（含边界）
+	// 结果按 provider, service, channel, model, day 升序排列
+	GetUsage(sinceDay, untilDay string) ([]*UsageRecord, error)
+}
+
+// ===== 每日可用性汇总（rollup）相关类型 =====
+
+// DailyRollupRow 单个监测项某日（UTC）的探测状态汇总
+//
+// 由 SaveRecord 在写入 probe_history 时同步累加，不受 retention 清理影响，
+// 用于 90 天热力图等超出原始数据保留期的长周期统计。
+type DailyRollupRow struct {
+	// Day 日期（UTC，格式 2006-01-02）
+	Day string
+
+	// Total 当日探测总数（热力图的分母）
+	Total int
+
+	// Green/Yellow/Red 各状态的探测次数
+	Green  int
+	Yellow int
+	Red    int
+
+	// LatencySum/LatencyCount 为 status > 0 的延迟聚合（与时间轴一致，用于长周期延迟趋势）
+	// 升级前已存在的汇总行为 0
+	LatencySum   int64
+	LatencyCount int
+}
+
+// DailyRollupStorage 为"每日可用性汇总"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现；首次建表时会从 probe_history 回填已有数据。
+type DailyRollupStorage interface {
+	// GetDailyRollupBatch 批量查询 [sinceDay, untilDay] 日期范围内的每日汇总（含边界）
+	// 每个监测项的结果按 day 升序排列；无数据的日期不返回
+	GetDailyRollupBatch(keys []MonitorKey, sinceDay, untilDay string) (map[MonitorKey][]DailyRollupRow, error)
+}
+
+// rollupDay 将 Unix 时间戳转换为汇总使用的日期（UTC）
+func rollupDay(timestamp int64) string {
+	return time.Unix(timestamp, 0).UTC().Format("2006-01-02")
+}
+
+// rollupStatusCounts 将单条记录的状态映射为 (green, yellow, red) 增量
+func rollupStatusCounts(status int) (green, yellow, red int) {
+	switch status {
+	case 1:
+		return 1, 0, 0
+	case 2:
+		return 0, 1, 0
+	case 0:
+		return 0, 0, 1
+	default:
+		return 0, 0, 0
+	}
+}
+
+// dailyRollupDelta 一批记录对单个监测项单日汇总的增量
+type dailyRollupDelta struct {
+	Key MonitorKey
+	DailyRollupRow
+}
+
+// groupDailyRollups 按 (监测项, 日期) 合并一批记录的汇总增量（保持首次出现顺序）
+func groupDailyRollups(records []*ProbeRecord) []dailyRollupDelta {
+	type groupKey struct {
+		key MonitorKey
+		day string
+	}
+	index := make(map[groupKey]int)
+	deltas := make([]dailyRollupDelta, 0)
+	for _, r := range records {
+		gk := groupKey{
+			key: MonitorKey{Provider: r.Provider, Service: r.Service, Channel: r.Channel, Model: r.Model},
+			day: rollupDay(r.Timestamp),
+		}
+		i, ok := index[gk]
+		if !ok {
+			i = len(deltas)
+			index[gk] = i
+			deltas = append(deltas, dailyRollupDelta{Key: gk.key, DailyRollupRow: DailyRollupRow{Day: gk.day}})
+		}
+		green, yellow, red := rollupStatusCounts(r.Status)
+		deltas[i].Total++
+		deltas[i].Green += green
+		deltas[i].Yellow += yellow
+		deltas[i].Red += red
+		if r.Status > 0 {
+			deltas[i].LatencySum += int64(r.Latency)
+			deltas[i].LatencyCount++
+		}
+	}
+	return deltas
+}
+
+// ===== 自助测试分享结果相关类型 =====
+
+// SelfTestResult 已分享的自助测试结果快照（不含 API Key 与响应片段）
+type SelfTestResult struct {
+	// ID 任务 ID（UUID）
+	ID string
+
+	TestType string
+	APIURL   string
+
+	// Status 任务最终状态（success/failed/timeout/canceled）
+	Status string
+
+	ProbeStatus  int
+	SubStatus    string
+	HTTPCode     int
+	Latency      int
+	ErrorMessage string
+
+	// 时间戳（Unix 秒）
+	CreatedAt  int64
+	FinishedAt int64
+	ExpiresAt  int64 // 分享到期时间，到期后不再返回并由清理逻辑删除
+}
+
+// SelfTestResultStorage 为"自助测试结果分享"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现；未实现时分享接口返回 501。
+type SelfTestResultStorage interface {
+	// SaveSelfTestResult 保存分享结果（按 id UPSERT，重复分享时刷新到期时间）
+	SaveSelfTestResult(result *SelfTestResult) error
+
+	// GetSelfTestResult 按任务 ID 查询分享结果，不存在时返回 (nil, nil)
+	GetSelfTestResult(id string) (*SelfTestResult, error)
+
+	// PurgeExpiredSelfTestResults 删除 expires_at <= now 的分享结果，返回删除行数
+	PurgeExpiredSelfTestResults(now int64) (int64, error)
+}
+
+// ===== 审计日志相关类型 =====
+
+// AuditEntry 审计日志条目（谁在何时做了什么）
+type AuditEntry struct {
+	ID        int64
+	Timestamp int64 // Unix 秒
+
+	// Actor 操作者（如 "admin_token"、"system:watcher"），不记录令牌原文
+	Actor string
+
+	// Action 操作类型（如 "config.reload"、"admin.audit.query"）
+	Action string
+
+	// Target 操作对象（可选，如配置文件路径、查询的资源）
+	Target string
+
+	// Detail 附加信息（可选，JSON 或纯文本）
+	Detail string
+
+	// IP 来源 IP（系统操作为空）
+	IP string
+
+	// Result 操作结果："success" 或 "failure"
+	Result string
+}
+
+// AuditFilter 审计日志查询条件（零值字段表示不过滤）
+type AuditFilter struct {
+	Actor  string
+	Action string // 支持前缀匹配：以 "." 结尾时匹配该前缀下的全部操作（如 "config."）
+	Since  int64  // Unix 秒（含）
+	Until  int64  // Unix 秒（含）
+	// BeforeID 游标分页：仅返回 id < BeforeID 的记录（按 id 倒序）
+	BeforeID int64
+	Limit    int
+}
+
+// AuditStorage 为"审计日志"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现；未实现时审计记录被忽略，/api/admin/audit 返回 501。
+type AuditStorage interface {
+	// AddAuditEntry 写入一条审计日志
+	AddAuditEntry(entry *AuditEntry) error
+
+	// GetAuditEntries 按条件查询审计日志（按 id 倒序）
+	GetAuditEntries(filter AuditFilter) ([]*AuditEntry, error)
+
+	// PurgeAuditEntries 删除 timestamp < before 的审计日志，返回删除行数
+	PurgeAuditEntries(before int64) (int64, error)
+}
+
+// ===== 探测调试捕获相关类型 =====
+
+// ProbeDebugEntry 单次探测的调试快照（请求/响应元数据）
+type ProbeDebugEntry struct {
+	ID        int64
+	Provider  string
+	Service   string
+	Channel   string
+	Model     string
+	Timestamp int64 // Unix 秒
+
+	Status    int
+	SubStatus SubStatus
+	HttpCode  int
+	Latency   int // 毫秒
+	Attempts  int // 实际尝试次数（含重试）
+
+	Method          string
+	URL             string
+	RequestHeaders  string // JSON 对象，敏感值已脱敏
+	ResponseHeaders string // JSON 对象
+	ResponseBody    string // 按 max_body_bytes 截断
+	BodyTruncated   bool
+	Error           string
+
+	// 连接阶段耗时（毫秒，-1 表示该阶段未发生，如复用连接时无 DNS/TCP/TLS）
+	DNSMs     int
+	ConnectMs int
+	TLSMs     int
+	TTFBMs    int
+
+	ExpiresAt int64 // Unix 秒，过期后由 PurgeExpiredProbeDebug 清理
+}
+
+// ProbeDebugFilter 调试快照查询条件（零值字段表示不过滤）
+type ProbeDebugFilter struct {
+	Provider string
+	Service  string
+	Channel  string
+	Model    string
+	// BeforeID 游标分页：仅返回 id < BeforeID 的记录（按 id 倒序）
+	BeforeID int64
+	Limit    int
+}
+
+// ProbeDebugStorage 为"探测调试捕获"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现；未实现时捕获结果被丢弃，/api/admin/probe-debug 返回 501。
+type ProbeDebugStorage interface {
+	// SaveProbeDebug 写入一条调试快照
+	SaveProbeDebug(entry *ProbeDebugEntry) error
+
+	// GetProbeDebugEntries 按条件查询调试快照（按 id 倒序）
+	GetProbeDebugEntries(filter ProbeDebugFilter) ([]*ProbeDebugEntry, error)
+
+	// PurgeExpiredProbeDebug 删除 expires_at <= now 的快照，返回删除行数
+	PurgeExpiredProbeDebug(now int64) (int64, error)
+}
+
+// ===== 事件 Webhook 死信相关类型 =====
+
+// WebhookDeadLetter 重试耗尽仍投递失败的事件 Webhook
+type WebhookDeadLetter struct {
+	ID        int64
+	URL       string
+	EventID   int64
+	EventType EventType
+	Payload   string // 投递的 JSON 请求体（可直接重放）
+	Attempts  int
+	LastError string
+	CreatedAt int64 // Unix 秒
+}
+
+// WebhookDeadLetterFilter 死信查询条件（零值字段表示不过滤）
+type WebhookDeadLetterFilter struct {
+	URL string
+	// BeforeID 游标分页：仅返回 id < BeforeID 的记录（按 id 倒序）
+	BeforeID int64
+	Limit    int
+}
+
+// WebhookDeadLetterStorage 为"事件 Webhook 死信"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现；未实现时死信仅写入日志，/api/admin/webhook-dead-letters 返回 501。
+type WebhookDeadLetterStorage interface {
+	// SaveWebhookDeadLetter 写入一条死信
+	SaveWebhookDeadLetter(dl *WebhookDeadLetter) error
+
+	// GetWebhookDeadLetters 按条件查询死信（按 id 倒序）
+	GetWebhookDeadLetters(filter WebhookDeadLetterFilter) ([]*WebhookDeadLetter, error)
+}
+
+// ===== 事件发件箱（outbox）相关类型 =====
+
+// OutboxEntry 事件发件箱中的一条待投递记录（每个事件的每个投递目标一条）
+type OutboxEntry struct {
+	ID            int64
+	EventID       int64
+	Destination   string // 投递目标（Webhook URL）
+	Attempts      int    // 已完成的尝试次数（不含进行中的尝试）
+	NextAttemptAt int64  // Unix 秒
+	LastError     string
+	CreatedAt     int64 // Unix 秒
+
+	// Event 领取时一并加载的事件（事件已被删除时为 nil）
+	Event *StatusEvent
+}
+
+// EventOutboxStorage 为"事件发件箱"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现；未实现时启用 events.outbox 会回退为内存推送。
+// 投递语义为至少一次：领取后在租约期内未完成的记录（如进程崩溃）会在租约到期后被重新领取。
+type EventOutboxStorage interface {
+	// SaveStatusEventWithOutbox 在同一事务中保存事件并为每个投递目标写入一条待投递记录
+	// 重复事件（唯一约束冲突）视为成功且不写入待投递记录，此时 event.ID 保持为 0
+	SaveStatusEventWithOutbox(event *StatusEvent, destinations []string) error
+
+	// ClaimOutboxEntries 按 id 升序领取最多 limit 条 next_attempt_at <= now 的未完成记录，
+	// 并将其 next_attempt_at 推迟到 now+lease，租约期内其它调用（含其它实例）不会再次领取
+	ClaimOutboxEntries(now int64, lease time.Duration, limit int) ([]*OutboxEntry, error)
+
+	// CompleteOutboxEntry 标记记录已完成（投递成功或已写入死信），lastError 为最后一次失败原因
+	CompleteOutboxEntry(id int64, attempts int, doneAt int64, lastError string) error
+
+	// RetryOutboxEntry 记录失败的尝试次数并设置下次尝试时间
+	RetryOutboxEntry(id int64, attempts int, nextAttemptAt int64, lastError string) error
+
+	// PurgeCompletedOutbox 删除 done_at < before 的已完成记录，返回删除行数
+	PurgeCompletedOutbox(before int64) (int64, error)
+}
+
+// ===== 探测数据透明度相关类型 =====
+
+// TransparencyRoot 单个小时探测记录的签名 Merkle 根
+//
+// 各小时按时间顺序封存，PrevRoot 指向上一个已封存小时的 Root，形成哈希链：
+// 篡改、删除或插入任意历史记录都会导致对应小时及之后的签名校验失败。
+type TransparencyRoot struct {
+	// Hour 小时起始时间（Unix 秒，UTC 整点），覆盖 [Hour, Hour+3600) 的探测记录
+	Hour int64
+
+	// LeafCount 叶子数（该小时的探测记录数）
+	LeafCount int
+
+	// Root Merkle 根（十六进制 SHA-256）
+	Root string
+
+	// PrevRoot 上一个已封存小时的 Root（首个为空）
+	PrevRoot string
+
+	// KeyID 签名公钥标识（公钥 SHA-256 的前 16 位十六进制）
+	KeyID string
+
+	// Signature 对签名消息的 Ed25519 签名（base64）
+	Signature string
+
+	CreatedAt int64 // Unix 秒
+}
+
+// TransparencyStorage 为"探测数据透明度"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现；未实现时不执行封存，/api/transparency 返回 501。
+type TransparencyStorage interface {
+	// GetRecordsInRange 查询 [since, until) 时间范围内的全部探测记录（按 id 升序）
+	GetRecordsInRange(since, until int64) ([]*ProbeRecord, error)
+
+	// SaveTransparencyRoot 保存小时根（该小时已存在时忽略）
+	SaveTransparencyRoot(root *TransparencyRoot) error
+
+	// GetLatestTransparencyRoot 查询最近封存的小时根，不存在时返回 (nil, nil)
+	GetLatestTransparencyRoot() (*TransparencyRoot, error)
+
+	// GetTransparencyRoots 查询 [sinceHour, untilHour] 范围内的小时根（按 hour 升序）
+	GetTransparencyRoots(sinceHour, untilHour int64) ([]*TransparencyRoot, error)
+}
+
+// ===== 已下线监测项相关类型 =====
+
+// DecommissionedMonitor 已从配置中移除的监测项
+// 保留展示所需的元数据，宽限期内仍可通过 /api/status?include_retired=true 查询其历史
+type DecommissionedMonitor struct {
+	Provider string
+	Service  string
+	Channel  string
+	Model    string
+	Parent   string // 父通道引用（子层非空），用于重建 groups 层级
+
+	ProviderName string
+	ProviderSlug string
+	ProviderURL  string
+	ServiceName  string
+	ChannelName  string
+	Category     string
+	Board        string
+	Hidden       bool
+
+	RetiredAt int64 // Unix 秒，下线（从配置移除）的时间
+}
+
+// Key 返回监测项键
+func (m *DecommissionedMonitor) Key() MonitorKey {
+	return MonitorKey{Provider: m.Provider, Service: m.Service, Channel: m.Channel, Model: m.Model}
+}
+
+// DecommissionStorage 为"已下线监测项登记与清理"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现；未实现时不登记下线监测项，include_retired 参数无效果。
+type DecommissionStorage interface {
+	// SaveDecommissionedMonitors 登记已下线监测项（已登记时保留原下线时间）
+	SaveDecommissionedMonitors(monitors []*DecommissionedMonitor) error
+
+	// DeleteDecommissionedMonitors 移除登记但保留历史数据（监测项重新加入配置时调用）
+	DeleteDecommissionedMonitors(keys []MonitorKey) error
+
+	// GetDecommissionedMonitors 查询全部登记（按 retired_at 升序）
+	GetDecommissionedMonitors() ([]*DecommissionedMonitor, error)
+
+	// PurgeMonitorRecords 删除指定监测项的一批探测记录，返回实际删除的行数
+	// 调用方负责循环调用直到无更多数据
+	PurgeMonitorRecords(ctx context.Context, key MonitorKey, batchSize int) (int64, error)
+
+	// DropDecommissionedMonitor 删除登记及该监测项的每日汇总、状态机状态与元数据版本（探测记录清理完成后调用）
+	DropDecommissionedMonitor(ctx context.Context, key MonitorKey) error
+}
+
+// ===== 服务商数据访问令牌相关类型 =====
+
+// ProviderToken 服务商数据访问令牌
+// 仅保存明文令牌的 SHA-256，明文只在签发时返回一次
+type ProviderToken struct {
+	ID           int64
+	ProviderSlug string // 可访问的服务商（provider_slug）
+	Name         string // 备注（如对接人/用途）
+	TokenHash    string // 明文令牌的 SHA-256（十六进制）
+	TokenPrefix  string // 明文令牌前缀（便于识别，不可用于鉴权）
+	CreatedAt    int64  // Unix 秒
+	RevokedAt    int64  // Unix 秒，0 表示有效
+}
+
+// ProviderTokenStorage 为"服务商数据访问令牌"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现；未实现时令牌管理与 /api/provider-portal/* 返回 501。
+type ProviderTokenStorage interface {
+	// CreateProviderToken 保存新令牌（回填 ID）
+	CreateProviderToken(token *ProviderToken) error
+
+	// GetProviderTokenByHash 按令牌哈希查询，不存在时返回 (nil, nil)
+	GetProviderTokenByHash(tokenHash string) (*ProviderToken, error)
+
+	// ListProviderTokens 查询令牌列表（按 id 倒序），providerSlug 为空时返回全部
+	ListProviderTokens(providerSlug string) ([]*ProviderToken, error)
+
+	// RevokeProviderToken 吊销令牌，返回令牌是否存在且此前有效
+	RevokeProviderToken(id, revokedAt int64) (bool, error)
+}
+
+// ===== 运维标注相关类型 =====
+
+// Annotation 运维标注：为监测项的某段时间附加说明（如"服务商确认上游故障"）
+// Service/Channel/Model 为空表示匹配该层级下的全部监测项
+type Annotation struct {
+	ID        int64
+	Provider  string
+	Service   string
+	Channel   string
+	Model     string
+	StartTime int64  // Unix 秒
+	EndTime   int64  // Unix 秒，0 表示尚未结束
+	Text      string // 说明文本
+	Link      string // 相关链接（可选，如公告/工单地址）
+	CreatedAt int64  // Unix 秒
+}
+
+// Matches 判断标注是否适用于指定监测项
+func (a *Annotation) Matches(provider, service, channel, model string) bool {
+	if a.Provider != provider {
+		return false
+	}
+	if a.Service != "" && a.Service != service {
+		return false
+	}
+	if a.Channel != "" && a.Channel != channel {
+		return false
+	}
+	return a.Model == "" || a.Model == model
+}
+
+// Covers 判断标注时间段是否包含指定时间点
+func (a *Annotation) Covers(ts int64) bool {
+	return ts >= a.StartTime && (a.EndTime == 0 || ts <= a.EndTime)
+}
+
+// AnnotationFilter 运维标注查询条件（零值字段表示不过滤）
+type AnnotationFilter struct {
+	Provider string
+	Since    int64 // Unix 秒：仅返回结束时间不早于 Since 的标注（未结束的标注始终匹配）
+	Until    int64 // Unix 秒：仅返回开始时间不晚于 Until 的标注
+	Limit    int
+}
+
+// AnnotationStorage 为"运维标注"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现；未实现时标注管理接口返回 501，状态与事件响应不附带标注。
+type AnnotationStorage interface {
+	// CreateAnnotation 保存新标注（回填 ID）
+	CreateAnnotation(annotation *Annotation) error
+
+	// GetAnnotations 查询与时间范围重叠的标注（按 start_time、id 升序）
+	GetAnnotations(filter AnnotationFilter) ([]*Annotation, error)
+
+	// DeleteAnnotation 删除标注，返回标注是否存在
+	DeleteAnnotation(id int64) (bool, error)
+}
+
+// ===== 自定义分组相关类型 =====
+
+// CustomGroupMember 分组成员（监测项 key，provider 为名称或 slug，channel 为空表示未配置 channel 的监测项）
+type CustomGroupMember struct {
+	Provider string `json:"provider"`
+	Service  string `json:"service"`
+	Channel  string `json:"channel,omitempty"`
+}
+
+// CustomGroup 通过管理 API 创建的自定义分组（配置文件中的分组不入库）
+type CustomGroup struct {
+	ID          string // URL 安全的分组标识
+	Name        string
+	Description string
+	Members     []CustomGroupMember
+	CreatedAt   int64 // Unix 秒
+	UpdatedAt   int64 // Unix 秒
+}
+
+// CustomGroupStorage 为"自定义分组"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现；未实现时仅配置文件中的分组可用，分组管理接口返回 501。
+type CustomGroupStorage interface {
+	// SaveCustomGroup 创建或覆盖分组（按 ID），覆盖时保留原 created_at
+	SaveCustomGroup(group *CustomGroup) error
+
+	// ListCustomGroups 查询全部分组（按 id 升序）
+	ListCustomGroups() ([]*CustomGroup, error)
+
+	// DeleteCustomGroup 删除分组，返回分组是否存在
+	DeleteCustomGroup(id string) (bool, error)
+}
+
+// ===== 服务商元数据自助修改相关类型 =====
+
+// 元数据修改申请状态
+const (
+	MetadataRequestPending  = "pending"
+	MetadataRequestApproved = "approved"
+	MetadataRequestRejected = "rejected"
+)
+
+// MetadataRequest 服务商通过数据门户提交的元数据修改申请（sponsor_url、price_min 等）
+// 管理员审核通过后写入运行时覆盖（overrides.yaml）生效
+type MetadataRequest struct {
+	ID           int64
+	ProviderSlug string
+	TokenID      int64  // 提交申请的服务商令牌 ID
+	Changes      string // 申请修改的字段（JSON 对象）
+	Comment      string // 提交说明（可选）
+	Status       string // pending / approved / rejected
+	ReviewNote   string // 审核备注（可选）
+	CreatedAt    int64  // Unix 秒
+	ReviewedAt   int64  // Unix 秒，0 表示尚未审核
+}
+
+// MetadataRequestFilter 元数据修改申请查询条件（零值字段表示不过滤）
+type MetadataRequestFilter struct {
+	ProviderSlug string
+	Status       string
+	Limit        int
+}
+
+// MetadataRequestStorage 为"服务商元数据自助修改"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现；未实现时 /api/provider-portal/metadata 与审核接口返回 501。
+type MetadataRequestStorage interface {
+	// CreateMetadataRequest 保存新申请（回填 ID）
+	CreateMetadataRequest(req *MetadataRequest) error
+
+	// GetMetadataRequest 按 ID 查询，不存在时返回 (nil, nil)
+	GetMetadataRequest(id int64) (*MetadataRequest, error)
+
+	// ListMetadataRequests 查询申请列表（按 id 倒序）
+	ListMetadataRequests(filter MetadataRequestFilter) ([]*MetadataRequest, error)
+
+	// ReviewMetadataRequest 将待审核申请标记为 approved / rejected，返回申请是否存在且此前待审核
+	ReviewMetadataRequest(id int64, status, reviewNote string, reviewedAt int64) (bool, error)
+}
+
+// ===== 监测项元数据版本相关类型 =====
+
+// MonitorMetadata 监测项展示元数据在某一时刻的快照
+// 服务商改名、赞助/价格/徽标变更时写入新版本，历史接口按记录时间关联当时生效的版本
+type MonitorMetadata struct {
+	Provider string
+	Service  string
+	Channel  string
+	Model    string
+
+	ProviderName string
+	ProviderSlug string
+	Sponsor      string
+	SponsorURL   string
+	PriceMin     *float64 // nil 表示未配置
+	PriceMax     *float64 // nil 表示未配置
+	Badges       []string // 生效的徽标 ID（按展示顺序）
+
+	ValidFrom int64 // Unix 秒，该版本开始生效的时间
+}
+
+// Key 返回监测项键
+func (m *MonitorMetadata) Key() MonitorKey {
+	return MonitorKey{Provider: m.Provider, Service: m.Service, Channel: m.Channel, Model: m.Model}
+}
+
+// MonitorMetadataStorage 为"监测项元数据版本"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现；未实现时不记录元数据版本，导出接口的 metadata 参数返回 501。
+type MonitorMetadataStorage interface {
+	// SaveMonitorMetadata 写入新版本（同一监测项同一 valid_from 已存在时覆盖）
+	SaveMonitorMetadata(versions []*MonitorMetadata) error
+
+	// GetLatestMonitorMetadata 查询每个监测项当前（valid_from 最大）的版本
+	GetLatestMonitorMetadata() (map[MonitorKey]*MonitorMetadata, error)
+
+	// GetMonitorMetadataHistory 查询 [since, until) 内生效过的版本（按 valid_from 升序）
+	// 包含 since 时刻正在生效的版本（valid_from <= since 中最新的一条）
+	GetMonitorMetadataHistory(key MonitorKey, since, until int64) ([]*MonitorMetadata, error)
+}
+
+// ===== 通道改名/合并相关类型 =====
+
+// ChannelRename 将 provider/service 下某通道的历史数据迁移到另一通道
+// 目标通道已有数据时合并：每日汇总与用量累加，状态机状态保留最近更新的一条
+type ChannelRename struct {
+	Provider string
+	Service  string
+	From     string
+	To       string
+}
+
+// ChannelRenameCount 单张表受影响的行数
+type ChannelRenameCount struct {
+	Table string
+	Rows  int64
+}
+
+// ChannelRenameStorage 为"通道改名/合并"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现；供 cmd/migrate-channel 使用。
+type ChannelRenameStorage interface {
+	// RenameChannel 在单个事务内迁移各表数据，返回各表受影响的行数
+	// dryRun 时执行后回滚，不修改数据
+	RenameChannel(ctx context.Context, rename ChannelRename, dryRun bool) ([]ChannelRenameCount, error)
+}
+
+// ===== 管理后台用户与会话相关类型 =====
+
+// AdminUser 管理后台本地用户
+// 仅保存密码的 argon2id 哈希（PHC 字符串格式）
+type AdminUser struct {
+	ID           int64
+	Username     string
+	PasswordHash string
+	Role         string // viewer / operator / admin
+	CreatedAt    int64  // Unix 秒
+	UpdatedAt    int64  // Unix 秒
+	DisabledAt   int64  // Unix 秒，0 表示启用
+}
+
+// AdminSession 管理后台登录会话
+// 仅保存明文会话令牌的 SHA-256，明文只在登录时返回一次（Cookie 或响应体）
+type AdminSession struct {
+	ID        int64
+	UserID    int64
+	TokenHash string // 明文令牌的 SHA-256（十六进制）
+	CreatedAt int64  // Unix 秒
+	ExpiresAt int64  // Unix 秒
+	IP        string // 登录 IP
+}
+
+// AdminUserStorage 为"管理后台用户与会话"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现；未实现时仅能使用 ADMIN_API_TOKEN 访问管理 API，账号相关接口返回 501。
+type AdminUserStorage interface {
+	// CreateAdminUser 保存新用户（回填 ID），用户名已存在时返回错误
+	CreateAdminUser(user *AdminUser) error
+
+	// GetAdminUser 按 ID 查询，不存在时返回 (nil, nil)
+	GetAdminUser(id int64) (*AdminUser, error)
+
+	// GetAdminUserByUsername 按用户名查询，不存在时返回 (nil, nil)
+	GetAdminUserByUsername(username string) (*AdminUser, error)
+
+	// ListAdminUsers 查询全部用户（按 id 升序）
+	ListAdminUsers() ([]*AdminUser, error)
+
+	// UpdateAdminUser 更新用户的密码哈希、角色与停用状态，返回用户是否存在
+	UpdateAdminUser(user *AdminUser) (bool, error)
+
+	// DeleteAdminUser 删除用户及其全部会话，返回用户是否存在
+	DeleteAdminUser(id int64) (bool, error)
+
+	// CreateAdminSession 保存新会话（回填 ID）
+	CreateAdminSession(session *AdminSession) error
+
+	// GetAdminSessionByHash 按令牌哈希查询会话（含已过期的会话，由调用方判断），不存在时返回 (nil, nil)
+	GetAdminSessionByHash(tokenHash string) (*AdminSession, error)
+
+	// DeleteAdminSession 删除会话（登出）
+	DeleteAdminSession(tokenHash string) error
+
+	// DeleteAdminUserSessions 删除用户的全部会话（改密、改角色、停用后强制重新登录）
+	DeleteAdminUserSessions(userID int64) error
+
+	// DeleteExpiredAdminSessions 删除已过期的会话，返回删除的行数
+	DeleteExpiredAdminSessions(now int64) (int64, error)
+}
+
+// StorageDiagnostics 存储诊断信息（表与索引大小、索引使用情况、启动以来的慢查询与索引建议）
+type StorageDiagnostics struct {
+	Backend   string // sqlite / postgres
+	Tables    []TableDiagnostics
+	Indexes   []IndexDiagnostics
+	SlowQuery SlowQuerySummary
+	Advice    []string // 索引建议（基于慢查询执行计划与索引使用统计的启发式规则）
+}
+
+// TableDiagnostics 单张表的大小与扫描统计
+type TableDiagnostics struct {
+	Name       string
+	Rows       int64 // SQLite 为精确行数，PostgreSQL 为统计信息中的估算值（n_live_tup）
+	TotalBytes int64 // 表与索引的总大小
+	IndexBytes int64
+	SeqScans   *int64 // 顺序扫描次数（仅 PostgreSQL，SQLite 不统计）
+	IndexScans *int64 // 索引扫描次数（仅 PostgreSQL，SQLite 不统计）
+}
+
+// IndexDiagnostics 单个索引的大小与使用统计
+type IndexDiagnostics struct {
+	Table  string
+	Name   string
+	Bytes  int64
+	Unique bool   // 唯一索引或主键（不参与"未使用索引"建议）
+	Scans  *int64 // 自统计重置以来的扫描次数（仅 PostgreSQL，SQLite 不统计）
+}
+
+// SlowQuerySummary 启动以来的慢查询汇总
+type SlowQuerySummary struct {
+	Enabled   bool
+	Threshold time.Duration
+	Since     time.Time // 开始统计的时间（存储初始化时间）
+	Total     int64     // 慢查询总次数（含因条数上限未保留明细的 SQL）
+	Top       []SlowQueryStat
+}
+
+// SlowQueryStat 按 SQL 文本（折叠空白与多行 VALUES 占位符）聚合的慢查询统计
+type SlowQueryStat struct {
+	SQL       string
+	Count     int64
+	TotalTime time.Duration
+	MaxTime   time.Duration
+	LastTime  time.Duration
+	LastSeen  time.Time
+	LastError string // 最近一次执行的错误（成功时为空）
+	Plan      string // 最近一次 EXPLAIN 结果（未启用或获取失败时为空）
+}
+
+// DiagnosticsStorage 为"存储诊断"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现；慢查询统计需启用 storage.slow_query。
+type DiagnosticsStorage interface {
+	// GetStorageDiagnostics 返回表与索引统计、按累计耗时降序的前 topSlow 条慢查询以及索引建议
+	GetStorageDiagnostics(topSlow int) (*StorageDiagnostics, error)
+}
+
+// MaintenanceOptions 单轮数据库维护参数
+type MaintenanceOptions struct {
+	VacuumThreshold  float64 // SQLite 空闲页占比 / PostgreSQL 死元组占比达到该值时 VACUUM
+	ReindexThreshold float64 // 索引页内未使用空间占比达到该值时 REINDEX（>= 1 表示不 REINDEX）
+	IncrementalPages int     // SQLite 单轮 incremental_vacuum 最多回收的页数
+}
+
+// MaintenanceStep 维护过程中执行（或评估后跳过）的单个动作
+type MaintenanceStep struct {
+	Action   string // vacuum / incremental_vacuum / analyze / reindex / checkpoint
+	Target   string // 表名或索引名，整库操作为空
+	Detail   string // 触发原因或执行结果（如 "free_ratio=0.23"）
+	Duration time.Duration
+	Error    string // 执行失败时的错误信息
+}
+
+// MaintenanceStorage 为"数据库维护"提供的可选能力接口
+//
+// SQLite 与 PostgreSQL 均实现。PostgreSQL 使用 advisory lock 确保多实例互斥，
+// 未获取锁时返回 ErrMaintenanceLockNotAcquired。
+type MaintenanceStorage interface {
+	// RunMaintenance 按阈值执行 VACUUM / ANALYZE / REINDEX，返回已执行的步骤
+	// 单个步骤失败不会中断后续步骤，错误记录在对应 MaintenanceStep.Error 中
+	RunMaintenance(ctx context.Context, opts MaintenanceOptions) ([]MaintenanceStep, error)
+}
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+)
+
+// errWriteQueueClosed 存储关闭后仍有写入
+var errWriteQueueClosed = errors.New("写入队列已关闭")
+
+// batchInsertFunc 在单个事务内写入一批探测记录并回填 ID
+type batchInsertFunc func(ctx context.Context, records []*ProbeRecord) error
+
+// writeRequest 单条待写入记录
+type writeRequest struct {
+	record *ProbeRecord
+	errCh  chan error // 所在批次提交后回传结果（缓冲 1）
+}
+
+// writeQueue 探测记录批量写入队列（组提交）
+//
+// SaveRecord 将记录放入有界队列后阻塞等待，后台协程按条数或时间间隔合并为一批写入，
+// 再把结果逐一回传。调用方语义与直接写入一致（返回时记录已落库、ID 已回填），
+// 但多条并发写入只占用一次事务，显著减少 SQLite 单连接下的串行等待。
+// 队列满时入队阻塞（背压）；close 会先写完队列中剩余记录再返回。
+type writeQueue struct {
+	insert   batchInsertFunc
+	maxBatch int
+	interval time.Duration
+
+	mu     sync.RWMutex // 保护 closed 与 items 的关闭
+	closed bool
+	items  chan *writeRequest
+	done   chan struct{}
+}
+
+// newWriteQueue 创建并启动批量写入队列
+func newWriteQueue(cfg config.WriteBatchConfig, insert batchInsertFunc) *writeQueue {
+	q := &writeQueue{
+		insert:   insert,
+		maxBatch: cfg.MaxBatchSize,
+		interval: cfg.FlushIntervalDuration,
+		items:    make(chan *writeRequest, cfg.QueueSize),
+		done:     make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// submit 入队并等待所在批次提交
+// 仅在入队阶段响应 ctx 取消；已入队的记录总会被写入，调用方需等待结果以拿到 ID
+func (q *writeQueue) submit(ctx context.Context, record *ProbeRecord) error {
+	req := &writeRequest{record: record, errCh: make(chan error, 1)}
+
+	q.mu.RLock()
+	if q.closed {
+		q.mu.RUnlock()
+		return errWriteQueueClosed
+	}
+	select {
+	case q.items <- req:
+	case <-ctx.Done():
+		q.mu.RUnlock()
+		return ctx.Err()
+	}
+	q.mu.RUnlock()
+
+	return <-req.errCh
+}
+
+// run 后台攒批写入，队列关闭后写完剩余记录并退出
+func (q *writeQueue) run() {
+	defer close(q.done)
+
+	ticker := time.NewTicker(q.interval)
+	defer ticker.Stop()
+
+	batch := make([]*writeRequest, 0, q.maxBatch)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		q.flush(batch)
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case req, ok := <-q.items:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, req)
+			if len(batch) >= q.maxBatch {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// flush 写入一批记录并回传结果（整批同成败）
+func (q *writeQueue) flush(batch []*writeRequest) {
+	records := make([]*ProbeRecord, len(batch))
+	for i, req := range batch {
+		records[i] = req.record
+	}
+
+	err := q.insert(context.Background(), records)
+	if err != nil {
+		logger.Error("storage", "批量写入探测记录失败", "records", len(records), "error", err)
+	}
+	for _, req := range batch {
+		req.errCh <- err
+	}
+}
+
+// close 停止接收新记录，写完队列中剩余记录后返回
+func (q *writeQueue) close() {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.items)
+	}
+	q.mu.Unlock()
+	<-q.done
+}
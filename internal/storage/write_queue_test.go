@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+)
+
+// recordingInsert 记录每次批量写入的条数，可选阻塞或返回固定错误
+type recordingInsert struct {
+	mu      sync.Mutex
+	batches []int
+	err     error
+	started chan struct{} // 非 nil 时每次写入开始发送一次信号
+	release chan struct{} // 非 nil 时写入阻塞到关闭
+}
+
+func (r *recordingInsert) insert(ctx context.Context, records []*ProbeRecord) error {
+	if r.started != nil {
+		r.started <- struct{}{}
+	}
+	if r.release != nil {
+		<-r.release
+	}
+	r.mu.Lock()
+	r.batches = append(r.batches, len(records))
+	r.mu.Unlock()
+	return r.err
+}
+
+func (r *recordingInsert) snapshot() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int(nil), r.batches...)
+}
+
+// newStoppedWriteQueue 创建尚未启动后台协程的队列，便于测试先把记录放入队列
+func newStoppedWriteQueue(insert batchInsertFunc, maxBatch, queueSize int, interval time.Duration) *writeQueue {
+	return &writeQueue{
+		insert:   insert,
+		maxBatch: maxBatch,
+		interval: interval,
+		items:    make(chan *writeRequest, queueSize),
+		done:     make(chan struct{}),
+	}
+}
+
+// submitAsync 并发提交 n 条记录，返回按提交顺序排列的结果通道
+func submitAsync(q *writeQueue, n int) []chan error {
+	results := make([]chan error, n)
+	for i := range results {
+		results[i] = make(chan error, 1)
+		go func(ch chan error) {
+			ch <- q.submit(context.Background(), &ProbeRecord{Provider: "p", Service: "cc"})
+		}(results[i])
+	}
+	return results
+}
+
+// waitQueued 等待队列中积压 n 条记录
+func waitQueued(t *testing.T, q *writeQueue, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(q.items) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("队列中记录数 %d，期望 %d", len(q.items), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func waitResult(t *testing.T, ch chan error) error {
+	t.Helper()
+	select {
+	case err := <-ch:
+		return err
+	case <-time.After(2 * time.Second):
+		t.Fatal("等待写入结果超时")
+		return nil
+	}
+}
+
+func TestWriteQueueBackpressure(t *testing.T) {
+	rec := &recordingInsert{started: make(chan struct{}, 1), release: make(chan struct{})}
+	q := newStoppedWriteQueue(rec.insert, 1, 1, time.Hour)
+	go q.run()
+	defer q.close()
+
+	// 第 1 条进入写入并阻塞，第 2 条占满队列
+	first := submitAsync(q, 1)
+	<-rec.started
+	second := submitAsync(q, 1)
+	waitQueued(t, q, 1)
+
+	// 队列已满：入队阻塞直到 ctx 超时
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := q.submit(ctx, &ProbeRecord{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("队列满时应阻塞到 ctx 超时，got %v", err)
+	}
+
+	close(rec.release)
+	if err := waitResult(t, first[0]); err != nil {
+		t.Fatalf("first submit: %v", err)
+	}
+	<-rec.started
+	if err := waitResult(t, second[0]); err != nil {
+		t.Fatalf("second submit: %v", err)
+	}
+}
+
+func TestWriteQueueDrainOnClose(t *testing.T) {
+	rec := &recordingInsert{}
+	q := newStoppedWriteQueue(rec.insert, 10, 10, time.Hour)
+
+	results := submitAsync(q, 3)
+	waitQueued(t, q, 3)
+	go q.run()
+
+	// 未达到条数、也未到刷新间隔：关闭时写完剩余记录
+	q.close()
+	for i, ch := range results {
+		if err := waitResult(t, ch); err != nil {
+			t.Fatalf("submit %d: %v", i, err)
+		}
+	}
+	if got := rec.snapshot(); len(got) != 1 || got[0] != 3 {
+		t.Fatalf("关闭时应合并写入剩余 3 条，got %v", got)
+	}
+
+	if err := q.submit(context.Background(), &ProbeRecord{}); !errors.Is(err, errWriteQueueClosed) {
+		t.Fatalf("关闭后写入应返回 errWriteQueueClosed，got %v", err)
+	}
+}
+
+func TestWriteQueueErrorFanOut(t *testing.T) {
+	errBoom := errors.New("boom")
+	rec := &recordingInsert{err: errBoom}
+	q := newStoppedWriteQueue(rec.insert, 3, 3, time.Hour)
+
+	results := submitAsync(q, 3)
+	waitQueued(t, q, 3)
+	go q.run()
+	defer q.close()
+
+	// 整批失败：每个调用方都拿到同一错误
+	for i, ch := range results {
+		if err := waitResult(t, ch); !errors.Is(err, errBoom) {
+			t.Fatalf("submit %d: 期望批次错误，got %v", i, err)
+		}
+	}
+	if got := rec.snapshot(); len(got) != 1 || got[0] != 3 {
+		t.Fatalf("达到 max_batch_size 应合并为一批，got %v", got)
+	}
+}
+
+func TestWriteQueueTickerFlush(t *testing.T) {
+	rec := &recordingInsert{}
+	q := newWriteQueue(config.WriteBatchConfig{
+		MaxBatchSize:          100,
+		QueueSize:             100,
+		FlushIntervalDuration: 10 * time.Millisecond,
+	}, rec.insert)
+	defer q.close()
+
+	// 远未达到条数上限，由定时刷新写入
+	results := submitAsync(q, 1)
+	if err := waitResult(t, results[0]); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if got := rec.snapshot(); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("定时刷新应写入 1 条，got %v", got)
+	}
+}
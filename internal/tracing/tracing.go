@@ -0,0 +1,90 @@
+// Package tracing 初始化 OpenTelemetry 分布式追踪（OTLP/HTTP 导出）
+//
+// 调用方通过 Start/StartKind 创建 otel span；未调用 Init 或 tracing.enabled=false 时
+// 全局 TracerProvider 为 otel 的空实现，span 均不记录，调用方无需判断是否启用。
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+)
+
+// instrumentationName 本服务创建 span 使用的 instrumentation scope
+const instrumentationName = "monitor"
+
+const (
+	exportQueueSize = 2048            // 待导出 span 缓冲，满时丢弃（追踪不能拖慢业务）
+	exportBatchSize = 512             // 单次上报最大 span 数
+	exportInterval  = 5 * time.Second // 定时上报间隔
+)
+
+// Init 按配置初始化全局 TracerProvider（OTLP/HTTP 导出到 <endpoint>/v1/traces）与 W3C traceparent 传播
+// 未启用时返回 nil；返回的 TracerProvider 需在退出前调用 Shutdown 刷新缓冲
+func Init(cfg config.TracingConfig) (*sdktrace.TracerProvider, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(cfg.Endpoint+"/v1/traces"),
+		otlptracehttp.WithHeaders(cfg.Headers),
+		otlptracehttp.WithTimeout(cfg.TimeoutDuration),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("创建 OTLP 导出器失败: %w", err)
+	}
+
+	ratio := 1.0
+	if cfg.SampleRatio != nil {
+		ratio = *cfg.SampleRatio
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter,
+			sdktrace.WithMaxQueueSize(exportQueueSize),
+			sdktrace.WithMaxExportBatchSize(exportBatchSize),
+			sdktrace.WithBatchTimeout(exportInterval),
+		),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", cfg.ServiceName))),
+		// 根 span 按 TraceID 确定性采样；下游请求携带 traceparent 时沿用上游采样决策
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warn("tracing", "上报追踪数据失败", "error", err)
+	}))
+	return tp, nil
+}
+
+// Start 创建 internal 类型的子 span
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return StartKind(ctx, trace.SpanKindInternal, name, attrs...)
+}
+
+// StartKind 创建指定类型的 span（父 span 取自 ctx，含 propagator 注入的远端上下文）
+func StartKind(ctx context.Context, kind trace.SpanKind, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// RecordError 记录错误事件并将 span 标记为失败（err 为 nil 时忽略）
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
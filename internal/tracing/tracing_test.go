@@ -0,0 +1,187 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"monitor/internal/config"
+)
+
+const remoteTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// initForTest 初始化追踪并在测试结束时关闭、恢复全局空实现
+func initForTest(t *testing.T, endpoint string, ratio float64, headers map[string]string) *sdktrace.TracerProvider {
+	t.Helper()
+	tp, err := Init(config.TracingConfig{
+		Enabled:         true,
+		Endpoint:        endpoint,
+		ServiceName:     "test-svc",
+		SampleRatio:     &ratio,
+		Headers:         headers,
+		TimeoutDuration: time.Second,
+	})
+	if err != nil || tp == nil {
+		t.Fatalf("Init() = %v, %v", tp, err)
+	}
+	t.Cleanup(func() {
+		tp.Shutdown(context.Background())
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+	return tp
+}
+
+// withTraceparent 按 W3C traceparent 请求头提取上游上下文（与 API 中间件一致）
+func withTraceparent(ctx context.Context, traceparent string) context.Context {
+	h := http.Header{}
+	h.Set("traceparent", traceparent)
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(h))
+}
+
+func TestInitDisabled(t *testing.T) {
+	tp, err := Init(config.TracingConfig{Enabled: false})
+	if tp != nil || err != nil {
+		t.Fatalf("未启用时应返回 nil, nil: %v, %v", tp, err)
+	}
+
+	// 未初始化时 span 为空实现，不记录也不产生有效 trace_id
+	_, span := Start(context.Background(), "noop", attribute.String("k", "v"))
+	RecordError(span, context.Canceled)
+	span.End()
+	if span.IsRecording() || span.SpanContext().IsValid() {
+		t.Fatalf("未启用追踪时 span 不应被记录")
+	}
+}
+
+func TestExportSpans(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		reqs []*coltracepb.ExportTraceServiceRequest
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("X-Token") != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		req := &coltracepb.ExportTraceServiceRequest{}
+		if err := proto.Unmarshal(body, req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		reqs = append(reqs, req)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	defer srv.Close()
+
+	tp := initForTest(t, srv.URL, 1, map[string]string{"X-Token": "secret"})
+
+	ctx, parent := StartKind(withTraceparent(context.Background(), remoteTraceparent), trace.SpanKindServer, "GET")
+	parent.SetName("GET /api/status")
+	_, child := Start(ctx, "storage.GetLatest", attribute.String("db.system", "sqlite"))
+	RecordError(child, context.DeadlineExceeded)
+	child.End()
+	parent.End()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := tp.Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var spans []*tracepb.Span
+	for _, req := range reqs {
+		for _, rs := range req.ResourceSpans {
+			if !hasAttribute(rs.Resource.Attributes, "service.name", "test-svc") {
+				t.Fatalf("unexpected resource attributes: %v", rs.Resource.Attributes)
+			}
+			for _, ss := range rs.ScopeSpans {
+				spans = append(spans, ss.Spans...)
+			}
+		}
+	}
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	c, p := spans[0], spans[1]
+	remote := trace.SpanContextFromContext(withTraceparent(context.Background(), remoteTraceparent))
+	if trace.TraceID(p.TraceId) != remote.TraceID() || trace.TraceID(c.TraceId) != remote.TraceID() {
+		t.Fatalf("span 应沿用上游 trace_id")
+	}
+	if trace.SpanID(p.ParentSpanId) != remote.SpanID() || trace.SpanID(c.ParentSpanId) != trace.SpanID(p.SpanId) {
+		t.Fatalf("父子关系错误")
+	}
+	if p.Name != "GET /api/status" || p.Kind != tracepb.Span_SPAN_KIND_SERVER {
+		t.Fatalf("unexpected parent span: name=%s kind=%v", p.Name, p.Kind)
+	}
+	if c.Status.GetCode() != tracepb.Status_STATUS_CODE_ERROR || c.Status.GetMessage() == "" {
+		t.Fatalf("child span 应标记为错误: %v", c.Status)
+	}
+	if !hasAttribute(c.Attributes, "db.system", "sqlite") {
+		t.Fatalf("unexpected child attributes: %v", c.Attributes)
+	}
+}
+
+func TestSamplingFollowsParent(t *testing.T) {
+	initForTest(t, "http://127.0.0.1:0", 0, nil)
+
+	ctx, root := Start(context.Background(), "root")
+	if root.IsRecording() || root.SpanContext().IsSampled() {
+		t.Fatalf("sample_ratio=0 时根 span 不应被采样")
+	}
+	if _, child := Start(ctx, "child"); child.IsRecording() {
+		t.Fatalf("未采样链路的子 span 不应重新采样")
+	}
+
+	// 上游已采样时沿用上游决策
+	_, span := StartKind(withTraceparent(context.Background(), remoteTraceparent), trace.SpanKindServer, "server")
+	if !span.IsRecording() || !span.SpanContext().IsSampled() {
+		t.Fatalf("上游 sampled=1 时应记录 span")
+	}
+}
+
+func TestRecordErrorIgnoresNil(t *testing.T) {
+	initForTest(t, "http://127.0.0.1:0", 1, nil)
+
+	_, span := Start(context.Background(), "op")
+	defer span.End()
+	RecordError(span, nil)
+	if ro := span.(sdktrace.ReadOnlySpan); ro.Status().Code != codes.Unset || len(ro.Events()) != 0 {
+		t.Fatalf("nil 错误不应改变 span 状态: %+v", ro.Status())
+	}
+	RecordError(span, errors.New("boom"))
+	if ro := span.(sdktrace.ReadOnlySpan); ro.Status().Description != "boom" || len(ro.Events()) != 1 {
+		t.Fatalf("unexpected span status: %+v events=%d", ro.Status(), len(ro.Events()))
+	}
+}
+
+func hasAttribute(attrs []*commonpb.KeyValue, key, value string) bool {
+	for _, kv := range attrs {
+		if kv.Key == key && kv.Value.GetStringValue() == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package transparency
+
+import (
+	"bytes"
+	"crypto/sha256"
+)
+
+// Merkle 树构造与 RFC 6962（Certificate Transparency）一致：
+//   - 空树：SHA-256("")
+//   - 叶子：SHA-256(0x00 || data)
+//   - 内部节点：SHA-256(0x01 || left || right)，左子树大小为小于 n 的最大 2 的幂
+
+// LeafHash 计算叶子哈希
+func LeafHash(data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x00})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// nodeHash 计算内部节点哈希
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x01})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// splitPoint 返回小于 n 的最大 2 的幂（n > 1）
+func splitPoint(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// MerkleRoot 计算叶子哈希序列的 Merkle 根
+func MerkleRoot(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		sum := sha256.Sum256(nil)
+		return sum[:]
+	case 1:
+		return leaves[0]
+	}
+	k := splitPoint(len(leaves))
+	return nodeHash(MerkleRoot(leaves[:k]), MerkleRoot(leaves[k:]))
+}
+
+// InclusionProof 生成第 index 个叶子的审计路径（自底向上）
+func InclusionProof(leaves [][]byte, index int) [][]byte {
+	if index < 0 || index >= len(leaves) || len(leaves) == 1 {
+		return nil
+	}
+	k := splitPoint(len(leaves))
+	if index < k {
+		return append(InclusionProof(leaves[:k], index), MerkleRoot(leaves[k:]))
+	}
+	return append(InclusionProof(leaves[k:], index-k), MerkleRoot(leaves[:k]))
+}
+
+// VerifyInclusion 校验叶子哈希在大小为 size 的树中位于 index，且审计路径可还原出 root
+func VerifyInclusion(leafHash []byte, index, size int, proof [][]byte, root []byte) bool {
+	if index < 0 || index >= size {
+		return false
+	}
+	computed, ok := rootFromProof(leafHash, index, size, proof)
+	return ok && bytes.Equal(computed, root)
+}
+
+// rootFromProof 按 InclusionProof 的构造顺序还原根哈希
+func rootFromProof(leafHash []byte, index, size int, proof [][]byte) ([]byte, bool) {
+	if size == 1 {
+		return leafHash, len(proof) == 0
+	}
+	if len(proof) == 0 {
+		return nil, false
+	}
+	k := splitPoint(size)
+	sibling := proof[len(proof)-1]
+	rest := proof[:len(proof)-1]
+	if index < k {
+		left, ok := rootFromProof(leafHash, index, k, rest)
+		return nodeHash(left, sibling), ok
+	}
+	right, ok := rootFromProof(leafHash, index-k, size-k, rest)
+	return nodeHash(sibling, right), ok
+}
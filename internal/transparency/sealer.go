@@ -0,0 +1,132 @@
+package transparency
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"sync"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/logger"
+	"monitor/internal/storage"
+)
+
+const (
+	// sealInterval 检查待封存小时的间隔
+	sealInterval = time.Minute
+
+	// maxHoursPerRun 单轮最多封存的小时数（避免补封存时长时间占用数据库）
+	maxHoursPerRun = 48
+)
+
+// Sealer 小时根封存任务
+// 每个整点小时结束并经过 seal_delay 后，读取该小时的全部探测记录计算 Merkle 根并签名保存
+type Sealer struct {
+	store    storage.Storage
+	key      ed25519.PrivateKey
+	delay    time.Duration
+	backfill int
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewSealer 创建封存任务（配置已通过 Normalize 校验）
+func NewSealer(store storage.Storage, cfg config.TransparencyConfig) (*Sealer, error) {
+	key, err := cfg.SigningKey()
+	if err != nil {
+		return nil, err
+	}
+	return &Sealer{
+		store:    store,
+		key:      key,
+		delay:    cfg.SealDelayDuration,
+		backfill: cfg.BackfillHours,
+		stopCh:   make(chan struct{}),
+	}, nil
+}
+
+// KeyID 签名公钥标识
+func (s *Sealer) KeyID() string {
+	return KeyID(s.key.Public().(ed25519.PublicKey))
+}
+
+// Start 启动封存任务（阻塞，应在 goroutine 中调用）
+func (s *Sealer) Start(ctx context.Context) {
+	ticker := time.NewTicker(sealInterval)
+	defer ticker.Stop()
+
+	s.run(ctx, time.Now())
+	for {
+		select {
+		case now := <-ticker.C:
+			s.run(ctx, now)
+		case <-ctx.Done():
+			return
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// Stop 停止封存任务（幂等，可重复调用）
+func (s *Sealer) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// run 执行一轮封存，失败只记录告警，下一轮重试
+func (s *Sealer) run(ctx context.Context, now time.Time) {
+	sealed, err := s.SealPending(ctx, now)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Warn("transparency", "封存小时根失败", "error", err)
+		}
+		return
+	}
+	if sealed > 0 {
+		logger.Info("transparency", "小时根封存完成", "hours", sealed)
+	}
+}
+
+// SealPending 封存所有已结束（含 seal_delay）但尚未封存的小时，返回封存数量
+// 从最近一个已封存小时的下一小时开始；首次运行时从 backfill_hours 之前开始
+func (s *Sealer) SealPending(ctx context.Context, now time.Time) (int, error) {
+	ts, ok := s.store.WithContext(ctx).(storage.TransparencyStorage)
+	if !ok {
+		return 0, fmt.Errorf("当前存储后端不支持透明度封存")
+	}
+
+	latest, err := ts.GetLatestTransparencyRoot()
+	if err != nil {
+		return 0, err
+	}
+
+	currentHour := now.Unix() / HourSeconds * HourSeconds
+	next := currentHour - int64(s.backfill)*HourSeconds
+	prevRoot := ""
+	if latest != nil {
+		next = latest.Hour + HourSeconds
+		prevRoot = latest.Root
+	}
+
+	sealed := 0
+	for ; sealed < maxHoursPerRun; sealed++ {
+		if now.Unix() < next+HourSeconds+int64(s.delay.Seconds()) {
+			break
+		}
+		records, err := ts.GetRecordsInRange(next, next+HourSeconds)
+		if err != nil {
+			return sealed, err
+		}
+		root := Seal(s.key, next, records, prevRoot, now.Unix())
+		if err := ts.SaveTransparencyRoot(root); err != nil {
+			return sealed, err
+		}
+		prevRoot = root.Root
+		next += HourSeconds
+	}
+	return sealed, nil
+}
@@ -0,0 +1,88 @@
+// Package transparency 提供探测数据的可公开验证能力
+//
+// 每个整点小时的探测记录（按 id 升序）构建为一棵 Merkle 树，根哈希与上一小时的根串成哈希链，
+// 再用 Ed25519 私钥签名。第三方拿到公钥后，可以通过 /api/transparency/proofs 下载记录与签名，
+// 独立重算叶子哈希、Merkle 根并校验签名，从而确认历史数据在封存后未被修改、删除或插入。
+package transparency
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"monitor/internal/storage"
+)
+
+// MessageVersion 签名消息格式版本
+const MessageVersion = "relay-pulse-transparency/v1"
+
+// HourSeconds 封存粒度（秒）
+const HourSeconds = 3600
+
+// LeafData 探测记录的规范化编码（叶子原文）
+// 各字段按固定顺序以 "\n" 连接：id, provider, service, channel, model, status, sub_status, http_code, latency, timestamp
+func LeafData(r *storage.ProbeRecord) []byte {
+	fields := []string{
+		strconv.FormatInt(r.ID, 10),
+		r.Provider,
+		r.Service,
+		r.Channel,
+		r.Model,
+		strconv.Itoa(r.Status),
+		string(r.SubStatus),
+		strconv.Itoa(r.HttpCode),
+		strconv.Itoa(r.Latency),
+		strconv.FormatInt(r.Timestamp, 10),
+	}
+	return []byte(strings.Join(fields, "\n"))
+}
+
+// LeafHashes 计算一组记录的叶子哈希（保持输入顺序）
+func LeafHashes(records []*storage.ProbeRecord) [][]byte {
+	leaves := make([][]byte, len(records))
+	for i, r := range records {
+		leaves[i] = LeafHash(LeafData(r))
+	}
+	return leaves
+}
+
+// SignedMessage 小时根的签名消息
+// 格式："relay-pulse-transparency/v1\nhour=<unix>\ncount=<n>\nroot=<hex>\nprev=<hex>"
+func SignedMessage(root *storage.TransparencyRoot) []byte {
+	return fmt.Appendf(nil, "%s\nhour=%d\ncount=%d\nroot=%s\nprev=%s",
+		MessageVersion, root.Hour, root.LeafCount, root.Root, root.PrevRoot)
+}
+
+// KeyID 公钥标识（公钥 SHA-256 的前 16 位十六进制）
+func KeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// Seal 为一个小时的记录构建并签名小时根
+// records 必须是 [hour, hour+3600) 内的全部记录且按 id 升序；prevRoot 为上一个已封存小时的根
+func Seal(key ed25519.PrivateKey, hour int64, records []*storage.ProbeRecord, prevRoot string, now int64) *storage.TransparencyRoot {
+	root := &storage.TransparencyRoot{
+		Hour:      hour,
+		LeafCount: len(records),
+		Root:      hex.EncodeToString(MerkleRoot(LeafHashes(records))),
+		PrevRoot:  prevRoot,
+		KeyID:     KeyID(key.Public().(ed25519.PublicKey)),
+		CreatedAt: now,
+	}
+	root.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, SignedMessage(root)))
+	return root
+}
+
+// VerifyRoot 校验小时根的签名
+func VerifyRoot(pub ed25519.PublicKey, root *storage.TransparencyRoot) bool {
+	sig, err := base64.StdEncoding.DecodeString(root.Signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(pub, SignedMessage(root), sig)
+}
@@ -0,0 +1,143 @@
+package transparency
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"monitor/internal/config"
+	"monitor/internal/storage"
+)
+
+func TestInclusionProofRoundTrip(t *testing.T) {
+	for size := 1; size <= 17; size++ {
+		leaves := make([][]byte, size)
+		for i := range leaves {
+			leaves[i] = LeafHash([]byte(fmt.Sprintf("leaf-%d", i)))
+		}
+		root := MerkleRoot(leaves)
+		for i := range leaves {
+			proof := InclusionProof(leaves, i)
+			if !VerifyInclusion(leaves[i], i, size, proof, root) {
+				t.Fatalf("size=%d index=%d: proof did not verify", size, i)
+			}
+			if size > 1 && VerifyInclusion(leaves[(i+1)%size], i, size, proof, root) {
+				t.Fatalf("size=%d index=%d: proof verified for wrong leaf", size, i)
+			}
+		}
+	}
+}
+
+func TestMerkleRootKnownValues(t *testing.T) {
+	// 空树为 SHA-256("")
+	if got := hex.EncodeToString(MerkleRoot(nil)); got != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Fatalf("unexpected empty root: %s", got)
+	}
+	// RFC 6962 叶子哈希：SHA-256(0x00 || "")
+	if got := hex.EncodeToString(LeafHash(nil)); got != "6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d" {
+		t.Fatalf("unexpected leaf hash: %s", got)
+	}
+}
+
+func TestSealAndVerify(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(nil)
+	records := []*storage.ProbeRecord{
+		{ID: 1, Provider: "relay", Service: "cc", Channel: "vip", Status: 1, Latency: 120, Timestamp: 3600},
+		{ID: 2, Provider: "relay", Service: "cc", Channel: "vip", Status: 0, SubStatus: storage.SubStatusNetworkError, Timestamp: 3660},
+	}
+	root := Seal(key, 3600, records, "abc", 7500)
+	pub := key.Public().(ed25519.PublicKey)
+
+	if root.LeafCount != 2 || root.KeyID != KeyID(pub) {
+		t.Fatalf("unexpected root: %+v", root)
+	}
+	if !VerifyRoot(pub, root) {
+		t.Fatalf("signature did not verify")
+	}
+
+	tampered := *root
+	tampered.PrevRoot = "def"
+	if VerifyRoot(pub, &tampered) {
+		t.Fatalf("signature verified after tampering prev_root")
+	}
+
+	records[1].Status = 1
+	if Seal(key, 3600, records, "abc", 7500).Root == root.Root {
+		t.Fatalf("root unchanged after tampering a record")
+	}
+}
+
+func TestSealerSealPending(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "monitor.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer store.Close()
+	if err := store.Init(); err != nil {
+		t.Fatalf("init sqlite: %v", err)
+	}
+
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	for i, offset := range []time.Duration{5 * time.Minute, 50 * time.Minute, 70 * time.Minute, 130 * time.Minute} {
+		if err := store.SaveRecord(&storage.ProbeRecord{
+			Provider: "relay", Service: "cc", Channel: "vip",
+			Status: 1, Latency: 100 + i, Timestamp: base.Add(offset).Unix(),
+		}); err != nil {
+			t.Fatalf("save record: %v", err)
+		}
+	}
+
+	_, key, _ := ed25519.GenerateKey(nil)
+	sealer, err := NewSealer(store, config.TransparencyConfig{
+		Enabled:           true,
+		PrivateKey:        base64.StdEncoding.EncodeToString(key.Seed()),
+		SealDelayDuration: 5 * time.Minute,
+		BackfillHours:     3,
+	})
+	if err != nil {
+		t.Fatalf("new sealer: %v", err)
+	}
+
+	// 13:03 时 12:00 小时尚未过 seal_delay，只封存 10:00 与 11:00
+	now := base.Add(3*time.Hour + 3*time.Minute)
+	sealed, err := sealer.SealPending(context.Background(), now)
+	if err != nil || sealed != 2 {
+		t.Fatalf("expected 2 sealed hours, got %d (err=%v)", sealed, err)
+	}
+
+	// 再次执行：12:00 满足延迟后才封存，且与上一小时串成哈希链
+	sealed, err = sealer.SealPending(context.Background(), now.Add(10*time.Minute))
+	if err != nil || sealed != 1 {
+		t.Fatalf("expected 1 sealed hour, got %d (err=%v)", sealed, err)
+	}
+
+	roots, err := store.GetTransparencyRoots(base.Unix(), base.Add(2*time.Hour).Unix())
+	if err != nil || len(roots) != 3 {
+		t.Fatalf("expected 3 roots, got %d (err=%v)", len(roots), err)
+	}
+	pub := key.Public().(ed25519.PublicKey)
+	wantCounts := []int{2, 1, 1}
+	for i, root := range roots {
+		if root.LeafCount != wantCounts[i] {
+			t.Fatalf("hour %d: expected %d leaves, got %d", i, wantCounts[i], root.LeafCount)
+		}
+		if !VerifyRoot(pub, root) {
+			t.Fatalf("hour %d: signature did not verify", i)
+		}
+		if i > 0 && root.PrevRoot != roots[i-1].Root {
+			t.Fatalf("hour %d: hash chain broken", i)
+		}
+		records, err := store.GetRecordsInRange(root.Hour, root.Hour+HourSeconds)
+		if err != nil {
+			t.Fatalf("load records: %v", err)
+		}
+		if got := hex.EncodeToString(MerkleRoot(LeafHashes(records))); got != root.Root {
+			t.Fatalf("hour %d: recomputed root mismatch", i)
+		}
+	}
+}
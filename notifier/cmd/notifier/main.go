@@ -13,6 +13,8 @@ import (
 
 	"notifier/internal/api"
 	"notifier/internal/config"
+	"notifier/internal/digest"
+	"notifier/internal/i18n"
 	"notifier/internal/notifier"
 	"notifier/internal/poller"
 	"notifier/internal/qq"
@@ -62,14 +64,21 @@ func main() {
 		"telegram_enabled", cfg.HasTelegramToken(),
 		"qq_enabled", cfg.HasQQ(),
 		"screenshot_enabled", cfg.HasScreenshot(),
+		"default_language", cfg.I18n.DefaultLanguage,
+		"digest_enabled", cfg.HasDigest(),
+		"robots", len(cfg.Robots),
 	)
 
+	// 默认语言（已在配置校验中确认可解析）
+	defaultLang, _ := i18n.Parse(cfg.I18n.DefaultLanguage)
+	i18n.SetDefault(defaultLang)
+
 	// 创建上下文，支持优雅关闭
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// 初始化存储层
-	store, err := storage.NewSQLiteStorage(cfg.Database.DSN)
+	store, err := storage.New(cfg.Database.Driver, cfg.Database.DSN)
 	if err != nil {
 		slog.Error("初始化存储失败", "error", err)
 		os.Exit(1)
@@ -80,7 +89,21 @@ func main() {
 		slog.Error("初始化数据库表失败", "error", err)
 		os.Exit(1)
 	}
-	slog.Info("存储层初始化成功")
+	slog.Info("存储层初始化成功", "driver", cfg.Database.Driver, "instance_id", cfg.Database.InstanceID)
+
+	// 同步群机器人（企业微信 / 钉钉）及其订阅
+	if cfg.HasRobots() {
+		if err := notifier.SyncRobots(ctx, store, cfg.Robots); err != nil {
+			slog.Error("同步群机器人失败", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// 启动审计日志清理（如果启用）
+	if cfg.Audit.Enabled {
+		go runAuditCleanup(ctx, store, cfg.Audit.RetentionDays)
+		slog.Info("审计日志已启用", "retention_days", cfg.Audit.RetentionDays)
+	}
 
 	// 初始化截图服务（如果启用）
 	var screenshotSvc *screenshot.Service
@@ -90,17 +113,28 @@ func main() {
 			cfg.Screenshot.Timeout,
 			cfg.Screenshot.MaxConcurrent,
 		)
+		screenshotSvc.SetTemplates(cfg.Screenshot.Templates, cfg.Screenshot.DefaultTemplate)
 		defer screenshotSvc.Close()
 		slog.Info("截图服务已启用",
 			"base_url", cfg.Screenshot.BaseURL,
 			"timeout", cfg.Screenshot.Timeout,
 			"max_concurrent", cfg.Screenshot.MaxConcurrent,
+			"templates", len(cfg.Screenshot.Templates),
+			"default_template", cfg.Screenshot.DefaultTemplate,
 		)
 	}
 
 	// 初始化 HTTP API 服务器
 	apiServer := api.NewServer(cfg, store)
 
+	// 当启用任一平台（含群机器人）时创建通知发送器（Bot 的 /broadcast 与管理 API 共用）
+	var sender *notifier.Sender
+	enableSender := cfg.HasTelegramToken() || cfg.HasQQ() || cfg.HasRobots()
+	if enableSender {
+		sender = notifier.NewSender(cfg, store)
+		apiServer.SetBroadcaster(sender)
+	}
+
 	// 启动 HTTP API 服务器
 	go func() {
 		if err := apiServer.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -111,24 +145,42 @@ func main() {
 
 	// 变量声明（用于优雅关闭）
 	var bot *telegram.Bot
-	var sender *notifier.Sender
 	var eventPoller *poller.Poller
+	var digestScheduler *digest.Scheduler
+	var qqWS *qq.WSClient
 
 	// 初始化 QQ Bot（如果启用）
-	// QQ Bot 通过 HTTP 回调工作，不需要主动运行 goroutine
+	// QQ Bot 通过 HTTP 回调接收上报；配置了 onebot_ws_url 时同时主动连接 WebSocket 接收
 	if cfg.HasQQ() {
 		qqClient := qq.NewClient(cfg.QQ.OneBotHTTPURL, cfg.QQ.AccessToken)
 		qqBot := qq.NewBot(qqClient, store, qq.Options{
 			MaxSubscriptionsPerUser: cfg.Limits.MaxSubscriptionsPerUser,
+			ChatTokenTTL:            cfg.Limits.BindTokenTTL,
 			EventsURL:               cfg.RelayPulse.EventsURL,
 			CallbackSecret:          cfg.QQ.CallbackSecret,
 			ScreenshotService:       screenshotSvc,
 			AdminWhitelist:          cfg.QQ.AdminWhitelist,
+			AuditEnabled:            cfg.Audit.Enabled,
+			DigestEnabled:           cfg.HasDigest(),
+			DigestDefaultHour:       cfg.Digest.DefaultHour,
 		})
+		if sender != nil {
+			qqBot.SetBroadcaster(sender)
+		}
 
 		// 注册 QQ 回调路由
 		apiServer.RegisterQQCallback(cfg.QQ.CallbackPath, qqBot)
-		slog.Info("QQ Bot 初始化成功", "callback_path", cfg.QQ.CallbackPath)
+
+		// WebSocket 上报（无需对外暴露回调端口）
+		if cfg.QQ.OneBotWSURL != "" {
+			qqWS = qq.NewWSClient(cfg.QQ.OneBotWSURL, cfg.QQ.AccessToken, qqBot.HandleEvent)
+			go func() {
+				if err := qqWS.Start(ctx); err != nil && ctx.Err() == nil {
+					slog.Error("OneBot WebSocket 错误", "error", err)
+				}
+			}()
+		}
+		slog.Info("QQ Bot 初始化成功", "callback_path", cfg.QQ.CallbackPath, "ws_enabled", qqWS != nil)
 	}
 
 	// 仅在配置了 Telegram Token 时启动 Telegram Bot
@@ -137,6 +189,9 @@ func main() {
 		if screenshotSvc != nil {
 			bot.SetScreenshotService(screenshotSvc)
 		}
+		if sender != nil {
+			bot.SetBroadcaster(sender)
+		}
 		go func() {
 			if err := bot.Start(ctx); err != nil && ctx.Err() == nil {
 				slog.Error("Telegram Bot 错误", "error", err)
@@ -145,10 +200,8 @@ func main() {
 		}()
 	}
 
-	// 当启用任一平台时，启动通知发送器和事件轮询器
-	if cfg.HasTelegramToken() || cfg.HasQQ() {
-		// 初始化通知发送器（多平台）
-		sender = notifier.NewSender(cfg, store)
+	// 当启用任一平台（含群机器人）时，启动通知发送器和事件轮询器
+	if enableSender {
 		go func() {
 			if err := sender.Start(ctx); err != nil && ctx.Err() == nil {
 				slog.Error("通知发送器错误", "error", err)
@@ -158,12 +211,27 @@ func main() {
 
 		// 初始化并启动事件轮询器
 		eventPoller = poller.NewPoller(cfg, store, sender.HandleEvent)
+		eventPoller.SetAlertHandler(sender.NotifyAdmins)
 		go func() {
 			if err := eventPoller.Start(ctx); err != nil && ctx.Err() == nil {
 				slog.Error("事件轮询器错误", "error", err)
 				cancel()
 			}
 		}()
+
+		// 定期摘要（如果启用）
+		if cfg.HasDigest() {
+			digestScheduler, err = digest.NewScheduler(cfg, store, screenshotSvc)
+			if err != nil {
+				slog.Error("初始化摘要调度器失败", "error", err)
+				os.Exit(1)
+			}
+			go func() {
+				if err := digestScheduler.Start(ctx); err != nil && ctx.Err() == nil {
+					slog.Error("摘要调度器错误", "error", err)
+				}
+			}()
+		}
 	} else {
 		slog.Warn("未配置任何通知平台（Telegram/QQ），Poller/Sender 功能已禁用",
 			"hint", "仅 API 服务器可用（bind-token 接口）")
@@ -186,12 +254,18 @@ func main() {
 	if eventPoller != nil {
 		eventPoller.Stop()
 	}
+	if digestScheduler != nil {
+		digestScheduler.Stop()
+	}
 	if sender != nil {
 		sender.Stop()
 	}
 	if bot != nil {
 		bot.Stop()
 	}
+	if qqWS != nil {
+		qqWS.Stop()
+	}
 
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer shutdownCancel()
@@ -202,3 +276,25 @@ func main() {
 
 	slog.Info("服务已关闭")
 }
+
+// runAuditCleanup 定期清理超过保留天数的审计日志
+func runAuditCleanup(ctx context.Context, store storage.Storage, retentionDays int) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		before := time.Now().AddDate(0, 0, -retentionDays)
+		deleted, err := store.CleanupOldAuditEntries(ctx, before)
+		if err != nil && ctx.Err() == nil {
+			slog.Warn("清理审计日志失败", "error", err)
+		} else if deleted > 0 {
+			slog.Info("审计日志清理完成", "deleted", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
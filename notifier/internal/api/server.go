@@ -3,11 +3,18 @@ package api
 import (
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"notifier/internal/config"
 	"notifier/internal/storage"
@@ -18,12 +25,18 @@ type QQCallbackHandler interface {
 	HandleCallback(w http.ResponseWriter, r *http.Request)
 }
 
+// Broadcaster 公告广播发起者（由通知发送器实现）
+type Broadcaster interface {
+	StartBroadcast(ctx context.Context, b *storage.Broadcast, onDone func(*storage.Broadcast)) error
+}
+
 // Server HTTP API 服务器
 type Server struct {
-	cfg     *config.Config
-	storage storage.Storage
-	server  *http.Server
-	mux     *http.ServeMux
+	cfg         *config.Config
+	storage     storage.Storage
+	broadcaster Broadcaster
+	server      *http.Server
+	mux         *http.ServeMux
 }
 
 // NewServer 创建 API 服务器
@@ -41,6 +54,18 @@ func NewServer(cfg *config.Config, store storage.Storage) *Server {
 	s.mux.HandleFunc("POST /api/bind-token", s.handleCreateBindToken)
 	s.mux.HandleFunc("GET /api/bind-token/{token}", s.handleGetBindToken)
 
+	// 订阅导入导出 API（需会话令牌，由 Bot /export 命令签发）
+	s.mux.HandleFunc("GET /api/subscriptions/export", s.handleExportSubscriptions)
+	s.mux.HandleFunc("POST /api/subscriptions/import", s.handleImportSubscriptions)
+
+	// 管理 API（需 Bearer Token）
+	s.mux.HandleFunc("GET /api/admin/audit", s.handleGetAudit)
+	s.mux.HandleFunc("GET /api/admin/dead-letters", s.handleGetDeadLetters)
+	s.mux.HandleFunc("POST /api/admin/dead-letters/{id}/requeue", s.handleRequeueDeadLetter)
+	s.mux.HandleFunc("POST /api/admin/broadcasts", s.handleCreateBroadcast)
+	s.mux.HandleFunc("GET /api/admin/broadcasts", s.handleGetBroadcasts)
+	s.mux.HandleFunc("GET /api/admin/broadcasts/{id}", s.handleGetBroadcast)
+
 	s.server = &http.Server{
 		Addr:         cfg.API.Addr,
 		Handler:      corsMiddleware(loggingMiddleware(s.mux)),
@@ -52,6 +77,11 @@ func NewServer(cfg *config.Config, store storage.Storage) *Server {
 	return s
 }
 
+// SetBroadcaster 设置公告广播发起者（未设置时 POST /api/admin/broadcasts 返回 503）
+func (s *Server) SetBroadcaster(b Broadcaster) {
+	s.broadcaster = b
+}
+
 // RegisterQQCallback 注册 QQ Bot 回调路由
 func (s *Server) RegisterQQCallback(path string, handler QQCallbackHandler) {
 	s.mux.HandleFunc("POST "+path, handler.HandleCallback)
@@ -167,7 +197,8 @@ func (s *Server) handleGetBindToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if bindToken == nil {
+	// 会话令牌仅用于订阅导入导出
+	if bindToken == nil || bindToken.Platform != "" {
 		writeError(w, http.StatusNotFound, "token 不存在")
 		return
 	}
@@ -189,8 +220,429 @@ func (s *Server) handleGetBindToken(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(resp)
 }
 
+// ImportSubscriptionsResponse 订阅导入响应
+type ImportSubscriptionsResponse struct {
+	*storage.ImportResult
+	Total int `json:"total"` // 导入后的订阅总数
+}
+
+// handleExportSubscriptions 导出会话订阅
+// GET /api/subscriptions/export，Authorization: Bearer <会话令牌>
+func (s *Server) handleExportSubscriptions(w http.ResponseWriter, r *http.Request) {
+	chat, ok := s.authorizeChat(w, r)
+	if !ok {
+		return
+	}
+
+	export, err := storage.ExportSubscriptions(r.Context(), s.storage, chat.Platform, chat.ChatID)
+	if err != nil {
+		slog.Error("导出订阅失败", "platform", chat.Platform, "chat_id", chat.ChatID, "error", err)
+		writeError(w, http.StatusInternalServerError, "内部错误")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="relaypulse-subscriptions.json"`)
+	json.NewEncoder(w).Encode(export)
+}
+
+// handleImportSubscriptions 导入会话订阅
+// POST /api/subscriptions/import?mode=merge|replace，Authorization: Bearer <会话令牌>，请求体为导出数据
+func (s *Server) handleImportSubscriptions(w http.ResponseWriter, r *http.Request) {
+	chat, ok := s.authorizeChat(w, r)
+	if !ok {
+		return
+	}
+
+	replace := false
+	switch r.URL.Query().Get("mode") {
+	case "", "merge":
+	case "replace":
+		replace = true
+	default:
+		writeError(w, http.StatusBadRequest, "mode 无效（可选 merge/replace）")
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportBodyBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "无效的请求体")
+		return
+	}
+	export, err := storage.ParseSubscriptionExport(data)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	result, err := storage.ImportSubscriptions(ctx, s.storage, chat.Platform, chat.ChatID, export,
+		s.cfg.Limits.MaxSubscriptionsPerUser, replace, 0)
+	if err != nil {
+		slog.Error("导入订阅失败", "platform", chat.Platform, "chat_id", chat.ChatID, "error", err)
+		writeError(w, http.StatusInternalServerError, "内部错误")
+		return
+	}
+
+	total, err := s.storage.CountSubscriptions(ctx, chat.Platform, chat.ChatID)
+	if err != nil {
+		slog.Error("统计订阅失败", "error", err)
+		writeError(w, http.StatusInternalServerError, "内部错误")
+		return
+	}
+
+	slog.Info("订阅已导入",
+		"platform", chat.Platform,
+		"chat_id", chat.ChatID,
+		"imported", result.Imported,
+		"skipped", result.Skipped,
+		"replace", replace,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ImportSubscriptionsResponse{ImportResult: result, Total: total})
+}
+
+// authorizeChat 校验请求携带的会话令牌，失败时写入错误响应
+func (s *Server) authorizeChat(w http.ResponseWriter, r *http.Request) (*storage.BindToken, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		writeError(w, http.StatusUnauthorized, "缺少 Authorization（请在 Bot 中发送 /export 获取令牌）")
+		return nil, false
+	}
+
+	chat, err := storage.ResolveChatToken(r.Context(), s.storage, token)
+	if errors.Is(err, storage.ErrInvalidChatToken) {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return nil, false
+	}
+	if err != nil {
+		slog.Error("查询会话令牌失败", "error", err)
+		writeError(w, http.StatusInternalServerError, "内部错误")
+		return nil, false
+	}
+	return chat, true
+}
+
+// AuditItem 审计日志条目
+type AuditItem struct {
+	ID        int64  `json:"id"`
+	Timestamp int64  `json:"timestamp"`
+	Platform  string `json:"platform,omitempty"`
+	Actor     string `json:"actor"`
+	Action    string `json:"action"`
+	Target    string `json:"target,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+	Result    string `json:"result"`
+}
+
+// GetAuditResponse 审计日志查询响应
+type GetAuditResponse struct {
+	Entries      []AuditItem `json:"entries"`
+	NextBeforeID int64       `json:"next_before_id"` // 下一页游标，无更多数据时为 0
+}
+
+// handleGetAudit 查询审计日志
+// GET /api/admin/audit?actor=qq:123&action=qq.command.add&since=0&before_id=0&limit=100
+func (s *Server) handleGetAudit(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeAdmin(w, r) {
+		return
+	}
+
+	q := r.URL.Query()
+	limit := adminPageLimit(q.Get("limit"))
+	filter := storage.AuditFilter{
+		Actor:  q.Get("actor"),
+		Action: q.Get("action"),
+		Limit:  limit + 1,
+	}
+	filter.Since, _ = strconv.ParseInt(q.Get("since"), 10, 64)
+	filter.BeforeID, _ = strconv.ParseInt(q.Get("before_id"), 10, 64)
+
+	entries, err := s.storage.GetAuditEntries(r.Context(), filter)
+	if err != nil {
+		slog.Error("查询审计日志失败", "error", err)
+		writeError(w, http.StatusInternalServerError, "内部错误")
+		return
+	}
+
+	resp := GetAuditResponse{Entries: make([]AuditItem, 0, len(entries))}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		resp.NextBeforeID = entries[limit-1].ID
+	}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, AuditItem{
+			ID:        e.ID,
+			Timestamp: e.Timestamp,
+			Platform:  e.Platform,
+			Actor:     e.Actor,
+			Action:    e.Action,
+			Target:    e.Target,
+			Detail:    e.Detail,
+			Result:    e.Result,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DeadLetterItem 死信投递条目
+type DeadLetterItem struct {
+	ID           int64  `json:"id"`
+	EventID      int64  `json:"event_id"`
+	Platform     string `json:"platform"`
+	ChatID       int64  `json:"chat_id"`
+	ErrorMessage string `json:"error_message,omitempty"`
+	RetryCount   int    `json:"retry_count"`
+	CreatedAt    int64  `json:"created_at"`
+	UpdatedAt    int64  `json:"updated_at"` // 进入死信队列的时间
+}
+
+// GetDeadLettersResponse 死信投递查询响应
+type GetDeadLettersResponse struct {
+	Deliveries   []DeadLetterItem `json:"deliveries"`
+	NextBeforeID int64            `json:"next_before_id"` // 下一页游标，无更多数据时为 0
+}
+
+// handleGetDeadLetters 查询重试耗尽的死信投递
+// GET /api/admin/dead-letters?platform=telegram&before_id=0&limit=100
+func (s *Server) handleGetDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeAdmin(w, r) {
+		return
+	}
+
+	q := r.URL.Query()
+	limit := adminPageLimit(q.Get("limit"))
+	filter := storage.DeliveryFilter{
+		Platform: q.Get("platform"),
+		Limit:    limit + 1,
+	}
+	filter.BeforeID, _ = strconv.ParseInt(q.Get("before_id"), 10, 64)
+
+	deliveries, err := s.storage.GetDeadDeliveries(r.Context(), filter)
+	if err != nil {
+		slog.Error("查询死信投递失败", "error", err)
+		writeError(w, http.StatusInternalServerError, "内部错误")
+		return
+	}
+
+	resp := GetDeadLettersResponse{Deliveries: make([]DeadLetterItem, 0, len(deliveries))}
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+		resp.NextBeforeID = deliveries[limit-1].ID
+	}
+	for _, d := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, DeadLetterItem{
+			ID:           d.ID,
+			EventID:      d.EventID,
+			Platform:     d.Platform,
+			ChatID:       d.ChatID,
+			ErrorMessage: d.ErrorMessage,
+			RetryCount:   d.RetryCount,
+			CreatedAt:    d.CreatedAt,
+			UpdatedAt:    d.UpdatedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handleRequeueDeadLetter 将死信投递重新放回待发送队列（重置重试次数，下一轮重试扫描时发送）
+// POST /api/admin/dead-letters/{id}/requeue
+func (s *Server) handleRequeueDeadLetter(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeAdmin(w, r) {
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, "无效的投递 ID")
+		return
+	}
+
+	ok, err := s.storage.RequeueDeadDelivery(r.Context(), id)
+	if err != nil {
+		slog.Error("重新入队死信投递失败", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "内部错误")
+		return
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, "死信投递不存在")
+		return
+	}
+
+	slog.Info("死信投递已重新入队", "id", id)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"id": id, "status": storage.DeliveryStatusPending})
+}
+
+// CreateBroadcastRequest 发起公告广播请求
+type CreateBroadcastRequest struct {
+	Message  string `json:"message"`
+	Provider string `json:"provider"` // 为空时发送给所有活跃会话
+}
+
+// BroadcastItem 公告广播条目
+type BroadcastItem struct {
+	ID         int64  `json:"id"`
+	Actor      string `json:"actor"`
+	Provider   string `json:"provider,omitempty"`
+	Message    string `json:"message"`
+	Status     string `json:"status"`
+	Total      int    `json:"total"`
+	Sent       int    `json:"sent"`
+	Failed     int    `json:"failed"`
+	OptedOut   int    `json:"opted_out"`
+	CreatedAt  int64  `json:"created_at"`
+	FinishedAt int64  `json:"finished_at,omitempty"`
+}
+
+// GetBroadcastsResponse 公告广播列表响应
+type GetBroadcastsResponse struct {
+	Broadcasts []BroadcastItem `json:"broadcasts"`
+}
+
+// handleCreateBroadcast 发起公告广播（后台按平台限流发送，立即返回目标会话统计）
+// POST /api/admin/broadcasts {"message": "...", "provider": ""}
+func (s *Server) handleCreateBroadcast(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeAdmin(w, r) {
+		return
+	}
+	if s.broadcaster == nil {
+		writeError(w, http.StatusServiceUnavailable, "公告广播不可用")
+		return
+	}
+
+	var req CreateBroadcastRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "无效的请求体")
+		return
+	}
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Message == "" {
+		writeError(w, http.StatusBadRequest, "公告内容不能为空")
+		return
+	}
+	if utf8.RuneCountInString(req.Message) > storage.MaxBroadcastLength {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("公告内容超过 %d 字", storage.MaxBroadcastLength))
+		return
+	}
+
+	b := &storage.Broadcast{
+		Actor:    "admin-api",
+		Provider: strings.TrimSpace(req.Provider),
+		Message:  req.Message,
+	}
+	if err := s.broadcaster.StartBroadcast(r.Context(), b, nil); err != nil {
+		slog.Error("发起公告广播失败", "error", err)
+		writeError(w, http.StatusInternalServerError, "内部错误")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(toBroadcastItem(b))
+}
+
+// handleGetBroadcasts 查询最近的公告广播
+// GET /api/admin/broadcasts?limit=100
+func (s *Server) handleGetBroadcasts(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeAdmin(w, r) {
+		return
+	}
+
+	broadcasts, err := s.storage.GetBroadcasts(r.Context(), adminPageLimit(r.URL.Query().Get("limit")))
+	if err != nil {
+		slog.Error("查询公告广播失败", "error", err)
+		writeError(w, http.StatusInternalServerError, "内部错误")
+		return
+	}
+
+	resp := GetBroadcastsResponse{Broadcasts: make([]BroadcastItem, 0, len(broadcasts))}
+	for _, b := range broadcasts {
+		resp.Broadcasts = append(resp.Broadcasts, toBroadcastItem(b))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handleGetBroadcast 查询单个公告广播的发送进度与统计
+// GET /api/admin/broadcasts/{id}
+func (s *Server) handleGetBroadcast(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeAdmin(w, r) {
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, "无效的公告 ID")
+		return
+	}
+
+	b, err := s.storage.GetBroadcast(r.Context(), id)
+	if err != nil {
+		slog.Error("查询公告广播失败", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "内部错误")
+		return
+	}
+	if b == nil {
+		writeError(w, http.StatusNotFound, "公告不存在")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toBroadcastItem(b))
+}
+
 // 辅助函数
 
+// toBroadcastItem 转换为公告广播响应条目
+func toBroadcastItem(b *storage.Broadcast) BroadcastItem {
+	return BroadcastItem{
+		ID:         b.ID,
+		Actor:      b.Actor,
+		Provider:   b.Provider,
+		Message:    b.Message,
+		Status:     b.Status,
+		Total:      b.Total,
+		Sent:       b.Sent,
+		Failed:     b.Failed,
+		OptedOut:   b.OptedOut,
+		CreatedAt:  b.CreatedAt,
+		FinishedAt: b.FinishedAt,
+	}
+}
+
+// authorizeAdmin 校验管理 API 的 Bearer Token，失败时写入错误响应并返回 false
+func (s *Server) authorizeAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if s.cfg.Audit.APIToken == "" {
+		writeError(w, http.StatusServiceUnavailable, "admin API 未配置，请设置 ADMIN_API_TOKEN 环境变量")
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.Audit.APIToken)) != 1 {
+		writeError(w, http.StatusUnauthorized, "无效的 Authorization")
+		return false
+	}
+	return true
+}
+
+// adminPageLimit 解析管理 API 的分页大小（默认 100，最大 500）
+func adminPageLimit(raw string) int {
+	limit, _ := strconv.Atoi(raw)
+	if limit <= 0 {
+		return 100
+	}
+	return min(limit, 500)
+}
+
+// maxImportBodyBytes 订阅导入请求体上限
+const maxImportBodyBytes = 1 << 20
+
 func writeError(w http.ResponseWriter, code int, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
@@ -223,7 +675,7 @@ func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusNoContent)
@@ -2,10 +2,15 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"notifier/internal/i18n"
+	"notifier/internal/screenshot"
+	"notifier/internal/storage"
 )
 
 // Config 通知服务配置
@@ -17,6 +22,11 @@ type Config struct {
 	API        APIConfig        `yaml:"api"`
 	Limits     LimitsConfig     `yaml:"limits"`
 	Screenshot ScreenshotConfig `yaml:"screenshot"`
+	Audit      AuditConfig      `yaml:"audit"`
+	I18n       I18nConfig       `yaml:"i18n"`
+	Digest     DigestConfig     `yaml:"digest"`
+	Robots     []RobotConfig    `yaml:"robots"`
+	Alerts     AlertsConfig     `yaml:"alerts"`
 }
 
 // RelayPulseConfig relay-pulse 事件 API 配置
@@ -24,28 +34,39 @@ type RelayPulseConfig struct {
 	EventsURL    string        `yaml:"events_url"`
 	APIToken     string        `yaml:"api_token"`
 	PollInterval time.Duration `yaml:"poll_interval"`
+
+	// 追赶与退避：游标落后时按 batch_size 分页连续拉取，请求失败时按 poll_interval × 2^n 退避（带抖动）
+	BatchSize    int           `yaml:"batch_size"`    // 每页事件数，默认 100，最大 500（主服务会再按自身上限截断）
+	CatchUpPages int           `yaml:"catchup_pages"` // 每轮最多连续拉取的页数，默认 20
+	MaxBackoff   time.Duration `yaml:"max_backoff"`   // 最大退避间隔，默认 5m
 }
 
 // TelegramConfig Telegram Bot 配置
 type TelegramConfig struct {
-	BotToken    string `yaml:"bot_token"`
-	BotUsername string `yaml:"bot_username"`
+	BotToken    string  `yaml:"bot_token"`
+	BotUsername string  `yaml:"bot_username"`
+	AdminIDs    []int64 `yaml:"admin_ids"` // 运维管理员 Telegram 用户 ID（可执行 /broadcast）
 }
 
 // QQConfig QQ Bot 配置（OneBot v11 / NapCatQQ）
 type QQConfig struct {
 	Enabled        bool    `yaml:"enabled"`         // 是否启用 QQ 通知
 	OneBotHTTPURL  string  `yaml:"onebot_http_url"` // OneBot HTTP API 地址
+	OneBotWSURL    string  `yaml:"onebot_ws_url"`   // OneBot WebSocket 地址（可选，配置后主动连接接收上报）
 	AccessToken    string  `yaml:"access_token"`    // OneBot API Token（可选）
 	CallbackPath   string  `yaml:"callback_path"`   // 接收上报的路径，默认 /qq/callback
 	CallbackSecret string  `yaml:"callback_secret"` // Webhook 签名密钥（可选）
-	AdminWhitelist []int64 `yaml:"admin_whitelist"` // 管理员白名单 QQ 号（可越权执行管理命令）
+	AdminWhitelist []int64 `yaml:"admin_whitelist"` // 管理员白名单 QQ 号（可越权执行管理命令，并可执行 /broadcast）
 }
 
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
 	Driver string `yaml:"driver"` // sqlite 或 postgres
 	DSN    string `yaml:"dsn"`
+
+	// 多实例部署（仅 postgres）：各实例共享数据库，通过 poll_cursor 行租约选出唯一的 Poller leader
+	InstanceID  string        `yaml:"instance_id"`  // 实例标识，默认 hostname-pid
+	LeaderLease time.Duration `yaml:"leader_lease"` // leader 租约时长，默认 max(30s, 3×poll_interval)
 }
 
 // APIConfig HTTP API 配置
@@ -59,6 +80,12 @@ type LimitsConfig struct {
 	MaxRetries              int           `yaml:"max_retries"`
 	BindTokenTTL            time.Duration `yaml:"bind_token_ttl"`
 
+	// 投递重试：失败后按 retry_base_delay × 2^(n-1) 退避（上限 retry_max_delay），
+	// 超过 max_retries 次后进入死信队列
+	RetryInterval  time.Duration `yaml:"retry_interval"`   // 重试扫描间隔
+	RetryBaseDelay time.Duration `yaml:"retry_base_delay"` // 首次重试延迟
+	RetryMaxDelay  time.Duration `yaml:"retry_max_delay"`  // 单次重试延迟上限
+
 	// 平台独立限流配置
 	TelegramRateLimitPerSecond int `yaml:"telegram_rate_limit_per_second"` // Telegram 发送限流（每秒消息数）
 	QQRateLimitPerSecond       int `yaml:"qq_rate_limit_per_second"`       // QQ 发送限流（每秒消息数，建议 1-2）
@@ -71,12 +98,47 @@ type LimitsConfig struct {
 	RateLimitPerSecond int `yaml:"rate_limit_per_second"`
 }
 
+// AlertsConfig 运维告警配置（notifier 自诊断消息，如主服务事件 ID 回退）
+type AlertsConfig struct {
+	Chats []AlertChat `yaml:"chats"` // 接收告警的会话，为空时仅写日志
+}
+
+// AlertChat 告警接收会话
+type AlertChat struct {
+	Platform string `yaml:"platform"` // telegram / qq
+	ChatID   int64  `yaml:"chat_id"`  // QQ 群聊使用负数群号（与订阅会话 ID 一致）
+}
+
 // ScreenshotConfig 截图功能配置
 type ScreenshotConfig struct {
 	Enabled       bool          `yaml:"enabled"`        // 是否启用截图功能
 	BaseURL       string        `yaml:"base_url"`       // 截图目标 URL，默认 https://relaypulse.top
 	Timeout       time.Duration `yaml:"timeout"`        // 截图超时时间，默认 30s
 	MaxConcurrent int           `yaml:"max_concurrent"` // 最大并发数，默认 3
+
+	// 截图模板：定制主题、语言、时间范围、展示列与品牌文案
+	Templates       map[string]screenshot.Template `yaml:"templates"`
+	DefaultTemplate string                         `yaml:"default_template"` // 未指定模板时使用的模板名称
+}
+
+// AuditConfig 审计日志配置（记录 Bot 管理命令与白名单越权）
+type AuditConfig struct {
+	Enabled       bool   `yaml:"enabled"`        // 是否启用审计日志
+	RetentionDays int    `yaml:"retention_days"` // 保留天数，默认 90
+	APIToken      string `yaml:"api_token"`      // 管理 API（/api/admin/*）访问令牌（未配置时接口返回 503）
+}
+
+// I18nConfig 多语言配置
+type I18nConfig struct {
+	// DefaultLanguage 默认语言（zh/en/ja/ru），用于未设置 /lang 且无法从客户端推断语言的会话，默认 zh
+	DefaultLanguage string `yaml:"default_language"`
+}
+
+// DigestConfig 定期摘要配置（各会话通过 /digest 开启）
+type DigestConfig struct {
+	Enabled     bool `yaml:"enabled"`      // 是否启用定期摘要
+	DefaultHour int  `yaml:"default_hour"` // /digest 未指定时刻时的默认发送时刻（UTC+8，0-23），默认 9
+	MaxMonitors int  `yaml:"max_monitors"` // 摘要中逐项列出的监测项上限，默认 10
 }
 
 // Load 从文件加载配置，并应用环境变量覆盖
@@ -116,9 +178,15 @@ func (c *Config) applyEnvOverrides() {
 	if v := os.Getenv("TELEGRAM_BOT_TOKEN"); v != "" {
 		c.Telegram.BotToken = v
 	}
+	if v := os.Getenv("DATABASE_DRIVER"); v != "" {
+		c.Database.Driver = v
+	}
 	if v := os.Getenv("DATABASE_DSN"); v != "" {
 		c.Database.DSN = v
 	}
+	if v := os.Getenv("INSTANCE_ID"); v != "" {
+		c.Database.InstanceID = v
+	}
 	if v := os.Getenv("API_ADDR"); v != "" {
 		c.API.Addr = v
 	}
@@ -127,12 +195,24 @@ func (c *Config) applyEnvOverrides() {
 		c.QQ.OneBotHTTPURL = v
 		c.QQ.Enabled = true
 	}
+	if v := os.Getenv("QQ_ONEBOT_WS_URL"); v != "" {
+		c.QQ.OneBotWSURL = v
+	}
 	if v := os.Getenv("QQ_ACCESS_TOKEN"); v != "" {
 		c.QQ.AccessToken = v
 	}
 	if v := os.Getenv("QQ_CALLBACK_SECRET"); v != "" {
 		c.QQ.CallbackSecret = v
 	}
+	if v := os.Getenv("ADMIN_API_TOKEN"); v != "" {
+		c.Audit.APIToken = v
+	}
+	if v := os.Getenv("DEFAULT_LANGUAGE"); v != "" {
+		c.I18n.DefaultLanguage = v
+	}
+	if v := os.Getenv("DIGEST_ENABLED"); v != "" {
+		c.Digest.Enabled = v == "true" || v == "1"
+	}
 }
 
 // setDefaults 设置默认值
@@ -140,12 +220,31 @@ func (c *Config) setDefaults() {
 	if c.RelayPulse.PollInterval == 0 {
 		c.RelayPulse.PollInterval = 5 * time.Second
 	}
+	if c.RelayPulse.BatchSize <= 0 {
+		c.RelayPulse.BatchSize = 100
+	}
+	if c.RelayPulse.CatchUpPages <= 0 {
+		c.RelayPulse.CatchUpPages = 20
+	}
+	if c.RelayPulse.MaxBackoff <= 0 {
+		c.RelayPulse.MaxBackoff = 5 * time.Minute
+	}
 	if c.Database.Driver == "" {
 		c.Database.Driver = "sqlite"
 	}
-	if c.Database.DSN == "" {
+	if c.Database.DSN == "" && c.Database.Driver == "sqlite" {
 		c.Database.DSN = "file:notifier.db?_journal_mode=WAL&_timeout=5000&_busy_timeout=5000"
 	}
+	if c.Database.InstanceID == "" {
+		host, err := os.Hostname()
+		if err != nil || host == "" {
+			host = "notifier"
+		}
+		c.Database.InstanceID = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	if c.Database.LeaderLease == 0 {
+		c.Database.LeaderLease = max(30*time.Second, 3*c.RelayPulse.PollInterval)
+	}
 	if c.API.Addr == "" {
 		c.API.Addr = ":8081"
 	}
@@ -174,6 +273,18 @@ func (c *Config) setDefaults() {
 	if c.Limits.BindTokenTTL == 0 {
 		c.Limits.BindTokenTTL = 5 * time.Minute
 	}
+	if c.Limits.RetryInterval <= 0 {
+		c.Limits.RetryInterval = 15 * time.Second
+	}
+	if c.Limits.RetryBaseDelay <= 0 {
+		c.Limits.RetryBaseDelay = 30 * time.Second
+	}
+	if c.Limits.RetryMaxDelay <= 0 {
+		c.Limits.RetryMaxDelay = 30 * time.Minute
+	}
+	if c.Limits.RetryMaxDelay < c.Limits.RetryBaseDelay {
+		c.Limits.RetryMaxDelay = c.Limits.RetryBaseDelay
+	}
 	// QQ 默认值
 	if c.QQ.CallbackPath == "" {
 		c.QQ.CallbackPath = "/qq/callback"
@@ -188,6 +299,21 @@ func (c *Config) setDefaults() {
 	if c.Screenshot.MaxConcurrent == 0 {
 		c.Screenshot.MaxConcurrent = 3
 	}
+	// Audit 默认值
+	if c.Audit.RetentionDays <= 0 {
+		c.Audit.RetentionDays = 90
+	}
+	// I18n 默认值
+	if c.I18n.DefaultLanguage == "" {
+		c.I18n.DefaultLanguage = string(i18n.ZH)
+	}
+	// Digest 默认值
+	if c.Digest.DefaultHour == 0 {
+		c.Digest.DefaultHour = 9
+	}
+	if c.Digest.MaxMonitors <= 0 {
+		c.Digest.MaxMonitors = 10
+	}
 }
 
 // validate 验证配置
@@ -198,6 +324,55 @@ func (c *Config) validate() error {
 	if c.RelayPulse.APIToken == "" {
 		return fmt.Errorf("relay_pulse.api_token 是必需的（环境变量 RELAY_PULSE_API_TOKEN）")
 	}
+	if c.RelayPulse.BatchSize > 500 {
+		return fmt.Errorf("relay_pulse.batch_size 无效: %d（1-500）", c.RelayPulse.BatchSize)
+	}
+	switch c.Database.Driver {
+	case "sqlite":
+	case "postgres":
+		if c.Database.DSN == "" {
+			return fmt.Errorf("database.dsn 是必需的（driver=postgres，环境变量 DATABASE_DSN）")
+		}
+	default:
+		return fmt.Errorf("database.driver 无效: %s（可选 sqlite/postgres）", c.Database.Driver)
+	}
+	// 租约需覆盖至少两个轮询周期，否则 leader 在正常轮询间隙就可能被接管
+	if c.Database.LeaderLease < 2*c.RelayPulse.PollInterval {
+		return fmt.Errorf("database.leader_lease (%s) 必须不小于 2×poll_interval (%s)",
+			c.Database.LeaderLease, 2*c.RelayPulse.PollInterval)
+	}
+	if _, ok := i18n.Parse(c.I18n.DefaultLanguage); !ok {
+		return fmt.Errorf("i18n.default_language 无效: %s（可选 zh/en/ja/ru）", c.I18n.DefaultLanguage)
+	}
+	for name, tmpl := range c.Screenshot.Templates {
+		if err := tmpl.Validate(); err != nil {
+			return fmt.Errorf("screenshot.templates.%s: %w", name, err)
+		}
+	}
+	if name := c.Screenshot.DefaultTemplate; name != "" {
+		if _, ok := c.Screenshot.Templates[name]; !ok {
+			return fmt.Errorf("screenshot.default_template 不存在: %s", name)
+		}
+	}
+	if c.Digest.DefaultHour < 0 || c.Digest.DefaultHour > 23 {
+		return fmt.Errorf("digest.default_hour 无效: %d（0-23）", c.Digest.DefaultHour)
+	}
+	if c.QQ.OneBotWSURL != "" {
+		if u, err := url.Parse(c.QQ.OneBotWSURL); err != nil || (u.Scheme != "ws" && u.Scheme != "wss") || u.Host == "" {
+			return fmt.Errorf("qq.onebot_ws_url 无效: %s（需为 ws:// 或 wss:// 地址）", c.QQ.OneBotWSURL)
+		}
+	}
+	if err := validateRobots(c.Robots); err != nil {
+		return err
+	}
+	for i, ch := range c.Alerts.Chats {
+		if ch.Platform != storage.PlatformTelegram && ch.Platform != storage.PlatformQQ {
+			return fmt.Errorf("alerts.chats[%d].platform 无效: %s（可选 telegram/qq）", i, ch.Platform)
+		}
+		if ch.ChatID == 0 {
+			return fmt.Errorf("alerts.chats[%d].chat_id 是必需的", i)
+		}
+	}
 	// Telegram Bot Token 在开发环境可选（仅 API 服务启动）
 	// 如果未设置，Bot 和 Poller 功能将不可用
 	return nil
@@ -213,6 +388,16 @@ func (c *Config) HasQQ() bool {
 	return c.QQ.Enabled && c.QQ.OneBotHTTPURL != ""
 }
 
+// HasDigest 检查是否启用了定期摘要
+func (c *Config) HasDigest() bool {
+	return c.Digest.Enabled
+}
+
+// HasRobots 检查是否配置了群机器人（企业微信 / 钉钉）
+func (c *Config) HasRobots() bool {
+	return len(c.Robots) > 0
+}
+
 // HasScreenshot 检查是否启用了截图功能
 func (c *Config) HasScreenshot() bool {
 	return c.Screenshot.Enabled
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"strings"
+
+	"notifier/internal/i18n"
+	"notifier/internal/storage"
+)
+
+// RobotConfig 群机器人配置（企业微信 / 钉钉）
+// 群机器人只能推送、无法接收命令，订阅关系在配置中声明，启动时同步到存储
+type RobotConfig struct {
+	Name          string              `yaml:"name"`          // 唯一名称（用于日志，并派生稳定的 chat_id）
+	Platform      string              `yaml:"platform"`      // wecom / dingtalk
+	WebhookURL    string              `yaml:"webhook_url"`   // Webhook 地址（企业微信含 key，钉钉含 access_token）
+	Secret        string              `yaml:"secret"`        // 钉钉加签密钥（SEC 开头，未启用加签时留空）
+	Language      string              `yaml:"language"`      // 消息语言（zh/en/ja/ru），默认使用 i18n.default_language
+	Subscriptions []RobotSubscription `yaml:"subscriptions"` // 订阅的监测项
+}
+
+// RobotSubscription 群机器人订阅（匹配规则与 /add 一致）
+type RobotSubscription struct {
+	Provider string `yaml:"provider"` // 必填
+	Service  string `yaml:"service"`  // 为空表示该 provider 下全部 service
+	Channel  string `yaml:"channel"`  // 为空表示全部 channel
+	Events   string `yaml:"events"`   // 接收的事件类型（同 /filter，如 "down,up"），为空表示全部
+}
+
+// ChatID 由平台与名称派生的稳定 chat_id（正数，重启与多实例间一致）
+func (r RobotConfig) ChatID() int64 {
+	h := fnv.New64a()
+	h.Write([]byte(r.Platform + ":" + r.Name))
+	return int64(h.Sum64() & (1<<63 - 1))
+}
+
+// validateRobots 验证群机器人配置
+func validateRobots(robots []RobotConfig) error {
+	seen := make(map[string]bool)
+	for i, r := range robots {
+		if r.Name == "" {
+			return fmt.Errorf("robots[%d].name 是必需的", i)
+		}
+		key := r.Platform + ":" + r.Name
+		if seen[key] {
+			return fmt.Errorf("robots[%d].name 重复: %s", i, r.Name)
+		}
+		seen[key] = true
+
+		switch r.Platform {
+		case storage.PlatformWeCom:
+			if r.Secret != "" {
+				return fmt.Errorf("robots.%s: 企业微信群机器人不支持加签，请移除 secret", r.Name)
+			}
+		case storage.PlatformDingTalk:
+		default:
+			return fmt.Errorf("robots.%s: platform 无效: %s（可选 wecom/dingtalk）", r.Name, r.Platform)
+		}
+
+		u, err := url.Parse(r.WebhookURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("robots.%s: webhook_url 必须是 https 地址", r.Name)
+		}
+		if r.Language != "" {
+			if _, ok := i18n.Parse(r.Language); !ok {
+				return fmt.Errorf("robots.%s: language 无效: %s（可选 zh/en/ja/ru）", r.Name, r.Language)
+			}
+		}
+
+		if len(r.Subscriptions) == 0 {
+			return fmt.Errorf("robots.%s: subscriptions 不能为空", r.Name)
+		}
+		for j, sub := range r.Subscriptions {
+			if strings.TrimSpace(sub.Provider) == "" {
+				return fmt.Errorf("robots.%s.subscriptions[%d].provider 是必需的", r.Name, j)
+			}
+			if sub.Channel != "" && sub.Service == "" {
+				return fmt.Errorf("robots.%s.subscriptions[%d]: 指定 channel 时必须指定 service", r.Name, j)
+			}
+			if sub.Events != "" {
+				if _, err := storage.ParseEventMask(sub.Events); err != nil {
+					return fmt.Errorf("robots.%s.subscriptions[%d].events: %w", r.Name, j, err)
+				}
+			}
+		}
+	}
+	return nil
+}
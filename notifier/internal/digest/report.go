@@ -0,0 +1,397 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"notifier/internal/i18n"
+	"notifier/internal/storage"
+)
+
+// maxStatusResponseSize /api/status 响应体上限（7d 全量数据）
+const maxStatusResponseSize = 32 << 20
+
+// timePoint 时间轴上的单个 bucket（字段与主服务 storage.TimePoint 一致）
+type timePoint struct {
+	Latency      int     `json:"latency"`      // 平均延迟（毫秒）
+	Availability float64 `json:"availability"` // 可用率百分比，缺失时为 -1
+}
+
+// statusResponse /api/status 响应（仅解析摘要需要的字段）
+type statusResponse struct {
+	Data []struct {
+		Provider string      `json:"provider"`
+		Service  string      `json:"service"`
+		Channel  string      `json:"channel"`
+		Timeline []timePoint `json:"timeline"`
+	} `json:"data"`
+	Groups []struct {
+		Provider string `json:"provider"`
+		Service  string `json:"service"`
+		Channel  string `json:"channel"`
+		Layers   []struct {
+			LayerOrder int         `json:"layer_order"` // 0=父层
+			Timeline   []timePoint `json:"timeline"`
+		} `json:"layers"`
+	} `json:"groups"`
+}
+
+// monitorTimeline 单个监测项的时间轴
+type monitorTimeline struct {
+	Provider string
+	Service  string
+	Channel  string
+	Timeline []timePoint
+}
+
+// fetchTimelines 从 /api/status 获取所有监测项在 period 内的时间轴
+func (s *Scheduler) fetchTimelines(ctx context.Context, period string) ([]monitorTimeline, error) {
+	u, err := url.Parse(s.statusURL)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 status_url: %w", err)
+	}
+	q := u.Query()
+	q.Set("period", period)
+	q.Set("board", "all")
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("API 返回错误 [%d]: %s", resp.StatusCode, string(body))
+	}
+
+	var statusResp statusResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxStatusResponseSize)).Decode(&statusResp); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+
+	timelines := make([]monitorTimeline, 0, len(statusResp.Data)+len(statusResp.Groups))
+	for _, d := range statusResp.Data {
+		timelines = append(timelines, monitorTimeline{
+			Provider: d.Provider, Service: d.Service, Channel: d.Channel, Timeline: d.Timeline,
+		})
+	}
+	// 多模型监测组取父层时间轴
+	for _, g := range statusResp.Groups {
+		for _, layer := range g.Layers {
+			if layer.LayerOrder == 0 {
+				timelines = append(timelines, monitorTimeline{
+					Provider: g.Provider, Service: g.Service, Channel: g.Channel, Timeline: layer.Timeline,
+				})
+				break
+			}
+		}
+	}
+	return timelines, nil
+}
+
+// deriveStatusURL 从 events_url 推导 /api/status 地址
+func deriveStatusURL(eventsURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(eventsURL))
+	if err != nil {
+		return "", fmt.Errorf("events_url 无效: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("events_url 无效: 缺少 scheme 或 host")
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	if strings.HasSuffix(path, "/api/events") {
+		path = strings.TrimSuffix(path, "/api/events") + "/api/status"
+	} else {
+		// 无法推断时使用根路径
+		path = "/api/status"
+	}
+
+	u.Path = path
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String(), nil
+}
+
+// monitorStat 单个监测项的统计
+type monitorStat struct {
+	Provider  string
+	Service   string
+	Channel   string
+	Uptime    float64 // 可用率（0-100），无数据时为 -1
+	Latency   int     // 平均延迟（毫秒），无数据时为 0
+	Incidents int     // 故障次数
+
+	// 延迟趋势：前后半段的延迟累计
+	firstSum, firstCount   int
+	secondSum, secondCount int
+}
+
+// Report 摘要报告
+type Report struct {
+	Monitors     []*monitorStat // 按可用率升序
+	Uptime       float64        // 整体可用率（各监测项平均），无数据时为 -1
+	Incidents    int            // 故障总数
+	Worst        *monitorStat   // 表现最差的监测项（可用率最低，其次故障最多）
+	Latency      int            // 平均延迟（毫秒）
+	LatencyTrend float64        // 后半段相对前半段的延迟变化（百分比），无法计算时为 NaN
+}
+
+// matchesAny 判断任一订阅是否覆盖该监测项
+func matchesAny(subs []*storage.Subscription, provider, service, channel string) bool {
+	for _, sub := range subs {
+		if sub.Covers(provider, service, channel) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildReport 汇总订阅范围内的时间轴与故障记录
+func buildReport(subs []*storage.Subscription, timelines []monitorTimeline, incidents []*storage.Incident) *Report {
+	report := &Report{Uptime: -1, LatencyTrend: math.NaN()}
+
+	statByKey := make(map[string]*monitorStat)
+	for _, tl := range timelines {
+		if !matchesAny(subs, tl.Provider, tl.Service, tl.Channel) {
+			continue
+		}
+		key := strings.ToLower(tl.Provider + "/" + tl.Service + "/" + tl.Channel)
+		if _, ok := statByKey[key]; ok {
+			continue
+		}
+		stat := &monitorStat{Provider: tl.Provider, Service: tl.Service, Channel: tl.Channel, Uptime: -1}
+
+		var availSum float64
+		var availCount, latencySum, latencyCount int
+		half := len(tl.Timeline) / 2
+		for i, p := range tl.Timeline {
+			if p.Availability >= 0 {
+				availSum += p.Availability
+				availCount++
+			}
+			if p.Latency > 0 {
+				latencySum += p.Latency
+				latencyCount++
+				if i < half {
+					stat.firstSum += p.Latency
+					stat.firstCount++
+				} else {
+					stat.secondSum += p.Latency
+					stat.secondCount++
+				}
+			}
+		}
+		if availCount > 0 {
+			stat.Uptime = availSum / float64(availCount)
+		}
+		if latencyCount > 0 {
+			stat.Latency = latencySum / latencyCount
+		}
+
+		statByKey[key] = stat
+		report.Monitors = append(report.Monitors, stat)
+	}
+
+	// 故障计数（按监测项归集；未出现在时间轴中的监测项只计入总数）
+	for _, inc := range incidents {
+		if !matchesAny(subs, inc.Provider, inc.Service, inc.Channel) {
+			continue
+		}
+		report.Incidents++
+		key := strings.ToLower(inc.Provider + "/" + inc.Service + "/" + inc.Channel)
+		if stat, ok := statByKey[key]; ok {
+			stat.Incidents++
+		}
+	}
+
+	// 整体指标
+	var uptimeSum float64
+	var uptimeCount, latencySum, latencyCount int
+	var firstSum, firstCount, secondSum, secondCount int
+	for _, stat := range report.Monitors {
+		if stat.Uptime >= 0 {
+			uptimeSum += stat.Uptime
+			uptimeCount++
+		}
+		if stat.Latency > 0 {
+			latencySum += stat.Latency
+			latencyCount++
+		}
+		firstSum += stat.firstSum
+		firstCount += stat.firstCount
+		secondSum += stat.secondSum
+		secondCount += stat.secondCount
+	}
+	if uptimeCount > 0 {
+		report.Uptime = uptimeSum / float64(uptimeCount)
+	}
+	if latencyCount > 0 {
+		report.Latency = latencySum / latencyCount
+	}
+	if firstCount > 0 && secondCount > 0 && firstSum > 0 {
+		firstAvg := float64(firstSum) / float64(firstCount)
+		secondAvg := float64(secondSum) / float64(secondCount)
+		report.LatencyTrend = (secondAvg - firstAvg) / firstAvg * 100
+	}
+
+	// 可用率升序（无数据排最后），同可用率按故障数降序
+	sort.SliceStable(report.Monitors, func(i, j int) bool {
+		a, b := report.Monitors[i], report.Monitors[j]
+		if (a.Uptime < 0) != (b.Uptime < 0) {
+			return b.Uptime < 0
+		}
+		if a.Uptime != b.Uptime {
+			return a.Uptime < b.Uptime
+		}
+		return a.Incidents > b.Incidents
+	})
+	if len(report.Monitors) > 0 && report.Monitors[0].Uptime >= 0 {
+		report.Worst = report.Monitors[0]
+	}
+
+	return report
+}
+
+// renderer 按平台渲染文案（Telegram 使用 HTML，QQ 使用纯文本）
+type renderer struct {
+	lang i18n.Lang
+	html bool
+}
+
+func (r renderer) t(key string, args ...any) string {
+	if r.html {
+		return i18n.HTML(r.lang, key, args...)
+	}
+	return i18n.Text(r.lang, key, args...)
+}
+
+func (r renderer) esc(s string) string {
+	if r.html {
+		return html.EscapeString(s)
+	}
+	return s
+}
+
+// monitorName 监测项名称（provider / service [/ channel]）
+func monitorName(stat *monitorStat) string {
+	name := stat.Provider + " / " + stat.Service
+	if stat.Channel != "" {
+		name += " / " + stat.Channel
+	}
+	return name
+}
+
+// formatUptime 格式化可用率
+func formatUptime(uptime float64) string {
+	return fmt.Sprintf("%.2f%%", uptime)
+}
+
+// uptimeEmoji 可用率对应的图标
+func uptimeEmoji(uptime float64) string {
+	switch {
+	case uptime < 0:
+		return "⚪"
+	case uptime >= 99:
+		return "🟢"
+	case uptime >= 95:
+		return "🟡"
+	default:
+		return "🔴"
+	}
+}
+
+// render 渲染摘要文本
+func (r renderer) render(report *Report, frequency string, start, end time.Time, maxMonitors int) string {
+	cst := time.FixedZone("CST", 8*60*60)
+	layout := "2006-01-02 15:04"
+
+	var sb strings.Builder
+	if frequency == storage.DigestWeekly {
+		sb.WriteString(r.t("digest.title_weekly"))
+	} else {
+		sb.WriteString(r.t("digest.title_daily"))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(r.t("digest.range", start.In(cst).Format(layout), end.In(cst).Format(layout)))
+	sb.WriteString("\n\n")
+
+	if report.Uptime < 0 {
+		sb.WriteString(r.t("digest.no_data"))
+		sb.WriteString("\n")
+		if report.Incidents > 0 {
+			sb.WriteString(r.t("digest.incidents", report.Incidents))
+			sb.WriteString("\n")
+		}
+	} else {
+		sb.WriteString(r.t("digest.uptime", formatUptime(report.Uptime)))
+		sb.WriteString("\n")
+		sb.WriteString(r.t("digest.incidents", report.Incidents))
+		sb.WriteString("\n")
+		if report.Worst != nil && len(report.Monitors) > 1 {
+			sb.WriteString(r.t("digest.worst", monitorName(report.Worst), formatUptime(report.Worst.Uptime)))
+			sb.WriteString("\n")
+		}
+		if report.Latency > 0 {
+			sb.WriteString(r.t("digest.latency", report.Latency, r.trend(report.LatencyTrend)))
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(r.t("digest.monitors"))
+		sb.WriteString("\n")
+		for i, stat := range report.Monitors {
+			if i >= maxMonitors {
+				sb.WriteString(r.t("digest.more", len(report.Monitors)-maxMonitors))
+				sb.WriteString("\n")
+				break
+			}
+			line := fmt.Sprintf("%s %s", uptimeEmoji(stat.Uptime), r.esc(monitorName(stat)))
+			if stat.Uptime >= 0 {
+				line += ": " + formatUptime(stat.Uptime)
+			}
+			if stat.Latency > 0 {
+				line += fmt.Sprintf(" · %d ms", stat.Latency)
+			}
+			if stat.Incidents > 0 {
+				line += " · " + r.t("digest.monitor_incidents", stat.Incidents)
+			}
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(r.t("digest.footer"))
+	return sb.String()
+}
+
+// trend 延迟趋势文案（变化不足 10% 视为持平）
+func (r renderer) trend(pct float64) string {
+	switch {
+	case math.IsNaN(pct):
+		return ""
+	case pct >= 10:
+		return r.t("digest.trend_up", int(math.Round(pct)))
+	case pct <= -10:
+		return r.t("digest.trend_down", int(math.Round(-pct)))
+	default:
+		return r.t("digest.trend_flat")
+	}
+}
@@ -0,0 +1,388 @@
+// Package digest 定期摘要：按会话设置每日/每周发送订阅监测项的可用率、故障次数与延迟趋势
+package digest
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"notifier/internal/config"
+	"notifier/internal/i18n"
+	"notifier/internal/qq"
+	"notifier/internal/screenshot"
+	"notifier/internal/storage"
+	"notifier/internal/telegram"
+)
+
+const (
+	checkInterval     = time.Minute             // 检查到期摘要的间隔
+	maxLateness       = 6 * time.Hour           // 超过该时长未发送的摘要直接跳过（服务长时间停机后不补发）
+	incidentRetention = 8 * 24 * time.Hour      // 故障记录保留时长（覆盖每周摘要窗口）
+	cleanupInterval   = time.Hour               // 故障记录清理间隔
+	qqSendInterval    = 1500 * time.Millisecond // QQ 连续发送间隔（降低风控）
+)
+
+// cst 摘要时刻与展示时间使用的时区（与通知时间一致）
+var cst = time.FixedZone("CST", 8*60*60)
+
+// Scheduler 定期摘要调度器
+type Scheduler struct {
+	cfg        *config.Config
+	storage    storage.Storage
+	screenshot *screenshot.Service
+	tgClient   *telegram.Client
+	qqClient   *qq.Client
+	httpClient *http.Client
+	statusURL  string
+
+	mu       sync.Mutex
+	running  bool
+	stopChan chan struct{}
+}
+
+// NewScheduler 创建摘要调度器（screenshotSvc 可为 nil）
+func NewScheduler(cfg *config.Config, store storage.Storage, screenshotSvc *screenshot.Service) (*Scheduler, error) {
+	statusURL, err := deriveStatusURL(cfg.RelayPulse.EventsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Scheduler{
+		cfg:        cfg,
+		storage:    store,
+		screenshot: screenshotSvc,
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+		statusURL: statusURL,
+		stopChan:  make(chan struct{}),
+	}
+
+	// 按配置初始化客户端
+	if cfg.HasTelegramToken() {
+		s.tgClient = telegram.NewClient(cfg.Telegram.BotToken)
+	}
+	if cfg.HasQQ() {
+		s.qqClient = qq.NewClient(cfg.QQ.OneBotHTTPURL, cfg.QQ.AccessToken)
+	}
+
+	return s, nil
+}
+
+// Start 启动调度
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return fmt.Errorf("摘要调度器已在运行")
+	}
+	s.running = true
+	s.stopChan = make(chan struct{})
+	s.mu.Unlock()
+
+	slog.Info("摘要调度器启动",
+		"status_url", s.statusURL,
+		"screenshot", s.screenshot != nil,
+	)
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Time{}
+	for {
+		if time.Since(lastCleanup) >= cleanupInterval {
+			s.cleanupIncidents(ctx)
+			lastCleanup = time.Now()
+		}
+		s.runDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			slog.Info("摘要调度器收到停止信号")
+			return ctx.Err()
+		case <-s.stopChan:
+			slog.Info("摘要调度器停止")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop 停止调度
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		close(s.stopChan)
+		s.running = false
+	}
+}
+
+// cleanupIncidents 清理过期的故障记录
+func (s *Scheduler) cleanupIncidents(ctx context.Context) {
+	deleted, err := s.storage.CleanupOldIncidents(ctx, time.Now().Add(-incidentRetention))
+	if err != nil && ctx.Err() == nil {
+		slog.Warn("清理故障记录失败", "error", err)
+	} else if deleted > 0 {
+		slog.Info("故障记录清理完成", "deleted", deleted)
+	}
+}
+
+// lastSlot 返回不晚于 now 的最近一个摘要时刻
+// daily：每天 hour:00；weekly：每周一 hour:00（UTC+8）
+func lastSlot(now time.Time, frequency string, hour int) time.Time {
+	n := now.In(cst)
+	slot := time.Date(n.Year(), n.Month(), n.Day(), hour, 0, 0, 0, cst)
+	if slot.After(n) {
+		slot = slot.AddDate(0, 0, -1)
+	}
+	if frequency == storage.DigestWeekly {
+		for slot.Weekday() != time.Monday {
+			slot = slot.AddDate(0, 0, -1)
+		}
+	}
+	return slot
+}
+
+// window 摘要统计窗口与对应的 /api/status period
+func window(frequency string) (time.Duration, string) {
+	if frequency == storage.DigestWeekly {
+		return 7 * 24 * time.Hour, "7d"
+	}
+	return 24 * time.Hour, "24h"
+}
+
+// runCache 单轮调度内共享的数据（同一 period 只请求一次）
+type runCache struct {
+	timelines map[string][]monitorTimeline
+	incidents map[string][]*storage.Incident
+}
+
+// runDue 发送所有到期的摘要
+func (s *Scheduler) runDue(ctx context.Context) {
+	chats, err := s.storage.GetDigestChats(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Warn("查询摘要 Chat 失败", "error", err)
+		}
+		return
+	}
+
+	now := time.Now()
+	cache := &runCache{
+		timelines: make(map[string][]monitorTimeline),
+		incidents: make(map[string][]*storage.Incident),
+	}
+
+	for _, chat := range chats {
+		if ctx.Err() != nil {
+			return
+		}
+		if !s.platformEnabled(chat.Platform) {
+			continue
+		}
+
+		slot := lastSlot(now, chat.DigestFrequency, chat.DigestHour)
+		if chat.DigestSentAt >= slot.Unix() {
+			continue
+		}
+
+		// 条件更新抢占，多实例下同一时刻只发送一次
+		claimed, err := s.storage.ClaimDigest(ctx, chat.Platform, chat.ChatID, chat.DigestSentAt, now.Unix())
+		if err != nil {
+			slog.Warn("抢占摘要发送失败", "platform", chat.Platform, "chat_id", chat.ChatID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if now.Sub(slot) > maxLateness {
+			slog.Info("摘要已过期，跳过本期", "platform", chat.Platform, "chat_id", chat.ChatID, "slot", slot)
+			continue
+		}
+		// 暂停通知（/pause）期间的摘要同样已抢占，恢复后不补发
+		if storage.IsPaused(chat.PausedUntil, now.Unix()) {
+			slog.Info("会话已暂停通知，跳过本期摘要", "platform", chat.Platform, "chat_id", chat.ChatID, "slot", slot)
+			continue
+		}
+
+		s.sendDigest(ctx, chat, now, cache)
+	}
+}
+
+// platformEnabled 平台客户端是否可用
+func (s *Scheduler) platformEnabled(platform string) bool {
+	switch platform {
+	case storage.PlatformTelegram:
+		return s.tgClient != nil
+	case storage.PlatformQQ:
+		return s.qqClient != nil
+	default:
+		return false
+	}
+}
+
+// sendDigest 生成并发送单个会话的摘要
+func (s *Scheduler) sendDigest(ctx context.Context, chat *storage.Chat, now time.Time, cache *runCache) {
+	subs, err := s.storage.GetSubscriptionsByChatID(ctx, chat.Platform, chat.ChatID)
+	if err != nil {
+		slog.Warn("查询订阅失败", "platform", chat.Platform, "chat_id", chat.ChatID, "error", err)
+		return
+	}
+	if len(subs) == 0 {
+		return
+	}
+
+	span, period := window(chat.DigestFrequency)
+	start := now.Add(-span)
+
+	timelines, ok := cache.timelines[period]
+	if !ok {
+		timelines, err = s.fetchTimelines(ctx, period)
+		if err != nil {
+			slog.Warn("获取状态数据失败，跳过摘要", "period", period, "error", err)
+			return
+		}
+		cache.timelines[period] = timelines
+	}
+
+	incidents, ok := cache.incidents[period]
+	if !ok {
+		incidents, err = s.storage.GetIncidentsSince(ctx, start.Unix())
+		if err != nil {
+			slog.Warn("查询故障记录失败", "error", err)
+		}
+		cache.incidents[period] = incidents
+	}
+
+	report := buildReport(subs, timelines, incidents)
+	lang := i18n.Resolve(chat.Language, "")
+
+	slog.Info("发送定期摘要",
+		"platform", chat.Platform,
+		"chat_id", chat.ChatID,
+		"frequency", chat.DigestFrequency,
+		"monitors", len(report.Monitors),
+		"incidents", report.Incidents,
+	)
+
+	r := renderer{lang: lang, html: chat.Platform == storage.PlatformTelegram}
+	text := r.render(report, chat.DigestFrequency, start, now, s.cfg.Digest.MaxMonitors)
+	if err := s.sendText(ctx, chat, text); err != nil {
+		slog.Warn("发送摘要失败", "platform", chat.Platform, "chat_id", chat.ChatID, "error", err)
+		s.handleSendError(ctx, chat, err)
+		return
+	}
+
+	// 附带截图（截图服务未启用或失败时仅发送文本）
+	if s.screenshot == nil {
+		return
+	}
+	providers, services := uniqueTargets(subs)
+	image, err := s.screenshot.CaptureWithOptions(ctx, providers, services, &screenshot.CaptureOptions{
+		Title:    renderer{lang: lang}.t(titleKey(chat.DigestFrequency)),
+		Period:   period,
+		Language: string(lang),
+	})
+	if err != nil {
+		slog.Warn("摘要截图失败", "platform", chat.Platform, "chat_id", chat.ChatID, "error", err)
+		return
+	}
+	if err := s.sendImage(ctx, chat, image); err != nil {
+		slog.Warn("发送摘要截图失败", "platform", chat.Platform, "chat_id", chat.ChatID, "error", err)
+	}
+}
+
+// titleKey 摘要标题文案 key
+func titleKey(frequency string) string {
+	if frequency == storage.DigestWeekly {
+		return "digest.title_weekly"
+	}
+	return "digest.title_daily"
+}
+
+// sendText 发送文本消息
+func (s *Scheduler) sendText(ctx context.Context, chat *storage.Chat, text string) error {
+	switch chat.Platform {
+	case storage.PlatformTelegram:
+		_, err := s.tgClient.SendMessageHTML(ctx, chat.ChatID, text)
+		return err
+	case storage.PlatformQQ:
+		defer s.qqPause(ctx)
+		var err error
+		// 负数 chatID 表示群聊，正数表示私聊
+		if chat.ChatID < 0 {
+			_, err = s.qqClient.SendGroupMessage(ctx, -chat.ChatID, text)
+		} else {
+			_, err = s.qqClient.SendPrivateMessage(ctx, chat.ChatID, text)
+		}
+		return err
+	default:
+		return fmt.Errorf("unknown platform: %s", chat.Platform)
+	}
+}
+
+// sendImage 发送图片消息
+func (s *Scheduler) sendImage(ctx context.Context, chat *storage.Chat, image []byte) error {
+	switch chat.Platform {
+	case storage.PlatformTelegram:
+		_, err := s.tgClient.SendPhoto(ctx, chat.ChatID, image, "")
+		return err
+	case storage.PlatformQQ:
+		defer s.qqPause(ctx)
+		base64Data := base64.StdEncoding.EncodeToString(image)
+		var err error
+		if chat.ChatID < 0 {
+			_, err = s.qqClient.SendGroupImageMessage(ctx, -chat.ChatID, base64Data)
+		} else {
+			_, err = s.qqClient.SendPrivateImageMessage(ctx, chat.ChatID, base64Data)
+		}
+		return err
+	default:
+		return fmt.Errorf("unknown platform: %s", chat.Platform)
+	}
+}
+
+// qqPause QQ 连续发送之间的间隔
+func (s *Scheduler) qqPause(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(qqSendInterval):
+	}
+}
+
+// handleSendError 处理发送错误（Telegram 被封禁时标记 Chat）
+func (s *Scheduler) handleSendError(ctx context.Context, chat *storage.Chat, err error) {
+	if chat.Platform == storage.PlatformTelegram && telegram.IsForbiddenError(err) {
+		if err := s.storage.UpdateChatStatus(ctx, chat.Platform, chat.ChatID, storage.ChatStatusBlocked); err != nil {
+			slog.Error("更新用户状态失败", "error", err)
+		}
+	}
+}
+
+// uniqueTargets 提取订阅中的 provider 与 service（去重，保持顺序）
+func uniqueTargets(subs []*storage.Subscription) ([]string, []string) {
+	seenProvider := make(map[string]bool)
+	seenService := make(map[string]bool)
+	var providers, services []string
+	for _, sub := range subs {
+		p := strings.ToLower(sub.Provider)
+		if !seenProvider[p] {
+			seenProvider[p] = true
+			providers = append(providers, sub.Provider)
+		}
+		svc := strings.ToLower(sub.Service)
+		if svc != "" && !seenService[svc] {
+			seenService[svc] = true
+			services = append(services, sub.Service)
+		}
+	}
+	return providers, services
+}
@@ -0,0 +1,161 @@
+package dingtalk
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 钉钉自定义机器人限制每个机器人每分钟最多 20 条消息
+const minSendInterval = 3 * time.Second
+
+// Client 钉钉自定义机器人客户端
+//
+// 安全设置：
+// - 加签：配置 secret（SEC 开头）后，每次请求附带 timestamp 与 sign
+// - 自定义关键词：消息内容需包含关键词，可在机器人安全设置中使用事件标题中的文案
+// - IP 地址段：需将 notifier 出口 IP 加入白名单
+type Client struct {
+	webhookURL string
+	secret     string
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	nextSend time.Time // 下一次允许发送的时间（单机器人限流）
+}
+
+// NewClient 创建钉钉机器人客户端
+// webhookURL 形如 https://oapi.dingtalk.com/robot/send?access_token=xxx，secret 为空表示未启用加签
+func NewClient(webhookURL, secret string) *Client {
+	return &Client{
+		webhookURL: strings.TrimSpace(webhookURL),
+		secret:     strings.TrimSpace(secret),
+		httpClient: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+	}
+}
+
+// apiResponse 机器人接口响应
+type apiResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// SendMarkdown 发送 markdown 消息
+// title 用于会话列表与通知栏预览；钉钉 markdown 中单个换行不生效，这里统一转换为硬换行
+func (c *Client) SendMarkdown(ctx context.Context, title, text string) error {
+	return c.send(ctx, map[string]any{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"title": title,
+			"text":  strings.ReplaceAll(text, "\n", "  \n"),
+		},
+	})
+}
+
+// send 发送消息并检查业务错误码
+func (c *Client) send(ctx context.Context, payload map[string]any) error {
+	if err := c.throttle(ctx); err != nil {
+		return err
+	}
+
+	target, err := c.signedURL(time.Now())
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP 错误 [%d]: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var apiResp apiResponse
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	if apiResp.ErrCode != 0 {
+		return fmt.Errorf("钉钉机器人错误 [%d]: %s", apiResp.ErrCode, apiResp.ErrMsg)
+	}
+	return nil
+}
+
+// signedURL 生成带签名的请求地址
+// 签名算法：sign = urlencode(base64(HmacSHA256(secret, timestamp + "\n" + secret)))，timestamp 为毫秒
+// 钉钉要求 timestamp 与服务器时间相差不超过 1 小时
+func (c *Client) signedURL(now time.Time) (string, error) {
+	if c.secret == "" {
+		return c.webhookURL, nil
+	}
+
+	u, err := url.Parse(c.webhookURL)
+	if err != nil {
+		return "", fmt.Errorf("解析 Webhook 地址失败: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(c.secret))
+	mac.Write([]byte(timestamp + "\n" + c.secret))
+
+	q := u.Query()
+	q.Set("timestamp", timestamp)
+	q.Set("sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// throttle 按最小发送间隔排队，避免触发机器人频率限制
+func (c *Client) throttle(ctx context.Context) error {
+	c.mu.Lock()
+	now := time.Now()
+	wait := c.nextSend.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	c.nextSend = now.Add(wait + minSendInterval)
+	c.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
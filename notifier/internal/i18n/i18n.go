@@ -0,0 +1,156 @@
+// Package i18n 通知服务的多语言文案（命令回复与事件通知）
+//
+// 文案模板使用 fmt 占位符，可包含 <b>…</b> 强调标记：
+// - HTML 渲染（Telegram）：转义模板与字符串参数后保留 <b> 标记
+// - Text 渲染（QQ）：去掉 <b> 标记，参数原样输出
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"sync/atomic"
+)
+
+// Lang 语言代码
+type Lang string
+
+// 支持的语言
+const (
+	ZH Lang = "zh"
+	EN Lang = "en"
+	JA Lang = "ja"
+	RU Lang = "ru"
+)
+
+// Supported 支持的语言（/lang 展示顺序）
+var Supported = []Lang{ZH, EN, JA, RU}
+
+// nativeNames 语言的自称
+var nativeNames = map[Lang]string{
+	ZH: "中文",
+	EN: "English",
+	JA: "日本語",
+	RU: "Русский",
+}
+
+var defaultLang atomic.Value // Lang
+
+func init() {
+	defaultLang.Store(ZH)
+}
+
+// SetDefault 设置默认语言（未设置偏好、且无法从平台推断语言的会话使用）
+func SetDefault(l Lang) {
+	defaultLang.Store(l)
+}
+
+// Default 返回默认语言
+func Default() Lang {
+	return defaultLang.Load().(Lang)
+}
+
+// Parse 解析语言代码，兼容 BCP 47 形式（如 en-US、zh-Hans、ru_RU）
+func Parse(code string) (Lang, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i > 0 {
+		code = code[:i]
+	}
+	switch code {
+	case "zh", "cn":
+		return ZH, true
+	case "en":
+		return EN, true
+	case "ja", "jp":
+		return JA, true
+	case "ru":
+		return RU, true
+	default:
+		return "", false
+	}
+}
+
+// Name 返回语言的自称（如 English）
+func Name(l Lang) string {
+	if n, ok := nativeNames[l]; ok {
+		return n
+	}
+	return string(l)
+}
+
+// Options 可选语言列表（用于 /lang 提示），如 "zh (中文), en (English)"
+func Options() string {
+	opts := make([]string, 0, len(Supported))
+	for _, l := range Supported {
+		opts = append(opts, fmt.Sprintf("%s (%s)", l, Name(l)))
+	}
+	return strings.Join(opts, ", ")
+}
+
+type ctxKey struct{}
+
+// WithLang 将会话语言写入 context（命令处理链路中传递）
+func WithLang(ctx context.Context, l Lang) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext 读取会话语言，未设置时返回默认语言
+func FromContext(ctx context.Context) Lang {
+	if l, ok := ctx.Value(ctxKey{}).(Lang); ok && l != "" {
+		return l
+	}
+	return Default()
+}
+
+// Resolve 按优先级选择语言：会话偏好 > 平台提供的语言代码 > 默认语言
+func Resolve(preference, platformCode string) Lang {
+	if l, ok := Parse(preference); ok {
+		return l
+	}
+	if l, ok := Parse(platformCode); ok {
+		return l
+	}
+	return Default()
+}
+
+// template 查找模板：目标语言缺失时回退中文，仍缺失时返回 key 本身
+func template(l Lang, key string) string {
+	entry, ok := messages[key]
+	if !ok {
+		return key
+	}
+	if s, ok := entry[l]; ok {
+		return s
+	}
+	return entry[ZH]
+}
+
+// Text 渲染纯文本消息（去掉 <b> 标记）
+func Text(l Lang, key string, args ...any) string {
+	tpl := strings.NewReplacer("<b>", "", "</b>", "").Replace(template(l, key))
+	if len(args) == 0 {
+		return tpl
+	}
+	return fmt.Sprintf(tpl, args...)
+}
+
+// htmlTagRestorer 恢复被转义的强调标记
+var htmlTagRestorer = strings.NewReplacer("&lt;b&gt;", "<b>", "&lt;/b&gt;", "</b>")
+
+// HTML 渲染 Telegram HTML 消息（模板与字符串参数均做转义）
+func HTML(l Lang, key string, args ...any) string {
+	tpl := htmlTagRestorer.Replace(html.EscapeString(template(l, key)))
+	if len(args) == 0 {
+		return tpl
+	}
+	escaped := make([]any, len(args))
+	for i, a := range args {
+		if s, ok := a.(string); ok {
+			escaped[i] = html.EscapeString(s)
+		} else {
+			escaped[i] = a
+		}
+	}
+	return fmt.Sprintf(tpl, escaped...)
+}
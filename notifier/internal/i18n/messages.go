@@ -0,0 +1,1552 @@
+package i18n
+
+// messages 文案目录：key → 语言 → 模板
+// 同一 key 的各语言模板必须使用相同顺序、相同类型的占位符
+var messages = map[string]map[Lang]string{
+	// ===== 通用 =====
+	"cmd.unknown": {
+		ZH: "未知命令。发送 /help 查看帮助。",
+		EN: "Unknown command. Send /help for help.",
+		JA: "不明なコマンドです。/help でヘルプを表示します。",
+		RU: "Неизвестная команда. Отправьте /help для справки.",
+	},
+	"cmd.error": {
+		ZH: "命令执行出错，请稍后重试。",
+		EN: "Something went wrong. Please try again later.",
+		JA: "コマンドの実行に失敗しました。しばらくしてから再試行してください。",
+		RU: "Ошибка выполнения команды. Повторите попытку позже.",
+	},
+	"cmd.status_check_failed": {
+		ZH: "状态检查失败，请稍后重试。",
+		EN: "Status check failed. Please try again later.",
+		JA: "ステータスチェックに失敗しました。しばらくしてから再試行してください。",
+		RU: "Не удалось проверить статус. Повторите попытку позже.",
+	},
+	"cmd.permission_check_failed": {
+		ZH: "权限校验失败，请稍后重试。",
+		EN: "Failed to verify permissions. Please try again later.",
+		JA: "権限の確認に失敗しました。しばらくしてから再試行してください。",
+		RU: "Не удалось проверить права. Повторите попытку позже.",
+	},
+	"cmd.permission_denied": {
+		ZH: "权限不足：该命令需要 %s 及以上角色（群主/管理员默认为 owner）。发送 /role 查看群内角色。",
+		EN: "Permission denied: this command requires the %s role or higher (group owners/admins are owners by default). Send /role to see group roles.",
+		JA: "権限がありません：このコマンドには %s 以上のロールが必要です（グループのオーナー/管理者は既定で owner）。/role でグループのロールを確認できます。",
+		RU: "Недостаточно прав: команде нужна роль %s или выше (владелец и администраторы группы по умолчанию owner). Отправьте /role, чтобы увидеть роли группы.",
+	},
+
+	// ===== /start =====
+	"start.welcome": {
+		ZH: `欢迎使用 <b>RelayPulse 通知 Bot</b>！
+
+我可以在你收藏的 LLM 中继服务状态变化时发送通知。
+
+<b>命令列表：</b>
+/list - 查看当前订阅
+/add <provider> [service] [channel] - 添加订阅
+/remove <provider> [service] [channel] - 移除订阅
+/filter <provider> [service] [channel] <types> - 设置接收的事件类型
+/clear - 清空所有订阅
+/snap - 截图订阅服务状态
+/status - 查看服务状态
+/lang [code] - 切换语言
+/digest [daily|weekly|off] - 定期摘要
+/help - 显示帮助
+
+<b>快速开始：</b>
+从 RelayPulse 网页点击"订阅通知"按钮，即可一键导入收藏列表。`,
+		EN: `Welcome to the <b>RelayPulse Notification Bot</b>!
+
+I notify you when the status of your favorite LLM relay services changes.
+
+<b>Commands:</b>
+/list - Show subscriptions
+/add <provider> [service] [channel] - Add a subscription
+/remove <provider> [service] [channel] - Remove a subscription
+/filter <provider> [service] [channel] <types> - Choose event types to receive
+/clear - Remove all subscriptions
+/snap - Screenshot of subscribed services
+/status - Bot status
+/lang [code] - Change language
+/digest [daily|weekly|off] - Scheduled digest
+/help - Show help
+
+<b>Quick start:</b>
+Click "Subscribe" on the RelayPulse website to import your favorites in one step.`,
+		JA: `<b>RelayPulse 通知 Bot</b> へようこそ！
+
+お気に入りの LLM リレーサービスのステータスが変化したときに通知します。
+
+<b>コマンド一覧：</b>
+/list - 購読一覧を表示
+/add <provider> [service] [channel] - 購読を追加
+/remove <provider> [service] [channel] - 購読を削除
+/filter <provider> [service] [channel] <types> - 受信するイベント種別を設定
+/clear - すべての購読を削除
+/snap - 購読中サービスのスクリーンショット
+/status - Bot のステータス
+/lang [code] - 言語を切り替え
+/digest [daily|weekly|off] - 定期サマリー
+/help - ヘルプを表示
+
+<b>クイックスタート：</b>
+RelayPulse のウェブページで「通知を購読」をクリックすると、お気に入りを一括インポートできます。`,
+		RU: `Добро пожаловать в <b>RelayPulse Notification Bot</b>!
+
+Я сообщаю об изменении статуса избранных LLM-ретрансляторов.
+
+<b>Команды:</b>
+/list - Список подписок
+/add <provider> [service] [channel] - Добавить подписку
+/remove <provider> [service] [channel] - Удалить подписку
+/filter <provider> [service] [channel] <types> - Выбрать типы событий
+/clear - Удалить все подписки
+/snap - Скриншот статуса подписок
+/status - Статус бота
+/lang [code] - Сменить язык
+/digest [daily|weekly|off] - Регулярная сводка
+/help - Справка
+
+<b>Быстрый старт:</b>
+Нажмите «Подписаться» на сайте RelayPulse, чтобы импортировать избранное одним действием.`,
+	},
+	"start.token_invalid": {
+		ZH: "绑定链接无效或已过期，请重新从网页获取。",
+		EN: "The link is invalid or has expired. Please get a new one from the website.",
+		JA: "リンクが無効か期限切れです。ウェブページから再取得してください。",
+		RU: "Ссылка недействительна или устарела. Получите новую на сайте.",
+	},
+	"start.token_missing": {
+		ZH: "绑定链接不存在，请重新从网页获取。",
+		EN: "The link does not exist. Please get a new one from the website.",
+		JA: "リンクが存在しません。ウェブページから再取得してください。",
+		RU: "Ссылка не найдена. Получите новую на сайте.",
+	},
+	"start.favorites_invalid": {
+		ZH: "收藏数据格式错误，请联系管理员。",
+		EN: "The favorites data is malformed. Please contact the administrator.",
+		JA: "お気に入りデータの形式が正しくありません。管理者に連絡してください。",
+		RU: "Некорректный формат избранного. Обратитесь к администратору.",
+	},
+	"start.validator_unconfigured": {
+		ZH: "当前无法验证订阅（验证服务未配置），为避免导入无效或冷板订阅，已拒绝本次导入。请稍后再试。",
+		EN: "Subscriptions cannot be verified right now (validator not configured), so the import was rejected to avoid invalid or cold-board entries. Please try again later.",
+		JA: "現在購読を検証できません（検証サービス未設定）。無効またはコールドボードの購読を避けるため、インポートを拒否しました。しばらくしてから再試行してください。",
+		RU: "Сейчас невозможно проверить подписки (валидатор не настроен), поэтому импорт отклонён во избежание недействительных или «холодных» записей. Повторите попытку позже.",
+	},
+	"start.limit_reached": {
+		ZH: "订阅数量已达上限（%d/%d）。请先使用 /clear 清空或 /remove 移除部分订阅。",
+		EN: "Subscription limit reached (%d/%d). Use /clear or /remove to free up slots first.",
+		JA: "購読数が上限に達しました（%d/%d）。先に /clear または /remove で購読を削除してください。",
+		RU: "Достигнут лимит подписок (%d/%d). Сначала освободите место с помощью /clear или /remove.",
+	},
+	"start.imported": {
+		ZH: "成功导入 <b>%d</b> 个订阅！\n\n发送 /list 查看当前订阅列表。",
+		EN: "Imported <b>%d</b> subscriptions!\n\nSend /list to see them.",
+		JA: "<b>%d</b> 件の購読をインポートしました！\n\n/list で購読一覧を表示します。",
+		RU: "Импортировано подписок: <b>%d</b>!\n\nОтправьте /list, чтобы их увидеть.",
+	},
+	"start.cold_skipped": {
+		ZH: "\n\n🚫 已跳过 <b>%d</b> 个冷板订阅（board=cold 不支持订阅通知）。",
+		EN: "\n\n🚫 Skipped <b>%d</b> cold-board entries (board=cold does not support notifications).",
+		JA: "\n\n🚫 コールドボードの購読 <b>%d</b> 件をスキップしました（board=cold は通知に対応していません）。",
+		RU: "\n\n🚫 Пропущено «холодных» записей: <b>%d</b> (board=cold не поддерживает уведомления).",
+	},
+	"start.failed": {
+		ZH: "\n\n⚠️ 有 <b>%d</b> 个订阅导入失败（可能已下线或参数不合法）。",
+		EN: "\n\n⚠️ <b>%d</b> subscriptions failed to import (possibly removed or invalid).",
+		JA: "\n\n⚠️ <b>%d</b> 件の購読をインポートできませんでした（削除済みまたはパラメータ不正の可能性）。",
+		RU: "\n\n⚠️ Не удалось импортировать подписок: <b>%d</b> (возможно, удалены или некорректны).",
+	},
+	"start.partial": {
+		ZH: "\n\n⚠️ 部分订阅因数量限制未能添加（%d/%d）",
+		EN: "\n\n⚠️ Some subscriptions were not added due to the limit (%d/%d)",
+		JA: "\n\n⚠️ 上限のため一部の購読を追加できませんでした（%d/%d）",
+		RU: "\n\n⚠️ Часть подписок не добавлена из-за лимита (%d/%d)",
+	},
+
+	// ===== /list =====
+	"list.empty": {
+		ZH: "你还没有订阅任何服务。\n\n使用 /add 添加订阅，或从网页点击「订阅通知」一键导入。",
+		EN: "You have no subscriptions yet.\n\nUse /add to subscribe, or click \"Subscribe\" on the website to import your favorites.",
+		JA: "まだ購読しているサービスはありません。\n\n/add で購読を追加するか、ウェブページの「通知を購読」から一括インポートしてください。",
+		RU: "У вас пока нет подписок.\n\nИспользуйте /add или нажмите «Подписаться» на сайте, чтобы импортировать избранное.",
+	},
+	"list.empty_qq": {
+		ZH: "你还没有订阅任何服务。\n\n使用 /add <provider> [service] 添加订阅。",
+		EN: "You have no subscriptions yet.\n\nUse /add <provider> [service] to subscribe.",
+		JA: "まだ購読しているサービスはありません。\n\n/add <provider> [service] で購読を追加してください。",
+		RU: "У вас пока нет подписок.\n\nИспользуйте /add <provider> [service], чтобы подписаться.",
+	},
+	"list.header": {
+		ZH: "<b>当前订阅（%d 个）：</b>\n\n",
+		EN: "<b>Subscriptions (%d):</b>\n\n",
+		JA: "<b>購読一覧（%d 件）：</b>\n\n",
+		RU: "<b>Подписки (%d):</b>\n\n",
+	},
+	"list.legacy": {
+		ZH: "（旧版）",
+		EN: " (legacy)",
+		JA: "（旧形式）",
+		RU: " (устаревшая)",
+	},
+	"list.filter": {
+		ZH: " [仅 %s]",
+		EN: " [only %s]",
+		JA: " [%s のみ]",
+		RU: " [только %s]",
+	},
+	"list.creator": {
+		ZH: " (%d 添加)",
+		EN: " (added by %d)",
+		JA: " (%d が追加)",
+		RU: " (добавил %d)",
+	},
+	"list.footer": {
+		ZH: "\n使用 /remove <provider> [service] [channel] 移除订阅\n使用 /filter <provider> [service] [channel] <types> 设置接收的事件类型",
+		EN: "\nUse /remove <provider> [service] [channel] to unsubscribe\nUse /filter <provider> [service] [channel] <types> to choose event types",
+		JA: "\n/remove <provider> [service] [channel] で購読を削除\n/filter <provider> [service] [channel] <types> で受信するイベント種別を設定",
+		RU: "\n/remove <provider> [service] [channel] — отписаться\n/filter <provider> [service] [channel] <types> — выбрать типы событий",
+	},
+
+	// ===== /add =====
+	"add.usage": {
+		ZH: "用法: /add <provider> [service] [channel]\n\n例如:\n/add 88code → 订阅 88code 所有服务\n/add 88code cc → 订阅 88code 的 cc 服务",
+		EN: "Usage: /add <provider> [service] [channel]\n\nExamples:\n/add 88code → all services of 88code\n/add 88code cc → the cc service of 88code",
+		JA: "使い方: /add <provider> [service] [channel]\n\n例:\n/add 88code → 88code のすべてのサービスを購読\n/add 88code cc → 88code の cc サービスを購読",
+		RU: "Использование: /add <provider> [service] [channel]\n\nПримеры:\n/add 88code → все сервисы 88code\n/add 88code cc → сервис cc у 88code",
+	},
+	"add.validator_unconfigured": {
+		ZH: "当前无法验证订阅（验证服务未配置），为避免订阅无效服务，已拒绝本次订阅。请从网页一键导入收藏。",
+		EN: "Subscriptions cannot be verified right now (validator not configured), so the request was rejected. Please import your favorites from the website.",
+		JA: "現在購読を検証できません（検証サービス未設定）。無効なサービスの購読を避けるため拒否しました。ウェブページからお気に入りをインポートしてください。",
+		RU: "Сейчас невозможно проверить подписку (валидатор не настроен), поэтому запрос отклонён. Импортируйте избранное с сайта.",
+	},
+	"add.validator_unavailable": {
+		ZH: "当前无法验证订阅（状态服务暂不可用），为避免订阅无效服务，已拒绝本次订阅。请稍后再试，或从网页一键导入收藏。",
+		EN: "Subscriptions cannot be verified right now (status service unavailable), so the request was rejected. Please try again later or import your favorites from the website.",
+		JA: "現在購読を検証できません（ステータスサービス利用不可）。無効なサービスの購読を避けるため拒否しました。しばらくしてから再試行するか、ウェブページからインポートしてください。",
+		RU: "Сейчас невозможно проверить подписку (сервис статусов недоступен), поэтому запрос отклонён. Повторите позже или импортируйте избранное с сайта.",
+	},
+	"add.quota_provider": {
+		ZH: "订阅配额不足。<b>%s</b> 有 %d 个订阅项，当前已用 %d/%d。\n\n请先移除部分订阅，或精确指定 service/channel。",
+		EN: "Not enough quota. <b>%s</b> has %d entries, you are using %d/%d.\n\nRemove some subscriptions first, or specify service/channel.",
+		JA: "購読枠が不足しています。<b>%s</b> には %d 件の項目があり、現在 %d/%d を使用中です。\n\n先に一部の購読を削除するか、service/channel を指定してください。",
+		RU: "Недостаточно квоты. У <b>%s</b> записей: %d, использовано %d/%d.\n\nСначала удалите часть подписок или укажите service/channel.",
+	},
+	"add.quota_service": {
+		ZH: "订阅配额不足。<b>%s / %s</b> 有 %d 个订阅项，当前已用 %d/%d。\n\n请先移除部分订阅，或精确指定 channel。",
+		EN: "Not enough quota. <b>%s / %s</b> has %d entries, you are using %d/%d.\n\nRemove some subscriptions first, or specify a channel.",
+		JA: "購読枠が不足しています。<b>%s / %s</b> には %d 件の項目があり、現在 %d/%d を使用中です。\n\n先に一部の購読を削除するか、channel を指定してください。",
+		RU: "Недостаточно квоты. У <b>%s / %s</b> записей: %d, использовано %d/%d.\n\nСначала удалите часть подписок или укажите channel.",
+	},
+	"add.added_provider": {
+		ZH: "已添加 <b>%d</b> 个订阅（%s 下所有服务通道）",
+		EN: "Added <b>%d</b> subscriptions (all services and channels of %s)",
+		JA: "<b>%d</b> 件の購読を追加しました（%s のすべてのサービス・チャネル）",
+		RU: "Добавлено подписок: <b>%d</b> (все сервисы и каналы %s)",
+	},
+	"add.added_service": {
+		ZH: "已添加 <b>%d</b> 个订阅（%s / %s 下所有通道）",
+		EN: "Added <b>%d</b> subscriptions (all channels of %s / %s)",
+		JA: "<b>%d</b> 件の購読を追加しました（%s / %s のすべてのチャネル）",
+		RU: "Добавлено подписок: <b>%d</b> (все каналы %s / %s)",
+	},
+	"add.limit_reached": {
+		ZH: "订阅数量已达上限（%d/%d）。请先移除部分订阅。",
+		EN: "Subscription limit reached (%d/%d). Remove some subscriptions first.",
+		JA: "購読数が上限に達しました（%d/%d）。先に一部の購読を削除してください。",
+		RU: "Достигнут лимит подписок (%d/%d). Сначала удалите часть подписок.",
+	},
+	"add.added_exact": {
+		ZH: "已订阅 <b>%s / %s / %s</b>",
+		EN: "Subscribed to <b>%s / %s / %s</b>",
+		JA: "<b>%s / %s / %s</b> を購読しました",
+		RU: "Подписка оформлена: <b>%s / %s / %s</b>",
+	},
+	"add.only_suffix": {
+		ZH: "\n仅接收: %s",
+		EN: "\nOnly: %s",
+		JA: "\n受信対象: %s のみ",
+		RU: "\nТолько: %s",
+	},
+
+	// ===== 冷板 / 未找到 =====
+	"cold.channel": {
+		ZH: "🚫 <b>%s / %s / %s</b> 已被移入冷板（board=cold），当前不支持订阅通知。",
+		EN: "🚫 <b>%s / %s / %s</b> has been moved to the cold board (board=cold) and does not support notifications.",
+		JA: "🚫 <b>%s / %s / %s</b> はコールドボード（board=cold）に移動されたため、通知を購読できません。",
+		RU: "🚫 <b>%s / %s / %s</b> перемещён на «холодную» доску (board=cold) и не поддерживает уведомления.",
+	},
+	"cold.service": {
+		ZH: "🚫 <b>%s / %s</b> 当前无可订阅的热板监测项（均为冷板）。",
+		EN: "🚫 <b>%s / %s</b> has no subscribable hot-board monitors (all are cold).",
+		JA: "🚫 <b>%s / %s</b> には購読可能なホットボードの監視項目がありません（すべてコールド）。",
+		RU: "🚫 У <b>%s / %s</b> нет доступных для подписки мониторов (все на «холодной» доске).",
+	},
+	"cold.provider": {
+		ZH: "🚫 <b>%s</b> 当前无可订阅的热板监测项（均为冷板）。",
+		EN: "🚫 <b>%s</b> has no subscribable hot-board monitors (all are cold).",
+		JA: "🚫 <b>%s</b> には購読可能なホットボードの監視項目がありません（すべてコールド）。",
+		RU: "🚫 У <b>%s</b> нет доступных для подписки мониторов (все на «холодной» доске).",
+	},
+	"cold.generic": {
+		ZH: "🚫 目标已被移入冷板（board=cold），当前不支持订阅通知。",
+		EN: "🚫 The target has been moved to the cold board (board=cold) and does not support notifications.",
+		JA: "🚫 対象はコールドボード（board=cold）に移動されたため、通知を購読できません。",
+		RU: "🚫 Цель перемещена на «холодную» доску (board=cold) и не поддерживает уведомления.",
+	},
+	"notfound.provider": {
+		ZH: "未找到服务商 <b>%s</b>。\n\n请到 RelayPulse 网页复制正确的 provider/service/channel，或使用网页一键导入收藏。",
+		EN: "Provider <b>%s</b> not found.\n\nCopy the correct provider/service/channel from the RelayPulse website, or import your favorites from there.",
+		JA: "プロバイダー <b>%s</b> が見つかりません。\n\nRelayPulse のウェブページで正しい provider/service/channel を確認するか、お気に入りを一括インポートしてください。",
+		RU: "Провайдер <b>%s</b> не найден.\n\nСкопируйте правильные provider/service/channel с сайта RelayPulse или импортируйте избранное оттуда.",
+	},
+	"notfound.service_candidates": {
+		ZH: "未找到 <b>%s / %s</b>。\n\n该服务商下可用的 service 例如：<b>%s</b>（仅显示前 %d 个）。",
+		EN: "<b>%s / %s</b> not found.\n\nAvailable services include: <b>%s</b> (first %d shown).",
+		JA: "<b>%s / %s</b> が見つかりません。\n\n利用可能な service の例：<b>%s</b>（先頭 %d 件のみ表示）。",
+		RU: "<b>%s / %s</b> не найден.\n\nДоступные service, например: <b>%s</b> (показаны первые %d).",
+	},
+	"notfound.service": {
+		ZH: "未找到 <b>%s / %s</b>。\n\n请到 RelayPulse 网页确认 service 是否正确，或使用网页一键导入收藏。",
+		EN: "<b>%s / %s</b> not found.\n\nCheck the service name on the RelayPulse website, or import your favorites from there.",
+		JA: "<b>%s / %s</b> が見つかりません。\n\nRelayPulse のウェブページで service 名を確認するか、お気に入りを一括インポートしてください。",
+		RU: "<b>%s / %s</b> не найден.\n\nПроверьте название service на сайте RelayPulse или импортируйте избранное оттуда.",
+	},
+	"notfound.channel_candidates": {
+		ZH: "未找到 <b>%s / %s / %s</b>。\n\n该 service 下可用的 channel 例如：<b>%s</b>（仅显示前 %d 个）。",
+		EN: "<b>%s / %s / %s</b> not found.\n\nAvailable channels include: <b>%s</b> (first %d shown).",
+		JA: "<b>%s / %s / %s</b> が見つかりません。\n\n利用可能な channel の例：<b>%s</b>（先頭 %d 件のみ表示）。",
+		RU: "<b>%s / %s / %s</b> не найден.\n\nДоступные channel, например: <b>%s</b> (показаны первые %d).",
+	},
+	"notfound.channel": {
+		ZH: "未找到 <b>%s / %s / %s</b>。",
+		EN: "<b>%s / %s / %s</b> not found.",
+		JA: "<b>%s / %s / %s</b> が見つかりません。",
+		RU: "<b>%s / %s / %s</b> не найден.",
+	},
+
+	// ===== /remove =====
+	"remove.usage": {
+		ZH: "用法: /remove <provider> [service] [channel]\n\n例如:\n/remove 88code → 移除 88code 所有订阅\n/remove 88code cc → 移除 88code 的 cc 服务订阅",
+		EN: "Usage: /remove <provider> [service] [channel]\n\nExamples:\n/remove 88code → remove all 88code subscriptions\n/remove 88code cc → remove the cc service of 88code",
+		JA: "使い方: /remove <provider> [service] [channel]\n\n例:\n/remove 88code → 88code のすべての購読を削除\n/remove 88code cc → 88code の cc サービスの購読を削除",
+		RU: "Использование: /remove <provider> [service] [channel]\n\nПримеры:\n/remove 88code → удалить все подписки 88code\n/remove 88code cc → удалить подписку на сервис cc у 88code",
+	},
+	"remove.provider": {
+		ZH: "已取消订阅 <b>%s / *</b>（包括该服务商下所有订阅）",
+		EN: "Unsubscribed from <b>%s / *</b> (all subscriptions of this provider)",
+		JA: "<b>%s / *</b> の購読を解除しました（このプロバイダーのすべての購読）",
+		RU: "Подписка отменена: <b>%s / *</b> (все подписки этого провайдера)",
+	},
+	"remove.service": {
+		ZH: "已取消订阅 <b>%s / %s</b>",
+		EN: "Unsubscribed from <b>%s / %s</b>",
+		JA: "<b>%s / %s</b> の購読を解除しました",
+		RU: "Подписка отменена: <b>%s / %s</b>",
+	},
+	"remove.exact": {
+		ZH: "已取消订阅 <b>%s / %s / %s</b>",
+		EN: "Unsubscribed from <b>%s / %s / %s</b>",
+		JA: "<b>%s / %s / %s</b> の購読を解除しました",
+		RU: "Подписка отменена: <b>%s / %s / %s</b>",
+	},
+
+	// ===== /filter =====
+	"filter.usage": {
+		ZH: "用法: /filter <provider> [service] [channel] <types>\n\n可选类型: %s\n\n例如:\n/filter 88code down → 88code 所有订阅仅接收 DOWN\n/filter 88code cc all → 恢复接收全部事件",
+		EN: "Usage: /filter <provider> [service] [channel] <types>\n\nTypes: %s\n\nExamples:\n/filter 88code down → only DOWN for all 88code subscriptions\n/filter 88code cc all → receive all events again",
+		JA: "使い方: /filter <provider> [service] [channel] <types>\n\n指定可能な種別: %s\n\n例:\n/filter 88code down → 88code のすべての購読で DOWN のみ受信\n/filter 88code cc all → すべてのイベントの受信に戻す",
+		RU: "Использование: /filter <provider> [service] [channel] <types>\n\nТипы: %s\n\nПримеры:\n/filter 88code down → только DOWN для всех подписок 88code\n/filter 88code cc all → снова получать все события",
+	},
+	"filter.invalid": {
+		ZH: "无法识别的事件类型（可选: %s）",
+		EN: "Unknown event type (available: %s)",
+		JA: "不明なイベント種別です（指定可能: %s）",
+		RU: "Неизвестный тип события (доступны: %s)",
+	},
+	"filter.not_found": {
+		ZH: "未找到匹配的订阅。使用 /list 查看当前订阅。",
+		EN: "No matching subscriptions. Use /list to see your subscriptions.",
+		JA: "一致する購読がありません。/list で購読一覧を確認してください。",
+		RU: "Подходящих подписок нет. Используйте /list, чтобы их просмотреть.",
+	},
+	"filter.updated_all": {
+		ZH: "已更新 <b>%d</b> 个订阅：接收全部事件",
+		EN: "Updated <b>%d</b> subscriptions: receiving all events",
+		JA: "<b>%d</b> 件の購読を更新しました：すべてのイベントを受信",
+		RU: "Обновлено подписок: <b>%d</b> — все события",
+	},
+	"filter.updated_only": {
+		ZH: "已更新 <b>%d</b> 个订阅：仅接收 %s",
+		EN: "Updated <b>%d</b> subscriptions: only %s",
+		JA: "<b>%d</b> 件の購読を更新しました：%s のみ受信",
+		RU: "Обновлено подписок: <b>%d</b> — только %s",
+	},
+
+	// ===== /clear =====
+	"clear.done": {
+		ZH: "已清空所有订阅。",
+		EN: "All subscriptions removed.",
+		JA: "すべての購読を削除しました。",
+		RU: "Все подписки удалены.",
+	},
+
+	// ===== /status =====
+	"status.telegram": {
+		ZH: "<b>服务状态</b>\n\n订阅数量: %d/%d\n服务版本: %s\n状态: 运行中 ✅\n\n数据源: %s",
+		EN: "<b>Service status</b>\n\nSubscriptions: %d/%d\nVersion: %s\nStatus: running ✅\n\nData source: %s",
+		JA: "<b>サービスステータス</b>\n\n購読数: %d/%d\nバージョン: %s\nステータス: 稼働中 ✅\n\nデータソース: %s",
+		RU: "<b>Статус сервиса</b>\n\nПодписки: %d/%d\nВерсия: %s\nСтатус: работает ✅\n\nИсточник данных: %s",
+	},
+	"status.title": {
+		ZH: "服务状态",
+		EN: "Service status",
+		JA: "サービスステータス",
+		RU: "Статус сервиса",
+	},
+	"status.count_limited": {
+		ZH: "订阅数量: %d/%d",
+		EN: "Subscriptions: %d/%d",
+		JA: "購読数: %d/%d",
+		RU: "Подписки: %d/%d",
+	},
+	"status.count": {
+		ZH: "订阅数量: %d",
+		EN: "Subscriptions: %d",
+		JA: "購読数: %d",
+		RU: "Подписки: %d",
+	},
+	"status.running": {
+		ZH: "状态: 运行中",
+		EN: "Status: running",
+		JA: "ステータス: 稼働中",
+		RU: "Статус: работает",
+	},
+	"status.source": {
+		ZH: "数据源: %s",
+		EN: "Data source: %s",
+		JA: "データソース: %s",
+		RU: "Источник данных: %s",
+	},
+
+	// ===== /help =====
+	"help.telegram": {
+		ZH: `<b>RelayPulse 通知 Bot 帮助</b>
+
+<b>命令列表：</b>
+/start - 开始使用 / 导入收藏
+/list - 查看当前订阅
+/add <provider> [service] [channel] - 添加订阅
+/remove <provider> [service] [channel] - 移除订阅
+/filter <provider> [service] [channel] <types> - 设置接收的事件类型
+/clear - 清空所有订阅
+/snap - 截图订阅服务状态
+/status - 查看服务状态
+/lang [code] - 切换语言（zh/en/ja/ru/auto）
+/digest [daily|weekly|off] [时刻] - 每日/每周摘要
+/export - 导出订阅（迁移/备份）
+/import <令牌|JSON> - 导入订阅
+/link [令牌|prefer|off] - 关联 Telegram 与 QQ 会话，同一事件只通知一次
+/role [成员] [角色] - 查看或分配群组角色
+/pause [时长] - 暂停通知（如 2h、3d，不指定则直到 /resume）
+/resume - 恢复通知
+/stats - 查看通知统计（近 24 小时/7 天）
+/announcements [on|off] - 接收或退订 RelayPulse 公告
+/help - 显示此帮助
+
+<b>快速开始：</b>
+1. 访问 RelayPulse 网站
+2. 收藏你关注的服务
+3. 点击"订阅通知"按钮
+4. 跳转到此 Bot 自动导入
+
+<b>手动添加订阅：</b>
+/add 88code → 订阅 88code 所有服务
+/add 88code cc → 订阅 88code 的 cc 服务
+/add duckcoding cc v1 → 精确订阅
+/add 88code cc --only=down → 仅接收 DOWN 事件
+
+<b>事件类型过滤：</b>
+/filter 88code down,up → 88code 所有订阅仅接收 DOWN/UP
+/filter 88code cc all → 恢复接收全部事件
+可选类型：down, up, cert, degraded, all
+
+<b>移除订阅：</b>
+/remove 88code → 移除 88code 所有订阅
+/remove 88code cc → 移除 88code 的 cc 订阅
+
+<b>内联查询：</b>
+在任意聊天输入 @机器人用户名 88code [cc] 即可查看当前状态（无需订阅）`,
+		EN: `<b>RelayPulse Notification Bot help</b>
+
+<b>Commands:</b>
+/start - Get started / import favorites
+/list - Show subscriptions
+/add <provider> [service] [channel] - Add a subscription
+/remove <provider> [service] [channel] - Remove a subscription
+/filter <provider> [service] [channel] <types> - Choose event types to receive
+/clear - Remove all subscriptions
+/snap - Screenshot of subscribed services
+/status - Bot status
+/lang [code] - Change language (zh/en/ja/ru/auto)
+/digest [daily|weekly|off] [hour] - Daily/weekly digest
+/export - Export subscriptions (migrate/back up)
+/import <token|JSON> - Import subscriptions
+/link [token|prefer|off] - Link Telegram and QQ chats to get each event once
+/role [member] [role] - Show or assign group roles
+/pause [duration] - Pause notifications (e.g. 2h, 3d; until /resume if omitted)
+/resume - Resume notifications
+/stats - Notification stats (last 24h/7d)
+/announcements [on|off] - Receive or opt out of RelayPulse announcements
+/help - Show this help
+
+<b>Quick start:</b>
+1. Open the RelayPulse website
+2. Add the services you care about to favorites
+3. Click "Subscribe"
+4. You will be redirected here and the favorites are imported
+
+<b>Add subscriptions manually:</b>
+/add 88code → all services of 88code
+/add 88code cc → the cc service of 88code
+/add duckcoding cc v1 → an exact channel
+/add 88code cc --only=down → DOWN events only
+
+<b>Event type filters:</b>
+/filter 88code down,up → only DOWN/UP for all 88code subscriptions
+/filter 88code cc all → receive all events again
+Types: down, up, cert, degraded, all
+
+<b>Remove subscriptions:</b>
+/remove 88code → remove all 88code subscriptions
+/remove 88code cc → remove the cc service of 88code
+
+<b>Inline mode:</b>
+Type @bot_username 88code [cc] in any chat to check the current status (no subscription needed)`,
+		JA: `<b>RelayPulse 通知 Bot ヘルプ</b>
+
+<b>コマンド一覧：</b>
+/start - 利用開始 / お気に入りをインポート
+/list - 購読一覧を表示
+/add <provider> [service] [channel] - 購読を追加
+/remove <provider> [service] [channel] - 購読を削除
+/filter <provider> [service] [channel] <types> - 受信するイベント種別を設定
+/clear - すべての購読を削除
+/snap - 購読中サービスのスクリーンショット
+/status - Bot のステータス
+/lang [code] - 言語を切り替え（zh/en/ja/ru/auto）
+/digest [daily|weekly|off] [時] - 毎日/毎週のサマリー
+/export - 購読をエクスポート（移行/バックアップ）
+/import <トークン|JSON> - 購読をインポート
+/link [トークン|prefer|off] - Telegram と QQ のチャットをリンクし、同じイベントを 1 回だけ通知
+/role [メンバー] [ロール] - グループのロールを表示/割り当て
+/pause [期間] - 通知を一時停止（例: 2h、3d。省略時は /resume まで）
+/resume - 通知を再開
+/stats - 通知の統計（直近 24 時間/7 日）
+/announcements [on|off] - RelayPulse のお知らせの受信/停止
+/help - このヘルプを表示
+
+<b>クイックスタート：</b>
+1. RelayPulse のウェブサイトを開く
+2. 気になるサービスをお気に入りに追加
+3. 「通知を購読」ボタンをクリック
+4. この Bot に移動して自動インポート
+
+<b>手動で購読を追加：</b>
+/add 88code → 88code のすべてのサービスを購読
+/add 88code cc → 88code の cc サービスを購読
+/add duckcoding cc v1 → チャネルを指定して購読
+/add 88code cc --only=down → DOWN イベントのみ受信
+
+<b>イベント種別フィルター：</b>
+/filter 88code down,up → 88code のすべての購読で DOWN/UP のみ受信
+/filter 88code cc all → すべてのイベントの受信に戻す
+指定可能な種別：down, up, cert, degraded, all
+
+<b>購読の削除：</b>
+/remove 88code → 88code のすべての購読を削除
+/remove 88code cc → 88code の cc の購読を削除
+
+<b>インラインモード：</b>
+任意のチャットで @Bot ユーザー名 88code [cc] と入力すると現在のステータスを確認できます（購読不要）`,
+		RU: `<b>Справка RelayPulse Notification Bot</b>
+
+<b>Команды:</b>
+/start - Начать / импортировать избранное
+/list - Список подписок
+/add <provider> [service] [channel] - Добавить подписку
+/remove <provider> [service] [channel] - Удалить подписку
+/filter <provider> [service] [channel] <types> - Выбрать типы событий
+/clear - Удалить все подписки
+/snap - Скриншот статуса подписок
+/status - Статус бота
+/lang [code] - Сменить язык (zh/en/ja/ru/auto)
+/digest [daily|weekly|off] [час] - Ежедневная/еженедельная сводка
+/export - Экспорт подписок (перенос/резервная копия)
+/import <токен|JSON> - Импорт подписок
+/link [токен|prefer|off] - Связать чаты Telegram и QQ, чтобы получать событие один раз
+/role [участник] [роль] - Роли в группе
+/pause [длительность] - Приостановить уведомления (например 2h, 3d; без неё — до /resume)
+/resume - Возобновить уведомления
+/stats - Статистика уведомлений (24 ч/7 дней)
+/announcements [on|off] - Получать объявления RelayPulse или отказаться от них
+/help - Эта справка
+
+<b>Быстрый старт:</b>
+1. Откройте сайт RelayPulse
+2. Добавьте нужные сервисы в избранное
+3. Нажмите «Подписаться»
+4. Вы перейдёте в этого бота, и избранное импортируется
+
+<b>Добавить подписку вручную:</b>
+/add 88code → все сервисы 88code
+/add 88code cc → сервис cc у 88code
+/add duckcoding cc v1 → конкретный канал
+/add 88code cc --only=down → только события DOWN
+
+<b>Фильтр типов событий:</b>
+/filter 88code down,up → только DOWN/UP для всех подписок 88code
+/filter 88code cc all → снова получать все события
+Типы: down, up, cert, degraded, all
+
+<b>Удалить подписки:</b>
+/remove 88code → удалить все подписки 88code
+/remove 88code cc → удалить подписку на cc у 88code
+
+<b>Инлайн-режим:</b>
+Введите @имя_бота 88code [cc] в любом чате, чтобы узнать текущий статус (без подписки)`,
+	},
+	"help.qq": {
+		ZH: `RelayPulse QQ 通知帮助
+
+命令列表：
+/list - 查看当前订阅
+/add <provider> [service] [channel] - 添加订阅
+/remove <provider> [service] [channel] - 移除订阅
+/filter <provider> [service] [channel] <types> - 设置接收的事件类型
+/clear - 清空所有订阅
+/snap - 截图订阅服务状态
+/status - 查看服务状态
+/lang [code] - 切换语言（zh/en/ja/ru/auto）
+/digest [daily|weekly|off] [时刻] - 每日/每周摘要
+/export - 导出订阅（迁移/备份）
+/import <令牌|JSON> - 导入订阅
+/link [令牌|prefer|off] - 关联 Telegram 与 QQ 会话，同一事件只通知一次
+/role [成员] [角色] - 查看或分配群组角色
+/pause [时长] - 暂停通知（如 2h、3d，不指定则直到 /resume）
+/resume - 恢复通知
+/stats - 查看通知统计（近 24 小时/7 天）
+/announcements [on|off] - 接收或退订 RelayPulse 公告
+/help - 显示此帮助
+
+手动添加订阅：
+/add 88code → 订阅 88code 所有服务
+/add 88code cc → 订阅 88code 的 cc 服务
+/add duckcoding cc v1 → 精确订阅
+/add 88code cc --only=down → 仅接收 DOWN 事件
+
+事件类型过滤：
+/filter 88code down,up → 88code 所有订阅仅接收 DOWN/UP
+/filter 88code cc all → 恢复接收全部事件
+可选类型：down, up, cert, degraded, all
+
+移除订阅：
+/remove 88code → 移除 88code 所有订阅
+/remove 88code cc → 移除 88code 的 cc 订阅
+
+全局指令（群聊无需@）：
+状态检查 - 快速截图订阅服务状态
+
+权限说明：
+1) 群聊：editor 及以上角色可执行 /add /remove /filter /clear /lang /digest /export /import /link /pause /resume /announcements，owner 可用 /role 分配角色；群主/管理员默认为 owner，其他成员为 viewer
+2) 私聊：好友可直接使用所有命令`,
+		EN: `RelayPulse QQ notification help
+
+Commands:
+/list - Show subscriptions
+/add <provider> [service] [channel] - Add a subscription
+/remove <provider> [service] [channel] - Remove a subscription
+/filter <provider> [service] [channel] <types> - Choose event types to receive
+/clear - Remove all subscriptions
+/snap - Screenshot of subscribed services
+/status - Bot status
+/lang [code] - Change language (zh/en/ja/ru/auto)
+/digest [daily|weekly|off] [hour] - Daily/weekly digest
+/export - Export subscriptions (migrate/back up)
+/import <token|JSON> - Import subscriptions
+/link [token|prefer|off] - Link Telegram and QQ chats to get each event once
+/role [member] [role] - Show or assign group roles
+/pause [duration] - Pause notifications (e.g. 2h, 3d; until /resume if omitted)
+/resume - Resume notifications
+/stats - Notification stats (last 24h/7d)
+/announcements [on|off] - Receive or opt out of RelayPulse announcements
+/help - Show this help
+
+Add subscriptions manually:
+/add 88code → all services of 88code
+/add 88code cc → the cc service of 88code
+/add duckcoding cc v1 → an exact channel
+/add 88code cc --only=down → DOWN events only
+
+Event type filters:
+/filter 88code down,up → only DOWN/UP for all 88code subscriptions
+/filter 88code cc all → receive all events again
+Types: down, up, cert, degraded, all
+
+Remove subscriptions:
+/remove 88code → remove all 88code subscriptions
+/remove 88code cc → remove the cc service of 88code
+
+Global keyword (no @ needed in groups):
+状态检查 - quick screenshot of subscribed services
+
+Permissions:
+1) Groups: editors and owners can run /add /remove /filter /clear /lang /digest /export /import /link /pause /resume /announcements; owners assign roles with /role. Group owners/admins are owners by default, other members are viewers
+2) Private chats: friends can use all commands`,
+		JA: `RelayPulse QQ 通知ヘルプ
+
+コマンド一覧：
+/list - 購読一覧を表示
+/add <provider> [service] [channel] - 購読を追加
+/remove <provider> [service] [channel] - 購読を削除
+/filter <provider> [service] [channel] <types> - 受信するイベント種別を設定
+/clear - すべての購読を削除
+/snap - 購読中サービスのスクリーンショット
+/status - Bot のステータス
+/lang [code] - 言語を切り替え（zh/en/ja/ru/auto）
+/digest [daily|weekly|off] [時] - 毎日/毎週のサマリー
+/export - 購読をエクスポート（移行/バックアップ）
+/import <トークン|JSON> - 購読をインポート
+/link [トークン|prefer|off] - Telegram と QQ のチャットをリンクし、同じイベントを 1 回だけ通知
+/role [メンバー] [ロール] - グループのロールを表示/割り当て
+/pause [期間] - 通知を一時停止（例: 2h、3d。省略時は /resume まで）
+/resume - 通知を再開
+/stats - 通知の統計（直近 24 時間/7 日）
+/announcements [on|off] - RelayPulse のお知らせの受信/停止
+/help - このヘルプを表示
+
+手動で購読を追加：
+/add 88code → 88code のすべてのサービスを購読
+/add 88code cc → 88code の cc サービスを購読
+/add duckcoding cc v1 → チャネルを指定して購読
+/add 88code cc --only=down → DOWN イベントのみ受信
+
+イベント種別フィルター：
+/filter 88code down,up → 88code のすべての購読で DOWN/UP のみ受信
+/filter 88code cc all → すべてのイベントの受信に戻す
+指定可能な種別：down, up, cert, degraded, all
+
+購読の削除：
+/remove 88code → 88code のすべての購読を削除
+/remove 88code cc → 88code の cc の購読を削除
+
+グローバルキーワード（グループで @ 不要）：
+状态检查 - 購読中サービスのスクリーンショット
+
+権限：
+1) グループ：editor 以上のロールが /add /remove /filter /clear /lang /digest /export /import /link /pause /resume /announcements を実行可能。owner は /role でロールを割り当て可能。グループのオーナー/管理者は既定で owner、その他のメンバーは viewer
+2) 個人チャット：友だちはすべてのコマンドを利用可能`,
+		RU: `Справка RelayPulse QQ
+
+Команды:
+/list - Список подписок
+/add <provider> [service] [channel] - Добавить подписку
+/remove <provider> [service] [channel] - Удалить подписку
+/filter <provider> [service] [channel] <types> - Выбрать типы событий
+/clear - Удалить все подписки
+/snap - Скриншот статуса подписок
+/status - Статус бота
+/lang [code] - Сменить язык (zh/en/ja/ru/auto)
+/digest [daily|weekly|off] [час] - Ежедневная/еженедельная сводка
+/export - Экспорт подписок (перенос/резервная копия)
+/import <токен|JSON> - Импорт подписок
+/link [токен|prefer|off] - Связать чаты Telegram и QQ, чтобы получать событие один раз
+/role [участник] [роль] - Роли в группе
+/pause [длительность] - Приостановить уведомления (например 2h, 3d; без неё — до /resume)
+/resume - Возобновить уведомления
+/stats - Статистика уведомлений (24 ч/7 дней)
+/announcements [on|off] - Получать объявления RelayPulse или отказаться от них
+/help - Эта справка
+
+Добавить подписку вручную:
+/add 88code → все сервисы 88code
+/add 88code cc → сервис cc у 88code
+/add duckcoding cc v1 → конкретный канал
+/add 88code cc --only=down → только события DOWN
+
+Фильтр типов событий:
+/filter 88code down,up → только DOWN/UP для всех подписок 88code
+/filter 88code cc all → снова получать все события
+Типы: down, up, cert, degraded, all
+
+Удалить подписки:
+/remove 88code → удалить все подписки 88code
+/remove 88code cc → удалить подписку на cc у 88code
+
+Глобальное ключевое слово (в группах без @):
+状态检查 - быстрый скриншот статуса подписок
+
+Права:
+1) Группы: /add /remove /filter /clear /lang /digest /export /import /link /pause /resume /announcements доступны ролям editor и owner; owner назначает роли через /role. Владелец и администраторы группы по умолчанию owner, остальные — viewer
+2) Личные чаты: друзьям доступны все команды`,
+	},
+
+	// ===== /snap =====
+	"snap.disabled": {
+		ZH: "截图功能未启用。",
+		EN: "Screenshots are not enabled.",
+		JA: "スクリーンショット機能は無効です。",
+		RU: "Скриншоты отключены.",
+	},
+	"snap.fetch_failed": {
+		ZH: "获取订阅信息失败，请稍后重试。",
+		EN: "Failed to load subscriptions. Please try again later.",
+		JA: "購読情報の取得に失敗しました。しばらくしてから再試行してください。",
+		RU: "Не удалось загрузить подписки. Повторите попытку позже.",
+	},
+	"snap.empty": {
+		ZH: "你还没有订阅任何服务。\n\n使用 /add 添加订阅后再试。",
+		EN: "You have no subscriptions yet.\n\nAdd some with /add and try again.",
+		JA: "まだ購読しているサービスはありません。\n\n/add で購読を追加してから再試行してください。",
+		RU: "У вас пока нет подписок.\n\nДобавьте их через /add и повторите попытку.",
+	},
+	"snap.generating": {
+		ZH: "正在生成 [%s专属] 的状态截图...",
+		EN: "Generating a status screenshot for [%s]...",
+		JA: "[%s] 専用のステータススクリーンショットを生成しています...",
+		RU: "Создаю скриншот статуса для [%s]...",
+	},
+	"snap.title": {
+		ZH: "%s 专属状态",
+		EN: "Status for %s",
+		JA: "%s 専用ステータス",
+		RU: "Статус для %s",
+	},
+	"snap.owner_you": {
+		ZH: "你",
+		EN: "you",
+		JA: "あなた",
+		RU: "вас",
+	},
+	"snap.owner_group": {
+		ZH: "群%d",
+		EN: "group %d",
+		JA: "グループ %d",
+		RU: "группы %d",
+	},
+	"snap.timeout": {
+		ZH: "截图超时，请稍后重试。",
+		EN: "Screenshot timed out. Please try again later.",
+		JA: "スクリーンショットがタイムアウトしました。しばらくしてから再試行してください。",
+		RU: "Время создания скриншота истекло. Повторите попытку позже.",
+	},
+	"snap.busy": {
+		ZH: "系统繁忙，请稍后重试。",
+		EN: "The system is busy. Please try again later.",
+		JA: "システムが混み合っています。しばらくしてから再試行してください。",
+		RU: "Система занята. Повторите попытку позже.",
+	},
+	"snap.failed": {
+		ZH: "截图生成失败，请稍后重试。",
+		EN: "Failed to generate the screenshot. Please try again later.",
+		JA: "スクリーンショットの生成に失敗しました。しばらくしてから再試行してください。",
+		RU: "Не удалось создать скриншот. Повторите попытку позже.",
+	},
+	"snap.send_failed": {
+		ZH: "截图生成成功，但发送失败。请稍后重试。",
+		EN: "The screenshot was generated but could not be sent. Please try again later.",
+		JA: "スクリーンショットは生成されましたが、送信に失敗しました。しばらくしてから再試行してください。",
+		RU: "Скриншот создан, но отправить его не удалось. Повторите попытку позже.",
+	},
+
+	// ===== /lang =====
+	"lang.current": {
+		ZH: "当前语言: %s\n\n用法: /lang <code>\n可选: %s\n/lang auto → 恢复默认（跟随客户端语言）",
+		EN: "Current language: %s\n\nUsage: /lang <code>\nAvailable: %s\n/lang auto → reset to default (follow client language)",
+		JA: "現在の言語: %s\n\n使い方: /lang <code>\n指定可能: %s\n/lang auto → 既定に戻す（クライアントの言語に従う）",
+		RU: "Текущий язык: %s\n\nИспользование: /lang <code>\nДоступны: %s\n/lang auto → сбросить (язык клиента)",
+	},
+	"lang.set": {
+		ZH: "语言已切换为 %s。",
+		EN: "Language set to %s.",
+		JA: "言語を %s に切り替えました。",
+		RU: "Язык изменён: %s.",
+	},
+	"lang.reset": {
+		ZH: "已恢复默认语言设置（当前: %s）。",
+		EN: "Language preference reset (now: %s).",
+		JA: "言語設定を既定に戻しました（現在: %s）。",
+		RU: "Настройка языка сброшена (сейчас: %s).",
+	},
+	"lang.invalid": {
+		ZH: "不支持的语言: %s\n\n可选: %s",
+		EN: "Unsupported language: %s\n\nAvailable: %s",
+		JA: "未対応の言語です: %s\n\n指定可能: %s",
+		RU: "Язык не поддерживается: %s\n\nДоступны: %s",
+	},
+
+	// ===== 定期摘要 =====
+	"digest.disabled": {
+		ZH: "定期摘要功能未启用。",
+		EN: "Scheduled digests are not enabled.",
+		JA: "定期サマリーは有効になっていません。",
+		RU: "Регулярные сводки не включены.",
+	},
+	"digest.usage": {
+		ZH: "用法：\n/digest daily [时刻] - 每天发送摘要\n/digest weekly [时刻] - 每周一发送摘要\n/digest off - 关闭摘要\n\n时刻为 0-23（UTC+8），默认 %d 点。",
+		EN: "Usage:\n/digest daily [hour] - send a digest every day\n/digest weekly [hour] - send a digest every Monday\n/digest off - turn digests off\n\nHour is 0-23 (UTC+8), default %d.",
+		JA: "使い方：\n/digest daily [時] - 毎日サマリーを送信\n/digest weekly [時] - 毎週月曜日にサマリーを送信\n/digest off - サマリーを停止\n\n時は 0-23（UTC+8）、既定は %d 時です。",
+		RU: "Использование:\n/digest daily [час] - сводка каждый день\n/digest weekly [час] - сводка каждый понедельник\n/digest off - отключить сводки\n\nЧас 0-23 (UTC+8), по умолчанию %d.",
+	},
+	"digest.status_off": {
+		ZH: "定期摘要: 未开启",
+		EN: "Scheduled digest: off",
+		JA: "定期サマリー: オフ",
+		RU: "Регулярная сводка: выключена",
+	},
+	"digest.status_on": {
+		ZH: "定期摘要: %s %02d:00 (UTC+8)",
+		EN: "Scheduled digest: %s at %02d:00 (UTC+8)",
+		JA: "定期サマリー: %s %02d:00 (UTC+8)",
+		RU: "Регулярная сводка: %s в %02d:00 (UTC+8)",
+	},
+	"digest.freq_daily": {
+		ZH: "每天",
+		EN: "daily",
+		JA: "毎日",
+		RU: "ежедневно",
+	},
+	"digest.freq_weekly": {
+		ZH: "每周一",
+		EN: "every Monday",
+		JA: "毎週月曜日",
+		RU: "по понедельникам",
+	},
+	"digest.set": {
+		ZH: "✅ 已开启定期摘要：%s %02d:00 (UTC+8) 发送订阅服务的可用率、故障次数与延迟趋势。",
+		EN: "✅ Scheduled digest enabled: %s at %02d:00 (UTC+8) with uptime, incidents and latency trend of your subscriptions.",
+		JA: "✅ 定期サマリーを有効にしました：%s %02d:00 (UTC+8) に購読サービスの稼働率・障害回数・レイテンシ傾向を送信します。",
+		RU: "✅ Регулярная сводка включена: %s в %02d:00 (UTC+8) — доступность, число сбоев и динамика задержки по подпискам.",
+	},
+	"digest.off": {
+		ZH: "已关闭定期摘要。",
+		EN: "Scheduled digest turned off.",
+		JA: "定期サマリーを停止しました。",
+		RU: "Регулярная сводка отключена.",
+	},
+	"digest.invalid_hour": {
+		ZH: "时刻无效: %s（应为 0-23）",
+		EN: "Invalid hour: %s (expected 0-23)",
+		JA: "時刻が無効です: %s（0-23 で指定してください）",
+		RU: "Неверный час: %s (ожидается 0-23)",
+	},
+	"digest.title_daily": {
+		ZH: "📊 <b>每日摘要</b>",
+		EN: "📊 <b>Daily digest</b>",
+		JA: "📊 <b>デイリーサマリー</b>",
+		RU: "📊 <b>Ежедневная сводка</b>",
+	},
+	"digest.title_weekly": {
+		ZH: "📊 <b>每周摘要</b>",
+		EN: "📊 <b>Weekly digest</b>",
+		JA: "📊 <b>ウィークリーサマリー</b>",
+		RU: "📊 <b>Еженедельная сводка</b>",
+	},
+	"digest.range": {
+		ZH: "统计区间: %s ~ %s",
+		EN: "Period: %s ~ %s (UTC+8)",
+		JA: "集計期間: %s ~ %s (UTC+8)",
+		RU: "Период: %s ~ %s (UTC+8)",
+	},
+	"digest.no_data": {
+		ZH: "订阅的监测项在此期间暂无数据。",
+		EN: "No data for your subscriptions in this period.",
+		JA: "この期間、購読中の監視項目にデータがありません。",
+		RU: "За этот период нет данных по подпискам.",
+	},
+	"digest.uptime": {
+		ZH: "可用率: <b>%s</b>",
+		EN: "Uptime: <b>%s</b>",
+		JA: "稼働率: <b>%s</b>",
+		RU: "Доступность: <b>%s</b>",
+	},
+	"digest.incidents": {
+		ZH: "故障次数: <b>%d</b>",
+		EN: "Incidents: <b>%d</b>",
+		JA: "障害回数: <b>%d</b>",
+		RU: "Сбоев: <b>%d</b>",
+	},
+	"digest.worst": {
+		ZH: "表现最差: %s（%s）",
+		EN: "Worst: %s (%s)",
+		JA: "最も不安定: %s（%s）",
+		RU: "Хуже всех: %s (%s)",
+	},
+	"digest.latency": {
+		ZH: "平均延迟: %d ms %s",
+		EN: "Avg latency: %d ms %s",
+		JA: "平均レイテンシ: %d ms %s",
+		RU: "Средняя задержка: %d мс %s",
+	},
+	"digest.trend_up": {
+		ZH: "↑ %d%%",
+		EN: "↑ %d%%",
+		JA: "↑ %d%%",
+		RU: "↑ %d%%",
+	},
+	"digest.trend_down": {
+		ZH: "↓ %d%%",
+		EN: "↓ %d%%",
+		JA: "↓ %d%%",
+		RU: "↓ %d%%",
+	},
+	"digest.trend_flat": {
+		ZH: "→ 持平",
+		EN: "→ stable",
+		JA: "→ 横ばい",
+		RU: "→ без изменений",
+	},
+	"digest.monitors": {
+		ZH: "<b>各监测项：</b>",
+		EN: "<b>Monitors:</b>",
+		JA: "<b>監視項目：</b>",
+		RU: "<b>Мониторы:</b>",
+	},
+	"digest.monitor_incidents": {
+		ZH: "故障 %d 次",
+		EN: "%d incidents",
+		JA: "障害 %d 回",
+		RU: "сбоев: %d",
+	},
+	"digest.more": {
+		ZH: "…另有 %d 项",
+		EN: "…and %d more",
+		JA: "…ほか %d 件",
+		RU: "…и ещё %d",
+	},
+	"digest.footer": {
+		ZH: "发送 /digest off 可关闭摘要",
+		EN: "Send /digest off to stop digests",
+		JA: "/digest off でサマリーを停止できます",
+		RU: "Отправьте /digest off, чтобы отключить сводки",
+	},
+
+	// ===== /export /import =====
+	"export.empty": {
+		ZH: "当前没有订阅，无需导出。",
+		EN: "You have no subscriptions to export.",
+		JA: "エクスポートする購読がありません。",
+		RU: "Нет подписок для экспорта.",
+	},
+	"export.header": {
+		ZH: "📦 已导出 %d 条订阅，令牌 %s（%d 分钟内有效）：\n• 在另一会话（Telegram 或 QQ）发送 /import %s 即可复制订阅\n• 也可保存下一条消息中的 JSON 作为备份，之后发送 /import <JSON> 还原\n• API：GET /api/subscriptions/export、POST /api/subscriptions/import，请求头 Authorization: Bearer <令牌>",
+		EN: "📦 Exported %d subscriptions. Token %s (valid for %d minutes):\n• Send /import %s in another chat (Telegram or QQ) to copy them\n• Or keep the JSON in the next message as a backup and restore it later with /import <JSON>\n• API: GET /api/subscriptions/export, POST /api/subscriptions/import with header Authorization: Bearer <token>",
+		JA: "📦 %d 件の購読をエクスポートしました。トークン %s（%d 分間有効）：\n• 別のチャット（Telegram または QQ）で /import %s を送信すると購読をコピーできます\n• 次のメッセージの JSON をバックアップとして保存し、後で /import <JSON> で復元することもできます\n• API：GET /api/subscriptions/export、POST /api/subscriptions/import（ヘッダー Authorization: Bearer <トークン>）",
+		RU: "📦 Экспортировано подписок: %d. Токен %s (действует %d мин.):\n• Отправьте /import %s в другом чате (Telegram или QQ), чтобы скопировать подписки\n• Или сохраните JSON из следующего сообщения как резервную копию и восстановите его командой /import <JSON>\n• API: GET /api/subscriptions/export, POST /api/subscriptions/import с заголовком Authorization: Bearer <токен>",
+	},
+	"import.usage": {
+		ZH: "用法：\n/import <令牌> - 复制 /export 所在会话的订阅\n/import <JSON> - 从 /export 导出的 JSON 还原订阅\n\n导入的订阅会合并到现有订阅，不会删除已有订阅。",
+		EN: "Usage:\n/import <token> - copy subscriptions from the chat where /export was sent\n/import <JSON> - restore subscriptions from JSON produced by /export\n\nImported subscriptions are merged into your current ones; nothing is removed.",
+		JA: "使い方：\n/import <トークン> - /export を実行したチャットの購読をコピー\n/import <JSON> - /export で出力した JSON から購読を復元\n\nインポートした購読は既存の購読に追加され、既存の購読は削除されません。",
+		RU: "Использование:\n/import <токен> - скопировать подписки из чата, где был выполнен /export\n/import <JSON> - восстановить подписки из JSON, полученного через /export\n\nИмпортированные подписки добавляются к текущим, ничего не удаляется.",
+	},
+	"import.token_invalid": {
+		ZH: "令牌无效或已过期，请在原会话重新发送 /export。",
+		EN: "The token is invalid or has expired. Send /export again in the original chat.",
+		JA: "トークンが無効か期限切れです。元のチャットで再度 /export を送信してください。",
+		RU: "Токен недействителен или истёк. Снова отправьте /export в исходном чате.",
+	},
+	"import.invalid": {
+		ZH: "导入数据无效: %s",
+		EN: "Invalid import data: %s",
+		JA: "インポートデータが無効です: %s",
+		RU: "Неверные данные для импорта: %s",
+	},
+	"import.done": {
+		ZH: "✅ 导入完成：新增/更新 %d 条，已存在 %d 条。",
+		EN: "✅ Import complete: %d added or updated, %d already present.",
+		JA: "✅ インポート完了：追加/更新 %d 件、既存 %d 件。",
+		RU: "✅ Импорт завершён: добавлено или обновлено %d, уже было %d.",
+	},
+	"import.skipped": {
+		ZH: "\n⚠️ 已达订阅上限 %d，跳过 %d 条。",
+		EN: "\n⚠️ Subscription limit %d reached, %d skipped.",
+		JA: "\n⚠️ 購読上限 %d に達したため、%d 件をスキップしました。",
+		RU: "\n⚠️ Достигнут лимит подписок %d, пропущено: %d.",
+	},
+
+	// ===== /link =====
+	"link.none": {
+		ZH: "当前会话未与其它平台关联。",
+		EN: "This chat is not linked to any other platform.",
+		JA: "このチャットは他のプラットフォームとリンクされていません。",
+		RU: "Этот чат не связан с другими платформами.",
+	},
+	"link.header": {
+		ZH: "🔗 <b>已关联 %d 个会话</b>（按投递顺序，同一事件只通知第一个可用会话）：\n",
+		EN: "🔗 <b>%d linked chats</b> (in delivery order; each event goes only to the first available chat):\n",
+		JA: "🔗 <b>%d 件のチャットをリンク済み</b>（配信順。同じイベントは最初に利用可能なチャットにのみ通知）：\n",
+		RU: "🔗 <b>Связано чатов: %d</b> (в порядке доставки; событие отправляется только в первый доступный чат):\n",
+	},
+	"link.current": {
+		ZH: "（当前会话）",
+		EN: " (this chat)",
+		JA: "（このチャット）",
+		RU: " (этот чат)",
+	},
+	"link.token": {
+		ZH: "在另一平台（Telegram 或 QQ）的会话中发送 /link %s 即可关联（%d 分钟内有效，仅可使用一次）。\n关联后，多个会话订阅了同一监测项时只会收到一条通知；发送 /link prefer 设为首选会话，/link off 取消关联。",
+		EN: "Send /link %s in a chat on the other platform (Telegram or QQ) to link it (valid for %d minutes, single use).\nOnce linked, you get one notification per event even if several chats subscribe to it. Send /link prefer to make a chat preferred, /link off to unlink.",
+		JA: "もう一方のプラットフォーム（Telegram または QQ）のチャットで /link %s を送信するとリンクされます（%d 分間有効、1 回のみ使用可能）。\nリンク後は、複数のチャットが同じ監視項目を購読していても通知は 1 件だけになります。/link prefer で優先チャットに設定、/link off でリンクを解除します。",
+		RU: "Отправьте /link %s в чате на другой платформе (Telegram или QQ), чтобы связать его (действует %d мин., одноразово).\nПосле связывания каждое событие приходит один раз, даже если на него подписаны несколько чатов. /link prefer — сделать чат основным, /link off — отвязать.",
+	},
+	"link.token_invalid": {
+		ZH: "关联令牌无效、已使用或已过期，请在原会话重新发送 /link。",
+		EN: "The link token is invalid, used or expired. Send /link again in the original chat.",
+		JA: "リンクトークンが無効、使用済み、または期限切れです。元のチャットで再度 /link を送信してください。",
+		RU: "Токен связывания недействителен, использован или истёк. Снова отправьте /link в исходном чате.",
+	},
+	"link.self": {
+		ZH: "不能将会话与自身关联，请在另一平台的会话中使用该令牌。",
+		EN: "A chat cannot be linked to itself. Use the token in a chat on the other platform.",
+		JA: "チャットを自分自身にリンクすることはできません。もう一方のプラットフォームのチャットでトークンを使用してください。",
+		RU: "Нельзя связать чат с самим собой. Используйте токен в чате на другой платформе.",
+	},
+	"link.done": {
+		ZH: "✅ 已关联，当前共 %d 个会话。同一事件只会通知首选会话，首选会话不可用时按顺序回退；发送 /link prefer 可将当前会话设为首选。",
+		EN: "✅ Linked. %d chats are now linked. Each event goes to the preferred chat and falls back in order if it is unavailable; send /link prefer to make this chat preferred.",
+		JA: "✅ リンクしました。現在 %d 件のチャットがリンクされています。イベントは優先チャットに通知され、利用できない場合は順にフォールバックします。/link prefer でこのチャットを優先に設定できます。",
+		RU: "✅ Связано. Всего связанных чатов: %d. Событие отправляется в основной чат, а при его недоступности — по порядку в следующие; отправьте /link prefer, чтобы сделать этот чат основным.",
+	},
+	"link.preferred": {
+		ZH: "✅ 已将当前会话设为首选，关联会话共同订阅的事件将优先通知这里。",
+		EN: "✅ This chat is now preferred; events subscribed by linked chats will be sent here first.",
+		JA: "✅ このチャットを優先に設定しました。リンクされたチャットが共通で購読しているイベントはここに優先して通知されます。",
+		RU: "✅ Этот чат теперь основной: события, на которые подписаны связанные чаты, будут приходить сюда.",
+	},
+	"link.unlinked": {
+		ZH: "已取消关联，当前会话将独立接收通知。",
+		EN: "Unlinked. This chat will receive notifications on its own.",
+		JA: "リンクを解除しました。このチャットは個別に通知を受け取ります。",
+		RU: "Связь удалена. Этот чат будет получать уведомления самостоятельно.",
+	},
+	"link.not_linked": {
+		ZH: "当前会话未与其它平台关联，发送 /link 获取关联令牌。",
+		EN: "This chat is not linked. Send /link to get a link token.",
+		JA: "このチャットはリンクされていません。/link を送信してリンクトークンを取得してください。",
+		RU: "Этот чат не связан. Отправьте /link, чтобы получить токен связывания.",
+	},
+
+	// ===== /role =====
+	"role.private": {
+		ZH: "私聊中你拥有全部权限，角色仅在群聊中生效。",
+		EN: "You have full access in private chats; roles only apply to groups.",
+		JA: "個人チャットではすべての権限があります。ロールはグループでのみ有効です。",
+		RU: "В личном чате у вас полный доступ; роли действуют только в группах.",
+	},
+	"role.usage": {
+		ZH: "用法：\n/role - 查看群内角色\n/role <用户ID> <角色|none> - 分配或移除角色（Telegram 可回复成员消息，QQ 可 @成员 代替用户ID）\n\n可选角色：%s\nowner：编辑订阅并分配角色\neditor：编辑订阅与会话设置\nviewer：仅查看\n群主/管理员默认为 owner，未分配角色的成员为 viewer。",
+		EN: "Usage:\n/role - show group roles\n/role <user_id> <role|none> - assign or remove a role (reply to a member's message on Telegram or @mention on QQ instead of the user ID)\n\nRoles: %s\nowner: edit subscriptions and assign roles\neditor: edit subscriptions and chat settings\nviewer: read only\nGroup owners/admins are owners by default; members without a role are viewers.",
+		JA: "使い方：\n/role - グループのロールを表示\n/role <ユーザーID> <ロール|none> - ロールを割り当て/削除（Telegram ではメンバーのメッセージに返信、QQ では @メンションでユーザーID の代わりに指定可能）\n\nロール：%s\nowner：購読の編集とロールの割り当て\neditor：購読とチャット設定の編集\nviewer：閲覧のみ\nグループのオーナー/管理者は既定で owner、ロールのないメンバーは viewer です。",
+		RU: "Использование:\n/role - роли в группе\n/role <user_id> <роль|none> - назначить или снять роль (в Telegram можно ответить на сообщение участника, в QQ — упомянуть через @ вместо ID)\n\nРоли: %s\nowner: изменение подписок и назначение ролей\neditor: изменение подписок и настроек чата\nviewer: только просмотр\nВладелец и администраторы группы по умолчанию owner; участники без роли — viewer.",
+	},
+	"role.list_empty": {
+		ZH: "👥 尚未分配角色。",
+		EN: "👥 No roles assigned yet.",
+		JA: "👥 まだロールが割り当てられていません。",
+		RU: "👥 Роли ещё не назначены.",
+	},
+	"role.list_header": {
+		ZH: "👥 群组角色（%d 人，群主/管理员默认为 owner）：\n",
+		EN: "👥 Group roles (%d members; group owners/admins are owners by default):\n",
+		JA: "👥 グループのロール（%d 人、グループのオーナー/管理者は既定で owner）：\n",
+		RU: "👥 Роли группы (%d; владелец и администраторы по умолчанию owner):\n",
+	},
+	"role.set": {
+		ZH: "✅ 已将 %d 设为 %s。",
+		EN: "✅ %d is now %s.",
+		JA: "✅ %d を %s に設定しました。",
+		RU: "✅ %d теперь %s.",
+	},
+	"role.removed": {
+		ZH: "已移除 %d 的角色。",
+		EN: "Removed the role of %d.",
+		JA: "%d のロールを削除しました。",
+		RU: "Роль %d снята.",
+	},
+
+	// ===== 内联查询（Telegram） =====
+	"inline.hint_title": {
+		ZH: "输入服务商名称查看当前状态",
+		EN: "Type a provider name to check its status",
+		JA: "プロバイダー名を入力して現在のステータスを確認",
+		RU: "Введите имя провайдера, чтобы узнать статус",
+	},
+	"inline.hint_desc": {
+		ZH: "例如：88code 或 88code cc",
+		EN: "e.g. 88code or 88code cc",
+		JA: "例：88code または 88code cc",
+		RU: "Например: 88code или 88code cc",
+	},
+	"inline.hint_message": {
+		ZH: "在任意聊天输入 @%s <provider> [service] 即可查看服务当前状态。",
+		EN: "Type @%s <provider> [service] in any chat to check the current status.",
+		JA: "任意のチャットで @%s <provider> [service] と入力すると現在のステータスを確認できます。",
+		RU: "Введите @%s <provider> [service] в любом чате, чтобы узнать текущий статус.",
+	},
+	"inline.unconfigured": {
+		ZH: "状态查询未配置",
+		EN: "Status lookup is not configured",
+		JA: "ステータス照会が設定されていません",
+		RU: "Запрос статуса не настроен",
+	},
+	"inline.unavailable": {
+		ZH: "状态服务暂时不可用，请稍后重试",
+		EN: "Status service is temporarily unavailable, please try again later",
+		JA: "ステータスサービスが一時的に利用できません。しばらくしてから再試行してください",
+		RU: "Сервис статуса временно недоступен, попробуйте позже",
+	},
+	"inline.not_found": {
+		ZH: "未找到: %s",
+		EN: "Not found: %s",
+		JA: "見つかりません: %s",
+		RU: "Не найдено: %s",
+	},
+	"inline.candidates": {
+		ZH: "可选: %s",
+		EN: "Available: %s",
+		JA: "候補: %s",
+		RU: "Доступно: %s",
+	},
+	"inline.empty": {
+		ZH: "暂无监测数据",
+		EN: "No monitoring data yet",
+		JA: "監視データがありません",
+		RU: "Нет данных мониторинга",
+	},
+	"inline.overview": {
+		ZH: "全部服务",
+		EN: "All services",
+		JA: "すべてのサービス",
+		RU: "Все сервисы",
+	},
+	"inline.summary": {
+		ZH: "正常 %d · 波动 %d · 不可用 %d",
+		EN: "Up %d · Degraded %d · Down %d",
+		JA: "正常 %d · 不安定 %d · 停止 %d",
+		RU: "Работает %d · Нестабильно %d · Недоступно %d",
+	},
+	"inline.status_up": {
+		ZH: "正常",
+		EN: "Up",
+		JA: "正常",
+		RU: "Работает",
+	},
+	"inline.status_degraded": {
+		ZH: "波动",
+		EN: "Degraded",
+		JA: "不安定",
+		RU: "Нестабильно",
+	},
+	"inline.status_down": {
+		ZH: "不可用",
+		EN: "Down",
+		JA: "停止",
+		RU: "Недоступно",
+	},
+	"inline.status_cold": {
+		ZH: "已暂停监测",
+		EN: "Monitoring paused",
+		JA: "監視停止中",
+		RU: "Мониторинг приостановлен",
+	},
+	"inline.updated": {
+		ZH: "更新时间: %s",
+		EN: "Updated: %s (UTC+8)",
+		JA: "更新時刻: %s (UTC+8)",
+		RU: "Обновлено: %s (UTC+8)",
+	},
+
+	// ===== 事件通知 =====
+	"event.up": {
+		ZH: "服务已恢复",
+		EN: "Service recovered",
+		JA: "サービスが復旧しました",
+		RU: "Сервис восстановлен",
+	},
+	"event.down": {
+		ZH: "服务不可用",
+		EN: "Service unavailable",
+		JA: "サービス利用不可",
+		RU: "Сервис недоступен",
+	},
+	"event.cert_expiring": {
+		ZH: "证书即将到期",
+		EN: "Certificate expiring soon",
+		JA: "証明書の有効期限が近づいています",
+		RU: "Сертификат скоро истекает",
+	},
+	"event.degraded_start": {
+		ZH: "服务性能下降",
+		EN: "Service degraded",
+		JA: "サービス性能が低下しています",
+		RU: "Производительность снижена",
+	},
+	"event.degraded_end": {
+		ZH: "服务性能已恢复",
+		EN: "Service performance recovered",
+		JA: "サービス性能が回復しました",
+		RU: "Производительность восстановлена",
+	},
+	"event.flapping": {
+		ZH: "服务波动",
+		EN: "Service unstable",
+		JA: "サービスが不安定です",
+		RU: "Сервис нестабилен",
+	},
+	"event.changed": {
+		ZH: "状态变更",
+		EN: "Status changed",
+		JA: "ステータスが変化しました",
+		RU: "Статус изменён",
+	},
+	"event.models": {
+		ZH: "\n模型: %s",
+		EN: "\nModels: %s",
+		JA: "\nモデル: %s",
+		RU: "\nМодели: %s",
+	},
+	"event.reason": {
+		ZH: "\n原因: %s",
+		EN: "\nReason: %s",
+		JA: "\n原因: %s",
+		RU: "\nПричина: %s",
+	},
+	"event.cause": {
+		ZH: "\n可能原因: %s",
+		EN: "\nProbable cause: %s",
+		JA: "\n推定原因: %s",
+		RU: "\nВероятная причина: %s",
+	},
+	"cause.auth_expired": {
+		ZH: "认证失败（Key 过期或额度耗尽）",
+		EN: "authentication failed (key expired or quota exhausted)",
+		JA: "認証失敗（キーの期限切れまたはクォータ枯渇）",
+		RU: "ошибка аутентификации (ключ истёк или квота исчерпана)",
+	},
+	"cause.upstream_outage": {
+		ZH: "上游官方服务故障",
+		EN: "upstream provider outage",
+		JA: "上流の公式サービス障害",
+		RU: "сбой вышестоящего сервиса",
+	},
+	"cause.rate_limited": {
+		ZH: "触发限流",
+		EN: "rate limited",
+		JA: "レート制限",
+		RU: "ограничение частоты запросов",
+	},
+	"cause.network": {
+		ZH: "网络连接异常",
+		EN: "network connectivity issue",
+		JA: "ネットワーク接続の問題",
+		RU: "проблема сетевого подключения",
+	},
+	"cause.content_change": {
+		ZH: "响应内容或接口格式变化",
+		EN: "response content or API format changed",
+		JA: "応答内容または API 形式の変更",
+		RU: "изменился ответ или формат API",
+	},
+	"event.cert_days": {
+		ZH: "\n证书剩余: %v 天",
+		EN: "\nCertificate expires in: %v days",
+		JA: "\n証明書の残り日数: %v 日",
+		RU: "\nДо истечения сертификата: %v дн.",
+	},
+	"event.time": {
+		ZH: "时间: %s",
+		EN: "Time: %s (UTC+8)",
+		JA: "時刻: %s (UTC+8)",
+		RU: "Время: %s (UTC+8)",
+	},
+	// ===== 暂停通知 =====
+	"pause.set": {
+		ZH: "🔕 已暂停通知至 %s（UTC+8）。\n\n订阅保持不变，暂停期间的事件不会补发；发送 /resume 可提前恢复。",
+		EN: "🔕 Notifications paused until %s (UTC+8).\n\nYour subscriptions are kept; events during the pause are not re-sent. Send /resume to resume early.",
+		JA: "🔕 %s（UTC+8）まで通知を一時停止しました。\n\n購読はそのまま保持され、停止中のイベントは再送されません。/resume で早めに再開できます。",
+		RU: "🔕 Уведомления приостановлены до %s (UTC+8).\n\nПодписки сохранены; события за время паузы повторно не отправляются. Отправьте /resume, чтобы возобновить раньше.",
+	},
+	"pause.set_indefinite": {
+		ZH: "🔕 已暂停通知，直到发送 /resume 恢复。\n\n订阅保持不变，暂停期间的事件不会补发。",
+		EN: "🔕 Notifications paused until you send /resume.\n\nYour subscriptions are kept; events during the pause are not re-sent.",
+		JA: "🔕 /resume を送信するまで通知を一時停止しました。\n\n購読はそのまま保持され、停止中のイベントは再送されません。",
+		RU: "🔕 Уведомления приостановлены до команды /resume.\n\nПодписки сохранены; события за время паузы повторно не отправляются.",
+	},
+	"pause.invalid": {
+		ZH: "暂停时长无效: %s\n\n用法：/pause [时长]，如 /pause 2h、/pause 3d、/pause 1w（1 分钟 ~ 90 天）；不指定时长则暂停到 /resume。",
+		EN: "Invalid pause duration: %s\n\nUsage: /pause [duration], e.g. /pause 2h, /pause 3d, /pause 1w (1 minute to 90 days). Without a duration notifications stay paused until /resume.",
+		JA: "一時停止の期間が無効です: %s\n\n使い方：/pause [期間]（例: /pause 2h、/pause 3d、/pause 1w、1 分〜90 日）。期間を省略すると /resume まで停止します。",
+		RU: "Неверная длительность паузы: %s\n\nИспользование: /pause [длительность], например /pause 2h, /pause 3d, /pause 1w (от 1 минуты до 90 дней). Без длительности пауза длится до /resume.",
+	},
+	"resume.done": {
+		ZH: "🔔 已恢复通知。",
+		EN: "🔔 Notifications resumed.",
+		JA: "🔔 通知を再開しました。",
+		RU: "🔔 Уведомления возобновлены.",
+	},
+	"resume.not_paused": {
+		ZH: "当前未暂停通知。",
+		EN: "Notifications are not paused.",
+		JA: "通知は一時停止されていません。",
+		RU: "Уведомления не приостановлены.",
+	},
+	// ===== 通知统计 =====
+	"stats.header": {
+		ZH: "<b>📊 通知统计</b>\n\n",
+		EN: "<b>📊 Notification stats</b>\n\n",
+		JA: "<b>📊 通知の統計</b>\n\n",
+		RU: "<b>📊 Статистика уведомлений</b>\n\n",
+	},
+	"stats.deliveries": {
+		ZH: "近 24 小时：%d 条通知\n近 7 天：%d 条通知\n",
+		EN: "Last 24h: %d notifications\nLast 7 days: %d notifications\n",
+		JA: "直近 24 時間：%d 件の通知\n直近 7 日：%d 件の通知\n",
+		RU: "За 24 ч: %d уведомлений\nЗа 7 дней: %d уведомлений\n",
+	},
+	"stats.top_header": {
+		ZH: "\n<b>通知最多的监测项（近 7 天）：</b>\n",
+		EN: "\n<b>Noisiest monitors (last 7 days):</b>\n",
+		JA: "\n<b>通知の多い監視項目（直近 7 日）：</b>\n",
+		RU: "\n<b>Самые шумные мониторы (7 дней):</b>\n",
+	},
+	"stats.top_item": {
+		ZH: "%d. %s：%d 条\n",
+		EN: "%d. %s: %d\n",
+		JA: "%d. %s：%d 件\n",
+		RU: "%d. %s: %d\n",
+	},
+	"stats.incidents_header": {
+		ZH: "\n<b>订阅服务故障（近 7 天）：</b>\n",
+		EN: "\n<b>Incidents on subscribed services (last 7 days):</b>\n",
+		JA: "\n<b>購読中サービスの障害（直近 7 日）：</b>\n",
+		RU: "\n<b>Сбои подписанных сервисов (7 дней):</b>\n",
+	},
+	"stats.incidents": {
+		ZH: "已恢复 %d 次，平均持续 %s\n",
+		EN: "%d resolved, average duration %s\n",
+		JA: "復旧 %d 件、平均継続時間 %s\n",
+		RU: "Устранено: %d, средняя длительность %s\n",
+	},
+	"stats.incidents_none": {
+		ZH: "暂无已恢复的故障\n",
+		EN: "No resolved incidents\n",
+		JA: "復旧した障害はありません\n",
+		RU: "Устранённых сбоев нет\n",
+	},
+	"stats.ongoing": {
+		ZH: "⚠️ 进行中：%d 个\n",
+		EN: "⚠️ Ongoing: %d\n",
+		JA: "⚠️ 継続中：%d 件\n",
+		RU: "⚠️ Продолжаются: %d\n",
+	},
+	// ===== 公告广播 =====
+	"broadcast.message": {
+		ZH: "📢 <b>RelayPulse 公告</b>\n\n%s\n\n发送 /announcements off 可不再接收公告（不影响故障通知）。",
+		EN: "📢 <b>RelayPulse announcement</b>\n\n%s\n\nSend /announcements off to stop receiving announcements (outage alerts are not affected).",
+		JA: "📢 <b>RelayPulse からのお知らせ</b>\n\n%s\n\n/announcements off でお知らせの受信を停止できます（障害通知には影響しません）。",
+		RU: "📢 <b>Объявление RelayPulse</b>\n\n%s\n\nОтправьте /announcements off, чтобы отказаться от объявлений (уведомления о сбоях останутся).",
+	},
+	"broadcast.usage": {
+		ZH: "用法：/broadcast <all|provider> <公告内容>\n\nall 发送给所有活跃会话；指定 provider 时仅发送给订阅了该服务商的会话。公告最长 %d 字。",
+		EN: "Usage: /broadcast <all|provider> <message>\n\nall sends to every active chat; a provider limits it to chats subscribed to that provider. Up to %d characters.",
+		JA: "使い方：/broadcast <all|provider> <本文>\n\nall はすべてのアクティブなチャットに送信します。provider を指定するとそのプロバイダーを購読しているチャットのみに送信します。本文は最大 %d 文字です。",
+		RU: "Использование: /broadcast <all|provider> <текст>\n\nall — во все активные чаты; provider — только в чаты с подпиской на этого провайдера. Не более %d символов.",
+	},
+	"broadcast.denied": {
+		ZH: "⛔ 仅运维管理员可发送公告。",
+		EN: "⛔ Only operators can send announcements.",
+		JA: "⛔ お知らせを送信できるのは運用管理者のみです。",
+		RU: "⛔ Отправлять объявления могут только операторы.",
+	},
+	"broadcast.unavailable": {
+		ZH: "公告广播暂不可用，请稍后重试。",
+		EN: "Announcements are unavailable right now. Please try again later.",
+		JA: "現在お知らせを送信できません。しばらくしてから再試行してください。",
+		RU: "Объявления сейчас недоступны. Повторите попытку позже.",
+	},
+	"broadcast.too_long": {
+		ZH: "公告过长（%d 字），最多 %d 字。",
+		EN: "The announcement is too long (%d characters, max %d).",
+		JA: "お知らせが長すぎます（%d 文字、最大 %d 文字）。",
+		RU: "Слишком длинное объявление (%d символов, максимум %d).",
+	},
+	"broadcast.started": {
+		ZH: "📢 公告 #%d 开始发送：%d 个会话（%d 个会话已退订公告）。完成后将在此通知发送结果。",
+		EN: "📢 Announcement #%d is being sent to %d chats (%d chats opted out). The results will be posted here when it finishes.",
+		JA: "📢 お知らせ #%d を %d 件のチャットに送信しています（%d 件は受信停止中）。完了するとここに結果を通知します。",
+		RU: "📢 Объявление #%d отправляется в %d чатов (%d отказались от объявлений). Итоги появятся здесь после завершения.",
+	},
+	"broadcast.empty": {
+		ZH: "公告 #%d 没有可发送的会话（%d 个会话已退订公告）。",
+		EN: "Announcement #%d has no chats to send to (%d chats opted out).",
+		JA: "お知らせ #%d の送信先チャットはありません（%d 件は受信停止中）。",
+		RU: "Для объявления #%d нет чатов для отправки (%d отказались от объявлений).",
+	},
+	"broadcast.done": {
+		ZH: "✅ 公告 #%d 发送完成：成功 %d，失败 %d，共 %d 个会话（%d 个会话已退订公告）。",
+		EN: "✅ Announcement #%d finished: %d sent, %d failed, %d chats in total (%d opted out).",
+		JA: "✅ お知らせ #%d の送信が完了しました：成功 %d、失敗 %d、合計 %d 件（受信停止 %d 件）。",
+		RU: "✅ Объявление #%d отправлено: успешно %d, ошибок %d, всего чатов %d (отказались: %d).",
+	},
+	"broadcast.aborted": {
+		ZH: "⚠️ 公告 #%d 因服务关闭中止：成功 %d，失败 %d，共 %d 个会话。",
+		EN: "⚠️ Announcement #%d was aborted by a shutdown: %d sent, %d failed, %d chats in total.",
+		JA: "⚠️ お知らせ #%d はサービス停止のため中断されました：成功 %d、失敗 %d、合計 %d 件。",
+		RU: "⚠️ Объявление #%d прервано остановкой сервиса: успешно %d, ошибок %d, всего чатов %d.",
+	},
+	"announcements.status_on": {
+		ZH: "RelayPulse 公告: 接收中\n\n公告用于通知 RelayPulse 自身的计划维护等事项。发送 /announcements off 退订，故障事件通知不受影响。",
+		EN: "RelayPulse announcements: on\n\nAnnouncements cover planned maintenance of RelayPulse itself. Send /announcements off to opt out; outage alerts are not affected.",
+		JA: "RelayPulse のお知らせ: 受信中\n\nお知らせは RelayPulse 自体の計画メンテナンスなどを通知します。/announcements off で受信を停止できます（障害通知には影響しません）。",
+		RU: "Объявления RelayPulse: включены\n\nОбъявления сообщают о плановых работах самого RelayPulse. Отправьте /announcements off, чтобы отказаться; уведомления о сбоях останутся.",
+	},
+	"announcements.status_off": {
+		ZH: "RelayPulse 公告: 已退订\n\n发送 /announcements on 恢复接收。",
+		EN: "RelayPulse announcements: off\n\nSend /announcements on to receive them again.",
+		JA: "RelayPulse のお知らせ: 受信停止中\n\n/announcements on で受信を再開できます。",
+		RU: "Объявления RelayPulse: отключены\n\nОтправьте /announcements on, чтобы снова их получать.",
+	},
+	"announcements.on": {
+		ZH: "✅ 已恢复接收 RelayPulse 公告。",
+		EN: "✅ RelayPulse announcements turned on.",
+		JA: "✅ RelayPulse のお知らせの受信を再開しました。",
+		RU: "✅ Объявления RelayPulse снова включены.",
+	},
+	"announcements.off": {
+		ZH: "已退订 RelayPulse 公告，故障事件通知不受影响。发送 /announcements on 可恢复。",
+		EN: "You will no longer receive RelayPulse announcements; outage alerts are not affected. Send /announcements on to undo.",
+		JA: "RelayPulse のお知らせの受信を停止しました。障害通知には影響しません。/announcements on で再開できます。",
+		RU: "Объявления RelayPulse отключены; уведомления о сбоях останутся. Отправьте /announcements on, чтобы вернуть их.",
+	},
+	"announcements.usage": {
+		ZH: "用法：/announcements [on|off]",
+		EN: "Usage: /announcements [on|off]",
+		JA: "使い方：/announcements [on|off]",
+		RU: "Использование: /announcements [on|off]",
+	},
+	"alert.event_regression": {
+		ZH: "⚠️ <b>事件 ID 回退</b>\n\n本地游标 %d 大于主服务最新事件 ID %d，主服务可能更换了数据库或恢复了旧备份。\n已将游标重置为主服务最新事件 ID，并清理 %d 条旧投递记录；新库中已有的事件不会补发。",
+		EN: "⚠️ <b>Event ID regression</b>\n\nLocal cursor %d is ahead of the server's latest event ID %d; the server database may have been replaced or restored from an old backup.\nThe cursor was reset to the server's latest event ID and %d stale delivery records were removed. Events already in the new database will not be re-sent.",
+		JA: "⚠️ <b>イベント ID の巻き戻り</b>\n\nローカルカーソル %d がサーバーの最新イベント ID %d を超えています。サーバーのデータベースが置き換えられたか、古いバックアップから復元された可能性があります。\nカーソルをサーバーの最新イベント ID にリセットし、古い配信記録 %d 件を削除しました。新しいデータベースに既にあるイベントは再送されません。",
+		RU: "⚠️ <b>Откат ID событий</b>\n\nЛокальный курсор %d больше последнего ID события на сервере (%d): возможно, база сервера заменена или восстановлена из старой копии.\nКурсор сброшен на последний ID сервера, удалено устаревших записей доставки: %d. События, уже находящиеся в новой базе, повторно не отправляются.",
+	},
+	"event.retry": {
+		ZH: "🔔 通知重试 (event_id: %d)\n\n如果您持续收到此消息，请检查订阅设置。",
+		EN: "🔔 Notification retry (event_id: %d)\n\nIf you keep receiving this message, please check your subscription settings.",
+		JA: "🔔 通知の再送 (event_id: %d)\n\nこのメッセージが繰り返し届く場合は、購読設定を確認してください。",
+		RU: "🔔 Повторное уведомление (event_id: %d)\n\nЕсли это сообщение приходит постоянно, проверьте настройки подписки.",
+	},
+}
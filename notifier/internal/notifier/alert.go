@@ -0,0 +1,48 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"notifier/internal/i18n"
+	"notifier/internal/storage"
+)
+
+// NotifyAdmins 向 alerts.chats 配置的会话发送运维告警（按会话语言偏好渲染）
+// 未配置告警会话时仅由调用方写日志；发送失败不重试
+func (s *Sender) NotifyAdmins(ctx context.Context, key string, args ...any) {
+	for _, ch := range s.cfg.Alerts.Chats {
+		if !s.waitPlatformRateLimit(ctx, ch.Platform) {
+			return
+		}
+
+		lang := s.chatLang(ctx, ch.Platform, ch.ChatID)
+		var err error
+		switch ch.Platform {
+		case storage.PlatformTelegram:
+			if s.tgClient == nil {
+				err = fmt.Errorf("telegram client not configured")
+				break
+			}
+			_, err = s.tgClient.SendMessageHTML(ctx, ch.ChatID, i18n.HTML(lang, key, args...))
+		case storage.PlatformQQ:
+			if s.qqClient == nil {
+				err = fmt.Errorf("qq client not configured")
+				break
+			}
+			text := i18n.Text(lang, key, args...)
+			if ch.ChatID < 0 {
+				_, err = s.qqClient.SendGroupMessage(ctx, -ch.ChatID, text)
+			} else {
+				_, err = s.qqClient.SendPrivateMessage(ctx, ch.ChatID, text)
+			}
+		default:
+			err = fmt.Errorf("unknown platform: %s", ch.Platform)
+		}
+
+		if err != nil {
+			slog.Error("发送运维告警失败", "key", key, "platform", ch.Platform, "chat_id", ch.ChatID, "error", err)
+		}
+	}
+}
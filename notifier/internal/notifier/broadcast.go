@@ -0,0 +1,145 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"notifier/internal/i18n"
+	"notifier/internal/storage"
+)
+
+// broadcastProgressEvery 每发送多少个会话持久化一次广播统计
+const broadcastProgressEvery = 20
+
+// StartBroadcast 发起公告广播：解析目标会话并写入广播记录后立即返回，发送在后台按平台限流进行
+// 目标为活跃的 Telegram / QQ 会话（已退订的除外），跨平台关联的身份只发送一次；
+// 没有目标会话时记录直接完成且不调用 onDone，否则全部发送结束后以最终统计调用 onDone（可为 nil）
+func (s *Sender) StartBroadcast(ctx context.Context, b *storage.Broadcast, onDone func(*storage.Broadcast)) error {
+	targets, optedOut, err := s.storage.GetBroadcastTargets(ctx, b.Provider)
+	if err != nil {
+		return err
+	}
+	targets = s.dedupeLinkedChats(targets)
+
+	now := time.Now().Unix()
+	b.Status = storage.BroadcastStatusRunning
+	b.Total = len(targets)
+	b.OptedOut = optedOut
+	b.CreatedAt = now
+	if len(targets) == 0 {
+		b.Status = storage.BroadcastStatusDone
+		b.FinishedAt = now
+	}
+	if err := s.storage.CreateBroadcast(ctx, b); err != nil {
+		return err
+	}
+
+	slog.Info("公告广播开始",
+		"broadcast_id", b.ID,
+		"actor", b.Actor,
+		"provider", b.Provider,
+		"total", b.Total,
+		"opted_out", b.OptedOut,
+	)
+	if len(targets) == 0 {
+		return nil
+	}
+
+	// 后台协程使用副本更新统计，调用方可安全读取 b
+	run := *b
+	go s.runBroadcast(&run, targets, onDone)
+	return nil
+}
+
+// runBroadcast 逐个会话发送公告（与事件通知共用平台限流），服务关闭时中止
+func (s *Sender) runBroadcast(b *storage.Broadcast, targets []*storage.ChatRef, onDone func(*storage.Broadcast)) {
+	ctx := s.getSendContext()
+	b.Status = storage.BroadcastStatusDone
+
+	for i, ref := range targets {
+		select {
+		case <-ctx.Done():
+			b.Status = storage.BroadcastStatusAborted
+		case <-s.stopChan:
+			b.Status = storage.BroadcastStatusAborted
+		default:
+		}
+		if b.Status == storage.BroadcastStatusAborted || !s.waitPlatformRateLimit(ctx, ref.Platform) {
+			b.Status = storage.BroadcastStatusAborted
+			break
+		}
+
+		if err := s.sendBroadcast(ctx, ref, b.Message); err != nil {
+			b.Failed++
+			slog.Warn("公告发送失败",
+				"broadcast_id", b.ID,
+				"platform", ref.Platform,
+				"chat_id", ref.ChatID,
+				"error", err,
+			)
+			// 不可恢复的错误（用户屏蔽 Bot）停用会话，与事件通知一致
+			if reason := undeliverableReason(ref.Platform, err); reason != "" {
+				if err := s.storage.UpdateChatStatus(ctx, ref.Platform, ref.ChatID, storage.ChatStatusBlocked); err != nil {
+					slog.Error("更新用户状态失败", "error", err)
+				}
+			}
+		} else {
+			b.Sent++
+		}
+
+		if (i+1)%broadcastProgressEvery == 0 {
+			if err := s.storage.UpdateBroadcast(ctx, b); err != nil {
+				slog.Warn("保存公告广播进度失败", "broadcast_id", b.ID, "error", err)
+			}
+		}
+	}
+
+	// 服务关闭时 ctx 可能已取消，最终统计使用独立 context 保存
+	b.FinishedAt = time.Now().Unix()
+	saveCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.storage.UpdateBroadcast(saveCtx, b); err != nil {
+		slog.Error("保存公告广播统计失败", "broadcast_id", b.ID, "error", err)
+	}
+
+	slog.Info("公告广播结束",
+		"broadcast_id", b.ID,
+		"status", b.Status,
+		"total", b.Total,
+		"sent", b.Sent,
+		"failed", b.Failed,
+	)
+	if onDone != nil {
+		onDone(b)
+	}
+}
+
+// sendBroadcast 向单个会话发送公告（按会话语言渲染标题与退订提示）
+func (s *Sender) sendBroadcast(ctx context.Context, ref *storage.ChatRef, message string) error {
+	lang := i18n.Resolve(ref.Language, "")
+
+	switch ref.Platform {
+	case storage.PlatformTelegram:
+		if s.tgClient == nil {
+			return fmt.Errorf("telegram client not configured")
+		}
+		_, err := s.tgClient.SendMessageHTML(ctx, ref.ChatID, i18n.HTML(lang, "broadcast.message", message))
+		return err
+	case storage.PlatformQQ:
+		if s.qqClient == nil {
+			return fmt.Errorf("qq client not configured")
+		}
+		text := i18n.Text(lang, "broadcast.message", message)
+		var err error
+		if ref.ChatID < 0 {
+			_, err = s.qqClient.SendGroupMessage(ctx, -ref.ChatID, text)
+		} else {
+			_, err = s.qqClient.SendPrivateMessage(ctx, ref.ChatID, text)
+		}
+		return err
+	default:
+		return fmt.Errorf("unknown platform: %s", ref.Platform)
+	}
+}
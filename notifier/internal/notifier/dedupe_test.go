@@ -0,0 +1,189 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"notifier/internal/config"
+	"notifier/internal/qq"
+	"notifier/internal/storage"
+	"notifier/internal/telegram"
+)
+
+// newDedupeTestSender 创建发送器，按参数决定 Telegram / QQ 客户端是否已配置
+func newDedupeTestSender(t *testing.T, store storage.Storage, tg, qqEnabled bool) *Sender {
+	t.Helper()
+	s := NewSender(&config.Config{}, store)
+	t.Cleanup(s.Stop)
+	s.tgClient, s.qqClient = nil, nil
+	if tg {
+		s.tgClient = telegram.NewClient("test")
+	}
+	if qqEnabled {
+		s.qqClient = qq.NewClient("http://127.0.0.1:0", "")
+	}
+	return s
+}
+
+// targets 返回投递目标的 "platform:chat_id" 列表（排序后便于比较）
+func targets(refs []*storage.ChatRef) []string {
+	out := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		out = append(out, fmt.Sprintf("%s:%d", ref.Platform, ref.ChatID))
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestDedupeLinkedChats(t *testing.T) {
+	ref := func(platform string, chatID int64, linkID string, priority int) *storage.ChatRef {
+		return &storage.ChatRef{Platform: platform, ChatID: chatID, LinkID: linkID, LinkPriority: priority}
+	}
+	cases := []struct {
+		name      string
+		tg, qq    bool
+		refs      []*storage.ChatRef
+		wantChats []string
+	}{
+		{
+			name:      "未关联的会话全部保留",
+			tg:        true,
+			qq:        true,
+			refs:      []*storage.ChatRef{ref("telegram", 1, "", 0), ref("qq", 2, "", 0)},
+			wantChats: []string{"qq:2", "telegram:1"},
+		},
+		{
+			name: "同一身份按投递顺序只保留首个",
+			tg:   true,
+			qq:   true,
+			refs: []*storage.ChatRef{
+				ref("telegram", 1, "a", 1), ref("qq", 2, "a", 0), ref("telegram", 3, "", 0),
+			},
+			wantChats: []string{"qq:2", "telegram:3"},
+		},
+		{
+			name:      "首选平台未配置时回退到下一个会话",
+			tg:        true,
+			qq:        false,
+			refs:      []*storage.ChatRef{ref("qq", 2, "a", 0), ref("telegram", 1, "a", 1)},
+			wantChats: []string{"telegram:1"},
+		},
+		{
+			name:      "身份内平台均未配置时仍按投递顺序保留一个",
+			tg:        false,
+			qq:        false,
+			refs:      []*storage.ChatRef{ref("telegram", 1, "a", 1), ref("qq", 2, "a", 0)},
+			wantChats: []string{"qq:2"},
+		},
+		{
+			name: "不同身份分别去重",
+			tg:   true,
+			qq:   true,
+			refs: []*storage.ChatRef{
+				ref("telegram", 1, "a", 0), ref("qq", 2, "a", 1),
+				ref("telegram", 3, "b", 1), ref("qq", 4, "b", 0),
+			},
+			wantChats: []string{"qq:4", "telegram:1"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newDedupeTestSender(t, nil, tc.tg, tc.qq)
+			if got := targets(s.dedupeLinkedChats(tc.refs)); !reflect.DeepEqual(got, tc.wantChats) {
+				t.Fatalf("dedupeLinkedChats = %v, want %v", got, tc.wantChats)
+			}
+		})
+	}
+}
+
+func TestLinkedChatsReceiveOneDelivery(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSQLiteStorage(t)
+
+	for _, c := range []*storage.Chat{
+		{Platform: storage.PlatformTelegram, ChatID: 1},
+		{Platform: storage.PlatformQQ, ChatID: 2},
+		{Platform: storage.PlatformTelegram, ChatID: 3},
+	} {
+		if err := store.UpsertChat(ctx, c); err != nil {
+			t.Fatalf("UpsertChat: %v", err)
+		}
+		if err := store.AddSubscription(ctx, &storage.Subscription{Platform: c.Platform, ChatID: c.ChatID, Provider: "relay"}); err != nil {
+			t.Fatalf("AddSubscription: %v", err)
+		}
+	}
+	if err := store.LinkChat(ctx, storage.PlatformQQ, 2, storage.PlatformTelegram, 1); err != nil {
+		t.Fatalf("LinkChat: %v", err)
+	}
+
+	both := newDedupeTestSender(t, store, true, true)
+	tgOnly := newDedupeTestSender(t, store, true, false)
+	check := func(s *Sender, want ...string) {
+		t.Helper()
+		refs, err := store.GetSubscribersByMonitor(ctx, "relay", "cc", "vip")
+		if err != nil {
+			t.Fatalf("GetSubscribersByMonitor: %v", err)
+		}
+		sort.Strings(want)
+		if got := targets(s.dedupeLinkedChats(refs)); !reflect.DeepEqual(got, want) {
+			t.Fatalf("delivery targets = %v, want %v", got, want)
+		}
+	}
+
+	// 关联后默认投递到身份内首个会话（被关联的目标会话）
+	check(both, "telegram:1", "telegram:3")
+
+	// /link prefer 后改为投递到 QQ；QQ 未配置时回退到 Telegram
+	if ok, err := store.SetPreferredChat(ctx, storage.PlatformQQ, 2); err != nil || !ok {
+		t.Fatalf("SetPreferredChat = %v, %v", ok, err)
+	}
+	check(both, "qq:2", "telegram:3")
+	check(tgOnly, "telegram:1", "telegram:3")
+
+	// 首选会话被封禁时回退到身份内的下一个会话
+	if err := store.UpdateChatStatus(ctx, storage.PlatformQQ, 2, "blocked"); err != nil {
+		t.Fatalf("UpdateChatStatus: %v", err)
+	}
+	check(both, "telegram:1", "telegram:3")
+	if err := store.UpdateChatStatus(ctx, storage.PlatformQQ, 2, "active"); err != nil {
+		t.Fatalf("UpdateChatStatus: %v", err)
+	}
+
+	// 解除关联后两个会话各自投递（身份只剩一个会话时一并解散）
+	if ok, err := store.UnlinkChat(ctx, storage.PlatformQQ, 2); err != nil || !ok {
+		t.Fatalf("UnlinkChat = %v, %v", ok, err)
+	}
+	check(both, "qq:2", "telegram:1", "telegram:3")
+	if links, err := store.GetLinkedChats(ctx, storage.PlatformTelegram, 1); err != nil || len(links) != 0 {
+		t.Fatalf("GetLinkedChats after unlink = %v, %v", links, err)
+	}
+}
+
+func TestCreateDeliveryIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSQLiteStorage(t)
+
+	first := &storage.Delivery{EventID: 7, Platform: storage.PlatformTelegram, ChatID: 1}
+	if err := store.CreateDelivery(ctx, first); err != nil {
+		t.Fatalf("CreateDelivery: %v", err)
+	}
+	if first.ID == 0 {
+		t.Fatal("首次创建应返回投递记录 ID")
+	}
+	other := &storage.Delivery{EventID: 7, Platform: storage.PlatformQQ, ChatID: 1}
+	if err := store.CreateDelivery(ctx, other); err != nil || other.ID == 0 || other.ID == first.ID {
+		t.Fatalf("不同目标应创建新记录: id=%d err=%v", other.ID, err)
+	}
+
+	// 同一事件与目标再次创建（如多实例或重放轮询）时返回 ID 0，分发方据此跳过重复发送
+	dup := &storage.Delivery{EventID: 7, Platform: storage.PlatformTelegram, ChatID: 1}
+	if err := store.CreateDelivery(ctx, dup); err != nil {
+		t.Fatalf("CreateDelivery duplicate: %v", err)
+	}
+	if dup.ID != 0 {
+		t.Fatalf("重复创建应返回 ID 0, got %d", dup.ID)
+	}
+}
@@ -0,0 +1,176 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"time"
+
+	"notifier/internal/i18n"
+	"notifier/internal/storage"
+)
+
+// retryBatchSize 每轮扫描取出的到期投递数
+const retryBatchSize = 100
+
+// retryLoop 重试工作协程：按 retry_interval 扫描已到重试时间的待发送投递
+func (s *Sender) retryLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Limits.RetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			s.processRetries(ctx)
+		}
+	}
+}
+
+// processRetries 处理一批到期的重试
+// 逐条串行发送：平台限流本身就是串行的，且可保证本轮未发完的记录不会在下一轮被重复取出
+func (s *Sender) processRetries(ctx context.Context) {
+	deliveries, err := s.storage.GetPendingDeliveries(ctx, retryBatchSize)
+	if err != nil {
+		slog.Error("获取待重试投递失败", "error", err)
+		return
+	}
+
+	for _, delivery := range deliveries {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		default:
+		}
+		s.retryDelivery(ctx, delivery)
+	}
+}
+
+// scheduleRetry 记录一次可恢复的发送失败
+// 按指数退避安排下次重试；已用完 max_retries 次重试的投递转入死信队列，等待管理员重新入队
+func (s *Sender) scheduleRetry(ctx context.Context, delivery *storage.Delivery, sendErr error) {
+	attempt := delivery.RetryCount + 1
+	if attempt > s.cfg.Limits.MaxRetries {
+		slog.Warn("投递重试次数耗尽，转入死信队列",
+			"delivery_id", delivery.ID,
+			"platform", delivery.Platform,
+			"chat_id", delivery.ChatID,
+			"retry_count", delivery.RetryCount,
+		)
+		if err := s.storage.UpdateDeliveryStatus(ctx, delivery.ID, storage.DeliveryStatusDead, "", sendErr.Error()); err != nil {
+			slog.Error("更新投递状态失败", "error", err)
+		}
+		return
+	}
+
+	delay := retryBackoff(attempt, s.cfg.Limits.RetryBaseDelay, s.cfg.Limits.RetryMaxDelay)
+	nextAttemptAt := time.Now().Add(delay).Unix()
+	if err := s.storage.ScheduleDeliveryRetry(ctx, delivery.ID, nextAttemptAt, sendErr.Error()); err != nil {
+		slog.Error("安排投递重试失败", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// retryBackoff 第 attempt 次重试前的等待时间：base × 2^(attempt-1)，不超过 maxDelay
+// 额外叠加最多 20% 的随机抖动，避免同一时刻失败的大量投递在同一时刻重试
+func retryBackoff(attempt int, base, maxDelay time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	if jitter := int64(delay) / 5; jitter > 0 {
+		delay += time.Duration(rand.Int63n(jitter))
+	}
+	return delay
+}
+
+// chatLang 查询会话语言偏好（查询失败或未设置时使用默认语言）
+func (s *Sender) chatLang(ctx context.Context, platform string, chatID int64) i18n.Lang {
+	chat, err := s.storage.GetChat(ctx, platform, chatID)
+	if err != nil || chat == nil {
+		return i18n.Default()
+	}
+	return i18n.Resolve(chat.Language, "")
+}
+
+// retryDelivery 重试单条投递（失败时与首次发送走同一套错误处理）
+func (s *Sender) retryDelivery(ctx context.Context, delivery *storage.Delivery) {
+	// 会话已暂停通知（/pause）：放弃重试，恢复后不补发
+	chat, err := s.storage.GetChat(ctx, delivery.Platform, delivery.ChatID)
+	if err != nil {
+		slog.Warn("查询会话失败", "platform", delivery.Platform, "chat_id", delivery.ChatID, "error", err)
+	}
+	if chat != nil && storage.IsPaused(chat.PausedUntil, time.Now().Unix()) {
+		if err := s.storage.UpdateDeliveryStatus(ctx, delivery.ID, storage.DeliveryStatusFailed, "", "chat paused"); err != nil {
+			slog.Error("更新投递状态失败", "error", err)
+		}
+		return
+	}
+
+	// 等待平台限流
+	if !s.waitPlatformRateLimit(ctx, delivery.Platform) {
+		return
+	}
+
+	// 简单的重试消息（按会话语言偏好渲染）
+	lang := i18n.Default()
+	if chat != nil {
+		lang = i18n.Resolve(chat.Language, "")
+	}
+	msg := i18n.Text(lang, "event.retry", delivery.EventID)
+
+	var messageID string
+
+	switch delivery.Platform {
+	case storage.PlatformTelegram:
+		if s.tgClient == nil {
+			err = fmt.Errorf("telegram client not configured")
+		} else {
+			result, sendErr := s.tgClient.SendMessageHTML(ctx, delivery.ChatID, msg)
+			if sendErr == nil && result != nil {
+				messageID = fmt.Sprintf("%d", result.MessageID)
+			}
+			err = sendErr
+		}
+
+	case storage.PlatformQQ:
+		if s.qqClient == nil {
+			err = fmt.Errorf("qq client not configured")
+		} else {
+			var mid int64
+			if delivery.ChatID < 0 {
+				mid, err = s.qqClient.SendGroupMessage(ctx, -delivery.ChatID, msg)
+			} else {
+				mid, err = s.qqClient.SendPrivateMessage(ctx, delivery.ChatID, msg)
+			}
+			if err == nil {
+				messageID = fmt.Sprintf("%d", mid)
+			}
+		}
+
+	case storage.PlatformWeCom, storage.PlatformDingTalk:
+		title, _, _ := strings.Cut(msg, "\n")
+		err = s.sendRobot(ctx, delivery, title, msg)
+
+	default:
+		err = fmt.Errorf("unknown platform: %s", delivery.Platform)
+	}
+
+	if err != nil {
+		s.handleSendError(ctx, delivery, err)
+		return
+	}
+
+	if err := s.storage.UpdateDeliveryStatus(ctx, delivery.ID, storage.DeliveryStatusSent, messageID, ""); err != nil {
+		slog.Error("更新投递状态失败", "error", err)
+	}
+}
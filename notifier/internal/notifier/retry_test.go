@@ -0,0 +1,114 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"notifier/internal/config"
+	"notifier/internal/storage"
+)
+
+// newTestSQLiteStorage 创建临时 SQLite 存储（测试结束时关闭）
+func newTestSQLiteStorage(t *testing.T) *storage.SQLiteStorage {
+	t.Helper()
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "notifier.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	if err := store.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	return store
+}
+
+func TestRetryBackoffSchedule(t *testing.T) {
+	base, maxDelay := 30*time.Second, 30*time.Minute
+	cases := []struct {
+		attempt int
+		want    time.Duration // 抖动前的延迟
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{6, 16 * time.Minute},
+		{7, 30 * time.Minute}, // 32m 截断到上限
+		{20, 30 * time.Minute},
+	}
+	for _, tc := range cases {
+		for i := 0; i < 50; i++ {
+			got := retryBackoff(tc.attempt, base, maxDelay)
+			if got < tc.want || got >= tc.want+tc.want/5 {
+				t.Fatalf("attempt %d: retryBackoff = %v, want [%v, %v)", tc.attempt, got, tc.want, tc.want+tc.want/5)
+			}
+		}
+	}
+}
+
+func TestScheduleRetryDeadLettersAfterMaxRetries(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSQLiteStorage(t)
+
+	cfg := &config.Config{}
+	cfg.Limits.MaxRetries = 2
+	cfg.Limits.RetryBaseDelay = time.Minute
+	cfg.Limits.RetryMaxDelay = time.Hour
+	s := NewSender(cfg, store)
+	defer s.Stop()
+
+	delivery := &storage.Delivery{EventID: 42, Platform: storage.PlatformTelegram, ChatID: 1001}
+	if err := store.CreateDelivery(ctx, delivery); err != nil {
+		t.Fatalf("CreateDelivery: %v", err)
+	}
+	sendErr := errors.New("connection reset")
+
+	// 前 max_retries 次失败按退避安排重试：仍为 pending，但未到重试时间不会被取出
+	for attempt := 1; attempt <= cfg.Limits.MaxRetries; attempt++ {
+		s.scheduleRetry(ctx, delivery, sendErr)
+		delivery.RetryCount = attempt
+
+		pending, err := store.GetPendingDeliveries(ctx, retryBatchSize)
+		if err != nil {
+			t.Fatalf("GetPendingDeliveries: %v", err)
+		}
+		if len(pending) != 0 {
+			t.Fatalf("attempt %d: 退避期内不应被取出: %+v", attempt, pending[0])
+		}
+		dead, err := store.GetDeadDeliveries(ctx, storage.DeliveryFilter{Limit: 10})
+		if err != nil {
+			t.Fatalf("GetDeadDeliveries: %v", err)
+		}
+		if len(dead) != 0 {
+			t.Fatalf("attempt %d: 未耗尽重试次数不应进入死信队列", attempt)
+		}
+	}
+
+	// 第 max_retries+1 次失败转入死信队列
+	s.scheduleRetry(ctx, delivery, sendErr)
+	dead, err := store.GetDeadDeliveries(ctx, storage.DeliveryFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetDeadDeliveries: %v", err)
+	}
+	if len(dead) != 1 || dead[0].ID != delivery.ID || dead[0].RetryCount != cfg.Limits.MaxRetries ||
+		dead[0].ErrorMessage != sendErr.Error() {
+		t.Fatalf("unexpected dead deliveries: %+v", dead)
+	}
+
+	// 管理员重新入队后立即可被重试协程取出，重试计数清零
+	if ok, err := store.RequeueDeadDelivery(ctx, delivery.ID); err != nil || !ok {
+		t.Fatalf("RequeueDeadDelivery = %v, %v", ok, err)
+	}
+	pending, err := store.GetPendingDeliveries(ctx, retryBatchSize)
+	if err != nil {
+		t.Fatalf("GetPendingDeliveries: %v", err)
+	}
+	if len(pending) != 1 || pending[0].ID != delivery.ID || pending[0].RetryCount != 0 {
+		t.Fatalf("unexpected pending deliveries after requeue: %+v", pending)
+	}
+	if ok, _ := store.RequeueDeadDelivery(ctx, delivery.ID); ok {
+		t.Fatal("非死信投递不应被重新入队")
+	}
+}
@@ -0,0 +1,170 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"notifier/internal/config"
+	"notifier/internal/dingtalk"
+	"notifier/internal/i18n"
+	"notifier/internal/poller"
+	"notifier/internal/storage"
+	"notifier/internal/wecom"
+)
+
+// errRobotNotConfigured 投递目标是已从配置中移除的群机器人
+var errRobotNotConfigured = errors.New("robot not configured")
+
+// robotKey 群机器人索引（平台 + 派生的 chat_id）
+type robotKey struct {
+	platform string
+	chatID   int64
+}
+
+// robotClient 群机器人客户端（按平台二选一）
+type robotClient struct {
+	name     string
+	wecom    *wecom.Client
+	dingtalk *dingtalk.Client
+}
+
+// isRobotPlatform 是否为群机器人平台
+func isRobotPlatform(platform string) bool {
+	return platform == storage.PlatformWeCom || platform == storage.PlatformDingTalk
+}
+
+// newRobotClients 按配置创建群机器人客户端
+func newRobotClients(robots []config.RobotConfig) map[robotKey]*robotClient {
+	clients := make(map[robotKey]*robotClient, len(robots))
+	for _, r := range robots {
+		c := &robotClient{name: r.Name}
+		switch r.Platform {
+		case storage.PlatformWeCom:
+			c.wecom = wecom.NewClient(r.WebhookURL)
+		case storage.PlatformDingTalk:
+			c.dingtalk = dingtalk.NewClient(r.WebhookURL, r.Secret)
+		default:
+			continue
+		}
+		clients[robotKey{platform: r.Platform, chatID: r.ChatID()}] = c
+	}
+	return clients
+}
+
+// SyncRobots 将配置中的群机器人及其订阅同步到存储
+// 群机器人无法接收命令，订阅以配置为准：每次启动清空后按配置重建
+func SyncRobots(ctx context.Context, store storage.Storage, robots []config.RobotConfig) error {
+	for _, r := range robots {
+		chatID := r.ChatID()
+		if err := store.UpsertChat(ctx, &storage.Chat{
+			Platform: r.Platform,
+			ChatID:   chatID,
+			Username: r.Name,
+		}); err != nil {
+			return fmt.Errorf("同步群机器人 %s 失败: %w", r.Name, err)
+		}
+		// 曾因从配置移除而停用的机器人重新加入时恢复投递
+		if err := store.UpdateChatStatus(ctx, r.Platform, chatID, storage.ChatStatusActive); err != nil {
+			return fmt.Errorf("同步群机器人 %s 失败: %w", r.Name, err)
+		}
+		if err := store.UpdateChatLanguage(ctx, r.Platform, chatID, r.Language); err != nil {
+			return fmt.Errorf("同步群机器人 %s 失败: %w", r.Name, err)
+		}
+
+		if err := store.ClearSubscriptions(ctx, r.Platform, chatID); err != nil {
+			return fmt.Errorf("同步群机器人 %s 订阅失败: %w", r.Name, err)
+		}
+		for _, sub := range r.Subscriptions {
+			var mask uint32
+			if sub.Events != "" {
+				m, err := storage.ParseEventMask(sub.Events)
+				if err != nil {
+					return fmt.Errorf("群机器人 %s 订阅事件类型无效: %w", r.Name, err)
+				}
+				mask = m
+			}
+			if err := store.AddSubscription(ctx, &storage.Subscription{
+				Platform:  r.Platform,
+				ChatID:    chatID,
+				Provider:  strings.TrimSpace(sub.Provider),
+				Service:   strings.TrimSpace(sub.Service),
+				Channel:   strings.TrimSpace(sub.Channel),
+				EventMask: mask,
+			}); err != nil {
+				return fmt.Errorf("同步群机器人 %s 订阅失败: %w", r.Name, err)
+			}
+		}
+
+		slog.Info("群机器人已同步",
+			"name", r.Name,
+			"platform", r.Platform,
+			"chat_id", chatID,
+			"subscriptions", len(r.Subscriptions),
+		)
+	}
+	return nil
+}
+
+// sendRobot 发送群机器人 markdown 消息（群机器人接口不返回消息 ID）
+func (s *Sender) sendRobot(ctx context.Context, delivery *storage.Delivery, title, content string) error {
+	c, ok := s.robots[robotKey{platform: delivery.Platform, chatID: delivery.ChatID}]
+	if !ok {
+		return errRobotNotConfigured
+	}
+	if c.wecom != nil {
+		return c.wecom.SendMarkdown(ctx, content)
+	}
+	return c.dingtalk.SendMarkdown(ctx, title, content)
+}
+
+// robotHeadlineColors 企业微信 markdown 标题着色（info 绿 / comment 灰 / warning 橙红）
+var robotHeadlineColors = map[string]string{
+	"🟢": "info",
+	"🟡": "comment",
+	"🔴": "warning",
+}
+
+// formatMessageMarkdown 格式化群机器人消息（markdown），返回标题与正文
+// 企业微信标题按状态着色；钉钉不支持 font 标签，仅使用标准 markdown
+func (s *Sender) formatMessageMarkdown(event *poller.Event, lang i18n.Lang, platform string) (string, string) {
+	emoji, key := eventHeadline(event)
+	headline := i18n.Text(lang, key)
+
+	title := fmt.Sprintf("%s %s", emoji, headline)
+	heading := title
+	if color, ok := robotHeadlineColors[emoji]; ok && platform == storage.PlatformWeCom {
+		heading = fmt.Sprintf(`%s <font color="%s">%s</font>`, emoji, color, headline)
+	}
+
+	location := fmt.Sprintf("**%s** / **%s**", event.Provider, event.Service)
+	if event.Channel != "" {
+		location += fmt.Sprintf(" / **%s**", event.Channel)
+	}
+
+	// 模型信息（多模型监测组会显示所有受影响的模型）
+	var modelLine string
+	if models := extractModels(event); len(models) > 0 {
+		modelLine = i18n.Text(lang, "event.models", strings.Join(models, ", "))
+	}
+
+	var details string
+	if subStatus, ok := event.Meta["sub_status"]; ok {
+		details = i18n.Text(lang, "event.reason", fmt.Sprintf("%v", subStatus))
+	}
+	if key := eventCauseKey(event); key != "" {
+		details += i18n.Text(lang, "event.cause", i18n.Text(lang, key))
+	}
+	if days, ok := event.Meta["cert_days_remaining"]; ok {
+		details += i18n.Text(lang, "event.cert_days", days)
+	}
+
+	content := fmt.Sprintf("### %s\n\n%s%s%s\n\n%s",
+		heading, location, modelLine, details,
+		i18n.Text(lang, "event.time", formatEventTime(event)))
+
+	// 标题包含监测项，便于在通知栏区分
+	return fmt.Sprintf("%s · %s", title, event.Provider), content
+}
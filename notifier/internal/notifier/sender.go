@@ -2,6 +2,7 @@ package notifier
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"html"
 	"log/slog"
@@ -12,6 +13,7 @@ import (
 	"time"
 
 	"notifier/internal/config"
+	"notifier/internal/i18n"
 	"notifier/internal/poller"
 	"notifier/internal/qq"
 	"notifier/internal/storage"
@@ -40,6 +42,7 @@ type Sender struct {
 	storage  storage.Storage
 	tgClient *telegram.Client
 	qqClient *qq.Client
+	robots   map[robotKey]*robotClient // 群机器人（企业微信 / 钉钉），各自限流
 
 	// 平台独立限流器
 	tgRateLimiter *time.Ticker
@@ -102,6 +105,9 @@ func NewSender(cfg *config.Config, store storage.Storage) *Sender {
 	if cfg.HasQQ() {
 		s.qqClient = qq.NewClient(cfg.QQ.OneBotHTTPURL, cfg.QQ.AccessToken)
 	}
+	if cfg.HasRobots() {
+		s.robots = newRobotClients(cfg.Robots)
+	}
 
 	return s
 }
@@ -276,6 +282,12 @@ func (s *Sender) flushAggregate(key aggregateKey) {
 
 	// 获取发送用的 context
 	sendCtx := s.getSendContext()
+
+	// 记录故障（聚合后计数，同组多 model 只算一次），供定期摘要统计
+	if merged.Type == "DOWN" {
+		s.recordIncident(sendCtx, &merged)
+	}
+
 	if err := s.dispatchEvent(sendCtx, &merged); err != nil {
 		slog.Error("聚合事件发送失败",
 			"provider", key.Provider,
@@ -306,6 +318,23 @@ func (s *Sender) getSendContext() context.Context {
 	return context.Background()
 }
 
+// recordIncident 记录故障（失败仅告警，不影响通知发送）
+func (s *Sender) recordIncident(ctx context.Context, event *poller.Event) {
+	observedAt := event.ObservedAt
+	if observedAt == 0 {
+		observedAt = event.CreatedAt
+	}
+	if err := s.storage.AddIncident(ctx, &storage.Incident{
+		EventID:    event.ID,
+		Provider:   event.Provider,
+		Service:    event.Service,
+		Channel:    event.Channel,
+		ObservedAt: observedAt,
+	}); err != nil {
+		slog.Warn("记录故障失败", "event_id", event.ID, "error", err)
+	}
+}
+
 // dispatchEvent 分发事件通知给所有订阅者
 func (s *Sender) dispatchEvent(ctx context.Context, event *poller.Event) error {
 	// 查找订阅者（返回 platform + chatID）
@@ -314,6 +343,21 @@ func (s *Sender) dispatchEvent(ctx context.Context, event *poller.Event) error {
 		return fmt.Errorf("查询订阅者失败: %w", err)
 	}
 
+	// 按订阅的事件类型过滤（如仅接收 DOWN），并跳过已暂停通知的会话（/pause）
+	// 暂停的会话与被封禁的会话一样在跨平台去重前排除，关联身份会回退到下一个会话
+	now := time.Now().Unix()
+	filtered := subscribers[:0]
+	for _, ref := range subscribers {
+		if storage.IsPaused(ref.PausedUntil, now) {
+			slog.Debug("会话已暂停通知，跳过", "platform", ref.Platform, "chat_id", ref.ChatID)
+			continue
+		}
+		if storage.EventMaskAllows(ref.EventMask, event.Type) {
+			filtered = append(filtered, ref)
+		}
+	}
+	subscribers = s.dedupeLinkedChats(filtered)
+
 	if len(subscribers) == 0 {
 		return nil
 	}
@@ -344,14 +388,71 @@ func (s *Sender) dispatchEvent(ctx context.Context, event *poller.Event) error {
 			)
 			continue
 		}
+		// 投递记录已存在（ID 为 0）：已由其它实例或此前的轮询处理
+		if delivery.ID == 0 {
+			continue
+		}
 
-		// 异步发送
-		go s.sendNotification(ctx, delivery, event)
+		// 异步发送（按订阅者的语言偏好渲染）
+		go s.sendNotification(ctx, delivery, event, i18n.Resolve(ref.Language, ""))
 	}
 
 	return nil
 }
 
+// dedupeLinkedChats 跨平台去重：同一身份（/link 关联）的多个会话只保留一个投递目标
+// 按身份内的投递顺序选择首个平台已启用的会话（如首选 QQ 但 QQ 未配置时回退到 Telegram）；
+// 被封禁的会话已在查询订阅者时排除，因此首选会话停用后自动回退到下一个
+func (s *Sender) dedupeLinkedChats(refs []*storage.ChatRef) []*storage.ChatRef {
+	chosen := make(map[string]*storage.ChatRef)
+	for _, ref := range refs {
+		if ref.LinkID == "" {
+			continue
+		}
+		cur := chosen[ref.LinkID]
+		if cur == nil || linkedChatBefore(ref, cur, s.platformEnabled) {
+			chosen[ref.LinkID] = ref
+		}
+	}
+	if len(chosen) == 0 {
+		return refs
+	}
+
+	deduped := refs[:0]
+	for _, ref := range refs {
+		if ref.LinkID != "" && chosen[ref.LinkID] != ref {
+			slog.Debug("跨平台去重跳过会话",
+				"platform", ref.Platform,
+				"chat_id", ref.ChatID,
+				"link_id", ref.LinkID,
+			)
+			continue
+		}
+		deduped = append(deduped, ref)
+	}
+	return deduped
+}
+
+// linkedChatBefore 判断同一身份内 a 是否应优先于 b 投递：平台可用优先，其次按投递顺序
+func linkedChatBefore(a, b *storage.ChatRef, enabled func(string) bool) bool {
+	if ea, eb := enabled(a.Platform), enabled(b.Platform); ea != eb {
+		return ea
+	}
+	return a.LinkPriority < b.LinkPriority
+}
+
+// platformEnabled 判断平台客户端是否已配置
+func (s *Sender) platformEnabled(platform string) bool {
+	switch platform {
+	case storage.PlatformTelegram:
+		return s.tgClient != nil
+	case storage.PlatformQQ:
+		return s.qqClient != nil
+	default:
+		return true
+	}
+}
+
 // sleepWithContext 带 context 的 sleep，返回 true 表示正常完成，false 表示被取消
 func (s *Sender) sleepWithContext(ctx context.Context, d time.Duration) bool {
 	if d <= 0 {
@@ -428,7 +529,7 @@ func (s *Sender) waitPlatformRateLimit(ctx context.Context, platform string) boo
 }
 
 // sendNotification 发送单条通知（多平台路由）
-func (s *Sender) sendNotification(ctx context.Context, delivery *storage.Delivery, event *poller.Event) {
+func (s *Sender) sendNotification(ctx context.Context, delivery *storage.Delivery, event *poller.Event, lang i18n.Lang) {
 	// 等待平台限流
 	if !s.waitPlatformRateLimit(ctx, delivery.Platform) {
 		return
@@ -441,9 +542,12 @@ func (s *Sender) sendNotification(ctx context.Context, delivery *storage.Deliver
 
 	switch delivery.Platform {
 	case storage.PlatformTelegram:
-		messageID, err = s.sendTelegram(ctx, delivery, event)
+		messageID, err = s.sendTelegram(ctx, delivery, event, lang)
 	case storage.PlatformQQ:
-		messageID, err = s.sendQQ(ctx, delivery, event)
+		messageID, err = s.sendQQ(ctx, delivery, event, lang)
+	case storage.PlatformWeCom, storage.PlatformDingTalk:
+		title, content := s.formatMessageMarkdown(event, lang, delivery.Platform)
+		err = s.sendRobot(ctx, delivery, title, content)
 	default:
 		err = fmt.Errorf("unknown platform: %s", delivery.Platform)
 	}
@@ -460,12 +564,12 @@ func (s *Sender) sendNotification(ctx context.Context, delivery *storage.Deliver
 }
 
 // sendTelegram 发送 Telegram 消息
-func (s *Sender) sendTelegram(ctx context.Context, delivery *storage.Delivery, event *poller.Event) (string, error) {
+func (s *Sender) sendTelegram(ctx context.Context, delivery *storage.Delivery, event *poller.Event, lang i18n.Lang) (string, error) {
 	if s.tgClient == nil {
 		return "", fmt.Errorf("telegram client not configured")
 	}
 
-	msg := s.formatMessageTelegram(event)
+	msg := s.formatMessageTelegram(event, lang)
 	result, err := s.tgClient.SendMessageHTML(ctx, delivery.ChatID, msg)
 	if err != nil {
 		return "", err
@@ -475,12 +579,12 @@ func (s *Sender) sendTelegram(ctx context.Context, delivery *storage.Delivery, e
 }
 
 // sendQQ 发送 QQ 消息
-func (s *Sender) sendQQ(ctx context.Context, delivery *storage.Delivery, event *poller.Event) (string, error) {
+func (s *Sender) sendQQ(ctx context.Context, delivery *storage.Delivery, event *poller.Event, lang i18n.Lang) (string, error) {
 	if s.qqClient == nil {
 		return "", fmt.Errorf("qq client not configured")
 	}
 
-	text := s.formatMessageQQ(event)
+	text := s.formatMessageQQ(event, lang)
 	var mid int64
 	var err error
 
@@ -567,61 +671,86 @@ func (s *Sender) handleSendError(ctx context.Context, delivery *storage.Delivery
 		"delivery_id", delivery.ID,
 		"platform", delivery.Platform,
 		"chat_id", delivery.ChatID,
+		"retry_count", delivery.RetryCount,
 		"error", sendErr,
 	)
 
-	// Telegram 平台检查是否被封禁
-	if delivery.Platform == storage.PlatformTelegram && telegram.IsForbiddenError(sendErr) {
+	// 不可恢复的错误（Telegram 被封禁 / 群机器人已移除）
+	if reason := undeliverableReason(delivery.Platform, sendErr); reason != "" {
 		// 标记用户为 blocked
 		if err := s.storage.UpdateChatStatus(ctx, delivery.Platform, delivery.ChatID, storage.ChatStatusBlocked); err != nil {
 			slog.Error("更新用户状态失败", "error", err)
 		}
 		// 标记投递失败
-		if err := s.storage.UpdateDeliveryStatus(ctx, delivery.ID, storage.DeliveryStatusFailed, "", "user blocked bot"); err != nil {
+		if err := s.storage.UpdateDeliveryStatus(ctx, delivery.ID, storage.DeliveryStatusFailed, "", reason); err != nil {
 			slog.Error("更新投递状态失败", "error", err)
 		}
 		return
 	}
 
-	// 增加重试计数
-	if err := s.storage.IncrementRetryCount(ctx, delivery.ID); err != nil {
-		slog.Error("增加重试计数失败", "error", err)
-	}
+	s.scheduleRetry(ctx, delivery, sendErr)
+}
 
-	// 更新错误信息
-	if err := s.storage.UpdateDeliveryStatus(ctx, delivery.ID, storage.DeliveryStatusPending, "", sendErr.Error()); err != nil {
-		slog.Error("更新投递状态失败", "error", err)
+// undeliverableReason 判断不可恢复的发送错误（停用会话、不再重试），返回空字符串表示可重试
+func undeliverableReason(platform string, err error) string {
+	switch {
+	case platform == storage.PlatformTelegram && telegram.IsForbiddenError(err):
+		return "user blocked bot"
+	case isRobotPlatform(platform) && errors.Is(err, errRobotNotConfigured):
+		return "robot not configured"
 	}
+	return ""
 }
 
-// formatMessageTelegram 格式化 Telegram 消息（HTML）
-func (s *Sender) formatMessageTelegram(event *poller.Event) string {
-	var emoji string
-	var statusText string
-
+// eventHeadline 事件的 emoji 与状态文案 key
+func eventHeadline(event *poller.Event) (emoji, key string) {
 	switch event.Type {
 	case "UP":
-		emoji = "🟢"
-		statusText = "服务已恢复"
+		return "🟢", "event.up"
 	case "DOWN":
-		emoji = "🔴"
-		statusText = "服务不可用"
+		return "🔴", "event.down"
+	case "CERT_EXPIRING":
+		return "🟡", "event.cert_expiring"
+	case "DEGRADED_START":
+		return "🟡", "event.degraded_start"
+	case "DEGRADED_END":
+		return "🟢", "event.degraded_end"
+	}
+	switch event.ToStatus {
+	case 1:
+		return "🟢", "event.up"
+	case 2:
+		return "🟡", "event.flapping"
+	case 0:
+		return "🔴", "event.down"
 	default:
-		switch event.ToStatus {
-		case 1:
-			emoji = "🟢"
-			statusText = "服务已恢复"
-		case 2:
-			emoji = "🟡"
-			statusText = "服务波动"
-		case 0:
-			emoji = "🔴"
-			statusText = "服务不可用"
-		default:
-			emoji = "⚪"
-			statusText = "状态变更"
-		}
+		return "⚪", "event.changed"
 	}
+}
+
+// eventCauseKey DOWN 事件可能原因（meta.cause，由主服务分类）的文案 key，无分类或未知分类时返回空
+func eventCauseKey(event *poller.Event) string {
+	cause, _ := event.Meta["cause"].(string)
+	switch cause {
+	case "auth_expired", "upstream_outage", "rate_limited", "network", "content_change":
+		return "cause." + cause
+	}
+	return ""
+}
+
+// formatEventTime 事件时间（CST，UTC+8）
+func formatEventTime(event *poller.Event) string {
+	eventTs := event.ObservedAt
+	if eventTs == 0 {
+		eventTs = event.CreatedAt
+	}
+	cst := time.FixedZone("CST", 8*60*60)
+	return time.Unix(eventTs, 0).In(cst).Format("2006-01-02 15:04:05")
+}
+
+// formatMessageTelegram 格式化 Telegram 消息（HTML）
+func (s *Sender) formatMessageTelegram(event *poller.Event, lang i18n.Lang) string {
+	emoji, key := eventHeadline(event)
 
 	// 转义 HTML 防止注入
 	provider := html.EscapeString(event.Provider)
@@ -635,66 +764,33 @@ func (s *Sender) formatMessageTelegram(event *poller.Event) string {
 
 	// 模型信息（多模型监测组会显示所有受影响的模型）
 	var modelLine string
-	models := extractModels(event)
-	if len(models) == 1 {
-		modelLine = fmt.Sprintf("\n模型: %s", html.EscapeString(models[0]))
-	} else if len(models) > 1 {
-		modelLine = fmt.Sprintf("\n模型: %s", html.EscapeString(strings.Join(models, ", ")))
+	if models := extractModels(event); len(models) > 0 {
+		modelLine = i18n.HTML(lang, "event.models", strings.Join(models, ", "))
 	}
 
 	var details string
 	if subStatus, ok := event.Meta["sub_status"]; ok {
-		details = fmt.Sprintf("\n原因: %s", html.EscapeString(fmt.Sprintf("%v", subStatus)))
+		details = i18n.HTML(lang, "event.reason", fmt.Sprintf("%v", subStatus))
 	}
-
-	eventTs := event.ObservedAt
-	if eventTs == 0 {
-		eventTs = event.CreatedAt
+	if key := eventCauseKey(event); key != "" {
+		details += i18n.HTML(lang, "event.cause", i18n.Text(lang, key))
+	}
+	if days, ok := event.Meta["cert_days_remaining"]; ok {
+		details += i18n.HTML(lang, "event.cert_days", days)
 	}
-	cst := time.FixedZone("CST", 8*60*60)
-	eventTime := time.Unix(eventTs, 0).In(cst).Format("2006-01-02 15:04:05")
-
-	return fmt.Sprintf(`%s <b>%s</b>
-
-%s%s%s
 
-时间: %s`,
-		emoji, statusText,
+	return fmt.Sprintf("%s <b>%s</b>\n\n%s%s%s\n\n%s",
+		emoji, i18n.HTML(lang, key),
 		location,
 		modelLine,
 		details,
-		eventTime,
+		i18n.HTML(lang, "event.time", formatEventTime(event)),
 	)
 }
 
 // formatMessageQQ 格式化 QQ 消息（纯文本）
-func (s *Sender) formatMessageQQ(event *poller.Event) string {
-	var emoji string
-	var statusText string
-
-	switch event.Type {
-	case "UP":
-		emoji = "🟢"
-		statusText = "服务已恢复"
-	case "DOWN":
-		emoji = "🔴"
-		statusText = "服务不可用"
-	default:
-		switch event.ToStatus {
-		case 1:
-			emoji = "🟢"
-			statusText = "服务已恢复"
-		case 2:
-			emoji = "🟡"
-			statusText = "服务波动"
-		case 0:
-			emoji = "🔴"
-			statusText = "服务不可用"
-		default:
-			emoji = "⚪"
-			statusText = "状态变更"
-		}
-	}
+func (s *Sender) formatMessageQQ(event *poller.Event, lang i18n.Lang) string {
+	emoji, key := eventHeadline(event)
 
 	location := fmt.Sprintf("%s / %s", event.Provider, event.Service)
 	if event.Channel != "" {
@@ -703,139 +799,22 @@ func (s *Sender) formatMessageQQ(event *poller.Event) string {
 
 	// 模型信息（多模型监测组会显示所有受影响的模型）
 	var modelLine string
-	models := extractModels(event)
-	if len(models) == 1 {
-		modelLine = fmt.Sprintf("\n模型: %s", models[0])
-	} else if len(models) > 1 {
-		modelLine = fmt.Sprintf("\n模型: %s", strings.Join(models, ", "))
+	if models := extractModels(event); len(models) > 0 {
+		modelLine = i18n.Text(lang, "event.models", strings.Join(models, ", "))
 	}
 
 	var details string
 	if subStatus, ok := event.Meta["sub_status"]; ok {
-		details = fmt.Sprintf("\n原因: %v", subStatus)
-	}
-
-	eventTs := event.ObservedAt
-	if eventTs == 0 {
-		eventTs = event.CreatedAt
-	}
-	cst := time.FixedZone("CST", 8*60*60)
-	eventTime := time.Unix(eventTs, 0).In(cst).Format("2006-01-02 15:04:05")
-
-	return fmt.Sprintf("%s %s\n\n%s%s%s\n\n时间: %s", emoji, statusText, location, modelLine, details, eventTime)
-}
-
-// retryLoop 重试失败的投递
-func (s *Sender) retryLoop(ctx context.Context) {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-s.stopChan:
-			return
-		case <-ticker.C:
-			s.processRetries(ctx)
-		}
-	}
-}
-
-// processRetries 处理重试
-func (s *Sender) processRetries(ctx context.Context) {
-	deliveries, err := s.storage.GetPendingDeliveries(ctx, 100)
-	if err != nil {
-		slog.Error("获取待重试投递失败", "error", err)
-		return
-	}
-
-	for _, delivery := range deliveries {
-		// 检查重试次数
-		if delivery.RetryCount >= s.cfg.Limits.MaxRetries {
-			// 超过最大重试次数，标记为失败
-			if err := s.storage.UpdateDeliveryStatus(ctx, delivery.ID, storage.DeliveryStatusFailed, "", "max retries exceeded"); err != nil {
-				slog.Error("更新投递状态失败", "error", err)
-			}
-			continue
-		}
-
-		// 重新发送
-		go s.retryDelivery(ctx, delivery)
-	}
-}
-
-// retryDelivery 重试单条投递
-func (s *Sender) retryDelivery(ctx context.Context, delivery *storage.Delivery) {
-	// 等待平台限流
-	if !s.waitPlatformRateLimit(ctx, delivery.Platform) {
-		return
+		details = i18n.Text(lang, "event.reason", fmt.Sprintf("%v", subStatus))
 	}
-
-	// 简单的重试消息
-	msg := fmt.Sprintf("🔔 通知重试 (event_id: %d)\n\n如果您持续收到此消息，请检查订阅设置。", delivery.EventID)
-
-	var err error
-	var messageID string
-
-	switch delivery.Platform {
-	case storage.PlatformTelegram:
-		if s.tgClient == nil {
-			err = fmt.Errorf("telegram client not configured")
-		} else {
-			result, sendErr := s.tgClient.SendMessageHTML(ctx, delivery.ChatID, msg)
-			if sendErr == nil && result != nil {
-				messageID = fmt.Sprintf("%d", result.MessageID)
-			}
-			err = sendErr
-		}
-
-	case storage.PlatformQQ:
-		if s.qqClient == nil {
-			err = fmt.Errorf("qq client not configured")
-		} else {
-			var mid int64
-			if delivery.ChatID < 0 {
-				mid, err = s.qqClient.SendGroupMessage(ctx, -delivery.ChatID, msg)
-			} else {
-				mid, err = s.qqClient.SendPrivateMessage(ctx, delivery.ChatID, msg)
-			}
-			if err == nil {
-				messageID = fmt.Sprintf("%d", mid)
-			}
-		}
-
-	default:
-		err = fmt.Errorf("unknown platform: %s", delivery.Platform)
+	if key := eventCauseKey(event); key != "" {
+		details += i18n.Text(lang, "event.cause", i18n.Text(lang, key))
 	}
-
-	if err != nil {
-		slog.Warn("重试投递失败",
-			"delivery_id", delivery.ID,
-			"platform", delivery.Platform,
-			"chat_id", delivery.ChatID,
-			"retry_count", delivery.RetryCount,
-			"error", err,
-		)
-
-		// Telegram 封禁检查
-		if delivery.Platform == storage.PlatformTelegram && telegram.IsForbiddenError(err) {
-			if err := s.storage.UpdateChatStatus(ctx, delivery.Platform, delivery.ChatID, storage.ChatStatusBlocked); err != nil {
-				slog.Error("更新用户状态失败", "error", err)
-			}
-			if err := s.storage.UpdateDeliveryStatus(ctx, delivery.ID, storage.DeliveryStatusFailed, "", "user blocked bot"); err != nil {
-				slog.Error("更新投递状态失败", "error", err)
-			}
-			return
-		}
-
-		if err := s.storage.IncrementRetryCount(ctx, delivery.ID); err != nil {
-			slog.Error("增加重试计数失败", "error", err)
-		}
-		return
+	if days, ok := event.Meta["cert_days_remaining"]; ok {
+		details += i18n.Text(lang, "event.cert_days", days)
 	}
 
-	if err := s.storage.UpdateDeliveryStatus(ctx, delivery.ID, storage.DeliveryStatusSent, messageID, ""); err != nil {
-		slog.Error("更新投递状态失败", "error", err)
-	}
+	return fmt.Sprintf("%s %s\n\n%s%s%s\n\n%s",
+		emoji, i18n.Text(lang, key), location, modelLine, details,
+		i18n.Text(lang, "event.time", formatEventTime(event)))
 }
@@ -0,0 +1,182 @@
+package poller
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"notifier/internal/config"
+	"notifier/internal/storage"
+)
+
+// memoryLease 内存版租约，语义与 PostgresStorage 的 poll_cursor 租约一致：
+// 持有者续约、空闲或过期时任意实例可获取、仅持有者可释放
+type memoryLease struct {
+	mu       sync.Mutex
+	now      time.Time
+	leader   string
+	until    time.Time
+	releases int
+	err      error
+}
+
+func (l *memoryLease) TryAcquireLeadership(ctx context.Context, instanceID string, lease time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return false, l.err
+	}
+	if l.leader == instanceID || l.leader == "" || l.until.Before(l.now) {
+		l.leader = instanceID
+		l.until = l.now.Add(lease)
+		return true, nil
+	}
+	return false, nil
+}
+
+func (l *memoryLease) ReleaseLeadership(ctx context.Context, instanceID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.leader == instanceID {
+		l.leader = ""
+		l.until = time.Time{}
+		l.releases++
+	}
+	return nil
+}
+
+func (l *memoryLease) advance(d time.Duration) {
+	l.mu.Lock()
+	l.now = l.now.Add(d)
+	l.mu.Unlock()
+}
+
+func (l *memoryLease) holder() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.leader
+}
+
+// leaseStore 只提供选主能力的存储（选主路径不访问其余存储方法）
+type leaseStore struct {
+	storage.Storage
+	*memoryLease
+}
+
+const testLease = 30 * time.Second
+
+// newLeaderPoller 创建已处于运行状态的轮询器（不启动轮询协程）
+func newLeaderPoller(lease *memoryLease, instanceID string) *Poller {
+	cfg := &config.Config{}
+	cfg.Database.InstanceID = instanceID
+	cfg.Database.LeaderLease = testLease
+	p := NewPoller(cfg, leaseStore{memoryLease: lease}, nil)
+	p.running = true
+	return p
+}
+
+func TestLeaderAcquireAndRenew(t *testing.T) {
+	lease := &memoryLease{now: time.Unix(1000, 0)}
+	a := newLeaderPoller(lease, "a")
+	b := newLeaderPoller(lease, "b")
+
+	if !a.acquireLeadership(context.Background()) {
+		t.Fatal("空闲租约应被 a 获取")
+	}
+	if b.acquireLeadership(context.Background()) {
+		t.Fatal("租约有效期内 b 不应成为 leader")
+	}
+
+	// a 在租约过半时续约，原到期时间之后 b 仍无法接管
+	lease.advance(testLease / 2)
+	if !a.acquireLeadership(context.Background()) {
+		t.Fatal("leader 续约应成功")
+	}
+	lease.advance(testLease/2 + time.Second)
+	if b.acquireLeadership(context.Background()) {
+		t.Fatal("续约后的租约仍有效，b 不应接管")
+	}
+	if !a.isLeader || b.isLeader {
+		t.Fatalf("isLeader 状态错误: a=%v b=%v", a.isLeader, b.isLeader)
+	}
+}
+
+func TestLeaderExpiryTakeover(t *testing.T) {
+	lease := &memoryLease{now: time.Unix(1000, 0)}
+	a := newLeaderPoller(lease, "a")
+	b := newLeaderPoller(lease, "b")
+
+	if !a.acquireLeadership(context.Background()) {
+		t.Fatal("空闲租约应被 a 获取")
+	}
+
+	// a 未续约（如进程卡住），租约过期后 b 接管，a 随后不再视为 leader
+	lease.advance(testLease + time.Second)
+	if !b.acquireLeadership(context.Background()) {
+		t.Fatal("租约过期后 b 应接管")
+	}
+	if a.acquireLeadership(context.Background()) {
+		t.Fatal("被接管后 a 不应再成为 leader")
+	}
+	if a.isLeader || !b.isLeader || lease.holder() != "b" {
+		t.Fatalf("接管后状态错误: a=%v b=%v holder=%q", a.isLeader, b.isLeader, lease.holder())
+	}
+}
+
+func TestLeaderReleaseOnStop(t *testing.T) {
+	lease := &memoryLease{now: time.Unix(1000, 0)}
+	a := newLeaderPoller(lease, "a")
+	b := newLeaderPoller(lease, "b")
+
+	if !a.acquireLeadership(context.Background()) {
+		t.Fatal("空闲租约应被 a 获取")
+	}
+
+	// 优雅关闭主动释放，b 无需等待租约过期
+	a.Stop()
+	if lease.releases != 1 || lease.holder() != "" {
+		t.Fatalf("Stop 应释放租约: releases=%d holder=%q", lease.releases, lease.holder())
+	}
+	if !b.acquireLeadership(context.Background()) {
+		t.Fatal("a 释放后 b 应立即成为 leader")
+	}
+
+	// 非 leader 关闭不触碰他人的租约
+	a.Stop()
+	if lease.releases != 1 || lease.holder() != "b" {
+		t.Fatalf("非 leader 关闭不应释放租约: releases=%d holder=%q", lease.releases, lease.holder())
+	}
+}
+
+func TestLeaderAcquireAfterStopReleases(t *testing.T) {
+	lease := &memoryLease{now: time.Unix(1000, 0)}
+	a := newLeaderPoller(lease, "a")
+
+	// Stop 与进行中的获取并发：Stop 之后拿到的租约立即交还
+	a.Stop()
+	if a.acquireLeadership(context.Background()) {
+		t.Fatal("停止后不应成为 leader")
+	}
+	if a.isLeader || lease.holder() != "" {
+		t.Fatalf("停止后获取的租约应立即释放: isLeader=%v holder=%q", a.isLeader, lease.holder())
+	}
+}
+
+func TestLeaderAcquireErrorSkipsRound(t *testing.T) {
+	lease := &memoryLease{now: time.Unix(1000, 0)}
+	a := newLeaderPoller(lease, "a")
+	if !a.acquireLeadership(context.Background()) {
+		t.Fatal("空闲租约应被 a 获取")
+	}
+
+	// 续约失败时保守地放弃本轮，避免与可能的新 leader 重复推送
+	lease.err = errors.New("connection refused")
+	if a.acquireLeadership(context.Background()) {
+		t.Fatal("续约失败时应跳过本轮")
+	}
+	if a.isLeader {
+		t.Fatal("续约失败后不应再视为 leader")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math/rand"
 	"net/http"
 	"strconv"
 	"sync"
@@ -23,7 +24,7 @@ type Event struct {
 	Service         string         `json:"service"`
 	Channel         string         `json:"channel,omitempty"`
 	Model           string         `json:"model,omitempty"`
-	Type            string         `json:"type"`              // DOWN 或 UP
+	Type            string         `json:"type"`              // DOWN / UP / CERT_EXPIRING / DEGRADED_START / DEGRADED_END
 	FromStatus      int            `json:"from_status"`       // 变更前状态
 	ToStatus        int            `json:"to_status"`         // 变更后状态
 	TriggerRecordID int64          `json:"trigger_record_id"` // 触发记录ID
@@ -42,24 +43,51 @@ type EventsResponse struct {
 	} `json:"meta"`
 }
 
+// LatestEventResponse /api/events/latest 响应
+type LatestEventResponse struct {
+	LatestID *int64 `json:"latest_id"` // 指针区分缺失与 0，避免误判为回退
+}
+
 // EventHandler 事件处理回调
 type EventHandler func(ctx context.Context, event *Event) error
 
+// AlertHandler 运维告警回调（key 为 i18n 消息键）
+type AlertHandler func(ctx context.Context, key string, args ...any)
+
+const (
+	latestCheckInterval  = 5 * time.Minute     // 空轮询时核对主服务最新事件 ID 的最小间隔（用于发现事件 ID 回退）
+	eventCacheRetention  = 30 * 24 * time.Hour // 事件缓存保留时长（覆盖 /stats 统计窗口）
+	eventCleanupInterval = time.Hour           // 事件缓存清理间隔
+)
+
 // Poller 事件轮询器
 type Poller struct {
 	cfg        *config.Config
 	storage    storage.Storage
 	httpClient *http.Client
 	handler    EventHandler
+	alert      AlertHandler
+
+	// 以下字段仅在轮询协程内访问
+	failures        int       // 连续请求失败次数
+	backoffUntil    time.Time // 退避截止时间，之前的轮询直接跳过
+	lastLatestCheck time.Time // 上次核对最新事件 ID 的时间
+	lastCleanup     time.Time // 上次清理事件缓存的时间
 
 	mu       sync.Mutex
 	running  bool
 	stopChan chan struct{}
+
+	// 多实例选主（存储实现 storage.LeaderElector 时生效）
+	elector  storage.LeaderElector
+	isLeader bool
 }
 
 // NewPoller 创建轮询器
 func NewPoller(cfg *config.Config, store storage.Storage, handler EventHandler) *Poller {
+	elector, _ := store.(storage.LeaderElector)
 	return &Poller{
+		elector: elector,
 		cfg:     cfg,
 		storage: store,
 		httpClient: &http.Client{
@@ -70,6 +98,11 @@ func NewPoller(cfg *config.Config, store storage.Storage, handler EventHandler)
 	}
 }
 
+// SetAlertHandler 设置运维告警回调（检测到事件 ID 回退时调用）
+func (p *Poller) SetAlertHandler(handler AlertHandler) {
+	p.alert = handler
+}
+
 // Start 启动轮询
 func (p *Poller) Start(ctx context.Context) error {
 	p.mu.Lock()
@@ -84,6 +117,8 @@ func (p *Poller) Start(ctx context.Context) error {
 	slog.Info("事件轮询器启动",
 		"events_url", p.cfg.RelayPulse.EventsURL,
 		"poll_interval", p.cfg.RelayPulse.PollInterval,
+		"leader_election", p.elector != nil,
+		"instance_id", p.cfg.Database.InstanceID,
 	)
 
 	ticker := time.NewTicker(p.cfg.RelayPulse.PollInterval)
@@ -109,16 +144,78 @@ func (p *Poller) Start(ctx context.Context) error {
 // Stop 停止轮询
 func (p *Poller) Stop() {
 	p.mu.Lock()
-	defer p.mu.Unlock()
-
 	if p.running {
 		close(p.stopChan)
 		p.running = false
 	}
+	wasLeader := p.isLeader
+	p.isLeader = false
+	p.mu.Unlock()
+
+	// 主动交出领导权，其它实例无需等待租约过期（网络调用不持有 p.mu）
+	if p.elector != nil && wasLeader {
+		p.releaseLeadership()
+	}
+}
+
+// releaseLeadership 释放 leader 租约
+func (p *Poller) releaseLeadership() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := p.elector.ReleaseLeadership(ctx, p.cfg.Database.InstanceID); err != nil {
+		slog.Warn("释放 leader 租约失败", "error", err)
+	}
+}
+
+// acquireLeadership 获取或续约领导权，返回本轮是否应执行轮询
+// 单实例存储（未实现 LeaderElector）始终返回 true；续约失败时保守地跳过本轮
+// isLeader 的读写均持有 p.mu；Stop 之后才拿到的租约立即释放，不再视为 leader
+func (p *Poller) acquireLeadership(ctx context.Context) bool {
+	if p.elector == nil {
+		return true
+	}
+
+	leader, err := p.elector.TryAcquireLeadership(ctx, p.cfg.Database.InstanceID, p.cfg.Database.LeaderLease)
+	if err != nil {
+		slog.Warn("获取 leader 租约失败，跳过本轮轮询", "error", err)
+		leader = false
+	}
+
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		if leader {
+			p.releaseLeadership()
+		}
+		return false
+	}
+	if leader != p.isLeader {
+		if leader {
+			slog.Info("当前实例成为 Poller leader", "instance_id", p.cfg.Database.InstanceID, "lease", p.cfg.Database.LeaderLease)
+		} else {
+			slog.Info("当前实例不再是 Poller leader", "instance_id", p.cfg.Database.InstanceID)
+		}
+		p.isLeader = leader
+	}
+	p.mu.Unlock()
+	return leader
 }
 
 // poll 执行一次轮询
+// 游标落后（长时间停机或积压）时按 batch_size 连续分页追赶，每轮最多 catchup_pages 页，剩余的下一轮继续
 func (p *Poller) poll(ctx context.Context) {
+	// 多实例部署时仅 leader 轮询
+	if !p.acquireLeadership(ctx) {
+		return
+	}
+
+	// 请求失败后的退避期内跳过
+	if time.Now().Before(p.backoffUntil) {
+		return
+	}
+
+	p.cleanupEvents(ctx)
+
 	// 获取游标
 	cursor, err := p.storage.GetCursor(ctx)
 	if err != nil {
@@ -126,21 +223,67 @@ func (p *Poller) poll(ctx context.Context) {
 		return
 	}
 
-	// 获取事件
-	events, err := p.fetchEvents(ctx, cursor)
-	if err != nil {
-		slog.Warn("获取事件失败", "error", err)
-		return
-	}
+	for page := 1; ; page++ {
+		resp, err := p.fetchEvents(ctx, cursor)
+		if err != nil {
+			p.backoff(err)
+			return
+		}
+		p.resetBackoff()
 
-	if len(events) == 0 {
-		return
+		if len(resp.Events) == 0 {
+			p.checkRegression(ctx, cursor)
+			return
+		}
+
+		slog.Debug("获取到新事件", "count", len(resp.Events), "since_id", cursor, "page", page)
+
+		maxID := p.handleEvents(ctx, cursor, resp.Events)
+		if maxID > cursor {
+			if err := p.storage.UpdateCursor(ctx, maxID); err != nil {
+				slog.Error("更新游标失败", "error", err)
+				return
+			}
+		}
+
+		if !resp.Meta.HasMore {
+			if page > 1 {
+				slog.Info("事件追赶完成", "pages", page, "cursor", maxID)
+			}
+			return
+		}
+		if maxID <= cursor {
+			slog.Warn("本页事件均处理失败，停止本轮追赶", "since_id", cursor)
+			return
+		}
+		if page == 1 {
+			slog.Info("游标落后，开始分页追赶", "since_id", cursor, "batch_size", p.cfg.RelayPulse.BatchSize)
+		}
+		if page >= p.cfg.RelayPulse.CatchUpPages {
+			slog.Info("本轮追赶达到页数上限，下一轮继续", "pages", page, "cursor", maxID)
+			return
+		}
+		cursor = maxID
+
+		// 追赶可能持续较久，每页前续约领导权并检查停止信号
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.stopChan:
+			return
+		default:
+		}
+		if !p.acquireLeadership(ctx) {
+			return
+		}
 	}
+}
 
-	slog.Debug("获取到新事件", "count", len(events), "since_id", cursor)
+// handleEvents 依次处理一页事件，返回处理成功的最大事件 ID（全部失败时为 cursor）
+func (p *Poller) handleEvents(ctx context.Context, cursor int64, events []Event) int64 {
+	p.cacheEvents(ctx, events)
 
-	// 处理事件
-	var maxID int64 = cursor
+	maxID := cursor
 	for _, event := range events {
 		if err := p.handler(ctx, &event); err != nil {
 			slog.Error("处理事件失败", "event_id", event.ID, "error", err)
@@ -151,22 +294,142 @@ func (p *Poller) poll(ctx context.Context) {
 			maxID = event.ID
 		}
 	}
+	return maxID
+}
 
-	// 更新游标
-	if maxID > cursor {
-		if err := p.storage.UpdateCursor(ctx, maxID); err != nil {
-			slog.Error("更新游标失败", "error", err)
+// cacheEvents 缓存事件元数据供 /stats 关联投递记录，失败不影响通知
+func (p *Poller) cacheEvents(ctx context.Context, events []Event) {
+	cached := make([]*storage.CachedEvent, 0, len(events))
+	for _, e := range events {
+		observedAt := e.ObservedAt
+		if observedAt == 0 {
+			observedAt = e.CreatedAt
 		}
+		cached = append(cached, &storage.CachedEvent{
+			ID:         e.ID,
+			Provider:   e.Provider,
+			Service:    e.Service,
+			Channel:    e.Channel,
+			Model:      e.Model,
+			Type:       e.Type,
+			ObservedAt: observedAt,
+		})
+	}
+	if err := p.storage.CacheEvents(ctx, cached); err != nil {
+		slog.Warn("缓存事件失败", "count", len(cached), "error", err)
+	}
+}
+
+// cleanupEvents 定期清理过期的事件缓存（仅 leader 执行）
+func (p *Poller) cleanupEvents(ctx context.Context) {
+	if time.Since(p.lastCleanup) < eventCleanupInterval {
+		return
+	}
+	p.lastCleanup = time.Now()
+
+	deleted, err := p.storage.CleanupOldEvents(ctx, time.Now().Add(-eventCacheRetention))
+	if err != nil && ctx.Err() == nil {
+		slog.Warn("清理事件缓存失败", "error", err)
+	} else if deleted > 0 {
+		slog.Info("事件缓存清理完成", "deleted", deleted)
 	}
 }
 
-// fetchEvents 从 relay-pulse 获取事件
-func (p *Poller) fetchEvents(ctx context.Context, sinceID int64) ([]Event, error) {
-	url := p.cfg.RelayPulse.EventsURL + "?since_id=" + strconv.FormatInt(sinceID, 10)
+// backoff 记录一次请求失败，按 poll_interval × 2^n 退避（上限 max_backoff）
+func (p *Poller) backoff(err error) {
+	p.failures++
+	delay := pollBackoff(p.failures, p.cfg.RelayPulse.PollInterval, p.cfg.RelayPulse.MaxBackoff)
+	p.backoffUntil = time.Now().Add(delay)
+	slog.Warn("获取事件失败，退避后重试", "error", err, "failures", p.failures, "backoff", delay)
+}
 
+// resetBackoff 请求成功后清除退避状态
+func (p *Poller) resetBackoff() {
+	if p.failures == 0 {
+		return
+	}
+	slog.Info("事件 API 恢复", "failures", p.failures)
+	p.failures = 0
+	p.backoffUntil = time.Time{}
+}
+
+// pollBackoff 第 failures 次连续失败后的退避时间：base × 2^failures，不超过 maxDelay
+// 在 [delay/2, delay) 内随机取值，避免主服务恢复时多个 notifier 同时重试
+func pollBackoff(failures int, base, maxDelay time.Duration) time.Duration {
+	delay := base
+	for i := 0; i < failures && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	if half := int64(delay) / 2; half > 0 {
+		return time.Duration(half + rand.Int63n(half))
+	}
+	return delay
+}
+
+// checkRegression 检测主服务事件 ID 回退（换库或恢复旧备份）
+// 回退后游标大于所有新事件 ID，轮询会一直拿不到事件，因此回拨游标到主服务最新事件 ID 并告警
+func (p *Poller) checkRegression(ctx context.Context, cursor int64) {
+	if cursor == 0 || time.Since(p.lastLatestCheck) < latestCheckInterval {
+		return
+	}
+	p.lastLatestCheck = time.Now()
+
+	latestID, err := p.fetchLatestID(ctx)
+	if err != nil {
+		slog.Warn("查询最新事件 ID 失败", "error", err)
+		return
+	}
+	if latestID >= cursor {
+		return
+	}
+
+	deleted, err := p.storage.ResetCursor(ctx, latestID)
+	if err != nil {
+		slog.Error("重置游标失败", "error", err)
+		return
+	}
+	slog.Warn("检测到事件 ID 回退，已重置游标",
+		"cursor", cursor,
+		"latest_id", latestID,
+		"deleted_deliveries", deleted,
+	)
+	if p.alert != nil {
+		p.alert(ctx, "alert.event_regression", cursor, latestID, deleted)
+	}
+}
+
+// fetchEvents 从 relay-pulse 获取一页事件
+func (p *Poller) fetchEvents(ctx context.Context, sinceID int64) (*EventsResponse, error) {
+	url := p.cfg.RelayPulse.EventsURL + "?since_id=" + strconv.FormatInt(sinceID, 10) +
+		"&limit=" + strconv.Itoa(p.cfg.RelayPulse.BatchSize)
+
+	var eventsResp EventsResponse
+	if err := p.getJSON(ctx, url, &eventsResp); err != nil {
+		return nil, err
+	}
+	return &eventsResp, nil
+}
+
+// fetchLatestID 从 relay-pulse 获取最新事件 ID
+func (p *Poller) fetchLatestID(ctx context.Context) (int64, error) {
+	var latest LatestEventResponse
+	if err := p.getJSON(ctx, p.cfg.RelayPulse.EventsURL+"/latest", &latest); err != nil {
+		return 0, err
+	}
+	if latest.LatestID == nil {
+		return 0, fmt.Errorf("响应缺少 latest_id")
+	}
+	return *latest.LatestID, nil
+}
+
+// getJSON 请求 relay-pulse 事件 API 并解析 JSON 响应
+func (p *Poller) getJSON(ctx context.Context, url string, out any) error {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %w", err)
+		return fmt.Errorf("创建请求失败: %w", err)
 	}
 
 	// 添加 API Token（如果配置了）
@@ -176,23 +439,21 @@ func (p *Poller) fetchEvents(ctx context.Context, sinceID int64) ([]Event, error
 
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("请求失败: %w", err)
+		return fmt.Errorf("请求失败: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusUnauthorized {
-		return nil, fmt.Errorf("API Token 无效或缺失")
+		return fmt.Errorf("API Token 无效或缺失")
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API 返回错误 [%d]: %s", resp.StatusCode, string(body))
+		return fmt.Errorf("API 返回错误 [%d]: %s", resp.StatusCode, string(body))
 	}
 
-	var eventsResp EventsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&eventsResp); err != nil {
-		return nil, fmt.Errorf("解析响应失败: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
 	}
-
-	return eventsResp.Events, nil
+	return nil
 }
@@ -0,0 +1,296 @@
+package poller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"notifier/internal/config"
+	"notifier/internal/storage"
+)
+
+// eventsServer 模拟 relay-pulse 事件 API：/api/events 按 since_id 与 limit 分页，/api/events/latest 返回最新事件 ID
+type eventsServer struct {
+	mu             sync.Mutex
+	ids            []int64 // 主服务中现存的事件 ID（升序）
+	status         int     // 非 0 时事件接口返回该状态码
+	eventRequests  int
+	latestRequests int
+}
+
+func (s *eventsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.URL.Path == "/api/events/latest" {
+		s.latestRequests++
+		var latest int64
+		if len(s.ids) > 0 {
+			latest = s.ids[len(s.ids)-1]
+		}
+		json.NewEncoder(w).Encode(map[string]int64{"latest_id": latest})
+		return
+	}
+
+	s.eventRequests++
+	if s.status != 0 {
+		http.Error(w, "unavailable", s.status)
+		return
+	}
+	since, _ := strconv.ParseInt(r.URL.Query().Get("since_id"), 10, 64)
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	var resp EventsResponse
+	resp.Events = []Event{}
+	resp.Meta.NextSinceID = since
+	for _, id := range s.ids {
+		if id <= since {
+			continue
+		}
+		if len(resp.Events) == limit {
+			resp.Meta.HasMore = true
+			break
+		}
+		resp.Events = append(resp.Events, Event{ID: id, Provider: "relay", Service: "cc", Type: "DOWN"})
+		resp.Meta.NextSinceID = id
+	}
+	resp.Meta.Count = len(resp.Events)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (s *eventsServer) requests() (events, latest int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.eventRequests, s.latestRequests
+}
+
+// eventIDs 返回 1..n 的事件 ID
+func eventIDs(n int) []int64 {
+	ids := make([]int64, n)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	return ids
+}
+
+// newTestPoller 创建基于临时 SQLite 存储与模拟事件 API 的轮询器，handler 为空时事件全部处理成功
+func newTestPoller(t *testing.T, srv *eventsServer, handler EventHandler) (*Poller, *storage.SQLiteStorage) {
+	t.Helper()
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "notifier.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	if err := store.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+
+	cfg := &config.Config{}
+	cfg.RelayPulse.EventsURL = ts.URL + "/api/events"
+	cfg.RelayPulse.PollInterval = 5 * time.Second
+	cfg.RelayPulse.BatchSize = 5
+	cfg.RelayPulse.CatchUpPages = 3
+	cfg.RelayPulse.MaxBackoff = 5 * time.Minute
+	if handler == nil {
+		handler = func(ctx context.Context, event *Event) error { return nil }
+	}
+	return NewPoller(cfg, store, handler), store
+}
+
+func cursorOf(t *testing.T, store storage.Storage) int64 {
+	t.Helper()
+	cursor, err := store.GetCursor(context.Background())
+	if err != nil {
+		t.Fatalf("GetCursor: %v", err)
+	}
+	return cursor
+}
+
+func TestPollCatchUpPages(t *testing.T) {
+	ctx := context.Background()
+	srv := &eventsServer{ids: eventIDs(23)}
+	var handled []int64
+	p, store := newTestPoller(t, srv, func(ctx context.Context, event *Event) error {
+		handled = append(handled, event.ID)
+		return nil
+	})
+
+	// 每轮最多 catchup_pages 页：3 页 × 5 条后停止，游标推进到 15
+	p.poll(ctx)
+	if got := cursorOf(t, store); got != 15 {
+		t.Fatalf("cursor after first round = %d, want 15", got)
+	}
+	if events, _ := srv.requests(); events != 3 {
+		t.Fatalf("event requests after first round = %d, want 3", events)
+	}
+
+	// 下一轮从游标继续，最后一页不足 batch_size 且 has_more=false 时结束
+	p.poll(ctx)
+	if got := cursorOf(t, store); got != 23 {
+		t.Fatalf("cursor after second round = %d, want 23", got)
+	}
+	if events, _ := srv.requests(); events != 5 {
+		t.Fatalf("event requests after second round = %d, want 5", events)
+	}
+	if !reflect.DeepEqual(handled, eventIDs(23)) {
+		t.Fatalf("handled events = %v, want each event once in order", handled)
+	}
+
+	// 已追上：空页不推进游标
+	p.poll(ctx)
+	if got := cursorOf(t, store); got != 23 {
+		t.Fatalf("cursor after idle round = %d, want 23", got)
+	}
+}
+
+func TestPollStopsWhenPageFails(t *testing.T) {
+	ctx := context.Background()
+	srv := &eventsServer{ids: eventIDs(12)}
+	broken := true
+	var handled []int64
+	p, store := newTestPoller(t, srv, func(ctx context.Context, event *Event) error {
+		if broken && event.ID > 5 && event.ID <= 10 {
+			return errors.New("storage unavailable")
+		}
+		handled = append(handled, event.ID)
+		return nil
+	})
+
+	// 第二页事件全部处理失败：停止本轮，不再请求后续页，游标停在第一页末尾
+	p.poll(ctx)
+	if got := cursorOf(t, store); got != 5 {
+		t.Fatalf("cursor = %d, want 5", got)
+	}
+	if events, _ := srv.requests(); events != 2 {
+		t.Fatalf("event requests = %d, want 2", events)
+	}
+
+	// 恢复后下一轮从失败的页重新处理
+	broken = false
+	p.poll(ctx)
+	if got := cursorOf(t, store); got != 12 {
+		t.Fatalf("cursor after recovery = %d, want 12", got)
+	}
+	if !reflect.DeepEqual(handled, eventIDs(12)) {
+		t.Fatalf("handled events = %v", handled)
+	}
+}
+
+func TestPollBackoff(t *testing.T) {
+	base, maxDelay := 5*time.Second, 5*time.Minute
+	cases := []struct {
+		failures int
+		want     time.Duration // 抖动前的退避时间，实际取值在 [want/2, want)
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{5, 160 * time.Second},
+		{6, 5 * time.Minute}, // 320s 截断到上限
+		{30, 5 * time.Minute},
+	}
+	for _, tc := range cases {
+		for i := 0; i < 50; i++ {
+			got := pollBackoff(tc.failures, base, maxDelay)
+			if got < tc.want/2 || got >= tc.want {
+				t.Fatalf("failures %d: pollBackoff = %v, want [%v, %v)", tc.failures, got, tc.want/2, tc.want)
+			}
+		}
+	}
+}
+
+func TestPollBacksOffOnError(t *testing.T) {
+	ctx := context.Background()
+	srv := &eventsServer{ids: eventIDs(3), status: http.StatusBadGateway}
+	p, store := newTestPoller(t, srv, nil)
+
+	p.poll(ctx)
+	if p.failures != 1 || !p.backoffUntil.After(time.Now()) {
+		t.Fatalf("expected backoff after error: failures=%d backoffUntil=%v", p.failures, p.backoffUntil)
+	}
+
+	// 退避期内直接跳过，不请求事件 API
+	srv.mu.Lock()
+	srv.status = 0
+	srv.mu.Unlock()
+	p.poll(ctx)
+	if events, _ := srv.requests(); events != 1 {
+		t.Fatalf("event requests during backoff = %d, want 1", events)
+	}
+
+	// 退避到期后恢复轮询并清除退避状态
+	p.backoffUntil = time.Now().Add(-time.Second)
+	p.poll(ctx)
+	if p.failures != 0 || !p.backoffUntil.IsZero() {
+		t.Fatalf("backoff not reset: failures=%d backoffUntil=%v", p.failures, p.backoffUntil)
+	}
+	if got := cursorOf(t, store); got != 3 {
+		t.Fatalf("cursor = %d, want 3", got)
+	}
+}
+
+func TestPollResetsCursorOnRegression(t *testing.T) {
+	ctx := context.Background()
+	// 主服务换库后事件 ID 从头开始，最新仅到 50
+	srv := &eventsServer{ids: []int64{48, 49, 50}}
+	p, store := newTestPoller(t, srv, nil)
+
+	var alerts [][]any
+	p.SetAlertHandler(func(ctx context.Context, key string, args ...any) {
+		alerts = append(alerts, append([]any{key}, args...))
+	})
+
+	if err := store.UpdateCursor(ctx, 100); err != nil {
+		t.Fatalf("UpdateCursor: %v", err)
+	}
+	for _, eventID := range []int64{40, 60, 90} {
+		if err := store.CreateDelivery(ctx, &storage.Delivery{EventID: eventID, Platform: storage.PlatformTelegram, ChatID: 1}); err != nil {
+			t.Fatalf("CreateDelivery: %v", err)
+		}
+	}
+
+	// 空轮询时核对最新事件 ID：回拨游标、清理超出的投递记录并告警
+	p.poll(ctx)
+	if got := cursorOf(t, store); got != 50 {
+		t.Fatalf("cursor after regression = %d, want 50", got)
+	}
+	want := [][]any{{"alert.event_regression", int64(100), int64(50), int64(2)}}
+	if !reflect.DeepEqual(alerts, want) {
+		t.Fatalf("alerts = %v, want %v", alerts, want)
+	}
+	pending, err := store.GetPendingDeliveries(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetPendingDeliveries: %v", err)
+	}
+	if len(pending) != 1 || pending[0].EventID != 40 {
+		t.Fatalf("remaining deliveries = %+v, want only event 40", pending)
+	}
+
+	// 回拨后新事件 ID 与旧投递记录不冲突，正常推进
+	srv.mu.Lock()
+	srv.ids = append(srv.ids, 51, 52)
+	srv.mu.Unlock()
+	p.poll(ctx)
+	if got := cursorOf(t, store); got != 52 {
+		t.Fatalf("cursor after new events = %d, want 52", got)
+	}
+
+	// 核对有最小间隔：间隔内的空轮询不再请求 /latest
+	p.poll(ctx)
+	if _, latest := srv.requests(); latest != 1 {
+		t.Fatalf("latest requests = %d, want 1", latest)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("unexpected extra alerts: %v", alerts)
+	}
+}
@@ -16,7 +16,9 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
+	"notifier/internal/i18n"
 	"notifier/internal/screenshot"
 	"notifier/internal/storage"
 	"notifier/internal/validator"
@@ -33,11 +35,16 @@ type Bot struct {
 	validator         *validator.RelayPulseValidator
 
 	maxSubscriptionsPerUser int
+	chatTokenTTL            time.Duration // /export 签发的会话令牌有效期
 	eventsURL               string
 	callbackSecret          string             // Webhook 签名密钥
 	adminWhitelist          map[int64]struct{} // 管理员白名单（可越权执行管理命令）
+	auditEnabled            bool               // 是否将管理命令写入审计日志
+	digestEnabled           bool               // 是否启用定期摘要
+	digestDefaultHour       int                // 默认摘要发送时刻（UTC+8）
 
-	handlers map[string]commandHandler
+	broadcaster Broadcaster
+	handlers    map[string]commandHandler
 
 	// 群聊"状态检查"防刷（同一群短时间内不重复响应）
 	statusCheckCooldown    time.Duration
@@ -51,13 +58,22 @@ type Bot struct {
 
 type commandHandler func(ctx context.Context, e *OneBotEvent, args string) error
 
+// Broadcaster 公告广播发起者（由通知发送器实现）
+type Broadcaster interface {
+	StartBroadcast(ctx context.Context, b *storage.Broadcast, onDone func(*storage.Broadcast)) error
+}
+
 // Options QQ Bot 初始化选项
 type Options struct {
 	MaxSubscriptionsPerUser int
+	ChatTokenTTL            time.Duration // /export 签发的会话令牌有效期
 	EventsURL               string
 	CallbackSecret          string              // Webhook 签名密钥（可选）
 	ScreenshotService       *screenshot.Service // 截图服务（可选）
 	AdminWhitelist          []int64             // 管理员白名单 QQ 号（可越权执行管理命令，可选）
+	AuditEnabled            bool                // 是否将管理命令写入审计日志（可选）
+	DigestEnabled           bool                // 是否启用定期摘要（/digest，可选）
+	DigestDefaultHour       int                 // /digest 未指定时刻时的默认发送时刻（UTC+8）
 }
 
 // NewBot 创建 QQ Bot
@@ -88,9 +104,13 @@ func NewBot(client *Client, store storage.Storage, opts Options) *Bot {
 		screenshotService:       opts.ScreenshotService,
 		validator:               v,
 		maxSubscriptionsPerUser: opts.MaxSubscriptionsPerUser,
+		chatTokenTTL:            opts.ChatTokenTTL,
 		eventsURL:               opts.EventsURL,
 		callbackSecret:          opts.CallbackSecret,
 		adminWhitelist:          adminWhitelist,
+		auditEnabled:            opts.AuditEnabled,
+		digestEnabled:           opts.DigestEnabled,
+		digestDefaultHour:       opts.DigestDefaultHour,
 		handlers:                make(map[string]commandHandler),
 		statusCheckCooldown:     30 * time.Second,
 		lastStatusCheckByGroup:  make(map[int64]time.Time),
@@ -100,14 +120,31 @@ func NewBot(client *Client, store storage.Storage, opts Options) *Bot {
 	b.handlers["list"] = b.handleList
 	b.handlers["add"] = b.handleAdd
 	b.handlers["remove"] = b.handleRemove
+	b.handlers["filter"] = b.handleFilter
 	b.handlers["clear"] = b.handleClear
 	b.handlers["status"] = b.handleStatus
 	b.handlers["help"] = b.handleHelp
 	b.handlers["snap"] = b.handleSnap
+	b.handlers["lang"] = b.handleLang
+	b.handlers["digest"] = b.handleDigest
+	b.handlers["export"] = b.handleExport
+	b.handlers["import"] = b.handleImport
+	b.handlers["role"] = b.handleRole
+	b.handlers["link"] = b.handleLink
+	b.handlers["pause"] = b.handlePause
+	b.handlers["resume"] = b.handleResume
+	b.handlers["stats"] = b.handleStats
+	b.handlers["announcements"] = b.handleAnnouncements
+	b.handlers["broadcast"] = b.handleBroadcast
 
 	return b
 }
 
+// SetBroadcaster 设置公告广播发起者（未设置时 /broadcast 不可用）
+func (b *Bot) SetBroadcaster(br Broadcaster) {
+	b.broadcaster = br
+}
+
 // isWhitelisted 检查用户是否在管理员白名单中
 func (b *Bot) isWhitelisted(userID int64) bool {
 	if userID <= 0 {
@@ -169,7 +206,11 @@ func (b *Bot) HandleCallback(w http.ResponseWriter, r *http.Request) {
 	// 快速响应，避免阻塞 NapCatQQ
 	b.writeOK(w)
 
-	// 异步处理消息
+	b.HandleEvent(event)
+}
+
+// HandleEvent 异步处理一条 OneBot 上报（HTTP 回调与 WebSocket 共用）
+func (b *Bot) HandleEvent(event OneBotEvent) {
 	go func(ev OneBotEvent) {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
@@ -307,11 +348,7 @@ func (b *Bot) handleMessage(ctx context.Context, e *OneBotEvent) {
 	// 提取纯文本
 	text := strings.TrimSpace(extractPlainText(e))
 
-	// 判断是否是机器人自发消息（兼容两种实现）：
-	// 1. post_type="message_sent"（NapCat 等）
-	// 2. post_type="message" 且 user_id=self_id（部分其他实现）
-	isSelfMessage := e.PostType == "message_sent" ||
-		(e.PostType == "message" && e.UserID != 0 && e.UserID == e.SelfID)
+	isSelfMessage := isSelfEvent(e)
 
 	// 自发消息仅允许 // 双斜杠命令，避免循环
 	if isSelfMessage {
@@ -347,11 +384,12 @@ func (b *Bot) handleMessage(ctx context.Context, e *OneBotEvent) {
 		if err := b.storage.UpdateChatCommandTime(ctx, storage.PlatformQQ, chatID); err != nil {
 			slog.Warn("更新命令时间失败", "chat_id", chatID, "error", err)
 		}
+		ctx = i18n.WithLang(ctx, b.chatLang(ctx, chatID))
 
 		slog.Info("群聊触发状态检查", "group_id", e.GroupID, "user_id", e.UserID)
 		if err := b.handleSnap(ctx, e, ""); err != nil {
 			slog.Error("状态检查截图失败", "chat_id", chatID, "error", err)
-			b.sendReply(ctx, e, "状态检查失败，请稍后重试。")
+			b.reply(ctx, e, "cmd.status_check_failed")
 		}
 		return
 	}
@@ -387,52 +425,111 @@ func (b *Bot) handleMessage(ctx context.Context, e *OneBotEvent) {
 		slog.Warn("更新命令时间失败", "chat_id", chatID, "error", err)
 	}
 
+	// 确定回复语言
+	ctx = i18n.WithLang(ctx, b.chatLang(ctx, chatID))
+
 	// 查找命令处理器
 	handler, found := b.handlers[cmd]
 	if !found {
-		b.sendReply(ctx, e, "未知命令。发送 /help 查看帮助。")
+		b.reply(ctx, e, "cmd.unknown")
 		return
 	}
 
-	// 群消息权限检查：管理命令需群管理员；白名单/自发命令可越权
-	if e.MessageType == "group" && isAdminOnlyCommand(cmd) {
+	// 群消息权限检查：按角色校验（群主/管理员视为 owner）；白名单/自发命令可越权
+	// via 记录授权方式，非空时命令执行结果写入审计日志
+	via := ""
+	if required := storage.RequiredRole(cmd, args); e.MessageType == "group" && required != storage.RoleViewer {
 		if isSelfMessage {
 			// 审计日志：机器人自发命令执行管理命令
 			slog.Info("机器人自发命令执行管理命令", "command", cmd, "group_id", e.GroupID)
+			via = "self"
 		} else if b.isWhitelisted(e.UserID) {
 			// 审计日志：白名单越权执行管理命令
 			slog.Info("管理员白名单越权执行管理命令", "command", cmd, "group_id", e.GroupID, "user_id", e.UserID)
+			via = "whitelist"
 		} else {
-			isAdmin, err := b.isGroupAdmin(ctx, e.GroupID, e.UserID)
+			role, source, err := b.memberRole(ctx, e, chatID, required)
 			if err != nil {
-				slog.Warn("群管理员校验失败", "group_id", e.GroupID, "user_id", e.UserID, "error", err)
-				b.sendReply(ctx, e, "权限校验失败，请稍后重试。")
+				slog.Warn("群成员角色校验失败", "group_id", e.GroupID, "user_id", e.UserID, "error", err)
+				b.reply(ctx, e, "cmd.permission_check_failed")
 				return
 			}
-			if !isAdmin {
-				b.sendReply(ctx, e, "权限不足：群聊中仅管理员可执行 /add /remove /clear。")
+			if !storage.RoleAtLeast(role, required) {
+				b.recordAudit(ctx, e, cmd, args, "", storage.AuditResultDenied)
+				b.reply(ctx, e, "cmd.permission_denied", required)
 				return
 			}
+			via = source
 		}
 	}
 
 	// 执行命令
 	if err := handler(ctx, e, args); err != nil {
 		slog.Error("QQ 命令执行失败", "command", cmd, "chat_id", chatID, "error", err)
-		b.sendReply(ctx, e, "命令执行出错，请稍后重试。")
+		b.reply(ctx, e, "cmd.error")
+		if via != "" {
+			b.recordAudit(ctx, e, cmd, args, via, storage.AuditResultFailure)
+		}
+		return
+	}
+	if via != "" {
+		b.recordAudit(ctx, e, cmd, args, via, storage.AuditResultSuccess)
 	}
 }
 
-// isAdminOnlyCommand 判断是否是仅管理员可用的命令
-func isAdminOnlyCommand(cmd string) bool {
-	switch cmd {
-	case "add", "remove", "clear":
-		return true
-	default:
-		return false
+// isSelfEvent 判断是否是机器人自发消息（兼容两种实现）：
+// 1. post_type="message_sent"（NapCat 等）
+// 2. post_type="message" 且 user_id=self_id（部分其他实现）
+func isSelfEvent(e *OneBotEvent) bool {
+	return e.PostType == "message_sent" ||
+		(e.PostType == "message" && e.UserID != 0 && e.UserID == e.SelfID)
+}
+
+// recordAudit 将群管理命令写入审计日志（未启用时忽略，写入失败仅告警）
+func (b *Bot) recordAudit(ctx context.Context, e *OneBotEvent, cmd, args, via, result string) {
+	if !b.auditEnabled {
+		return
+	}
+
+	detail := "args=" + args
+	if via != "" {
+		detail = "via=" + via + " " + detail
+	}
+	entry := &storage.AuditEntry{
+		Platform: storage.PlatformQQ,
+		Actor:    fmt.Sprintf("qq:%d", e.UserID),
+		Action:   "qq.command." + cmd,
+		Target:   fmt.Sprintf("group:%d", e.GroupID),
+		Detail:   detail,
+		Result:   result,
+	}
+	if err := b.storage.AddAuditEntry(ctx, entry); err != nil {
+		slog.Warn("写入审计日志失败", "command", cmd, "group_id", e.GroupID, "error", err)
 	}
 }
 
+// memberRole 群成员的有效角色及其来源（role:<角色> 或 group_admin）
+// 已分配角色不满足 required 时查询群管理员身份，群主/管理员视为 owner
+func (b *Bot) memberRole(ctx context.Context, e *OneBotEvent, chatID int64, required string) (string, string, error) {
+	role, err := b.storage.GetChatRole(ctx, storage.PlatformQQ, chatID, e.UserID)
+	if err != nil {
+		return "", "", err
+	}
+	role = storage.HigherRole(role, storage.RoleViewer)
+	if storage.RoleAtLeast(role, required) {
+		return role, "role:" + role, nil
+	}
+
+	isAdmin, err := b.isGroupAdmin(ctx, e.GroupID, e.UserID)
+	if err != nil {
+		return "", "", err
+	}
+	if isAdmin {
+		return storage.RoleOwner, "group_admin", nil
+	}
+	return role, "role:" + role, nil
+}
+
 // isGroupAdmin 检查用户是否是群管理员（二次确认）
 func (b *Bot) isGroupAdmin(ctx context.Context, groupID, userID int64) (bool, error) {
 	if groupID == 0 || userID == 0 {
@@ -477,6 +574,24 @@ func (b *Bot) ensureChat(ctx context.Context, e *OneBotEvent, chatID int64) erro
 	})
 }
 
+// chatLang 会话语言：/lang 偏好 > 默认语言（QQ 不提供客户端语言）
+func (b *Bot) chatLang(ctx context.Context, chatID int64) i18n.Lang {
+	chat, err := b.storage.GetChat(ctx, storage.PlatformQQ, chatID)
+	if err != nil {
+		slog.Warn("查询语言偏好失败", "chat_id", chatID, "error", err)
+		return i18n.Default()
+	}
+	if chat == nil {
+		return i18n.Default()
+	}
+	return i18n.Resolve(chat.Language, "")
+}
+
+// reply 按会话语言渲染文案并回复
+func (b *Bot) reply(ctx context.Context, e *OneBotEvent, key string, args ...any) {
+	b.sendReply(ctx, e, i18n.Text(i18n.FromContext(ctx), key, args...))
+}
+
 // sendReply 发送回复
 func (b *Bot) sendReply(ctx context.Context, e *OneBotEvent, text string) {
 	if e == nil {
@@ -516,28 +631,39 @@ func (b *Bot) handleList(ctx context.Context, e *OneBotEvent, args string) error
 	}
 
 	if len(subs) == 0 {
-		b.sendReply(ctx, e, "你还没有订阅任何服务。\n\n使用 /add <provider> [service] 添加订阅。")
+		b.reply(ctx, e, "list.empty_qq")
 		return nil
 	}
 
+	lang := i18n.FromContext(ctx)
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("当前订阅（%d 个）：\n\n", len(subs)))
+	sb.WriteString(i18n.Text(lang, "list.header", len(subs)))
 
 	for i, sub := range subs {
+		// 仅接收部分事件类型时追加标注
+		filter := ""
+		if sub.EventMask != storage.EventMaskAll {
+			filter = i18n.Text(lang, "list.filter", storage.FormatEventMask(sub.EventMask))
+		}
+		// 群聊中标注订阅创建者
+		if e.MessageType == "group" && sub.CreatedBy != 0 {
+			filter += i18n.Text(lang, "list.creator", sub.CreatedBy)
+		}
+
 		// 根据订阅级别显示不同格式
 		if sub.Service == "" {
 			// 旧版通配订阅（provider 级）
-			sb.WriteString(fmt.Sprintf("%d. %s / *（旧版）\n", i+1, sub.Provider))
+			sb.WriteString(fmt.Sprintf("%d. %s / *%s%s\n", i+1, sub.Provider, i18n.Text(lang, "list.legacy"), filter))
 		} else if sub.Channel != "" {
 			// 精确订阅（provider / service / channel）
-			sb.WriteString(fmt.Sprintf("%d. %s / %s / %s\n", i+1, sub.Provider, sub.Service, sub.Channel))
+			sb.WriteString(fmt.Sprintf("%d. %s / %s / %s%s\n", i+1, sub.Provider, sub.Service, sub.Channel, filter))
 		} else {
 			// service 级订阅（provider / service）
-			sb.WriteString(fmt.Sprintf("%d. %s / %s\n", i+1, sub.Provider, sub.Service))
+			sb.WriteString(fmt.Sprintf("%d. %s / %s%s\n", i+1, sub.Provider, sub.Service, filter))
 		}
 	}
 
-	sb.WriteString("\n使用 /remove <provider> [service] [channel] 移除订阅。")
+	sb.WriteString(i18n.Text(lang, "list.footer"))
 
 	b.sendReply(ctx, e, sb.String())
 	return nil
@@ -548,15 +674,20 @@ func (b *Bot) handleList(ctx context.Context, e *OneBotEvent, args string) error
 // - /add <provider> → 展开订阅该 provider 下所有 service/channel
 // - /add <provider> <service> → 展开订阅该 service 下所有 channel
 // - /add <provider> <service> <channel> → 精确订阅
+// 可附加 --only=<types> 仅接收指定事件类型（如 --only=down,up）
 func (b *Bot) handleAdd(ctx context.Context, e *OneBotEvent, args string) error {
 	chatID, ok := chatKey(e)
 	if !ok {
 		return nil
 	}
 
-	parts := strings.Fields(args)
+	parts, mask, err := storage.ExtractEventMaskFlag(strings.Fields(args))
+	if err != nil {
+		b.reply(ctx, e, "filter.invalid", storage.EventMaskNames)
+		return nil
+	}
 	if len(parts) < 1 {
-		b.sendReply(ctx, e, "用法: /add <provider> [service] [channel]\n\n例如:\n/add 88code → 订阅 88code 所有服务\n/add 88code cc → 订阅 88code 的 cc 服务")
+		b.reply(ctx, e, "add.usage")
 		return nil
 	}
 
@@ -579,7 +710,7 @@ func (b *Bot) handleAdd(ctx context.Context, e *OneBotEvent, args string) error
 
 	// 验证服务是否配置
 	if b.validator == nil {
-		b.sendReply(ctx, e, "当前无法验证订阅（验证服务未配置），为避免订阅无效服务，已拒绝本次订阅。请从网页一键导入收藏。")
+		b.reply(ctx, e, "add.validator_unconfigured")
 		return nil
 	}
 
@@ -592,10 +723,7 @@ func (b *Bot) handleAdd(ctx context.Context, e *OneBotEvent, args string) error
 
 		// 检查配额
 		if maxSubs > 0 && count+len(targets) > maxSubs {
-			b.sendReply(ctx, e, fmt.Sprintf(
-				"订阅配额不足。%s 有 %d 个订阅项，当前已用 %d/%d。\n\n请先移除部分订阅，或精确指定 service/channel。",
-				provider, len(targets), count, maxSubs,
-			))
+			b.reply(ctx, e, "add.quota_provider", provider, len(targets), count, maxSubs)
 			return nil
 		}
 
@@ -603,21 +731,21 @@ func (b *Bot) handleAdd(ctx context.Context, e *OneBotEvent, args string) error
 		added := 0
 		for _, t := range targets {
 			sub := &storage.Subscription{
-				Platform: storage.PlatformQQ,
-				ChatID:   chatID,
-				Provider: t.Provider,
-				Service:  t.Service,
-				Channel:  t.Channel,
+				Platform:  storage.PlatformQQ,
+				ChatID:    chatID,
+				Provider:  t.Provider,
+				Service:   t.Service,
+				Channel:   t.Channel,
+				EventMask: mask,
+				CreatedBy: e.UserID,
 			}
 			if err := b.storage.AddSubscription(ctx, sub); err == nil {
 				added++
 			}
 		}
 
-		b.sendReply(ctx, e, fmt.Sprintf(
-			"已添加 %d 个订阅（%s 下所有服务通道）",
-			added, provider,
-		))
+		lang := i18n.FromContext(ctx)
+		b.sendReply(ctx, e, i18n.Text(lang, "add.added_provider", added, provider)+eventMaskSuffix(lang, mask))
 		return nil
 	}
 
@@ -630,10 +758,7 @@ func (b *Bot) handleAdd(ctx context.Context, e *OneBotEvent, args string) error
 
 		// 检查配额
 		if maxSubs > 0 && count+len(targets) > maxSubs {
-			b.sendReply(ctx, e, fmt.Sprintf(
-				"订阅配额不足。%s / %s 有 %d 个订阅项，当前已用 %d/%d。\n\n请先移除部分订阅，或精确指定 channel。",
-				provider, service, len(targets), count, maxSubs,
-			))
+			b.reply(ctx, e, "add.quota_service", provider, service, len(targets), count, maxSubs)
 			return nil
 		}
 
@@ -641,30 +766,27 @@ func (b *Bot) handleAdd(ctx context.Context, e *OneBotEvent, args string) error
 		added := 0
 		for _, t := range targets {
 			sub := &storage.Subscription{
-				Platform: storage.PlatformQQ,
-				ChatID:   chatID,
-				Provider: t.Provider,
-				Service:  t.Service,
-				Channel:  t.Channel,
+				Platform:  storage.PlatformQQ,
+				ChatID:    chatID,
+				Provider:  t.Provider,
+				Service:   t.Service,
+				Channel:   t.Channel,
+				EventMask: mask,
+				CreatedBy: e.UserID,
 			}
 			if err := b.storage.AddSubscription(ctx, sub); err == nil {
 				added++
 			}
 		}
 
-		b.sendReply(ctx, e, fmt.Sprintf(
-			"已添加 %d 个订阅（%s / %s 下所有通道）",
-			added, provider, service,
-		))
+		lang := i18n.FromContext(ctx)
+		b.sendReply(ctx, e, i18n.Text(lang, "add.added_service", added, provider, service)+eventMaskSuffix(lang, mask))
 		return nil
 	}
 
 	// 精确订阅
 	if maxSubs > 0 && count >= maxSubs {
-		b.sendReply(ctx, e, fmt.Sprintf(
-			"订阅数量已达上限（%d/%d）。请先移除部分订阅。",
-			count, maxSubs,
-		))
+		b.reply(ctx, e, "add.limit_reached", count, maxSubs)
 		return nil
 	}
 
@@ -674,46 +796,45 @@ func (b *Bot) handleAdd(ctx context.Context, e *OneBotEvent, args string) error
 	}
 
 	sub := &storage.Subscription{
-		Platform: storage.PlatformQQ,
-		ChatID:   chatID,
-		Provider: target.Provider,
-		Service:  target.Service,
-		Channel:  target.Channel,
+		Platform:  storage.PlatformQQ,
+		ChatID:    chatID,
+		Provider:  target.Provider,
+		Service:   target.Service,
+		Channel:   target.Channel,
+		EventMask: mask,
+		CreatedBy: e.UserID,
 	}
 
 	if err := b.storage.AddSubscription(ctx, sub); err != nil {
 		return err
 	}
 
-	b.sendReply(ctx, e, fmt.Sprintf(
-		"已订阅 %s / %s / %s",
-		target.Provider, target.Service, target.Channel,
-	))
+	lang := i18n.FromContext(ctx)
+	b.sendReply(ctx, e, i18n.Text(lang, "add.added_exact", target.Provider, target.Service, target.Channel)+eventMaskSuffix(lang, mask))
 	return nil
 }
 
+// eventMaskSuffix 添加订阅回复中的事件类型说明（接收全部时为空）
+func eventMaskSuffix(lang i18n.Lang, mask uint32) string {
+	if mask == storage.EventMaskAll {
+		return ""
+	}
+	return i18n.Text(lang, "add.only_suffix", storage.FormatEventMask(mask))
+}
+
 // handleAddError 处理添加订阅时的错误
 func (b *Bot) handleAddError(ctx context.Context, e *OneBotEvent, err error, provider, service, channel string) error {
 	// 冷板错误处理
 	var cb *validator.ColdBoardError
 	if errors.As(err, &cb) {
 		if cb.Channel != "" {
-			b.sendReply(ctx, e, fmt.Sprintf(
-				"🚫 %s / %s / %s 已被移入冷板（board=cold），当前不支持订阅通知。",
-				cb.Provider, cb.Service, cb.Channel,
-			))
+			b.reply(ctx, e, "cold.channel", cb.Provider, cb.Service, cb.Channel)
 		} else if cb.Service != "" {
-			b.sendReply(ctx, e, fmt.Sprintf(
-				"🚫 %s / %s 当前无可订阅的热板监测项（均为冷板）。",
-				cb.Provider, cb.Service,
-			))
+			b.reply(ctx, e, "cold.service", cb.Provider, cb.Service)
 		} else if cb.Provider != "" {
-			b.sendReply(ctx, e, fmt.Sprintf(
-				"🚫 %s 当前无可订阅的热板监测项（均为冷板）。",
-				cb.Provider,
-			))
+			b.reply(ctx, e, "cold.provider", cb.Provider)
 		} else {
-			b.sendReply(ctx, e, "🚫 目标已被移入冷板（board=cold），当前不支持订阅通知。")
+			b.reply(ctx, e, "cold.generic")
 		}
 		return nil
 	}
@@ -722,35 +843,20 @@ func (b *Bot) handleAddError(ctx context.Context, e *OneBotEvent, err error, pro
 	if errors.As(err, &nf) {
 		switch nf.Level {
 		case validator.NotFoundProvider:
-			b.sendReply(ctx, e, fmt.Sprintf(
-				"未找到服务商 %s。\n\n请到 RelayPulse 网页复制正确的 provider/service/channel，或使用网页一键导入收藏。",
-				provider,
-			))
+			b.reply(ctx, e, "notfound.provider", provider)
 		case validator.NotFoundService:
 			cands := validator.FormatCandidates(nf.Candidates, "service")
 			if cands != "" {
-				b.sendReply(ctx, e, fmt.Sprintf(
-					"未找到 %s / %s。\n\n该服务商下可用的 service 例如：%s（仅显示前 %d 个）。",
-					provider, service, cands, 8,
-				))
+				b.reply(ctx, e, "notfound.service_candidates", provider, service, cands, 8)
 			} else {
-				b.sendReply(ctx, e, fmt.Sprintf(
-					"未找到 %s / %s。\n\n请到 RelayPulse 网页确认 service 是否正确，或使用网页一键导入收藏。",
-					provider, service,
-				))
+				b.reply(ctx, e, "notfound.service", provider, service)
 			}
 		case validator.NotFoundChannel:
 			cands := validator.FormatCandidates(nf.Candidates, "channel")
 			if cands != "" {
-				b.sendReply(ctx, e, fmt.Sprintf(
-					"未找到 %s / %s / %s。\n\n该 service 下可用的 channel 例如：%s（仅显示前 %d 个）。",
-					provider, service, channel, cands, 8,
-				))
+				b.reply(ctx, e, "notfound.channel_candidates", provider, service, channel, cands, 8)
 			} else {
-				b.sendReply(ctx, e, fmt.Sprintf(
-					"未找到 %s / %s / %s。",
-					provider, service, channel,
-				))
+				b.reply(ctx, e, "notfound.channel", provider, service, channel)
 			}
 		}
 		return nil
@@ -758,7 +864,7 @@ func (b *Bot) handleAddError(ctx context.Context, e *OneBotEvent, err error, pro
 
 	var ue *validator.UnavailableError
 	if errors.As(err, &ue) {
-		b.sendReply(ctx, e, "当前无法验证订阅（状态服务暂不可用），为避免订阅无效服务，已拒绝本次订阅。请稍后再试，或从网页一键导入收藏。")
+		b.reply(ctx, e, "add.validator_unavailable")
 		return nil
 	}
 
@@ -778,7 +884,7 @@ func (b *Bot) handleRemove(ctx context.Context, e *OneBotEvent, args string) err
 
 	parts := strings.Fields(args)
 	if len(parts) < 1 {
-		b.sendReply(ctx, e, "用法: /remove <provider> [service] [channel]\n\n例如:\n/remove 88code → 移除 88code 所有订阅\n/remove 88code cc → 移除 88code 的 cc 服务订阅")
+		b.reply(ctx, e, "remove.usage")
 		return nil
 	}
 
@@ -804,13 +910,67 @@ func (b *Bot) handleRemove(ctx context.Context, e *OneBotEvent, args string) err
 	// 根据删除级别显示不同消息
 	if service == "" {
 		// provider 级删除（级联）
-		b.sendReply(ctx, e, fmt.Sprintf("已取消订阅 %s / *（包括该服务商下所有订阅）", provider))
+		b.reply(ctx, e, "remove.provider", provider)
 	} else if channel != "" {
 		// 精确删除
-		b.sendReply(ctx, e, fmt.Sprintf("已取消订阅 %s / %s / %s", provider, service, channel))
+		b.reply(ctx, e, "remove.exact", provider, service, channel)
 	} else {
 		// service 级删除
-		b.sendReply(ctx, e, fmt.Sprintf("已取消订阅 %s / %s", provider, service))
+		b.reply(ctx, e, "remove.service", provider, service)
+	}
+	return nil
+}
+
+// handleFilter 处理 /filter 命令
+// 最后一个参数为事件类型列表，其余参数的匹配规则与 /remove 一致：
+// - /filter <provider> <types> → 该 provider 下所有订阅
+// - /filter <provider> <service> <types> → 该 service 下所有通道
+// - /filter <provider> <service> <channel> <types> → 精确一条
+func (b *Bot) handleFilter(ctx context.Context, e *OneBotEvent, args string) error {
+	chatID, ok := chatKey(e)
+	if !ok {
+		return nil
+	}
+
+	parts := strings.Fields(args)
+	if len(parts) < 2 || len(parts) > 4 {
+		b.reply(ctx, e, "filter.usage", storage.EventMaskNames)
+		return nil
+	}
+
+	mask, err := storage.ParseEventMask(parts[len(parts)-1])
+	if err != nil {
+		b.reply(ctx, e, "filter.invalid", storage.EventMaskNames)
+		return nil
+	}
+
+	provider := parts[0]
+	service := ""
+	if len(parts) > 2 {
+		service = parts[1]
+	}
+	channel := ""
+	if len(parts) > 3 {
+		channel = parts[2]
+	}
+	// "default" 归一化为空字符串（与 add/remove 对齐）
+	if strings.EqualFold(channel, "default") {
+		channel = ""
+	}
+
+	updated, err := b.storage.SetSubscriptionEventMask(ctx, storage.PlatformQQ, chatID, provider, service, channel, mask)
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		b.reply(ctx, e, "filter.not_found")
+		return nil
+	}
+
+	if mask == storage.EventMaskAll {
+		b.reply(ctx, e, "filter.updated_all", updated)
+	} else {
+		b.reply(ctx, e, "filter.updated_only", updated, storage.FormatEventMask(mask))
 	}
 	return nil
 }
@@ -826,7 +986,7 @@ func (b *Bot) handleClear(ctx context.Context, e *OneBotEvent, args string) erro
 		return err
 	}
 
-	b.sendReply(ctx, e, "已清空所有订阅。")
+	b.reply(ctx, e, "clear.done")
 	return nil
 }
 
@@ -842,19 +1002,20 @@ func (b *Bot) handleStatus(ctx context.Context, e *OneBotEvent, args string) err
 		return err
 	}
 
+	lang := i18n.FromContext(ctx)
 	max := b.maxSubscriptionsPerUser
-	msg := "服务状态\n\n"
+	msg := i18n.Text(lang, "status.title") + "\n\n"
 
 	if max > 0 {
-		msg += fmt.Sprintf("订阅数量: %d/%d\n", count, max)
+		msg += i18n.Text(lang, "status.count_limited", count, max) + "\n"
 	} else {
-		msg += fmt.Sprintf("订阅数量: %d\n", count)
+		msg += i18n.Text(lang, "status.count", count) + "\n"
 	}
 
-	msg += "状态: 运行中\n"
+	msg += i18n.Text(lang, "status.running") + "\n"
 
 	if b.eventsURL != "" {
-		msg += fmt.Sprintf("数据源: %s\n", b.eventsURL)
+		msg += i18n.Text(lang, "status.source", b.eventsURL) + "\n"
 	}
 
 	b.sendReply(ctx, e, strings.TrimSpace(msg))
@@ -863,34 +1024,7 @@ func (b *Bot) handleStatus(ctx context.Context, e *OneBotEvent, args string) err
 
 // handleHelp 处理 /help 命令
 func (b *Bot) handleHelp(ctx context.Context, e *OneBotEvent, args string) error {
-	help := `RelayPulse QQ 通知帮助
-
-命令列表：
-/list - 查看当前订阅
-/add <provider> [service] [channel] - 添加订阅
-/remove <provider> [service] [channel] - 移除订阅
-/clear - 清空所有订阅
-/snap - 截图订阅服务状态
-/status - 查看服务状态
-/help - 显示此帮助
-
-手动添加订阅：
-/add 88code → 订阅 88code 所有服务
-/add 88code cc → 订阅 88code 的 cc 服务
-/add duckcoding cc v1 → 精确订阅
-
-移除订阅：
-/remove 88code → 移除 88code 所有订阅
-/remove 88code cc → 移除 88code 的 cc 订阅
-
-全局指令（群聊无需@）：
-状态检查 - 快速截图订阅服务状态
-
-权限说明：
-1) 群聊：仅管理员可执行 /add /remove /clear
-2) 私聊：好友可直接使用所有命令`
-
-	b.sendReply(ctx, e, help)
+	b.reply(ctx, e, "help.qq")
 	return nil
 }
 
@@ -978,7 +1112,7 @@ func (b *Bot) handleSnap(ctx context.Context, e *OneBotEvent, args string) error
 
 	// 检查截图服务是否启用
 	if b.screenshotService == nil {
-		b.sendReply(ctx, e, "截图功能未启用。")
+		b.reply(ctx, e, "snap.disabled")
 		return nil
 	}
 
@@ -986,11 +1120,11 @@ func (b *Bot) handleSnap(ctx context.Context, e *OneBotEvent, args string) error
 	subs, err := b.storage.GetSubscriptionsByChatID(ctx, storage.PlatformQQ, chatID)
 	if err != nil {
 		slog.Error("获取订阅失败", "chat_id", chatID, "error", err)
-		b.sendReply(ctx, e, "获取订阅信息失败，请稍后重试。")
+		b.reply(ctx, e, "snap.fetch_failed")
 		return nil
 	}
 	if len(subs) == 0 {
-		b.sendReply(ctx, e, "你还没有订阅任何服务。\n\n使用 /add <provider> <service> 添加订阅后再试。")
+		b.reply(ctx, e, "snap.empty")
 		return nil
 	}
 
@@ -1004,26 +1138,28 @@ func (b *Bot) handleSnap(ctx context.Context, e *OneBotEvent, args string) error
 	ownerLabel := b.getOwnerLabel(ctx, e)
 
 	// 发送提示
-	b.sendReply(ctx, e, fmt.Sprintf("正在生成 [%s专属] 的状态截图...", ownerLabel))
+	b.reply(ctx, e, "snap.generating", ownerLabel)
 
 	// 截图（使用独立的超时 ctx）
 	snapCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	// 构建截图标题（群名 + 专属状态）
-	title := ownerLabel + " 专属状态"
+	lang := i18n.FromContext(ctx)
+	title := i18n.Text(lang, "snap.title", ownerLabel)
 	pngData, err := b.screenshotService.CaptureWithOptions(snapCtx, providers, services, &screenshot.CaptureOptions{
-		Title: title,
+		Title:    title,
+		Language: string(lang),
 	})
 	if err != nil {
 		slog.Error("截图失败", "chat_id", chatID, "providers", providers, "error", err)
 		// 区分错误类型
 		if errors.Is(err, context.DeadlineExceeded) {
-			b.sendReply(ctx, e, "截图超时，请稍后重试。")
+			b.reply(ctx, e, "snap.timeout")
 		} else if errors.Is(err, screenshot.ErrConcurrencyLimit) {
-			b.sendReply(ctx, e, "系统繁忙，请稍后重试。")
+			b.reply(ctx, e, "snap.busy")
 		} else {
-			b.sendReply(ctx, e, "截图生成失败，请稍后重试。")
+			b.reply(ctx, e, "snap.failed")
 		}
 		return nil
 	}
@@ -1033,11 +1169,547 @@ func (b *Bot) handleSnap(ctx context.Context, e *OneBotEvent, args string) error
 	defer sendCancel()
 	if err := b.sendImage(sendCtx, e, pngData); err != nil {
 		slog.Error("发送图片失败", "chat_id", chatID, "error", err)
-		b.sendReply(ctx, e, "截图生成成功，但发送失败。请稍后重试。")
+		b.reply(ctx, e, "snap.send_failed")
+	}
+	return nil
+}
+
+// handleLang 处理 /lang 命令（查看或设置会话语言，群聊中仅管理员可设置）
+// - /lang → 显示当前语言与可选项
+// - /lang <code> → 设置语言偏好（zh/en/ja/ru）
+// - /lang auto → 清除偏好，恢复默认语言
+func (b *Bot) handleLang(ctx context.Context, e *OneBotEvent, args string) error {
+	chatID, ok := chatKey(e)
+	if !ok {
+		return nil
+	}
+
+	code := strings.ToLower(strings.TrimSpace(args))
+	if code == "" {
+		b.reply(ctx, e, "lang.current", i18n.Name(i18n.FromContext(ctx)), i18n.Options())
+		return nil
+	}
+
+	if code == "auto" || code == "default" {
+		if err := b.storage.UpdateChatLanguage(ctx, storage.PlatformQQ, chatID, ""); err != nil {
+			return err
+		}
+		lang := i18n.Default()
+		b.sendReply(ctx, e, i18n.Text(lang, "lang.reset", i18n.Name(lang)))
+		return nil
+	}
+
+	lang, ok := i18n.Parse(code)
+	if !ok {
+		b.reply(ctx, e, "lang.invalid", args, i18n.Options())
+		return nil
+	}
+	if err := b.storage.UpdateChatLanguage(ctx, storage.PlatformQQ, chatID, string(lang)); err != nil {
+		return err
+	}
+	b.sendReply(ctx, e, i18n.Text(lang, "lang.set", i18n.Name(lang)))
+	return nil
+}
+
+// handleDigest 处理 /digest 命令（查看或设置定期摘要，群聊中仅管理员可设置）
+// - /digest → 显示当前设置与用法
+// - /digest daily|weekly [hour] → 每天/每周一 hour:00（UTC+8）发送摘要
+// - /digest off → 关闭摘要
+func (b *Bot) handleDigest(ctx context.Context, e *OneBotEvent, args string) error {
+	chatID, ok := chatKey(e)
+	if !ok {
+		return nil
+	}
+	if !b.digestEnabled {
+		b.reply(ctx, e, "digest.disabled")
+		return nil
+	}
+
+	lang := i18n.FromContext(ctx)
+	if strings.TrimSpace(args) == "" {
+		chat, err := b.storage.GetChat(ctx, storage.PlatformQQ, chatID)
+		if err != nil {
+			return err
+		}
+		b.sendReply(ctx, e, digestStatus(lang, chat)+"\n\n"+i18n.Text(lang, "digest.usage", b.digestDefaultHour))
+		return nil
+	}
+
+	frequency, hour, err := storage.ParseDigestSchedule(args, b.digestDefaultHour)
+	if errors.Is(err, storage.ErrInvalidDigestHour) {
+		b.reply(ctx, e, "digest.invalid_hour", strings.Fields(args)[1])
+		return nil
+	}
+	if err != nil {
+		b.reply(ctx, e, "digest.usage", b.digestDefaultHour)
+		return nil
+	}
+
+	if err := b.storage.UpdateChatDigest(ctx, storage.PlatformQQ, chatID, frequency, hour); err != nil {
+		return err
+	}
+	if frequency == storage.DigestOff {
+		b.reply(ctx, e, "digest.off")
+		return nil
+	}
+	b.reply(ctx, e, "digest.set", digestFrequencyName(lang, frequency), hour)
+	return nil
+}
+
+// handleExport 处理 /export 命令（导出订阅并签发会话令牌，群聊中仅管理员可用）
+// 令牌可写入订阅，群聊中对群成员可见，有效期内需注意保管
+func (b *Bot) handleExport(ctx context.Context, e *OneBotEvent, args string) error {
+	chatID, ok := chatKey(e)
+	if !ok {
+		return nil
+	}
+
+	export, err := storage.ExportSubscriptions(ctx, b.storage, storage.PlatformQQ, chatID)
+	if err != nil {
+		return err
+	}
+	if len(export.Subscriptions) == 0 {
+		b.reply(ctx, e, "export.empty")
+		return nil
+	}
+
+	token, err := storage.CreateChatToken(ctx, b.storage, storage.PlatformQQ, chatID, b.chatTokenTTL)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(export)
+	if err != nil {
+		return err
+	}
+
+	b.reply(ctx, e, "export.header", len(export.Subscriptions), token.Token, int(b.chatTokenTTL.Minutes()), token.Token)
+	b.sendReply(ctx, e, string(data))
+	return nil
+}
+
+// handleImport 处理 /import 命令（群聊中仅管理员可用）
+// - /import <令牌> → 复制签发令牌的会话（可为 Telegram）的订阅
+// - /import <JSON> → 从 /export 导出的 JSON 还原订阅
+func (b *Bot) handleImport(ctx context.Context, e *OneBotEvent, args string) error {
+	chatID, ok := chatKey(e)
+	if !ok {
+		return nil
+	}
+
+	args = strings.TrimSpace(args)
+	if args == "" {
+		b.reply(ctx, e, "import.usage")
+		return nil
 	}
+
+	var export *storage.SubscriptionExport
+	if strings.HasPrefix(args, "{") {
+		parsed, err := storage.ParseSubscriptionExport([]byte(args))
+		if err != nil {
+			b.reply(ctx, e, "import.invalid", err.Error())
+			return nil
+		}
+		export = parsed
+	} else {
+		source, err := storage.ResolveChatToken(ctx, b.storage, args)
+		if errors.Is(err, storage.ErrInvalidChatToken) {
+			b.reply(ctx, e, "import.token_invalid")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		export, err = storage.ExportSubscriptions(ctx, b.storage, source.Platform, source.ChatID)
+		if err != nil {
+			return err
+		}
+	}
+
+	result, err := storage.ImportSubscriptions(ctx, b.storage, storage.PlatformQQ, chatID, export, b.maxSubscriptionsPerUser, false, e.UserID)
+	if err != nil {
+		return err
+	}
+
+	lang := i18n.FromContext(ctx)
+	reply := i18n.Text(lang, "import.done", result.Imported, result.Existing)
+	if result.Skipped > 0 {
+		reply += i18n.Text(lang, "import.skipped", b.maxSubscriptionsPerUser, result.Skipped)
+	}
+	b.sendReply(ctx, e, reply)
 	return nil
 }
 
+// handleLink 处理 /link 命令（跨平台身份关联，群聊中需 editor 及以上角色）
+// - /link → 查看已关联的会话并签发一次性关联令牌
+// - /link <令牌> → 与签发令牌的会话（可为 Telegram）关联
+// - /link prefer → 将当前会话设为首选投递会话
+// - /link off → 取消当前会话的关联
+func (b *Bot) handleLink(ctx context.Context, e *OneBotEvent, args string) error {
+	chatID, ok := chatKey(e)
+	if !ok {
+		return nil
+	}
+	arg := strings.TrimSpace(args)
+
+	switch strings.ToLower(arg) {
+	case "":
+		lang := i18n.FromContext(ctx)
+		links, err := b.storage.GetLinkedChats(ctx, storage.PlatformQQ, chatID)
+		if err != nil {
+			return err
+		}
+		token, err := storage.CreateLinkToken(ctx, b.storage, storage.PlatformQQ, chatID, b.chatTokenTTL)
+		if err != nil {
+			return err
+		}
+
+		var sb strings.Builder
+		if len(links) == 0 {
+			sb.WriteString(i18n.Text(lang, "link.none") + "\n\n")
+		} else {
+			sb.WriteString(i18n.Text(lang, "link.header", len(links)))
+			for i, l := range links {
+				sb.WriteString(fmt.Sprintf("%d. %s %d", i+1, l.Platform, l.ChatID))
+				if l.Platform == storage.PlatformQQ && l.ChatID == chatID {
+					sb.WriteString(i18n.Text(lang, "link.current"))
+				}
+				sb.WriteString("\n")
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString(i18n.Text(lang, "link.token", token.Token, int(b.chatTokenTTL.Minutes())))
+		b.sendReply(ctx, e, sb.String())
+		return nil
+
+	case "prefer":
+		linked, err := b.storage.SetPreferredChat(ctx, storage.PlatformQQ, chatID)
+		if err != nil {
+			return err
+		}
+		if !linked {
+			b.reply(ctx, e, "link.not_linked")
+			return nil
+		}
+		b.reply(ctx, e, "link.preferred")
+		return nil
+
+	case "off":
+		linked, err := b.storage.UnlinkChat(ctx, storage.PlatformQQ, chatID)
+		if err != nil {
+			return err
+		}
+		if !linked {
+			b.reply(ctx, e, "link.not_linked")
+			return nil
+		}
+		b.reply(ctx, e, "link.unlinked")
+		return nil
+	}
+
+	source, err := storage.ConsumeLinkToken(ctx, b.storage, arg)
+	if errors.Is(err, storage.ErrInvalidLinkToken) {
+		b.reply(ctx, e, "link.token_invalid")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if source.Platform == storage.PlatformQQ && source.ChatID == chatID {
+		b.reply(ctx, e, "link.self")
+		return nil
+	}
+	if err := b.storage.LinkChat(ctx, storage.PlatformQQ, chatID, source.Platform, source.ChatID); err != nil {
+		return err
+	}
+	links, err := b.storage.GetLinkedChats(ctx, storage.PlatformQQ, chatID)
+	if err != nil {
+		return err
+	}
+	b.reply(ctx, e, "link.done", len(links))
+	return nil
+}
+
+// handleRole 处理 /role 命令（群组角色）
+// - /role → 查看群内已分配的角色
+// - /role <QQ号|@成员> <owner|editor|viewer|none> → 分配或移除角色（需 owner）
+func (b *Bot) handleRole(ctx context.Context, e *OneBotEvent, args string) error {
+	chatID, ok := chatKey(e)
+	if !ok {
+		return nil
+	}
+	if e.MessageType != "group" {
+		b.reply(ctx, e, "role.private")
+		return nil
+	}
+
+	lang := i18n.FromContext(ctx)
+	parts := strings.Fields(args)
+	if len(parts) == 0 {
+		roles, err := b.storage.GetChatRoles(ctx, storage.PlatformQQ, chatID)
+		if err != nil {
+			return err
+		}
+		if len(roles) == 0 {
+			b.sendReply(ctx, e, i18n.Text(lang, "role.list_empty")+"\n\n"+i18n.Text(lang, "role.usage", storage.RoleNames))
+			return nil
+		}
+		var sb strings.Builder
+		sb.WriteString(i18n.Text(lang, "role.list_header", len(roles)))
+		for _, r := range roles {
+			sb.WriteString(fmt.Sprintf("• %d — %s\n", r.UserID, r.Role))
+		}
+		b.sendReply(ctx, e, strings.TrimSpace(sb.String()))
+		return nil
+	}
+
+	// @成员 不出现在纯文本中，此时仅剩角色参数
+	var target int64
+	roleArg := parts[len(parts)-1]
+	switch len(parts) {
+	case 1:
+		target = b.mentionedUser(e)
+	case 2:
+		target, _ = strconv.ParseInt(parts[0], 10, 64)
+	}
+	if target <= 0 {
+		b.reply(ctx, e, "role.usage", storage.RoleNames)
+		return nil
+	}
+
+	if strings.EqualFold(roleArg, "none") {
+		if err := b.storage.RemoveChatRole(ctx, storage.PlatformQQ, chatID, target); err != nil {
+			return err
+		}
+		b.reply(ctx, e, "role.removed", target)
+		return nil
+	}
+
+	role, ok := storage.ParseRole(roleArg)
+	if !ok {
+		b.reply(ctx, e, "role.usage", storage.RoleNames)
+		return nil
+	}
+	if err := b.storage.SetChatRole(ctx, &storage.ChatRole{
+		Platform:  storage.PlatformQQ,
+		ChatID:    chatID,
+		UserID:    target,
+		Role:      role,
+		GrantedBy: e.UserID,
+	}); err != nil {
+		return err
+	}
+	b.reply(ctx, e, "role.set", target, role)
+	return nil
+}
+
+// handlePause 处理 /pause 命令（暂停通知，保留订阅，群聊中需 editor 及以上角色）
+// - /pause → 暂停到 /resume
+// - /pause <时长> → 暂停指定时长（如 2h、3d、1w）
+func (b *Bot) handlePause(ctx context.Context, e *OneBotEvent, args string) error {
+	chatID, ok := chatKey(e)
+	if !ok {
+		return nil
+	}
+
+	arg := strings.TrimSpace(args)
+	if arg == "" {
+		if err := b.storage.UpdateChatPause(ctx, storage.PlatformQQ, chatID, storage.PausedIndefinitely); err != nil {
+			return err
+		}
+		b.reply(ctx, e, "pause.set_indefinite")
+		return nil
+	}
+
+	d, err := storage.ParsePauseDuration(arg)
+	if err != nil {
+		b.reply(ctx, e, "pause.invalid", arg)
+		return nil
+	}
+	until := time.Now().Add(d).Unix()
+	if err := b.storage.UpdateChatPause(ctx, storage.PlatformQQ, chatID, until); err != nil {
+		return err
+	}
+	b.reply(ctx, e, "pause.set", storage.PauseEndText(until))
+	return nil
+}
+
+// handleResume 处理 /resume 命令（恢复通知，群聊中需 editor 及以上角色）
+func (b *Bot) handleResume(ctx context.Context, e *OneBotEvent, args string) error {
+	chatID, ok := chatKey(e)
+	if !ok {
+		return nil
+	}
+
+	chat, err := b.storage.GetChat(ctx, storage.PlatformQQ, chatID)
+	if err != nil {
+		return err
+	}
+	if chat == nil || !storage.IsPaused(chat.PausedUntil, time.Now().Unix()) {
+		b.reply(ctx, e, "resume.not_paused")
+		return nil
+	}
+	if err := b.storage.UpdateChatPause(ctx, storage.PlatformQQ, chatID, 0); err != nil {
+		return err
+	}
+	b.reply(ctx, e, "resume.done")
+	return nil
+}
+
+// handleStats 处理 /stats 命令（近 24 小时/7 天通知数、通知最多的监测项与订阅服务故障时长）
+func (b *Bot) handleStats(ctx context.Context, e *OneBotEvent, args string) error {
+	chatID, ok := chatKey(e)
+	if !ok {
+		return nil
+	}
+
+	stats, err := storage.ComputeChatStats(ctx, b.storage, storage.PlatformQQ, chatID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	lang := i18n.FromContext(ctx)
+	var sb strings.Builder
+	sb.WriteString(i18n.Text(lang, "stats.header"))
+	sb.WriteString(i18n.Text(lang, "stats.deliveries", stats.Last24h, stats.Last7d))
+	if len(stats.TopMonitors) > 0 {
+		sb.WriteString(i18n.Text(lang, "stats.top_header"))
+		for i, c := range stats.TopMonitors {
+			sb.WriteString(i18n.Text(lang, "stats.top_item", i+1, c.Name(), c.Total))
+		}
+	}
+	sb.WriteString(i18n.Text(lang, "stats.incidents_header"))
+	if stats.Incidents > 0 {
+		sb.WriteString(i18n.Text(lang, "stats.incidents", stats.Incidents, storage.FormatStatsDuration(stats.AvgIncidentDuration)))
+	} else {
+		sb.WriteString(i18n.Text(lang, "stats.incidents_none"))
+	}
+	if stats.Ongoing > 0 {
+		sb.WriteString(i18n.Text(lang, "stats.ongoing", stats.Ongoing))
+	}
+
+	b.sendReply(ctx, e, sb.String())
+	return nil
+}
+
+// handleAnnouncements 处理 /announcements 命令（接收或退订 RelayPulse 公告，群聊中设置需 editor 及以上角色）
+func (b *Bot) handleAnnouncements(ctx context.Context, e *OneBotEvent, args string) error {
+	chatID, ok := chatKey(e)
+	if !ok {
+		return nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		chat, err := b.storage.GetChat(ctx, storage.PlatformQQ, chatID)
+		if err != nil {
+			return err
+		}
+		if chat != nil && chat.AnnouncementsOff {
+			b.reply(ctx, e, "announcements.status_off")
+		} else {
+			b.reply(ctx, e, "announcements.status_on")
+		}
+	case "on":
+		if err := b.storage.UpdateChatAnnouncements(ctx, storage.PlatformQQ, chatID, false); err != nil {
+			return err
+		}
+		b.reply(ctx, e, "announcements.on")
+	case "off":
+		if err := b.storage.UpdateChatAnnouncements(ctx, storage.PlatformQQ, chatID, true); err != nil {
+			return err
+		}
+		b.reply(ctx, e, "announcements.off")
+	default:
+		b.reply(ctx, e, "announcements.usage")
+	}
+	return nil
+}
+
+// handleBroadcast 处理 /broadcast 命令（仅管理员白名单或机器人自发命令）
+// /broadcast <all|provider> <公告内容> → 向所有活跃会话或订阅了该服务商的会话发送公告，完成后在此会话回复发送统计
+func (b *Bot) handleBroadcast(ctx context.Context, e *OneBotEvent, args string) error {
+	if !isSelfEvent(e) && !b.isWhitelisted(e.UserID) {
+		slog.Warn("非管理员尝试发送公告", "user_id", e.UserID, "group_id", e.GroupID)
+		b.reply(ctx, e, "broadcast.denied")
+		return nil
+	}
+	if b.broadcaster == nil {
+		b.reply(ctx, e, "broadcast.unavailable")
+		return nil
+	}
+
+	provider, message, ok := storage.ParseBroadcastArgs(args)
+	if !ok {
+		b.reply(ctx, e, "broadcast.usage", storage.MaxBroadcastLength)
+		return nil
+	}
+	if n := utf8.RuneCountInString(message); n > storage.MaxBroadcastLength {
+		b.reply(ctx, e, "broadcast.too_long", n, storage.MaxBroadcastLength)
+		return nil
+	}
+
+	lang := i18n.FromContext(ctx)
+	bc := &storage.Broadcast{
+		Actor:    fmt.Sprintf("qq:%d", e.UserID),
+		Provider: provider,
+		Message:  message,
+	}
+	onDone := func(done *storage.Broadcast) {
+		// 广播可能持续较久，完成通知不依赖命令的 context
+		replyCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if done.Status == storage.BroadcastStatusAborted {
+			b.sendReply(replyCtx, e, i18n.Text(lang, "broadcast.aborted", done.ID, done.Sent, done.Failed, done.Total))
+			return
+		}
+		b.sendReply(replyCtx, e, i18n.Text(lang, "broadcast.done", done.ID, done.Sent, done.Failed, done.Total, done.OptedOut))
+	}
+	if err := b.broadcaster.StartBroadcast(ctx, bc, onDone); err != nil {
+		return err
+	}
+
+	if bc.Total == 0 {
+		b.reply(ctx, e, "broadcast.empty", bc.ID, bc.OptedOut)
+		return nil
+	}
+	b.reply(ctx, e, "broadcast.started", bc.ID, bc.Total, bc.OptedOut)
+	return nil
+}
+
+// mentionedUser 消息中第一个被 @ 的成员（不含机器人自身），未找到返回 0
+func (b *Bot) mentionedUser(e *OneBotEvent) int64 {
+	var segs []MessageSegment
+	if err := json.Unmarshal(e.Message, &segs); err != nil {
+		return 0
+	}
+	selfID := b.getSelfID()
+	for _, seg := range segs {
+		if seg.Type != "at" {
+			continue
+		}
+		if qq, err := strconv.ParseInt(seg.Data.QQ, 10, 64); err == nil && qq != selfID {
+			return qq
+		}
+	}
+	return 0
+}
+
+// digestStatus 当前摘要设置
+func digestStatus(lang i18n.Lang, chat *storage.Chat) string {
+	if chat == nil || chat.DigestFrequency == storage.DigestOff {
+		return i18n.Text(lang, "digest.status_off")
+	}
+	return i18n.Text(lang, "digest.status_on", digestFrequencyName(lang, chat.DigestFrequency), chat.DigestHour)
+}
+
+// digestFrequencyName 摘要频率的展示名称
+func digestFrequencyName(lang i18n.Lang, frequency string) string {
+	if frequency == storage.DigestWeekly {
+		return i18n.Text(lang, "digest.freq_weekly")
+	}
+	return i18n.Text(lang, "digest.freq_daily")
+}
+
 // extractUniqueProviders 从订阅列表中提取去重的 provider 列表
 func extractUniqueProviders(subs []*storage.Subscription) []string {
 	seen := make(map[string]struct{})
@@ -1066,8 +1738,9 @@ func extractUniqueServices(subs []*storage.Subscription) []string {
 
 // getOwnerLabel 获取截图专属标识（群名或用户昵称）
 func (b *Bot) getOwnerLabel(ctx context.Context, e *OneBotEvent) string {
+	lang := i18n.FromContext(ctx)
 	if e == nil {
-		return "你"
+		return i18n.Text(lang, "snap.owner_you")
 	}
 
 	switch e.MessageType {
@@ -1077,7 +1750,7 @@ func (b *Bot) getOwnerLabel(ctx context.Context, e *OneBotEvent) string {
 			info, err := b.client.GetGroupInfo(ctx, e.GroupID)
 			if err != nil {
 				slog.Warn("获取群信息失败，回退到群号", "group_id", e.GroupID, "error", err)
-				return fmt.Sprintf("群%d", e.GroupID)
+				return i18n.Text(lang, "snap.owner_group", e.GroupID)
 			}
 			if info.GroupName != "" {
 				// 截断过长的群名（最多 20 字符）
@@ -1087,7 +1760,7 @@ func (b *Bot) getOwnerLabel(ctx context.Context, e *OneBotEvent) string {
 				}
 				return name
 			}
-			return fmt.Sprintf("群%d", e.GroupID)
+			return i18n.Text(lang, "snap.owner_group", e.GroupID)
 		}
 	case "private":
 		// 私聊：使用发送者昵称
@@ -1101,7 +1774,7 @@ func (b *Bot) getOwnerLabel(ctx context.Context, e *OneBotEvent) string {
 		}
 	}
 
-	return "你"
+	return i18n.Text(lang, "snap.owner_you")
 }
 
 // sendImage 发送图片消息
@@ -22,6 +22,7 @@ type OneBotEvent struct {
 
 	// meta_event / notice / request（预留字段）
 	MetaEventType string `json:"meta_event_type,omitempty"`
+	Interval      int64  `json:"interval,omitempty"` // 心跳间隔（毫秒，heartbeat 元事件）
 	NoticeType    string `json:"notice_type,omitempty"`
 	RequestType   string `json:"request_type,omitempty"`
 }
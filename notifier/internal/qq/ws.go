@@ -0,0 +1,187 @@
+package qq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+const (
+	wsDialTimeout      = 10 * time.Second
+	wsInitialBackoff   = time.Second
+	wsMaxBackoff       = time.Minute
+	wsDefaultHeartbeat = 30 * time.Second // 未收到心跳间隔前假定的心跳周期（NapCatQQ 默认 30s）
+	wsHeartbeatMisses  = 3                // 连续错过的心跳次数达到该值视为连接失效
+	wsMaxPayloadBytes  = 1 << 20          // 单条上报上限（与 HTTP 回调一致）
+)
+
+// WSClient OneBot v11 WebSocket 事件接收器
+//
+// notifier 主动连接 NapCatQQ 的 WebSocket 服务接收上报，无需对外暴露 HTTP 回调端口：
+// - 断线后按指数退避重连（1s 起，最长 1 分钟）
+// - 根据 meta_event 心跳间隔检测假死连接，超时未收到任何上报即主动重连
+type WSClient struct {
+	url         string
+	accessToken string
+	handler     func(OneBotEvent)
+
+	mu       sync.Mutex
+	running  bool
+	stopChan chan struct{}
+}
+
+// NewWSClient 创建 WebSocket 事件接收器，handler 处理收到的上报事件
+func NewWSClient(url, accessToken string, handler func(OneBotEvent)) *WSClient {
+	return &WSClient{
+		url:         strings.TrimSpace(url),
+		accessToken: accessToken,
+		handler:     handler,
+	}
+}
+
+// Start 启动接收循环（阻塞直至 ctx 取消或 Stop）
+func (c *WSClient) Start(ctx context.Context) error {
+	c.mu.Lock()
+	if c.running {
+		c.mu.Unlock()
+		return fmt.Errorf("OneBot WebSocket 已在运行")
+	}
+	c.running = true
+	c.stopChan = make(chan struct{})
+	c.mu.Unlock()
+
+	slog.Info("OneBot WebSocket 接收器启动", "url", c.url)
+
+	backoff := wsInitialBackoff
+	for {
+		connected, err := c.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.stopChan:
+			slog.Info("OneBot WebSocket 接收器停止")
+			return nil
+		default:
+		}
+
+		// 成功建立过连接则重置退避
+		if connected {
+			backoff = wsInitialBackoff
+		}
+		slog.Warn("OneBot WebSocket 连接断开，稍后重连", "error", err, "retry_in", backoff)
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-c.stopChan:
+			t.Stop()
+			slog.Info("OneBot WebSocket 接收器停止")
+			return nil
+		case <-t.C:
+		}
+
+		backoff *= 2
+		if backoff > wsMaxBackoff {
+			backoff = wsMaxBackoff
+		}
+	}
+}
+
+// Stop 停止接收循环并关闭当前连接
+func (c *WSClient) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.running {
+		close(c.stopChan)
+		c.running = false
+	}
+}
+
+// runOnce 建立一次连接并持续读取，返回是否成功建立过连接及断开原因
+func (c *WSClient) runOnce(ctx context.Context) (bool, error) {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return false, err
+	}
+	slog.Info("OneBot WebSocket 已连接", "url", c.url)
+
+	// ctx 取消或 Stop 时关闭连接，使阻塞的读取立即返回
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-c.stopChan:
+		case <-done:
+		}
+		_ = conn.Close()
+	}()
+
+	heartbeat := wsDefaultHeartbeat
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(heartbeat * wsHeartbeatMisses)); err != nil {
+			return true, fmt.Errorf("设置读取超时失败: %w", err)
+		}
+
+		var data []byte
+		if err := websocket.Message.Receive(conn, &data); err != nil {
+			return true, fmt.Errorf("读取上报失败: %w", err)
+		}
+
+		var event OneBotEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			slog.Warn("OneBot WebSocket 上报解析失败", "error", err)
+			continue
+		}
+
+		if event.PostType == "meta_event" {
+			switch event.MetaEventType {
+			case "heartbeat":
+				// 按实际心跳间隔调整假死检测阈值
+				if event.Interval > 0 {
+					heartbeat = time.Duration(event.Interval) * time.Millisecond
+				}
+			case "lifecycle":
+				slog.Info("OneBot WebSocket 生命周期事件", "sub_type", event.SubType, "self_id", event.SelfID)
+			}
+			continue
+		}
+
+		// 无 post_type 的帧是 API 调用响应，接收器不发起调用，直接忽略
+		if event.PostType == "" {
+			continue
+		}
+		c.handler(event)
+	}
+}
+
+// dial 建立 WebSocket 连接（Access Token 通过 Authorization 头传递）
+func (c *WSClient) dial(ctx context.Context) (*websocket.Conn, error) {
+	cfg, err := websocket.NewConfig(c.url, "http://localhost/")
+	if err != nil {
+		return nil, fmt.Errorf("解析 WebSocket 地址失败: %w", err)
+	}
+	if c.accessToken != "" {
+		cfg.Header.Set("Authorization", "Bearer "+c.accessToken)
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, wsDialTimeout)
+	defer cancel()
+
+	conn, err := cfg.DialContext(dialCtx)
+	if err != nil {
+		return nil, fmt.Errorf("连接失败: %w", err)
+	}
+	conn.MaxPayloadBytes = wsMaxPayloadBytes
+	return conn, nil
+}
@@ -17,8 +17,14 @@ import (
 var ErrConcurrencyLimit = errors.New("截图并发已达到上限")
 
 // CaptureOptions 截图可选参数
+// 非空字段优先于截图模板，模板未指定的字段使用内置默认值
 type CaptureOptions struct {
-	Title string // 截图标题（群名/用户名 + 专属状态）
+	Title    string   // 截图标题（群名/用户名 + 专属状态）
+	Period   string   // 时间范围（如 24h、7d），默认 90m
+	Theme    string   // 主题：dark/light 或前端主题 ID，默认 dark
+	Language string   // 页面语言：zh/en/ja/ru，默认 zh
+	Columns  []string // 展示的表格列，默认全部
+	Template string   // 截图模板名称，为空时使用默认模板
 }
 
 // Service 提供基于 Playwright 的截图服务
@@ -35,6 +41,9 @@ type Service struct {
 	sem         chan struct{}
 	mu          sync.Mutex
 	initialized bool
+
+	templates       map[string]Template
+	defaultTemplate string
 }
 
 // NewService 创建截图服务
@@ -49,6 +58,40 @@ func NewService(baseURL string, timeout time.Duration, maxConcurrent int) *Servi
 	}
 }
 
+// SetTemplates 设置截图模板（需在首次截图前调用）
+// defaultTemplate 为未指定模板时使用的模板名称，为空表示不使用模板
+func (s *Service) SetTemplates(templates map[string]Template, defaultTemplate string) {
+	s.templates = templates
+	s.defaultTemplate = defaultTemplate
+}
+
+// resolveTemplate 合并内置默认值、模板与调用参数
+func (s *Service) resolveTemplate(opts *CaptureOptions) Template {
+	resolved := Template{Period: "90m"}
+
+	name := s.defaultTemplate
+	if opts != nil && opts.Template != "" {
+		name = opts.Template
+	}
+	if name != "" {
+		if tmpl, ok := s.templates[name]; ok {
+			resolved = resolved.merge(tmpl)
+		} else {
+			slog.Warn("截图模板不存在，使用默认设置", "template", name)
+		}
+	}
+
+	if opts != nil {
+		resolved = resolved.merge(Template{
+			Theme:    opts.Theme,
+			Language: opts.Language,
+			Period:   opts.Period,
+			Columns:  opts.Columns,
+		})
+	}
+	return resolved
+}
+
 // ensureInitialized 懒加载初始化 Playwright 和 Browser
 func (s *Service) ensureInitialized() error {
 	s.mu.Lock()
@@ -94,12 +137,16 @@ func (s *Service) ensureInitialized() error {
 }
 
 // buildURL 构建截图 URL
-// 格式: {baseURL}/?provider=p1,p2&service=s1,s2&period=90m&screenshot=1[&title=xxx]
-func (s *Service) buildURL(providers, services []string, opts *CaptureOptions) (string, error) {
+// 格式: {baseURL}[/{lang}]?provider=p1,p2&service=s1,s2&period=90m&screenshot=1[&title=xxx][&theme=xxx][&columns=c1,c2][&brand=xxx]
+// 非中文语言使用前端的语言路径前缀（如 /en）
+func (s *Service) buildURL(providers, services []string, opts *CaptureOptions, tmpl Template) (string, error) {
 	u, err := url.Parse(s.baseURL)
 	if err != nil {
 		return "", fmt.Errorf("解析 baseURL 失败: %w", err)
 	}
+	if prefix := langPaths[tmpl.renderLang()]; prefix != "" {
+		u.Path = strings.TrimRight(u.Path, "/") + "/" + prefix
+	}
 
 	q := u.Query()
 	if len(providers) > 0 {
@@ -108,8 +155,17 @@ func (s *Service) buildURL(providers, services []string, opts *CaptureOptions) (
 	if len(services) > 0 {
 		q.Set("service", strings.Join(services, ","))
 	}
-	q.Set("period", "90m")
+	q.Set("period", tmpl.Period)
 	q.Set("screenshot", "1")
+	if theme := tmpl.renderTheme(); theme != "" {
+		q.Set("theme", theme)
+	}
+	if cols := tmpl.renderColumns(); len(cols) > 0 {
+		q.Set("columns", strings.Join(cols, ","))
+	}
+	if brand := strings.TrimSpace(tmpl.Brand); brand != "" {
+		q.Set("brand", brand)
+	}
 	if opts != nil && opts.Title != "" {
 		// 规范化：去除控制字符，限制长度
 		title := strings.TrimSpace(opts.Title)
@@ -156,22 +212,24 @@ func (s *Service) CaptureWithOptions(ctx context.Context, providers, services []
 		return nil, err
 	}
 
-	targetURL, err := s.buildURL(providers, services, opts)
+	tmpl := s.resolveTemplate(opts)
+	targetURL, err := s.buildURL(providers, services, opts, tmpl)
 	if err != nil {
 		return nil, err
 	}
 
 	// 避免把群名等敏感信息（title）打进日志
-	slog.Debug("开始截图", "providers", providers, "services", services, "has_title", opts != nil && opts.Title != "")
+	slog.Debug("开始截图", "providers", providers, "services", services, "has_title", opts != nil && opts.Title != "",
+		"language", tmpl.renderLang(), "period", tmpl.Period)
 
-	// 创建浏览器上下文（固定宽度 1200px，强制中文语言）
+	// 创建浏览器上下文（固定宽度 1200px）
 	browserCtx, err := s.browser.NewContext(playwright.BrowserNewContextOptions{
 		Viewport: &playwright.Size{
 			Width:  1200,
 			Height: 800,
 		},
-		// 强制中文语言
-		Locale: playwright.String("zh-CN"),
+		// 与页面语言一致，保证日期等本地化格式统一
+		Locale: playwright.String(langLocales[tmpl.renderLang()]),
 		// 禁用动画
 		ReducedMotion: playwright.ReducedMotionReduce,
 	})
@@ -0,0 +1,126 @@
+package screenshot
+
+import (
+	"fmt"
+	"strings"
+
+	"notifier/internal/i18n"
+)
+
+// 可选的截图列（与前端 StatusTable 的 TABLE_COLUMNS 保持一致）
+var validColumns = []string{"provider", "service", "channel", "price", "listed", "status", "uptime", "latency", "trend"}
+
+// 可选的时间范围（与前端时间范围选项保持一致）
+var validPeriods = []string{"90m", "24h", "7d", "30d"}
+
+// 主题别名：dark/light 映射为前端默认的深色/浅色主题
+var themeAliases = map[string]string{
+	"dark":         "default-dark",
+	"light":        "light-cool",
+	"default-dark": "default-dark",
+	"night-dark":   "night-dark",
+	"light-cool":   "light-cool",
+	"light-warm":   "light-warm",
+}
+
+// 语言对应的前端路径前缀与浏览器 locale
+var langPaths = map[i18n.Lang]string{
+	i18n.ZH: "",
+	i18n.EN: "en",
+	i18n.JA: "ja",
+	i18n.RU: "ru",
+}
+
+var langLocales = map[i18n.Lang]string{
+	i18n.ZH: "zh-CN",
+	i18n.EN: "en-US",
+	i18n.JA: "ja-JP",
+	i18n.RU: "ru-RU",
+}
+
+// Template 截图渲染模板（自托管时定制主题与品牌）
+// 空字段表示不覆盖，沿用调用方参数或内置默认值
+type Template struct {
+	Theme    string   `yaml:"theme"`    // dark/light 或前端主题 ID（default-dark/night-dark/light-cool/light-warm）
+	Language string   `yaml:"language"` // zh/en/ja/ru
+	Period   string   `yaml:"period"`   // 90m/24h/7d/30d
+	Columns  []string `yaml:"columns"`  // 展示的表格列，为空时展示全部
+	Brand    string   `yaml:"brand"`    // 标题栏品牌文案（如站点名称）
+}
+
+// Validate 校验模板字段
+func (t Template) Validate() error {
+	if t.Theme != "" {
+		if _, ok := themeAliases[strings.ToLower(t.Theme)]; !ok {
+			return fmt.Errorf("theme 无效: %s（可选 dark/light/default-dark/night-dark/light-cool/light-warm）", t.Theme)
+		}
+	}
+	if t.Language != "" {
+		if _, ok := i18n.Parse(t.Language); !ok {
+			return fmt.Errorf("language 无效: %s（可选 zh/en/ja/ru）", t.Language)
+		}
+	}
+	if t.Period != "" && !contains(validPeriods, t.Period) {
+		return fmt.Errorf("period 无效: %s（可选 %s）", t.Period, strings.Join(validPeriods, "/"))
+	}
+	for _, col := range t.Columns {
+		if !contains(validColumns, strings.ToLower(col)) {
+			return fmt.Errorf("columns 无效: %s（可选 %s）", col, strings.Join(validColumns, "/"))
+		}
+	}
+	return nil
+}
+
+// merge 用 override 的非空字段覆盖 t
+func (t Template) merge(override Template) Template {
+	if override.Theme != "" {
+		t.Theme = override.Theme
+	}
+	if override.Language != "" {
+		t.Language = override.Language
+	}
+	if override.Period != "" {
+		t.Period = override.Period
+	}
+	if len(override.Columns) > 0 {
+		t.Columns = override.Columns
+	}
+	if override.Brand != "" {
+		t.Brand = override.Brand
+	}
+	return t
+}
+
+// renderLang 解析模板语言（无效或未指定时为中文）
+func (t Template) renderLang() i18n.Lang {
+	if lang, ok := i18n.Parse(t.Language); ok {
+		return lang
+	}
+	return i18n.ZH
+}
+
+// renderTheme 解析模板主题对应的前端主题 ID（无效或未指定时为空，由前端使用默认主题）
+func (t Template) renderTheme() string {
+	return themeAliases[strings.ToLower(t.Theme)]
+}
+
+// renderColumns 规范化并过滤无效列
+func (t Template) renderColumns() []string {
+	var cols []string
+	for _, col := range t.Columns {
+		col = strings.ToLower(strings.TrimSpace(col))
+		if contains(validColumns, col) && !contains(cols, col) {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Broadcast 公告广播（运营方通过 /broadcast 或管理 API 发起，用于通知 RelayPulse 自身的计划维护等）
+type Broadcast struct {
+	ID         int64
+	Actor      string // 发起者，如 telegram:123456、qq:123456、admin-api
+	Provider   string // 目标服务商（空表示全部活跃会话）
+	Message    string
+	Status     string // running/done/aborted
+	Total      int    // 目标会话数（已排除退订会话，跨平台关联的身份只计一次）
+	Sent       int
+	Failed     int
+	OptedOut   int // 因退订公告而跳过的会话数
+	CreatedAt  int64
+	FinishedAt int64 // 0 表示尚未完成
+}
+
+// BroadcastStatus 公告广播状态常量
+const (
+	BroadcastStatusRunning = "running"
+	BroadcastStatusDone    = "done"
+	BroadcastStatusAborted = "aborted" // 服务关闭导致中止，未发送的会话不计入 Sent/Failed
+)
+
+// MaxBroadcastLength 公告正文长度上限（字符数，为 Telegram 单条消息 4096 字符上限留出标题与退订提示）
+const MaxBroadcastLength = 3500
+
+// broadcastColumns 公告广播查询列（两种存储共用）
+const broadcastColumns = `id, actor, provider, message, status, total, sent, failed, opted_out, created_at, finished_at`
+
+// BroadcastTargetAll /broadcast 命令中表示全部活跃会话的目标
+const BroadcastTargetAll = "all"
+
+// ParseBroadcastArgs 解析 /broadcast 参数：<all|provider> <公告内容>（内容可跨多行，保留原始换行）
+// 目标为 all 时返回空 provider
+func ParseBroadcastArgs(args string) (provider, message string, ok bool) {
+	args = strings.TrimSpace(args)
+	idx := strings.IndexFunc(args, unicode.IsSpace)
+	if idx <= 0 {
+		return "", "", false
+	}
+	target, message := args[:idx], strings.TrimSpace(args[idx:])
+	if message == "" {
+		return "", "", false
+	}
+	if strings.EqualFold(target, BroadcastTargetAll) {
+		return "", message, true
+	}
+	return target, message, true
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 摘要频率（chats.digest_frequency）
+const (
+	DigestOff    = ""
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+var (
+	// ErrDigestUsage /digest 参数无法识别
+	ErrDigestUsage = errors.New("无效的摘要设置")
+	// ErrInvalidDigestHour 摘要时刻超出 0-23
+	ErrInvalidDigestHour = errors.New("无效的摘要时刻")
+)
+
+// digestFrequencyAliases 命令中可用的频率名（小写）
+var digestFrequencyAliases = map[string]string{
+	"daily":  DigestDaily,
+	"day":    DigestDaily,
+	"每天":     DigestDaily,
+	"每日":     DigestDaily,
+	"weekly": DigestWeekly,
+	"week":   DigestWeekly,
+	"每周":     DigestWeekly,
+	"off":    DigestOff,
+	"none":   DigestOff,
+	"关闭":     DigestOff,
+}
+
+// ParseDigestSchedule 解析 /digest 参数："daily|weekly [hour]" 或 "off"
+// 未指定时刻时使用 defaultHour；时刻支持 9、09、9:00 形式（UTC+8）
+func ParseDigestSchedule(args string, defaultHour int) (frequency string, hour int, err error) {
+	fields := strings.Fields(strings.ToLower(args))
+	if len(fields) == 0 || len(fields) > 2 {
+		return "", 0, ErrDigestUsage
+	}
+
+	frequency, ok := digestFrequencyAliases[fields[0]]
+	if !ok {
+		return "", 0, ErrDigestUsage
+	}
+	if frequency == DigestOff {
+		if len(fields) > 1 {
+			return "", 0, ErrDigestUsage
+		}
+		return DigestOff, defaultHour, nil
+	}
+
+	hour = defaultHour
+	if len(fields) == 2 {
+		raw := strings.TrimSuffix(strings.TrimSuffix(fields[1], ":00"), "点")
+		hour, err = strconv.Atoi(raw)
+		if err != nil || hour < 0 || hour > 23 {
+			return "", 0, fmt.Errorf("%w: %s", ErrInvalidDigestHour, fields[1])
+		}
+	}
+	return frequency, hour, nil
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 订阅级事件类型过滤（位掩码存储在 subscriptions.event_mask）
+// 0 表示接收全部类型，兼容过滤功能上线前的旧订阅
+const (
+	EventMaskDown          uint32 = 1 << iota // 不可用
+	EventMaskUp                               // 恢复可用
+	EventMaskCertExpiring                     // 证书即将过期
+	EventMaskDegradedStart                    // 性能下降
+	EventMaskDegradedEnd                      // 性能恢复
+
+	EventMaskAll uint32 = 0
+)
+
+// eventMaskByType 事件类型（/api/events 的 type 字段）到掩码位的映射
+var eventMaskByType = map[string]uint32{
+	"DOWN":           EventMaskDown,
+	"UP":             EventMaskUp,
+	"CERT_EXPIRING":  EventMaskCertExpiring,
+	"DEGRADED_START": EventMaskDegradedStart,
+	"DEGRADED_END":   EventMaskDegradedEnd,
+}
+
+// eventMaskAliases 命令中可用的类型名（小写）
+var eventMaskAliases = map[string]uint32{
+	"down":           EventMaskDown,
+	"up":             EventMaskUp,
+	"cert":           EventMaskCertExpiring,
+	"cert_expiring":  EventMaskCertExpiring,
+	"degraded":       EventMaskDegradedStart | EventMaskDegradedEnd,
+	"degraded_start": EventMaskDegradedStart,
+	"degraded_end":   EventMaskDegradedEnd,
+}
+
+// eventMaskOrder 展示顺序
+var eventMaskOrder = []struct {
+	bit  uint32
+	name string
+}{
+	{EventMaskDown, "DOWN"},
+	{EventMaskUp, "UP"},
+	{EventMaskCertExpiring, "CERT_EXPIRING"},
+	{EventMaskDegradedStart, "DEGRADED_START"},
+	{EventMaskDegradedEnd, "DEGRADED_END"},
+}
+
+// EventMaskNames 命令帮助中展示的可选类型
+const EventMaskNames = "down, up, cert, degraded（degraded_start/degraded_end）, all"
+
+// EventMaskAllows 判断掩码是否接收该事件类型
+// 未知类型仅投递给接收全部类型（mask=0）的订阅
+func EventMaskAllows(mask uint32, eventType string) bool {
+	if mask == EventMaskAll {
+		return true
+	}
+	return mask&eventMaskByType[eventType] != 0
+}
+
+// ParseEventMask 解析类型列表（逗号或 + 分隔，大小写不敏感），"all" 表示全部
+func ParseEventMask(spec string) (uint32, error) {
+	var mask uint32
+	fields := strings.FieldsFunc(strings.ToLower(spec), func(r rune) bool {
+		return r == ',' || r == '+' || r == '，'
+	})
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("未指定事件类型")
+	}
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "all" {
+			return EventMaskAll, nil
+		}
+		bit, ok := eventMaskAliases[f]
+		if !ok {
+			return 0, fmt.Errorf("未知事件类型: %s（可选: %s）", f, EventMaskNames)
+		}
+		mask |= bit
+	}
+	return mask, nil
+}
+
+// FormatEventMask 格式化掩码用于展示
+func FormatEventMask(mask uint32) string {
+	if mask == EventMaskAll {
+		return "全部"
+	}
+	var names []string
+	for _, o := range eventMaskOrder {
+		if mask&o.bit != 0 {
+			names = append(names, o.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// ExtractEventMaskFlag 从命令参数中提取 --only=<types> 标志（也接受 only=<types>）
+// 返回去除标志后的参数；未携带标志时 mask 为 EventMaskAll
+func ExtractEventMaskFlag(parts []string) (rest []string, mask uint32, err error) {
+	for _, p := range parts {
+		v := strings.TrimLeft(p, "-—")
+		if !strings.HasPrefix(strings.ToLower(v), "only=") {
+			rest = append(rest, p)
+			continue
+		}
+		mask, err = ParseEventMask(v[len("only="):])
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return rest, mask, nil
+}
+
+// mergeChatRefs 合并同一 Chat 的多条匹配订阅（通配 + 精确），掩码取并集
+// 任一订阅接收全部类型时结果为全部
+func mergeChatRefs(refs []*ChatRef) []*ChatRef {
+	type key struct {
+		platform string
+		chatID   int64
+	}
+	merged := make([]*ChatRef, 0, len(refs))
+	index := make(map[key]*ChatRef, len(refs))
+	for _, r := range refs {
+		k := key{r.Platform, r.ChatID}
+		existing, ok := index[k]
+		if !ok {
+			index[k] = r
+			merged = append(merged, r)
+			continue
+		}
+		if existing.EventMask == EventMaskAll || r.EventMask == EventMaskAll {
+			existing.EventMask = EventMaskAll
+		} else {
+			existing.EventMask |= r.EventMask
+		}
+	}
+	return merged
+}
@@ -0,0 +1,201 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SubscriptionExportVersion 订阅导出格式版本
+const SubscriptionExportVersion = 1
+
+// SubscriptionExport 订阅导出数据（与平台无关，可在 Telegram / QQ 之间迁移或用于备份）
+type SubscriptionExport struct {
+	Version       int                    `json:"version"`
+	Platform      string                 `json:"platform,omitempty"` // 导出来源平台（仅供参考，导入时忽略）
+	ExportedAt    int64                  `json:"exported_at,omitempty"`
+	Subscriptions []ExportedSubscription `json:"subscriptions"`
+}
+
+// ExportedSubscription 导出的单条订阅
+type ExportedSubscription struct {
+	Provider string `json:"provider"`
+	Service  string `json:"service,omitempty"`
+	Channel  string `json:"channel,omitempty"`
+	Events   string `json:"events,omitempty"` // 事件类型列表（同 --only），为空表示全部
+}
+
+// ImportResult 订阅导入结果
+type ImportResult struct {
+	Imported int `json:"imported"` // 新增或更新的订阅数
+	Existing int `json:"existing"` // 已存在且无需变更的订阅数
+	Skipped  int `json:"skipped"`  // 超出订阅上限而跳过的订阅数
+}
+
+// ErrInvalidChatToken 会话令牌不存在、已过期或不是会话令牌
+var ErrInvalidChatToken = errors.New("会话令牌无效或已过期")
+
+// CreateChatToken 为会话签发令牌（/export 命令），有效期内可多次用于订阅导入导出
+func CreateChatToken(ctx context.Context, store Storage, platform string, chatID int64, ttl time.Duration) (*BindToken, error) {
+	return createChatToken(ctx, store, platform, chatID, "", ttl)
+}
+
+// createChatToken 签发归属于会话的令牌（purpose 区分用途）
+func createChatToken(ctx context.Context, store Storage, platform string, chatID int64, purpose string, ttl time.Duration) (*BindToken, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("生成会话令牌失败: %w", err)
+	}
+
+	now := time.Now()
+	token := &BindToken{
+		Token:     hex.EncodeToString(buf),
+		Favorites: "[]",
+		Platform:  platform,
+		ChatID:    chatID,
+		Purpose:   purpose,
+		ExpiresAt: now.Add(ttl).Unix(),
+		CreatedAt: now.Unix(),
+	}
+	if err := store.CreateBindToken(ctx, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// ResolveChatToken 校验会话令牌并返回其所属会话
+func ResolveChatToken(ctx context.Context, store Storage, token string) (*BindToken, error) {
+	bt, err := store.GetBindToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if bt == nil || bt.Platform == "" || bt.Purpose != "" || bt.ExpiresAt < time.Now().Unix() {
+		return nil, ErrInvalidChatToken
+	}
+	return bt, nil
+}
+
+// ExportSubscriptions 导出会话的全部订阅
+func ExportSubscriptions(ctx context.Context, store Storage, platform string, chatID int64) (*SubscriptionExport, error) {
+	subs, err := store.GetSubscriptionsByChatID(ctx, platform, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &SubscriptionExport{
+		Version:       SubscriptionExportVersion,
+		Platform:      platform,
+		ExportedAt:    time.Now().Unix(),
+		Subscriptions: make([]ExportedSubscription, 0, len(subs)),
+	}
+	for _, sub := range subs {
+		item := ExportedSubscription{
+			Provider: sub.Provider,
+			Service:  sub.Service,
+			Channel:  sub.Channel,
+		}
+		if sub.EventMask != EventMaskAll {
+			item.Events = FormatEventMask(sub.EventMask)
+		}
+		export.Subscriptions = append(export.Subscriptions, item)
+	}
+	return export, nil
+}
+
+// ParseSubscriptionExport 解析并校验导出数据
+func ParseSubscriptionExport(data []byte) (*SubscriptionExport, error) {
+	var export SubscriptionExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("解析导出数据失败: %w", err)
+	}
+	if export.Version != SubscriptionExportVersion {
+		return nil, fmt.Errorf("不支持的导出格式版本: %d", export.Version)
+	}
+	for i, item := range export.Subscriptions {
+		if strings.TrimSpace(item.Provider) == "" {
+			return nil, fmt.Errorf("第 %d 条订阅缺少 provider", i+1)
+		}
+		if strings.TrimSpace(item.Service) == "" && strings.TrimSpace(item.Channel) != "" {
+			return nil, fmt.Errorf("第 %d 条订阅指定了 channel 但缺少 service", i+1)
+		}
+		if item.Events != "" {
+			if _, err := ParseEventMask(item.Events); err != nil {
+				return nil, fmt.Errorf("第 %d 条订阅事件类型无效: %w", i+1, err)
+			}
+		}
+	}
+	return &export, nil
+}
+
+// ImportSubscriptions 将导出数据合并到会话订阅
+// replace 为 true 时先清空现有订阅；limit > 0 时新增订阅不超过该上限，超出部分计入 Skipped
+// createdBy 记录为新订阅的创建者（0 表示未知）；导入不重新校验监测项是否存在，以便完整还原备份
+func ImportSubscriptions(ctx context.Context, store Storage, platform string, chatID int64, export *SubscriptionExport, limit int, replace bool, createdBy int64) (*ImportResult, error) {
+	if replace {
+		if err := store.ClearSubscriptions(ctx, platform, chatID); err != nil {
+			return nil, err
+		}
+	}
+
+	current, err := store.GetSubscriptionsByChatID(ctx, platform, chatID)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]uint32, len(current))
+	for _, sub := range current {
+		existing[subscriptionKey(sub.Provider, sub.Service, sub.Channel)] = sub.EventMask
+	}
+
+	result := &ImportResult{}
+	for _, item := range export.Subscriptions {
+		provider := strings.TrimSpace(item.Provider)
+		service := strings.TrimSpace(item.Service)
+		channel := strings.TrimSpace(item.Channel)
+
+		var mask uint32
+		if item.Events != "" {
+			m, err := ParseEventMask(item.Events)
+			if err != nil {
+				return nil, err
+			}
+			mask = m
+		}
+
+		key := subscriptionKey(provider, service, channel)
+		if oldMask, ok := existing[key]; ok {
+			// AddSubscription 不会将已有过滤重置为全部，与之保持一致
+			if mask == EventMaskAll || mask == oldMask {
+				result.Existing++
+				continue
+			}
+		} else if limit > 0 && len(existing) >= limit {
+			result.Skipped++
+			continue
+		}
+
+		if err := store.AddSubscription(ctx, &Subscription{
+			Platform:  platform,
+			ChatID:    chatID,
+			Provider:  provider,
+			Service:   service,
+			Channel:   channel,
+			EventMask: mask,
+			CreatedBy: createdBy,
+		}); err != nil {
+			return nil, err
+		}
+		existing[key] = mask
+		result.Imported++
+	}
+	return result, nil
+}
+
+// subscriptionKey 订阅唯一键（与 subscriptions 表唯一约束一致）
+func subscriptionKey(provider, service, channel string) string {
+	return provider + "\x00" + service + "\x00" + channel
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BindTokenPurposeLink 跨平台身份关联令牌（/link 签发，一次性）
+const BindTokenPurposeLink = "link"
+
+// ChatLink 跨平台身份关联
+//
+// 同一 LinkID 下的会话（如同一用户的 Telegram 私聊与 QQ 私聊）视为同一逻辑用户：
+// 多个会话订阅了同一事件时只按 Priority 顺序投递给第一个可用会话
+type ChatLink struct {
+	LinkID    string
+	Platform  string
+	ChatID    int64
+	Priority  int // 投递顺序（越小越优先，首个为首选会话）
+	CreatedAt int64
+}
+
+// ErrInvalidLinkToken 关联令牌不存在、已过期、已使用或不是关联令牌
+var ErrInvalidLinkToken = errors.New("关联令牌无效或已过期")
+
+// CreateLinkToken 为会话签发跨平台关联令牌，在另一会话发送 /link <令牌> 完成关联
+func CreateLinkToken(ctx context.Context, store Storage, platform string, chatID int64, ttl time.Duration) (*BindToken, error) {
+	return createChatToken(ctx, store, platform, chatID, BindTokenPurposeLink, ttl)
+}
+
+// ConsumeLinkToken 校验并消费关联令牌，返回签发令牌的会话
+func ConsumeLinkToken(ctx context.Context, store Storage, token string) (*BindToken, error) {
+	bt, err := store.GetBindToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if bt == nil || bt.Purpose != BindTokenPurposeLink || bt.UsedAt != 0 || bt.ExpiresAt < time.Now().Unix() {
+		return nil, ErrInvalidLinkToken
+	}
+	consumed, err := store.ConsumeBindToken(ctx, token)
+	if err != nil || consumed == nil {
+		// 并发消费时另一方已使用
+		return nil, ErrInvalidLinkToken
+	}
+	return consumed, nil
+}
+
+// newLinkID 生成随机身份标识
+func newLinkID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成身份标识失败: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PausedIndefinitely chats.paused_until 取此值表示无限期暂停（直到 /resume）
+const PausedIndefinitely int64 = -1
+
+// MaxPauseDuration /pause 可指定的最长暂停时长
+const MaxPauseDuration = 90 * 24 * time.Hour
+
+// ErrInvalidPauseDuration /pause 时长无法识别或超出范围
+var ErrInvalidPauseDuration = errors.New("无效的暂停时长")
+
+// pauseUnits /pause 时长支持的天、周单位（其余交给 time.ParseDuration，如 30m、2h、1h30m）
+var pauseUnits = map[string]time.Duration{
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// IsPaused 判断暂停是否在 now 时仍然生效（0 表示未暂停）
+func IsPaused(pausedUntil, now int64) bool {
+	return pausedUntil == PausedIndefinitely || pausedUntil > now
+}
+
+// PauseEndText 暂停截止时间的展示文本（UTC+8）
+func PauseEndText(pausedUntil int64) string {
+	cst := time.FixedZone("CST", 8*60*60)
+	return time.Unix(pausedUntil, 0).In(cst).Format("2006-01-02 15:04")
+}
+
+// ParsePauseDuration 解析 /pause 时长：30m、2h、3d、1w 或 1h30m 等组合，范围 1 分钟 ~ 90 天
+func ParsePauseDuration(arg string) (time.Duration, error) {
+	arg = strings.ToLower(strings.TrimSpace(arg))
+
+	if n := len(arg); n > 1 {
+		if unit, ok := pauseUnits[arg[n-1:]]; ok {
+			count, err := strconv.Atoi(arg[:n-1])
+			if err != nil || count <= 0 || count > int(MaxPauseDuration/unit) {
+				return 0, fmt.Errorf("%w: %s", ErrInvalidPauseDuration, arg)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(arg)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidPauseDuration, arg)
+	}
+	if d < time.Minute || d > MaxPauseDuration {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidPauseDuration, arg)
+	}
+	return d, nil
+}
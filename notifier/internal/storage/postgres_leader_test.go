@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// newPostgresTestStorage 连接 NOTIFIER_TEST_POSTGRES_DSN 指定的数据库（未设置时跳过）
+// 测试会重置 poll_cursor 上的租约，请使用专用的测试库
+func newPostgresTestStorage(t *testing.T) *PostgresStorage {
+	t.Helper()
+	dsn := os.Getenv("NOTIFIER_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("未设置 NOTIFIER_TEST_POSTGRES_DSN，跳过 PostgreSQL 测试")
+	}
+	s, err := NewPostgresStorage(dsn)
+	if err != nil {
+		t.Fatalf("NewPostgresStorage: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	ctx := context.Background()
+	if err := s.Init(ctx); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if _, err := s.pool.Exec(ctx, `UPDATE poll_cursor SET leader_id = '', lease_until = NULL WHERE id = 1`); err != nil {
+		t.Fatalf("reset lease: %v", err)
+	}
+	return s
+}
+
+func mustAcquire(t *testing.T, s *PostgresStorage, instanceID string, lease time.Duration, want bool) {
+	t.Helper()
+	got, err := s.TryAcquireLeadership(context.Background(), instanceID, lease)
+	if err != nil {
+		t.Fatalf("TryAcquireLeadership(%s): %v", instanceID, err)
+	}
+	if got != want {
+		t.Fatalf("TryAcquireLeadership(%s) = %v, want %v", instanceID, got, want)
+	}
+}
+
+func TestPostgresLeaderAcquireRenewRelease(t *testing.T) {
+	s := newPostgresTestStorage(t)
+
+	mustAcquire(t, s, "a", time.Minute, true)
+	mustAcquire(t, s, "b", time.Minute, false)
+	mustAcquire(t, s, "a", time.Minute, true) // 续约
+
+	// 仅持有者可释放
+	if err := s.ReleaseLeadership(context.Background(), "b"); err != nil {
+		t.Fatalf("ReleaseLeadership(b): %v", err)
+	}
+	mustAcquire(t, s, "b", time.Minute, false)
+
+	if err := s.ReleaseLeadership(context.Background(), "a"); err != nil {
+		t.Fatalf("ReleaseLeadership(a): %v", err)
+	}
+	mustAcquire(t, s, "b", time.Minute, true)
+}
+
+func TestPostgresLeaderExpiryTakeover(t *testing.T) {
+	s := newPostgresTestStorage(t)
+
+	mustAcquire(t, s, "a", 200*time.Millisecond, true)
+	mustAcquire(t, s, "b", 200*time.Millisecond, false)
+
+	time.Sleep(300 * time.Millisecond)
+	mustAcquire(t, s, "b", time.Minute, true)
+	mustAcquire(t, s, "a", time.Minute, false)
+}
@@ -0,0 +1,74 @@
+package storage
+
+import "strings"
+
+// 群组角色（chat_roles.role）
+// 私聊中用户始终视为 owner；群聊中未分配角色的成员视为 viewer
+const (
+	RoleOwner  = "owner"  // 编辑订阅与会话设置，并可分配角色
+	RoleEditor = "editor" // 编辑订阅与会话设置
+	RoleViewer = "viewer" // 仅查看订阅与状态
+)
+
+// RoleNames 命令帮助中展示的可选角色
+const RoleNames = "owner, editor, viewer"
+
+// roleRank 角色权限等级（未知角色为 0）
+var roleRank = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// ChatRole 群成员角色
+type ChatRole struct {
+	Platform  string
+	ChatID    int64
+	UserID    int64
+	Role      string
+	GrantedBy int64 // 分配角色的用户（0 表示未知）
+	CreatedAt int64
+	UpdatedAt int64
+}
+
+// ParseRole 解析角色名（大小写不敏感）
+func ParseRole(s string) (string, bool) {
+	role := strings.ToLower(strings.TrimSpace(s))
+	_, ok := roleRank[role]
+	return role, ok
+}
+
+// RoleAtLeast 判断角色是否不低于 required
+func RoleAtLeast(role, required string) bool {
+	return roleRank[role] >= roleRank[required]
+}
+
+// HigherRole 返回两个角色中权限较高者
+func HigherRole(a, b string) string {
+	if roleRank[b] > roleRank[a] {
+		return b
+	}
+	return a
+}
+
+// RequiredRole 群聊中执行命令所需的最低角色
+// /role 不带参数为查看角色，/start 不带 token 为欢迎信息，/announcements 不带参数为查看公告设置，均无需编辑权限
+func RequiredRole(cmd, args string) string {
+	hasArgs := strings.TrimSpace(args) != ""
+	switch cmd {
+	case "role":
+		if hasArgs {
+			return RoleOwner
+		}
+		return RoleViewer
+	case "start", "announcements":
+		if hasArgs {
+			return RoleEditor
+		}
+		return RoleViewer
+	case "add", "remove", "filter", "clear", "lang", "digest", "export", "import", "link", "pause", "resume":
+		return RoleEditor
+	default:
+		return RoleViewer
+	}
+}
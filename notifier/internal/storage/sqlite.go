@@ -146,6 +146,9 @@ func (s *SQLiteStorage) Init(ctx context.Context) error {
 		CREATE TABLE IF NOT EXISTS bind_tokens (
 			token TEXT PRIMARY KEY,
 			favorites TEXT NOT NULL,
+			platform TEXT NOT NULL DEFAULT '',
+			chat_id INTEGER NOT NULL DEFAULT 0,
+			purpose TEXT NOT NULL DEFAULT '',
 			expires_at INTEGER NOT NULL,
 			used_at INTEGER,
 			created_at INTEGER NOT NULL
@@ -154,12 +157,111 @@ func (s *SQLiteStorage) Init(ctx context.Context) error {
 		return fmt.Errorf("创建 bind_tokens 表失败: %w", err)
 	}
 
+	// 会话令牌列（旧库补齐，默认空 = 网页收藏绑定 token）
+	for _, col := range []struct{ name, def string }{
+		{"platform", "TEXT NOT NULL DEFAULT ''"},
+		{"chat_id", "INTEGER NOT NULL DEFAULT 0"},
+		{"purpose", "TEXT NOT NULL DEFAULT ''"},
+	} {
+		has, err := s.hasColumn(ctx, "bind_tokens", col.name)
+		if err != nil {
+			return err
+		}
+		if !has {
+			if _, err := s.db.ExecContext(ctx, `ALTER TABLE bind_tokens ADD COLUMN `+col.name+` `+col.def); err != nil {
+				return fmt.Errorf("添加 bind_tokens.%s 列失败: %w", col.name, err)
+			}
+		}
+	}
+
 	if err := execWithRetry(ctx, s.db, `
 		CREATE INDEX IF NOT EXISTS idx_bind_tokens_expires ON bind_tokens(expires_at)
 	`); err != nil {
 		return fmt.Errorf("创建 bind_tokens 索引失败: %w", err)
 	}
 
+	// 审计日志表
+	if err := execWithRetry(ctx, s.db, `
+		CREATE TABLE IF NOT EXISTS audit_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			timestamp INTEGER NOT NULL,
+			platform TEXT NOT NULL DEFAULT '',
+			actor TEXT NOT NULL,
+			action TEXT NOT NULL,
+			target TEXT NOT NULL DEFAULT '',
+			detail TEXT NOT NULL DEFAULT '',
+			result TEXT NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("创建 audit_log 表失败: %w", err)
+	}
+
+	if err := execWithRetry(ctx, s.db, `
+		CREATE INDEX IF NOT EXISTS idx_audit_log_timestamp ON audit_log(timestamp)
+	`); err != nil {
+		return fmt.Errorf("创建 audit_log 索引失败: %w", err)
+	}
+
+	// 故障记录表（摘要统计）
+	if err := execWithRetry(ctx, s.db, `
+		CREATE TABLE IF NOT EXISTS incidents (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			event_id INTEGER NOT NULL UNIQUE,
+			provider TEXT NOT NULL,
+			service TEXT NOT NULL,
+			channel TEXT NOT NULL DEFAULT '',
+			observed_at INTEGER NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("创建 incidents 表失败: %w", err)
+	}
+
+	if err := execWithRetry(ctx, s.db, `
+		CREATE INDEX IF NOT EXISTS idx_incidents_observed ON incidents(observed_at)
+	`); err != nil {
+		return fmt.Errorf("创建 incidents 索引失败: %w", err)
+	}
+
+	// 事件缓存表（轮询到的事件元数据，/stats 统计）
+	if err := execWithRetry(ctx, s.db, `
+		CREATE TABLE IF NOT EXISTS events (
+			id INTEGER PRIMARY KEY,
+			provider TEXT NOT NULL,
+			service TEXT NOT NULL,
+			channel TEXT NOT NULL DEFAULT '',
+			model TEXT NOT NULL DEFAULT '',
+			type TEXT NOT NULL,
+			observed_at INTEGER NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("创建 events 表失败: %w", err)
+	}
+
+	if err := execWithRetry(ctx, s.db, `
+		CREATE INDEX IF NOT EXISTS idx_events_observed ON events(observed_at)
+	`); err != nil {
+		return fmt.Errorf("创建 events 索引失败: %w", err)
+	}
+
+	// 公告广播表
+	if err := execWithRetry(ctx, s.db, `
+		CREATE TABLE IF NOT EXISTS broadcasts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			actor TEXT NOT NULL,
+			provider TEXT NOT NULL DEFAULT '',
+			message TEXT NOT NULL,
+			status TEXT NOT NULL,
+			total INTEGER NOT NULL DEFAULT 0,
+			sent INTEGER NOT NULL DEFAULT 0,
+			failed INTEGER NOT NULL DEFAULT 0,
+			opted_out INTEGER NOT NULL DEFAULT 0,
+			created_at INTEGER NOT NULL,
+			finished_at INTEGER NOT NULL DEFAULT 0
+		)
+	`); err != nil {
+		return fmt.Errorf("创建 broadcasts 表失败: %w", err)
+	}
+
 	return nil
 }
 
@@ -392,6 +494,12 @@ func (s *SQLiteStorage) ensureMultiPlatformSchema(ctx context.Context) error {
 			status TEXT NOT NULL DEFAULT 'active',
 			last_command_at INTEGER,
 			command_count INTEGER NOT NULL DEFAULT 0,
+			language TEXT NOT NULL DEFAULT '',
+			digest_frequency TEXT NOT NULL DEFAULT '',
+			digest_hour INTEGER NOT NULL DEFAULT 9,
+			digest_sent_at INTEGER NOT NULL DEFAULT 0,
+			announcements_off INTEGER NOT NULL DEFAULT 0,
+			paused_until INTEGER NOT NULL DEFAULT 0,
 			created_at INTEGER NOT NULL,
 			updated_at INTEGER NOT NULL,
 			PRIMARY KEY (platform, chat_id)
@@ -400,6 +508,62 @@ func (s *SQLiteStorage) ensureMultiPlatformSchema(ctx context.Context) error {
 		return fmt.Errorf("创建 chats 表失败: %w", err)
 	}
 
+	// 语言偏好列（旧库补齐，默认空 = 未设置）
+	hasLanguage, err := s.hasColumn(ctx, "chats", "language")
+	if err != nil {
+		return err
+	}
+	if !hasLanguage {
+		if _, err := s.db.ExecContext(ctx, `
+			ALTER TABLE chats ADD COLUMN language TEXT NOT NULL DEFAULT ''
+		`); err != nil {
+			return fmt.Errorf("添加 chats.language 列失败: %w", err)
+		}
+	}
+
+	// 摘要设置列（旧库补齐，默认关闭）
+	for _, col := range []struct{ name, def string }{
+		{"digest_frequency", "TEXT NOT NULL DEFAULT ''"},
+		{"digest_hour", "INTEGER NOT NULL DEFAULT 9"},
+		{"digest_sent_at", "INTEGER NOT NULL DEFAULT 0"},
+	} {
+		has, err := s.hasColumn(ctx, "chats", col.name)
+		if err != nil {
+			return err
+		}
+		if !has {
+			if _, err := s.db.ExecContext(ctx, `ALTER TABLE chats ADD COLUMN `+col.name+` `+col.def); err != nil {
+				return fmt.Errorf("添加 chats.%s 列失败: %w", col.name, err)
+			}
+		}
+	}
+
+	// 公告退订列（旧库补齐，默认接收）
+	hasAnnouncementsOff, err := s.hasColumn(ctx, "chats", "announcements_off")
+	if err != nil {
+		return err
+	}
+	if !hasAnnouncementsOff {
+		if _, err := s.db.ExecContext(ctx, `
+			ALTER TABLE chats ADD COLUMN announcements_off INTEGER NOT NULL DEFAULT 0
+		`); err != nil {
+			return fmt.Errorf("添加 chats.announcements_off 列失败: %w", err)
+		}
+	}
+
+	// 通知暂停列（旧库补齐，默认未暂停）
+	hasPausedUntil, err := s.hasColumn(ctx, "chats", "paused_until")
+	if err != nil {
+		return err
+	}
+	if !hasPausedUntil {
+		if _, err := s.db.ExecContext(ctx, `
+			ALTER TABLE chats ADD COLUMN paused_until INTEGER NOT NULL DEFAULT 0
+		`); err != nil {
+			return fmt.Errorf("添加 chats.paused_until 列失败: %w", err)
+		}
+	}
+
 	// subscriptions 表
 	if _, err := s.db.ExecContext(ctx, `
 		CREATE TABLE IF NOT EXISTS subscriptions (
@@ -409,6 +573,7 @@ func (s *SQLiteStorage) ensureMultiPlatformSchema(ctx context.Context) error {
 			provider TEXT NOT NULL,
 			service TEXT NOT NULL,
 			channel TEXT NOT NULL DEFAULT '',
+			event_mask INTEGER NOT NULL DEFAULT 0,
 			created_at INTEGER NOT NULL,
 			UNIQUE(platform, chat_id, provider, service, channel),
 			FOREIGN KEY (platform, chat_id) REFERENCES chats(platform, chat_id) ON DELETE CASCADE
@@ -417,6 +582,69 @@ func (s *SQLiteStorage) ensureMultiPlatformSchema(ctx context.Context) error {
 		return fmt.Errorf("创建 subscriptions 表失败: %w", err)
 	}
 
+	// 事件类型过滤列（旧库补齐，默认 0 = 接收全部类型）
+	hasEventMask, err := s.hasColumn(ctx, "subscriptions", "event_mask")
+	if err != nil {
+		return err
+	}
+	if !hasEventMask {
+		if _, err := s.db.ExecContext(ctx, `
+			ALTER TABLE subscriptions ADD COLUMN event_mask INTEGER NOT NULL DEFAULT 0
+		`); err != nil {
+			return fmt.Errorf("添加 subscriptions.event_mask 列失败: %w", err)
+		}
+	}
+
+	// 订阅创建者列（旧库补齐，默认 0 = 未知）
+	hasCreatedBy, err := s.hasColumn(ctx, "subscriptions", "created_by")
+	if err != nil {
+		return err
+	}
+	if !hasCreatedBy {
+		if _, err := s.db.ExecContext(ctx, `
+			ALTER TABLE subscriptions ADD COLUMN created_by INTEGER NOT NULL DEFAULT 0
+		`); err != nil {
+			return fmt.Errorf("添加 subscriptions.created_by 列失败: %w", err)
+		}
+	}
+
+	// 群组角色表
+	if _, err := s.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS chat_roles (
+			platform TEXT NOT NULL,
+			chat_id INTEGER NOT NULL,
+			user_id INTEGER NOT NULL,
+			role TEXT NOT NULL,
+			granted_by INTEGER NOT NULL DEFAULT 0,
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL,
+			PRIMARY KEY (platform, chat_id, user_id),
+			FOREIGN KEY (platform, chat_id) REFERENCES chats(platform, chat_id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("创建 chat_roles 表失败: %w", err)
+	}
+
+	// 跨平台身份关联表（同一 link_id 的会话视为同一用户，通知只投递一次）
+	if _, err := s.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS chat_links (
+			platform TEXT NOT NULL,
+			chat_id INTEGER NOT NULL,
+			link_id TEXT NOT NULL,
+			priority INTEGER NOT NULL DEFAULT 0,
+			created_at INTEGER NOT NULL,
+			PRIMARY KEY (platform, chat_id),
+			FOREIGN KEY (platform, chat_id) REFERENCES chats(platform, chat_id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("创建 chat_links 表失败: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_chat_links_link ON chat_links(link_id)
+	`); err != nil {
+		return fmt.Errorf("创建 chat_links 索引失败: %w", err)
+	}
+
 	// 订阅索引
 	if _, err := s.db.ExecContext(ctx, `
 		CREATE INDEX IF NOT EXISTS idx_subscriptions_psc ON subscriptions(provider, service, channel)
@@ -440,6 +668,7 @@ func (s *SQLiteStorage) ensureMultiPlatformSchema(ctx context.Context) error {
 			message_id TEXT,
 			error_message TEXT,
 			retry_count INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at INTEGER NOT NULL DEFAULT 0,
 			created_at INTEGER NOT NULL,
 			updated_at INTEGER NOT NULL,
 			UNIQUE(event_id, platform, chat_id)
@@ -448,6 +677,19 @@ func (s *SQLiteStorage) ensureMultiPlatformSchema(ctx context.Context) error {
 		return fmt.Errorf("创建 deliveries 表失败: %w", err)
 	}
 
+	// 重试退避列（旧库补齐，默认 0 = 立即可重试）
+	hasNextAttempt, err := s.hasColumn(ctx, "deliveries", "next_attempt_at")
+	if err != nil {
+		return err
+	}
+	if !hasNextAttempt {
+		if _, err := s.db.ExecContext(ctx, `
+			ALTER TABLE deliveries ADD COLUMN next_attempt_at INTEGER NOT NULL DEFAULT 0
+		`); err != nil {
+			return fmt.Errorf("添加 deliveries.next_attempt_at 列失败: %w", err)
+		}
+	}
+
 	// deliveries 索引
 	if _, err := s.db.ExecContext(ctx, `
 		CREATE INDEX IF NOT EXISTS idx_deliveries_pending ON deliveries(status, created_at) WHERE status = 'pending'
@@ -459,6 +701,11 @@ func (s *SQLiteStorage) ensureMultiPlatformSchema(ctx context.Context) error {
 	`); err != nil {
 		return fmt.Errorf("创建 deliveries 索引失败: %w", err)
 	}
+	if _, err := s.db.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS idx_deliveries_chat ON deliveries(platform, chat_id, created_at)
+	`); err != nil {
+		return fmt.Errorf("创建 deliveries 索引失败: %w", err)
+	}
 
 	return nil
 }
@@ -491,6 +738,34 @@ func (s *SQLiteStorage) UpdateCursor(ctx context.Context, lastEventID int64) err
 	return nil
 }
 
+// ResetCursor 回拨轮询游标并清理旧事件 ID 空间的投递记录
+func (s *SQLiteStorage) ResetCursor(ctx context.Context, lastEventID int64) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE poll_cursor SET last_event_id = ?, updated_at = ? WHERE id = 1
+	`, lastEventID, time.Now().Unix()); err != nil {
+		return 0, fmt.Errorf("重置游标失败: %w", err)
+	}
+	result, err := tx.ExecContext(ctx, `DELETE FROM deliveries WHERE event_id > ?`, lastEventID)
+	if err != nil {
+		return 0, fmt.Errorf("清理投递记录失败: %w", err)
+	}
+	deleted, _ := result.RowsAffected()
+	if _, err := tx.ExecContext(ctx, `DELETE FROM events WHERE id > ?`, lastEventID); err != nil {
+		return 0, fmt.Errorf("清理事件缓存失败: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("提交事务失败: %w", err)
+	}
+	return deleted, nil
+}
+
 // ===== Chat 管理（多平台） =====
 
 // UpsertChat 创建或更新 Chat
@@ -516,11 +791,13 @@ func (s *SQLiteStorage) GetChat(ctx context.Context, platform string, chatID int
 	var lastCommandAt sql.NullInt64
 
 	err := s.db.QueryRowContext(ctx, `
-		SELECT platform, chat_id, username, first_name, status, last_command_at, command_count, created_at, updated_at
+		SELECT platform, chat_id, username, first_name, status, last_command_at, command_count, language, created_at, updated_at,
+			digest_frequency, digest_hour, digest_sent_at, announcements_off, paused_until
 		FROM chats WHERE platform = ? AND chat_id = ?
 	`, platform, chatID).Scan(
 		&chat.Platform, &chat.ChatID, &chat.Username, &chat.FirstName, &chat.Status,
-		&lastCommandAt, &chat.CommandCount, &chat.CreatedAt, &chat.UpdatedAt,
+		&lastCommandAt, &chat.CommandCount, &chat.Language, &chat.CreatedAt, &chat.UpdatedAt,
+		&chat.DigestFrequency, &chat.DigestHour, &chat.DigestSentAt, &chat.AnnouncementsOff, &chat.PausedUntil,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -560,22 +837,68 @@ func (s *SQLiteStorage) UpdateChatCommandTime(ctx context.Context, platform stri
 	return nil
 }
 
+// UpdateChatLanguage 更新 Chat 语言偏好
+func (s *SQLiteStorage) UpdateChatLanguage(ctx context.Context, platform string, chatID int64, language string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE chats SET language = ?, updated_at = ? WHERE platform = ? AND chat_id = ?
+	`, language, time.Now().Unix(), platform, chatID)
+	if err != nil {
+		return fmt.Errorf("更新语言偏好失败: %w", err)
+	}
+	return nil
+}
+
+// UpdateChatPause 设置通知暂停截止时间
+func (s *SQLiteStorage) UpdateChatPause(ctx context.Context, platform string, chatID int64, pausedUntil int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE chats SET paused_until = ?, updated_at = ? WHERE platform = ? AND chat_id = ?
+	`, pausedUntil, time.Now().Unix(), platform, chatID)
+	if err != nil {
+		return fmt.Errorf("更新通知暂停设置失败: %w", err)
+	}
+	return nil
+}
+
 // ===== 订阅管理 =====
 
 // AddSubscription 添加订阅
+// 订阅已存在时仅在指定了事件类型过滤（EventMask 非 0）时更新过滤，不会把已有过滤重置为全部
 func (s *SQLiteStorage) AddSubscription(ctx context.Context, sub *Subscription) error {
 	now := time.Now().Unix()
 	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO subscriptions (platform, chat_id, provider, service, channel, created_at)
-		VALUES (?, ?, ?, ?, ?, ?)
-		ON CONFLICT(platform, chat_id, provider, service, channel) DO NOTHING
-	`, sub.Platform, sub.ChatID, sub.Provider, sub.Service, sub.Channel, now)
+		INSERT INTO subscriptions (platform, chat_id, provider, service, channel, event_mask, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(platform, chat_id, provider, service, channel) DO UPDATE SET
+			event_mask = excluded.event_mask
+		WHERE excluded.event_mask <> 0
+	`, sub.Platform, sub.ChatID, sub.Provider, sub.Service, sub.Channel, sub.EventMask, sub.CreatedBy, now)
 	if err != nil {
 		return fmt.Errorf("添加订阅失败: %w", err)
 	}
 	return nil
 }
 
+// SetSubscriptionEventMask 设置订阅的事件类型过滤
+// 匹配规则与 RemoveSubscription 一致：provider 级 / service 级 / 精确
+func (s *SQLiteStorage) SetSubscriptionEventMask(ctx context.Context, platform string, chatID int64, provider, service, channel string, mask uint32) (int64, error) {
+	query := `UPDATE subscriptions SET event_mask = ? WHERE platform = ? AND chat_id = ? AND provider = ?`
+	args := []any{mask, platform, chatID, provider}
+	if service != "" {
+		query += ` AND service = ?`
+		args = append(args, service)
+		if channel != "" {
+			query += ` AND channel = ?`
+			args = append(args, channel)
+		}
+	}
+
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("更新订阅过滤失败: %w", err)
+	}
+	return result.RowsAffected()
+}
+
 // RemoveSubscription 移除订阅
 // 支持级联删除：
 // - service=="" && channel=="" → 删除该 provider 下所有订阅
@@ -610,7 +933,7 @@ func (s *SQLiteStorage) RemoveSubscription(ctx context.Context, platform string,
 // GetSubscriptionsByChatID 获取用户的所有订阅
 func (s *SQLiteStorage) GetSubscriptionsByChatID(ctx context.Context, platform string, chatID int64) ([]*Subscription, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, platform, chat_id, provider, service, channel, created_at
+		SELECT id, platform, chat_id, provider, service, channel, event_mask, created_by, created_at
 		FROM subscriptions WHERE platform = ? AND chat_id = ? ORDER BY created_at DESC
 	`, platform, chatID)
 	if err != nil {
@@ -621,7 +944,7 @@ func (s *SQLiteStorage) GetSubscriptionsByChatID(ctx context.Context, platform s
 	var subs []*Subscription
 	for rows.Next() {
 		sub := &Subscription{}
-		if err := rows.Scan(&sub.ID, &sub.Platform, &sub.ChatID, &sub.Provider, &sub.Service, &sub.Channel, &sub.CreatedAt); err != nil {
+		if err := rows.Scan(&sub.ID, &sub.Platform, &sub.ChatID, &sub.Provider, &sub.Service, &sub.Channel, &sub.EventMask, &sub.CreatedBy, &sub.CreatedAt); err != nil {
 			return nil, fmt.Errorf("扫描订阅失败: %w", err)
 		}
 		subs = append(subs, sub)
@@ -634,11 +957,14 @@ func (s *SQLiteStorage) GetSubscriptionsByChatID(ctx context.Context, platform s
 // 匹配规则：
 // - service 为空时匹配所有 service，否则精确匹配
 // - channel 为空时匹配所有 channel，否则精确匹配
-// 使用 DISTINCT 避免重复（用户可能同时有通配和精确订阅）
+// 用户可能同时有通配和精确订阅，按 Chat 去重并合并事件类型过滤
 func (s *SQLiteStorage) GetSubscribersByMonitor(ctx context.Context, provider, service, channel string) ([]*ChatRef, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT DISTINCT s.platform, s.chat_id FROM subscriptions s
+		SELECT s.platform, s.chat_id, s.event_mask, c.language,
+		       COALESCE(l.link_id, ''), COALESCE(l.priority, 0), c.paused_until
+		FROM subscriptions s
 		JOIN chats c ON s.platform = c.platform AND s.chat_id = c.chat_id
+		LEFT JOIN chat_links l ON s.platform = l.platform AND s.chat_id = l.chat_id
 		WHERE s.provider = ?
 		  AND (s.service = '' OR s.service = ?)
 		  AND (s.channel = '' OR s.channel = ?)
@@ -652,13 +978,13 @@ func (s *SQLiteStorage) GetSubscribersByMonitor(ctx context.Context, provider, s
 	var refs []*ChatRef
 	for rows.Next() {
 		ref := &ChatRef{}
-		if err := rows.Scan(&ref.Platform, &ref.ChatID); err != nil {
+		if err := rows.Scan(&ref.Platform, &ref.ChatID, &ref.EventMask, &ref.Language, &ref.LinkID, &ref.LinkPriority, &ref.PausedUntil); err != nil {
 			return nil, fmt.Errorf("扫描订阅者失败: %w", err)
 		}
 		refs = append(refs, ref)
 	}
 
-	return refs, nil
+	return mergeChatRefs(refs), nil
 }
 
 // CountSubscriptions 统计用户订阅数
@@ -691,9 +1017,9 @@ func (s *SQLiteStorage) ClearSubscriptions(ctx context.Context, platform string,
 // CreateBindToken 创建绑定 token
 func (s *SQLiteStorage) CreateBindToken(ctx context.Context, token *BindToken) error {
 	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO bind_tokens (token, favorites, expires_at, created_at)
-		VALUES (?, ?, ?, ?)
-	`, token.Token, token.Favorites, token.ExpiresAt, token.CreatedAt)
+		INSERT INTO bind_tokens (token, favorites, platform, chat_id, purpose, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, token.Token, token.Favorites, token.Platform, token.ChatID, token.Purpose, token.ExpiresAt, token.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("创建绑定 token 失败: %w", err)
 	}
@@ -706,9 +1032,9 @@ func (s *SQLiteStorage) GetBindToken(ctx context.Context, token string) (*BindTo
 	var usedAt sql.NullInt64
 
 	err := s.db.QueryRowContext(ctx, `
-		SELECT token, favorites, expires_at, used_at, created_at
+		SELECT token, favorites, platform, chat_id, purpose, expires_at, used_at, created_at
 		FROM bind_tokens WHERE token = ?
-	`, token).Scan(&bt.Token, &bt.Favorites, &bt.ExpiresAt, &usedAt, &bt.CreatedAt)
+	`, token).Scan(&bt.Token, &bt.Favorites, &bt.Platform, &bt.ChatID, &bt.Purpose, &bt.ExpiresAt, &usedAt, &bt.CreatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -738,9 +1064,9 @@ func (s *SQLiteStorage) ConsumeBindToken(ctx context.Context, token string) (*Bi
 	var usedAt sql.NullInt64
 
 	err = tx.QueryRowContext(ctx, `
-		SELECT token, favorites, expires_at, used_at, created_at
+		SELECT token, favorites, platform, chat_id, purpose, expires_at, used_at, created_at
 		FROM bind_tokens WHERE token = ?
-	`, token).Scan(&bt.Token, &bt.Favorites, &bt.ExpiresAt, &usedAt, &bt.CreatedAt)
+	`, token).Scan(&bt.Token, &bt.Favorites, &bt.Platform, &bt.ChatID, &bt.Purpose, &bt.ExpiresAt, &usedAt, &bt.CreatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -795,6 +1121,14 @@ func (s *SQLiteStorage) CreateDelivery(ctx context.Context, delivery *Delivery)
 		return fmt.Errorf("创建投递记录失败: %w", err)
 	}
 
+	// 已存在同一事件与目标的投递记录时不插入，ID 置 0 供调用方跳过重复发送
+	// （冲突时 LastInsertId 仍返回连接上一次插入的 ID，不能据此判断）
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("创建投递记录失败: %w", err)
+	} else if n == 0 {
+		delivery.ID = 0
+		return nil
+	}
 	id, _ := result.LastInsertId()
 	delivery.ID = id
 	delivery.CreatedAt = now
@@ -814,22 +1148,83 @@ func (s *SQLiteStorage) UpdateDeliveryStatus(ctx context.Context, id int64, stat
 	return nil
 }
 
-// GetPendingDeliveries 获取待发送的投递记录
+// GetPendingDeliveries 获取已到重试时间的待发送投递记录
 func (s *SQLiteStorage) GetPendingDeliveries(ctx context.Context, limit int) ([]*Delivery, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, event_id, platform, chat_id, status, message_id, error_message, retry_count, created_at, updated_at
-		FROM deliveries WHERE status = 'pending' ORDER BY created_at ASC LIMIT ?
-	`, limit)
+		SELECT `+sqliteDeliveryColumns+`
+		FROM deliveries WHERE status = 'pending' AND next_attempt_at <= ? ORDER BY created_at ASC LIMIT ?
+	`, time.Now().Unix(), limit)
 	if err != nil {
 		return nil, fmt.Errorf("查询待发送投递失败: %w", err)
 	}
 	defer rows.Close()
 
+	return scanSQLiteDeliveries(rows)
+}
+
+// ScheduleDeliveryRetry 记录一次发送失败并安排下次重试
+func (s *SQLiteStorage) ScheduleDeliveryRetry(ctx context.Context, id int64, nextAttemptAt int64, errorMsg string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE deliveries SET status = 'pending', retry_count = retry_count + 1, next_attempt_at = ?, error_message = ?, updated_at = ?
+		WHERE id = ?
+	`, nextAttemptAt, errorMsg, time.Now().Unix(), id)
+	if err != nil {
+		return fmt.Errorf("安排投递重试失败: %w", err)
+	}
+	return nil
+}
+
+// GetDeadDeliveries 查询死信投递（按 id 倒序）
+func (s *SQLiteStorage) GetDeadDeliveries(ctx context.Context, filter DeliveryFilter) ([]*Delivery, error) {
+	query := `SELECT ` + sqliteDeliveryColumns + ` FROM deliveries WHERE status = 'dead'`
+	var args []any
+	if filter.Platform != "" {
+		query += ` AND platform = ?`
+		args = append(args, filter.Platform)
+	}
+	if filter.BeforeID > 0 {
+		query += ` AND id < ?`
+		args = append(args, filter.BeforeID)
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, filter.Limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询死信投递失败: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSQLiteDeliveries(rows)
+}
+
+// RequeueDeadDelivery 将死信投递重新放回待发送队列
+func (s *SQLiteStorage) RequeueDeadDelivery(ctx context.Context, id int64) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE deliveries SET status = 'pending', retry_count = 0, next_attempt_at = 0, updated_at = ?
+		WHERE id = ? AND status = 'dead'
+	`, time.Now().Unix(), id)
+	if err != nil {
+		return false, fmt.Errorf("重新入队死信投递失败: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("重新入队死信投递失败: %w", err)
+	}
+	return n > 0, nil
+}
+
+// sqliteDeliveryColumns 投递记录查询列（与 scanSQLiteDeliveries 对应）
+const sqliteDeliveryColumns = `id, event_id, platform, chat_id, status, message_id, error_message, retry_count, next_attempt_at, created_at, updated_at`
+
+// scanSQLiteDeliveries 扫描投递记录
+func scanSQLiteDeliveries(rows *sql.Rows) ([]*Delivery, error) {
 	var deliveries []*Delivery
 	for rows.Next() {
 		d := &Delivery{}
 		var messageID, errorMessage sql.NullString
-		if err := rows.Scan(&d.ID, &d.EventID, &d.Platform, &d.ChatID, &d.Status, &messageID, &errorMessage, &d.RetryCount, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		if err := rows.Scan(&d.ID, &d.EventID, &d.Platform, &d.ChatID, &d.Status, &messageID, &errorMessage,
+			&d.RetryCount, &d.NextAttemptAt, &d.CreatedAt, &d.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("扫描投递记录失败: %w", err)
 		}
 		if messageID.Valid {
@@ -841,27 +1236,599 @@ func (s *SQLiteStorage) GetPendingDeliveries(ctx context.Context, limit int) ([]
 		deliveries = append(deliveries, d)
 	}
 
-	return deliveries, nil
+	return deliveries, rows.Err()
+}
+
+// CleanupOldDeliveries 清理旧的投递记录
+func (s *SQLiteStorage) CleanupOldDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM deliveries WHERE created_at < ? AND status IN ('sent', 'failed')
+	`, before.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("清理旧投递记录失败: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// ===== 审计日志 =====
+
+// AddAuditEntry 写入审计日志
+func (s *SQLiteStorage) AddAuditEntry(ctx context.Context, entry *AuditEntry) error {
+	if entry.Timestamp == 0 {
+		entry.Timestamp = time.Now().Unix()
+	}
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO audit_log (timestamp, platform, actor, action, target, detail, result)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, entry.Timestamp, entry.Platform, entry.Actor, entry.Action, entry.Target, entry.Detail, entry.Result)
+	if err != nil {
+		return fmt.Errorf("写入审计日志失败: %w", err)
+	}
+
+	id, _ := result.LastInsertId()
+	entry.ID = id
+	return nil
+}
+
+// GetAuditEntries 查询审计日志（按 id 倒序）
+func (s *SQLiteStorage) GetAuditEntries(ctx context.Context, filter AuditFilter) ([]*AuditEntry, error) {
+	query := `SELECT id, timestamp, platform, actor, action, target, detail, result FROM audit_log WHERE 1 = 1`
+	var args []any
+	if filter.Actor != "" {
+		query += ` AND actor = ?`
+		args = append(args, filter.Actor)
+	}
+	if filter.Action != "" {
+		query += ` AND action = ?`
+		args = append(args, filter.Action)
+	}
+	if filter.Since > 0 {
+		query += ` AND timestamp >= ?`
+		args = append(args, filter.Since)
+	}
+	if filter.BeforeID > 0 {
+		query += ` AND id < ?`
+		args = append(args, filter.BeforeID)
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, filter.Limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询审计日志失败: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*AuditEntry
+	for rows.Next() {
+		e := &AuditEntry{}
+		if err := rows.Scan(&e.ID, &e.Timestamp, &e.Platform, &e.Actor, &e.Action, &e.Target, &e.Detail, &e.Result); err != nil {
+			return nil, fmt.Errorf("扫描审计日志失败: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
 }
 
-// IncrementRetryCount 增加重试次数
-func (s *SQLiteStorage) IncrementRetryCount(ctx context.Context, id int64) error {
+// CleanupOldAuditEntries 清理旧的审计日志
+func (s *SQLiteStorage) CleanupOldAuditEntries(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM audit_log WHERE timestamp < ?`, before.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("清理旧审计日志失败: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// ===== 定期摘要 =====
+
+// UpdateChatDigest 设置 Chat 的摘要频率与发送时刻
+func (s *SQLiteStorage) UpdateChatDigest(ctx context.Context, platform string, chatID int64, frequency string, hour int) error {
+	now := time.Now().Unix()
 	_, err := s.db.ExecContext(ctx, `
-		UPDATE deliveries SET retry_count = retry_count + 1, updated_at = ? WHERE id = ?
-	`, time.Now().Unix(), id)
+		UPDATE chats SET digest_frequency = ?, digest_hour = ?, digest_sent_at = ?, updated_at = ?
+		WHERE platform = ? AND chat_id = ?
+	`, frequency, hour, now, now, platform, chatID)
 	if err != nil {
-		return fmt.Errorf("增加重试次数失败: %w", err)
+		return fmt.Errorf("更新摘要设置失败: %w", err)
 	}
 	return nil
 }
 
-// CleanupOldDeliveries 清理旧的投递记录
-func (s *SQLiteStorage) CleanupOldDeliveries(ctx context.Context, before time.Time) (int64, error) {
+// GetDigestChats 获取开启了摘要的活跃 Chat
+func (s *SQLiteStorage) GetDigestChats(ctx context.Context) ([]*Chat, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT platform, chat_id, COALESCE(username, ''), COALESCE(first_name, ''), language,
+			digest_frequency, digest_hour, digest_sent_at, paused_until
+		FROM chats
+		WHERE digest_frequency != '' AND status = 'active'
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("查询摘要 Chat 失败: %w", err)
+	}
+	defer rows.Close()
+
+	var chats []*Chat
+	for rows.Next() {
+		chat := &Chat{Status: ChatStatusActive}
+		if err := rows.Scan(&chat.Platform, &chat.ChatID, &chat.Username, &chat.FirstName, &chat.Language,
+			&chat.DigestFrequency, &chat.DigestHour, &chat.DigestSentAt, &chat.PausedUntil); err != nil {
+			return nil, fmt.Errorf("扫描摘要 Chat 失败: %w", err)
+		}
+		chats = append(chats, chat)
+	}
+
+	return chats, rows.Err()
+}
+
+// ClaimDigest 抢占一次摘要发送
+func (s *SQLiteStorage) ClaimDigest(ctx context.Context, platform string, chatID int64, prevSentAt, sentAt int64) (bool, error) {
 	result, err := s.db.ExecContext(ctx, `
-		DELETE FROM deliveries WHERE created_at < ? AND status IN ('sent', 'failed')
-	`, before.Unix())
+		UPDATE chats SET digest_sent_at = ?
+		WHERE platform = ? AND chat_id = ? AND digest_sent_at = ?
+	`, sentAt, platform, chatID, prevSentAt)
 	if err != nil {
-		return 0, fmt.Errorf("清理旧投递记录失败: %w", err)
+		return false, fmt.Errorf("更新摘要发送时间失败: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// AddIncident 记录故障（按 event_id 幂等）
+func (s *SQLiteStorage) AddIncident(ctx context.Context, incident *Incident) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO incidents (event_id, provider, service, channel, observed_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, incident.EventID, incident.Provider, incident.Service, incident.Channel, incident.ObservedAt)
+	if err != nil {
+		return fmt.Errorf("记录故障失败: %w", err)
+	}
+	return nil
+}
+
+// GetIncidentsSince 获取指定时间之后发生的故障
+func (s *SQLiteStorage) GetIncidentsSince(ctx context.Context, since int64) ([]*Incident, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, event_id, provider, service, channel, observed_at
+		FROM incidents WHERE observed_at >= ? ORDER BY observed_at
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("查询故障记录失败: %w", err)
+	}
+	defer rows.Close()
+
+	var incidents []*Incident
+	for rows.Next() {
+		inc := &Incident{}
+		if err := rows.Scan(&inc.ID, &inc.EventID, &inc.Provider, &inc.Service, &inc.Channel, &inc.ObservedAt); err != nil {
+			return nil, fmt.Errorf("扫描故障记录失败: %w", err)
+		}
+		incidents = append(incidents, inc)
+	}
+
+	return incidents, rows.Err()
+}
+
+// CleanupOldIncidents 清理旧的故障记录
+func (s *SQLiteStorage) CleanupOldIncidents(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM incidents WHERE observed_at < ?`, before.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("清理旧故障记录失败: %w", err)
 	}
 	return result.RowsAffected()
 }
+
+// ===== 事件缓存 =====
+
+// CacheEvents 缓存事件元数据（按事件 ID 幂等）
+func (s *SQLiteStorage) CacheEvents(ctx context.Context, events []*CachedEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, e := range events {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO events (id, provider, service, channel, model, type, observed_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, e.ID, e.Provider, e.Service, e.Channel, e.Model, e.Type, e.ObservedAt); err != nil {
+			return fmt.Errorf("缓存事件失败: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+	return nil
+}
+
+// GetCachedEvents 获取指定时间之后发生的事件
+func (s *SQLiteStorage) GetCachedEvents(ctx context.Context, since int64) ([]*CachedEvent, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, provider, service, channel, model, type, observed_at
+		FROM events WHERE observed_at >= ? ORDER BY observed_at, id
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("查询事件缓存失败: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*CachedEvent
+	for rows.Next() {
+		e := &CachedEvent{}
+		if err := rows.Scan(&e.ID, &e.Provider, &e.Service, &e.Channel, &e.Model, &e.Type, &e.ObservedAt); err != nil {
+			return nil, fmt.Errorf("扫描事件缓存失败: %w", err)
+		}
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+// CleanupOldEvents 清理旧的事件缓存
+func (s *SQLiteStorage) CleanupOldEvents(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM events WHERE observed_at < ?`, before.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("清理旧事件缓存失败: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// GetDeliveryCounts 按监测项统计会话成功投递的通知数
+func (s *SQLiteStorage) GetDeliveryCounts(ctx context.Context, platform string, chatID int64, since, recentSince int64) ([]*MonitorDeliveryCount, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT COALESCE(e.provider, ''), COALESCE(e.service, ''), COALESCE(e.channel, ''),
+		       SUM(CASE WHEN d.created_at >= ? THEN 1 ELSE 0 END), COUNT(*)
+		FROM deliveries d
+		LEFT JOIN events e ON e.id = d.event_id
+		WHERE d.platform = ? AND d.chat_id = ? AND d.status = 'sent' AND d.created_at >= ?
+		GROUP BY 1, 2, 3
+	`, recentSince, platform, chatID, since)
+	if err != nil {
+		return nil, fmt.Errorf("统计投递数失败: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []*MonitorDeliveryCount
+	for rows.Next() {
+		c := &MonitorDeliveryCount{}
+		if err := rows.Scan(&c.Provider, &c.Service, &c.Channel, &c.Recent, &c.Total); err != nil {
+			return nil, fmt.Errorf("扫描投递统计失败: %w", err)
+		}
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
+
+// ===== 群组角色 =====
+
+// GetChatRole 获取群成员角色（未分配时返回空字符串）
+func (s *SQLiteStorage) GetChatRole(ctx context.Context, platform string, chatID, userID int64) (string, error) {
+	var role string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT role FROM chat_roles WHERE platform = ? AND chat_id = ? AND user_id = ?
+	`, platform, chatID, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("查询群成员角色失败: %w", err)
+	}
+	return role, nil
+}
+
+// SetChatRole 设置群成员角色（已存在时覆盖）
+func (s *SQLiteStorage) SetChatRole(ctx context.Context, role *ChatRole) error {
+	now := time.Now().Unix()
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO chat_roles (platform, chat_id, user_id, role, granted_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(platform, chat_id, user_id) DO UPDATE SET
+			role = excluded.role,
+			granted_by = excluded.granted_by,
+			updated_at = excluded.updated_at
+	`, role.Platform, role.ChatID, role.UserID, role.Role, role.GrantedBy, now, now)
+	if err != nil {
+		return fmt.Errorf("设置群成员角色失败: %w", err)
+	}
+	return nil
+}
+
+// RemoveChatRole 移除群成员角色
+func (s *SQLiteStorage) RemoveChatRole(ctx context.Context, platform string, chatID, userID int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM chat_roles WHERE platform = ? AND chat_id = ? AND user_id = ?
+	`, platform, chatID, userID)
+	if err != nil {
+		return fmt.Errorf("移除群成员角色失败: %w", err)
+	}
+	return nil
+}
+
+// GetChatRoles 获取群内所有已分配的角色（按权限从高到低）
+func (s *SQLiteStorage) GetChatRoles(ctx context.Context, platform string, chatID int64) ([]*ChatRole, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT platform, chat_id, user_id, role, granted_by, created_at, updated_at
+		FROM chat_roles WHERE platform = ? AND chat_id = ?
+		ORDER BY CASE role WHEN 'owner' THEN 0 WHEN 'editor' THEN 1 ELSE 2 END, created_at
+	`, platform, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("查询群成员角色失败: %w", err)
+	}
+	defer rows.Close()
+
+	var roles []*ChatRole
+	for rows.Next() {
+		r := &ChatRole{}
+		if err := rows.Scan(&r.Platform, &r.ChatID, &r.UserID, &r.Role, &r.GrantedBy, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("扫描群成员角色失败: %w", err)
+		}
+		roles = append(roles, r)
+	}
+
+	return roles, rows.Err()
+}
+
+// ===== 跨平台身份关联 =====
+
+// LinkChat 将会话加入目标会话所在的身份
+func (s *SQLiteStorage) LinkChat(ctx context.Context, platform string, chatID int64, targetPlatform string, targetChatID int64) error {
+	if platform == targetPlatform && chatID == targetChatID {
+		return fmt.Errorf("不能关联会话自身")
+	}
+	now := time.Now().Unix()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	var linkID string
+	err = tx.QueryRowContext(ctx,
+		`SELECT link_id FROM chat_links WHERE platform = ? AND chat_id = ?`,
+		targetPlatform, targetChatID,
+	).Scan(&linkID)
+	if err == sql.ErrNoRows {
+		if linkID, err = newLinkID(); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO chat_links (platform, chat_id, link_id, priority, created_at) VALUES (?, ?, ?, 0, ?)
+		`, targetPlatform, targetChatID, linkID, now); err != nil {
+			return fmt.Errorf("创建身份关联失败: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("查询身份关联失败: %w", err)
+	}
+
+	if _, err := sqliteLeaveLink(ctx, tx, platform, chatID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO chat_links (platform, chat_id, link_id, priority, created_at)
+		VALUES (?, ?, ?, (SELECT COALESCE(MAX(priority), 0) + 1 FROM chat_links WHERE link_id = ?), ?)
+	`, platform, chatID, linkID, linkID, now); err != nil {
+		return fmt.Errorf("创建身份关联失败: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+	return nil
+}
+
+// UnlinkChat 将会话移出所在身份
+func (s *SQLiteStorage) UnlinkChat(ctx context.Context, platform string, chatID int64) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	linked, err := sqliteLeaveLink(ctx, tx, platform, chatID)
+	if err != nil || !linked {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("提交事务失败: %w", err)
+	}
+	return true, nil
+}
+
+// sqliteLeaveLink 在事务内将会话移出所在身份（身份只剩一个会话时一并解散），返回是否存在关联
+func sqliteLeaveLink(ctx context.Context, tx *sql.Tx, platform string, chatID int64) (bool, error) {
+	var linkID string
+	err := tx.QueryRowContext(ctx,
+		`SELECT link_id FROM chat_links WHERE platform = ? AND chat_id = ?`, platform, chatID,
+	).Scan(&linkID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("查询身份关联失败: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM chat_links WHERE platform = ? AND chat_id = ?`, platform, chatID,
+	); err != nil {
+		return false, fmt.Errorf("移除身份关联失败: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM chat_links WHERE link_id = ? AND (SELECT COUNT(*) FROM chat_links WHERE link_id = ?) < 2
+	`, linkID, linkID); err != nil {
+		return false, fmt.Errorf("解散身份关联失败: %w", err)
+	}
+	return true, nil
+}
+
+// GetLinkedChats 获取会话所在身份的全部会话（按投递顺序）
+func (s *SQLiteStorage) GetLinkedChats(ctx context.Context, platform string, chatID int64) ([]*ChatLink, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT l.link_id, l.platform, l.chat_id, l.priority, l.created_at
+		FROM chat_links l
+		JOIN chat_links self ON self.link_id = l.link_id
+		WHERE self.platform = ? AND self.chat_id = ?
+		ORDER BY l.priority, l.created_at
+	`, platform, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("查询身份关联失败: %w", err)
+	}
+	defer rows.Close()
+
+	var links []*ChatLink
+	for rows.Next() {
+		l := &ChatLink{}
+		if err := rows.Scan(&l.LinkID, &l.Platform, &l.ChatID, &l.Priority, &l.CreatedAt); err != nil {
+			return nil, fmt.Errorf("扫描身份关联失败: %w", err)
+		}
+		links = append(links, l)
+	}
+
+	return links, rows.Err()
+}
+
+// SetPreferredChat 将会话设为所在身份的首选投递会话
+func (s *SQLiteStorage) SetPreferredChat(ctx context.Context, platform string, chatID int64) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE chat_links
+		SET priority = (SELECT MIN(priority) - 1 FROM chat_links l WHERE l.link_id = chat_links.link_id)
+		WHERE platform = ? AND chat_id = ?
+	`, platform, chatID)
+	if err != nil {
+		return false, fmt.Errorf("设置首选会话失败: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// ===== 公告广播 =====
+
+// UpdateChatAnnouncements 设置会话是否退订公告广播
+func (s *SQLiteStorage) UpdateChatAnnouncements(ctx context.Context, platform string, chatID int64, off bool) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE chats SET announcements_off = ?, updated_at = ? WHERE platform = ? AND chat_id = ?
+	`, off, time.Now().Unix(), platform, chatID)
+	if err != nil {
+		return fmt.Errorf("更新公告设置失败: %w", err)
+	}
+	return nil
+}
+
+// GetBroadcastTargets 获取公告广播的目标会话
+func (s *SQLiteStorage) GetBroadcastTargets(ctx context.Context, provider string) ([]*ChatRef, int, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT c.platform, c.chat_id, c.language, c.announcements_off,
+		       COALESCE(l.link_id, ''), COALESCE(l.priority, 0)
+		FROM chats c
+		LEFT JOIN chat_links l ON c.platform = l.platform AND c.chat_id = l.chat_id
+		WHERE c.status = 'active'
+		  AND c.platform IN (?, ?)
+		  AND (? = '' OR EXISTS (
+		      SELECT 1 FROM subscriptions s
+		      WHERE s.platform = c.platform AND s.chat_id = c.chat_id AND LOWER(s.provider) = LOWER(?)
+		  ))
+		ORDER BY c.platform, c.chat_id
+	`, PlatformTelegram, PlatformQQ, provider, provider)
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询公告目标失败: %w", err)
+	}
+	defer rows.Close()
+
+	var refs []*ChatRef
+	optedOut := 0
+	for rows.Next() {
+		ref := &ChatRef{}
+		var off bool
+		if err := rows.Scan(&ref.Platform, &ref.ChatID, &ref.Language, &off, &ref.LinkID, &ref.LinkPriority); err != nil {
+			return nil, 0, fmt.Errorf("扫描公告目标失败: %w", err)
+		}
+		if off {
+			optedOut++
+			continue
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, optedOut, rows.Err()
+}
+
+// CreateBroadcast 创建公告广播记录
+func (s *SQLiteStorage) CreateBroadcast(ctx context.Context, b *Broadcast) error {
+	if b.CreatedAt == 0 {
+		b.CreatedAt = time.Now().Unix()
+	}
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO broadcasts (actor, provider, message, status, total, sent, failed, opted_out, created_at, finished_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, b.Actor, b.Provider, b.Message, b.Status, b.Total, b.Sent, b.Failed, b.OptedOut, b.CreatedAt, b.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("创建公告广播失败: %w", err)
+	}
+	b.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// UpdateBroadcast 更新公告广播的状态与投递统计
+func (s *SQLiteStorage) UpdateBroadcast(ctx context.Context, b *Broadcast) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE broadcasts SET status = ?, sent = ?, failed = ?, finished_at = ? WHERE id = ?
+	`, b.Status, b.Sent, b.Failed, b.FinishedAt, b.ID)
+	if err != nil {
+		return fmt.Errorf("更新公告广播失败: %w", err)
+	}
+	return nil
+}
+
+// GetBroadcast 获取公告广播
+func (s *SQLiteStorage) GetBroadcast(ctx context.Context, id int64) (*Broadcast, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+broadcastColumns+` FROM broadcasts WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("查询公告广播失败: %w", err)
+	}
+	defer rows.Close()
+
+	list, err := scanSQLiteBroadcasts(rows)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+// GetBroadcasts 获取最近的公告广播
+func (s *SQLiteStorage) GetBroadcasts(ctx context.Context, limit int) ([]*Broadcast, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+broadcastColumns+` FROM broadcasts ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("查询公告广播失败: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSQLiteBroadcasts(rows)
+}
+
+// scanSQLiteBroadcasts 扫描公告广播记录
+func scanSQLiteBroadcasts(rows *sql.Rows) ([]*Broadcast, error) {
+	var list []*Broadcast
+	for rows.Next() {
+		b := &Broadcast{}
+		if err := rows.Scan(&b.ID, &b.Actor, &b.Provider, &b.Message, &b.Status,
+			&b.Total, &b.Sent, &b.Failed, &b.OptedOut, &b.CreatedAt, &b.FinishedAt); err != nil {
+			return nil, fmt.Errorf("扫描公告广播失败: %w", err)
+		}
+		list = append(list, b)
+	}
+	return list, rows.Err()
+}
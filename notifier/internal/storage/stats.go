@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 会话统计（/stats）的时间窗口
+const (
+	StatsRecentWindow = 24 * time.Hour
+	StatsWindow       = 7 * 24 * time.Hour
+	StatsTopMonitors  = 5 // 展示的最频繁监测项数量
+)
+
+// ChatStats 会话通知统计
+type ChatStats struct {
+	Last24h     int                     // 近 24 小时成功投递数
+	Last7d      int                     // 近 7 天成功投递数
+	TopMonitors []*MonitorDeliveryCount // 近 7 天通知最多的监测项（按 Total 降序）
+
+	Incidents           int           // 近 7 天已恢复的故障数（订阅范围内）
+	AvgIncidentDuration time.Duration // 已恢复故障的平均持续时长
+	Ongoing             int           // 尚未恢复的故障数
+}
+
+// Covers 判断订阅是否覆盖该监测项（规则与 GetSubscribersByMonitor 一致）
+func (sub *Subscription) Covers(provider, service, channel string) bool {
+	if !strings.EqualFold(sub.Provider, provider) {
+		return false
+	}
+	if sub.Service != "" && !strings.EqualFold(sub.Service, service) {
+		return false
+	}
+	if sub.Channel != "" && !strings.EqualFold(sub.Channel, channel) {
+		return false
+	}
+	return true
+}
+
+// Name 监测项名称（provider / service [/ channel]）
+func (c *MonitorDeliveryCount) Name() string {
+	name := c.Provider + " / " + c.Service
+	if c.Channel != "" {
+		name += " / " + c.Channel
+	}
+	return name
+}
+
+// FormatStatsDuration 格式化故障持续时长（如 45m、2h 5m、1d 3h），不足 1 分钟显示 <1m
+func FormatStatsDuration(d time.Duration) string {
+	minutes := int64(d / time.Minute)
+	switch {
+	case minutes < 1:
+		return "<1m"
+	case minutes < 60:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes < 24*60:
+		return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+	default:
+		return fmt.Sprintf("%dd %dh", minutes/(24*60), minutes%(24*60)/60)
+	}
+}
+
+// ComputeChatStats 统计会话近 7 天的通知数与订阅范围内的故障时长
+// 投递数来自 deliveries 关联事件缓存；故障时长按同一监测项的 DOWN 与其后第一个 UP 配对计算
+func ComputeChatStats(ctx context.Context, st Storage, platform string, chatID int64, now time.Time) (*ChatStats, error) {
+	since := now.Add(-StatsWindow).Unix()
+	recentSince := now.Add(-StatsRecentWindow).Unix()
+
+	counts, err := st.GetDeliveryCounts(ctx, platform, chatID, since, recentSince)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ChatStats{}
+	for _, c := range counts {
+		stats.Last24h += c.Recent
+		stats.Last7d += c.Total
+		// 事件缓存缺失（早于缓存上线或已清理）的投递只计入总数
+		if c.Provider != "" {
+			stats.TopMonitors = append(stats.TopMonitors, c)
+		}
+	}
+	sort.SliceStable(stats.TopMonitors, func(i, j int) bool {
+		return stats.TopMonitors[i].Total > stats.TopMonitors[j].Total
+	})
+	if len(stats.TopMonitors) > StatsTopMonitors {
+		stats.TopMonitors = stats.TopMonitors[:StatsTopMonitors]
+	}
+
+	subs, err := st.GetSubscriptionsByChatID(ctx, platform, chatID)
+	if err != nil {
+		return nil, err
+	}
+	if len(subs) == 0 {
+		return stats, nil
+	}
+
+	events, err := st.GetCachedEvents(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	// 按监测项记录未恢复故障的开始时间
+	downSince := make(map[string]int64)
+	var total time.Duration
+	for _, e := range events {
+		if !coveredByAny(subs, e.Provider, e.Service, e.Channel) {
+			continue
+		}
+		key := strings.ToLower(e.Provider + "/" + e.Service + "/" + e.Channel)
+		switch e.Type {
+		case "DOWN":
+			if _, ok := downSince[key]; !ok {
+				downSince[key] = e.ObservedAt
+			}
+		case "UP":
+			start, ok := downSince[key]
+			if !ok {
+				continue
+			}
+			delete(downSince, key)
+			stats.Incidents++
+			total += time.Duration(e.ObservedAt-start) * time.Second
+		}
+	}
+	stats.Ongoing = len(downSince)
+	if stats.Incidents > 0 {
+		stats.AvgIncidentDuration = total / time.Duration(stats.Incidents)
+	}
+
+	return stats, nil
+}
+
+// coveredByAny 判断任一订阅是否覆盖该监测项
+func coveredByAny(subs []*Subscription, provider, service, channel string) bool {
+	for _, sub := range subs {
+		if sub.Covers(provider, service, channel) {
+			return true
+		}
+	}
+	return false
+}
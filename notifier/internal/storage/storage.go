@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"fmt"
 	"time"
 )
 
@@ -9,12 +10,21 @@ import (
 const (
 	PlatformTelegram = "telegram"
 	PlatformQQ       = "qq"
+	PlatformWeCom    = "wecom"    // 企业微信群机器人（仅推送，订阅来自配置）
+	PlatformDingTalk = "dingtalk" // 钉钉自定义机器人（仅推送，订阅来自配置）
 )
 
 // ChatRef 投递目标引用（平台 + ChatID）
 type ChatRef struct {
-	Platform string
-	ChatID   int64
+	Platform  string
+	ChatID    int64
+	EventMask uint32 // 接收的事件类型（多条匹配订阅取并集，0 表示全部）
+	Language  string // 语言偏好（空表示未设置）
+
+	LinkID       string // 跨平台身份标识（未关联为空）
+	LinkPriority int    // 身份内的投递顺序（越小越优先）
+
+	PausedUntil int64 // 通知暂停截止时间（0 表示未暂停，PausedIndefinitely 表示直到 /resume）
 }
 
 // Storage 存储接口
@@ -33,6 +43,10 @@ type Storage interface {
 	// UpdateCursor 更新轮询游标
 	UpdateCursor(ctx context.Context, lastEventID int64) error
 
+	// ResetCursor 事件 ID 回退（主服务换库）时将游标回拨到 lastEventID，
+	// 同时删除 event_id 大于 lastEventID 的投递记录与事件缓存（属于旧事件 ID 空间，会与新事件按 event_id 去重冲突），返回删除的投递数
+	ResetCursor(ctx context.Context, lastEventID int64) (int64, error)
+
 	// ===== Chat 管理（多平台） =====
 
 	// UpsertChat 创建或更新 Chat
@@ -47,11 +61,20 @@ type Storage interface {
 	// UpdateChatCommandTime 更新用户命令时间（防滥用）
 	UpdateChatCommandTime(ctx context.Context, platform string, chatID int64) error
 
+	// UpdateChatLanguage 更新 Chat 语言偏好（空字符串表示恢复默认）
+	UpdateChatLanguage(ctx context.Context, platform string, chatID int64, language string) error
+
+	// UpdateChatPause 设置通知暂停截止时间（0 表示恢复通知，PausedIndefinitely 表示无限期暂停）
+	UpdateChatPause(ctx context.Context, platform string, chatID int64, pausedUntil int64) error
+
 	// ===== 订阅管理 =====
 
 	// AddSubscription 添加订阅
 	AddSubscription(ctx context.Context, sub *Subscription) error
 
+	// SetSubscriptionEventMask 设置订阅的事件类型过滤（匹配规则与 RemoveSubscription 一致），返回更新的订阅数
+	SetSubscriptionEventMask(ctx context.Context, platform string, chatID int64, provider, service, channel string, mask uint32) (int64, error)
+
 	// RemoveSubscription 移除订阅
 	RemoveSubscription(ctx context.Context, platform string, chatID int64, provider, service, channel string) error
 
@@ -89,14 +112,142 @@ type Storage interface {
 	// UpdateDeliveryStatus 更新投递状态
 	UpdateDeliveryStatus(ctx context.Context, id int64, status string, messageID string, errorMsg string) error
 
-	// GetPendingDeliveries 获取待发送的投递记录
+	// GetPendingDeliveries 获取已到重试时间的待发送投递记录
 	GetPendingDeliveries(ctx context.Context, limit int) ([]*Delivery, error)
 
-	// IncrementRetryCount 增加重试次数
-	IncrementRetryCount(ctx context.Context, id int64) error
+	// ScheduleDeliveryRetry 记录一次发送失败：重试次数加一，并在 nextAttemptAt 之前不再重试
+	ScheduleDeliveryRetry(ctx context.Context, id int64, nextAttemptAt int64, errorMsg string) error
+
+	// GetDeadDeliveries 查询死信投递（按 id 倒序）
+	GetDeadDeliveries(ctx context.Context, filter DeliveryFilter) ([]*Delivery, error)
+
+	// RequeueDeadDelivery 将死信投递重新放回待发送队列（重置重试次数），返回该死信是否存在
+	RequeueDeadDelivery(ctx context.Context, id int64) (bool, error)
 
 	// CleanupOldDeliveries 清理旧的投递记录
 	CleanupOldDeliveries(ctx context.Context, before time.Time) (int64, error)
+
+	// ===== 审计日志 =====
+
+	// AddAuditEntry 写入审计日志
+	AddAuditEntry(ctx context.Context, entry *AuditEntry) error
+
+	// GetAuditEntries 查询审计日志（按 id 倒序）
+	GetAuditEntries(ctx context.Context, filter AuditFilter) ([]*AuditEntry, error)
+
+	// CleanupOldAuditEntries 清理旧的审计日志
+	CleanupOldAuditEntries(ctx context.Context, before time.Time) (int64, error)
+
+	// ===== 定期摘要 =====
+
+	// UpdateChatDigest 设置 Chat 的摘要频率与发送时刻（frequency 为空表示关闭）
+	// 同时将上次发送时间置为当前时间，避免新设置立即补发已过去的时段
+	UpdateChatDigest(ctx context.Context, platform string, chatID int64, frequency string, hour int) error
+
+	// GetDigestChats 获取开启了摘要的活跃 Chat
+	GetDigestChats(ctx context.Context) ([]*Chat, error)
+
+	// ClaimDigest 抢占一次摘要发送（上次发送时间仍为 prevSentAt 时更新为 sentAt）
+	// 返回 false 表示已被其它实例或此前的调度发送
+	ClaimDigest(ctx context.Context, platform string, chatID int64, prevSentAt, sentAt int64) (bool, error)
+
+	// AddIncident 记录故障（DOWN 事件，按 event_id 幂等）
+	AddIncident(ctx context.Context, incident *Incident) error
+
+	// GetIncidentsSince 获取指定时间之后发生的故障
+	GetIncidentsSince(ctx context.Context, since int64) ([]*Incident, error)
+
+	// CleanupOldIncidents 清理旧的故障记录
+	CleanupOldIncidents(ctx context.Context, before time.Time) (int64, error)
+
+	// ===== 事件缓存（/stats 统计） =====
+
+	// CacheEvents 缓存轮询到的事件元数据（按事件 ID 幂等）
+	CacheEvents(ctx context.Context, events []*CachedEvent) error
+
+	// GetCachedEvents 获取指定时间之后发生的事件（按发生时间、ID 升序）
+	GetCachedEvents(ctx context.Context, since int64) ([]*CachedEvent, error)
+
+	// CleanupOldEvents 清理旧的事件缓存
+	CleanupOldEvents(ctx context.Context, before time.Time) (int64, error)
+
+	// GetDeliveryCounts 统计会话自 since 起成功投递的通知数（按监测项分组，事件未缓存的计入空监测项），
+	// Recent 为其中 recentSince 之后的数量
+	GetDeliveryCounts(ctx context.Context, platform string, chatID int64, since, recentSince int64) ([]*MonitorDeliveryCount, error)
+
+	// ===== 群组角色 =====
+
+	// GetChatRole 获取群成员角色（未分配时返回空字符串）
+	GetChatRole(ctx context.Context, platform string, chatID, userID int64) (string, error)
+
+	// SetChatRole 设置群成员角色（已存在时覆盖）
+	SetChatRole(ctx context.Context, role *ChatRole) error
+
+	// RemoveChatRole 移除群成员角色
+	RemoveChatRole(ctx context.Context, platform string, chatID, userID int64) error
+
+	// GetChatRoles 获取群内所有已分配的角色
+	GetChatRoles(ctx context.Context, platform string, chatID int64) ([]*ChatRole, error)
+
+	// ===== 跨平台身份关联 =====
+
+	// LinkChat 将会话加入目标会话所在的身份（目标尚未关联时新建），当前会话排在回退顺序末尾
+	// 当前会话已属于其它身份时先退出原身份
+	LinkChat(ctx context.Context, platform string, chatID int64, targetPlatform string, targetChatID int64) error
+
+	// UnlinkChat 将会话移出所在身份，返回是否存在关联（身份只剩一个会话时一并解散）
+	UnlinkChat(ctx context.Context, platform string, chatID int64) (bool, error)
+
+	// GetLinkedChats 获取会话所在身份的全部会话（按投递顺序），未关联时返回空
+	GetLinkedChats(ctx context.Context, platform string, chatID int64) ([]*ChatLink, error)
+
+	// SetPreferredChat 将会话设为所在身份的首选投递会话，返回是否存在关联
+	SetPreferredChat(ctx context.Context, platform string, chatID int64) (bool, error)
+
+	// ===== 公告广播 =====
+
+	// UpdateChatAnnouncements 设置会话是否退订公告广播
+	UpdateChatAnnouncements(ctx context.Context, platform string, chatID int64, off bool) error
+
+	// GetBroadcastTargets 获取公告广播的目标会话：活跃的 Telegram / QQ 会话，provider 非空时仅限订阅了该服务商的会话
+	// 已退订公告的会话不在结果中，其数量由第二个返回值给出
+	GetBroadcastTargets(ctx context.Context, provider string) ([]*ChatRef, int, error)
+
+	// CreateBroadcast 创建公告广播记录
+	CreateBroadcast(ctx context.Context, b *Broadcast) error
+
+	// UpdateBroadcast 更新公告广播的状态与投递统计
+	UpdateBroadcast(ctx context.Context, b *Broadcast) error
+
+	// GetBroadcast 获取公告广播（不存在时返回 nil）
+	GetBroadcast(ctx context.Context, id int64) (*Broadcast, error)
+
+	// GetBroadcasts 获取最近的公告广播（按 id 倒序）
+	GetBroadcasts(ctx context.Context, limit int) ([]*Broadcast, error)
+}
+
+// LeaderElector 多实例部署时的 Poller 选主（可选能力，由共享存储实现）
+//
+// 领导权以 poll_cursor 行上的租约表示：leader 在租约内持续续约，
+// 租约过期后其它实例可接管。未实现该接口的存储（SQLite）视为单实例，始终为 leader。
+type LeaderElector interface {
+	// TryAcquireLeadership 获取或续约领导权，返回当前实例是否为 leader
+	TryAcquireLeadership(ctx context.Context, instanceID string, lease time.Duration) (bool, error)
+
+	// ReleaseLeadership 主动释放领导权（优雅关闭时调用，仅在持有时生效）
+	ReleaseLeadership(ctx context.Context, instanceID string) error
+}
+
+// New 按驱动创建存储（sqlite / postgres）
+func New(driver, dsn string) (Storage, error) {
+	switch driver {
+	case "", "sqlite":
+		return NewSQLiteStorage(dsn)
+	case "postgres":
+		return NewPostgresStorage(dsn)
+	default:
+		return nil, fmt.Errorf("不支持的数据库驱动: %s", driver)
+	}
 }
 
 // Chat 多平台用户/群
@@ -108,8 +259,17 @@ type Chat struct {
 	Status        string // active/blocked
 	LastCommandAt int64
 	CommandCount  int
+	Language      string // 语言偏好（zh/en/ja/ru，空表示未设置）
 	CreatedAt     int64
 	UpdatedAt     int64
+
+	DigestFrequency string // 摘要频率（daily/weekly，空表示关闭）
+	DigestHour      int    // 摘要发送时刻（UTC+8 小时，0-23）
+	DigestSentAt    int64  // 上次发送摘要的时间
+
+	AnnouncementsOff bool // 是否退订公告广播（/announcements off）
+
+	PausedUntil int64 // 通知暂停截止时间（/pause，0 表示未暂停，PausedIndefinitely 表示直到 /resume）
 }
 
 // Subscription 订阅关系
@@ -120,6 +280,8 @@ type Subscription struct {
 	Provider  string
 	Service   string
 	Channel   string
+	EventMask uint32 // 接收的事件类型位掩码，0 表示全部
+	CreatedBy int64  // 创建订阅的用户（群聊问责，0 表示未知：配置同步或旧数据）
 	CreatedAt int64
 }
 
@@ -127,6 +289,9 @@ type Subscription struct {
 type BindToken struct {
 	Token     string
 	Favorites string // JSON 格式的收藏列表
+	Platform  string // 会话令牌所属平台（为空表示网页收藏绑定 token）
+	ChatID    int64  // 会话令牌所属会话，用于订阅导入导出 API 鉴权
+	Purpose   string // 令牌用途（空表示网页收藏绑定 / 会话令牌，link 表示跨平台身份关联）
 	ExpiresAt int64
 	UsedAt    int64 // 0 表示未使用
 	CreatedAt int64
@@ -134,23 +299,90 @@ type BindToken struct {
 
 // Delivery 投递记录
 type Delivery struct {
-	ID           int64
-	EventID      int64
-	Platform     string
-	ChatID       int64
-	Status       string // pending/sent/failed
-	MessageID    string
-	ErrorMessage string
-	RetryCount   int
-	CreatedAt    int64
-	UpdatedAt    int64
+	ID            int64
+	EventID       int64
+	Platform      string
+	ChatID        int64
+	Status        string // pending/sent/failed/dead
+	MessageID     string
+	ErrorMessage  string
+	RetryCount    int
+	NextAttemptAt int64 // 下次重试时间（0 表示立即）
+	CreatedAt     int64
+	UpdatedAt     int64
 }
 
+// AuditEntry 审计日志（管理命令、白名单越权等）
+type AuditEntry struct {
+	ID        int64
+	Timestamp int64
+	Platform  string
+	Actor     string // 操作者，如 qq:123456
+	Action    string // 操作类型，如 qq.command.add
+	Target    string // 操作对象，如 group:123456
+	Detail    string // 附加信息（命令参数、授权方式等）
+	Result    string // success/failure/denied
+}
+
+// Incident 故障记录（聚合后的 DOWN 事件，用于摘要统计）
+type Incident struct {
+	ID         int64
+	EventID    int64
+	Provider   string
+	Service    string
+	Channel    string
+	ObservedAt int64
+}
+
+// CachedEvent 轮询器缓存的事件元数据（用于 /stats 关联投递记录与监测项）
+type CachedEvent struct {
+	ID         int64
+	Provider   string
+	Service    string
+	Channel    string
+	Model      string
+	Type       string
+	ObservedAt int64
+}
+
+// MonitorDeliveryCount 单个监测项的成功投递数（事件缓存缺失时监测项为空）
+type MonitorDeliveryCount struct {
+	Provider string
+	Service  string
+	Channel  string
+	Recent   int // recentSince 之后的投递数
+	Total    int // since 之后的投递数
+}
+
+// AuditFilter 审计日志查询条件（零值表示不过滤）
+type AuditFilter struct {
+	Actor    string
+	Action   string
+	Since    int64
+	BeforeID int64 // 游标分页：仅返回 id < BeforeID 的记录
+	Limit    int
+}
+
+// DeliveryFilter 死信投递查询条件（零值表示不过滤）
+type DeliveryFilter struct {
+	Platform string
+	BeforeID int64 // 游标分页：仅返回 id < BeforeID 的记录
+	Limit    int
+}
+
+// AuditResult 审计结果常量
+const (
+	AuditResultSuccess = "success"
+	AuditResultFailure = "failure"
+	AuditResultDenied  = "denied"
+)
+
 // DeliveryStatus 投递状态常量
 const (
 	DeliveryStatusPending = "pending"
 	DeliveryStatusSent    = "sent"
-	DeliveryStatusFailed  = "failed"
+	DeliveryStatusFailed  = "failed" // 不可恢复的错误（会话已停用）
+	DeliveryStatusDead    = "dead"   // 重试耗尽，进入死信队列等待人工重新入队
 )
 
 // ChatStatus 用户状态常量
@@ -7,11 +7,14 @@ import (
 	"fmt"
 	"html"
 	"log/slog"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"notifier/internal/config"
+	"notifier/internal/i18n"
 	"notifier/internal/screenshot"
 	"notifier/internal/storage"
 	"notifier/internal/validator"
@@ -24,7 +27,9 @@ type Bot struct {
 	storage           storage.Storage
 	screenshotService *screenshot.Service
 	validator         *validator.RelayPulseValidator
+	broadcaster       Broadcaster
 	handlers          map[string]CommandHandler
+	username          string // Bot 用户名（内联查询提示使用）
 
 	mu       sync.Mutex
 	running  bool
@@ -34,6 +39,11 @@ type Bot struct {
 // CommandHandler 命令处理函数
 type CommandHandler func(ctx context.Context, msg *Message, args string) error
 
+// Broadcaster 公告广播发起者（由通知发送器实现）
+type Broadcaster interface {
+	StartBroadcast(ctx context.Context, b *storage.Broadcast, onDone func(*storage.Broadcast)) error
+}
+
 // NewBot 创建 Bot
 func NewBot(cfg *config.Config, store storage.Storage) *Bot {
 	client := NewClient(cfg.Telegram.BotToken)
@@ -63,10 +73,22 @@ func NewBot(cfg *config.Config, store storage.Storage) *Bot {
 	b.handlers["list"] = b.handleList
 	b.handlers["add"] = b.handleAdd
 	b.handlers["remove"] = b.handleRemove
+	b.handlers["filter"] = b.handleFilter
 	b.handlers["clear"] = b.handleClear
 	b.handlers["status"] = b.handleStatus
 	b.handlers["help"] = b.handleHelp
 	b.handlers["snap"] = b.handleSnap
+	b.handlers["lang"] = b.handleLang
+	b.handlers["digest"] = b.handleDigest
+	b.handlers["export"] = b.handleExport
+	b.handlers["import"] = b.handleImport
+	b.handlers["role"] = b.handleRole
+	b.handlers["link"] = b.handleLink
+	b.handlers["pause"] = b.handlePause
+	b.handlers["resume"] = b.handleResume
+	b.handlers["stats"] = b.handleStats
+	b.handlers["announcements"] = b.handleAnnouncements
+	b.handlers["broadcast"] = b.handleBroadcast
 
 	return b
 }
@@ -76,6 +98,11 @@ func (b *Bot) SetScreenshotService(svc *screenshot.Service) {
 	b.screenshotService = svc
 }
 
+// SetBroadcaster 设置公告广播发起者（未设置时 /broadcast 不可用）
+func (b *Bot) SetBroadcaster(br Broadcaster) {
+	b.broadcaster = br
+}
+
 // Start 启动 Bot（Long Polling）
 func (b *Bot) Start(ctx context.Context) error {
 	b.mu.Lock()
@@ -93,6 +120,7 @@ func (b *Bot) Start(ctx context.Context) error {
 		return fmt.Errorf("验证 Bot Token 失败: %w", err)
 	}
 	slog.Info("Telegram Bot 启动", "username", me.Username, "id", me.ID)
+	b.username = me.Username
 
 	var offset int64 = 0
 	pollTimeout := 30 // Long Polling 超时秒数
@@ -124,6 +152,9 @@ func (b *Bot) Start(ctx context.Context) error {
 			if update.Message != nil {
 				go b.handleMessage(ctx, update.Message)
 			}
+			if update.InlineQuery != nil {
+				go b.handleInlineQuery(ctx, update.InlineQuery)
+			}
 		}
 	}
 }
@@ -159,9 +190,12 @@ func (b *Bot) handleMessage(ctx context.Context, msg *Message) {
 		args = strings.TrimSpace(parts[1])
 	}
 
+	// 确定回复语言
+	ctx = i18n.WithLang(ctx, b.chatLang(ctx, msg))
+
 	handler, ok := b.handlers[cmdPart]
 	if !ok {
-		b.sendReply(ctx, msg.Chat.ID, "未知命令。发送 /help 查看帮助。")
+		b.reply(ctx, msg.Chat.ID, "cmd.unknown")
 		return
 	}
 
@@ -175,13 +209,88 @@ func (b *Bot) handleMessage(ctx context.Context, msg *Message) {
 		slog.Warn("更新命令时间失败", "error", err)
 	}
 
+	// 群聊按角色校验权限（私聊不受限）
+	if isGroupChat(msg.Chat) {
+		if required := storage.RequiredRole(cmdPart, args); required != storage.RoleViewer {
+			role, err := b.memberRole(ctx, msg, required)
+			if err != nil {
+				slog.Warn("群成员角色校验失败", "chat_id", msg.Chat.ID, "error", err)
+				b.reply(ctx, msg.Chat.ID, "cmd.permission_check_failed")
+				return
+			}
+			if !storage.RoleAtLeast(role, required) {
+				b.reply(ctx, msg.Chat.ID, "cmd.permission_denied", required)
+				return
+			}
+		}
+	}
+
 	// 执行命令
 	if err := handler(ctx, msg, args); err != nil {
 		slog.Error("命令执行失败", "command", cmdPart, "chat_id", msg.Chat.ID, "error", err)
-		b.sendReply(ctx, msg.Chat.ID, "命令执行出错，请稍后重试。")
+		b.reply(ctx, msg.Chat.ID, "cmd.error")
 	}
 }
 
+// memberRole 群成员的有效角色：已分配角色与群管理员身份（群主/管理员视为 owner）取较高者
+// 仅在已分配角色不满足 required 时查询 Telegram 成员信息
+func (b *Bot) memberRole(ctx context.Context, msg *Message, required string) (string, error) {
+	// 匿名管理员以群组身份发言
+	if msg.SenderChat != nil && msg.SenderChat.ID == msg.Chat.ID {
+		return storage.RoleOwner, nil
+	}
+	if msg.From == nil {
+		return storage.RoleViewer, nil
+	}
+
+	role, err := b.storage.GetChatRole(ctx, storage.PlatformTelegram, msg.Chat.ID, msg.From.ID)
+	if err != nil {
+		return "", err
+	}
+	role = storage.HigherRole(role, storage.RoleViewer)
+	if storage.RoleAtLeast(role, required) {
+		return role, nil
+	}
+
+	member, err := b.client.GetChatMember(ctx, msg.Chat.ID, msg.From.ID)
+	if err != nil {
+		return "", err
+	}
+	if member.Status == "creator" || member.Status == "administrator" {
+		return storage.RoleOwner, nil
+	}
+	return role, nil
+}
+
+// isGroupChat 是否为群组（角色仅在群组中生效）
+func isGroupChat(chat *Chat) bool {
+	return chat != nil && (chat.Type == "group" || chat.Type == "supergroup")
+}
+
+// senderID 消息发送者 ID（匿名管理员或频道消息为 0）
+func senderID(msg *Message) int64 {
+	if msg.From == nil {
+		return 0
+	}
+	return msg.From.ID
+}
+
+// chatLang 会话语言：/lang 偏好 > 客户端语言 > 默认语言
+func (b *Bot) chatLang(ctx context.Context, msg *Message) i18n.Lang {
+	preference := ""
+	if chat, err := b.storage.GetChat(ctx, storage.PlatformTelegram, msg.Chat.ID); err != nil {
+		slog.Warn("查询语言偏好失败", "chat_id", msg.Chat.ID, "error", err)
+	} else if chat != nil {
+		preference = chat.Language
+	}
+
+	clientCode := ""
+	if msg.From != nil {
+		clientCode = msg.From.LanguageCode
+	}
+	return i18n.Resolve(preference, clientCode)
+}
+
 // ensureUser 确保用户存在
 func (b *Bot) ensureUser(ctx context.Context, msg *Message) error {
 	chat := &storage.Chat{
@@ -207,27 +316,16 @@ func (b *Bot) sendReply(ctx context.Context, chatID int64, text string) {
 	}
 }
 
+// reply 按会话语言渲染文案并回复
+func (b *Bot) reply(ctx context.Context, chatID int64, key string, args ...any) {
+	b.sendReply(ctx, chatID, i18n.HTML(i18n.FromContext(ctx), key, args...))
+}
+
 // handleStart 处理 /start 命令
 func (b *Bot) handleStart(ctx context.Context, msg *Message, args string) error {
 	if args == "" {
 		// 普通 /start
-		welcome := `欢迎使用 <b>RelayPulse 通知 Bot</b>！
-
-我可以在你收藏的 LLM 中继服务状态变化时发送通知。
-
-<b>命令列表：</b>
-/list - 查看当前订阅
-/add &lt;provider&gt; [service] [channel] - 添加订阅
-/remove &lt;provider&gt; [service] [channel] - 移除订阅
-/clear - 清空所有订阅
-/snap - 截图订阅服务状态
-/status - 查看服务状态
-/help - 显示帮助
-
-<b>快速开始：</b>
-从 RelayPulse 网页点击"订阅通知"按钮，即可一键导入收藏列表。`
-
-		b.sendReply(ctx, msg.Chat.ID, welcome)
+		b.reply(ctx, msg.Chat.ID, "start.welcome")
 		return nil
 	}
 
@@ -238,12 +336,13 @@ func (b *Bot) handleStart(ctx context.Context, msg *Message, args string) error
 	bindToken, err := b.storage.ConsumeBindToken(ctx, token)
 	if err != nil {
 		slog.Warn("消费绑定 token 失败", "error", err)
-		b.sendReply(ctx, msg.Chat.ID, "绑定链接无效或已过期，请重新从网页获取。")
+		b.reply(ctx, msg.Chat.ID, "start.token_invalid")
 		return nil
 	}
 
-	if bindToken == nil {
-		b.sendReply(ctx, msg.Chat.ID, "绑定链接不存在，请重新从网页获取。")
+	// 会话令牌（/export 签发）需通过 /import 使用
+	if bindToken == nil || bindToken.Platform != "" {
+		b.reply(ctx, msg.Chat.ID, "start.token_missing")
 		return nil
 	}
 
@@ -251,13 +350,13 @@ func (b *Bot) handleStart(ctx context.Context, msg *Message, args string) error
 	favorites, err := parseBindTokenFavorites(bindToken.Favorites)
 	if err != nil {
 		slog.Error("解析收藏列表失败", "error", err)
-		b.sendReply(ctx, msg.Chat.ID, "收藏数据格式错误，请联系管理员。")
+		b.reply(ctx, msg.Chat.ID, "start.favorites_invalid")
 		return nil
 	}
 
 	// 为避免导入无效/冷板订阅，要求验证器可用
 	if b.validator == nil {
-		b.sendReply(ctx, msg.Chat.ID, "当前无法验证订阅（验证服务未配置），为避免导入无效或冷板订阅，已拒绝本次导入。请稍后再试。")
+		b.reply(ctx, msg.Chat.ID, "start.validator_unconfigured")
 		return nil
 	}
 
@@ -270,10 +369,7 @@ func (b *Bot) handleStart(ctx context.Context, msg *Message, args string) error
 	maxSubs := b.cfg.Limits.MaxSubscriptionsPerUser
 	availableSlots := maxSubs - currentCount
 	if availableSlots <= 0 {
-		b.sendReply(ctx, msg.Chat.ID, fmt.Sprintf(
-			"订阅数量已达上限（%d/%d）。请先使用 /clear 清空或 /remove 移除部分订阅。",
-			currentCount, maxSubs,
-		))
+		b.reply(ctx, msg.Chat.ID, "start.limit_reached", currentCount, maxSubs)
 		return nil
 	}
 
@@ -299,11 +395,12 @@ func (b *Bot) handleStart(ctx context.Context, msg *Message, args string) error
 		}
 
 		sub := &storage.Subscription{
-			Platform: storage.PlatformTelegram,
-			ChatID:   msg.Chat.ID,
-			Provider: target.Provider,
-			Service:  target.Service,
-			Channel:  target.Channel,
+			Platform:  storage.PlatformTelegram,
+			ChatID:    msg.Chat.ID,
+			Provider:  target.Provider,
+			Service:   target.Service,
+			Channel:   target.Channel,
+			CreatedBy: senderID(msg),
 		}
 
 		if err := b.storage.AddSubscription(ctx, sub); err != nil {
@@ -314,19 +411,17 @@ func (b *Bot) handleStart(ctx context.Context, msg *Message, args string) error
 		added++
 	}
 
-	reply := fmt.Sprintf(
-		"成功导入 <b>%d</b> 个订阅！\n\n发送 /list 查看当前订阅列表。",
-		added,
-	)
+	lang := i18n.FromContext(ctx)
+	reply := i18n.HTML(lang, "start.imported", added)
 
 	if coldRejected > 0 {
-		reply += fmt.Sprintf("\n\n🚫 已跳过 <b>%d</b> 个冷板订阅（board=cold 不支持订阅通知）。", coldRejected)
+		reply += i18n.HTML(lang, "start.cold_skipped", coldRejected)
 	}
 	if failed > 0 {
-		reply += fmt.Sprintf("\n\n⚠️ 有 <b>%d</b> 个订阅导入失败（可能已下线或参数不合法）。", failed)
+		reply += i18n.HTML(lang, "start.failed", failed)
 	}
 	if len(favorites) > added+coldRejected+failed {
-		reply += fmt.Sprintf("\n\n⚠️ 部分订阅因数量限制未能添加（%d/%d）", added, len(favorites))
+		reply += i18n.HTML(lang, "start.partial", added, len(favorites))
 	}
 
 	b.sendReply(ctx, msg.Chat.ID, reply)
@@ -341,12 +436,13 @@ func (b *Bot) handleList(ctx context.Context, msg *Message, args string) error {
 	}
 
 	if len(subs) == 0 {
-		b.sendReply(ctx, msg.Chat.ID, "你还没有订阅任何服务。\n\n使用 /add 添加订阅，或从网页点击「订阅通知」一键导入。")
+		b.reply(ctx, msg.Chat.ID, "list.empty")
 		return nil
 	}
 
+	lang := i18n.FromContext(ctx)
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("<b>当前订阅（%d 个）：</b>\n\n", len(subs)))
+	sb.WriteString(i18n.HTML(lang, "list.header", len(subs)))
 
 	for i, sub := range subs {
 		// 转义 HTML 防止注入
@@ -354,20 +450,30 @@ func (b *Bot) handleList(ctx context.Context, msg *Message, args string) error {
 		service := html.EscapeString(sub.Service)
 		channel := html.EscapeString(sub.Channel)
 
+		// 仅接收部分事件类型时追加标注
+		filter := ""
+		if sub.EventMask != storage.EventMaskAll {
+			filter = i18n.HTML(lang, "list.filter", storage.FormatEventMask(sub.EventMask))
+		}
+		// 群组中标注订阅创建者
+		if isGroupChat(msg.Chat) && sub.CreatedBy != 0 {
+			filter += i18n.HTML(lang, "list.creator", sub.CreatedBy)
+		}
+
 		// 根据订阅级别显示不同格式
 		if sub.Service == "" {
 			// 旧版通配订阅（provider 级）
-			sb.WriteString(fmt.Sprintf("%d. %s / *（旧版）\n", i+1, provider))
+			sb.WriteString(fmt.Sprintf("%d. %s / *%s%s\n", i+1, provider, i18n.HTML(lang, "list.legacy"), filter))
 		} else if channel != "" {
 			// 精确订阅（provider / service / channel）
-			sb.WriteString(fmt.Sprintf("%d. %s / %s / %s\n", i+1, provider, service, channel))
+			sb.WriteString(fmt.Sprintf("%d. %s / %s / %s%s\n", i+1, provider, service, channel, filter))
 		} else {
 			// service 级订阅（provider / service）
-			sb.WriteString(fmt.Sprintf("%d. %s / %s\n", i+1, provider, service))
+			sb.WriteString(fmt.Sprintf("%d. %s / %s%s\n", i+1, provider, service, filter))
 		}
 	}
 
-	sb.WriteString("\n使用 /remove &lt;provider&gt; [service] [channel] 移除订阅")
+	sb.WriteString(i18n.HTML(lang, "list.footer"))
 
 	b.sendReply(ctx, msg.Chat.ID, sb.String())
 	return nil
@@ -378,10 +484,15 @@ func (b *Bot) handleList(ctx context.Context, msg *Message, args string) error {
 // - /add <provider> → 展开订阅该 provider 下所有 service/channel
 // - /add <provider> <service> → 展开订阅该 service 下所有 channel
 // - /add <provider> <service> <channel> → 精确订阅
+// 可附加 --only=<types> 仅接收指定事件类型（如 --only=down,up）
 func (b *Bot) handleAdd(ctx context.Context, msg *Message, args string) error {
-	parts := strings.Fields(args)
+	parts, mask, err := storage.ExtractEventMaskFlag(strings.Fields(args))
+	if err != nil {
+		b.reply(ctx, msg.Chat.ID, "filter.invalid", storage.EventMaskNames)
+		return nil
+	}
 	if len(parts) < 1 {
-		b.sendReply(ctx, msg.Chat.ID, "用法: /add &lt;provider&gt; [service] [channel]\n\n例如:\n/add 88code → 订阅 88code 所有服务\n/add 88code cc → 订阅 88code 的 cc 服务")
+		b.reply(ctx, msg.Chat.ID, "add.usage")
 		return nil
 	}
 
@@ -404,7 +515,7 @@ func (b *Bot) handleAdd(ctx context.Context, msg *Message, args string) error {
 
 	// 验证服务是否配置
 	if b.validator == nil {
-		b.sendReply(ctx, msg.Chat.ID, "当前无法验证订阅（验证服务未配置），为避免订阅无效服务，已拒绝本次订阅。请从网页一键导入收藏。")
+		b.reply(ctx, msg.Chat.ID, "add.validator_unconfigured")
 		return nil
 	}
 
@@ -417,10 +528,7 @@ func (b *Bot) handleAdd(ctx context.Context, msg *Message, args string) error {
 
 		// 检查配额（maxSubs==0 表示无限制）
 		if maxSubs > 0 && count+len(targets) > maxSubs {
-			b.sendReply(ctx, msg.Chat.ID, fmt.Sprintf(
-				"订阅配额不足。<b>%s</b> 有 %d 个订阅项，当前已用 %d/%d。\n\n请先移除部分订阅，或精确指定 service/channel。",
-				html.EscapeString(provider), len(targets), count, maxSubs,
-			))
+			b.reply(ctx, msg.Chat.ID, "add.quota_provider", provider, len(targets), count, maxSubs)
 			return nil
 		}
 
@@ -428,21 +536,21 @@ func (b *Bot) handleAdd(ctx context.Context, msg *Message, args string) error {
 		added := 0
 		for _, t := range targets {
 			sub := &storage.Subscription{
-				Platform: storage.PlatformTelegram,
-				ChatID:   msg.Chat.ID,
-				Provider: t.Provider,
-				Service:  t.Service,
-				Channel:  t.Channel,
+				Platform:  storage.PlatformTelegram,
+				ChatID:    msg.Chat.ID,
+				Provider:  t.Provider,
+				Service:   t.Service,
+				Channel:   t.Channel,
+				EventMask: mask,
+				CreatedBy: senderID(msg),
 			}
 			if err := b.storage.AddSubscription(ctx, sub); err == nil {
 				added++
 			}
 		}
 
-		b.sendReply(ctx, msg.Chat.ID, fmt.Sprintf(
-			"已添加 <b>%d</b> 个订阅（%s 下所有服务通道）",
-			added, html.EscapeString(provider),
-		))
+		lang := i18n.FromContext(ctx)
+		b.sendReply(ctx, msg.Chat.ID, i18n.HTML(lang, "add.added_provider", added, provider)+eventMaskSuffix(lang, mask))
 		return nil
 	}
 
@@ -455,10 +563,7 @@ func (b *Bot) handleAdd(ctx context.Context, msg *Message, args string) error {
 
 		// 检查配额（maxSubs==0 表示无限制）
 		if maxSubs > 0 && count+len(targets) > maxSubs {
-			b.sendReply(ctx, msg.Chat.ID, fmt.Sprintf(
-				"订阅配额不足。<b>%s / %s</b> 有 %d 个订阅项，当前已用 %d/%d。\n\n请先移除部分订阅，或精确指定 channel。",
-				html.EscapeString(provider), html.EscapeString(service), len(targets), count, maxSubs,
-			))
+			b.reply(ctx, msg.Chat.ID, "add.quota_service", provider, service, len(targets), count, maxSubs)
 			return nil
 		}
 
@@ -466,30 +571,27 @@ func (b *Bot) handleAdd(ctx context.Context, msg *Message, args string) error {
 		added := 0
 		for _, t := range targets {
 			sub := &storage.Subscription{
-				Platform: storage.PlatformTelegram,
-				ChatID:   msg.Chat.ID,
-				Provider: t.Provider,
-				Service:  t.Service,
-				Channel:  t.Channel,
+				Platform:  storage.PlatformTelegram,
+				ChatID:    msg.Chat.ID,
+				Provider:  t.Provider,
+				Service:   t.Service,
+				Channel:   t.Channel,
+				EventMask: mask,
+				CreatedBy: senderID(msg),
 			}
 			if err := b.storage.AddSubscription(ctx, sub); err == nil {
 				added++
 			}
 		}
 
-		b.sendReply(ctx, msg.Chat.ID, fmt.Sprintf(
-			"已添加 <b>%d</b> 个订阅（%s / %s 下所有通道）",
-			added, html.EscapeString(provider), html.EscapeString(service),
-		))
+		lang := i18n.FromContext(ctx)
+		b.sendReply(ctx, msg.Chat.ID, i18n.HTML(lang, "add.added_service", added, provider, service)+eventMaskSuffix(lang, mask))
 		return nil
 	}
 
 	// 精确订阅
 	if maxSubs > 0 && count >= maxSubs {
-		b.sendReply(ctx, msg.Chat.ID, fmt.Sprintf(
-			"订阅数量已达上限（%d/%d）。请先移除部分订阅。",
-			count, maxSubs,
-		))
+		b.reply(ctx, msg.Chat.ID, "add.limit_reached", count, maxSubs)
 		return nil
 	}
 
@@ -499,52 +601,45 @@ func (b *Bot) handleAdd(ctx context.Context, msg *Message, args string) error {
 	}
 
 	sub := &storage.Subscription{
-		Platform: storage.PlatformTelegram,
-		ChatID:   msg.Chat.ID,
-		Provider: target.Provider,
-		Service:  target.Service,
-		Channel:  target.Channel,
+		Platform:  storage.PlatformTelegram,
+		ChatID:    msg.Chat.ID,
+		Provider:  target.Provider,
+		Service:   target.Service,
+		Channel:   target.Channel,
+		EventMask: mask,
+		CreatedBy: senderID(msg),
 	}
 
 	if err := b.storage.AddSubscription(ctx, sub); err != nil {
 		return err
 	}
 
-	b.sendReply(ctx, msg.Chat.ID, fmt.Sprintf(
-		"已订阅 <b>%s / %s / %s</b>",
-		html.EscapeString(target.Provider),
-		html.EscapeString(target.Service),
-		html.EscapeString(target.Channel),
-	))
+	lang := i18n.FromContext(ctx)
+	b.sendReply(ctx, msg.Chat.ID, i18n.HTML(lang, "add.added_exact", target.Provider, target.Service, target.Channel)+eventMaskSuffix(lang, mask))
 	return nil
 }
 
+// eventMaskSuffix 添加订阅回复中的事件类型说明（接收全部时为空）
+func eventMaskSuffix(lang i18n.Lang, mask uint32) string {
+	if mask == storage.EventMaskAll {
+		return ""
+	}
+	return i18n.HTML(lang, "add.only_suffix", storage.FormatEventMask(mask))
+}
+
 // handleAddError 处理添加订阅时的错误
 func (b *Bot) handleAddError(ctx context.Context, chatID int64, err error, provider, service, channel string) error {
-	providerEsc := html.EscapeString(provider)
-	serviceEsc := html.EscapeString(service)
-	channelEsc := html.EscapeString(channel)
-
 	// 冷板错误处理
 	var cb *validator.ColdBoardError
 	if errors.As(err, &cb) {
 		if cb.Channel != "" {
-			b.sendReply(ctx, chatID, fmt.Sprintf(
-				"🚫 <b>%s / %s / %s</b> 已被移入冷板（board=cold），当前不支持订阅通知。",
-				html.EscapeString(cb.Provider), html.EscapeString(cb.Service), html.EscapeString(cb.Channel),
-			))
+			b.reply(ctx, chatID, "cold.channel", cb.Provider, cb.Service, cb.Channel)
 		} else if cb.Service != "" {
-			b.sendReply(ctx, chatID, fmt.Sprintf(
-				"🚫 <b>%s / %s</b> 当前无可订阅的热板监测项（均为冷板）。",
-				html.EscapeString(cb.Provider), html.EscapeString(cb.Service),
-			))
+			b.reply(ctx, chatID, "cold.service", cb.Provider, cb.Service)
 		} else if cb.Provider != "" {
-			b.sendReply(ctx, chatID, fmt.Sprintf(
-				"🚫 <b>%s</b> 当前无可订阅的热板监测项（均为冷板）。",
-				html.EscapeString(cb.Provider),
-			))
+			b.reply(ctx, chatID, "cold.provider", cb.Provider)
 		} else {
-			b.sendReply(ctx, chatID, "🚫 目标已被移入冷板（board=cold），当前不支持订阅通知。")
+			b.reply(ctx, chatID, "cold.generic")
 		}
 		return nil
 	}
@@ -553,35 +648,20 @@ func (b *Bot) handleAddError(ctx context.Context, chatID int64, err error, provi
 	if errors.As(err, &nf) {
 		switch nf.Level {
 		case validator.NotFoundProvider:
-			b.sendReply(ctx, chatID, fmt.Sprintf(
-				"未找到服务商 <b>%s</b>。\n\n请到 RelayPulse 网页复制正确的 provider/service/channel，或使用网页一键导入收藏。",
-				providerEsc,
-			))
+			b.reply(ctx, chatID, "notfound.provider", provider)
 		case validator.NotFoundService:
 			cands := validator.FormatCandidates(nf.Candidates, "service")
 			if cands != "" {
-				b.sendReply(ctx, chatID, fmt.Sprintf(
-					"未找到 <b>%s / %s</b>。\n\n该服务商下可用的 service 例如：<b>%s</b>（仅显示前 %d 个）。",
-					providerEsc, serviceEsc, html.EscapeString(cands), 8,
-				))
+				b.reply(ctx, chatID, "notfound.service_candidates", provider, service, cands, 8)
 			} else {
-				b.sendReply(ctx, chatID, fmt.Sprintf(
-					"未找到 <b>%s / %s</b>。\n\n请到 RelayPulse 网页确认 service 是否正确，或使用网页一键导入收藏。",
-					providerEsc, serviceEsc,
-				))
+				b.reply(ctx, chatID, "notfound.service", provider, service)
 			}
 		case validator.NotFoundChannel:
 			cands := validator.FormatCandidates(nf.Candidates, "channel")
 			if cands != "" {
-				b.sendReply(ctx, chatID, fmt.Sprintf(
-					"未找到 <b>%s / %s / %s</b>。\n\n该 service 下可用的 channel 例如：<b>%s</b>（仅显示前 %d 个）。",
-					providerEsc, serviceEsc, channelEsc, html.EscapeString(cands), 8,
-				))
+				b.reply(ctx, chatID, "notfound.channel_candidates", provider, service, channel, cands, 8)
 			} else {
-				b.sendReply(ctx, chatID, fmt.Sprintf(
-					"未找到 <b>%s / %s / %s</b>。",
-					providerEsc, serviceEsc, channelEsc,
-				))
+				b.reply(ctx, chatID, "notfound.channel", provider, service, channel)
 			}
 		}
 		return nil
@@ -589,7 +669,7 @@ func (b *Bot) handleAddError(ctx context.Context, chatID int64, err error, provi
 
 	var ue *validator.UnavailableError
 	if errors.As(err, &ue) {
-		b.sendReply(ctx, chatID, "当前无法验证订阅（状态服务暂不可用），为避免订阅无效服务，已拒绝本次订阅。请稍后再试，或从网页一键导入收藏。")
+		b.reply(ctx, chatID, "add.validator_unavailable")
 		return nil
 	}
 
@@ -604,7 +684,7 @@ func (b *Bot) handleAddError(ctx context.Context, chatID int64, err error, provi
 func (b *Bot) handleRemove(ctx context.Context, msg *Message, args string) error {
 	parts := strings.Fields(args)
 	if len(parts) < 1 {
-		b.sendReply(ctx, msg.Chat.ID, "用法: /remove &lt;provider&gt; [service] [channel]\n\n例如:\n/remove 88code → 移除 88code 所有订阅\n/remove 88code cc → 移除 88code 的 cc 服务订阅")
+		b.reply(ctx, msg.Chat.ID, "remove.usage")
 		return nil
 	}
 
@@ -627,21 +707,65 @@ func (b *Bot) handleRemove(ctx context.Context, msg *Message, args string) error
 		return err
 	}
 
-	// 转义 HTML 防止注入
-	providerEsc := html.EscapeString(provider)
-	serviceEsc := html.EscapeString(service)
-	channelEsc := html.EscapeString(channel)
-
 	// 根据删除级别显示不同消息
 	if service == "" {
 		// provider 级删除（级联）
-		b.sendReply(ctx, msg.Chat.ID, fmt.Sprintf("已取消订阅 <b>%s / *</b>（包括该服务商下所有订阅）", providerEsc))
+		b.reply(ctx, msg.Chat.ID, "remove.provider", provider)
 	} else if channel != "" {
 		// 精确删除
-		b.sendReply(ctx, msg.Chat.ID, fmt.Sprintf("已取消订阅 <b>%s / %s / %s</b>", providerEsc, serviceEsc, channelEsc))
+		b.reply(ctx, msg.Chat.ID, "remove.exact", provider, service, channel)
 	} else {
 		// service 级删除
-		b.sendReply(ctx, msg.Chat.ID, fmt.Sprintf("已取消订阅 <b>%s / %s</b>", providerEsc, serviceEsc))
+		b.reply(ctx, msg.Chat.ID, "remove.service", provider, service)
+	}
+	return nil
+}
+
+// handleFilter 处理 /filter 命令
+// 最后一个参数为事件类型列表，其余参数的匹配规则与 /remove 一致：
+// - /filter <provider> <types> → 该 provider 下所有订阅
+// - /filter <provider> <service> <types> → 该 service 下所有通道
+// - /filter <provider> <service> <channel> <types> → 精确一条
+func (b *Bot) handleFilter(ctx context.Context, msg *Message, args string) error {
+	parts := strings.Fields(args)
+	if len(parts) < 2 || len(parts) > 4 {
+		b.reply(ctx, msg.Chat.ID, "filter.usage", storage.EventMaskNames)
+		return nil
+	}
+
+	mask, err := storage.ParseEventMask(parts[len(parts)-1])
+	if err != nil {
+		b.reply(ctx, msg.Chat.ID, "filter.invalid", storage.EventMaskNames)
+		return nil
+	}
+
+	provider := parts[0]
+	service := ""
+	if len(parts) > 2 {
+		service = parts[1]
+	}
+	channel := ""
+	if len(parts) > 3 {
+		channel = parts[2]
+	}
+	// "default" 归一化为空字符串（与 add/remove 对齐）
+	if strings.EqualFold(channel, "default") {
+		channel = ""
+	}
+
+	updated, err := b.storage.SetSubscriptionEventMask(ctx, storage.PlatformTelegram, msg.Chat.ID, provider, service, channel, mask)
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		b.reply(ctx, msg.Chat.ID, "filter.not_found")
+		return nil
+	}
+
+	if mask == storage.EventMaskAll {
+		b.reply(ctx, msg.Chat.ID, "filter.updated_all", updated)
+	} else {
+		b.reply(ctx, msg.Chat.ID, "filter.updated_only", updated, storage.FormatEventMask(mask))
 	}
 	return nil
 }
@@ -652,7 +776,7 @@ func (b *Bot) handleClear(ctx context.Context, msg *Message, args string) error
 		return err
 	}
 
-	b.sendReply(ctx, msg.Chat.ID, "已清空所有订阅。")
+	b.reply(ctx, msg.Chat.ID, "clear.done")
 	return nil
 }
 
@@ -663,52 +787,17 @@ func (b *Bot) handleStatus(ctx context.Context, msg *Message, args string) error
 		return err
 	}
 
-	status := fmt.Sprintf(`<b>服务状态</b>
-
-订阅数量: %d/%d
-服务版本: %s
-状态: 运行中 ✅
-
-数据源: %s`,
+	b.reply(ctx, msg.Chat.ID, "status.telegram",
 		count, b.cfg.Limits.MaxSubscriptionsPerUser,
 		"dev", // TODO: 从外部传入版本号
 		b.cfg.RelayPulse.EventsURL,
 	)
-
-	b.sendReply(ctx, msg.Chat.ID, status)
 	return nil
 }
 
 // handleHelp 处理 /help 命令
 func (b *Bot) handleHelp(ctx context.Context, msg *Message, args string) error {
-	help := `<b>RelayPulse 通知 Bot 帮助</b>
-
-<b>命令列表：</b>
-/start - 开始使用 / 导入收藏
-/list - 查看当前订阅
-/add &lt;provider&gt; [service] [channel] - 添加订阅
-/remove &lt;provider&gt; [service] [channel] - 移除订阅
-/clear - 清空所有订阅
-/snap - 截图订阅服务状态
-/status - 查看服务状态
-/help - 显示此帮助
-
-<b>快速开始：</b>
-1. 访问 RelayPulse 网站
-2. 收藏你关注的服务
-3. 点击"订阅通知"按钮
-4. 跳转到此 Bot 自动导入
-
-<b>手动添加订阅：</b>
-/add 88code → 订阅 88code 所有服务
-/add 88code cc → 订阅 88code 的 cc 服务
-/add duckcoding cc v1 → 精确订阅
-
-<b>移除订阅：</b>
-/remove 88code → 移除 88code 所有订阅
-/remove 88code cc → 移除 88code 的 cc 订阅`
-
-	b.sendReply(ctx, msg.Chat.ID, help)
+	b.reply(ctx, msg.Chat.ID, "help.telegram")
 	return nil
 }
 
@@ -718,7 +807,7 @@ func (b *Bot) handleSnap(ctx context.Context, msg *Message, args string) error {
 
 	// 检查截图服务是否启用
 	if b.screenshotService == nil {
-		b.sendReply(ctx, chatID, "截图功能未启用。")
+		b.reply(ctx, chatID, "snap.disabled")
 		return nil
 	}
 
@@ -726,11 +815,11 @@ func (b *Bot) handleSnap(ctx context.Context, msg *Message, args string) error {
 	subs, err := b.storage.GetSubscriptionsByChatID(ctx, storage.PlatformTelegram, chatID)
 	if err != nil {
 		slog.Error("获取订阅失败", "chat_id", chatID, "error", err)
-		b.sendReply(ctx, chatID, "获取订阅信息失败，请稍后重试。")
+		b.reply(ctx, chatID, "snap.fetch_failed")
 		return nil
 	}
 	if len(subs) == 0 {
-		b.sendReply(ctx, chatID, "你还没有订阅任何服务。\n\n使用 /add 添加订阅后再试。")
+		b.reply(ctx, chatID, "snap.empty")
 		return nil
 	}
 
@@ -741,29 +830,31 @@ func (b *Bot) handleSnap(ctx context.Context, msg *Message, args string) error {
 	services := extractUniqueServices(subs)
 
 	// 构建专属标识（群名/用户名）
-	ownerLabel := getOwnerLabel(msg)
+	lang := i18n.FromContext(ctx)
+	ownerLabel := getOwnerLabel(lang, msg)
 
-	// 发送提示（HTML 模式需要转义动态文本）
-	b.sendReply(ctx, chatID, fmt.Sprintf("正在生成 [%s专属] 的状态截图...", html.EscapeString(ownerLabel)))
+	// 发送提示
+	b.reply(ctx, chatID, "snap.generating", ownerLabel)
 
 	// 截图（使用独立的超时 ctx）
 	snapCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	// 构建截图标题（群名/用户名 + 专属状态）
-	title := ownerLabel + " 专属状态"
+	title := i18n.Text(lang, "snap.title", ownerLabel)
 	pngData, err := b.screenshotService.CaptureWithOptions(snapCtx, providers, services, &screenshot.CaptureOptions{
-		Title: title,
+		Title:    title,
+		Language: string(lang),
 	})
 	if err != nil {
 		slog.Error("截图失败", "chat_id", chatID, "providers", providers, "error", err)
 		// 区分错误类型
 		if errors.Is(err, context.DeadlineExceeded) {
-			b.sendReply(ctx, chatID, "截图超时，请稍后重试。")
+			b.reply(ctx, chatID, "snap.timeout")
 		} else if errors.Is(err, screenshot.ErrConcurrencyLimit) {
-			b.sendReply(ctx, chatID, "系统繁忙，请稍后重试。")
+			b.reply(ctx, chatID, "snap.busy")
 		} else {
-			b.sendReply(ctx, chatID, "截图生成失败，请稍后重试。")
+			b.reply(ctx, chatID, "snap.failed")
 		}
 		return nil
 	}
@@ -771,11 +862,508 @@ func (b *Bot) handleSnap(ctx context.Context, msg *Message, args string) error {
 	// 发送图片
 	if _, err := b.client.SendPhoto(ctx, chatID, pngData, ""); err != nil {
 		slog.Error("发送图片失败", "chat_id", chatID, "error", err)
-		b.sendReply(ctx, chatID, "截图生成成功，但发送失败。请稍后重试。")
+		b.reply(ctx, chatID, "snap.send_failed")
+	}
+	return nil
+}
+
+// handleLang 处理 /lang 命令（查看或设置会话语言）
+// - /lang → 显示当前语言与可选项
+// - /lang <code> → 设置语言偏好（zh/en/ja/ru）
+// - /lang auto → 清除偏好，恢复跟随客户端语言
+func (b *Bot) handleLang(ctx context.Context, msg *Message, args string) error {
+	code := strings.ToLower(strings.TrimSpace(args))
+	if code == "" {
+		b.reply(ctx, msg.Chat.ID, "lang.current", i18n.Name(i18n.FromContext(ctx)), i18n.Options())
+		return nil
+	}
+
+	if code == "auto" || code == "default" {
+		if err := b.storage.UpdateChatLanguage(ctx, storage.PlatformTelegram, msg.Chat.ID, ""); err != nil {
+			return err
+		}
+		clientCode := ""
+		if msg.From != nil {
+			clientCode = msg.From.LanguageCode
+		}
+		lang := i18n.Resolve("", clientCode)
+		b.sendReply(ctx, msg.Chat.ID, i18n.HTML(lang, "lang.reset", i18n.Name(lang)))
+		return nil
+	}
+
+	lang, ok := i18n.Parse(code)
+	if !ok {
+		b.reply(ctx, msg.Chat.ID, "lang.invalid", args, i18n.Options())
+		return nil
+	}
+	if err := b.storage.UpdateChatLanguage(ctx, storage.PlatformTelegram, msg.Chat.ID, string(lang)); err != nil {
+		return err
+	}
+	b.sendReply(ctx, msg.Chat.ID, i18n.HTML(lang, "lang.set", i18n.Name(lang)))
+	return nil
+}
+
+// handleRole 处理 /role 命令（群组角色）
+// - /role → 查看群内已分配的角色
+// - /role <user_id> <owner|editor|viewer|none> → 分配或移除角色（需 owner）
+// - 回复成员消息发送 /role <owner|editor|viewer|none> → 同上
+func (b *Bot) handleRole(ctx context.Context, msg *Message, args string) error {
+	if !isGroupChat(msg.Chat) {
+		b.reply(ctx, msg.Chat.ID, "role.private")
+		return nil
+	}
+
+	parts := strings.Fields(args)
+	if len(parts) == 0 {
+		roles, err := b.storage.GetChatRoles(ctx, storage.PlatformTelegram, msg.Chat.ID)
+		if err != nil {
+			return err
+		}
+		lang := i18n.FromContext(ctx)
+		if len(roles) == 0 {
+			b.sendReply(ctx, msg.Chat.ID, i18n.HTML(lang, "role.list_empty")+"\n\n"+i18n.HTML(lang, "role.usage", storage.RoleNames))
+			return nil
+		}
+		var sb strings.Builder
+		sb.WriteString(i18n.HTML(lang, "role.list_header", len(roles)))
+		for _, r := range roles {
+			sb.WriteString(fmt.Sprintf("• <code>%d</code> — %s\n", r.UserID, r.Role))
+		}
+		b.sendReply(ctx, msg.Chat.ID, sb.String())
+		return nil
+	}
+
+	var target int64
+	var roleArg string
+	switch {
+	case len(parts) == 1 && msg.ReplyToMessage != nil && msg.ReplyToMessage.From != nil:
+		target, roleArg = msg.ReplyToMessage.From.ID, parts[0]
+	case len(parts) == 2:
+		target, _ = strconv.ParseInt(parts[0], 10, 64)
+		roleArg = parts[1]
+	}
+	if target <= 0 {
+		b.reply(ctx, msg.Chat.ID, "role.usage", storage.RoleNames)
+		return nil
+	}
+
+	if strings.EqualFold(roleArg, "none") {
+		if err := b.storage.RemoveChatRole(ctx, storage.PlatformTelegram, msg.Chat.ID, target); err != nil {
+			return err
+		}
+		b.reply(ctx, msg.Chat.ID, "role.removed", target)
+		return nil
+	}
+
+	role, ok := storage.ParseRole(roleArg)
+	if !ok {
+		b.reply(ctx, msg.Chat.ID, "role.usage", storage.RoleNames)
+		return nil
+	}
+	if err := b.storage.SetChatRole(ctx, &storage.ChatRole{
+		Platform:  storage.PlatformTelegram,
+		ChatID:    msg.Chat.ID,
+		UserID:    target,
+		Role:      role,
+		GrantedBy: senderID(msg),
+	}); err != nil {
+		return err
+	}
+	b.reply(ctx, msg.Chat.ID, "role.set", target, role)
+	return nil
+}
+
+// handleDigest 处理 /digest 命令（查看或设置定期摘要）
+// - /digest → 显示当前设置与用法
+// - /digest daily|weekly [hour] → 每天/每周一 hour:00（UTC+8）发送摘要
+// - /digest off → 关闭摘要
+func (b *Bot) handleDigest(ctx context.Context, msg *Message, args string) error {
+	if !b.cfg.HasDigest() {
+		b.reply(ctx, msg.Chat.ID, "digest.disabled")
+		return nil
+	}
+
+	lang := i18n.FromContext(ctx)
+	if strings.TrimSpace(args) == "" {
+		chat, err := b.storage.GetChat(ctx, storage.PlatformTelegram, msg.Chat.ID)
+		if err != nil {
+			return err
+		}
+		b.sendReply(ctx, msg.Chat.ID, digestStatus(lang, chat)+"\n\n"+i18n.HTML(lang, "digest.usage", b.cfg.Digest.DefaultHour))
+		return nil
+	}
+
+	frequency, hour, err := storage.ParseDigestSchedule(args, b.cfg.Digest.DefaultHour)
+	if errors.Is(err, storage.ErrInvalidDigestHour) {
+		b.reply(ctx, msg.Chat.ID, "digest.invalid_hour", strings.Fields(args)[1])
+		return nil
+	}
+	if err != nil {
+		b.reply(ctx, msg.Chat.ID, "digest.usage", b.cfg.Digest.DefaultHour)
+		return nil
+	}
+
+	if err := b.storage.UpdateChatDigest(ctx, storage.PlatformTelegram, msg.Chat.ID, frequency, hour); err != nil {
+		return err
 	}
+	if frequency == storage.DigestOff {
+		b.reply(ctx, msg.Chat.ID, "digest.off")
+		return nil
+	}
+	b.reply(ctx, msg.Chat.ID, "digest.set", digestFrequencyName(lang, frequency), hour)
 	return nil
 }
 
+// handleExport 处理 /export 命令（导出订阅并签发会话令牌）
+func (b *Bot) handleExport(ctx context.Context, msg *Message, args string) error {
+	export, err := storage.ExportSubscriptions(ctx, b.storage, storage.PlatformTelegram, msg.Chat.ID)
+	if err != nil {
+		return err
+	}
+	if len(export.Subscriptions) == 0 {
+		b.reply(ctx, msg.Chat.ID, "export.empty")
+		return nil
+	}
+
+	ttl := b.cfg.Limits.BindTokenTTL
+	token, err := storage.CreateChatToken(ctx, b.storage, storage.PlatformTelegram, msg.Chat.ID, ttl)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(export)
+	if err != nil {
+		return err
+	}
+
+	b.reply(ctx, msg.Chat.ID, "export.header", len(export.Subscriptions), token.Token, int(ttl.Minutes()), token.Token)
+	b.sendReply(ctx, msg.Chat.ID, "<pre>"+html.EscapeString(string(data))+"</pre>")
+	return nil
+}
+
+// handleImport 处理 /import 命令
+// - /import <令牌> → 复制签发令牌的会话（可为 QQ）的订阅
+// - /import <JSON> → 从 /export 导出的 JSON 还原订阅
+func (b *Bot) handleImport(ctx context.Context, msg *Message, args string) error {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		b.reply(ctx, msg.Chat.ID, "import.usage")
+		return nil
+	}
+
+	var export *storage.SubscriptionExport
+	if strings.HasPrefix(args, "{") {
+		parsed, err := storage.ParseSubscriptionExport([]byte(args))
+		if err != nil {
+			b.reply(ctx, msg.Chat.ID, "import.invalid", err.Error())
+			return nil
+		}
+		export = parsed
+	} else {
+		source, err := storage.ResolveChatToken(ctx, b.storage, args)
+		if errors.Is(err, storage.ErrInvalidChatToken) {
+			b.reply(ctx, msg.Chat.ID, "import.token_invalid")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		export, err = storage.ExportSubscriptions(ctx, b.storage, source.Platform, source.ChatID)
+		if err != nil {
+			return err
+		}
+	}
+
+	maxSubs := b.cfg.Limits.MaxSubscriptionsPerUser
+	result, err := storage.ImportSubscriptions(ctx, b.storage, storage.PlatformTelegram, msg.Chat.ID, export, maxSubs, false, senderID(msg))
+	if err != nil {
+		return err
+	}
+
+	lang := i18n.FromContext(ctx)
+	reply := i18n.HTML(lang, "import.done", result.Imported, result.Existing)
+	if result.Skipped > 0 {
+		reply += i18n.HTML(lang, "import.skipped", maxSubs, result.Skipped)
+	}
+	b.sendReply(ctx, msg.Chat.ID, reply)
+	return nil
+}
+
+// handleLink 处理 /link 命令（跨平台身份关联）
+// - /link → 查看已关联的会话并签发一次性关联令牌
+// - /link <令牌> → 与签发令牌的会话（可为 QQ）关联
+// - /link prefer → 将当前会话设为首选投递会话
+// - /link off → 取消当前会话的关联
+func (b *Bot) handleLink(ctx context.Context, msg *Message, args string) error {
+	chatID := msg.Chat.ID
+	arg := strings.TrimSpace(args)
+
+	switch strings.ToLower(arg) {
+	case "":
+		lang := i18n.FromContext(ctx)
+		links, err := b.storage.GetLinkedChats(ctx, storage.PlatformTelegram, chatID)
+		if err != nil {
+			return err
+		}
+		ttl := b.cfg.Limits.BindTokenTTL
+		token, err := storage.CreateLinkToken(ctx, b.storage, storage.PlatformTelegram, chatID, ttl)
+		if err != nil {
+			return err
+		}
+
+		var sb strings.Builder
+		if len(links) == 0 {
+			sb.WriteString(i18n.HTML(lang, "link.none") + "\n\n")
+		} else {
+			sb.WriteString(i18n.HTML(lang, "link.header", len(links)))
+			for i, l := range links {
+				sb.WriteString(fmt.Sprintf("%d. %s <code>%d</code>", i+1, l.Platform, l.ChatID))
+				if l.Platform == storage.PlatformTelegram && l.ChatID == chatID {
+					sb.WriteString(i18n.HTML(lang, "link.current"))
+				}
+				sb.WriteString("\n")
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString(i18n.HTML(lang, "link.token", token.Token, int(ttl.Minutes())))
+		b.sendReply(ctx, chatID, sb.String())
+		return nil
+
+	case "prefer":
+		linked, err := b.storage.SetPreferredChat(ctx, storage.PlatformTelegram, chatID)
+		if err != nil {
+			return err
+		}
+		if !linked {
+			b.reply(ctx, chatID, "link.not_linked")
+			return nil
+		}
+		b.reply(ctx, chatID, "link.preferred")
+		return nil
+
+	case "off":
+		linked, err := b.storage.UnlinkChat(ctx, storage.PlatformTelegram, chatID)
+		if err != nil {
+			return err
+		}
+		if !linked {
+			b.reply(ctx, chatID, "link.not_linked")
+			return nil
+		}
+		b.reply(ctx, chatID, "link.unlinked")
+		return nil
+	}
+
+	source, err := storage.ConsumeLinkToken(ctx, b.storage, arg)
+	if errors.Is(err, storage.ErrInvalidLinkToken) {
+		b.reply(ctx, chatID, "link.token_invalid")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if source.Platform == storage.PlatformTelegram && source.ChatID == chatID {
+		b.reply(ctx, chatID, "link.self")
+		return nil
+	}
+	if err := b.storage.LinkChat(ctx, storage.PlatformTelegram, chatID, source.Platform, source.ChatID); err != nil {
+		return err
+	}
+	links, err := b.storage.GetLinkedChats(ctx, storage.PlatformTelegram, chatID)
+	if err != nil {
+		return err
+	}
+	b.reply(ctx, chatID, "link.done", len(links))
+	return nil
+}
+
+// handlePause 处理 /pause 命令（暂停通知，保留订阅）
+// - /pause → 暂停到 /resume
+// - /pause <时长> → 暂停指定时长（如 2h、3d、1w）
+func (b *Bot) handlePause(ctx context.Context, msg *Message, args string) error {
+	chatID := msg.Chat.ID
+	arg := strings.TrimSpace(args)
+	if arg == "" {
+		if err := b.storage.UpdateChatPause(ctx, storage.PlatformTelegram, chatID, storage.PausedIndefinitely); err != nil {
+			return err
+		}
+		b.reply(ctx, chatID, "pause.set_indefinite")
+		return nil
+	}
+
+	d, err := storage.ParsePauseDuration(arg)
+	if err != nil {
+		b.reply(ctx, chatID, "pause.invalid", arg)
+		return nil
+	}
+	until := time.Now().Add(d).Unix()
+	if err := b.storage.UpdateChatPause(ctx, storage.PlatformTelegram, chatID, until); err != nil {
+		return err
+	}
+	b.reply(ctx, chatID, "pause.set", storage.PauseEndText(until))
+	return nil
+}
+
+// handleResume 处理 /resume 命令（恢复通知）
+func (b *Bot) handleResume(ctx context.Context, msg *Message, args string) error {
+	chatID := msg.Chat.ID
+	chat, err := b.storage.GetChat(ctx, storage.PlatformTelegram, chatID)
+	if err != nil {
+		return err
+	}
+	if chat == nil || !storage.IsPaused(chat.PausedUntil, time.Now().Unix()) {
+		b.reply(ctx, chatID, "resume.not_paused")
+		return nil
+	}
+	if err := b.storage.UpdateChatPause(ctx, storage.PlatformTelegram, chatID, 0); err != nil {
+		return err
+	}
+	b.reply(ctx, chatID, "resume.done")
+	return nil
+}
+
+// handleStats 处理 /stats 命令（近 24 小时/7 天通知数、通知最多的监测项与订阅服务故障时长）
+func (b *Bot) handleStats(ctx context.Context, msg *Message, args string) error {
+	stats, err := storage.ComputeChatStats(ctx, b.storage, storage.PlatformTelegram, msg.Chat.ID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	lang := i18n.FromContext(ctx)
+	var sb strings.Builder
+	sb.WriteString(i18n.HTML(lang, "stats.header"))
+	sb.WriteString(i18n.HTML(lang, "stats.deliveries", stats.Last24h, stats.Last7d))
+	if len(stats.TopMonitors) > 0 {
+		sb.WriteString(i18n.HTML(lang, "stats.top_header"))
+		for i, c := range stats.TopMonitors {
+			sb.WriteString(i18n.HTML(lang, "stats.top_item", i+1, c.Name(), c.Total))
+		}
+	}
+	sb.WriteString(i18n.HTML(lang, "stats.incidents_header"))
+	if stats.Incidents > 0 {
+		sb.WriteString(i18n.HTML(lang, "stats.incidents", stats.Incidents, storage.FormatStatsDuration(stats.AvgIncidentDuration)))
+	} else {
+		sb.WriteString(i18n.HTML(lang, "stats.incidents_none"))
+	}
+	if stats.Ongoing > 0 {
+		sb.WriteString(i18n.HTML(lang, "stats.ongoing", stats.Ongoing))
+	}
+
+	b.sendReply(ctx, msg.Chat.ID, sb.String())
+	return nil
+}
+
+// handleAnnouncements 处理 /announcements 命令（接收或退订 RelayPulse 公告）
+func (b *Bot) handleAnnouncements(ctx context.Context, msg *Message, args string) error {
+	chatID := msg.Chat.ID
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		chat, err := b.storage.GetChat(ctx, storage.PlatformTelegram, chatID)
+		if err != nil {
+			return err
+		}
+		if chat != nil && chat.AnnouncementsOff {
+			b.reply(ctx, chatID, "announcements.status_off")
+		} else {
+			b.reply(ctx, chatID, "announcements.status_on")
+		}
+	case "on":
+		if err := b.storage.UpdateChatAnnouncements(ctx, storage.PlatformTelegram, chatID, false); err != nil {
+			return err
+		}
+		b.reply(ctx, chatID, "announcements.on")
+	case "off":
+		if err := b.storage.UpdateChatAnnouncements(ctx, storage.PlatformTelegram, chatID, true); err != nil {
+			return err
+		}
+		b.reply(ctx, chatID, "announcements.off")
+	default:
+		b.reply(ctx, chatID, "announcements.usage")
+	}
+	return nil
+}
+
+// handleBroadcast 处理 /broadcast 命令（仅 telegram.admin_ids 中的运维管理员）
+// /broadcast <all|provider> <公告内容> → 向所有活跃会话或订阅了该服务商的会话发送公告，完成后在此会话回复发送统计
+func (b *Bot) handleBroadcast(ctx context.Context, msg *Message, args string) error {
+	chatID := msg.Chat.ID
+	if !b.isAdmin(msg) {
+		slog.Warn("非管理员尝试发送公告", "chat_id", chatID, "user_id", senderID(msg))
+		b.reply(ctx, chatID, "broadcast.denied")
+		return nil
+	}
+	if b.broadcaster == nil {
+		b.reply(ctx, chatID, "broadcast.unavailable")
+		return nil
+	}
+
+	provider, message, ok := storage.ParseBroadcastArgs(args)
+	if !ok {
+		b.reply(ctx, chatID, "broadcast.usage", storage.MaxBroadcastLength)
+		return nil
+	}
+	if n := utf8.RuneCountInString(message); n > storage.MaxBroadcastLength {
+		b.reply(ctx, chatID, "broadcast.too_long", n, storage.MaxBroadcastLength)
+		return nil
+	}
+
+	lang := i18n.FromContext(ctx)
+	bc := &storage.Broadcast{
+		Actor:    fmt.Sprintf("telegram:%d", senderID(msg)),
+		Provider: provider,
+		Message:  message,
+	}
+	onDone := func(done *storage.Broadcast) {
+		// 广播可能持续较久，完成通知不依赖命令的 context
+		replyCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if done.Status == storage.BroadcastStatusAborted {
+			b.sendReply(replyCtx, chatID, i18n.HTML(lang, "broadcast.aborted", done.ID, done.Sent, done.Failed, done.Total))
+			return
+		}
+		b.sendReply(replyCtx, chatID, i18n.HTML(lang, "broadcast.done", done.ID, done.Sent, done.Failed, done.Total, done.OptedOut))
+	}
+	if err := b.broadcaster.StartBroadcast(ctx, bc, onDone); err != nil {
+		return err
+	}
+
+	if bc.Total == 0 {
+		b.reply(ctx, chatID, "broadcast.empty", bc.ID, bc.OptedOut)
+		return nil
+	}
+	b.reply(ctx, chatID, "broadcast.started", bc.ID, bc.Total, bc.OptedOut)
+	return nil
+}
+
+// isAdmin 消息发送者是否为运维管理员（telegram.admin_ids）
+func (b *Bot) isAdmin(msg *Message) bool {
+	id := senderID(msg)
+	if id <= 0 {
+		return false
+	}
+	for _, adminID := range b.cfg.Telegram.AdminIDs {
+		if adminID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// digestStatus 当前摘要设置（HTML）
+func digestStatus(lang i18n.Lang, chat *storage.Chat) string {
+	if chat == nil || chat.DigestFrequency == storage.DigestOff {
+		return i18n.HTML(lang, "digest.status_off")
+	}
+	return i18n.HTML(lang, "digest.status_on", digestFrequencyName(lang, chat.DigestFrequency), chat.DigestHour)
+}
+
+// digestFrequencyName 摘要频率的展示名称
+func digestFrequencyName(lang i18n.Lang, frequency string) string {
+	if frequency == storage.DigestWeekly {
+		return i18n.Text(lang, "digest.freq_weekly")
+	}
+	return i18n.Text(lang, "digest.freq_daily")
+}
+
 // extractUniqueProviders 从订阅列表中提取去重的 provider 列表
 func extractUniqueProviders(subs []*storage.Subscription) []string {
 	seen := make(map[string]struct{})
@@ -803,9 +1391,9 @@ func extractUniqueServices(subs []*storage.Subscription) []string {
 }
 
 // getOwnerLabel 获取截图专属标识（群名或用户名）
-func getOwnerLabel(msg *Message) string {
+func getOwnerLabel(lang i18n.Lang, msg *Message) string {
 	if msg == nil || msg.Chat == nil {
-		return "你"
+		return i18n.Text(lang, "snap.owner_you")
 	}
 
 	// 辅助函数：截断并清理文本
@@ -837,7 +1425,7 @@ func getOwnerLabel(msg *Message) string {
 		return name
 	}
 
-	return "你"
+	return i18n.Text(lang, "snap.owner_you")
 }
 
 // Favorite 收藏项
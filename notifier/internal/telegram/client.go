@@ -31,11 +31,12 @@ func NewClient(token string) *Client {
 
 // User Telegram 用户
 type User struct {
-	ID        int64  `json:"id"`
-	IsBot     bool   `json:"is_bot"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name,omitempty"`
-	Username  string `json:"username,omitempty"`
+	ID           int64  `json:"id"`
+	IsBot        bool   `json:"is_bot"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name,omitempty"`
+	Username     string `json:"username,omitempty"`
+	LanguageCode string `json:"language_code,omitempty"` // 客户端语言（IETF 语言标签）
 }
 
 // Chat Telegram 聊天
@@ -50,17 +51,34 @@ type Chat struct {
 
 // Message Telegram 消息
 type Message struct {
-	MessageID int64  `json:"message_id"`
-	From      *User  `json:"from,omitempty"`
-	Chat      *Chat  `json:"chat"`
-	Date      int64  `json:"date"`
-	Text      string `json:"text,omitempty"`
+	MessageID      int64    `json:"message_id"`
+	From           *User    `json:"from,omitempty"`
+	SenderChat     *Chat    `json:"sender_chat,omitempty"` // 以群组身份发言（匿名管理员）时为群组本身
+	Chat           *Chat    `json:"chat"`
+	Date           int64    `json:"date"`
+	Text           string   `json:"text,omitempty"`
+	ReplyToMessage *Message `json:"reply_to_message,omitempty"`
+}
+
+// ChatMember 群组成员信息（用于权限判断）
+type ChatMember struct {
+	Status string `json:"status"` // creator / administrator / member / restricted / left / kicked
+	User   *User  `json:"user"`
+}
+
+// InlineQuery Telegram 内联查询（@BotUsername 关键词）
+type InlineQuery struct {
+	ID     string `json:"id"`
+	From   *User  `json:"from"`
+	Query  string `json:"query"`
+	Offset string `json:"offset"`
 }
 
 // Update Telegram 更新
 type Update struct {
-	UpdateID int64    `json:"update_id"`
-	Message  *Message `json:"message,omitempty"`
+	UpdateID    int64        `json:"update_id"`
+	Message     *Message     `json:"message,omitempty"`
+	InlineQuery *InlineQuery `json:"inline_query,omitempty"`
 }
 
 // APIResponse Telegram API 响应
@@ -78,6 +96,21 @@ type SendMessageRequest struct {
 	ParseMode string `json:"parse_mode,omitempty"`
 }
 
+// InputTextMessageContent 内联结果被选中后发送的文本消息
+type InputTextMessageContent struct {
+	MessageText string `json:"message_text"`
+	ParseMode   string `json:"parse_mode,omitempty"`
+}
+
+// InlineQueryResultArticle 内联查询结果（文章卡片）
+type InlineQueryResultArticle struct {
+	Type                string                  `json:"type"` // 固定为 article
+	ID                  string                  `json:"id"`
+	Title               string                  `json:"title"`
+	Description         string                  `json:"description,omitempty"`
+	InputMessageContent InputTextMessageContent `json:"input_message_content"`
+}
+
 // GetMe 获取 Bot 信息
 func (c *Client) GetMe(ctx context.Context) (*User, error) {
 	resp, err := c.doRequest(ctx, "getMe", nil)
@@ -93,6 +126,24 @@ func (c *Client) GetMe(ctx context.Context) (*User, error) {
 	return &user, nil
 }
 
+// GetChatMember 获取群组成员信息
+func (c *Client) GetChatMember(ctx context.Context, chatID, userID int64) (*ChatMember, error) {
+	resp, err := c.doRequest(ctx, "getChatMember", map[string]interface{}{
+		"chat_id": chatID,
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var member ChatMember
+	if err := json.Unmarshal(resp.Result, &member); err != nil {
+		return nil, fmt.Errorf("解析成员信息失败: %w", err)
+	}
+
+	return &member, nil
+}
+
 // GetUpdates 获取更新（Long Polling）
 func (c *Client) GetUpdates(ctx context.Context, offset int64, timeout int) ([]Update, error) {
 	params := map[string]interface{}{
@@ -146,6 +197,23 @@ func (c *Client) SendMessageHTML(ctx context.Context, chatID int64, text string)
 	return c.SendMessage(ctx, chatID, text, "HTML")
 }
 
+// AnswerInlineQuery 回复内联查询
+// cacheTime 为 Telegram 服务端缓存结果的秒数
+func (c *Client) AnswerInlineQuery(ctx context.Context, queryID string, results []InlineQueryResultArticle, cacheTime int) error {
+	if results == nil {
+		results = []InlineQueryResultArticle{}
+	}
+	params := map[string]interface{}{
+		"inline_query_id": queryID,
+		"results":         results,
+		"cache_time":      cacheTime,
+		"is_personal":     false,
+	}
+
+	_, err := c.doRequest(ctx, "answerInlineQuery", params)
+	return err
+}
+
 // SendPhoto 发送图片消息（上传图片数据）
 func (c *Client) SendPhoto(ctx context.Context, chatID int64, photoData []byte, caption string) (*Message, error) {
 	url := c.baseURL + "/sendPhoto"
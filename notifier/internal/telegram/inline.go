@@ -0,0 +1,236 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+
+	"notifier/internal/i18n"
+	"notifier/internal/validator"
+)
+
+const (
+	inlineCacheTime  = 10 // Telegram 服务端缓存内联结果的秒数
+	inlineMaxResults = 20 // 单次回复的最大结果数
+	inlineTimeout    = 8 * time.Second
+)
+
+// handleInlineQuery 处理内联查询（@BotUsername <provider> [service]），返回当前状态卡片
+func (b *Bot) handleInlineQuery(ctx context.Context, q *InlineQuery) {
+	ctx, cancel := context.WithTimeout(ctx, inlineTimeout)
+	defer cancel()
+
+	clientCode := ""
+	if q.From != nil {
+		clientCode = q.From.LanguageCode
+	}
+	lang := i18n.Resolve("", clientCode)
+
+	results := b.inlineResults(ctx, lang, q.Query)
+	if err := b.client.AnswerInlineQuery(ctx, q.ID, results, inlineCacheTime); err != nil {
+		slog.Warn("回复内联查询失败", "query", q.Query, "error", err)
+	}
+}
+
+// inlineResults 根据查询内容生成内联结果
+func (b *Bot) inlineResults(ctx context.Context, lang i18n.Lang, query string) []InlineQueryResultArticle {
+	parts := strings.Fields(query)
+	if len(parts) == 0 {
+		return []InlineQueryResultArticle{b.inlineHint(lang)}
+	}
+	if b.validator == nil {
+		return []InlineQueryResultArticle{inlineNotice("unconfigured", i18n.Text(lang, "inline.unconfigured"), "")}
+	}
+
+	provider := parts[0]
+	service := ""
+	if len(parts) > 1 {
+		service = parts[1]
+	}
+
+	snapshot, err := b.validator.QueryStatus(ctx, provider, service)
+	if err != nil {
+		return []InlineQueryResultArticle{b.inlineError(ctx, lang, err, provider, service)}
+	}
+	if len(snapshot.Channels) == 0 {
+		return []InlineQueryResultArticle{inlineNotice("empty", snapshot.Provider, i18n.Text(lang, "inline.empty"))}
+	}
+
+	// 按 service 分组（保持 API 返回顺序）
+	var services []string
+	groups := make(map[string][]validator.ChannelStatus)
+	for _, ch := range snapshot.Channels {
+		if _, ok := groups[ch.Service]; !ok {
+			services = append(services, ch.Service)
+		}
+		groups[ch.Service] = append(groups[ch.Service], ch)
+	}
+
+	results := make([]InlineQueryResultArticle, 0, len(services)+1)
+	if len(services) > 1 {
+		results = append(results, statusCard(lang, "all", true,
+			snapshot.Provider+" · "+i18n.Text(lang, "inline.overview"),
+			snapshot.Provider, snapshot.Channels))
+	}
+	for i, svc := range services {
+		if len(results) >= inlineMaxResults {
+			break
+		}
+		// 结果 ID 限长 64 字节，使用序号而非 service 名称
+		results = append(results, statusCard(lang, fmt.Sprintf("svc:%d", i), false,
+			snapshot.Provider+" / "+svc,
+			snapshot.Provider+" / "+svc, groups[svc]))
+	}
+	return results
+}
+
+// inlineError 将验证器错误转换为提示结果
+func (b *Bot) inlineError(ctx context.Context, lang i18n.Lang, err error, provider, service string) InlineQueryResultArticle {
+	var nf *validator.NotFoundError
+	if errors.As(err, &nf) {
+		target := provider
+		desc := ""
+		if nf.Level != validator.NotFoundProvider && service != "" {
+			target = provider + " / " + service
+			// service 不存在时，列出该 provider 下的可选 service
+			if snapshot, err := b.validator.QueryStatus(ctx, provider, ""); err == nil {
+				desc = i18n.Text(lang, "inline.candidates",
+					validator.FormatCandidates(snapshotServices(snapshot), "service"))
+			}
+		}
+		return inlineNotice("not_found", i18n.Text(lang, "inline.not_found", target), desc)
+	}
+
+	slog.Warn("内联查询状态失败", "provider", provider, "service", service, "error", err)
+	return inlineNotice("unavailable", i18n.Text(lang, "inline.unavailable"), "")
+}
+
+// inlineHint 空查询时的用法提示
+func (b *Bot) inlineHint(lang i18n.Lang) InlineQueryResultArticle {
+	return InlineQueryResultArticle{
+		Type:        "article",
+		ID:          "hint",
+		Title:       i18n.Text(lang, "inline.hint_title"),
+		Description: i18n.Text(lang, "inline.hint_desc"),
+		InputMessageContent: InputTextMessageContent{
+			MessageText: i18n.HTML(lang, "inline.hint_message", b.username),
+			ParseMode:   "HTML",
+		},
+	}
+}
+
+// inlineNotice 构造提示类结果（被选中时发送标题与说明）
+func inlineNotice(id, title, desc string) InlineQueryResultArticle {
+	text := html.EscapeString(title)
+	if desc != "" {
+		text += "\n" + html.EscapeString(desc)
+	}
+	return InlineQueryResultArticle{
+		Type:        "article",
+		ID:          id,
+		Title:       title,
+		Description: desc,
+		InputMessageContent: InputTextMessageContent{
+			MessageText: text,
+			ParseMode:   "HTML",
+		},
+	}
+}
+
+// statusCard 构造状态卡片：列表中展示汇总，被选中时发送逐通道状态
+// overview 为 true 时卡片跨多个 service，逐行展示 service 名称
+func statusCard(lang i18n.Lang, id string, overview bool, title, heading string, channels []validator.ChannelStatus) InlineQueryResultArticle {
+	var up, degraded, down int
+	var latest time.Time
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<b>%s</b>\n\n", html.EscapeString(heading)))
+	for _, ch := range channels {
+		emoji, label := channelStatusLabel(lang, ch)
+		switch {
+		case ch.Board == "cold":
+		case ch.Status == "up":
+			up++
+		case ch.Status == "degraded":
+			degraded++
+		default:
+			down++
+		}
+
+		name := ch.Channel
+		if overview || name == "" {
+			// 概览卡片或默认通道：展示 service 名称
+			name = strings.TrimSuffix(ch.Service+" / "+ch.Channel, " / ")
+		}
+		sb.WriteString(fmt.Sprintf("%s %s: %s", emoji, html.EscapeString(name), label))
+		if ch.LatencyMs > 0 && ch.Board != "cold" {
+			sb.WriteString(fmt.Sprintf(" · %d ms", ch.LatencyMs))
+		}
+		sb.WriteString("\n")
+
+		if t, err := time.Parse(time.RFC3339, ch.UpdatedAt); err == nil && t.After(latest) {
+			latest = t
+		}
+	}
+	if !latest.IsZero() {
+		cst := time.FixedZone("CST", 8*60*60)
+		sb.WriteString("\n" + i18n.HTML(lang, "inline.updated", latest.In(cst).Format("2006-01-02 15:04:05")))
+	}
+
+	return InlineQueryResultArticle{
+		Type:        "article",
+		ID:          id,
+		Title:       overallEmoji(up, degraded, down) + " " + title,
+		Description: i18n.Text(lang, "inline.summary", up, degraded, down),
+		InputMessageContent: InputTextMessageContent{
+			MessageText: strings.TrimRight(sb.String(), "\n"),
+			ParseMode:   "HTML",
+		},
+	}
+}
+
+// channelStatusLabel 通道状态对应的图标与文案
+func channelStatusLabel(lang i18n.Lang, ch validator.ChannelStatus) (string, string) {
+	if ch.Board == "cold" {
+		return "⚪", i18n.Text(lang, "inline.status_cold")
+	}
+	switch ch.Status {
+	case "up":
+		return "🟢", i18n.Text(lang, "inline.status_up")
+	case "degraded":
+		return "🟡", i18n.Text(lang, "inline.status_degraded")
+	default:
+		return "🔴", i18n.Text(lang, "inline.status_down")
+	}
+}
+
+// overallEmoji 汇总状态图标：有不可用为红，有波动为黄，否则为绿
+func overallEmoji(up, degraded, down int) string {
+	switch {
+	case down > 0:
+		return "🔴"
+	case degraded > 0:
+		return "🟡"
+	case up > 0:
+		return "🟢"
+	default:
+		return "⚪"
+	}
+}
+
+// snapshotServices 提取快照中的 service 名称（去重）
+func snapshotServices(snapshot *validator.StatusSnapshot) []string {
+	seen := make(map[string]bool)
+	var services []string
+	for _, ch := range snapshot.Channels {
+		if !seen[ch.Service] {
+			seen[ch.Service] = true
+			services = append(services, ch.Service)
+		}
+	}
+	return services
+}
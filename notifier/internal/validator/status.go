@@ -0,0 +1,139 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultStatusTTL 状态快照缓存 TTL（状态变化快，仅用于削峰）
+const defaultStatusTTL = 15 * time.Second
+
+// ChannelStatus 单个通道的当前状态
+type ChannelStatus struct {
+	Service   string // service 名称
+	Channel   string // channel 名称（空表示默认通道）
+	Status    string // up/down/degraded
+	LatencyMs int    // 最近一次探测延迟（毫秒）
+	UpdatedAt string // 最近一次探测时间（RFC3339）
+	Board     string // hot/cold
+}
+
+// StatusSnapshot provider（或 provider/service）的当前状态快照
+type StatusSnapshot struct {
+	Provider string          // 规范化的 provider 名称
+	Channels []ChannelStatus // 按 service、channel 排列
+}
+
+type statusCacheEntry struct {
+	expireAt time.Time
+	snapshot *StatusSnapshot
+	notFound *NotFoundError // 负向缓存
+}
+
+// QueryStatus 查询 provider（可选 service）的当前状态，结果短暂缓存
+// 返回 NotFoundError（目标不存在）或 UnavailableError（状态服务不可用）
+func (v *RelayPulseValidator) QueryStatus(ctx context.Context, provider, service string) (*StatusSnapshot, error) {
+	provider = strings.TrimSpace(provider)
+	service = strings.TrimSpace(service)
+	if provider == "" {
+		return nil, &NotFoundError{Level: NotFoundProvider, Provider: provider, Service: service}
+	}
+
+	key := "status:" + strings.ToLower(provider) + "/" + strings.ToLower(service)
+
+	// 检查缓存
+	v.mu.Lock()
+	if entry, ok := v.statusCache[key]; ok && time.Now().Before(entry.expireAt) {
+		v.mu.Unlock()
+		if entry.notFound != nil {
+			return nil, entry.notFound
+		}
+		return entry.snapshot, nil
+	}
+
+	// singleflight
+	if call, ok := v.inflight[key]; ok {
+		v.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil, &UnavailableError{Cause: ctx.Err()}
+		case <-call.done:
+			if call.err != nil {
+				return nil, call.err
+			}
+			return call.val.(*StatusSnapshot), nil
+		}
+	}
+
+	call := &inflightCall{done: make(chan struct{})}
+	v.inflight[key] = call
+	v.mu.Unlock()
+
+	// 执行 API 调用
+	snapshot, err := v.fetchStatus(ctx, provider, service)
+
+	v.mu.Lock()
+	v.evictExpiredCacheLocked() // 写入前清理过期缓存
+	if err == nil {
+		v.statusCache[key] = &statusCacheEntry{
+			expireAt: time.Now().Add(v.statusTTL),
+			snapshot: snapshot,
+		}
+	} else {
+		var nf *NotFoundError
+		if errors.As(err, &nf) {
+			v.statusCache[key] = &statusCacheEntry{
+				expireAt: time.Now().Add(v.negativeTTL),
+				notFound: nf,
+			}
+		}
+	}
+	call.val = snapshot
+	call.err = err
+	close(call.done)
+	delete(v.inflight, key)
+	v.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// fetchStatus 从 API 获取当前状态
+func (v *RelayPulseValidator) fetchStatus(ctx context.Context, provider, service string) (*StatusSnapshot, error) {
+	resp, err := v.callStatusQuery(ctx, provider, service, "")
+	if err != nil {
+		return nil, &UnavailableError{Cause: err}
+	}
+
+	if resp.Error != nil {
+		// 只有 NOT_FOUND 错误码才转为 NotFoundError，其他错误视为服务不可用
+		if !strings.EqualFold(resp.Error.Code, "NOT_FOUND") {
+			return nil, &UnavailableError{Cause: fmt.Errorf("API 错误: %s - %s", resp.Error.Code, resp.Error.Message)}
+		}
+		return nil, &NotFoundError{
+			Level:    parseNotFoundLevel(resp.Error.Message),
+			Provider: provider,
+			Service:  service,
+		}
+	}
+
+	snapshot := &StatusSnapshot{Provider: resp.Provider}
+	for _, svc := range resp.Services {
+		for _, ch := range svc.Channels {
+			snapshot.Channels = append(snapshot.Channels, ChannelStatus{
+				Service:   svc.Name,
+				Channel:   ch.Name,
+				Status:    strings.TrimSpace(ch.Status),
+				LatencyMs: ch.LatencyMs,
+				UpdatedAt: ch.UpdatedAt,
+				Board:     strings.TrimSpace(ch.Board),
+			})
+		}
+	}
+	return snapshot, nil
+}
@@ -1,5 +1,6 @@
 // Package validator 提供订阅目标验证功能
-// 通过调用 relay-pulse 主服务的 /api/status/query 接口验证 provider/service/channel 是否存在
+// 通过调用 relay-pulse 主服务的 /api/status/query 接口验证 provider/service/channel 是否存在，
+// 并提供带短期缓存的当前状态查询（Inline Query 等场景）
 package validator
 
 import (
@@ -111,12 +112,14 @@ type RelayPulseValidator struct {
 
 	positiveTTL time.Duration
 	negativeTTL time.Duration
+	statusTTL   time.Duration
 
 	mu sync.Mutex
 
 	// 缓存：key 使用 lowercase 标识
 	providerCache map[string]*providerCacheEntry // "prov:<provider>" -> entry
 	serviceCache  map[string]*serviceCacheEntry  // "svc:<provider>/<service>" -> entry
+	statusCache   map[string]*statusCacheEntry   // "status:<provider>/<service>" -> entry
 
 	// singleflight: 防止并发击穿
 	inflight map[string]*inflightCall
@@ -165,8 +168,10 @@ func NewRelayPulseValidator(eventsURL string) (*RelayPulseValidator, error) {
 		},
 		positiveTTL:   defaultPositiveTTL,
 		negativeTTL:   defaultNegativeTTL,
+		statusTTL:     defaultStatusTTL,
 		providerCache: make(map[string]*providerCacheEntry),
 		serviceCache:  make(map[string]*serviceCacheEntry),
+		statusCache:   make(map[string]*statusCacheEntry),
 		inflight:      make(map[string]*inflightCall),
 	}, nil
 }
@@ -619,8 +624,11 @@ type statusQueryResponse struct {
 	Services []struct {
 		Name     string `json:"name"`
 		Channels []struct {
-			Name  string `json:"name"`
-			Board string `json:"board,omitempty"` // hot/cold
+			Name      string `json:"name"`
+			Status    string `json:"status,omitempty"`     // up/down/degraded
+			LatencyMs int    `json:"latency_ms,omitempty"` // 毫秒
+			UpdatedAt string `json:"updated_at,omitempty"` // RFC3339
+			Board     string `json:"board,omitempty"`      // hot/cold
 		} `json:"channels"`
 	} `json:"services,omitempty"`
 	Error *struct {
@@ -749,7 +757,7 @@ func limitSlice(s []string, max int) []string {
 // evictExpiredCacheLocked 清理过期缓存条目（调用前必须持有 v.mu 锁）
 // 如果清理后仍超过 maxCacheEntries，则清空所有缓存
 func (v *RelayPulseValidator) evictExpiredCacheLocked() {
-	totalEntries := len(v.providerCache) + len(v.serviceCache)
+	totalEntries := len(v.providerCache) + len(v.serviceCache) + len(v.statusCache)
 	if totalEntries < maxCacheEntries {
 		return
 	}
@@ -770,10 +778,18 @@ func (v *RelayPulseValidator) evictExpiredCacheLocked() {
 		}
 	}
 
+	// 清理过期的状态缓存
+	for key, entry := range v.statusCache {
+		if now.After(entry.expireAt) {
+			delete(v.statusCache, key)
+		}
+	}
+
 	// 如果清理后仍超过限制，清空所有缓存（简单粗暴但有效）
-	totalEntries = len(v.providerCache) + len(v.serviceCache)
+	totalEntries = len(v.providerCache) + len(v.serviceCache) + len(v.statusCache)
 	if totalEntries >= maxCacheEntries {
 		v.providerCache = make(map[string]*providerCacheEntry)
 		v.serviceCache = make(map[string]*serviceCacheEntry)
+		v.statusCache = make(map[string]*statusCacheEntry)
 	}
 }
@@ -0,0 +1,139 @@
+package wecom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 企业微信群机器人限制每个机器人每分钟最多 20 条消息
+const minSendInterval = 3 * time.Second
+
+// markdown 消息内容上限（UTF-8 字节）
+const maxMarkdownBytes = 4096
+
+// Client 企业微信群机器人客户端
+//
+// 鉴权方式：Webhook 地址中的 key 即为凭证（群机器人不支持加签），需妥善保管
+type Client struct {
+	webhookURL string
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	nextSend time.Time // 下一次允许发送的时间（单机器人限流）
+}
+
+// NewClient 创建企业微信群机器人客户端
+// webhookURL 形如 https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=xxx
+func NewClient(webhookURL string) *Client {
+	return &Client{
+		webhookURL: strings.TrimSpace(webhookURL),
+		httpClient: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+	}
+}
+
+// apiResponse 机器人接口响应
+type apiResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// SendMarkdown 发送 markdown 消息
+// 企业微信 markdown 支持 **加粗** 与 <font color="info|comment|warning"> 着色，换行使用 \n
+func (c *Client) SendMarkdown(ctx context.Context, content string) error {
+	if len(content) > maxMarkdownBytes {
+		content = truncateUTF8(content, maxMarkdownBytes)
+	}
+	return c.send(ctx, map[string]any{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"content": content,
+		},
+	})
+}
+
+// send 发送消息并检查业务错误码
+func (c *Client) send(ctx context.Context, payload map[string]any) error {
+	if err := c.throttle(ctx); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.webhookURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP 错误 [%d]: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var apiResp apiResponse
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	if apiResp.ErrCode != 0 {
+		return fmt.Errorf("企业微信机器人错误 [%d]: %s", apiResp.ErrCode, apiResp.ErrMsg)
+	}
+	return nil
+}
+
+// throttle 按最小发送间隔排队，避免触发机器人频率限制
+func (c *Client) throttle(ctx context.Context) error {
+	c.mu.Lock()
+	now := time.Now()
+	wait := c.nextSend.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	c.nextSend = now.Add(wait + minSendInterval)
+	c.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// truncateUTF8 按字节截断且不破坏 UTF-8 字符
+func truncateUTF8(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	cut := maxBytes
+	for cut > 0 && (s[cut]&0xC0) == 0x80 {
+		cut--
+	}
+	return s[:cut]
+}